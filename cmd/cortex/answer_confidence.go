@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hurttlocker/cortex/internal/ask"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// askAnswerConfidence grades the facts behind an ask answer's citations. It
+// returns nil when the store cannot grade them.
+func askAnswerConfidence(ctx context.Context, s store.Store, citations []ask.Citation) *store.AnswerConfidence {
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return nil
+	}
+	ids := make([]int64, 0, len(citations))
+	for _, c := range citations {
+		if c.MemoryID > 0 {
+			ids = append(ids, c.MemoryID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	conf, err := sqlStore.AnswerConfidence(ctx, ids)
+	if err != nil {
+		return nil
+	}
+	return conf
+}
+
+// answerConfidenceLine renders the confidence band under an answer. Answers
+// resting on stale or conflicting facts get a warning marker.
+func answerConfidenceLine(conf *store.AnswerConfidence) string {
+	if conf == nil {
+		return ""
+	}
+	marker := ""
+	switch {
+	case conf.Band == store.ConfidenceBandLow:
+		marker = "⚠️ "
+	case len(conf.ConflictingFacts) > 0 || len(conf.StaleFacts) > 0:
+		marker = "⚡ "
+	}
+	line := marker + "Confidence: " + conf.Summary()
+	if len(conf.ConflictingFacts) > 0 {
+		line += fmt.Sprintf(" — check: cortex conflicts (facts %v)", conf.ConflictingFacts)
+	}
+	return line
+}
+
+func printAnswerConfidence(conf *store.AnswerConfidence) {
+	if line := answerConfidenceLine(conf); line != "" {
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestAnswerConfidenceLine(t *testing.T) {
+	if got := answerConfidenceLine(nil); got != "" {
+		t.Fatalf("nil confidence should print nothing, got %q", got)
+	}
+	high := &store.AnswerConfidence{Band: store.ConfidenceBandHigh, Score: 0.91, Facts: 3}
+	if got := answerConfidenceLine(high); got != "Confidence: high (0.91): 3 facts, none stale or conflicting" {
+		t.Fatalf("unexpected high line %q", got)
+	}
+	shaky := &store.AnswerConfidence{Band: store.ConfidenceBandLow, Score: 0.31, Facts: 4, StaleFacts: []int64{1, 2, 3}, ConflictingFacts: []int64{7}}
+	got := answerConfidenceLine(shaky)
+	for _, want := range []string{"⚠️ Confidence: low (0.31)", "3 of 4 facts stale", "1 in conflict", "cortex conflicts (facts [7])"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("line %q missing %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func archiveUsageText() string {
+	return `Usage: cortex archive <set|release|list|audit> [flags]
+
+An archive hold (legal hold) makes a memory or fact immutable for compliance:
+held records are never deleted, purged, cleaned up, or retired, and their
+content cannot be rewritten. A held fact can still be superseded. Every
+change to a held record, and every hold placed or released, is written to
+the archive audit trail. A hold on a memory covers all of its facts.
+
+This is separate from 'cortex beliefs archive', which retires a fact.
+
+Subcommands:
+  set <memory|fact> <id>... [--reason <text>]       Place a hold
+  release <memory|fact> <id>... [--reason <text>]   Lift a hold (audited)
+  list [--kind memory|fact] [--json]                Held records
+  audit [<memory|fact> <id>] [--limit N] [--json]   Audit trail, newest first
+
+Examples:
+  cortex archive set memory 42 --reason "Q3 board decision"
+  cortex archive set fact 1201 1202
+  cortex archive audit fact 1201`
+}
+
+func runArchive(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(archiveUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "set":
+		return runArchiveSet(args[1:], false)
+	case "release":
+		return runArchiveSet(args[1:], true)
+	case "list":
+		return runArchiveList(args[1:])
+	case "audit":
+		return runArchiveAudit(args[1:])
+	default:
+		return fmt.Errorf("unknown archive subcommand: %s", args[0])
+	}
+}
+
+func runArchiveSet(args []string, release bool) error {
+	verb := "set"
+	if release {
+		verb = "release"
+	}
+	var kind, reason string
+	var ids []int64
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--reason" && i+1 < len(args):
+			i++
+			reason = args[i]
+		case strings.HasPrefix(arg, "--reason="):
+			reason = strings.TrimPrefix(arg, "--reason=")
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case kind == "":
+			kind = strings.ToLower(arg)
+			if !store.IsHoldKind(kind) {
+				return fmt.Errorf("unknown archive kind %q (use memory or fact)", arg)
+			}
+		default:
+			id, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+			if err != nil || id <= 0 {
+				return fmt.Errorf("invalid %s id: %s", kind, arg)
+			}
+			ids = append(ids, id)
+		}
+	}
+	if kind == "" || len(ids) == 0 {
+		return fmt.Errorf("usage: cortex archive %s <memory|fact> <id>... [--reason <text>]", verb)
+	}
+
+	ss, err := openArchiveStore()
+	if err != nil {
+		return err
+	}
+	defer ss.Close()
+
+	ctx := context.Background()
+	for _, id := range ids {
+		if release {
+			if err := ss.ReleaseArchiveHold(ctx, kind, id, reason); err != nil {
+				return err
+			}
+			fmt.Printf("Released archive hold on %s %d\n", kind, id)
+			continue
+		}
+		if err := ss.SetArchiveHold(ctx, kind, id, reason); err != nil {
+			return err
+		}
+		fmt.Printf("🔒 Archive hold on %s %d\n", kind, id)
+	}
+	return nil
+}
+
+func runArchiveList(args []string) error {
+	kind := ""
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--kind" && i+1 < len(args):
+			i++
+			kind = strings.ToLower(args[i])
+		case strings.HasPrefix(args[i], "--kind="):
+			kind = strings.ToLower(strings.TrimPrefix(args[i], "--kind="))
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	if kind != "" && !store.IsHoldKind(kind) {
+		return fmt.Errorf("--kind must be memory or fact")
+	}
+	jsonOutput, err := parseSourcesJSONFlag(rest)
+	if err != nil {
+		return err
+	}
+
+	ss, err := openArchiveStore()
+	if err != nil {
+		return err
+	}
+	defer ss.Close()
+
+	holds, err := ss.ListArchiveHolds(context.Background(), kind)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		if holds == nil {
+			holds = []store.ArchiveHold{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(holds)
+	}
+	if len(holds) == 0 {
+		fmt.Println("No archive holds. Use `cortex archive set <memory|fact> <id>`.")
+		return nil
+	}
+	fmt.Printf("%-6s  %-8s  %-10s  %-24s  %s\n", "KIND", "ID", "SINCE", "REASON", "RECORD")
+	fmt.Println(strings.Repeat("─", 80))
+	for _, h := range holds {
+		fmt.Printf("%-6s  %-8d  %-10s  %-24s  %s\n", h.Kind, h.TargetID, h.CreatedAt.Format("2006-01-02"),
+			truncateArchiveText(h.Reason, 24), truncateArchiveText(h.Summary, 60))
+	}
+	return nil
+}
+
+func runArchiveAudit(args []string) error {
+	kind := ""
+	var id int64
+	limit := 50
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--limit" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid --limit: %s", args[i])
+			}
+			limit = n
+		case strings.HasPrefix(arg, "--limit="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--limit="))
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid --limit: %s", arg)
+			}
+			limit = n
+		case strings.HasPrefix(arg, "-"):
+			rest = append(rest, arg)
+		case kind == "":
+			kind = strings.ToLower(arg)
+			if !store.IsHoldKind(kind) {
+				return fmt.Errorf("unknown archive kind %q (use memory or fact)", arg)
+			}
+		case id == 0:
+			n, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid %s id: %s", kind, arg)
+			}
+			id = n
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if kind != "" && id == 0 {
+		return fmt.Errorf("usage: cortex archive audit [<memory|fact> <id>] [--limit N] [--json]")
+	}
+	jsonOutput, err := parseSourcesJSONFlag(rest)
+	if err != nil {
+		return err
+	}
+
+	ss, err := openArchiveStore()
+	if err != nil {
+		return err
+	}
+	defer ss.Close()
+
+	entries, err := ss.ListArchiveAudit(context.Background(), kind, id, limit)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		if entries == nil {
+			entries = []store.ArchiveAuditEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No archive audit entries.")
+		return nil
+	}
+	for _, e := range entries {
+		fmt.Printf("%s  %-7s  %s %d", e.CreatedAt.Format("2006-01-02 15:04:05"), e.Action, e.Kind, e.TargetID)
+		switch {
+		case e.Action == "update":
+			fmt.Printf("\n    old: %s\n    new: %s\n", e.OldValue, e.NewValue)
+		case e.NewValue != "":
+			fmt.Printf("  (%s)\n", e.NewValue)
+		case e.OldValue != "":
+			fmt.Printf("  (was: %s)\n", e.OldValue)
+		default:
+			fmt.Println()
+		}
+	}
+	return nil
+}
+
+func truncateArchiveText(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len([]rune(s)) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
+
+func openArchiveStore() (*store.SQLiteStore, error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("opening store: %w", err)
+	}
+	ss, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, fmt.Errorf("archive requires SQLiteStore")
+	}
+	return ss, nil
+}
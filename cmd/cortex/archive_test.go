@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunArchive_SetListAuditRelease(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "Board approved hiring two engineers", SourceFile: "board.md", ContentHash: "a"})
+	factID, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "hiring", Predicate: "approved", Object: "two engineers", FactType: "decision", Confidence: 0.9})
+	s.Close()
+
+	for _, args := range [][]string{
+		{"set"},
+		{"set", "memory"},
+		{"set", "note", "1"},
+		{"set", "fact", "abc"},
+		{"set", "fact", "1", "--bogus"},
+		{"release", "fact", "1"},
+		{"list", "--kind", "entity"},
+		{"audit", "fact"},
+		{"frobnicate"},
+	} {
+		if err := runArchive(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+
+	out := captureStdout(func() {
+		if err := runArchive([]string{"set", "fact", strconv.FormatInt(factID, 10), "--reason", "board minutes"}); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Archive hold on fact") {
+		t.Fatalf("unexpected set output: %q", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runArchive([]string{"list", "--json"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	var holds []store.ArchiveHold
+	if err := json.Unmarshal([]byte(out), &holds); err != nil {
+		t.Fatalf("parsing list: %v\n%s", err, out)
+	}
+	if len(holds) != 1 || holds[0].TargetID != factID || holds[0].Summary != "hiring approved two engineers" {
+		t.Fatalf("unexpected holds: %+v", holds)
+	}
+
+	s, _ = store.NewStore(getStoreConfig())
+	err = s.UpdateFactState(ctx, factID, store.FactStateRetired)
+	s.Close()
+	if err == nil {
+		t.Fatal("retiring a held fact should fail")
+	}
+
+	out = captureStdout(func() {
+		if err := runArchive([]string{"release", "fact", strconv.FormatInt(factID, 10), "--reason=closed"}); err != nil {
+			t.Fatalf("release: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Released") {
+		t.Fatalf("unexpected release output: %q", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runArchive([]string{"audit", "fact", strconv.FormatInt(factID, 10)}); err != nil {
+			t.Fatalf("audit: %v", err)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "release") || !strings.Contains(lines[1], "(board minutes)") {
+		t.Fatalf("unexpected audit output: %q", out)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunBackup(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddMemory(ctx, &store.Memory{Content: "deploy notes", SourceFile: "notes.md"}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	dest := filepath.Join(t.TempDir(), "backup.db")
+	var runErr error
+	out := captureStdout(func() { runErr = runBackup([]string{dest, "--json"}) })
+	if runErr != nil {
+		t.Fatalf("backup: %v", runErr)
+	}
+	var info store.BackupInfo
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if !info.Consistent || info.Method != store.BackupMethodVacuumInto || info.Guarantee == "" || info.Source != globalDBPath {
+		t.Fatalf("unexpected metadata: %+v", info)
+	}
+
+	b, err := store.NewStore(store.StoreConfig{DBPath: dest, ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	memories, _ := b.ListMemories(ctx, store.ListOpts{Limit: 10})
+	b.Close()
+	if len(memories) != 1 {
+		t.Fatalf("backup holds %d memories, want 1", len(memories))
+	}
+
+	if err := runBackup([]string{dest}); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected refusal to overwrite, got %v", err)
+	}
+	captureStdout(func() { runErr = runBackup([]string{dest, "--force", "--json"}) })
+	if runErr != nil {
+		t.Fatalf("backup --force: %v", runErr)
+	}
+	if err := runBackup(nil); err == nil {
+		t.Fatal("expected usage error without a destination")
+	}
+	if err := runBackup([]string{dest, "--nope"}); err == nil {
+		t.Fatal("expected error for an unknown flag")
+	}
+}
+
+func TestRunExport_JSONRecordsSnapshot(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "deploy notes", SourceFile: "notes.md"})
+	if _, err := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "region", Object: "us-east", FactType: "kv"}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	path := filepath.Join(t.TempDir(), "facts.json")
+	if err := runExport([]string{"--facts", "--format", "json", "--output", path}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got exportJSON
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid export %s: %v", data, err)
+	}
+	snap := got.Export.Snapshot
+	if got.Export.Kind != "facts" || got.Export.Count != 1 || len(got.Facts) != 1 {
+		t.Fatalf("unexpected export: %+v", got.Export)
+	}
+	if snap == nil || !snap.Consistent || snap.Path != "" || snap.TakenAt.IsZero() {
+		t.Fatalf("export should record its snapshot: %+v", snap)
+	}
+
+	// The wrapped export still works as a diff baseline.
+	out := captureStdout(func() { err = runDiff([]string{path, "--json"}) })
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	var diff diffJSON
+	if err := json.Unmarshal([]byte(out), &diff); err != nil || !diff.Empty() {
+		t.Fatalf("diff against a fresh export = %s (%v)", out, err)
+	}
+}
+
+func TestRunExport_KeepsNamespace(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	work := store.WithNamespace(ctx, "work")
+	for _, m := range []struct {
+		ctx     context.Context
+		content string
+	}{{work, "sprint review on friday"}, {work, "oncall rotates weekly"}, {ctx, "water the plants"}} {
+		if _, err := s.AddMemory(m.ctx, &store.Memory{Content: m.content, SourceFile: "notes.md"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	export := func(ns string) exportJSON {
+		t.Helper()
+		t.Setenv("CORTEX_NAMESPACE", ns)
+		path := filepath.Join(t.TempDir(), "memories.json")
+		if err := runExport([]string{"--format", "json", "--output", path}); err != nil {
+			t.Fatalf("export: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got exportJSON
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("invalid export %s: %v", data, err)
+		}
+		return got
+	}
+	got := export("work")
+	if got.Export.Count != 2 || len(got.Memories) != 2 {
+		t.Fatalf("work export = %+v", got.Export)
+	}
+	for _, m := range got.Memories {
+		if m.Namespace != "work" {
+			t.Fatalf("work export has memory %d from %q", m.ID, m.Namespace)
+		}
+	}
+	if got := export("*"); got.Export.Count != 3 {
+		t.Fatalf("all-namespace export count = %d, want 3", got.Export.Count)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/hurttlocker/cortex/internal/brief"
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const briefUsage = "usage: cortex brief [--project <name>] [--clusters] [--llm <provider/model>] [--no-refresh] [--force] [--json]"
+
+// briefOutput is the --json shape of `cortex brief`.
+type briefOutput struct {
+	*brief.Card
+	Refresh *brief.RefreshResult `json:"refresh,omitempty"`
+	Stale   int                  `json:"stale,omitempty"`
+}
+
+// runBrief prints the hierarchical briefs (store identity card, project
+// domains, and with --project or --clusters their cluster briefs), first
+// regenerating the stale ones when an LLM is configured.
+func runBrief(args []string) error {
+	project := ""
+	llmFlag := ""
+	withClusters := false
+	noRefresh := false
+	force := false
+	jsonOutput := false
+
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--project" && i+1 < len(args):
+			i++
+			project = args[i]
+		case strings.HasPrefix(args[i], "--project="):
+			project = strings.TrimPrefix(args[i], "--project=")
+		case args[i] == "--llm" && i+1 < len(args):
+			i++
+			llmFlag = args[i]
+		case strings.HasPrefix(args[i], "--llm="):
+			llmFlag = strings.TrimPrefix(args[i], "--llm=")
+		case args[i] == "--clusters":
+			withClusters = true
+		case args[i] == "--no-refresh":
+			noRefresh = true
+		case args[i] == "--force":
+			force = true
+		case args[i] == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s\n%s", args[i], briefUsage)
+		default:
+			return fmt.Errorf("unexpected argument: %s\n%s", args[i], briefUsage)
+		}
+	}
+	if noRefresh && force {
+		return fmt.Errorf("--force and --no-refresh are mutually exclusive")
+	}
+
+	if llmFlag == "" && !noRefresh {
+		resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		llmFlag = strings.TrimSpace(resolvedCfg.EffectiveLLMModel("brief", "").Value)
+	}
+	if force && llmFlag == "" {
+		return fmt.Errorf("--force needs an LLM: pass --llm <provider/model> or set llm.provider")
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("brief requires SQLite store")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	out := briefOutput{}
+	opts := brief.Options{Project: project, Force: force}
+	if llmFlag != "" && !noRefresh {
+		llmCfg, err := llm.ParseLLMFlag(llmFlag)
+		if err != nil {
+			return fmt.Errorf("parsing --llm: %w", err)
+		}
+		provider, err := newScrubbedProvider(llmCfg)
+		if err != nil {
+			return fmt.Errorf("creating LLM provider: %w", err)
+		}
+		defer reportScrubbed(provider)
+		if out.Refresh, err = brief.Refresh(ctx, sqlStore, provider, opts); err != nil {
+			return fmt.Errorf("refreshing briefs: %w", err)
+		}
+	} else if out.Stale, err = brief.Stale(ctx, sqlStore, opts); err != nil {
+		return fmt.Errorf("checking briefs: %w", err)
+	}
+
+	if out.Card, err = brief.Load(ctx, sqlStore, project, withClusters); err != nil {
+		return fmt.Errorf("loading briefs: %w", err)
+	}
+
+	if jsonOutput || !isTTY() {
+		data, _ := json.MarshalIndent(out, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	printBrief(out, project)
+	return nil
+}
+
+func printBrief(out briefOutput, project string) {
+	if r := out.Refresh; r != nil {
+		generated := 0
+		for _, n := range r.Generated {
+			generated += n
+		}
+		fmt.Printf("🔄 Briefs refreshed with %s: %d regenerated, %d unchanged", r.Model, generated, r.Unchanged)
+		if r.Removed > 0 {
+			fmt.Printf(", %d removed", r.Removed)
+		}
+		fmt.Println()
+		for _, e := range r.Errors {
+			fmt.Printf("   ⚠️  %s\n", e)
+		}
+		fmt.Println()
+	}
+
+	if out.Identity == nil && len(out.Domains) == 0 {
+		switch {
+		case project != "":
+			fmt.Printf("No brief for project %q yet.\n", project)
+		case out.Refresh == nil:
+			fmt.Println("No briefs yet. Run `cortex cluster --rebuild`, then `cortex brief --llm <provider/model>`.")
+		default:
+			fmt.Println("No briefs yet: no cluster has enough facts. Run `cortex cluster --rebuild` first.")
+		}
+		return
+	}
+
+	if c := out.Identity; c != nil {
+		fmt.Printf("🪪 Identity card (%d facts, %s)\n\n%s\n\n", c.FactCount, c.UpdatedAt.Local().Format("2006-01-02"), wrapBrief(c.Summary, 78, ""))
+	}
+	for _, d := range out.Domains {
+		fmt.Printf("📁 %s (%d facts)\n%s\n", d.Title, d.FactCount, wrapBrief(d.Summary, 78, "   "))
+		for _, c := range d.Clusters {
+			fmt.Printf("\n   • %s (%d facts)\n%s\n", c.Title, c.FactCount, wrapBrief(c.Summary, 78, "     "))
+		}
+		fmt.Println()
+	}
+	if out.Stale > 0 {
+		fmt.Printf("⏳ %d cluster %s changed since the last refresh. Pass --llm <provider/model> (or set llm.provider) to update.\n",
+			out.Stale, pluralize("brief", "briefs", out.Stale))
+	}
+}
+
+// wrapBrief word-wraps text at width columns, prefixing each line with indent.
+func wrapBrief(text string, width int, indent string) string {
+	var b strings.Builder
+	line := indent
+	for _, word := range strings.Fields(text) {
+		if len(line) > len(indent) && len(line)+1+len(word) > width {
+			b.WriteString(line + "\n")
+			line = indent
+		}
+		if len(line) > len(indent) {
+			line += " "
+		}
+		line += word
+	}
+	b.WriteString(line)
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunBrief_ShowsStoredBriefs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	sqlStore := s.(*store.SQLiteStore)
+	for _, b := range []*store.Brief{
+		{Level: store.BriefLevelStore, Title: "Identity card", Summary: "A trader who runs ops.", FactCount: 9, Fingerprint: "s"},
+		{Level: store.BriefLevelProject, Key: "ops", Project: "ops", Title: "ops", Summary: "Deploys on fly.io.", FactCount: 5, Fingerprint: "p1"},
+		{Level: store.BriefLevelProject, Key: "trading", Project: "trading", Title: "trading", Summary: "ORB on SPY.", FactCount: 4, Fingerprint: "p2"},
+		{Level: store.BriefLevelCluster, Key: store.ClusterBriefKey("ops", 1), Project: "ops", ClusterID: 1, Title: "deploy", Summary: "Blue-green in iad.", FactCount: 5, Fingerprint: "c1"},
+	} {
+		if err := sqlStore.UpsertBrief(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	if err := runBrief([]string{"--bogus"}); err == nil || !strings.Contains(err.Error(), "unknown flag: --bogus") {
+		t.Fatalf("expected unknown flag error, got %v", err)
+	}
+
+	out := captureStdout(func() {
+		if err := runBrief([]string{"--no-refresh", "--json"}); err != nil {
+			t.Fatalf("brief: %v", err)
+		}
+	})
+	var card struct {
+		Identity *store.Brief `json:"identity"`
+		Domains  []struct {
+			Title    string        `json:"title"`
+			Clusters []store.Brief `json:"clusters"`
+		} `json:"domains"`
+		Stale int `json:"stale"`
+	}
+	if err := json.Unmarshal([]byte(out), &card); err != nil {
+		t.Fatalf("decoding %q: %v", out, err)
+	}
+	if card.Identity == nil || card.Identity.Summary != "A trader who runs ops." || len(card.Domains) != 2 || card.Domains[0].Title != "ops" {
+		t.Fatalf("unexpected card: %s", out)
+	}
+	// The stored ops cluster no longer exists in the (empty) store.
+	if card.Stale != 1 || len(card.Domains[0].Clusters) != 0 {
+		t.Fatalf("expected one stale brief and no clusters without --clusters: %s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runBrief([]string{"--no-refresh", "--project", "ops", "--json"}); err != nil {
+			t.Fatalf("brief --project: %v", err)
+		}
+	})
+	if strings.Contains(out, "Identity card") || !strings.Contains(out, "Blue-green in iad.") || strings.Contains(out, "ORB on SPY.") {
+		t.Fatalf("expected only the ops domain with its clusters: %s", out)
+	}
+}
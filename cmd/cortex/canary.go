@@ -0,0 +1,361 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/extract"
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// Canary defaults: apply 5% of the batch first, then require the judge to
+// confirm 80% of the sampled changes and the conflict rate (conflicts per
+// active fact) to rise by no more than one point.
+const (
+	defaultCanarySample       = 0.05
+	defaultCanaryAgreement    = 0.8
+	defaultCanaryConflictRise = 0.01
+
+	// canaryConflictScanLimit caps conflict detection when measuring the
+	// conflict rate before and after the sample.
+	canaryConflictScanLimit = 100000
+)
+
+// canaryOptions holds the --canary flags shared by classify, summarize, and
+// conflicts --resolve llm.
+type canaryOptions struct {
+	Enabled         bool
+	Sample          float64 // fraction of the items applied first
+	MinAgreement    float64 // share of sampled changes the judge must confirm
+	MaxConflictRise float64 // allowed increase in conflicts per active fact
+	JudgeLLM        string  // provider/model of the judge; "" = the pass's own model
+}
+
+func newCanaryOptions() canaryOptions {
+	return canaryOptions{
+		Sample:          defaultCanarySample,
+		MinAgreement:    defaultCanaryAgreement,
+		MaxConflictRise: defaultCanaryConflictRise,
+	}
+}
+
+// parseFlag consumes the canary flag at args[i]. It returns the index of the
+// last argument used and false when args[i] is not a canary flag. Setting
+// any --canary-* value turns the canary on.
+func (o *canaryOptions) parseFlag(args []string, i int) (int, bool, error) {
+	name, value, hasValue := strings.Cut(args[i], "=")
+	switch name {
+	case "--canary":
+		if hasValue {
+			return i, false, nil
+		}
+		o.Enabled = true
+		return i, true, nil
+	case "--canary-sample", "--canary-agreement", "--canary-max-conflict-rise", "--canary-judge":
+	default:
+		return i, false, nil
+	}
+	if !hasValue {
+		if i+1 >= len(args) {
+			return i, true, fmt.Errorf("%s requires a value", name)
+		}
+		i++
+		value = args[i]
+	}
+	o.Enabled = true
+
+	if name == "--canary-judge" {
+		o.JudgeLLM = value
+		return i, true, nil
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	switch name {
+	case "--canary-sample":
+		if err != nil || v <= 0 || v > 1 {
+			return i, true, fmt.Errorf("invalid --canary-sample: %s (expected 0 < value <= 1)", value)
+		}
+		o.Sample = v
+	case "--canary-agreement":
+		if err != nil || v < 0 || v > 1 {
+			return i, true, fmt.Errorf("invalid --canary-agreement: %s (expected 0-1)", value)
+		}
+		o.MinAgreement = v
+	case "--canary-max-conflict-rise":
+		if err != nil || v < 0 {
+			return i, true, fmt.Errorf("invalid --canary-max-conflict-rise: %s (expected >= 0)", value)
+		}
+		o.MaxConflictRise = v
+	}
+	return i, true, nil
+}
+
+// judge returns the provider that reviews the sample.
+func (o canaryOptions) judge(fallback llm.Provider) (llm.Provider, error) {
+	if o.JudgeLLM == "" {
+		return fallback, nil
+	}
+	cfg, err := llm.ParseLLMFlag(o.JudgeLLM)
+	if err != nil {
+		return nil, fmt.Errorf("parsing --canary-judge: %w", err)
+	}
+	provider, err := newScrubbedProvider(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating canary judge: %w", err)
+	}
+	return provider, nil
+}
+
+// canarySplit picks a random fraction of items (at least one) as the
+// canary sample and returns it and the rest, both in their original order.
+func canarySplit[T any](items []T, fraction float64, rng *rand.Rand) (sample, rest []T) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	n := int(float64(len(items))*fraction + 0.5)
+	if n < 1 {
+		n = 1
+	}
+	if n > len(items) {
+		n = len(items)
+	}
+	picked := rng.Perm(len(items))[:n]
+	sort.Ints(picked)
+	inSample := make(map[int]bool, n)
+	for _, i := range picked {
+		inSample[i] = true
+		sample = append(sample, items[i])
+	}
+	for i, item := range items {
+		if !inSample[i] {
+			rest = append(rest, item)
+		}
+	}
+	return sample, rest
+}
+
+// canaryRun collects what the sample phase of a --canary run applied.
+// Checkpoint keys are held back until the canary passes, so a rolled-back
+// sample is processed again by the next run.
+type canaryRun struct {
+	operation    string
+	opts         canaryOptions
+	st           *store.SQLiteStore
+	total        int
+	sampleSize   int
+	rateBefore   float64
+	changes      []string                      // applied changes, described for the judge
+	undos        []func(context.Context) error // in the order applied
+	deferredKeys []string
+}
+
+// canaryReport is the outcome of a canary.
+type canaryReport struct {
+	Operation          string   `json:"operation"`
+	SampleSize         int      `json:"sample_size"`
+	Total              int      `json:"total"`
+	Changes            int      `json:"changes"`
+	Agreed             int      `json:"agreed"`
+	Agreement          float64  `json:"agreement"`
+	ConflictRateBefore float64  `json:"conflict_rate_before"`
+	ConflictRateAfter  float64  `json:"conflict_rate_after"`
+	Passed             bool     `json:"passed"`
+	Failures           []string `json:"failures,omitempty"`
+	Rejected           []string `json:"rejected,omitempty"` // changes the judge did not confirm
+	RolledBack         int      `json:"rolled_back,omitempty"`
+}
+
+// startCanary measures the conflict rate before sampleSize of total items
+// are applied.
+func startCanary(ctx context.Context, s *store.SQLiteStore, operation string, opts canaryOptions, sampleSize, total int) (*canaryRun, error) {
+	rate, err := canaryConflictRate(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	return &canaryRun{operation: operation, opts: opts, st: s, total: total, sampleSize: sampleSize, rateBefore: rate}, nil
+}
+
+// add records an applied change and how to revert it. undo may be nil when
+// the change is reverted as part of a larger one (see addUndo).
+func (c *canaryRun) add(description string, undo func(context.Context) error) {
+	c.changes = append(c.changes, description)
+	if undo != nil {
+		c.undos = append(c.undos, undo)
+	}
+}
+
+// addUndo records how to revert changes that are undone together, such as
+// all the summary facts of one cluster.
+func (c *canaryRun) addUndo(undo func(context.Context) error) {
+	c.undos = append(c.undos, undo)
+}
+
+// record holds checkpoint keys back until the canary passes.
+func (c *canaryRun) record(keys ...string) {
+	c.deferredKeys = append(c.deferredKeys, keys...)
+}
+
+// rollback reverts the sample's changes, newest first. It keeps going past
+// a failed step and reports every failure.
+func (c *canaryRun) rollback(ctx context.Context) error {
+	ctx = context.WithoutCancel(ctx)
+	var errs []string
+	for i := len(c.undos) - 1; i >= 0; i-- {
+		if err := c.undos[i](ctx); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("rolling back canary: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// evaluate has judge review the sample's changes and compares the conflict
+// rate with the one measured before. A canary with no changes passes.
+func (c *canaryRun) evaluate(ctx context.Context, judge llm.Provider) (*canaryReport, error) {
+	report := &canaryReport{
+		Operation:          c.operation,
+		SampleSize:         c.sampleSize,
+		Total:              c.total,
+		Changes:            len(c.changes),
+		ConflictRateBefore: c.rateBefore,
+		Agreement:          1,
+	}
+	after, err := canaryConflictRate(ctx, c.st)
+	if err != nil {
+		return nil, err
+	}
+	report.ConflictRateAfter = after
+
+	if len(c.changes) > 0 {
+		verdicts, err := extract.JudgeChanges(ctx, judge, c.operation, c.changes)
+		if err != nil {
+			// A judge that cannot answer cannot vouch for the sample.
+			report.Agreement = 0
+			report.Failures = append(report.Failures, fmt.Sprintf("judge unavailable: %v", err))
+		} else {
+			for i, v := range verdicts {
+				if v.Correct {
+					report.Agreed++
+					continue
+				}
+				rejected := c.changes[i]
+				if v.Reason != "" {
+					rejected += " (" + v.Reason + ")"
+				}
+				report.Rejected = append(report.Rejected, rejected)
+			}
+			report.Agreement = float64(report.Agreed) / float64(len(verdicts))
+			if report.Agreement < c.opts.MinAgreement {
+				report.Failures = append(report.Failures, fmt.Sprintf("judge confirmed %.0f%% of changes (need %.0f%%)",
+					report.Agreement*100, c.opts.MinAgreement*100))
+			}
+		}
+	}
+	if rise := report.ConflictRateAfter - report.ConflictRateBefore; rise > c.opts.MaxConflictRise+1e-9 {
+		report.Failures = append(report.Failures, fmt.Sprintf("conflict rate rose %.2f points (allowed %.2f)",
+			rise*100, c.opts.MaxConflictRise*100))
+	}
+	report.Passed = len(report.Failures) == 0
+	return report, nil
+}
+
+// conclude evaluates the canary and prints its report to w. When it passes,
+// the sample's checkpoint keys are recorded; when it fails, the sample is
+// rolled back and an error is returned.
+func (c *canaryRun) conclude(ctx context.Context, judge llm.Provider, checkpoint *batchCheckpoint, w io.Writer) (*canaryReport, error) {
+	report, err := c.evaluate(ctx, judge)
+	if err != nil {
+		if rbErr := c.rollback(ctx); rbErr != nil {
+			return nil, fmt.Errorf("%w (%v)", err, rbErr)
+		}
+		return nil, err
+	}
+	if report.Passed {
+		printCanaryReport(w, report)
+		return report, checkpoint.record(ctx, c.deferredKeys...)
+	}
+	if err := c.rollback(ctx); err != nil {
+		printCanaryReport(w, report)
+		return report, err
+	}
+	report.RolledBack = len(c.changes)
+	printCanaryReport(w, report)
+	return report, fmt.Errorf("%s canary failed: %s; the sample was rolled back and nothing else was changed",
+		c.operation, strings.Join(report.Failures, "; "))
+}
+
+// canaryConflictRate returns conflicts per active fact.
+func canaryConflictRate(ctx context.Context, s *store.SQLiteStore) (float64, error) {
+	active, err := s.CountActiveFacts(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if active == 0 {
+		return 0, nil
+	}
+	conflicts, err := s.GetAttributeConflictsLimitWithSuperseded(ctx, canaryConflictScanLimit, false)
+	if err != nil {
+		return 0, fmt.Errorf("measuring conflict rate: %w", err)
+	}
+	return float64(len(conflicts)) / float64(active), nil
+}
+
+func printCanaryReport(w io.Writer, r *canaryReport) {
+	verdict := "passed"
+	if !r.Passed {
+		verdict = "FAILED"
+	}
+	fmt.Fprintf(w, "Canary %s: %d of %d items, %d changes applied\n", verdict, r.SampleSize, r.Total, r.Changes)
+	if r.Changes > 0 {
+		fmt.Fprintf(w, "  Judge agreement: %d/%d (%.0f%%)\n", r.Agreed, r.Changes, r.Agreement*100)
+	}
+	fmt.Fprintf(w, "  Conflict rate:   %.2f%% → %.2f%%\n", r.ConflictRateBefore*100, r.ConflictRateAfter*100)
+	for _, f := range r.Failures {
+		fmt.Fprintf(w, "  ✗ %s\n", f)
+	}
+	show := len(r.Rejected)
+	if show > 5 {
+		show = 5
+	}
+	for _, rej := range r.Rejected[:show] {
+		fmt.Fprintf(w, "  rejected: %s\n", truncStr(rej, 160))
+	}
+	if len(r.Rejected) > show {
+		fmt.Fprintf(w, "  ... and %d more rejected\n", len(r.Rejected)-show)
+	}
+	if r.RolledBack > 0 {
+		fmt.Fprintf(w, "  Rolled back %d changes\n", r.RolledBack)
+	}
+	fmt.Fprintln(w)
+}
+
+// newCanaryRand seeds the sample selection.
+func newCanaryRand() *rand.Rand {
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// describeFact renders a fact for the judge.
+func describeFact(id int64, subject, predicate, object string) string {
+	if subject == "" {
+		subject = "(none)"
+	}
+	return fmt.Sprintf("#%d %q → %q → %q", id, subject, predicate, truncStr(object, 120))
+}
+
+// canaryOutput is where the canary report goes: stderr when stdout carries
+// JSON.
+func canaryOutput(jsonOutput bool) io.Writer {
+	if jsonOutput {
+		return os.Stderr
+	}
+	return os.Stdout
+}
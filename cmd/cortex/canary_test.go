@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// canaryJudgeStub confirms every change when agree is set, and none
+// otherwise.
+type canaryJudgeStub struct {
+	agree bool
+	calls int
+}
+
+func (p *canaryJudgeStub) Complete(context.Context, string, llm.CompletionOpts) (string, error) {
+	p.calls++
+	if p.agree {
+		return `{"verdicts":[{"index":1,"correct":true}]}`, nil
+	}
+	return `{"verdicts":[{"index":1,"correct":false,"reason":"wrong type"}]}`, nil
+}
+
+func (p *canaryJudgeStub) Name() string { return "stub/judge" }
+
+func TestCanaryOptions_ParseFlag(t *testing.T) {
+	opts := newCanaryOptions()
+	args := []string{"--canary-sample", "0.1", "--canary-agreement=0.9", "--canary-judge", "openrouter/x", "--json"}
+	for i := 0; i < len(args); i++ {
+		next, ok, err := opts.parseFlag(args, i)
+		if err != nil {
+			t.Fatalf("parseFlag(%q): %v", args[i], err)
+		}
+		if !ok {
+			if args[i] != "--json" {
+				t.Fatalf("%q should be a canary flag", args[i])
+			}
+			continue
+		}
+		i = next
+	}
+	if !opts.Enabled || opts.Sample != 0.1 || opts.MinAgreement != 0.9 || opts.JudgeLLM != "openrouter/x" || opts.MaxConflictRise != defaultCanaryConflictRise {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+
+	for _, bad := range [][]string{{"--canary-sample", "0"}, {"--canary-sample=1.5"}, {"--canary-agreement", "x"}, {"--canary-max-conflict-rise=-1"}, {"--canary-judge"}} {
+		o := newCanaryOptions()
+		if _, _, err := o.parseFlag(bad, 0); err == nil {
+			t.Errorf("expected error for %v", bad)
+		}
+	}
+}
+
+func TestCanarySplit(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+	sample, rest := canarySplit(items, 0.05, rand.New(rand.NewSource(1)))
+	if len(sample) != 5 || len(rest) != 95 {
+		t.Fatalf("split = %d/%d, want 5/95", len(sample), len(rest))
+	}
+	seen := map[int]bool{}
+	for _, part := range [][]int{sample, rest} {
+		for i, v := range part {
+			if seen[v] {
+				t.Fatalf("item %d in both parts", v)
+			}
+			seen[v] = true
+			if i > 0 && part[i-1] > v {
+				t.Fatalf("split should keep the original order: %v", part)
+			}
+		}
+	}
+
+	// A tiny batch still gets a one-item canary.
+	if sample, rest := canarySplit([]int{7, 8}, 0.05, rand.New(rand.NewSource(1))); len(sample) != 1 || len(rest) != 1 {
+		t.Fatalf("tiny split = %v/%v", sample, rest)
+	}
+}
+
+func TestCanaryRun_PassRecordsCheckpointFailRollsBack(t *testing.T) {
+	s, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	sqlStore := s.(*store.SQLiteStore)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "Q prefers dark mode", SourceFile: "notes.md"})
+	factID, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "Q", Predicate: "theme", Object: "dark mode", FactType: "kv"})
+
+	// classifyOnce applies one reclassification through a canary, the way
+	// runClassify's batch callback does.
+	classifyOnce := func(judge *canaryJudgeStub) (*canaryReport, error) {
+		t.Helper()
+		checkpoint, err := openBatchCheckpoint(ctx, s, "classify", true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		run, err := startCanary(ctx, sqlStore, "classify", newCanaryOptions(), 1, 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sqlStore.UpdateFactType(ctx, factID, "preference"); err != nil {
+			t.Fatal(err)
+		}
+		run.add(describeFact(factID, "Q", "theme", "dark mode")+": type kv → preference", func(ctx context.Context) error {
+			return sqlStore.UpdateFactType(ctx, factID, "kv")
+		})
+		run.record(classifyCheckpointKey(factID))
+		return run.conclude(ctx, judge, checkpoint, io.Discard)
+	}
+
+	report, err := classifyOnce(&canaryJudgeStub{agree: true})
+	if err != nil || !report.Passed || report.Agreed != 1 {
+		t.Fatalf("passing canary = %+v, %v", report, err)
+	}
+	if f, _ := s.GetFact(ctx, factID); f.FactType != "preference" {
+		t.Fatalf("passing canary should keep the change, type = %s", f.FactType)
+	}
+	if done, _ := sqlStore.CheckpointedItems(ctx, "classify"); !done[classifyCheckpointKey(factID)] {
+		t.Fatal("passing canary should checkpoint its sample")
+	}
+
+	if err := sqlStore.UpdateFactType(ctx, factID, "kv"); err != nil {
+		t.Fatal(err)
+	}
+	report, err = classifyOnce(&canaryJudgeStub{agree: false})
+	if err == nil || !strings.Contains(err.Error(), "canary failed") {
+		t.Fatalf("expected canary failure, got %v", err)
+	}
+	if report.Passed || report.RolledBack != 1 || len(report.Rejected) != 1 || !strings.Contains(report.Rejected[0], "wrong type") {
+		t.Fatalf("failing canary = %+v", report)
+	}
+	if f, _ := s.GetFact(ctx, factID); f.FactType != "kv" {
+		t.Fatalf("failing canary should roll back, type = %s", f.FactType)
+	}
+	if done, _ := sqlStore.CheckpointedItems(ctx, "classify"); len(done) != 0 {
+		t.Fatalf("failing canary should not checkpoint its sample: %v", done)
+	}
+}
+
+func TestCanaryRun_ConflictRiseFails(t *testing.T) {
+	s, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	sqlStore := s.(*store.SQLiteStore)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "Alice lives in Paris", SourceFile: "notes.md"})
+	s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "Alice", Predicate: "lives_in", Object: "Paris", FactType: "location"})
+
+	run, err := startCanary(ctx, sqlStore, "summarize", newCanaryOptions(), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The sample adds a contradicting fact: conflicts per active fact go
+	// from 0 to 1 in 2.
+	added, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "Alice", Predicate: "lives_in", Object: "Berlin", FactType: "location"})
+	run.add("summary fact #2", func(ctx context.Context) error {
+		_, err := sqlStore.DeleteFactsByIDs(ctx, []int64{added})
+		return err
+	})
+
+	report, err := run.evaluate(ctx, &canaryJudgeStub{agree: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Passed || report.ConflictRateAfter <= report.ConflictRateBefore || len(report.Failures) != 1 || !strings.Contains(report.Failures[0], "conflict rate") {
+		t.Fatalf("expected a conflict-rate failure, got %+v", report)
+	}
+}
+
+func TestCanaryFlag_RejectedWithDryRunOrWithoutLLMResolve(t *testing.T) {
+	if err := runClassify([]string{"--canary", "--dry-run"}); err == nil || !strings.Contains(err.Error(), "--dry-run") {
+		t.Fatalf("classify: %v", err)
+	}
+	if err := runSummarize([]string{"--llm", "openrouter/x", "--canary", "--dry-run"}); err == nil || !strings.Contains(err.Error(), "--dry-run") {
+		t.Fatalf("summarize: %v", err)
+	}
+	if err := runConflicts([]string{"--canary"}); err == nil || !strings.Contains(err.Error(), "--resolve llm") {
+		t.Fatalf("conflicts: %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const captureUsage = `usage: cortex capture <command>
+
+Commands:
+  report [--since 24h] [--top N] [--json]  Sessions, filter rejections, facts, and noisiest channels
+
+Rejections are recorded when imports run with --capture-dedupe or
+--capture-low-signal; dry runs are not counted.`
+
+// runCapture handles `cortex capture`: visibility into auto-capture hooks.
+func runCapture(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", captureUsage)
+	}
+	switch args[0] {
+	case "report":
+		return runCaptureReport(args[1:])
+	case "--help", "-h", "help":
+		fmt.Println(captureUsage)
+		return nil
+	default:
+		return fmt.Errorf("unknown capture command: %s\n%s", args[0], captureUsage)
+	}
+}
+
+func runCaptureReport(args []string) error {
+	window := 24 * time.Hour
+	top := 5
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--json":
+			jsonOutput = true
+		case arg == "--since" || arg == "--top":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", arg)
+			}
+			i++
+			if err := setCaptureReportFlag(arg, args[i], &window, &top); err != nil {
+				return err
+			}
+		case strings.HasPrefix(arg, "--since=") || strings.HasPrefix(arg, "--top="):
+			name, value, _ := strings.Cut(arg, "=")
+			if err := setCaptureReportFlag(name, value, &window, &top); err != nil {
+				return err
+			}
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("capture report requires SQLiteStore")
+	}
+	rep, err := sqlStore.CaptureReport(context.Background(), time.Now().UTC().Add(-window), top)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rep)
+	}
+	printCaptureReport(rep, window)
+	return nil
+}
+
+func setCaptureReportFlag(name, value string, window *time.Duration, top *int) error {
+	switch name {
+	case "--since":
+		d, err := parseSinceDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid --since value: %s (use e.g. 24h, 7d, 2w)", value)
+		}
+		*window = d
+	case "--top":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid --top value: %s", value)
+		}
+		*top = n
+	}
+	return nil
+}
+
+func printCaptureReport(rep *store.CaptureReport, window time.Duration) {
+	fmt.Printf("Capture report — last %s (since %s)\n\n", formatCaptureWindow(window), rep.Since.Local().Format("2006-01-02 15:04"))
+	seen := rep.Captured + rep.Deduped + rep.LowSignal
+	fmt.Printf("  Sessions detected:     %d\n", rep.Sessions)
+	fmt.Printf("  Memories captured:     %d\n", rep.Captured)
+	fmt.Printf("  Dedupe suppressions:   %d%s\n", rep.Deduped, captureShare(rep.Deduped, seen))
+	fmt.Printf("  Low-signal rejections: %d%s\n", rep.LowSignal, captureShare(rep.LowSignal, seen))
+	fmt.Printf("  Facts produced:        %d", rep.Facts)
+	if rep.Captured > 0 {
+		fmt.Printf(" (%.1f per memory, %.0f%% of memories yield none)", float64(rep.Facts)/float64(rep.Captured), rep.NoFactShare*100)
+	}
+	fmt.Println()
+
+	if seen == 0 {
+		fmt.Println("\nNo capture activity in this window.")
+		return
+	}
+	if len(rep.Channels) > 0 {
+		fmt.Println("\nNoisiest channels:")
+		fmt.Printf("  %-24s %8s %8s %8s %6s %6s\n", "CHANNEL", "CAPTURED", "DEDUPED", "LOW-SIG", "FACTS", "NOISE")
+		for _, c := range rep.Channels {
+			fmt.Printf("  %-24s %8d %8d %8d %6d %5.0f%%\n", c.Channel, c.Captured, c.Deduped, c.LowSignal, c.Facts, c.NoiseRate*100)
+		}
+	}
+	if rep.Deduped+rep.LowSignal == 0 && rep.Captured > 0 {
+		fmt.Println("\nNo rejections recorded — are --capture-dedupe and --capture-low-signal enabled on the capture hook?")
+	}
+}
+
+// captureShare formats n as a share of all captured chunks seen.
+func captureShare(n, total int) string {
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%.0f%% of chunks)", float64(n)/float64(total)*100)
+}
+
+// formatCaptureWindow renders the report window the way it was likely typed.
+func formatCaptureWindow(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+	out := d.String()
+	if strings.HasSuffix(out, "m0s") {
+		out = strings.TrimSuffix(out, "0s")
+	}
+	if strings.HasSuffix(out, "h0m") {
+		out = strings.TrimSuffix(out, "0m")
+	}
+	return out
+}
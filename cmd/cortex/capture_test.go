@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunCaptureReport(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := &store.Metadata{SessionKey: "agent:main:1", Channel: "discord"}
+	if _, err := s.AddMemory(ctx, &store.Memory{Content: "we deploy on fridays", SourceFile: "auto-capture.md", Metadata: meta}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.(*store.SQLiteStore).RecordCaptureRejection(ctx, store.CaptureRejectLowSignal, "auto-capture.md", meta); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	var runErr error
+	out := captureStdout(func() { runErr = runCapture([]string{"report", "--since", "2d", "--json"}) })
+	if runErr != nil {
+		t.Fatalf("capture report: %v", runErr)
+	}
+	var rep store.CaptureReport
+	if err := json.Unmarshal([]byte(out), &rep); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if rep.Sessions != 1 || rep.Captured != 1 || rep.LowSignal != 1 {
+		t.Fatalf("report = %+v", rep)
+	}
+	if len(rep.Channels) != 1 || rep.Channels[0].Channel != "discord" || rep.Channels[0].NoiseRate != 0.5 {
+		t.Fatalf("channels = %+v", rep.Channels)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"report", "--since", "soon"}, "invalid --since"},
+		{[]string{"report", "--top=-1"}, "invalid --top"},
+		{[]string{"report", "--since"}, "needs a value"},
+		{[]string{"report", "--verbose"}, "unknown flag"},
+		{[]string{"tail"}, "unknown capture command"},
+	} {
+		err := runCapture(tc.args)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("runCapture(%v) = %v, want %q", tc.args, err, tc.want)
+		}
+	}
+}
+
+func TestFormatCaptureWindow(t *testing.T) {
+	for d, want := range map[string]string{"24h": "1d", "48h": "2d", "6h": "6h", "90m": "1h30m"} {
+		dur, _ := parseSinceDuration(d)
+		if got := formatCaptureWindow(dur); got != want {
+			t.Errorf("formatCaptureWindow(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
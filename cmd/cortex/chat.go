@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/embed"
+	"github.com/hurttlocker/cortex/internal/reason"
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func chatUsageText() string {
+	return `Usage: cortex chat [--model <provider/model>] [--project <name>] [--capture] [flags]
+
+Interactive assistant over your memories. Every message is answered from
+memories and facts retrieved for it, with the recent conversation as
+context. Short follow-ups ("why?") are searched together with the previous
+question.
+
+Flags:
+  --model <provider/model>  LLM to use (same defaults as cortex reason)
+  --project <name>          Only retrieve from this project
+  --embed <provider/model>  Embedder for hybrid retrieval
+  --capture                 Save each exchange as a memory (source chat/<time>)
+  --max-tokens <n>          Reply length cap (default 768)
+  --history <n>             Earlier exchanges sent with each message (default 6)
+
+` + chatCommandsText()
+}
+
+func chatCommandsText() string {
+	return `Commands inside chat:
+  /search <query>           Show matching memories, without asking the LLM
+  /fact <id|query>          Show a fact by ID, or search facts
+  /pin <fact-id|text>       Keep a fact or a note in context for every message
+  /pins, /unpin <n|all>     List or drop pins
+  /capture [on|off]         Toggle saving exchanges as memories
+  /clear                    Forget the conversation so far (pins stay)
+  /help, /quit`
+}
+
+func runChat(args []string) error {
+	modelFlag, embedFlag := "", ""
+	session := &reason.ChatSession{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--help" || arg == "-h":
+			fmt.Println(chatUsageText())
+			return nil
+		case arg == "--model" && i+1 < len(args):
+			i++
+			modelFlag = args[i]
+		case strings.HasPrefix(arg, "--model="):
+			modelFlag = strings.TrimPrefix(arg, "--model=")
+		case arg == "--project" && i+1 < len(args):
+			i++
+			session.Project = args[i]
+		case strings.HasPrefix(arg, "--project="):
+			session.Project = strings.TrimPrefix(arg, "--project=")
+		case arg == "--embed" && i+1 < len(args):
+			i++
+			embedFlag = args[i]
+		case strings.HasPrefix(arg, "--embed="):
+			embedFlag = strings.TrimPrefix(arg, "--embed=")
+		case arg == "--capture":
+			session.Capture = true
+		case arg == "--max-tokens" && i+1 < len(args):
+			i++
+			v, err := strconv.Atoi(args[i])
+			if err != nil || v <= 0 {
+				return fmt.Errorf("invalid --max-tokens: %s", args[i])
+			}
+			session.MaxTokens = v
+		case arg == "--history" && i+1 < len(args):
+			i++
+			v, err := strconv.Atoi(args[i])
+			if err != nil || v <= 0 {
+				return fmt.Errorf("invalid --history: %s", args[i])
+			}
+			session.HistoryTurns = v
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	if modelFlag == "" {
+		modelFlag = reason.DefaultLocalModel
+		if os.Getenv("OPENROUTER_API_KEY") != "" {
+			modelFlag = reason.DefaultInteractiveModel
+		}
+	}
+	provider, model := reason.ParseProviderModel(modelFlag)
+	llm, err := reason.NewLLM(reason.LLMConfig{Provider: provider, Model: model})
+	if err != nil {
+		return err
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+
+	searchEngine := search.NewEngine(s)
+	if embedFlag != "" {
+		cfg, err := embed.ParseEmbedFlag(embedFlag)
+		if err != nil {
+			return fmt.Errorf("parsing embed provider: %w", err)
+		}
+		client, err := embed.NewClient(cfg)
+		if err != nil {
+			return fmt.Errorf("creating embedder: %w", err)
+		}
+		searchEngine = search.NewEngineWithEmbedder(s, client)
+	}
+	engine := reason.NewEngine(reason.EngineConfig{
+		SearchEngine: searchEngine,
+		Store:        s,
+		LLM:          llm,
+		ConfigDir:    getConfigDir(),
+	})
+	defer func() { printScrubReport(provider+"/"+model, llm.ScrubReport()) }()
+
+	fmt.Printf("cortex chat — %s/%s. /help for commands, /quit to leave.\n", provider, model)
+	if session.Capture {
+		fmt.Println("Capturing this conversation as memories.")
+	}
+	repl := &chatREPL{engine: engine, search: searchEngine, store: s, session: session, out: os.Stdout}
+	return repl.run(context.Background(), os.Stdin)
+}
+
+// chatTurner answers one chat message; *reason.Engine in production.
+type chatTurner interface {
+	ChatTurn(ctx context.Context, s *reason.ChatSession, message string) (*reason.ChatReply, error)
+}
+
+type chatREPL struct {
+	engine  chatTurner
+	search  *search.Engine
+	store   store.Store
+	session *reason.ChatSession
+	out     io.Writer
+}
+
+func (c *chatREPL) run(ctx context.Context, in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for {
+		fmt.Fprint(c.out, "\nyou › ")
+		if !scanner.Scan() {
+			fmt.Fprintln(c.out)
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "/") {
+			if quit := c.command(ctx, line); quit {
+				return nil
+			}
+			continue
+		}
+		reply, err := c.engine.ChatTurn(ctx, c.session, line)
+		if err != nil {
+			fmt.Fprintf(c.out, "error: %v\n", err)
+			continue
+		}
+		fmt.Fprintf(c.out, "\n%s\n", reply.Content)
+		if line := answerConfidenceLine(reply.Confidence); line != "" && reply.Confidence.Band != store.ConfidenceBandHigh {
+			// Only shaky answers interrupt the conversation.
+			fmt.Fprintln(c.out, line)
+		}
+		footer := fmt.Sprintf("─── %d memories, %d facts | %s | %d→%d tokens",
+			reply.MemoriesUsed, reply.FactsUsed, reply.Duration.Round(time.Millisecond), reply.TokensIn, reply.TokensOut)
+		if reply.Confidence != nil {
+			footer += " | confidence " + reply.Confidence.Band
+		}
+		if reply.MemoryID > 0 {
+			footer += fmt.Sprintf(" | saved as memory %d", reply.MemoryID)
+		}
+		fmt.Fprintln(c.out, footer+" ───")
+	}
+}
+
+// command runs a slash command and reports whether the chat should end.
+func (c *chatREPL) command(ctx context.Context, line string) bool {
+	name, rest, _ := strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+	switch name {
+	case "/quit", "/exit", "/q":
+		return true
+	case "/help", "/?":
+		fmt.Fprintln(c.out, chatCommandsText())
+	case "/search":
+		c.searchMemories(ctx, rest)
+	case "/fact", "/facts":
+		c.showFacts(ctx, rest)
+	case "/pin":
+		c.pin(ctx, rest)
+	case "/pins":
+		if len(c.session.Pins) == 0 {
+			fmt.Fprintln(c.out, "No pins.")
+		}
+		for i, p := range c.session.Pins {
+			fmt.Fprintf(c.out, "  %d. %s\n", i+1, p)
+		}
+	case "/unpin":
+		c.unpin(rest)
+	case "/capture":
+		switch rest {
+		case "", "on":
+			c.session.Capture = true
+		case "off":
+			c.session.Capture = false
+		default:
+			fmt.Fprintln(c.out, "usage: /capture [on|off]")
+			return false
+		}
+		fmt.Fprintf(c.out, "Capture %s.\n", map[bool]string{true: "on", false: "off"}[c.session.Capture])
+	case "/clear":
+		c.session.History = nil
+		fmt.Fprintln(c.out, "Conversation cleared.")
+	default:
+		fmt.Fprintf(c.out, "unknown command %s (/help lists them)\n", name)
+	}
+	return false
+}
+
+func (c *chatREPL) searchMemories(ctx context.Context, query string) {
+	if query == "" {
+		fmt.Fprintln(c.out, "usage: /search <query>")
+		return
+	}
+	results, err := c.search.Search(ctx, query, search.Options{Limit: 5, Project: c.session.Project})
+	if err != nil {
+		fmt.Fprintf(c.out, "error: %v\n", err)
+		return
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(c.out, "No memories found.")
+		return
+	}
+	for _, r := range results {
+		fmt.Fprintf(c.out, "  #%d %s (%.2f)\n     %s\n", r.MemoryID, r.SourceFile, r.Score, truncateChatLine(r.Content, 160))
+	}
+}
+
+func (c *chatREPL) showFacts(ctx context.Context, arg string) {
+	if arg == "" {
+		fmt.Fprintln(c.out, "usage: /fact <id|query>")
+		return
+	}
+	if id, err := strconv.ParseInt(arg, 10, 64); err == nil {
+		f, err := c.store.GetFact(ctx, id)
+		if err != nil || f == nil {
+			fmt.Fprintf(c.out, "fact %d not found\n", id)
+			return
+		}
+		fmt.Fprintf(c.out, "  #%d %s %s %s [%s, %.2f]\n", f.ID, f.Subject, f.Predicate, f.Object, f.FactType, f.Confidence)
+		return
+	}
+	facts, err := c.search.SearchFacts(ctx, arg, search.Options{Limit: 10, Project: c.session.Project})
+	if err != nil {
+		fmt.Fprintf(c.out, "error: %v\n", err)
+		return
+	}
+	if len(facts) == 0 {
+		fmt.Fprintln(c.out, "No facts found.")
+		return
+	}
+	for _, f := range facts {
+		fmt.Fprintf(c.out, "  #%d %s %s %s [%s, %.2f]\n", f.FactID, f.Subject, f.Predicate, f.Object, f.FactType, f.Confidence)
+	}
+}
+
+func (c *chatREPL) pin(ctx context.Context, arg string) {
+	if arg == "" {
+		fmt.Fprintln(c.out, "usage: /pin <fact-id|text>")
+		return
+	}
+	text := arg
+	if id, err := strconv.ParseInt(arg, 10, 64); err == nil {
+		f, err := c.store.GetFact(ctx, id)
+		if err != nil || f == nil {
+			fmt.Fprintf(c.out, "fact %d not found\n", id)
+			return
+		}
+		text = fmt.Sprintf("%s %s %s (fact #%d, confidence %.2f)", f.Subject, f.Predicate, f.Object, f.ID, f.Confidence)
+	}
+	c.session.Pins = append(c.session.Pins, text)
+	fmt.Fprintf(c.out, "Pinned %d: %s\n", len(c.session.Pins), text)
+}
+
+func (c *chatREPL) unpin(arg string) {
+	if arg == "all" {
+		c.session.Pins = nil
+		fmt.Fprintln(c.out, "All pins removed.")
+		return
+	}
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > len(c.session.Pins) {
+		fmt.Fprintln(c.out, "usage: /unpin <n|all> (see /pins)")
+		return
+	}
+	c.session.Pins = append(c.session.Pins[:n-1], c.session.Pins[n:]...)
+	fmt.Fprintf(c.out, "Unpinned %d.\n", n)
+}
+
+func truncateChatLine(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > max {
+		return string(r[:max]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/reason"
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+type fakeChatTurner struct{ messages []string }
+
+func (f *fakeChatTurner) ChatTurn(_ context.Context, s *reason.ChatSession, message string) (*reason.ChatReply, error) {
+	f.messages = append(f.messages, message)
+	s.History = append(s.History, reason.ChatMessage{Role: "user", Content: message}, reason.ChatMessage{Role: "assistant", Content: "ok"})
+	return &reason.ChatReply{Content: "reply to " + message, MemoriesUsed: 2}, nil
+}
+
+func TestChatREPL_Commands(t *testing.T) {
+	s, err := store.NewStore(store.StoreConfig{DBPath: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "Alice leads the payments team", SourceFile: "team.md"})
+	factID, err := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "Alice", Predicate: "leads", Object: "payments team", FactType: "relationship", Confidence: 0.9})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	turner := &fakeChatTurner{}
+	var out bytes.Buffer
+	session := &reason.ChatSession{}
+	repl := &chatREPL{engine: turner, search: search.NewEngine(s), store: s, session: session, out: &out}
+	input := strings.Join([]string{
+		"/search payments",
+		"/fact " + strconv.FormatInt(factID, 10),
+		"/pin " + strconv.FormatInt(factID, 10),
+		"/pin remember the offsite",
+		"/pins",
+		"who leads payments?",
+		"/unpin 2",
+		"/capture on",
+		"/clear",
+		"/bogus",
+		"/quit",
+		"never sent",
+	}, "\n")
+	if err := repl.run(ctx, strings.NewReader(input)); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"team.md",                   // /search
+		"Alice leads payments team", // /fact and /pin by ID
+		"Pinned 2: remember the offsite",
+		"reply to who leads payments?",
+		"─── 2 memories",
+		"Unpinned 2.",
+		"Capture on.",
+		"Conversation cleared.",
+		"unknown command /bogus",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if len(turner.messages) != 1 {
+		t.Fatalf("sent %v, want only the one chat message before /quit", turner.messages)
+	}
+	if len(session.Pins) != 1 || !strings.Contains(session.Pins[0], "fact #") || !session.Capture || session.History != nil {
+		t.Fatalf("unexpected session state: %+v", session)
+	}
+}
+
+func TestRunChat_FlagErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"--bogus"},
+		{"hello"},
+		{"--max-tokens", "x"},
+		{"--history", "0"},
+	} {
+		if err := runChat(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// batchCheckpoint tracks which items a resumable LLM batch command (classify,
+// conflicts --resolve llm, summarize) has already processed, so a run killed
+// by a flaky provider or Ctrl-C picks up where it stopped. Dry runs and
+// non-SQLite stores get a checkpoint that remembers nothing.
+type batchCheckpoint struct {
+	st        *store.SQLiteStore
+	operation string
+	done      map[string]bool
+	recorded  int
+}
+
+// openBatchCheckpoint loads operation's checkpoint, discarding it first when
+// restart is set.
+func openBatchCheckpoint(ctx context.Context, s store.Store, operation string, restart, dryRun bool) (*batchCheckpoint, error) {
+	cp := &batchCheckpoint{operation: operation, done: map[string]bool{}}
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok || dryRun {
+		return cp, nil
+	}
+	cp.st = sqlStore
+	if restart {
+		n, err := sqlStore.ClearCheckpoint(ctx, operation)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			fmt.Fprintf(os.Stderr, "Discarded %s checkpoint (%d items)\n", operation, n)
+		}
+		return cp, nil
+	}
+	done, err := sqlStore.CheckpointedItems(ctx, operation)
+	if err != nil {
+		return nil, err
+	}
+	cp.done = done
+	return cp, nil
+}
+
+// has reports whether key was processed by an earlier run.
+func (cp *batchCheckpoint) has(key string) bool { return cp.done[key] }
+
+// resumed returns how many items earlier runs processed.
+func (cp *batchCheckpoint) resumed() int { return len(cp.done) }
+
+// record marks keys as processed.
+func (cp *batchCheckpoint) record(ctx context.Context, keys ...string) error {
+	if cp.st == nil {
+		return nil
+	}
+	if err := cp.st.RecordCheckpoint(context.WithoutCancel(ctx), cp.operation, keys...); err != nil {
+		return err
+	}
+	cp.recorded += len(keys)
+	return nil
+}
+
+// finish clears the checkpoint once all pending items of this run have been
+// recorded; otherwise it tells the user how to resume.
+func (cp *batchCheckpoint) finish(ctx context.Context, pending int, unit string) {
+	if cp.st == nil {
+		return
+	}
+	if cp.recorded >= pending {
+		if _, err := cp.st.ClearCheckpoint(context.WithoutCancel(ctx), cp.operation); err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: clearing %s checkpoint: %v\n", cp.operation, err)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n  Progress saved: %d of %d %s done this run. Re-run the same command to resume (--restart starts over).\n",
+		cp.recorded, pending, unit)
+}
+
+// announceResume prints how much an earlier run already covered.
+func (cp *batchCheckpoint) announceResume(unit string) {
+	if n := cp.resumed(); n > 0 {
+		fmt.Fprintf(os.Stderr, "Resuming %s: skipping %d %s processed by an earlier run (--restart starts over)\n",
+			cp.operation, n, unit)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestBatchCheckpoint_ResumeFinishRestart(t *testing.T) {
+	ctx := context.Background()
+	s, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	cp, err := openBatchCheckpoint(ctx, s, "classify", false, false)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if err := cp.record(ctx, classifyCheckpointKey(1), classifyCheckpointKey(2)); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	cp.finish(ctx, 5, "facts") // interrupted: 2 of 5 done
+
+	cp, err = openBatchCheckpoint(ctx, s, "classify", false, false)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if cp.resumed() != 2 || !cp.has("fact:1") || cp.has("fact:3") {
+		t.Fatalf("expected facts 1 and 2 to be checkpointed, got %v", cp.done)
+	}
+	if err := cp.record(ctx, "fact:3", "fact:4", "fact:5"); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	cp.finish(ctx, 3, "facts") // the resumed run covered everything left
+
+	if cp, _ = openBatchCheckpoint(ctx, s, "classify", false, false); cp.resumed() != 0 {
+		t.Fatalf("checkpoint should be cleared after a complete run, got %v", cp.done)
+	}
+
+	_ = cp.record(ctx, "fact:9")
+	if cp, _ = openBatchCheckpoint(ctx, s, "classify", true, false); cp.resumed() != 0 {
+		t.Fatalf("--restart should discard the checkpoint, got %v", cp.done)
+	}
+
+	dry, _ := openBatchCheckpoint(ctx, s, "classify", false, true)
+	_ = dry.record(ctx, "fact:10")
+	if cp, _ = openBatchCheckpoint(ctx, s, "classify", false, false); cp.has("fact:10") {
+		t.Fatal("dry runs must not write checkpoints")
+	}
+}
+
+func TestConflictCheckpointKey_OrderIndependent(t *testing.T) {
+	if conflictCheckpointKey(9, 3) != conflictCheckpointKey(3, 9) || conflictCheckpointKey(3, 9) != "pair:3-9" {
+		t.Fatalf("unexpected keys %q / %q", conflictCheckpointKey(9, 3), conflictCheckpointKey(3, 9))
+	}
+}
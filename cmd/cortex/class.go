@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// classQueryLimit is how many keyword hits `cortex class set --query`
+// reclassifies by default.
+const classQueryLimit = 100
+
+func classUsageText() string {
+	return `Usage:
+  cortex class set <class|none> [--source <glob>] [--project <name>] [--query "<text>" [--limit N]]
+                   [--unclassified | --from <class>] [--dry-run] [--json]
+
+Reassigns the class of existing memories in bulk. Filters are combined; at
+least one of --source, --project, or --query is required. --query
+reclassifies the top keyword-search hits (--limit, default ` + strconv.Itoa(classQueryLimit) + `).
+--unclassified and --from narrow the change to memories with no class or a
+given class. "none" clears the class. --dry-run prints the counts without
+writing.
+
+Classes: ` + strings.Join(store.AvailableMemoryClasses(), ", ") + `
+
+Examples:
+  cortex class set rule --source "~/notes/runbooks/*" --unclassified --dry-run
+  cortex class set decision --query "we decided" --project ops --limit 50`
+}
+
+func runClass(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(classUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "set":
+		return runClassSet(args[1:])
+	default:
+		return fmt.Errorf("unknown class subcommand: %s", args[0])
+	}
+}
+
+type classSetJSON struct {
+	*store.MemoryClassPlan
+	DryRun  bool  `json:"dry_run"`
+	Updated int64 `json:"updated"`
+}
+
+// runClassSet handles `cortex class set`.
+func runClassSet(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: cortex class set <class|none> [--source <glob>] [--project <name>] [--query \"...\"] [--dry-run]")
+	}
+	class := store.NormalizeMemoryClass(args[0])
+	if class == "none" {
+		class = ""
+	} else if !store.IsValidMemoryClass(class) {
+		return fmt.Errorf("invalid class %q (valid: %s, none)", args[0], strings.Join(store.AvailableMemoryClasses(), ", "))
+	}
+
+	var filter store.MemoryClassFilter
+	source, query := "", ""
+	limit := classQueryLimit
+	dryRun, jsonOutput := false, false
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == "--source" && i+1 < len(args):
+			i++
+			source = args[i]
+		case strings.HasPrefix(args[i], "--source="):
+			source = strings.TrimPrefix(args[i], "--source=")
+		case args[i] == "--project" && i+1 < len(args):
+			i++
+			filter.Project = args[i]
+		case strings.HasPrefix(args[i], "--project="):
+			filter.Project = strings.TrimPrefix(args[i], "--project=")
+		case args[i] == "--query" && i+1 < len(args):
+			i++
+			query = args[i]
+		case strings.HasPrefix(args[i], "--query="):
+			query = strings.TrimPrefix(args[i], "--query=")
+		case args[i] == "--limit" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid --limit value: %s", args[i])
+			}
+			limit = n
+		case strings.HasPrefix(args[i], "--limit="):
+			n, err := strconv.Atoi(strings.TrimPrefix(args[i], "--limit="))
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid --limit value: %s", args[i])
+			}
+			limit = n
+		case args[i] == "--from" && i+1 < len(args):
+			i++
+			filter.FromClass = args[i]
+		case strings.HasPrefix(args[i], "--from="):
+			filter.FromClass = strings.TrimPrefix(args[i], "--from=")
+		case args[i] == "--unclassified":
+			filter.Unclassified = true
+		case args[i] == "--dry-run":
+			dryRun = true
+		case args[i] == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	if source == "" && filter.Project == "" && strings.TrimSpace(query) == "" {
+		return fmt.Errorf("specify --source, --project, or --query to select memories")
+	}
+	if filter.FromClass != "" && !store.IsValidMemoryClass(filter.FromClass) {
+		return fmt.Errorf("invalid --from class %q (valid: %s)", filter.FromClass, strings.Join(store.AvailableMemoryClasses(), ", "))
+	}
+	if source != "" {
+		filter.SourcePattern = strings.ReplaceAll(expandUserPath(source), "*", "%")
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	ss, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("class set requires SQLiteStore")
+	}
+	ctx := context.Background()
+
+	var describe []string
+	if source != "" {
+		describe = append(describe, "source="+source)
+	}
+	if filter.Project != "" {
+		describe = append(describe, "project="+filter.Project)
+	}
+	if query = strings.TrimSpace(query); query != "" {
+		describe = append(describe, fmt.Sprintf("query=%q", query))
+		results, err := search.NewEngine(s).Search(ctx, query, search.Options{
+			Mode:          search.ModeKeyword,
+			Limit:         limit,
+			MinScore:      -1,
+			Project:       filter.Project,
+			DisableDedupe: true,
+		})
+		if err != nil {
+			return fmt.Errorf("searching %q: %w", query, err)
+		}
+		filter.IDs = []int64{}
+		seen := map[int64]bool{}
+		for _, r := range results {
+			if r.Kind == "" && r.MemoryID > 0 && !seen[r.MemoryID] {
+				seen[r.MemoryID] = true
+				filter.IDs = append(filter.IDs, r.MemoryID)
+			}
+		}
+	}
+
+	plan, err := ss.PlanMemoryClassChange(ctx, class, filter)
+	if err != nil {
+		return err
+	}
+	var updated int64
+	if !dryRun && plan.Changes > 0 {
+		updated, err = ss.SetMemoryClassBulk(ctx, class, filter, strings.Join(describe, " "))
+		if err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(classSetJSON{MemoryClassPlan: plan, DryRun: dryRun, Updated: updated}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	target := class
+	if target == "" {
+		target = "(none)"
+	}
+	verb := "Reclassified"
+	count := int(updated)
+	if dryRun {
+		verb, count = "Would reclassify", plan.Changes
+	}
+	fmt.Printf("%s %d of %d matching %s → %s\n", verb, count, plan.Matched,
+		pluralize("memory", "memories", plan.Matched), target)
+	from := make([]string, 0, len(plan.ByClass))
+	for c := range plan.ByClass {
+		from = append(from, c)
+	}
+	sort.Slice(from, func(i, j int) bool {
+		if plan.ByClass[from[i]] != plan.ByClass[from[j]] {
+			return plan.ByClass[from[i]] > plan.ByClass[from[j]]
+		}
+		return from[i] < from[j]
+	})
+	for _, c := range from {
+		label := c
+		if label == "" {
+			label = "unclassified"
+		}
+		fmt.Printf("  %-14s %d\n", label, plan.ByClass[c])
+	}
+	if skipped := plan.Matched - plan.Changes; skipped > 0 {
+		fmt.Printf("  %d already %s\n", skipped, target)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunClassSet_DryRunSourceAndQuery(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	runbook := filepath.Join(home, "runbooks", "deploy.md")
+	for _, m := range []*store.Memory{
+		{Content: "Always drain the node before a kernel upgrade.", SourceFile: runbook},
+		{Content: "Rotate the signing keys every quarter.", SourceFile: runbook, MemoryClass: "status"},
+		{Content: "We decided to adopt Postgres for the ledger.", SourceFile: filepath.Join(home, "chat.md"), Project: "ops"},
+		{Content: "Lunch was great today.", SourceFile: filepath.Join(home, "chat.md"), Project: "ops"},
+	} {
+		if _, err := s.AddMemory(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	var runErr error
+	out := captureStdout(func() {
+		runErr = runClass([]string{"set", "rule", "--source", "~/runbooks/*", "--unclassified", "--dry-run"})
+	})
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+	if !strings.Contains(out, "Would reclassify 1 of 1 matching memory → rule") || !strings.Contains(out, "unclassified") {
+		t.Fatalf("unexpected dry-run output:\n%s", out)
+	}
+
+	out = captureStdout(func() { runErr = runClass([]string{"set", "rule", "--source", "~/runbooks/*", "--json"}) })
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+	var res classSetJSON
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if res.Updated != 2 || res.ByClass["status"] != 1 || res.DryRun {
+		t.Fatalf("unexpected result: %s", out)
+	}
+
+	out = captureStdout(func() {
+		runErr = runClass([]string{"set", "decision", "--query", "decided Postgres", "--project", "ops"})
+	})
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+	if !strings.Contains(out, "Reclassified 1 of 1 matching memory → decision") {
+		t.Fatalf("unexpected query output:\n%s", out)
+	}
+
+	if err := runClass([]string{"set", "rule", "--unclassified"}); err == nil || !strings.Contains(err.Error(), "--source, --project, or --query") {
+		t.Fatalf("expected selector error, got %v", err)
+	}
+	if err := runClass([]string{"set", "bogus", "--project", "ops"}); err == nil {
+		t.Fatal("expected invalid class error")
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func compactUsageText() string {
+	return `Usage: cortex compact --older-than <age> [--classes a,b] [--limit N] [--dry-run] [--json]
+       cortex compact restore <memory-id>... | --all [--json]
+       cortex compact show <memory-id>
+       cortex compact status [--json]
+
+Once facts are extracted, old raw memories (chat transcripts, scratch notes)
+mostly take up space. Compaction moves their content into a compressed
+archive table and leaves a one-line stub in its place. Facts, embeddings,
+and the memory row itself are kept, so recall and search over facts work as
+before; keyword search no longer matches the raw text. Only memories with at
+least one extracted fact are compacted, never memories under archive hold.
+
+Run 'cortex optimize --vacuum-only' afterwards to shrink the database file.
+
+Flags:
+  --older-than <age>   Only memories imported longer ago than this (e.g. 90d, 12w)
+  --classes <list>     Only these memory classes (e.g. scratch,status); default any
+  --min-bytes <n>      Skip memories smaller than this (default 512)
+  --limit <n>          Compact at most N memories
+  --dry-run            Report what would be compacted and saved
+  --json               Machine-readable output
+
+Examples:
+  cortex compact --older-than 90d --classes scratch,status --dry-run
+  cortex compact --older-than 90d --classes scratch,status
+  cortex compact restore 1842`
+}
+
+func runCompact(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "--help", "-h", "help":
+			fmt.Println(compactUsageText())
+			return nil
+		case "restore":
+			return runCompactRestore(args[1:])
+		case "show":
+			return runCompactShow(args[1:])
+		case "status":
+			return runCompactStatus(args[1:])
+		}
+	}
+
+	var opts store.CompactOptions
+	var olderThan string
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--older-than" && i+1 < len(args):
+			i++
+			olderThan = args[i]
+		case strings.HasPrefix(arg, "--older-than="):
+			olderThan = strings.TrimPrefix(arg, "--older-than=")
+		case arg == "--classes" && i+1 < len(args):
+			i++
+			classes, err := store.ParseMemoryClassList(args[i])
+			if err != nil {
+				return err
+			}
+			opts.Classes = classes
+		case strings.HasPrefix(arg, "--classes="):
+			classes, err := store.ParseMemoryClassList(strings.TrimPrefix(arg, "--classes="))
+			if err != nil {
+				return err
+			}
+			opts.Classes = classes
+		case arg == "--min-bytes" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid --min-bytes value: %s", args[i])
+			}
+			opts.MinBytes = n
+		case arg == "--limit" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid --limit value: %s", args[i])
+			}
+			opts.Limit = n
+		case arg == "--dry-run":
+			opts.DryRun = true
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if olderThan == "" {
+		return fmt.Errorf("usage: cortex compact --older-than <age> [--classes a,b] [--limit N] [--dry-run] [--json]")
+	}
+	age, err := parseSinceDuration(olderThan)
+	if err != nil {
+		return fmt.Errorf("invalid --older-than value: %w", err)
+	}
+	opts.OlderThan = time.Now().Add(-age)
+
+	ss, err := openCompactStore()
+	if err != nil {
+		return err
+	}
+	defer ss.Close()
+
+	report, err := ss.CompactMemories(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+	if jsonOutput || !isTTY() {
+		return writeCompactJSON(report)
+	}
+
+	if report.Compacted == 0 {
+		fmt.Println("No memories to compact.")
+		return nil
+	}
+	verb := "Compacted"
+	if report.DryRun {
+		verb = "Would compact"
+	}
+	fmt.Printf("%s %d memories: %s of raw content → %s archived, %s saved\n",
+		verb, report.Compacted, formatBytes(report.OriginalBytes), formatBytes(report.ArchivedBytes), formatBytes(report.SavedBytes))
+	if report.Skipped > 0 {
+		fmt.Printf("Skipped %d memories that would not shrink.\n", report.Skipped)
+	}
+	if !report.DryRun {
+		fmt.Println("Restore with 'cortex compact restore <id>'; run 'cortex optimize --vacuum-only' to shrink the file.")
+	}
+	return nil
+}
+
+func runCompactRestore(args []string) error {
+	var ids []int64
+	all, jsonOutput := false, false
+	for _, arg := range args {
+		switch {
+		case arg == "--all":
+			all = true
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			id, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+			if err != nil || id <= 0 {
+				return fmt.Errorf("invalid memory id: %s", arg)
+			}
+			ids = append(ids, id)
+		}
+	}
+	if all == (len(ids) > 0) {
+		return fmt.Errorf("usage: cortex compact restore <memory-id>... | --all [--json]")
+	}
+
+	ss, err := openCompactStore()
+	if err != nil {
+		return err
+	}
+	defer ss.Close()
+
+	restored, err := ss.RestoreCompactedMemories(context.Background(), ids)
+	if err != nil {
+		return err
+	}
+	if jsonOutput || !isTTY() {
+		if restored == nil {
+			restored = []int64{}
+		}
+		return writeCompactJSON(map[string]interface{}{"restored": restored})
+	}
+	if len(restored) == 0 {
+		fmt.Println("Nothing to restore: no matching compacted memories.")
+		return nil
+	}
+	fmt.Printf("Restored %d memories.\n", len(restored))
+	return nil
+}
+
+func runCompactShow(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: cortex compact show <memory-id>")
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid memory id: %s", args[0])
+	}
+
+	ss, err := openCompactStore()
+	if err != nil {
+		return err
+	}
+	defer ss.Close()
+
+	content, ok, err := ss.ArchivedMemoryContent(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("memory %d is not compacted", id)
+	}
+	fmt.Println(content)
+	return nil
+}
+
+func runCompactStatus(args []string) error {
+	jsonOutput := false
+	for _, arg := range args {
+		switch {
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	ss, err := openCompactStore()
+	if err != nil {
+		return err
+	}
+	defer ss.Close()
+
+	stats, err := ss.GetCompactionStats(context.Background())
+	if err != nil {
+		return err
+	}
+	if jsonOutput || !isTTY() {
+		return writeCompactJSON(stats)
+	}
+	if stats.Memories == 0 {
+		fmt.Println("No compacted memories.")
+		return nil
+	}
+	fmt.Printf("%d compacted memories: %s of raw content stored as %s\n",
+		stats.Memories, formatBytes(stats.OriginalBytes), formatBytes(stats.ArchivedBytes))
+	return nil
+}
+
+func openCompactStore() (*store.SQLiteStore, error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("opening store: %w", err)
+	}
+	ss, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, fmt.Errorf("compact requires SQLite store")
+	}
+	return ss, nil
+}
+
+func writeCompactJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunCompact(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := strings.Repeat("long transcript line about the migration plan. ", 50)
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: content, MemoryClass: store.MemoryClassScratch})
+	if _, err := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "migration", Predicate: "status", Object: "planned", FactType: "state"}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	run := func(args ...string) string {
+		t.Helper()
+		var runErr error
+		out := captureStdout(func() { runErr = runCompact(args) })
+		if runErr != nil {
+			t.Fatalf("runCompact(%v): %v", args, runErr)
+		}
+		return out
+	}
+
+	// --older-than 0d covers everything imported before now.
+	var report store.CompactionReport
+	if err := json.Unmarshal([]byte(run("--older-than", "0d", "--classes", "scratch", "--json")), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Compacted != 1 || report.SavedBytes <= 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if out := run("show", "1"); strings.TrimSpace(out) != strings.TrimSpace(content) {
+		t.Fatalf("show printed %q", out)
+	}
+	var stats store.CompactionStats
+	if err := json.Unmarshal([]byte(run("status", "--json")), &stats); err != nil || stats.Memories != 1 {
+		t.Fatalf("status = %+v, %v", stats, err)
+	}
+	if out := run("restore", "--all", "--json"); !strings.Contains(out, `"restored": [`) {
+		t.Fatalf("restore printed %q", out)
+	}
+}
+
+func TestRunCompact_Errors(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"--older-than", "soon"},
+		{"--older-than", "90d", "--classes", "chatter"},
+		{"--older-than", "90d", "--force"},
+		{"restore"},
+		{"restore", "3", "--all"},
+		{"show"},
+	} {
+		if err := runCompact(args); err == nil {
+			t.Errorf("runCompact(%v): expected error", args)
+		}
+	}
+}
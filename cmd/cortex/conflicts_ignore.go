@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func conflictsIgnoreUsageText() string {
+	return `Usage:
+  cortex conflicts ignore <fact1> <fact2> [--reason "text"] [--json]
+  cortex conflicts ignore list [--json]
+  cortex conflicts unignore <fact1> <fact2>
+
+Accepted disagreements: ignored pairs are left out of conflict detection,
+health reports, lifecycle conflict supersedes, and alerts. Open conflict
+alerts for the pair are acknowledged when it is ignored.`
+}
+
+// runConflictsIgnore handles `cortex conflicts ignore`.
+func runConflictsIgnore(args []string) error {
+	if len(args) > 0 && (args[0] == "--help" || args[0] == "-h" || args[0] == "help") {
+		fmt.Println(conflictsIgnoreUsageText())
+		return nil
+	}
+	if len(args) > 0 && args[0] == "list" {
+		return runConflictsIgnoreList(args[1:])
+	}
+
+	reason := ""
+	jsonOutput := false
+	var ids []int64
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--reason" && i+1 < len(args):
+			i++
+			reason = args[i]
+		case strings.HasPrefix(args[i], "--reason="):
+			reason = strings.TrimPrefix(args[i], "--reason=")
+		case args[i] == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		default:
+			id, err := parseConflictFactID(args[i])
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) != 2 {
+		return fmt.Errorf("usage: cortex conflicts ignore <fact1> <fact2> [--reason \"text\"]")
+	}
+
+	ss, closeStore, err := openConflictExceptionStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	exception, err := ss.IgnoreConflict(context.Background(), ids[0], ids[1], reason)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		data, _ := json.MarshalIndent(exception, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Printf("Ignoring conflict between facts %d and %d", exception.Fact1ID, exception.Fact2ID)
+	if exception.Reason != "" {
+		fmt.Printf(" (%s)", exception.Reason)
+	}
+	fmt.Println()
+	return nil
+}
+
+// runConflictsUnignore handles `cortex conflicts unignore`.
+func runConflictsUnignore(args []string) error {
+	var ids []int64
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			return fmt.Errorf("unknown flag: %s", arg)
+		}
+		id, err := parseConflictFactID(arg)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) != 2 {
+		return fmt.Errorf("usage: cortex conflicts unignore <fact1> <fact2>")
+	}
+
+	ss, closeStore, err := openConflictExceptionStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	removed, err := ss.UnignoreConflict(context.Background(), ids[0], ids[1])
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("facts %d and %d are not an ignored pair", ids[0], ids[1])
+	}
+	fmt.Printf("Conflict between facts %d and %d will be detected again\n", ids[0], ids[1])
+	return nil
+}
+
+type conflictExceptionView struct {
+	store.ConflictException
+	Fact1 string `json:"fact1,omitempty"`
+	Fact2 string `json:"fact2,omitempty"`
+}
+
+func runConflictsIgnoreList(args []string) error {
+	jsonOutput := false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOutput = true
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return fmt.Errorf("unknown flag: %s", arg)
+			}
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	ss, closeStore, err := openConflictExceptionStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	exceptions, err := ss.ListConflictExceptions(ctx)
+	if err != nil {
+		return err
+	}
+	factText := func(id int64) string {
+		f, err := ss.GetFact(ctx, id)
+		if err != nil || f == nil {
+			return ""
+		}
+		return formatFactText(f.Subject, f.Predicate, f.Object)
+	}
+	views := make([]conflictExceptionView, 0, len(exceptions))
+	for _, e := range exceptions {
+		views = append(views, conflictExceptionView{ConflictException: e, Fact1: factText(e.Fact1ID), Fact2: factText(e.Fact2ID)})
+	}
+
+	if jsonOutput || !isTTY() {
+		data, _ := json.MarshalIndent(views, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(views) == 0 {
+		fmt.Println("No ignored conflicts.")
+		return nil
+	}
+	for _, v := range views {
+		fmt.Printf("  %d ↔ %d  %s\n", v.Fact1ID, v.Fact2ID, v.CreatedAt.Format("2006-01-02"))
+		fmt.Printf("     \"%s\"\n     \"%s\"\n", truncateString(v.Fact1, 80), truncateString(v.Fact2, 80))
+		if v.Reason != "" {
+			fmt.Printf("     reason: %s\n", v.Reason)
+		}
+	}
+	fmt.Printf("\n%d ignored %s\n", len(views), pluralize("pair", "pairs", len(views)))
+	return nil
+}
+
+func parseConflictFactID(raw string) (int64, error) {
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid fact id: %s", raw)
+	}
+	return id, nil
+}
+
+func openConflictExceptionStore() (*store.SQLiteStore, func(), error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening store: %w", err)
+	}
+	ss, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, nil, fmt.Errorf("conflict exceptions require SQLiteStore")
+	}
+	return ss, func() { s.Close() }, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunConflictsIgnore_ListAndUnignore(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "homes"})
+	a, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "Q", Predicate: "lives_in", Object: "Philadelphia", FactType: "identity"})
+	b, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "Q", Predicate: "lives_in", Object: "New York", FactType: "identity"})
+	s.Close()
+
+	ids := []string{strconv.FormatInt(b, 10), strconv.FormatInt(a, 10)}
+	out := captureStdout(func() {
+		if err := runConflicts(append([]string{"ignore"}, append(ids, "--reason", "two homes")...)); err != nil {
+			t.Fatalf("ignore: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Ignoring conflict between facts "+strconv.FormatInt(a, 10)+" and "+strconv.FormatInt(b, 10)) || !strings.Contains(out, "two homes") {
+		t.Fatalf("unexpected ignore output:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runConflicts([]string{"ignore", "list", "--json"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"reason": "two homes"`) || !strings.Contains(out, "Philadelphia") {
+		t.Fatalf("unexpected list output:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runConflicts([]string{"--json"}); err != nil {
+			t.Fatalf("conflicts: %v", err)
+		}
+	})
+	if strings.Contains(out, "New York") {
+		t.Fatalf("ignored pair still reported:\n%s", out)
+	}
+
+	if err := runConflicts(append([]string{"unignore"}, ids...)); err != nil {
+		t.Fatalf("unignore: %v", err)
+	}
+	if err := runConflicts(append([]string{"unignore"}, ids...)); err == nil {
+		t.Fatal("expected error unignoring a pair that is not ignored")
+	}
+	if err := runConflicts([]string{"ignore", strconv.FormatInt(a, 10)}); err == nil {
+		t.Fatal("expected usage error with one fact id")
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/connect"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// statsTrendSyncs is how many recent syncs cortex stats charts per connector.
+const statsTrendSyncs = 20
+
+func runConnectHistory(args []string) error {
+	provider := ""
+	limit := 20
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--limit" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid --limit value: %s", args[i])
+			}
+			limit = n
+		case strings.HasPrefix(args[i], "--limit="):
+			n, err := strconv.Atoi(strings.TrimPrefix(args[i], "--limit="))
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid --limit value: %s", strings.TrimPrefix(args[i], "--limit="))
+			}
+			limit = n
+		case args[i] == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		case provider == "":
+			provider = args[i]
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	if provider == "" {
+		return fmt.Errorf("usage: cortex connect history <provider> [--limit N] [--json]")
+	}
+
+	st, err := store.NewStore(store.StoreConfig{DBPath: getDBPath(), ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer st.Close()
+	sqliteSt, ok := st.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("connector operations require SQLite store")
+	}
+	cs := connect.NewConnectorStore(sqliteSt.GetDB())
+
+	ctx := context.Background()
+	if _, err := cs.Get(ctx, provider); err != nil {
+		return fmt.Errorf("connector %q not found", provider)
+	}
+	history, err := cs.History(ctx, provider, limit)
+	if err != nil {
+		return err
+	}
+	spike := connect.DetectSkipSpike(history)
+
+	if jsonOutput || !isTTY() {
+		if history == nil {
+			history = []connect.SyncHistoryEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"provider":   provider,
+			"syncs":      history,
+			"skip_spike": spike,
+		})
+	}
+
+	if len(history) == 0 {
+		fmt.Printf("No syncs recorded for %s yet.\n", provider)
+		fmt.Printf("  cortex connect sync --provider %s\n", provider)
+		return nil
+	}
+	fmt.Printf("Sync history: %s (last %d)\n\n", provider, len(history))
+	fmt.Printf("  %-16s  %-6s  %7s  %8s  %7s  %5s  %8s\n", "STARTED", "STATUS", "FETCHED", "IMPORTED", "SKIPPED", "SKIP%", "DURATION")
+	for _, e := range history {
+		fmt.Printf("  %-16s  %-6s  %7d  %8d  %7d  %4.0f%%  %8s\n",
+			e.StartedAt.Local().Format("2006-01-02 15:04"), e.Status,
+			e.RecordsFetched, e.RecordsImported, e.RecordsSkipped, e.SkipRatio()*100,
+			e.Duration.Round(time.Millisecond))
+		if e.Error != "" {
+			fmt.Printf("    ⚠ %s\n", e.Error)
+		}
+	}
+	if spike != nil {
+		fmt.Printf("\n⚠ Skip ratio spike: %s\n", spike)
+	}
+	return nil
+}
+
+// loadConnectorTrends returns a SyncTrend per connector that has synced,
+// or nil when s is not a SQLite store.
+func loadConnectorTrends(ctx context.Context, s store.Store) ([]connect.SyncTrend, error) {
+	sqliteSt, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return nil, nil
+	}
+	cs := connect.NewConnectorStore(sqliteSt.GetDB())
+	connectors, err := cs.List(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	var trends []connect.SyncTrend
+	for _, c := range connectors {
+		history, err := cs.History(ctx, c.Provider, statsTrendSyncs)
+		if err != nil {
+			return nil, err
+		}
+		if len(history) > 0 {
+			trends = append(trends, connect.TrendFromHistory(c.Provider, history))
+		}
+	}
+	return trends, nil
+}
+
+// sparkline renders values as a row of block characters scaled to the
+// largest value.
+func sparkline(values []int) string {
+	const levels = "▁▂▃▄▅▆▇█"
+	blocks := []rune(levels)
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if max > 0 && v > 0 {
+			i = (v*(len(blocks)-1) + max - 1) / max
+		}
+		b.WriteRune(blocks[i])
+	}
+	return b.String()
+}
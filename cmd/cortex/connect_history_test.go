@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/connect"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]int{0, 1, 4, 8}); got != "▁▂▅█" {
+		t.Fatalf("sparkline = %q", got)
+	}
+	if got := sparkline([]int{0, 0}); got != "▁▁" {
+		t.Fatalf("all-zero sparkline = %q", got)
+	}
+}
+
+func TestRunConnectHistory_JSONAndTrends(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dbPath := filepath.Join(t.TempDir(), "cortex.db")
+	oldDBPath := globalDBPath
+	globalDBPath = dbPath
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, err := store.NewStore(store.StoreConfig{DBPath: dbPath})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	ctx := context.Background()
+	cs := connect.NewConnectorStore(s.(*store.SQLiteStore).GetDB())
+	if _, err := cs.Add(ctx, "slack", nil); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(-time.Hour)
+	for i, r := range []struct{ fetched, imported, skipped int }{{20, 18, 2}, {30, 27, 3}, {10, 10, 0}, {40, 4, 36}} {
+		if err := cs.RecordSyncHistory(ctx, connect.SyncResult{
+			Provider: "slack", SyncedAt: start.Add(time.Duration(i) * time.Minute),
+			RecordsFetched: r.fetched, RecordsImported: r.imported, RecordsSkipped: r.skipped,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	trends, err := loadConnectorTrends(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trends) != 1 || trends[0].Syncs != 4 || trends[0].Imported[3] != 4 || trends[0].Spike == nil {
+		t.Fatalf("unexpected trends: %+v", trends)
+	}
+	s.Close()
+
+	var runErr error
+	out := captureStdout(func() { runErr = runConnectHistory([]string{"slack", "--limit", "3", "--json"}) })
+	if runErr != nil {
+		t.Fatalf("runConnectHistory: %v", runErr)
+	}
+	var payload struct {
+		Syncs     []connect.SyncHistoryEntry `json:"syncs"`
+		SkipSpike *connect.SkipSpike         `json:"skip_spike"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if len(payload.Syncs) != 3 || payload.Syncs[0].RecordsSkipped != 36 {
+		t.Fatalf("expected the 3 newest syncs, newest first: %+v", payload.Syncs)
+	}
+	// Three rows leave only two baseline syncs, too few to call a spike.
+	if payload.SkipSpike != nil {
+		t.Fatalf("unexpected spike with a short window: %+v", payload.SkipSpike)
+	}
+
+	if err := runConnectHistory([]string{"nope"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if err := runConnectHistory(nil); err == nil {
+		t.Fatal("expected a usage error")
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseConsensusModels splits the --llm list of --resolve consensus and
+// checks that quorum (0 = majority) is a majority of it.
+func parseConsensusModels(llmFlag string, quorum int) ([]string, error) {
+	var models []string
+	seen := map[string]bool{}
+	for _, m := range strings.Split(llmFlag, ",") {
+		m = strings.TrimSpace(m)
+		if m == "" || seen[m] {
+			continue
+		}
+		seen[m] = true
+		models = append(models, m)
+	}
+	if len(models) < 2 {
+		return nil, fmt.Errorf("--resolve consensus needs two or more different models: --llm <model1>,<model2>[,...]")
+	}
+	if quorum != 0 && (quorum <= len(models)/2 || quorum > len(models)) {
+		return nil, fmt.Errorf("invalid --quorum %d: must be a majority of the %d models (%d-%d)", quorum, len(models), len(models)/2+1, len(models))
+	}
+	return models, nil
+}
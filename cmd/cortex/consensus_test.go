@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConsensusModels(t *testing.T) {
+	models, err := parseConsensusModels(" openrouter/a, openrouter/b ,openrouter/a,ollama/c", 0)
+	if err != nil || strings.Join(models, "|") != "openrouter/a|openrouter/b|ollama/c" {
+		t.Fatalf("parseConsensusModels = %v, %v", models, err)
+	}
+	if _, err := parseConsensusModels("openrouter/a,openrouter/b,ollama/c", 3); err != nil {
+		t.Fatalf("unanimity should be allowed: %v", err)
+	}
+	for _, tc := range []struct {
+		flag   string
+		quorum int
+	}{
+		{"openrouter/a", 0},
+		{"openrouter/a,openrouter/a", 0},
+		{"openrouter/a,openrouter/b,ollama/c", 1},
+		{"openrouter/a,openrouter/b,ollama/c", 4},
+		{"openrouter/a,openrouter/b,ollama/c,ollama/d", 2},
+	} {
+		if _, err := parseConsensusModels(tc.flag, tc.quorum); err == nil {
+			t.Errorf("expected error for %q with quorum %d", tc.flag, tc.quorum)
+		}
+	}
+}
+
+func TestRunConflicts_ConsensusFlagValidation(t *testing.T) {
+	if err := runConflicts([]string{"--quorum", "2"}); err == nil || !strings.Contains(err.Error(), "--resolve consensus") {
+		t.Fatalf("--quorum without consensus: %v", err)
+	}
+}
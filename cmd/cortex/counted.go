@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// countedJSON is the envelope JSON output switches to with --count. Total
+// covers every match, not just the returned page; Estimated marks totals
+// that stopped at a scan cap and may be higher.
+type countedJSON struct {
+	Total      int    `json:"total"`
+	Estimated  bool   `json:"estimated,omitempty"`
+	Returned   int    `json:"returned"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Results    any    `json:"results"`
+}
+
+func outputCountedJSON(results any, returned, total int, estimated bool, nextCursor string) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(countedJSON{
+		Total:      total,
+		Estimated:  estimated,
+		Returned:   returned,
+		NextCursor: nextCursor,
+		Results:    results,
+	})
+}
+
+// printCountFooter is the terminal counterpart of countedJSON.
+func printCountFooter(noun string, returned, total int, estimated bool) {
+	if estimated {
+		fmt.Printf("\nShowing %d of at least %d %s (estimated)\n", returned, total, noun)
+		return
+	}
+	fmt.Printf("\nShowing %d of %d %s\n", returned, total, noun)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+type countedTestEnvelope struct {
+	Total      int               `json:"total"`
+	Estimated  bool              `json:"estimated"`
+	Returned   int               `json:"returned"`
+	NextCursor string            `json:"next_cursor"`
+	Results    []json.RawMessage `json:"results"`
+}
+
+func TestCountFlag_ListSearchAndStaleEnvelopes(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		if _, err := s.AddMemory(ctx, &store.Memory{Content: fmt.Sprintf("Failover drill %d for the primary database", i), SourceFile: fmt.Sprintf("drill-%d.md", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	parse := func(out string) countedTestEnvelope {
+		t.Helper()
+		var env countedTestEnvelope
+		if err := json.Unmarshal([]byte(out), &env); err != nil {
+			t.Fatalf("parse envelope: %v\n%s", err, out)
+		}
+		return env
+	}
+
+	out := captureStdout(func() {
+		if err := runList([]string{"--limit", "3", "--count", "--json"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	page := parse(out)
+	if page.Total != 4 || page.Returned != 3 || len(page.Results) != 3 || page.NextCursor == "" {
+		t.Fatalf("unexpected list envelope: %+v", page)
+	}
+	out = captureStdout(func() {
+		if err := runList([]string{"--limit", "3", "--count", "--json", "--cursor", page.NextCursor}); err != nil {
+			t.Fatalf("list page 2: %v", err)
+		}
+	})
+	if next := parse(out); next.Total != 4 || next.Returned != 1 || next.NextCursor != "" {
+		t.Fatalf("unexpected second list page: %+v", next)
+	}
+
+	out = captureStdout(func() {
+		if err := runSearch([]string{"failover", "--mode", "keyword", "--limit", "1", "--no-dedupe", "--count", "--json"}); err != nil {
+			t.Fatalf("search: %v", err)
+		}
+	})
+	if hits := parse(out); hits.Total != 4 || hits.Returned != 1 || hits.Estimated || hits.NextCursor == "" {
+		t.Fatalf("unexpected search envelope: %+v", hits)
+	}
+	if err := runSearch([]string{"failover", "--count", "--group-by", "source"}); err == nil {
+		t.Fatal("expected --count/--group-by conflict")
+	}
+
+	out = captureStdout(func() {
+		if err := runStale([]string{"--count", "--json"}); err != nil {
+			t.Fatalf("stale: %v", err)
+		}
+	})
+	if stale := parse(out); stale.Total != 0 || stale.Returned != 0 || stale.Results == nil {
+		t.Fatalf("unexpected stale envelope: %+v", stale)
+	}
+}
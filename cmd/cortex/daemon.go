@@ -0,0 +1,581 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/connect"
+	"github.com/hurttlocker/cortex/internal/daemon"
+	"github.com/hurttlocker/cortex/internal/embed"
+	"github.com/hurttlocker/cortex/internal/graph"
+	"github.com/hurttlocker/cortex/internal/jobs"
+	cortexmcp "github.com/hurttlocker/cortex/internal/mcp"
+	"github.com/hurttlocker/cortex/internal/schedule"
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+	"github.com/hurttlocker/cortex/internal/webhook"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	defaultDaemonPort      = 8090
+	defaultDaemonSyncEvery = 3 * time.Hour
+	daemonIndexRefresh     = 5 * time.Minute
+
+	defaultDaemonIntegrityEvery = 24 * time.Hour
+	// daemonIntegrityDelay keeps the first integrity check out of startup,
+	// when the embed watcher and HNSW warm-up are busiest.
+	daemonIntegrityDelay = 10 * time.Minute
+
+	// defaultDaemonSnapshotEvery refreshes ambient-state connectors (Home
+	// Assistant, MQTT) between full syncs; their snapshots expire in ~1h.
+	defaultDaemonSnapshotEvery = 15 * time.Minute
+)
+
+type daemonOptions struct {
+	port           int
+	embedFlag      string
+	llmFlag        string
+	embedInterval  time.Duration
+	syncEvery      time.Duration
+	snapshotEvery  time.Duration
+	integrityEvery time.Duration
+	extract        bool
+	agentID        string
+	logFile        string
+	noMCP          bool
+	noGraph        bool
+	noEmbed        bool
+	noSync         bool
+	noIntegrity    bool
+	noJobs         bool
+	withSchedules  bool
+}
+
+func daemonUsageText() string {
+	return strings.TrimSpace(`cortex daemon — Run MCP, graph, embed watcher, and connector sync in one process
+
+Usage:
+  cortex daemon [flags]
+  cortex daemon schedules [--json]   List schedules, next runs, and run history
+
+One HTTP listener serves:
+  /sse, /message       MCP over HTTP+SSE
+  /                    Graph explorer UI and /api/* routes
+  /healthz             Per-subsystem health (503 when any subsystem is down)
+  /readyz              Readiness (BM25 immediately; ?require=vectors waits for HNSW)
+  /hooks/{name}        Signed inbound captures (when import.webhook.secret or
+                       CORTEX_WEBHOOK_SECRET is set; see docs/connectors.md)
+
+Flags:
+  --port <N>                 HTTP port (default: 8090)
+  --embed <provider/model>   Embedding provider (default: config / CORTEX_EMBED)
+  --embed-interval <dur>     Embed watcher poll interval (default: 30m)
+  --sync-every <dur>         Connector sync interval (default: 3h, min 5m)
+  --snapshot-every <dur>     Home Assistant/MQTT snapshot interval (default: 15m, min 1m)
+  --integrity-every <dur>    Integrity check interval (default: 24h, min 1h)
+  --extract                  Run fact extraction on connector sync
+  --llm <provider/model>     LLM for enrich jobs (default: none; enrich jobs wait)
+  --agent <id>               Scope MCP operations to this agent
+  --log-file <path>          Append daemon logs to a file (default: stderr)
+  --with-schedules           Run the schedules: list from config.yaml
+  --no-mcp, --no-graph, --no-embed, --no-sync, --no-integrity, --no-jobs
+                             Disable individual subsystems
+
+With --with-schedules the daemon replaces launchd/systemd timers: each entry
+under schedules: names a task (connector_sync, embed, maintain, digest), a
+cron expression ("15 3 * * *", @daily, "@every 2h"), and an optional jitter.
+A run that is still going when its next time comes is skipped, as is one
+already running in another daemon on the same database; every run is
+recorded (see cortex daemon schedules). A connector_sync schedule replaces
+the --sync-every loop and an embed schedule replaces the embed watcher.
+
+The integrity subsystem runs PRAGMA quick_check, compares the FTS and HNSW
+indexes with their base tables, and looks for foreign-key orphans. Failures
+raise an "integrity" alert, sent to the alert webhook when one is configured.
+
+The jobs subsystem runs background jobs queued with cortex jobs start or the
+MCP job tools (extract, cluster_rebuild; embed with an embedding provider;
+enrich with --llm).
+
+Subsystems restart with exponential backoff when they fail; SIGINT/SIGTERM
+shuts everything down gracefully.`)
+}
+
+func parseDaemonArgs(args []string) (daemonOptions, error) {
+	opts := daemonOptions{
+		port:           defaultDaemonPort,
+		embedInterval:  defaultEmbedInterval,
+		syncEvery:      defaultDaemonSyncEvery,
+		snapshotEvery:  defaultDaemonSnapshotEvery,
+		integrityEvery: defaultDaemonIntegrityEvery,
+	}
+	duration := func(name, raw string) (time.Duration, error) {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("invalid %s value: %s", name, raw)
+		}
+		return d, nil
+	}
+
+	for i := 0; i < len(args); i++ {
+		var err error
+		switch {
+		case args[i] == "--port" && i+1 < len(args):
+			i++
+			if opts.port, err = strconv.Atoi(args[i]); err != nil || opts.port <= 0 {
+				return opts, fmt.Errorf("invalid --port value: %s", args[i])
+			}
+		case strings.HasPrefix(args[i], "--port="):
+			raw := strings.TrimPrefix(args[i], "--port=")
+			if opts.port, err = strconv.Atoi(raw); err != nil || opts.port <= 0 {
+				return opts, fmt.Errorf("invalid --port value: %s", raw)
+			}
+		case args[i] == "--embed" && i+1 < len(args):
+			i++
+			opts.embedFlag = args[i]
+		case strings.HasPrefix(args[i], "--embed="):
+			opts.embedFlag = strings.TrimPrefix(args[i], "--embed=")
+		case args[i] == "--llm" && i+1 < len(args):
+			i++
+			opts.llmFlag = args[i]
+		case strings.HasPrefix(args[i], "--llm="):
+			opts.llmFlag = strings.TrimPrefix(args[i], "--llm=")
+		case args[i] == "--embed-interval" && i+1 < len(args):
+			i++
+			if opts.embedInterval, err = duration("--embed-interval", args[i]); err != nil {
+				return opts, err
+			}
+		case args[i] == "--sync-every" && i+1 < len(args):
+			i++
+			if opts.syncEvery, err = duration("--sync-every", args[i]); err != nil {
+				return opts, err
+			}
+		case args[i] == "--snapshot-every" && i+1 < len(args):
+			i++
+			if opts.snapshotEvery, err = duration("--snapshot-every", args[i]); err != nil {
+				return opts, err
+			}
+		case args[i] == "--integrity-every" && i+1 < len(args):
+			i++
+			if opts.integrityEvery, err = duration("--integrity-every", args[i]); err != nil {
+				return opts, err
+			}
+		case args[i] == "--extract":
+			opts.extract = true
+		case args[i] == "--agent" && i+1 < len(args):
+			i++
+			opts.agentID = args[i]
+		case strings.HasPrefix(args[i], "--agent="):
+			opts.agentID = strings.TrimPrefix(args[i], "--agent=")
+		case args[i] == "--log-file" && i+1 < len(args):
+			i++
+			opts.logFile = args[i]
+		case args[i] == "--no-mcp":
+			opts.noMCP = true
+		case args[i] == "--no-graph":
+			opts.noGraph = true
+		case args[i] == "--no-embed":
+			opts.noEmbed = true
+		case args[i] == "--no-sync":
+			opts.noSync = true
+		case args[i] == "--no-integrity":
+			opts.noIntegrity = true
+		case args[i] == "--no-jobs":
+			opts.noJobs = true
+		case args[i] == "--with-schedules":
+			opts.withSchedules = true
+		case strings.HasPrefix(args[i], "-"):
+			return opts, fmt.Errorf("unknown flag: %s", args[i])
+		default:
+			return opts, fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	if !opts.noSync && opts.syncEvery < 5*time.Minute {
+		return opts, fmt.Errorf("minimum --sync-every is 5m (got %s)", opts.syncEvery)
+	}
+	if !opts.noSync && opts.snapshotEvery < time.Minute {
+		return opts, fmt.Errorf("minimum --snapshot-every is 1m (got %s)", opts.snapshotEvery)
+	}
+	if !opts.noIntegrity && opts.integrityEvery < time.Hour {
+		return opts, fmt.Errorf("minimum --integrity-every is 1h (got %s)", opts.integrityEvery)
+	}
+	return opts, nil
+}
+
+func runDaemon(args []string) error {
+	for _, a := range args {
+		if a == "--help" || a == "-h" {
+			fmt.Println(daemonUsageText())
+			return nil
+		}
+	}
+	if len(args) > 0 && args[0] == "schedules" {
+		return runDaemonSchedulesList(args[1:])
+	}
+	opts, err := parseDaemonArgs(args)
+	if err != nil {
+		return err
+	}
+
+	var logOut io.Writer = os.Stderr
+	if opts.logFile != "" {
+		path := expandUserPath(opts.logFile)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("creating log directory: %w", err)
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("opening log file: %w", err)
+		}
+		defer f.Close()
+		logOut = f
+	}
+	logger := log.New(logOut, "cortex-daemon ", log.LstdFlags)
+
+	// One store handle shared by every subsystem.
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("daemon requires SQLiteStore")
+	}
+	wireWebhook(s)
+	resolvedCfg, cfgErr := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{CLIDBPath: globalDBPath})
+	if cfgErr == nil {
+		applyExtractionRuntimeConfig(resolvedCfg)
+	}
+	var scheduleJobs []schedule.Job
+	if opts.withSchedules {
+		if cfgErr != nil {
+			return fmt.Errorf("loading schedules: %w", cfgErr)
+		}
+		if len(resolvedCfg.Schedules) == 0 {
+			return fmt.Errorf("--with-schedules: no schedules: defined in %s", resolvedCfg.ConfigPath)
+		}
+		if scheduleJobs, err = buildScheduleJobs(resolvedCfg.Schedules, sqlStore, opts, resolvedCfg); err != nil {
+			return err
+		}
+	}
+
+	embedCfg, embedLabel, err := resolveBackgroundEmbedConfig(opts.embedFlag, opts.embedFlag != "")
+	if err != nil {
+		return err
+	}
+
+	sup := daemon.NewSupervisor(logger)
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", sup.HealthHandler())
+
+	engine := search.NewEngine(s)
+	var embedder embed.Embedder
+	if embedCfg != nil {
+		client, err := embed.NewClient(embedCfg)
+		switch {
+		case err == nil:
+			embedder = client
+			engine = search.NewEngineWithEmbedder(s, embedder)
+		case opts.embedFlag != "":
+			return fmt.Errorf("creating embedder: %w", err)
+		default:
+			// An implicitly configured provider that is unavailable should not
+			// keep BM25, the graph, and connector sync from running.
+			logger.Printf("embeddings disabled: %s: %v", embedLabel, err)
+			embedCfg, embedLabel = nil, ""
+		}
+	}
+	mux.Handle("/readyz", readinessHandler(s, engine))
+	mux.Handle("/status", statusHandler(s))
+
+	var runner *jobs.Runner
+	if !opts.noJobs {
+		if runner, err = newJobRunner(sqlStore, opts.llmFlag, embedder); err != nil {
+			return err
+		}
+		runner.Logger = log.New(logger.Writer(), logger.Prefix()+"[jobs] ", logger.Flags())
+	}
+
+	if !opts.noMCP {
+		mcpServer := cortexmcp.NewServer(cortexmcp.ServerConfig{
+			Store:            s,
+			DBPath:           getDBPath(),
+			Version:          version,
+			AgentID:          opts.agentID,
+			ReadOnly:         globalReadOnly,
+			Jobs:             runner,
+			SearchEngine:     engine,
+			SearchProfiles:   loadSearchProfiles(),
+			SearchFreshness:  loadSearchFreshness(),
+			SearchGraphBoost: loadSearchGraphBoost(),
+		})
+		sse := server.NewSSEServer(mcpServer)
+		mux.Handle("/sse", sse)
+		mux.Handle("/message", sse)
+		if embedCfg != nil {
+			sup.Add(daemon.Subsystem{Name: "hnsw", Run: func(ctx context.Context, l *log.Logger) error {
+				return runDaemonHNSWRefresher(ctx, l, engine)
+			}})
+		}
+	}
+	if !opts.noGraph {
+		mux.Handle("/", graph.NewHandler(graph.ServerConfig{Store: sqlStore, AgentFilter: opts.agentID, Version: version}))
+	}
+	if hooks := resolvedCfg.Import.Webhook; cfgErr == nil && hooks.Enabled() {
+		mux.Handle("/hooks/", webhook.NewHandler(webhook.Config{
+			Store:   sqlStore,
+			Secret:  hooks.SecretFor,
+			Project: hooks.Project,
+			Extract: hooks.Extract,
+			Jobs:    runner,
+			Quotas:  connect.QuotaPolicyFromConfig(resolvedCfg.Import.Quotas),
+			Logger:  log.New(logger.Writer(), logger.Prefix()+"[webhook] ", logger.Flags()),
+		}))
+	}
+	sup.Add(daemon.Subsystem{Name: "http", Run: func(ctx context.Context, l *log.Logger) error {
+		return runDaemonHTTP(ctx, l, opts.port, mux)
+	}})
+
+	if !opts.noEmbed && opts.withSchedules && hasScheduledTask(resolvedCfg.Schedules, schedule.TaskEmbed) {
+		logger.Printf("embed watcher disabled: embedding runs on its schedule")
+	} else if !opts.noEmbed {
+		if embedCfg == nil {
+			logger.Printf("embed watcher disabled: no embedding provider available (pass --embed or set CORTEX_EMBED)")
+		} else {
+			sup.Add(daemon.Subsystem{Name: "embed", Run: func(ctx context.Context, l *log.Logger) error {
+				return runDaemonEmbedWatch(ctx, l, s, opts)
+			}})
+		}
+	}
+	if !opts.noSync {
+		if opts.withSchedules && hasScheduledTask(resolvedCfg.Schedules, schedule.TaskConnectorSync) {
+			logger.Printf("sync loop disabled: connectors sync on their schedule")
+		} else {
+			sup.Add(daemon.Subsystem{Name: "sync", Run: func(ctx context.Context, l *log.Logger) error {
+				return runDaemonSync(ctx, l, sqlStore, opts)
+			}})
+		}
+		sup.Add(daemon.Subsystem{Name: "snapshots", Run: func(ctx context.Context, l *log.Logger) error {
+			return runDaemonSnapshots(ctx, l, sqlStore, opts)
+		}})
+	}
+	if runner != nil {
+		sup.Add(daemon.Subsystem{Name: "jobs", Run: func(ctx context.Context, l *log.Logger) error {
+			return runner.Run(ctx)
+		}})
+	}
+	if len(scheduleJobs) > 0 {
+		sup.Add(daemon.Subsystem{Name: "schedules", Run: func(ctx context.Context, l *log.Logger) error {
+			return runDaemonSchedules(ctx, l, sqlStore, scheduleJobs)
+		}})
+	}
+	if !opts.noIntegrity {
+		sup.Add(daemon.Subsystem{Name: "integrity", Run: func(ctx context.Context, l *log.Logger) error {
+			return runDaemonIntegrity(ctx, l, sqlStore, opts.integrityEvery)
+		}})
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Printf("starting (db=%s, port=%d, embed=%s)", getDBPath(), opts.port, valueOrNone(embedLabel))
+	err = sup.Run(ctx)
+	logger.Printf("shut down")
+	return err
+}
+
+// runDaemonHTTP serves mux until ctx is cancelled, then drains connections.
+func runDaemonHTTP(ctx context.Context, logger *log.Logger, port int, mux http.Handler) error {
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	logger.Printf("listening on http://localhost:%d (MCP: /sse, health: /healthz, status: /status)", port)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+		if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// runDaemonHNSWRefresher warms the MCP engine's HNSW index at startup and
+// re-warms it whenever the embed watcher rewrites the persisted index.
+func runDaemonHNSWRefresher(ctx context.Context, logger *log.Logger, engine *search.Engine) error {
+	engine.SetHNSWParams(resolveHNSWParams())
+	path := getHNSWPath()
+	var lastMod time.Time
+	for {
+		info, statErr := os.Stat(path)
+		if statErr != nil || lastMod.IsZero() || info.ModTime().After(lastMod) {
+			<-engine.WarmHNSW(ctx, path, hnswStaleSec)
+			st := engine.HNSWStatus()
+			logger.Printf("index %s (%d vectors, %dms)", st.State, st.Vectors, st.ElapsedMs)
+			if info, err := os.Stat(path); err == nil {
+				lastMod = info.ModTime()
+			}
+		}
+		if waitForDurationOrCancel(ctx, daemonIndexRefresh) {
+			return nil
+		}
+	}
+}
+
+// runDaemonEmbedWatch is `cortex embed --watch` on the shared store handle.
+func runDaemonEmbedWatch(ctx context.Context, logger *log.Logger, s store.Store, opts daemonOptions) error {
+	lockPath := getEmbedLockPath()
+	lock, err := acquireEmbedRunLock(lockPath)
+	if err != nil {
+		if errors.Is(err, errEmbedLockHeld) {
+			return fmt.Errorf("another embedding process is already running (%s)", lockPath)
+		}
+		return err
+	}
+	defer lock.Release()
+
+	embedEngine, err := newEmbedEngineForFlag(s, opts.embedFlag)
+	if err != nil {
+		return err
+	}
+	logger.Printf("watching for unembedded memories every %s", opts.embedInterval)
+	return runEmbedLoop(ctx, s, embedEngine, embedCmdOptions{
+		embedFlag: opts.embedFlag,
+		batchSize: defaultEmbedBatchSize,
+		workers:   2,
+		watch:     true,
+		interval:  opts.embedInterval,
+	})
+}
+
+// runDaemonSync replaces the launchd/systemd schedule from
+// `cortex connect schedule`: sync all enabled connectors every opts.syncEvery.
+func runDaemonSync(ctx context.Context, logger *log.Logger, sqlStore *store.SQLiteStore, opts daemonOptions) error {
+	engine := connect.NewSyncEngine(connect.DefaultRegistry, connect.NewConnectorStore(sqlStore.GetDB()), sqlStore, globalVerbose)
+	syncOpts := daemonSyncOptions(opts)
+
+	for {
+		start := time.Now()
+		results, err := engine.SyncAll(ctx, syncOpts)
+		if err != nil {
+			return err
+		}
+		imported, failed := 0, 0
+		for _, r := range results {
+			imported += r.RecordsImported
+			if r.Error != "" {
+				failed++
+				logger.Printf("%s: %s", r.Provider, r.Error)
+			}
+		}
+		if len(results) > 0 {
+			logger.Printf("synced %d connectors (imported=%d failed=%d) in %s; next in %s",
+				len(results), imported, failed, time.Since(start).Round(time.Millisecond), opts.syncEvery)
+		}
+		if waitForDurationOrCancel(ctx, opts.syncEvery) {
+			return nil
+		}
+	}
+}
+
+// runDaemonSnapshots refreshes snapshot connectors (Home Assistant, MQTT)
+// every opts.snapshotEvery so ambient state stays current between full
+// syncs. It stays quiet when none are configured.
+func runDaemonSnapshots(ctx context.Context, logger *log.Logger, sqlStore *store.SQLiteStore, opts daemonOptions) error {
+	engine := connect.NewSyncEngine(connect.DefaultRegistry, connect.NewConnectorStore(sqlStore.GetDB()), sqlStore, globalVerbose)
+	syncOpts := daemonSyncOptions(opts)
+	for {
+		results, err := engine.SyncSnapshots(ctx, syncOpts)
+		if err != nil {
+			return err
+		}
+		for _, r := range results {
+			if r.Error != "" {
+				logger.Printf("%s: %s", r.Provider, r.Error)
+			}
+		}
+		if waitForDurationOrCancel(ctx, opts.snapshotEvery) {
+			return nil
+		}
+	}
+}
+
+func daemonSyncOptions(opts daemonOptions) connect.SyncOptions {
+	syncOpts := connect.SyncOptions{Extract: opts.extract, Enrich: opts.extract, AgentID: opts.agentID}
+	if resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{}); err == nil {
+		syncOpts.Quotas = connect.QuotaPolicyFromConfig(resolvedCfg.Import.Quotas)
+	}
+	return syncOpts
+}
+
+// runDaemonIntegrity verifies the database every interval, starting
+// daemonIntegrityDelay after launch, and raises an integrity alert when a
+// check fails. A failing check is not a subsystem error: the daemon keeps
+// serving so the alert can be acted on.
+func runDaemonIntegrity(ctx context.Context, logger *log.Logger, sqlStore *store.SQLiteStore, every time.Duration) error {
+	if waitForDurationOrCancel(ctx, daemonIntegrityDelay) {
+		return nil
+	}
+	for {
+		if err := checkIntegrityOnce(ctx, logger, sqlStore); err != nil {
+			return err
+		}
+		if waitForDurationOrCancel(ctx, every) {
+			return nil
+		}
+	}
+}
+
+func checkIntegrityOnce(ctx context.Context, logger *log.Logger, sqlStore *store.SQLiteStore) error {
+	report, err := sqlStore.VerifyIntegrity(ctx, getHNSWPath())
+	if err != nil {
+		return err
+	}
+	if report.OK() {
+		logger.Printf("integrity ok (%d memories, %d embeddings, %dms)", report.Memories, report.Embeddings, report.ElapsedMs)
+		return nil
+	}
+	for _, p := range report.Problems {
+		logger.Printf("integrity %s [%s]: %s", p.Severity, p.Check, p.Detail)
+	}
+	if _, err := sqlStore.RecordIntegrityAlert(ctx, report); err != nil {
+		return err
+	}
+	if sqlStore.Webhook != nil {
+		sqlStore.Webhook.Flush()
+	}
+	return nil
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/brief"
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/connect"
+	"github.com/hurttlocker/cortex/internal/lifecycle"
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/schedule"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// scheduleRunsShown is how many recent runs `cortex daemon schedules` lists.
+const scheduleRunsShown = 10
+
+// hasScheduledTask reports whether any schedule runs task.
+func hasScheduledTask(schedules []cfgresolver.ScheduleConfig, task string) bool {
+	for _, sc := range schedules {
+		if sc.Task == task {
+			return true
+		}
+	}
+	return false
+}
+
+// buildScheduleJobs turns the schedules: config into scheduler jobs that
+// run against the daemon's shared store.
+func buildScheduleJobs(schedules []cfgresolver.ScheduleConfig, sqlStore *store.SQLiteStore, opts daemonOptions, resolvedCfg cfgresolver.ResolvedConfig) ([]schedule.Job, error) {
+	jobs := make([]schedule.Job, 0, len(schedules))
+	for _, sc := range schedules {
+		parsed, err := schedule.ParseCron(sc.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", sc.Name, err)
+		}
+		job := schedule.Job{Name: sc.Name, Task: sc.Task, Schedule: parsed, Jitter: sc.JitterDuration()}
+		sc := sc
+		switch sc.Task {
+		case schedule.TaskConnectorSync:
+			job.Run = func(ctx context.Context) (string, error) {
+				return runScheduledSync(ctx, sqlStore, sc, opts)
+			}
+		case schedule.TaskEmbed:
+			job.Run = func(ctx context.Context) (string, error) {
+				return runScheduledEmbed(ctx, sqlStore, opts)
+			}
+		case schedule.TaskMaintain:
+			policies := resolvedCfg.Policies
+			job.Run = func(ctx context.Context) (string, error) {
+				return runScheduledMaintain(ctx, sqlStore, policies)
+			}
+		case schedule.TaskDigest:
+			model := strings.TrimSpace(sc.LLM)
+			if model == "" {
+				model = strings.TrimSpace(resolvedCfg.EffectiveLLMModel("brief", "").Value)
+			}
+			job.Run = func(ctx context.Context) (string, error) {
+				return runScheduledDigest(ctx, sqlStore, model, sc.Project)
+			}
+		case schedule.TaskLearnEdges:
+			job.Run = func(ctx context.Context) (string, error) {
+				result, err := sqlStore.LearnCoaccessEdges(ctx, store.DefaultLearnEdgesOpts())
+				if err != nil {
+					return "", err
+				}
+				return learnEdgesSummary(result), nil
+			}
+		default:
+			return nil, fmt.Errorf("schedule %s: unknown task %q", sc.Name, sc.Task)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// runDaemonSchedules runs the configured schedules until ctx is cancelled.
+func runDaemonSchedules(ctx context.Context, logger *log.Logger, sqlStore *store.SQLiteStore, jobs []schedule.Job) error {
+	now := time.Now()
+	for _, job := range jobs {
+		logger.Printf("%s (%s): next run %s", job.Name, job.Task, job.Schedule.Next(now).Format(time.RFC3339))
+	}
+	return schedule.New(jobs, sqlStore, logger).Run(ctx)
+}
+
+func runScheduledSync(ctx context.Context, sqlStore *store.SQLiteStore, sc cfgresolver.ScheduleConfig, opts daemonOptions) (string, error) {
+	engine := connect.NewSyncEngine(connect.DefaultRegistry, connect.NewConnectorStore(sqlStore.GetDB()), sqlStore, globalVerbose)
+	syncOpts := daemonSyncOptions(opts)
+	if sc.Extract {
+		syncOpts.Extract, syncOpts.Enrich = true, true
+	}
+
+	var results []connect.SyncResult
+	if sc.Provider != "" {
+		r, err := engine.SyncProvider(ctx, sc.Provider, syncOpts)
+		if err != nil {
+			return "", err
+		}
+		results = []connect.SyncResult{r}
+	} else {
+		var err error
+		if results, err = engine.SyncAll(ctx, syncOpts); err != nil {
+			return "", err
+		}
+	}
+
+	imported := 0
+	var failed []string
+	for _, r := range results {
+		imported += r.RecordsImported
+		if r.Error != "" {
+			failed = append(failed, r.Provider+": "+r.Error)
+		}
+	}
+	detail := fmt.Sprintf("%d connectors, %d imported", len(results), imported)
+	if len(failed) > 0 {
+		return detail, errors.New(strings.Join(failed, "; "))
+	}
+	return detail, nil
+}
+
+func runScheduledEmbed(ctx context.Context, s store.Store, opts daemonOptions) (string, error) {
+	lockPath := getEmbedLockPath()
+	lock, err := acquireEmbedRunLock(lockPath)
+	if err != nil {
+		if errors.Is(err, errEmbedLockHeld) {
+			return "", fmt.Errorf("another embedding process is already running (%s)", lockPath)
+		}
+		return "", err
+	}
+	defer lock.Release()
+
+	embedEngine, err := newEmbedEngineForFlag(s, opts.embedFlag)
+	if err != nil {
+		return "", err
+	}
+	summary, err := runEmbedPass(ctx, s, embedEngine, embedCmdOptions{
+		embedFlag: opts.embedFlag,
+		batchSize: defaultEmbedBatchSize,
+		workers:   2,
+	})
+	if err != nil {
+		return "", err
+	}
+	if summary == nil || summary.result == nil {
+		return "nothing to embed", nil
+	}
+	return fmt.Sprintf("%d embedded, %d errors", summary.result.EmbeddingsAdded, len(summary.result.Errors)), nil
+}
+
+func runScheduledMaintain(ctx context.Context, sqlStore *store.SQLiteStore, policies cfgresolver.PolicyConfig) (string, error) {
+	runner, err := lifecycle.NewRunner(sqlStore, policies)
+	if err != nil {
+		return "", err
+	}
+	report, err := runner.Run(ctx, false)
+	if err != nil {
+		return "", err
+	}
+	_ = setMetaTimestamp(ctx, sqlStore, "last_lifecycle_run_at", time.Now().UTC())
+	detail := fmt.Sprintf("lifecycle scanned %d, applied %d", report.Scanned, report.Applied)
+	tiered, err := autoTier(ctx, sqlStore)
+	if tiered != "" {
+		detail += "; " + tiered
+	}
+	return detail, err
+}
+
+func runScheduledDigest(ctx context.Context, sqlStore *store.SQLiteStore, model, project string) (string, error) {
+	if model == "" {
+		return "", fmt.Errorf("digest needs an LLM: set llm: on the schedule or llm.provider")
+	}
+	llmCfg, err := llm.ParseLLMFlag(model)
+	if err != nil {
+		return "", fmt.Errorf("parsing llm %q: %w", model, err)
+	}
+	provider, err := newScrubbedProvider(llmCfg)
+	if err != nil {
+		return "", fmt.Errorf("creating LLM provider: %w", err)
+	}
+	res, err := brief.Refresh(ctx, sqlStore, provider, brief.Options{Project: project})
+	if err != nil {
+		return "", err
+	}
+	generated := 0
+	for _, n := range res.Generated {
+		generated += n
+	}
+	detail := fmt.Sprintf("%d briefs regenerated, %d unchanged", generated, res.Unchanged)
+	if len(res.Errors) > 0 {
+		return detail, errors.New(strings.Join(res.Errors, "; "))
+	}
+	return detail, nil
+}
+
+// scheduleStatus is one row of `cortex daemon schedules`.
+type scheduleStatus struct {
+	cfgresolver.ScheduleConfig
+	NextRun    time.Time           `json:"next_run"`
+	RecentRuns []store.ScheduleRun `json:"recent_runs"`
+}
+
+// runDaemonSchedulesList prints the configured schedules with their next
+// firing and recent run history.
+func runDaemonSchedulesList(args []string) error {
+	jsonOutput := false
+	for _, a := range args {
+		switch {
+		case a == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(a, "-"):
+			return fmt.Errorf("unknown flag: %s", a)
+		default:
+			return fmt.Errorf("unexpected argument: %s", a)
+		}
+	}
+
+	resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{CLIDBPath: globalDBPath})
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	s, err := store.NewStore(store.StoreConfig{DBPath: getDBPath(), ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("schedules require SQLite store")
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	statuses := make([]scheduleStatus, 0, len(resolvedCfg.Schedules))
+	for _, sc := range resolvedCfg.Schedules {
+		st := scheduleStatus{ScheduleConfig: sc, RecentRuns: []store.ScheduleRun{}}
+		if parsed, err := schedule.ParseCron(sc.Cron); err == nil {
+			st.NextRun = parsed.Next(now)
+		}
+		runs, err := sqlStore.ListScheduleRuns(ctx, sc.Name, scheduleRunsShown)
+		if err != nil {
+			return err
+		}
+		if runs != nil {
+			st.RecentRuns = runs
+		}
+		statuses = append(statuses, st)
+	}
+
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("No schedules configured. Add a schedules: list to config.yaml, e.g.:")
+		fmt.Println()
+		fmt.Println("  schedules:")
+		fmt.Println("    - name: nightly-sync")
+		fmt.Println("      task: connector_sync")
+		fmt.Println(`      cron: "15 3 * * *"`)
+		fmt.Println("      jitter: 10m")
+		fmt.Println()
+		fmt.Println("then run: cortex daemon --with-schedules")
+		return nil
+	}
+	for _, st := range statuses {
+		fmt.Printf("%s  (%s, %s", st.Name, st.Task, st.Cron)
+		if st.Jitter != "" {
+			fmt.Printf(", jitter %s", st.Jitter)
+		}
+		fmt.Println(")")
+		if !st.NextRun.IsZero() {
+			fmt.Printf("  next: %s\n", st.NextRun.Local().Format("2006-01-02 15:04"))
+		}
+		if len(st.RecentRuns) == 0 {
+			fmt.Println("  no runs recorded yet")
+		}
+		for _, r := range st.RecentRuns {
+			line := r.Detail
+			if r.Error != "" {
+				line = strings.TrimSpace(line + " ⚠ " + r.Error)
+			}
+			fmt.Printf("  %-16s  %-7s  %8s  %s\n", r.StartedAt.Local().Format("2006-01-02 15:04"), r.Status,
+				r.Duration().Round(time.Second), line)
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/schedule"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const testSchedulesYAML = `schedules:
+  - name: nightly-sync
+    task: connector_sync
+    cron: "15 3 * * *"
+    jitter: 10m
+  - name: tidy
+    task: maintain
+    cron: "@daily"
+`
+
+func TestParseDaemonArgs_WithSchedules(t *testing.T) {
+	opts, err := parseDaemonArgs([]string{"--with-schedules", "--no-mcp"})
+	if err != nil || !opts.withSchedules || !opts.noMCP {
+		t.Fatalf("unexpected opts: %+v %v", opts, err)
+	}
+	if opts, _ := parseDaemonArgs(nil); opts.withSchedules {
+		t.Fatal("schedules must be opt-in")
+	}
+}
+
+func TestBuildScheduleJobs(t *testing.T) {
+	s, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer s.Close()
+	sqlStore := s.(*store.SQLiteStore)
+	schedules := []cfgresolver.ScheduleConfig{
+		{Name: "nightly-sync", Task: schedule.TaskConnectorSync, Cron: "15 3 * * *", Jitter: "10m"},
+		{Name: "tidy", Task: schedule.TaskMaintain, Cron: "@daily"},
+	}
+	jobs, err := buildScheduleJobs(schedules, sqlStore, daemonOptions{}, cfgresolver.ResolvedConfig{Policies: cfgresolver.DefaultPolicyConfig()})
+	if err != nil {
+		t.Fatalf("buildScheduleJobs: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].Jitter != 10*time.Minute || jobs[1].Task != schedule.TaskMaintain {
+		t.Fatalf("unexpected jobs: %+v", jobs)
+	}
+	if !hasScheduledTask(schedules, schedule.TaskConnectorSync) || hasScheduledTask(schedules, schedule.TaskEmbed) {
+		t.Fatal("hasScheduledTask mismatch")
+	}
+
+	// The maintain task runs the lifecycle policies on the shared store.
+	detail, err := jobs[1].Run(context.Background())
+	if err != nil || !strings.Contains(detail, "lifecycle") {
+		t.Fatalf("maintain run = %q, %v", detail, err)
+	}
+
+	if _, err := buildScheduleJobs([]cfgresolver.ScheduleConfig{{Name: "x", Task: "backup", Cron: "@daily"}}, sqlStore, daemonOptions{}, cfgresolver.ResolvedConfig{}); err == nil {
+		t.Fatal("expected an unknown task to be rejected")
+	}
+	if _, err := runScheduledDigest(context.Background(), sqlStore, "", ""); err == nil {
+		t.Fatal("expected digest without an LLM to fail")
+	}
+}
+
+func TestRunDaemonSchedulesList_JSON(t *testing.T) {
+	home := useWorkspaceHome(t)
+	if err := os.MkdirAll(filepath.Join(home, ".cortex"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".cortex", "config.yaml"), []byte(testSchedulesYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	globalDBPath = filepath.Join(home, "cortex.db")
+
+	s, err := store.NewStore(store.StoreConfig{DBPath: globalDBPath})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	sqlStore := s.(*store.SQLiteStore)
+	ctx := context.Background()
+	started := time.Now().Add(-time.Hour)
+	id, ok, err := sqlStore.BeginScheduleRun(ctx, "tidy", schedule.TaskMaintain, started, time.Hour)
+	if err != nil || !ok {
+		t.Fatalf("BeginScheduleRun: %v %v", ok, err)
+	}
+	if err := sqlStore.FinishScheduleRun(ctx, id, schedule.StatusOK, "lifecycle scanned 3, applied 0", "", started.Add(time.Second)); err != nil {
+		t.Fatalf("FinishScheduleRun: %v", err)
+	}
+	s.Close()
+
+	var runErr error
+	out := captureStdout(func() { runErr = runDaemonSchedulesList([]string{"--json"}) })
+	if runErr != nil {
+		t.Fatalf("runDaemonSchedulesList: %v", runErr)
+	}
+	var statuses []scheduleStatus
+	if err := json.Unmarshal([]byte(out), &statuses); err != nil {
+		t.Fatalf("decoding %q: %v", out, err)
+	}
+	if len(statuses) != 2 || statuses[0].Name != "nightly-sync" || statuses[0].NextRun.IsZero() {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+	if len(statuses[0].RecentRuns) != 0 || len(statuses[1].RecentRuns) != 1 || statuses[1].RecentRuns[0].Status != schedule.StatusOK {
+		t.Fatalf("unexpected run history: %+v", statuses)
+	}
+
+	if err := runDaemonSchedulesList([]string{"--bogus"}); err == nil {
+		t.Fatal("expected unknown flag error")
+	}
+}
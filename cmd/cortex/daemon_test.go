@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseDaemonArgs(t *testing.T) {
+	opts, err := parseDaemonArgs(nil)
+	if err != nil {
+		t.Fatalf("parseDaemonArgs defaults: %v", err)
+	}
+	if opts.port != defaultDaemonPort || opts.syncEvery != defaultDaemonSyncEvery || opts.snapshotEvery != defaultDaemonSnapshotEvery || opts.embedInterval != defaultEmbedInterval || opts.integrityEvery != defaultDaemonIntegrityEvery {
+		t.Fatalf("unexpected defaults: %+v", opts)
+	}
+
+	opts, err = parseDaemonArgs([]string{"--port=9000", "--embed", "ollama/nomic-embed-text", "--sync-every", "1h", "--no-graph", "--extract", "--agent", "ops"})
+	if err != nil {
+		t.Fatalf("parseDaemonArgs: %v", err)
+	}
+	if opts.port != 9000 || opts.embedFlag != "ollama/nomic-embed-text" || opts.syncEvery != time.Hour || !opts.noGraph || !opts.extract || opts.agentID != "ops" {
+		t.Fatalf("unexpected opts: %+v", opts)
+	}
+
+	for _, bad := range [][]string{
+		{"--port", "zero"},
+		{"--sync-every", "1m"},
+		{"--snapshot-every", "30s"},
+		{"--embed-interval", "-5s"},
+		{"--integrity-every", "10m"},
+		{"--bogus"},
+		{"extra"},
+	} {
+		if _, err := parseDaemonArgs(bad); err == nil {
+			t.Fatalf("expected error for %v", bad)
+		}
+	}
+
+	// --no-sync lifts the minimum interval check.
+	if _, err := parseDaemonArgs([]string{"--sync-every", "1m", "--no-sync"}); err != nil {
+		t.Fatalf("--no-sync should skip interval validation: %v", err)
+	}
+	if opts, err := parseDaemonArgs([]string{"--integrity-every", "10m", "--no-integrity"}); err != nil || !opts.noIntegrity {
+		t.Fatalf("--no-integrity should skip interval validation: %+v %v", opts, err)
+	}
+	if opts, err := parseDaemonArgs([]string{"--llm=openrouter/x", "--no-jobs"}); err != nil || !opts.noJobs || opts.llmFlag != "openrouter/x" {
+		t.Fatalf("unexpected jobs flags: %+v %v", opts, err)
+	}
+}
+
+func TestRunDaemonHTTP_ShutsDownOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- runDaemonHTTP(ctx, log.New(io.Discard, "", 0), 0, http.NotFoundHandler())
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("runDaemonHTTP: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("runDaemonHTTP did not shut down")
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const decayUsage = `usage: cortex decay [--warning 0.5] [--critical 0.3] [--archive-below <0-1>] [--dry-run] [--json]
+       cortex decay --watch [--every 6h] [sweep flags]
+       cortex decay schedule [--every 6h] [--archive-below <0-1>] [--install|--uninstall|--show]
+       cortex decay alerts [--limit 50] [--json]`
+
+var decaySweepUnit = scheduledUnit{
+	Label:       "com.cortex.decay-sweep",
+	Service:     "cortex-decay-sweep",
+	Description: "Cortex Decay Sweep",
+	Command:     "decay schedule",
+}
+
+// runDecay sweeps the store for decayed facts: decay alerts for facts
+// below the warning and critical thresholds, and optionally retirement of
+// facts below --archive-below. With --watch it sweeps every --every until
+// interrupted.
+func runDecay(args []string) error {
+	if len(args) > 0 && args[0] == "schedule" {
+		return runDecaySchedule(args[1:])
+	}
+	if len(args) > 0 && args[0] == "alerts" {
+		return runDecayAlerts(args[1:])
+	}
+
+	opts := store.DecaySweepOptions{Thresholds: store.DefaultDecayThresholds()}
+	watch := false
+	every := 6 * time.Hour
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--warning" || arg == "--critical" || arg == "--archive-below":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", arg)
+			}
+			i++
+			v, err := strconv.ParseFloat(args[i], 64)
+			if err != nil || v < 0 || v > 1 {
+				return fmt.Errorf("invalid %s value: %s (expected 0-1)", arg, args[i])
+			}
+			switch arg {
+			case "--warning":
+				opts.Thresholds.Warning = v
+			case "--critical":
+				opts.Thresholds.Critical = v
+			default:
+				opts.ArchiveBelow = v
+			}
+		case arg == "--every":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", arg)
+			}
+			i++
+			d, err := time.ParseDuration(args[i])
+			if err != nil || d < time.Minute {
+				return fmt.Errorf("invalid --every value: %s (minimum 1m)", args[i])
+			}
+			every = d
+		case arg == "--watch":
+			watch = true
+		case arg == "--dry-run" || arg == "-n":
+			opts.DryRun = true
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s\n%s", arg, decayUsage)
+		}
+	}
+	if opts.Thresholds.Critical > opts.Thresholds.Warning {
+		return fmt.Errorf("--critical (%.2f) must not be above --warning (%.2f)", opts.Thresholds.Critical, opts.Thresholds.Warning)
+	}
+	if watch && opts.DryRun {
+		return fmt.Errorf("--watch cannot be used with --dry-run")
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("decay requires SQLiteStore")
+	}
+	wireWebhook(s)
+	defer flushWebhook(s)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	asJSON := jsonOutput || !isTTY()
+	if !watch {
+		res, err := sqlStore.SweepDecay(ctx, opts)
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(res)
+		}
+		printDecaySweep(res)
+		return nil
+	}
+
+	// --watch prints one line (or one JSON object) per sweep.
+	if !asJSON {
+		fmt.Printf("Sweeping decay every %s (Ctrl-C to stop)\n", every)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	for {
+		res, err := sqlStore.SweepDecay(ctx, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "decay sweep failed: %v\n", err)
+		} else if asJSON {
+			enc.Encode(res)
+		} else {
+			fmt.Printf("%s  %s\n", res.SweptAt.Local().Format("2006-01-02 15:04"), decaySweepSummary(res))
+		}
+		flushWebhook(s)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(every):
+		}
+	}
+}
+
+// decaySweepSummary is one sweep on one line.
+func decaySweepSummary(res *store.DecaySweepResult) string {
+	line := fmt.Sprintf("%d facts scanned, %d new %s (%d critical)",
+		res.FactsScanned, res.AlertsCreated, pluralize("alert", "alerts", res.AlertsCreated), res.CriticalCount)
+	if len(res.Archived) > 0 || res.Held > 0 {
+		verb := "archived"
+		if res.DryRun {
+			verb = "would archive"
+		}
+		line += fmt.Sprintf(", %s %d", verb, len(res.Archived))
+		if res.Held > 0 {
+			line += fmt.Sprintf(" (%d held)", res.Held)
+		}
+	}
+	return line
+}
+
+func printDecaySweep(res *store.DecaySweepResult) {
+	fmt.Printf("Decay sweep: %s\n", decaySweepSummary(res))
+	if res.AlertsSkipped > 0 {
+		fmt.Printf("  %d fading %s already had open alerts\n", res.AlertsSkipped, pluralize("fact", "facts", res.AlertsSkipped))
+	}
+	if res.AlertsCreated > 0 {
+		fmt.Println("  Review with: cortex decay alerts")
+	}
+}
+
+// runDecaySchedule installs `cortex decay` as a launchd job or systemd
+// timer, like `cortex connect schedule` does for syncs.
+func runDecaySchedule(args []string) error {
+	fs := flag.NewFlagSet("decay-schedule", flag.ContinueOnError)
+	every := fs.String("every", "6h", "Sweep interval (e.g., 1h, 6h, 24h)")
+	archiveBelow := fs.Float64("archive-below", 0, "Retire facts whose effective confidence is below this (0 = never)")
+	install := fs.Bool("install", false, "Install and load the schedule (macOS: launchd, Linux: systemd)")
+	uninstall := fs.Bool("uninstall", false, "Remove the installed schedule")
+	showOnly := fs.Bool("show", false, "Print the schedule config to stdout without installing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument: %s\n%s", fs.Arg(0), decayUsage)
+	}
+	if *uninstall {
+		return uninstallSchedule(decaySweepUnit)
+	}
+
+	interval, err := time.ParseDuration(*every)
+	if err != nil {
+		return fmt.Errorf("invalid interval %q: %w", *every, err)
+	}
+	if interval < 5*time.Minute {
+		return fmt.Errorf("minimum interval is 5m (got %s)", *every)
+	}
+	if *archiveBelow < 0 || *archiveBelow > 1 {
+		return fmt.Errorf("invalid --archive-below value: %g (expected 0-1)", *archiveBelow)
+	}
+
+	cortexBin, err := os.Executable()
+	if err != nil {
+		cortexBin = "cortex"
+	}
+	dbPath := getDBPath()
+	if dbPath == "" {
+		home, _ := os.UserHomeDir()
+		dbPath = filepath.Join(home, ".cortex", "cortex.db")
+	}
+
+	sweepArgs := []string{"decay", "--json"}
+	if *archiveBelow > 0 {
+		sweepArgs = append(sweepArgs, "--archive-below", strconv.FormatFloat(*archiveBelow, 'g', -1, 64))
+	}
+	if runtime.GOOS == "darwin" {
+		return generateLaunchd(decaySweepUnit, cortexBin, dbPath, sweepArgs, int(interval.Seconds()), *install, *showOnly)
+	}
+	return generateSystemd(decaySweepUnit, cortexBin, dbPath, sweepArgs, int(interval.Seconds()), *install, *showOnly)
+}
+
+// runDecayAlerts lists the open decay alerts, most recent first.
+func runDecayAlerts(args []string) error {
+	limit := 50
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--limit":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", arg)
+			}
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid --limit value: %s", args[i])
+			}
+			limit = n
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s\n%s", arg, decayUsage)
+		}
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("decay requires SQLiteStore")
+	}
+	unacked := false
+	alerts, err := sqlStore.ListAlerts(context.Background(), store.AlertFilter{
+		Type: store.AlertTypeDecay, Acknowledged: &unacked, Limit: limit,
+	})
+	if err != nil {
+		return err
+	}
+	if alerts == nil {
+		alerts = []store.Alert{}
+	}
+
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(alerts)
+	}
+	if len(alerts) == 0 {
+		fmt.Println("No open decay alerts.")
+		return nil
+	}
+	for _, a := range alerts {
+		fmt.Printf("  %-8s %s\n", a.Severity, a.Message)
+	}
+	fmt.Println("\nStill true? cortex reinforce <fact-id>. Outdated? cortex stale --action archive.")
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunDecay_SweepsAndArchives(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlStore := s.(*store.SQLiteStore)
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "old notes", SourceFile: "notes.md"})
+	fading, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "vpn", Predicate: "host", Object: "old.example", FactType: "kv", DecayRate: 0.01})
+	gone, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "vpn", Predicate: "port", Object: "1194", FactType: "kv", DecayRate: 0.01})
+	sqlStore.ExecContext(ctx, "UPDATE facts SET last_reinforced = ? WHERE id = ?", time.Now().UTC().AddDate(0, 0, -80), fading)
+	sqlStore.ExecContext(ctx, "UPDATE facts SET last_reinforced = ? WHERE id = ?", time.Now().UTC().AddDate(0, 0, -400), gone)
+	s.Close()
+
+	sweep := func(args ...string) store.DecaySweepResult {
+		t.Helper()
+		var runErr error
+		out := captureStdout(func() { runErr = runDecay(append(args, "--json")) })
+		if runErr != nil {
+			t.Fatalf("decay %v: %v", args, runErr)
+		}
+		var res store.DecaySweepResult
+		if err := json.Unmarshal([]byte(out), &res); err != nil {
+			t.Fatalf("invalid JSON %q: %v", out, err)
+		}
+		return res
+	}
+
+	if res := sweep("--archive-below", "0.05", "--dry-run"); !res.DryRun || len(res.Archived) != 1 || res.AlertsCreated != 2 {
+		t.Fatalf("dry run = %+v", res)
+	}
+	if res := sweep("--archive-below", "0.05"); len(res.Archived) != 1 || res.Archived[0] != gone || res.AlertsSkipped != 1 {
+		t.Fatalf("sweep = %+v", res)
+	}
+
+	var runErr error
+	out := captureStdout(func() { runErr = runDecay([]string{"alerts", "--json"}) })
+	var alerts []store.Alert
+	if runErr != nil || json.Unmarshal([]byte(out), &alerts) != nil || len(alerts) != 1 || *alerts[0].FactID != fading {
+		t.Fatalf("decay alerts = %s, %v", out, runErr)
+	}
+
+	out = captureStdout(func() { runErr = runDecay([]string{"schedule", "--show", "--every", "12h", "--archive-below", "0.05"}) })
+	if runErr != nil || !strings.Contains(out, "decay --json --archive-below 0.05") || !strings.Contains(out, "decay-sweep") {
+		t.Fatalf("schedule --show = %q, %v", out, runErr)
+	}
+
+	for _, args := range [][]string{
+		{"--warning", "0.2", "--critical", "0.4"},
+		{"--archive-below", "2"},
+		{"--watch", "--dry-run"},
+		{"--every", "1s", "--watch"},
+		{"schedule", "--every", "1m"},
+		{"bogus"},
+	} {
+		if err := runDecay(args); err == nil {
+			t.Errorf("runDecay(%v): expected error", args)
+		}
+	}
+}
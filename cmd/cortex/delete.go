@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func deleteUsageText() string {
+	return `Usage: cortex delete <id>... [flags]
+       cortex delete --stdin
+       cortex delete [filters] [flags]
+
+Soft-deletes memories, which hides them from search and listings. With
+filters, every matching memory is deleted; IDs and filters combine (a
+record must match both). Facts are selected by their memory's source,
+project, and class, and by their own creation date.
+
+Filters:
+  --source <pattern>    Source file glob, e.g. '*/scratch/*' or '/notes/ops.md'
+  --project <name>      Project tag
+  --before <date>       Imported (facts: created) before YYYY-MM-DD, RFC3339, or an age like 30d
+  --class <class>       Memory class (rule, decision, preference, identity, status, scratch)
+
+Flags:
+  --facts               Delete facts instead of memories (always permanent)
+  --purge               Remove memories permanently with their facts, edges, and embeddings
+  --dry-run, -n         Show what would be deleted, change nothing
+  --yes, -y             Don't ask for confirmation
+  --json                Machine-readable report
+  --stdin               Read memory IDs from stdin (no filters)
+
+Records under an archive hold are never deleted; they are counted as held.
+
+Examples:
+  cortex delete 412 413
+  cortex delete --source '*/scratch/*' --before 90d --dry-run
+  cortex delete --project demo --purge --yes
+  cortex delete --facts --class scratch --before 2026-01-01`
+}
+
+// batchDeleteArgs are the `cortex delete` flags that select records by
+// filter or change how they are deleted.
+type batchDeleteArgs struct {
+	sel   store.BatchDelete
+	facts bool
+	yes   bool
+	json  bool
+}
+
+// set reports whether any flag that needs the batch path was given.
+func (b *batchDeleteArgs) set() bool {
+	return b.facts || b.sel.Purge || b.sel.DryRun || b.sel.Source != "" || b.sel.Project != "" ||
+		!b.sel.Before.IsZero() || b.sel.Class != "" || b.json || b.yes
+}
+
+// parse consumes args[*i] (and its value) when it is a batch delete flag.
+func (b *batchDeleteArgs) parse(args []string, i *int) (bool, error) {
+	arg := args[*i]
+	name, value, hasValue := strings.Cut(arg, "=")
+	switch name {
+	case "--facts", "--purge", "--dry-run", "-n", "--yes", "-y", "--json":
+		if hasValue {
+			return false, fmt.Errorf("unknown flag: %s", arg)
+		}
+	}
+	switch name {
+	case "--facts":
+		b.facts = true
+	case "--purge":
+		b.sel.Purge = true
+	case "--dry-run", "-n":
+		b.sel.DryRun = true
+	case "--yes", "-y":
+		b.yes = true
+	case "--json":
+		b.json = true
+	case "--source", "--project", "--before", "--class":
+		if !hasValue {
+			if *i+1 >= len(args) {
+				return false, fmt.Errorf("%s needs a value", name)
+			}
+			*i++
+			value = args[*i]
+		}
+		if strings.TrimSpace(value) == "" {
+			return false, fmt.Errorf("invalid %s value: %q", name, value)
+		}
+		switch name {
+		case "--source":
+			b.sel.Source = value
+		case "--project":
+			b.sel.Project = value
+		case "--class":
+			b.sel.Class = value
+		case "--before":
+			t, err := parseDeleteBefore(value)
+			if err != nil {
+				return false, err
+			}
+			b.sel.Before = t
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// parseDeleteBefore reads --before as a date or timestamp, or as an age
+// (30d, 2w, 12h) counted back from now.
+func parseDeleteBefore(raw string) (time.Time, error) {
+	if t, ok := parseTimeForWhere(raw); ok {
+		return t, nil
+	}
+	if d, err := parseSinceDuration(raw); err == nil {
+		return time.Now().UTC().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --before value: %s (use YYYY-MM-DD, RFC3339, or an age like 30d)", raw)
+}
+
+// runBatchDelete previews the selection, asks for confirmation unless
+// --yes or --dry-run, and deletes.
+func runBatchDelete(ids []int64, b batchDeleteArgs) error {
+	b.sel.IDs = ids
+	if b.sel.Empty() {
+		return fmt.Errorf("cortex delete needs IDs or a filter (--source, --project, --before, --class)")
+	}
+	if b.facts && b.sel.Purge {
+		return fmt.Errorf("--purge applies to memories; deleted facts are always removed permanently")
+	}
+	if !b.sel.DryRun && !b.yes && (b.json || !isTTY()) {
+		return fmt.Errorf("batch delete needs --dry-run or --yes when not run interactively")
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+	del := s.DeleteMemories
+	kind, kinds := "memory", "memories"
+	if b.facts {
+		del = s.DeleteFacts
+		kind, kinds = "fact", "facts"
+	}
+
+	if !b.yes || b.sel.DryRun {
+		preview := b.sel
+		preview.DryRun = true
+		plan, err := del(ctx, preview)
+		if err != nil {
+			return err
+		}
+		if b.sel.DryRun {
+			return printBatchDelete(plan, kind, kinds, b.json)
+		}
+		printBatchDelete(plan, kind, kinds, false)
+		if len(plan.IDs) == 0 {
+			return nil
+		}
+		prompt := "Soft-delete these memories?"
+		if b.facts || b.sel.Purge {
+			prompt = fmt.Sprintf("Permanently delete these %s? This cannot be undone.", kinds)
+		}
+		fmt.Printf("\n%s [y/N] ", prompt)
+		var answer string
+		fmt.Scanln(&answer)
+		answer = strings.TrimSpace(strings.ToLower(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	res, err := del(ctx, b.sel)
+	if err != nil {
+		return err
+	}
+	return printBatchDelete(res, kind, kinds, b.json)
+}
+
+func printBatchDelete(res *store.BatchDeleteResult, kind, kinds string, jsonOutput bool) error {
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	n := len(res.IDs)
+	verb := "Deleted"
+	switch {
+	case res.DryRun:
+		verb = "Would delete"
+	case kind == "memory" && !res.Purge:
+		verb = "Soft-deleted"
+	}
+	fmt.Printf("%s %d %s", verb, n, pluralize(kind, kinds, n))
+	if n > 0 && n <= 20 {
+		fmt.Printf(" (#%s)", strings.ReplaceAll(strings.Trim(fmt.Sprint(res.IDs), "[]"), " ", ", #"))
+	}
+	fmt.Println()
+	switch {
+	case kind == "memory" && res.Purge:
+		fmt.Printf("  with %d facts, %d edges, %d embeddings\n", res.Facts, res.Edges, res.Embeddings)
+	case kind == "fact":
+		fmt.Printf("  with %d edges\n", res.Edges)
+	}
+	if res.Clusters > 0 {
+		fmt.Printf("  removed %d empty %s\n", res.Clusters, pluralize("cluster", "clusters", int(res.Clusters)))
+	}
+	if res.Held > 0 {
+		fmt.Printf("  kept %d under an archive hold (see `cortex archive list`)\n", res.Held)
+	}
+	if res.DryRun {
+		fmt.Println("\nDry run — nothing changed.")
+	}
+	return nil
+}
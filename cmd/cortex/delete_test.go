@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunDelete_BatchFilters(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	scratch, _ := s.AddMemory(ctx, &store.Memory{Content: "scratch note", SourceFile: "/notes/scratch/a.md", Project: "demo"})
+	keep, _ := s.AddMemory(ctx, &store.Memory{Content: "runbook", SourceFile: "/notes/ops.md", Project: "ops"})
+	fact, _ := s.AddFact(ctx, &store.Fact{MemoryID: keep, Subject: "deploy", Predicate: "uses", Object: "fly.io", FactType: "kv"})
+	s.Close()
+
+	var runErr error
+	out := captureStdout(func() { runErr = runDelete([]string{"--source", "*/scratch/*", "--dry-run", "--json"}) })
+	if runErr != nil {
+		t.Fatalf("dry run: %v", runErr)
+	}
+	var plan store.BatchDeleteResult
+	if err := json.Unmarshal([]byte(out), &plan); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if !plan.DryRun || len(plan.IDs) != 1 || plan.IDs[0] != scratch {
+		t.Fatalf("plan = %+v", plan)
+	}
+
+	if err := runDelete([]string{"--project", "demo"}); err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Fatalf("non-interactive delete without --yes should fail, got %v", err)
+	}
+
+	out = captureStdout(func() { runErr = runDelete([]string{"--project", "demo", "--purge", "--yes"}) })
+	if runErr != nil || !strings.Contains(out, "Deleted 1 memory") {
+		t.Fatalf("purge: err=%v out=%q", runErr, out)
+	}
+
+	out = captureStdout(func() {
+		runErr = runDelete([]string{strconv.FormatInt(fact, 10), "--facts", "--yes"})
+	})
+	if runErr != nil || !strings.Contains(out, "Deleted 1 fact") {
+		t.Fatalf("delete fact: err=%v out=%q", runErr, out)
+	}
+
+	s, err = store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if m, _ := s.GetMemory(ctx, scratch); m != nil {
+		t.Fatalf("memory %d should be purged", scratch)
+	}
+	if m, _ := s.GetMemory(ctx, keep); m == nil || m.DeletedAt != nil {
+		t.Fatalf("memory %d should be untouched", keep)
+	}
+	if f, _ := s.GetFact(ctx, fact); f != nil {
+		t.Fatalf("fact %d should be deleted", fact)
+	}
+}
+
+func TestRunDelete_BatchArgErrors(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--dry-run"}, "needs IDs or a filter"},
+		{[]string{"--before", "yesterday-ish", "--dry-run"}, "invalid --before"},
+		{[]string{"--source"}, "needs a value"},
+		{[]string{"--stdin", "--project", "x"}, "--stdin cannot be combined"},
+		{[]string{"--facts", "--purge", "--project", "x", "--yes"}, "--purge applies to memories"},
+		{[]string{"--purge=yes"}, "unknown flag"},
+	} {
+		err := runDelete(tc.args)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("runDelete(%v) = %v, want %q", tc.args, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// diffSectionLimit caps how many rows of each kind terminal output shows.
+const diffSectionLimit = 20
+
+func diffUsageText() string {
+	return `Usage:
+  cortex diff <backup.db|export.json> [--facts|--memories] [--min-delta N] [--limit N] [--json]
+
+Compares the current store against a snapshot and reports added, removed,
+and superseded facts, confidence changes, and added, removed, or re-imported
+memories. The snapshot is either a copy of the database file from
+` + "`cortex backup`" + ` (opened read-only) or a JSON export from
+` + "`cortex export --format json [--facts]`" + `.
+Rows are matched by ID, so the snapshot must come from this store.`
+}
+
+// diffSnapshotFile is a loaded snapshot plus which kinds of rows it covers:
+// a JSON export holds only facts or only memories.
+type diffSnapshotFile struct {
+	*store.Snapshot
+	HasFacts    bool
+	HasMemories bool
+}
+
+type diffJSON struct {
+	Snapshot string `json:"snapshot"`
+	store.SnapshotDiff
+}
+
+// runDiff handles `cortex diff`.
+func runDiff(args []string) error {
+	if len(args) > 0 && (args[0] == "--help" || args[0] == "-h" || args[0] == "help") {
+		fmt.Println(diffUsageText())
+		return nil
+	}
+
+	factsOnly, memoriesOnly, jsonOutput := false, false, false
+	minDelta := store.DefaultConfidenceDelta
+	limit := diffSectionLimit
+	path := ""
+	for i := 0; i < len(args); i++ {
+		var err error
+		switch {
+		case args[i] == "--facts":
+			factsOnly = true
+		case args[i] == "--memories":
+			memoriesOnly = true
+		case args[i] == "--json":
+			jsonOutput = true
+		case args[i] == "--min-delta" && i+1 < len(args):
+			i++
+			minDelta, err = parseDiffMinDelta(args[i])
+		case strings.HasPrefix(args[i], "--min-delta="):
+			minDelta, err = parseDiffMinDelta(strings.TrimPrefix(args[i], "--min-delta="))
+		case args[i] == "--limit" && i+1 < len(args):
+			i++
+			limit, err = parseDiffLimit(args[i])
+		case strings.HasPrefix(args[i], "--limit="):
+			limit, err = parseDiffLimit(strings.TrimPrefix(args[i], "--limit="))
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		case path == "":
+			path = args[i]
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if path == "" {
+		return fmt.Errorf("usage: cortex diff <backup.db|export.json> [--facts|--memories] [--json]")
+	}
+	if factsOnly && memoriesOnly {
+		return fmt.Errorf("--facts and --memories are mutually exclusive")
+	}
+
+	ctx := context.Background()
+	before, err := loadDiffSnapshot(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	ss, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("diff requires SQLiteStore")
+	}
+	after, err := ss.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	wantFacts := !memoriesOnly
+	wantMemories := !factsOnly
+	if !before.HasFacts || !before.HasMemories {
+		// A single-kind export says nothing about the other kind.
+		if factsOnly && !before.HasFacts || memoriesOnly && !before.HasMemories {
+			return fmt.Errorf("%s does not contain the requested rows", path)
+		}
+		wantFacts = wantFacts && before.HasFacts
+		wantMemories = wantMemories && before.HasMemories
+	}
+	if !wantFacts {
+		before.Facts, after.Facts = nil, nil
+	}
+	if !wantMemories {
+		before.Memories, after.Memories = nil, nil
+	}
+	diff := store.DiffSnapshots(before.Snapshot, after, minDelta)
+
+	if jsonOutput || !isTTY() {
+		data, _ := json.MarshalIndent(diffJSON{Snapshot: path, SnapshotDiff: diff}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	printSnapshotDiff(path, diff, wantFacts, wantMemories, limit)
+	return nil
+}
+
+func parseDiffMinDelta(raw string) (float64, error) {
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil || f <= 0 || f > 1 {
+		return 0, fmt.Errorf("invalid --min-delta value: %s (must be in (0, 1])", raw)
+	}
+	return f, nil
+}
+
+func parseDiffLimit(raw string) (int, error) {
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid --limit value: %s", raw)
+	}
+	return n, nil
+}
+
+// loadDiffSnapshot reads a snapshot from a SQLite database file or a JSON
+// export, telling them apart by the SQLite file header.
+func loadDiffSnapshot(ctx context.Context, path string) (*diffSnapshotFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+	if bytes.HasPrefix(data, []byte("SQLite format 3\x00")) {
+		s, err := store.NewStore(store.StoreConfig{DBPath: path, ReadOnly: true})
+		if err != nil {
+			return nil, fmt.Errorf("opening snapshot: %w", err)
+		}
+		defer s.Close()
+		ss, ok := s.(*store.SQLiteStore)
+		if !ok {
+			return nil, fmt.Errorf("diff requires SQLiteStore")
+		}
+		snap, err := ss.Snapshot(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("reading snapshot %s: %w", path, err)
+		}
+		return &diffSnapshotFile{Snapshot: snap, HasFacts: true, HasMemories: true}, nil
+	}
+	return parseDiffExport(data, path)
+}
+
+// parseDiffExport loads a `cortex export --format json` file: facts (with
+// --facts) or memories, either under the export metadata or, for older
+// exports, as a bare array.
+func parseDiffExport(data []byte, path string) (*diffSnapshotFile, error) {
+	var envelope struct {
+		Export *struct {
+			Kind string `json:"kind"`
+		} `json:"export"`
+		Memories json.RawMessage `json:"memories"`
+		Facts    json.RawMessage `json:"facts"`
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) && json.Unmarshal(data, &envelope) == nil && envelope.Export != nil {
+		switch {
+		case envelope.Export.Kind == "facts" && len(envelope.Facts) > 0:
+			data = envelope.Facts
+		case envelope.Export.Kind == "memories" && len(envelope.Memories) > 0:
+			data = envelope.Memories
+		default:
+			return nil, fmt.Errorf("%s is an empty export; nothing to compare", path)
+		}
+	}
+
+	var probe []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("%s is neither a cortex database nor a JSON export", path)
+	}
+	if len(probe) == 0 {
+		return nil, fmt.Errorf("%s is an empty export; nothing to compare", path)
+	}
+
+	snap := store.NewSnapshot()
+	if _, ok := probe[0]["Predicate"]; ok {
+		var facts []store.Fact
+		if err := json.Unmarshal(data, &facts); err != nil {
+			return nil, fmt.Errorf("parsing fact export: %w", err)
+		}
+		for _, f := range facts {
+			snap.Facts[f.ID] = store.SnapshotFact{
+				ID: f.ID, MemoryID: f.MemoryID, Subject: f.Subject, Predicate: f.Predicate,
+				Object: f.Object, Confidence: f.Confidence, SupersededBy: f.SupersededBy,
+			}
+		}
+		return &diffSnapshotFile{Snapshot: snap, HasFacts: true}, nil
+	}
+	if _, ok := probe[0]["ContentHash"]; ok {
+		var memories []store.Memory
+		if err := json.Unmarshal(data, &memories); err != nil {
+			return nil, fmt.Errorf("parsing memory export: %w", err)
+		}
+		for _, m := range memories {
+			snap.Memories[m.ID] = store.SnapshotMemory{
+				ID: m.ID, SourceFile: m.SourceFile, ContentHash: m.ContentHash, Deleted: m.DeletedAt != nil,
+			}
+		}
+		return &diffSnapshotFile{Snapshot: snap, HasMemories: true}, nil
+	}
+	return nil, fmt.Errorf("%s does not look like a cortex fact or memory export", path)
+}
+
+func printSnapshotDiff(path string, d store.SnapshotDiff, facts, memories bool, limit int) {
+	fmt.Printf("Changes since %s\n", path)
+	if d.Empty() {
+		fmt.Println("\nNo changes.")
+		return
+	}
+	factLine := func(f store.SnapshotFact) string {
+		return truncateString(formatFactText(f.Subject, f.Predicate, f.Object), 80)
+	}
+	section := func(n int, row func(i int)) {
+		shown := n
+		if limit < shown {
+			shown = limit
+		}
+		for i := 0; i < shown; i++ {
+			row(i)
+		}
+		if n > shown {
+			fmt.Printf("    … %d more (use --json for all)\n", n-shown)
+		}
+	}
+
+	if facts {
+		fmt.Printf("\nFacts: +%d added, -%d removed, %d superseded, %d confidence %s\n",
+			len(d.FactsAdded), len(d.FactsRemoved), len(d.FactsSuperseded),
+			len(d.ConfidenceChanged), pluralize("change", "changes", len(d.ConfidenceChanged)))
+		section(len(d.FactsAdded), func(i int) {
+			f := d.FactsAdded[i]
+			fmt.Printf("  + #%d %s\n", f.ID, factLine(f))
+		})
+		section(len(d.FactsRemoved), func(i int) {
+			f := d.FactsRemoved[i]
+			fmt.Printf("  - #%d %s\n", f.ID, factLine(f))
+		})
+		section(len(d.FactsSuperseded), func(i int) {
+			f := d.FactsSuperseded[i]
+			fmt.Printf("  ↷ #%d %s (superseded by #%d)\n", f.ID, factLine(f), *f.SupersededBy)
+		})
+		section(len(d.ConfidenceChanged), func(i int) {
+			c := d.ConfidenceChanged[i]
+			fmt.Printf("  ~ #%d %.2f → %.2f %s\n", c.ID, c.Before, c.After, factLine(c.SnapshotFact))
+		})
+	}
+	if memories {
+		fmt.Printf("\nMemories: +%d added, -%d removed, %d changed\n",
+			len(d.MemoriesAdded), len(d.MemoriesRemoved), len(d.MemoriesChanged))
+		for _, group := range []struct {
+			mark string
+			rows []store.SnapshotMemory
+		}{{"+", d.MemoriesAdded}, {"-", d.MemoriesRemoved}, {"~", d.MemoriesChanged}} {
+			section(len(group.rows), func(i int) {
+				m := group.rows[i]
+				fmt.Printf("  %s #%d %s\n", group.mark, m.ID, m.SourceFile)
+			})
+		}
+	}
+}
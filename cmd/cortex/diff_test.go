@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunDiff_AgainstDatabaseCopyAndExport(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "deploy notes", SourceFile: "notes.md"})
+	oldFact, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "region", Object: "us-east", FactType: "kv", Confidence: 0.9})
+	facts, err := s.ListFacts(ctx, store.ListOpts{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	backup := filepath.Join(home, "backup.db")
+	data, err := os.ReadFile(globalDBPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(backup, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	export := filepath.Join(home, "facts.json")
+	exported, _ := json.Marshal(facts)
+	if err := os.WriteFile(export, exported, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	newFact, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "region", Object: "eu-west", FactType: "kv", Confidence: 0.9})
+	if err := s.SupersedeFact(ctx, oldFact, newFact, "moved region"); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	for _, snapshot := range []string{backup, export} {
+		var runErr error
+		out := captureStdout(func() { runErr = runDiff([]string{snapshot, "--facts", "--json"}) })
+		if runErr != nil {
+			t.Fatalf("diff %s: %v", snapshot, runErr)
+		}
+		var got diffJSON
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("diff %s: invalid JSON %q: %v", snapshot, out, err)
+		}
+		if len(got.FactsAdded) != 1 || got.FactsAdded[0].ID != newFact {
+			t.Fatalf("diff %s: facts added = %+v", snapshot, got.FactsAdded)
+		}
+		if len(got.FactsSuperseded) != 1 || got.FactsSuperseded[0].ID != oldFact {
+			t.Fatalf("diff %s: facts superseded = %+v", snapshot, got.FactsSuperseded)
+		}
+	}
+
+	// The backup was opened read-only and must not pick up the new fact.
+	b, err := store.NewStore(store.StoreConfig{DBPath: backup, ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if f, _ := b.GetFact(ctx, newFact); f != nil {
+		t.Fatal("backup was modified by diff")
+	}
+
+	if err := runDiff([]string{export, "--memories"}); err == nil || !strings.Contains(err.Error(), "does not contain") {
+		t.Fatalf("expected error for memories diff against a fact export, got %v", err)
+	}
+	if err := runDiff([]string{backup, "--facts", "--memories"}); err == nil {
+		t.Fatal("expected --facts with --memories to fail")
+	}
+}
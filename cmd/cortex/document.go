@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const documentUsage = `usage: cortex document <command>
+
+Commands:
+  list [--all] [--json]                  Documents (source files) with chunk, fact, and embedding counts
+  show <id|path> [--json]                One document's stats and chunks
+  delete <id|path> [--dry-run] [--json]  Soft-delete every chunk of a document
+  refresh <id|path> [refresh-source flags]  Re-import a document from disk`
+
+// runDocument handles `cortex document`: the memories chunked from one
+// source file, handled as a unit.
+func runDocument(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", documentUsage)
+	}
+	switch args[0] {
+	case "list", "ls":
+		return runDocumentList(args[1:])
+	case "show":
+		return runDocumentShow(args[1:])
+	case "delete", "rm":
+		return runDocumentDelete(args[1:])
+	case "refresh":
+		return runDocumentRefresh(args[1:])
+	case "--help", "-h", "help":
+		fmt.Println(documentUsage)
+		return nil
+	default:
+		return fmt.Errorf("unknown document command: %s\n%s", args[0], documentUsage)
+	}
+}
+
+func runDocumentList(args []string) error {
+	all, jsonOutput := false, false
+	for _, arg := range args {
+		switch {
+		case arg == "--all":
+			all = true
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	s, closeStore, err := openDocumentStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	docs, err := s.ListDocuments(context.Background(), all)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput || !isTTY() {
+		if docs == nil {
+			docs = []*store.Document{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(docs)
+	}
+	if len(docs) == 0 {
+		fmt.Println("No documents. Import files with `cortex import <path>`.")
+		return nil
+	}
+	fmt.Printf("%-6s %6s %6s %8s %10s  %s\n", "ID", "CHUNKS", "FACTS", "EMBEDDED", "SIZE", "SOURCE")
+	for _, d := range docs {
+		fmt.Printf("%-6d %6d %6d %8d %10s  %s\n", d.ID, d.Chunks, d.Facts, d.Embedded, formatBytes(d.Bytes), d.SourceFile)
+	}
+	fmt.Printf("\n%d %s\n", len(docs), pluralize("document", "documents", len(docs)))
+	return nil
+}
+
+// documentShowJSON is the `cortex document show --json` document.
+type documentShowJSON struct {
+	Document *store.Document `json:"document"`
+	Chunks   []*store.Memory `json:"chunks"`
+}
+
+func runDocumentShow(args []string) error {
+	ref, jsonOutput, err := parseDocumentArgs(args, "show", nil)
+	if err != nil {
+		return err
+	}
+
+	s, closeStore, err := openDocumentStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	ctx := context.Background()
+	doc, err := resolveDocument(ctx, s, ref)
+	if err != nil {
+		return err
+	}
+	chunks, err := s.ListMemories(ctx, store.ListOpts{SourceFile: doc.SourceFile, Limit: math.MaxInt32})
+	if err != nil {
+		return err
+	}
+	if err := s.AttachMemoryAnchors(ctx, chunks); err != nil {
+		return err
+	}
+
+	if jsonOutput || !isTTY() {
+		if chunks == nil {
+			chunks = []*store.Memory{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(documentShowJSON{Document: doc, Chunks: chunks})
+	}
+	printDocument(doc)
+	fmt.Printf("\n📄 Chunks (%d):\n", len(chunks))
+	for _, m := range chunks {
+		where := ""
+		if m.Anchor != nil && m.Anchor.HeadingPath != "" {
+			where = "§ " + m.Anchor.HeadingPath + "  "
+		} else if m.SourceLine > 0 {
+			where = fmt.Sprintf("line %d  ", m.SourceLine)
+		}
+		content := strings.ReplaceAll(search.TruncateContent(m.Content, 100), "\n", " ")
+		fmt.Printf("  #%d %s%s\n", m.ID, where, content)
+	}
+	return nil
+}
+
+func printDocument(d *store.Document) {
+	fmt.Printf("Document #%d  📁 %s\n", d.ID, d.SourceFile)
+	if d.VersionHash != "" {
+		hash := d.VersionHash
+		if len(hash) > 12 {
+			hash = hash[:12]
+		}
+		fmt.Printf("  version:  %s\n", hash)
+	}
+	fmt.Printf("  chunks:   %d", d.Chunks)
+	if d.DeletedChunks > 0 {
+		fmt.Printf(" (+%d deleted)", d.DeletedChunks)
+	}
+	fmt.Println()
+	fmt.Printf("  facts:    %d\n", d.Facts)
+	fmt.Printf("  embedded: %d/%d\n", d.Embedded, d.Chunks)
+	fmt.Printf("  size:     %s\n", formatBytes(d.Bytes))
+	if !d.FirstImported.IsZero() {
+		fmt.Printf("  imported: %s – %s\n", d.FirstImported.Local().Format("2006-01-02 15:04"), d.LastImported.Local().Format("2006-01-02 15:04"))
+	}
+}
+
+func runDocumentDelete(args []string) error {
+	dryRun := false
+	ref, jsonOutput, err := parseDocumentArgs(args, "delete [--dry-run]", func(arg string) bool {
+		if arg == "--dry-run" || arg == "-n" {
+			dryRun = true
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+
+	s, closeStore, err := openDocumentStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	ctx := context.Background()
+	doc, err := resolveDocument(ctx, s, ref)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		if jsonOutput || !isTTY() {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(map[string]interface{}{"document": doc, "dry_run": true})
+		}
+		printDocument(doc)
+		fmt.Printf("\n[dry-run] Would delete %d %s. No changes made.\n", doc.Chunks, pluralize("chunk", "chunks", doc.Chunks))
+		return nil
+	}
+
+	del, err := s.DeleteDocument(ctx, doc.ID)
+	if err != nil {
+		return err
+	}
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{"document_id": doc.ID, "source_file": doc.SourceFile, "deleted": del.Deleted, "held": del.Held})
+	}
+	fmt.Printf("Deleted %d %s of %s\n", del.Deleted, pluralize("chunk", "chunks", del.Deleted), doc.SourceFile)
+	if del.Held > 0 {
+		fmt.Printf("  kept %d under an archive hold (see `cortex archive list`)\n", del.Held)
+	}
+	return nil
+}
+
+// runDocumentRefresh re-imports a document through refresh-source, which
+// takes the same flags.
+func runDocumentRefresh(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: cortex document refresh <id|path> [--dry-run] [--extract] [--embed <model>] [--force]")
+	}
+	s, closeStore, err := openDocumentStore()
+	if err != nil {
+		return err
+	}
+	doc, err := resolveDocument(context.Background(), s, args[0])
+	closeStore()
+	if err != nil {
+		return err
+	}
+	return runRefreshSource(append([]string{doc.SourceFile}, args[1:]...))
+}
+
+// parseDocumentArgs reads `<id|path> [--json]` plus any flags extra accepts.
+func parseDocumentArgs(args []string, cmd string, extra func(string) bool) (string, bool, error) {
+	ref, jsonOutput := "", false
+	for _, arg := range args {
+		switch {
+		case arg == "--json":
+			jsonOutput = true
+		case extra != nil && extra(arg):
+		case strings.HasPrefix(arg, "-"):
+			return "", false, fmt.Errorf("unknown flag: %s", arg)
+		case ref == "":
+			ref = arg
+		default:
+			return "", false, fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if ref == "" {
+		return "", false, fmt.Errorf("usage: cortex document %s <id|path> [--json]", cmd)
+	}
+	return ref, jsonOutput, nil
+}
+
+// resolveDocument finds a document by ID or source path. Relative paths
+// are tried as given and then as absolute paths, which is how import
+// records them.
+func resolveDocument(ctx context.Context, s *store.SQLiteStore, ref string) (*store.Document, error) {
+	doc, err := s.ResolveDocument(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		if abs, absErr := filepath.Abs(ref); absErr == nil && abs != ref {
+			if doc, err = s.ResolveDocument(ctx, abs); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("document %q not found (see `cortex document list`)", ref)
+	}
+	return doc, nil
+}
+
+func openDocumentStore() (*store.SQLiteStore, func(), error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening store: %w", err)
+	}
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, nil, fmt.Errorf("documents require SQLiteStore")
+	}
+	return sqlStore, func() { s.Close() }, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunDocumentListShowDelete(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m1, _ := s.AddMemory(ctx, &store.Memory{Content: "deploys use fly.io", SourceFile: "/notes/ops.md"})
+	s.AddMemory(ctx, &store.Memory{Content: "region is iad", SourceFile: "/notes/ops.md"})
+	other, _ := s.AddMemory(ctx, &store.Memory{Content: "lunch is at noon", SourceFile: "/notes/life.md"})
+	s.AddFact(ctx, &store.Fact{MemoryID: m1, Subject: "deploy", Predicate: "uses", Object: "fly.io", FactType: "kv"})
+	s.Close()
+
+	var runErr error
+	out := captureStdout(func() { runErr = runDocument([]string{"list", "--json"}) })
+	if runErr != nil {
+		t.Fatalf("document list: %v", runErr)
+	}
+	var docs []store.Document
+	if err := json.Unmarshal([]byte(out), &docs); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(docs) != 2 || docs[1].SourceFile != "/notes/ops.md" || docs[1].Chunks != 2 || docs[1].Facts != 1 {
+		t.Fatalf("documents = %+v", docs)
+	}
+	opsID := strconv.FormatInt(docs[1].ID, 10)
+
+	out = captureStdout(func() { runErr = runDocument([]string{"show", "/notes/ops.md", "--json"}) })
+	if runErr != nil {
+		t.Fatalf("document show: %v", runErr)
+	}
+	var shown documentShowJSON
+	if err := json.Unmarshal([]byte(out), &shown); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if shown.Document.ID != docs[1].ID || len(shown.Chunks) != 2 {
+		t.Fatalf("show = %+v", shown)
+	}
+
+	out = captureStdout(func() { runErr = runExport([]string{"--document", opsID, "--format", "json"}) })
+	if runErr != nil {
+		t.Fatalf("export --document: %v", runErr)
+	}
+	if !strings.Contains(out, "fly.io") || strings.Contains(out, "lunch") {
+		t.Fatalf("export --document should hold only ops.md chunks:\n%s", out)
+	}
+
+	out = captureStdout(func() { runErr = runDocument([]string{"delete", opsID, "--json"}) })
+	if runErr != nil {
+		t.Fatalf("document delete: %v", runErr)
+	}
+	if !strings.Contains(out, `"deleted": 2`) {
+		t.Fatalf("delete output = %s", out)
+	}
+
+	s, err = store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if m, _ := s.GetMemory(ctx, m1); m == nil || m.DeletedAt == nil {
+		t.Fatal("ops.md chunk should be soft-deleted")
+	}
+	if m, _ := s.GetMemory(ctx, other); m == nil || m.DeletedAt != nil {
+		t.Fatal("life.md chunk should be untouched")
+	}
+}
+
+func TestRunDocument_Errors(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	if err := runDocument([]string{"show", "/missing.md"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("missing document error = %v", err)
+	}
+	if err := runDocument([]string{"list", "--bogus"}); err == nil || !strings.Contains(err.Error(), "unknown flag") {
+		t.Fatalf("bad flag error = %v", err)
+	}
+	if err := runDocument([]string{"frobnicate"}); err == nil {
+		t.Fatal("unknown subcommand should fail")
+	}
+}
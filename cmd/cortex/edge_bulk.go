@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const edgeImportUsage = `usage: cortex edge import <file.csv|-> [--agent <id>] [--dry-run] [--json]
+
+Each row is: source_fact_id, target_fact_id, type[, confidence]
+A header row naming those columns (source, target, type, confidence) is
+optional and lets the columns come in any order. Every row is validated
+first; if any is invalid nothing is imported.`
+
+const edgeRemoveUsage = `usage: cortex edge remove <edge_id>
+       cortex edge remove [--source-fact N] [--target-fact N] [--type X] [--origin explicit|detected|inferred] [--dry-run] [--json]`
+
+// edgeImportRow is one parsed CSV row and the line it came from.
+type edgeImportRow struct {
+	line int
+	edge store.FactEdge
+}
+
+// runEdgeImport adds edges in bulk from a CSV file, for graphs curated
+// outside Cortex.
+func runEdgeImport(args []string) error {
+	var path, agentID string
+	dryRun, jsonOutput := false, false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--agent":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", arg)
+			}
+			i++
+			agentID = args[i]
+		case strings.HasPrefix(arg, "--agent="):
+			agentID = strings.TrimPrefix(arg, "--agent=")
+		case arg == "--dry-run":
+			dryRun = true
+		case arg == "--json":
+			jsonOutput = true
+		case arg == "-" && path == "":
+			path = arg
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case path == "":
+			path = arg
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if path == "" {
+		return fmt.Errorf("%s", edgeImportUsage)
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", path, err)
+		}
+		defer f.Close()
+		in = f
+	}
+	rows, err := parseEdgeCSV(in)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("%s has no edges", path)
+	}
+	edges := make([]store.FactEdge, len(rows))
+	for i, r := range rows {
+		edges[i] = r.edge
+		edges[i].AgentID = agentID
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("edges require SQLiteStore")
+	}
+
+	res, err := sqlStore.ImportEdges(context.Background(), edges, dryRun)
+	if err != nil {
+		return err
+	}
+
+	type lineProblem struct {
+		Line   int    `json:"line"`
+		Reason string `json:"reason"`
+	}
+	problems := make([]lineProblem, len(res.Invalid))
+	for i, p := range res.Invalid {
+		problems[i] = lineProblem{Line: rows[p.Index].line, Reason: p.Reason}
+	}
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(struct {
+			Added    int           `json:"added"`
+			Existing int           `json:"existing"`
+			Invalid  []lineProblem `json:"invalid,omitempty"`
+			DryRun   bool          `json:"dry_run"`
+		}{res.Added, res.Existing, problems, res.DryRun}); err != nil {
+			return err
+		}
+	} else {
+		for _, p := range problems {
+			fmt.Printf("  line %d: %s\n", p.Line, p.Reason)
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d invalid %s; nothing imported", len(problems), pluralize("row", "rows", len(problems)))
+	}
+	if jsonOutput {
+		return nil
+	}
+	verb := "Imported"
+	if res.DryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d %s", verb, res.Added, pluralize("edge", "edges", res.Added))
+	if res.Existing > 0 {
+		fmt.Printf(" (%d already present)", res.Existing)
+	}
+	fmt.Println(".")
+	return nil
+}
+
+// parseEdgeCSV reads source, target, type, and optional confidence columns.
+// A first row whose source column is not a number is read as a header.
+func parseEdgeCSV(in io.Reader) ([]edgeImportRow, error) {
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+
+	cols := map[string]int{"source": 0, "target": 1, "type": 2, "confidence": 3}
+	var rows []edgeImportRow
+	first := true
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+		if first {
+			first = false
+			if _, err := strconv.ParseInt(strings.TrimSpace(record[0]), 10, 64); err != nil {
+				header, err := edgeCSVHeader(record)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+				cols = header
+				continue
+			}
+		}
+		field := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := edgeImportRow{line: line}
+		var err1, err2 error
+		row.edge.SourceFactID, err1 = strconv.ParseInt(field("source"), 10, 64)
+		row.edge.TargetFactID, err2 = strconv.ParseInt(field("target"), 10, 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("line %d: source and target must be fact IDs", line)
+		}
+		row.edge.EdgeType = store.EdgeType(field("type"))
+		if c := field("confidence"); c != "" {
+			conf, err := strconv.ParseFloat(c, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid confidence %q", line, c)
+			}
+			row.edge.Confidence = conf
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func edgeCSVHeader(record []string) (map[string]int, error) {
+	aliases := map[string]string{
+		"source": "source", "source_fact_id": "source", "source_fact": "source", "from": "source",
+		"target": "target", "target_fact_id": "target", "target_fact": "target", "to": "target",
+		"type": "type", "edge_type": "type",
+		"confidence": "confidence",
+	}
+	cols := map[string]int{}
+	for i, name := range record {
+		if col, ok := aliases[strings.ToLower(strings.TrimSpace(name))]; ok {
+			cols[col] = i
+		}
+	}
+	for _, required := range []string{"source", "target", "type"} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("header is missing a %s column", required)
+		}
+	}
+	return cols, nil
+}
+
+// runEdgeRemoveBulk removes every edge matching the given filters.
+func runEdgeRemoveBulk(args []string) error {
+	var filter store.EdgeFilter
+	dryRun, jsonOutput := false, false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--source-fact", "--target-fact", "--type", "--origin":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", arg)
+			}
+			i++
+			val := args[i]
+			switch arg {
+			case "--source-fact", "--target-fact":
+				id, err := strconv.ParseInt(val, 10, 64)
+				if err != nil || id <= 0 {
+					return fmt.Errorf("invalid %s value: %s", arg, val)
+				}
+				if arg == "--source-fact" {
+					filter.SourceFactID = id
+				} else {
+					filter.TargetFactID = id
+				}
+			case "--type":
+				t, err := store.ParseEdgeType(val)
+				if err != nil {
+					return err
+				}
+				filter.Type = t
+			case "--origin":
+				switch src := store.EdgeSource(strings.ToLower(val)); src {
+				case store.EdgeSourceExplicit, store.EdgeSourceDetected, store.EdgeSourceInferred:
+					filter.Source = src
+				default:
+					return fmt.Errorf("invalid --origin value: %s (use explicit, detected, or inferred)", val)
+				}
+			}
+		case "--dry-run":
+			dryRun = true
+		case "--json":
+			jsonOutput = true
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return fmt.Errorf("unknown flag: %s", arg)
+			}
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if filter.Empty() {
+		return fmt.Errorf("%s", edgeRemoveUsage)
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("edges require SQLiteStore")
+	}
+	ctx := context.Background()
+
+	if dryRun {
+		edges, err := sqlStore.FindEdges(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if edges == nil {
+			edges = []store.FactEdge{}
+		}
+		if jsonOutput {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(map[string]any{"dry_run": true, "removed": len(edges), "edges": edges})
+		}
+		for _, e := range edges {
+			fmt.Printf("  #%d fact %d -[%s]→ fact %d (%.0f%%, %s)\n",
+				e.ID, e.SourceFactID, e.EdgeType, e.TargetFactID, e.Confidence*100, e.Source)
+		}
+		fmt.Printf("Would remove %d %s.\n", len(edges), pluralize("edge", "edges", len(edges)))
+		return nil
+	}
+
+	n, err := sqlStore.RemoveEdges(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{"dry_run": false, "removed": n})
+	}
+	fmt.Printf("✓ Removed %d %s.\n", n, pluralize("edge", "edges", n))
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunEdgeImportAndBulkRemove(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "deploy notes", SourceFile: "notes.md"})
+	f1, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "tool", Object: "argo", FactType: "kv"})
+	f2, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "window", Object: "friday", FactType: "kv"})
+	f3, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "owner", Object: "ops", FactType: "kv"})
+	s.Close()
+
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.csv")
+	os.WriteFile(bad, []byte(fmt.Sprintf("%d,%d,supports\n%d,999,supports\n", f1, f2, f1)), 0o644)
+	var runErr error
+	out := captureStdout(func() { runErr = runEdgeImport([]string{bad}) })
+	if runErr == nil || !strings.Contains(runErr.Error(), "nothing imported") || !strings.Contains(out, "line 2: fact 999 not found") {
+		t.Fatalf("bad import = %q, %v", out, runErr)
+	}
+
+	good := filepath.Join(dir, "edges.csv")
+	os.WriteFile(good, []byte(fmt.Sprintf("type,confidence,source,target\nsupports,0.9,%d,%d\nrelates_to,,%d,%d\nrelates_to,,%d,%d\n", f1, f2, f1, f3, f2, f3)), 0o644)
+	out = captureStdout(func() { runErr = runEdgeImport([]string{good, "--dry-run"}) })
+	if runErr != nil || !strings.Contains(out, "Would import 3 edges.") {
+		t.Fatalf("dry-run import = %q, %v", out, runErr)
+	}
+	out = captureStdout(func() { runErr = runEdgeImport([]string{good, "--agent", "analyst"}) })
+	if runErr != nil || !strings.Contains(out, "Imported 3 edges.") {
+		t.Fatalf("import = %q, %v", out, runErr)
+	}
+	out = captureStdout(func() { runErr = runEdgeImport([]string{good}) })
+	if runErr != nil || !strings.Contains(out, "Imported 0 edges (3 already present).") {
+		t.Fatalf("re-import = %q, %v", out, runErr)
+	}
+
+	src := fmt.Sprint(f1)
+	out = captureStdout(func() {
+		runErr = runEdge([]string{"remove", "--source-fact", src, "--type", "relates_to", "--dry-run"})
+	})
+	if runErr != nil || !strings.Contains(out, "Would remove 1 edge.") {
+		t.Fatalf("dry-run remove = %q, %v", out, runErr)
+	}
+	out = captureStdout(func() { runErr = runEdge([]string{"remove", "--source-fact", src, "--type", "relates_to"}) })
+	if runErr != nil || !strings.Contains(out, "Removed 1 edge.") {
+		t.Fatalf("remove = %q, %v", out, runErr)
+	}
+
+	s, err = store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	edges, _ := s.(*store.SQLiteStore).FindEdges(ctx, store.EdgeFilter{})
+	if len(edges) != 2 || edges[0].AgentID != "analyst" || edges[0].Confidence != 0.9 {
+		t.Fatalf("edges left = %+v", edges)
+	}
+
+	for _, args := range [][]string{
+		{"remove", "--type", "causes"},
+		{"remove", "--source-fact", "x"},
+		{"remove", "--dry-run"},
+		{"import"},
+	} {
+		if err := runEdge(args); err == nil {
+			t.Errorf("runEdge(%v): expected error", args)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const edgeLearnUsage = "usage: cortex edge learn [--min-count N] [--max-new N] [--dry-run] [--json]"
+
+// runEdgeLearn turns sustained co-access into weighted relates_to edges and
+// fades learned edges whose facts stopped being retrieved together. The
+// daemon runs the same pass as the learn_edges schedule task.
+func runEdgeLearn(args []string) error {
+	opts := store.DefaultLearnEdgesOpts()
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--min-count" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid --min-count value: %s", args[i])
+			}
+			opts.MinCount = n
+		case arg == "--max-new" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid --max-new value: %s", args[i])
+			}
+			opts.MaxNew = n
+		case arg == "--dry-run":
+			opts.DryRun = true
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("%s", edgeLearnUsage)
+		}
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("edges require SQLiteStore")
+	}
+
+	result, err := sqlStore.LearnCoaccessEdges(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	if result.DryRun {
+		fmt.Print("🔍 Dry run: ")
+	}
+	fmt.Printf("🔗 %s\n", learnEdgesSummary(result))
+	for _, c := range result.Changes {
+		switch c.Action {
+		case "create":
+			fmt.Printf("  + fact #%d -[relates_to]- fact #%d  weight %.2f (%d co-accesses)\n", c.FactIDA, c.FactIDB, c.NewWeight, c.Count)
+		case "remove":
+			fmt.Printf("  - fact #%d -[relates_to]- fact #%d  weight %.2f → removed\n", c.FactIDA, c.FactIDB, c.OldWeight)
+		default:
+			fmt.Printf("  ~ fact #%d -[relates_to]- fact #%d  weight %.2f → %.2f (%s)\n", c.FactIDA, c.FactIDB, c.OldWeight, c.NewWeight, c.Action)
+		}
+	}
+	return nil
+}
+
+// learnEdgesSummary is the one-line result of a learning pass, shared by
+// the CLI and the schedule run history.
+func learnEdgesSummary(r *store.LearnEdgesResult) string {
+	return fmt.Sprintf("%d learned edges: %d created, %d strengthened, %d decayed, %d removed",
+		r.Learned, r.Created, r.Strengthened, r.Decayed, r.Removed)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunEdgeLearn(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlStore := s.(*store.SQLiteStore)
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "deploy notes", SourceFile: "notes.md"})
+	f1, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "tool", Object: "argo", FactType: "kv"})
+	f2, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "window", Object: "friday", FactType: "kv"})
+	for i := 0; i < 3; i++ {
+		if err := sqlStore.RecordCooccurrence(ctx, f1, f2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Backdate the first sighting so the pair counts as sustained.
+	if _, err := sqlStore.GetDB().Exec(`UPDATE fact_cooccurrence_v1 SET first_seen = datetime(last_seen, '-3 days')`); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	run := func(args ...string) store.LearnEdgesResult {
+		t.Helper()
+		var runErr error
+		out := captureStdout(func() { runErr = runEdgeLearn(args) })
+		if runErr != nil {
+			t.Fatalf("runEdgeLearn(%v): %v", args, runErr)
+		}
+		var res store.LearnEdgesResult
+		if err := json.Unmarshal([]byte(out), &res); err != nil {
+			t.Fatalf("decoding %q: %v", out, err)
+		}
+		return res
+	}
+
+	if res := run("--json"); res.Created != 0 {
+		t.Fatalf("3 co-accesses are below the default minimum: %+v", res)
+	}
+	if res := run("--min-count", "3", "--dry-run", "--json"); res.Created != 1 || !res.DryRun {
+		t.Fatalf("dry run = %+v", res)
+	}
+	if res := run("--min-count", "3", "--json"); res.Created != 1 || res.Changes[0].NewWeight != 0.5 {
+		t.Fatalf("learn = %+v", res)
+	}
+
+	for _, args := range [][]string{{"--min-count", "0"}, {"--max-new", "x"}, {"--bogus"}, {"extra"}} {
+		if err := runEdgeLearn(args); err == nil {
+			t.Errorf("runEdgeLearn(%v): expected error", args)
+		}
+	}
+}
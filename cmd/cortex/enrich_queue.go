@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/jobs"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// minEnrichContent is the shortest memory worth an enrichment call; the
+// enrich job and runEnrichmentOnImportedMemories skip anything shorter.
+const minEnrichContent = 50
+
+// queueImportEnrichment hands LLM enrichment of freshly imported memories
+// to a background enrich job instead of running it inline, and marks them
+// pending enrichment until the job gets to them. Whatever runner claims the
+// job (cortex daemon --llm, cortex mcp --llm, or jobs start --run) supplies
+// the model. With useSkipList, memories on the enrich zero-yield skip list
+// are not queued.
+func queueImportEnrichment(ctx context.Context, s store.Store, newMemoryIDs []int64, useSkipList bool) error {
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("--async-enrich requires SQLiteStore")
+	}
+	memories, err := s.GetMemoriesByIDs(ctx, newMemoryIDs)
+	if err != nil {
+		return fmt.Errorf("fetching new memories: %w", err)
+	}
+	// Enrichment providers are remote; local-llm-only projects are skipped.
+	memories, _ = store.FilterMemoriesByPrivacy(memories, store.PrivacyLocalLLMOnly)
+	var skip map[int64]bool
+	if useSkipList {
+		if skip, err = sqlStore.ZeroYieldMemories(ctx, store.YieldStageEnrich, memories); err != nil {
+			return err
+		}
+	}
+	var ids []int64
+	skipped := 0
+	for _, m := range memories {
+		if len(strings.TrimSpace(m.Content)) < minEnrichContent {
+			continue
+		}
+		if skip[m.ID] {
+			skipped++
+			continue
+		}
+		ids = append(ids, m.ID)
+	}
+	printSkipListNotice(skipped)
+	if len(ids) == 0 {
+		fmt.Println("  🧠 Enrichment: nothing to queue")
+		return nil
+	}
+
+	job, err := jobs.Enqueue(ctx, sqlStore, jobs.KindEnrich, jobs.ExtractParams{MemoryIDs: ids, Enrich: true}, "import")
+	if err != nil {
+		return err
+	}
+	if err := sqlStore.MarkEnrichmentPending(ctx, job.ID, ids); err != nil {
+		return err
+	}
+	fmt.Printf("  🧠 Enrichment queued: job #%d for %d %s (cortex jobs status %d)\n",
+		job.ID, len(ids), pluralize("memory", "memories", len(ids)), job.ID)
+	fmt.Println("     Runs in cortex daemon or cortex mcp started with --llm; facts upgrade as it goes.")
+	return nil
+}
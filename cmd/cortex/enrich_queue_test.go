@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/jobs"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestImportAsyncEnrich_QueuesJob(t *testing.T) {
+	useWorkspaceHome(t)
+	dir := t.TempDir()
+	globalDBPath = filepath.Join(dir, "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	notes := filepath.Join(dir, "ops.md")
+	content := "# Ops\n\nDeploy target: fly.io\n\nDeploys run on fridays after the on-call engineer reviews the checklist.\n"
+	if err := os.WriteFile(notes, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var runErr error
+	out := captureStdout(func() { runErr = runImport([]string{notes, "--async-enrich"}) })
+	if runErr != nil {
+		t.Fatalf("import: %v", runErr)
+	}
+	if !strings.Contains(out, "Enrichment queued: job #") || strings.Contains(out, "Running LLM enrichment") {
+		t.Fatalf("import output = %q", out)
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	sqlStore := s.(*store.SQLiteStore)
+	ctx := context.Background()
+
+	queued, err := sqlStore.ListJobs(ctx, store.JobListOpts{Kind: jobs.KindEnrich})
+	if err != nil || len(queued) != 1 || queued[0].Status != store.JobQueued || queued[0].CreatedBy != "import" {
+		t.Fatalf("enrich jobs = %+v, %v", queued, err)
+	}
+	status, err := sqlStore.Status(ctx)
+	if err != nil || status.PendingEnrichment == 0 {
+		t.Fatalf("status = %+v, %v", status, err)
+	}
+
+	memories, _ := s.ListMemories(ctx, store.ListOpts{Limit: 10})
+	if len(memories) == 0 {
+		t.Fatal("no memories imported")
+	}
+	view, err := buildShowView(ctx, sqlStore, memories[0].ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.Enrichment == nil || view.Enrichment.JobID != queued[0].ID || view.Enrichment.Status != store.EnrichmentPending {
+		t.Fatalf("show enrichment = %+v", view.Enrichment)
+	}
+}
@@ -0,0 +1,415 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hurttlocker/cortex/internal/embed"
+	"github.com/hurttlocker/cortex/internal/extract"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func examplesUsageText() string {
+	return `Usage: cortex examples <add|list|remove> [flags]
+
+Curated few-shot examples for LLM enrichment. Each example pairs a piece of
+content with the facts it should yield; during 'import --enrich' the most
+similar examples (by embedding, or word overlap when no embedder is
+configured) are shown to the model before each chunk.
+
+Subcommands:
+  add --content <file> --facts <file.json> [--note <text>] [--embed <provider/model>]
+  add --text "<content>" --facts <file.json> [--note <text>]
+                        Store an example; facts is a JSON array of
+                        {"subject","predicate","object","type"} objects
+  list [--json]         List stored examples
+  remove <id>           Delete an example
+
+An example is embedded when added if an embedder is configured (or --embed is
+given); examples added without one are embedded on first use.`
+}
+
+func runExamples(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(examplesUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "add":
+		return runExamplesAdd(args[1:])
+	case "list":
+		return runExamplesList(args[1:])
+	case "remove", "rm":
+		return runExamplesRemove(args[1:])
+	default:
+		return fmt.Errorf("unknown examples subcommand: %s", args[0])
+	}
+}
+
+func openExamplesStore() (*store.SQLiteStore, func(), error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening store: %w", err)
+	}
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, nil, fmt.Errorf("examples require SQLiteStore")
+	}
+	return sqlStore, func() { s.Close() }, nil
+}
+
+func runExamplesAdd(args []string) error {
+	contentPath, text, factsPath, note, embedFlag := "", "", "", "", ""
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--content" && i+1 < len(args):
+			i++
+			contentPath = args[i]
+		case strings.HasPrefix(args[i], "--content="):
+			contentPath = strings.TrimPrefix(args[i], "--content=")
+		case args[i] == "--text" && i+1 < len(args):
+			i++
+			text = args[i]
+		case strings.HasPrefix(args[i], "--text="):
+			text = strings.TrimPrefix(args[i], "--text=")
+		case args[i] == "--facts" && i+1 < len(args):
+			i++
+			factsPath = args[i]
+		case strings.HasPrefix(args[i], "--facts="):
+			factsPath = strings.TrimPrefix(args[i], "--facts=")
+		case args[i] == "--note" && i+1 < len(args):
+			i++
+			note = args[i]
+		case strings.HasPrefix(args[i], "--note="):
+			note = strings.TrimPrefix(args[i], "--note=")
+		case args[i] == "--embed" && i+1 < len(args):
+			i++
+			embedFlag = args[i]
+		case strings.HasPrefix(args[i], "--embed="):
+			embedFlag = strings.TrimPrefix(args[i], "--embed=")
+		default:
+			return fmt.Errorf("unknown flag: %s", args[i])
+		}
+	}
+	if (contentPath == "") == (text == "") || factsPath == "" {
+		return fmt.Errorf("usage: cortex examples add (--content <file> | --text <content>) --facts <file.json> [--note <text>]")
+	}
+
+	content := text
+	if contentPath != "" {
+		raw, err := os.ReadFile(contentPath)
+		if err != nil {
+			return fmt.Errorf("reading content: %w", err)
+		}
+		content = string(raw)
+	}
+	if strings.TrimSpace(content) == "" {
+		return fmt.Errorf("example content is empty")
+	}
+
+	rawFacts, err := os.ReadFile(factsPath)
+	if err != nil {
+		return fmt.Errorf("reading facts: %w", err)
+	}
+	facts, err := parseExampleFacts(rawFacts)
+	if err != nil {
+		return err
+	}
+	factsJSON, _ := json.Marshal(facts)
+
+	ex := &store.ExtractionExample{Content: content, Facts: string(factsJSON), Note: note}
+	embedder, model, embedErr := resolveExampleEmbedder(embedFlag)
+	if embedErr != nil && embedFlag != "" {
+		return fmt.Errorf("configuring embedder: %w", embedErr)
+	}
+	if embedder != nil {
+		vec, err := embedder.Embed(context.Background(), content)
+		if err != nil {
+			if embedFlag != "" {
+				return fmt.Errorf("embedding example: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "  Warning: embedding failed (%v); the example will be embedded on first use\n", err)
+		} else {
+			ex.Embedding, ex.EmbedModel = vec, model
+		}
+	}
+
+	s, closeStore, err := openExamplesStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	id, err := s.AddExtractionExample(context.Background(), ex)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added example #%d (%d %s", id, len(facts), pluralize("fact", "facts", len(facts)))
+	if ex.EmbedModel != "" {
+		fmt.Printf(", embedded with %s", ex.EmbedModel)
+	}
+	fmt.Println(")")
+	return nil
+}
+
+// parseExampleFacts validates a JSON array of expected facts.
+func parseExampleFacts(raw []byte) ([]extract.ExtractedFact, error) {
+	var facts []extract.ExtractedFact
+	if err := json.Unmarshal(raw, &facts); err != nil {
+		return nil, fmt.Errorf("facts must be a JSON array of {subject, predicate, object, type}: %w", err)
+	}
+	for i, f := range facts {
+		if strings.TrimSpace(f.Predicate) == "" || strings.TrimSpace(f.Object) == "" {
+			return nil, fmt.Errorf("fact %d: predicate and object are required", i)
+		}
+		if strings.TrimSpace(f.FactType) == "" {
+			facts[i].FactType = "kv"
+		}
+	}
+	return facts, nil
+}
+
+type exampleListEntry struct {
+	ID         int64  `json:"id"`
+	Note       string `json:"note,omitempty"`
+	Content    string `json:"content"`
+	Facts      int    `json:"facts"`
+	EmbedModel string `json:"embed_model,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+func runExamplesList(args []string) error {
+	jsonOut := false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOut = true
+		default:
+			return fmt.Errorf("unknown flag: %s", arg)
+		}
+	}
+
+	s, closeStore, err := openExamplesStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	examples, err := s.ListExtractionExamples(context.Background())
+	if err != nil {
+		return err
+	}
+	entries := make([]exampleListEntry, 0, len(examples))
+	for _, ex := range examples {
+		facts, _ := parseExampleFacts([]byte(ex.Facts))
+		entries = append(entries, exampleListEntry{
+			ID:         ex.ID,
+			Note:       ex.Note,
+			Content:    ex.Content,
+			Facts:      len(facts),
+			EmbedModel: ex.EmbedModel,
+			CreatedAt:  ex.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		})
+	}
+
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No examples. Add one with: cortex examples add --content <file> --facts <file.json>")
+		return nil
+	}
+	for _, e := range entries {
+		embedded := "not embedded"
+		if e.EmbedModel != "" {
+			embedded = e.EmbedModel
+		}
+		fmt.Printf("#%-4d %d %s · %s", e.ID, e.Facts, pluralize("fact", "facts", e.Facts), embedded)
+		if e.Note != "" {
+			fmt.Printf(" · %s", e.Note)
+		}
+		fmt.Printf("\n      %s\n", truncateString(strings.Join(strings.Fields(e.Content), " "), 100))
+	}
+	return nil
+}
+
+func runExamplesRemove(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: cortex examples remove <id>")
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid example id: %s", args[0])
+	}
+
+	s, closeStore, err := openExamplesStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	if err := s.DeleteExtractionExample(context.Background(), id); err != nil {
+		return err
+	}
+	fmt.Printf("Removed example #%d\n", id)
+	return nil
+}
+
+// resolveExampleEmbedder returns the embedder used for example similarity
+// and its provider/model key. With an empty flag it auto-resolves and
+// returns (nil, "", nil) when nothing usable is available locally.
+func resolveExampleEmbedder(embedFlag string) (embed.Embedder, string, error) {
+	cfg, err := embed.ResolveEmbedConfig(embedFlag)
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg == nil {
+		if embedFlag != "" {
+			return nil, "", fmt.Errorf("no embedding configuration found")
+		}
+		return nil, "", nil
+	}
+	if embedFlag == "" && cfg.WillDownload {
+		// Don't trigger a model download just to rank a handful of examples.
+		return nil, "", nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, "", err
+	}
+	embedder, err := newEmbedClient(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	return embedder, cfg.Provider + "/" + cfg.Model, nil
+}
+
+// enrichExampleSelector picks the stored examples most similar to a chunk.
+type enrichExampleSelector struct {
+	s        *store.SQLiteStore
+	embedder embed.Embedder
+	model    string
+	examples []store.ExtractionExample
+}
+
+// newEnrichExampleSelector returns nil when no examples are stored, so
+// enrichment without examples costs nothing extra. Examples not yet
+// embedded with the current model are embedded and saved.
+func newEnrichExampleSelector(ctx context.Context, s store.Store) *enrichExampleSelector {
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return nil
+	}
+	examples, err := sqlStore.ListExtractionExamples(ctx)
+	if err != nil || len(examples) == 0 {
+		return nil
+	}
+
+	sel := &enrichExampleSelector{s: sqlStore, examples: examples}
+	embedder, model, err := resolveExampleEmbedder("")
+	if err != nil || embedder == nil {
+		return sel
+	}
+	for i, ex := range sel.examples {
+		if ex.EmbedModel == model && len(ex.Embedding) > 0 {
+			continue
+		}
+		vec, err := embedder.Embed(ctx, ex.Content)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  Examples: embedding failed (%v); using word overlap\n", err)
+			return sel
+		}
+		if err := sqlStore.SetExtractionExampleEmbedding(ctx, ex.ID, vec, model); err == nil {
+			sel.examples[i].Embedding, sel.examples[i].EmbedModel = vec, model
+		}
+	}
+	sel.embedder, sel.model = embedder, model
+	return sel
+}
+
+// pick returns up to extract.MaxEnrichExamples examples for content.
+func (sel *enrichExampleSelector) pick(ctx context.Context, content string) []extract.FewShotExample {
+	if sel == nil {
+		return nil
+	}
+	var chosen []store.ExtractionExample
+	if sel.embedder != nil {
+		if vec, err := sel.embedder.Embed(ctx, content); err == nil {
+			if scored, err := sel.s.NearestExtractionExamples(ctx, vec, sel.model, extract.MaxEnrichExamples); err == nil {
+				for _, sc := range scored {
+					chosen = append(chosen, sc.ExtractionExample)
+				}
+			}
+		}
+	}
+	if len(chosen) == 0 {
+		chosen = nearestExamplesByOverlap(sel.examples, content, extract.MaxEnrichExamples)
+	}
+
+	out := make([]extract.FewShotExample, 0, len(chosen))
+	for _, ex := range chosen {
+		facts, err := parseExampleFacts([]byte(ex.Facts))
+		if err != nil {
+			continue
+		}
+		out = append(out, extract.FewShotExample{Content: ex.Content, Facts: facts})
+	}
+	return out
+}
+
+// nearestExamplesByOverlap ranks examples by word-set Jaccard similarity,
+// the fallback when no embedder is available. Examples sharing no words
+// with content are never chosen.
+func nearestExamplesByOverlap(examples []store.ExtractionExample, content string, limit int) []store.ExtractionExample {
+	target := exampleWordSet(content)
+	type scoredExample struct {
+		ex    store.ExtractionExample
+		score float64
+	}
+	var scored []scoredExample
+	for _, ex := range examples {
+		words := exampleWordSet(ex.Content)
+		shared := 0
+		for w := range words {
+			if target[w] {
+				shared++
+			}
+		}
+		if shared == 0 {
+			continue
+		}
+		union := len(words) + len(target) - shared
+		scored = append(scored, scoredExample{ex: ex, score: float64(shared) / float64(union)})
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].score > scored[j].score })
+	if len(scored) > limit {
+		scored = scored[:limit]
+	}
+	out := make([]store.ExtractionExample, 0, len(scored))
+	for _, sc := range scored {
+		out = append(out, sc.ex)
+	}
+	return out
+}
+
+func exampleWordSet(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if utf8.RuneCountInString(w) >= 3 {
+			words[w] = true
+		}
+	}
+	return words
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/embed"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// keywordEmbedder maps text onto fixed axes so similarity is predictable.
+type keywordEmbedder struct{}
+
+func (keywordEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	lower := strings.ToLower(text)
+	vec := make([]float32, 3)
+	for i, kw := range []string{"trade", "dose", "deploy"} {
+		if strings.Contains(lower, kw) {
+			vec[i] = 1
+		}
+	}
+	return vec, nil
+}
+
+func (k keywordEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i], _ = k.Embed(ctx, t)
+	}
+	return out, nil
+}
+
+func (keywordEmbedder) Dimensions() int { return 3 }
+
+func useExamplesTestDB(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CORTEX_EMBED", "ollama/test-embed")
+	oldDBPath, oldFactory := globalDBPath, newEmbedClient
+	globalDBPath = filepath.Join(home, "cortex.db")
+	newEmbedClient = func(cfg *embed.EmbedConfig) (embed.Embedder, error) { return keywordEmbedder{}, nil }
+	t.Cleanup(func() { globalDBPath, newEmbedClient = oldDBPath, oldFactory })
+	return home
+}
+
+func writeExampleFacts(t *testing.T, dir, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, "facts.json")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunExamples_AddListRemove(t *testing.T) {
+	home := useExamplesTestDB(t)
+	facts := writeExampleFacts(t, home, `[{"subject":"desk","predicate":"trade size","object":"2 lots","type":"config"}]`)
+
+	out := captureStdout(func() {
+		if err := runExamples([]string{"add", "--text", "Trade size is 2 lots", "--facts", facts, "--note", "trading"}); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Added example #1 (1 fact, embedded with ollama/test-embed)") {
+		t.Fatalf("unexpected add output: %q", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runExamples([]string{"list"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	if !strings.Contains(out, "#1") || !strings.Contains(out, "trading") || !strings.Contains(out, "Trade size is 2 lots") {
+		t.Fatalf("unexpected list output:\n%s", out)
+	}
+
+	captureStdout(func() {
+		if err := runExamples([]string{"remove", "1"}); err != nil {
+			t.Fatalf("remove: %v", err)
+		}
+	})
+	if err := runExamples([]string{"remove", "1"}); err == nil {
+		t.Fatal("expected error removing a missing example")
+	}
+}
+
+func TestRunExamplesAdd_RejectsBadFacts(t *testing.T) {
+	home := useExamplesTestDB(t)
+	if err := runExamples([]string{"add", "--text", "x", "--facts", writeExampleFacts(t, home, `{"not":"an array"}`)}); err == nil {
+		t.Fatal("expected error for non-array facts")
+	}
+	if err := runExamples([]string{"add", "--text", "x", "--facts", writeExampleFacts(t, home, `[{"subject":"s"}]`)}); err == nil {
+		t.Fatal("expected error for fact without predicate/object")
+	}
+	if err := runExamples([]string{"add", "--facts", writeExampleFacts(t, home, `[]`)}); err == nil {
+		t.Fatal("expected usage error without content")
+	}
+}
+
+func TestEnrichExampleSelector_PicksMostSimilarAndBackfillsEmbeddings(t *testing.T) {
+	useExamplesTestDB(t)
+	s, closeStore, err := openExamplesStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+	ctx := context.Background()
+
+	// Added without embeddings; the selector embeds them on first use.
+	for _, ex := range []store.ExtractionExample{
+		{Content: "Dose is 5mg twice daily", Facts: `[{"predicate":"dose","object":"5mg"}]`},
+		{Content: "Trade entries only after 9:45", Facts: `[{"predicate":"trade window","object":"after 9:45"}]`},
+	} {
+		ex := ex
+		if _, err := s.AddExtractionExample(ctx, &ex); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sel := newEnrichExampleSelector(ctx, s)
+	if sel == nil || sel.embedder == nil {
+		t.Fatal("expected an embedding-backed selector")
+	}
+	picked := sel.pick(ctx, "New trade rule: no trades on Fridays")
+	if len(picked) == 0 || !strings.HasPrefix(picked[0].Content, "Trade entries") {
+		t.Fatalf("expected trading example first, got %+v", picked)
+	}
+	if picked[0].Facts[0].FactType != "kv" {
+		t.Errorf("missing type should default to kv, got %q", picked[0].Facts[0].FactType)
+	}
+
+	stored, _ := s.ListExtractionExamples(ctx)
+	for _, ex := range stored {
+		if ex.EmbedModel != "ollama/test-embed" {
+			t.Errorf("example %d not backfilled: %q", ex.ID, ex.EmbedModel)
+		}
+	}
+}
+
+func TestEnrichExampleSelector_NilWithoutExamples(t *testing.T) {
+	useExamplesTestDB(t)
+	s, closeStore, err := openExamplesStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	sel := newEnrichExampleSelector(context.Background(), s)
+	if sel != nil {
+		t.Fatal("expected nil selector when no examples are stored")
+	}
+	if got := sel.pick(context.Background(), "anything"); got != nil {
+		t.Fatalf("nil selector should pick nothing, got %+v", got)
+	}
+}
+
+func TestNearestExamplesByOverlap(t *testing.T) {
+	examples := []store.ExtractionExample{
+		{ID: 1, Content: "kubernetes deploy rollout strategy"},
+		{ID: 2, Content: "grocery list apples bananas"},
+		{ID: 3, Content: "deploy pipeline for kubernetes clusters"},
+	}
+	got := nearestExamplesByOverlap(examples, "Rolled out the kubernetes deploy", 5)
+	if len(got) != 2 || got[0].ID != 1 {
+		t.Fatalf("expected examples 1 then 3, got %+v", got)
+	}
+}
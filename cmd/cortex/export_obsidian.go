@@ -815,6 +815,9 @@ func runExportObsidian(args []string) error {
 	if err != nil {
 		return fmt.Errorf("listing facts: %w", err)
 	}
+	if all, err = exportableFacts(ctx, s, all); err != nil {
+		return err
+	}
 	var active []*store.Fact
 	for _, f := range all {
 		if f.State == "active" || f.State == "core" || f.State == "" {
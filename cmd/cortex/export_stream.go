@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// exportJSONLLine is one line of `cortex export --format jsonl`. The first
+// line carries Export; every later line carries exactly one record, so the
+// file can be written and read a row at a time.
+type exportJSONLLine struct {
+	Export    *exportJSONLMeta       `json:"export,omitempty"`
+	Memory    *store.Memory          `json:"memory,omitempty"`
+	Fact      *store.Fact            `json:"fact,omitempty"`
+	Edge      *store.FactEdge        `json:"edge,omitempty"`
+	Embedding *store.MemoryEmbedding `json:"embedding,omitempty"`
+}
+
+// exportJSONLMeta is exportMetaJSON without the count, which a stream
+// doesn't know when it starts.
+type exportJSONLMeta struct {
+	Kind       string            `json:"kind"`
+	Format     string            `json:"format"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Snapshot   *store.BackupInfo `json:"snapshot,omitempty"`
+}
+
+// exportJSONL writes memories (or facts) matching opts as JSON lines, one
+// page at a time, so exporting a large store doesn't hold it all in memory.
+// kind "all" writes the matching memories followed by their facts, the
+// edges between those facts, and the memories' embeddings: everything
+// `cortex import --format jsonl` needs to rebuild them elsewhere.
+func exportJSONL(ctx context.Context, s store.Store, opts store.ListOpts, kind string, output *os.File, snapshot *store.BackupInfo) error {
+	sqlStore, isSQLite := s.(*store.SQLiteStore)
+	if kind == "all" && !isSQLite {
+		return fmt.Errorf("--all requires SQLiteStore")
+	}
+	w := bufio.NewWriter(output)
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(exportJSONLLine{Export: &exportJSONLMeta{
+		Kind: kind, Format: "jsonl", ExportedAt: time.Now().UTC(), Snapshot: snapshot,
+	}}); err != nil {
+		return err
+	}
+
+	if kind == "facts" {
+		err := streamExportFacts(ctx, s, opts, func(page []*store.Fact) error {
+			for _, f := range page {
+				if err := enc.Encode(exportJSONLLine{Fact: f}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	memoryIDs := map[int64]bool{}
+	err := streamExportMemories(ctx, s, opts, func(page []*store.Memory) error {
+		for _, m := range page {
+			if err := enc.Encode(exportJSONLLine{Memory: m}); err != nil {
+				return err
+			}
+			memoryIDs[m.ID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if kind != "all" {
+		return w.Flush()
+	}
+
+	// Facts follow their memories, superseded ones included so the
+	// importer can restore the supersession.
+	factOpts := opts
+	factOpts.After, factOpts.IncludeSuperseded = "", true
+	factIDs := map[int64]bool{}
+	err = streamExportFacts(ctx, s, factOpts, func(page []*store.Fact) error {
+		for _, f := range page {
+			if !memoryIDs[f.MemoryID] {
+				continue
+			}
+			if err := enc.Encode(exportJSONLLine{Fact: f}); err != nil {
+				return err
+			}
+			factIDs[f.ID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	err = sqlStore.StreamEdges(ctx, func(page []store.FactEdge) error {
+		for i := range page {
+			if !factIDs[page[i].SourceFactID] || !factIDs[page[i].TargetFactID] {
+				continue
+			}
+			if err := enc.Encode(exportJSONLLine{Edge: &page[i]}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	err = sqlStore.StreamEmbeddings(ctx, func(page []store.MemoryEmbedding) error {
+		for i := range page {
+			if !memoryIDs[page[i].MemoryID] {
+				continue
+			}
+			if err := enc.Encode(exportJSONLLine{Embedding: &page[i]}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// streamExportMemories hands fn each page of exportable memories matching
+// opts, with anchors attached and no-export projects left out.
+func streamExportMemories(ctx context.Context, s store.Store, opts store.ListOpts, fn func([]*store.Memory) error) error {
+	sqlStore, isSQLite := s.(*store.SQLiteStore)
+	held := 0
+	each := func(page []*store.Memory) error {
+		page, n := store.FilterMemoriesByPrivacy(page, store.PrivacyNoExport)
+		held += n
+		if isSQLite {
+			if err := sqlStore.AttachMemoryAnchors(ctx, page); err != nil {
+				return err
+			}
+		}
+		return fn(page)
+	}
+
+	var err error
+	if isSQLite {
+		err = sqlStore.StreamMemories(ctx, opts, each)
+	} else {
+		opts.Limit = math.MaxInt32
+		var all []*store.Memory
+		if all, err = s.ListMemories(ctx, opts); err == nil {
+			err = each(all)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("listing memories: %w", err)
+	}
+	if held > 0 {
+		fmt.Fprintf(os.Stderr, "Left out %d memories from no-export projects\n", held)
+	}
+	return nil
+}
+
+// streamExportFacts is streamExportMemories for facts.
+func streamExportFacts(ctx context.Context, s store.Store, opts store.ListOpts, fn func([]*store.Fact) error) error {
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		opts.Limit = math.MaxInt32
+		facts, err := s.ListFacts(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("listing facts: %w", err)
+		}
+		return fn(facts)
+	}
+
+	held := 0
+	err := sqlStore.StreamFacts(ctx, opts, func(page []*store.Fact) error {
+		kept, n, err := sqlStore.FilterFactsByPrivacy(ctx, page, store.PrivacyNoExport)
+		if err != nil {
+			return fmt.Errorf("checking privacy zones: %w", err)
+		}
+		held += n
+		return fn(kept)
+	})
+	if err != nil {
+		return fmt.Errorf("listing facts: %w", err)
+	}
+	if held > 0 {
+		fmt.Fprintf(os.Stderr, "Left out %d facts from no-export projects\n", held)
+	}
+	return nil
+}
+
+// parseExportSince reads --since as a date or timestamp, or as an age (7d,
+// 2w, 12h) counted back from now.
+func parseExportSince(raw string) (time.Time, error) {
+	if t, ok := parseTimeForWhere(raw); ok {
+		return t, nil
+	}
+	if d, err := parseSinceDuration(raw); err == nil {
+		return time.Now().UTC().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since value: %s (use YYYY-MM-DD, RFC3339, or an age like 7d)", raw)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunExport_JSONLStreamsFilteredRows(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	work, _ := s.AddMemory(ctx, &store.Memory{Content: "ship on friday", SourceFile: "work.md", Project: "work", MemoryClass: "decision"})
+	s.AddMemory(ctx, &store.Memory{Content: "buy oat milk", SourceFile: "home.md"})
+	s.AddFact(ctx, &store.Fact{MemoryID: work, Subject: "ship", Predicate: "day", Object: "friday", FactType: "kv"})
+	s.Close()
+
+	readLines := func(args ...string) []exportJSONLLine {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "out.jsonl")
+		if err := runExport(append(args, "--format", "jsonl", "--output", path)); err != nil {
+			t.Fatalf("export %v: %v", args, err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		var lines []exportJSONLLine
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			var line exportJSONLLine
+			if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+				t.Fatalf("invalid line %q: %v", sc.Text(), err)
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	}
+
+	lines := readLines()
+	if len(lines) != 3 || lines[0].Export == nil || lines[0].Export.Kind != "memories" || lines[0].Export.Snapshot == nil {
+		t.Fatalf("memories export = %+v", lines)
+	}
+	for _, l := range lines[1:] {
+		if l.Memory == nil || l.Fact != nil || l.Export != nil {
+			t.Fatalf("record line should hold one memory: %+v", l)
+		}
+	}
+
+	lines = readLines("--project", "work", "--class", "decision", "--since", "1d")
+	if len(lines) != 2 || lines[1].Memory.Content != "ship on friday" {
+		t.Fatalf("filtered export = %+v", lines)
+	}
+
+	lines = readLines("--facts", "--project", "work")
+	if len(lines) != 2 || lines[0].Export.Kind != "facts" || lines[1].Fact == nil || lines[1].Fact.Object != "friday" {
+		t.Fatalf("facts export = %+v", lines)
+	}
+
+	if lines = readLines("--since", "2999-01-01"); len(lines) != 1 {
+		t.Fatalf("future --since should export only the header, got %+v", lines)
+	}
+	for _, args := range [][]string{{"--since", "soon"}, {"--class", "bogus"}} {
+		if err := runExport(args); err == nil {
+			t.Errorf("runExport(%v): expected error", args)
+		}
+	}
+}
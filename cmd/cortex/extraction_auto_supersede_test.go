@@ -20,7 +20,7 @@ func importAndExtractFacts(t *testing.T, ctx context.Context, engine *ingest.Eng
 		t.Fatalf("ImportFile(%s): %v", filePath, err)
 	}
 
-	extractionStats, err := runExtractionOnImportedMemories(ctx, s, "", importResult.NewMemoryIDs, nil)
+	extractionStats, err := runExtractionOnImportedMemories(ctx, s, "", importResult.NewMemoryIDs, true, nil)
 	if err != nil {
 		t.Fatalf("runExtractionOnImportedMemories(%s): %v", filePath, err)
 	}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunExtractionOnImportedMemories_SkipsZeroYieldContent(t *testing.T) {
+	ctx := context.Background()
+	s, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer s.Close()
+
+	id, err := s.AddMemory(ctx, &store.Memory{Content: "lorem ipsum dolor sit amet", SourceFile: "noise.log"})
+	if err != nil {
+		t.Fatalf("AddMemory: %v", err)
+	}
+
+	for i := 0; i < store.ZeroYieldSkipAfter; i++ {
+		stats, err := runExtractionOnImportedMemories(ctx, s, "", []int64{id}, true, nil)
+		if err != nil {
+			t.Fatalf("run %d: %v", i+1, err)
+		}
+		if stats.FactsExtracted != 0 || stats.Skipped != 0 {
+			t.Fatalf("run %d stats = %+v, want an empty, unskipped run", i+1, stats)
+		}
+	}
+
+	stats, err := runExtractionOnImportedMemories(ctx, s, "", []int64{id}, true, nil)
+	if err != nil || stats.Skipped != 1 {
+		t.Fatalf("stats = %+v, %v; want the memory skipped", stats, err)
+	}
+	stats, err = runExtractionOnImportedMemories(ctx, s, "", []int64{id}, false, nil)
+	if err != nil || stats.Skipped != 0 {
+		t.Fatalf("without the skip list stats = %+v, %v", stats, err)
+	}
+}
+
+func TestRunExtractionOnImportedMemories_RulesSkipListSparesLLM(t *testing.T) {
+	ctx := context.Background()
+	s, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer s.Close()
+
+	content := "lorem ipsum dolor sit amet"
+	id, err := s.AddMemory(ctx, &store.Memory{Content: content, SourceFile: "noise.log"})
+	if err != nil {
+		t.Fatalf("AddMemory: %v", err)
+	}
+	for i := 0; i < store.ZeroYieldSkipAfter; i++ {
+		if _, err := runExtractionOnImportedMemories(ctx, s, "", []int64{id}, true, nil); err != nil {
+			t.Fatalf("rules-only run %d: %v", i+1, err)
+		}
+	}
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		reply := `{"facts":[{"subject":"lorem","predicate":"precedes","object":"ipsum","type":"kv","confidence":0.9,"source_quote":"lorem ipsum"}]}`
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": reply}}},
+		})
+	}))
+	defer srv.Close()
+	t.Setenv("CORTEX_LLM_ENDPOINT", srv.URL)
+
+	stats, err := runExtractionOnImportedMemories(ctx, s, "ollama/test-model", []int64{id}, true, nil)
+	if err != nil {
+		t.Fatalf("LLM run: %v", err)
+	}
+	if stats.Skipped != 0 || calls != 1 {
+		t.Fatalf("LLM run stats = %+v after %d model calls; empty rules-only runs must not skip it", stats, calls)
+	}
+	if stats, _ := runExtractionOnImportedMemories(ctx, s, "", []int64{id}, true, nil); stats.Skipped != 1 {
+		t.Fatalf("rules-only run stats = %+v, want the memory still skipped", stats)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// runFactSources handles `cortex fact sources <fact_id>`: every memory that
+// contributed to a fact, including the originals behind summaries and merges.
+func runFactSources(args []string) error {
+	var factID int64
+	jsonOutput := false
+	for _, arg := range args {
+		switch {
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case factID == 0:
+			id, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil || id <= 0 {
+				return fmt.Errorf("invalid fact id %q", arg)
+			}
+			factID = id
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if factID == 0 {
+		return fmt.Errorf("usage: cortex fact sources <fact_id> [--json]")
+	}
+
+	s, closeStore, err := openFactSourcesStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	ctx := context.Background()
+	fact, err := s.GetFact(ctx, factID)
+	if err != nil {
+		return err
+	}
+	if fact == nil {
+		return fmt.Errorf("fact %d not found", factID)
+	}
+	sources, err := s.ListFactSources(ctx, factID)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput || !isTTY() {
+		if sources == nil {
+			sources = []store.FactSource{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{"fact_id": factID, "sources": sources})
+	}
+	fmt.Printf("Fact #%d: %s %s %s\n", fact.ID, fact.Subject, fact.Predicate, fact.Object)
+	printFactSources(sources)
+	return nil
+}
+
+func printFactSources(sources []store.FactSource) {
+	if len(sources) == 0 {
+		fmt.Println("  (no recorded sources; run `cortex fact repair-sources`)")
+		return
+	}
+	for _, src := range sources {
+		marks := ""
+		if src.Primary {
+			marks += " ★"
+		}
+		if src.Deleted {
+			marks += " (deleted)"
+		}
+		fmt.Printf("  memory #%d  %-12s  %s%s\n", src.MemoryID, src.Role, src.SourceFile, marks)
+	}
+}
+
+// runFactRepairSources handles `cortex fact repair-sources`.
+func runFactRepairSources(args []string) error {
+	dryRun, jsonOutput := false, false
+	for _, arg := range args {
+		switch {
+		case arg == "--dry-run" || arg == "-n":
+			dryRun = true
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	s, closeStore, err := openFactSourcesStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	report, err := s.RepairFactSources(context.Background(), dryRun)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	verb := "Repaired"
+	if dryRun {
+		verb = "Would repair (dry run)"
+	}
+	fmt.Printf("%s fact backlinks:\n", verb)
+	fmt.Printf("  source links added:   %d\n", report.LinksAdded)
+	fmt.Printf("  facts repointed:      %d\n", report.Repointed)
+	fmt.Printf("  dangling consolidated facts: %d\n", report.Dangling)
+	if report.Dangling > 0 {
+		fmt.Println("  (every memory behind these summary/merge facts is deleted; review with `cortex fact sources <id>`)")
+	}
+	return nil
+}
+
+func openFactSourcesStore() (*store.SQLiteStore, func(), error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening store: %w", err)
+	}
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, nil, fmt.Errorf("fact sources require SQLiteStore")
+	}
+	return sqlStore, func() { s.Close() }, nil
+}
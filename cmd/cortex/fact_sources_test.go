@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunFactSourcesAndRepair(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlStore := s.(*store.SQLiteStore)
+	m1, _ := s.AddMemory(ctx, &store.Memory{Content: "deploys use fly.io", SourceFile: "ops.md"})
+	m2, _ := s.AddMemory(ctx, &store.Memory{Content: "deploy region is iad", SourceFile: "infra.md"})
+	a, _ := s.AddFact(ctx, &store.Fact{MemoryID: m1, Subject: "deploy", Predicate: "uses", Object: "fly.io", FactType: "kv"})
+	b, _ := s.AddFact(ctx, &store.Fact{MemoryID: m2, Subject: "deploy", Predicate: "region", Object: "iad", FactType: "kv"})
+	summaryID, err := sqlStore.ApplyClusterSummary(ctx, 1, &store.Fact{MemoryID: m1, Subject: "deploy", Predicate: "setup", Object: "fly.io in iad", FactType: "kv"}, []int64{a, b}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	var runErr error
+	out := captureStdout(func() { runErr = runFactCommand([]string{"sources", strconv.FormatInt(summaryID, 10), "--json"}) })
+	if runErr != nil {
+		t.Fatalf("fact sources: %v", runErr)
+	}
+	var got struct {
+		FactID  int64              `json:"fact_id"`
+		Sources []store.FactSource `json:"sources"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if got.FactID != summaryID || len(got.Sources) != 2 || !got.Sources[0].Primary || got.Sources[0].MemoryID != m1 {
+		t.Fatalf("sources = %+v", got)
+	}
+
+	out = captureStdout(func() { runErr = runFactCommand([]string{"repair-sources", "--dry-run", "--json"}) })
+	if runErr != nil {
+		t.Fatalf("repair-sources: %v", runErr)
+	}
+	var report store.FactSourceRepair
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if !report.DryRun || report.LinksAdded != 0 || report.Repointed != 0 {
+		t.Fatalf("a consistent store needs no repair: %+v", report)
+	}
+
+	if err := runFactCommand([]string{"sources"}); err == nil {
+		t.Fatal("expected usage error without a fact id")
+	}
+	if err := runFactCommand([]string{"sources", "99999"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if err := runFactCommand([]string{"repair-sources", "--bogus"}); err == nil || !strings.Contains(err.Error(), "unknown flag") {
+		t.Fatalf("expected unknown flag, got %v", err)
+	}
+}
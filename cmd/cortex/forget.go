@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const defaultForgetSimilarity = 0.8
+
+func forgetUsageText() string {
+	return `Usage: cortex forget --subject <name> [flags]
+
+Erase a person or other subject from the store (GDPR-style erasure). Finds
+every memory and fact mentioning the subject — by exact, case-insensitive
+text and by embedding similarity — reports them, and after confirmation
+deletes them with their embeddings, FTS entries, graph edges, events, and
+alert/webhook history. Records under archive hold are reported and kept.
+
+Flags:
+  --subject <name>        Subject to forget (required)
+  --dry-run               Report matches without changing anything
+  --redact                Replace the subject with [REDACTED] instead of
+                          deleting; semantic-only matches are left alone
+  --exact                 Skip the embedding-similarity search
+  --similarity <0-1>      Minimum similarity for semantic matches (default 0.8)
+  --embed <provider/model>  Embedder for the similarity search (default: auto)
+  --yes, -y               Don't ask for confirmation
+  --json                  Print the plan (and result) as JSON
+
+Deleted rows can linger in free database pages and in backups; run
+'cortex optimize --vacuum-only' afterwards and rotate old backups.`
+}
+
+func runForget(args []string) error {
+	var subject, embedFlag string
+	dryRun, redact, exact, yes, jsonOutput := false, false, false, false, false
+	similarity := defaultForgetSimilarity
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--help" || arg == "-h":
+			fmt.Println(forgetUsageText())
+			return nil
+		case arg == "--subject" && i+1 < len(args):
+			i++
+			subject = args[i]
+		case strings.HasPrefix(arg, "--subject="):
+			subject = strings.TrimPrefix(arg, "--subject=")
+		case arg == "--similarity" && i+1 < len(args):
+			i++
+			v, err := strconv.ParseFloat(args[i], 64)
+			if err != nil || v <= 0 || v > 1 {
+				return fmt.Errorf("invalid --similarity: %s (want 0-1)", args[i])
+			}
+			similarity = v
+		case strings.HasPrefix(arg, "--similarity="):
+			v, err := strconv.ParseFloat(strings.TrimPrefix(arg, "--similarity="), 64)
+			if err != nil || v <= 0 || v > 1 {
+				return fmt.Errorf("invalid --similarity: %s (want 0-1)", arg)
+			}
+			similarity = v
+		case arg == "--embed" && i+1 < len(args):
+			i++
+			embedFlag = args[i]
+		case strings.HasPrefix(arg, "--embed="):
+			embedFlag = strings.TrimPrefix(arg, "--embed=")
+		case arg == "--dry-run":
+			dryRun = true
+		case arg == "--redact":
+			redact = true
+		case arg == "--exact":
+			exact = true
+		case arg == "--yes" || arg == "-y":
+			yes = true
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return fmt.Errorf("usage: cortex forget --subject <name> [--dry-run] [--redact] [--exact] [--yes]")
+	}
+	if len([]rune(subject)) < 3 {
+		return fmt.Errorf("subject %q is too short to match safely", subject)
+	}
+	if exact && embedFlag != "" {
+		return fmt.Errorf("--exact cannot be combined with --embed")
+	}
+	if jsonOutput && !dryRun && !yes {
+		return fmt.Errorf("--json needs --dry-run or --yes (no interactive confirmation)")
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	ss, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("forget requires SQLiteStore")
+	}
+	ctx := context.Background()
+
+	opts := store.ForgetOptions{Subject: subject, MinSimilarity: similarity}
+	if !exact {
+		embedder, model, err := resolveExampleEmbedder(embedFlag)
+		if err != nil {
+			return err
+		}
+		if embedder == nil {
+			fmt.Fprintln(os.Stderr, "No embedder configured; matching exact text only.")
+		} else {
+			vec, err := embedder.Embed(ctx, subject)
+			if err != nil {
+				return fmt.Errorf("embedding subject with %s: %w", model, err)
+			}
+			opts.Vector = vec
+		}
+	}
+
+	plan, err := ss.PlanForget(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if jsonOutput && dryRun {
+		return printForgetJSON(plan, nil)
+	}
+	if !jsonOutput {
+		printForgetPlan(plan)
+	}
+	if len(plan.Memories) == 0 && len(plan.Facts) == 0 {
+		return nil
+	}
+	if dryRun {
+		if !jsonOutput {
+			fmt.Println("\nDry run — nothing changed.")
+		}
+		return nil
+	}
+
+	if !yes {
+		action := "Delete"
+		if redact {
+			action = "Redact"
+		}
+		fmt.Printf("\n%s these records? This cannot be undone. [y/N] ", action)
+		var answer string
+		fmt.Scanln(&answer)
+		answer = strings.TrimSpace(strings.ToLower(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	res, err := ss.ApplyForget(ctx, plan, redact)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printForgetJSON(plan, res)
+	}
+	verb := "Deleted"
+	if redact {
+		verb = "Redacted"
+	}
+	fmt.Printf("\n✅ %s %d memories and %d facts mentioning %q\n", verb, res.Memories, res.Facts, plan.Subject)
+	fmt.Printf("   Removed %d embeddings, %d events, %d alerts\n", res.Embeddings, res.Events, res.Alerts)
+	fmt.Printf("   %s %d reviews, %d triage excerpts, %d annotations; removed %d briefs, %d cached reason results\n",
+		verb, res.Reviews, res.Triage, res.Annotations, res.Briefs, res.ReasonCache)
+	if res.Skipped > 0 {
+		fmt.Printf("   Skipped %d (archive hold or semantic-only)\n", res.Skipped)
+	}
+	fmt.Println("   Run 'cortex optimize --vacuum-only' to purge freed pages from disk.")
+	return nil
+}
+
+func printForgetPlan(plan *store.ForgetPlan) {
+	if len(plan.Memories) == 0 && len(plan.Facts) == 0 {
+		fmt.Printf("Nothing mentions %q.\n", plan.Subject)
+		return
+	}
+	fmt.Printf("Records mentioning %q:\n", plan.Subject)
+	if len(plan.Memories) > 0 {
+		fmt.Printf("\nMemories (%d):\n", len(plan.Memories))
+		for _, m := range plan.Memories {
+			fmt.Printf("  #%-6d %s  %s\n", m.ID, forgetMatchLabel(m), m.Text)
+		}
+	}
+	if len(plan.Facts) > 0 {
+		fmt.Printf("\nFacts (%d):\n", len(plan.Facts))
+		for _, f := range plan.Facts {
+			fmt.Printf("  #%-6d %s  %s\n", f.ID, forgetMatchLabel(f), f.Text)
+		}
+	}
+}
+
+func forgetMatchLabel(m store.ForgetMatch) string {
+	label := "exact   "
+	if m.Match == "semantic" {
+		label = fmt.Sprintf("sim %.2f", m.Similarity)
+	}
+	if m.Held {
+		label += " 🔒held"
+	}
+	return "[" + label + "]"
+}
+
+func printForgetJSON(plan *store.ForgetPlan, res *store.ForgetResult) error {
+	if plan.Memories == nil {
+		plan.Memories = []store.ForgetMatch{}
+	}
+	if plan.Facts == nil {
+		plan.Facts = []store.ForgetMatch{}
+	}
+	out := struct {
+		*store.ForgetPlan
+		Result *store.ForgetResult `json:"result,omitempty"`
+	}{plan, res}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunForget_DryRunThenDelete(t *testing.T) {
+	useExamplesTestDB(t)
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	ss := s.(*store.SQLiteStore)
+	named, _ := s.AddMemory(ctx, &store.Memory{Content: "Dana Deploy owns the release train", SourceFile: "team.md", ContentHash: "a"})
+	similar, _ := s.AddMemory(ctx, &store.Memory{Content: "Release train runs every Tuesday deploy window", SourceFile: "team.md", ContentHash: "b"})
+	other, _ := s.AddMemory(ctx, &store.Memory{Content: "Lunch menu for Friday", SourceFile: "misc.md", ContentHash: "c"})
+	ss.AddEmbedding(ctx, named, []float32{0, 0, 1})
+	ss.AddEmbedding(ctx, similar, []float32{0, 0, 1})
+	ss.AddEmbedding(ctx, other, []float32{1, 0, 0})
+	s.AddFact(ctx, &store.Fact{MemoryID: named, Subject: "Dana Deploy", Predicate: "owns", Object: "release train", FactType: "kv", Confidence: 0.9})
+	s.Close()
+
+	for _, args := range [][]string{
+		{},
+		{"--subject", "ab"},
+		{"--subject", "Dana", "--similarity", "2"},
+		{"--subject", "Dana", "--exact", "--embed", "ollama/x"},
+		{"--subject", "Dana", "--json"},
+		{"--subject", "Dana", "extra"},
+	} {
+		if err := runForget(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+
+	out := captureStdout(func() {
+		if err := runForget([]string{"--subject", "dana deploy", "--dry-run", "--json"}); err != nil {
+			t.Fatalf("dry run: %v", err)
+		}
+	})
+	var plan store.ForgetPlan
+	if err := json.Unmarshal([]byte(out), &plan); err != nil {
+		t.Fatalf("parsing plan: %v\n%s", err, out)
+	}
+	if len(plan.Memories) != 2 || plan.Memories[1].Match != "semantic" || len(plan.Facts) != 1 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+
+	out = captureStdout(func() {
+		if err := runForget([]string{"--subject", "dana deploy", "--exact", "--yes"}); err != nil {
+			t.Fatalf("forget: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Deleted 1 memories and 1 facts") || !strings.Contains(out, "1 embeddings") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	s, _ = store.NewStore(getStoreConfig())
+	defer s.Close()
+	if m, _ := s.GetMemory(ctx, named); m != nil {
+		t.Fatal("memory naming the subject should be gone")
+	}
+	if m, _ := s.GetMemory(ctx, similar); m == nil {
+		t.Fatal("--exact should leave semantic matches alone")
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/graph"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const graphMetricsUsage = "usage: cortex graph metrics [--min-confidence 0.5] [--top 10] [--sample 1000] [--json]"
+
+// runGraphMetrics reports the facts the rest of the graph depends on:
+// centrality hubs, bridge edges, and connected components.
+func runGraphMetrics(args []string) error {
+	var opts graph.MetricsOptions
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--min-confidence" || arg == "--top" || arg == "--sample":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", arg)
+			}
+			i++
+			if arg == "--min-confidence" {
+				c, err := strconv.ParseFloat(args[i], 64)
+				if err != nil || c < 0 || c > 1 {
+					return fmt.Errorf("invalid --min-confidence: %s", args[i])
+				}
+				opts.MinConfidence = c
+				continue
+			}
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid %s value: %s", arg, args[i])
+			}
+			if arg == "--top" {
+				opts.Top = n
+			} else {
+				opts.Pivots = n
+			}
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s\n%s", arg, graphMetricsUsage)
+		}
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("graph metrics require SQLiteStore")
+	}
+
+	m, err := graph.ComputeMetrics(context.Background(), sqlStore, opts)
+	if err != nil {
+		return err
+	}
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	}
+
+	if m.Edges == 0 {
+		fmt.Println("No edges yet. Add some with cortex edge add, edge import, or infer.")
+		return nil
+	}
+	fmt.Printf("Graph: %d facts, %d edges, %d %s, %d %s\n", m.Facts, m.Edges,
+		m.ComponentCount, pluralize("component", "components", m.ComponentCount),
+		m.BridgeCount, pluralize("bridge", "bridges", m.BridgeCount))
+	if m.BetweennessSampled {
+		fmt.Printf("Betweenness estimated from %d of %d facts (--sample to change).\n", m.BetweennessSources, m.Facts)
+	}
+
+	fmt.Printf("\nHubs (what the most paths run through):\n")
+	for i, h := range m.Hubs {
+		fmt.Printf("  %2d. #%-6d %-48s between %.3f  degree %d\n", i+1, h.FactID,
+			truncateString(strings.Join([]string{h.Subject, h.Predicate, h.Object}, " "), 48), h.Betweenness, h.Degree)
+	}
+
+	if len(m.Bridges) > 0 {
+		fmt.Printf("\nBridges (single edges holding parts of the graph together):\n")
+		for _, b := range m.Bridges {
+			fmt.Printf("  #%-6d fact %d -[%s]- fact %d  cuts off %d %s\n", b.EdgeID, b.SourceFactID, b.EdgeType,
+				b.TargetFactID, b.Cuts, pluralize("fact", "facts", b.Cuts))
+		}
+	}
+
+	fmt.Printf("\nLargest components:\n")
+	for _, c := range m.Components {
+		fmt.Printf("  %d facts, %d edges (hub #%d)\n", c.Facts, c.Edges, c.Hub)
+	}
+
+	if len(m.Clusters) > 0 {
+		fmt.Printf("\nHubs by cluster:\n")
+		for _, c := range m.Clusters {
+			ids := make([]string, len(c.Hubs))
+			for i, h := range c.Hubs {
+				ids[i] = fmt.Sprintf("#%d %s", h.FactID, truncateString(h.Subject, 24))
+			}
+			fmt.Printf("  %-24s %s\n", truncateString(c.Name, 24), strings.Join(ids, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/graph"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunGraphMetrics(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlStore := s.(*store.SQLiteStore)
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "deploy notes", SourceFile: "notes.md"})
+	hub, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "tool", Object: "argo", FactType: "kv"})
+	for _, obj := range []string{"friday", "ops", "us-east"} {
+		leaf, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "detail", Object: obj, FactType: "kv"})
+		if err := sqlStore.AddEdge(ctx, &store.FactEdge{SourceFactID: hub, TargetFactID: leaf, EdgeType: store.EdgeTypeRelatesTo}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	var runErr error
+	out := captureStdout(func() { runErr = runGraph([]string{"metrics", "--json", "--top", "2"}) })
+	if runErr != nil {
+		t.Fatalf("graph metrics: %v", runErr)
+	}
+	var m graph.Metrics
+	if err := json.Unmarshal([]byte(out), &m); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if m.Facts != 4 || m.Edges != 3 || len(m.Hubs) != 2 || m.Hubs[0].FactID != hub || m.BridgeCount != 3 {
+		t.Fatalf("metrics = %+v", m)
+	}
+
+	for _, args := range [][]string{{"metrics", "--top", "0"}, {"metrics", "--min-confidence", "2"}, {"metrics", "extra"}} {
+		if err := runGraph(args); err == nil || strings.Contains(err.Error(), "opening store") {
+			t.Errorf("runGraph(%v) = %v, want a flag error", args, err)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const importJSONLUsage = "usage: cortex import <export.jsonl|-> --format jsonl [--dry-run] [--id-map <file.csv>] [--json]"
+
+// splitImportFormat pulls --format out of import args. found is false when
+// no --format was given and the args are a normal file import.
+func splitImportFormat(args []string) (format string, rest []string, found bool, err error) {
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--format":
+			if i+1 >= len(args) {
+				return "", nil, false, fmt.Errorf("--format needs a value")
+			}
+			format, found = args[i+1], true
+			i++
+		case strings.HasPrefix(args[i], "--format="):
+			format, found = strings.TrimPrefix(args[i], "--format="), true
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return format, rest, found, nil
+}
+
+// runImportJSONL restores a `cortex export --format jsonl` file into the
+// store: memories, facts, edges, and embeddings, deduplicated against what
+// is already here, with every cross-reference remapped to the new IDs.
+func runImportJSONL(args []string) error {
+	path := ""
+	idMapPath := ""
+	dryRun := false
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--dry-run" || arg == "-n":
+			dryRun = true
+		case arg == "--json":
+			jsonOutput = true
+		case arg == "--id-map":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", arg)
+			}
+			i++
+			idMapPath = args[i]
+		case strings.HasPrefix(arg, "--id-map="):
+			idMapPath = strings.TrimPrefix(arg, "--id-map=")
+		case arg != "-" && strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case path == "":
+			path = arg
+		default:
+			return fmt.Errorf("unexpected argument: %s\n%s", arg, importJSONLUsage)
+		}
+	}
+	if path == "" {
+		return fmt.Errorf(importJSONLUsage)
+	}
+	if dryRun && idMapPath != "" {
+		return fmt.Errorf("--id-map can't be used with --dry-run")
+	}
+
+	in := os.Stdin
+	if path != "-" {
+		f, err := os.Open(expandUserPath(path))
+		if err != nil {
+			return fmt.Errorf("opening export: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("jsonl import requires SQLiteStore")
+	}
+	defer flushWebhook(s)
+
+	ctx := context.Background()
+	im, err := sqlStore.NewRecordImporter(ctx, dryRun)
+	if err != nil {
+		return err
+	}
+	kind, err := importJSONLRecords(ctx, bufio.NewReader(in), im)
+	if err != nil {
+		return err
+	}
+	res, err := im.Finish(ctx)
+	if err != nil {
+		return err
+	}
+	if idMapPath != "" {
+		if err := writeImportIDMap(idMapPath, im); err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	verb := "Imported"
+	if dryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %s export:\n", verb, kind)
+	fmt.Printf("  memories:   %d new, %d already here\n", res.MemoriesAdded, res.MemoriesDuplicate)
+	if kind == "all" {
+		fmt.Printf("  facts:      %d new, %d already here", res.FactsAdded, res.FactsDuplicate)
+		if res.FactsOrphaned > 0 {
+			fmt.Printf(", %d without a memory", res.FactsOrphaned)
+		}
+		fmt.Println()
+		fmt.Printf("  edges:      %d new, %d already here, %d skipped\n", res.EdgesAdded, res.EdgesDuplicate, res.EdgesSkipped)
+		fmt.Printf("  embeddings: %d new, %d skipped\n", res.EmbeddingsAdded, res.EmbeddingsSkipped)
+		if res.Supersessions > 0 {
+			fmt.Printf("  restored %d %s\n", res.Supersessions, pluralize("supersession", "supersessions", res.Supersessions))
+		}
+	}
+	if !dryRun && res.MemoriesAdded > res.EmbeddingsAdded {
+		fmt.Println("Run cortex embed to embed imported memories that came without vectors.")
+	}
+	return nil
+}
+
+// importJSONLRecords feeds each line of an export to im and returns the
+// export's kind. Facts-only exports are refused: their facts have no
+// memories to attach to.
+func importJSONLRecords(ctx context.Context, r io.Reader, im *store.RecordImporter) (string, error) {
+	dec := json.NewDecoder(r)
+	var header exportJSONLLine
+	if err := dec.Decode(&header); err != nil || header.Export == nil || header.Export.Format != "jsonl" {
+		return "", fmt.Errorf("not a cortex jsonl export: the first line must be its export header")
+	}
+	kind := header.Export.Kind
+	switch kind {
+	case "memories", "all":
+	case "facts":
+		return "", fmt.Errorf("a facts-only export can't be imported; re-export with --all")
+	default:
+		return "", fmt.Errorf("unknown export kind %q", kind)
+	}
+
+	for n := 2; ; n++ {
+		var line exportJSONLLine
+		err := dec.Decode(&line)
+		if errors.Is(err, io.EOF) {
+			return kind, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("line %d: %w", n, err)
+		}
+		switch {
+		case line.Memory != nil:
+			err = im.ImportMemory(ctx, line.Memory)
+		case line.Fact != nil:
+			err = im.ImportFact(ctx, line.Fact)
+		case line.Edge != nil:
+			err = im.ImportEdge(ctx, *line.Edge)
+		case line.Embedding != nil:
+			err = im.ImportEmbedding(ctx, *line.Embedding)
+		default:
+			err = fmt.Errorf("no record")
+		}
+		if err != nil {
+			return "", fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+}
+
+// writeImportIDMap writes the importer's remapping table as CSV rows of
+// kind,old_id,new_id.
+func writeImportIDMap(path string, im *store.RecordImporter) error {
+	f, err := os.Create(expandUserPath(path))
+	if err != nil {
+		return fmt.Errorf("creating id map: %w", err)
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	w.Write([]string{"kind", "old_id", "new_id"})
+	memories, facts := im.IDMap()
+	for _, table := range []struct {
+		kind string
+		ids  map[int64]int64
+	}{{"memory", memories}, {"fact", facts}} {
+		old := make([]int64, 0, len(table.ids))
+		for id := range table.ids {
+			old = append(old, id)
+		}
+		sort.Slice(old, func(i, j int) bool { return old[i] < old[j] })
+		for _, id := range old {
+			w.Write([]string{table.kind, strconv.FormatInt(id, 10), strconv.FormatInt(table.ids[id], 10)})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("writing id map: %w", err)
+	}
+	return f.Close()
+}
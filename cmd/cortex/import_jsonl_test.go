@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunImportJSONL_RoundTripsExportAll(t *testing.T) {
+	useWorkspaceHome(t)
+	dir := t.TempDir()
+	globalDBPath = filepath.Join(dir, "src.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlStore := s.(*store.SQLiteStore)
+	mem, _ := s.AddMemory(ctx, &store.Memory{Content: "ship on friday with argo", SourceFile: "work.md"})
+	day, _ := s.AddFact(ctx, &store.Fact{MemoryID: mem, Subject: "ship", Predicate: "day", Object: "friday", FactType: "kv"})
+	tool, _ := s.AddFact(ctx, &store.Fact{MemoryID: mem, Subject: "ship", Predicate: "tool", Object: "argo", FactType: "kv"})
+	sqlStore.AddEdge(ctx, &store.FactEdge{SourceFactID: tool, TargetFactID: day, EdgeType: store.EdgeTypeSupports})
+	sqlStore.AddEmbedding(ctx, mem, []float32{0.5, 0.5})
+	s.Close()
+
+	export := filepath.Join(dir, "all.jsonl")
+	if err := runExport([]string{"--format", "jsonl", "--all", "--output", export}); err != nil {
+		t.Fatalf("export --all: %v", err)
+	}
+	facts := filepath.Join(dir, "facts.jsonl")
+	if err := runExport([]string{"--format", "jsonl", "--facts", "--output", facts}); err != nil {
+		t.Fatal(err)
+	}
+
+	globalDBPath = filepath.Join(dir, "dst.db")
+	importJSON := func(args ...string) store.RecordImportResult {
+		t.Helper()
+		var runErr error
+		out := captureStdout(func() { runErr = runImport(append(args, "--format", "jsonl", "--json")) })
+		if runErr != nil {
+			t.Fatalf("import %v: %v", args, runErr)
+		}
+		var res store.RecordImportResult
+		if err := json.Unmarshal([]byte(out), &res); err != nil {
+			t.Fatalf("invalid JSON %q: %v", out, err)
+		}
+		return res
+	}
+
+	if res := importJSON(export, "--dry-run"); res.MemoriesAdded != 1 || res.FactsAdded != 2 || res.EdgesAdded != 1 || res.EmbeddingsAdded != 1 {
+		t.Fatalf("dry run = %+v", res)
+	}
+	idMap := filepath.Join(dir, "ids.csv")
+	if res := importJSON(export, "--id-map", idMap); res.DryRun || res.MemoriesAdded != 1 || res.FactsAdded != 2 || res.EdgesAdded != 1 || res.EmbeddingsAdded != 1 {
+		t.Fatalf("import = %+v", res)
+	}
+	if res := importJSON(export); res.MemoriesDuplicate != 1 || res.FactsDuplicate != 2 || res.EdgesDuplicate != 1 || res.MemoriesAdded+res.FactsAdded+res.EdgesAdded != 0 {
+		t.Fatalf("re-import = %+v", res)
+	}
+	ids, err := os.ReadFile(idMap)
+	if err != nil || !strings.HasPrefix(string(ids), "kind,old_id,new_id\nmemory,") || strings.Count(string(ids), "\nfact,") != 2 {
+		t.Fatalf("id map = %q, %v", ids, err)
+	}
+
+	for _, args := range [][]string{
+		{facts, "--format", "jsonl"},
+		{export, "--format", "csv"},
+		{export, "--format", "jsonl", "--dry-run", "--id-map", idMap},
+		{filepath.Join(dir, "missing.jsonl"), "--format", "jsonl"},
+	} {
+		if err := runImport(args); err == nil {
+			t.Errorf("runImport(%v): expected error", args)
+		}
+	}
+	if err := runExport([]string{"--format", "json", "--all"}); err == nil {
+		t.Error("--all without --format jsonl should fail")
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/ann"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const defaultQuantizeSample = 2000
+
+type indexQuantizeReport struct {
+	From            string                 `json:"from"`
+	To              string                 `json:"to"`
+	Applied         bool                   `json:"applied"`
+	StoreVectors    int                    `json:"store_vectors"`
+	StoredBytes     int64                  `json:"stored_bytes"`
+	Impact          ann.QuantizationImpact `json:"impact"`
+	Reencoded       int64                  `json:"reencoded,omitempty"`
+	IndexVectors    int                    `json:"index_vectors,omitempty"`
+	StoredBytesNext int64                  `json:"stored_bytes_after,omitempty"`
+}
+
+// runIndexQuantize switches a store between float32 and int8 embeddings.
+// It always measures the recall impact on a sample first; without --report it
+// then re-encodes every stored vector and rebuilds the HNSW index.
+func runIndexQuantize(args []string) error {
+	var (
+		mode       string
+		reportOnly bool
+		jsonOut    bool
+		sample     = defaultQuantizeSample
+	)
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--report" || args[i] == "--dry-run":
+			reportOnly = true
+		case args[i] == "--json":
+			jsonOut = true
+		case args[i] == "--sample" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid --sample: %s", args[i])
+			}
+			sample = n
+		case args[i] == "--help" || args[i] == "-h":
+			fmt.Println(indexUsageText())
+			return nil
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		default:
+			if mode != "" {
+				return fmt.Errorf("unexpected argument: %s", args[i])
+			}
+			mode = args[i]
+		}
+	}
+	if mode == "" {
+		return fmt.Errorf("usage: cortex index quantize <none|int8> [--report] [--sample N] [--json]")
+	}
+	target, err := ann.ParseQuantization(mode)
+	if err != nil {
+		return err
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("index quantize requires SQLiteStore")
+	}
+
+	ctx := context.Background()
+	current, err := sqlStore.EmbeddingQuantization(ctx)
+	if err != nil {
+		return err
+	}
+	ids, vectors, total, err := sampleStoreEmbeddings(ctx, s, sample)
+	if err != nil {
+		return err
+	}
+	storedBytes, err := sqlStore.EmbeddingBytes(ctx)
+	if err != nil {
+		return err
+	}
+
+	report := indexQuantizeReport{
+		From:         current,
+		To:           string(target),
+		StoreVectors: total,
+		StoredBytes:  storedBytes,
+		Impact:       ann.MeasureQuantizationImpact(ids, vectors, target, resolveHNSWParams(), indexRecallK, 100),
+	}
+
+	if !reportOnly && current != string(target) {
+		if globalReadOnly {
+			return fmt.Errorf("cannot quantize: database is in read-only mode")
+		}
+		n, err := sqlStore.SetEmbeddingQuantization(ctx, string(target))
+		if err != nil {
+			return fmt.Errorf("re-quantizing embeddings: %w", err)
+		}
+		report.Reencoded = n
+		report.Applied = true
+		count, err := rebuildHNSWIndex(ctx, s)
+		if err != nil {
+			return fmt.Errorf("rebuilding HNSW index: %w", err)
+		}
+		report.IndexVectors = count
+		report.StoredBytesNext, _ = sqlStore.EmbeddingBytes(ctx)
+	}
+
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printIndexQuantizeReport(report, reportOnly)
+	return nil
+}
+
+func printIndexQuantizeReport(r indexQuantizeReport, reportOnly bool) {
+	im := r.Impact
+	fmt.Printf("Vector quantization: %s → %s\n", r.From, r.To)
+	fmt.Printf("  Store vectors: %d (%s stored)\n", r.StoreVectors, formatBytes(r.StoredBytes))
+	if im.Vectors == 0 {
+		fmt.Println("  No embeddings found. Run 'cortex embed' first.")
+	} else {
+		ratio := 1.0
+		if im.BytesAfter > 0 {
+			ratio = float64(im.BytesBefore) / float64(im.BytesAfter)
+		}
+		fmt.Printf("\nRecall impact (recall@%d vs float32 brute force, %d queries over %d vectors):\n", im.K, im.Queries, im.Vectors)
+		fmt.Printf("  Exact (%s):   %.1f%%\n", im.Mode, im.ExactRecall*100)
+		fmt.Printf("  HNSW (float32): %.1f%%\n", im.BaselineIndex*100)
+		fmt.Printf("  HNSW (%s):    %.1f%%\n", im.Mode, im.IndexRecall*100)
+		fmt.Printf("  Vector memory:  %.1fx smaller\n", ratio)
+	}
+
+	switch {
+	case r.Applied:
+		fmt.Printf("\n✓ Re-encoded %d embeddings (%s → %s on disk)\n", r.Reencoded, formatBytes(r.StoredBytes), formatBytes(r.StoredBytesNext))
+		fmt.Printf("✓ Rebuilt HNSW index (%d vectors)\n", r.IndexVectors)
+		if r.To == string(ann.QuantizationNone) {
+			fmt.Println("Note: vectors were restored to float32 but keep int8 precision; run `cortex embed --force` to regenerate full-precision vectors.")
+		}
+	case reportOnly:
+		fmt.Printf("\nReport only — run `cortex index quantize %s` to apply.\n", r.To)
+	default:
+		fmt.Printf("\nStore already uses %s; nothing to do.\n", r.To)
+	}
+}
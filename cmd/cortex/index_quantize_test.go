@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunIndexQuantize_ReportThenApply(t *testing.T) {
+	tmp := t.TempDir()
+	dbPath := filepath.Join(tmp, "cortex.db")
+	oldDBPath := globalDBPath
+	globalDBPath = dbPath
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+	t.Setenv("HOME", filepath.Join(tmp, "home"))
+
+	seedEmbeddedStore(t, dbPath, 40, 8)
+
+	var runErr error
+	out := captureStdout(func() {
+		runErr = runIndexQuantize([]string{"int8", "--report", "--json"})
+	})
+	if runErr != nil {
+		t.Fatalf("runIndexQuantize --report: %v", runErr)
+	}
+	var report indexQuantizeReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("decode report: %v\n%s", err, out)
+	}
+	if report.Applied || report.From != "none" || report.To != "int8" {
+		t.Fatalf("report-only run should not apply: %+v", report)
+	}
+	if report.Impact.BytesBefore != 4*report.Impact.BytesAfter {
+		t.Fatalf("expected 4x byte reduction, got %+v", report.Impact)
+	}
+
+	out = captureStdout(func() {
+		runErr = runIndexQuantize([]string{"int8"})
+	})
+	if runErr != nil {
+		t.Fatalf("runIndexQuantize: %v", runErr)
+	}
+	if !strings.Contains(out, "Re-encoded 40 embeddings") || !strings.Contains(out, "Rebuilt HNSW index (40 vectors)") {
+		t.Fatalf("unexpected apply output:\n%s", out)
+	}
+
+	s, err := store.NewStore(store.StoreConfig{DBPath: dbPath})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer s.Close()
+	mode, err := s.(*store.SQLiteStore).EmbeddingQuantization(context.Background())
+	if err != nil || mode != "int8" {
+		t.Fatalf("store quantization = %q, %v; want int8", mode, err)
+	}
+}
+
+func TestRunIndexQuantize_RejectsUnknownMode(t *testing.T) {
+	if err := runIndexQuantize([]string{"pq"}); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+	if err := runIndexQuantize(nil); err == nil {
+		t.Fatal("expected usage error without a mode")
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/ann"
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const (
+	indexRecallK           = 10
+	indexRecallSample      = 50
+	defaultIndexTuneSample = 2000
+	defaultIndexTuneTarget = 0.95
+)
+
+func indexUsageText() string {
+	return strings.TrimSpace(`Usage: cortex index [tune|quantize]
+
+Commands:
+  cortex index                       Rebuild the HNSW index and report a recall estimate
+  cortex index tune [flags]          Measure recall@k vs brute force and recommend params
+  cortex index quantize <none|int8>  Re-encode stored embeddings + index (--report for impact only)
+
+Tune flags:
+  --sample <N>       Vectors sampled from the store to build test indexes (default: 2000)
+  --queries <N>      Query vectors used to measure recall (default: 100)
+  --k <N>            Recall@k cutoff (default: 10)
+  --target <0-1>     Minimum acceptable recall (default: 0.95)
+  --json             Emit JSON
+
+Parameters are read from config.yaml:
+  index:
+    hnsw:
+      m: 16
+      ef_construction: 200
+      ef_search: 50`)
+}
+
+// resolveHNSWParams returns the HNSW parameters configured under index.hnsw.
+// Unset fields stay zero so the ann defaults apply.
+func resolveHNSWParams() ann.Params {
+	resolved, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{CLIDBPath: globalDBPath})
+	if err != nil {
+		return ann.Params{}
+	}
+	h := resolved.Index.HNSW
+	return ann.Params{M: h.M, EfConstruction: h.EfConstruction, EfSearch: h.EfSearch}
+}
+
+type indexTuneOptions struct {
+	sample  int
+	queries int
+	k       int
+	target  float64
+	json    bool
+}
+
+type indexTuneReport struct {
+	StoreVectors   int              `json:"store_vectors"`
+	SampledVectors int              `json:"sampled_vectors"`
+	K              int              `json:"k"`
+	TargetRecall   float64          `json:"target_recall"`
+	Current        ann.Params       `json:"current"`
+	SizeHint       ann.Params       `json:"size_hint"`
+	Recommended    *ann.TuneResult  `json:"recommended,omitempty"`
+	Results        []ann.TuneResult `json:"results"`
+}
+
+func parseIndexTuneArgs(args []string) (indexTuneOptions, error) {
+	opts := indexTuneOptions{
+		sample:  defaultIndexTuneSample,
+		queries: 100,
+		k:       indexRecallK,
+		target:  defaultIndexTuneTarget,
+	}
+	intFlag := func(name, raw string) (int, error) {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			return 0, fmt.Errorf("invalid %s: %s", name, raw)
+		}
+		return v, nil
+	}
+	for i := 0; i < len(args); i++ {
+		var err error
+		switch {
+		case args[i] == "--sample" && i+1 < len(args):
+			i++
+			opts.sample, err = intFlag("--sample", args[i])
+		case args[i] == "--queries" && i+1 < len(args):
+			i++
+			opts.queries, err = intFlag("--queries", args[i])
+		case args[i] == "--k" && i+1 < len(args):
+			i++
+			opts.k, err = intFlag("--k", args[i])
+		case args[i] == "--target" && i+1 < len(args):
+			i++
+			v, perr := strconv.ParseFloat(args[i], 64)
+			if perr != nil || v <= 0 || v > 1 {
+				err = fmt.Errorf("invalid --target: %s (expected 0-1)", args[i])
+			}
+			opts.target = v
+		case args[i] == "--json":
+			opts.json = true
+		default:
+			err = fmt.Errorf("unknown argument: %s", args[i])
+		}
+		if err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+func runIndexTune(args []string) error {
+	for _, a := range args {
+		if a == "--help" || a == "-h" {
+			fmt.Println(indexUsageText())
+			return nil
+		}
+	}
+	opts, err := parseIndexTuneArgs(args)
+	if err != nil {
+		return err
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	ids, vectors, total, err := sampleStoreEmbeddings(ctx, s, opts.sample)
+	if err != nil {
+		return err
+	}
+	if len(vectors) == 0 {
+		fmt.Println("No embeddings found. Run 'cortex embed' first.")
+		return nil
+	}
+
+	sizeHint := ann.RecommendParams(total)
+	candidates := ann.DefaultTuneCandidates()
+	candidates = append(candidates, sizeHint)
+
+	if !opts.json {
+		fmt.Fprintf(os.Stderr, "Tuning HNSW on %d of %d vectors (recall@%d, target %.0f%%)...\n",
+			len(vectors), total, opts.k, opts.target*100)
+	}
+	results := ann.Tune(ids, vectors, ann.TuneOptions{
+		K:            opts.k,
+		QuerySample:  opts.queries,
+		TargetRecall: opts.target,
+		Candidates:   candidates,
+	})
+
+	report := indexTuneReport{
+		StoreVectors:   total,
+		SampledVectors: len(vectors),
+		K:              opts.k,
+		TargetRecall:   opts.target,
+		Current:        resolveHNSWParams().WithDefaults(),
+		SizeHint:       sizeHint,
+		Results:        results,
+	}
+	for i := range results {
+		if results[i].Recommended {
+			report.Recommended = &results[i]
+			break
+		}
+	}
+
+	if opts.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printIndexTuneReport(report)
+	return nil
+}
+
+// sampleStoreEmbeddings loads up to limit embeddings at a fixed stride across
+// all embedded memories, skipping vectors whose dimension differs from the first.
+func sampleStoreEmbeddings(ctx context.Context, s store.Store, limit int) ([]int64, [][]float32, int, error) {
+	all, err := s.ListMemoryIDsWithEmbeddings(ctx, 0)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("listing embedded memories: %w", err)
+	}
+	total := len(all)
+	if total == 0 {
+		return nil, nil, 0, nil
+	}
+	stride := 1
+	if limit > 0 && total > limit {
+		stride = total / limit
+	}
+
+	var ids []int64
+	var vectors [][]float32
+	dims := 0
+	for i := 0; i < total && (limit <= 0 || len(vectors) < limit); i += stride {
+		vec, err := s.GetEmbedding(ctx, all[i])
+		if err != nil || len(vec) == 0 {
+			continue
+		}
+		if dims == 0 {
+			dims = len(vec)
+		}
+		if len(vec) != dims {
+			continue
+		}
+		ids = append(ids, all[i])
+		vectors = append(vectors, vec)
+	}
+	return ids, vectors, total, nil
+}
+
+func printIndexTuneReport(r indexTuneReport) {
+	fmt.Printf("HNSW tuning — %d vectors in store, %d sampled, recall@%d\n\n", r.StoreVectors, r.SampledVectors, r.K)
+	fmt.Printf("  %-4s %-6s %-6s %-8s %-10s %-10s\n", "M", "efC", "efS", "recall", "build", "query")
+	shown := 0
+	for _, res := range r.Results {
+		if shown >= 12 && !res.Recommended {
+			continue
+		}
+		marker := " "
+		if res.Recommended {
+			marker = "★"
+		}
+		fmt.Printf("%s %-4d %-6d %-6d %-8s %-10s %-10s\n", marker,
+			res.Params.M, res.Params.EfConstruction, res.Params.EfSearch,
+			fmt.Sprintf("%.1f%%", res.Recall*100),
+			res.BuildTime.Round(time.Millisecond), res.AvgQueryTime.Round(time.Microsecond))
+		shown++
+	}
+	if len(r.Results) > shown {
+		fmt.Printf("  … %d more (use --json for all)\n", len(r.Results)-shown)
+	}
+
+	fmt.Printf("\nCurrent:  M=%d efConstruction=%d efSearch=%d\n", r.Current.M, r.Current.EfConstruction, r.Current.EfSearch)
+	if r.Recommended == nil {
+		return
+	}
+	p := r.Recommended.Params
+	if !r.Recommended.MeetsTarget {
+		fmt.Printf("No candidate reached %.0f%% recall; best was %.1f%%.\n", r.TargetRecall*100, r.Recommended.Recall*100)
+	}
+	fmt.Printf("Recommend: M=%d efConstruction=%d efSearch=%d (recall %.1f%%)\n", p.M, p.EfConstruction, p.EfSearch, r.Recommended.Recall*100)
+	if r.SampledVectors < r.StoreVectors {
+		fmt.Printf("Note: measured on a sample; at %d vectors the size heuristic suggests M=%d efSearch=%d.\n",
+			r.StoreVectors, r.SizeHint.M, r.SizeHint.EfSearch)
+	}
+	fmt.Printf("\nAdd to ~/.cortex/config.yaml, then run `cortex index`:\n")
+	fmt.Printf("  index:\n    hnsw:\n      m: %d\n      ef_construction: %d\n      ef_search: %d\n", p.M, p.EfConstruction, p.EfSearch)
+}
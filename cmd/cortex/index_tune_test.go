@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func seedEmbeddedStore(t *testing.T, dbPath string, n, dims int) {
+	t.Helper()
+	s, err := store.NewStore(store.StoreConfig{DBPath: dbPath})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < n; i++ {
+		id, err := s.AddMemory(ctx, &store.Memory{Content: fmt.Sprintf("memory %d", i), SourceFile: "seed.md", SourceLine: i + 1})
+		if err != nil {
+			t.Fatalf("AddMemory: %v", err)
+		}
+		vec := make([]float32, dims)
+		for d := range vec {
+			vec[d] = rng.Float32()*2 - 1
+		}
+		if err := s.AddEmbedding(ctx, id, vec); err != nil {
+			t.Fatalf("AddEmbedding: %v", err)
+		}
+	}
+}
+
+func TestParseIndexTuneArgs(t *testing.T) {
+	opts, err := parseIndexTuneArgs([]string{"--sample", "500", "--k", "5", "--target", "0.9", "--json"})
+	if err != nil {
+		t.Fatalf("parseIndexTuneArgs: %v", err)
+	}
+	if opts.sample != 500 || opts.k != 5 || opts.target != 0.9 || !opts.json {
+		t.Fatalf("unexpected opts: %+v", opts)
+	}
+
+	for _, bad := range [][]string{{"--k", "0"}, {"--target", "1.5"}, {"--bogus"}} {
+		if _, err := parseIndexTuneArgs(bad); err == nil {
+			t.Fatalf("expected error for %v", bad)
+		}
+	}
+}
+
+func TestRunIndexTune_JSONRecommendsParams(t *testing.T) {
+	tmp := t.TempDir()
+	dbPath := filepath.Join(tmp, "cortex.db")
+	oldDBPath := globalDBPath
+	globalDBPath = dbPath
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+	t.Setenv("HOME", filepath.Join(tmp, "home"))
+
+	seedEmbeddedStore(t, dbPath, 60, 8)
+
+	var runErr error
+	out := captureStdout(func() {
+		runErr = runIndexTune([]string{"--sample", "60", "--queries", "10", "--k", "5", "--json"})
+	})
+	if runErr != nil {
+		t.Fatalf("runIndexTune: %v", runErr)
+	}
+
+	var report indexTuneReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("decode report: %v\n%s", err, out)
+	}
+	if report.StoreVectors != 60 || report.SampledVectors != 60 {
+		t.Fatalf("unexpected vector counts: %+v", report)
+	}
+	if report.Recommended == nil || len(report.Results) == 0 {
+		t.Fatalf("expected a recommendation, got %+v", report)
+	}
+	if report.Current.M == 0 {
+		t.Fatalf("current params should be resolved with defaults: %+v", report.Current)
+	}
+}
+
+func TestRunIndex_ReportsRecallEstimate(t *testing.T) {
+	tmp := t.TempDir()
+	dbPath := filepath.Join(tmp, "cortex.db")
+	oldDBPath := globalDBPath
+	globalDBPath = dbPath
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+	t.Setenv("HOME", filepath.Join(tmp, "home"))
+
+	seedEmbeddedStore(t, dbPath, 30, 8)
+
+	var runErr error
+	out := captureStdout(func() {
+		runErr = runIndex(nil)
+	})
+	if runErr != nil {
+		t.Fatalf("runIndex: %v", runErr)
+	}
+	if !strings.Contains(out, "Recall@10 estimate:") || !strings.Contains(out, "Params: M=16") {
+		t.Fatalf("expected params and recall estimate in output, got:\n%s", out)
+	}
+}
@@ -0,0 +1,480 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/embed"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// initImportCandidates are the home-relative directories cortex init offers
+// to import when they exist and hold notes.
+var initImportCandidates = []string{
+	"notes",
+	"Notes",
+	"Documents/notes",
+	"Documents/Notes",
+	"Obsidian",
+	"Documents/Obsidian",
+	".openclaw/workspace", // OpenClaw agent workspace
+	"clawd",               // older OpenClaw workspace location
+}
+
+// initScanLimit caps how many files cortex init counts per candidate
+// directory, so a huge tree does not stall the wizard.
+const initScanLimit = 10000
+
+// Indirections for tests.
+var (
+	initOllamaRunning = isOllamaRunning
+	initOllamaModels  = listOllamaModels
+	initImportDir     = func(dir string) error { return runImport([]string{dir, "--recursive", "--extract"}) }
+)
+
+// initChoice is one option offered by a cortex init prompt.
+type initChoice struct {
+	Value string // "" = none
+	Label string
+}
+
+// initLocation is a directory cortex init offers to import.
+type initLocation struct {
+	Path  string
+	Files int
+}
+
+type initWizard struct {
+	in        *bufio.Reader
+	assumeYes bool
+	noImport  bool
+}
+
+func runInit(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	nonInteractive := fs.Bool("y", false, "Accept defaults without prompting")
+	noImport := fs.Bool("no-import", false, "Skip importing detected note folders")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument: %s\nUsage: cortex init [-y] [--no-import]", fs.Arg(0))
+	}
+	w := &initWizard{in: bufio.NewReader(os.Stdin), assumeYes: *nonInteractive, noImport: *noImport}
+	return w.run()
+}
+
+func (w *initWizard) run() error {
+	configPath := cfgresolver.DefaultConfigPath()
+
+	fmt.Println("🧠 Cortex Setup")
+	fmt.Println()
+
+	// Step 1: config (detect providers, choose defaults, write).
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	if _, err := os.Stat(configPath); err == nil {
+		fmt.Printf("  ✓ Config already exists: %s\n", configPath)
+		fmt.Println()
+	} else if err := w.writeConfig(configPath); err != nil {
+		return err
+	}
+
+	// Step 2: database.
+	dbPath, memories, err := initDatabase()
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+
+	// Step 3: first import + extraction.
+	imported := 0
+	if !w.noImport {
+		locations := detectImportLocations()
+		if memories > 0 && len(locations) > 0 {
+			fmt.Printf("  Database already holds %d memories; import more below or press Enter to skip.\n", memories)
+		}
+		for _, loc := range locations {
+			if !w.confirm(fmt.Sprintf("  Import %s (%d notes) and extract facts?", displayHomePath(loc.Path), loc.Files), memories == 0) {
+				continue
+			}
+			fmt.Println()
+			if err := initImportDir(loc.Path); err != nil {
+				fmt.Fprintf(os.Stderr, "  ⚠ Import of %s failed: %v\n", loc.Path, err)
+				continue
+			}
+			imported++
+			fmt.Println()
+		}
+		if len(locations) == 0 {
+			fmt.Println("  No note folders found in the usual places (~/notes, ~/Obsidian, OpenClaw workspace).")
+			fmt.Println()
+		}
+	}
+
+	// Step 4: health summary.
+	report := runDoctorChecks()
+	fmt.Printf("🩺 Health: %d pass, %d warn, %d fail\n", report.Summary.Pass, report.Summary.Warn, report.Summary.Fail)
+	for _, check := range report.Checks {
+		if check.Status == "pass" {
+			continue
+		}
+		icon := "!"
+		if check.Status == "fail" {
+			icon = "✗"
+		}
+		fmt.Printf("  %s %-14s %s\n", icon, check.Name, check.Details)
+		if check.Hint != "" {
+			fmt.Printf("      hint: %s\n", check.Hint)
+		}
+	}
+	fmt.Println()
+
+	fmt.Println("🚀 Ready! Next steps:")
+	fmt.Println()
+	if imported == 0 {
+		fmt.Println("  cortex import ~/notes/ --recursive --extract   # Import your files")
+	}
+	fmt.Println("  cortex search \"what I know\"   # Search your knowledge")
+	fmt.Println("  cortex mcp                     # Serve memory to your agent")
+	fmt.Printf("  cortex doctor                  # Re-check setup (db: %s)\n", dbPath)
+	fmt.Println()
+	return nil
+}
+
+// writeConfig detects LLM keys, Ollama, and the default embedder, lets the
+// user pick defaults, and writes config.yaml.
+func (w *initWizard) writeConfig(configPath string) error {
+	ollamaModels := []string(nil)
+	if initOllamaRunning() {
+		ollamaModels = initOllamaModels()
+		fmt.Printf("  ✓ Ollama running on localhost:11434 (%d models)\n", len(ollamaModels))
+	}
+
+	llmChoices := initLLMChoices(ollamaModels)
+	if len(llmChoices) == 1 {
+		fmt.Println("  ⚠ No LLM API key found in environment")
+		fmt.Println("    Set one of: OPENROUTER_API_KEY, GOOGLE_API_KEY, GEMINI_API_KEY, OPENAI_API_KEY")
+		fmt.Println("    LLM features (enrichment, classification, answers) will be skipped until configured.")
+	}
+	llmChoice := w.choose("LLM for extraction and answers", llmChoices)
+	fmt.Println()
+
+	embedChoices := initEmbedChoices(ollamaModels)
+	if len(embedChoices) == 1 {
+		fmt.Println("  ⚠ No embedder detected")
+		fmt.Println("    Semantic search will require an explicit --embed provider.")
+	}
+	embedChoice := w.choose("Embedder for semantic search", embedChoices)
+	fmt.Println()
+
+	cfgContent := initConfigYAML(llmChoice, embedChoice)
+	if !w.confirm(fmt.Sprintf("  Write config to %s?", configPath), true) {
+		fmt.Println("  Skipped config write.")
+		fmt.Println()
+		return nil
+	}
+	if err := os.WriteFile(configPath, []byte(cfgContent), 0644); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	fmt.Printf("  ✓ Wrote %s\n", configPath)
+	fmt.Println()
+	return nil
+}
+
+// initLLMChoices lists LLM defaults from API keys in the environment and
+// local Ollama chat models, ending with "none".
+func initLLMChoices(ollamaModels []string) []initChoice {
+	var choices []initChoice
+	for _, c := range []struct{ env, model string }{
+		{"OPENROUTER_API_KEY", "openrouter/deepseek/deepseek-chat"},
+		{"GOOGLE_API_KEY", "google/gemini-2.0-flash"},
+		{"GEMINI_API_KEY", "google/gemini-2.0-flash"},
+		{"OPENAI_API_KEY", "openai/gpt-4o-mini"},
+	} {
+		if os.Getenv(c.env) == "" || hasInitChoice(choices, c.model) {
+			continue
+		}
+		choices = append(choices, initChoice{Value: c.model, Label: fmt.Sprintf("%s (key from %s)", c.model, c.env)})
+	}
+	for _, m := range ollamaModels {
+		if isOllamaEmbedModel(m) {
+			continue
+		}
+		ref := "ollama/" + strings.TrimSuffix(m, ":latest")
+		choices = append(choices, initChoice{Value: ref, Label: ref + " (local)"})
+	}
+	return append(choices, initChoice{Label: "none — configure later"})
+}
+
+// initEmbedChoices lists embedder defaults: whatever cortex already resolves
+// (env, ONNX model on disk), then local Ollama embedding models, ending with
+// "none" (BM25 keyword search only).
+func initEmbedChoices(ollamaModels []string) []initChoice {
+	var choices []initChoice
+	if cfg, err := embed.ResolveEmbedConfig(""); err == nil {
+		if ref := resolvedEmbedRef(cfg); ref != "" {
+			label := ref
+			if src := resolvedEmbedSource(cfg); src != "" {
+				label += " (" + src + ")"
+			}
+			choices = append(choices, initChoice{Value: ref, Label: label})
+		}
+	}
+	for _, m := range ollamaModels {
+		ref := "ollama/" + strings.TrimSuffix(m, ":latest")
+		if !isOllamaEmbedModel(m) || hasInitChoice(choices, ref) {
+			continue
+		}
+		choices = append(choices, initChoice{Value: ref, Label: ref + " (local)"})
+	}
+	return append(choices, initChoice{Label: "none — keyword (BM25) search only"})
+}
+
+func hasInitChoice(choices []initChoice, value string) bool {
+	for _, c := range choices {
+		if c.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isOllamaEmbedModel(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "embed") || strings.Contains(name, "minilm") || strings.HasPrefix(name, "bge")
+}
+
+// initConfigYAML renders config.yaml for the chosen defaults.
+func initConfigYAML(llmChoice, embedChoice string) string {
+	lines := []string{
+		"# Cortex configuration",
+		"# Docs: https://github.com/hurttlocker/cortex",
+		"",
+	}
+	if llmChoice != "" {
+		lines = append(lines, "llm:", "  provider: "+llmChoice, "")
+	} else {
+		lines = append(lines,
+			"# llm:",
+			"#   provider: openrouter/deepseek/deepseek-chat",
+			"#   api_key: your-key-here",
+			"")
+	}
+	if embedChoice != "" {
+		lines = append(lines, "embed:", "  provider: "+embedChoice, "")
+	} else {
+		lines = append(lines, "# embed:", "#   provider: ollama/nomic-embed-text", "")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// initDatabase creates the database if needed and returns its path and
+// current memory count.
+func initDatabase() (string, int, error) {
+	dbPath := getDBPath()
+	if dbPath == "" {
+		dbPath = store.DefaultDBPath
+	}
+	dbPath = expandUserPath(dbPath)
+
+	if _, err := os.Stat(dbPath); err == nil {
+		st, err := store.NewStore(store.StoreConfig{DBPath: dbPath, ReadOnly: true})
+		if err != nil {
+			fmt.Printf("  ⚠ Database exists but cannot open: %v\n", err)
+			fmt.Println("    Try: cortex doctor")
+			return dbPath, 0, nil
+		}
+		defer st.Close()
+		stats, _ := st.Stats(context.Background())
+		if stats == nil {
+			fmt.Printf("  ✓ Database: %s (exists)\n", dbPath)
+			return dbPath, 0, nil
+		}
+		fmt.Printf("  ✓ Database: %s (%d memories, %d facts)\n", dbPath, stats.MemoryCount, stats.FactCount)
+		return dbPath, int(stats.MemoryCount), nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return "", 0, fmt.Errorf("creating database directory: %w", err)
+	}
+	st, err := store.NewStore(store.StoreConfig{DBPath: dbPath})
+	if err != nil {
+		return "", 0, fmt.Errorf("creating database: %w", err)
+	}
+	st.Close()
+	fmt.Printf("  ✓ Created database: %s\n", dbPath)
+	return dbPath, 0, nil
+}
+
+// detectImportLocations returns the initImportCandidates under $HOME that
+// contain markdown or text notes.
+func detectImportLocations() []initLocation {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	var out []initLocation
+	seen := map[string]bool{}
+	for _, rel := range initImportCandidates {
+		dir := filepath.Join(home, rel)
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		// Notes and notes are the same directory on case-insensitive disks.
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			key := strings.ToLower(real)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		if n := countNoteFiles(dir); n > 0 {
+			out = append(out, initLocation{Path: dir, Files: n})
+		}
+	}
+	return out
+}
+
+// countNoteFiles counts .md and .txt files under dir, skipping hidden
+// directories, up to initScanLimit.
+func countNoteFiles(dir string) int {
+	n := 0
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(d.Name())) {
+		case ".md", ".markdown", ".txt":
+			n++
+			if n >= initScanLimit {
+				return filepath.SkipAll
+			}
+		}
+		return nil
+	})
+	return n
+}
+
+func displayHomePath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
+
+// choose prints choices and reads a 1-based pick; Enter (or -y) takes the
+// first. It returns the chosen Value.
+func (w *initWizard) choose(title string, choices []initChoice) string {
+	if w.assumeYes || len(choices) == 1 {
+		c := choices[0]
+		if c.Value != "" {
+			fmt.Printf("  ✓ %s: %s\n", title, c.Label)
+		}
+		return c.Value
+	}
+	fmt.Printf("  %s:\n", title)
+	for i, c := range choices {
+		fmt.Printf("    %d) %s\n", i+1, c.Label)
+	}
+	for {
+		fmt.Printf("  Choose [1-%d] (default 1): ", len(choices))
+		line, err := w.in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return choices[0].Value
+		}
+		if n, convErr := strconv.Atoi(line); convErr == nil && n >= 1 && n <= len(choices) {
+			return choices[n-1].Value
+		}
+		if err != nil { // EOF with a bad answer: take the default
+			return choices[0].Value
+		}
+		fmt.Printf("  Please enter a number from 1 to %d.\n", len(choices))
+	}
+}
+
+// confirm asks a yes/no question. Enter (or -y) takes def.
+func (w *initWizard) confirm(prompt string, def bool) bool {
+	if w.assumeYes {
+		return def
+	}
+	hint := "[Y/n]"
+	if !def {
+		hint = "[y/N]"
+	}
+	fmt.Printf("%s %s ", prompt, hint)
+	line, _ := w.in.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "":
+		return def
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// isOllamaRunning checks if Ollama is reachable on localhost
+func isOllamaRunning() bool {
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Get("http://localhost:11434/api/tags")
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == 200
+}
+
+// listOllamaModels returns the names of models installed in the local
+// Ollama, or nil when it cannot be reached.
+func listOllamaModels() []string {
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Get("http://localhost:11434/api/tags")
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	var body struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(body.Models))
+	for _, m := range body.Models {
+		if m.Name != "" {
+			names = append(names, m.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useInitHome isolates cortex init: a fresh HOME, no provider keys, no
+// Ollama, and imports recorded instead of run.
+func useInitHome(t *testing.T) (home string, imported *[]string) {
+	t.Helper()
+	home = useWorkspaceHome(t)
+	for _, key := range []string{"OPENROUTER_API_KEY", "GOOGLE_API_KEY", "GEMINI_API_KEY", "OPENAI_API_KEY", "CORTEX_EMBED", "CORTEX_LLM"} {
+		t.Setenv(key, "")
+	}
+	oldRunning, oldModels, oldImport := initOllamaRunning, initOllamaModels, initImportDir
+	t.Cleanup(func() { initOllamaRunning, initOllamaModels, initImportDir = oldRunning, oldModels, oldImport })
+	initOllamaRunning = func() bool { return false }
+	initOllamaModels = func() []string { return nil }
+	var dirs []string
+	initImportDir = func(dir string) error {
+		dirs = append(dirs, dir)
+		return nil
+	}
+	return home, &dirs
+}
+
+func writeNote(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("# note\n\nsomething worth remembering\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInitWizard_AcceptDefaults(t *testing.T) {
+	home, imported := useInitHome(t)
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	writeNote(t, filepath.Join(home, "notes", "a.md"))
+	writeNote(t, filepath.Join(home, ".openclaw", "workspace", "memory", "2026-01-01.md"))
+	writeNote(t, filepath.Join(home, "Obsidian", ".obsidian", "hidden.md")) // hidden dirs don't count
+
+	w := &initWizard{in: bufio.NewReader(strings.NewReader("")), assumeYes: true}
+	var err error
+	out := captureStdout(func() { err = w.run() })
+	if err != nil {
+		t.Fatalf("run: %v\n%s", err, out)
+	}
+
+	cfg, err := os.ReadFile(filepath.Join(home, ".cortex", "config.yaml"))
+	if err != nil {
+		t.Fatalf("reading config: %v", err)
+	}
+	if !strings.Contains(string(cfg), "provider: openai/gpt-4o-mini") {
+		t.Fatalf("expected the detected LLM in config, got:\n%s", cfg)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".cortex", "cortex.db")); err != nil {
+		t.Fatalf("expected the database to be created: %v", err)
+	}
+	want := []string{filepath.Join(home, "notes"), filepath.Join(home, ".openclaw", "workspace")}
+	if strings.Join(*imported, ",") != strings.Join(want, ",") {
+		t.Fatalf("imported %v, want %v", *imported, want)
+	}
+	if !strings.Contains(out, "🩺 Health:") {
+		t.Fatalf("expected a health summary, got:\n%s", out)
+	}
+}
+
+func TestInitWizard_InteractiveChoices(t *testing.T) {
+	home, imported := useInitHome(t)
+	t.Setenv("OPENROUTER_API_KEY", "or-test")
+	initOllamaRunning = func() bool { return true }
+	initOllamaModels = func() []string { return []string{"llama3.2:latest", "nomic-embed-text:latest"} }
+	writeNote(t, filepath.Join(home, "notes", "a.md"))
+
+	// LLM: pick 2 (ollama/llama3.2); embed: an out-of-range answer, then 2
+	// (after the built-in default); write config: yes; import ~/notes: no.
+	input := "2\n9\n2\ny\nn\n"
+	w := &initWizard{in: bufio.NewReader(strings.NewReader(input))}
+	var err error
+	out := captureStdout(func() { err = w.run() })
+	if err != nil {
+		t.Fatalf("run: %v\n%s", err, out)
+	}
+	cfg, _ := os.ReadFile(filepath.Join(home, ".cortex", "config.yaml"))
+	if !strings.Contains(string(cfg), "provider: ollama/llama3.2\n") || !strings.Contains(string(cfg), "provider: ollama/nomic-embed-text\n") {
+		t.Fatalf("unexpected config:\n%s", cfg)
+	}
+	if len(*imported) != 0 {
+		t.Fatalf("declined import still ran: %v", *imported)
+	}
+	if !strings.Contains(out, "Please enter a number") {
+		t.Fatalf("expected a re-prompt for an out-of-range choice, got:\n%s", out)
+	}
+
+	// A second run keeps the existing config and, with memories absent,
+	// still offers the import.
+	w = &initWizard{in: bufio.NewReader(strings.NewReader("\n"))}
+	out = captureStdout(func() { err = w.run() })
+	if err != nil || !strings.Contains(out, "Config already exists") || len(*imported) != 1 {
+		t.Fatalf("second run: err=%v imported=%v\n%s", err, *imported, out)
+	}
+}
+
+func TestInitChoices(t *testing.T) {
+	useInitHome(t)
+	t.Setenv("GOOGLE_API_KEY", "g")
+	t.Setenv("GEMINI_API_KEY", "g")
+	llm := initLLMChoices([]string{"qwen2.5:7b", "mxbai-embed-large:latest"})
+	if len(llm) != 3 || llm[0].Value != "google/gemini-2.0-flash" || llm[1].Value != "ollama/qwen2.5:7b" || llm[2].Value != "" {
+		t.Fatalf("unexpected LLM choices: %+v", llm)
+	}
+	emb := initEmbedChoices([]string{"qwen2.5:7b", "mxbai-embed-large:latest"})
+	if emb[len(emb)-1].Value != "" || !hasInitChoice(emb, "ollama/mxbai-embed-large") || hasInitChoice(emb, "ollama/qwen2.5:7b") {
+		t.Fatalf("unexpected embed choices: %+v", emb)
+	}
+
+	cfg := initConfigYAML("", "")
+	if strings.Contains(cfg, "\nllm:") || strings.Contains(cfg, "\nembed:") {
+		t.Fatalf("no choices should leave commented templates, got:\n%s", cfg)
+	}
+	if err := runInit([]string{"extra"}); err == nil {
+		t.Fatal("expected unexpected argument error")
+	}
+}
@@ -0,0 +1,420 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/embed"
+	"github.com/hurttlocker/cortex/internal/jobs"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func jobsUsageText() string {
+	return `Usage: cortex jobs <list|status|start|cancel> [flags]
+
+Long-running work (extraction, enrichment, embedding, cluster rebuilds) runs
+as background jobs. Jobs are stored in the database and run by whichever
+process hosts a job runner: cortex daemon, cortex mcp, or jobs start --run.
+
+Subcommands:
+  list [--status queued|running|done|failed|cancelled|active] [--kind K] [--limit N] [--json]
+                        Show jobs, newest first
+  status <id> [--json]  Show one job's progress, result, and error
+  start <kind> [--memory-ids 1,2,3] [--enrich] [--source <file>] [--run]
+        [--llm <provider/model>] [--embed <provider/model>] [--json]
+                        Queue a job; --run executes it here and waits
+  cancel <id>           Cancel a queued job, or stop a running one at its
+                        next checkpoint (work already done is kept)
+
+Kinds:
+  extract               Rule-based fact extraction for --memory-ids (--enrich adds LLM enrichment)
+  enrich                Extraction plus LLM enrichment (runner needs --llm)
+  embed                 Embed memories without embeddings (runner needs --embed)
+  cluster_rebuild       Recompute topic clusters
+
+MCP clients use cortex_job_start, cortex_job_status, and cortex_job_cancel.`
+}
+
+// jobKinds are the built-in job kinds `cortex jobs start` accepts.
+var jobKinds = []string{jobs.KindExtract, jobs.KindEnrich, jobs.KindEmbed, jobs.KindClusterRebuild}
+
+func runJobs(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(jobsUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "list", "ls":
+		return runJobsList(args[1:])
+	case "status", "show":
+		return runJobsStatus(args[1:])
+	case "start":
+		return runJobsStart(args[1:])
+	case "cancel":
+		return runJobsCancel(args[1:])
+	default:
+		return fmt.Errorf("unknown jobs subcommand: %s", args[0])
+	}
+}
+
+func openJobsStore() (*store.SQLiteStore, func(), error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening store: %w", err)
+	}
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, nil, fmt.Errorf("jobs require SQLiteStore")
+	}
+	return sqlStore, func() { s.Close() }, nil
+}
+
+// newJobRunner builds a runner with the built-in handlers. llmFlag enables
+// enrichment and embedder enables embed jobs; either may be empty/nil.
+func newJobRunner(s *store.SQLiteStore, llmFlag string, embedder embed.Embedder) (*jobs.Runner, error) {
+	opts := jobs.BuiltinOptions{ReviewThreshold: enrichReviewThreshold, Embedder: embedder}
+	if llmFlag != "" {
+		provider, err := tryCreateProvider(llmFlag)
+		if err != nil {
+			return nil, fmt.Errorf("creating LLM provider: %w", err)
+		}
+		opts.Enricher = provider
+	}
+	runner := jobs.NewRunner(s)
+	jobs.RegisterBuiltins(runner, opts)
+	return runner, nil
+}
+
+func parseJobID(args []string, verb string) (int64, []string, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return 0, nil, fmt.Errorf("usage: cortex jobs %s <id>", verb)
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, nil, fmt.Errorf("invalid job id: %s", args[0])
+	}
+	return id, args[1:], nil
+}
+
+func runJobsList(args []string) error {
+	opts := store.JobListOpts{Limit: 20}
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--status" && i+1 < len(args):
+			i++
+			opts.Status = args[i]
+		case strings.HasPrefix(args[i], "--status="):
+			opts.Status = strings.TrimPrefix(args[i], "--status=")
+		case args[i] == "--kind" && i+1 < len(args):
+			i++
+			opts.Kind = args[i]
+		case strings.HasPrefix(args[i], "--kind="):
+			opts.Kind = strings.TrimPrefix(args[i], "--kind=")
+		case args[i] == "--limit" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("--limit must be a positive integer")
+			}
+			opts.Limit = n
+		case strings.HasPrefix(args[i], "--limit="):
+			n, err := strconv.Atoi(strings.TrimPrefix(args[i], "--limit="))
+			if err != nil || n <= 0 {
+				return fmt.Errorf("--limit must be a positive integer")
+			}
+			opts.Limit = n
+		case args[i] == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	s, closeStore, err := openJobsStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	list, err := s.ListJobs(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		if list == nil {
+			list = []*store.Job{}
+		}
+		data, _ := json.MarshalIndent(list, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(list) == 0 {
+		fmt.Println("No jobs.")
+		return nil
+	}
+	for _, j := range list {
+		fmt.Printf("#%-5d %-16s %-10s %s  %s\n", j.ID, j.Kind, j.Status, jobProgressLabel(j), j.CreatedAt.Local().Format("2006-01-02 15:04"))
+		if j.Error != "" {
+			fmt.Printf("       error: %s\n", truncateString(j.Error, 100))
+		}
+	}
+	return nil
+}
+
+func jobProgressLabel(j *store.Job) string {
+	if j.Total > 0 {
+		return fmt.Sprintf("%d/%d", j.Progress, j.Total)
+	}
+	return "-"
+}
+
+func runJobsStatus(args []string) error {
+	id, rest, err := parseJobID(args, "status")
+	if err != nil {
+		return err
+	}
+	jsonOutput := false
+	for _, a := range rest {
+		switch {
+		case a == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(a, "-"):
+			return fmt.Errorf("unknown flag: %s", a)
+		default:
+			return fmt.Errorf("unexpected argument: %s", a)
+		}
+	}
+
+	s, closeStore, err := openJobsStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	j, err := s.GetJob(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	if j == nil {
+		return fmt.Errorf("job %d not found", id)
+	}
+	if jsonOutput {
+		data, _ := json.MarshalIndent(j, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	printJob(j)
+	return nil
+}
+
+func printJob(j *store.Job) {
+	fmt.Printf("Job %d (%s): %s\n", j.ID, j.Kind, j.Status)
+	if j.CancelRequested && !j.Finished() {
+		fmt.Println("  cancellation requested")
+	}
+	fmt.Printf("  progress: %s\n", jobProgressLabel(j))
+	if j.Params != "" {
+		fmt.Printf("  params:   %s\n", j.Params)
+	}
+	if j.CreatedBy != "" {
+		fmt.Printf("  created:  %s by %s\n", j.CreatedAt.Local().Format(time.RFC3339), j.CreatedBy)
+	} else {
+		fmt.Printf("  created:  %s\n", j.CreatedAt.Local().Format(time.RFC3339))
+	}
+	if j.StartedAt != nil {
+		fmt.Printf("  started:  %s\n", j.StartedAt.Local().Format(time.RFC3339))
+	}
+	if j.FinishedAt != nil {
+		fmt.Printf("  finished: %s\n", j.FinishedAt.Local().Format(time.RFC3339))
+	}
+	if j.Result != "" {
+		fmt.Printf("  result:   %s\n", j.Result)
+	}
+	if j.Error != "" {
+		fmt.Printf("  error:    %s\n", j.Error)
+	}
+}
+
+func runJobsStart(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: cortex jobs start <%s> [flags]", strings.Join(jobKinds, "|"))
+	}
+	kind := args[0]
+	known := false
+	for _, k := range jobKinds {
+		known = known || k == kind
+	}
+	if !known {
+		return fmt.Errorf("unknown job kind %q (valid: %s)", kind, strings.Join(jobKinds, ", "))
+	}
+
+	var memoryIDs []int64
+	var source, llmFlag, embedFlag string
+	enrich, runNow, jsonOutput := false, false, false
+	rest := args[1:]
+	for i := 0; i < len(rest); i++ {
+		switch {
+		case rest[i] == "--memory-ids" && i+1 < len(rest):
+			i++
+			ids, err := parseJobMemoryIDs(rest[i])
+			if err != nil {
+				return err
+			}
+			memoryIDs = append(memoryIDs, ids...)
+		case strings.HasPrefix(rest[i], "--memory-ids="):
+			ids, err := parseJobMemoryIDs(strings.TrimPrefix(rest[i], "--memory-ids="))
+			if err != nil {
+				return err
+			}
+			memoryIDs = append(memoryIDs, ids...)
+		case rest[i] == "--enrich":
+			enrich = true
+		case rest[i] == "--source" && i+1 < len(rest):
+			i++
+			source = rest[i]
+		case strings.HasPrefix(rest[i], "--source="):
+			source = strings.TrimPrefix(rest[i], "--source=")
+		case rest[i] == "--llm" && i+1 < len(rest):
+			i++
+			llmFlag = rest[i]
+		case strings.HasPrefix(rest[i], "--llm="):
+			llmFlag = strings.TrimPrefix(rest[i], "--llm=")
+		case rest[i] == "--embed" && i+1 < len(rest):
+			i++
+			embedFlag = rest[i]
+		case strings.HasPrefix(rest[i], "--embed="):
+			embedFlag = strings.TrimPrefix(rest[i], "--embed=")
+		case rest[i] == "--run":
+			runNow = true
+		case rest[i] == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(rest[i], "-"):
+			return fmt.Errorf("unknown flag: %s", rest[i])
+		default:
+			return fmt.Errorf("unexpected argument: %s", rest[i])
+		}
+	}
+
+	var params any
+	switch kind {
+	case jobs.KindExtract, jobs.KindEnrich:
+		if len(memoryIDs) == 0 {
+			return fmt.Errorf("%s jobs require --memory-ids", kind)
+		}
+		params = jobs.ExtractParams{MemoryIDs: memoryIDs, Enrich: enrich}
+	case jobs.KindEmbed:
+		params = jobs.EmbedParams{Source: source}
+	}
+
+	s, closeStore, err := openJobsStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	// Build the runner before queueing so a bad --llm/--embed leaves no job behind.
+	var runner *jobs.Runner
+	if runNow {
+		var embedder embed.Embedder
+		if kind == jobs.KindEmbed {
+			if embedFlag == "" {
+				return fmt.Errorf("embed jobs run with --run require --embed <provider/model>")
+			}
+			embedCfg, err := embed.NewEmbedConfig(embedFlag)
+			if err != nil {
+				return fmt.Errorf("invalid embed model: %w", err)
+			}
+			if embedder, err = embed.NewClient(embedCfg); err != nil {
+				return fmt.Errorf("creating embedder: %w", err)
+			}
+		}
+		if kind == jobs.KindEnrich && llmFlag == "" {
+			return fmt.Errorf("enrich jobs run with --run require --llm <provider/model>")
+		}
+		if runner, err = newJobRunner(s, llmFlag, embedder); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	job, err := jobs.Enqueue(ctx, s, kind, params, "cli")
+	if err != nil {
+		return err
+	}
+	if runner != nil {
+		if !jsonOutput {
+			fmt.Fprintf(os.Stderr, "Running job %d (%s)...\n", job.ID, kind)
+		}
+		if job, err = runner.RunJob(ctx, job.ID); err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(job, "", "  ")
+		fmt.Println(string(data))
+	} else if runner != nil {
+		printJob(job)
+	} else {
+		fmt.Printf("Queued job %d (%s). It runs in cortex daemon or cortex mcp; follow it with: cortex jobs status %d\n", job.ID, kind, job.ID)
+	}
+	if job.Status == store.JobFailed {
+		return fmt.Errorf("job %d failed: %s", job.ID, job.Error)
+	}
+	return nil
+}
+
+func parseJobMemoryIDs(raw string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid memory id: %s", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func runJobsCancel(args []string) error {
+	id, rest, err := parseJobID(args, "cancel")
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("unexpected argument: %s", rest[0])
+	}
+
+	s, closeStore, err := openJobsStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	j, err := s.CancelJob(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	if j.Status == store.JobRunning {
+		fmt.Printf("Cancellation requested for job %d; it stops at its next checkpoint.\n", id)
+	} else {
+		fmt.Printf("Job %d cancelled.\n", id)
+	}
+	return nil
+}
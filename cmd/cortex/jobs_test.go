@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunJobs_StartRunListCancel(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, closeStore, err := openJobsStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	memID, _ := s.AddMemory(context.Background(), &store.Memory{Content: "Database: PostgreSQL 16\nDeploy target: fly.io", SourceFile: "infra.md"})
+	closeStore()
+
+	out := captureStdout(func() {
+		if err := runJobs([]string{"start", "extract", "--memory-ids", "1", "--run"}); err != nil {
+			t.Fatalf("start --run: %v", err)
+		}
+	})
+	if memID != 1 || !strings.Contains(out, "Job 1 (extract): done") || !strings.Contains(out, `"facts_extracted":`) {
+		t.Fatalf("unexpected start --run output:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runJobs([]string{"start", "cluster_rebuild"}); err != nil {
+			t.Fatalf("start: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Queued job 2 (cluster_rebuild)") {
+		t.Fatalf("unexpected start output: %q", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runJobs([]string{"list", "--status", "active"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	if !strings.Contains(out, "#2") || strings.Contains(out, "#1 ") {
+		t.Fatalf("active list should show only the queued job:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runJobs([]string{"cancel", "2"}); err != nil {
+			t.Fatalf("cancel: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Job 2 cancelled") {
+		t.Fatalf("unexpected cancel output: %q", out)
+	}
+	if err := runJobs([]string{"cancel", "2"}); err == nil || !strings.Contains(err.Error(), "already cancelled") {
+		t.Fatalf("second cancel should fail, got %v", err)
+	}
+
+	for _, bad := range [][]string{
+		{"start", "reindex"},
+		{"start", "extract"},
+		{"start", "embed", "--run"},
+		{"status", "abc"},
+		{"list", "--bogus"},
+	} {
+		if err := runJobs(bad); err == nil {
+			t.Fatalf("expected error for %v", bad)
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/hurttlocker/cortex/internal/ann"
 	"github.com/hurttlocker/cortex/internal/answer"
 	"github.com/hurttlocker/cortex/internal/ask"
 	cfgresolver "github.com/hurttlocker/cortex/internal/config"
@@ -39,6 +40,8 @@ import (
 	"github.com/hurttlocker/cortex/internal/rerank"
 	"github.com/hurttlocker/cortex/internal/search"
 	"github.com/hurttlocker/cortex/internal/store"
+	"github.com/hurttlocker/cortex/internal/timings"
+	"github.com/hurttlocker/cortex/internal/tokens"
 	"github.com/mark3labs/mcp-go/server"
 	"gopkg.in/yaml.v3"
 )
@@ -61,6 +64,13 @@ func main() {
 		os.Exit(0)
 	}
 
+	startCommand(args[0])
+	if globalWorkspaces != "" {
+		exitWithError(checkWorkspaceList(args[0]))
+	} else if args[0] != "workspace" {
+		exitWithError(checkWorkspaceSelection())
+	}
+	exitWithError(checkNamespace())
 	switch args[0] {
 	case "import":
 		exitWithError(runImport(args[1:]))
@@ -70,6 +80,10 @@ func main() {
 		exitWithError(runClassify(args[1:]))
 	case "summarize":
 		exitWithError(runSummarize(args[1:]))
+	case "brief":
+		exitWithError(runBrief(args[1:]))
+	case "translate":
+		exitWithError(runTranslate(args[1:]))
 	case "embed":
 		exitWithError(runEmbed(args[1:]))
 	case "embed-source":
@@ -104,6 +118,28 @@ func main() {
 		exitWithError(runList(args[1:]))
 	case "export":
 		exitWithError(runExport(args[1:]))
+	case "diff":
+		exitWithError(runDiff(args[1:]))
+	case "backup":
+		exitWithError(runBackup(args[1:]))
+	case "show":
+		exitWithError(runShow(args[1:]))
+	case "document", "documents", "doc":
+		exitWithError(runDocument(args[1:]))
+	case "capture":
+		exitWithError(runCapture(args[1:]))
+	case "namespace":
+		exitWithError(runNamespace(args[1:]))
+	case "watch":
+		exitWithError(runWatch(args[1:]))
+	case "decay":
+		exitWithError(runDecay(args[1:]))
+	case "triage":
+		exitWithError(runTriage(args[1:]))
+	case "meta":
+		exitWithError(runMeta(args[1:]))
+	case "class":
+		exitWithError(runClass(args[1:]))
 	case "stale":
 		exitWithError(runStale(args[1:]))
 	case "conflicts":
@@ -112,6 +148,10 @@ func main() {
 		exitWithError(runReinforce(args[1:]))
 	case "supersede":
 		exitWithError(runSupersede(args[1:]))
+	case "delete":
+		exitWithError(runDelete(args[1:]))
+	case "forget":
+		exitWithError(runForget(args[1:]))
 	case "directive":
 		exitWithError(runDirective(args[1:]))
 	case "propose":
@@ -120,6 +160,16 @@ func main() {
 		exitWithError(runFactCommand(args[1:]))
 	case "fact-history":
 		exitWithError(runFactHistory(args[1:]))
+	case "seed":
+		exitWithError(runSeed(args[1:]))
+	case "note":
+		exitWithError(runNote(args[1:]))
+	case "review":
+		exitWithError(runReview(args[1:]))
+	case "jobs":
+		exitWithError(runJobs(args[1:]))
+	case "normalize":
+		exitWithError(runNormalize(args[1:]))
 	case "edge":
 		exitWithError(runEdge(args[1:]))
 	case "graph":
@@ -136,6 +186,10 @@ func main() {
 		exitWithError(runRefreshSource(args[1:]))
 	case "cleanup":
 		exitWithError(runCleanup(args[1:]))
+	case "tier":
+		exitWithError(runTier(args[1:]))
+	case "compact":
+		exitWithError(runCompact(args[1:]))
 	case "optimize":
 		exitWithError(runOptimize(args[1:]))
 	case "backfill-scope":
@@ -144,8 +198,12 @@ func main() {
 		exitWithError(runSuppress(args[1:]))
 	case "source-weight":
 		exitWithError(runSourceWeight(args[1:]))
+	case "sources":
+		exitWithError(runSources(args[1:]))
 	case "projects":
 		exitWithError(runProjects(args[1:]))
+	case "archive":
+		exitWithError(runArchive(args[1:]))
 	case "agents":
 		exitWithError(runAgents(args[1:]))
 	case "entity":
@@ -154,8 +212,20 @@ func main() {
 		exitWithError(runTag(args[1:]))
 	case "reason":
 		exitWithError(runReason(args[1:]))
+	case "chat":
+		exitWithError(runChat(args[1:]))
 	case "ledger":
 		exitWithError(runLedger(args[1:]))
+	case "telemetry":
+		exitWithError(runTelemetry(args[1:]))
+	case "prompts":
+		exitWithError(runPrompts(args[1:]))
+	case "tokens":
+		exitWithError(runTokens(args[1:]))
+	case "examples":
+		exitWithError(runExamples(args[1:]))
+	case "workspace":
+		exitWithError(runWorkspace(args[1:]))
 	case "bench":
 		exitWithError(runBench(args[1:]))
 	case "eval":
@@ -174,6 +244,10 @@ func main() {
 		exitWithError(runCompletion(args[1:]))
 	case "mcp":
 		exitWithError(runMCP(args[1:]))
+	case "daemon":
+		exitWithError(runDaemon(args[1:]))
+	case "serve":
+		exitWithError(runServe(args[1:]))
 	case "version":
 		fmt.Printf("cortex %s\n", version)
 	case "--version", "-v":
@@ -187,6 +261,7 @@ func main() {
 		printUsage()
 		os.Exit(1)
 	}
+	finishCommand()
 }
 
 func exitWithError(err error) {
@@ -197,6 +272,7 @@ func exitWithError(err error) {
 	if hint := remediationHint(err); hint != "" {
 		fmt.Fprintf(os.Stderr, "Hint: %s\n", hint)
 	}
+	finishCommand()
 	os.Exit(1)
 }
 
@@ -256,9 +332,11 @@ func remediationHint(err error) string {
 
 	// LLM provider errors
 	case strings.Contains(msg, "unknown llm provider"):
-		return "Supported LLM providers: google, openrouter. Example: `--llm google/gemini-3-flash`"
+		return "Supported LLM providers: google, openrouter, or `--llm auto` for budget-aware routing. Example: `--llm google/gemini-3-flash`"
 	case strings.Contains(msg, "invalid --llm format"):
-		return "Use format: `--llm provider/model` (e.g., `--llm google/gemini-3-flash`)."
+		return "Use format: `--llm provider/model` (e.g., `--llm google/gemini-3-flash`) or `--llm auto`."
+	case strings.Contains(msg, "daily llm budget"):
+		return "Check today's spend with `cortex telemetry report --since 24h`."
 	case strings.Contains(msg, "rate limit"),
 		strings.Contains(msg, "429"):
 		return "API rate limit hit. Wait a moment and retry, or switch to a different provider."
@@ -305,10 +383,23 @@ func parseGlobalFlags(args []string) []string {
 			i++ // skip the value
 		case strings.HasPrefix(args[i], "--db="):
 			globalDBPath = strings.TrimPrefix(args[i], "--db=")
+		case args[i] == "--workspace" && i+1 < len(args):
+			setGlobalWorkspace(args[i+1])
+			i++
+		case strings.HasPrefix(args[i], "--workspace="):
+			setGlobalWorkspace(strings.TrimPrefix(args[i], "--workspace="))
+		case args[i] == "--namespace" && i+1 < len(args):
+			os.Setenv("CORTEX_NAMESPACE", args[i+1])
+			i++
+		case strings.HasPrefix(args[i], "--namespace="):
+			os.Setenv("CORTEX_NAMESPACE", strings.TrimPrefix(args[i], "--namespace="))
 		case args[i] == "--verbose" || args[i] == "-v":
 			globalVerbose = true
 		case args[i] == "--read-only" || args[i] == "--readonly":
 			globalReadOnly = true
+		case args[i] == "--timings":
+			globalTimings = true
+			timings.EnableDB()
 		case strings.HasPrefix(args[i], "-"):
 			// Skip unknown flags but keep them for subcommand processing
 			filtered = append(filtered, args[i])
@@ -317,11 +408,26 @@ func parseGlobalFlags(args []string) []string {
 		}
 	}
 
+	if globalTimings {
+		globalTimingsJSON = wantsJSONOutput(filtered)
+	}
 	return filtered
 }
 
+// setGlobalWorkspace records --workspace. A single name is exported so every
+// config resolution (and child processes such as MCP servers) sees the same
+// workspace; a list is kept for federated search.
+func setGlobalWorkspace(value string) {
+	if isWorkspaceList(value) {
+		globalWorkspaces = value
+		return
+	}
+	globalWorkspaces = ""
+	os.Setenv("CORTEX_WORKSPACE", value)
+}
+
 // getDBPath returns the database path using the resolution order:
-// config.yaml < env vars < --db
+// config.yaml < active workspace < env vars < --workspace < --db
 func getDBPath() string {
 	resolved, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{CLIDBPath: globalDBPath})
 	if err == nil && strings.TrimSpace(resolved.DBPath.Value) != "" {
@@ -339,11 +445,30 @@ func getDBPath() string {
 	return "" // Let store.NewStore use its default
 }
 
-// getStoreConfig returns a StoreConfig with the global DB path and read-only flag.
+// getStoreConfig returns a StoreConfig with the global DB path, read-only
+// flag, and namespace.
 func getStoreConfig() store.StoreConfig {
-	return store.StoreConfig{DBPath: getDBPath(), ReadOnly: globalReadOnly}
+	return store.StoreConfig{DBPath: getDBPath(), ReadOnly: globalReadOnly, Namespace: getNamespace()}
 }
 
+// getNamespace returns the active namespace from --namespace or
+// CORTEX_NAMESPACE. --namespace is exported like --workspace so child
+// processes (MCP servers, hooks) stay in the same namespace.
+func getNamespace() string {
+	return strings.TrimSpace(os.Getenv("CORTEX_NAMESPACE"))
+}
+
+func checkNamespace() error {
+	if err := store.ValidateNamespace(getNamespace()); err != nil {
+		return fmt.Errorf("--namespace / CORTEX_NAMESPACE: %w", err)
+	}
+	return nil
+}
+
+// enrichReviewThreshold is extract.review_threshold: LLM-enriched facts
+// below it are queued for `cortex review` rather than stored.
+var enrichReviewThreshold = cfgresolver.DefaultReviewThreshold
+
 func applyExtractionRuntimeConfig(resolved cfgresolver.ResolvedConfig) {
 	extract.DecayRates = extract.CloneDecayRates(extract.BaseDecayRates)
 	if len(resolved.Policies.DecayRates) > 0 {
@@ -353,6 +478,13 @@ func applyExtractionRuntimeConfig(resolved cfgresolver.ResolvedConfig) {
 	}
 	ingest.SetConfiguredFactSuppressions(resolved.Extract.SuppressPatterns)
 	store.SetPredicatePolicies(resolved.Policies.PredicatePolicies)
+	store.SetPredicateAliases(resolved.Policies.PredicateAliases)
+	extract.SetTaxonomy(resolved.Extract.Taxonomy)
+	store.SetCustomFactTypes(extract.CustomTypeBases())
+	enrichReviewThreshold = resolved.Extract.EffectiveReviewThreshold()
+	if err := loadUserTagRules(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring %s: %v\n", store.TagRulesFileName, err)
+	}
 }
 
 func setMetaTimestamp(ctx context.Context, ss *store.SQLiteStore, key string, at time.Time) error {
@@ -403,6 +535,15 @@ func wireWebhook(s store.Store) {
 	}
 }
 
+// flushWebhook delivers alerts still batched on s's webhook. Short-lived
+// commands defer it so alerts raised on the way out, such as subject
+// subscription notifications, are not lost.
+func flushWebhook(s store.Store) {
+	if ss, ok := s.(*store.SQLiteStore); ok && ss.Webhook != nil {
+		ss.Webhook.FlushWait()
+	}
+}
+
 // getHNSWPath returns the path for the persisted HNSW index file.
 // By default this is ~/.cortex/hnsw.idx. If --db / CORTEX_DB is set,
 // the index is stored alongside that database file.
@@ -441,16 +582,26 @@ func boolToInt(v bool) int {
 
 func runImport(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: cortex import <path> [--recursive] [--dry-run] [--extract] [--no-enrich] [--no-classify] [--include .md,.txt] [--exclude .go,.js] [--project <name>] [--class <class>] [--auto-tag] [--metadata <json>] [--capture-dedupe] [--import-quality-gate] [--llm <provider/model>] [--embed <provider/model>]")
+		return fmt.Errorf("usage: cortex import <path> [--recursive] [--dry-run] [--extract] [--no-enrich] [--async-enrich] [--no-classify] [--no-skip-list] [--include .md,.txt] [--exclude .go,.js] [--project <name>] [--class <class>] [--auto-tag] [--metadata <json>] [--capture-dedupe] [--import-quality-gate] [--triage] [--triage-threshold <0-1>] [--max-file-size <size>] [--oversized skip|head|tail|sample] [--workers N] [--rescan] [--llm <provider/model>] [--embed <provider/model>]\n       cortex import <export.jsonl> --format jsonl [--dry-run] [--id-map <file.csv>]")
+	}
+	if format, rest, found, err := splitImportFormat(args); err != nil {
+		return err
+	} else if found {
+		if format != "jsonl" {
+			return fmt.Errorf("unsupported import format: %s (supported: jsonl)", format)
+		}
+		return runImportJSONL(rest)
 	}
 
 	// Parse flags
 	var paths []string
-	opts := ingest.ImportOptions{}
+	opts := ingest.ImportOptions{Workers: ingest.DefaultImportWorkers}
 	enableExtraction := false
 	enableEnrichment := true // #227: enrichment on by default when extracting
+	asyncEnrich := false
 	noClassify := false
 	noInfer := false
+	noSkipList := false
 	llmFlag := ""
 	embedFlag := ""
 	projectFlag := ""
@@ -461,6 +612,8 @@ func runImport(args []string) error {
 	excludeExts := ""
 	captureDedupe := false
 	importQualityGate := false
+	triage := false
+	triageThreshold := extract.DefaultTriageThreshold
 	similarityThreshold := 0.95
 	dedupeWindowSec := 300
 	captureLowSignal := false
@@ -473,6 +626,8 @@ func runImport(args []string) error {
 			opts.Recursive = true
 		case args[i] == "--dry-run" || args[i] == "-n":
 			opts.DryRun = true
+		case args[i] == "--rescan":
+			opts.Rescan = true
 		case args[i] == "--extract":
 			enableExtraction = true
 		case args[i] == "--enrich":
@@ -480,6 +635,12 @@ func runImport(args []string) error {
 			enableExtraction = true // --enrich implies --extract
 		case args[i] == "--no-enrich":
 			enableEnrichment = false
+		case args[i] == "--async-enrich":
+			asyncEnrich = true
+			enableEnrichment = true
+			enableExtraction = true
+		case args[i] == "--no-skip-list":
+			noSkipList = true
 		case args[i] == "--no-classify":
 			noClassify = true
 		case args[i] == "--no-infer":
@@ -505,6 +666,23 @@ func runImport(args []string) error {
 			captureDedupe = true
 		case args[i] == "--import-quality-gate":
 			importQualityGate = true
+		case args[i] == "--triage":
+			triage = true
+		case args[i] == "--triage-threshold" && i+1 < len(args):
+			i++
+			f, err := strconv.ParseFloat(args[i], 64)
+			if err != nil {
+				return fmt.Errorf("invalid --triage-threshold value: %s", args[i])
+			}
+			triageThreshold = f
+			triage = true
+		case strings.HasPrefix(args[i], "--triage-threshold="):
+			f, err := strconv.ParseFloat(strings.TrimPrefix(args[i], "--triage-threshold="), 64)
+			if err != nil {
+				return fmt.Errorf("invalid --triage-threshold value: %s", args[i])
+			}
+			triageThreshold = f
+			triage = true
 		case args[i] == "--similarity-threshold" && i+1 < len(args):
 			i++
 			f, err := strconv.ParseFloat(args[i], 64)
@@ -571,12 +749,46 @@ func runImport(args []string) error {
 			excludeExts = args[i]
 		case strings.HasPrefix(args[i], "--exclude="):
 			excludeExts = strings.TrimPrefix(args[i], "--exclude=")
+		case args[i] == "--max-file-size" && i+1 < len(args):
+			i++
+			n, err := ingest.ParseFileSize(args[i])
+			if err != nil {
+				return fmt.Errorf("invalid --max-file-size: %w", err)
+			}
+			opts.MaxFileSize = n
+		case strings.HasPrefix(args[i], "--max-file-size="):
+			n, err := ingest.ParseFileSize(strings.TrimPrefix(args[i], "--max-file-size="))
+			if err != nil {
+				return fmt.Errorf("invalid --max-file-size: %w", err)
+			}
+			opts.MaxFileSize = n
+		case args[i] == "--oversized" && i+1 < len(args):
+			i++
+			opts.Oversized = args[i]
+		case strings.HasPrefix(args[i], "--oversized="):
+			opts.Oversized = strings.TrimPrefix(args[i], "--oversized=")
+		case args[i] == "--workers" && i+1 < len(args):
+			i++
+			n, err := parseImportWorkers(args[i])
+			if err != nil {
+				return err
+			}
+			opts.Workers = n
+		case strings.HasPrefix(args[i], "--workers="):
+			n, err := parseImportWorkers(strings.TrimPrefix(args[i], "--workers="))
+			if err != nil {
+				return err
+			}
+			opts.Workers = n
 		case strings.HasPrefix(args[i], "-"):
 			return fmt.Errorf("unknown flag: %s", args[i])
 		default:
 			paths = append(paths, args[i])
 		}
 	}
+	if !ingest.ValidOversizedMode(opts.Oversized) {
+		return fmt.Errorf("invalid --oversized value %q (valid: skip, head, tail, sample)", opts.Oversized)
+	}
 
 	// Parse comma-separated include/exclude extensions
 	if includeExts != "" {
@@ -605,6 +817,9 @@ func runImport(args []string) error {
 	if captureMinChars <= 0 {
 		return fmt.Errorf("--capture-min-chars must be > 0")
 	}
+	if triageThreshold <= 0 || triageThreshold > 1 {
+		return fmt.Errorf("--triage-threshold must be between 0 and 1")
+	}
 
 	// Set project on import options
 	opts.Project = projectFlag
@@ -658,6 +873,7 @@ func runImport(args []string) error {
 	}
 	defer s.Close()
 	wireWebhook(s)
+	defer flushWebhook(s)
 
 	engine := ingest.NewEngine(s)
 	if importQualityGate {
@@ -667,6 +883,20 @@ func runImport(args []string) error {
 		}
 		opts.ImportKeepDropGate = gate
 	}
+	if triage {
+		triageLLM := llmFlag
+		if triageLLM == "" {
+			triageLLM = resolvedCfg.EffectiveLLMModel("classify", extract.DefaultClassifyModel).Value
+			if triageLLM == "" {
+				triageLLM = extract.DefaultClassifyModel
+			}
+		}
+		provider, err := tryCreateProvider(triageLLM)
+		if err != nil {
+			return fmt.Errorf("configuring import triage: %w", err)
+		}
+		opts.Triage = &ingest.ImportTriage{Provider: provider, Threshold: triageThreshold, Model: triageLLM}
+	}
 	ctx := context.Background()
 	importStart := time.Now()
 	totalFactsExtracted := 0
@@ -714,7 +944,7 @@ func runImport(args []string) error {
 	if enableExtraction && !opts.DryRun && totalResult.MemoriesNew > 0 {
 		fmt.Println("\nRunning extraction...")
 		var lastExtractProgress time.Time
-		extractionStats, err := runExtractionOnImportedMemories(ctx, s, llmFlag, totalResult.NewMemoryIDs, func(done, total, facts int) {
+		extractionStats, err := runExtractionOnImportedMemories(ctx, s, llmFlag, totalResult.NewMemoryIDs, !noSkipList, func(done, total, facts int) {
 			now := time.Now()
 			if done < total && now.Sub(lastExtractProgress) < 500*time.Millisecond {
 				return
@@ -733,13 +963,21 @@ func runImport(args []string) error {
 			} else {
 				fmt.Printf("  Facts extracted: %d (rules only)\n", extractionStats.FactsExtracted)
 			}
+			printSkipListNotice(extractionStats.Skipped)
 			totalFactsExtracted += extractionStats.FactsExtracted
 		}
 
 		// Run LLM enrichment (default when extracting, skip with --no-enrich)
 		// Graceful degradation: if no API key, skip silently with one-line notice.
 		llmAvailable := enableEnrichment
-		if enableEnrichment && extractionStats != nil {
+		if asyncEnrich && extractionStats != nil {
+			// Hand enrichment to a job runner so import returns after the
+			// rules; classification waits for `cortex classify` too.
+			llmAvailable = false
+			if err := queueImportEnrichment(ctx, s, totalResult.NewMemoryIDs, !noSkipList); err != nil {
+				fmt.Fprintf(os.Stderr, "  Enrichment queue error: %v\n", err)
+			}
+		} else if enableEnrichment && extractionStats != nil {
 			enrichLLM := llmFlag
 			if enrichLLM == "" {
 				if resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{}); err == nil {
@@ -755,14 +993,18 @@ func runImport(args []string) error {
 				llmAvailable = false
 			} else {
 				fmt.Println("\nRunning LLM enrichment...")
-				enrichStats, err := runEnrichmentOnImportedMemories(ctx, s, enrichLLM, totalResult.NewMemoryIDs)
+				enrichStats, err := runEnrichmentOnImportedMemories(ctx, s, enrichLLM, totalResult.NewMemoryIDs, !noSkipList)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "  Enrichment error: %v\n", err)
-				} else if enrichStats.NewFacts > 0 {
-					fmt.Printf("  🧠 Enrichment: +%d new facts from LLM (%.1fs avg latency)\n",
-						enrichStats.NewFacts, enrichStats.AvgLatency.Seconds())
 				} else {
-					fmt.Println("  🧠 Enrichment: LLM found no additional facts")
+					if enrichStats.NewFacts > 0 {
+						fmt.Printf("  🧠 Enrichment: +%d new facts from LLM (%.1fs avg latency)\n",
+							enrichStats.NewFacts, enrichStats.AvgLatency.Seconds())
+					} else if enrichStats.Queued == 0 {
+						fmt.Println("  🧠 Enrichment: LLM found no additional facts")
+					}
+					printEnrichReviewNotice(enrichStats)
+					printSkipListNotice(enrichStats.Skipped)
 				}
 			}
 		}
@@ -827,6 +1069,16 @@ func runImport(args []string) error {
 		}
 	}
 
+	if !opts.DryRun && totalResult.MemoriesNew > 0 {
+		if ss, ok := s.(*store.SQLiteStore); ok {
+			if line, err := autoTier(ctx, ss); err != nil {
+				fmt.Fprintf(os.Stderr, "  Storage tiering error: %v\n", err)
+			} else if line != "" {
+				fmt.Fprintf(os.Stderr, "  Storage %s\n", line)
+			}
+		}
+	}
+
 	// Newly imported memories are implicitly queued for embedding because they have
 	// no embedding row yet. Start or reuse the detached worker to process them
 	// asynchronously so import stays non-blocking.
@@ -861,7 +1113,7 @@ func runImport(args []string) error {
 func runSearch(args []string) error {
 	// Parse flags and query
 	var queryParts []string
-	mode := "keyword"
+	mode := "auto"
 	limit := 10
 	limitExplicit := false
 	budget := 0
@@ -882,16 +1134,28 @@ func runSearch(args []string) error {
 	sourceFlag := ""
 	intentFlag := "all"
 	sourceBoostFlags := []string{}
+	graphBoostFlag := -1.0 // -1 = search.graph_boost from config
 	scopeFlags := []string{}
 	showMetadata := false
 	explain := false
+	dumpPath := ""
 	includeSuperseded := false
 	dedupe := true
 	factMode := false
+	expandSummaries := false
 	entityGraph := false
 	expandFlag := false
 	llmFlag := ""
+	profileFlag := ""
+	groupByFlag := ""
+	offset := 0
+	cursorFlag := ""
+	countFlag := false
+	modeExplicit := false
 	rerankMode := rerank.ModeAuto
+	highlightFlag := false
+	highlightStyle := ""
+	snippetTokens := 0
 
 	for i := 0; i < len(args); i++ {
 		switch {
@@ -947,6 +1211,19 @@ func runSearch(args []string) error {
 			sourceBoostFlags = append(sourceBoostFlags, args[i])
 		case strings.HasPrefix(args[i], "--source-boost="):
 			sourceBoostFlags = append(sourceBoostFlags, strings.TrimPrefix(args[i], "--source-boost="))
+		case args[i] == "--graph-boost" && i+1 < len(args):
+			i++
+			w, err := parseGraphBoostArg(args[i])
+			if err != nil {
+				return err
+			}
+			graphBoostFlag = w
+		case strings.HasPrefix(args[i], "--graph-boost="):
+			w, err := parseGraphBoostArg(strings.TrimPrefix(args[i], "--graph-boost="))
+			if err != nil {
+				return err
+			}
+			graphBoostFlag = w
 		case args[i] == "--scope" && i+1 < len(args):
 			i++
 			scopeFlags = append(scopeFlags, args[i])
@@ -971,10 +1248,37 @@ func runSearch(args []string) error {
 			boostSessionKeyFlag = strings.TrimPrefix(args[i], "--boost-session-key=")
 		case args[i] == "--explain":
 			explain = true
+		case args[i] == "--dump" && i+1 < len(args):
+			i++
+			dumpPath = args[i]
+		case strings.HasPrefix(args[i], "--dump="):
+			dumpPath = strings.TrimPrefix(args[i], "--dump=")
 		case args[i] == "--include-superseded":
 			includeSuperseded = true
+		case args[i] == "--highlight":
+			highlightFlag = true
+		case strings.HasPrefix(args[i], "--highlight="):
+			highlightFlag = true
+			highlightStyle = strings.TrimPrefix(args[i], "--highlight=")
+		case args[i] == "--snippet-tokens" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil {
+				return fmt.Errorf("invalid --snippet-tokens value: %s", args[i])
+			}
+			snippetTokens = n
+			highlightFlag = true
+		case strings.HasPrefix(args[i], "--snippet-tokens="):
+			n, err := strconv.Atoi(strings.TrimPrefix(args[i], "--snippet-tokens="))
+			if err != nil {
+				return fmt.Errorf("invalid --snippet-tokens value: %s", args[i])
+			}
+			snippetTokens = n
+			highlightFlag = true
 		case args[i] == "--facts":
 			factMode = true
+		case args[i] == "--expand-summaries":
+			expandSummaries = true
 		case args[i] == "--entity-graph":
 			entityGraph = true
 		case args[i] == "--dedupe":
@@ -991,8 +1295,20 @@ func runSearch(args []string) error {
 		case args[i] == "--mode" && i+1 < len(args):
 			i++
 			mode = args[i]
+			modeExplicit = true
 		case strings.HasPrefix(args[i], "--mode="):
 			mode = strings.TrimPrefix(args[i], "--mode=")
+			modeExplicit = true
+		case args[i] == "--group-by" && i+1 < len(args):
+			i++
+			groupByFlag = args[i]
+		case strings.HasPrefix(args[i], "--group-by="):
+			groupByFlag = strings.TrimPrefix(args[i], "--group-by=")
+		case args[i] == "--profile" && i+1 < len(args):
+			i++
+			profileFlag = args[i]
+		case strings.HasPrefix(args[i], "--profile="):
+			profileFlag = strings.TrimPrefix(args[i], "--profile=")
 		case args[i] == "--limit" && i+1 < len(args):
 			i++
 			n, err := strconv.Atoi(args[i])
@@ -1021,6 +1337,26 @@ func runSearch(args []string) error {
 				return fmt.Errorf("invalid --budget value: %s", args[i])
 			}
 			budget = n
+		case args[i] == "--offset" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid --offset value: %s", args[i])
+			}
+			offset = n
+		case strings.HasPrefix(args[i], "--offset="):
+			n, err := strconv.Atoi(strings.TrimPrefix(args[i], "--offset="))
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid --offset value: %s", args[i])
+			}
+			offset = n
+		case args[i] == "--cursor" && i+1 < len(args):
+			i++
+			cursorFlag = args[i]
+		case strings.HasPrefix(args[i], "--cursor="):
+			cursorFlag = strings.TrimPrefix(args[i], "--cursor=")
+		case args[i] == "--count":
+			countFlag = true
 		case (args[i] == "--min-score" || args[i] == "--min-confidence") && i+1 < len(args):
 			i++
 			f, err := strconv.ParseFloat(args[i], 64)
@@ -1071,7 +1407,7 @@ func runSearch(args []string) error {
 
 	query := strings.Join(queryParts, " ")
 	if query == "" {
-		return fmt.Errorf("usage: cortex search <query> [--mode keyword|semantic|hybrid|rrf] [--limit N] [--budget N] [--facts] [--entity-graph] [--embed <provider/model>] [--rerank[=auto|on|off]] [--expand] [--llm <provider/model>] [--class rule,decision] [--no-class-boost] [--include-superseded] [--dedupe|--no-dedupe] [--explain] [--json] [--agent <id>] [--channel <name>] [--session-key <key>] [--scope agent:<id>|entity:<id>|session:<id>|project:<id>] [--boost-agent <id>] [--boost-channel <name>] [--boost-session-key <key>] [--source <provider>] [--intent memory|import|connector|all] [--source-boost <prefix[:weight]>] [--after YYYY-MM-DD] [--before YYYY-MM-DD] [--show-metadata]")
+		return fmt.Errorf("usage: cortex search <query> [--mode auto|keyword|semantic|hybrid|rrf] [--profile <name>] [--group-by source|project|class] [--limit N] [--offset N|--cursor C] [--count] [--budget N] [--facts [--expand-summaries]] [--entity-graph] [--embed <provider/model>] [--rerank[=auto|on|off]] [--expand] [--llm <provider/model>] [--class rule,decision] [--no-class-boost] [--include-superseded] [--dedupe|--no-dedupe] [--highlight[=mark|ansi|plain]] [--snippet-tokens N] [--explain [--dump <file.jsonl>]] [--json] [--agent <id>] [--channel <name>] [--session-key <key>] [--scope agent:<id>|entity:<id>|session:<id>|project:<id>] [--boost-agent <id>] [--boost-channel <name>] [--boost-session-key <key>] [--source <source|type>] [--intent memory|import|connector|all] [--source-boost <prefix[:weight]>] [--graph-boost <0-1>] [--after YYYY-MM-DD] [--before YYYY-MM-DD] [--show-metadata]")
 	}
 	if limit < 1 || limit > 1000 {
 		return fmt.Errorf("--limit must be between 1 and 1000")
@@ -1079,19 +1415,37 @@ func runSearch(args []string) error {
 	if budget < 0 {
 		return fmt.Errorf("--budget must be >= 0")
 	}
-
-	searchMode, err := search.ParseMode(mode)
-	if err != nil {
-		return err
+	var groupBy search.GroupBy
+	if strings.TrimSpace(groupByFlag) != "" {
+		parsed, err := search.ParseGroupBy(groupByFlag)
+		if err != nil {
+			return err
+		}
+		groupBy = parsed
+		if budget > 0 || factMode {
+			return fmt.Errorf("--group-by cannot be combined with --budget or --facts")
+		}
 	}
-
-	searchLimit := limit
-	if budget > 0 {
-		if !limitExplicit {
-			searchLimit = 50
-		} else if searchLimit < 50 {
-			searchLimit = 50
+	if expandSummaries && !factMode {
+		return fmt.Errorf("--expand-summaries requires --facts")
+	}
+	paged := offset > 0 || strings.TrimSpace(cursorFlag) != ""
+	if paged && (groupBy != "" || budget > 0 || factMode || expandFlag) {
+		return fmt.Errorf("--offset/--cursor cannot be combined with --group-by, --budget, --facts, or --expand")
+	}
+	if countFlag && (groupBy != "" || budget > 0 || factMode || expandFlag) {
+		return fmt.Errorf("--count cannot be combined with --group-by, --budget, --facts, or --expand")
+	}
+	if strings.TrimSpace(dumpPath) != "" {
+		if !explain {
+			return fmt.Errorf("--dump requires --explain")
 		}
+		if factMode || expandFlag {
+			return fmt.Errorf("--dump cannot be combined with --facts or --expand")
+		}
+	}
+	if globalWorkspaces != "" && (groupBy != "" || budget > 0 || factMode || expandFlag || paged || countFlag || dumpPath != "") {
+		return fmt.Errorf("searching several workspaces cannot be combined with --group-by, --budget, --facts, --expand, --offset/--cursor, --count, or --dump")
 	}
 
 	resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
@@ -1099,19 +1453,40 @@ func runSearch(args []string) error {
 		return fmt.Errorf("loading config: %w", err)
 	}
 
-	// Open store
-	s, err := store.NewStore(getStoreConfig())
-	if err != nil {
-		return fmt.Errorf("opening store: %w", err)
+	var profile *cfgresolver.SearchProfile
+	if strings.TrimSpace(profileFlag) != "" {
+		p, err := search.LookupProfile(resolvedCfg.Search.Profiles, profileFlag)
+		if err != nil {
+			return err
+		}
+		profile = &p
+		if !modeExplicit && strings.TrimSpace(p.Mode) != "" {
+			mode = p.Mode
+		}
 	}
-	defer s.Close()
 
-	engine, err := newSearchEngineForMode(s, searchMode, embedFlag)
+	searchMode, err := search.ParseMode(mode)
 	if err != nil {
 		return err
 	}
-	if err := configureSearchReranker(engine, rerankMode, true); err != nil {
-		return err
+
+	searchLimit := limit
+	if groupBy != "" {
+		// --limit counts groups; fetch enough hits to fill them.
+		searchLimit = limit * search.GroupCandidateMultiplier
+		if searchLimit < 50 {
+			searchLimit = 50
+		}
+		if searchLimit > 1000 {
+			searchLimit = 1000
+		}
+	}
+	if budget > 0 {
+		if !limitExplicit {
+			searchLimit = 50
+		} else if searchLimit < 50 {
+			searchLimit = 50
+		}
 	}
 
 	ctx := context.Background()
@@ -1143,6 +1518,10 @@ func runSearch(args []string) error {
 	if err != nil {
 		return err
 	}
+	graphBoost := resolvedCfg.Search.GraphBoost
+	if graphBoostFlag >= 0 {
+		graphBoost = graphBoostFlag
+	}
 
 	opts := search.Options{
 		Mode:              searchMode,
@@ -1161,13 +1540,54 @@ func runSearch(args []string) error {
 		Intent:            intentFlag,
 		Scope:             scopeFilters,
 		SourceBoosts:      parsedSourceBoosts,
+		Freshness:         search.FreshnessFromConfig(resolvedCfg.Search.Freshness),
+		GraphBoost:        graphBoost,
 		IncludeSuperseded: includeSuperseded,
+		ExpandSummaries:   expandSummaries,
 		Explain:           explain,
 		DisableDedupe:     !dedupe,
 		BoostAgent:        boostAgentFlag,
 		BoostChannel:      boostChannelFlag,
 		BoostSessionKey:   boostSessionKeyFlag,
 		RerankMode:        rerankMode,
+		Offset:            offset,
+		Cursor:            strings.TrimSpace(cursorFlag),
+	}
+	if highlightFlag {
+		if opts.Highlight, err = resolveSearchHighlight(highlightStyle, snippetTokens, jsonOutput || !isTTY()); err != nil {
+			return err
+		}
+	}
+	if profile != nil {
+		if err := search.ApplyProfile(&opts, *profile); err != nil {
+			return fmt.Errorf("search profile %q: %w", profileFlag, err)
+		}
+	}
+
+	if globalWorkspaces != "" {
+		return runFederatedSearch(ctx, query, opts, federatedSearchFlags{
+			embed:       embedFlag,
+			rerankMode:  rerankMode,
+			jsonOutput:  jsonOutput,
+			showMeta:    showMetadata,
+			explain:     explain,
+			displayMode: searchMode,
+		})
+	}
+
+	// Open store
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+
+	engine, err := newSearchEngineForMode(s, searchMode, embedFlag)
+	if err != nil {
+		return err
+	}
+	if err := configureSearchReranker(engine, rerankMode, true); err != nil {
+		return err
 	}
 
 	if factMode {
@@ -1236,11 +1656,23 @@ func runSearch(args []string) error {
 		results = mergeExpandedResults(allResults, opts.Limit)
 	} else {
 		// Normal search (no expansion or expansion returned only original)
+		var trace *search.Trace
+		if dumpPath != "" {
+			trace = search.NewTrace()
+			opts.Trace = trace
+		}
 		var err error
 		results, err = engine.Search(ctx, query, opts)
 		if err != nil {
 			return err
 		}
+		if trace != nil {
+			if err := writeSearchTraceDump(dumpPath, trace); err != nil {
+				return err
+			}
+			// Keep the trace out of the follow-up diagnosis and count queries.
+			opts.Trace = nil
+		}
 	}
 
 	candidateCount := len(results)
@@ -1265,13 +1697,51 @@ func runSearch(args []string) error {
 		}
 	}
 
+	if groupBy != "" {
+		if jsonOutput || !isTTY() {
+			enriched := enrichSearchResultsWithFactIDs(ctx, s, results, includeSuperseded)
+			return outputGroupedJSON(search.GroupResults(enriched, groupBy, limit))
+		}
+		return outputTTYGroupedSearch(query, search.GroupResults(results, groupBy, limit), groupBy, len(results))
+	}
+
+	var diagnosis *search.NoResultDiagnosis
+	if explain && len(results) == 0 {
+		if diagnosis, err = engine.DiagnoseNoResults(ctx, query, opts); err != nil {
+			return fmt.Errorf("diagnosing empty search: %w", err)
+		}
+	}
+
+	totalMatches, totalEstimated := 0, false
+	if countFlag {
+		if totalMatches, totalEstimated, err = engine.CountMatches(ctx, query, opts); err != nil {
+			return fmt.Errorf("counting matches: %w", err)
+		}
+	}
+
 	// Determine output format
 	if jsonOutput || !isTTY() {
 		enriched := enrichSearchResultsWithFactIDs(ctx, s, results, includeSuperseded)
+		if countFlag {
+			if enriched == nil {
+				enriched = []search.Result{}
+			}
+			stripSnippetHighlights(enriched)
+			return outputCountedJSON(enriched, len(enriched), totalMatches, totalEstimated, opts.NextCursor(results))
+		}
 		if budget > 0 {
 			return outputBudgetJSON(query, searchMode, budget, packedTokens, candidateCount, enriched)
 		}
-		return outputJSON(enriched)
+		if err := outputJSON(enriched); err != nil {
+			return err
+		}
+		if diagnosis != nil {
+			printNoResultDiagnosis(os.Stderr, diagnosis)
+		}
+		if len(expandedQueries) <= 1 {
+			printNextCursorHint("cortex search "+strconv.Quote(query), opts.NextCursor(results), true)
+		}
+		return nil
 	}
 
 	// Show expanded queries header in TTY mode
@@ -1287,15 +1757,46 @@ func runSearch(args []string) error {
 		fmt.Println()
 	}
 
-	if err := outputTTYSearch(query, results, showMetadata, explain, searchMode); err != nil {
+	if err := outputTTYSearch(query, results, showMetadata, explain, searchMode, highlightFlag); err != nil {
 		return err
 	}
+	if diagnosis != nil {
+		printNoResultDiagnosis(os.Stdout, diagnosis)
+	}
 	if budget > 0 && len(results) > 0 {
 		fmt.Printf("Packed %d / %d estimated tokens\n", packedTokens, budget)
 	}
+	if countFlag {
+		printCountFooter("results", len(results), totalMatches, totalEstimated)
+	}
+	if budget == 0 && len(expandedQueries) <= 1 {
+		printNextCursorHint("cortex search "+strconv.Quote(query), opts.NextCursor(results), false)
+	}
 	return nil
 }
 
+// printNoResultDiagnosis shows which search stage filtered out every
+// candidate of an empty `cortex search --explain`.
+func printNoResultDiagnosis(w io.Writer, d *search.NoResultDiagnosis) {
+	fmt.Fprintf(w, "\n🔎 Why no results (%s mode):\n", d.Mode)
+	fmt.Fprintf(w, "   store: %d %s\n", d.StoreMemories, pluralize("memory", "memories", int(d.StoreMemories)))
+	if d.StoreMemories > 0 {
+		capped := ""
+		if d.Capped {
+			capped = "+"
+		}
+		fmt.Fprintf(w, "   FTS matched %d%s %s\n", d.Candidates, capped, pluralize("candidate", "candidates", d.Candidates))
+	}
+	for _, st := range d.Stages {
+		fmt.Fprintf(w, "   %-10s -%d → %d", st.Stage, st.Removed, st.Remaining)
+		if st.Detail != "" {
+			fmt.Fprintf(w, "  (%s)", st.Detail)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "   💡 %s\n", d.Cause)
+}
+
 // mergeExpandedResults merges results from multiple expanded queries using RRF.
 // Each result set is treated as a separate ranked list.
 func mergeExpandedResults(resultSets [][]search.Result, limit int) []search.Result {
@@ -1361,6 +1862,46 @@ func appendUniqueFold(values []string, candidate string) []string {
 	return append(values, candidate)
 }
 
+// loadSearchProfiles returns the configured search profiles, or nil when the
+// config cannot be read (the MCP server still starts without them).
+func loadSearchProfiles() cfgresolver.SearchProfiles {
+	cfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+	if err != nil {
+		return nil
+	}
+	return cfg.Search.Profiles
+}
+
+// loadSearchFreshness returns the configured memory freshness decay, or nil
+// when it is disabled or the config cannot be read.
+func loadSearchFreshness() *search.Freshness {
+	cfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+	if err != nil {
+		return nil
+	}
+	return search.FreshnessFromConfig(cfg.Search.Freshness)
+}
+
+// loadSearchGraphBoost returns the configured graph proximity boost weight,
+// or 0 when the config cannot be read.
+func loadSearchGraphBoost() float64 {
+	cfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+	if err != nil {
+		return 0
+	}
+	return cfg.Search.GraphBoost
+}
+
+// parseGraphBoostArg parses a --graph-boost weight, 0 (off) to
+// search.MaxGraphBoost.
+func parseGraphBoostArg(raw string) (float64, error) {
+	w, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil || w < 0 || w > search.MaxGraphBoost {
+		return 0, fmt.Errorf("invalid --graph-boost value: %s (want 0 to %g)", raw, search.MaxGraphBoost)
+	}
+	return w, nil
+}
+
 func parseSourceBoostArg(raw string) (search.SourceBoost, error) {
 	value := strings.TrimSpace(raw)
 	if value == "" {
@@ -1912,6 +2453,7 @@ func runAsk(args []string) error {
 	if err != nil {
 		return err
 	}
+	defer reportScrubbed(provider)
 
 	searchLimit := limit
 	if budget > 0 {
@@ -1973,6 +2515,9 @@ func runAsk(args []string) error {
 	if res.PackedTokens == 0 {
 		res.PackedTokens = packedTokens
 	}
+	if len(res.Citations) > 0 {
+		res.Confidence = askAnswerConfidence(context.Background(), s, res.Citations)
+	}
 	if jsonOutput || !isTTY() {
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -2000,6 +2545,10 @@ func runAsk(args []string) error {
 		}
 		fmt.Printf(" (score %.2f)\n", c.Score)
 	}
+	if res.Confidence != nil {
+		fmt.Println()
+		printAnswerConfidence(res.Confidence)
+	}
 	if budget > 0 {
 		fmt.Printf("\nPacked %d / %d estimated tokens from %d candidate results\n", packedTokens, budget, candidateCount)
 	}
@@ -2187,6 +2736,7 @@ func runAnswer(args []string) error {
 	if err != nil {
 		return err
 	}
+	defer reportScrubbed(provider)
 	if degradeReason != "" && verbose {
 		fmt.Fprintf(os.Stderr, "[answer] LLM unavailable (%s), using degrade mode\n", degradeReason)
 	}
@@ -2238,6 +2788,12 @@ func newSearchEngineForMode(s store.Store, mode search.Mode, embedFlag string) (
 	needsEmbedder := mode == search.ModeHybrid || mode == search.ModeRRF || mode == search.ModeSemantic
 	embedExplicit := strings.TrimSpace(embedFlag) != ""
 
+	// Auto mode only routes to hybrid when an embedder is explicitly
+	// configured; it never probes Ollama or downloads the ONNX model.
+	if mode == search.ModeAuto {
+		needsEmbedder = embedExplicit || embedProviderConfigured()
+	}
+
 	if !needsEmbedder {
 		return search.NewEngine(s), nil
 	}
@@ -2276,10 +2832,18 @@ func newSearchEngineForMode(s store.Store, mode search.Mode, embedFlag string) (
 	return engine, nil
 }
 
+// embedProviderConfigured reports whether config or environment names an
+// embedding provider, as opposed to one that would be auto-detected.
+func embedProviderConfigured() bool {
+	cfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+	return err == nil && strings.TrimSpace(cfg.EmbedProvider.Value) != ""
+}
+
 func loadSearchHNSW(engine *search.Engine, detail string) {
 	if engine == nil {
 		return
 	}
+	engine.SetHNSWParams(resolveHNSWParams())
 	hnswPath := getHNSWPath()
 	if count, err := engine.LoadOrBuildHNSW(context.Background(), hnswPath, 3600); err == nil && count > 0 && globalVerbose {
 		if detail != "" {
@@ -2719,15 +3283,19 @@ Notes:
 
 func runFactCommand(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: cortex fact <keep|drop> <fact_id> [fact_id...]")
+		return fmt.Errorf("usage: cortex fact <keep|drop|sources|repair-sources> ...")
 	}
 	switch strings.ToLower(strings.TrimSpace(args[0])) {
 	case "keep":
 		return runBeliefs(append([]string{"set", "core"}, args[1:]...))
 	case "drop":
 		return runBeliefs(append([]string{"set", "retired"}, args[1:]...))
+	case "sources":
+		return runFactSources(args[1:])
+	case "repair-sources":
+		return runFactRepairSources(args[1:])
 	default:
-		return fmt.Errorf("unknown fact subcommand %q (expected: keep, drop)", args[0])
+		return fmt.Errorf("unknown fact subcommand %q (expected: keep, drop, sources, repair-sources)", args[0])
 	}
 }
 
@@ -3443,11 +4011,23 @@ func runStats(args []string) error {
 		return fmt.Errorf("getting extended stats: %w", err)
 	}
 
+	connectorTrends, err := loadConnectorTrends(ctx, s)
+	if err != nil {
+		return fmt.Errorf("getting connector trends: %w", err)
+	}
+	for _, t := range connectorTrends {
+		if t.Spike != nil {
+			observeStats.Alerts = append(observeStats.Alerts, t.Spike.String())
+		}
+	}
+	storageCfg := resolveStorageConfig()
+	observeStats.ApplyBudget(storageCfg.BudgetBytes(), storageCfg.WarnRatio())
+
 	if opts.jsonOutput || !isTTY() {
-		return outputEnhancedStatsJSON(observeStats, dateRange)
+		return outputEnhancedStatsJSON(observeStats, dateRange, connectorTrends)
 	}
 
-	return outputEnhancedStatsTTY(observeStats, dateRange)
+	return outputEnhancedStatsTTY(observeStats, dateRange, connectorTrends)
 }
 
 type healthReport struct {
@@ -4120,7 +4700,9 @@ func runStale(args []string) error {
 		Limit:         math.MaxInt, // default to all stale facts unless caller sets --limit
 	}
 	jsonOutput := false
+	countFlag := false
 	agentFlag := ""
+	action := staleActionOpts{}
 
 	// Parse flags
 	for i := 0; i < len(args); i++ {
@@ -4130,6 +4712,43 @@ func runStale(args []string) error {
 			agentFlag = args[i]
 		case strings.HasPrefix(args[i], "--agent="):
 			agentFlag = strings.TrimPrefix(args[i], "--agent=")
+		case args[i] == "--action" && i+1 < len(args):
+			i++
+			a, err := parseStaleAction(args[i])
+			if err != nil {
+				return err
+			}
+			action.Action = a
+		case strings.HasPrefix(args[i], "--action="):
+			a, err := parseStaleAction(strings.TrimPrefix(args[i], "--action="))
+			if err != nil {
+				return err
+			}
+			action.Action = a
+		case args[i] == "--ids" && i+1 < len(args):
+			i++
+			ids, err := parseStaleIDs(args[i])
+			if err != nil {
+				return err
+			}
+			action.IDs = ids
+		case strings.HasPrefix(args[i], "--ids="):
+			ids, err := parseStaleIDs(strings.TrimPrefix(args[i], "--ids="))
+			if err != nil {
+				return err
+			}
+			action.IDs = ids
+		case args[i] == "--llm" && i+1 < len(args):
+			i++
+			action.LLM = args[i]
+		case strings.HasPrefix(args[i], "--llm="):
+			action.LLM = strings.TrimPrefix(args[i], "--llm=")
+		case args[i] == "--yes" || args[i] == "-y":
+			action.Yes = true
+		case args[i] == "--apply":
+			action.Apply = true
+		case args[i] == "--dry-run" || args[i] == "-n":
+			action.DryRun = true
 		case args[i] == "--days" && i+1 < len(args):
 			i++
 			days, err := strconv.Atoi(args[i])
@@ -4183,6 +4802,8 @@ func runStale(args []string) error {
 			opts.Limit = limit
 		case args[i] == "--json":
 			jsonOutput = true
+		case args[i] == "--count":
+			countFlag = true
 		case args[i] == "--include-superseded":
 			opts.IncludeSuperseded = true
 		case strings.HasPrefix(args[i], "-"):
@@ -4197,6 +4818,13 @@ func runStale(args []string) error {
 		}
 	}
 
+	if action.Action == "" && (action.IDs != nil || action.LLM != "" || action.Yes || action.Apply || action.DryRun) {
+		return fmt.Errorf("--ids, --llm, --yes, --apply, and --dry-run need --action reinforce|supersede-prompt|archive")
+	}
+	if action.Action != staleActionSupersedePrompt && (action.LLM != "" || action.Apply) {
+		return fmt.Errorf("--llm and --apply only apply to --action supersede-prompt")
+	}
+
 	// Open store
 	cfg := getStoreConfig()
 	s, err := store.NewStore(cfg)
@@ -4218,7 +4846,22 @@ func runStale(args []string) error {
 		return fmt.Errorf("getting stale facts: %w", err)
 	}
 
+	if action.Action != "" {
+		action.JSON = jsonOutput
+		return runStaleAction(ctx, s, staleFacts, action)
+	}
+
+	totalStale, totalEstimated := 0, false
+	if countFlag {
+		if totalStale, totalEstimated, err = engine.CountStaleFacts(ctx, opts); err != nil {
+			return fmt.Errorf("counting stale facts: %w", err)
+		}
+	}
+
 	if jsonOutput || !isTTY() {
+		if countFlag {
+			return outputCountedJSON(staleFacts, len(staleFacts), totalStale, totalEstimated, "")
+		}
 		return outputStaleJSON(staleFacts)
 	}
 
@@ -4229,10 +4872,33 @@ func runStale(args []string) error {
 		totalFacts = int(storeStats.FactCount)
 	}
 
-	return outputStaleTTY(staleFacts, opts, totalFacts)
+	if err := outputStaleTTY(staleFacts, opts, totalFacts); err != nil {
+		return err
+	}
+	if countFlag && len(staleFacts) > 0 {
+		printCountFooter("stale facts", len(staleFacts), totalStale, totalEstimated)
+	}
+	return nil
+}
+
+// conflictCheckpointKey is the checkpoint item key for a conflict pair,
+// independent of which fact is listed first.
+func conflictCheckpointKey(a, b int64) string {
+	if a > b {
+		a, b = b, a
+	}
+	return fmt.Sprintf("pair:%d-%d", a, b)
 }
 
 func runConflicts(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "ignore":
+			return runConflictsIgnore(args[1:])
+		case "unignore":
+			return runConflictsUnignore(args[1:])
+		}
+	}
 	jsonOutput := false
 	verboseOutput := globalVerbose
 	resolveStrategy := ""
@@ -4245,10 +4911,41 @@ func runConflicts(args []string) error {
 	autoResolve := false
 	autoThreshold := 0.85
 	agentFlag := ""
+	criticalThreshold := 0.0 // 0 = policies.conflict_severity.critical_threshold
+	minSeverity := ""
+	countFlag := false
+	restart := false
+	quorum := 0 // 0 = majority of the --resolve consensus models
+	canaryOpts := newCanaryOptions()
 
 	// Parse flags
 	for i := 0; i < len(args); i++ {
+		if next, ok, err := canaryOpts.parseFlag(args, i); err != nil {
+			return err
+		} else if ok {
+			i = next
+			continue
+		}
 		switch {
+		case args[i] == "--critical-threshold" && i+1 < len(args):
+			i++
+			v, err := strconv.ParseFloat(args[i], 64)
+			if err != nil || v <= 0 || v > 1 {
+				return fmt.Errorf("invalid --critical-threshold: %s (expected 0 < value <= 1)", args[i])
+			}
+			criticalThreshold = v
+		case strings.HasPrefix(args[i], "--critical-threshold="):
+			raw := strings.TrimPrefix(args[i], "--critical-threshold=")
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil || v <= 0 || v > 1 {
+				return fmt.Errorf("invalid --critical-threshold: %s (expected 0 < value <= 1)", raw)
+			}
+			criticalThreshold = v
+		case args[i] == "--min-severity" && i+1 < len(args):
+			i++
+			minSeverity = strings.ToLower(args[i])
+		case strings.HasPrefix(args[i], "--min-severity="):
+			minSeverity = strings.ToLower(strings.TrimPrefix(args[i], "--min-severity="))
 		case args[i] == "--agent" && i+1 < len(args):
 			i++
 			agentFlag = args[i]
@@ -4256,6 +4953,8 @@ func runConflicts(args []string) error {
 			agentFlag = strings.TrimPrefix(args[i], "--agent=")
 		case args[i] == "--json":
 			jsonOutput = true
+		case args[i] == "--count":
+			countFlag = true
 		case args[i] == "--verbose" || args[i] == "-v":
 			verboseOutput = true
 		case args[i] == "--dry-run" || args[i] == "-n":
@@ -4280,6 +4979,19 @@ func runConflicts(args []string) error {
 			resolveStrategy = args[i]
 		case strings.HasPrefix(args[i], "--resolve="):
 			resolveStrategy = strings.TrimPrefix(args[i], "--resolve=")
+		case args[i] == "--quorum" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid --quorum: %s", args[i])
+			}
+			quorum = n
+		case strings.HasPrefix(args[i], "--quorum="):
+			n, err := strconv.Atoi(strings.TrimPrefix(args[i], "--quorum="))
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid --quorum: %s", args[i])
+			}
+			quorum = n
 		case args[i] == "--limit" && i+1 < len(args):
 			i++
 			n, err := strconv.Atoi(args[i])
@@ -4314,6 +5026,8 @@ func runConflicts(args []string) error {
 			llmFlag = args[i]
 		case strings.HasPrefix(args[i], "--llm="):
 			llmFlag = strings.TrimPrefix(args[i], "--llm=")
+		case args[i] == "--restart":
+			restart = true
 		default:
 			if strings.HasPrefix(args[i], "-") {
 				return fmt.Errorf("unknown flag: %s", args[i])
@@ -4321,7 +5035,18 @@ func runConflicts(args []string) error {
 			return fmt.Errorf("unexpected argument: %s", args[i])
 		}
 	}
-
+	if quorum > 0 && resolveStrategy != "consensus" {
+		return fmt.Errorf("--quorum requires --resolve consensus")
+	}
+	if canaryOpts.Enabled {
+		if resolveStrategy != "llm" && resolveStrategy != "consensus" {
+			return fmt.Errorf("--canary requires --resolve llm or --resolve consensus")
+		}
+		if dryRun {
+			return fmt.Errorf("--canary applies a sample for real; it cannot be combined with --dry-run")
+		}
+	}
+
 	// Open store
 	cfg := getStoreConfig()
 	s, err := store.NewStore(cfg)
@@ -4337,6 +5062,15 @@ func runConflicts(args []string) error {
 	}
 	engine := observe.NewEngine(s, dbPath)
 	resolver := observe.NewResolver(s, engine)
+	if criticalThreshold == 0 {
+		if policies, err := cfgresolver.ResolvePolicyConfig(""); err == nil {
+			criticalThreshold = policies.ConflictSeverity.CriticalThreshold
+		}
+	}
+	engine.SetCriticalThreshold(criticalThreshold)
+	if minSeverity != "" && observe.SeverityRank(minSeverity) < 0 {
+		return fmt.Errorf("invalid --min-severity: %s (valid: critical, high, medium, low)", minSeverity)
+	}
 
 	if autoThreshold < 0 || autoThreshold > 1 {
 		return fmt.Errorf("--threshold must be between 0.0 and 1.0")
@@ -4414,28 +5148,87 @@ func runConflicts(args []string) error {
 	}
 
 	// LLM-powered resolution: --resolve llm [--llm model]
-	if resolveStrategy == "llm" {
+	// Multi-model resolution: --resolve consensus --llm m1,m2,m3 [--quorum N]
+	if resolveStrategy == "llm" || resolveStrategy == "consensus" {
+		consensus := resolveStrategy == "consensus"
 		if llmFlag == "" {
+			if consensus {
+				return fmt.Errorf("--resolve consensus needs two or more models: --llm <model1>,<model2>[,...]")
+			}
 			llmFlag = extract.DefaultResolveModel
 		}
-		llmCfg, err := llm.ParseLLMFlag(llmFlag)
-		if err != nil {
-			return fmt.Errorf("parsing --llm: %w", err)
+		models := []string{llmFlag}
+		if consensus {
+			models, err = parseConsensusModels(llmFlag, quorum)
+			if err != nil {
+				return err
+			}
 		}
-		provider, err := llm.NewProvider(llmCfg)
+		providers := make([]llm.Provider, 0, len(models))
+		for _, model := range models {
+			llmCfg, err := llm.ParseLLMFlag(model)
+			if err != nil {
+				return fmt.Errorf("parsing --llm: %w", err)
+			}
+			p, err := newScrubbedProvider(llmCfg)
+			if err != nil {
+				return fmt.Errorf("creating LLM provider: %w", err)
+			}
+			defer reportScrubbed(p)
+			providers = append(providers, p)
+		}
+		provider := providers[0]
+		resolvePairs := func(ctx context.Context, pairs []extract.ConflictPair, opts extract.ResolveOpts) (*extract.ResolveResult, error) {
+			if consensus {
+				return extract.ResolveConflictsConsensus(ctx, providers, pairs, extract.ConsensusOpts{ResolveOpts: opts, Quorum: quorum})
+			}
+			return extract.ResolveConflictsLLM(ctx, provider, pairs, opts)
+		}
+
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		// Pairs resolved or flagged by an interrupted earlier run are skipped.
+		checkpointOp := "conflicts-resolve"
+		if consensus {
+			checkpointOp = "conflicts-consensus"
+		}
+		checkpoint, err := openBatchCheckpoint(ctx, s, checkpointOp, restart, dryRun)
 		if err != nil {
-			return fmt.Errorf("creating LLM provider: %w", err)
+			return fmt.Errorf("loading checkpoint: %w", err)
 		}
 
 		// Get conflicts
-		conflicts, err := engine.GetConflictsLimitWithSuperseded(ctx, limitFlag, includeSuperseded)
+		detectLimit := limitFlag
+		if detectLimit > 0 {
+			detectLimit += checkpoint.resumed()
+		}
+		detected, err := engine.GetConflictsLimitWithSuperseded(ctx, detectLimit, includeSuperseded)
 		if err != nil {
 			return fmt.Errorf("detecting conflicts: %w", err)
 		}
+		conflicts := make([]observe.Conflict, 0, len(detected))
+		for _, c := range detected {
+			if !checkpoint.has(conflictCheckpointKey(c.Fact1.ID, c.Fact2.ID)) {
+				conflicts = append(conflicts, c)
+			}
+		}
+		conflicts, held, err := remoteLLMConflicts(ctx, s, conflicts)
+		if err != nil {
+			return fmt.Errorf("checking privacy zones: %w", err)
+		}
+		if held > 0 {
+			fmt.Fprintf(os.Stderr, "Skipping %d conflicts involving local-llm-only projects\n", held)
+		}
+		if limitFlag > 0 && len(conflicts) > limitFlag {
+			conflicts = conflicts[:limitFlag]
+		}
 		if len(conflicts) == 0 {
+			checkpoint.finish(ctx, 0, "conflicts")
 			fmt.Println("No conflicts found.")
 			return nil
 		}
+		checkpoint.announceResume("conflicts")
 
 		// Convert to ConflictPair format
 		pairs := make([]extract.ConflictPair, len(conflicts))
@@ -4469,84 +5262,173 @@ func runConflicts(args []string) error {
 			}
 		}
 
-		fmt.Printf("Resolving %d conflicts with LLM (model: %s)\n", len(pairs), provider.Name())
+		if consensus {
+			q := quorum
+			if q == 0 {
+				q = extract.MajorityQuorum(len(providers))
+			}
+			fmt.Printf("Resolving %d conflicts by consensus of %d models (%d must agree: %s)\n", len(pairs), len(providers), q, strings.Join(models, ", "))
+		} else {
+			fmt.Printf("Resolving %d conflicts with LLM (model: %s)\n", len(pairs), provider.Name())
+		}
 		if dryRun {
 			fmt.Println("DRY RUN — no changes will be applied")
 		}
 		fmt.Println()
 
-		result, err := extract.ResolveConflictsLLM(ctx, provider, pairs, extract.ResolveOpts{
+		// Apply each batch's resolutions (unless dry run) as soon as it
+		// returns, so a failure part-way keeps everything done so far.
+		opts := extract.ResolveOpts{
 			MinConfidence: 0.7,
 			DryRun:        dryRun,
 			Concurrency:   extract.DefaultResolveConcurrency,
 			BatchSize:     5,
-		})
-		if err != nil {
-			return fmt.Errorf("LLM resolution failed: %w", err)
 		}
-
-		// Apply resolutions (unless dry run)
 		applied := 0
+		var canary *canaryRun // set while a --canary sample is applied
+		var sqlStore *store.SQLiteStore
 		if !dryRun {
-			sqlStore, ok := s.(*store.SQLiteStore)
+			var ok bool
+			sqlStore, ok = s.(*store.SQLiteStore)
 			if !ok {
 				return fmt.Errorf("resolve requires SQLite store")
 			}
-			for _, r := range result.Resolutions {
-				switch r.Action {
-				case "supersede":
-					if err := sqlStore.SupersedeFact(ctx, r.LoserID, r.WinnerID, r.Reason); err != nil {
-						fmt.Fprintf(os.Stderr, "  Warning: failed to supersede fact %d: %v\n", r.LoserID, err)
-						continue
+			// Facts as they were before resolution, for --canary rollback.
+			before := make(map[int64]store.Fact, 2*len(conflicts))
+			for _, c := range conflicts {
+				before[c.Fact1.ID], before[c.Fact2.ID] = c.Fact1, c.Fact2
+			}
+			restore := func(ctx context.Context, id, by int64) error {
+				f := before[id]
+				_, err := sqlStore.RestoreSupersededFact(ctx, id, by, f.Confidence, f.State)
+				return err
+			}
+			describePair := func(r extract.ConflictResolution) string {
+				for _, p := range pairs {
+					if p.Index == r.PairIndex {
+						return fmt.Sprintf("conflict between %s and %s",
+							describeFact(p.Fact1.ID, p.Fact1.Subject, p.Fact1.Predicate, p.Fact1.Object),
+							describeFact(p.Fact2.ID, p.Fact2.Subject, p.Fact2.Predicate, p.Fact2.Object))
 					}
-					_ = s.ReinforceFact(ctx, r.WinnerID)
-					applied++
-				case "merge":
-					if r.MergedFact != nil {
-						// Find memory ID from one of the original facts
-						var memID int64
-						if orig, err := s.GetFact(ctx, r.WinnerID); err == nil && orig != nil {
-							memID = orig.MemoryID
-						} else if orig, err := s.GetFact(ctx, r.LoserID); err == nil && orig != nil {
-							memID = orig.MemoryID
+				}
+				return fmt.Sprintf("conflict pair %d", r.PairIndex)
+			}
+			applyCtx := context.WithoutCancel(ctx)
+			opts.OnBatch = func(batch []extract.ConflictPair, resolutions []extract.ConflictResolution) error {
+				for _, r := range resolutions {
+					switch r.Action {
+					case "supersede":
+						if err := sqlStore.SupersedeFact(applyCtx, r.LoserID, r.WinnerID, r.Reason); err != nil {
+							fmt.Fprintf(os.Stderr, "  Warning: failed to supersede fact %d: %v\n", r.LoserID, err)
+							continue
+						}
+						_ = s.ReinforceFact(applyCtx, r.WinnerID)
+						applied++
+						if canary != nil {
+							canary.add(fmt.Sprintf("%s: kept #%d, superseded #%d (%s)", describePair(r), r.WinnerID, r.LoserID, r.Reason),
+								func(ctx context.Context) error { return restore(ctx, r.LoserID, r.WinnerID) })
 						}
-						// Find memory ID from conflict pair facts
-						if memID == 0 {
+					case "merge":
+						if r.MergedFact != nil {
+							// Find memory ID from one of the original facts
+							var memID int64
+							if orig, err := s.GetFact(applyCtx, r.WinnerID); err == nil && orig != nil {
+								memID = orig.MemoryID
+							} else if orig, err := s.GetFact(applyCtx, r.LoserID); err == nil && orig != nil {
+								memID = orig.MemoryID
+							}
+							// Find memory ID from conflict pair facts
+							if memID == 0 {
+								for _, p := range pairs {
+									if p.Fact1.ID == r.WinnerID || p.Fact1.ID == r.LoserID {
+										if f, err := s.GetFact(applyCtx, p.Fact1.ID); err == nil && f != nil {
+											memID = f.MemoryID
+										}
+										break
+									}
+								}
+							}
+							newFact := &store.Fact{
+								MemoryID:   memID,
+								Subject:    r.MergedFact.Subject,
+								Predicate:  r.MergedFact.Predicate,
+								Object:     r.MergedFact.Object,
+								FactType:   r.MergedFact.FactType,
+								Confidence: r.Confidence,
+							}
+							newID, err := s.AddFact(applyCtx, newFact)
+							if err != nil {
+								fmt.Fprintf(os.Stderr, "  Warning: failed to create merged fact: %v\n", err)
+								continue
+							}
+							// Supersede both originals
 							for _, p := range pairs {
-								if p.Fact1.ID == r.WinnerID || p.Fact1.ID == r.LoserID {
-									if f, err := s.GetFact(ctx, p.Fact1.ID); err == nil && f != nil {
-										memID = f.MemoryID
+								if p.Index == r.PairIndex {
+									_ = sqlStore.SupersedeFact(applyCtx, p.Fact1.ID, newID, "merged: "+r.Reason)
+									_ = sqlStore.SupersedeFact(applyCtx, p.Fact2.ID, newID, "merged: "+r.Reason)
+									if canary != nil {
+										id1, id2 := p.Fact1.ID, p.Fact2.ID
+										canary.add(fmt.Sprintf("%s: both replaced by merged fact %s (%s)", describePair(r),
+											describeFact(newID, newFact.Subject, newFact.Predicate, newFact.Object), r.Reason),
+											func(ctx context.Context) error {
+												if err := restore(ctx, id1, newID); err != nil {
+													return err
+												}
+												if err := restore(ctx, id2, newID); err != nil {
+													return err
+												}
+												_, err := sqlStore.DeleteFactsByIDs(ctx, []int64{newID})
+												return err
+											})
 									}
 									break
 								}
 							}
+							applied++
 						}
-						newFact := &store.Fact{
-							MemoryID:   memID,
-							Subject:    r.MergedFact.Subject,
-							Predicate:  r.MergedFact.Predicate,
-							Object:     r.MergedFact.Object,
-							FactType:   r.MergedFact.FactType,
-							Confidence: r.Confidence,
-						}
-						newID, err := s.AddFact(ctx, newFact)
-						if err != nil {
-							fmt.Fprintf(os.Stderr, "  Warning: failed to create merged fact: %v\n", err)
-							continue
-						}
-						// Supersede both originals
-						for _, p := range pairs {
-							if p.Index == r.PairIndex {
-								_ = sqlStore.SupersedeFact(ctx, p.Fact1.ID, newID, "merged: "+r.Reason)
-								_ = sqlStore.SupersedeFact(ctx, p.Fact2.ID, newID, "merged: "+r.Reason)
-								break
-							}
-						}
-						applied++
 					}
 				}
+				keys := make([]string, len(batch))
+				for i, p := range batch {
+					keys[i] = conflictCheckpointKey(p.Fact1.ID, p.Fact2.ID)
+				}
+				if canary != nil {
+					canary.record(keys...)
+					return nil
+				}
+				return checkpoint.record(ctx, keys...)
+			}
+		}
+
+		// With --canary, a random sample of pairs is resolved and reviewed
+		// first; the rest only runs if the sample passes.
+		pending := pairs
+		if canaryOpts.Enabled {
+			judge, err := canaryOpts.judge(provider)
+			if err != nil {
+				return err
+			}
+			sample, rest := canarySplit(pairs, canaryOpts.Sample, newCanaryRand())
+			if canary, err = startCanary(ctx, sqlStore, "resolve", canaryOpts, len(sample), len(pairs)); err != nil {
+				return err
+			}
+			fmt.Printf("Canary: resolving %d of %d conflicts first\n", len(sample), len(pairs))
+			if _, err := resolvePairs(ctx, sample, opts); err != nil {
+				_ = canary.rollback(ctx)
+				return fmt.Errorf("canary resolution failed: %w", err)
 			}
+			if _, err := canary.conclude(ctx, judge, checkpoint, canaryOutput(jsonOutput)); err != nil {
+				return err
+			}
+			canary = nil
+			pending = rest
+		}
+
+		result, err := resolvePairs(ctx, pending, opts)
+		if err != nil {
+			return fmt.Errorf("LLM resolution failed: %w", err)
 		}
+		checkpoint.finish(ctx, len(pairs), "conflicts")
 
 		// Output
 		if jsonOutput {
@@ -4561,6 +5443,12 @@ func runConflicts(args []string) error {
 		fmt.Printf("  Merged:        %d\n", result.Merged)
 		fmt.Printf("  Flagged:       %d (human review needed)\n", result.Flagged)
 		fmt.Printf("  Errors:        %d\n", result.Errors)
+		if result.Retries > 0 {
+			fmt.Printf("  Retries:       %d (%d rate-limited)\n", result.Retries, result.RateLimits)
+		}
+		if result.StopReason != "" {
+			fmt.Printf("  Not attempted: %d (%s)\n", result.Skipped, result.StopReason)
+		}
 		if !dryRun {
 			fmt.Printf("\n  ✅ Applied: %d resolutions\n", applied)
 		}
@@ -4584,6 +5472,16 @@ func runConflicts(args []string) error {
 				if r.MergedFact != nil {
 					fmt.Printf("     Merged → %s | %s → %s\n", r.MergedFact.Subject, r.MergedFact.Predicate, r.MergedFact.Object)
 				}
+				for _, v := range r.Votes {
+					switch v.Action {
+					case "":
+						fmt.Printf("       · %s: no answer\n", v.Model)
+					case "supersede":
+						fmt.Printf("       · %s: keep %d over %d (%.2f)\n", v.Model, v.WinnerID, v.LoserID, v.Confidence)
+					default:
+						fmt.Printf("       · %s: %s (%.2f)\n", v.Model, v.Action, v.Confidence)
+					}
+				}
 			}
 		}
 
@@ -4640,11 +5538,76 @@ func runConflicts(args []string) error {
 		conflicts = filtered
 	}
 
+	// Critical conflicts become alerts (and webhook deliveries when
+	// CORTEX_ALERT_WEBHOOK_URL is set); existing unacknowledged alerts are not repeated.
+	wireWebhook(s)
+	escalated, err := engine.EscalateCriticalConflicts(ctx, conflicts)
+	if err != nil {
+		return fmt.Errorf("escalating critical conflicts: %w", err)
+	}
+	flushWebhook(s)
+
+	if minSeverity != "" {
+		filtered := make([]observe.Conflict, 0, len(conflicts))
+		for _, c := range conflicts {
+			if observe.SeverityRank(c.Severity) >= observe.SeverityRank(minSeverity) {
+				filtered = append(filtered, c)
+			}
+		}
+		conflicts = filtered
+	}
+
+	totalConflicts, totalEstimated := 0, false
+	if countFlag {
+		if totalConflicts, totalEstimated, err = countConflicts(ctx, engine, includeSuperseded, agentFlag, minSeverity); err != nil {
+			return err
+		}
+	}
+
 	if jsonOutput || !isTTY() {
+		if countFlag {
+			if conflicts == nil {
+				conflicts = []observe.Conflict{}
+			}
+			return outputCountedJSON(conflicts, len(conflicts), totalConflicts, totalEstimated, "")
+		}
 		return outputConflictsJSON(conflicts)
 	}
 
-	return outputConflictsTTY(conflicts, verboseOutput)
+	if err := outputConflictsTTY(conflicts, verboseOutput); err != nil {
+		return err
+	}
+	if countFlag && len(conflicts) > 0 {
+		printCountFooter("conflicts", len(conflicts), totalConflicts, totalEstimated)
+	}
+	if escalated > 0 {
+		fmt.Printf("\n🚨 Escalated %d critical %s to alerts (threshold %.2f)\n", escalated, pluralize("conflict", "conflicts", escalated), engine.CriticalThreshold())
+	}
+	return nil
+}
+
+// conflictCountCap bounds the detection pass behind `conflicts --count`.
+const conflictCountCap = 10000
+
+// countConflicts totals detected conflicts past --limit, with the same agent
+// and severity filters as the listing. The total is estimated when detection
+// stopped at conflictCountCap.
+func countConflicts(ctx context.Context, engine *observe.Engine, includeSuperseded bool, agent, minSeverity string) (int, bool, error) {
+	all, err := engine.GetConflictsLimitWithSuperseded(ctx, conflictCountCap, includeSuperseded)
+	if err != nil {
+		return 0, false, fmt.Errorf("counting conflicts: %w", err)
+	}
+	total := 0
+	for _, c := range all {
+		if agent != "" && c.Fact1.AgentID != agent && c.Fact2.AgentID != agent {
+			continue
+		}
+		if minSeverity != "" && observe.SeverityRank(c.Severity) < observe.SeverityRank(minSeverity) {
+			continue
+		}
+		total++
+	}
+	return total, len(all) >= conflictCountCap, nil
 }
 
 type autoResolveItem struct {
@@ -4701,6 +5664,32 @@ func runAutoResolveConflicts(ctx context.Context, st store.Store, conflicts []ob
 		return batch, nil
 	}
 
+	// Ambiguous pairs from local-llm-only projects stay unresolved.
+	ambFacts := make([]*store.Fact, 0, 2*len(ambiguous))
+	for i := range ambiguous {
+		ambFacts = append(ambFacts, &ambiguous[i].c.Fact1, &ambiguous[i].c.Fact2)
+	}
+	held, err := heldFromRemoteLLM(ctx, st, ambFacts)
+	if err != nil {
+		return nil, fmt.Errorf("checking privacy zones: %w", err)
+	}
+	if len(held) > 0 {
+		remote := ambiguous[:0]
+		for _, entry := range ambiguous {
+			if held[entry.c.Fact1.ID] || held[entry.c.Fact2.ID] {
+				batch.LLM++
+				batch.Skipped++
+				batch.Results = append(batch.Results, autoResolveItem{Method: "llm", Reason: "skipped: local-llm-only project"})
+				continue
+			}
+			remote = append(remote, entry)
+		}
+		ambiguous = remote
+		if len(ambiguous) == 0 {
+			return batch, nil
+		}
+	}
+
 	// LLM fallback for ambiguous conflicts
 	if llmFlag == "" {
 		if resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{}); err == nil {
@@ -4721,7 +5710,7 @@ func runAutoResolveConflicts(ctx context.Context, st store.Store, conflicts []ob
 		}
 		return batch, nil
 	}
-	provider, err := llm.NewProvider(llmCfg)
+	provider, err := newScrubbedProvider(llmCfg)
 	if err != nil {
 		for range ambiguous {
 			batch.LLM++
@@ -4730,6 +5719,7 @@ func runAutoResolveConflicts(ctx context.Context, st store.Store, conflicts []ob
 		}
 		return batch, nil
 	}
+	defer reportScrubbed(provider)
 
 	pairs := make([]extract.ConflictPair, len(ambiguous))
 	for i, entry := range ambiguous {
@@ -4958,6 +5948,9 @@ func runExtract(args []string) error {
 	return outputExtractTTY(filepath, facts)
 }
 
+// classifyCheckpointKey is the checkpoint item key for a fact.
+func classifyCheckpointKey(id int64) string { return fmt.Sprintf("fact:%d", id) }
+
 func runClassify(args []string) error {
 	llmFlag := ""
 	batchSize := extract.DefaultClassifyBatchSize
@@ -4966,8 +5959,16 @@ func runClassify(args []string) error {
 	dryRun := false
 	jsonOutput := false
 	agentFlag := ""
+	restart := false
+	canaryOpts := newCanaryOptions()
 
 	for i := 0; i < len(args); i++ {
+		if next, ok, err := canaryOpts.parseFlag(args, i); err != nil {
+			return err
+		} else if ok {
+			i = next
+			continue
+		}
 		switch {
 		case args[i] == "--agent" && i+1 < len(args):
 			i++
@@ -4979,6 +5980,8 @@ func runClassify(args []string) error {
 			llmFlag = args[i]
 		case strings.HasPrefix(args[i], "--llm="):
 			llmFlag = strings.TrimPrefix(args[i], "--llm=")
+		case args[i] == "--restart":
+			restart = true
 		case args[i] == "--batch-size" && i+1 < len(args):
 			i++
 			n, err := strconv.Atoi(args[i])
@@ -5008,11 +6011,17 @@ func runClassify(args []string) error {
 			return fmt.Errorf("unknown flag: %s", args[i])
 		}
 	}
+	if canaryOpts.Enabled && dryRun {
+		return fmt.Errorf("--canary applies a sample for real; it cannot be combined with --dry-run")
+	}
 
+	resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	applyExtractionRuntimeConfig(resolvedCfg)
 	if llmFlag == "" {
-		if resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{}); err == nil {
-			llmFlag = resolvedCfg.EffectiveLLMModel("classify", extract.DefaultClassifyModel).Value
-		}
+		llmFlag = resolvedCfg.EffectiveLLMModel("classify", extract.DefaultClassifyModel).Value
 		if llmFlag == "" {
 			llmFlag = extract.DefaultClassifyModel // deepseek-v3.2
 		}
@@ -5023,10 +6032,11 @@ func runClassify(args []string) error {
 	if err != nil {
 		return fmt.Errorf("parsing --llm: %w", err)
 	}
-	provider, err := llm.NewProvider(llmCfg)
+	provider, err := newScrubbedProvider(llmCfg)
 	if err != nil {
 		return fmt.Errorf("creating LLM provider: %w", err)
 	}
+	defer reportScrubbed(provider)
 
 	// Open store
 	s, err := store.NewStore(getStoreConfig())
@@ -5035,30 +6045,61 @@ func runClassify(args []string) error {
 	}
 	defer s.Close()
 
-	ctx := context.Background()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok && !dryRun {
+		return fmt.Errorf("classify requires SQLite store")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Facts classified by an interrupted earlier run are skipped.
+	operation := "classify"
+	if agentFlag != "" {
+		operation += ":agent=" + agentFlag
+	}
+	checkpoint, err := openBatchCheckpoint(ctx, s, operation, restart, dryRun)
+	if err != nil {
+		return fmt.Errorf("loading checkpoint: %w", err)
+	}
 
 	// Get kv-type facts to classify
 	listLimit := 10000
 	if limit > 0 {
 		listLimit = limit
 	}
-	facts, err := s.ListFacts(ctx, store.ListOpts{
+	listed, err := s.ListFacts(ctx, store.ListOpts{
 		FactType: "kv",
-		Limit:    listLimit,
+		Limit:    listLimit + checkpoint.resumed(),
 		Agent:    agentFlag,
 	})
 	if err != nil {
 		return fmt.Errorf("listing facts: %w", err)
 	}
+	facts := make([]*store.Fact, 0, len(listed))
+	for _, f := range listed {
+		if !checkpoint.has(classifyCheckpointKey(f.ID)) {
+			facts = append(facts, f)
+		}
+	}
+	facts, held, err := remoteLLMFacts(ctx, s, facts)
+	if err != nil {
+		return fmt.Errorf("checking privacy zones: %w", err)
+	}
+	if held > 0 {
+		fmt.Fprintf(os.Stderr, "Skipping %d facts from local-llm-only projects\n", held)
+	}
 
 	if len(facts) == 0 {
+		checkpoint.finish(ctx, 0, "facts")
 		fmt.Println("No kv-type facts to classify.")
 		return nil
 	}
 
-	if limit > 0 && len(facts) > limit {
-		facts = facts[:limit]
+	if len(facts) > listLimit {
+		facts = facts[:listLimit]
 	}
+	checkpoint.announceResume("facts")
 
 	fmt.Printf("Found %d kv-type facts to classify (batch size: %d, concurrency: %d, model: %s)\n",
 		len(facts), batchSize, concurrency, provider.Name())
@@ -5079,7 +6120,8 @@ func runClassify(args []string) error {
 		}
 	}
 
-	// Run classification
+	// Run classification. Each batch is applied and checkpointed as soon as
+	// it returns, so a failure part-way keeps everything done so far.
 	opts := extract.ClassifyOpts{
 		BatchSize:     batchSize,
 		MinConfidence: 0.8,
@@ -5087,28 +6129,70 @@ func runClassify(args []string) error {
 		DryRun:        dryRun,
 		Concurrency:   concurrency,
 	}
-
-	result, err := extract.ClassifyFacts(ctx, provider, classifyFacts, opts)
-	if err != nil {
-		return fmt.Errorf("classification failed: %w", err)
-	}
-
-	// Apply changes (unless dry run)
 	applied := 0
+	var canary *canaryRun // set while a --canary sample is applied
 	if !dryRun {
-		sqlStore, ok := s.(*store.SQLiteStore)
-		if !ok {
-			return fmt.Errorf("classify requires SQLite store")
-		}
-		for _, c := range result.Classified {
-			if err := sqlStore.UpdateFactType(ctx, c.FactID, c.NewType); err != nil {
-				fmt.Fprintf(os.Stderr, "  Warning: failed to update fact %d: %v\n", c.FactID, err)
-				continue
+		opts.OnBatch = func(batch []extract.ClassifyableFact, classified []extract.FactClassification) error {
+			for _, c := range classified {
+				if err := sqlStore.UpdateFactType(context.WithoutCancel(ctx), c.FactID, c.NewType); err != nil {
+					fmt.Fprintf(os.Stderr, "  Warning: failed to update fact %d: %v\n", c.FactID, err)
+					continue
+				}
+				applied++
+				if canary != nil {
+					var desc string
+					for _, f := range batch {
+						if f.ID == c.FactID {
+							desc = describeFact(f.ID, f.Subject, f.Predicate, f.Object)
+						}
+					}
+					canary.add(fmt.Sprintf("fact %s: type %s → %s", desc, c.OldType, c.NewType), func(ctx context.Context) error {
+						return sqlStore.UpdateFactType(ctx, c.FactID, c.OldType)
+					})
+				}
+			}
+			keys := make([]string, len(batch))
+			for i, f := range batch {
+				keys[i] = classifyCheckpointKey(f.ID)
 			}
-			applied++
+			if canary != nil {
+				canary.record(keys...)
+				return nil
+			}
+			return checkpoint.record(ctx, keys...)
+		}
+	}
+
+	// With --canary, a random sample is classified and reviewed first; the
+	// rest only runs if the sample passes.
+	pending := classifyFacts
+	if canaryOpts.Enabled {
+		judge, err := canaryOpts.judge(provider)
+		if err != nil {
+			return err
+		}
+		sample, rest := canarySplit(classifyFacts, canaryOpts.Sample, newCanaryRand())
+		if canary, err = startCanary(ctx, sqlStore, "classify", canaryOpts, len(sample), len(classifyFacts)); err != nil {
+			return err
 		}
+		fmt.Printf("Canary: classifying %d of %d facts first\n", len(sample), len(classifyFacts))
+		if _, err := extract.ClassifyFacts(ctx, provider, sample, opts); err != nil {
+			_ = canary.rollback(ctx)
+			return fmt.Errorf("canary classification failed: %w", err)
+		}
+		if _, err := canary.conclude(ctx, judge, checkpoint, canaryOutput(jsonOutput)); err != nil {
+			return err
+		}
+		canary = nil
+		pending = rest
 	}
 
+	result, err := extract.ClassifyFacts(ctx, provider, pending, opts)
+	if err != nil {
+		return fmt.Errorf("classification failed: %w", err)
+	}
+	checkpoint.finish(ctx, len(classifyFacts), "facts")
+
 	// Output
 	if jsonOutput {
 		data, _ := json.MarshalIndent(result, "", "  ")
@@ -5128,6 +6212,12 @@ func runClassify(args []string) error {
 	fmt.Printf("  Unchanged:       %d\n", result.Unchanged)
 	fmt.Printf("  Errors:          %d\n", result.Errors)
 	fmt.Printf("  Batches:         %d\n", result.BatchCount)
+	if result.Retries > 0 {
+		fmt.Printf("  Retries:         %d (%d rate-limited)\n", result.Retries, result.RateLimits)
+	}
+	if result.StopReason != "" {
+		fmt.Printf("  Not attempted:   %d (%s)\n", result.Skipped, result.StopReason)
+	}
 
 	if len(typeCounts) > 0 {
 		fmt.Println("\n  Type distribution:")
@@ -5157,14 +6247,32 @@ func runClassify(args []string) error {
 	return nil
 }
 
+// summarizeCheckpointKey is the checkpoint item key for a cluster.
+func summarizeCheckpointKey(id int64) string { return fmt.Sprintf("cluster:%d", id) }
+
+// summarizeUndoWindow is how long after `cortex summarize` a cluster's
+// compaction can be reverted with `cortex summarize undo`.
+const summarizeUndoWindow = 14 * 24 * time.Hour
+
 func runSummarize(args []string) error {
+	if len(args) > 0 && args[0] == "undo" {
+		return runSummarizeUndo(args[1:])
+	}
 	llmFlag := ""
 	minClusterSize := extract.DefaultMinClusterSize
 	clusterID := int64(0)
 	dryRun := false
+	restart := false
 	jsonOutput := false
+	canaryOpts := newCanaryOptions()
 
 	for i := 0; i < len(args); i++ {
+		if next, ok, err := canaryOpts.parseFlag(args, i); err != nil {
+			return err
+		} else if ok {
+			i = next
+			continue
+		}
 		switch {
 		case args[i] == "--llm" && i+1 < len(args):
 			i++
@@ -5187,6 +6295,8 @@ func runSummarize(args []string) error {
 			clusterID = n
 		case args[i] == "--dry-run" || args[i] == "-n":
 			dryRun = true
+		case args[i] == "--restart":
+			restart = true
 		case args[i] == "--json":
 			jsonOutput = true
 		case strings.HasPrefix(args[i], "-"):
@@ -5195,7 +6305,10 @@ func runSummarize(args []string) error {
 	}
 
 	if llmFlag == "" {
-		return fmt.Errorf("usage: cortex summarize --llm <provider/model> [--min-cluster-size N] [--cluster <id>] [--dry-run] [--json]")
+		return fmt.Errorf("usage: cortex summarize --llm <provider/model> [--min-cluster-size N] [--cluster <id>] [--dry-run] [--restart] [--canary] [--json]\n       cortex summarize undo <cluster-id> [--json]")
+	}
+	if canaryOpts.Enabled && dryRun {
+		return fmt.Errorf("--canary applies a sample for real; it cannot be combined with --dry-run")
 	}
 
 	// Create LLM provider
@@ -5203,10 +6316,11 @@ func runSummarize(args []string) error {
 	if err != nil {
 		return fmt.Errorf("parsing --llm: %w", err)
 	}
-	provider, err := llm.NewProvider(llmCfg)
+	provider, err := newScrubbedProvider(llmCfg)
 	if err != nil {
 		return fmt.Errorf("creating LLM provider: %w", err)
 	}
+	defer reportScrubbed(provider)
 
 	// Open store
 	s, err := store.NewStore(getStoreConfig())
@@ -5220,7 +6334,14 @@ func runSummarize(args []string) error {
 		return fmt.Errorf("summarize requires SQLite store")
 	}
 
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Clusters summarized by an interrupted earlier run are skipped.
+	checkpoint, err := openBatchCheckpoint(ctx, s, "summarize", restart, dryRun)
+	if err != nil {
+		return fmt.Errorf("loading checkpoint: %w", err)
+	}
 
 	// Get clusters
 	clusters, err := sqlStore.ListClusters(ctx)
@@ -5242,14 +6363,21 @@ func runSummarize(args []string) error {
 		if clusterID > 0 && c.ID != clusterID {
 			continue
 		}
+		if checkpoint.has(summarizeCheckpointKey(c.ID)) {
+			continue
+		}
 
 		detail, err := sqlStore.GetClusterDetail(ctx, c.ID, 200)
 		if err != nil {
 			continue
 		}
+		visible, _, err := remoteLLMFacts(ctx, s, detail.Facts)
+		if err != nil || len(visible) < minClusterSize {
+			continue
+		}
 
-		facts := make([]extract.ClusterFactInput, 0, len(detail.Facts))
-		for _, f := range detail.Facts {
+		facts := make([]extract.ClusterFactInput, 0, len(visible))
+		for _, f := range visible {
 			facts = append(facts, extract.ClusterFactInput{
 				ID:         f.ID,
 				Subject:    f.Subject,
@@ -5268,6 +6396,7 @@ func runSummarize(args []string) error {
 	}
 
 	if len(clusterInputs) == 0 {
+		checkpoint.finish(ctx, 0, "clusters")
 		fmt.Printf("No clusters meet the minimum size (%d facts).\n", minClusterSize)
 		return nil
 	}
@@ -5277,33 +6406,34 @@ func runSummarize(args []string) error {
 		totalFacts += len(ci.Facts)
 	}
 
+	checkpoint.announceResume("clusters")
 	fmt.Printf("Summarizing %d clusters (%d total facts, model: %s)\n", len(clusterInputs), totalFacts, provider.Name())
 	if dryRun {
 		fmt.Println("DRY RUN — no changes will be applied")
 	}
 	fmt.Println()
 
+	// Each cluster is applied and checkpointed as soon as it is summarized,
+	// so a failure part-way keeps everything done so far.
 	opts := extract.SummarizeOpts{
 		MinClusterSize: minClusterSize,
 		ClusterID:      clusterID,
 		DryRun:         dryRun,
 	}
-
-	result, err := extract.SummarizeClusters(ctx, provider, clusterInputs, opts)
-	if err != nil {
-		return fmt.Errorf("summarization failed: %w", err)
-	}
-
-	// Apply changes (unless dry run)
 	applied := 0
-	if !dryRun && len(result.Summaries) > 0 {
-		for _, summary := range result.Summaries {
-			// Create new summary facts
+	var canary *canaryRun // set while a --canary sample is applied
+	canaryFacts := map[int64]extract.ClusterFactInput{}
+	if !dryRun {
+		applyCtx := context.WithoutCancel(ctx)
+		opts.OnCluster = func(summary extract.ClusterSummary) error {
+			clusterApplied := 0
+			// Create new summary facts. Each records which originals it
+			// replaced, so `cortex summarize undo` can revert the cluster.
 			for _, sf := range summary.SummaryFacts {
 				// Find a memory ID from one of the replaced facts
 				var memoryID int64
 				if len(sf.Replaces) > 0 {
-					oldFact, err := s.GetFact(ctx, sf.Replaces[0])
+					oldFact, err := s.GetFact(applyCtx, sf.Replaces[0])
 					if err == nil {
 						memoryID = oldFact.MemoryID
 					}
@@ -5321,20 +6451,72 @@ func runSummarize(args []string) error {
 					newFact.DecayRate = rate
 				}
 
-				newFactID, err := s.AddFact(ctx, newFact)
-				if err != nil {
+				if _, err := sqlStore.ApplyClusterSummary(applyCtx, summary.ClusterID, newFact, sf.Replaces, sf.Reasoning); err != nil {
 					continue
 				}
-
-				// Supersede old facts
-				for _, oldID := range sf.Replaces {
-					_ = sqlStore.SupersedeFact(ctx, oldID, newFactID, sf.Reasoning)
-				}
 				applied++
+				clusterApplied++
+				if canary != nil {
+					replaced := make([]string, 0, len(sf.Replaces))
+					for _, id := range sf.Replaces {
+						if f, ok := canaryFacts[id]; ok {
+							replaced = append(replaced, describeFact(f.ID, f.Subject, f.Predicate, f.Object))
+						}
+					}
+					canary.add(fmt.Sprintf("cluster %q: %s replaced by summary fact %q → %q → %q",
+						summary.ClusterName, strings.Join(replaced, "; "), sf.Subject, sf.Predicate, truncStr(sf.Object, 200)), nil)
+				}
 			}
+			if canary != nil {
+				if clusterApplied > 0 {
+					clusterID := summary.ClusterID
+					canary.addUndo(func(ctx context.Context) error {
+						_, err := sqlStore.UndoClusterSummary(ctx, clusterID, 0)
+						return err
+					})
+				}
+				canary.record(summarizeCheckpointKey(summary.ClusterID))
+				return nil
+			}
+			return checkpoint.record(ctx, summarizeCheckpointKey(summary.ClusterID))
 		}
 	}
 
+	// With --canary, a random sample of clusters is summarized and reviewed
+	// first; the rest only runs if the sample passes.
+	pending := clusterInputs
+	if canaryOpts.Enabled {
+		judge, err := canaryOpts.judge(provider)
+		if err != nil {
+			return err
+		}
+		for _, ci := range clusterInputs {
+			for _, f := range ci.Facts {
+				canaryFacts[f.ID] = f
+			}
+		}
+		sample, rest := canarySplit(clusterInputs, canaryOpts.Sample, newCanaryRand())
+		if canary, err = startCanary(ctx, sqlStore, "summarize", canaryOpts, len(sample), len(clusterInputs)); err != nil {
+			return err
+		}
+		fmt.Printf("Canary: summarizing %d of %d clusters first\n", len(sample), len(clusterInputs))
+		if _, err := extract.SummarizeClusters(ctx, provider, sample, opts); err != nil {
+			_ = canary.rollback(ctx)
+			return fmt.Errorf("canary summarization failed: %w", err)
+		}
+		if _, err := canary.conclude(ctx, judge, checkpoint, canaryOutput(jsonOutput)); err != nil {
+			return err
+		}
+		canary = nil
+		pending = rest
+	}
+
+	result, err := extract.SummarizeClusters(ctx, provider, pending, opts)
+	if err != nil {
+		return fmt.Errorf("summarization failed: %w", err)
+	}
+	checkpoint.finish(ctx, len(clusterInputs), "clusters")
+
 	// Output
 	if jsonOutput {
 		data, _ := json.MarshalIndent(result, "", "  ")
@@ -5350,6 +6532,12 @@ func runSummarize(args []string) error {
 	if result.TotalOriginal > 0 {
 		fmt.Printf("  Compression:        %.1fx\n", float64(result.TotalOriginal)/float64(max(result.TotalNew, 1)))
 	}
+	if result.Errors > 0 {
+		fmt.Printf("  Errors:             %d clusters\n", result.Errors)
+	}
+	if result.StopReason != "" {
+		fmt.Printf("  Not attempted:      %d clusters (%s)\n", result.Skipped, result.StopReason)
+	}
 
 	for _, s := range result.Summaries {
 		fmt.Printf("\n  📦 %s (cluster #%d):\n", s.ClusterName, s.ClusterID)
@@ -5371,11 +6559,66 @@ func runSummarize(args []string) error {
 
 	if !dryRun {
 		fmt.Printf("\n  ✅ Applied: %d summary facts created, %d originals superseded\n", applied, result.TotalSupersede)
+		fmt.Printf("     Revert a cluster within %d days: cortex summarize undo <cluster-id>\n", int(summarizeUndoWindow.Hours()/24))
 	}
 
 	return nil
 }
 
+func runSummarizeUndo(args []string) error {
+	usage := "usage: cortex summarize undo <cluster-id> [--json]"
+	var clusterID int64
+	jsonOutput := false
+	for _, a := range args {
+		switch {
+		case a == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(a, "-"):
+			return fmt.Errorf("unknown flag: %s\n%s", a, usage)
+		case clusterID == 0:
+			n, err := strconv.ParseInt(strings.TrimPrefix(a, "#"), 10, 64)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid cluster id: %s", a)
+			}
+			clusterID = n
+		default:
+			return fmt.Errorf("%s", usage)
+		}
+	}
+	if clusterID == 0 {
+		return fmt.Errorf("%s", usage)
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("summarize undo requires SQLite store")
+	}
+
+	res, err := sqlStore.UndoClusterSummary(context.Background(), clusterID, summarizeUndoWindow)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		data, _ := json.MarshalIndent(res, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Printf("↩️  Undid cluster #%d summary (from %s): %d summary %s removed, %d %s restored\n",
+		clusterID, res.SummarizedAt.Local().Format("2006-01-02 15:04"),
+		res.SummaryFacts, pluralize("fact", "facts", res.SummaryFacts),
+		res.RestoredFacts, pluralize("original", "originals", res.RestoredFacts))
+	if res.SkippedOriginals > 0 {
+		fmt.Printf("   %d %s changed since the summary and kept their current state\n",
+			res.SkippedOriginals, pluralize("original", "originals", res.SkippedOriginals))
+	}
+	return nil
+}
+
 func truncStr(s string, n int) string {
 	if len(s) <= n {
 		return s
@@ -5386,8 +6629,9 @@ func truncStr(s string, n int) string {
 func runList(args []string) error {
 	// Parse flags
 	var limit int = 20
-	var sourceFile, factType, classFlag, agentFlag string
-	var listFacts, jsonOutput, includeSuperseded bool
+	var offset int
+	var sourceFile, factType, classFlag, agentFlag, cursor string
+	var listFacts, jsonOutput, includeSuperseded, countFlag bool
 
 	for i := 0; i < len(args); i++ {
 		switch {
@@ -5404,6 +6648,22 @@ func runList(args []string) error {
 			if limit, err = strconv.Atoi(strings.TrimPrefix(args[i], "--limit=")); err != nil {
 				return fmt.Errorf("invalid --limit value: %s", args[i])
 			}
+		case args[i] == "--offset" && i+1 < len(args):
+			i++
+			var err error
+			if offset, err = strconv.Atoi(args[i]); err != nil || offset < 0 {
+				return fmt.Errorf("invalid --offset value: %s", args[i])
+			}
+		case strings.HasPrefix(args[i], "--offset="):
+			var err error
+			if offset, err = strconv.Atoi(strings.TrimPrefix(args[i], "--offset=")); err != nil || offset < 0 {
+				return fmt.Errorf("invalid --offset value: %s", args[i])
+			}
+		case args[i] == "--cursor" && i+1 < len(args):
+			i++
+			cursor = args[i]
+		case strings.HasPrefix(args[i], "--cursor="):
+			cursor = strings.TrimPrefix(args[i], "--cursor=")
 		case args[i] == "--source" && i+1 < len(args):
 			i++
 			sourceFile = args[i]
@@ -5426,6 +6686,8 @@ func runList(args []string) error {
 			agentFlag = strings.TrimPrefix(args[i], "--agent=")
 		case args[i] == "--json":
 			jsonOutput = true
+		case args[i] == "--count":
+			countFlag = true
 		case args[i] == "--include-superseded":
 			includeSuperseded = true
 		case strings.HasPrefix(args[i], "-"):
@@ -5437,12 +6699,11 @@ func runList(args []string) error {
 
 	// Validate fact type if provided
 	if factType != "" {
-		validTypes := map[string]bool{
-			"kv": true, "relationship": true, "preference": true, "temporal": true,
-			"identity": true, "location": true, "decision": true, "state": true, "config": true,
+		if resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{}); err == nil {
+			applyExtractionRuntimeConfig(resolvedCfg)
 		}
-		if !validTypes[factType] {
-			return fmt.Errorf("unknown fact type %q (valid: kv, relationship, preference, temporal, identity, location, decision, state, config)", factType)
+		if _, _, err := store.ResolveFactType(factType); err != nil {
+			return err
 		}
 	}
 
@@ -5464,7 +6725,8 @@ func runList(args []string) error {
 
 	opts := store.ListOpts{
 		Limit:             limit,
-		Offset:            0,
+		Offset:            offset,
+		Cursor:            cursor,
 		SourceFile:        sourceFile,
 		FactType:          factType,
 		MemoryClasses:     classes,
@@ -5478,21 +6740,94 @@ func runList(args []string) error {
 			return fmt.Errorf("listing facts: %w", err)
 		}
 
+		next := ""
+		if len(facts) > 0 && len(facts) == limit {
+			next = store.FactsCursor(opts.SortBy, facts[len(facts)-1])
+		}
+		total := 0
+		if countFlag {
+			if total, err = countListRows(ctx, s, opts, true); err != nil {
+				return err
+			}
+		}
 		if jsonOutput || !isTTY() {
-			return outputListFactsJSON(facts)
+			if countFlag {
+				if facts == nil {
+					facts = []*store.Fact{}
+				}
+				return outputCountedJSON(facts, len(facts), total, false, next)
+			}
+			err = outputListFactsJSON(facts)
+		} else {
+			err = outputListFactsTTY(facts, opts)
+			if err == nil && countFlag {
+				printCountFooter("facts", len(facts), total, false)
+			}
 		}
-		return outputListFactsTTY(facts, opts)
+		if err == nil {
+			printNextCursorHint("cortex list --facts", next, jsonOutput || !isTTY())
+		}
+		return err
 	} else {
 		memories, err := s.ListMemories(ctx, opts)
 		if err != nil {
 			return fmt.Errorf("listing memories: %w", err)
 		}
 
+		next := ""
+		if len(memories) > 0 && len(memories) == limit {
+			next = store.MemoriesCursor(memories[len(memories)-1])
+		}
+		total := 0
+		if countFlag {
+			if total, err = countListRows(ctx, s, opts, false); err != nil {
+				return err
+			}
+		}
 		if jsonOutput || !isTTY() {
-			return outputListMemoriesJSON(memories)
+			if countFlag {
+				if memories == nil {
+					memories = []*store.Memory{}
+				}
+				return outputCountedJSON(memories, len(memories), total, false, next)
+			}
+			err = outputListMemoriesJSON(memories)
+		} else {
+			err = outputListMemoriesTTY(memories, opts)
+			if err == nil && countFlag {
+				printCountFooter("memories", len(memories), total, false)
+			}
+		}
+		if err == nil {
+			printNextCursorHint("cortex list", next, jsonOutput || !isTTY())
 		}
-		return outputListMemoriesTTY(memories, opts)
+		return err
+	}
+}
+
+// countListRows totals the rows a `cortex list` page was drawn from.
+func countListRows(ctx context.Context, s store.Store, opts store.ListOpts, facts bool) (int, error) {
+	ss, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return 0, fmt.Errorf("--count requires SQLiteStore")
+	}
+	if facts {
+		return ss.CountFacts(ctx, opts)
 	}
+	return ss.CountMemories(ctx, opts)
+}
+
+// printNextCursorHint tells the user how to fetch the next page. Machine
+// output keeps stdout clean, so the hint goes to stderr there.
+func printNextCursorHint(command, cursor string, machine bool) {
+	if cursor == "" {
+		return
+	}
+	if machine {
+		fmt.Fprintf(os.Stderr, "next_cursor: %s\n", cursor)
+		return
+	}
+	fmt.Printf("\nNext page: %s --cursor %s\n", command, cursor)
 }
 
 func runExport(args []string) error {
@@ -5503,12 +6838,45 @@ func runExport(args []string) error {
 	// Parse flags
 	var format string = "json"
 	var outputFile string
-	var exportFacts bool
+	var exportFacts, exportAll bool
+	var documentRef string
+	var project, classFlag string
+	var since time.Time
 
 	for i := 0; i < len(args); i++ {
 		switch {
 		case args[i] == "--facts":
 			exportFacts = true
+		case args[i] == "--all":
+			exportAll = true
+		case args[i] == "--project" && i+1 < len(args):
+			i++
+			project = args[i]
+		case strings.HasPrefix(args[i], "--project="):
+			project = strings.TrimPrefix(args[i], "--project=")
+		case args[i] == "--class" && i+1 < len(args):
+			i++
+			classFlag = args[i]
+		case strings.HasPrefix(args[i], "--class="):
+			classFlag = strings.TrimPrefix(args[i], "--class=")
+		case args[i] == "--since" && i+1 < len(args):
+			i++
+			t, err := parseExportSince(args[i])
+			if err != nil {
+				return err
+			}
+			since = t
+		case strings.HasPrefix(args[i], "--since="):
+			t, err := parseExportSince(strings.TrimPrefix(args[i], "--since="))
+			if err != nil {
+				return err
+			}
+			since = t
+		case args[i] == "--document" && i+1 < len(args):
+			i++
+			documentRef = args[i]
+		case strings.HasPrefix(args[i], "--document="):
+			documentRef = strings.TrimPrefix(args[i], "--document=")
 		case args[i] == "--format" && i+1 < len(args):
 			i++
 			format = args[i]
@@ -5527,19 +6895,51 @@ func runExport(args []string) error {
 	}
 
 	// Validate format
-	if format != "json" && format != "markdown" && format != "csv" {
-		return fmt.Errorf("unsupported format: %s (supported: json, markdown, csv)", format)
+	if format != "json" && format != "jsonl" && format != "markdown" && format != "csv" {
+		return fmt.Errorf("unsupported format: %s (supported: json, jsonl, markdown, csv)", format)
+	}
+	if exportAll && (format != "jsonl" || exportFacts) {
+		return fmt.Errorf("--all needs --format jsonl and can't be combined with --facts")
+	}
+	classes, err := store.ParseMemoryClassList(classFlag)
+	if err != nil {
+		return err
 	}
 
 	// Open store
-	s, err := store.NewStore(getStoreConfig())
+	live, err := store.NewStore(getStoreConfig())
 	if err != nil {
 		return fmt.Errorf("opening store: %w", err)
 	}
-	defer s.Close()
+	defer live.Close()
 
 	ctx := context.Background()
 
+	// Export from a point-in-time snapshot so concurrent writers (MCP
+	// server, daemon) can't leave memories and facts out of step.
+	s, snapshot, cleanup, err := openSnapshotStore(ctx, live)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	listOpts := store.ListOpts{Project: project, MemoryClasses: classes}
+	if !since.IsZero() {
+		listOpts.After = since.UTC().Format("2006-01-02 15:04:05")
+	}
+	// --document narrows the export to one source file's chunks and facts.
+	if documentRef != "" {
+		ss, ok := s.(*store.SQLiteStore)
+		if !ok {
+			return fmt.Errorf("--document requires SQLiteStore")
+		}
+		doc, err := resolveDocument(ctx, ss, documentRef)
+		if err != nil {
+			return err
+		}
+		listOpts.SourceFile = doc.SourceFile
+	}
+
 	// Set output destination
 	output := os.Stdout
 	if outputFile != "" {
@@ -5551,18 +6951,35 @@ func runExport(args []string) error {
 		output = file
 	}
 
+	if format == "jsonl" {
+		kind := "memories"
+		if exportFacts {
+			kind = "facts"
+		} else if exportAll {
+			kind = "all"
+		}
+		return exportJSONL(ctx, s, listOpts, kind, output, snapshot)
+	}
 	if exportFacts {
-		facts, err := s.ListFacts(ctx, store.ListOpts{Limit: math.MaxInt32}) // TODO: Add pagination for v0.2
+		var facts []*store.Fact
+		err := streamExportFacts(ctx, s, listOpts, func(page []*store.Fact) error {
+			facts = append(facts, page...)
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("listing facts: %w", err)
+			return err
 		}
-		return exportFactsInFormat(facts, format, output)
+		return exportFactsInFormat(facts, format, output, snapshot)
 	} else {
-		memories, err := s.ListMemories(ctx, store.ListOpts{Limit: math.MaxInt32}) // TODO: Add pagination for v0.2
+		var memories []*store.Memory
+		err := streamExportMemories(ctx, s, listOpts, func(page []*store.Memory) error {
+			memories = append(memories, page...)
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("listing memories: %w", err)
+			return err
 		}
-		return exportMemoriesInFormat(memories, format, output)
+		return exportMemoriesInFormat(memories, format, output, snapshot)
 	}
 }
 
@@ -5571,11 +6988,14 @@ type ExtractionStats struct {
 	FactsExtracted      int
 	RulesFactsExtracted int
 	LLMFactsExtracted   int
+	Skipped             int // memories on the zero-yield skip list
 	FactIDs             []int64
 }
 
 // runExtractionOnImportedMemories runs extraction on recently imported memories.
-func runExtractionOnImportedMemories(ctx context.Context, s store.Store, llmFlag string, newMemoryIDs []int64, progressFn func(done, total, facts int)) (*ExtractionStats, error) {
+// With useSkipList, memories whose content extraction has repeatedly found
+// nothing in are left alone (see store.ZeroYieldSkipAfter).
+func runExtractionOnImportedMemories(ctx context.Context, s store.Store, llmFlag string, newMemoryIDs []int64, useSkipList bool, progressFn func(done, total, facts int)) (*ExtractionStats, error) {
 	// Only process newly imported memories — never re-extract on existing ones
 	if len(newMemoryIDs) == 0 {
 		return &ExtractionStats{}, nil
@@ -5600,11 +7020,52 @@ func runExtractionOnImportedMemories(ctx context.Context, s store.Store, llmFlag
 	}
 
 	pipeline := extract.NewPipeline(llmConfig)
+	rulesOnly := pipeline
+	if llmConfig != nil && !llmConfig.IsLocal() {
+		rulesOnly = extract.NewPipeline(nil)
+		defer func() { printScrubReport(llmConfig.Provider+"/"+llmConfig.Model, pipeline.ScrubReport()) }()
+	}
 	stats := &ExtractionStats{}
 	processed := 0
 	total := len(memories)
+	// Each extractor keeps its own skip list: content the rules found
+	// nothing in may still hold facts for the model.
+	llmStage := store.YieldStageExtract
+	if llmConfig != nil {
+		llmStage = store.ExtractionYieldStage(llmConfig.Provider + "/" + llmConfig.Model)
+	}
+	stageFor := func(m *store.Memory) (*extract.Pipeline, string) {
+		if !store.ProjectAllows(m.Project, store.PrivacyLocalLLMOnly) && rulesOnly != pipeline {
+			return rulesOnly, store.YieldStageExtract
+		}
+		return pipeline, llmStage
+	}
+	yieldStore, _ := s.(*store.SQLiteStore)
+	skip := map[int64]bool{}
+	if yieldStore != nil && useSkipList {
+		byStage := map[string][]*store.Memory{}
+		for _, m := range memories {
+			_, stage := stageFor(m)
+			byStage[stage] = append(byStage[stage], m)
+		}
+		for stage, group := range byStage {
+			skipped, err := yieldStore.ZeroYieldMemories(ctx, stage, group)
+			if err != nil {
+				return nil, err
+			}
+			for id := range skipped {
+				skip[id] = true
+			}
+		}
+	}
 
 	for _, memory := range memories {
+		if skip[memory.ID] {
+			stats.Skipped++
+			processed++
+			continue
+		}
+
 		// Build metadata
 		metadata := map[string]string{
 			"source_file": memory.SourceFile,
@@ -5619,11 +7080,15 @@ func runExtractionOnImportedMemories(ctx context.Context, s store.Store, llmFlag
 			metadata["timestamp_start"] = memory.Metadata.TimestampStart
 		}
 
-		// Extract facts
-		facts, err := pipeline.Extract(ctx, memory.Content, metadata)
+		// Extract facts; local-llm-only projects never reach a remote model.
+		p, stage := stageFor(memory)
+		facts, err := p.Extract(ctx, memory.Content, metadata)
 		if err != nil {
 			continue // Skip errors, continue with next memory
 		}
+		if yieldStore != nil {
+			_ = yieldStore.RecordExtractionYield(ctx, stage, memory.Content, len(facts))
+		}
 
 		// Store facts and track extraction method
 		for _, extractedFact := range facts {
@@ -5637,6 +7102,7 @@ func runExtractionOnImportedMemories(ctx context.Context, s store.Store, llmFlag
 				DecayRate:    extractedFact.DecayRate,
 				SourceQuote:  extractedFact.SourceQuote,
 				TemporalNorm: extractedFact.TemporalNorm,
+				Negated:      extractedFact.Negated,
 			}
 			store.ApplyMemoryScopeToFact(memory, fact)
 
@@ -5671,17 +7137,31 @@ func runExtractionOnImportedMemories(ctx context.Context, s store.Store, llmFlag
 	return stats, nil
 }
 
+// printSkipListNotice reports memories left out because their content is on
+// the zero-yield skip list.
+func printSkipListNotice(skipped int) {
+	if skipped == 0 {
+		return
+	}
+	fmt.Printf("  ⏭  Skipped %d %s that repeatedly yielded no facts (--no-skip-list to retry)\n",
+		skipped, pluralize("memory", "memories", skipped))
+}
+
 // EnrichmentStats holds statistics about LLM enrichment run.
 type EnrichmentStats struct {
 	NewFacts   int
+	Queued     int // held for review (confidence below extract.review_threshold)
+	Skipped    int // memories on the zero-yield skip list
 	AvgLatency time.Duration
 	FactIDs    []int64
 }
 
 // runEnrichmentOnImportedMemories runs LLM enrichment on recently imported memories.
 // For each memory, it re-runs rule extraction to get the baseline, then asks the LLM
-// what the rules missed. New facts are stored with extraction_method="llm-enrich".
-func runEnrichmentOnImportedMemories(ctx context.Context, s store.Store, llmFlag string, newMemoryIDs []int64) (*EnrichmentStats, error) {
+// what the rules missed. New facts are stored with extraction_method="llm-enrich";
+// those below enrichReviewThreshold go to the review queue instead. With
+// useSkipList, memories the LLM has repeatedly found nothing new in are skipped.
+func runEnrichmentOnImportedMemories(ctx context.Context, s store.Store, llmFlag string, newMemoryIDs []int64, useSkipList bool) (*EnrichmentStats, error) {
 	// Only enrich newly imported memories — never re-enrich existing ones
 	if len(newMemoryIDs) == 0 {
 		return &EnrichmentStats{}, nil
@@ -5692,26 +7172,41 @@ func runEnrichmentOnImportedMemories(ctx context.Context, s store.Store, llmFlag
 	if err != nil {
 		return nil, fmt.Errorf("parsing --llm flag: %w", err)
 	}
-	provider, err := llm.NewProvider(llmCfg)
+	provider, err := newScrubbedProvider(llmCfg)
 	if err != nil {
 		return nil, fmt.Errorf("creating LLM provider: %w", err)
 	}
+	defer reportScrubbed(provider)
 
 	memories, err := s.GetMemoriesByIDs(ctx, newMemoryIDs)
 	if err != nil {
 		return nil, fmt.Errorf("fetching new memories: %w", err)
 	}
+	// Enrichment providers are remote; local-llm-only projects are skipped.
+	memories, _ = store.FilterMemoriesByPrivacy(memories, store.PrivacyLocalLLMOnly)
 
 	pipeline := extract.NewPipeline()
+	examples := newEnrichExampleSelector(ctx, s)
+	reviewStore, _ := s.(*store.SQLiteStore)
 	stats := &EnrichmentStats{}
 	var totalLatency time.Duration
 	enrichCount := 0
+	var skip map[int64]bool
+	if reviewStore != nil && useSkipList {
+		if skip, err = reviewStore.ZeroYieldMemories(ctx, store.YieldStageEnrich, memories); err != nil {
+			return nil, err
+		}
+	}
 
 	for _, memory := range memories {
 		// Skip very short content
 		if len(strings.TrimSpace(memory.Content)) < 50 {
 			continue
 		}
+		if skip[memory.ID] {
+			stats.Skipped++
+			continue
+		}
 
 		// Get rule-extracted facts as baseline
 		metadata := map[string]string{
@@ -5737,7 +7232,7 @@ func runEnrichmentOnImportedMemories(ctx context.Context, s store.Store, llmFlag
 		if memory.Metadata != nil {
 			anchor = memory.Metadata.TimestampStart
 		}
-		result, err := extract.EnrichFacts(ctx, provider, memory.Content, ruleFacts, anchor)
+		result, err := extract.EnrichFactsWithExamples(ctx, provider, memory.Content, ruleFacts, anchor, examples.pick(ctx, memory.Content))
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "  Enrichment warning (memory %d): %v\n", memory.ID, err)
 			continue
@@ -5745,6 +7240,9 @@ func runEnrichmentOnImportedMemories(ctx context.Context, s store.Store, llmFlag
 
 		totalLatency += result.Latency
 		enrichCount++
+		if reviewStore != nil {
+			_ = reviewStore.RecordExtractionYield(ctx, store.YieldStageEnrich, memory.Content, len(result.NewFacts))
+		}
 
 		// Store new facts
 		for _, ef := range result.NewFacts {
@@ -5757,11 +7255,30 @@ func runEnrichmentOnImportedMemories(ctx context.Context, s store.Store, llmFlag
 				Confidence:  ef.Confidence,
 				DecayRate:   ef.DecayRate,
 				SourceQuote: ef.SourceQuote,
+				Negated:     ef.Negated,
 			}
 			if !ingest.ShouldStoreExtractedFact(fact) {
 				continue
 			}
 
+			if reviewStore != nil && fact.Confidence < enrichReviewThreshold {
+				if _, err := reviewStore.QueueFactReview(ctx, &store.FactReview{
+					MemoryID:    fact.MemoryID,
+					Subject:     fact.Subject,
+					Predicate:   fact.Predicate,
+					Object:      fact.Object,
+					FactType:    fact.FactType,
+					Confidence:  fact.Confidence,
+					DecayRate:   fact.DecayRate,
+					SourceQuote: fact.SourceQuote,
+					Method:      ef.ExtractionMethod,
+					Model:       result.Model,
+				}); err == nil {
+					stats.Queued++
+				}
+				continue
+			}
+
 			factID, err := s.AddFact(ctx, fact)
 			if err != nil {
 				continue
@@ -5809,6 +7326,10 @@ func classifyImportedKVFacts(ctx context.Context, s store.Store, llmFlag string,
 		return nil, fmt.Errorf("listing kv facts for new memories: %w", err)
 	}
 
+	kvFacts, _, err = remoteLLMFacts(ctx, s, kvFacts)
+	if err != nil {
+		return nil, fmt.Errorf("checking privacy zones: %w", err)
+	}
 	if len(kvFacts) == 0 {
 		return &ClassifyImportStats{}, nil
 	}
@@ -5818,10 +7339,11 @@ func classifyImportedKVFacts(ctx context.Context, s store.Store, llmFlag string,
 	if err != nil {
 		return nil, fmt.Errorf("parsing LLM flag: %w", err)
 	}
-	provider, err := llm.NewProvider(llmCfg)
+	provider, err := newScrubbedProvider(llmCfg)
 	if err != nil {
 		return nil, fmt.Errorf("creating LLM provider: %w", err)
 	}
+	defer reportScrubbed(provider)
 
 	// Convert to ClassifyableFact format
 	classifyFacts := make([]extract.ClassifyableFact, len(kvFacts))
@@ -5919,7 +7441,15 @@ func runEmbeddingOnImportedMemories(ctx context.Context, s store.Store, embedFla
 
 func runReinforce(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: cortex reinforce <fact_id> [fact_id...]")
+		return fmt.Errorf("usage: cortex reinforce <fact_id> [fact_id...] | cortex reinforce --stdin")
+	}
+	if len(args) == 1 && args[0] == "--stdin" {
+		s, err := store.NewStore(getStoreConfig())
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+		defer s.Close()
+		return runReinforceStdin(context.Background(), s)
 	}
 
 	s, err := store.NewStore(getStoreConfig())
@@ -5954,7 +7484,29 @@ func runReinforce(args []string) error {
 
 func runSupersede(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: cortex supersede <old_fact_id> --by <new_fact_id> [--reason <text>]")
+		return fmt.Errorf("usage: cortex supersede <old_fact_id> --by <new_fact_id> [--reason <text>] | cortex supersede --stdin [--reason <text>]")
+	}
+	if args[0] == "--stdin" {
+		reason := ""
+		for i := 1; i < len(args); i++ {
+			switch {
+			case args[i] == "--reason" && i+1 < len(args):
+				i++
+				reason = args[i]
+			case strings.HasPrefix(args[i], "--reason="):
+				reason = strings.TrimPrefix(args[i], "--reason=")
+			default:
+				return fmt.Errorf("unknown argument: %s", args[i])
+			}
+		}
+		s, err := store.NewStore(getStoreConfig())
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+		defer s.Close()
+		wireWebhook(s)
+		defer flushWebhook(s)
+		return runSupersedeStdin(context.Background(), s, reason)
 	}
 
 	oldFactID, err := strconv.ParseInt(args[0], 10, 64)
@@ -5998,6 +7550,8 @@ func runSupersede(args []string) error {
 		return fmt.Errorf("opening store: %w", err)
 	}
 	defer s.Close()
+	wireWebhook(s)
+	defer flushWebhook(s)
 
 	ctx := context.Background()
 	if err := s.SupersedeFact(ctx, oldFactID, newFactID, reason); err != nil {
@@ -6052,6 +7606,41 @@ func runFactHistory(args []string) error {
 	}
 	fmt.Println()
 
+	sources, err := sqlStore.ListFactSources(ctx, factID)
+	if err != nil {
+		return fmt.Errorf("getting sources: %w", err)
+	}
+	if len(sources) > 1 || (len(sources) == 1 && !sources[0].Primary) {
+		fmt.Printf("📚 Sources (%d memories):\n", len(sources))
+		printFactSources(sources)
+		fmt.Println()
+	}
+
+	corroborations, err := sqlStore.ListFactCorroborations(ctx, factID)
+	if err != nil {
+		return fmt.Errorf("getting corroborations: %w", err)
+	}
+	if len(corroborations) > 0 {
+		fmt.Printf("🤝 Corroborated by %d independent source(s):\n", len(corroborations))
+		for _, c := range corroborations {
+			fmt.Printf("  %s  %s (memory #%d)  confidence %.2f → %.2f\n",
+				c.CreatedAt.Format("2006-01-02 15:04"), c.SourceFile, c.MemoryID, c.ConfidenceBefore, c.ConfidenceAfter)
+		}
+		fmt.Println()
+	}
+
+	notes, err := sqlStore.ListAnnotations(ctx, store.AnnotationTargetFact, factID)
+	if err != nil {
+		return fmt.Errorf("getting notes: %w", err)
+	}
+	if len(notes) > 0 {
+		fmt.Printf("📝 Notes:\n")
+		for _, n := range notes {
+			fmt.Printf("  %s\n", formatAnnotationLine(n))
+		}
+		fmt.Println()
+	}
+
 	// Get access summary
 	summary, err := sqlStore.GetFactAccessSummary(ctx, factID)
 	if err != nil {
@@ -6077,18 +7666,22 @@ func runFactHistory(args []string) error {
 
 func runEdge(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: cortex edge [add|list|remove] ...")
+		return fmt.Errorf("usage: cortex edge [add|import|list|remove|learn] ...")
 	}
 
 	switch args[0] {
 	case "add":
 		return runEdgeAdd(args[1:])
+	case "import":
+		return runEdgeImport(args[1:])
 	case "list":
 		return runEdgeList(args[1:])
 	case "remove":
 		return runEdgeRemove(args[1:])
+	case "learn":
+		return runEdgeLearn(args[1:])
 	default:
-		return fmt.Errorf("unknown edge subcommand: %s (use add, list, remove)", args[0])
+		return fmt.Errorf("unknown edge subcommand: %s (use add, import, list, remove, learn)", args[0])
 	}
 }
 
@@ -6202,7 +7795,10 @@ func runEdgeList(args []string) error {
 
 func runEdgeRemove(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: cortex edge remove <edge_id>")
+		return fmt.Errorf("%s", edgeRemoveUsage)
+	}
+	if strings.HasPrefix(args[0], "-") {
+		return runEdgeRemoveBulk(args)
 	}
 
 	edgeID, err := strconv.ParseInt(args[0], 10, 64)
@@ -6294,7 +7890,7 @@ func runInfer(args []string) error {
 }
 
 func graphUsageError() error {
-	return fmt.Errorf("usage: cortex graph <fact_id> [--depth 2] [--min-confidence 0.5] [--export json] [--agent <id>]\n       cortex graph --subject \"topic\" [--depth 2] [--min-confidence 0.5] [--export json] [--agent <id>]\n       cortex graph --serve [--port 8090]")
+	return fmt.Errorf("usage: cortex graph <fact_id> [--depth 2] [--min-confidence 0.5] [--export json] [--agent <id>]\n       cortex graph --subject \"topic\" [--depth 2] [--min-confidence 0.5] [--export json] [--agent <id>]\n       cortex graph --serve [--port 8090]\n       cortex graph metrics [--min-confidence 0.5] [--top 10] [--json]")
 }
 
 func runGraph(args []string) error {
@@ -6306,6 +7902,9 @@ func runGraph(args []string) error {
 	if args[0] == "--serve" {
 		return runGraphServe(args[1:])
 	}
+	if args[0] == "metrics" {
+		return runGraphMetrics(args[1:])
+	}
 
 	depth := 2
 	minConf := 0.0
@@ -6624,6 +8223,9 @@ func runGraphServe(args []string) error {
 		Store:       sqlStore,
 		Port:        port,
 		AgentFilter: agentFilter,
+		Readiness:   readinessHandler(s, nil),
+		Status:      statusHandler(s),
+		Version:     version,
 	})
 }
 
@@ -6981,50 +8583,11 @@ func runUpdate(args []string) error {
 		return err
 	}
 
-	factCount := int64(0)
+	factCount := 0
 	if reextract {
-		if _, err := s.DeleteFactsByMemoryID(ctx, memoryID); err != nil {
+		if factCount, err = ingest.ReextractMemoryFacts(ctx, s, memory, content); err != nil {
 			return err
 		}
-
-		pipeline := extract.NewPipeline()
-		metadata := map[string]string{"source_file": memory.SourceFile}
-		if strings.HasSuffix(strings.ToLower(memory.SourceFile), ".md") {
-			metadata["format"] = "markdown"
-		}
-		if memory.SourceSection != "" {
-			metadata["source_section"] = memory.SourceSection
-		}
-		if memory.Metadata != nil && strings.TrimSpace(memory.Metadata.TimestampStart) != "" {
-			metadata["timestamp_start"] = memory.Metadata.TimestampStart
-		}
-
-		extractedFacts, err := pipeline.Extract(ctx, content, metadata)
-		if err != nil {
-			return fmt.Errorf("re-extracting facts: %w", err)
-		}
-
-		for _, extractedFact := range extractedFacts {
-			fact := &store.Fact{
-				MemoryID:     memoryID,
-				Subject:      extractedFact.Subject,
-				Predicate:    extractedFact.Predicate,
-				Object:       extractedFact.Object,
-				FactType:     extractedFact.FactType,
-				Confidence:   extractedFact.Confidence,
-				DecayRate:    extractedFact.DecayRate,
-				SourceQuote:  extractedFact.SourceQuote,
-				TemporalNorm: extractedFact.TemporalNorm,
-			}
-			store.ApplyMemoryScopeToFact(memory, fact)
-			_, stored, err := ingest.StoreExtractedFact(ctx, s, fact)
-			if err != nil {
-				continue
-			}
-			if stored {
-				factCount++
-			}
-		}
 	}
 
 	fmt.Printf("✅ Updated memory %d\n", memoryID)
@@ -7034,9 +8597,18 @@ func runUpdate(args []string) error {
 	return nil
 }
 
+// parseImportWorkers parses --workers for import and reimport.
+func parseImportWorkers(raw string) (int, error) {
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 || n > 64 {
+		return 0, fmt.Errorf("invalid --workers value: %s (must be 1-64)", raw)
+	}
+	return n, nil
+}
+
 func runReimport(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: cortex reimport <path> [--recursive] [--extract] [--no-enrich] [--no-classify] [--embed <provider/model>] [--force]")
+		return fmt.Errorf("usage: cortex reimport <path> [--recursive] [--extract] [--no-enrich] [--no-classify] [--no-skip-list] [--workers N] [--embed <provider/model>] [--force]")
 	}
 
 	// Parse flags
@@ -7045,9 +8617,11 @@ func runReimport(args []string) error {
 	enableExtraction := false
 	noEnrich := false
 	noClassify := false
+	noSkipList := false
 	embedFlag := ""
 	llmFlag := ""
 	force := false
+	workers := ingest.DefaultImportWorkers
 
 	for i := 0; i < len(args); i++ {
 		switch {
@@ -7059,6 +8633,8 @@ func runReimport(args []string) error {
 			noEnrich = true
 		case args[i] == "--no-classify":
 			noClassify = true
+		case args[i] == "--no-skip-list":
+			noSkipList = true
 		case args[i] == "--embed" && i+1 < len(args):
 			i++
 			embedFlag = args[i]
@@ -7071,6 +8647,19 @@ func runReimport(args []string) error {
 			llmFlag = strings.TrimPrefix(args[i], "--llm=")
 		case args[i] == "--force" || args[i] == "-f":
 			force = true
+		case args[i] == "--workers" && i+1 < len(args):
+			i++
+			n, err := parseImportWorkers(args[i])
+			if err != nil {
+				return err
+			}
+			workers = n
+		case strings.HasPrefix(args[i], "--workers="):
+			n, err := parseImportWorkers(strings.TrimPrefix(args[i], "--workers="))
+			if err != nil {
+				return err
+			}
+			workers = n
 		case strings.HasPrefix(args[i], "-"):
 			return fmt.Errorf("unknown flag: %s", args[i])
 		default:
@@ -7110,10 +8699,12 @@ func runReimport(args []string) error {
 	}
 
 	// Auto-backup before wipe (safety net for failed/incomplete reimports)
+	backupPath := ""
 	if _, statErr := os.Stat(dbPath); statErr == nil {
-		backupPath := dbPath + ".pre-reimport"
-		if copyErr := copyFile(dbPath, backupPath); copyErr != nil {
-			fmt.Fprintf(os.Stderr, "  Warning: could not backup DB to %s: %v\n", backupPath, copyErr)
+		backupPath = dbPath + ".pre-reimport"
+		if backupErr := backupDatabase(dbPath, backupPath); backupErr != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: could not backup DB to %s: %v\n", backupPath, backupErr)
+			backupPath = ""
 		} else {
 			fmt.Printf("  ✓ Backed up to %s\n", backupPath)
 		}
@@ -7138,8 +8729,17 @@ func runReimport(args []string) error {
 
 	engine := ingest.NewEngine(s)
 	ctx := context.Background()
+
+	// Keep the zero-yield skip list: the wiped store is about to see the
+	// same content again.
+	if sqlStore, ok := s.(*store.SQLiteStore); ok && backupPath != "" {
+		if _, err := sqlStore.CarryOverExtractionYield(ctx, backupPath); err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: could not carry over the extraction skip list: %v\n", err)
+		}
+	}
 	opts := ingest.ImportOptions{
 		Recursive: recursive,
+		Workers:   workers,
 		ProgressFn: func(current, total int, file string) {
 			fmt.Printf("  [%d/%d] %s\n", current, total, file)
 		},
@@ -7164,11 +8764,12 @@ func runReimport(args []string) error {
 	// Run extraction if requested — only on newly imported memories
 	if enableExtraction && totalResult.MemoriesNew > 0 {
 		fmt.Println("  Extracting facts...")
-		extractionStats, err := runExtractionOnImportedMemories(ctx, s, llmFlag, totalResult.NewMemoryIDs, nil)
+		extractionStats, err := runExtractionOnImportedMemories(ctx, s, llmFlag, totalResult.NewMemoryIDs, !noSkipList, nil)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "  Extraction error: %v\n", err)
 		} else {
 			fmt.Printf("  ✓ Extracted %d facts\n", extractionStats.FactsExtracted)
+			printSkipListNotice(extractionStats.Skipped)
 		}
 
 		// v0.9.0: LLM enrichment on reimport (graceful skip if no API key or --no-enrich)
@@ -7186,11 +8787,15 @@ func runReimport(args []string) error {
 				fmt.Fprintf(os.Stderr, "  Skipping LLM enrichment (no API key). Set OPENROUTER_API_KEY for richer facts, or pass --no-enrich to silence this.\n")
 			} else {
 				fmt.Println("  Running LLM enrichment...")
-				enrichStats, err := runEnrichmentOnImportedMemories(ctx, s, enrichLLM, totalResult.NewMemoryIDs)
+				enrichStats, err := runEnrichmentOnImportedMemories(ctx, s, enrichLLM, totalResult.NewMemoryIDs, !noSkipList)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "  Enrichment error: %v\n", err)
-				} else if enrichStats.NewFacts > 0 {
-					fmt.Printf("  🧠 Enrichment: +%d new facts from LLM\n", enrichStats.NewFacts)
+				} else {
+					if enrichStats.NewFacts > 0 {
+						fmt.Printf("  🧠 Enrichment: +%d new facts from LLM\n", enrichStats.NewFacts)
+					}
+					printEnrichReviewNotice(enrichStats)
+					printSkipListNotice(enrichStats.Skipped)
 				}
 			}
 		}
@@ -7249,7 +8854,7 @@ func runReimport(args []string) error {
 // exactly one file and does NOT touch the rest of the database.
 func runRefreshSource(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: cortex refresh-source <path> [--dry-run] [--extract] [--no-enrich] [--no-classify] [--embed <model>] [--llm <model>] [--force]")
+		return fmt.Errorf("usage: cortex refresh-source <path> [--dry-run] [--extract] [--no-enrich] [--no-classify] [--no-skip-list] [--embed <model>] [--llm <model>] [--force]")
 	}
 
 	var path string
@@ -7257,6 +8862,7 @@ func runRefreshSource(args []string) error {
 	enableExtraction := false
 	noEnrich := false
 	noClassify := false
+	noSkipList := false
 	embedFlag := ""
 	llmFlag := ""
 	force := false
@@ -7271,6 +8877,8 @@ func runRefreshSource(args []string) error {
 			noEnrich = true
 		case args[i] == "--no-classify":
 			noClassify = true
+		case args[i] == "--no-skip-list":
+			noSkipList = true
 		case args[i] == "--embed" && i+1 < len(args):
 			i++
 			embedFlag = args[i]
@@ -7301,6 +8909,7 @@ Flags:
   --extract            Run fact extraction on newly imported memories
   --no-enrich          Skip LLM enrichment (only with --extract)
   --no-classify        Skip fact classification (only with --extract)
+  --no-skip-list       Extract even content that has repeatedly yielded no facts
   --embed <model>      Generate embeddings for new memories
   --llm <model>        LLM for extraction/enrichment
   --force, -f          Skip confirmation prompt
@@ -7420,11 +9029,12 @@ Examples:
 	// Run extraction if requested
 	if enableExtraction && result.MemoriesNew > 0 {
 		fmt.Println("  Extracting facts...")
-		extractionStats, err := runExtractionOnImportedMemories(ctx, s, llmFlag, result.NewMemoryIDs, nil)
+		extractionStats, err := runExtractionOnImportedMemories(ctx, s, llmFlag, result.NewMemoryIDs, !noSkipList, nil)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "  Extraction error: %v\n", err)
 		} else {
 			fmt.Printf("  ✓ Extracted %d facts\n", extractionStats.FactsExtracted)
+			printSkipListNotice(extractionStats.Skipped)
 		}
 
 		if !noEnrich {
@@ -7441,11 +9051,15 @@ Examples:
 				fmt.Fprintf(os.Stderr, "  Skipping LLM enrichment (no API key). Pass --no-enrich to silence this.\n")
 			} else {
 				fmt.Println("  Running LLM enrichment...")
-				enrichStats, err := runEnrichmentOnImportedMemories(ctx, s, enrichLLM, result.NewMemoryIDs)
+				enrichStats, err := runEnrichmentOnImportedMemories(ctx, s, enrichLLM, result.NewMemoryIDs, !noSkipList)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "  Enrichment error: %v\n", err)
-				} else if enrichStats.NewFacts > 0 {
-					fmt.Printf("  🧠 Enrichment: +%d new facts from LLM\n", enrichStats.NewFacts)
+				} else {
+					if enrichStats.NewFacts > 0 {
+						fmt.Printf("  🧠 Enrichment: +%d new facts from LLM\n", enrichStats.NewFacts)
+					}
+					printEnrichReviewNotice(enrichStats)
+					printSkipListNotice(enrichStats.Skipped)
 				}
 			}
 		}
@@ -7719,9 +9333,12 @@ func runCleanup(args []string) error {
 	purgeNoise := false
 	pruneTemporalNoise := false
 	dedupFacts := false
+	semanticDedup := false
 	resolveConflicts := false
 	conflictThreshold := 0.85
 	dedupThreshold := 0.90
+	semanticThreshold := store.DefaultSemanticDedupThreshold
+	embedFlag := ""
 	agentFlag := ""
 	for i := 0; i < len(args); i++ {
 		switch {
@@ -7733,6 +9350,8 @@ func runCleanup(args []string) error {
 			pruneTemporalNoise = true
 		case args[i] == "--dedup-facts":
 			dedupFacts = true
+		case args[i] == "--semantic-dedup":
+			semanticDedup = true
 		case args[i] == "--resolve-conflicts":
 			resolveConflicts = true
 		case args[i] == "--threshold" && i+1 < len(args):
@@ -7761,6 +9380,24 @@ func runCleanup(args []string) error {
 				return fmt.Errorf("invalid --dedup-threshold: %s", args[i])
 			}
 			dedupThreshold = v
+		case args[i] == "--semantic-threshold" && i+1 < len(args):
+			i++
+			v, err := strconv.ParseFloat(args[i], 64)
+			if err != nil || v <= 0 || v > 1 {
+				return fmt.Errorf("invalid --semantic-threshold: %s", args[i])
+			}
+			semanticThreshold = v
+		case strings.HasPrefix(args[i], "--semantic-threshold="):
+			v, err := strconv.ParseFloat(strings.TrimPrefix(args[i], "--semantic-threshold="), 64)
+			if err != nil || v <= 0 || v > 1 {
+				return fmt.Errorf("invalid --semantic-threshold: %s", args[i])
+			}
+			semanticThreshold = v
+		case args[i] == "--embed" && i+1 < len(args):
+			i++
+			embedFlag = args[i]
+		case strings.HasPrefix(args[i], "--embed="):
+			embedFlag = strings.TrimPrefix(args[i], "--embed=")
 		case args[i] == "--agent" && i+1 < len(args):
 			i++
 			agentFlag = args[i]
@@ -7768,12 +9405,32 @@ func runCleanup(args []string) error {
 			agentFlag = strings.TrimPrefix(args[i], "--agent=")
 		default:
 			if strings.HasPrefix(args[i], "-") {
-				return fmt.Errorf("unknown flag: %s\nUsage: cortex cleanup [--dry-run] [--purge-noise] [--prune-temporal-noise] [--dedup-facts] [--resolve-conflicts] [--threshold 0.85] [--dedup-threshold 0.90] [--agent <id>]", args[i])
+				return fmt.Errorf("unknown flag: %s\nUsage: cortex cleanup [--dry-run] [--purge-noise] [--prune-temporal-noise] [--dedup-facts] [--semantic-dedup] [--resolve-conflicts] [--threshold 0.85] [--dedup-threshold 0.90] [--semantic-threshold 0.88] [--embed <provider/model>] [--agent <id>]", args[i])
 			}
 			return fmt.Errorf("unexpected argument: %s", args[i])
 		}
 	}
 
+	// Resolve the embedder before touching the database so a missing provider
+	// fails the whole run instead of leaving it half done.
+	var semanticEmbedder embed.Embedder
+	if semanticDedup {
+		embedCfg, err := embed.ResolveEmbedConfig(embedFlag)
+		if err != nil {
+			return fmt.Errorf("configuring embedder: %w", err)
+		}
+		if embedCfg == nil {
+			return fmt.Errorf("--semantic-dedup needs an embedder (pass --embed <provider/model> or set CORTEX_EMBED)")
+		}
+		if err := embedCfg.Validate(); err != nil {
+			return fmt.Errorf("invalid embedding configuration: %w", err)
+		}
+		semanticEmbedder, err = newEmbedClient(embedCfg)
+		if err != nil {
+			return fmt.Errorf("creating embedder: %w", err)
+		}
+	}
+
 	s, err := store.NewStore(getStoreConfig())
 	if err != nil {
 		return fmt.Errorf("opening store: %w", err)
@@ -7802,12 +9459,12 @@ func runCleanup(args []string) error {
 	_ = memAgentArgs // used below in queries
 	_ = factAgentArgs
 
-	if dryRun && !purgeNoise && !pruneTemporalNoise && !dedupFacts && !resolveConflicts {
+	if dryRun && !purgeNoise && !pruneTemporalNoise && !dedupFacts && !semanticDedup && !resolveConflicts {
 		// Count what would be cleaned without deleting (#57)
 		var shortCount, numericCount, factsCount, temporalNoiseCount int
-		_ = ss.QueryRowContext(ctx, `SELECT COUNT(*) FROM memories WHERE LENGTH(content) < 20 AND deleted_at IS NULL`+memAgentWhere, memAgentArgs...).Scan(&shortCount)
-		_ = ss.QueryRowContext(ctx, `SELECT COUNT(*) FROM memories WHERE content GLOB '[0-9]*' AND content NOT GLOB '*[^0-9]*' AND deleted_at IS NULL`+memAgentWhere, memAgentArgs...).Scan(&numericCount)
-		_ = ss.QueryRowContext(ctx, `SELECT COUNT(*) FROM facts WHERE (subject IS NULL OR subject = '')`+factAgentWhere, factAgentArgs...).Scan(&factsCount)
+		_ = ss.QueryRowContext(ctx, `SELECT COUNT(*) FROM memories WHERE LENGTH(content) < 20 AND deleted_at IS NULL AND `+store.NotHeldMemoryClause+memAgentWhere, memAgentArgs...).Scan(&shortCount)
+		_ = ss.QueryRowContext(ctx, `SELECT COUNT(*) FROM memories WHERE content GLOB '[0-9]*' AND content NOT GLOB '*[^0-9]*' AND deleted_at IS NULL AND `+store.NotHeldMemoryClause+memAgentWhere, memAgentArgs...).Scan(&numericCount)
+		_ = ss.QueryRowContext(ctx, `SELECT COUNT(*) FROM facts WHERE (subject IS NULL OR subject = '') AND `+store.NotHeldFactClause+factAgentWhere, factAgentArgs...).Scan(&factsCount)
 		if ids, err := listTemporalNoiseFactIDs(ctx, ss, agentFlag); err == nil {
 			temporalNoiseCount = len(ids)
 		}
@@ -7823,21 +9480,21 @@ func runCleanup(args []string) error {
 	// Base cleanup (skip in dry-run + purge-noise mode)
 	if !dryRun {
 		// 1. Delete short memories (likely garbage chunks).
-		res, err := ss.ExecContext(ctx, `DELETE FROM memories WHERE LENGTH(content) < 20`+memAgentWhere, memAgentArgs...)
+		res, err := ss.ExecContext(ctx, `DELETE FROM memories WHERE LENGTH(content) < 20 AND `+store.NotHeldMemoryClause+memAgentWhere, memAgentArgs...)
 		if err != nil {
 			return fmt.Errorf("deleting short memories: %w", err)
 		}
 		shortDeleted, _ := res.RowsAffected()
 
 		// 2. Delete purely numeric memories.
-		res, err = ss.ExecContext(ctx, `DELETE FROM memories WHERE content GLOB '[0-9]*' AND content NOT GLOB '*[^0-9]*'`+memAgentWhere, memAgentArgs...)
+		res, err = ss.ExecContext(ctx, `DELETE FROM memories WHERE content GLOB '[0-9]*' AND content NOT GLOB '*[^0-9]*' AND `+store.NotHeldMemoryClause+memAgentWhere, memAgentArgs...)
 		if err != nil {
 			return fmt.Errorf("deleting numeric memories: %w", err)
 		}
 		numericDeleted, _ := res.RowsAffected()
 
 		// 3. Delete headless facts (subject is null or empty).
-		res, err = ss.ExecContext(ctx, `DELETE FROM facts WHERE (subject IS NULL OR subject = '')`+factAgentWhere, factAgentArgs...)
+		res, err = ss.ExecContext(ctx, `DELETE FROM facts WHERE (subject IS NULL OR subject = '') AND `+store.NotHeldFactClause+factAgentWhere, factAgentArgs...)
 		if err != nil {
 			return fmt.Errorf("deleting headless facts: %w", err)
 		}
@@ -7886,6 +9543,19 @@ func runCleanup(args []string) error {
 		}
 	}
 
+	if semanticDedup {
+		report, err := ss.SemanticDedupFacts(ctx, store.SemanticDedupOptions{
+			Agent:      agentFlag,
+			Threshold:  semanticThreshold,
+			DryRun:     dryRun,
+			MaxPreview: 25,
+		}, semanticEmbedder.EmbedBatch)
+		if err != nil {
+			return fmt.Errorf("semantic-dedup failed: %w", err)
+		}
+		printSemanticDedupReport(report, dryRun)
+	}
+
 	if pruneTemporalNoise || purgeNoise {
 		pruned, err := pruneTemporalNoiseFacts(ctx, ss, dryRun, agentFlag)
 		if err != nil {
@@ -8129,7 +9799,7 @@ func purgeNoiseFacts(ctx context.Context, ss *store.SQLiteStore, dryRun bool) (i
 		if len(idsToDelete) > 0 && !dryRun {
 			// Batch delete
 			for _, id := range idsToDelete {
-				if _, err := ss.ExecContext(ctx, `DELETE FROM facts WHERE id = ?`, id); err != nil {
+				if _, err := ss.ExecContext(ctx, `DELETE FROM facts WHERE id = ? AND `+store.NotHeldFactClause, id); err != nil {
 					return 0, err
 				}
 			}
@@ -8140,6 +9810,35 @@ func purgeNoiseFacts(ctx context.Context, ss *store.SQLiteStore, dryRun bool) (i
 	return totalPurged, nil
 }
 
+func printSemanticDedupReport(report *store.SemanticDedupReport, dryRun bool) {
+	if dryRun {
+		fmt.Printf("\nSemantic fact dedup dry run:\n")
+	} else {
+		fmt.Printf("\nSemantic fact dedup complete:\n")
+	}
+	fmt.Printf("  Subjects scanned: %d\n", report.SubjectsScanned)
+	if report.SubjectsSkipped > 0 {
+		fmt.Printf("  Subjects skipped (too many facts): %d\n", report.SubjectsSkipped)
+	}
+	fmt.Printf("  Facts embedded:   %d\n", report.FactsEmbedded)
+	fmt.Printf("  Clusters:         %d\n", report.Clusters)
+	if dryRun {
+		fmt.Printf("  Facts to merge:   %d\n", report.Merged)
+	} else {
+		fmt.Printf("  Facts merged:     %d\n", report.Merged)
+	}
+	if len(report.Preview) > 0 {
+		fmt.Printf("  Clusters (canonical <- merged):\n")
+		for _, c := range report.Preview {
+			fmt.Printf("    - %s | %s %s (%.2f -> %.2f)\n",
+				c.Subject, c.CanonicalPredicate, c.CanonicalObject, c.CanonicalConfidence, c.CombinedConfidence)
+			for _, m := range c.Merged {
+				fmt.Printf("        <- %s %s (%.2f) | sim=%.3f\n", m.Predicate, m.Object, m.Confidence, m.Similarity)
+			}
+		}
+	}
+}
+
 // purgeDuplicateFacts removes exact (subject, predicate, object) duplicates,
 // keeping the one with highest confidence.
 func purgeDuplicateFacts(ctx context.Context, ss *store.SQLiteStore, dryRun bool) (int64, error) {
@@ -8165,7 +9864,7 @@ func purgeDuplicateFacts(ctx context.Context, ss *store.SQLiteStore, dryRun bool
 			WHERE superseded_by IS NULL
 			GROUP BY LOWER(TRIM(subject)), LOWER(TRIM(predicate)), LOWER(TRIM(object))
 		)
-	`)
+		AND `+store.NotHeldFactClause)
 	if err != nil {
 		return 0, err
 	}
@@ -8265,7 +9964,7 @@ func purgeExcessFacts(ctx context.Context, ss *store.SQLiteStore, maxPerMemory i
 
 		if len(idsToDelete) > 0 && !dryRun {
 			for _, id := range idsToDelete {
-				if _, err := ss.ExecContext(ctx, `DELETE FROM facts WHERE id = ?`, id); err != nil {
+				if _, err := ss.ExecContext(ctx, `DELETE FROM facts WHERE id = ? AND `+store.NotHeldFactClause, id); err != nil {
 					return 0, err
 				}
 			}
@@ -8279,6 +9978,7 @@ func purgeExcessFacts(ctx context.Context, ss *store.SQLiteStore, maxPerMemory i
 func runMCP(args []string) error {
 	var port int
 	var embedModel string
+	var llmModel string
 	var agentID string
 
 	for i := 0; i < len(args); i++ {
@@ -8306,6 +10006,11 @@ func runMCP(args []string) error {
 			i++
 		case strings.HasPrefix(args[i], "--embed="):
 			embedModel = strings.TrimPrefix(args[i], "--embed=")
+		case args[i] == "--llm" && i+1 < len(args):
+			llmModel = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--llm="):
+			llmModel = strings.TrimPrefix(args[i], "--llm=")
 		case args[i] == "--help" || args[i] == "-h":
 			fmt.Println(`cortex mcp — Start Model Context Protocol server
 
@@ -8314,14 +10019,18 @@ Usage:
   cortex mcp --port 8080             Start MCP server (HTTP+SSE transport)
 
 Flags:
-  --port <N>                         HTTP+SSE port (default: stdio)
+  --port <N>                         HTTP+SSE port (default: stdio); also serves GET /readyz and /status
   --embed <provider/model>           Enable semantic/hybrid/rrf search
+  --llm <provider/model>             LLM for enrichment jobs (cortex_import enrich=true, cortex_job_start)
   --agent <id>                       Scope all operations to this agent
   -h, --help                         Show this help
 
-Tools (17):
-  cortex_search         Search memories (bm25, semantic, hybrid, rrf)
+Tools (20):
+  cortex_search         Search memories (bm25, semantic, hybrid, rrf, auto)
   cortex_import         Save new memories (with optional fact extraction)
+  cortex_job_start      Start a background job (extract, enrich, embed, cluster_rebuild)
+  cortex_job_status     Progress and results of background jobs
+  cortex_job_cancel     Cancel a queued or running job
   cortex_stats          Memory health overview
   cortex_facts          Query structured facts by subject or type
   cortex_stale          Find fading facts (confidence decay)
@@ -8358,12 +10067,15 @@ Resources (4):
 	wireWebhook(s)
 
 	mcpCfg := cortexmcp.ServerConfig{
-		Store:   s,
-		DBPath:  getDBPath(),
-		Version: version,
-		AgentID: agentID,
+		Store:            s,
+		DBPath:           getDBPath(),
+		Version:          version,
+		AgentID:          agentID,
+		ReadOnly:         globalReadOnly,
+		SearchProfiles:   loadSearchProfiles(),
+		SearchFreshness:  loadSearchFreshness(),
+		SearchGraphBoost: loadSearchGraphBoost(),
 	}
-
 	// Wire up embedder if requested
 	if embedModel != "" {
 		embedCfg, err := embed.NewEmbedConfig(embedModel)
@@ -8372,19 +10084,39 @@ Resources (4):
 		}
 		embedder, err := embed.NewClient(embedCfg)
 		if err != nil {
-			return fmt.Errorf("creating embedder: %w", err)
+			return fmt.Errorf("creating embedder: %w", err)
+		}
+		mcpCfg.Embedder = embedder
+		// Serve BM25 right away; the HNSW index is mapped/built in the background.
+		mcpCfg.SearchEngine = search.NewEngineWithEmbedder(s, embedder)
+		warmSearchHNSW(mcpCfg.SearchEngine)
+	}
+
+	// Background jobs: this server runs queued work (also jobs queued with
+	// `cortex jobs start`) for as long as it is up.
+	if sqlStore, ok := s.(*store.SQLiteStore); ok {
+		runner, err := newJobRunner(sqlStore, llmModel, mcpCfg.Embedder)
+		if err != nil {
+			return err
 		}
-		mcpCfg.Embedder = embedder
+		mcpCfg.Jobs = runner
 	}
 
 	mcpServer := cortexmcp.NewServer(mcpCfg)
+	if mcpCfg.Jobs != nil {
+		mcpCfg.Jobs.Start(context.Background())
+	}
 
 	if port > 0 {
-		// HTTP+SSE transport
+		// HTTP+SSE transport, plus /readyz and /status probes
 		sseServer := server.NewSSEServer(mcpServer)
+		mux := http.NewServeMux()
+		mux.Handle("/readyz", readinessHandler(s, mcpCfg.SearchEngine))
+		mux.Handle("/status", statusHandler(s))
+		mux.Handle("/", sseServer)
 		addr := fmt.Sprintf(":%d", port)
-		fmt.Fprintf(os.Stderr, "Cortex MCP server listening on http://localhost%s/sse\n", addr)
-		return sseServer.Start(addr)
+		fmt.Fprintf(os.Stderr, "Cortex MCP server listening on http://localhost%s/sse (readiness: /readyz, status: /status)\n", addr)
+		return http.ListenAndServe(addr, mux)
 	}
 
 	// Default: stdio transport
@@ -8392,6 +10124,24 @@ Resources (4):
 }
 
 func runIndex(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "tune":
+			return runIndexTune(args[1:])
+		case "quantize":
+			return runIndexQuantize(args[1:])
+		}
+	}
+	for _, a := range args {
+		switch a {
+		case "--help", "-h":
+			fmt.Println(indexUsageText())
+			return nil
+		default:
+			return fmt.Errorf("unknown argument: %s\n\n%s", a, indexUsageText())
+		}
+	}
+
 	s, err := store.NewStore(getStoreConfig())
 	if err != nil {
 		return fmt.Errorf("opening store: %w", err)
@@ -8400,6 +10150,7 @@ func runIndex(args []string) error {
 
 	ctx := context.Background()
 	engine := search.NewEngine(s)
+	engine.SetHNSWParams(resolveHNSWParams())
 
 	hnswPath := getHNSWPath()
 	fmt.Println("Building HNSW index from stored embeddings...")
@@ -8424,12 +10175,20 @@ func runIndex(args []string) error {
 
 	info, _ := os.Stat(hnswPath)
 	sizeMB := float64(info.Size()) / (1024 * 1024)
+	params := engine.HNSWParams()
+	recall := engine.EstimateHNSWRecall(indexRecallK, indexRecallSample)
 
 	fmt.Printf("HNSW index built:\n")
 	fmt.Printf("  Vectors: %d\n", count)
 	fmt.Printf("  Build time: %s\n", buildTime.Round(time.Millisecond))
 	fmt.Printf("  File: %s (%.1f MB)\n", hnswPath, sizeMB)
+	fmt.Printf("  Params: M=%d efConstruction=%d efSearch=%d\n", params.M, params.EfConstruction, params.EfSearch)
+	fmt.Printf("  Quantization: %s\n", engine.HNSWQuantization())
+	fmt.Printf("  Recall@%d estimate: %.1f%% (vs brute force, %d-query sample)\n", indexRecallK, recall*100, min(indexRecallSample, count))
 	fmt.Printf("  Search: O(log N) vs O(N) brute-force\n")
+	if rec := ann.RecommendParams(count); rec.M > params.M || rec.EfSearch > params.EfSearch {
+		fmt.Printf("  Hint: store size suggests M=%d efSearch=%d — run `cortex index tune` to measure.\n", rec.M, rec.EfSearch)
+	}
 	return nil
 }
 
@@ -9016,6 +10775,7 @@ func runEmbedPass(ctx context.Context, s store.Store, embedEngine *ingest.EmbedE
 
 func rebuildHNSWIndex(ctx context.Context, s store.Store) (int, error) {
 	engine := search.NewEngine(s)
+	engine.SetHNSWParams(resolveHNSWParams())
 	count, err := engine.BuildHNSW(ctx)
 	if err != nil {
 		return 0, err
@@ -9555,7 +11315,11 @@ func outputListFactsTTY(facts []*store.Fact, opts store.ListOpts) error {
 			factContent = factContent[:57] + "..."
 		}
 
-		fmt.Printf("  %d. [%s] %s\n", i+1, fact.FactType, factContent)
+		factType := fact.FactType
+		if fact.CustomType != "" {
+			factType = fact.CustomType
+		}
+		fmt.Printf("  %d. [%s] %s\n", i+1, factType, factContent)
 		fmt.Printf("     Confidence: %.2f · Decay: %.3f/day\n",
 			fact.Confidence, fact.DecayRate)
 		if fact.AgentID != "" {
@@ -9576,10 +11340,10 @@ func outputListFactsTTY(facts []*store.Fact, opts store.ListOpts) error {
 	return nil
 }
 
-func exportMemoriesInFormat(memories []*store.Memory, format string, output *os.File) error {
+func exportMemoriesInFormat(memories []*store.Memory, format string, output *os.File, snapshot *store.BackupInfo) error {
 	switch format {
 	case "json":
-		return exportMemoriesJSON(memories, output)
+		return exportMemoriesJSON(memories, output, snapshot)
 	case "markdown":
 		return exportMemoriesMarkdown(memories, output)
 	case "csv":
@@ -9589,10 +11353,10 @@ func exportMemoriesInFormat(memories []*store.Memory, format string, output *os.
 	}
 }
 
-func exportFactsInFormat(facts []*store.Fact, format string, output *os.File) error {
+func exportFactsInFormat(facts []*store.Fact, format string, output *os.File, snapshot *store.BackupInfo) error {
 	switch format {
 	case "json":
-		return exportFactsJSON(facts, output)
+		return exportFactsJSON(facts, output, snapshot)
 	case "markdown":
 		return exportFactsMarkdown(facts, output)
 	case "csv":
@@ -9602,10 +11366,29 @@ func exportFactsInFormat(facts []*store.Fact, format string, output *os.File) er
 	}
 }
 
-func exportMemoriesJSON(memories []*store.Memory, output *os.File) error {
+// exportJSON is the layout of `cortex export --format json`: metadata
+// describing the snapshot the rows were read from, then the rows. Exports
+// from before the metadata was added are bare arrays.
+type exportJSON struct {
+	Export   exportMetaJSON  `json:"export"`
+	Memories []*store.Memory `json:"memories,omitempty"`
+	Facts    []*store.Fact   `json:"facts,omitempty"`
+}
+
+type exportMetaJSON struct {
+	Kind       string            `json:"kind"`
+	Count      int               `json:"count"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Snapshot   *store.BackupInfo `json:"snapshot,omitempty"`
+}
+
+func exportMemoriesJSON(memories []*store.Memory, output *os.File, snapshot *store.BackupInfo) error {
 	enc := json.NewEncoder(output)
 	enc.SetIndent("", "  ")
-	return enc.Encode(memories)
+	return enc.Encode(exportJSON{
+		Export:   exportMetaJSON{Kind: "memories", Count: len(memories), ExportedAt: time.Now().UTC(), Snapshot: snapshot},
+		Memories: memories,
+	})
 }
 
 func exportMemoriesMarkdown(memories []*store.Memory, output *os.File) error {
@@ -9632,6 +11415,9 @@ func exportMemoriesMarkdown(memories []*store.Memory, output *os.File) error {
 				}
 				fmt.Fprintf(output, "\n\n")
 			}
+			if a := memory.Anchor; a != nil && a.ByteEnd > a.ByteStart {
+				fmt.Fprintf(output, "_bytes %d–%d_\n\n", a.ByteStart, a.ByteEnd)
+			}
 
 			fmt.Fprintf(output, "%s\n\n", memory.Content)
 		}
@@ -9642,30 +11428,43 @@ func exportMemoriesMarkdown(memories []*store.Memory, output *os.File) error {
 
 func exportMemoriesCSV(memories []*store.Memory, output *os.File) error {
 	// Write CSV header
-	fmt.Fprintf(output, "id,content,source_file,source_line,source_section,imported_at\n")
+	fmt.Fprintf(output, "id,content,source_file,source_line,source_section,imported_at,heading_path,byte_start,byte_end\n")
 
 	for _, memory := range memories {
 		// Escape quotes in content
 		content := strings.ReplaceAll(memory.Content, `"`, `""`)
 		sourceFile := strings.ReplaceAll(memory.SourceFile, `"`, `""`)
 		sourceSection := strings.ReplaceAll(memory.SourceSection, `"`, `""`)
+		headingPath, byteStart, byteEnd := "", "", ""
+		if a := memory.Anchor; a != nil {
+			headingPath = strings.ReplaceAll(a.HeadingPath, `"`, `""`)
+			if a.ByteEnd > a.ByteStart {
+				byteStart, byteEnd = strconv.FormatInt(a.ByteStart, 10), strconv.FormatInt(a.ByteEnd, 10)
+			}
+		}
 
-		fmt.Fprintf(output, `%d,"%s","%s",%d,"%s",%s`+"\n",
+		fmt.Fprintf(output, `%d,"%s","%s",%d,"%s",%s,"%s",%s,%s`+"\n",
 			memory.ID,
 			content,
 			sourceFile,
 			memory.SourceLine,
 			sourceSection,
-			memory.ImportedAt.Format("2006-01-02T15:04:05Z07:00"))
+			memory.ImportedAt.Format("2006-01-02T15:04:05Z07:00"),
+			headingPath,
+			byteStart,
+			byteEnd)
 	}
 
 	return nil
 }
 
-func exportFactsJSON(facts []*store.Fact, output *os.File) error {
+func exportFactsJSON(facts []*store.Fact, output *os.File, snapshot *store.BackupInfo) error {
 	enc := json.NewEncoder(output)
 	enc.SetIndent("", "  ")
-	return enc.Encode(facts)
+	return enc.Encode(exportJSON{
+		Export: exportMetaJSON{Kind: "facts", Count: len(facts), ExportedAt: time.Now().UTC(), Snapshot: snapshot},
+		Facts:  facts,
+	})
 }
 
 func exportFactsMarkdown(facts []*store.Fact, output *os.File) error {
@@ -9860,30 +11659,45 @@ func estimateSearchResultTokens(r search.Result) int {
 	if snippet := strings.TrimSpace(r.Snippet); snippet != "" && len(snippet) < len(text) {
 		text = snippet
 	}
-	if text == "" {
-		return 1
-	}
-	return (len(text) + 3) / 4
+	return max(1, tokens.Count(text))
 }
 
 func clipSearchResultToBudget(r search.Result, budget int) search.Result {
 	if budget <= 0 {
 		return r
 	}
-	maxChars := budget * 4
-	if maxChars <= 0 {
-		maxChars = 1
+	// Leave a token for the "..." TruncateContent appends.
+	clipTo := func(text string) string {
+		prefix, _ := tokens.Default.Truncate(text, budget-1)
+		return search.TruncateContent(text, max(1, len(prefix)))
 	}
-	r.Content = search.TruncateContent(r.Content, maxChars)
+	r.Content = clipTo(r.Content)
 	if r.Snippet != "" {
-		snippet := strings.ReplaceAll(strings.ReplaceAll(r.Snippet, "<b>", ""), "</b>", "")
-		r.Snippet = search.TruncateContent(snippet, maxChars)
+		r.Snippet = clipTo(strings.ReplaceAll(strings.ReplaceAll(r.Snippet, "<b>", ""), "</b>", ""))
 	}
 	r.TokenEstimate = budget
 	r.Truncated = true
 	return r
 }
 
+// writeSearchTraceDump writes the per-candidate scoring trace for --dump as
+// JSONL, one candidate per line, including candidates that were filtered out.
+func writeSearchTraceDump(path string, trace *search.Trace) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating --dump file: %w", err)
+	}
+	if err := trace.WriteJSONL(f); err != nil {
+		f.Close()
+		return fmt.Errorf("writing --dump file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing --dump file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d candidate traces to %s\n", len(trace.Candidates()), path)
+	return nil
+}
+
 func packSearchResultsByBudget(results []search.Result, budget int, capLimit int) ([]search.Result, int) {
 	if budget <= 0 || len(results) == 0 {
 		return results, 0
@@ -9930,21 +11744,63 @@ func outputJSON(results []search.Result) error {
 	if results == nil {
 		results = []search.Result{}
 	}
-	// Strip HTML highlight tags from FTS5 snippets for clean JSON output
+	stripSnippetHighlights(results)
+	if len(results) == 0 {
+		fmt.Println("[]")
+		fmt.Fprintln(os.Stderr, "No results found. Try different keywords or check `cortex stats`.")
+		return nil
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+// resolveSearchHighlight builds the search highlight option for --highlight
+// and --snippet-tokens. Without an explicit style, machine output gets
+// <mark> tags and terminals get ANSI colors (plain text under NO_COLOR).
+func resolveSearchHighlight(style string, tokens int, machineOutput bool) (*search.Highlight, error) {
+	if err := search.ValidateSnippetTokens(tokens); err != nil {
+		return nil, fmt.Errorf("--snippet-tokens: %w", err)
+	}
+	if strings.TrimSpace(style) == "" {
+		switch {
+		case machineOutput:
+			style = search.HighlightMark
+		case os.Getenv("NO_COLOR") != "":
+			style = search.HighlightPlain
+		default:
+			style = search.HighlightANSI
+		}
+	}
+	parsed, err := search.ParseHighlightStyle(style)
+	if err != nil {
+		return nil, fmt.Errorf("--highlight: %w", err)
+	}
+	return &search.Highlight{Style: parsed, Tokens: tokens}, nil
+}
+
+// stripSnippetHighlights removes FTS5 highlight tags for clean JSON output.
+func stripSnippetHighlights(results []search.Result) {
 	for i := range results {
 		if results[i].Snippet != "" {
 			results[i].Snippet = strings.ReplaceAll(results[i].Snippet, "<b>", "")
 			results[i].Snippet = strings.ReplaceAll(results[i].Snippet, "</b>", "")
 		}
 	}
-	if len(results) == 0 {
-		fmt.Println("[]")
-		fmt.Fprintln(os.Stderr, "No results found. Try different keywords or check `cortex stats`.")
-		return nil
+}
+
+func outputGroupedJSON(groups []search.ResultGroup) error {
+	if groups == nil {
+		groups = []search.ResultGroup{}
+	}
+	for _, g := range groups {
+		for i := range g.Results {
+			g.Results[i].Snippet = strings.ReplaceAll(strings.ReplaceAll(g.Results[i].Snippet, "<b>", ""), "</b>", "")
+		}
 	}
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
-	return enc.Encode(results)
+	return enc.Encode(groups)
 }
 
 type budgetSearchJSON struct {
@@ -9999,7 +11855,7 @@ func outputFactSearchJSON(results []search.FactResult) error {
 }
 
 func outputTTY(query string, results []search.Result) error {
-	return outputTTYSearch(query, results, false, false, "")
+	return outputTTYSearch(query, results, false, false, "", false)
 }
 
 func outputTTYFactSearch(query string, results []search.FactResult) error {
@@ -10020,11 +11876,50 @@ func outputTTYFactSearch(query string, results []search.FactResult) error {
 		if r.SourceFile != "" {
 			fmt.Printf("     📁 %s  type:%s  conf:%.2f\n", r.SourceFile, r.FactType, r.Confidence)
 		}
+		if len(r.Summarizes) > 0 {
+			fmt.Printf("     summarizes %d %s:\n", len(r.Summarizes), pluralize("fact", "facts", len(r.Summarizes)))
+			for _, o := range r.Summarizes {
+				fmt.Printf("       ↳ #%d %s — %s: %s\n", o.FactID, o.Subject, o.Predicate, o.Object)
+			}
+		}
+	}
+	return nil
+}
+
+// searchGroupPreview is how many hits each group shows before collapsing.
+const searchGroupPreview = 2
+
+func outputTTYGroupedSearch(query string, groups []search.ResultGroup, by search.GroupBy, matches int) error {
+	if len(groups) == 0 {
+		fmt.Printf("No results for %q\n", query)
+		fmt.Println("  Try different keywords, or check `cortex stats` to verify your database has memories.")
+		return nil
+	}
+
+	fmt.Printf("Results for %q grouped by %s (%d %s, %d %s)\n\n", query, by,
+		len(groups), pluralize("group", "groups", len(groups)),
+		matches, pluralize("match", "matches", matches))
+	for i, g := range groups {
+		fmt.Printf("  %d. %s  best %.2f  (%d %s)\n", i+1, g.Key, g.BestScore, g.Count, pluralize("match", "matches", g.Count))
+		for j, r := range g.Results {
+			if j == searchGroupPreview {
+				fmt.Printf("     … %d more\n", len(g.Results)-searchGroupPreview)
+				break
+			}
+			content := strings.ReplaceAll(search.TruncateContent(r.Content, 160), "\n", " ")
+			fmt.Printf("     [%.2f] %s", r.Score, content)
+			if by != search.GroupBySource && r.SourceFile != "" {
+				fmt.Printf("  📁 %s", r.SourceFile)
+			} else if r.SourceLine > 0 {
+				fmt.Printf("  :%d", r.SourceLine)
+			}
+			fmt.Println()
+		}
 	}
 	return nil
 }
 
-func outputTTYSearch(query string, results []search.Result, showMetadata bool, explain bool, mode search.Mode) error {
+func outputTTYSearch(query string, results []search.Result, showMetadata bool, explain bool, mode search.Mode, showSnippets bool) error {
 	if len(results) == 0 {
 		fmt.Printf("No results for %q\n", query)
 		fmt.Println("  Try different keywords, or check `cortex stats` to verify your database has memories.")
@@ -10049,6 +11944,9 @@ func outputTTYSearch(query string, results []search.Result, showMetadata bool, e
 		content = strings.ReplaceAll(content, "\n", " ")
 
 		fmt.Printf("  %d. [%.2f] %s\n", i+1, r.Score, content)
+		if showSnippets && r.Snippet != "" {
+			fmt.Printf("     ✂ %s\n", strings.ReplaceAll(r.Snippet, "\n", " "))
+		}
 		if r.SourceFile != "" {
 			fmt.Printf("     📁 %s", r.SourceFile)
 			if r.SourceLine > 0 {
@@ -10065,6 +11963,16 @@ func outputTTYSearch(query string, results []search.Result, showMetadata bool, e
 			}
 			fmt.Println()
 		}
+		if r.Anchor != nil && r.Anchor.HeadingPath != "" {
+			fmt.Printf("     § %s\n", r.Anchor.HeadingPath)
+		}
+		if r.Workspace != "" {
+			fmt.Printf("     🗂 %s", r.Workspace)
+			if len(r.AlsoIn) > 0 {
+				fmt.Printf("  (also in %s)", strings.Join(r.AlsoIn, ", "))
+			}
+			fmt.Println()
+		}
 		// Show metadata if requested (--show-metadata flag, Issue #30)
 		if showMetadata && r.Metadata != nil {
 			meta := r.Metadata
@@ -10099,6 +12007,9 @@ func outputTTYSearch(query string, results []search.Result, showMetadata bool, e
 				}
 				fmt.Println()
 			}
+			if e.ModeRoute != nil {
+				fmt.Printf("     🔀 mode=%s→%s (%s)\n", e.ModeRoute.Requested, e.ModeRoute.Selected, e.ModeRoute.Reason)
+			}
 			if !e.Provenance.Timestamp.IsZero() {
 				fmt.Printf("     ⏱ imported=%s  age=%.1f days\n", e.Provenance.Timestamp.Format(time.RFC3339), e.Provenance.AgeDays)
 			}
@@ -10136,9 +12047,22 @@ func outputTTYSearch(query string, results []search.Result, showMetadata bool, e
 			if e.RankComponents.SourceWeight != 0 {
 				fmt.Printf("     • source_weight=%.3f\n", e.RankComponents.SourceWeight)
 			}
+			if e.RankComponents.FreshnessMultiplier != 0 {
+				if e.RankComponents.FreshnessHalfLifeDays > 0 {
+					fmt.Printf("     • freshness×%.3f (half-life %.0f days)\n", e.RankComponents.FreshnessMultiplier, e.RankComponents.FreshnessHalfLifeDays)
+				} else {
+					fmt.Printf("     • freshness×%.3f (no decay for class)\n", e.RankComponents.FreshnessMultiplier)
+				}
+			}
+			if e.RankComponents.GraphBoostMultiplier != 0 {
+				fmt.Printf("     • graph×%.3f (linked to memory #%d)\n", e.RankComponents.GraphBoostMultiplier, e.RankComponents.GraphBoostSeedMemory)
+			}
 			if e.Why != "" {
 				fmt.Printf("     💡 %s\n", e.Why)
 			}
+			for _, n := range e.Notes {
+				fmt.Printf("     📝 %s #%d: %s\n", n.TargetType, n.TargetID, formatAnnotationLine(n))
+			}
 		}
 		fmt.Println()
 	}
@@ -10192,15 +12116,17 @@ func outputStatsTTY(stats *store.StoreStats, sourceFiles int, dateRange string)
 }
 
 // Enhanced stats output functions
-func outputEnhancedStatsJSON(stats *observe.Stats, dateRange string) error {
+func outputEnhancedStatsJSON(stats *observe.Stats, dateRange string, connectors []connect.SyncTrend) error {
 	type enhancedStatsJSON struct {
 		*observe.Stats
-		DateRange string `json:"date_range"`
+		DateRange  string              `json:"date_range"`
+		Connectors []connect.SyncTrend `json:"connectors,omitempty"`
 	}
 
 	s := enhancedStatsJSON{
-		Stats:     stats,
-		DateRange: dateRange,
+		Stats:      stats,
+		DateRange:  dateRange,
+		Connectors: connectors,
 	}
 
 	enc := json.NewEncoder(os.Stdout)
@@ -10208,7 +12134,7 @@ func outputEnhancedStatsJSON(stats *observe.Stats, dateRange string) error {
 	return enc.Encode(s)
 }
 
-func outputEnhancedStatsTTY(stats *observe.Stats, dateRange string) error {
+func outputEnhancedStatsTTY(stats *observe.Stats, dateRange string, connectors []connect.SyncTrend) error {
 	fmt.Println("╭─────────────────────────────────────────────╮")
 	fmt.Println("│              Cortex Memory Stats             │")
 	fmt.Println("├─────────────────────────────────────────────┤")
@@ -10218,6 +12144,9 @@ func outputEnhancedStatsTTY(stats *observe.Stats, dateRange string) error {
 	if stats.StorageBytes > 0 {
 		fmt.Printf("│ Storage:         %-27s │\n", formatBytes(stats.StorageBytes))
 	}
+	if stats.Budget != nil {
+		fmt.Printf("│ Budget:          %-27s │\n", fmt.Sprintf("%.0f%% of %s (%s)", stats.Budget.Percent, formatBytes(stats.Budget.BudgetBytes), stats.Budget.Level))
+	}
 	fmt.Printf("│ Denied import:   %-27d │\n", stats.DeniedAtImportCount)
 	fmt.Printf("│ Avg confidence:  %.2f%-22s │\n", stats.AvgConfidence, "")
 
@@ -10269,6 +12198,22 @@ func outputEnhancedStatsTTY(stats *observe.Stats, dateRange string) error {
 	fmt.Printf("│   Facts (24h):     %-24d │\n", stats.Growth.Facts24h)
 	fmt.Printf("│   Facts (7d):      %-24d │\n", stats.Growth.Facts7d)
 
+	if len(connectors) > 0 {
+		fmt.Println("├─────────────────────────────────────────────┤")
+		fmt.Println("│ Connector Trends (imported per sync)         │")
+		for _, t := range connectors {
+			name := t.Provider
+			if len(name) > 12 {
+				name = name[:11] + "…"
+			}
+			skip := 0.0
+			if n := len(t.SkipRatios); n > 0 {
+				skip = t.SkipRatios[n-1]
+			}
+			fmt.Printf("│   %-12s %-20s skip %3.0f%% │\n", name, sparkline(t.Imported), skip*100)
+		}
+	}
+
 	if len(stats.Alerts) > 0 {
 		fmt.Println("├─────────────────────────────────────────────┤")
 		fmt.Println("│ Alerts                                       │")
@@ -10468,6 +12413,9 @@ func rankConflictsForDisplay(conflicts []observe.Conflict) []observe.Conflict {
 	ordered := make([]observe.Conflict, len(conflicts))
 	copy(ordered, conflicts)
 	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].SeverityScore != ordered[j].SeverityScore {
+			return ordered[i].SeverityScore > ordered[j].SeverityScore
+		}
 		if ordered[i].Similarity == ordered[j].Similarity {
 			li := conflictLabel(ordered[i])
 			lj := conflictLabel(ordered[j])
@@ -10488,6 +12436,19 @@ func outputConflictsTTY(conflicts []observe.Conflict, verbose bool) error {
 	}
 
 	fmt.Printf("Conflicts Detected: %d\n", len(conflicts))
+	bySeverity := map[string]int{}
+	for _, c := range conflicts {
+		bySeverity[c.Severity]++
+	}
+	var severityParts []string
+	for _, level := range []string{observe.SeverityCritical, observe.SeverityHigh, observe.SeverityMedium, observe.SeverityLow} {
+		if n := bySeverity[level]; n > 0 {
+			severityParts = append(severityParts, fmt.Sprintf("%d %s", n, level))
+		}
+	}
+	if len(severityParts) > 0 {
+		fmt.Printf("By Severity: %s\n", strings.Join(severityParts, ", "))
+	}
 	groups := summarizeConflictGroups(conflicts)
 	if len(groups) > 0 {
 		fmt.Printf("Conflict Groups: %d\n\n", len(groups))
@@ -10527,7 +12488,11 @@ func outputConflictsTTY(conflicts []observe.Conflict, verbose bool) error {
 		if c.CrossAgent {
 			crossTag = " ⚠️ CROSS-AGENT"
 		}
-		fmt.Printf("\n❌ [%d/%d] %s conflict%s\n", i+1, len(ranked), conflictType, crossTag)
+		severityTag := ""
+		if c.Severity != "" {
+			severityTag = fmt.Sprintf(" (%s severity %.2f)", c.Severity, c.SeverityScore)
+		}
+		fmt.Printf("\n❌ [%d/%d] %s conflict%s%s\n", i+1, len(ranked), conflictType, severityTag, crossTag)
 		agent1 := ""
 		if c.Fact1.AgentID != "" {
 			agent1 = fmt.Sprintf(" [%s]", c.Fact1.AgentID)
@@ -10539,6 +12504,9 @@ func outputConflictsTTY(conflicts []observe.Conflict, verbose bool) error {
 		fmt.Printf("   \"%s\" (confidence: %.2f, id: %d)%s\n", formatFactText(c.Fact1.Subject, c.Fact1.Predicate, c.Fact1.Object), c.Fact1.Confidence, c.Fact1.ID, agent1)
 		fmt.Printf("   \"%s\" (confidence: %.2f, id: %d)%s\n", formatFactText(c.Fact2.Subject, c.Fact2.Predicate, c.Fact2.Object), c.Fact2.Confidence, c.Fact2.ID, agent2)
 		fmt.Printf("   Similarity: %.2f\n", c.Similarity)
+		if len(c.SeverityReasons) > 0 {
+			fmt.Printf("   Severity: %s\n", strings.Join(c.SeverityReasons, ", "))
+		}
 	}
 
 	if detailLimit < len(ranked) {
@@ -10611,12 +12579,17 @@ func isTTY() bool {
 
 // tryCreateProvider attempts to create an LLM provider, returning nil + error
 // if API keys are missing. Used for graceful degradation when LLM is default-on.
+// The provider scrubs secrets from prompts.
 func tryCreateProvider(llmFlag string) (llm.Provider, error) {
 	cfg, err := llm.ParseLLMFlag(llmFlag)
 	if err != nil {
 		return nil, err
 	}
-	return llm.NewProvider(cfg)
+	provider, err := newScrubbedProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return provider, nil
 }
 
 // copyFile copies src to dst. Used for pre-reimport backups.
@@ -10654,6 +12627,10 @@ func formatBytes(b int64) string {
 }
 
 func runReason(args []string) error {
+	if len(args) > 0 && args[0] == "preset" {
+		return runReasonPreset(args[1:])
+	}
+
 	// Parse flags
 	var queryParts []string
 	presetName := ""
@@ -10668,6 +12645,7 @@ func runReason(args []string) error {
 	maxIterations := 8
 	maxDepth := 1
 	verbose := false
+	noCache := false
 
 	for i := 0; i < len(args); i++ {
 		switch {
@@ -10719,6 +12697,8 @@ func runReason(args []string) error {
 			}
 		case args[i] == "--verbose", args[i] == "-v":
 			verbose = true
+		case args[i] == "--no-cache":
+			noCache = true
 		case strings.HasPrefix(args[i], "-"):
 			return fmt.Errorf("unknown flag: %s", args[i])
 		default:
@@ -10744,7 +12724,7 @@ func runReason(args []string) error {
 
 	query := strings.Join(queryParts, " ")
 	if query == "" && presetName == "" {
-		return fmt.Errorf("usage: cortex reason <query> [--preset <name>] [--model <provider/model>] [--project <name>] [--list]")
+		return fmt.Errorf("usage: cortex reason <query> [--preset <name>] [--model <provider/model>] [--project <name>] [--no-cache] [--list]")
 	}
 
 	// Smart model defaults based on preset and available API keys:
@@ -10808,6 +12788,7 @@ func runReason(args []string) error {
 		LLM:          llm,
 		ConfigDir:    configDir,
 	})
+	defer func() { printScrubReport(provider+"/"+model, llm.ScrubReport()) }()
 
 	// Run reasoning
 	ctx := context.Background()
@@ -10843,6 +12824,7 @@ func runReason(args []string) error {
 		// TTY output
 		fmt.Println(rResult.Content)
 		fmt.Println()
+		printAnswerConfidence(rResult.Confidence)
 		fmt.Printf("─── %s/%s | %d iterations, %d calls | %d memories, %d facts | search %s, llm %s | %d→%d tokens",
 			rResult.Provider, rResult.Model,
 			rResult.Iterations, rResult.TotalCalls,
@@ -10854,11 +12836,14 @@ func runReason(args []string) error {
 		if len(rResult.SubQueries) > 0 {
 			fmt.Printf(" | %d sub-queries", len(rResult.SubQueries))
 		}
+		if rResult.CacheHits > 0 || rResult.CachedTokens > 0 {
+			fmt.Printf(" | cache %d hits, %d tokens", rResult.CacheHits, rResult.CachedTokens)
+		}
 		fmt.Println(" ───")
 
 		if shouldWriteReasonTelemetry() {
-			costUSD, costKnown := estimateReasonRunCost(rResult.Provider, rResult.Model, rResult.TokensIn, rResult.TokensOut)
-			err := writeReasonTelemetry(reasonRunTelemetry{
+			costUSD, costKnown := estimateReasonRunCostCached(rResult.Provider, rResult.Model, rResult.TokensIn, rResult.CachedTokens, rResult.TokensOut)
+			annotateCommandTelemetry("recursive", reasonRunTelemetry{
 				Timestamp:      time.Now().UTC().Format(time.RFC3339),
 				Mode:           "recursive",
 				Query:          truncateReasonQuery(query),
@@ -10872,15 +12857,14 @@ func runReason(args []string) error {
 				FactsUsed:      rResult.FactsUsed,
 				TokensIn:       rResult.TokensIn,
 				TokensOut:      rResult.TokensOut,
+				CacheHits:      rResult.CacheHits,
+				CachedTokens:   rResult.CachedTokens,
 				SearchMS:       rResult.SearchTime.Milliseconds(),
 				LLMMS:          rResult.LLMTime.Milliseconds(),
 				WallMS:         time.Since(runStarted).Milliseconds(),
 				CostUSD:        costUSD,
 				CostKnown:      costKnown,
 			})
-			if err != nil && verbose {
-				fmt.Fprintf(os.Stderr, "Warning: failed to write reason telemetry: %v\n", err)
-			}
 		}
 
 		return nil
@@ -10894,6 +12878,7 @@ func runReason(args []string) error {
 		MaxTokens:  maxTokens,
 		MaxContext: maxContext,
 		JSONOutput: jsonOutput,
+		NoCache:    noCache,
 	})
 	if err != nil {
 		return err
@@ -10909,17 +12894,29 @@ func runReason(args []string) error {
 	// TTY output
 	fmt.Println(result.Content)
 	fmt.Println()
-	fmt.Printf("─── %s/%s | %d memories, %d facts | search %s, llm %s | %d→%d tokens ───\n",
+	printAnswerConfidence(result.Confidence)
+	if result.Cached {
+		// Served from the reason cache: nothing was searched or sent to the
+		// LLM, and nothing was spent.
+		fmt.Printf("─── %s/%s | %d memories, %d facts | cached result, store unchanged (--no-cache to rerun) ───\n",
+			result.Provider, result.Model, result.MemoriesUsed, result.FactsUsed)
+		return nil
+	}
+	fmt.Printf("─── %s/%s | %d memories, %d facts | search %s, llm %s | %d→%d tokens",
 		result.Provider, result.Model,
 		result.MemoriesUsed, result.FactsUsed,
 		result.SearchTime.Round(time.Millisecond),
 		result.LLMTime.Round(time.Millisecond),
 		result.TokensIn, result.TokensOut,
 	)
+	if result.CacheHits > 0 || result.CachedTokens > 0 {
+		fmt.Printf(" | cache %d hits, %d tokens", result.CacheHits, result.CachedTokens)
+	}
+	fmt.Println(" ───")
 
 	if shouldWriteReasonTelemetry() {
-		costUSD, costKnown := estimateReasonRunCost(result.Provider, result.Model, result.TokensIn, result.TokensOut)
-		err := writeReasonTelemetry(reasonRunTelemetry{
+		costUSD, costKnown := estimateReasonRunCostCached(result.Provider, result.Model, result.TokensIn, result.CachedTokens, result.TokensOut)
+		annotateCommandTelemetry("one-shot", reasonRunTelemetry{
 			Timestamp:      time.Now().UTC().Format(time.RFC3339),
 			Mode:           "one-shot",
 			Query:          truncateReasonQuery(query),
@@ -10933,15 +12930,14 @@ func runReason(args []string) error {
 			FactsUsed:      result.FactsUsed,
 			TokensIn:       result.TokensIn,
 			TokensOut:      result.TokensOut,
+			CacheHits:      result.CacheHits,
+			CachedTokens:   result.CachedTokens,
 			SearchMS:       result.SearchTime.Milliseconds(),
 			LLMMS:          result.LLMTime.Milliseconds(),
 			WallMS:         time.Since(runStarted).Milliseconds(),
 			CostUSD:        costUSD,
 			CostKnown:      costKnown,
 		})
-		if err != nil && verbose {
-			fmt.Fprintf(os.Stderr, "Warning: failed to write reason telemetry: %v\n", err)
-		}
 	}
 
 	return nil
@@ -10961,6 +12957,8 @@ type reasonRunTelemetry struct {
 	FactsUsed      int     `json:"facts_used"`
 	TokensIn       int     `json:"tokens_in"`
 	TokensOut      int     `json:"tokens_out"`
+	CacheHits      int     `json:"cache_hits,omitempty"`
+	CachedTokens   int     `json:"cached_tokens,omitempty"`
 	SearchMS       int64   `json:"search_ms"`
 	LLMMS          int64   `json:"llm_ms"`
 	WallMS         int64   `json:"wall_ms"`
@@ -10988,41 +12986,34 @@ func truncateReasonQuery(q string) string {
 }
 
 func estimateReasonRunCost(provider, model string, tokensIn, tokensOut int) (float64, bool) {
-	if provider != "openrouter" {
+	return estimateReasonRunCostCached(provider, model, tokensIn, 0, tokensOut)
+}
+
+// cachedInputDiscount is the fraction of the input price charged for prompt
+// tokens served from a Gemini context cache.
+const cachedInputDiscount = 0.25
+
+// estimateReasonRunCostCached prices a run where cachedTokens of tokensIn
+// were served from a context cache. Native Gemini runs are priced with the
+// OpenRouter rates for the same google/ model.
+func estimateReasonRunCostCached(provider, model string, tokensIn, cachedTokens, tokensOut int) (float64, bool) {
+	switch provider {
+	case "openrouter":
+	case "google", "gemini":
+		model = "google/" + model
+	default:
 		return 0, false
 	}
 	pricing, ok := reason.ModelPricing[model]
 	if !ok || (pricing[0] == 0 && pricing[1] == 0) {
 		return 0, false
 	}
-	cost := (float64(tokensIn) * pricing[0] / 1_000_000) + (float64(tokensOut) * pricing[1] / 1_000_000)
+	cachedTokens = min(cachedTokens, tokensIn)
+	input := float64(tokensIn-cachedTokens) + float64(cachedTokens)*cachedInputDiscount
+	cost := (input * pricing[0] / 1_000_000) + (float64(tokensOut) * pricing[1] / 1_000_000)
 	return cost, true
 }
 
-func writeReasonTelemetry(event reasonRunTelemetry) error {
-	if event.Timestamp == "" {
-		event.Timestamp = time.Now().UTC().Format(time.RFC3339)
-	}
-	path := filepath.Join(getConfigDir(), "reason-telemetry.jsonl")
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
-	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	b, err := json.Marshal(event)
-	if err != nil {
-		return err
-	}
-	if _, err := f.Write(append(b, '\n')); err != nil {
-		return err
-	}
-	return nil
-}
-
 type benchCLIOptions struct {
 	embedFlag     string
 	includeLocal  bool
@@ -11597,6 +13588,9 @@ func runEntity(args []string) error {
 }
 
 func runProjects(args []string) error {
+	if len(args) > 0 && args[0] == "privacy" {
+		return runProjectsPrivacy(args[1:])
+	}
 	jsonOutput := false
 	for _, arg := range args {
 		if arg == "--json" {
@@ -11614,6 +13608,9 @@ func runProjects(args []string) error {
 	if err != nil {
 		return err
 	}
+	for i := range projects {
+		projects[i].Privacy = store.ProjectPrivacyFor(projects[i].Name).Restrictions()
+	}
 
 	if jsonOutput {
 		enc := json.NewEncoder(os.Stdout)
@@ -11626,14 +13623,14 @@ func runProjects(args []string) error {
 		return nil
 	}
 
-	fmt.Printf("%-20s  %8s  %8s\n", "PROJECT", "MEMORIES", "FACTS")
-	fmt.Println(strings.Repeat("─", 42))
+	fmt.Printf("%-20s  %8s  %8s  %s\n", "PROJECT", "MEMORIES", "FACTS", "PRIVACY")
+	fmt.Println(strings.Repeat("─", 56))
 	for _, p := range projects {
 		name := p.Name
 		if name == "" {
 			name = "(untagged)"
 		}
-		fmt.Printf("%-20s  %8d  %8d\n", name, p.MemoryCount, p.FactCount)
+		fmt.Printf("%-20s  %8d  %8d  %s\n", name, p.MemoryCount, p.FactCount, strings.Join(p.Privacy, ", "))
 	}
 	return nil
 }
@@ -11651,12 +13648,16 @@ func truncateString(s string, max int) string {
 
 func runTag(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: cortex tag --project <name> [--source <pattern>] [--id <id>...] [--auto]")
+		return fmt.Errorf("usage: cortex tag --project <name> [--source <pattern>] [--id <id>...] [--stdin] [--auto] | cortex tag rules <list|test>")
+	}
+	if args[0] == "rules" {
+		return runTagRules(args[1:])
 	}
 
 	project := ""
 	sourcePattern := ""
 	autoTag := false
+	fromStdin := false
 	var memoryIDs []int64
 
 	for i := 0; i < len(args); i++ {
@@ -11680,6 +13681,8 @@ func runTag(args []string) error {
 			memoryIDs = append(memoryIDs, id)
 		case args[i] == "--auto":
 			autoTag = true
+		case args[i] == "--stdin":
+			fromStdin = true
 		default:
 			// Try parsing as memory ID
 			id, err := strconv.ParseInt(args[i], 10, 64)
@@ -11694,6 +13697,9 @@ func runTag(args []string) error {
 	if !autoTag && project == "" {
 		return fmt.Errorf("--project is required (or use --auto for auto-tagging)")
 	}
+	if fromStdin && (autoTag || sourcePattern != "" || len(memoryIDs) > 0) {
+		return fmt.Errorf("--stdin cannot be combined with --auto, --source, or memory IDs")
+	}
 
 	s, err := store.NewStore(getStoreConfig())
 	if err != nil {
@@ -11704,8 +13710,14 @@ func runTag(args []string) error {
 	ctx := context.Background()
 
 	if autoTag {
+		if err := loadUserTagRules(); err != nil {
+			return err
+		}
 		return runAutoTag(ctx, s)
 	}
+	if fromStdin {
+		return runTagStdin(ctx, s, project)
+	}
 
 	var totalTagged int64
 
@@ -11736,8 +13748,9 @@ func runTag(args []string) error {
 	return nil
 }
 
-// runAutoTag applies default project rules to all untagged memories.
-// Uses path-based rules first, then content-keyword matching as fallback.
+// runAutoTag applies user and default project rules to all untagged memories.
+// Uses path-based rules first, then content-keyword matching as fallback,
+// and reports which rules fired.
 func runAutoTag(ctx context.Context, s store.Store) error {
 	// Get all untagged memories
 	memories, err := s.ListMemories(ctx, store.ListOpts{
@@ -11747,9 +13760,12 @@ func runAutoTag(ctx context.Context, s store.Store) error {
 		return fmt.Errorf("listing memories: %w", err)
 	}
 
+	pathRules, contentRules := store.ActiveProjectRules(), store.ActiveContentRules()
 	tagged := 0
 	byProject := make(map[string]int)
 	byMethod := map[string]int{"path": 0, "content": 0}
+	byRule := make(map[string]int)
+	var ruleOrder []string
 
 	for _, m := range memories {
 		if m.Project != "" {
@@ -11757,25 +13773,33 @@ func runAutoTag(ctx context.Context, s store.Store) error {
 		}
 
 		// Try path rules first, then content keywords
-		inferred := store.InferProjectFull(m.SourceFile, m.Content, store.DefaultProjectRules, store.DefaultContentRules)
-		if inferred == "" {
+		match := store.MatchTagRule(m.SourceFile, m.Content, pathRules, contentRules)
+		if match == nil {
 			continue // No matching rule
 		}
 
-		// Track which method matched
-		if store.InferProject(m.SourceFile, store.DefaultProjectRules) != "" {
-			byMethod["path"]++
-		} else {
-			byMethod["content"]++
-		}
-
-		_, err := s.TagMemories(ctx, inferred, []int64{m.ID})
+		_, err := s.TagMemories(ctx, match.Project, []int64{m.ID})
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "  Warning: failed to tag memory %d: %v\n", m.ID, err)
 			continue
 		}
 		tagged++
-		byProject[inferred]++
+		byProject[match.Project]++
+		byMethod[match.Method]++
+
+		// Content matches name the keywords that hit; group by rule origin
+		// and project so the summary stays short.
+		ruleKey := fmt.Sprintf("%s → %s (%s)", match.Rule, match.Project, match.Origin)
+		if match.Method == "content" {
+			ruleKey = fmt.Sprintf("content keywords → %s (%s)", match.Project, match.Origin)
+		}
+		if byRule[ruleKey] == 0 {
+			ruleOrder = append(ruleOrder, ruleKey)
+		}
+		byRule[ruleKey]++
+		if globalVerbose {
+			fmt.Printf("  #%d %s → %s [%s rule: %s]\n", m.ID, truncateString(m.SourceFile, 60), match.Project, match.Method, match.Rule)
+		}
 	}
 
 	if tagged == 0 {
@@ -11788,6 +13812,10 @@ func runAutoTag(ctx context.Context, s store.Store) error {
 		fmt.Printf("  %s: %d\n", project, count)
 	}
 	fmt.Printf("\nBy method: %d path-based, %d content-based\n", byMethod["path"], byMethod["content"])
+	fmt.Println("\nBy rule:")
+	for _, rule := range ruleOrder {
+		fmt.Printf("  %4d  %s\n", byRule[rule], rule)
+	}
 	return nil
 }
 
@@ -11801,10 +13829,12 @@ Subcommands:
   add <provider>      Add a new connector
   sync                Sync connectors (--all or --provider <name>) [--extract] [--no-infer] [--llm <model>]
   status              Show connector health and sync state
+  history <provider>  Show recent syncs (fetched/imported/skipped, duration, errors)
   schedule            Generate auto-sync schedule (launchd/systemd)
   remove <provider>   Remove a connector
   enable <provider>   Enable a disabled connector
   disable <provider>  Disable a connector
+  resume <provider>   Lift a quota pause
   providers           List available provider types`)
 		return nil
 	}
@@ -11818,6 +13848,8 @@ Subcommands:
 		return runConnectSync(args[1:])
 	case "status":
 		return runConnectStatus()
+	case "history":
+		return runConnectHistory(args[1:])
 	case "schedule":
 		return runConnectSchedule(args[1:])
 	case "remove":
@@ -11826,6 +13858,8 @@ Subcommands:
 		return runConnectEnable(args[1:])
 	case "disable":
 		return runConnectDisable(args[1:])
+	case "resume":
+		return runConnectResume(args[1:])
 	case "providers":
 		return runConnectProviders()
 	default:
@@ -11945,8 +13979,10 @@ func runConnectSync(args []string) error {
 		return fmt.Errorf("opening store: %w", err)
 	}
 	defer st.Close()
+	var quotas *connect.QuotaPolicy
 	if resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{}); err == nil {
 		applyExtractionRuntimeConfig(resolvedCfg)
+		quotas = connect.QuotaPolicyFromConfig(resolvedCfg.Import.Quotas)
 	}
 
 	sqliteSt, ok := st.(*store.SQLiteStore)
@@ -11975,6 +14011,7 @@ func runConnectSync(args []string) error {
 		NoInfer: *noInfer,
 		LLM:     *llmFlag,
 		AgentID: *agentFlag,
+		Quotas:  quotas,
 		ProgressFn: func(p connect.SyncProgress) {
 			if p.Total <= 0 {
 				return
@@ -12021,7 +14058,9 @@ func runConnectSync(args []string) error {
 }
 
 func printSyncResult(r connect.SyncResult) {
-	if r.Error != "" {
+	if r.PausedUntil != nil && r.RecordsFetched > 0 {
+		fmt.Printf("⏸ %s: %d fetched, %d imported, then %s\n", r.Provider, r.RecordsFetched, r.RecordsImported, r.Error)
+	} else if r.Error != "" {
 		fmt.Printf("✗ %s: %s\n", r.Provider, r.Error)
 	} else {
 		fmt.Printf("✓ %s: %d fetched, %d imported, %d skipped (%s)\n",
@@ -12033,6 +14072,12 @@ func printSyncResult(r connect.SyncResult) {
 		if r.EdgesInferred > 0 {
 			fmt.Printf("  🔗 Edges inferred: %d\n", r.EdgesInferred)
 		}
+		if r.RecordsExpired > 0 {
+			fmt.Printf("  Snapshots expired: %d\n", r.RecordsExpired)
+		}
+	}
+	if r.SkipSpike != nil {
+		fmt.Printf("  ⚠ Skip ratio spike: %s (cortex connect history %s)\n", r.SkipSpike, r.Provider)
 	}
 }
 
@@ -12054,6 +14099,10 @@ func runConnectStatus() error {
 	if err != nil {
 		return err
 	}
+	var quotas *connect.QuotaPolicy
+	if resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{}); err == nil {
+		quotas = connect.QuotaPolicyFromConfig(resolvedCfg.Import.Quotas)
+	}
 
 	if len(connectors) == 0 {
 		fmt.Println("No connectors configured.")
@@ -12081,11 +14130,29 @@ func runConnectStatus() error {
 		if c.LastError != "" {
 			fmt.Printf("         ⚠  Error: %s\n", c.LastError)
 		}
+		if c.Paused(time.Now()) {
+			fmt.Printf("         ⏸  Paused until %s: %s (cortex connect resume %s)\n",
+				c.PausedUntil.Local().Format("2006-01-02 15:04"), c.PauseReason, c.Provider)
+		}
+		if q := connect.EffectiveQuota(c, quotas); !q.IsZero() {
+			fmt.Printf("         Quota: %s\n", formatConnectQuota(q))
+		}
 		fmt.Println()
 	}
 	return nil
 }
 
+func formatConnectQuota(q connect.Quota) string {
+	var parts []string
+	if q.Hourly > 0 {
+		parts = append(parts, fmt.Sprintf("%d/hour", q.Hourly))
+	}
+	if q.Daily > 0 {
+		parts = append(parts, fmt.Sprintf("%d/day", q.Daily))
+	}
+	return strings.Join(parts, ", ")
+}
+
 func runConnectRemove(args []string) error {
 	if len(args) < 1 {
 		return fmt.Errorf("usage: cortex connect remove <provider>")
@@ -12119,6 +14186,27 @@ func runConnectEnable(args []string) error {
 	return setConnectorEnabled(args[0], true)
 }
 
+func runConnectResume(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: cortex connect resume <provider>")
+	}
+	st, err := store.NewStore(store.StoreConfig{DBPath: getDBPath()})
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer st.Close()
+
+	sqliteSt, ok := st.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("connector operations require SQLite store")
+	}
+	if err := connect.NewConnectorStore(sqliteSt.GetDB()).Resume(context.Background(), args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Resumed connector: %s (the next sync picks up where the paused one stopped)\n", args[0])
+	return nil
+}
+
 func runConnectDisable(args []string) error {
 	if len(args) < 1 {
 		return fmt.Errorf("usage: cortex connect disable <provider>")
@@ -12172,178 +14260,6 @@ type doctorReport struct {
 	Checks      []doctorCheck `json:"checks"`
 }
 
-func runInit(args []string) error {
-	fs := flag.NewFlagSet("init", flag.ContinueOnError)
-	nonInteractive := fs.Bool("y", false, "Accept defaults without prompting")
-	if err := fs.Parse(args); err != nil {
-		return err
-	}
-
-	configPath := cfgresolver.DefaultConfigPath()
-	configDir := filepath.Dir(configPath)
-
-	fmt.Println("🧠 Cortex Setup")
-	fmt.Println()
-
-	// Step 1: Create config directory
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("creating config directory: %w", err)
-	}
-
-	// Step 2: Check if config already exists
-	if _, err := os.Stat(configPath); err == nil {
-		fmt.Printf("  ✓ Config already exists: %s\n", configPath)
-		fmt.Println()
-	} else {
-		// Step 3: Detect LLM provider from env
-		llmProvider := ""
-		llmEnvKey := ""
-		switch {
-		case os.Getenv("OPENROUTER_API_KEY") != "":
-			llmProvider = "openrouter/deepseek/deepseek-chat"
-			llmEnvKey = "OPENROUTER_API_KEY"
-		case os.Getenv("GOOGLE_API_KEY") != "" || os.Getenv("GEMINI_API_KEY") != "":
-			llmProvider = "google/gemini-2.0-flash"
-			if os.Getenv("GOOGLE_API_KEY") != "" {
-				llmEnvKey = "GOOGLE_API_KEY"
-			} else {
-				llmEnvKey = "GEMINI_API_KEY"
-			}
-		case os.Getenv("OPENAI_API_KEY") != "":
-			llmProvider = "openai/gpt-4o-mini"
-			llmEnvKey = "OPENAI_API_KEY"
-		}
-
-		embedCfg, _ := embed.ResolveEmbedConfig("")
-		embedProvider := resolvedEmbedRef(embedCfg)
-		embedSource := resolvedEmbedSource(embedCfg)
-
-		if llmProvider != "" {
-			fmt.Printf("  ✓ Detected LLM key: %s\n", llmEnvKey)
-			fmt.Printf("    Default model: %s\n", llmProvider)
-		} else {
-			fmt.Println("  ⚠ No LLM API key found in environment")
-			fmt.Println("    Set one of: OPENROUTER_API_KEY, GOOGLE_API_KEY, GEMINI_API_KEY, OPENAI_API_KEY")
-			fmt.Println("    LLM features (enrichment, classification, answers) will be skipped until configured.")
-		}
-		fmt.Println()
-
-		if embedProvider != "" {
-			fmt.Printf("  ✓ Default embedder: %s\n", embedProvider)
-			if embedSource != "" {
-				fmt.Printf("    Source: %s\n", embedSource)
-			}
-		} else {
-			fmt.Println("  ⚠ No embedder detected")
-			fmt.Println("    Semantic search will require an explicit --embed provider.")
-		}
-		fmt.Println()
-
-		// Build config YAML
-		var cfgLines []string
-		cfgLines = append(cfgLines, "# Cortex configuration")
-		cfgLines = append(cfgLines, "# Docs: https://github.com/hurttlocker/cortex")
-		cfgLines = append(cfgLines, "")
-		if llmProvider != "" {
-			cfgLines = append(cfgLines, "llm:")
-			cfgLines = append(cfgLines, fmt.Sprintf("  provider: %s", llmProvider))
-			cfgLines = append(cfgLines, fmt.Sprintf("  # API key detected from %s", llmEnvKey))
-			cfgLines = append(cfgLines, "")
-		} else {
-			cfgLines = append(cfgLines, "# llm:")
-			cfgLines = append(cfgLines, "#   provider: openrouter/deepseek/deepseek-chat")
-			cfgLines = append(cfgLines, "#   api_key: your-key-here")
-			cfgLines = append(cfgLines, "")
-		}
-		if strings.HasPrefix(embedProvider, "ollama/") {
-			cfgLines = append(cfgLines, "embed:")
-			cfgLines = append(cfgLines, fmt.Sprintf("  provider: %s", embedProvider))
-			cfgLines = append(cfgLines, "")
-		} else {
-			cfgLines = append(cfgLines, "# embed:")
-			cfgLines = append(cfgLines, "#   provider: onnx/all-minilm-l6-v2")
-			cfgLines = append(cfgLines, "")
-		}
-		cfgContent := strings.Join(cfgLines, "\n") + "\n"
-
-		if !*nonInteractive {
-			fmt.Printf("  Write config to %s? [Y/n] ", configPath)
-			var response string
-			fmt.Scanln(&response)
-			response = strings.TrimSpace(strings.ToLower(response))
-			if response != "" && response != "y" && response != "yes" {
-				fmt.Println("  Skipped config write.")
-				fmt.Println()
-				goto dbSetup
-			}
-		}
-
-		if err := os.WriteFile(configPath, []byte(cfgContent), 0644); err != nil {
-			return fmt.Errorf("writing config: %w", err)
-		}
-		fmt.Printf("  ✓ Wrote %s\n", configPath)
-		fmt.Println()
-	}
-
-dbSetup:
-	// Step 4: Create/verify database
-	dbPath := getDBPath()
-	if dbPath == "" {
-		dbPath = expandUserPath(store.DefaultDBPath)
-	}
-	dbPath = expandUserPath(dbPath)
-
-	if _, err := os.Stat(dbPath); err == nil {
-		st, err := store.NewStore(store.StoreConfig{DBPath: dbPath, ReadOnly: true})
-		if err != nil {
-			fmt.Printf("  ⚠ Database exists but cannot open: %v\n", err)
-			fmt.Println("    Try: cortex doctor")
-		} else {
-			ctx := context.Background()
-			stats, _ := st.Stats(ctx)
-			st.Close()
-			if stats != nil {
-				fmt.Printf("  ✓ Database: %s (%d memories, %d facts)\n", dbPath, stats.MemoryCount, stats.FactCount)
-			} else {
-				fmt.Printf("  ✓ Database: %s (exists)\n", dbPath)
-			}
-		}
-	} else {
-		// Create the DB directory and empty database
-		if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
-			return fmt.Errorf("creating database directory: %w", err)
-		}
-		st, err := store.NewStore(store.StoreConfig{DBPath: dbPath})
-		if err != nil {
-			return fmt.Errorf("creating database: %w", err)
-		}
-		st.Close()
-		fmt.Printf("  ✓ Created database: %s\n", dbPath)
-	}
-	fmt.Println()
-
-	// Step 5: Print next steps
-	fmt.Println("🚀 Ready! Next steps:")
-	fmt.Println()
-	fmt.Println("  cortex import ~/notes/        # Import your files")
-	fmt.Println("  cortex search \"what I know\"   # Search your knowledge")
-	fmt.Println("  cortex doctor                  # Verify full setup")
-	fmt.Println()
-
-	return nil
-}
-
-// isOllamaRunning checks if Ollama is reachable on localhost
-func isOllamaRunning() bool {
-	client := &http.Client{Timeout: 2 * time.Second}
-	resp, err := client.Get("http://localhost:11434/api/tags")
-	if err != nil {
-		return false
-	}
-	resp.Body.Close()
-	return resp.StatusCode == 200
-}
-
 func runDoctor(args []string) error {
 	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
 	jsonOut := fs.Bool("json", false, "Output report as JSON")
@@ -12707,6 +14623,16 @@ func runDoctorChecks() doctorReport {
 		})
 	}
 
+	if resolveErr == nil {
+		var embedCfg *embed.EmbedConfig
+		if autoEmbedErr == nil {
+			embedCfg = autoEmbedCfg
+		}
+		for _, check := range providerFailoverChecks(resolvedCfg, embedCfg) {
+			addDoctorCheck(&report, check)
+		}
+	}
+
 	// Version check — compare against latest GitHub release (cached 24h)
 	if latest, err := checkLatestRelease(); err == nil && latest != "" {
 		if latest != version && latest != "v"+version {
@@ -12854,8 +14780,9 @@ func runConnectSchedule(args []string) error {
 	}
 
 	if *uninstall {
-		return uninstallSchedule()
+		return uninstallSchedule(connectSyncUnit)
 	}
+	fmt.Fprintln(os.Stderr, "Tip: cortex daemon --with-schedules runs syncs (and embed, maintain, digest tasks) on cron schedules from config.yaml without OS units.")
 
 	// Parse interval
 	interval, err := time.ParseDuration(*every)
@@ -12885,13 +14812,30 @@ func runConnectSchedule(args []string) error {
 	}
 
 	if runtime.GOOS == "darwin" {
-		return generateLaunchd(cortexBin, dbPath, syncArgs, intervalSec, *install, *showOnly)
+		return generateLaunchd(connectSyncUnit, cortexBin, dbPath, syncArgs, intervalSec, *install, *showOnly)
 	}
-	return generateSystemd(cortexBin, dbPath, syncArgs, intervalSec, *install, *showOnly)
+	return generateSystemd(connectSyncUnit, cortexBin, dbPath, syncArgs, intervalSec, *install, *showOnly)
+}
+
+// scheduledUnit names the launchd job or systemd timer a schedule command
+// installs.
+type scheduledUnit struct {
+	Label       string // launchd label
+	Service     string // systemd unit name, also the log file name
+	Description string
+	Command     string // the schedule command, for install hints
+}
+
+var connectSyncUnit = scheduledUnit{
+	Label:       "com.cortex.connect-sync",
+	Service:     "cortex-connect-sync",
+	Description: "Cortex Connect Sync",
+	Command:     "connect schedule",
 }
 
-func generateLaunchd(cortexBin, dbPath string, syncArgs []string, intervalSec int, install, showOnly bool) error {
-	label := "com.cortex.connect-sync"
+func generateLaunchd(unit scheduledUnit, cortexBin, dbPath string, syncArgs []string, intervalSec int, install, showOnly bool) error {
+	label := unit.Label
+	logName := strings.TrimPrefix(unit.Service, "cortex-")
 	home, _ := os.UserHomeDir()
 	plistDir := filepath.Join(home, "Library", "LaunchAgents")
 	plistPath := filepath.Join(plistDir, label+".plist")
@@ -12924,13 +14868,13 @@ func generateLaunchd(cortexBin, dbPath string, syncArgs []string, intervalSec in
   <key>StartInterval</key>
   <integer>%d</integer>
   <key>StandardOutPath</key>
-  <string>%s/connect-sync.log</string>
+  <string>%s/%s.log</string>
   <key>StandardErrorPath</key>
-  <string>%s/connect-sync.err</string>
+  <string>%s/%s.err</string>
   <key>RunAtLoad</key>
   <true/>
 </dict>
-</plist>`, label, argsXML, dbPath, home, intervalSec, logDir, logDir)
+</plist>`, label, argsXML, dbPath, home, intervalSec, logDir, logName, logDir, logName)
 
 	if showOnly {
 		fmt.Println(plist)
@@ -12941,14 +14885,14 @@ func generateLaunchd(cortexBin, dbPath string, syncArgs []string, intervalSec in
 	fmt.Printf("  Binary: %s\n", cortexBin)
 	fmt.Printf("  Interval: every %s\n", time.Duration(intervalSec)*time.Second)
 	fmt.Printf("  Args: %v\n", syncArgs)
-	fmt.Printf("  Logs: %s/connect-sync.{log,err}\n", logDir)
+	fmt.Printf("  Logs: %s/%s.{log,err}\n", logDir, logName)
 
 	if !install {
 		fmt.Println()
 		fmt.Println(plist)
 		fmt.Println()
-		fmt.Println("To install: cortex connect schedule --install")
-		fmt.Println("To remove:  cortex connect schedule --uninstall")
+		fmt.Printf("To install: cortex %s --install\n", unit.Command)
+		fmt.Printf("To remove:  cortex %s --uninstall\n", unit.Command)
 		return nil
 	}
 
@@ -12973,21 +14917,21 @@ func generateLaunchd(cortexBin, dbPath string, syncArgs []string, intervalSec in
 	}
 
 	fmt.Printf("\n✓ Installed and loaded: %s\n", label)
-	fmt.Printf("  Syncing every %s with fact extraction\n", time.Duration(intervalSec)*time.Second)
+	fmt.Printf("  Running cortex %s every %s\n", strings.Join(syncArgs, " "), time.Duration(intervalSec)*time.Second)
 	return nil
 }
 
-func generateSystemd(cortexBin, dbPath string, syncArgs []string, intervalSec int, install, showOnly bool) error {
+func generateSystemd(unit scheduledUnit, cortexBin, dbPath string, syncArgs []string, intervalSec int, install, showOnly bool) error {
 	home, _ := os.UserHomeDir()
 	unitDir := filepath.Join(home, ".config", "systemd", "user")
-	serviceName := "cortex-connect-sync"
+	serviceName := unit.Service
 	servicePath := filepath.Join(unitDir, serviceName+".service")
 	timerPath := filepath.Join(unitDir, serviceName+".timer")
 
 	fullCmd := cortexBin + " " + strings.Join(syncArgs, " ")
 
 	service := fmt.Sprintf(`[Unit]
-Description=Cortex Connect Sync
+Description=%s
 After=network.target
 
 [Service]
@@ -12998,12 +14942,12 @@ Environment=PATH=/usr/local/bin:/usr/bin:/bin:%s/bin
 
 [Install]
 WantedBy=default.target
-`, fullCmd, dbPath, home)
+`, unit.Description, fullCmd, dbPath, home)
 
 	// Convert interval to systemd OnUnitActiveSec format
 	intervalMin := intervalSec / 60
 	timer := fmt.Sprintf(`[Unit]
-Description=Cortex Connect Sync Timer
+Description=%s Timer
 
 [Timer]
 OnBootSec=2min
@@ -13012,7 +14956,7 @@ Persistent=true
 
 [Install]
 WantedBy=timers.target
-`, intervalMin)
+`, unit.Description, intervalMin)
 
 	if showOnly {
 		fmt.Println("# " + servicePath)
@@ -13034,8 +14978,8 @@ WantedBy=timers.target
 		fmt.Println("--- Timer ---")
 		fmt.Println(timer)
 		fmt.Println()
-		fmt.Println("To install: cortex connect schedule --install")
-		fmt.Println("To remove:  cortex connect schedule --uninstall")
+		fmt.Printf("To install: cortex %s --install\n", unit.Command)
+		fmt.Printf("To remove:  cortex %s --uninstall\n", unit.Command)
 		return nil
 	}
 
@@ -13061,13 +15005,13 @@ WantedBy=timers.target
 	}
 
 	fmt.Printf("\n✓ Installed and started: %s.timer\n", serviceName)
-	fmt.Printf("  Syncing every %dmin with fact extraction\n", intervalMin)
+	fmt.Printf("  Running cortex %s every %dmin\n", strings.Join(syncArgs, " "), intervalMin)
 	return nil
 }
 
-func uninstallSchedule() error {
+func uninstallSchedule(unit scheduledUnit) error {
 	if runtime.GOOS == "darwin" {
-		label := "com.cortex.connect-sync"
+		label := unit.Label
 		home, _ := os.UserHomeDir()
 		plistPath := filepath.Join(home, "Library", "LaunchAgents", label+".plist")
 
@@ -13080,7 +15024,7 @@ func uninstallSchedule() error {
 	}
 
 	// Linux systemd
-	serviceName := "cortex-connect-sync"
+	serviceName := unit.Service
 	execCommand("systemctl", "--user", "disable", "--now", serviceName+".timer")
 
 	home, _ := os.UserHomeDir()
@@ -13102,15 +15046,15 @@ func execCommand(name string, args ...string) error {
 
 // cortexCommands is the authoritative list of top-level commands for completion.
 var cortexCommands = []string{
-	"import", "reimport", "refresh-source", "search", "recall", "context", "query", "list", "export", "update", "demo",
-	"extract", "classify", "reinforce", "supersede", "fact", "fact-history",
-	"stats", "health", "stale", "conflicts", "agents", "projects",
-	"graph", "cluster",
-	"reason", "bench", "eval",
-	"cleanup", "backfill-scope", "optimize", "embed", "tag", "answer", "ask", "lifecycle", "beliefs", "suppress", "source-weight",
+	"import", "reimport", "refresh-source", "search", "recall", "context", "query", "list", "export", "diff", "backup", "show", "document", "triage", "update", "meta", "class", "delete", "forget", "demo",
+	"extract", "classify", "reinforce", "supersede", "fact", "fact-history", "seed", "note", "review", "jobs", "normalize",
+	"stats", "health", "capture", "stale", "conflicts", "agents", "projects", "namespace", "watch", "decay",
+	"graph", "edge", "cluster",
+	"reason", "chat", "translate", "bench", "eval", "telemetry", "prompts", "examples", "tokens",
+	"cleanup", "backfill-scope", "optimize", "compact", "tier", "embed", "tag", "answer", "ask", "lifecycle", "beliefs", "archive", "suppress", "source-weight", "sources",
 	"rerank-setup", "rerank-serve",
-	"connect", "integration",
-	"mcp", "doctor", "completion", "version", "help",
+	"connect", "integration", "workspace",
+	"mcp", "daemon", "serve", "doctor", "completion", "version", "help",
 }
 
 func runCompletion(args []string) error {
@@ -13183,10 +15127,10 @@ Usage:
   cortex <command> --help             Show detailed help for a command
 
 Memory:
-  import <path>         Import memories from files or directories
+  import <path>         Import memories from files or directories (--format jsonl restores an export)
   reimport <path>       Wipe database and reimport from scratch
   refresh-source <path> Refresh one source file without touching the rest of the DB
-  search <query>        Search memories or facts (keyword, semantic, hybrid, or rrf)
+  search <query>        Search memories or facts (auto, keyword, semantic, hybrid, or rrf)
   recall <query>        Rank retrievable memories with prompt-eligibility diagnostics
   context <query>       Build a prompt-safe memory block for IDE/agent injection
   query                 Filter facts by metadata (--where clauses)
@@ -13196,50 +15140,88 @@ Memory:
   rerank-serve          Run the local reranker daemon for warm cross-encoder scoring
   lifecycle run         Apply built-in lifecycle policies to facts
   beliefs               Belief lifecycle stats + manual state overrides
-  list                  List memories or facts
-  export                Export memory store (json, markdown, csv)
+  archive set|list      Legal holds: make memories/facts immutable, with an audit trail
+  list                  List memories or facts (--cursor pages stably)
+  show <id>             One memory with its facts, edges, embedding, and access history (--context)
+  document <cmd>        Documents (source files): list, show, delete, refresh
+  export                Export memory store (json, jsonl, markdown, csv; --since, --project, --class; jsonl --all)
+  diff <snapshot>       Compare the store against a backup or JSON export
+  backup <dest.db>      Point-in-time copy of the database, safe during live writes
+  triage                Audit log of LLM import triage (import --triage)
   update <id>           Update a memory's content
+  meta set <id>         Fix a memory's agent, channel, class, or metadata (unset/show)
+  class set <class>     Bulk-reassign memory classes by source, project, or query
+  delete <id>           Soft-delete memories (--stdin; filters: --source --project --before --class; --facts, --purge, --dry-run)
+  forget --subject <x>  Erase everything mentioning a subject (GDPR; --dry-run, --redact)
   demo                  Run a full 60-second demo on temp data
 
 Facts:
   extract <file>        Extract facts from a file (without importing)
   classify              Reclassify kv facts using LLM
-  reinforce <id>        Reset decay timer on a fact
-  supersede <id>        Mark a fact as superseded by a newer one
+  reinforce <id>        Reset decay timer on a fact (--stdin for ID lists)
+  supersede <id>        Mark a fact as superseded by a newer one (--stdin for pairs)
   fact keep <id>        Mark a fact as core / operator-kept
   fact drop <id>        Retire a fact
+  fact sources <id>     Every memory behind a fact, incl. summary/merge originals (repair-sources)
+  seed apply <file>     Bootstrap identity/preference/rule facts from a YAML seed
+  note add <id> "text"  Attach a note to a fact or memory (list/search/remove)
+  review list           Low-confidence LLM facts awaiting approve/reject
+  jobs list|status|start|cancel  Background extraction, enrichment, embedding, cluster rebuild jobs
+  normalize predicates  Rewrite predicates to canonical forms (uses, works at, ...)
 
 Observe:
   stats                 Memory statistics, health, and growth
   health                Actionable production health report
-  stale                 Find outdated facts (confidence decay)
-  conflicts             Detect contradictory facts
+  capture report        Auto-capture sessions, dedupe/low-signal rejections, noisiest channels (--since 24h)
+  stale                 Find outdated facts (--action reinforce|supersede-prompt|archive)
+  decay                 Sweep for decayed facts: alerts, --archive-below, --watch, schedule, alerts
+  conflicts             Detect contradictory facts, most severe first
+  conflicts ignore <a> <b>  Accept a disagreement (list / unignore)
   agents                List known agents with per-agent stats
   entity                List, inspect, and merge canonical entities
-  projects              List project tags with counts
+  projects              List project tags with counts (projects privacy list|set for zones)
+  namespace list|move   List namespaces with counts; move memories between them
+  watch subject "<s>"   Notify (alert, webhook, MCP push) when facts about a subject change (list/remove/events)
 
 Knowledge Graph:
   graph <fact_id>       Explore fact relationships (CLI)
   graph --subject <s>   Explore graph from all facts matching a subject
   graph --serve         Launch interactive graph explorer (web UI)
+  graph metrics         Hub facts (centrality), bridge edges, components, top hubs per cluster
+  edge add|list|remove  Curate fact edges (edge import <csv> adds in bulk; remove --source-fact N --type X)
   cluster               List or rebuild topic clusters
+  summarize             Compact clusters into summary facts (summarize undo <id> reverts)
+  brief [--project X]   Identity card and per-project briefs (refreshes stale ones)
 
 LLM:
   reason <query>        LLM reasoning over memories (search → analyze)
+  reason preset install|list|search|publish  Share presets via a checksummed registry
+  chat                  Interactive assistant: retrieval + reason per turn, /search /fact /pin
+  translate --to <lang> Translate memories and facts; search matches across languages
   bench                 Benchmark LLM models for reasoning quality/speed
   eval search           Deterministic retrieval eval over fixture corpus
+  prompts list|show|init|test  Override enrich/classify/resolve/summarize prompts (~/.cortex/prompts/)
+  examples add|list|remove     Curate few-shot (content → facts) examples for enrichment
 
 Maintenance:
+  init                  Setup wizard: config, LLM/embedder, first import, health check
   doctor                Health check (DB, embeddings, connectors, LLM keys)
   cleanup               Remove garbage memories, headless facts, and prune noise
   backfill-scope        Infer missing fact scope from linked memory metadata
   optimize              DB maintenance (integrity check, VACUUM, ANALYZE)
+  compact               Archive old raw memory text once facts are extracted (compact restore undoes)
+  tier                  Shrink the DB under its storage.budget (compact, quantize, vacuum)
   embed [provider/model] Generate embeddings, run/watch the worker, or show status
   embed-source <path>   Finish embeddings for one source file
+  index [tune|quantize] Rebuild the HNSW index, tune params, or switch vector quantization
   suppress              Manage extract suppression patterns in config
   source-weight         Manage search source weights in config
-  tag                   Tag memories by project
+  sources list|stats|set  Source registry: type, trust weight, default class
+  workspace add|list|use|remove  Named DB + model profiles (select with --workspace)
+  tag                   Tag memories by project (tag rules list|test for auto-tag rules)
   ledger record|list    Record/list session outcomes (implicit memory layer)
+  telemetry report      Summarize LLM/embedding usage, latency, and cost by model or command
+  tokens count <file>   Approximate token counts per model family (--model provider/model)
   propose scan|list|accept|dismiss  Propose directives from recurring ledger fix patterns (accept is human-gated)
 
 Connectors:
@@ -13251,15 +15233,21 @@ Connectors:
 
 Integration:
   mcp                   Start MCP server (stdio or --port for HTTP+SSE)
+  daemon                Run MCP + graph + embed watcher + connector sync in one process
+  serve                 Serve the read-only HTTP API (--openapi prints its OpenAPI spec)
   doctor                Validate setup (DB, embeddings, LLM keys, connectors)
   completion            Generate shell completions (bash, zsh, fish)
   version               Print version
 
 Global Flags:
   --db <path>           Database path (default: ~/.cortex/cortex.db, env: CORTEX_DB)
+  --workspace <name>    Use a named workspace's DB and model defaults (env: CORTEX_WORKSPACE)
   --read-only           Open database in read-only mode
+  --namespace <name>    Scope every command to one namespace; "*" reads all (env: CORTEX_NAMESPACE)
   --agent <id>          Scope operations to a specific agent
   --verbose, -v         Show detailed output
+  --timings             Print DB/embed/LLM time, tokens, and cost after the command
+                        (as a {"timings": ...} JSON line on stderr with --json)
   -h, --help            Show this help
 
 Quick Start:
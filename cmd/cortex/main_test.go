@@ -225,6 +225,17 @@ func TestEstimateReasonRunCost_KnownModel(t *testing.T) {
 	}
 }
 
+func TestEstimateReasonRunCostCached_GeminiDiscount(t *testing.T) {
+	cost, known := estimateReasonRunCostCached("gemini", "gemini-2.5-flash", 1000, 800, 500)
+	if !known {
+		t.Fatal("expected native gemini runs to be priced")
+	}
+	want := 0.00036 // (200 + 800*0.25)*0.15/M + 500*0.60/M
+	if math.Abs(cost-want) > 1e-10 {
+		t.Fatalf("cost = %.8f, want %.8f", cost, want)
+	}
+}
+
 func TestEstimateReasonRunCost_UnknownModel(t *testing.T) {
 	cost, known := estimateReasonRunCost("openrouter", "unknown/model", 1000, 500)
 	if known {
@@ -249,32 +260,6 @@ func TestShouldWriteReasonTelemetry_Disabled(t *testing.T) {
 	}
 }
 
-func TestWriteReasonTelemetry_WritesJSONL(t *testing.T) {
-	home := t.TempDir()
-	t.Setenv("HOME", home)
-
-	entry := reasonRunTelemetry{
-		Timestamp: "2026-02-19T20:00:00Z",
-		Mode:      "one-shot",
-		Query:     "test query",
-		Provider:  "openrouter",
-		Model:     "google/gemini-3-flash-preview",
-	}
-	if err := writeReasonTelemetry(entry); err != nil {
-		t.Fatalf("writeReasonTelemetry failed: %v", err)
-	}
-
-	path := filepath.Join(home, ".cortex", "reason-telemetry.jsonl")
-	b, err := os.ReadFile(path)
-	if err != nil {
-		t.Fatalf("reading telemetry file failed: %v", err)
-	}
-	s := string(b)
-	if !strings.Contains(s, `"mode":"one-shot"`) || !strings.Contains(s, `"query":"test query"`) {
-		t.Fatalf("unexpected telemetry content: %s", s)
-	}
-}
-
 // ==================== outputStaleTTY ====================
 
 func captureStdout(fn func()) string {
@@ -605,6 +590,104 @@ func TestRunSearch_InvalidLimitRejected(t *testing.T) {
 	}
 }
 
+func TestRunSearch_UnknownProfileRejected(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cfgDir := filepath.Join(home, ".cortex")
+	if err := os.MkdirAll(cfgDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cfgDir, "config.yaml"), []byte("search:\n  profiles:\n    trading-ops:\n      project: trading\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runSearch([]string{"hello", "--profile=ops"})
+	if err == nil {
+		t.Fatal("expected unknown profile error")
+	}
+	if !strings.Contains(err.Error(), `unknown search profile "ops" (available: trading-ops)`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRunSearch_DefaultsToAutoRouting(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CORTEX_EMBED", "")
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddMemory(context.Background(), &store.Memory{Content: "Ticket OPS-1234 tracks the rollback runbook", SourceFile: "ops.md"}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	out := captureStdout(func() {
+		if err := runSearch([]string{"OPS-1234", "--explain", "--json"}); err != nil {
+			t.Fatalf("search: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"mode_route"`) || !strings.Contains(out, `exact identifier \"OPS-1234\"`) {
+		t.Fatalf("expected auto routing in explain output:\n%s", out)
+	}
+}
+
+func TestRunSearch_GroupBySource(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for i, content := range []string{
+		"Rollback step one: freeze deploys",
+		"Rollback step two: revert the release tag",
+		"Rollback step three: notify the channel",
+	} {
+		if _, err := s.AddMemory(ctx, &store.Memory{Content: content, SourceFile: "runbook.md", SourceLine: i + 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.AddMemory(ctx, &store.Memory{Content: "Rollback drills happen quarterly", SourceFile: "calendar.md"}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	out := captureStdout(func() {
+		if err := runSearch([]string{"rollback", "--group-by", "source", "--limit", "5", "--no-dedupe", "--json"}); err != nil {
+			t.Fatalf("search: %v", err)
+		}
+	})
+	var groups []search.ResultGroup
+	if err := json.Unmarshal([]byte(out), &groups); err != nil {
+		t.Fatalf("parse grouped output: %v\n%s", err, out)
+	}
+	counts := map[string]int{}
+	for _, g := range groups {
+		counts[g.Key] = g.Count
+	}
+	if counts["runbook.md"] != 3 || counts["calendar.md"] != 1 {
+		t.Fatalf("unexpected groups: %+v", counts)
+	}
+
+	if err := runSearch([]string{"rollback", "--group-by=agent"}); err == nil {
+		t.Fatal("expected invalid group-by error")
+	}
+	if err := runSearch([]string{"rollback", "--group-by=source", "--budget", "200"}); err == nil {
+		t.Fatal("expected --group-by/--budget conflict")
+	}
+}
+
 func TestParseSearchScopeFlags(t *testing.T) {
 	scopes, err := parseSearchScopeFlags([]string{
 		"agent:niot",
@@ -632,9 +715,9 @@ func TestParseSearchScopeFlags(t *testing.T) {
 
 func TestPackSearchResultsByBudget(t *testing.T) {
 	results := []search.Result{
-		{Content: strings.Repeat("a", 80)},
-		{Content: strings.Repeat("b", 120)},
-		{Content: strings.Repeat("c", 200)},
+		{Content: strings.TrimSpace(strings.Repeat("alpha ", 20))},
+		{Content: strings.TrimSpace(strings.Repeat("beta ", 30))},
+		{Content: strings.TrimSpace(strings.Repeat("gamma ", 50))},
 	}
 
 	packed, used := packSearchResultsByBudget(results, 55, 0)
@@ -695,6 +778,28 @@ func TestParseSourceBoostArg_CustomWeightAndClamp(t *testing.T) {
 	}
 }
 
+func TestParseGraphBoostArg(t *testing.T) {
+	if w, err := parseGraphBoostArg("0.3"); err != nil || w != 0.3 {
+		t.Fatalf("parseGraphBoostArg(0.3) = %f, %v", w, err)
+	}
+	for _, bad := range []string{"-0.1", "1.5", "strong"} {
+		if _, err := parseGraphBoostArg(bad); err == nil {
+			t.Errorf("parseGraphBoostArg(%q): expected error", bad)
+		}
+	}
+}
+
+func TestParseImportWorkers(t *testing.T) {
+	if n, err := parseImportWorkers("8"); err != nil || n != 8 {
+		t.Fatalf("parseImportWorkers(8) = %d, %v", n, err)
+	}
+	for _, bad := range []string{"0", "-2", "65", "many"} {
+		if _, err := parseImportWorkers(bad); err == nil {
+			t.Errorf("parseImportWorkers(%q): expected error", bad)
+		}
+	}
+}
+
 func TestParseBenchArgs_Compare(t *testing.T) {
 	opts, err := parseBenchArgs([]string{"--compare", "google/gemini-2.5-flash,deepseek/deepseek-v3.2", "--recursive"})
 	if err != nil {
@@ -869,6 +974,36 @@ func TestOutputConflictsTTY_PrioritizesHigherSimilarity(t *testing.T) {
 	}
 }
 
+func TestOutputConflictsTTY_NegationsFirst(t *testing.T) {
+	conflicts := []observe.Conflict{
+		{
+			ConflictType: "attribute",
+			Similarity:   1.0,
+			Fact1:        store.Fact{ID: 1, Subject: "db", Predicate: "engine", Object: "sqlite"},
+			Fact2:        store.Fact{ID: 2, Subject: "db", Predicate: "engine", Object: "postgres"},
+		},
+		{
+			ConflictType:  "negation",
+			Similarity:    1.0,
+			Severity:      observe.SeverityHigh,
+			SeverityScore: 0.76,
+			Fact1:         store.Fact{ID: 3, Subject: "sso", Predicate: "is", Object: "enabled"},
+			Fact2:         store.Fact{ID: 4, Subject: "sso", Predicate: "is not", Object: "enabled", Negated: true},
+		},
+	}
+
+	out := captureStdout(func() {
+		if err := outputConflictsTTY(conflicts, false); err != nil {
+			t.Fatalf("outputConflictsTTY: %v", err)
+		}
+	})
+
+	neg := strings.Index(out, "negation conflict (high severity 0.76)")
+	if neg < 0 || neg > strings.Index(out, "attribute conflict") {
+		t.Fatalf("expected high-severity negation to be shown first, got: %q", out)
+	}
+}
+
 func TestOutputResolveBatchTTY_CompactsByDefault(t *testing.T) {
 	batch := &observe.ResolveBatch{Total: 15, Resolved: 15, Results: make([]observe.Resolution, 0, 15)}
 	for i := 0; i < 15; i++ {
@@ -3434,6 +3569,49 @@ func TestRunCleanup_PrunesTemporalNoiseFacts(t *testing.T) {
 	}
 }
 
+func TestRunCleanup_SemanticDedup(t *testing.T) {
+	useExamplesTestDB(t)
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	ctx := context.Background()
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "desk sizing notes with enough context to survive base cleanup"})
+	keep, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "desk", Predicate: "trade_size", Object: "2 lots", FactType: "config", Confidence: 0.9})
+	dupe, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "desk", Predicate: "trades", Object: "two lots per entry", FactType: "config", Confidence: 0.5})
+	s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "desk", Predicate: "deploy_window", Object: "Fridays", FactType: "config", Confidence: 0.7})
+	s.Close()
+
+	out := captureStdout(func() {
+		if err := runCleanup([]string{"--semantic-dedup", "--dry-run"}); err != nil {
+			t.Fatalf("runCleanup dry run: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Semantic fact dedup dry run") || !strings.Contains(out, "Facts to merge:   1") ||
+		!strings.Contains(out, "desk | trade_size 2 lots (0.90 -> 0.95)") {
+		t.Fatalf("unexpected dry-run output:\n%s", out)
+	}
+
+	captureStdout(func() {
+		if err := runCleanup([]string{"--semantic-dedup"}); err != nil {
+			t.Fatalf("runCleanup: %v", err)
+		}
+	})
+	s, err = store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatalf("Reopen store: %v", err)
+	}
+	defer s.Close()
+	if f, _ := s.GetFact(ctx, dupe); f.SupersededBy == nil || *f.SupersededBy != keep {
+		t.Fatalf("expected fact %d superseded by %d, got %+v", dupe, keep, f)
+	}
+
+	if err := runCleanup([]string{"--semantic-threshold", "1.5"}); err == nil {
+		t.Fatal("expected error for out-of-range --semantic-threshold")
+	}
+}
+
 func TestCompletion_Bash(t *testing.T) {
 	exitCode, out := runMainSubprocess(t, "completion", "bash")
 	if exitCode != 0 {
@@ -3489,3 +3667,20 @@ func TestCompletion_NoArgs(t *testing.T) {
 		t.Fatalf("expected usage text, got: %q", out)
 	}
 }
+
+func TestPrintNoResultDiagnosis(t *testing.T) {
+	var buf bytes.Buffer
+	printNoResultDiagnosis(&buf, &search.NoResultDiagnosis{
+		Mode:          search.ModeKeyword,
+		StoreMemories: 3,
+		Candidates:    3,
+		Stages:        []search.DiagnosisStage{{Stage: "class", Removed: 3, Remaining: 0, Detail: "class=decision"}},
+		Cause:         "the class filter removed the last 3 candidates",
+	})
+	out := buf.String()
+	for _, want := range []string{"store: 3 memories", "FTS matched 3 candidates", "class      -3 → 0  (class=decision)", "💡 the class filter"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func metaUsageText() string {
+	return `Usage: cortex meta <set|unset|show> <memory_id> [flags]
+
+Correct a memory's provenance after import without re-importing it. Every
+change is recorded in the event log and listed by ` + "`cortex meta show`" + `.
+
+Subcommands:
+  set <id> [--agent <id>] [--channel <name>] [--class <class>] [--metadata <json>] [--json]
+                        Set fields; --metadata merges keys into the existing metadata
+  unset <id> <field>... [--json]
+                        Clear fields: agent, channel, class, or any metadata key
+  show <id> [--json]    Current class and metadata, plus the change history
+
+Metadata keys: ` + strings.Join(store.MetadataKeys(), ", ") + `
+
+Example:
+  cortex meta set 42 --agent hawk --class rule
+  cortex meta unset 42 channel session_key`
+}
+
+// metaFieldAliases maps CLI field names to metadata keys.
+var metaFieldAliases = map[string]string{
+	"agent": "agent_id",
+}
+
+func runMeta(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(metaUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "set":
+		return runMetaEdit(args[1:], false)
+	case "unset":
+		return runMetaEdit(args[1:], true)
+	case "show":
+		return runMetaShow(args[1:])
+	default:
+		return fmt.Errorf("unknown meta subcommand: %s", args[0])
+	}
+}
+
+func parseMetaMemoryID(args []string) (int64, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return 0, fmt.Errorf("memory id is required")
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid memory id %q", args[0])
+	}
+	return id, nil
+}
+
+func openMetaStore() (*store.SQLiteStore, func(), error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening store: %w", err)
+	}
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, nil, fmt.Errorf("meta requires SQLiteStore")
+	}
+	return sqlStore, func() { s.Close() }, nil
+}
+
+// runMetaEdit handles `cortex meta set` and `cortex meta unset`.
+func runMetaEdit(args []string, unset bool) error {
+	id, err := parseMetaMemoryID(args)
+	if err != nil {
+		return err
+	}
+	edit := store.MemoryMetaEdit{Set: map[string]interface{}{}, Actor: "cli"}
+	jsonOutput := false
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--json" {
+			jsonOutput = true
+			continue
+		}
+		if unset {
+			field := metaFieldName(strings.TrimPrefix(arg, "--"))
+			if field == "class" {
+				empty := ""
+				edit.Class = &empty
+			} else {
+				edit.Unset = append(edit.Unset, field)
+			}
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !strings.HasPrefix(arg, "--") {
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+		switch name {
+		case "agent", "channel", "class", "metadata":
+		default:
+			return fmt.Errorf("unknown flag: %s", arg)
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a value", arg)
+			}
+			i++
+			value = args[i]
+		}
+		switch name {
+		case "class":
+			edit.Class = &value
+		case "metadata":
+			var fields map[string]interface{}
+			if err := json.Unmarshal([]byte(value), &fields); err != nil {
+				return fmt.Errorf("invalid --metadata: %w", err)
+			}
+			for k, v := range fields {
+				edit.Set[k] = v
+			}
+		default:
+			edit.Set[metaFieldName(name)] = value
+		}
+	}
+	if len(edit.Set) == 0 && len(edit.Unset) == 0 && edit.Class == nil {
+		if unset {
+			return fmt.Errorf("usage: cortex meta unset <memory_id> <field>...")
+		}
+		return fmt.Errorf("usage: cortex meta set <memory_id> [--agent X] [--channel Y] [--class C] [--metadata <json>]")
+	}
+
+	s, closeStore, err := openMetaStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	changes, err := s.EditMemoryMeta(context.Background(), id, edit)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		if changes == nil {
+			changes = []store.MemoryMetaChange{}
+		}
+		data, _ := json.MarshalIndent(map[string]interface{}{"memory_id": id, "changes": changes}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(changes) == 0 {
+		fmt.Printf("Memory %d unchanged.\n", id)
+		return nil
+	}
+	fmt.Printf("Updated memory %d:\n", id)
+	for _, c := range changes {
+		fmt.Printf("  %s: %s → %s\n", c.Field, metaDisplay(c.Old), metaDisplay(c.New))
+	}
+	return nil
+}
+
+// runMetaShow handles `cortex meta show`.
+func runMetaShow(args []string) error {
+	id, err := parseMetaMemoryID(args)
+	if err != nil {
+		return err
+	}
+	jsonOutput := false
+	for _, arg := range args[1:] {
+		switch {
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	s, closeStore, err := openMetaStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	ctx := context.Background()
+	m, err := s.GetMemory(ctx, id)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return fmt.Errorf("memory %d not found", id)
+	}
+	history, err := s.MemoryMetaHistory(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		if history == nil {
+			history = []store.MemoryEvent{}
+		}
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"memory_id":    id,
+			"source_file":  m.SourceFile,
+			"memory_class": m.MemoryClass,
+			"metadata":     m.Metadata,
+			"history":      history,
+		}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Printf("Memory %d (%s)\n", id, m.SourceFile)
+	fmt.Printf("  class: %s\n", metaDisplay(m.MemoryClass))
+	if m.Metadata == nil {
+		fmt.Println("  metadata: (none)")
+	} else {
+		raw, _ := json.Marshal(m.Metadata)
+		var fields map[string]interface{}
+		_ = json.Unmarshal(raw, &fields)
+		for _, key := range store.MetadataKeys() {
+			if v, ok := fields[key]; ok {
+				fmt.Printf("  %s: %v\n", key, v)
+			}
+		}
+	}
+	if len(history) > 0 {
+		fmt.Println("\nHistory:")
+		for _, e := range history {
+			fmt.Printf("  %s  %s → %s\n", e.CreatedAt.Format("2006-01-02 15:04"),
+				strings.TrimPrefix(e.OldValue, "memory "), strings.TrimPrefix(e.NewValue, "memory "))
+		}
+	}
+	return nil
+}
+
+func metaFieldName(name string) string {
+	if key, ok := metaFieldAliases[name]; ok {
+		return key
+	}
+	return name
+}
+
+func metaDisplay(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return v
+}
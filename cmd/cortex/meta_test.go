@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunMeta_SetUnsetShow(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	memID, _ := s.AddMemory(ctx, &store.Memory{
+		Content:    "always run migrations before deploy",
+		SourceFile: "chat.md",
+		Metadata:   &store.Metadata{AgentID: "sage", Channel: "discord"},
+	})
+	s.Close()
+	id := strconv.FormatInt(memID, 10)
+
+	var runErr error
+	out := captureStdout(func() {
+		runErr = runMeta([]string{"set", id, "--agent", "hawk", "--class=rule", "--metadata", `{"session_key":"agent:hawk:main"}`})
+	})
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+	if !strings.Contains(out, "agent_id: sage → hawk") || !strings.Contains(out, "memory_class: (none) → rule") {
+		t.Fatalf("unexpected set output:\n%s", out)
+	}
+
+	out = captureStdout(func() { runErr = runMeta([]string{"unset", id, "channel", "--class"}) })
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+	if !strings.Contains(out, "channel: discord → (none)") || !strings.Contains(out, "memory_class: rule → (none)") {
+		t.Fatalf("unexpected unset output:\n%s", out)
+	}
+
+	out = captureStdout(func() { runErr = runMeta([]string{"show", id}) })
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+	if !strings.Contains(out, "agent_id: hawk") || !strings.Contains(out, "session_key: agent:hawk:main") ||
+		strings.Contains(out, "channel: discord") || strings.Count(out, "→") != 5 {
+		t.Fatalf("unexpected show output:\n%s", out)
+	}
+
+	if err := runMeta([]string{"set", id, "--metadata", `{"k":"v"}`}); err == nil || !strings.Contains(err.Error(), "unknown metadata key") {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+	if err := runMeta([]string{"set", id, "--project", "x"}); err == nil || !strings.Contains(err.Error(), "unknown flag") {
+		t.Fatalf("expected unknown flag error, got %v", err)
+	}
+}
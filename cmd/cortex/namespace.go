@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const namespaceUsage = `usage: cortex namespace <command>
+
+Commands:
+  list [--json]                          Namespaces with memory and fact counts
+  move --to <ns> [--source <glob>]       Move memories (and their facts) from the
+                                         active namespace into <ns>
+
+The active namespace comes from --namespace or CORTEX_NAMESPACE ("default"
+when unset; "*" reads every namespace).`
+
+// runNamespace handles `cortex namespace`: inspecting and reorganizing the
+// namespaces that partition one database.
+func runNamespace(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", namespaceUsage)
+	}
+	switch args[0] {
+	case "list", "ls":
+		return runNamespaceList(args[1:])
+	case "move":
+		return runNamespaceMove(args[1:])
+	case "--help", "-h", "help":
+		fmt.Println(namespaceUsage)
+		return nil
+	default:
+		return fmt.Errorf("unknown namespace command: %s\n%s", args[0], namespaceUsage)
+	}
+}
+
+func runNamespaceList(args []string) error {
+	jsonOutput := false
+	for _, arg := range args {
+		switch {
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	sqlStore, closeStore, err := openNamespaceStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	namespaces, err := sqlStore.ListNamespaces(context.Background())
+	if err != nil {
+		return err
+	}
+	if namespaces == nil {
+		namespaces = []store.NamespaceInfo{}
+	}
+
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(namespaces)
+	}
+	if len(namespaces) == 0 {
+		fmt.Println("No memories yet.")
+		return nil
+	}
+	active := store.NormalizeNamespace(getNamespace())
+	fmt.Printf("  %-24s %10s %10s\n", "NAMESPACE", "MEMORIES", "FACTS")
+	for _, ns := range namespaces {
+		marker := " "
+		if ns.Name == active {
+			marker = "*"
+		}
+		fmt.Printf("%s %-24s %10d %10d\n", marker, ns.Name, ns.Memories, ns.Facts)
+	}
+	return nil
+}
+
+func runNamespaceMove(args []string) error {
+	var to, source string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--to" || arg == "--source":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", arg)
+			}
+			i++
+			if arg == "--to" {
+				to = args[i]
+			} else {
+				source = args[i]
+			}
+		case strings.HasPrefix(arg, "--to="):
+			to = strings.TrimPrefix(arg, "--to=")
+		case strings.HasPrefix(arg, "--source="):
+			source = strings.TrimPrefix(arg, "--source=")
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if strings.TrimSpace(to) == "" {
+		return fmt.Errorf("namespace move needs --to <namespace>\n%s", namespaceUsage)
+	}
+
+	sqlStore, closeStore, err := openNamespaceStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	n, err := sqlStore.MoveToNamespace(context.Background(), to, source)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Moved %d %s to namespace %s.\n", n, pluralize("memory", "memories", int(n)), store.NormalizeNamespace(to))
+	return nil
+}
+
+func openNamespaceStore() (*store.SQLiteStore, func(), error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening store: %w", err)
+	}
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, nil, fmt.Errorf("namespace commands require SQLiteStore")
+	}
+	return sqlStore, func() { s.Close() }, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunNamespace(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+	t.Setenv("CORTEX_NAMESPACE", "")
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, m := range []*store.Memory{
+		{Content: "buy oat milk", SourceFile: "/home/list.md"},
+		{Content: "ship v2 on monday", SourceFile: "/work/todo.md"},
+		{Content: "book the team offsite", SourceFile: "/work/plan.md"},
+	} {
+		if _, err := s.AddMemory(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	var runErr error
+	out := captureStdout(func() { runErr = runNamespace([]string{"move", "--to", "work", "--source=/work/*"}) })
+	if runErr != nil || !strings.Contains(out, "Moved 2 memories to namespace work.") {
+		t.Fatalf("namespace move = %q, %v", out, runErr)
+	}
+
+	out = captureStdout(func() { runErr = runNamespace([]string{"list", "--json"}) })
+	if runErr != nil {
+		t.Fatalf("namespace list: %v", runErr)
+	}
+	var namespaces []store.NamespaceInfo
+	if err := json.Unmarshal([]byte(out), &namespaces); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(namespaces) != 2 || namespaces[0].Name != "work" || namespaces[0].Memories != 2 || namespaces[1].Name != "default" {
+		t.Fatalf("namespaces = %+v", namespaces)
+	}
+
+	// --namespace scopes the store every command opens.
+	parseGlobalFlags([]string{"--namespace", "work", "list"})
+	s, err = store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if n, err := s.(*store.SQLiteStore).CountMemories(ctx, store.ListOpts{}); err != nil || n != 2 {
+		t.Fatalf("memories in work = %d, %v", n, err)
+	}
+
+	t.Setenv("CORTEX_NAMESPACE", "no spaces")
+	if err := checkNamespace(); err == nil {
+		t.Fatal("expected an invalid namespace error")
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"move"}, "needs --to"},
+		{[]string{"move", "--to"}, "--to needs a value"},
+		{[]string{"list", "--bogus"}, "unknown flag: --bogus"},
+		{[]string{"rename"}, "unknown namespace command: rename"},
+	} {
+		if err := runNamespace(tc.args); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("runNamespace(%v) = %v, want %q", tc.args, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/extract"
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func normalizeUsageText() string {
+	return `Usage: cortex normalize <predicates|aliases> [flags]
+
+New facts have their predicate rewritten to a canonical form on insert
+("is using", "utilizes" → "uses"). The alias table is built in and extended
+in config.yaml:
+
+  policies:
+    predicate_aliases:
+      leverages: uses       # alias: canonical
+      works for: ""         # "" removes a built-in alias
+
+Subcommands:
+  predicates [--dry-run] [--llm <provider/model>] [--save] [--json]
+                        Rewrite stored facts to the canonical predicates. With
+                        --llm, the model also maps paraphrases the table misses;
+                        --save writes those mappings to policies.predicate_aliases
+  aliases [--json]      Show the active alias table`
+}
+
+func runNormalize(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(normalizeUsageText())
+		return nil
+	}
+	resolved, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	applyExtractionRuntimeConfig(resolved)
+	switch args[0] {
+	case "predicates":
+		return runNormalizePredicates(args[1:])
+	case "aliases":
+		return runNormalizeAliases(args[1:])
+	default:
+		return fmt.Errorf("unknown normalize subcommand: %s", args[0])
+	}
+}
+
+type predicateAliasView struct {
+	Alias     string `json:"alias"`
+	Canonical string `json:"canonical"`
+	Origin    string `json:"origin"` // "built-in" or "config"
+}
+
+func runNormalizeAliases(args []string) error {
+	jsonOutput := false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOutput = true
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return fmt.Errorf("unknown flag: %s", arg)
+			}
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	aliases := store.PredicateAliases()
+	views := make([]predicateAliasView, 0, len(aliases))
+	for alias, canonical := range aliases {
+		origin := "config"
+		if store.IsBuiltinPredicateAlias(alias, canonical) {
+			origin = "built-in"
+		}
+		views = append(views, predicateAliasView{Alias: alias, Canonical: canonical, Origin: origin})
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].Canonical != views[j].Canonical {
+			return views[i].Canonical < views[j].Canonical
+		}
+		return views[i].Alias < views[j].Alias
+	})
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(views, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	for _, v := range views {
+		fmt.Printf("  %-24s → %-14s %s\n", v.Alias, v.Canonical, v.Origin)
+	}
+	fmt.Printf("\n%d %s\n", len(views), pluralize("alias", "aliases", len(views)))
+	return nil
+}
+
+func runNormalizePredicates(args []string) error {
+	dryRun, save, jsonOutput := false, false, false
+	llmFlag := ""
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--dry-run" || args[i] == "-n":
+			dryRun = true
+		case args[i] == "--save":
+			save = true
+		case args[i] == "--json":
+			jsonOutput = true
+		case args[i] == "--llm" && i+1 < len(args):
+			i++
+			llmFlag = args[i]
+		case strings.HasPrefix(args[i], "--llm="):
+			llmFlag = strings.TrimPrefix(args[i], "--llm=")
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	if save && llmFlag == "" {
+		return fmt.Errorf("--save only applies with --llm")
+	}
+
+	var provider llm.Provider
+	if llmFlag != "" {
+		llmCfg, err := llm.ParseLLMFlag(llmFlag)
+		if err != nil {
+			return fmt.Errorf("parsing --llm: %w", err)
+		}
+		provider, err = llm.NewProvider(llmCfg)
+		if err != nil {
+			return fmt.Errorf("creating LLM provider: %w", err)
+		}
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	ss, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("normalize requires SQLiteStore")
+	}
+	ctx := context.Background()
+
+	var suggested map[string]string
+	if provider != nil {
+		suggested, err = suggestPredicateAliases(ctx, ss, provider)
+		if err != nil {
+			return err
+		}
+	}
+
+	report, err := ss.NormalizePredicates(ctx, store.PredicateNormalizeOptions{DryRun: dryRun, Extra: suggested})
+	if err != nil {
+		return err
+	}
+
+	savedPath := ""
+	if save && len(suggested) > 0 && !dryRun {
+		savedPath, err = savePredicateAliases(suggested)
+		if err != nil {
+			return fmt.Errorf("saving predicate aliases: %w", err)
+		}
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"report":    report,
+			"suggested": suggested,
+			"saved_to":  savedPath,
+		}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if dryRun {
+		fmt.Printf("Predicate normalization dry run (%d distinct predicates):\n", report.DistinctPredicates)
+	} else {
+		fmt.Printf("Predicate normalization (%d distinct predicates):\n", report.DistinctPredicates)
+	}
+	if len(report.Renames) == 0 {
+		fmt.Println("  Already canonical.")
+	}
+	for _, r := range report.Renames {
+		marker := ""
+		if _, ok := suggested[r.From]; ok {
+			marker = "  (llm)"
+		}
+		fmt.Printf("  %-24s → %-14s %d %s%s\n", r.From, r.To, r.Facts, pluralize("fact", "facts", r.Facts), marker)
+	}
+	if dryRun {
+		fmt.Printf("\n%d %s would be updated\n", report.FactsUpdated, pluralize("fact", "facts", report.FactsUpdated))
+	} else {
+		fmt.Printf("\nUpdated %d %s\n", report.FactsUpdated, pluralize("fact", "facts", report.FactsUpdated))
+	}
+	if savedPath != "" {
+		fmt.Printf("Saved %d LLM %s to %s\n", len(suggested), pluralize("alias", "aliases", len(suggested)), savedPath)
+	} else if len(suggested) > 0 && !save {
+		fmt.Println("LLM mappings applied to this run only; add --save to apply them to new facts too.")
+	}
+	return nil
+}
+
+// suggestPredicateAliases sends the predicates the alias table leaves alone
+// to the LLM and returns its alias → canonical mappings.
+func suggestPredicateAliases(ctx context.Context, ss *store.SQLiteStore, provider llm.Provider) (map[string]string, error) {
+	usage, err := ss.ListPredicateUsage(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	var pending []extract.PredicateCount
+	for _, u := range usage {
+		if store.CanonicalPredicate(u.Predicate) == u.Predicate {
+			pending = append(pending, extract.PredicateCount{Predicate: u.Predicate, Facts: u.Facts})
+		}
+	}
+	canonicalSet := map[string]bool{}
+	for _, c := range store.PredicateAliases() {
+		canonicalSet[c] = true
+	}
+	canonical := make([]string, 0, len(canonicalSet))
+	for c := range canonicalSet {
+		canonical = append(canonical, c)
+	}
+
+	fmt.Fprintf(os.Stderr, "Asking %s to map %d %s...\n", provider.Name(), len(pending), pluralize("predicate", "predicates", len(pending)))
+	suggested, err := extract.SuggestPredicateAliases(ctx, provider, pending, canonical)
+	if err != nil {
+		return nil, fmt.Errorf("suggesting predicate aliases: %w", err)
+	}
+	return suggested, nil
+}
+
+// savePredicateAliases merges aliases into policies.predicate_aliases in
+// config.yaml and returns the file written.
+func savePredicateAliases(aliases map[string]string) (string, error) {
+	cfg, cfgPath, err := loadMutableConfig("")
+	if err != nil {
+		return "", err
+	}
+	existing := getNestedMap(getNestedMap(cfg, "policies"), "predicate_aliases")
+	for alias, canonical := range aliases {
+		existing[alias] = canonical
+	}
+	if err := writeMutableConfig(cfgPath, cfg); err != nil {
+		return "", err
+	}
+	return cfgPath, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunNormalize_PredicatesAndAliases(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() {
+		globalDBPath = oldDBPath
+		store.SetPredicateAliases(nil)
+	})
+	cfgDir := filepath.Join(home, ".cortex")
+	if err := os.MkdirAll(cfgDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cfgDir, "config.yaml"), []byte("policies:\n  predicate_aliases:\n    leverages: uses\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss := s.(*store.SQLiteStore)
+	ctx := context.Background()
+	memID, _ := ss.AddMemory(ctx, &store.Memory{Content: "stack notes"})
+	legacy, _ := ss.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "api", Predicate: "queries", Object: "postgres", FactType: "relationship"})
+	ss.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "cli", Predicate: "reads", Object: "config", FactType: "relationship"})
+	// Written before any alias table existed.
+	if _, err := ss.ExecContext(ctx, `UPDATE facts SET predicate = 'is_using' WHERE id = ?`, legacy); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	out := captureStdout(func() {
+		if err := runNormalize([]string{"predicates", "--dry-run"}); err != nil {
+			t.Fatalf("dry run: %v", err)
+		}
+	})
+	if !strings.Contains(out, "is_using") || !strings.Contains(out, "1 fact would be updated") {
+		t.Fatalf("unexpected dry-run output:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runNormalize([]string{"predicates"}); err != nil {
+			t.Fatalf("normalize: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Updated 1 fact") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	s, err = store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f, _ := s.GetFact(ctx, legacy); f.Predicate != "uses" {
+		t.Fatalf("expected predicate rewritten to uses, got %q", f.Predicate)
+	}
+	s.Close()
+
+	out = captureStdout(func() {
+		if err := runNormalize([]string{"aliases"}); err != nil {
+			t.Fatalf("aliases: %v", err)
+		}
+	})
+	if !strings.Contains(out, "leverages") || !strings.Contains(out, "config") || !strings.Contains(out, "built-in") {
+		t.Fatalf("expected config and built-in aliases:\n%s", out)
+	}
+
+	if err := runNormalize([]string{"predicates", "--save"}); err == nil || !strings.Contains(err.Error(), "--llm") {
+		t.Fatalf("expected --save to require --llm, got %v", err)
+	}
+	if err := runNormalize([]string{"bogus"}); err == nil {
+		t.Fatal("expected unknown subcommand error")
+	}
+}
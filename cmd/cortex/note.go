@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func noteUsageText() string {
+	return `Usage: cortex note <add|list|search|remove> [flags]
+
+Attach free-text notes to facts and memories. Notes show up in fact-history,
+search --explain, and the graph UI.
+
+Subcommands:
+  add <id> "<text>" [--memory] [--label <label>] [--agent <id>]
+                        Annotate fact <id> (or memory <id> with --memory);
+                        --label adds a short tag such as verified or disputed
+  list [<id>] [--memory] [--json]
+                        Notes on one fact/memory, or the most recent notes
+  search <query> [--limit N] [--json]
+                        Notes whose text or label contains every word of query
+  remove <note_id>      Delete a note
+
+Example:
+  cortex note add 1234 "verified manually 2026-02" --label verified`
+}
+
+func runNote(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(noteUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "add":
+		return runNoteAdd(args[1:])
+	case "list", "ls":
+		return runNoteList(args[1:])
+	case "search":
+		return runNoteSearch(args[1:])
+	case "remove", "rm":
+		return runNoteRemove(args[1:])
+	default:
+		return fmt.Errorf("unknown note subcommand: %s", args[0])
+	}
+}
+
+func openNoteStore() (*store.SQLiteStore, func(), error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening store: %w", err)
+	}
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, nil, fmt.Errorf("notes require SQLiteStore")
+	}
+	return sqlStore, func() { s.Close() }, nil
+}
+
+func runNoteAdd(args []string) error {
+	targetType := store.AnnotationTargetFact
+	label, agent := "", ""
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--memory":
+			targetType = store.AnnotationTargetMemory
+		case args[i] == "--label" && i+1 < len(args):
+			i++
+			label = args[i]
+		case strings.HasPrefix(args[i], "--label="):
+			label = strings.TrimPrefix(args[i], "--label=")
+		case args[i] == "--agent" && i+1 < len(args):
+			i++
+			agent = args[i]
+		case strings.HasPrefix(args[i], "--agent="):
+			agent = strings.TrimPrefix(args[i], "--agent=")
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+	if len(positional) < 2 {
+		return fmt.Errorf("usage: cortex note add <id> \"<text>\" [--memory] [--label <label>] [--agent <id>]")
+	}
+	targetID, err := strconv.ParseInt(positional[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s id %q", targetType, positional[0])
+	}
+
+	s, closeStore, err := openNoteStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	id, err := s.AddAnnotation(context.Background(), &store.Annotation{
+		TargetType: targetType,
+		TargetID:   targetID,
+		Body:       strings.Join(positional[1:], " "),
+		Label:      label,
+		Author:     agent,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added note #%d to %s %d\n", id, targetType, targetID)
+	return nil
+}
+
+func runNoteList(args []string) error {
+	targetType := store.AnnotationTargetFact
+	jsonOutput := false
+	idArg := ""
+	for _, arg := range args {
+		switch {
+		case arg == "--memory":
+			targetType = store.AnnotationTargetMemory
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case idArg == "":
+			idArg = arg
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	s, closeStore, err := openNoteStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	var notes []store.Annotation
+	if idArg == "" {
+		notes, err = s.RecentAnnotations(ctx, 50)
+	} else {
+		targetID, parseErr := strconv.ParseInt(idArg, 10, 64)
+		if parseErr != nil {
+			return fmt.Errorf("invalid %s id %q", targetType, idArg)
+		}
+		notes, err = s.ListAnnotations(ctx, targetType, targetID)
+	}
+	if err != nil {
+		return err
+	}
+	return printAnnotations(notes, jsonOutput, "No notes.")
+}
+
+func runNoteSearch(args []string) error {
+	jsonOutput := false
+	limit := 50
+	var terms []string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--json":
+			jsonOutput = true
+		case args[i] == "--limit" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("--limit must be a positive integer")
+			}
+			limit = n
+		case strings.HasPrefix(args[i], "--limit="):
+			n, err := strconv.Atoi(strings.TrimPrefix(args[i], "--limit="))
+			if err != nil || n <= 0 {
+				return fmt.Errorf("--limit must be a positive integer")
+			}
+			limit = n
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		default:
+			terms = append(terms, args[i])
+		}
+	}
+	if len(terms) == 0 {
+		return fmt.Errorf("usage: cortex note search <query> [--limit N] [--json]")
+	}
+
+	s, closeStore, err := openNoteStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	notes, err := s.SearchAnnotations(context.Background(), strings.Join(terms, " "), limit)
+	if err != nil {
+		return err
+	}
+	return printAnnotations(notes, jsonOutput, "No matching notes.")
+}
+
+func runNoteRemove(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: cortex note remove <note_id>")
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid note id %q", args[0])
+	}
+	s, closeStore, err := openNoteStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	if err := s.DeleteAnnotation(context.Background(), id); err != nil {
+		return err
+	}
+	fmt.Printf("Removed note #%d\n", id)
+	return nil
+}
+
+func printAnnotations(notes []store.Annotation, jsonOutput bool, empty string) error {
+	if jsonOutput {
+		if notes == nil {
+			notes = []store.Annotation{}
+		}
+		data, _ := json.MarshalIndent(notes, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(notes) == 0 {
+		fmt.Println(empty)
+		return nil
+	}
+	for _, n := range notes {
+		fmt.Printf("#%-5d %s %-6d %s\n", n.ID, n.TargetType, n.TargetID, formatAnnotationLine(n))
+	}
+	return nil
+}
+
+// formatAnnotationLine renders a note as `[label] body — author, date`.
+func formatAnnotationLine(n store.Annotation) string {
+	var b strings.Builder
+	if n.Label != "" {
+		fmt.Fprintf(&b, "[%s] ", n.Label)
+	}
+	b.WriteString(n.Body)
+	meta := n.CreatedAt.Format("2006-01-02")
+	if n.Author != "" {
+		meta = n.Author + ", " + meta
+	}
+	fmt.Fprintf(&b, " — %s", meta)
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunNote_AddListSearchRemove(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, closeStore, err := openNoteStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "Q lives in Philadelphia"})
+	factID, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "Q", Predicate: "lives_in", Object: "Philadelphia", FactType: "location"})
+	closeStore()
+
+	factArg := fmt.Sprint(factID)
+	out := captureStdout(func() {
+		if err := runNote([]string{"add", factArg, "verified manually 2026-02", "--label", "verified", "--agent", "q"}); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+		if err := runNote([]string{"add", fmt.Sprint(memID), "--memory", "from the old wiki"}); err != nil {
+			t.Fatalf("add --memory: %v", err)
+		}
+	})
+	if !strings.Contains(out, fmt.Sprintf("Added note #1 to fact %d", factID)) || !strings.Contains(out, fmt.Sprintf("Added note #2 to memory %d", memID)) {
+		t.Fatalf("unexpected add output: %q", out)
+	}
+	if err := runNote([]string{"add", "9999", "nope"}); err == nil || !strings.Contains(err.Error(), "fact 9999 not found") {
+		t.Fatalf("expected missing fact error, got %v", err)
+	}
+
+	out = captureStdout(func() {
+		if err := runNote([]string{"list", factArg}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	if !strings.Contains(out, "[verified] verified manually 2026-02 — q,") || strings.Contains(out, "old wiki") {
+		t.Fatalf("unexpected list output:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runNote([]string{"search", "wiki", "--json"}); err != nil {
+			t.Fatalf("search: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"target_type": "memory"`) || strings.Contains(out, "verified manually") {
+		t.Fatalf("unexpected search output:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runFactHistory([]string{factArg}); err != nil {
+			t.Fatalf("fact-history: %v", err)
+		}
+	})
+	if !strings.Contains(out, "📝 Notes:") || !strings.Contains(out, "verified manually 2026-02") {
+		t.Fatalf("fact-history should show notes:\n%s", out)
+	}
+
+	captureStdout(func() {
+		if err := runNote([]string{"remove", "1"}); err != nil {
+			t.Fatalf("remove: %v", err)
+		}
+	})
+	if err := runNote([]string{"remove", "1"}); err == nil {
+		t.Fatal("expected error removing a missing note")
+	}
+}
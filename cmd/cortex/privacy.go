@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/observe"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func projectsPrivacyUsageText() string {
+	return `Usage: cortex projects privacy <list|set> [flags]
+
+A privacy zone keeps one project's memories and facts on this machine:
+
+  no-export        left out of cortex export (all formats) and alert webhooks
+  no-mcp           hidden from MCP tools and resources (search, answer,
+                   context, reason, facts, graph, recent)
+  local-llm-only   never sent to a remote model; import extraction falls back
+                   to rules, enrichment/classify/summarize/conflict resolution
+                   skip it, and reason/answer leave it out unless the model
+                   runs on ollama
+
+Subcommands:
+  list [--json]                 Projects with at least one restriction
+  set <project> [flags]         Replace a project's restrictions
+      --no-export
+      --no-mcp
+      --local-llm-only
+      --clear                   Remove every restriction`
+}
+
+func runProjectsPrivacy(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(projectsPrivacyUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "list":
+		return runProjectsPrivacyList(args[1:])
+	case "set":
+		return runProjectsPrivacySet(args[1:])
+	default:
+		return fmt.Errorf("unknown projects privacy subcommand: %s", args[0])
+	}
+}
+
+func runProjectsPrivacyList(args []string) error {
+	jsonOutput, err := parseSourcesJSONFlag(args)
+	if err != nil {
+		return err
+	}
+	ss, err := openPrivacyStore()
+	if err != nil {
+		return err
+	}
+	defer ss.Close()
+
+	zones, err := ss.ListProjectPrivacy(context.Background())
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		if zones == nil {
+			zones = []store.ProjectPrivacy{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(zones)
+	}
+	if len(zones) == 0 {
+		fmt.Println("No privacy zones. Use `cortex projects privacy set <project> --no-export|--no-mcp|--local-llm-only`.")
+		return nil
+	}
+	fmt.Printf("%-20s  %s\n", "PROJECT", "RESTRICTIONS")
+	fmt.Println(strings.Repeat("─", 56))
+	for _, z := range zones {
+		fmt.Printf("%-20s  %s\n", z.Project, strings.Join(z.Restrictions(), ", "))
+	}
+	return nil
+}
+
+func runProjectsPrivacySet(args []string) error {
+	var zone store.ProjectPrivacy
+	clear := false
+	for _, arg := range args {
+		switch {
+		case arg == "--no-export":
+			zone.NoExport = true
+		case arg == "--no-mcp":
+			zone.NoMCP = true
+		case arg == "--local-llm-only":
+			zone.LocalLLMOnly = true
+		case arg == "--clear":
+			clear = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case zone.Project == "":
+			zone.Project = strings.TrimSpace(arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if zone.Project == "" {
+		return fmt.Errorf("usage: cortex projects privacy set <project> [--no-export] [--no-mcp] [--local-llm-only] [--clear]")
+	}
+	restricted := len(zone.Restrictions()) > 0
+	switch {
+	case clear && restricted:
+		return fmt.Errorf("--clear cannot be combined with restriction flags")
+	case !clear && !restricted:
+		return fmt.Errorf("give at least one of --no-export, --no-mcp, --local-llm-only (or --clear)")
+	}
+
+	ss, err := openPrivacyStore()
+	if err != nil {
+		return err
+	}
+	defer ss.Close()
+	if err := ss.SetProjectPrivacy(context.Background(), zone); err != nil {
+		return err
+	}
+	if clear {
+		fmt.Printf("Cleared privacy zone for %s\n", zone.Project)
+		return nil
+	}
+	fmt.Printf("Privacy zone for %s: %s\n", zone.Project, strings.Join(zone.Restrictions(), ", "))
+	return nil
+}
+
+func openPrivacyStore() (*store.SQLiteStore, error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("opening store: %w", err)
+	}
+	ss, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, fmt.Errorf("projects privacy requires SQLiteStore")
+	}
+	return ss, nil
+}
+
+// exportableFacts drops facts from no-export projects, noting the count on
+// stderr.
+func exportableFacts(ctx context.Context, s store.Store, facts []*store.Fact) ([]*store.Fact, error) {
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return facts, nil
+	}
+	kept, held, err := sqlStore.FilterFactsByPrivacy(ctx, facts, store.PrivacyNoExport)
+	if err != nil {
+		return nil, fmt.Errorf("checking privacy zones: %w", err)
+	}
+	if held > 0 {
+		fmt.Fprintf(os.Stderr, "Left out %d facts from no-export projects\n", held)
+	}
+	return kept, nil
+}
+
+// heldFromRemoteLLM returns the IDs of facts whose memory's project is
+// local-llm-only; callers leave them out of prompts to remote models.
+func heldFromRemoteLLM(ctx context.Context, s store.Store, facts []*store.Fact) (map[int64]bool, error) {
+	held := map[int64]bool{}
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok || len(facts) == 0 {
+		return held, nil
+	}
+	allowed, _, err := sqlStore.FilterFactsByPrivacy(ctx, facts, store.PrivacyLocalLLMOnly)
+	if err != nil {
+		return nil, err
+	}
+	keep := make(map[int64]bool, len(allowed))
+	for _, f := range allowed {
+		keep[f.ID] = true
+	}
+	for _, f := range facts {
+		if !keep[f.ID] {
+			held[f.ID] = true
+		}
+	}
+	return held, nil
+}
+
+// remoteLLMFacts drops facts from local-llm-only projects before they are
+// sent to a remote model, and reports how many were held back.
+func remoteLLMFacts(ctx context.Context, s store.Store, facts []*store.Fact) ([]*store.Fact, int, error) {
+	held, err := heldFromRemoteLLM(ctx, s, facts)
+	if err != nil || len(held) == 0 {
+		return facts, 0, err
+	}
+	kept := make([]*store.Fact, 0, len(facts)-len(held))
+	for _, f := range facts {
+		if !held[f.ID] {
+			kept = append(kept, f)
+		}
+	}
+	return kept, len(held), nil
+}
+
+// remoteLLMConflicts drops conflicts where either fact is local-llm-only.
+func remoteLLMConflicts(ctx context.Context, s store.Store, conflicts []observe.Conflict) ([]observe.Conflict, int, error) {
+	facts := make([]*store.Fact, 0, 2*len(conflicts))
+	for i := range conflicts {
+		facts = append(facts, &conflicts[i].Fact1, &conflicts[i].Fact2)
+	}
+	held, err := heldFromRemoteLLM(ctx, s, facts)
+	if err != nil || len(held) == 0 {
+		return conflicts, 0, err
+	}
+	kept := make([]observe.Conflict, 0, len(conflicts))
+	for _, c := range conflicts {
+		if !held[c.Fact1.ID] && !held[c.Fact2.ID] {
+			kept = append(kept, c)
+		}
+	}
+	return kept, len(conflicts) - len(kept), nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunProjectsPrivacy_SetListExport(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	open, _ := s.AddMemory(ctx, &store.Memory{Content: "shared roadmap notes", SourceFile: "work.md", Project: "work", ContentHash: "a"})
+	secret, _ := s.AddMemory(ctx, &store.Memory{Content: "client contract terms", SourceFile: "client.md", Project: "client-x", ContentHash: "b"})
+	s.AddFact(ctx, &store.Fact{MemoryID: open, Subject: "roadmap", Predicate: "owner", Object: "alice", FactType: "kv", Confidence: 0.9})
+	s.AddFact(ctx, &store.Fact{MemoryID: secret, Subject: "contract", Predicate: "value", Object: "$2M", FactType: "kv", Confidence: 0.9})
+	s.Close()
+
+	for _, args := range [][]string{
+		{"set"},
+		{"set", "client-x"},
+		{"set", "client-x", "--clear", "--no-mcp"},
+		{"set", "client-x", "--bogus"},
+		{"set", "client-x", "other", "--no-mcp"},
+	} {
+		if err := runProjectsPrivacy(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+
+	out := captureStdout(func() {
+		if err := runProjectsPrivacy([]string{"set", "client-x", "--no-export", "--local-llm-only"}); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	})
+	t.Cleanup(func() { runProjectsPrivacy([]string{"set", "client-x", "--clear"}) })
+	if !strings.Contains(out, "no-export, local-llm-only") {
+		t.Fatalf("unexpected set output: %q", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runProjectsPrivacy([]string{"list"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	if !strings.Contains(out, "client-x") || strings.Contains(out, "work ") {
+		t.Fatalf("unexpected list output: %q", out)
+	}
+
+	exportPath := filepath.Join(home, "export.json")
+	if err := runExport([]string{"--format", "json", "--output", exportPath}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	data, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "shared roadmap notes") || strings.Contains(string(data), "client contract terms") {
+		t.Fatalf("export should leave out no-export memories:\n%s", data)
+	}
+
+	factsPath := filepath.Join(home, "facts.json")
+	if err := runExport([]string{"--facts", "--format", "json", "--output", factsPath}); err != nil {
+		t.Fatalf("export facts: %v", err)
+	}
+	data, _ = os.ReadFile(factsPath)
+	if !strings.Contains(string(data), "roadmap") || strings.Contains(string(data), "$2M") {
+		t.Fatalf("fact export should leave out no-export facts:\n%s", data)
+	}
+
+	out = captureStdout(func() {
+		if err := runProjectsPrivacy([]string{"set", "client-x", "--clear"}); err != nil {
+			t.Fatalf("clear: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Cleared") || !store.ProjectAllows("client-x", store.PrivacyNoExport) {
+		t.Fatalf("clear did not remove the zone: %q", out)
+	}
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/extract"
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+func promptsUsageText() string {
+	return `Usage: cortex prompts <list|show|init|test> [flags]
+
+Inspect and override the prompts used by enrich, classify, resolve,
+summarize, and predicates. Overrides are Go text/template files in ~/.cortex/prompts/:
+
+  <name>.system.tmpl    replaces the system prompt
+  <name>.user.tmpl      replaces the user message
+
+Either file may be omitted; the built-in is used for the missing half.
+
+Subcommands:
+  list [--json]                         List prompts and whether they are overridden
+  show <name> [--builtin]               Print the effective templates and their variables
+  init [name...] [--force]              Write the built-in templates to ~/.cortex/prompts/ as a starting point
+  test <name> --input <file> [--llm provider/model] [--json]
+                                        Render a prompt from a test input; with --llm, send it
+                                        and check that the response parses
+
+Test input is JSON with the template's fields (see 'show'); enrich also
+accepts a plain-text file, used as the chunk.
+
+Template functions: truncate N s, date t, printf, upper, lower, join.`
+}
+
+func runPrompts(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(promptsUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "list":
+		return runPromptsList(args[1:])
+	case "show":
+		return runPromptsShow(args[1:])
+	case "init":
+		return runPromptsInit(args[1:])
+	case "test":
+		return runPromptsTest(args[1:])
+	default:
+		return fmt.Errorf("unknown prompts subcommand: %s", args[0])
+	}
+}
+
+type promptListEntry struct {
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	SystemOverride string `json:"system_override,omitempty"`
+	UserOverride   string `json:"user_override,omitempty"`
+}
+
+func runPromptsList(args []string) error {
+	jsonOut := false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOut = true
+		default:
+			return fmt.Errorf("unknown flag: %s", arg)
+		}
+	}
+
+	var entries []promptListEntry
+	for _, spec := range extract.PromptSpecs() {
+		systemPath, userPath, err := extract.PromptOverridePaths(spec.Name)
+		if err != nil {
+			return err
+		}
+		entry := promptListEntry{Name: spec.Name, Description: spec.Description}
+		if promptFileExists(systemPath) {
+			entry.SystemOverride = systemPath
+		}
+		if promptFileExists(userPath) {
+			entry.UserOverride = userPath
+		}
+		entries = append(entries, entry)
+	}
+
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	dir, _ := extract.PromptDir()
+	fmt.Printf("Prompt overrides: %s\n\n", dir)
+	for _, e := range entries {
+		status := "built-in"
+		switch {
+		case e.SystemOverride != "" && e.UserOverride != "":
+			status = "overridden (system + user)"
+		case e.SystemOverride != "":
+			status = "overridden (system)"
+		case e.UserOverride != "":
+			status = "overridden (user)"
+		}
+		fmt.Printf("  %-10s %-28s %s\n", e.Name, status, e.Description)
+	}
+	return nil
+}
+
+func runPromptsShow(args []string) error {
+	name, builtinOnly := "", false
+	for _, arg := range args {
+		switch {
+		case arg == "--builtin":
+			builtinOnly = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case name == "":
+			name = arg
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if name == "" {
+		return fmt.Errorf("usage: cortex prompts show <name> [--builtin]")
+	}
+	spec, err := extract.LookupPromptSpec(name)
+	if err != nil {
+		return err
+	}
+	systemPath, userPath, err := extract.PromptOverridePaths(spec.Name)
+	if err != nil {
+		return err
+	}
+
+	systemText, systemSource := spec.System, "built-in"
+	userText, userSource := spec.User, "built-in"
+	if !builtinOnly {
+		if raw, err := os.ReadFile(systemPath); err == nil {
+			systemText, systemSource = string(raw), systemPath
+		}
+		if raw, err := os.ReadFile(userPath); err == nil {
+			userText, userSource = string(raw), userPath
+		}
+	}
+
+	fmt.Printf("# %s — %s\n\n", spec.Name, spec.Description)
+	fmt.Println("Variables:")
+	for _, v := range spec.Variables {
+		fmt.Printf("  %-18s %s\n", v.Name, v.Description)
+	}
+	fmt.Printf("\n--- system (%s) ---\n%s\n", systemSource, systemText)
+	fmt.Printf("\n--- user (%s) ---\n%s\n", userSource, userText)
+	return nil
+}
+
+func runPromptsInit(args []string) error {
+	force := false
+	var names []string
+	for _, arg := range args {
+		switch {
+		case arg == "--force":
+			force = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			names = append(names, arg)
+		}
+	}
+
+	var specs []extract.PromptSpec
+	if len(names) == 0 {
+		specs = extract.PromptSpecs()
+	}
+	for _, name := range names {
+		spec, err := extract.LookupPromptSpec(name)
+		if err != nil {
+			return err
+		}
+		specs = append(specs, spec)
+	}
+
+	dir, err := extract.PromptDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating prompts dir: %w", err)
+	}
+
+	for _, spec := range specs {
+		systemPath, userPath, err := extract.PromptOverridePaths(spec.Name)
+		if err != nil {
+			return err
+		}
+		for _, f := range []struct{ path, text string }{{systemPath, spec.System}, {userPath, spec.User}} {
+			if promptFileExists(f.path) && !force {
+				fmt.Printf("  skip   %s (exists; use --force to overwrite)\n", f.path)
+				continue
+			}
+			if err := os.WriteFile(f.path, []byte(f.text), 0o644); err != nil {
+				return fmt.Errorf("writing %s: %w", f.path, err)
+			}
+			fmt.Printf("  wrote  %s\n", f.path)
+		}
+	}
+	return nil
+}
+
+type promptTestResult struct {
+	Prompt    *extract.RenderedPrompt `json:"prompt"`
+	Model     string                  `json:"model,omitempty"`
+	Response  string                  `json:"response,omitempty"`
+	LatencyMS int64                   `json:"latency_ms,omitempty"`
+	Parsed    int                     `json:"parsed_items"`
+	ParseErr  string                  `json:"parse_error,omitempty"`
+}
+
+func runPromptsTest(args []string) error {
+	name, inputPath, llmFlag, jsonOut := "", "", "", false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--input" && i+1 < len(args):
+			i++
+			inputPath = args[i]
+		case strings.HasPrefix(args[i], "--input="):
+			inputPath = strings.TrimPrefix(args[i], "--input=")
+		case args[i] == "--llm" && i+1 < len(args):
+			i++
+			llmFlag = args[i]
+		case strings.HasPrefix(args[i], "--llm="):
+			llmFlag = strings.TrimPrefix(args[i], "--llm=")
+		case args[i] == "--json":
+			jsonOut = true
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		case name == "":
+			name = args[i]
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	if name == "" || inputPath == "" {
+		return fmt.Errorf("usage: cortex prompts test <name> --input <file> [--llm provider/model] [--json]")
+	}
+
+	spec, err := extract.LookupPromptSpec(name)
+	if err != nil {
+		return err
+	}
+	raw, err := os.ReadFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("reading input: %w", err)
+	}
+	data, err := extract.DecodePromptInput(spec.Name, raw)
+	if err != nil {
+		return err
+	}
+	rendered, err := extract.RenderPrompt(spec.Name, data)
+	if err != nil {
+		return err
+	}
+
+	result := promptTestResult{Prompt: rendered}
+	if llmFlag != "" {
+		provider, err := tryCreateProvider(llmFlag)
+		if err != nil {
+			return fmt.Errorf("LLM provider: %w", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+		defer cancel()
+
+		start := time.Now()
+		response, err := provider.Complete(ctx, rendered.User, llm.CompletionOpts{
+			Temperature: 0.1,
+			MaxTokens:   spec.MaxTokens,
+			System:      rendered.System,
+			Task:        spec.Task,
+		})
+		if err != nil {
+			return fmt.Errorf("LLM %s call: %w", spec.Name, err)
+		}
+		result.Model = provider.Name()
+		result.Response = response
+		result.LatencyMS = time.Since(start).Milliseconds()
+		result.Parsed, err = extract.CheckPromptResponse(spec.Name, response)
+		if err != nil {
+			result.ParseErr = err.Error()
+		}
+	}
+
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	fmt.Printf("--- system (%s) ---\n%s\n", rendered.SystemSource, rendered.System)
+	fmt.Printf("\n--- user (%s) ---\n%s\n", rendered.UserSource, rendered.User)
+	if llmFlag == "" {
+		return nil
+	}
+	fmt.Printf("\n--- response (%s, %s) ---\n%s\n\n", result.Model, formatTimingMS(result.LatencyMS), result.Response)
+	if result.ParseErr != "" {
+		fmt.Printf("✗ response does not parse: %s\n", result.ParseErr)
+	} else {
+		fmt.Printf("✓ response parses: %d %s\n", result.Parsed, pluralize("item", "items", result.Parsed))
+	}
+	return nil
+}
+
+func promptFileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
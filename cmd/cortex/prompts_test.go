@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func usePromptsTestHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	return filepath.Join(home, ".cortex", "prompts")
+}
+
+func TestRunPromptsInit_WritesBuiltinsAndSkipsExisting(t *testing.T) {
+	dir := usePromptsTestHome(t)
+
+	out := captureStdout(func() {
+		if err := runPrompts([]string{"init", "classify"}); err != nil {
+			t.Fatalf("init: %v", err)
+		}
+	})
+	if strings.Count(out, "wrote") != 2 {
+		t.Fatalf("expected system + user written, got:\n%s", out)
+	}
+	userPath := filepath.Join(dir, "classify.user.tmpl")
+	if _, err := os.Stat(userPath); err != nil {
+		t.Fatalf("expected %s: %v", userPath, err)
+	}
+
+	out = captureStdout(func() {
+		if err := runPrompts([]string{"init", "classify"}); err != nil {
+			t.Fatalf("init again: %v", err)
+		}
+	})
+	if strings.Count(out, "skip") != 2 {
+		t.Fatalf("expected existing files skipped, got:\n%s", out)
+	}
+}
+
+func TestRunPromptsTest_RendersOverride(t *testing.T) {
+	dir := usePromptsTestHome(t)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "summarize.user.tmpl"),
+		[]byte(`Desk {{.ClusterName}}:{{range .Facts}} #{{.ID}}{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	input := filepath.Join(t.TempDir(), "in.json")
+	if err := os.WriteFile(input, []byte(`{"cluster_name":"Trading","facts":[{"id":4,"predicate":"p","object":"o"},{"id":9}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(func() {
+		if err := runPrompts([]string{"test", "summarize", "--input", input, "--json"}); err != nil {
+			t.Fatalf("test: %v", err)
+		}
+	})
+	var result promptTestResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Prompt.User != "Desk Trading: #4 #9" {
+		t.Errorf("user = %q", result.Prompt.User)
+	}
+	if result.Prompt.SystemSource != "built-in" {
+		t.Errorf("system source = %q, want built-in", result.Prompt.SystemSource)
+	}
+}
+
+func TestRunPromptsList_ShowsOverrideStatus(t *testing.T) {
+	dir := usePromptsTestHome(t)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "enrich.system.tmpl"), []byte("custom"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(func() {
+		if err := runPrompts([]string{"list"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	if !strings.Contains(out, "overridden (system)") {
+		t.Errorf("expected enrich to show as overridden, got:\n%s", out)
+	}
+}
+
+func TestRunPrompts_Errors(t *testing.T) {
+	usePromptsTestHome(t)
+	if err := runPrompts([]string{"show", "nope"}); err == nil {
+		t.Error("expected unknown prompt error")
+	}
+	if err := runPrompts([]string{"test", "classify"}); err == nil {
+		t.Error("expected usage error without --input")
+	}
+	if err := runPrompts([]string{"bogus"}); err == nil {
+		t.Error("expected unknown subcommand error")
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/circuit"
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/embed"
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+// providerProbeTimeout bounds each doctor health probe.
+const providerProbeTimeout = 8 * time.Second
+
+// providerFailoverChecks health-probes every provider in the configured LLM
+// and embedding fallback chains (llm.fallback / embed.fallback). Chains that
+// are not configured produce no checks.
+func providerFailoverChecks(resolved cfgresolver.ResolvedConfig, embedCfg *embed.EmbedConfig) []doctorCheck {
+	var checks []doctorCheck
+	if len(resolved.LLMFallback) > 0 {
+		primary := resolved.EffectiveLLMModel("default", "").Value
+		cfg, err := llm.ParseLLMFlag(primary)
+		var chain []llm.Provider
+		if err == nil {
+			var p llm.Provider
+			if p, err = llm.NewProvider(cfg); err == nil {
+				chain = []llm.Provider{p}
+				if f, ok := p.(*llm.Failover); ok {
+					chain = f.Members()
+				}
+			}
+		}
+		if err != nil {
+			checks = append(checks, doctorCheck{
+				Name:    "llm_failover",
+				Status:  "fail",
+				Details: fmt.Sprintf("no usable LLM provider in chain: %v", err),
+				Hint:    "Set an API key for the primary provider or one of llm.fallback.",
+			})
+		} else {
+			checks = append(checks, probeLLMChain(chain, 1+len(resolved.LLMFallback)))
+		}
+	}
+
+	if embedCfg != nil && len(embedCfg.Fallback) > 0 {
+		e, err := embed.NewClient(embedCfg)
+		if err != nil {
+			checks = append(checks, doctorCheck{
+				Name:    "embed_failover",
+				Status:  "fail",
+				Details: fmt.Sprintf("no usable embedder in chain: %v", err),
+				Hint:    "Check embed.provider and embed.fallback.",
+			})
+		} else {
+			name := resolvedEmbedRef(embedCfg)
+			if embedCfg.Validate() != nil {
+				name = "first usable fallback"
+			}
+			members := []embed.FailoverMember{{Name: name, Embedder: e}}
+			if f, ok := e.(*embed.FailoverEmbedder); ok {
+				members = f.Members()
+			}
+			checks = append(checks, probeEmbedChain(members, 1+len(embedCfg.Fallback)))
+		}
+	}
+	return checks
+}
+
+// probeLLMChain probes each provider in order. configured is the number of
+// providers the user listed; ones that could not be built are reported as
+// skipped.
+func probeLLMChain(chain []llm.Provider, configured int) doctorCheck {
+	results := make([]chainProbe, 0, len(chain))
+	for _, p := range chain {
+		ctx, cancel := context.WithTimeout(context.Background(), providerProbeTimeout)
+		err := llm.Probe(ctx, p)
+		cancel()
+		if errors.Is(err, llm.ErrNoHealthCheck) {
+			results = append(results, chainProbe{name: p.Name(), note: "not probed"})
+			continue
+		}
+		results = append(results, chainProbe{name: p.Name(), err: err, breaker: llm.BreakerFor(p).Status()})
+	}
+	return chainDoctorCheck("llm_failover", results, configured)
+}
+
+func probeEmbedChain(members []embed.FailoverMember, configured int) doctorCheck {
+	results := make([]chainProbe, 0, len(members))
+	for _, m := range members {
+		ctx, cancel := context.WithTimeout(context.Background(), providerProbeTimeout)
+		err := embed.Probe(ctx, m)
+		cancel()
+		results = append(results, chainProbe{name: m.Name, err: err, breaker: embed.BreakerFor(m.Name).Status()})
+	}
+	return chainDoctorCheck("embed_failover", results, configured)
+}
+
+type chainProbe struct {
+	name    string
+	err     error
+	note    string
+	breaker circuit.Status
+}
+
+// chainDoctorCheck passes when every provider answered, warns when some
+// did, and fails when none did.
+func chainDoctorCheck(name string, results []chainProbe, configured int) doctorCheck {
+	parts := make([]string, 0, len(results))
+	healthy := 0
+	for _, r := range results {
+		switch {
+		case r.note != "":
+			healthy++
+			parts = append(parts, fmt.Sprintf("%s (%s)", r.name, r.note))
+		case r.err == nil:
+			healthy++
+			parts = append(parts, r.name+" (ok)")
+		default:
+			parts = append(parts, fmt.Sprintf("%s (down: %s; breaker %s)", r.name, truncateString(r.err.Error(), 80), r.breaker.State))
+		}
+	}
+	check := doctorCheck{Name: name, Status: "pass", Details: strings.Join(parts, " → ")}
+	if skipped := configured - len(results); skipped > 0 {
+		check.Details += fmt.Sprintf(" · %d configured provider(s) skipped (missing API key, invalid or duplicate spec)", skipped)
+	}
+	switch {
+	case healthy == 0:
+		check.Status = "fail"
+		check.Hint = "No provider in the chain is reachable; LLM/embedding work will fail until one recovers."
+	case healthy < len(results):
+		check.Status = "warn"
+		check.Hint = "Calls will fail over past the providers that are down."
+	case configured > len(results):
+		check.Status = "warn"
+		check.Hint = "Set the missing API keys or fix the fallback specs."
+	}
+	return check
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/circuit"
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+type probeStubProvider struct {
+	name string
+	err  error
+}
+
+func (p *probeStubProvider) Complete(context.Context, string, llm.CompletionOpts) (string, error) {
+	return "", nil
+}
+
+func (p *probeStubProvider) Name() string { return p.name }
+
+func (p *probeStubProvider) HealthCheck(context.Context) error { return p.err }
+
+func TestProbeLLMChain(t *testing.T) {
+	circuit.Reset()
+	t.Cleanup(circuit.Reset)
+
+	up := &probeStubProvider{name: "openrouter/openai/gpt-4o-mini"}
+	down := &probeStubProvider{name: "google/gemini-2.5-flash", err: errors.New("google API error (status 403): key invalid")}
+
+	check := probeLLMChain([]llm.Provider{up, down}, 2)
+	if check.Status != "warn" {
+		t.Fatalf("expected warn with one provider down, got %+v", check)
+	}
+	if !strings.Contains(check.Details, "openrouter/openai/gpt-4o-mini (ok) → google/gemini-2.5-flash (down: ") {
+		t.Fatalf("unexpected details: %s", check.Details)
+	}
+
+	if check := probeLLMChain([]llm.Provider{up}, 3); check.Status != "warn" || !strings.Contains(check.Details, "2 configured provider(s) skipped") {
+		t.Fatalf("expected skipped providers to warn, got %+v", check)
+	}
+	if check := probeLLMChain([]llm.Provider{up}, 1); check.Status != "pass" {
+		t.Fatalf("expected pass, got %+v", check)
+	}
+	if check := probeLLMChain([]llm.Provider{down}, 1); check.Status != "fail" {
+		t.Fatalf("expected fail with every provider down, got %+v", check)
+	}
+}
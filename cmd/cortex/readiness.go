@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// hnswStaleSec matches the interactive search path: persisted indexes older
+// than an hour are rebuilt instead of mapped.
+const hnswStaleSec = 3600
+
+// warmSearchHNSW starts a background HNSW warm-up for long-running servers so
+// they can answer BM25 queries immediately. Progress is logged to stderr.
+func warmSearchHNSW(engine *search.Engine) {
+	engine.SetHNSWParams(resolveHNSWParams())
+	done := engine.WarmHNSW(context.Background(), getHNSWPath(), hnswStaleSec)
+	go func() {
+		<-done
+		st := engine.HNSWStatus()
+		switch st.State {
+		case search.HNSWReady:
+			how := "loaded"
+			if st.Mapped {
+				how = "mapped"
+			}
+			fmt.Fprintf(os.Stderr, "HNSW index ready: %d vectors %s in %dms\n", st.Vectors, how, st.ElapsedMs)
+		case search.HNSWFailed:
+			fmt.Fprintf(os.Stderr, "warning: HNSW warm-up failed (semantic search stays brute-force): %s\n", st.Error)
+		}
+	}()
+}
+
+// readinessReport is the /readyz payload.
+type readinessReport struct {
+	Ready   bool              `json:"ready"`
+	BM25    string            `json:"bm25"`
+	Vectors search.HNSWStatus `json:"vectors"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// readinessHandler serves /readyz. The server is ready once the store answers
+// (BM25 works from the first request); with ?require=vectors it also waits
+// for the HNSW warm-up to settle. Not ready responds 503.
+func readinessHandler(s store.Store, engine *search.Engine) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := readinessReport{BM25: "ready", Vectors: search.HNSWStatus{State: search.HNSWCold}}
+		if engine != nil {
+			report.Vectors = engine.HNSWStatus()
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+		defer cancel()
+		if err := pingStore(ctx, s); err != nil {
+			report.BM25 = "unavailable"
+			report.Error = err.Error()
+		}
+
+		report.Ready = report.BM25 == "ready"
+		if r.URL.Query().Get("require") == "vectors" && report.Vectors.State == search.HNSWWarming {
+			report.Ready = false
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if !report.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+func pingStore(ctx context.Context, s store.Store) error {
+	if sqlStore, ok := s.(*store.SQLiteStore); ok {
+		return sqlStore.GetDB().PingContext(ctx)
+	}
+	return nil
+}
+
+// statusHandler serves /status: counts, last import and sync, pending
+// conflicts, and the alert backlog, plus a one-line summary that plugins can
+// put in their own heartbeats. A store error responds 503.
+func statusHandler(s store.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		sqlStore, ok := s.(*store.SQLiteStore)
+		if !ok {
+			w.WriteHeader(http.StatusNotImplemented)
+			json.NewEncoder(w).Encode(map[string]string{"error": "status requires SQLiteStore"})
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+		defer cancel()
+		status, err := sqlStore.Status(ctx)
+		if err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(status)
+	})
+}
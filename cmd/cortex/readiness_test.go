@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	s, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer s.Close()
+
+	probe := func(h http.Handler, url string) (int, readinessReport) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		var report readinessReport
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatalf("decode /readyz: %v\n%s", err, rec.Body.String())
+		}
+		return rec.Code, report
+	}
+
+	// Graph server: no engine, ready as soon as the store answers.
+	code, report := probe(readinessHandler(s, nil), "/readyz?require=vectors")
+	if code != http.StatusOK || !report.Ready || report.BM25 != "ready" || report.Vectors.State != search.HNSWCold {
+		t.Fatalf("graph readiness = %d %+v", code, report)
+	}
+
+	// Engine whose warm-up finished on an empty store: vectors settled.
+	engine := search.NewEngine(s)
+	t.Setenv("HOME", t.TempDir())
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+	<-engine.WarmHNSW(t.Context(), getHNSWPath(), hnswStaleSec)
+	code, report = probe(readinessHandler(s, engine), "/readyz?require=vectors")
+	if code != http.StatusOK || report.Vectors.State != search.HNSWEmpty {
+		t.Fatalf("engine readiness = %d %+v", code, report)
+	}
+
+	// Closed store: BM25 unavailable → 503.
+	s.Close()
+	code, report = probe(readinessHandler(s, nil), "/readyz")
+	if code != http.StatusServiceUnavailable || report.Ready || report.BM25 != "unavailable" {
+		t.Fatalf("closed-store readiness = %d %+v", code, report)
+	}
+}
+
+func TestStatusHandler(t *testing.T) {
+	s, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer s.Close()
+	if _, err := s.AddMemory(t.Context(), &store.Memory{Content: "deploys use fly.io", SourceFile: "/notes/ops.md"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	statusHandler(s).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	var status store.StoreStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("decode /status: %v\n%s", err, rec.Body.String())
+	}
+	if rec.Code != http.StatusOK || status.Memories != 1 || status.Summary == "" {
+		t.Fatalf("status = %d %+v", rec.Code, status)
+	}
+
+	// Closed store → 503 with the error.
+	s.Close()
+	rec = httptest.NewRecorder()
+	statusHandler(s).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("closed-store status = %d %s", rec.Code, rec.Body.String())
+	}
+}
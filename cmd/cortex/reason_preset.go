@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/reason"
+	"gopkg.in/yaml.v3"
+)
+
+func reasonPresetUsageText() string {
+	return `Usage: cortex reason preset <install|remove|list|search|publish> [flags]
+
+Share reason presets. Installed presets live in ~/.cortex/presets/, one
+YAML file per preset, and are used like any other: cortex reason --preset <name>.
+
+Subcommands:
+  install <name|url|git|file> [--sha256 <hex>] [--registry <url>] [--force]
+                                Install a preset. A bare name is looked up in the
+                                registry, whose index carries the checksum; URL and
+                                git sources (git+https://host/repo.git#path/preset.yaml)
+                                require --sha256. --force replaces an installed preset
+                                or shadows a builtin.
+  remove <name>                 Delete an installed preset
+  list [--json]                 List builtin, custom, and installed presets
+  search [text] [--registry <url>] [--json]
+                                List the presets a registry offers
+  publish <name> [--out <dir>] [--url <url>] [--author <name>]
+                                Write <name>.yaml (from an existing preset, or a
+                                skeleton) and print its registry index entry
+
+The registry defaults to the community index in the Cortex repo; set
+CORTEX_PRESET_REGISTRY or pass --registry to use another.`
+}
+
+func runReasonPreset(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(reasonPresetUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "install":
+		return runReasonPresetInstall(args[1:])
+	case "remove":
+		return runReasonPresetRemove(args[1:])
+	case "list":
+		return runReasonPresetList(args[1:])
+	case "search":
+		return runReasonPresetSearch(args[1:])
+	case "publish":
+		return runReasonPresetPublish(args[1:])
+	default:
+		return fmt.Errorf("unknown preset subcommand: %s", args[0])
+	}
+}
+
+func presetRegistryURL(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if env := strings.TrimSpace(os.Getenv("CORTEX_PRESET_REGISTRY")); env != "" {
+		return env
+	}
+	return reason.DefaultPresetRegistry
+}
+
+func runReasonPresetInstall(args []string) error {
+	opts := reason.InstallOptions{}
+	registry := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--sha256" && i+1 < len(args):
+			i++
+			opts.SHA256 = args[i]
+		case strings.HasPrefix(arg, "--sha256="):
+			opts.SHA256 = strings.TrimPrefix(arg, "--sha256=")
+		case arg == "--registry" && i+1 < len(args):
+			i++
+			registry = args[i]
+		case strings.HasPrefix(arg, "--registry="):
+			registry = strings.TrimPrefix(arg, "--registry=")
+		case arg == "--force":
+			opts.Force = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case opts.Source == "":
+			opts.Source = arg
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if opts.Source == "" {
+		return fmt.Errorf("usage: cortex reason preset install <name|url|git|file> [--sha256 <hex>] [--registry <url>] [--force]")
+	}
+	opts.Registry = presetRegistryURL(registry)
+
+	rec, err := reason.InstallPreset(context.Background(), getConfigDir(), opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Installed preset %q from %s\n", rec.Name, rec.Source)
+	fmt.Printf("  sha256 %s\n", rec.SHA256)
+	fmt.Printf("  run it: cortex reason --preset %s\n", rec.Name)
+	return nil
+}
+
+func runReasonPresetRemove(args []string) error {
+	name := ""
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case name == "":
+			name = arg
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if name == "" {
+		return fmt.Errorf("usage: cortex reason preset remove <name>")
+	}
+	if err := reason.RemovePreset(getConfigDir(), name); err != nil {
+		return err
+	}
+	fmt.Printf("Removed preset %q\n", name)
+	return nil
+}
+
+type presetListEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Origin      string `json:"origin"` // builtin, custom, or installed
+	Source      string `json:"source,omitempty"`
+	SHA256      string `json:"sha256,omitempty"`
+}
+
+func runReasonPresetList(args []string) error {
+	jsonOut := false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOut = true
+		default:
+			return fmt.Errorf("unknown flag: %s", arg)
+		}
+	}
+	configDir := getConfigDir()
+	installed, err := reason.InstalledPresets(configDir)
+	if err != nil {
+		return err
+	}
+	custom, err := reason.LoadCustomPresets(configDir)
+	if err != nil {
+		return err
+	}
+
+	byName := map[string]presetListEntry{}
+	for name, p := range reason.BuiltinPresets {
+		byName[name] = presetListEntry{Name: name, Description: p.Description, Origin: "builtin"}
+	}
+	for name, p := range custom {
+		e := presetListEntry{Name: name, Description: p.Description, Origin: "custom"}
+		if rec, ok := installed[name]; ok {
+			e.Origin, e.Source, e.SHA256 = "installed", rec.Source, rec.SHA256
+		}
+		byName[name] = e
+	}
+	entries := make([]presetListEntry, 0, len(byName))
+	for _, e := range byName {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	fmt.Printf("Presets (installed in %s):\n\n", reason.PresetsDir(configDir))
+	for _, e := range entries {
+		fmt.Printf("  %-20s %-10s %s\n", e.Name, e.Origin, e.Description)
+	}
+	return nil
+}
+
+func runReasonPresetSearch(args []string) error {
+	jsonOut := false
+	registry, text := "", ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--json":
+			jsonOut = true
+		case arg == "--registry" && i+1 < len(args):
+			i++
+			registry = args[i]
+		case strings.HasPrefix(arg, "--registry="):
+			registry = strings.TrimPrefix(arg, "--registry=")
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case text == "":
+			text = strings.ToLower(arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	index := presetRegistryURL(registry)
+	reg, err := reason.FetchRegistry(context.Background(), nil, index)
+	if err != nil {
+		return err
+	}
+	matches := []reason.RegistryEntry{}
+	for _, e := range reg.Presets {
+		if text == "" || strings.Contains(strings.ToLower(e.Name+" "+e.Description), text) {
+			matches = append(matches, e)
+		}
+	}
+
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(matches)
+	}
+	if len(matches) == 0 {
+		fmt.Printf("No presets found in %s\n", index)
+		return nil
+	}
+	fmt.Printf("Presets in %s:\n\n", index)
+	for _, e := range matches {
+		fmt.Printf("  %-20s %s\n", e.Name, e.Description)
+	}
+	fmt.Println("\nInstall one with: cortex reason preset install <name>")
+	return nil
+}
+
+func runReasonPresetPublish(args []string) error {
+	name, outDir, fileURL, author := "", ".", "", ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--out" && i+1 < len(args):
+			i++
+			outDir = args[i]
+		case strings.HasPrefix(arg, "--out="):
+			outDir = strings.TrimPrefix(arg, "--out=")
+		case arg == "--url" && i+1 < len(args):
+			i++
+			fileURL = args[i]
+		case strings.HasPrefix(arg, "--url="):
+			fileURL = strings.TrimPrefix(arg, "--url=")
+		case arg == "--author" && i+1 < len(args):
+			i++
+			author = args[i]
+		case strings.HasPrefix(arg, "--author="):
+			author = strings.TrimPrefix(arg, "--author=")
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case name == "":
+			name = arg
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if name == "" {
+		return fmt.Errorf("usage: cortex reason preset publish <name> [--out <dir>] [--url <url>] [--author <name>]")
+	}
+
+	p, err := reason.GetPreset(name, getConfigDir())
+	scaffold := err != nil
+	if scaffold {
+		p = &reason.Preset{
+			Name:        name,
+			Description: "Describe what this preset is for",
+			System:      "You are an analyst reviewing a personal knowledge base. Be specific and cite the memories you rely on.",
+			Template:    "Question: {{.Query}}\n\nRelevant memories:\n{{context}}\n\nAnswer the question from the memories above.",
+			MaxTokens:   1024,
+			SearchLimit: 20,
+			SearchMode:  "hybrid",
+		}
+	}
+	if fileURL == "" {
+		fileURL = name + ".yaml" // relative to the registry index
+	}
+	data, entry, err := reason.PublishPreset(*p, author, fileURL)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(outDir, name+".yaml")
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	snippet, err := yaml.Marshal([]reason.RegistryEntry{entry})
+	if err != nil {
+		return err
+	}
+
+	if scaffold {
+		fmt.Printf("Wrote skeleton preset %s\n", path)
+		fmt.Printf("After editing it, update sha256 in the entry below (sha256sum %s).\n\n", path)
+	} else {
+		fmt.Printf("Wrote %s\n\n", path)
+	}
+	fmt.Println("Add this entry under `presets:` in the registry index:")
+	fmt.Println()
+	fmt.Print(indentLines(string(snippet), "  "))
+	return nil
+}
+
+func indentLines(s, prefix string) string {
+	lines := strings.SplitAfter(s, "\n")
+	var b strings.Builder
+	for _, line := range lines {
+		if line != "" {
+			b.WriteString(prefix + line)
+		}
+	}
+	return b.String()
+}
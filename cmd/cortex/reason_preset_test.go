@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunReasonPreset_InstallListPublish(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	index, err := filepath.Abs(filepath.Join("..", "..", "presets", "index.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CORTEX_PRESET_REGISTRY", index)
+
+	out := captureStdout(func() {
+		if err := runReason([]string{"preset", "search", "incident"}); err != nil {
+			t.Fatalf("search: %v", err)
+		}
+	})
+	if !strings.Contains(out, "incident-review") || strings.Contains(out, "meeting-prep") {
+		t.Fatalf("unexpected search output:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runReason([]string{"preset", "install", "incident-review"}); err != nil {
+			t.Fatalf("install: %v", err)
+		}
+	})
+	if !strings.Contains(out, `Installed preset "incident-review"`) {
+		t.Fatalf("unexpected install output:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".cortex", "presets", "incident-review.yaml")); err != nil {
+		t.Fatalf("preset file not written: %v", err)
+	}
+
+	out = captureStdout(func() {
+		if err := runReason([]string{"preset", "list", "--json"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	var entries []presetListEntry
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("parsing list: %v\n%s", err, out)
+	}
+	origins := map[string]string{}
+	for _, e := range entries {
+		origins[e.Name] = e.Origin
+	}
+	if origins["incident-review"] != "installed" || origins["daily-digest"] != "builtin" {
+		t.Fatalf("unexpected origins: %v", origins)
+	}
+
+	outDir := t.TempDir()
+	out = captureStdout(func() {
+		if err := runReason([]string{"preset", "publish", "incident-review", "--out", outDir, "--author", "me"}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	})
+	if !strings.Contains(out, "name: incident-review") || !strings.Contains(out, "sha256: ") || !strings.Contains(out, "author: me") {
+		t.Fatalf("unexpected publish output:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "incident-review.yaml")); err != nil {
+		t.Fatalf("published file missing: %v", err)
+	}
+
+	captureStdout(func() {
+		if err := runReason([]string{"preset", "remove", "incident-review"}); err != nil {
+			t.Fatalf("remove: %v", err)
+		}
+	})
+}
+
+func TestRunReasonPreset_Errors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for _, args := range [][]string{
+		{"bogus"},
+		{"install"},
+		{"install", "a", "b"},
+		{"install", "x", "--bogus"},
+		{"install", "https://example.com/p.yaml"},
+		{"remove", "never-installed"},
+		{"publish"},
+	} {
+		if err := runReasonPreset(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/hurttlocker/cortex/internal/rerank"
 	"github.com/hurttlocker/cortex/internal/search"
 	"github.com/hurttlocker/cortex/internal/store"
+	"github.com/hurttlocker/cortex/internal/tokens"
 )
 
 const (
@@ -396,7 +397,7 @@ func parseRecallQueryOptions(args []string, command string) (recallQueryOptions,
 			}
 			opts.RerankMode = parsed
 		case strings.HasPrefix(args[i], "-"):
-			return opts, fmt.Errorf("unknown flag: %s\nusage: cortex %s <query> [--mode keyword|semantic|hybrid|rrf] [--limit N] [--max-items N] [--max-tokens N] [--embed <provider/model>] [--rerank[=auto|on|off]] [--min-score N] [--class rule,decision] [--project <name>] [--agent <id>] [--channel <name>] [--session-key <key>] [--scope agent:<id>|entity:<id>|session:<id>|project:<id>] [--boost-agent <id>] [--boost-channel <name>] [--boost-session-key <key>] [--source <provider>] [--source-boost <prefix[:weight]>] [--after YYYY-MM-DD] [--before YYYY-MM-DD] [--allow-evidence-fallback] [--json]", args[i], command)
+			return opts, fmt.Errorf("unknown flag: %s\nusage: cortex %s <query> [--mode keyword|semantic|hybrid|rrf] [--limit N] [--max-items N] [--max-tokens N] [--embed <provider/model>] [--rerank[=auto|on|off]] [--min-score N] [--class rule,decision] [--project <name>] [--agent <id>] [--channel <name>] [--session-key <key>] [--scope agent:<id>|entity:<id>|session:<id>|project:<id>] [--boost-agent <id>] [--boost-channel <name>] [--boost-session-key <key>] [--source <source|type>] [--source-boost <prefix[:weight]>] [--after YYYY-MM-DD] [--before YYYY-MM-DD] [--allow-evidence-fallback] [--json]", args[i], command)
 		default:
 			queryParts = append(queryParts, args[i])
 		}
@@ -404,7 +405,7 @@ func parseRecallQueryOptions(args []string, command string) (recallQueryOptions,
 
 	opts.Query = strings.TrimSpace(strings.Join(queryParts, " "))
 	if opts.Query == "" {
-		return opts, fmt.Errorf("usage: cortex %s <query> [--mode keyword|semantic|hybrid|rrf] [--limit N] [--max-items N] [--max-tokens N] [--embed <provider/model>] [--rerank[=auto|on|off]] [--min-score N] [--class rule,decision] [--project <name>] [--agent <id>] [--channel <name>] [--session-key <key>] [--scope agent:<id>|entity:<id>|session:<id>|project:<id>] [--boost-agent <id>] [--boost-channel <name>] [--boost-session-key <key>] [--source <provider>] [--source-boost <prefix[:weight]>] [--after YYYY-MM-DD] [--before YYYY-MM-DD] [--allow-evidence-fallback] [--json]", command)
+		return opts, fmt.Errorf("usage: cortex %s <query> [--mode keyword|semantic|hybrid|rrf] [--limit N] [--max-items N] [--max-tokens N] [--embed <provider/model>] [--rerank[=auto|on|off]] [--min-score N] [--class rule,decision] [--project <name>] [--agent <id>] [--channel <name>] [--session-key <key>] [--scope agent:<id>|entity:<id>|session:<id>|project:<id>] [--boost-agent <id>] [--boost-channel <name>] [--boost-session-key <key>] [--source <source|type>] [--source-boost <prefix[:weight]>] [--after YYYY-MM-DD] [--before YYYY-MM-DD] [--allow-evidence-fallback] [--json]", command)
 	}
 
 	return opts, nil
@@ -476,6 +477,8 @@ func buildRecallItems(ctx context.Context, opts recallQueryOptions) ([]recallIte
 		Source:            opts.Source,
 		Scope:             scopeFilters,
 		SourceBoosts:      parsedSourceBoosts,
+		Freshness:         search.FreshnessFromConfig(resolvedCfg.Search.Freshness),
+		GraphBoost:        resolvedCfg.Search.GraphBoost,
 		IncludeSuperseded: true,
 		BoostAgent:        opts.BoostAgent,
 		BoostChannel:      opts.BoostChannel,
@@ -1003,9 +1006,9 @@ func cleanSnippet(snippet string) string {
 }
 
 func estimateContextItemTokens(item recallItem) int {
-	lines := 5
-	chars := len(item.SourceFile) + len(item.SourceSection) + len(item.PromptText) + lines*6
-	return max(1, estimateTokens(strings.Repeat("x", chars)))
+	// Five formatted lines of labels and punctuation around the fields.
+	const lineOverhead = 5 * 2
+	return tokens.Count(item.SourceFile) + tokens.Count(item.SourceSection) + tokens.Count(item.PromptText) + lineOverhead
 }
 
 func estimateContextBlockTokens(items []recallItem) int {
@@ -1016,7 +1019,7 @@ func estimateTokens(text string) int {
 	if text == "" {
 		return 0
 	}
-	return max(1, (len(text)+3)/4)
+	return max(1, tokens.Count(text))
 }
 
 func filteredQueryTokens(query string) map[string]struct{} {
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func reviewUsageText() string {
+	return `Usage: cortex review <list|approve|reject> [flags]
+
+LLM-enriched facts below extract.review_threshold (default 0.5; 0 disables)
+are held in a review queue instead of active memory. Nothing in the queue is
+searchable until approved.
+
+Subcommands:
+  list [--status pending|approved|rejected|all] [--below <conf>] [--limit N] [--json]
+                        Show queued facts, lowest confidence first
+  approve <id>... | --all [--below <conf>] [--reviewer <name>]
+                        Store the facts as active memory
+  reject <id>... | --all [--below <conf>] [--reason <text>] [--reviewer <name>]
+                        Discard the facts
+
+--all acts on every pending fact (narrowed by --below). MCP clients use
+cortex_review_list, cortex_review_approve, and cortex_review_reject.`
+}
+
+func runReview(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(reviewUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "list", "ls":
+		return runReviewList(args[1:])
+	case "approve":
+		return runReviewResolve(args[1:], true)
+	case "reject":
+		return runReviewResolve(args[1:], false)
+	default:
+		return fmt.Errorf("unknown review subcommand: %s", args[0])
+	}
+}
+
+func openReviewStore() (*store.SQLiteStore, func(), error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening store: %w", err)
+	}
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, nil, fmt.Errorf("review requires SQLiteStore")
+	}
+	return sqlStore, func() { s.Close() }, nil
+}
+
+func parseReviewConfidence(flag, raw string) (float64, error) {
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || v <= 0 || v > 1 {
+		return 0, fmt.Errorf("%s must be a number in (0, 1]", flag)
+	}
+	return v, nil
+}
+
+func runReviewList(args []string) error {
+	opts := store.FactReviewListOpts{}
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--status" && i+1 < len(args):
+			i++
+			opts.Status = args[i]
+		case strings.HasPrefix(args[i], "--status="):
+			opts.Status = strings.TrimPrefix(args[i], "--status=")
+		case args[i] == "--below" && i+1 < len(args):
+			i++
+			v, err := parseReviewConfidence("--below", args[i])
+			if err != nil {
+				return err
+			}
+			opts.BelowConfidence = v
+		case strings.HasPrefix(args[i], "--below="):
+			v, err := parseReviewConfidence("--below", strings.TrimPrefix(args[i], "--below="))
+			if err != nil {
+				return err
+			}
+			opts.BelowConfidence = v
+		case args[i] == "--limit" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("--limit must be a positive integer")
+			}
+			opts.Limit = n
+		case strings.HasPrefix(args[i], "--limit="):
+			n, err := strconv.Atoi(strings.TrimPrefix(args[i], "--limit="))
+			if err != nil || n <= 0 {
+				return fmt.Errorf("--limit must be a positive integer")
+			}
+			opts.Limit = n
+		case args[i] == "--json":
+			jsonOutput = true
+		default:
+			return fmt.Errorf("unknown flag: %s", args[i])
+		}
+	}
+
+	s, closeStore, err := openReviewStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	reviews, err := s.ListFactReviews(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		if reviews == nil {
+			reviews = []*store.FactReview{}
+		}
+		data, _ := json.MarshalIndent(reviews, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(reviews) == 0 {
+		fmt.Println("Review queue is empty.")
+		return nil
+	}
+	for _, r := range reviews {
+		fmt.Printf("#%-5d %.2f  %s %s %s  [%s]\n", r.ID, r.Confidence, r.Subject, r.Predicate, truncateString(r.Object, 80), r.FactType)
+		detail := fmt.Sprintf("memory %d · %s", r.MemoryID, r.Method)
+		if r.Model != "" {
+			detail += " (" + r.Model + ")"
+		}
+		if r.Status != store.FactReviewPending {
+			detail += " · " + r.Status
+			if r.FactID != nil {
+				detail += fmt.Sprintf(" → fact %d", *r.FactID)
+			}
+			if r.Reason != "" {
+				detail += ": " + r.Reason
+			}
+		}
+		fmt.Printf("       %s\n", detail)
+		if r.SourceQuote != "" {
+			fmt.Printf("       \"%s\"\n", truncateString(r.SourceQuote, 100))
+		}
+	}
+	if opts.Status == "" || opts.Status == store.FactReviewPending {
+		fmt.Printf("\n%d pending. Approve: cortex review approve <id>...  Reject: cortex review reject <id>...\n", len(reviews))
+	}
+	return nil
+}
+
+func runReviewResolve(args []string, approve bool) error {
+	verb := "reject"
+	if approve {
+		verb = "approve"
+	}
+	all := false
+	below := 0.0
+	reviewer, reason := "", ""
+	var ids []int64
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--all":
+			all = true
+		case args[i] == "--below" && i+1 < len(args):
+			i++
+			v, err := parseReviewConfidence("--below", args[i])
+			if err != nil {
+				return err
+			}
+			below = v
+		case strings.HasPrefix(args[i], "--below="):
+			v, err := parseReviewConfidence("--below", strings.TrimPrefix(args[i], "--below="))
+			if err != nil {
+				return err
+			}
+			below = v
+		case args[i] == "--reviewer" && i+1 < len(args):
+			i++
+			reviewer = args[i]
+		case strings.HasPrefix(args[i], "--reviewer="):
+			reviewer = strings.TrimPrefix(args[i], "--reviewer=")
+		case !approve && args[i] == "--reason" && i+1 < len(args):
+			i++
+			reason = args[i]
+		case !approve && strings.HasPrefix(args[i], "--reason="):
+			reason = strings.TrimPrefix(args[i], "--reason=")
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		default:
+			id, err := strconv.ParseInt(args[i], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid review id %q", args[i])
+			}
+			ids = append(ids, id)
+		}
+	}
+	if all == (len(ids) > 0) {
+		return fmt.Errorf("usage: cortex review %s <id>... | --all [--below <conf>]", verb)
+	}
+	if below > 0 && !all {
+		return fmt.Errorf("--below only applies with --all")
+	}
+
+	s, closeStore, err := openReviewStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	ctx := context.Background()
+
+	if all {
+		pending, err := s.ListFactReviews(ctx, store.FactReviewListOpts{BelowConfidence: below})
+		if err != nil {
+			return err
+		}
+		for _, r := range pending {
+			ids = append(ids, r.ID)
+		}
+	}
+
+	var factIDs []int64
+	var failures []string
+	for _, id := range ids {
+		if approve {
+			factID, err := s.ApproveFactReview(ctx, id, reviewer)
+			if err != nil {
+				failures = append(failures, err.Error())
+				continue
+			}
+			factIDs = append(factIDs, factID)
+		} else if err := s.RejectFactReview(ctx, id, reviewer, reason); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	done := len(ids) - len(failures)
+
+	if approve && len(factIDs) > 0 {
+		if _, err := s.UpdateClusters(ctx, factIDs); err != nil {
+			fmt.Fprintf(os.Stderr, "  Cluster update warning: %v\n", err)
+		}
+	}
+
+	if approve {
+		fmt.Printf("Approved %d %s into active memory\n", done, pluralize("fact", "facts", done))
+	} else {
+		fmt.Printf("Rejected %d %s\n", done, pluralize("fact", "facts", done))
+	}
+	for _, f := range failures {
+		fmt.Fprintf(os.Stderr, "  %s\n", f)
+	}
+	if len(failures) > 0 && done == 0 {
+		return fmt.Errorf("no reviews were %sd", verb)
+	}
+	return nil
+}
+
+// printEnrichReviewNotice points at the review queue after an enrichment run
+// that held facts back.
+func printEnrichReviewNotice(stats *EnrichmentStats) {
+	if stats == nil || stats.Queued == 0 {
+		return
+	}
+	fmt.Printf("  🔍 %d low-confidence %s held for review (cortex review list)\n",
+		stats.Queued, pluralize("fact", "facts", stats.Queued))
+}
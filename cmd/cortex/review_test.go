@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunReview_ListApproveRejectAll(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, closeStore, err := openReviewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "Maybe we switch the broker to IBKR at some point"})
+	var ids []int64
+	for i, conf := range []float64{0.2, 0.35, 0.45} {
+		id, err := s.QueueFactReview(ctx, &store.FactReview{MemoryID: memID, Subject: "desk", Predicate: "might switch broker", Object: fmt.Sprintf("option %d", i), Confidence: conf, Method: "llm-enrich"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	closeStore()
+
+	out := captureStdout(func() {
+		if err := runReview([]string{"list"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	if !strings.Contains(out, "0.20  desk might switch broker option 0") || !strings.Contains(out, "3 pending") {
+		t.Fatalf("unexpected list output:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runReview([]string{"approve", fmt.Sprint(ids[2]), "--reviewer", "q"}); err != nil {
+			t.Fatalf("approve: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Approved 1 fact into active memory") {
+		t.Fatalf("unexpected approve output: %q", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runReview([]string{"reject", "--all", "--below", "0.3", "--reason", "speculative"}); err != nil {
+			t.Fatalf("reject --all: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Rejected 1 fact") {
+		t.Fatalf("unexpected reject output: %q", out)
+	}
+
+	if err := runReview([]string{"approve"}); err == nil {
+		t.Fatal("expected usage error without ids or --all")
+	}
+	if err := runReview([]string{"approve", "1", "--below", "0.5"}); err == nil {
+		t.Fatal("expected error for --below without --all")
+	}
+
+	s, closeStore, err = openReviewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+	pending, _ := s.ListFactReviews(ctx, store.FactReviewListOpts{})
+	if len(pending) != 1 || pending[0].ID != ids[1] {
+		t.Fatalf("expected only the 0.35 review pending, got %+v", pending)
+	}
+	if facts, _ := s.ListFacts(ctx, store.ListOpts{Limit: 10}); len(facts) != 1 || facts[0].Object != "option 2" {
+		t.Fatalf("expected only the approved fact in memory, got %+v", facts)
+	}
+}
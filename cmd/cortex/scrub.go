@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+// newScrubbedProvider creates the provider for cfg wrapped in a secrets
+// scrubber; every llm.NewProvider backend is remote.
+func newScrubbedProvider(cfg llm.Config) (*llm.Scrubbed, error) {
+	p, err := llm.NewProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return llm.Scrub(p), nil
+}
+
+// reportScrubbed notes on stderr what a scrubbed provider masked during this
+// run. Other providers, and runs that masked nothing, print nothing.
+func reportScrubbed(p llm.Provider) {
+	if s, ok := p.(*llm.Scrubbed); ok && s != nil {
+		printScrubReport(s.Name(), s.Report())
+	}
+}
+
+func printScrubReport(target string, r llm.ScrubReport) {
+	if r.Masked() == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "🔒 Scrubbed %s before sending to %s\n", r, target)
+}
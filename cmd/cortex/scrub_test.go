@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+func TestTryCreateProvider_ScrubsAndReports(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CORTEX_LLM_FALLBACK", "")
+	t.Setenv("OPENROUTER_API_KEY", "test-key")
+
+	provider, err := tryCreateProvider("openrouter/openai/gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("tryCreateProvider: %v", err)
+	}
+	scrubbed, ok := provider.(*llm.Scrubbed)
+	if !ok {
+		t.Fatalf("expected a scrubbed provider, got %T", provider)
+	}
+
+	if out := captureStderr(func() { reportScrubbed(scrubbed) }); out != "" {
+		t.Fatalf("nothing masked yet, got %q", out)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // the request fails before leaving the machine; scrubbing still runs
+	scrubbed.Complete(ctx, "ping ops@example.com", llm.CompletionOpts{})
+
+	out := captureStderr(func() { reportScrubbed(scrubbed) })
+	if !strings.Contains(out, "Scrubbed 1 value in 1 of 1 prompt (email 1)") || !strings.Contains(out, "openrouter") {
+		t.Fatalf("unexpected report: %q", out)
+	}
+	if out := captureStderr(func() { reportScrubbed(nil) }); out != "" {
+		t.Fatalf("nil provider should print nothing, got %q", out)
+	}
+}
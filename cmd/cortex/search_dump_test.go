@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunSearch_DumpWritesTraceJSONL(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	s.AddMemory(ctx, &store.Memory{Content: "Failover drill for the primary database", SourceFile: "drill.md", ContentHash: "a"})
+	s.AddMemory(ctx, &store.Memory{Content: "Failover alert from pager", SourceFile: "github:issues/9", ContentHash: "b"})
+	s.Close()
+
+	dump := filepath.Join(home, "explain.jsonl")
+	if err := runSearch([]string{"failover", "--dump", dump}); err == nil || !strings.Contains(err.Error(), "--explain") {
+		t.Fatalf("expected --dump to require --explain, got %v", err)
+	}
+	if err := runSearch([]string{"failover", "--explain", "--facts", "--dump", dump}); err == nil {
+		t.Fatal("expected --dump/--facts conflict")
+	}
+
+	captureStdout(func() {
+		if err := runSearch([]string{"failover", "--mode", "keyword", "--explain", "--json", "--source", "file", "--dump=" + dump}); err != nil {
+			t.Fatalf("search: %v", err)
+		}
+	})
+
+	f, err := os.Open(dump)
+	if err != nil {
+		t.Fatalf("open dump: %v", err)
+	}
+	defer f.Close()
+	rows := map[string]map[string]any{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var row map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatalf("decode %q: %v", scanner.Text(), err)
+		}
+		rows[row["source_file"].(string)] = row
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 traced candidates, got %d: %v", len(rows), rows)
+	}
+	if rows["drill.md"]["rank"] != 1.0 || rows["github:issues/9"]["filtered_at"] != "source_filter" {
+		t.Errorf("unexpected dump rows: %v", rows)
+	}
+}
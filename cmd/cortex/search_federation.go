@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/rerank"
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// globalWorkspaces holds a --workspace value naming more than one workspace
+// (or carrying an inline weight). Only search accepts it; everything else
+// still takes a single workspace through CORTEX_WORKSPACE.
+var globalWorkspaces string
+
+// workspaceSelection is one entry of a federated --workspace list.
+type workspaceSelection struct {
+	Name   string
+	Weight float64 // 0 = use the configured weight
+}
+
+// isWorkspaceList reports whether a --workspace value selects a federation
+// rather than a single profile.
+func isWorkspaceList(value string) bool {
+	return strings.ContainsAny(value, ",:")
+}
+
+// parseWorkspaceList parses "work:2,personal" into selections.
+func parseWorkspaceList(value string) ([]workspaceSelection, error) {
+	var out []workspaceSelection
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		sel := workspaceSelection{Name: part}
+		if name, rawWeight, ok := strings.Cut(part, ":"); ok {
+			w, err := parseWorkspaceWeight(rawWeight)
+			if err != nil {
+				return nil, err
+			}
+			sel = workspaceSelection{Name: strings.TrimSpace(name), Weight: w}
+		}
+		if !cfgresolver.ValidWorkspaceName(sel.Name) {
+			return nil, fmt.Errorf("invalid workspace name %q in --workspace %s", sel.Name, value)
+		}
+		if seen[sel.Name] {
+			return nil, fmt.Errorf("workspace %q listed twice in --workspace %s", sel.Name, value)
+		}
+		seen[sel.Name] = true
+		out = append(out, sel)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("--workspace %q names no workspaces", value)
+	}
+	return out, nil
+}
+
+func parseWorkspaceWeight(raw string) (float64, error) {
+	w, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil || w <= 0 {
+		return 0, fmt.Errorf("invalid workspace weight %q (must be a positive number)", raw)
+	}
+	return w, nil
+}
+
+// checkWorkspaceList validates a federated --workspace before any command
+// runs: only search federates, and every named workspace must exist.
+func checkWorkspaceList(command string) error {
+	if globalWorkspaces == "" {
+		return nil
+	}
+	if command != "search" {
+		return fmt.Errorf("--workspace %s: several workspaces can only be searched together (cortex --workspace a,b search <query>)", globalWorkspaces)
+	}
+	if globalDBPath != "" {
+		return fmt.Errorf("--db cannot be combined with a --workspace list")
+	}
+	selections, err := parseWorkspaceList(globalWorkspaces)
+	if err != nil {
+		return err
+	}
+	resolved, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+	if err != nil && !errors.Is(err, cfgresolver.ErrUnknownWorkspace) {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	for _, sel := range selections {
+		if _, ok := resolved.Workspaces[sel.Name]; !ok {
+			return fmt.Errorf("%w %q; run `cortex workspace list`", cfgresolver.ErrUnknownWorkspace, sel.Name)
+		}
+	}
+	return nil
+}
+
+// federatedSearchFlags are the runSearch settings the federation needs on
+// top of the shared search.Options.
+type federatedSearchFlags struct {
+	embed       string
+	rerankMode  rerank.Mode
+	jsonOutput  bool
+	showMeta    bool
+	explain     bool
+	displayMode search.Mode
+}
+
+// runFederatedSearch opens every workspace in globalWorkspaces with its own
+// profile (database and embedder), runs query against each with opts, and
+// prints the weighted-RRF merge with per-result workspace labels.
+func runFederatedSearch(ctx context.Context, query string, opts search.Options, flags federatedSearchFlags) error {
+	selections, err := parseWorkspaceList(globalWorkspaces)
+	if err != nil {
+		return err
+	}
+
+	members := make([]search.FederatedMember, 0, len(selections))
+	for i, sel := range selections {
+		resolved, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{Workspace: sel.Name})
+		if err != nil {
+			return fmt.Errorf("workspace %s: %w", sel.Name, err)
+		}
+		ws := resolved.Workspaces[sel.Name]
+		weight := sel.Weight
+		if weight == 0 {
+			weight = ws.Weight
+		}
+
+		s, err := store.NewStore(store.StoreConfig{DBPath: expandUserPath(ws.DB), ReadOnly: globalReadOnly, Namespace: getNamespace()})
+		if err != nil {
+			return fmt.Errorf("opening workspace %s: %w", sel.Name, err)
+		}
+		defer s.Close()
+
+		embedFlag := flags.embed
+		if strings.TrimSpace(embedFlag) == "" {
+			embedFlag = ws.Embed
+		}
+		engine, err := newSearchEngineForMode(s, opts.Mode, embedFlag)
+		if err != nil {
+			return fmt.Errorf("workspace %s: %w", sel.Name, err)
+		}
+		// Offer the reranker download at most once.
+		if err := configureSearchReranker(engine, flags.rerankMode, i == 0); err != nil {
+			return err
+		}
+		members = append(members, search.FederatedMember{Name: sel.Name, Weight: weight, Searcher: engine})
+	}
+
+	res, err := search.NewFederation(members...).SearchFederated(ctx, query, opts)
+	if err != nil {
+		return err
+	}
+	for _, m := range res.Members {
+		if m.Error != "" {
+			fmt.Fprintf(os.Stderr, "  Warning: workspace %s failed: %s\n", m.Name, m.Error)
+		} else if globalVerbose {
+			fmt.Fprintf(os.Stderr, "  %s (weight %g): %d hits in %dms\n", m.Name, m.Weight, m.Hits, m.Duration.Milliseconds())
+		}
+	}
+
+	if flags.jsonOutput || !isTTY() {
+		return outputJSON(res.Results)
+	}
+	return outputTTYSearch(query, res.Results, flags.showMeta, flags.explain, flags.displayMode, false)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func seedWorkspaceDB(t *testing.T, path string, contents ...string) {
+	t.Helper()
+	s, err := store.NewStore(store.StoreConfig{DBPath: path})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer s.Close()
+	for i, c := range contents {
+		if _, err := s.AddMemory(context.Background(), &store.Memory{Content: c, SourceFile: filepath.Base(path) + ".md", SourceLine: i + 1, ImportedAt: time.Now().UTC()}); err != nil {
+			t.Fatalf("AddMemory: %v", err)
+		}
+	}
+}
+
+func TestParseWorkspaceList(t *testing.T) {
+	got, err := parseWorkspaceList("work:2, personal,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != (workspaceSelection{Name: "work", Weight: 2}) || got[1] != (workspaceSelection{Name: "personal"}) {
+		t.Fatalf("unexpected selections: %+v", got)
+	}
+	for _, bad := range []string{"work,work", "work:0", "work:x", "bad name,b", ","} {
+		if _, err := parseWorkspaceList(bad); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestRunSearch_FederatesWorkspaces(t *testing.T) {
+	home := useWorkspaceHome(t)
+	t.Cleanup(func() { globalWorkspaces = "" })
+	workDB := filepath.Join(home, "work.db")
+	personalDB := filepath.Join(home, "personal.db")
+	seedWorkspaceDB(t, workDB, "kubernetes deploy checklist for the payments cluster")
+	seedWorkspaceDB(t, personalDB, "kubernetes homelab upgrade notes")
+
+	captureStdout(func() {
+		if err := runWorkspace([]string{"add", "work", "--db", workDB, "--weight", "2"}); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+		if err := runWorkspace([]string{"add", "personal", "--db", personalDB}); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	})
+	resolved, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+	if err != nil || resolved.Workspaces["work"].Weight != 2 {
+		t.Fatalf("expected the weight saved, got %+v (%v)", resolved.Workspaces["work"], err)
+	}
+
+	args := parseGlobalFlags([]string{"--workspace", "personal,work", "search", "kubernetes", "--mode", "keyword", "--json"})
+	if globalWorkspaces != "personal,work" {
+		t.Fatalf("expected a federated selection, got %q", globalWorkspaces)
+	}
+	if err := checkWorkspaceList("stats"); err == nil {
+		t.Fatal("only search should accept several workspaces")
+	}
+	if err := checkWorkspaceList(args[0]); err != nil {
+		t.Fatalf("checkWorkspaceList: %v", err)
+	}
+
+	var runErr error
+	out := captureStdout(func() { runErr = runSearch(args[1:]) })
+	if runErr != nil {
+		t.Fatalf("runSearch: %v", runErr)
+	}
+	var results []search.Result
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected a hit from each workspace, got %+v", results)
+	}
+	// The configured weight puts work first despite being listed second.
+	if results[0].Workspace != "work" || results[1].Workspace != "personal" {
+		t.Fatalf("unexpected provenance order: %s, %s", results[0].Workspace, results[1].Workspace)
+	}
+
+	// An inline weight overrides the configured one.
+	globalWorkspaces = "personal:5,work"
+	out = captureStdout(func() { runErr = runSearch(args[1:]) })
+	if runErr != nil {
+		t.Fatalf("runSearch: %v", runErr)
+	}
+	results = nil
+	if err := json.Unmarshal([]byte(out), &results); err != nil || len(results) != 2 || results[0].Workspace != "personal" {
+		t.Fatalf("expected personal first with an inline weight, got %+v (%v)", results, err)
+	}
+
+	if err := runSearch([]string{"kubernetes", "--facts"}); err == nil {
+		t.Fatal("expected --facts to be rejected for federated search")
+	}
+	globalWorkspaces = "work,missing"
+	if err := checkWorkspaceList("search"); !errors.Is(err, cfgresolver.ErrUnknownWorkspace) {
+		t.Fatalf("expected ErrUnknownWorkspace, got %v", err)
+	}
+}
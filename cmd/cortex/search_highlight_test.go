@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunSearch_HighlightJSONKeepsMarks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.AddMemory(context.Background(), &store.Memory{Content: "Failover drill for the primary database", SourceFile: "drill.md", ContentHash: "a"})
+	s.Close()
+
+	if err := runSearch([]string{"failover", "--snippet-tokens", "65"}); err == nil {
+		t.Fatal("expected error for --snippet-tokens 65")
+	}
+	if err := runSearch([]string{"failover", "--highlight=bold"}); err == nil {
+		t.Fatal("expected error for unknown --highlight style")
+	}
+
+	out := captureStdout(func() {
+		if err := runSearch([]string{"failover drill", "--mode", "keyword", "--json", "--highlight"}); err != nil {
+			t.Fatalf("search: %v", err)
+		}
+	})
+	var results []search.Result
+	if err := json.Unmarshal([]byte(out), &results); err != nil || len(results) != 1 {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if snip := results[0].Snippet; !strings.Contains(snip, "<mark>Failover</mark>") || !strings.Contains(snip, "<mark>drill</mark>") {
+		t.Fatalf("snippet = %q", snip)
+	}
+}
+
+func TestResolveSearchHighlight_DefaultsByOutput(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	if h, _ := resolveSearchHighlight("", 0, true); h.Style != search.HighlightMark {
+		t.Fatalf("machine output style = %q", h.Style)
+	}
+	if h, _ := resolveSearchHighlight("", 16, false); h.Style != search.HighlightANSI || h.Tokens != 16 {
+		t.Fatalf("terminal highlight = %+v", h)
+	}
+	t.Setenv("NO_COLOR", "1")
+	if h, _ := resolveSearchHighlight("", 0, false); h.Style != search.HighlightPlain {
+		t.Fatalf("NO_COLOR style = %q", h.Style)
+	}
+	if h, _ := resolveSearchHighlight("mark", 0, false); h.Style != search.HighlightMark {
+		t.Fatalf("explicit style = %q", h.Style)
+	}
+}
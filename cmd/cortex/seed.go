@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/seed"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func seedUsageText() string {
+	return `Usage: cortex seed apply <file.yaml> [--dry-run] [--json]
+
+Bootstrap identity, preference, decision and rule facts from a structured
+seed file instead of hoping extraction derives them from prose. Each fact is
+stored verbatim under the source seed:<name>. Re-applying a file is a no-op;
+when a fact's object changes, the new value supersedes the one the seed
+stored before. Predicates listed with several objects keep them all.
+
+Seed file:
+  name: profile              # defaults to the file name
+  agent: main                # optional, per file or per fact
+  project: home              # optional, per file or per fact
+  defaults:
+    class: preference        # identity|preference|decision|status|rule|scratch
+    confidence: 0.95
+  facts:
+    - subject: Q
+      predicate: timezone
+      object: America/New_York
+      class: identity
+      core: true             # pin as a core fact
+    - subject: deploys
+      predicate: require
+      object: a passing CI run
+      class: rule
+      confidence: 1
+
+A fact's type follows its class (rule and scratch map to kv); set type: to
+override it.
+
+Flags:
+  --dry-run   Show what would change without writing
+  --json      Machine-readable result`
+}
+
+func runSeed(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(seedUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "apply":
+		return runSeedApply(args[1:])
+	default:
+		return fmt.Errorf("unknown seed subcommand: %s", args[0])
+	}
+}
+
+func runSeedApply(args []string) error {
+	var path string
+	dryRun, jsonOutput := false, false
+	for _, a := range args {
+		switch {
+		case a == "--dry-run":
+			dryRun = true
+		case a == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(a, "-"):
+			return fmt.Errorf("unknown flag: %s", a)
+		case path == "":
+			path = a
+		default:
+			return fmt.Errorf("unexpected argument: %s", a)
+		}
+	}
+	if path == "" {
+		return fmt.Errorf("usage: cortex seed apply <file.yaml> [--dry-run] [--json]")
+	}
+
+	f, err := seed.Load(path)
+	if err != nil {
+		return err
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("seed requires SQLite store")
+	}
+
+	res, err := seed.Apply(context.Background(), sqlStore, f, dryRun)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+
+	for _, o := range res.Facts {
+		marker := " "
+		switch o.Action {
+		case seed.ActionAdded:
+			marker = "+"
+		case seed.ActionUpdated:
+			marker = "~"
+		}
+		fmt.Printf("  %s %s %s %s", marker, o.Subject, o.Predicate, o.Object)
+		for _, id := range o.Superseded {
+			fmt.Printf("  (supersedes fact %d)", id)
+		}
+		fmt.Println()
+	}
+	prefix := ""
+	if res.DryRun {
+		prefix = "Dry run: "
+	}
+	fmt.Printf("%s%s: %d added, %d updated, %d unchanged\n", prefix, res.Source, res.Added, res.Updated, res.Unchanged)
+	return nil
+}
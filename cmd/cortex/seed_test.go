@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/seed"
+)
+
+func TestRunSeedApply(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	path := filepath.Join(t.TempDir(), "profile.yaml")
+	doc := "facts:\n  - {subject: Q, predicate: timezone, object: UTC, class: identity}\n  - {subject: Q, predicate: prefers, object: tabs, class: preference}\n"
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	apply := func(args ...string) seed.Result {
+		t.Helper()
+		var runErr error
+		out := captureStdout(func() { runErr = runSeed(append([]string{"apply", path, "--json"}, args...)) })
+		if runErr != nil {
+			t.Fatalf("runSeed: %v", runErr)
+		}
+		var res seed.Result
+		if err := json.Unmarshal([]byte(out), &res); err != nil {
+			t.Fatalf("decoding %q: %v", out, err)
+		}
+		return res
+	}
+
+	if res := apply("--dry-run"); !res.DryRun || res.Added != 2 {
+		t.Fatalf("dry run = %+v", res)
+	}
+	if res := apply(); res.Added != 2 || res.Source != "seed:profile" {
+		t.Fatalf("apply = %+v", res)
+	}
+	if res := apply(); res.Unchanged != 2 {
+		t.Fatalf("re-apply = %+v", res)
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Replace(doc, "tabs", "spaces", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if res := apply(); res.Updated != 1 || res.Unchanged != 1 {
+		t.Fatalf("changed apply = %+v", res)
+	}
+}
+
+func TestRunSeed_Errors(t *testing.T) {
+	if err := runSeed([]string{"plant"}); err == nil {
+		t.Fatal("expected unknown subcommand error")
+	}
+	if err := runSeed([]string{"apply"}); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+	if err := runSeed([]string{"apply", "a.yaml", "--force"}); err == nil || !strings.Contains(err.Error(), "unknown flag") {
+		t.Fatalf("expected unknown flag error, got %v", err)
+	}
+}
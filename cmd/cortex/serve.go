@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/graph"
+)
+
+func serveUsageText() string {
+	return `Usage: cortex serve [--port 8090] [--agent <id>]
+       cortex serve --openapi [--format yaml|json]
+
+Serve the read-only HTTP API (graph, search, facts, clusters, stats,
+impact, timeline) and the graph explorer UI. The OpenAPI 3 document for
+the API is served at /api/openapi.json.
+
+Flags:
+  --port, -p <n>          Port to listen on (default 8090)
+  --agent <id>            Scope every response to one agent
+  --openapi               Print the OpenAPI spec and exit
+  --format yaml|json      Spec format for --openapi (default yaml)`
+}
+
+func runServe(args []string) error {
+	openAPI := false
+	format := "yaml"
+	var passthrough []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--help" || arg == "-h":
+			fmt.Println(serveUsageText())
+			return nil
+		case arg == "--openapi":
+			openAPI = true
+		case arg == "--format" && i+1 < len(args):
+			i++
+			format = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--format=")))
+		case (arg == "--port" || arg == "-p" || arg == "--agent") && i+1 < len(args):
+			if arg != "--agent" {
+				if _, err := strconv.Atoi(args[i+1]); err != nil {
+					return fmt.Errorf("invalid --port: %s", args[i+1])
+				}
+			}
+			passthrough = append(passthrough, arg, args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--agent="):
+			passthrough = append(passthrough, arg)
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if format != "yaml" && format != "json" {
+		return fmt.Errorf("unsupported --format %q (supported: yaml, json)", format)
+	}
+	if !openAPI {
+		return runGraphServe(passthrough)
+	}
+
+	spec := graph.OpenAPISpec(version)
+	var data []byte
+	var err error
+	if format == "json" {
+		data, err = spec.JSON()
+	} else {
+		data, err = spec.YAML()
+	}
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
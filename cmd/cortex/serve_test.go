@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunServe_OpenAPI(t *testing.T) {
+	for _, args := range [][]string{
+		{"--openapi", "--format", "xml"},
+		{"--openapi", "--bogus"},
+		{"--port", "abc"},
+		{"extra"},
+	} {
+		if err := runServe(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+
+	out := captureStdout(func() {
+		if err := runServe([]string{"--openapi"}); err != nil {
+			t.Fatalf("yaml: %v", err)
+		}
+	})
+	if !strings.HasPrefix(out, "openapi: 3.0.3\n") || !strings.Contains(out, "/api/search:") {
+		t.Fatalf("unexpected YAML spec:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runServe([]string{"--openapi", "--format=json"}); err != nil {
+			t.Fatalf("json: %v", err)
+		}
+	})
+	var spec struct {
+		Info  struct{ Version string }
+		Paths map[string]any
+	}
+	if err := json.Unmarshal([]byte(out), &spec); err != nil {
+		t.Fatalf("parsing JSON spec: %v", err)
+	}
+	if spec.Info.Version != version || spec.Paths["/api/timeline"] == nil {
+		t.Fatalf("unexpected JSON spec: %+v", spec)
+	}
+}
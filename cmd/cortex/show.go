@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const showUsage = "usage: cortex show <memory_id> [--context [N]] [--json]"
+
+// defaultShowContext is how many chunks on each side --context shows.
+const defaultShowContext = 2
+
+// showAccessLimit caps the access history listed by `cortex show`.
+const showAccessLimit = 10
+
+// showJSON is the `cortex show --json` document.
+type showJSON struct {
+	Memory    *store.Memory     `json:"memory"`
+	Context   *showContextJSON  `json:"context,omitempty"`
+	Facts     []*store.Fact     `json:"facts"`
+	Embedding showEmbeddingJSON `json:"embedding"`
+	// Enrichment is set while the memory waits on a background enrich job.
+	Enrichment *store.EnrichmentState `json:"enrichment,omitempty"`
+	Edges      []showEdgeJSON         `json:"edges"`
+	Access     showAccessJSON         `json:"access"`
+}
+
+type showContextJSON struct {
+	Before []*store.Memory `json:"before"`
+	After  []*store.Memory `json:"after"`
+}
+
+type showEmbeddingJSON struct {
+	Embedded   bool `json:"embedded"`
+	Dimensions int  `json:"dimensions,omitempty"`
+}
+
+// showEdgeJSON is an edge touching one of the memory's facts, with the fact
+// at the other end so the view reads without another lookup.
+type showEdgeJSON struct {
+	store.FactEdge
+	Other *store.Fact `json:"other,omitempty"`
+}
+
+type showAccessJSON struct {
+	Total  int                `json:"total"`
+	Recent []store.FactAccess `json:"recent"`
+}
+
+// runShow handles `cortex show`: one memory with its neighbouring chunks,
+// facts, embedding status, edges, and access history.
+func runShow(args []string) error {
+	var id int64
+	contextN := 0
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--json":
+			jsonOutput = true
+		case arg == "--context":
+			contextN = defaultShowContext
+			// A count may follow once the ID is known: `show 42 --context 5`
+			// but `show --context 42`.
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") && id != 0 {
+				n, err := strconv.Atoi(args[i+1])
+				if err != nil || n < 1 {
+					return fmt.Errorf("invalid --context value: %s", args[i+1])
+				}
+				contextN = n
+				i++
+			}
+		case strings.HasPrefix(arg, "--context="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--context="))
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid --context value: %s", strings.TrimPrefix(arg, "--context="))
+			}
+			contextN = n
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case id == 0:
+			parsed, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil || parsed <= 0 {
+				return fmt.Errorf("invalid memory id %q", arg)
+			}
+			id = parsed
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if id == 0 {
+		return fmt.Errorf("%s", showUsage)
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("show requires SQLiteStore")
+	}
+
+	view, err := buildShowView(context.Background(), sqlStore, id, contextN)
+	if err != nil {
+		return err
+	}
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(view)
+	}
+	printShowView(view)
+	return nil
+}
+
+// buildShowView gathers everything `cortex show` prints for one memory.
+func buildShowView(ctx context.Context, s *store.SQLiteStore, id int64, contextN int) (*showJSON, error) {
+	m, err := s.GetMemory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, fmt.Errorf("memory %d not found", id)
+	}
+	view := &showJSON{Memory: m, Facts: []*store.Fact{}, Edges: []showEdgeJSON{}}
+	anchored := []*store.Memory{m}
+
+	if contextN > 0 {
+		before, after, err := s.SourceNeighbors(ctx, m, contextN)
+		if err != nil {
+			return nil, err
+		}
+		if before == nil {
+			before = []*store.Memory{}
+		}
+		if after == nil {
+			after = []*store.Memory{}
+		}
+		view.Context = &showContextJSON{Before: before, After: after}
+		anchored = append(append(anchored, before...), after...)
+	}
+	if err := s.AttachMemoryAnchors(ctx, anchored); err != nil {
+		return nil, err
+	}
+
+	facts, err := s.GetFactsByMemoryIDs(ctx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+	if facts != nil {
+		view.Facts = facts
+	}
+
+	dims, err := s.MemoryEmbeddingDimensions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	view.Embedding = showEmbeddingJSON{Embedded: dims > 0, Dimensions: dims}
+
+	if view.Enrichment, err = s.GetEnrichmentState(ctx, id); err != nil {
+		return nil, err
+	}
+
+	seen := map[int64]bool{}
+	for _, f := range facts {
+		edges, err := s.GetEdgesForFact(ctx, f.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range edges {
+			if seen[e.ID] {
+				continue
+			}
+			seen[e.ID] = true
+			otherID := e.TargetFactID
+			if otherID == f.ID {
+				otherID = e.SourceFactID
+			}
+			other, err := s.GetFact(ctx, otherID)
+			if err != nil {
+				return nil, err
+			}
+			view.Edges = append(view.Edges, showEdgeJSON{FactEdge: e, Other: other})
+		}
+	}
+	sort.SliceStable(view.Edges, func(i, j int) bool { return view.Edges[i].ID < view.Edges[j].ID })
+
+	recent, total, err := s.MemoryAccessHistory(ctx, id, showAccessLimit)
+	if err != nil {
+		return nil, err
+	}
+	if recent == nil {
+		recent = []store.FactAccess{}
+	}
+	view.Access = showAccessJSON{Total: total, Recent: recent}
+	return view, nil
+}
+
+func printShowView(v *showJSON) {
+	m := v.Memory
+	fmt.Printf("Memory #%d", m.ID)
+	if m.SourceFile != "" {
+		fmt.Printf("  📁 %s", m.SourceFile)
+		if m.SourceLine > 0 {
+			fmt.Printf(":%d", m.SourceLine)
+		}
+	}
+	fmt.Println()
+	if m.Anchor != nil && m.Anchor.HeadingPath != "" {
+		fmt.Printf("  § %s\n", m.Anchor.HeadingPath)
+	} else if m.SourceSection != "" {
+		fmt.Printf("  § %s\n", m.SourceSection)
+	}
+	var tags []string
+	if m.Project != "" {
+		tags = append(tags, "project:"+m.Project)
+	}
+	if m.MemoryClass != "" {
+		tags = append(tags, "class:"+m.MemoryClass)
+	}
+	tags = append(tags, "imported "+m.ImportedAt.Format("2006-01-02 15:04"))
+	fmt.Printf("  %s\n\n", strings.Join(tags, " · "))
+
+	if v.Context != nil {
+		for _, c := range v.Context.Before {
+			printShowNeighbor(c)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSpace(m.Content), "\n") {
+		fmt.Printf("  │ %s\n", line)
+	}
+	if v.Context != nil {
+		for _, c := range v.Context.After {
+			printShowNeighbor(c)
+		}
+	}
+	fmt.Println()
+
+	if v.Embedding.Embedded {
+		fmt.Printf("🧭 Embedding: yes (%d dims)\n", v.Embedding.Dimensions)
+	} else {
+		fmt.Println("🧭 Embedding: none (run `cortex embed`)")
+	}
+	if e := v.Enrichment; e != nil {
+		if e.Status == store.EnrichmentPending {
+			fmt.Printf("🧠 Enrichment: pending (job #%d, queued %s)\n", e.JobID, e.QueuedAt.Local().Format("2006-01-02 15:04"))
+		} else {
+			fmt.Printf("🧠 Enrichment: stalled (job #%d ended without enriching it; see cortex jobs status %d)\n", e.JobID, e.JobID)
+		}
+	}
+
+	fmt.Printf("\n🧩 Facts (%d):\n", len(v.Facts))
+	if len(v.Facts) == 0 {
+		fmt.Println("  (none extracted)")
+	}
+	for _, f := range v.Facts {
+		fmt.Printf("  #%d %s %s %s  (%s, %.2f)\n", f.ID, f.Subject, f.Predicate, f.Object, f.FactType, f.Confidence)
+	}
+
+	fmt.Printf("\n🔗 Edges (%d):\n", len(v.Edges))
+	if len(v.Edges) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, e := range v.Edges {
+		other := ""
+		if e.Other != nil {
+			other = fmt.Sprintf("  %s %s %s", e.Other.Subject, e.Other.Predicate, e.Other.Object)
+		}
+		fmt.Printf("  #%d fact #%d -[%s]→ fact #%d (%.0f%%, %s)%s\n",
+			e.ID, e.SourceFactID, e.EdgeType, e.TargetFactID, e.Confidence*100, e.Source, other)
+	}
+
+	fmt.Printf("\n📊 Access history (%d):\n", v.Access.Total)
+	if v.Access.Total == 0 {
+		fmt.Println("  (never accessed)")
+	}
+	for _, a := range v.Access.Recent {
+		agent := ""
+		if a.AgentID != "" {
+			agent = " [" + a.AgentID + "]"
+		}
+		fmt.Printf("  %s  %s fact #%d%s\n", a.CreatedAt.Local().Format("2006-01-02 15:04"), a.AccessType, a.FactID, agent)
+	}
+	if more := v.Access.Total - len(v.Access.Recent); more > 0 {
+		fmt.Printf("  … %d older\n", more)
+	}
+}
+
+func printShowNeighbor(m *store.Memory) {
+	content := strings.ReplaceAll(search.TruncateContent(m.Content, 160), "\n", " ")
+	fmt.Printf("  ┆ #%d %s\n", m.ID, content)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunShow(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlStore := s.(*store.SQLiteStore)
+	var ids []int64
+	for i, content := range []string{"intro", "deploys run on tuesday", "rollback uses the previous tag", "oncall rotates weekly"} {
+		id, err := s.AddMemory(ctx, &store.Memory{Content: content, SourceFile: "/runbook.md", SourceLine: 1 + i*4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	target := ids[1]
+	factID, _ := s.AddFact(ctx, &store.Fact{MemoryID: target, Subject: "deploys", Predicate: "run on", Object: "tuesday", FactType: "temporal"})
+	otherFact, _ := s.AddFact(ctx, &store.Fact{MemoryID: ids[3], Subject: "oncall", Predicate: "rotates", Object: "weekly", FactType: "temporal"})
+	if err := sqlStore.AddEdge(ctx, &store.FactEdge{SourceFactID: otherFact, TargetFactID: factID, EdgeType: store.EdgeTypeRelatesTo, Confidence: 0.7}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sqlStore.RecordFactAccess(ctx, factID, "hawk", store.AccessTypeSearch); err != nil {
+		t.Fatal(err)
+	}
+	if err := sqlStore.AddEmbedding(ctx, target, []float32{0.1, 0.2, 0.3}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	var runErr error
+	out := captureStdout(func() { runErr = runShow([]string{strconv.FormatInt(target, 10), "--context", "1", "--json"}) })
+	if runErr != nil {
+		t.Fatalf("show: %v", runErr)
+	}
+	var view showJSON
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if view.Memory == nil || view.Memory.ID != target {
+		t.Fatalf("memory = %+v", view.Memory)
+	}
+	if view.Context == nil || len(view.Context.Before) != 1 || view.Context.Before[0].ID != ids[0] ||
+		len(view.Context.After) != 1 || view.Context.After[0].ID != ids[2] {
+		t.Fatalf("context = %+v", view.Context)
+	}
+	if len(view.Facts) != 1 || view.Facts[0].ID != factID {
+		t.Fatalf("facts = %+v", view.Facts)
+	}
+	if !view.Embedding.Embedded || view.Embedding.Dimensions != 3 {
+		t.Fatalf("embedding = %+v", view.Embedding)
+	}
+	if len(view.Edges) != 1 || view.Edges[0].Other == nil || view.Edges[0].Other.ID != otherFact {
+		t.Fatalf("edges = %+v", view.Edges)
+	}
+	if view.Access.Total != 1 || len(view.Access.Recent) != 1 || view.Access.Recent[0].AgentID != "hawk" {
+		t.Fatalf("access = %+v", view.Access)
+	}
+
+	// Without --context there is no neighbourhood.
+	out = captureStdout(func() { runErr = runShow([]string{strconv.FormatInt(ids[0], 10), "--json"}) })
+	if runErr != nil || strings.Contains(out, `"context"`) || !strings.Contains(out, `"embedded": false`) {
+		t.Fatalf("plain show = %q, %v", out, runErr)
+	}
+
+	if err := runShow(nil); err == nil {
+		t.Fatal("expected usage error without an ID")
+	}
+	if err := runShow([]string{strconv.FormatInt(target, 10), "--context", "0"}); err == nil || !strings.Contains(err.Error(), "invalid --context") {
+		t.Fatalf("expected invalid --context error, got %v", err)
+	}
+	if err := runShow([]string{"99999"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
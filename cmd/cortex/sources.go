@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const (
+	minSourceTrust = 0.1
+	maxSourceTrust = 3.0
+)
+
+func sourcesUsageText() string {
+	return `Usage: cortex sources <list|stats|set> [flags]
+
+Every memory's source_file resolves to a registered source: a connector
+("github:issue/1" → github), an API capture (mcp-import, webhook), an
+auto-capture hook, or an imported file. search --source accepts a source
+key, its display name, or a type (file, connector, capture, api).
+
+Subcommands:
+  list [--json]         Show the registry: type, display name, trust, default class
+  stats [--json]        Memories, facts, and last import per source
+  set <key> [flags]     Add or change a source
+      --type <type>       file|connector|capture|api (required for new keys)
+      --name <text>       Display name
+      --trust <weight>    Search score multiplier, 0.1-3.0 (1.0 = neutral)
+      --class <class>     Default memory class for imports without one (none clears)
+      --reset             Drop your changes and restore the built-in entry`
+}
+
+func runSources(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(sourcesUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "list":
+		return runSourcesList(args[1:])
+	case "stats":
+		return runSourcesStats(args[1:])
+	case "set":
+		return runSourcesSet(args[1:])
+	default:
+		return fmt.Errorf("unknown sources subcommand: %s", args[0])
+	}
+}
+
+func parseSourcesJSONFlag(args []string) (bool, error) {
+	jsonOutput := false
+	for _, arg := range args {
+		switch {
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return false, fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return false, fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	return jsonOutput, nil
+}
+
+func openSourcesStore() (*store.SQLiteStore, error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("opening store: %w", err)
+	}
+	ss, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, fmt.Errorf("sources requires SQLiteStore")
+	}
+	return ss, nil
+}
+
+func runSourcesList(args []string) error {
+	jsonOutput, err := parseSourcesJSONFlag(args)
+	if err != nil {
+		return err
+	}
+	// Opening the store loads the changes made with `sources set`.
+	ss, err := openSourcesStore()
+	if err != nil {
+		return err
+	}
+	defer ss.Close()
+
+	sources := store.ListSources()
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sources)
+	}
+	fmt.Printf("%-16s  %-10s  %-18s  %5s  %-10s\n", "SOURCE", "TYPE", "NAME", "TRUST", "CLASS")
+	fmt.Println(strings.Repeat("─", 68))
+	for _, src := range sources {
+		class := src.DefaultClass
+		if class == "" {
+			class = "-"
+		}
+		marker := ""
+		if src.Custom {
+			marker = "  (custom)"
+		}
+		fmt.Printf("%-16s  %-10s  %-18s  %5.2f  %-10s%s\n", src.Key, src.Type, truncateString(src.DisplayName, 18), src.TrustWeight, class, marker)
+	}
+	return nil
+}
+
+func runSourcesStats(args []string) error {
+	jsonOutput, err := parseSourcesJSONFlag(args)
+	if err != nil {
+		return err
+	}
+	ss, err := openSourcesStore()
+	if err != nil {
+		return err
+	}
+	defer ss.Close()
+
+	stats, err := ss.SourceStats(context.Background())
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	byType := map[string]int{}
+	fmt.Printf("%-16s  %-10s  %8s  %8s  %6s  %-10s\n", "SOURCE", "TYPE", "MEMORIES", "FACTS", "FILES", "LAST")
+	fmt.Println(strings.Repeat("─", 68))
+	for _, st := range stats {
+		if st.Memories == 0 {
+			continue
+		}
+		byType[st.Type] += st.Memories
+		last := "-"
+		if !st.LastImport.IsZero() {
+			last = st.LastImport.Format("2006-01-02")
+		}
+		fmt.Printf("%-16s  %-10s  %8d  %8d  %6d  %-10s\n", st.Key, st.Type, st.Memories, st.Facts, st.SourceFiles, last)
+	}
+	parts := make([]string, 0, len(store.SourceTypes))
+	for _, t := range store.SourceTypes {
+		parts = append(parts, fmt.Sprintf("%s %d", t, byType[t]))
+	}
+	fmt.Printf("\nBy type: %s\n", strings.Join(parts, ", "))
+	return nil
+}
+
+type sourcesSetOptions struct {
+	key   string
+	typ   *string
+	name  *string
+	trust *float64
+	class *string
+	reset bool
+}
+
+func parseSourcesSetArgs(args []string) (sourcesSetOptions, error) {
+	var opts sourcesSetOptions
+	value := func(i *int, flag string) (string, bool) {
+		arg := args[*i]
+		if strings.HasPrefix(arg, flag+"=") {
+			return strings.TrimPrefix(arg, flag+"="), true
+		}
+		if arg == flag && *i+1 < len(args) {
+			*i++
+			return args[*i], true
+		}
+		return "", false
+	}
+	for i := 0; i < len(args); i++ {
+		if v, ok := value(&i, "--type"); ok {
+			v = store.NormalizeSourceKey(v)
+			if !store.IsSourceType(v) {
+				return opts, fmt.Errorf("invalid --type %q (valid: %s)", v, strings.Join(store.SourceTypes, ", "))
+			}
+			opts.typ = &v
+			continue
+		}
+		if v, ok := value(&i, "--name"); ok {
+			v = strings.TrimSpace(v)
+			opts.name = &v
+			continue
+		}
+		if v, ok := value(&i, "--trust"); ok {
+			w, err := strconv.ParseFloat(v, 64)
+			if err != nil || w < minSourceTrust || w > maxSourceTrust {
+				return opts, fmt.Errorf("--trust must be between %.1f and %.1f (got %s)", minSourceTrust, maxSourceTrust, v)
+			}
+			opts.trust = &w
+			continue
+		}
+		if v, ok := value(&i, "--class"); ok {
+			v = store.NormalizeMemoryClass(v)
+			if v == "none" {
+				v = ""
+			}
+			if v != "" && !store.IsValidMemoryClass(v) {
+				return opts, fmt.Errorf("invalid --class %q", v)
+			}
+			opts.class = &v
+			continue
+		}
+		switch {
+		case args[i] == "--reset":
+			opts.reset = true
+		case strings.HasPrefix(args[i], "-"):
+			return opts, fmt.Errorf("unknown flag: %s", args[i])
+		case opts.key == "":
+			opts.key = store.NormalizeSourceKey(args[i])
+		default:
+			return opts, fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	if opts.key == "" {
+		return opts, fmt.Errorf("usage: cortex sources set <key> [--type T] [--name N] [--trust W] [--class C] [--reset]")
+	}
+	if opts.reset && (opts.typ != nil || opts.name != nil || opts.trust != nil || opts.class != nil) {
+		return opts, fmt.Errorf("--reset cannot be combined with other flags")
+	}
+	if !opts.reset && opts.typ == nil && opts.name == nil && opts.trust == nil && opts.class == nil {
+		return opts, fmt.Errorf("nothing to set: pass --type, --name, --trust, --class, or --reset")
+	}
+	return opts, nil
+}
+
+func runSourcesSet(args []string) error {
+	opts, err := parseSourcesSetArgs(args)
+	if err != nil {
+		return err
+	}
+	ss, err := openSourcesStore()
+	if err != nil {
+		return err
+	}
+	defer ss.Close()
+	ctx := context.Background()
+
+	if opts.reset {
+		removed, err := ss.ResetSource(ctx, opts.key)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return fmt.Errorf("source %q has no changes to reset", opts.key)
+		}
+		if src, ok := store.LookupSource(opts.key); ok {
+			fmt.Printf("Restored built-in source %s (%s, trust %.2f)\n", src.Key, src.Type, src.TrustWeight)
+		} else {
+			fmt.Printf("Removed source %s\n", opts.key)
+		}
+		return nil
+	}
+
+	src, exists := store.LookupSource(opts.key)
+	if exists && src.Key != opts.key {
+		// Matched by display name; edit that entry.
+		opts.key = src.Key
+	}
+	if !exists {
+		if opts.typ == nil {
+			return fmt.Errorf("unknown source %q: pass --type to add it", opts.key)
+		}
+		src = store.Source{Key: opts.key, DisplayName: opts.key, TrustWeight: store.DefaultSourceTrustWeight}
+	}
+	if opts.typ != nil {
+		src.Type = *opts.typ
+	}
+	if opts.name != nil {
+		src.DisplayName = *opts.name
+	}
+	if opts.trust != nil {
+		src.TrustWeight = *opts.trust
+	}
+	if opts.class != nil {
+		src.DefaultClass = *opts.class
+	}
+	if err := ss.SetSource(ctx, src); err != nil {
+		return err
+	}
+	class := src.DefaultClass
+	if class == "" {
+		class = "none"
+	}
+	fmt.Printf("Saved source %s: type %s, name %q, trust %.2f, default class %s\n", src.Key, src.Type, src.DisplayName, src.TrustWeight, class)
+	return nil
+}
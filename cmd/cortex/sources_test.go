@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestParseSourcesSetArgs(t *testing.T) {
+	opts, err := parseSourcesSetArgs([]string{"Knowledge", "--type", "file", "--name=Knowledge base", "--trust", "1.4", "--class", "none"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if opts.key != "knowledge" || *opts.typ != "file" || *opts.name != "Knowledge base" || *opts.trust != 1.4 || *opts.class != "" {
+		t.Fatalf("unexpected opts: %+v", opts)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"github"},
+		{"github", "--trust", "5"},
+		{"github", "--trust", "abc"},
+		{"github", "--type", "cloud"},
+		{"github", "--class", "nonsense"},
+		{"github", "--reset", "--trust", "1.2"},
+		{"github", "--bogus"},
+		{"github", "slack", "--trust", "1"},
+	} {
+		if _, err := parseSourcesSetArgs(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestRunSources_SetListStats(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	s.AddMemory(ctx, &store.Memory{Content: "issue", SourceFile: "github:issues/1", ContentHash: "a"})
+	s.AddMemory(ctx, &store.Memory{Content: "runbook", SourceFile: "runbooks/deploy.md", ContentHash: "b"})
+	s.Close()
+
+	if err := runSources([]string{"set", "runbooks"}); err == nil || !strings.Contains(err.Error(), "nothing to set") {
+		t.Fatalf("expected nothing-to-set error, got %v", err)
+	}
+	if err := runSources([]string{"set", "runbooks", "--trust", "1.2"}); err == nil || !strings.Contains(err.Error(), "--type") {
+		t.Fatalf("new source without --type should fail, got %v", err)
+	}
+	out := captureStdout(func() {
+		if err := runSources([]string{"set", "runbooks", "--type", "file", "--name", "Runbooks", "--trust", "1.2", "--class", "rule"}); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+		if err := runSources([]string{"set", "GitHub", "--trust", "0.9"}); err != nil {
+			t.Fatalf("set by display name: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Saved source runbooks: type file") || !strings.Contains(out, "Saved source github: type connector") {
+		t.Fatalf("unexpected set output:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runSources([]string{"list"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	for _, want := range []string{"runbooks", "Runbooks", "1.20", "rule", "(custom)", "homeassistant"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("list output missing %q:\n%s", want, out)
+		}
+	}
+
+	out = captureStdout(func() {
+		if err := runSources([]string{"stats"}); err != nil {
+			t.Fatalf("stats: %v", err)
+		}
+	})
+	if !strings.Contains(out, "runbooks") || !strings.Contains(out, "By type: file 1, connector 1, capture 0, api 0") {
+		t.Errorf("unexpected stats output:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runSources([]string{"set", "github", "--reset"}); err != nil {
+			t.Fatalf("reset: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Restored built-in source github") {
+		t.Errorf("unexpected reset output:\n%s", out)
+	}
+	if err := runSources([]string{"set", "github", "--reset"}); err == nil {
+		t.Error("resetting an unchanged source should fail")
+	}
+}
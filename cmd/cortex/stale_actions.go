@@ -0,0 +1,451 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/observe"
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// Stale remediation actions (cortex stale --action ...) close the loop from
+// detection to action:
+//
+//   - reinforce: bump facts the operator confirms are still true, so they
+//     stop decaying (confirmed per fact, or up front with --ids / --yes)
+//   - supersede-prompt: build one prompt per fact asking whether it still
+//     holds, with evidence from a search over newer memories; with --llm the
+//     prompts are answered, and --apply reinforces facts that hold and
+//     supersedes outdated ones with the model's replacement
+//   - archive: retire what is left
+const (
+	staleActionReinforce       = "reinforce"
+	staleActionSupersedePrompt = "supersede-prompt"
+	staleActionArchive         = "archive"
+
+	staleEvidenceLimit = 3
+)
+
+// staleActionInput is where interactive confirmations are read from.
+var staleActionInput io.Reader = os.Stdin
+
+type staleActionOpts struct {
+	Action string
+	IDs    map[int64]bool // only act on these stale facts (operator-confirmed for reinforce)
+	Yes    bool           // skip confirmation
+	LLM    string         // supersede-prompt: answer the prompts with this model
+	Apply  bool           // supersede-prompt: act on the answers
+	DryRun bool
+	JSON   bool
+}
+
+func parseStaleAction(v string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case staleActionReinforce:
+		return staleActionReinforce, nil
+	case staleActionSupersedePrompt, "prompt":
+		return staleActionSupersedePrompt, nil
+	case staleActionArchive, "retire":
+		return staleActionArchive, nil
+	}
+	return "", fmt.Errorf("invalid --action %q (expected reinforce, supersede-prompt, or archive)", v)
+}
+
+func parseStaleIDs(v string) (map[int64]bool, error) {
+	ids := map[int64]bool{}
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimPrefix(strings.TrimSpace(part), "#")
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid fact id in --ids: %s", part)
+		}
+		ids[id] = true
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("--ids needs at least one fact id")
+	}
+	return ids, nil
+}
+
+// staleActionResult reports what a remediation action did.
+type staleActionResult struct {
+	Action     string          `json:"action"`
+	DryRun     bool            `json:"dry_run,omitempty"`
+	Considered int             `json:"considered"`
+	Reinforced []int64         `json:"reinforced,omitempty"`
+	Archived   []int64         `json:"archived,omitempty"`
+	Superseded []staleRevision `json:"superseded,omitempty"`
+	Skipped    int             `json:"skipped,omitempty"`
+	Prompts    []stalePrompt   `json:"prompts,omitempty"`
+	Errors     []string        `json:"errors,omitempty"`
+}
+
+// staleRevision is an outdated fact replaced by the model's correction.
+type staleRevision struct {
+	OldFactID int64  `json:"old_fact_id"`
+	NewFactID int64  `json:"new_fact_id,omitempty"`
+	Object    string `json:"object"`
+}
+
+// stalePrompt is one "does this still hold?" question, with its answer when
+// an LLM was given.
+type stalePrompt struct {
+	FactID   int64         `json:"fact_id"`
+	Fact     string        `json:"fact"`
+	Evidence []string      `json:"evidence,omitempty"`
+	Prompt   string        `json:"prompt"`
+	Verdict  *staleVerdict `json:"verdict,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	fact     *store.Fact
+}
+
+// staleVerdict is the model's answer to a stale prompt.
+type staleVerdict struct {
+	Verdict     string `json:"verdict"` // holds | outdated | unsure
+	Replacement string `json:"replacement,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+func runStaleAction(ctx context.Context, s store.Store, staleFacts []observe.StaleFact, opts staleActionOpts) error {
+	if globalReadOnly && (opts.Action != staleActionSupersedePrompt || opts.Apply) && !opts.DryRun {
+		return fmt.Errorf("stale --action %s is not available in --read-only mode", opts.Action)
+	}
+	targets := staleFacts[:0:0]
+	for _, sf := range staleFacts {
+		if opts.IDs == nil || opts.IDs[sf.Fact.ID] {
+			targets = append(targets, sf)
+		}
+	}
+	if opts.IDs != nil && len(targets) < len(opts.IDs) {
+		fmt.Fprintf(os.Stderr, "note: %d of the --ids are not stale under the current filter and were skipped\n", len(opts.IDs)-len(targets))
+	}
+	res := &staleActionResult{Action: opts.Action, DryRun: opts.DryRun, Considered: len(targets)}
+
+	var err error
+	switch opts.Action {
+	case staleActionReinforce:
+		err = staleReinforce(ctx, s, targets, opts, res)
+	case staleActionArchive:
+		err = staleArchive(ctx, s, targets, opts, res)
+	case staleActionSupersedePrompt:
+		err = staleSupersedePrompt(ctx, s, targets, opts, res)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.JSON || !isTTY() {
+		data, _ := json.MarshalIndent(res, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	printStaleActionResult(res)
+	return nil
+}
+
+func staleReinforce(ctx context.Context, s store.Store, targets []observe.StaleFact, opts staleActionOpts, res *staleActionResult) error {
+	// --ids are the operator's confirmation; otherwise ask per fact.
+	confirmEach := opts.IDs == nil && !opts.Yes
+	if confirmEach && !isTTY() && staleActionInput == os.Stdin {
+		return fmt.Errorf("stale --action reinforce needs --ids or --yes when not interactive")
+	}
+	reader := bufio.NewReader(staleActionInput)
+	for _, sf := range targets {
+		if confirmEach {
+			fmt.Fprintf(os.Stderr, "#%d  %q (%d days old)\n    Still true? [y/N/q]: ", sf.Fact.ID, staleFactText(&sf.Fact), sf.DaysSinceReinforced)
+			line, err := reader.ReadString('\n')
+			answer := strings.ToLower(strings.TrimSpace(line))
+			if answer == "q" || answer == "quit" || (err != nil && answer == "") {
+				break
+			}
+			if answer != "y" && answer != "yes" {
+				res.Skipped++
+				continue
+			}
+		}
+		if opts.DryRun {
+			res.Reinforced = append(res.Reinforced, sf.Fact.ID)
+			continue
+		}
+		if err := s.ReinforceFact(ctx, sf.Fact.ID); err != nil {
+			res.Errors = append(res.Errors, fmt.Sprintf("fact %d: %v", sf.Fact.ID, err))
+			continue
+		}
+		res.Reinforced = append(res.Reinforced, sf.Fact.ID)
+	}
+	return nil
+}
+
+func staleArchive(ctx context.Context, s store.Store, targets []observe.StaleFact, opts staleActionOpts, res *staleActionResult) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	if !opts.Yes && !opts.DryRun {
+		if !isTTY() && staleActionInput == os.Stdin {
+			return fmt.Errorf("stale --action archive needs --yes when not interactive")
+		}
+		fmt.Fprintf(os.Stderr, "Archive %d stale %s (state=retired)? [y/N]: ", len(targets), pluralize("fact", "facts", len(targets)))
+		line, _ := bufio.NewReader(staleActionInput).ReadString('\n')
+		if answer := strings.ToLower(strings.TrimSpace(line)); answer != "y" && answer != "yes" {
+			res.Skipped = len(targets)
+			return nil
+		}
+	}
+	for _, sf := range targets {
+		if opts.DryRun {
+			res.Archived = append(res.Archived, sf.Fact.ID)
+			continue
+		}
+		if err := s.UpdateFactState(ctx, sf.Fact.ID, store.FactStateRetired); err != nil {
+			res.Errors = append(res.Errors, fmt.Sprintf("fact %d: %v", sf.Fact.ID, err))
+			continue
+		}
+		res.Archived = append(res.Archived, sf.Fact.ID)
+	}
+	return nil
+}
+
+const staleVerdictSystem = `You check whether a remembered fact is still true.
+Answer with JSON only: {"verdict":"holds|outdated|unsure","replacement":"<new object value if outdated and the evidence says what it is now, else empty>","reason":"<one sentence>"}.
+Say "outdated" only when the evidence contradicts the fact; with no evidence either way, say "unsure".`
+
+func staleSupersedePrompt(ctx context.Context, s store.Store, targets []observe.StaleFact, opts staleActionOpts, res *staleActionResult) error {
+	if opts.Apply && opts.LLM == "" {
+		return fmt.Errorf("--apply needs --llm <provider/model> to answer the prompts")
+	}
+	engine := search.NewEngine(s)
+	for _, sf := range targets {
+		f := sf.Fact
+		p := stalePrompt{FactID: f.ID, Fact: staleFactText(&f), fact: &f}
+		p.Evidence = staleEvidence(ctx, engine, &f)
+		p.Prompt = buildStalePrompt(&f, sf.DaysSinceReinforced, p.Evidence)
+		res.Prompts = append(res.Prompts, p)
+	}
+	if opts.LLM == "" || len(res.Prompts) == 0 {
+		return nil
+	}
+
+	llmCfg, err := llm.ParseLLMFlag(opts.LLM)
+	if err != nil {
+		return fmt.Errorf("parsing --llm: %w", err)
+	}
+	provider, err := llm.NewProvider(llmCfg)
+	if err != nil {
+		return fmt.Errorf("creating LLM provider: %w", err)
+	}
+	for i := range res.Prompts {
+		p := &res.Prompts[i]
+		callCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		raw, err := provider.Complete(callCtx, p.Prompt, llm.CompletionOpts{
+			System:      staleVerdictSystem,
+			Temperature: 0,
+			MaxTokens:   256,
+			Format:      "json",
+			Task:        llm.TaskClassify,
+		})
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			p.Error = err.Error()
+			continue
+		}
+		v, err := parseStaleVerdict(raw)
+		if err != nil {
+			p.Error = err.Error()
+			continue
+		}
+		p.Verdict = v
+		if opts.Apply {
+			applyStaleVerdict(ctx, s, p, opts.DryRun, res)
+		}
+	}
+	return nil
+}
+
+// applyStaleVerdict reinforces a fact that holds and supersedes an outdated
+// one with its replacement. Outdated facts without a replacement and unsure
+// answers are left for the operator.
+func applyStaleVerdict(ctx context.Context, s store.Store, p *stalePrompt, dryRun bool, res *staleActionResult) {
+	switch {
+	case p.Verdict.Verdict == "holds":
+		if !dryRun {
+			if err := s.ReinforceFact(ctx, p.FactID); err != nil {
+				res.Errors = append(res.Errors, fmt.Sprintf("fact %d: %v", p.FactID, err))
+				return
+			}
+		}
+		res.Reinforced = append(res.Reinforced, p.FactID)
+	case p.Verdict.Verdict == "outdated" && p.Verdict.Replacement != "":
+		rev := staleRevision{OldFactID: p.FactID, Object: p.Verdict.Replacement}
+		if !dryRun {
+			old := p.fact
+			newID, err := s.AddFact(ctx, &store.Fact{
+				MemoryID:    old.MemoryID,
+				Subject:     old.Subject,
+				Predicate:   old.Predicate,
+				Object:      p.Verdict.Replacement,
+				FactType:    old.FactType,
+				Confidence:  old.Confidence,
+				SourceQuote: p.Verdict.Reason,
+				AgentID:     old.AgentID,
+			})
+			if err != nil {
+				res.Errors = append(res.Errors, fmt.Sprintf("fact %d: %v", p.FactID, err))
+				return
+			}
+			reason := "stale: " + p.Verdict.Reason
+			if sqlStore, ok := s.(*store.SQLiteStore); ok {
+				err = sqlStore.SupersedeFactWithSource(ctx, p.FactID, newID, reason, "stale")
+			} else {
+				err = s.SupersedeFact(ctx, p.FactID, newID, reason)
+			}
+			if err != nil {
+				res.Errors = append(res.Errors, fmt.Sprintf("fact %d: %v", p.FactID, err))
+				return
+			}
+			rev.NewFactID = newID
+		}
+		res.Superseded = append(res.Superseded, rev)
+	default:
+		res.Skipped++
+	}
+}
+
+func parseStaleVerdict(raw string) (*staleVerdict, error) {
+	cleaned := strings.TrimSpace(raw)
+	if start, end := strings.Index(cleaned, "{"), strings.LastIndex(cleaned, "}"); start >= 0 && end > start {
+		cleaned = cleaned[start : end+1]
+	}
+	var v staleVerdict
+	if err := json.Unmarshal([]byte(cleaned), &v); err != nil {
+		return nil, fmt.Errorf("invalid JSON from LLM: %w", err)
+	}
+	v.Verdict = strings.ToLower(strings.TrimSpace(v.Verdict))
+	switch v.Verdict {
+	case "holds", "outdated", "unsure":
+	default:
+		v.Verdict = "unsure"
+	}
+	v.Replacement = strings.TrimSpace(v.Replacement)
+	v.Reason = strings.TrimSpace(v.Reason)
+	return &v, nil
+}
+
+// staleEvidence returns snippets of the best keyword matches for the fact,
+// skipping the memory it was extracted from.
+func staleEvidence(ctx context.Context, engine *search.Engine, f *store.Fact) []string {
+	query := strings.TrimSpace(f.Subject + " " + f.Predicate)
+	if query == "" {
+		query = f.Object
+	}
+	results, err := engine.Search(ctx, query, search.Options{Mode: search.ModeKeyword, Limit: staleEvidenceLimit + 1})
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, r := range results {
+		if r.MemoryID == f.MemoryID || len(out) >= staleEvidenceLimit {
+			continue
+		}
+		text := r.Snippet
+		if text == "" {
+			text = truncStr(strings.Join(strings.Fields(r.Content), " "), 280)
+		}
+		date := ""
+		if !r.ImportedAt.IsZero() {
+			date = r.ImportedAt.Format("2006-01-02") + ", "
+		}
+		out = append(out, fmt.Sprintf("[%s%s] %s", date, r.SourceFile, text))
+	}
+	return out
+}
+
+func buildStalePrompt(f *store.Fact, days int, evidence []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Fact #%d, last confirmed %d days ago:\n  %s\n", f.ID, days, staleFactText(f))
+	if f.SourceQuote != "" {
+		fmt.Fprintf(&b, "Original source: %q\n", f.SourceQuote)
+	}
+	if len(evidence) == 0 {
+		b.WriteString("\nNo other memories mention it.\n")
+	} else {
+		b.WriteString("\nEvidence from other memories:\n")
+		for _, e := range evidence {
+			fmt.Fprintf(&b, "- %s\n", e)
+		}
+	}
+	b.WriteString("\nDoes this fact still hold?")
+	return b.String()
+}
+
+func staleFactText(f *store.Fact) string {
+	if f.Subject != "" {
+		return fmt.Sprintf("%s %s %s", f.Subject, f.Predicate, f.Object)
+	}
+	return fmt.Sprintf("%s: %s", f.Predicate, f.Object)
+}
+
+func printStaleActionResult(res *staleActionResult) {
+	prefix := ""
+	if res.DryRun {
+		prefix = "[dry run] "
+	}
+	if res.Action == staleActionSupersedePrompt && len(res.Reinforced)+len(res.Superseded) == 0 {
+		for _, p := range res.Prompts {
+			fmt.Printf("── fact #%d ──\n%s\n", p.FactID, p.Prompt)
+			switch {
+			case p.Verdict != nil:
+				fmt.Printf("→ %s", p.Verdict.Verdict)
+				if p.Verdict.Replacement != "" {
+					fmt.Printf(" (now: %s)", p.Verdict.Replacement)
+				}
+				if p.Verdict.Reason != "" {
+					fmt.Printf(" — %s", p.Verdict.Reason)
+				}
+				fmt.Println()
+			case p.Error != "":
+				fmt.Printf("→ error: %s\n", p.Error)
+			}
+			fmt.Println()
+		}
+		if len(res.Prompts) == 0 {
+			fmt.Println("No stale facts to check.")
+		}
+		return
+	}
+	if len(res.Reinforced) > 0 {
+		fmt.Printf("%s✅ Reinforced %d %s\n", prefix, len(res.Reinforced), pluralize("fact", "facts", len(res.Reinforced)))
+	}
+	if len(res.Superseded) > 0 {
+		fmt.Printf("%s🔁 Superseded %d outdated %s\n", prefix, len(res.Superseded), pluralize("fact", "facts", len(res.Superseded)))
+		for _, r := range res.Superseded {
+			fmt.Printf("   #%d → %q\n", r.OldFactID, r.Object)
+		}
+	}
+	if len(res.Archived) > 0 {
+		fmt.Printf("%s🗄️  Archived %d %s\n", prefix, len(res.Archived), pluralize("fact", "facts", len(res.Archived)))
+	}
+	if res.Skipped > 0 {
+		fmt.Printf("   %d left as is\n", res.Skipped)
+	}
+	if res.Considered == 0 {
+		fmt.Println("No stale facts matched.")
+	}
+	for _, e := range res.Errors {
+		fmt.Printf("   ⚠️  %s\n", e)
+	}
+}
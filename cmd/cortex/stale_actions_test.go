@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func openStaleActionDB(t *testing.T, factCount int) {
+	t.Helper()
+	dbPath := seedStaleFactsDB(t, factCount)
+	globalDBPath = ""
+	t.Cleanup(func() { globalDBPath = "" })
+	t.Setenv("CORTEX_DB", dbPath)
+}
+
+func runStaleActionJSON(t *testing.T, args ...string) staleActionResult {
+	t.Helper()
+	out := captureStdout(func() {
+		if err := runStale(append([]string{"--json", "--days", "1", "--min-confidence", "1.0"}, args...)); err != nil {
+			t.Fatalf("runStale %v: %v", args, err)
+		}
+	})
+	var res staleActionResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("unmarshal action output: %v\noutput=%s", err, out)
+	}
+	return res
+}
+
+func TestRunStale_ActionReinforceThenArchive(t *testing.T) {
+	openStaleActionDB(t, 4)
+
+	res := runStaleActionJSON(t, "--action", "reinforce", "--ids", "1,3")
+	if len(res.Reinforced) != 2 || res.Considered != 2 {
+		t.Fatalf("expected facts 1 and 3 reinforced, got %+v", res)
+	}
+
+	// Reinforced facts are no longer stale, so archive takes the rest.
+	res = runStaleActionJSON(t, "--action", "archive", "--yes")
+	if len(res.Archived) != 2 || res.Archived[0] == 1 || res.Archived[1] == 3 {
+		t.Fatalf("expected facts 2 and 4 archived, got %+v", res)
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if f, _ := s.GetFact(context.Background(), 2); f.State != store.FactStateRetired {
+		t.Fatalf("expected fact 2 retired, got %q", f.State)
+	}
+	if f, _ := s.GetFact(context.Background(), 1); f.State == store.FactStateRetired {
+		t.Fatal("reinforced fact must not be archived")
+	}
+}
+
+func TestRunStale_ActionReinforceInteractive(t *testing.T) {
+	openStaleActionDB(t, 3)
+	oldInput := staleActionInput
+	staleActionInput = strings.NewReader("y\nn\nq\n")
+	t.Cleanup(func() { staleActionInput = oldInput })
+
+	res := runStaleActionJSON(t, "--action", "reinforce")
+	if len(res.Reinforced) != 1 || res.Skipped != 1 {
+		t.Fatalf("expected one confirmed, one declined, then quit: %+v", res)
+	}
+}
+
+func TestRunStale_ActionSupersedePromptWithoutLLM(t *testing.T) {
+	openStaleActionDB(t, 2)
+
+	res := runStaleActionJSON(t, "--action", "supersede-prompt")
+	if len(res.Prompts) != 2 {
+		t.Fatalf("expected a prompt per stale fact, got %+v", res)
+	}
+	if p := res.Prompts[0]; !strings.Contains(p.Prompt, p.Fact) || !strings.Contains(p.Prompt, "still hold") {
+		t.Fatalf("unexpected prompt: %q", p.Prompt)
+	}
+
+	if err := runStale([]string{"--action", "supersede-prompt", "--apply"}); err == nil || !strings.Contains(err.Error(), "--apply needs --llm") {
+		t.Fatalf("expected --apply to require --llm, got %v", err)
+	}
+	if err := runStale([]string{"--yes"}); err == nil || !strings.Contains(err.Error(), "need --action") {
+		t.Fatalf("expected --yes without --action to fail, got %v", err)
+	}
+	if err := runStale([]string{"--action", "delete"}); err == nil || !strings.Contains(err.Error(), "invalid --action") {
+		t.Fatalf("expected invalid action error, got %v", err)
+	}
+}
+
+func TestApplyStaleVerdict(t *testing.T) {
+	openStaleActionDB(t, 3)
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+
+	res := &staleActionResult{}
+	for id, raw := range map[int64]string{
+		1: `{"verdict":"holds","reason":"still in the runbook"}`,
+		2: "```json\n{\"verdict\":\"outdated\",\"replacement\":\"archived\",\"reason\":\"moved\"}\n```",
+		3: `{"verdict":"maybe"}`,
+	} {
+		v, err := parseStaleVerdict(raw)
+		if err != nil {
+			t.Fatalf("parseStaleVerdict(%q): %v", raw, err)
+		}
+		f, _ := s.GetFact(ctx, id)
+		applyStaleVerdict(ctx, s, &stalePrompt{FactID: id, Verdict: v, fact: f}, false, res)
+	}
+	if len(res.Reinforced) != 1 || len(res.Superseded) != 1 || res.Skipped != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	old, _ := s.GetFact(ctx, 2)
+	if old.SupersededBy == nil || *old.SupersededBy != res.Superseded[0].NewFactID {
+		t.Fatalf("expected fact 2 superseded by the replacement, got %+v", old)
+	}
+	if repl, _ := s.GetFact(ctx, res.Superseded[0].NewFactID); repl.Object != "archived" || repl.Subject != old.Subject {
+		t.Fatalf("unexpected replacement fact: %+v", repl)
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// batchInput is where --stdin commands read from; tests swap it out.
+var batchInput io.Reader = os.Stdin
+
+// batchLine is one non-blank, non-comment line of --stdin input.
+type batchLine struct {
+	Num  int
+	Text string
+}
+
+// readBatchLines reads --stdin input. Blank lines and lines starting with #
+// are skipped so annotated ID lists can be piped in as-is.
+func readBatchLines(r io.Reader) ([]batchLine, error) {
+	var lines []batchLine
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	num := 0
+	for scanner.Scan() {
+		num++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		lines = append(lines, batchLine{Num: num, Text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading stdin: %w", err)
+	}
+	return lines, nil
+}
+
+// batchFields splits a line on whitespace, commas, and JSON brackets or
+// quotes, so `jq` output such as `[12, 15]` or `"12"` parses like `12 15`.
+func batchFields(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == '[' || r == ']' || r == '"'
+	})
+}
+
+// batchIDs parses every field on a line as a positive ID.
+func batchIDs(line batchLine) ([]int64, error) {
+	fields := batchFields(line.Text)
+	ids := make([]int64, 0, len(fields))
+	for _, f := range fields {
+		id, err := strconv.ParseInt(f, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid ID %q", f)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// batchReport collects per-line failures for a --stdin run.
+type batchReport struct {
+	Done   int
+	Failed int
+}
+
+func (b *batchReport) fail(line batchLine, err error) {
+	b.Failed++
+	fmt.Fprintf(os.Stderr, "  line %d: %v\n", line.Num, err)
+}
+
+// finish prints the summary, with detail appended when set, and returns an
+// error when any line failed so pipelines see a non-zero exit.
+func (b *batchReport) finish(verb, one, many, detail string) error {
+	fmt.Printf("%s %d %s from stdin%s", verb, b.Done, pluralize(one, many, b.Done), detail)
+	if b.Failed > 0 {
+		fmt.Printf(" (%d %s failed)", b.Failed, pluralize("line", "lines", b.Failed))
+	}
+	fmt.Println()
+	if b.Failed > 0 {
+		return fmt.Errorf("%d %s of input failed", b.Failed, pluralize("line", "lines", b.Failed))
+	}
+	return nil
+}
+
+// runReinforceStdin reinforces fact IDs read one or more per line.
+func runReinforceStdin(ctx context.Context, s store.Store) error {
+	lines, err := readBatchLines(batchInput)
+	if err != nil {
+		return err
+	}
+	var report batchReport
+	for _, line := range lines {
+		ids, err := batchIDs(line)
+		if err != nil {
+			report.fail(line, err)
+			continue
+		}
+		for _, id := range ids {
+			if err := s.ReinforceFact(ctx, id); err != nil {
+				report.fail(line, err)
+				continue
+			}
+			report.Done++
+		}
+	}
+	return report.finish("Reinforced", "fact", "facts", "")
+}
+
+// runSupersedeStdin reads "<old_id> <new_id> [reason]" pairs, one per line.
+// defaultReason applies to lines without their own.
+func runSupersedeStdin(ctx context.Context, s store.Store, defaultReason string) error {
+	lines, err := readBatchLines(batchInput)
+	if err != nil {
+		return err
+	}
+	var report batchReport
+	for _, line := range lines {
+		oldID, newID, reason, err := parseSupersedeLine(line.Text)
+		if err != nil {
+			report.fail(line, err)
+			continue
+		}
+		if reason == "" {
+			reason = defaultReason
+		}
+		if err := s.SupersedeFact(ctx, oldID, newID, reason); err != nil {
+			report.fail(line, err)
+			continue
+		}
+		report.Done++
+	}
+	return report.finish("Superseded", "fact", "facts", "")
+}
+
+// parseSupersedeLine splits "12 15 merged duplicate" or "12,15" into the
+// old and new fact IDs and an optional free-text reason.
+func parseSupersedeLine(text string) (oldID, newID int64, reason string, err error) {
+	rest := text
+	var ids [2]int64
+	for i := range ids {
+		rest = strings.TrimLeftFunc(rest, func(r rune) bool { return unicode.IsSpace(r) || r == ',' })
+		end := strings.IndexFunc(rest, func(r rune) bool { return unicode.IsSpace(r) || r == ',' })
+		if end < 0 {
+			end = len(rest)
+		}
+		token := rest[:end]
+		if token == "" {
+			return 0, 0, "", fmt.Errorf("expected <old_fact_id> <new_fact_id> [reason]")
+		}
+		id, err := strconv.ParseInt(token, 10, 64)
+		if err != nil || id <= 0 {
+			return 0, 0, "", fmt.Errorf("invalid fact ID %q", token)
+		}
+		ids[i] = id
+		rest = rest[end:]
+	}
+	reason = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(rest), ","))
+	return ids[0], ids[1], reason, nil
+}
+
+// runTagStdin assigns project to memory IDs read one or more per line.
+func runTagStdin(ctx context.Context, s store.Store, project string) error {
+	lines, err := readBatchLines(batchInput)
+	if err != nil {
+		return err
+	}
+	var report batchReport
+	for _, line := range lines {
+		ids, err := batchIDs(line)
+		if err != nil {
+			report.fail(line, err)
+			continue
+		}
+		for _, id := range ids {
+			n, err := s.TagMemories(ctx, project, []int64{id})
+			if err != nil {
+				report.fail(line, err)
+				continue
+			}
+			if n == 0 {
+				report.fail(line, fmt.Errorf("memory %d not found", id))
+				continue
+			}
+			report.Done++
+		}
+	}
+	return report.finish("Tagged", "memory", "memories", fmt.Sprintf(" → project %q", project))
+}
+
+// runDelete handles `cortex delete`, which soft-deletes memories so they
+// drop out of search and listings. Filters, --facts, --purge, and --dry-run
+// go through runBatchDelete.
+func runDelete(args []string) error {
+	fromStdin := false
+	var ids []int64
+	var batch batchDeleteArgs
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		consumed, err := batch.parse(args, &i)
+		if err != nil {
+			return err
+		}
+		switch {
+		case consumed:
+		case arg == "--help" || arg == "-h":
+			fmt.Println(deleteUsageText())
+			return nil
+		case arg == "--stdin":
+			fromStdin = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			id, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil || id <= 0 {
+				return fmt.Errorf("invalid id: %s", arg)
+			}
+			ids = append(ids, id)
+		}
+	}
+	if fromStdin && len(ids) > 0 {
+		return fmt.Errorf("pass memory IDs as arguments or with --stdin, not both")
+	}
+	if fromStdin && batch.set() {
+		return fmt.Errorf("--stdin cannot be combined with filters, --facts, --purge, or --dry-run")
+	}
+	if batch.set() {
+		return runBatchDelete(ids, batch)
+	}
+	if !fromStdin && len(ids) == 0 {
+		return fmt.Errorf("usage: cortex delete <memory_id> [memory_id...] | cortex delete --stdin | cortex delete [filters] [--dry-run] (see --help)")
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+
+	if !fromStdin {
+		for _, id := range ids {
+			if err := s.DeleteMemory(ctx, id); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Deleted %d %s\n", len(ids), pluralize("memory", "memories", len(ids)))
+		return nil
+	}
+
+	lines, err := readBatchLines(batchInput)
+	if err != nil {
+		return err
+	}
+	var report batchReport
+	for _, line := range lines {
+		lineIDs, err := batchIDs(line)
+		if err != nil {
+			report.fail(line, err)
+			continue
+		}
+		for _, id := range lineIDs {
+			if err := s.DeleteMemory(ctx, id); err != nil {
+				report.fail(line, err)
+				continue
+			}
+			report.Done++
+		}
+	}
+	return report.finish("Deleted", "memory", "memories", "")
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func withBatchInput(t *testing.T, input string) {
+	t.Helper()
+	old := batchInput
+	batchInput = strings.NewReader(input)
+	t.Cleanup(func() { batchInput = old })
+}
+
+func TestStdinBatch_ReinforceSupersedeTagDelete(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	var memIDs, factIDs []int64
+	for i := 0; i < 3; i++ {
+		memID, err := s.AddMemory(ctx, &store.Memory{Content: fmt.Sprintf("batch memory %d", i), SourceFile: "batch.md", SourceLine: i + 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		memIDs = append(memIDs, memID)
+		factID, err := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "batch", Predicate: "step", Object: fmt.Sprint(i), FactType: "kv", Confidence: 0.8})
+		if err != nil {
+			t.Fatal(err)
+		}
+		factIDs = append(factIDs, factID)
+	}
+	s.Close()
+
+	withBatchInput(t, fmt.Sprintf("# from jq\n%d\n[%d, %d]\n\nnot-an-id\n999999\n", factIDs[0], factIDs[1], factIDs[2]))
+	var runErr error
+	out := captureStdout(func() { runErr = runReinforce([]string{"--stdin"}) })
+	if !strings.Contains(out, "Reinforced 3 facts from stdin (2 lines failed)") {
+		t.Fatalf("unexpected reinforce summary: %q", out)
+	}
+	if runErr == nil || !strings.Contains(runErr.Error(), "2 lines") {
+		t.Fatalf("expected failed-lines error, got %v", runErr)
+	}
+
+	withBatchInput(t, fmt.Sprintf("%d %d duplicate wording\n%d,%d\n", factIDs[0], factIDs[1], factIDs[2], factIDs[1]))
+	out = captureStdout(func() { runErr = runSupersede([]string{"--stdin", "--reason", "batch cleanup"}) })
+	if runErr != nil || !strings.Contains(out, "Superseded 2 facts from stdin") {
+		t.Fatalf("supersede: err=%v out=%q", runErr, out)
+	}
+
+	withBatchInput(t, fmt.Sprintf("%d\n%d\n", memIDs[0], memIDs[1]))
+	out = captureStdout(func() { runErr = runTag([]string{"--project", "ops", "--stdin"}) })
+	if runErr != nil || !strings.Contains(out, `Tagged 2 memories from stdin → project "ops"`) {
+		t.Fatalf("tag: err=%v out=%q", runErr, out)
+	}
+
+	withBatchInput(t, fmt.Sprintf("%d\n", memIDs[2]))
+	out = captureStdout(func() { runErr = runDelete([]string{"--stdin"}) })
+	if runErr != nil || !strings.Contains(out, "Deleted 1 memory from stdin") {
+		t.Fatalf("delete: err=%v out=%q", runErr, out)
+	}
+
+	s, err = store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	old, err := s.GetFact(ctx, factIDs[0])
+	if err != nil || old.SupersededBy == nil || *old.SupersededBy != factIDs[1] {
+		t.Fatalf("fact %d not superseded: %+v", factIDs[0], old)
+	}
+	tagged, err := s.GetMemory(ctx, memIDs[1])
+	if err != nil || tagged.Project != "ops" {
+		t.Fatalf("memory %d not tagged: %+v", memIDs[1], tagged)
+	}
+	if deleted, _ := s.GetMemory(ctx, memIDs[2]); deleted == nil || deleted.DeletedAt == nil {
+		t.Fatalf("memory %d not soft-deleted: %+v", memIDs[2], deleted)
+	}
+}
+
+func TestParseSupersedeLine(t *testing.T) {
+	oldID, newID, reason, err := parseSupersedeLine("12, 15, merged duplicate")
+	if err != nil || oldID != 12 || newID != 15 || reason != "merged duplicate" {
+		t.Fatalf("got %d %d %q %v", oldID, newID, reason, err)
+	}
+	if _, _, _, err := parseSupersedeLine("12"); err == nil {
+		t.Fatal("expected error for a single ID")
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunSummarizeUndo(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "deploy notes"})
+	a, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "uses", Object: "fly.io", FactType: "kv"})
+	b, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "region", Object: "iad", FactType: "kv"})
+	if _, err := s.(*store.SQLiteStore).ApplyClusterSummary(ctx, 5, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "setup", Object: "fly.io iad", FactType: "kv"}, []int64{a, b}, ""); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	if err := runSummarize([]string{"undo"}); err == nil || !strings.Contains(err.Error(), "usage: cortex summarize undo") {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+	if err := runSummarize([]string{"undo", "9"}); err == nil || !strings.Contains(err.Error(), "no summaries to undo") {
+		t.Fatalf("expected nothing to undo for cluster 9, got %v", err)
+	}
+
+	out := captureStdout(func() {
+		if err := runSummarize([]string{"undo", "5"}); err != nil {
+			t.Fatalf("undo: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Undid cluster #5 summary") || !strings.Contains(out, "2 originals restored") {
+		t.Fatalf("unexpected undo output:\n%s", out)
+	}
+}
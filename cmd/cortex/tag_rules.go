@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func tagRulesUsageText() string {
+	return `Usage: cortex tag rules <list|test> [flags]
+
+Auto-tag rules map memories to projects for 'tag --auto' and 'import
+--auto-tag'. Rules in ~/.cortex/tag-rules.yaml are checked before the
+built-in defaults:
+
+  path_rules:                      # first match wins
+    - glob: "~/notes/garden/**"    # * within a directory, ** across them
+      project: garden
+    - contains: allotment          # case-insensitive substring of the path
+      project: garden
+  content_rules:                   # rule with the most keyword hits wins
+    - keywords: [tomato, compost, seedling]
+      min_hits: 2                  # default 2
+      project: garden
+
+Subcommands:
+  list [--json]                        Show user and built-in rules in match order
+  test <path> [--text <content>] [--json]
+                                       Show which rule would tag a memory from <path>;
+                                       content rules use --text or the file's contents`
+}
+
+func tagRulesPath() string {
+	return filepath.Join(getConfigDir(), store.TagRulesFileName)
+}
+
+// loadUserTagRules installs the rules from tag-rules.yaml (if any) so
+// auto-tagging sees them ahead of the built-ins.
+func loadUserTagRules() error {
+	pathRules, contentRules, err := store.LoadTagRules(tagRulesPath())
+	if err != nil {
+		return err
+	}
+	store.SetUserTagRules(pathRules, contentRules)
+	return nil
+}
+
+func runTagRules(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(tagRulesUsageText())
+		return nil
+	}
+	if err := loadUserTagRules(); err != nil {
+		return err
+	}
+	switch args[0] {
+	case "list":
+		return runTagRulesList(args[1:])
+	case "test":
+		return runTagRulesTest(args[1:])
+	default:
+		return fmt.Errorf("unknown tag rules subcommand: %s", args[0])
+	}
+}
+
+type tagRuleView struct {
+	Project string `json:"project"`
+	Rule    string `json:"rule"`
+	Origin  string `json:"origin"`
+}
+
+func runTagRulesList(args []string) error {
+	jsonOutput := false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOutput = true
+		default:
+			return fmt.Errorf("unknown flag: %s", arg)
+		}
+	}
+
+	var pathViews, contentViews []tagRuleView
+	for _, r := range store.ActiveProjectRules() {
+		pathViews = append(pathViews, tagRuleView{Project: r.Project, Rule: r.Describe(), Origin: tagRuleOrigin(r.Origin)})
+	}
+	for _, r := range store.ActiveContentRules() {
+		contentViews = append(contentViews, tagRuleView{Project: r.Project, Rule: r.Describe(), Origin: tagRuleOrigin(r.Origin)})
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"file":          tagRulesPath(),
+			"path_rules":    pathViews,
+			"content_rules": contentViews,
+		}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if _, err := os.Stat(tagRulesPath()); err != nil {
+		fmt.Printf("No %s (built-in rules only)\n\n", tagRulesPath())
+	}
+	fmt.Println("Path rules (first match wins):")
+	printTagRuleViews(pathViews)
+	fmt.Println("\nContent rules (most keyword hits wins):")
+	printTagRuleViews(contentViews)
+	return nil
+}
+
+func printTagRuleViews(views []tagRuleView) {
+	origin := ""
+	for _, v := range views {
+		if v.Origin != origin {
+			origin = v.Origin
+			fmt.Printf("  %s\n", origin)
+		}
+		fmt.Printf("    → %-12s %s\n", v.Project, truncateString(v.Rule, 100))
+	}
+}
+
+func runTagRulesTest(args []string) error {
+	path := ""
+	text := ""
+	hasText := false
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--text" && i+1 < len(args):
+			i++
+			text, hasText = args[i], true
+		case strings.HasPrefix(args[i], "--text="):
+			text, hasText = strings.TrimPrefix(args[i], "--text="), true
+		case args[i] == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		case path == "":
+			path = args[i]
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	if path == "" {
+		return fmt.Errorf("usage: cortex tag rules test <path> [--text <content>] [--json]")
+	}
+	if !hasText {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", path, err)
+			}
+			text = string(b)
+		}
+	}
+
+	match := store.MatchTagRule(path, text, store.ActiveProjectRules(), store.ActiveContentRules())
+	if jsonOutput {
+		data, _ := json.MarshalIndent(map[string]interface{}{"path": path, "match": match}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	if match == nil {
+		fmt.Printf("%s: no rule matched\n", path)
+		return nil
+	}
+	fmt.Printf("%s → %s\n", path, match.Project)
+	fmt.Printf("  %s rule: %s\n", match.Method, match.Rule)
+	fmt.Printf("  from:    %s\n", match.Origin)
+	return nil
+}
+
+func tagRuleOrigin(origin string) string {
+	if origin == "" {
+		return "built-in"
+	}
+	return origin
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func useTagRulesHome(t *testing.T, rules string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".cortex"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".cortex", store.TagRulesFileName), []byte(rules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() {
+		globalDBPath = oldDBPath
+		store.SetUserTagRules(nil, nil)
+	})
+	return home
+}
+
+const gardenTagRules = `path_rules:
+  - glob: "**/garden/**"
+    project: garden
+content_rules:
+  - keywords: [tomato, compost]
+    project: garden
+`
+
+func TestRunTagRules_ListAndTest(t *testing.T) {
+	home := useTagRulesHome(t, gardenTagRules)
+
+	out := captureStdout(func() {
+		if err := runTag([]string{"rules", "list"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	if !strings.Contains(out, `glob "**/garden/**"`) || !strings.Contains(out, "built-in") {
+		t.Fatalf("expected user and built-in rules:\n%s", out)
+	}
+	if strings.Index(out, "garden/**") > strings.Index(out, "built-in") {
+		t.Errorf("user rules should be listed before built-ins:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		if err := runTag([]string{"rules", "test", "notes/garden/beds.md"}); err != nil {
+			t.Fatalf("test: %v", err)
+		}
+	})
+	if !strings.Contains(out, "→ garden") || !strings.Contains(out, filepath.Join(home, ".cortex", store.TagRulesFileName)) {
+		t.Fatalf("expected match from the rules file:\n%s", out)
+	}
+
+	note := filepath.Join(home, "daily.md")
+	if err := os.WriteFile(note, []byte("Spread compost around the tomato beds."), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out = captureStdout(func() {
+		if err := runTag([]string{"rules", "test", note}); err != nil {
+			t.Fatalf("test: %v", err)
+		}
+	})
+	if !strings.Contains(out, "content rule: keywords") {
+		t.Fatalf("file contents should be checked against content rules:\n%s", out)
+	}
+}
+
+func TestRunAutoTag_ReportsMatchedRule(t *testing.T) {
+	useTagRulesHome(t, gardenTagRules)
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, m := range []*store.Memory{
+		{Content: "Raised bed layout", SourceFile: "/notes/garden/beds.md"},
+		{Content: "Nothing to see", SourceFile: "/notes/misc.md"},
+	} {
+		if _, err := s.AddMemory(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	out := captureStdout(func() {
+		if err := runTag([]string{"--auto"}); err != nil {
+			t.Fatalf("tag --auto: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Auto-tagged 1 memories") || !strings.Contains(out, `glob "**/garden/**" → garden`) {
+		t.Fatalf("expected matched rule in output:\n%s", out)
+	}
+}
+
+func TestRunTagRules_BadFile(t *testing.T) {
+	useTagRulesHome(t, "path_rules:\n  - glob: x\n")
+	if err := runTag([]string{"rules", "list"}); err == nil || !strings.Contains(err.Error(), "project is required") {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+}
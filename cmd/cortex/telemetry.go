@@ -0,0 +1,463 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/store"
+	"github.com/hurttlocker/cortex/internal/timings"
+)
+
+// Every command records its LLM and embedding usage (one telemetry_events
+// row per provider/model) when it finishes. Commands can attach a mode and
+// a JSON detail blob to their LLM rows; `cortex reason` uses this for its
+// one-shot/recursive run record.
+var (
+	currentCommand        string
+	pendingTelemetryMode  string
+	pendingTelemetryExtra string
+)
+
+const legacyReasonTelemetryFile = "reason-telemetry.jsonl"
+
+func shouldWriteTelemetry() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("CORTEX_TELEMETRY"))) {
+	case "0", "false", "off", "no", "disabled":
+		return false
+	default:
+		return true
+	}
+}
+
+// startCommand notes the command being run, turns on usage recording, and
+// hooks the `--llm auto` router up to the telemetry table and --verbose.
+func startCommand(name string) {
+	currentCommand = name
+	if shouldWriteTelemetry() && !timings.Enabled() {
+		timings.Enable()
+	}
+	llm.SpendToday = telemetrySpendToday
+	if globalVerbose {
+		llm.OnRoute = func(d llm.RouteDecision) {
+			fmt.Fprintf(os.Stderr, "  llm auto: %s → %s/%s [%s] ~%d tokens, est $%.5f\n",
+				d.Task, d.Provider, d.Model, d.Reason, d.PromptTokens, d.EstCostUSD)
+		}
+	}
+}
+
+// telemetrySpendToday reports today's recorded LLM spend for the router's
+// daily budget. Errors count as zero spend rather than blocking the call.
+func telemetrySpendToday() float64 {
+	sqlStore, closeStore, err := openTelemetryStore()
+	if err != nil {
+		return 0
+	}
+	defer closeStore()
+	cost, err := sqlStore.TelemetryCostSince(context.Background(), llm.StartOfToday())
+	if err != nil {
+		return 0
+	}
+	return cost
+}
+
+// finishCommand prints the --timings footer and records telemetry. It runs
+// on both the success and the error path.
+func finishCommand() {
+	printTimingsFooter()
+	if err := recordCommandTelemetry(); err != nil && globalVerbose {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record telemetry: %v\n", err)
+	}
+}
+
+// annotateCommandTelemetry attaches a mode and detail payload to the LLM
+// rows the current command will record.
+func annotateCommandTelemetry(mode string, detail any) {
+	pendingTelemetryMode = mode
+	pendingTelemetryExtra = ""
+	if detail != nil {
+		if b, err := json.Marshal(detail); err == nil {
+			pendingTelemetryExtra = string(b)
+		}
+	}
+}
+
+// commandTelemetryEvents turns the recorded per-model usage into rows.
+func commandTelemetryEvents(command string, usage []timings.ModelUsage, mode, detail string) []store.TelemetryEvent {
+	events := make([]store.TelemetryEvent, 0, len(usage))
+	for _, u := range usage {
+		e := store.TelemetryEvent{
+			Command:    command,
+			Kind:       string(u.Kind),
+			Provider:   u.Provider,
+			Model:      u.Model,
+			Calls:      u.Calls,
+			TokensIn:   u.TokensIn,
+			TokensOut:  u.TokensOut,
+			DurationMS: u.DurationMS,
+			Errors:     u.Errors,
+			Failovers:  u.Failovers,
+			Repairs:    u.Repairs,
+			Reprompts:  u.Reprompts,
+		}
+		if u.Kind == timings.LLM {
+			e.CostUSD, e.CostKnown = estimateReasonRunCostCached(u.Provider, u.Model, u.TokensIn, u.CachedTokens, u.TokensOut)
+			e.Mode, e.Detail = mode, detail
+			if len(u.Routes) > 0 {
+				// Routed calls keep their routing decisions for audit.
+				if e.Mode == "" {
+					e.Mode = llm.AutoProvider
+				}
+				payload := map[string]any{"routes": u.Routes}
+				if detail != "" {
+					payload["run"] = json.RawMessage(detail)
+				}
+				if b, err := json.Marshal(payload); err == nil {
+					e.Detail = string(b)
+				}
+			}
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func recordCommandTelemetry() error {
+	if !shouldWriteTelemetry() || globalReadOnly || !timings.Enabled() {
+		return nil
+	}
+	if currentCommand == "reason" && !shouldWriteReasonTelemetry() {
+		return nil
+	}
+	usage := timings.Snapshot().Models
+	if len(usage) == 0 {
+		return nil
+	}
+
+	sqlStore, closeStore, err := openTelemetryStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	return sqlStore.RecordTelemetryEvents(context.Background(),
+		commandTelemetryEvents(currentCommand, usage, pendingTelemetryMode, pendingTelemetryExtra))
+}
+
+func openTelemetryStore() (*store.SQLiteStore, func(), error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening store: %w", err)
+	}
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, nil, fmt.Errorf("telemetry requires SQLiteStore")
+	}
+	return sqlStore, func() { s.Close() }, nil
+}
+
+// importLegacyReasonTelemetry moves ~/.cortex/reason-telemetry.jsonl (the
+// pre-table reason log) into telemetry_events, then renames the file so it
+// is imported only once. It returns the number of rows imported.
+func importLegacyReasonTelemetry(ctx context.Context, s *store.SQLiteStore) (int, error) {
+	path := filepath.Join(getConfigDir(), legacyReasonTelemetryFile)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var events []store.TelemetryEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var ev reasonRunTelemetry
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			continue // skip corrupt lines rather than blocking the move
+		}
+		at, _ := time.Parse(time.RFC3339, ev.Timestamp)
+		events = append(events, store.TelemetryEvent{
+			Command:    "reason",
+			Kind:       store.TelemetryKindLLM,
+			Provider:   ev.Provider,
+			Model:      ev.Model,
+			Mode:       ev.Mode,
+			Calls:      max(ev.Iterations, 1),
+			TokensIn:   ev.TokensIn,
+			TokensOut:  ev.TokensOut,
+			DurationMS: ev.LLMMS,
+			CostUSD:    ev.CostUSD,
+			CostKnown:  ev.CostKnown,
+			Detail:     line,
+			CreatedAt:  at,
+		})
+	}
+	f.Close()
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	if err := s.RecordTelemetryEvents(ctx, events); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(path, path+".imported"); err != nil {
+		return len(events), fmt.Errorf("renaming %s after import: %w", path, err)
+	}
+	return len(events), nil
+}
+
+func telemetryUsageText() string {
+	return `Usage: cortex telemetry report [--since 7d] [--by model|command|mode|kind] [--json]
+
+Summarize LLM and embedding usage recorded by every command: calls,
+tokens, p50/p95 latency, and estimated cost. REPAIRED counts structured
+(JSON) replies fixed locally / re-asked from the model.
+
+Flags:
+  --since <window>   Only include events newer than this (default: 7d; "all" for everything)
+  --by <dimension>   Group by model (default), provider, command, mode, or kind
+  --json             Output as JSON
+
+Telemetry is stored in the cortex database (telemetry_events table).
+Disable recording with CORTEX_TELEMETRY=off.`
+}
+
+func runTelemetry(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(telemetryUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "report":
+		return runTelemetryReport(args[1:])
+	default:
+		return fmt.Errorf("unknown telemetry subcommand: %s", args[0])
+	}
+}
+
+// telemetryReportRow aggregates the events sharing one group key.
+type telemetryReportRow struct {
+	Key            string  `json:"key"`
+	Events         int     `json:"events"`
+	Calls          int     `json:"calls"`
+	TokensIn       int     `json:"tokens_in"`
+	TokensOut      int     `json:"tokens_out"`
+	Errors         int     `json:"errors"`
+	Failovers      int     `json:"failovers"`
+	Repairs        int     `json:"repairs"`   // structured replies repaired locally
+	Reprompts      int     `json:"reprompts"` // structured replies re-asked
+	P50MS          int64   `json:"p50_ms"`
+	P95MS          int64   `json:"p95_ms"`
+	CostUSD        float64 `json:"cost_usd"`
+	CostKnownShare float64 `json:"cost_known_share"` // of LLM events
+}
+
+type telemetryReport struct {
+	Since string               `json:"since,omitempty"`
+	By    string               `json:"by"`
+	Total telemetryReportRow   `json:"total"`
+	Rows  []telemetryReportRow `json:"rows"`
+}
+
+func runTelemetryReport(args []string) error {
+	sinceFlag, by, jsonOut := "7d", "model", false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--since" && i+1 < len(args):
+			i++
+			sinceFlag = args[i]
+		case strings.HasPrefix(args[i], "--since="):
+			sinceFlag = strings.TrimPrefix(args[i], "--since=")
+		case args[i] == "--by" && i+1 < len(args):
+			i++
+			by = args[i]
+		case strings.HasPrefix(args[i], "--by="):
+			by = strings.TrimPrefix(args[i], "--by=")
+		case args[i] == "--json":
+			jsonOut = true
+		case args[i] == "--help" || args[i] == "-h":
+			fmt.Println(telemetryUsageText())
+			return nil
+		default:
+			return fmt.Errorf("unknown flag: %s", args[i])
+		}
+	}
+
+	by = strings.ToLower(strings.TrimSpace(by))
+	switch by {
+	case "model", "provider", "command", "mode", "kind":
+	default:
+		return fmt.Errorf("invalid --by %q (valid: model, provider, command, mode, kind)", by)
+	}
+	var since time.Time
+	if s := strings.ToLower(strings.TrimSpace(sinceFlag)); s != "" && s != "all" {
+		d, err := parseSinceDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		since = time.Now().UTC().Add(-d)
+	}
+
+	sqlStore, closeStore, err := openTelemetryStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	if !globalReadOnly {
+		if n, err := importLegacyReasonTelemetry(ctx, sqlStore); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: importing %s: %v\n", legacyReasonTelemetryFile, err)
+		} else if n > 0 && !jsonOut {
+			fmt.Fprintf(os.Stderr, "Imported %d legacy reason telemetry events from %s\n", n, legacyReasonTelemetryFile)
+		}
+	}
+
+	events, err := sqlStore.ListTelemetryEvents(ctx, since)
+	if err != nil {
+		return err
+	}
+	report := buildTelemetryReport(events, by)
+	if !since.IsZero() {
+		report.Since = since.Format(time.RFC3339)
+	}
+
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printTelemetryReport(report, sinceFlag)
+	return nil
+}
+
+func telemetryGroupKey(e store.TelemetryEvent, by string) string {
+	var key string
+	switch by {
+	case "command":
+		key = e.Command
+	case "mode":
+		key = e.Mode
+	case "kind":
+		key = e.Kind
+	case "provider":
+		key = e.Kind + ":" + e.Provider
+	default:
+		key = e.Provider + "/" + e.Model
+	}
+	if strings.Trim(key, "/") == "" {
+		return "(none)"
+	}
+	return key
+}
+
+func buildTelemetryReport(events []store.TelemetryEvent, by string) telemetryReport {
+	type group struct {
+		row        telemetryReportRow
+		durations  []int64
+		llm, known int
+	}
+	groups := map[string]*group{}
+	total := &group{row: telemetryReportRow{Key: "total"}}
+
+	for _, e := range events {
+		key := telemetryGroupKey(e, by)
+		g := groups[key]
+		if g == nil {
+			g = &group{row: telemetryReportRow{Key: key}}
+			groups[key] = g
+		}
+		for _, acc := range []*group{g, total} {
+			acc.row.Events++
+			acc.row.Calls += e.Calls
+			acc.row.TokensIn += e.TokensIn
+			acc.row.TokensOut += e.TokensOut
+			acc.row.Errors += e.Errors
+			acc.row.Failovers += e.Failovers
+			acc.row.Repairs += e.Repairs
+			acc.row.Reprompts += e.Reprompts
+			acc.row.CostUSD += e.CostUSD
+			acc.durations = append(acc.durations, e.DurationMS)
+			if e.Kind == store.TelemetryKindLLM {
+				acc.llm++
+				if e.CostKnown {
+					acc.known++
+				}
+			}
+		}
+	}
+
+	finish := func(g *group) telemetryReportRow {
+		sort.Slice(g.durations, func(i, j int) bool { return g.durations[i] < g.durations[j] })
+		g.row.P50MS = percentileMS(g.durations, 0.50)
+		g.row.P95MS = percentileMS(g.durations, 0.95)
+		if g.llm > 0 {
+			g.row.CostKnownShare = float64(g.known) / float64(g.llm)
+		}
+		return g.row
+	}
+
+	report := telemetryReport{By: by, Total: finish(total), Rows: []telemetryReportRow{}}
+	for _, g := range groups {
+		report.Rows = append(report.Rows, finish(g))
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if report.Rows[i].CostUSD != report.Rows[j].CostUSD {
+			return report.Rows[i].CostUSD > report.Rows[j].CostUSD
+		}
+		if report.Rows[i].Calls != report.Rows[j].Calls {
+			return report.Rows[i].Calls > report.Rows[j].Calls
+		}
+		return report.Rows[i].Key < report.Rows[j].Key
+	})
+	return report
+}
+
+// percentileMS returns the nearest-rank percentile of sorted values.
+func percentileMS(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted))*p+0.999999) - 1
+	idx = max(0, min(idx, len(sorted)-1))
+	return sorted[idx]
+}
+
+func printTelemetryReport(r telemetryReport, sinceFlag string) {
+	window := "all time"
+	if r.Since != "" {
+		window = "last " + sinceFlag
+	}
+	fmt.Printf("Telemetry by %s (%s): %d events, %d calls, %d→%d tokens, $%.4f\n\n",
+		r.By, window, r.Total.Events, r.Total.Calls, r.Total.TokensIn, r.Total.TokensOut, r.Total.CostUSD)
+	if len(r.Rows) == 0 {
+		fmt.Println("No telemetry recorded in this window.")
+		return
+	}
+	fmt.Printf("%-40s  %6s  %6s  %6s  %9s  %9s  %10s  %10s  %8s  %8s  %10s  %6s\n",
+		strings.ToUpper(r.By), "EVENTS", "CALLS", "ERRORS", "FAILOVERS", "REPAIRED", "TOKENS IN", "TOKENS OUT", "P50", "P95", "COST", "PRICED")
+	fmt.Println(strings.Repeat("─", 149))
+	for _, row := range r.Rows {
+		priced := "-"
+		if row.CostKnownShare > 0 || row.CostUSD > 0 {
+			priced = fmt.Sprintf("%.0f%%", row.CostKnownShare*100)
+		}
+		fmt.Printf("%-40s  %6d  %6d  %6d  %9d  %9s  %10d  %10d  %8s  %8s  %10s  %6s\n",
+			truncateString(row.Key, 40), row.Events, row.Calls, row.Errors, row.Failovers,
+			fmt.Sprintf("%d/%d", row.Repairs, row.Reprompts), row.TokensIn, row.TokensOut,
+			formatTimingMS(row.P50MS), formatTimingMS(row.P95MS), fmt.Sprintf("$%.4f", row.CostUSD), priced)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+	"github.com/hurttlocker/cortex/internal/timings"
+)
+
+func useTelemetryTestDB(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CORTEX_TELEMETRY", "")
+	t.Setenv("CORTEX_REASON_TELEMETRY", "")
+	oldDBPath, oldCommand := globalDBPath, currentCommand
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() {
+		globalDBPath, currentCommand = oldDBPath, oldCommand
+		pendingTelemetryMode, pendingTelemetryExtra = "", ""
+		timings.Disable()
+	})
+	return home
+}
+
+func TestRecordCommandTelemetry_ReasonRun(t *testing.T) {
+	useTelemetryTestDB(t)
+	startCommand("reason")
+
+	call := timings.Begin(timings.LLM, "openrouter", "google/gemini-3-flash-preview")
+	call.Tokens(1000, 200)
+	call.End()
+	timings.Begin(timings.Embed, "ollama", "nomic-embed-text").End()
+	annotateCommandTelemetry("recursive", reasonRunTelemetry{Mode: "recursive", Query: "test query"})
+
+	if err := recordCommandTelemetry(); err != nil {
+		t.Fatalf("recordCommandTelemetry: %v", err)
+	}
+
+	s, closeStore, err := openTelemetryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+	events, err := s.ListTelemetryEvents(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("ListTelemetryEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected llm + embed rows, got %+v", events)
+	}
+	llm := events[0]
+	if llm.Kind != store.TelemetryKindLLM {
+		llm = events[1]
+	}
+	if llm.Command != "reason" || llm.Mode != "recursive" || llm.TokensIn != 1000 || !llm.CostKnown || !strings.Contains(llm.Detail, `"query":"test query"`) {
+		t.Fatalf("unexpected reason row: %+v", llm)
+	}
+}
+
+func TestRecordCommandTelemetry_Disabled(t *testing.T) {
+	useTelemetryTestDB(t)
+	t.Setenv("CORTEX_REASON_TELEMETRY", "off")
+	startCommand("reason")
+	timings.Begin(timings.LLM, "ollama", "llama3").End()
+	if err := recordCommandTelemetry(); err != nil {
+		t.Fatalf("recordCommandTelemetry: %v", err)
+	}
+	if _, err := os.Stat(globalDBPath); !os.IsNotExist(err) {
+		t.Fatalf("expected no store to be opened when reason telemetry is off (stat err=%v)", err)
+	}
+}
+
+func TestTelemetryReport_ImportsLegacyAndGroups(t *testing.T) {
+	home := useTelemetryTestDB(t)
+
+	legacy := filepath.Join(home, ".cortex", legacyReasonTelemetryFile)
+	if err := os.MkdirAll(filepath.Dir(legacy), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	lines := []reasonRunTelemetry{
+		{Timestamp: now.Add(-time.Hour).Format(time.RFC3339), Mode: "one-shot", Provider: "openrouter", Model: "m1", TokensIn: 100, TokensOut: 10, LLMMS: 800, CostUSD: 0.01, CostKnown: true},
+		{Timestamp: now.Add(-2 * time.Hour).Format(time.RFC3339), Mode: "recursive", Provider: "ollama", Model: "m2", Iterations: 4, LLMMS: 9000},
+		{Timestamp: now.Add(-30 * 24 * time.Hour).Format(time.RFC3339), Mode: "one-shot", Provider: "openrouter", Model: "m1", LLMMS: 100},
+	}
+	var buf strings.Builder
+	for _, l := range lines {
+		b, _ := json.Marshal(l)
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("not json\n")
+	if err := os.WriteFile(legacy, []byte(buf.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(func() {
+		if err := runTelemetryReport([]string{"--since", "7d", "--by", "mode", "--json"}); err != nil {
+			t.Fatalf("runTelemetryReport: %v", err)
+		}
+	})
+	var report telemetryReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("decode report: %v\n%s", err, out)
+	}
+	if report.Total.Events != 2 || len(report.Rows) != 2 {
+		t.Fatalf("expected 2 recent events in 2 groups, got %+v", report)
+	}
+	if report.Rows[0].Key != "one-shot" || report.Rows[0].CostKnownShare != 1 || report.Rows[1].Calls != 4 {
+		t.Fatalf("unexpected rows: %+v", report.Rows)
+	}
+	if _, err := os.Stat(legacy + ".imported"); err != nil {
+		t.Fatalf("legacy file not renamed after import: %v", err)
+	}
+
+	// The import is one-time: a second report sees the same rows.
+	out = captureStdout(func() {
+		if err := runTelemetryReport([]string{"--since=all", "--json"}); err != nil {
+			t.Fatalf("runTelemetryReport: %v", err)
+		}
+	})
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Total.Events != 3 || report.By != "model" {
+		t.Fatalf("expected 3 events by model, got %+v", report)
+	}
+
+	if err := runTelemetryReport([]string{"--by", "planet"}); err == nil {
+		t.Fatal("expected error for invalid --by")
+	}
+}
+
+func TestRecordCommandTelemetry_ProviderFailures(t *testing.T) {
+	useTelemetryTestDB(t)
+	startCommand("enrich")
+
+	call := timings.Begin(timings.LLM, "openrouter", "openai/gpt-4o-mini")
+	call.Fail(errors.New("openrouter API error (status 503): unavailable"))
+	call.End()
+	timings.RecordFailover(timings.LLM, "openrouter", "openai/gpt-4o-mini")
+	timings.Begin(timings.LLM, "google", "gemini-2.5-flash").End()
+
+	if err := recordCommandTelemetry(); err != nil {
+		t.Fatalf("recordCommandTelemetry: %v", err)
+	}
+	s, closeStore, err := openTelemetryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+	events, err := s.ListTelemetryEvents(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("ListTelemetryEvents: %v", err)
+	}
+
+	report := buildTelemetryReport(events, "provider")
+	byKey := map[string]telemetryReportRow{}
+	for _, row := range report.Rows {
+		byKey[row.Key] = row
+	}
+	if or := byKey["llm:openrouter"]; or.Calls != 1 || or.Errors != 1 || or.Failovers != 1 {
+		t.Fatalf("unexpected openrouter row: %+v", or)
+	}
+	if g := byKey["llm:google"]; g.Calls != 1 || g.Errors != 0 || g.Failovers != 0 {
+		t.Fatalf("unexpected google row: %+v", g)
+	}
+	if report.Total.Errors != 1 || report.Total.Failovers != 1 {
+		t.Fatalf("unexpected totals: %+v", report.Total)
+	}
+}
+
+func TestPercentileMS(t *testing.T) {
+	vals := []int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+	if got := percentileMS(vals, 0.5); got != 50 {
+		t.Fatalf("p50 = %d, want 50", got)
+	}
+	if got := percentileMS(vals, 0.95); got != 100 {
+		t.Fatalf("p95 = %d, want 100", got)
+	}
+	if got := percentileMS(nil, 0.5); got != 0 {
+		t.Fatalf("empty p50 = %d", got)
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/ann"
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// Tiering steps, in the order they are tried: compaction is restorable,
+// quantization is lossy, and VACUUM hands the freed pages back to the OS.
+const (
+	tierStepCompact  = "compact"
+	tierStepQuantize = "quantize"
+	tierStepVacuum   = "vacuum"
+)
+
+// tierStep is one thing tiering did (or, in a dry run, would do).
+type tierStep struct {
+	Step       string `json:"step"`
+	Detail     string `json:"detail"`
+	SavedBytes int64  `json:"saved_bytes,omitempty"` // estimated for compact and quantize
+}
+
+// tierReport is the outcome of runStorageTiering.
+type tierReport struct {
+	BudgetBytes int64      `json:"budget_bytes"`
+	SizeBefore  int64      `json:"size_before"`
+	SizeAfter   int64      `json:"size_after"`
+	OverBudget  bool       `json:"over_budget"`
+	DryRun      bool       `json:"dry_run,omitempty"`
+	Steps       []tierStep `json:"steps,omitempty"`
+	Suggestions []string   `json:"suggestions,omitempty"`
+}
+
+// summary renders the report in one line for logs.
+func (r *tierReport) summary() string {
+	steps := make([]string, 0, len(r.Steps))
+	for _, st := range r.Steps {
+		steps = append(steps, st.Step)
+	}
+	line := fmt.Sprintf("tiering: %s → %s (budget %s)", formatBytes(r.SizeBefore), formatBytes(r.SizeAfter), formatBytes(r.BudgetBytes))
+	if len(steps) > 0 {
+		line += ": " + strings.Join(steps, ", ")
+	}
+	if r.SizeAfter > r.BudgetBytes {
+		line += "; still over budget, run cortex summarize"
+	}
+	return line
+}
+
+func tierUsageText() string {
+	return `Usage: cortex tier [--dry-run] [--force] [--json]
+
+Shrink the database back under its size budget (storage.budget in
+config.yaml). Tiering runs automatically after imports and on the daemon's
+maintain schedule once the budget is exceeded; cortex stats warns from
+storage.warn_at of the budget on (default 80%). Steps, in order, until the
+database fits:
+
+  1. compact   Archive the raw text of old memories whose facts are extracted
+               (storage.tiering.compact_classes, default scratch, older than
+               compact_after_days, default 30; see cortex compact)
+  2. quantize  Re-encode embeddings as int8 (skipped with no_quantize: true)
+  3. vacuum    Return the freed pages to the OS
+
+If the database is still over budget afterwards, run cortex summarize to
+consolidate fact clusters, widen the tiering settings, or raise the budget.
+
+Config:
+  storage:
+    budget: 2GB
+    warn_at: 0.8
+    tiering:
+      compact_after_days: 30
+      compact_classes: [scratch, status]
+      no_quantize: false
+      disabled: false       # true: warn only, never tier automatically
+
+Flags:
+  --dry-run   Report what tiering would do
+  --force     Tier even when under budget
+  --json      Machine-readable output`
+}
+
+func runTier(args []string) error {
+	dryRun, force, jsonOutput := false, false, false
+	for _, arg := range args {
+		switch {
+		case arg == "--help" || arg == "-h":
+			fmt.Println(tierUsageText())
+			return nil
+		case arg == "--dry-run":
+			dryRun = true
+		case arg == "--force":
+			force = true
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	cfg := resolveStorageConfig()
+	if cfg.BudgetBytes() <= 0 {
+		return fmt.Errorf("no storage budget configured (set storage.budget in config.yaml, e.g. 2GB)")
+	}
+	if globalReadOnly && !dryRun {
+		return fmt.Errorf("cannot tier: database is in read-only mode")
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("tier requires SQLite store")
+	}
+
+	report, err := runStorageTiering(context.Background(), sqlStore, cfg, dryRun, force)
+	if err != nil {
+		return err
+	}
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	fmt.Printf("Storage: %s of %s budget\n", formatBytes(report.SizeBefore), formatBytes(report.BudgetBytes))
+	if !report.OverBudget && !force {
+		fmt.Println("Under budget; nothing to do.")
+		return nil
+	}
+	if len(report.Steps) == 0 {
+		fmt.Println("No tiering step applies.")
+	}
+	for _, st := range report.Steps {
+		fmt.Printf("  %-9s %s\n", st.Step, st.Detail)
+	}
+	if report.DryRun {
+		fmt.Printf("Dry run: estimated size afterwards %s\n", formatBytes(report.SizeAfter))
+	} else {
+		fmt.Printf("Size now: %s\n", formatBytes(report.SizeAfter))
+	}
+	for _, sg := range report.Suggestions {
+		fmt.Printf("  → %s\n", sg)
+	}
+	return nil
+}
+
+// resolveStorageConfig returns the storage: config, or the zero value (no
+// budget) when the config cannot be read.
+func resolveStorageConfig() cfgresolver.StorageConfig {
+	resolved, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{CLIDBPath: globalDBPath})
+	if err != nil {
+		return cfgresolver.StorageConfig{}
+	}
+	return resolved.Storage
+}
+
+func storageSize(ctx context.Context, s *store.SQLiteStore) (int64, error) {
+	stats, err := s.Stats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return stats.DBSizeBytes, nil
+}
+
+// runStorageTiering applies the tiering steps while the database is over
+// cfg's budget (or unconditionally with force). In a dry run the sizes
+// after each step are estimates.
+func runStorageTiering(ctx context.Context, s *store.SQLiteStore, cfg cfgresolver.StorageConfig, dryRun, force bool) (*tierReport, error) {
+	size, err := storageSize(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	budget := cfg.BudgetBytes()
+	report := &tierReport{BudgetBytes: budget, SizeBefore: size, SizeAfter: size, OverBudget: size > budget, DryRun: dryRun}
+	if !report.OverBudget && !force {
+		return report, nil
+	}
+
+	classes, err := store.ParseMemoryClassList(strings.Join(cfg.Tiering.Classes(), ","))
+	if err != nil {
+		return nil, fmt.Errorf("storage.tiering.compact_classes: %w", err)
+	}
+	remaining := size
+	changed := false
+
+	compacted, err := s.CompactMemories(ctx, store.CompactOptions{
+		OlderThan: time.Now().Add(-cfg.Tiering.CompactAge()),
+		Classes:   classes,
+		DryRun:    dryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if compacted.Compacted > 0 {
+		report.Steps = append(report.Steps, tierStep{
+			Step:       tierStepCompact,
+			Detail:     fmt.Sprintf("%d %s memories, %s of raw text archived", compacted.Compacted, strings.Join(classes, "/"), formatBytes(compacted.OriginalBytes)),
+			SavedBytes: compacted.SavedBytes,
+		})
+		remaining -= compacted.SavedBytes
+		changed = true
+	}
+
+	if (remaining > budget || force) && !cfg.Tiering.NoQuantize {
+		current, err := s.EmbeddingQuantization(ctx)
+		if err != nil {
+			return nil, err
+		}
+		embeddingBytes, err := s.EmbeddingBytes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if current != string(ann.QuantizationInt8) && embeddingBytes > 0 {
+			// int8 stores one byte per dimension instead of four.
+			st := tierStep{Step: tierStepQuantize, SavedBytes: embeddingBytes * 3 / 4}
+			if dryRun {
+				st.Detail = fmt.Sprintf("re-encode %s of embeddings as int8", formatBytes(embeddingBytes))
+			} else {
+				n, err := s.SetEmbeddingQuantization(ctx, string(ann.QuantizationInt8))
+				if err != nil {
+					return nil, fmt.Errorf("quantizing embeddings: %w", err)
+				}
+				if _, err := rebuildHNSWIndex(ctx, s); err != nil {
+					return nil, fmt.Errorf("rebuilding HNSW index: %w", err)
+				}
+				st.Detail = fmt.Sprintf("%d embeddings re-encoded as int8", n)
+			}
+			report.Steps = append(report.Steps, st)
+			remaining -= st.SavedBytes
+			changed = true
+		}
+	}
+
+	if changed {
+		if dryRun {
+			report.Steps = append(report.Steps, tierStep{Step: tierStepVacuum, Detail: "return freed pages to the OS"})
+		} else {
+			if err := s.Vacuum(ctx); err != nil {
+				return nil, fmt.Errorf("vacuum: %w", err)
+			}
+			report.Steps = append(report.Steps, tierStep{Step: tierStepVacuum, Detail: "freed pages returned to the OS"})
+			if remaining, err = storageSize(ctx, s); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	report.SizeAfter = remaining
+
+	if report.SizeAfter > budget {
+		report.Suggestions = append(report.Suggestions,
+			"run cortex summarize to consolidate fact clusters into summary facts",
+			"widen storage.tiering.compact_classes or lower compact_after_days",
+			"or raise storage.budget")
+	}
+	return report, nil
+}
+
+// autoTier runs tiering when the database is over its budget, for commands
+// that tier as a side effect (import, the daemon's maintain schedule). It
+// returns a one-line summary, or "" when nothing was needed.
+func autoTier(ctx context.Context, s *store.SQLiteStore) (string, error) {
+	cfg := resolveStorageConfig()
+	if cfg.BudgetBytes() <= 0 || cfg.Tiering.Disabled || globalReadOnly {
+		return "", nil
+	}
+	report, err := runStorageTiering(ctx, s, cfg, false, false)
+	if err != nil || !report.OverBudget {
+		return "", err
+	}
+	return report.summary(), nil
+}
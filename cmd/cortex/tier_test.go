@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func writeTierConfig(t *testing.T, home, storageYAML string) {
+	t.Helper()
+	dir := filepath.Join(home, ".cortex")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(storageYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunTier(t *testing.T) {
+	home := useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss := s.(*store.SQLiteStore)
+	content := strings.Repeat("scratch notes from an old debugging session. ", 60)
+	memID, _ := ss.AddMemory(ctx, &store.Memory{Content: content, MemoryClass: store.MemoryClassScratch})
+	if _, err := ss.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "bug", Predicate: "status", Object: "fixed", FactType: "state"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.AddEmbedding(ctx, memID, make([]float32, 64)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ss.ExecContext(ctx, `UPDATE memories SET imported_at = ?`, time.Now().Add(-60*24*time.Hour).UTC()); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	run := func(args ...string) tierReport {
+		t.Helper()
+		var runErr error
+		out := captureStdout(func() { runErr = runTier(append(args, "--json")) })
+		if runErr != nil {
+			t.Fatalf("runTier(%v): %v", args, runErr)
+		}
+		var report tierReport
+		if err := json.Unmarshal([]byte(out), &report); err != nil {
+			t.Fatalf("decoding %q: %v", out, err)
+		}
+		return report
+	}
+
+	if err := runTier(nil); err == nil || !strings.Contains(err.Error(), "no storage budget") {
+		t.Fatalf("expected a missing-budget error, got %v", err)
+	}
+
+	writeTierConfig(t, home, "storage:\n  budget: 1GB\n")
+	if report := run(); report.OverBudget || len(report.Steps) != 0 {
+		t.Fatalf("under budget should do nothing, got %+v", report)
+	}
+
+	// A budget the database cannot meet runs every step and still suggests
+	// summarizing.
+	writeTierConfig(t, home, "storage:\n  budget: 1KB\n")
+	dry := run("--dry-run")
+	if !dry.OverBudget || len(dry.Steps) != 3 || dry.Steps[0].Step != tierStepCompact || dry.Steps[1].Step != tierStepQuantize {
+		t.Fatalf("unexpected dry run: %+v", dry)
+	}
+	report := run()
+	if len(report.Steps) != 3 || report.Steps[2].Step != tierStepVacuum || len(report.Suggestions) == 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	s, err = store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	ss = s.(*store.SQLiteStore)
+	if _, ok, _ := ss.ArchivedMemoryContent(ctx, memID); !ok {
+		t.Fatal("expected the scratch memory to be compacted")
+	}
+	if q, _ := ss.EmbeddingQuantization(ctx); q != "int8" {
+		t.Fatalf("expected int8 embeddings, got %s", q)
+	}
+
+	// With tiering disabled the budget only warns.
+	writeTierConfig(t, home, "storage:\n  budget: 1KB\n  tiering:\n    disabled: true\n")
+	if line, err := autoTier(ctx, ss); err != nil || line != "" {
+		t.Fatalf("autoTier with tiering disabled = %q, %v", line, err)
+	}
+}
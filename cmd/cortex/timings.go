@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/timings"
+)
+
+// globalTimings is set by the --timings global flag; globalTimingsJSON when
+// the command itself was asked for JSON output, so the footer stays
+// machine-readable too.
+var (
+	globalTimings     bool
+	globalTimingsJSON bool
+)
+
+// wantsJSONOutput reports whether a command's args request JSON output.
+func wantsJSONOutput(args []string) bool {
+	for i, a := range args {
+		switch {
+		case a == "--json", a == "--format=json":
+			return true
+		case a == "--format" && i+1 < len(args) && args[i+1] == "json":
+			return true
+		}
+	}
+	return false
+}
+
+// timingsSummary snapshots the recorded timings and prices the LLM token
+// usage with the same table reason telemetry uses. Embedding calls are not
+// priced.
+func timingsSummary() timings.Summary {
+	s := timings.Snapshot()
+	s.CostKnown = true
+	for _, m := range s.Models {
+		if m.Kind != timings.LLM {
+			continue
+		}
+		cost, ok := estimateReasonRunCostCached(m.Provider, m.Model, m.TokensIn, m.CachedTokens, m.TokensOut)
+		if !ok {
+			s.CostKnown = false
+			continue
+		}
+		s.CostUSD += cost
+	}
+	return s
+}
+
+// printTimingsFooter writes the --timings breakdown to stderr, keeping
+// stdout clean for the command's own output. It is a no-op without the flag.
+func printTimingsFooter() {
+	if !globalTimings {
+		return
+	}
+	writeTimingsFooter(os.Stderr, timingsSummary(), globalTimingsJSON)
+}
+
+func writeTimingsFooter(w io.Writer, s timings.Summary, asJSON bool) {
+	if asJSON {
+		b, err := json.Marshal(map[string]timings.Summary{"timings": s})
+		if err == nil {
+			fmt.Fprintln(w, string(b))
+		}
+		return
+	}
+
+	parts := []string{
+		fmt.Sprintf("wall %s", formatTimingMS(s.WallMS)),
+		fmt.Sprintf("db %s (%d %s)", formatTimingMS(s.DBMS), s.DBCalls, pluralize("query", "queries", s.DBCalls)),
+		fmt.Sprintf("embed %s (%d %s)", formatTimingMS(s.EmbedMS), s.EmbedCalls, pluralize("batch", "batches", s.EmbedCalls)),
+		fmt.Sprintf("llm %s (%d %s)", formatTimingMS(s.LLMMS), s.LLMCalls, pluralize("call", "calls", s.LLMCalls)),
+	}
+	if s.TokensIn > 0 || s.TokensOut > 0 {
+		parts = append(parts, fmt.Sprintf("tokens %d in / %d out", s.TokensIn, s.TokensOut))
+	}
+	switch {
+	case s.LLMCalls == 0:
+	case s.CostKnown:
+		parts = append(parts, fmt.Sprintf("cost $%.4f", s.CostUSD))
+	case s.CostUSD > 0:
+		parts = append(parts, fmt.Sprintf("cost ≥$%.4f (some models unpriced)", s.CostUSD))
+	default:
+		parts = append(parts, "cost n/a")
+	}
+	fmt.Fprintf(w, "⏱  %s\n", strings.Join(parts, " · "))
+	if len(s.Models) > 1 {
+		for _, m := range s.Models {
+			line := fmt.Sprintf("   %s %s/%s: %d %s, %s, %d in / %d out", m.Kind, m.Provider, m.Model, m.Calls, pluralize("call", "calls", m.Calls), formatTimingMS(m.DurationMS), m.TokensIn, m.TokensOut)
+			if m.Errors > 0 {
+				line += fmt.Sprintf(", %d %s", m.Errors, pluralize("error", "errors", m.Errors))
+			}
+			if m.Failovers > 0 {
+				line += fmt.Sprintf(", %d %s", m.Failovers, pluralize("failover", "failovers", m.Failovers))
+			}
+			if m.Repairs+m.Reprompts > 0 {
+				line += fmt.Sprintf(", %d JSON %s (%d re-asked)", m.Repairs+m.Reprompts, pluralize("repair", "repairs", m.Repairs+m.Reprompts), m.Reprompts)
+			}
+			if m.CacheHits > 0 {
+				line += fmt.Sprintf(", %d cache %s (%d tokens)", m.CacheHits, pluralize("hit", "hits", m.CacheHits), m.CachedTokens)
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+}
+
+func formatTimingMS(ms int64) string {
+	if ms < 1000 {
+		return fmt.Sprintf("%dms", ms)
+	}
+	return fmt.Sprintf("%.2fs", float64(ms)/1000)
+}
+
+func pluralize(one, many string, n int) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
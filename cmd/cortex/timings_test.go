@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/timings"
+)
+
+func TestWantsJSONOutput(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"search", "x", "--json"}, true},
+		{[]string{"list", "--format", "json"}, true},
+		{[]string{"list", "--format=json"}, true},
+		{[]string{"list", "--format", "table"}, false},
+		{[]string{"stats"}, false},
+	} {
+		if got := wantsJSONOutput(tc.args); got != tc.want {
+			t.Errorf("wantsJSONOutput(%v) = %v, want %v", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestWriteTimingsFooter(t *testing.T) {
+	s := timings.Summary{
+		WallMS: 1250, DBMS: 12, DBCalls: 1, LLMMS: 900, LLMCalls: 2,
+		TokensIn: 1200, TokensOut: 300,
+		Models: []timings.ModelUsage{
+			{Kind: timings.LLM, Provider: "openrouter", Model: "a", Calls: 1, TokensIn: 1000, TokensOut: 200},
+			{Kind: timings.LLM, Provider: "ollama", Model: "b", Calls: 1, TokensIn: 200, TokensOut: 100},
+		},
+		CostUSD: 0.0012,
+	}
+
+	var buf bytes.Buffer
+	writeTimingsFooter(&buf, s, false)
+	out := buf.String()
+	for _, want := range []string{"wall 1.25s", "db 12ms (1 query)", "llm 900ms (2 calls)", "tokens 1200 in / 300 out", "cost ≥$0.0012", "llm ollama/b: 1 call"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("footer missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	writeTimingsFooter(&buf, s, true)
+	var decoded map[string]timings.Summary
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("JSON footer: %v\n%s", err, buf.String())
+	}
+	if got := decoded["timings"]; got.LLMCalls != 2 || len(got.Models) != 2 {
+		t.Fatalf("decoded footer = %+v", got)
+	}
+}
+
+func TestTimingsSummaryPricesKnownModels(t *testing.T) {
+	timings.Enable()
+	t.Cleanup(timings.Disable)
+
+	call := timings.Begin(timings.LLM, "ollama", "llama3")
+	call.Tokens(10, 10)
+	call.End()
+	if s := timingsSummary(); s.CostKnown || s.CostUSD != 0 {
+		t.Fatalf("local model should leave cost unknown: %+v", s)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hurttlocker/cortex/internal/tokens"
+)
+
+func tokensUsageText() string {
+	return `Usage: cortex tokens count <file|-> [file...] [--model <provider/model>|--family <name>] [--json]
+
+Approximate how many tokens a model would see for each file ("-" reads
+stdin). The estimate follows the model family's tokenizer (cl100k, o200k,
+claude, gemini, llama), so code and CJK text are not undercounted the way a
+chars/4 estimate undercounts them. Without --model or --family, every family
+is shown.`
+}
+
+type tokenCountEntry struct {
+	File   string         `json:"file"`
+	Chars  int            `json:"chars"`
+	Bytes  int            `json:"bytes"`
+	Tokens map[string]int `json:"tokens"`
+}
+
+func runTokens(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(tokensUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "count":
+		return runTokensCount(args[1:], os.Stdin)
+	default:
+		return fmt.Errorf("unknown tokens subcommand: %s", args[0])
+	}
+}
+
+func runTokensCount(args []string, stdin io.Reader) error {
+	var files []string
+	families := tokens.Families
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--model" && i+1 < len(args):
+			i++
+			families = []tokens.Family{tokens.ForModel(args[i])}
+		case strings.HasPrefix(args[i], "--model="):
+			families = []tokens.Family{tokens.ForModel(strings.TrimPrefix(args[i], "--model="))}
+		case args[i] == "--family" && i+1 < len(args):
+			i++
+			f, ok := tokens.ParseFamily(args[i])
+			if !ok {
+				return fmt.Errorf("unknown --family %q (valid: %s)", args[i], joinTokenFamilies(tokens.Families))
+			}
+			families = []tokens.Family{f}
+		case strings.HasPrefix(args[i], "--family="):
+			v := strings.TrimPrefix(args[i], "--family=")
+			f, ok := tokens.ParseFamily(v)
+			if !ok {
+				return fmt.Errorf("unknown --family %q (valid: %s)", v, joinTokenFamilies(tokens.Families))
+			}
+			families = []tokens.Family{f}
+		case args[i] == "--json":
+			jsonOutput = true
+		case args[i] == "-":
+			files = append(files, args[i])
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		default:
+			files = append(files, args[i])
+		}
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("usage: cortex tokens count <file|-> [file...] [--model <provider/model>|--family <name>] [--json]")
+	}
+
+	entries := make([]tokenCountEntry, 0, len(files))
+	for _, file := range files {
+		var data []byte
+		var err error
+		if file == "-" {
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", file, err)
+		}
+		text := string(data)
+		entry := tokenCountEntry{File: file, Chars: utf8.RuneCountInString(text), Bytes: len(data), Tokens: map[string]int{}}
+		for _, f := range families {
+			entry.Tokens[string(f)] = f.Count(text)
+		}
+		entries = append(entries, entry)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	for _, e := range entries {
+		fmt.Printf("%s: %d chars, %d bytes (chars/4 ≈ %d)\n", e.File, e.Chars, e.Bytes, (e.Bytes+3)/4)
+		for _, f := range families {
+			fmt.Printf("  %-8s %8d tokens\n", f, e.Tokens[string(f)])
+		}
+	}
+	return nil
+}
+
+func joinTokenFamilies(families []tokens.Family) string {
+	names := make([]string, len(families))
+	for i, f := range families {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunTokensCount(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "notes.md")
+	if err := os.WriteFile(file, []byte("The quick brown fox jumps over the lazy dog"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(func() {
+		if err := runTokensCount([]string{file}, nil); err != nil {
+			t.Fatalf("count: %v", err)
+		}
+	})
+	for _, want := range []string{"43 chars", "cl100k", "o200k", "claude", "gemini", "llama", "9 tokens"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	out = captureStdout(func() {
+		if err := runTokensCount([]string{"-", "--model", "openrouter/anthropic/claude-sonnet-4", "--json"}, strings.NewReader("数据库故障")); err != nil {
+			t.Fatalf("count stdin: %v", err)
+		}
+	})
+	var entries []tokenCountEntry
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out)
+	}
+	if len(entries) != 1 || entries[0].File != "-" || entries[0].Chars != 5 || len(entries[0].Tokens) != 1 || entries[0].Tokens["claude"] < 5 {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	for _, args := range [][]string{{}, {file, "--family", "bogus"}, {file, "--bogus"}, {filepath.Join(dir, "missing.md")}} {
+		if err := runTokensCount(args, nil); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
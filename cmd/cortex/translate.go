@@ -0,0 +1,350 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/extract"
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const translateUsage = "usage: cortex translate --to <lang> [--project <name>] [--llm <provider/model>] [--limit N] [--dry-run] [--json]"
+
+// translatePageSize is how many memories are listed per store query.
+const translatePageSize = 500
+
+// translateOptions configures one cortex translate run.
+type translateOptions struct {
+	Lang    string
+	Project string
+	Limit   int // memories to send to the LLM (0 = all)
+	DryRun  bool
+}
+
+// translateReport is the result (and --json shape) of cortex translate.
+type translateReport struct {
+	Lang               string   `json:"lang"`
+	Model              string   `json:"model,omitempty"`
+	Pending            int      `json:"pending"` // memories with something left to translate
+	MemoriesTranslated int      `json:"memories_translated"`
+	FactsTranslated    int      `json:"facts_translated"`
+	AlreadyInLang      int      `json:"already_in_lang"`
+	HeldBack           int      `json:"held_back,omitempty"` // local-llm-only memories
+	Errors             []string `json:"errors,omitempty"`
+	DryRun             bool     `json:"dry_run,omitempty"`
+}
+
+// translateCandidate is a memory with something still untranslated: the
+// memory itself, or facts extracted from it after it was translated.
+type translateCandidate struct {
+	memory *store.Memory
+	facts  []*store.Fact // untranslated active facts
+	done   bool          // the memory itself is already handled
+}
+
+// runTranslate translates memories and their facts into one language,
+// keeping the originals and linking each translation to them.
+func runTranslate(args []string) error {
+	opts := translateOptions{}
+	llmFlag := ""
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--to" && i+1 < len(args):
+			i++
+			opts.Lang = args[i]
+		case strings.HasPrefix(args[i], "--to="):
+			opts.Lang = strings.TrimPrefix(args[i], "--to=")
+		case args[i] == "--project" && i+1 < len(args):
+			i++
+			opts.Project = args[i]
+		case strings.HasPrefix(args[i], "--project="):
+			opts.Project = strings.TrimPrefix(args[i], "--project=")
+		case args[i] == "--llm" && i+1 < len(args):
+			i++
+			llmFlag = args[i]
+		case strings.HasPrefix(args[i], "--llm="):
+			llmFlag = strings.TrimPrefix(args[i], "--llm=")
+		case args[i] == "--limit" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid --limit: %s", args[i])
+			}
+			opts.Limit = n
+		case args[i] == "--dry-run" || args[i] == "-n":
+			opts.DryRun = true
+		case args[i] == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s\n%s", args[i], translateUsage)
+		default:
+			return fmt.Errorf("unexpected argument: %s\n%s", args[i], translateUsage)
+		}
+	}
+	opts.Lang = store.NormalizeLang(opts.Lang)
+	if opts.Lang == "" {
+		return fmt.Errorf("%s", translateUsage)
+	}
+
+	if llmFlag == "" && !opts.DryRun {
+		resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		llmFlag = strings.TrimSpace(resolvedCfg.EffectiveLLMModel("translate", "").Value)
+		if llmFlag == "" {
+			return fmt.Errorf("translate needs an LLM: pass --llm <provider/model> or set llm.provider")
+		}
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("translate requires SQLite store")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var provider llm.Provider
+	if !opts.DryRun {
+		llmCfg, err := llm.ParseLLMFlag(llmFlag)
+		if err != nil {
+			return fmt.Errorf("parsing --llm: %w", err)
+		}
+		scrubbed, err := newScrubbedProvider(llmCfg)
+		if err != nil {
+			return fmt.Errorf("creating LLM provider: %w", err)
+		}
+		defer reportScrubbed(scrubbed)
+		provider = scrubbed
+	}
+
+	report, err := translateStore(ctx, sqlStore, provider, opts)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	if report.DryRun {
+		fmt.Printf("%d memories have content to translate into %q", report.Pending, report.Lang)
+		if report.HeldBack > 0 {
+			fmt.Printf(" (%d more held back: local-llm-only projects)", report.HeldBack)
+		}
+		fmt.Println(". Nothing was sent to an LLM (dry run).")
+		return nil
+	}
+	fmt.Printf("Translated into %q with %s:\n", report.Lang, report.Model)
+	fmt.Printf("  Memories translated: %d\n", report.MemoriesTranslated)
+	fmt.Printf("  Facts translated:    %d\n", report.FactsTranslated)
+	fmt.Printf("  Already in %s:       %d\n", report.Lang, report.AlreadyInLang)
+	if report.HeldBack > 0 {
+		fmt.Printf("  Held back:           %d (local-llm-only projects)\n", report.HeldBack)
+	}
+	if n := len(report.Errors); n > 0 {
+		fmt.Printf("  Errors:              %d (rerun to retry)\n", n)
+		for _, e := range report.Errors {
+			fmt.Printf("    - %s\n", e)
+		}
+	}
+	return nil
+}
+
+// translateStore translates every pending memory (up to opts.Limit) with
+// provider. A dry run only counts them; provider may then be nil. A failed
+// memory is reported and left pending for the next run.
+func translateStore(ctx context.Context, s *store.SQLiteStore, provider llm.Provider, opts translateOptions) (*translateReport, error) {
+	report := &translateReport{Lang: opts.Lang, DryRun: opts.DryRun}
+	if provider != nil {
+		report.Model = provider.Name()
+	}
+	candidates, err := translateCandidates(ctx, s, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	memories := make([]*store.Memory, len(candidates))
+	for i, c := range candidates {
+		memories[i] = c.memory
+	}
+	// Translation providers are remote; local-llm-only projects are skipped.
+	allowed, held := store.FilterMemoriesByPrivacy(memories, store.PrivacyLocalLLMOnly)
+	report.HeldBack = held
+	keep := make(map[int64]bool, len(allowed))
+	for _, m := range allowed {
+		keep[m.ID] = true
+	}
+	pending := candidates[:0]
+	for _, c := range candidates {
+		if keep[c.memory.ID] {
+			pending = append(pending, c)
+		}
+	}
+	if opts.Limit > 0 && len(pending) > opts.Limit {
+		pending = pending[:opts.Limit]
+	}
+	report.Pending = len(pending)
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for _, c := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := translateCandidateMemory(ctx, s, provider, opts.Lang, c, report); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("memory %d: %v", c.memory.ID, err))
+		}
+	}
+	return report, nil
+}
+
+// translateCandidates lists the memories in opts.Project with something
+// not yet translated into opts.Lang. Translations themselves are skipped.
+func translateCandidates(ctx context.Context, s *store.SQLiteStore, opts translateOptions) ([]translateCandidate, error) {
+	doneMemories, err := s.TranslatedOriginals(ctx, store.TranslationKindMemory, opts.Lang)
+	if err != nil {
+		return nil, err
+	}
+	doneFacts, err := s.TranslatedOriginals(ctx, store.TranslationKindFact, opts.Lang)
+	if err != nil {
+		return nil, err
+	}
+	memoryTranslations, err := s.TranslationIDs(ctx, store.TranslationKindMemory)
+	if err != nil {
+		return nil, err
+	}
+	factTranslations, err := s.TranslationIDs(ctx, store.TranslationKindFact)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []translateCandidate
+	for offset := 0; ; offset += translatePageSize {
+		page, err := s.ListMemories(ctx, store.ListOpts{Limit: translatePageSize, Offset: offset, Project: opts.Project})
+		if err != nil {
+			return nil, fmt.Errorf("listing memories: %w", err)
+		}
+		ids := make([]int64, 0, len(page))
+		for _, m := range page {
+			if !memoryTranslations[m.ID] {
+				ids = append(ids, m.ID)
+			}
+		}
+		facts, err := s.ListFactsByMemoryIDs(ctx, ids, "", 100*translatePageSize)
+		if err != nil {
+			return nil, fmt.Errorf("listing facts: %w", err)
+		}
+		byMemory := map[int64][]*store.Fact{}
+		for _, f := range facts {
+			if doneFacts[f.ID] || factTranslations[f.ID] || f.State == store.FactStateRetired || f.State == store.FactStateSuperseded {
+				continue
+			}
+			byMemory[f.MemoryID] = append(byMemory[f.MemoryID], f)
+		}
+		for _, m := range page {
+			if memoryTranslations[m.ID] {
+				continue
+			}
+			c := translateCandidate{memory: m, facts: byMemory[m.ID], done: doneMemories[m.ID]}
+			if !c.done || len(c.facts) > 0 {
+				out = append(out, c)
+			}
+		}
+		if len(page) < translatePageSize {
+			return out, nil
+		}
+	}
+}
+
+// translateCandidateMemory sends one memory and its pending facts to the
+// LLM and stores what comes back.
+func translateCandidateMemory(ctx context.Context, s *store.SQLiteStore, provider llm.Provider, lang string, c translateCandidate, report *translateReport) error {
+	model := provider.Name()
+	translatedMemoryID := int64(0)
+	if c.done {
+		t, err := s.TranslationFor(ctx, store.TranslationKindMemory, c.memory.ID, lang)
+		if err != nil {
+			return err
+		}
+		if t != nil && t.TranslationID == 0 {
+			// The memory is already in lang, so facts extracted from it
+			// later are too.
+			for _, f := range c.facts {
+				if err := s.MarkInLanguage(ctx, store.TranslationKindFact, f.ID, lang, model); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if t != nil {
+			translatedMemoryID = t.TranslationID
+		}
+	}
+
+	facts := make([]extract.TranslateFactInput, 0, len(c.facts))
+	byID := make(map[int64]*store.Fact, len(c.facts))
+	for _, f := range c.facts {
+		facts = append(facts, extract.TranslateFactInput{ID: f.ID, Subject: f.Subject, Predicate: f.Predicate, Object: f.Object})
+		byID[f.ID] = f
+	}
+	res, err := extract.TranslateMemory(ctx, provider, extract.NewTranslatePromptData(lang, c.memory.Content, facts))
+	if err != nil {
+		return err
+	}
+
+	if store.NormalizeLang(res.SourceLang) == lang {
+		if !c.done {
+			if err := s.MarkInLanguage(ctx, store.TranslationKindMemory, c.memory.ID, lang, model); err != nil {
+				return err
+			}
+		}
+		for _, f := range c.facts {
+			if err := s.MarkInLanguage(ctx, store.TranslationKindFact, f.ID, lang, model); err != nil {
+				return err
+			}
+		}
+		report.AlreadyInLang++
+		return nil
+	}
+
+	if !c.done {
+		if res.Text == "" {
+			return fmt.Errorf("LLM returned no translation")
+		}
+		id, err := s.AddMemoryTranslation(ctx, c.memory.ID, lang, res.SourceLang, model, res.Text)
+		if err != nil {
+			return err
+		}
+		translatedMemoryID = id
+		report.MemoriesTranslated++
+	}
+	for _, tf := range res.Facts {
+		if byID[tf.ID] == nil {
+			continue
+		}
+		translated := &store.Fact{MemoryID: translatedMemoryID, Subject: tf.Subject, Predicate: tf.Predicate, Object: tf.Object}
+		if _, err := s.AddFactTranslation(ctx, tf.ID, translated, lang, res.SourceLang, model); err != nil {
+			return err
+		}
+		report.FactsTranslated++
+	}
+	return nil
+}
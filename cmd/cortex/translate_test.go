@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// translateStub answers German memories with a translation and anything
+// else as already English.
+type translateStub struct{ calls int }
+
+func (p *translateStub) Complete(_ context.Context, prompt string, _ llm.CompletionOpts) (string, error) {
+	p.calls++
+	if strings.Contains(prompt, "wohnt") {
+		return `{"source_lang":"de","text":"Alice lives in Berlin.","facts":[{"id":1,"subject":"Alice","predicate":"lives_in","object":"Berlin"}]}`, nil
+	}
+	return `{"source_lang":"en"}`, nil
+}
+
+func (p *translateStub) Name() string { return "stub/translate" }
+
+func TestTranslateStore_TranslatesOnce(t *testing.T) {
+	s, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	sqlStore := s.(*store.SQLiteStore)
+	ctx := context.Background()
+
+	deID, _ := s.AddMemory(ctx, &store.Memory{Content: "Alice wohnt in Berlin.", SourceFile: "de.md", Project: "home"})
+	factID, _ := s.AddFact(ctx, &store.Fact{MemoryID: deID, Subject: "Alice", Predicate: "wohnt_in", Object: "Berlin", FactType: "location", Confidence: 0.9})
+	if factID != 1 {
+		t.Fatalf("stub expects fact 1, got %d", factID)
+	}
+	s.AddMemory(ctx, &store.Memory{Content: "Bob likes tea.", SourceFile: "en.md", Project: "home"})
+	s.AddMemory(ctx, &store.Memory{Content: "Carol mag Kaffee.", SourceFile: "other.md", Project: "work"})
+
+	opts := translateOptions{Lang: "en", Project: "home"}
+	dry, err := translateStore(ctx, sqlStore, nil, translateOptions{Lang: "en", Project: "home", DryRun: true})
+	if err != nil || dry.Pending != 2 {
+		t.Fatalf("dry run: %+v, %v", dry, err)
+	}
+
+	provider := &translateStub{}
+	report, err := translateStore(ctx, sqlStore, provider, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.MemoriesTranslated != 1 || report.FactsTranslated != 1 || report.AlreadyInLang != 1 || len(report.Errors) != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if tr, _ := sqlStore.TranslationFor(ctx, store.TranslationKindFact, factID, "en"); tr == nil || tr.TranslationID == 0 {
+		t.Fatalf("expected fact %d translated, got %+v", factID, tr)
+	}
+
+	// A second run has nothing left: translations are not themselves
+	// candidates, and the English memory was recorded as such.
+	provider.calls = 0
+	report, err = translateStore(ctx, sqlStore, provider, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Pending != 0 || provider.calls != 0 {
+		t.Fatalf("expected nothing pending, got %+v after %d calls", report, provider.calls)
+	}
+}
+
+func TestRunTranslate_Flags(t *testing.T) {
+	if err := runTranslate(nil); err == nil || !strings.Contains(err.Error(), "--to <lang>") {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+	if err := runTranslate([]string{"--to", "en", "--bogus"}); err == nil || !strings.Contains(err.Error(), "unknown flag: --bogus") {
+		t.Fatalf("expected unknown flag error, got %v", err)
+	}
+	if err := runTranslate([]string{"--to", "en", "--limit", "x"}); err == nil || !strings.Contains(err.Error(), "invalid --limit") {
+		t.Fatalf("expected invalid limit error, got %v", err)
+	}
+}
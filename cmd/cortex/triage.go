@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func triageUsageText() string {
+	return `Usage:
+  cortex triage [--kept|--dropped] [--source <path>] [--limit N] [--json]
+
+Shows the audit log of LLM import triage (` + "`cortex import --triage`" + `): the
+novelty, durability, and specificity scores of each chunk, the threshold it
+was held to, and whether it became a memory. Dropped chunks keep a short
+excerpt so the decision can be reviewed; re-import the file without --triage
+to keep something the model dropped.`
+}
+
+// runTriage handles `cortex triage`.
+func runTriage(args []string) error {
+	if len(args) > 0 && (args[0] == "--help" || args[0] == "-h" || args[0] == "help") {
+		fmt.Println(triageUsageText())
+		return nil
+	}
+
+	filter := store.ImportTriageFilter{Limit: 50}
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--kept":
+			filter.Decision = store.TriageKept
+		case args[i] == "--dropped":
+			filter.Decision = store.TriageDropped
+		case args[i] == "--json":
+			jsonOutput = true
+		case args[i] == "--source" && i+1 < len(args):
+			i++
+			filter.SourceFile = args[i]
+		case strings.HasPrefix(args[i], "--source="):
+			filter.SourceFile = strings.TrimPrefix(args[i], "--source=")
+		case args[i] == "--limit" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid --limit value: %s", args[i])
+			}
+			filter.Limit = n
+		case strings.HasPrefix(args[i], "--limit="):
+			n, err := strconv.Atoi(strings.TrimPrefix(args[i], "--limit="))
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid --limit value: %s", args[i])
+			}
+			filter.Limit = n
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+	defer s.Close()
+	ss, ok := s.(*store.SQLiteStore)
+	if !ok {
+		return fmt.Errorf("triage requires SQLiteStore")
+	}
+	records, err := ss.ListImportTriage(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		if records == nil {
+			records = []store.ImportTriageRecord{}
+		}
+		data, _ := json.MarshalIndent(records, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(records) == 0 {
+		fmt.Println("No triage decisions recorded. Import with --triage to score chunks before they become memories.")
+		return nil
+	}
+	for _, r := range records {
+		where := r.SourceFile
+		if r.SourceLine > 0 {
+			where = fmt.Sprintf("%s:%d", where, r.SourceLine)
+		}
+		target := "dropped"
+		if r.Decision == store.TriageKept {
+			target = fmt.Sprintf("kept as memory #%d", r.MemoryID)
+			if r.MemoryID == 0 {
+				target = "kept (memory deleted)"
+			}
+		}
+		fmt.Printf("%.2f/%.2f  %s  %s\n", r.Score, r.Threshold, target, where)
+		fmt.Printf("  novelty %.2f · durability %.2f · specificity %.2f  (%s, %s)\n",
+			r.Novelty, r.Durability, r.Specificity, r.Model, r.CreatedAt.Format("2006-01-02 15:04"))
+		if r.Reason != "" {
+			fmt.Printf("  reason: %s\n", r.Reason)
+		}
+		fmt.Printf("  %s\n", truncateString(strings.ReplaceAll(r.Excerpt, "\n", " "), 120))
+	}
+	fmt.Printf("\n%d %s\n", len(records), pluralize("decision", "decisions", len(records)))
+	return nil
+}
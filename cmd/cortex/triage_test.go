@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunTriage_ListsAndFiltersDecisions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	oldDBPath := globalDBPath
+	globalDBPath = filepath.Join(home, "cortex.db")
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+
+	ctx := context.Background()
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss := s.(*store.SQLiteStore)
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "We moved the ledger to Postgres.", SourceFile: "notes.md"})
+	for _, r := range []*store.ImportTriageRecord{
+		{MemoryID: memID, SourceFile: "notes.md", SourceLine: 3, Excerpt: "We moved the ledger to Postgres.", Score: 0.8, Threshold: 0.5, Decision: store.TriageKept, Model: "fake/m"},
+		{SourceFile: "chat.log", Excerpt: "ok sounds good", Score: 0.1, Threshold: 0.5, Decision: store.TriageDropped, Reason: "chatter", Model: "fake/m"},
+	} {
+		if err := ss.RecordImportTriage(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	var runErr error
+	out := captureStdout(func() { runErr = runTriage(nil) })
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+	if !strings.Contains(out, "kept as memory #") || !strings.Contains(out, "notes.md:3") ||
+		!strings.Contains(out, "reason: chatter") || !strings.Contains(out, "2 decisions") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	out = captureStdout(func() { runErr = runTriage([]string{"--dropped", "--json"}) })
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+	var records []store.ImportTriageRecord
+	if err := json.Unmarshal([]byte(out), &records); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(records) != 1 || records[0].SourceFile != "chat.log" {
+		t.Fatalf("--dropped should list only dropped chunks: %+v", records)
+	}
+
+	if err := runTriage([]string{"extra"}); err == nil || !strings.Contains(err.Error(), "unexpected argument") {
+		t.Fatalf("expected unexpected argument error, got %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const watchUsage = `usage: cortex watch <command>
+
+Commands:
+  subject "<subject>" [--agent <id>]     Notify when facts about <subject> are
+                                         added, superseded, or conflict
+  list [--json]                          Subscriptions in the active namespace
+  remove <id>                            Delete a subscription
+  events [--since 24h] [--limit N] [--json]
+                                         Recent subscription notifications
+
+Notifications are stored as "subscription" alerts, POSTed to the alert
+webhook (CORTEX_ALERT_WEBHOOK_URL), and pushed to connected MCP clients.`
+
+// runWatch handles `cortex watch`: persisted subject subscriptions that raise
+// alerts when knowledge about a subject changes.
+func runWatch(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", watchUsage)
+	}
+	switch args[0] {
+	case "subject":
+		return runWatchSubject(args[1:])
+	case "list", "ls":
+		return runWatchList(args[1:])
+	case "remove", "rm":
+		return runWatchRemove(args[1:])
+	case "events":
+		return runWatchEvents(args[1:])
+	case "--help", "-h", "help":
+		fmt.Println(watchUsage)
+		return nil
+	default:
+		return fmt.Errorf("unknown watch command: %s\n%s", args[0], watchUsage)
+	}
+}
+
+func runWatchSubject(args []string) error {
+	var subject, agentID string
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--agent":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", arg)
+			}
+			i++
+			agentID = args[i]
+		case strings.HasPrefix(arg, "--agent="):
+			agentID = strings.TrimPrefix(arg, "--agent=")
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case subject == "":
+			subject = arg
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if strings.TrimSpace(subject) == "" {
+		return fmt.Errorf("watch subject needs a subject\n%s", watchUsage)
+	}
+
+	sqlStore, closeStore, err := openWatchStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	sub := &store.SubjectSubscription{Subject: subject, AgentID: agentID}
+	if err := sqlStore.CreateSubscription(context.Background(), sub); err != nil {
+		return err
+	}
+
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sub)
+	}
+	fmt.Printf("Watching %q (subscription #%d, namespace %s).\n", sub.Subject, sub.ID, sub.Namespace)
+	return nil
+}
+
+func runWatchList(args []string) error {
+	jsonOutput := false
+	for _, arg := range args {
+		switch {
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	sqlStore, closeStore, err := openWatchStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	subs, err := sqlStore.ListSubscriptions(context.Background())
+	if err != nil {
+		return err
+	}
+	if subs == nil {
+		subs = []*store.SubjectSubscription{}
+	}
+
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(subs)
+	}
+	if len(subs) == 0 {
+		fmt.Println("No subscriptions. Add one with: cortex watch subject \"<subject>\"")
+		return nil
+	}
+	fmt.Printf("  %-5s %-32s %-12s %-12s %8s  %s\n", "ID", "SUBJECT", "AGENT", "NAMESPACE", "NOTIFIED", "LAST")
+	for _, sub := range subs {
+		agent := sub.AgentID
+		if agent == "" {
+			agent = "-"
+		}
+		last := "never"
+		if sub.LastNotifiedAt != nil {
+			last = sub.LastNotifiedAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Printf("  %-5d %-32s %-12s %-12s %8d  %s\n", sub.ID, truncateString(sub.Subject, 32), agent, sub.Namespace, sub.NotifyCount, last)
+	}
+	return nil
+}
+
+func runWatchRemove(args []string) error {
+	if len(args) != 1 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: cortex watch remove <id>")
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid subscription id: %s", args[0])
+	}
+
+	sqlStore, closeStore, err := openWatchStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	if err := sqlStore.RemoveSubscription(context.Background(), id); err != nil {
+		return err
+	}
+	fmt.Printf("Removed subscription #%d.\n", id)
+	return nil
+}
+
+func runWatchEvents(args []string) error {
+	since := "24h"
+	limit := 50
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--since" || arg == "--limit":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", arg)
+			}
+			i++
+			if arg == "--since" {
+				since = args[i]
+			} else {
+				n, err := strconv.Atoi(args[i])
+				if err != nil || n <= 0 {
+					return fmt.Errorf("invalid --limit value: %s", args[i])
+				}
+				limit = n
+			}
+		case strings.HasPrefix(arg, "--since="):
+			since = strings.TrimPrefix(arg, "--since=")
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	window, err := parseSinceDuration(since)
+	if err != nil {
+		return fmt.Errorf("invalid --since value: %w", err)
+	}
+	cutoff := time.Now().UTC().Add(-window)
+
+	sqlStore, closeStore, err := openWatchStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+	alerts, err := sqlStore.ListAlerts(context.Background(), store.AlertFilter{
+		Type:           store.AlertTypeSubscription,
+		Limit:          limit,
+		SinceCreatedAt: &cutoff,
+	})
+	if err != nil {
+		return err
+	}
+	if alerts == nil {
+		alerts = []store.Alert{}
+	}
+
+	if jsonOutput || !isTTY() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(alerts)
+	}
+	if len(alerts) == 0 {
+		fmt.Printf("No subscription notifications in the last %s.\n", since)
+		return nil
+	}
+	for _, a := range alerts {
+		fmt.Printf("  %s  %s\n", a.CreatedAt.Local().Format("2006-01-02 15:04"), a.Message)
+	}
+	return nil
+}
+
+func openWatchStore() (*store.SQLiteStore, func(), error) {
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening store: %w", err)
+	}
+	sqlStore, ok := s.(*store.SQLiteStore)
+	if !ok {
+		s.Close()
+		return nil, nil, fmt.Errorf("watch commands require SQLiteStore")
+	}
+	return sqlStore, func() { s.Close() }, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRunWatch(t *testing.T) {
+	useWorkspaceHome(t)
+	globalDBPath = filepath.Join(t.TempDir(), "cortex.db")
+	t.Cleanup(func() { globalDBPath = "" })
+	t.Setenv("CORTEX_NAMESPACE", "")
+
+	var runErr error
+	out := captureStdout(func() { runErr = runWatch([]string{"subject", "Deployment  Policy", "--json"}) })
+	if runErr != nil {
+		t.Fatalf("watch subject: %v", runErr)
+	}
+	var sub store.SubjectSubscription
+	if err := json.Unmarshal([]byte(out), &sub); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if sub.ID == 0 || sub.Subject != "deployment policy" || sub.Namespace != "default" {
+		t.Fatalf("subscription = %+v", sub)
+	}
+
+	s, err := store.NewStore(getStoreConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	memID, err := s.AddMemory(ctx, &store.Memory{Content: "deploys need two approvals", SourceFile: "/ops/policy.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deployment policy", Predicate: "requires", Object: "two approvals", FactType: "kv", Confidence: 0.9}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	out = captureStdout(func() { runErr = runWatch([]string{"events", "--since", "1h", "--json"}) })
+	if runErr != nil {
+		t.Fatalf("watch events: %v", runErr)
+	}
+	var alerts []store.Alert
+	if err := json.Unmarshal([]byte(out), &alerts); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(alerts) != 1 || !strings.Contains(alerts[0].Message, "added") {
+		t.Fatalf("alerts = %+v", alerts)
+	}
+
+	out = captureStdout(func() { runErr = runWatch([]string{"list", "--json"}) })
+	var subs []store.SubjectSubscription
+	if err := json.Unmarshal([]byte(out), &subs); runErr != nil || err != nil {
+		t.Fatalf("watch list = %q, %v, %v", out, runErr, err)
+	}
+	if len(subs) != 1 || subs[0].NotifyCount != 1 {
+		t.Fatalf("subscriptions = %+v", subs)
+	}
+
+	out = captureStdout(func() { runErr = runWatch([]string{"remove", "1"}) })
+	if runErr != nil || !strings.Contains(out, "Removed subscription #1.") {
+		t.Fatalf("watch remove = %q, %v", out, runErr)
+	}
+	if err := runWatch([]string{"remove", "1"}); err == nil {
+		t.Fatal("removing a missing subscription should fail")
+	}
+	if err := runWatch([]string{"subject"}); err == nil {
+		t.Fatal("watch subject without a subject should fail")
+	}
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+)
+
+func workspaceUsageText() string {
+	return `Usage: cortex workspace <add|list|use|remove> [flags]
+
+Named workspaces bundle a database with its model defaults in
+~/.cortex/config.yaml, so 'cortex --workspace work search ...' replaces
+'--db ... --embed ... --llm ...'.
+
+Subcommands:
+  add <name> --db <path> [--embed <provider/model>] [--llm <provider/model>] [--weight <n>] [--use] [--force]
+                        Define a workspace (--use also makes it active;
+                        --force replaces an existing definition; --weight
+                        scales its hits in federated search)
+  list [--json]         List workspaces; * marks the active one
+  use <name>            Make a workspace active for every command
+  use --clear           Go back to the top-level config
+  remove <name>         Delete a workspace definition
+
+Precedence: config.yaml < active workspace < env (CORTEX_DB, CORTEX_LLM,
+CORTEX_EMBED) < --workspace / CORTEX_WORKSPACE < --db / --llm / --embed.
+
+Federated search: 'cortex --workspace work,personal search <query>' searches
+several workspaces at once and merges the results by weighted rank fusion.
+Inline weights override configured ones: --workspace work:2,personal.`
+}
+
+// checkWorkspaceSelection fails fast when --workspace, CORTEX_WORKSPACE, or
+// the config's active workspace names a profile that does not exist, rather
+// than letting commands fall back to the default database.
+func checkWorkspaceSelection() error {
+	if _, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{}); errors.Is(err, cfgresolver.ErrUnknownWorkspace) {
+		return err
+	}
+	return nil
+}
+
+func runWorkspace(args []string) error {
+	if len(args) < 1 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		fmt.Println(workspaceUsageText())
+		return nil
+	}
+	switch args[0] {
+	case "add":
+		return runWorkspaceAdd(args[1:])
+	case "list", "ls":
+		return runWorkspaceList(args[1:])
+	case "use":
+		return runWorkspaceUse(args[1:])
+	case "remove", "rm":
+		return runWorkspaceRemove(args[1:])
+	default:
+		return fmt.Errorf("unknown workspace subcommand: %s", args[0])
+	}
+}
+
+func runWorkspaceAdd(args []string) error {
+	name, dbPath, embedFlag, llmFlag := "", "", "", ""
+	use, force := false, false
+	weight := 0.0
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--db" && i+1 < len(args):
+			i++
+			dbPath = args[i]
+		case strings.HasPrefix(args[i], "--db="):
+			dbPath = strings.TrimPrefix(args[i], "--db=")
+		case args[i] == "--embed" && i+1 < len(args):
+			i++
+			embedFlag = args[i]
+		case strings.HasPrefix(args[i], "--embed="):
+			embedFlag = strings.TrimPrefix(args[i], "--embed=")
+		case args[i] == "--llm" && i+1 < len(args):
+			i++
+			llmFlag = args[i]
+		case strings.HasPrefix(args[i], "--llm="):
+			llmFlag = strings.TrimPrefix(args[i], "--llm=")
+		case args[i] == "--weight" && i+1 < len(args):
+			i++
+			w, err := parseWorkspaceWeight(args[i])
+			if err != nil {
+				return err
+			}
+			weight = w
+		case strings.HasPrefix(args[i], "--weight="):
+			w, err := parseWorkspaceWeight(strings.TrimPrefix(args[i], "--weight="))
+			if err != nil {
+				return err
+			}
+			weight = w
+		case args[i] == "--use":
+			use = true
+		case args[i] == "--force":
+			force = true
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		case name == "":
+			name = args[i]
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	if name == "" || strings.TrimSpace(dbPath) == "" {
+		return fmt.Errorf("usage: cortex workspace add <name> --db <path> [--embed <provider/model>] [--llm <provider/model>] [--use]")
+	}
+	if !cfgresolver.ValidWorkspaceName(name) {
+		return fmt.Errorf("invalid workspace name %q (letters, digits, '.', '_' or '-')", name)
+	}
+	dbPath = strings.TrimSpace(dbPath)
+	if !strings.HasPrefix(dbPath, "~/") {
+		// Config is read from any directory, so relative paths would drift.
+		abs, err := filepath.Abs(dbPath)
+		if err != nil {
+			return fmt.Errorf("resolving --db: %w", err)
+		}
+		dbPath = abs
+	}
+
+	cfg, cfgPath, err := loadMutableConfig("")
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	workspaces := getNestedMap(cfg, "workspaces")
+	if _, exists := workspaces[name]; exists && !force {
+		return fmt.Errorf("workspace %q already exists (use --force to replace it)", name)
+	}
+	entry := map[string]any{"db": dbPath}
+	if v := strings.TrimSpace(embedFlag); v != "" {
+		entry["embed"] = v
+	}
+	if v := strings.TrimSpace(llmFlag); v != "" {
+		entry["llm"] = v
+	}
+	if weight > 0 {
+		entry["weight"] = weight
+	}
+	workspaces[name] = entry
+	if use {
+		cfg["workspace"] = name
+	}
+	if err := writeMutableConfig(cfgPath, cfg); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+
+	fmt.Printf("Added workspace %q → %s\n", name, dbPath)
+	if use {
+		fmt.Printf("Active workspace: %s\n", name)
+	} else {
+		fmt.Printf("Use it with: cortex --workspace %s <command>  (or: cortex workspace use %s)\n", name, name)
+	}
+	return nil
+}
+
+type workspaceView struct {
+	Name   string  `json:"name"`
+	Active bool    `json:"active"`
+	DB     string  `json:"db"`
+	Embed  string  `json:"embed,omitempty"`
+	LLM    string  `json:"llm,omitempty"`
+	Weight float64 `json:"weight,omitempty"`
+}
+
+func runWorkspaceList(args []string) error {
+	jsonOutput := false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOutput = true
+		default:
+			return fmt.Errorf("unknown flag: %s", arg)
+		}
+	}
+
+	resolved, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+	if err != nil && !errors.Is(err, cfgresolver.ErrUnknownWorkspace) {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	names := make([]string, 0, len(resolved.Workspaces))
+	for name := range resolved.Workspaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	views := make([]workspaceView, 0, len(names))
+	for _, name := range names {
+		ws := resolved.Workspaces[name]
+		views = append(views, workspaceView{
+			Name:   name,
+			Active: name == resolved.Workspace.Value,
+			DB:     ws.DB,
+			Embed:  ws.Embed,
+			LLM:    ws.LLM,
+			Weight: ws.Weight,
+		})
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"active":     resolved.Workspace.Value,
+			"source":     resolved.Workspace.From,
+			"workspaces": views,
+		}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(views) == 0 {
+		fmt.Println("No workspaces defined. Add one with: cortex workspace add <name> --db <path>")
+		return nil
+	}
+	for _, v := range views {
+		marker := " "
+		if v.Active {
+			marker = "*"
+		}
+		fmt.Printf("%s %-16s %s\n", marker, v.Name, v.DB)
+		if v.Embed != "" {
+			fmt.Printf("  %-16s embed: %s\n", "", v.Embed)
+		}
+		if v.LLM != "" {
+			fmt.Printf("  %-16s llm:   %s\n", "", v.LLM)
+		}
+		if v.Weight > 0 {
+			fmt.Printf("  %-16s weight: %g\n", "", v.Weight)
+		}
+	}
+	if resolved.Workspace.Value != "" {
+		fmt.Printf("\nActive: %s (from %s)\n", resolved.Workspace.Value, resolved.Workspace.From)
+	}
+	if err != nil {
+		fmt.Printf("\nWarning: %v\n", err)
+	}
+	return nil
+}
+
+func runWorkspaceUse(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: cortex workspace use <name> | --clear")
+	}
+	cfg, cfgPath, err := loadMutableConfig("")
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	if args[0] == "--clear" {
+		delete(cfg, "workspace")
+		if err := writeMutableConfig(cfgPath, cfg); err != nil {
+			return fmt.Errorf("writing config: %w", err)
+		}
+		fmt.Println("Cleared active workspace; commands use the top-level config.")
+		return nil
+	}
+
+	name := args[0]
+	workspaces, _ := cfg["workspaces"].(map[string]any)
+	if _, ok := workspaces[name]; !ok {
+		return fmt.Errorf("%w %q; run `cortex workspace list`", cfgresolver.ErrUnknownWorkspace, name)
+	}
+	cfg["workspace"] = name
+	if err := writeMutableConfig(cfgPath, cfg); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	fmt.Printf("Active workspace: %s\n", name)
+	return nil
+}
+
+func runWorkspaceRemove(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: cortex workspace remove <name>")
+	}
+	name := args[0]
+	cfg, cfgPath, err := loadMutableConfig("")
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	workspaces, _ := cfg["workspaces"].(map[string]any)
+	if _, ok := workspaces[name]; !ok {
+		return fmt.Errorf("%w %q; run `cortex workspace list`", cfgresolver.ErrUnknownWorkspace, name)
+	}
+	delete(workspaces, name)
+	if len(workspaces) == 0 {
+		delete(cfg, "workspaces")
+	}
+	wasActive := cfg["workspace"] == name
+	if wasActive {
+		delete(cfg, "workspace")
+	}
+	if err := writeMutableConfig(cfgPath, cfg); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	fmt.Printf("Removed workspace %q (database left in place)\n", name)
+	if wasActive {
+		fmt.Println("It was active; commands now use the top-level config.")
+	}
+	return nil
+}
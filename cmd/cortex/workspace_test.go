@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+)
+
+func useWorkspaceHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CORTEX_WORKSPACE", "")
+	t.Setenv("CORTEX_DB", "")
+	t.Setenv("CORTEX_DB_PATH", "")
+	oldDBPath := globalDBPath
+	globalDBPath = ""
+	t.Cleanup(func() { globalDBPath = oldDBPath })
+	return home
+}
+
+func TestRunWorkspace_AddUseListRemove(t *testing.T) {
+	home := useWorkspaceHome(t)
+	workDB := filepath.Join(home, "work.db")
+
+	captureStdout(func() {
+		if err := runWorkspace([]string{"add", "work", "--db", workDB, "--embed", "ollama/nomic-embed-text", "--llm", "openrouter/deepseek/deepseek-v3.2"}); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+		if err := runWorkspace([]string{"add", "personal", "--db", "~/personal.db"}); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	})
+	if err := runWorkspace([]string{"add", "work", "--db", workDB}); err == nil {
+		t.Fatal("expected error re-adding without --force")
+	}
+
+	// Not active yet: --workspace (via CORTEX_WORKSPACE) selects it.
+	if got := getDBPath(); got == workDB {
+		t.Fatal("workspace should not apply before it is selected")
+	}
+	parseGlobalFlags([]string{"--workspace", "work", "stats"})
+	if got := getDBPath(); got != workDB {
+		t.Fatalf("getDBPath with --workspace = %q, want %q", got, workDB)
+	}
+	os.Setenv("CORTEX_WORKSPACE", "")
+
+	captureStdout(func() {
+		if err := runWorkspace([]string{"use", "personal"}); err != nil {
+			t.Fatalf("use: %v", err)
+		}
+	})
+	if got := getDBPath(); got != filepath.Join(home, "personal.db") {
+		t.Fatalf("active workspace DB = %q", got)
+	}
+
+	out := captureStdout(func() {
+		if err := runWorkspace([]string{"list"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	if !strings.Contains(out, "* personal") || !strings.Contains(out, "embed: ollama/nomic-embed-text") {
+		t.Fatalf("unexpected list output:\n%s", out)
+	}
+
+	if err := runWorkspace([]string{"use", "missing"}); !errors.Is(err, cfgresolver.ErrUnknownWorkspace) {
+		t.Fatalf("expected unknown workspace error, got %v", err)
+	}
+
+	captureStdout(func() {
+		if err := runWorkspace([]string{"remove", "personal"}); err != nil {
+			t.Fatalf("remove: %v", err)
+		}
+	})
+	resolved, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+	if err != nil {
+		t.Fatalf("config should stay valid after removing the active workspace: %v", err)
+	}
+	if resolved.Workspace.Value != "" || len(resolved.Workspaces) != 1 {
+		t.Fatalf("unexpected workspaces after remove: %+v", resolved.Workspaces)
+	}
+}
+
+func TestCheckWorkspaceSelection(t *testing.T) {
+	useWorkspaceHome(t)
+	if err := checkWorkspaceSelection(); err != nil {
+		t.Fatalf("no workspace selected should pass, got %v", err)
+	}
+	t.Setenv("CORTEX_WORKSPACE", "ghost")
+	if err := checkWorkspaceSelection(); !errors.Is(err, cfgresolver.ErrUnknownWorkspace) {
+		t.Fatalf("expected unknown workspace error, got %v", err)
+	}
+}
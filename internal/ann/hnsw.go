@@ -34,13 +34,17 @@ type Index struct {
 	EfSearch       int     // search-time beam width (default: 50)
 	LevelMult      float64 // level generation multiplier: 1/ln(M)
 
+	quant Quantization // vector representation ("" = none)
+	unmap func() error // releases the file mapping (LoadMmap only)
+
 	rng *rand.Rand
 }
 
 // node represents a single vector in the HNSW graph.
 type node struct {
 	id      int64     // external memory ID
-	vector  []float32 // embedding vector
+	vector  []float32 // embedding vector (nil when quantized)
+	codes   []int8    // int8 codes (nil unless quantized)
 	friends [][]int   // friends[layer] = sorted list of neighbor node indices
 	level   int       // max level for this node
 }
@@ -116,6 +120,10 @@ func (idx *Index) Insert(id int64, vector []float32) {
 		friends: make([][]int, level+1),
 		level:   level,
 	}
+	if idx.quant == QuantizationInt8 {
+		n.codes, _ = QuantizeInt8(vector)
+		n.vector = nil
+	}
 
 	idx.nodes = append(idx.nodes, n)
 	idx.idToIdx[id] = nodeIdx
@@ -242,13 +250,13 @@ func (idx *Index) randomLevel() int {
 // greedyClosest finds the single closest node to query at the given layer,
 // starting from entry point ep. Used for descending through upper layers.
 func (idx *Index) greedyClosest(query []float32, ep int, layer int) int {
-	dist := cosineDistance(query, idx.nodes[ep].vector)
+	dist := idx.queryDist(query, ep)
 
 	for {
 		improved := false
 		if layer < len(idx.nodes[ep].friends) {
 			for _, friendIdx := range idx.nodes[ep].friends[layer] {
-				friendDist := cosineDistance(query, idx.nodes[friendIdx].vector)
+				friendDist := idx.queryDist(query, friendIdx)
 				if friendDist < dist {
 					ep = friendIdx
 					dist = friendDist
@@ -269,7 +277,7 @@ func (idx *Index) searchLayer(query []float32, ep int, ef int, layer int) []cand
 	visited := make(map[int]bool)
 	visited[ep] = true
 
-	epDist := cosineDistance(query, idx.nodes[ep].vector)
+	epDist := idx.queryDist(query, ep)
 	candidates := []candidate{{idx: ep, dist: epDist}} // min-heap behavior via sort
 	results := []candidate{{idx: ep, dist: epDist}}    // max-heap behavior (we keep closest ef)
 
@@ -294,7 +302,7 @@ func (idx *Index) searchLayer(query []float32, ep int, ef int, layer int) []cand
 				}
 				visited[neighborIdx] = true
 
-				neighborDist := cosineDistance(query, idx.nodes[neighborIdx].vector)
+				neighborDist := idx.queryDist(query, neighborIdx)
 
 				// Add if closer than farthest result or results not full
 				if neighborDist < results[len(results)-1].dist || len(results) < ef {
@@ -343,9 +351,8 @@ func (idx *Index) shrinkNeighbors(nodeIdx int, neighbors []int, maxConn int, lay
 	}
 
 	scored_neighbors := make([]scored, len(neighbors))
-	vec := idx.nodes[nodeIdx].vector
 	for i, nIdx := range neighbors {
-		scored_neighbors[i] = scored{idx: nIdx, dist: cosineDistance(vec, idx.nodes[nIdx].vector)}
+		scored_neighbors[i] = scored{idx: nIdx, dist: idx.nodeDist(nodeIdx, nIdx)}
 	}
 
 	sort.Slice(scored_neighbors, func(i, j int) bool {
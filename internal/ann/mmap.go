@@ -0,0 +1,233 @@
+package ann
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"unsafe"
+)
+
+// LoadMmap restores an index saved by Save() by memory-mapping the file
+// instead of decoding it value by value.
+//
+// Graph structure (ids, levels, friend lists) is decoded up front, but vector
+// data stays in the mapping and is aliased directly, so the OS pages vectors
+// in lazily as searches touch them. Mapped vectors are read-only; nodes added
+// later with Insert get their own heap copies as usual.
+//
+// Platforms without mmap, and big-endian hosts (where float32 data cannot be
+// aliased), fall back to reading the file into memory.
+//
+// Call Close to release the mapping once the index is no longer used. Save
+// replaces files atomically, so rebuilding the index at the same path while
+// a mapped copy is live is safe.
+func LoadMmap(path string) (_ *Index, err error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("mapping index file: %w", err)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("loading index %s: corrupt persisted data: %v", path, r)
+		}
+		if err != nil && unmap != nil {
+			unmap()
+		}
+	}()
+
+	idx, err := decodeMapped(data, nativeLittleEndian())
+	if err != nil {
+		return nil, fmt.Errorf("loading index %s: %w", path, err)
+	}
+	idx.unmap = unmap
+	return idx, nil
+}
+
+// Close releases the file mapping behind an index returned by LoadMmap.
+// The index must not be used afterwards. Close is a no-op for heap indexes.
+func (idx *Index) Close() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.unmap == nil {
+		return nil
+	}
+	err := idx.unmap()
+	idx.unmap = nil
+	idx.nodes = nil
+	idx.idToIdx = map[int64]int{}
+	idx.entryPoint = -1
+	idx.maxLevel = -1
+	return err
+}
+
+// Mapped reports whether the index's vectors are backed by a file mapping.
+func (idx *Index) Mapped() bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.unmap != nil
+}
+
+// mappedReader walks a persisted index held in memory.
+type mappedReader struct {
+	buf []byte
+	off int
+}
+
+func (r *mappedReader) take(n int) ([]byte, error) {
+	if n < 0 || r.off+n > len(r.buf) {
+		return nil, fmt.Errorf("unexpected end of data at offset %d (need %d bytes)", r.off, n)
+	}
+	b := r.buf[r.off : r.off+n]
+	r.off += n
+	return b, nil
+}
+
+func (r *mappedReader) int32() (int32, error) {
+	b, err := r.take(4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.LittleEndian.Uint32(b)), nil
+}
+
+func (r *mappedReader) int64() (int64, error) {
+	b, err := r.take(8)
+	if err != nil {
+		return 0, err
+	}
+	return int64(binary.LittleEndian.Uint64(b)), nil
+}
+
+// decodeMapped parses the Save() format from data. When alias is true,
+// node vectors point into data rather than being copied.
+func decodeMapped(data []byte, alias bool) (*Index, error) {
+	r := &mappedReader{buf: data}
+
+	magicBuf, err := r.take(len(magic))
+	if err != nil {
+		return nil, fmt.Errorf("reading magic: %w", err)
+	}
+	if string(magicBuf) != magic {
+		return nil, fmt.Errorf("invalid magic: %q (expected %q)", string(magicBuf), magic)
+	}
+
+	var header [9]int32
+	for i := range header {
+		if header[i], err = r.int32(); err != nil {
+			return nil, fmt.Errorf("reading header: %w", err)
+		}
+	}
+	version, dims, nodeCount, entryPoint, maxLevel := header[0], header[1], header[2], header[3], header[4]
+	m, mmax0, efConst, efSearch := header[5], header[6], header[7], header[8]
+	if version != persistVersionFloat && version != persistVersionQuant {
+		return nil, fmt.Errorf("unsupported version: %d", version)
+	}
+	if err := validateHeader(dims, nodeCount, entryPoint, maxLevel, m, mmax0, efConst, efSearch); err != nil {
+		return nil, err
+	}
+
+	quant := QuantizationNone
+	if version == persistVersionQuant {
+		q, err := r.int32()
+		if err != nil {
+			return nil, fmt.Errorf("reading quantization: %w", err)
+		}
+		switch q {
+		case persistQuantNone:
+		case persistQuantInt8:
+			quant = QuantizationInt8
+		default:
+			return nil, fmt.Errorf("invalid quantization: %d", q)
+		}
+	}
+
+	idx := &Index{
+		dims:           int(dims),
+		M:              int(m),
+		Mmax0:          int(mmax0),
+		EfConstruction: int(efConst),
+		EfSearch:       int(efSearch),
+		LevelMult:      1.0 / math.Log(float64(m)),
+		entryPoint:     int(entryPoint),
+		maxLevel:       int(maxLevel),
+		quant:          quant,
+		nodes:          make([]node, 0, nodeCount),
+		idToIdx:        make(map[int64]int, nodeCount),
+		rng:            rand.New(rand.NewSource(42)),
+	}
+
+	for i := int32(0); i < nodeCount; i++ {
+		id, err := r.int64()
+		if err != nil {
+			return nil, fmt.Errorf("reading node %d id: %w", i, err)
+		}
+		level, err := r.int32()
+		if err != nil {
+			return nil, fmt.Errorf("reading node %d level: %w", i, err)
+		}
+		if level < 0 || level > maxPersistedLevel {
+			return nil, fmt.Errorf("reading node %d level: invalid value %d", i, level)
+		}
+		if level > maxLevel && maxLevel >= 0 {
+			return nil, fmt.Errorf("reading node %d level: %d exceeds header maxLevel %d", i, level, maxLevel)
+		}
+
+		n := node{id: id, level: int(level)}
+		if quant == QuantizationInt8 {
+			raw, err := r.take(int(dims))
+			if err != nil {
+				return nil, fmt.Errorf("reading node %d codes: %w", i, err)
+			}
+			n.codes = unsafe.Slice((*int8)(unsafe.Pointer(&raw[0])), len(raw))
+		} else {
+			raw, err := r.take(int(dims) * 4)
+			if err != nil {
+				return nil, fmt.Errorf("reading node %d vector: %w", i, err)
+			}
+			if alias && uintptr(unsafe.Pointer(&raw[0]))%4 == 0 {
+				n.vector = unsafe.Slice((*float32)(unsafe.Pointer(&raw[0])), dims)
+			} else {
+				n.vector = make([]float32, dims)
+				for d := range n.vector {
+					n.vector[d] = math.Float32frombits(binary.LittleEndian.Uint32(raw[d*4:]))
+				}
+			}
+		}
+
+		n.friends = make([][]int, level+1)
+		for l := int32(0); l <= level; l++ {
+			friendCount, err := r.int32()
+			if err != nil {
+				return nil, fmt.Errorf("reading node %d layer %d friend count: %w", i, l, err)
+			}
+			if friendCount < 0 || friendCount > nodeCount || friendCount > maxPersistedFriendRefs {
+				return nil, fmt.Errorf("reading node %d layer %d friend count: invalid value %d", i, l, friendCount)
+			}
+			friends := make([]int, friendCount)
+			for j := range friends {
+				fIdx, err := r.int32()
+				if err != nil {
+					return nil, fmt.Errorf("reading node %d layer %d friend %d: %w", i, l, j, err)
+				}
+				if fIdx < 0 || fIdx >= nodeCount {
+					return nil, fmt.Errorf("reading node %d layer %d friend %d: invalid node ref %d", i, l, j, fIdx)
+				}
+				friends[j] = int(fIdx)
+			}
+			n.friends[l] = friends
+		}
+
+		idx.nodes = append(idx.nodes, n)
+		idx.idToIdx[id] = int(i)
+	}
+
+	return idx, nil
+}
+
+// nativeLittleEndian reports whether persisted float32 data can be aliased
+// in place on this host.
+func nativeLittleEndian() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}
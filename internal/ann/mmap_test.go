@@ -0,0 +1,127 @@
+package ann
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadMmapMatchesLoad(t *testing.T) {
+	for _, q := range []Quantization{QuantizationNone, QuantizationInt8} {
+		t.Run(string(q), func(t *testing.T) {
+			rng := rand.New(rand.NewSource(21))
+			idx := New(24)
+			idx.SetQuantization(q)
+			for i := 0; i < 200; i++ {
+				idx.Insert(int64(i+1), randomVector(24, rng))
+			}
+			path := filepath.Join(t.TempDir(), "idx.hnsw")
+			if err := idx.Save(path); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+
+			heap, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			mapped, err := LoadMmap(path)
+			if err != nil {
+				t.Fatalf("LoadMmap: %v", err)
+			}
+			defer mapped.Close()
+
+			if mapped.Len() != heap.Len() || mapped.Quantization() != q || mapped.Params() != heap.Params() {
+				t.Fatalf("mapped index differs: len=%d quant=%q params=%+v", mapped.Len(), mapped.Quantization(), mapped.Params())
+			}
+			for i := 0; i < 10; i++ {
+				query := randomVector(24, rng)
+				a, b := heap.Search(query, 5), mapped.Search(query, 5)
+				if len(a) != len(b) {
+					t.Fatalf("result count mismatch: %d vs %d", len(a), len(b))
+				}
+				for j := range a {
+					if a[j].ID != b[j].ID {
+						t.Fatalf("query %d result %d: %d vs %d", i, j, a[j].ID, b[j].ID)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMmapSurvivesRebuildAtSamePath(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	idx := New(8)
+	for i := 0; i < 50; i++ {
+		idx.Insert(int64(i+1), randomVector(8, rng))
+	}
+	path := filepath.Join(t.TempDir(), "idx.hnsw")
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	mapped, err := LoadMmap(path)
+	if err != nil {
+		t.Fatalf("LoadMmap: %v", err)
+	}
+	defer mapped.Close()
+
+	// Overwrite the file while the mapping is live, then keep using it.
+	rebuilt := New(8)
+	rebuilt.Insert(999, randomVector(8, rng))
+	if err := rebuilt.Save(path); err != nil {
+		t.Fatalf("Save over mapped file: %v", err)
+	}
+	if got := mapped.Search(randomVector(8, rng), 3); len(got) != 3 {
+		t.Fatalf("mapped index unusable after rebuild: %v", got)
+	}
+
+	// Inserts after a mapped load get heap vectors.
+	mapped.Insert(1000, randomVector(8, rng))
+	if !mapped.Has(1000) || mapped.Len() != 51 {
+		t.Fatalf("insert after LoadMmap failed: len=%d", mapped.Len())
+	}
+
+	matches, _ := filepath.Glob(path + ".tmp-*")
+	if len(matches) != 0 {
+		t.Fatalf("Save left temp files behind: %v", matches)
+	}
+}
+
+func TestLoadMmapRejectsCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	truncated := filepath.Join(dir, "short.hnsw")
+	if err := os.WriteFile(truncated, []byte(magic+"\x01\x00"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMmap(truncated); err == nil {
+		t.Fatal("expected error for truncated header")
+	}
+
+	empty := filepath.Join(dir, "empty.hnsw")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMmap(empty); err == nil {
+		t.Fatal("expected error for empty file")
+	}
+
+	if _, err := LoadMmap(filepath.Join(dir, "missing.hnsw")); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
+
+func TestLoadMmapEmptyIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty-index.hnsw")
+	if err := New(4).Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	idx, err := LoadMmap(path)
+	if err != nil {
+		t.Fatalf("LoadMmap: %v", err)
+	}
+	defer idx.Close()
+	if idx.Len() != 0 {
+		t.Fatalf("Len = %d, want 0", idx.Len())
+	}
+}
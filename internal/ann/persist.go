@@ -6,14 +6,27 @@ import (
 	"io"
 	"math"
 	"os"
+	"path/filepath"
 )
 
 // File format: cortex-hnsw v1
 // Header: magic(8) + version(4) + dims(4) + nodeCount(4) + entryPoint(4) + maxLevel(4) + M(4) + Mmax0(4) + efConst(4) + efSearch(4)
 // Per node: id(8) + level(4) + vector(dims*4) + for each layer: friendCount(4) + friends(friendCount*4)
+//
+// Version 2 (int8-quantized indexes) appends quantization(4) to the header and
+// stores each vector as dims int8 codes instead of dims float32s. Unquantized
+// indexes are still written as v1 so older binaries can read them.
 
 const magic = "CXHNSW01"
 
+const (
+	persistVersionFloat = 1
+	persistVersionQuant = 2
+
+	persistQuantNone = 0
+	persistQuantInt8 = 1
+)
+
 const (
 	maxPersistedDims       = 65536
 	maxPersistedNodeCount  = 10_000_000
@@ -27,11 +40,20 @@ func (idx *Index) Save(path string) error {
 	idx.mu.RLock()
 	defer idx.mu.RUnlock()
 
-	f, err := os.Create(path)
+	// Write to a sibling temp file and rename into place, so readers (including
+	// live LoadMmap mappings of the old file) never observe a partial index.
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
 	if err != nil {
 		return fmt.Errorf("creating index file: %w", err)
 	}
-	defer f.Close()
+	tmpPath := f.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			f.Close()
+			os.Remove(tmpPath)
+		}
+	}()
 
 	w := &countWriter{w: f}
 
@@ -39,7 +61,12 @@ func (idx *Index) Save(path string) error {
 	if _, err := w.Write([]byte(magic)); err != nil {
 		return err
 	}
-	if err := writeInt32(w, 1); err != nil { // version
+	quantized := idx.quant == QuantizationInt8
+	version := int32(persistVersionFloat)
+	if quantized {
+		version = persistVersionQuant
+	}
+	if err := writeInt32(w, version); err != nil {
 		return err
 	}
 	if err := writeInt32(w, int32(idx.dims)); err != nil {
@@ -66,6 +93,11 @@ func (idx *Index) Save(path string) error {
 	if err := writeInt32(w, int32(idx.EfSearch)); err != nil {
 		return err
 	}
+	if quantized {
+		if err := writeInt32(w, persistQuantInt8); err != nil {
+			return err
+		}
+	}
 
 	// Nodes
 	for _, n := range idx.nodes {
@@ -78,10 +110,20 @@ func (idx *Index) Save(path string) error {
 			return err
 		}
 		// Vector
-		for _, v := range n.vector {
-			if err := writeFloat32(w, v); err != nil {
+		if quantized {
+			codes := make([]byte, len(n.codes))
+			for i, c := range n.codes {
+				codes[i] = byte(c)
+			}
+			if _, err := w.Write(codes); err != nil {
 				return err
 			}
+		} else {
+			for _, v := range n.vector {
+				if err := writeFloat32(w, v); err != nil {
+					return err
+				}
+			}
 		}
 		// Friends per layer
 		for l := 0; l <= n.level; l++ {
@@ -97,7 +139,17 @@ func (idx *Index) Save(path string) error {
 		}
 	}
 
-	return f.Sync()
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replacing index file: %w", err)
+	}
+	committed = true
+	return nil
 }
 
 // Load restores an HNSW index from a binary file created by Save().
@@ -128,7 +180,7 @@ func Load(path string) (_ *Index, err error) {
 	if err != nil {
 		return nil, fmt.Errorf("reading version: %w", err)
 	}
-	if version != 1 {
+	if version != persistVersionFloat && version != persistVersionQuant {
 		return nil, fmt.Errorf("unsupported version: %d", version)
 	}
 
@@ -169,6 +221,21 @@ func Load(path string) (_ *Index, err error) {
 		return nil, err
 	}
 
+	quant := QuantizationNone
+	if version == persistVersionQuant {
+		q, err := readInt32(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading quantization: %w", err)
+		}
+		switch q {
+		case persistQuantNone:
+		case persistQuantInt8:
+			quant = QuantizationInt8
+		default:
+			return nil, fmt.Errorf("invalid quantization: %d", q)
+		}
+	}
+
 	idx := &Index{
 		dims:           int(dims),
 		M:              int(m),
@@ -178,6 +245,7 @@ func Load(path string) (_ *Index, err error) {
 		LevelMult:      1.0 / math.Log(float64(m)),
 		entryPoint:     int(entryPoint),
 		maxLevel:       int(maxLevel),
+		quant:          quant,
 		nodes:          make([]node, 0, nodeCount),
 		idToIdx:        make(map[int64]int, nodeCount),
 	}
@@ -201,13 +269,26 @@ func Load(path string) (_ *Index, err error) {
 		}
 
 		// Vector
-		vector := make([]float32, dims)
-		for d := int32(0); d < dims; d++ {
-			v, err := readFloat32(f)
-			if err != nil {
-				return nil, fmt.Errorf("reading node %d vector[%d]: %w", i, d, err)
+		var vector []float32
+		var codes []int8
+		if quant == QuantizationInt8 {
+			raw := make([]byte, dims)
+			if _, err := io.ReadFull(f, raw); err != nil {
+				return nil, fmt.Errorf("reading node %d codes: %w", i, err)
+			}
+			codes = make([]int8, dims)
+			for d, b := range raw {
+				codes[d] = int8(b)
+			}
+		} else {
+			vector = make([]float32, dims)
+			for d := int32(0); d < dims; d++ {
+				v, err := readFloat32(f)
+				if err != nil {
+					return nil, fmt.Errorf("reading node %d vector[%d]: %w", i, d, err)
+				}
+				vector[d] = v
 			}
-			vector[d] = v
 		}
 
 		// Friends
@@ -236,6 +317,7 @@ func Load(path string) (_ *Index, err error) {
 		n := node{
 			id:      id,
 			vector:  vector,
+			codes:   codes,
 			friends: friends,
 			level:   int(level),
 		}
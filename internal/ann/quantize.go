@@ -0,0 +1,210 @@
+package ann
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Quantization selects how vectors are held in memory and on disk.
+type Quantization string
+
+const (
+	// QuantizationNone keeps full float32 vectors (4 bytes/dim).
+	QuantizationNone Quantization = "none"
+	// QuantizationInt8 keeps symmetric per-vector int8 codes (1 byte/dim).
+	// Cosine distance is scale-invariant, so the per-vector scale is not
+	// needed for ranking and the index stores codes only.
+	QuantizationInt8 Quantization = "int8"
+)
+
+// ParseQuantization normalizes a user-supplied quantization name.
+func ParseQuantization(raw string) (Quantization, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "none", "off", "float32", "f32":
+		return QuantizationNone, nil
+	case "int8", "i8", "sq8":
+		return QuantizationInt8, nil
+	default:
+		return "", fmt.Errorf("unknown quantization %q (valid: none, int8)", raw)
+	}
+}
+
+// BytesPerDim returns the per-dimension storage cost of q.
+func (q Quantization) BytesPerDim() int {
+	if q == QuantizationInt8 {
+		return 1
+	}
+	return 4
+}
+
+// QuantizeInt8 maps v onto int8 codes with a symmetric per-vector scale:
+// v[i] ≈ float32(q[i]) * scale.
+func QuantizeInt8(v []float32) ([]int8, float32) {
+	var maxAbs float32
+	for _, x := range v {
+		if a := float32(math.Abs(float64(x))); a > maxAbs {
+			maxAbs = a
+		}
+	}
+	q := make([]int8, len(v))
+	if maxAbs == 0 {
+		return q, 0
+	}
+	scale := maxAbs / 127
+	for i, x := range v {
+		r := math.Round(float64(x / scale))
+		if r > 127 {
+			r = 127
+		} else if r < -127 {
+			r = -127
+		}
+		q[i] = int8(r)
+	}
+	return q, scale
+}
+
+// DequantizeInt8 reverses QuantizeInt8.
+func DequantizeInt8(q []int8, scale float32) []float32 {
+	v := make([]float32, len(q))
+	for i, x := range q {
+		v[i] = float32(x) * scale
+	}
+	return v
+}
+
+// SetQuantization selects the in-memory vector representation. It must be
+// called before the first Insert; later calls on a non-empty index are ignored.
+func (idx *Index) SetQuantization(q Quantization) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if len(idx.nodes) > 0 {
+		return
+	}
+	idx.quant = q
+}
+
+// Quantization reports the index's vector representation.
+func (idx *Index) Quantization() Quantization {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if idx.quant == "" {
+		return QuantizationNone
+	}
+	return idx.quant
+}
+
+// queryDist is the cosine distance from a float query to node i.
+func (idx *Index) queryDist(query []float32, i int) float32 {
+	n := &idx.nodes[i]
+	if n.codes != nil {
+		return cosineDistanceInt8(query, n.codes)
+	}
+	return cosineDistance(query, n.vector)
+}
+
+// nodeDist is the cosine distance between nodes i and j.
+func (idx *Index) nodeDist(i, j int) float32 {
+	a, b := &idx.nodes[i], &idx.nodes[j]
+	if a.codes != nil && b.codes != nil {
+		return cosineDistanceInt8Pair(a.codes, b.codes)
+	}
+	return cosineDistance(idx.nodeVector(i), idx.nodeVector(j))
+}
+
+// nodeVector returns node i as float32. For int8 nodes the codes are returned
+// unscaled, which is equivalent for cosine distance.
+func (idx *Index) nodeVector(i int) []float32 {
+	n := &idx.nodes[i]
+	if n.codes != nil {
+		return DequantizeInt8(n.codes, 1)
+	}
+	return n.vector
+}
+
+func cosineDistanceInt8(a []float32, b []int8) float32 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 2.0
+	}
+	var dot, normA, normB float32
+	for i := range a {
+		bv := float32(b[i])
+		dot += a[i] * bv
+		normA += a[i] * a[i]
+		normB += bv * bv
+	}
+	if normA == 0 || normB == 0 {
+		return 2.0
+	}
+	sim := dot / (float32(math.Sqrt(float64(normA))) * float32(math.Sqrt(float64(normB))))
+	return 1.0 - sim
+}
+
+func cosineDistanceInt8Pair(a, b []int8) float32 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 2.0
+	}
+	var dot, normA, normB int64
+	for i := range a {
+		av, bv := int64(a[i]), int64(b[i])
+		dot += av * bv
+		normA += av * av
+		normB += bv * bv
+	}
+	if normA == 0 || normB == 0 {
+		return 2.0
+	}
+	sim := float64(dot) / (math.Sqrt(float64(normA)) * math.Sqrt(float64(normB)))
+	return float32(1.0 - sim)
+}
+
+// QuantizationImpact summarizes the recall cost of quantizing a vector set.
+type QuantizationImpact struct {
+	Mode          Quantization `json:"mode"`
+	Vectors       int          `json:"vectors"`
+	Queries       int          `json:"queries"`
+	K             int          `json:"k"`
+	ExactRecall   float64      `json:"exact_recall"`   // quantized brute force vs float brute force
+	IndexRecall   float64      `json:"index_recall"`   // quantized HNSW vs float brute force
+	BaselineIndex float64      `json:"baseline_index"` // float HNSW vs float brute force
+	BytesBefore   int64        `json:"bytes_before"`
+	BytesAfter    int64        `json:"bytes_after"`
+}
+
+// MeasureQuantizationImpact builds float and quantized indexes over vectors and
+// reports recall@k of each against exact float32 brute force, plus the raw
+// vector byte footprint before and after.
+func MeasureQuantizationImpact(ids []int64, vectors [][]float32, mode Quantization, params Params, k, querySample int) QuantizationImpact {
+	out := QuantizationImpact{Mode: mode, Vectors: len(vectors), K: k}
+	if len(vectors) == 0 || len(ids) != len(vectors) || k <= 0 {
+		return out
+	}
+	dims := len(vectors[0])
+	out.BytesBefore = int64(len(vectors) * dims * QuantizationNone.BytesPerDim())
+	out.BytesAfter = int64(len(vectors) * dims * mode.BytesPerDim())
+
+	base := NewFromParams(dims, params)
+	quant := NewFromParams(dims, params)
+	quant.SetQuantization(mode)
+	for i, v := range vectors {
+		base.Insert(ids[i], v)
+		quant.Insert(ids[i], v)
+	}
+
+	queries := base.sampleVectors(querySample)
+	out.Queries = len(queries)
+	var exactHits, indexHits, baseHits, total int
+	for _, q := range queries {
+		truth := base.bruteForce(q, k)
+		exactHits += overlap(truth, quant.bruteForce(q, k))
+		indexHits += overlap(truth, quant.Search(q, k))
+		baseHits += overlap(truth, base.Search(q, k))
+		total += len(truth)
+	}
+	if total > 0 {
+		out.ExactRecall = float64(exactHits) / float64(total)
+		out.IndexRecall = float64(indexHits) / float64(total)
+		out.BaselineIndex = float64(baseHits) / float64(total)
+	}
+	return out
+}
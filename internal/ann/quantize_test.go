@@ -0,0 +1,127 @@
+package ann
+
+import (
+	"math"
+	"math/rand"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseQuantization(t *testing.T) {
+	cases := map[string]Quantization{"": QuantizationNone, "none": QuantizationNone, "INT8": QuantizationInt8, "sq8": QuantizationInt8}
+	for in, want := range cases {
+		got, err := ParseQuantization(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseQuantization(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseQuantization("pq16"); err == nil {
+		t.Fatal("expected error for unknown quantization")
+	}
+}
+
+func TestQuantizeInt8RoundTrip(t *testing.T) {
+	v := []float32{0.5, -1.0, 0.25, 0}
+	codes, scale := QuantizeInt8(v)
+	if codes[1] != -127 {
+		t.Fatalf("max-magnitude component should map to -127, got %d", codes[1])
+	}
+	back := DequantizeInt8(codes, scale)
+	for i := range v {
+		if math.Abs(float64(back[i]-v[i])) > float64(scale) {
+			t.Fatalf("component %d: %f -> %f exceeds one quantization step (%f)", i, v[i], back[i], scale)
+		}
+	}
+
+	zeros, zscale := QuantizeInt8([]float32{0, 0})
+	if zscale != 0 || zeros[0] != 0 {
+		t.Fatalf("zero vector should quantize to zeros with scale 0, got %v %f", zeros, zscale)
+	}
+}
+
+func TestInt8IndexSearchMatchesFloat(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	dims := 32
+	base := New(dims)
+	quant := New(dims)
+	quant.SetQuantization(QuantizationInt8)
+	for i := 0; i < 300; i++ {
+		v := randomVector(dims, rng)
+		base.Insert(int64(i+1), v)
+		quant.Insert(int64(i+1), v)
+	}
+	if quant.Quantization() != QuantizationInt8 {
+		t.Fatalf("Quantization = %q", quant.Quantization())
+	}
+	if quant.nodes[0].vector != nil || len(quant.nodes[0].codes) != dims {
+		t.Fatal("int8 index should hold codes, not float vectors")
+	}
+
+	// SetQuantization after inserts is ignored.
+	quant.SetQuantization(QuantizationNone)
+	if quant.Quantization() != QuantizationInt8 {
+		t.Fatal("SetQuantization on non-empty index should be a no-op")
+	}
+
+	var hits, total int
+	for i := 0; i < 30; i++ {
+		q := randomVector(dims, rng)
+		truth := base.bruteForce(q, 10)
+		hits += overlap(truth, quant.SearchEf(q, 10, 200))
+		total += len(truth)
+	}
+	if recall := float64(hits) / float64(total); recall < 0.85 {
+		t.Fatalf("int8 recall@10 = %.3f, want >= 0.85", recall)
+	}
+}
+
+func TestInt8IndexSaveLoad(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	idx := New(16)
+	idx.SetQuantization(QuantizationInt8)
+	for i := 0; i < 50; i++ {
+		idx.Insert(int64(i+1), randomVector(16, rng))
+	}
+
+	path := filepath.Join(t.TempDir(), "q.idx")
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Quantization() != QuantizationInt8 || loaded.Len() != 50 {
+		t.Fatalf("loaded quant=%q len=%d", loaded.Quantization(), loaded.Len())
+	}
+
+	q := randomVector(16, rng)
+	a, b := idx.Search(q, 5), loaded.Search(q, 5)
+	if len(a) != len(b) {
+		t.Fatalf("result count mismatch: %d vs %d", len(a), len(b))
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID {
+			t.Fatalf("result %d differs after reload: %d vs %d", i, a[i].ID, b[i].ID)
+		}
+	}
+}
+
+func TestMeasureQuantizationImpact(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	n := 150
+	ids := make([]int64, n)
+	vectors := make([][]float32, n)
+	for i := range vectors {
+		ids[i] = int64(i + 1)
+		vectors[i] = randomVector(24, rng)
+	}
+
+	impact := MeasureQuantizationImpact(ids, vectors, QuantizationInt8, DefaultParams(), 10, 20)
+	if impact.BytesAfter*4 != impact.BytesBefore {
+		t.Fatalf("int8 should cut raw vector bytes 4x: %d -> %d", impact.BytesBefore, impact.BytesAfter)
+	}
+	if impact.Queries != 20 || impact.ExactRecall < 0.8 || impact.ExactRecall > 1 {
+		t.Fatalf("unexpected impact: %+v", impact)
+	}
+}
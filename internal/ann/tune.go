@@ -0,0 +1,288 @@
+package ann
+
+import (
+	"sort"
+	"time"
+)
+
+// Params groups the HNSW tuning knobs that can be set per store via config.
+// A zero field means "use the built-in default".
+type Params struct {
+	M              int `json:"m"`
+	EfConstruction int `json:"ef_construction"`
+	EfSearch       int `json:"ef_search"`
+}
+
+// DefaultParams returns the built-in HNSW parameters.
+func DefaultParams() Params {
+	return Params{M: DefaultM, EfConstruction: DefaultEfConstruction, EfSearch: DefaultEfSearch}
+}
+
+// WithDefaults fills zero/invalid fields from DefaultParams.
+func (p Params) WithDefaults() Params {
+	d := DefaultParams()
+	if p.M < 2 {
+		p.M = d.M
+	}
+	if p.EfConstruction <= 0 {
+		p.EfConstruction = d.EfConstruction
+	}
+	if p.EfSearch <= 0 {
+		p.EfSearch = d.EfSearch
+	}
+	return p
+}
+
+// NewFromParams creates a new HNSW index using p (zero fields fall back to defaults).
+func NewFromParams(dims int, p Params) *Index {
+	p = p.WithDefaults()
+	return NewWithParams(dims, p.M, p.EfConstruction, p.EfSearch)
+}
+
+// Params returns the parameters the index was built (or loaded) with.
+func (idx *Index) Params() Params {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return Params{M: idx.M, EfConstruction: idx.EfConstruction, EfSearch: idx.EfSearch}
+}
+
+// SetEfSearch overrides the search-time beam width. efSearch is a query-time
+// knob, so it can be changed on a loaded index without a rebuild.
+func (idx *Index) SetEfSearch(ef int) {
+	if ef <= 0 {
+		return
+	}
+	idx.mu.Lock()
+	idx.EfSearch = ef
+	idx.mu.Unlock()
+}
+
+// RecommendParams returns size-appropriate HNSW parameters for a store with n
+// vectors. Small stores keep the defaults; larger graphs need more links and a
+// wider beam to hold recall as the graph deepens.
+func RecommendParams(n int) Params {
+	switch {
+	case n < 10_000:
+		return DefaultParams()
+	case n < 100_000:
+		return Params{M: 24, EfConstruction: 256, EfSearch: 96}
+	default:
+		return Params{M: 32, EfConstruction: 400, EfSearch: 160}
+	}
+}
+
+// EstimateRecall measures recall@k of the index against exact brute-force
+// search, using up to sampleSize of the indexed vectors as queries. ef is the
+// search beam width to evaluate (0 = the index's EfSearch). Returns a value in
+// [0, 1]; an empty index reports 0.
+func (idx *Index) EstimateRecall(k, sampleSize, ef int) float64 {
+	queries := idx.sampleVectors(sampleSize)
+	if len(queries) == 0 || k <= 0 {
+		return 0
+	}
+	if ef <= 0 {
+		ef = idx.Params().EfSearch
+	}
+
+	var hits, total int
+	for _, q := range queries {
+		exact := idx.bruteForce(q, k)
+		hits += overlap(exact, idx.SearchEf(q, k, ef))
+		total += len(exact)
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(hits) / float64(total)
+}
+
+// overlap counts how many of got appear in want.
+func overlap(want, got []Result) int {
+	ids := make(map[int64]bool, len(want))
+	for _, r := range want {
+		ids[r.ID] = true
+	}
+	n := 0
+	for _, r := range got {
+		if ids[r.ID] {
+			n++
+		}
+	}
+	return n
+}
+
+// sampleVectors returns up to n indexed vectors chosen at a fixed stride so
+// repeated measurements on the same index are deterministic.
+func (idx *Index) sampleVectors(n int) [][]float32 {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	total := len(idx.nodes)
+	if total == 0 || n <= 0 {
+		return nil
+	}
+	if n > total {
+		n = total
+	}
+	stride := total / n
+	out := make([][]float32, 0, n)
+	for i := 0; i < total && len(out) < n; i += stride {
+		out = append(out, idx.nodeVector(i))
+	}
+	return out
+}
+
+// bruteForce returns the exact k nearest neighbors by scanning every node.
+func (idx *Index) bruteForce(query []float32, k int) []Result {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	all := make([]Result, len(idx.nodes))
+	for i, n := range idx.nodes {
+		all[i] = Result{ID: n.id, Distance: idx.queryDist(query, i)}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Distance < all[j].Distance })
+	if len(all) > k {
+		all = all[:k]
+	}
+	return all
+}
+
+// TuneResult is one measured parameter combination from Tune.
+type TuneResult struct {
+	Params         Params        `json:"params"`
+	Recall         float64       `json:"recall"`
+	BuildTime      time.Duration `json:"build_time_ns"`
+	AvgQueryTime   time.Duration `json:"avg_query_time_ns"`
+	Recommended    bool          `json:"recommended,omitempty"`
+	MeetsTarget    bool          `json:"meets_target"`
+	queryCostProxy int
+}
+
+// TuneOptions controls Tune.
+type TuneOptions struct {
+	K            int     // recall@k (default 10)
+	QuerySample  int     // number of query vectors (default 100)
+	TargetRecall float64 // minimum acceptable recall (default 0.95)
+	Candidates   []Params
+}
+
+// DefaultTuneCandidates is the grid Tune sweeps when no candidates are given.
+// Builds are shared across candidates with the same M/efConstruction; efSearch
+// is swept on the built graph.
+func DefaultTuneCandidates() []Params {
+	var out []Params
+	for _, m := range []int{12, 16, 24, 32} {
+		for _, efc := range []int{100, 200, 400} {
+			for _, efs := range []int{32, 50, 96, 160} {
+				out = append(out, Params{M: m, EfConstruction: efc, EfSearch: efs})
+			}
+		}
+	}
+	return out
+}
+
+// Tune builds indexes over vectors with each candidate parameter set,
+// measures recall@k against brute force, and marks the cheapest candidate
+// that meets the target recall as recommended (or the best-recall candidate
+// if none does). Results are sorted by recall descending.
+func Tune(ids []int64, vectors [][]float32, opts TuneOptions) []TuneResult {
+	if len(vectors) == 0 || len(ids) != len(vectors) {
+		return nil
+	}
+	if opts.K <= 0 {
+		opts.K = 10
+	}
+	if opts.QuerySample <= 0 {
+		opts.QuerySample = 100
+	}
+	if opts.TargetRecall <= 0 {
+		opts.TargetRecall = 0.95
+	}
+	candidates := opts.Candidates
+	if len(candidates) == 0 {
+		candidates = DefaultTuneCandidates()
+	}
+
+	type buildKey struct{ m, efc int }
+	built := map[buildKey]*Index{}
+	buildTimes := map[buildKey]time.Duration{}
+
+	// Every candidate indexes the same vectors, so the query sample and its
+	// exact neighbors are computed once and shared.
+	var queries [][]float32
+	var exact [][]Result
+
+	var results []TuneResult
+	for _, c := range candidates {
+		c = c.WithDefaults()
+		key := buildKey{c.M, c.EfConstruction}
+		idx, ok := built[key]
+		if !ok {
+			start := time.Now()
+			idx = NewFromParams(len(vectors[0]), c)
+			for i, v := range vectors {
+				idx.Insert(ids[i], v)
+			}
+			built[key] = idx
+			buildTimes[key] = time.Since(start)
+		}
+
+		if queries == nil {
+			queries = idx.sampleVectors(opts.QuerySample)
+			exact = make([][]Result, len(queries))
+			for i, q := range queries {
+				exact[i] = idx.bruteForce(q, opts.K)
+			}
+		}
+
+		start := time.Now()
+		var hits, total int
+		for i, q := range queries {
+			approx := idx.SearchEf(q, opts.K, c.EfSearch)
+			hits += overlap(exact[i], approx)
+			total += len(exact[i])
+		}
+		var avg time.Duration
+		if len(queries) > 0 {
+			avg = time.Since(start) / time.Duration(len(queries))
+		}
+		recall := 0.0
+		if total > 0 {
+			recall = float64(hits) / float64(total)
+		}
+
+		results = append(results, TuneResult{
+			Params:         c,
+			Recall:         recall,
+			BuildTime:      buildTimes[key],
+			AvgQueryTime:   avg,
+			MeetsTarget:    recall >= opts.TargetRecall,
+			queryCostProxy: c.M * c.EfSearch,
+		})
+	}
+
+	best := -1
+	for i, r := range results {
+		if !r.MeetsTarget {
+			continue
+		}
+		if best == -1 || r.queryCostProxy < results[best].queryCostProxy ||
+			(r.queryCostProxy == results[best].queryCostProxy && r.Params.EfConstruction < results[best].Params.EfConstruction) {
+			best = i
+		}
+	}
+	if best == -1 {
+		for i, r := range results {
+			if best == -1 || r.Recall > results[best].Recall {
+				best = i
+			}
+		}
+	}
+	if best >= 0 {
+		results[best].Recommended = true
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Recall > results[j].Recall })
+	return results
+}
@@ -0,0 +1,115 @@
+package ann
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestParamsWithDefaults(t *testing.T) {
+	p := Params{EfSearch: 80}.WithDefaults()
+	if p.M != DefaultM || p.EfConstruction != DefaultEfConstruction || p.EfSearch != 80 {
+		t.Fatalf("WithDefaults = %+v", p)
+	}
+
+	idx := NewFromParams(8, Params{M: 24})
+	got := idx.Params()
+	if got.M != 24 || got.EfConstruction != DefaultEfConstruction || got.EfSearch != DefaultEfSearch {
+		t.Fatalf("NewFromParams params = %+v", got)
+	}
+}
+
+func TestRecommendParamsScalesWithSize(t *testing.T) {
+	small := RecommendParams(1_000)
+	mid := RecommendParams(50_000)
+	large := RecommendParams(500_000)
+	if small != DefaultParams() {
+		t.Fatalf("small store should keep defaults, got %+v", small)
+	}
+	if !(mid.M > small.M && large.M > mid.M) {
+		t.Fatalf("M should grow with store size: %d, %d, %d", small.M, mid.M, large.M)
+	}
+	if !(mid.EfSearch > small.EfSearch && large.EfSearch > mid.EfSearch) {
+		t.Fatalf("efSearch should grow with store size: %d, %d, %d", small.EfSearch, mid.EfSearch, large.EfSearch)
+	}
+}
+
+func TestEstimateRecall(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	idx := New(16)
+	if got := idx.EstimateRecall(10, 20, 0); got != 0 {
+		t.Fatalf("empty index recall = %f, want 0", got)
+	}
+	for i := 0; i < 300; i++ {
+		idx.Insert(int64(i+1), randomVector(16, rng))
+	}
+
+	// A beam as wide as the index is exhaustive on this size.
+	if got := idx.EstimateRecall(10, 30, 300); got < 0.99 {
+		t.Fatalf("exhaustive ef recall = %f, want ~1.0", got)
+	}
+	narrow := idx.EstimateRecall(10, 30, 10)
+	if narrow <= 0 || narrow > 1 {
+		t.Fatalf("narrow ef recall out of range: %f", narrow)
+	}
+}
+
+func TestSetEfSearch(t *testing.T) {
+	idx := New(4)
+	idx.SetEfSearch(128)
+	if idx.EfSearch != 128 {
+		t.Fatalf("EfSearch = %d, want 128", idx.EfSearch)
+	}
+	idx.SetEfSearch(0)
+	if idx.EfSearch != 128 {
+		t.Fatalf("SetEfSearch(0) should be a no-op, got %d", idx.EfSearch)
+	}
+}
+
+func TestTuneRecommendsCheapestCandidateMeetingTarget(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	n := 200
+	ids := make([]int64, n)
+	vectors := make([][]float32, n)
+	for i := range vectors {
+		ids[i] = int64(i + 1)
+		vectors[i] = randomVector(12, rng)
+	}
+
+	results := Tune(ids, vectors, TuneOptions{
+		K:            5,
+		QuerySample:  25,
+		TargetRecall: 0.5,
+		Candidates: []Params{
+			{M: 8, EfConstruction: 100, EfSearch: 200},
+			{M: 8, EfConstruction: 100, EfSearch: 20},
+		},
+	})
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	var recommended *TuneResult
+	for i := range results {
+		if results[i].Recommended {
+			if recommended != nil {
+				t.Fatal("more than one candidate marked recommended")
+			}
+			recommended = &results[i]
+		}
+	}
+	if recommended == nil {
+		t.Fatal("no candidate marked recommended")
+	}
+	if !recommended.MeetsTarget || recommended.Params.EfSearch != 20 {
+		t.Fatalf("expected the cheaper candidate meeting target, got %+v", recommended)
+	}
+	if results[0].Recall < results[1].Recall {
+		t.Fatalf("results should be sorted by recall desc: %+v", results)
+	}
+}
+
+func TestTuneEmptyInput(t *testing.T) {
+	if got := Tune(nil, nil, TuneOptions{}); got != nil {
+		t.Fatalf("Tune(nil) = %+v, want nil", got)
+	}
+}
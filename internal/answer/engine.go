@@ -11,6 +11,7 @@ import (
 	cfgresolver "github.com/hurttlocker/cortex/internal/config"
 	"github.com/hurttlocker/cortex/internal/llm"
 	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
 	"github.com/hurttlocker/cortex/internal/temporal"
 )
 
@@ -60,6 +61,7 @@ func NewEngine(searcher Searcher, provider llm.Provider, model string) *Engine {
 
 // ResolveProvider resolves a provider/model from CLI/config and attempts provider init.
 // If no usable provider is available, it returns (nil, model, reason, nil) for graceful degradation.
+// The provider masks secrets in prompts (see llm.Scrub).
 func ResolveProvider(modelFlag string) (llm.Provider, string, string, error) {
 	resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{CLILLM: modelFlag})
 	if err != nil {
@@ -86,7 +88,7 @@ func ResolveProvider(modelFlag string) (llm.Provider, string, string, error) {
 	if err != nil {
 		return nil, model, "no_llm_configured", nil
 	}
-	return provider, model, "", nil
+	return llm.Scrub(provider), model, "", nil
 }
 
 func (e *Engine) Answer(ctx context.Context, opts Options) (*Result, error) {
@@ -112,6 +114,12 @@ func (e *Engine) Answer(ctx context.Context, opts Options) (*Result, error) {
 		opts.MaxContextChars = 5500
 	}
 
+	if e.llm != nil {
+		// Every provider behind internal/llm is remote, so local-llm-only
+		// projects never reach the prompt.
+		opts.Search.ExcludeProjects = append(append([]string(nil), opts.Search.ExcludeProjects...),
+			store.ProjectsRestricting(store.PrivacyLocalLLMOnly)...)
+	}
 	results, err := e.searcher.Search(ctx, opts.Query, opts.Search)
 	if err != nil {
 		return nil, err
@@ -162,6 +170,7 @@ Rules:
 		System:      systemPrompt,
 		Temperature: 0.1,
 		MaxTokens:   240,
+		Task:        llm.TaskAnswer,
 	})
 	if err != nil {
 		return fallbackResult(results, "llm_error"), nil
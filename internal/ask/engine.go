@@ -10,6 +10,7 @@ import (
 
 	"github.com/hurttlocker/cortex/internal/llm"
 	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
 )
 
 var citationRefRE = regexp.MustCompile(`\[(\d+)\]`)
@@ -39,6 +40,9 @@ type Result struct {
 	Provider     string          `json:"provider,omitempty"`
 	Budget       int             `json:"budget,omitempty"`
 	PackedTokens int             `json:"packed_tokens,omitempty"`
+	// Confidence grades the facts behind the cited memories; set by callers
+	// with store access.
+	Confidence *store.AnswerConfidence `json:"confidence,omitempty"`
 }
 
 type Options struct {
@@ -140,6 +144,7 @@ Rules:
 		Temperature: 0.1,
 		MaxTokens:   700,
 		Model:       e.model,
+		Task:        llm.TaskAnswer,
 	})
 	if err != nil {
 		return fallbackResult(question, results, opts, "llm_error", err.Error()), nil
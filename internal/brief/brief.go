@@ -0,0 +1,417 @@
+// Package brief maintains Cortex's hierarchical summaries: a short brief per
+// topic cluster within each project, a domain brief per project rolled up
+// from its cluster briefs, and a store-level "identity card" rolled up from
+// the domain briefs. Agents read them to bootstrap quickly (cortex brief,
+// cortex://brief) instead of searching from scratch.
+//
+// Every brief carries a fingerprint of its inputs. Refresh regenerates only
+// the briefs whose inputs changed, and their ancestors, so running it after
+// each import costs LLM calls in proportion to what changed.
+package brief
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const (
+	// DefaultMinFacts is the smallest cluster slice that gets a brief.
+	DefaultMinFacts = 3
+	// DefaultMaxClusters caps cluster briefs per project (largest first).
+	DefaultMaxClusters = 12
+
+	// sliceFactLimit caps facts sent to the LLM per cluster brief.
+	sliceFactLimit = 60
+	// briefTimeout bounds a single brief generation call.
+	briefTimeout = 60 * time.Second
+)
+
+// UntaggedProject is how briefs label facts from memories without a project.
+const UntaggedProject = "(untagged)"
+
+// Options configures Refresh.
+type Options struct {
+	Project     string // only refresh this project (the store brief is left alone)
+	MinFacts    int    // default DefaultMinFacts
+	MaxClusters int    // default DefaultMaxClusters
+	Force       bool   // regenerate even when fingerprints match
+}
+
+// RefreshResult reports what Refresh did.
+type RefreshResult struct {
+	Generated map[string]int `json:"generated"` // by level
+	Unchanged int            `json:"unchanged"`
+	Removed   int            `json:"removed"`
+	Errors    []string       `json:"errors,omitempty"`
+	Model     string         `json:"model,omitempty"`
+}
+
+// Stored is the subset of SQLiteStore briefs need.
+type Stored interface {
+	BriefSlices(ctx context.Context, limit int) ([]store.BriefSlice, error)
+	ListBriefs(ctx context.Context, level string) ([]store.Brief, error)
+	UpsertBrief(ctx context.Context, b *store.Brief) error
+	DeleteBrief(ctx context.Context, level, key string) error
+}
+
+// plan is the brief hierarchy the current facts call for, with the
+// fingerprint each brief should have.
+type plan struct {
+	slices   map[string]store.BriefSlice // by cluster brief key
+	projects map[string][]string         // project → cluster brief keys, largest first
+	sliceFP  map[string]string
+}
+
+func buildPlan(slices []store.BriefSlice, opts Options) plan {
+	p := plan{
+		slices:   map[string]store.BriefSlice{},
+		projects: map[string][]string{},
+		sliceFP:  map[string]string{},
+	}
+	byProject := map[string][]store.BriefSlice{}
+	for _, s := range slices {
+		if s.FactCount < opts.MinFacts {
+			continue
+		}
+		byProject[s.Project] = append(byProject[s.Project], s)
+	}
+	for project, group := range byProject {
+		sort.SliceStable(group, func(i, j int) bool { return group[i].FactCount > group[j].FactCount })
+		if len(group) > opts.MaxClusters {
+			group = group[:opts.MaxClusters]
+		}
+		for _, s := range group {
+			key := store.ClusterBriefKey(s.Project, s.ClusterID)
+			p.slices[key] = s
+			p.projects[project] = append(p.projects[project], key)
+			p.sliceFP[key] = sliceFingerprint(s)
+		}
+	}
+	return p
+}
+
+// Refresh regenerates stale briefs with provider and removes briefs whose
+// clusters or projects no longer exist. A failed generation keeps the old
+// brief (if any) and is reported in Errors; its ancestors are still rolled
+// up from what is stored.
+func Refresh(ctx context.Context, st Stored, provider llm.Provider, opts Options) (*RefreshResult, error) {
+	if opts.MinFacts <= 0 {
+		opts.MinFacts = DefaultMinFacts
+	}
+	if opts.MaxClusters <= 0 {
+		opts.MaxClusters = DefaultMaxClusters
+	}
+	slices, err := st.BriefSlices(ctx, sliceFactLimit)
+	if err != nil {
+		return nil, err
+	}
+	// Providers are remote, so local-llm-only projects get no briefs.
+	remote := slices[:0:0]
+	for _, s := range slices {
+		if store.ProjectAllows(s.Project, store.PrivacyLocalLLMOnly) {
+			remote = append(remote, s)
+		}
+	}
+	p := buildPlan(remote, opts)
+	res := &RefreshResult{Generated: map[string]int{}, Model: provider.Name()}
+
+	inScope := func(project string) bool { return opts.Project == "" || matchProject(project, opts.Project) }
+
+	// Level 1: cluster briefs.
+	stored, err := storedByKey(ctx, st, store.BriefLevelCluster)
+	if err != nil {
+		return nil, err
+	}
+	for key, s := range p.slices {
+		if !inScope(s.Project) {
+			continue
+		}
+		if old, ok := stored[key]; ok && old.Fingerprint == p.sliceFP[key] && !opts.Force {
+			res.Unchanged++
+			continue
+		}
+		summary, err := generate(ctx, provider, clusterPrompt(s))
+		if err != nil {
+			if ctx.Err() != nil {
+				return res, ctx.Err()
+			}
+			res.Errors = append(res.Errors, fmt.Sprintf("cluster %s: %v", key, err))
+			continue
+		}
+		b := &store.Brief{
+			Level: store.BriefLevelCluster, Key: key, Project: s.Project, ClusterID: s.ClusterID,
+			Title: clusterTitle(s), Summary: summary, FactCount: s.FactCount,
+			Fingerprint: p.sliceFP[key], Model: provider.Name(),
+		}
+		if err := st.UpsertBrief(ctx, b); err != nil {
+			return res, err
+		}
+		stored[key] = *b
+		res.Generated[store.BriefLevelCluster]++
+	}
+	for key, old := range stored {
+		if _, ok := p.slices[key]; !ok && inScope(old.Project) {
+			if err := st.DeleteBrief(ctx, store.BriefLevelCluster, key); err != nil {
+				return res, err
+			}
+			delete(stored, key)
+			res.Removed++
+		}
+	}
+
+	// Level 2: project (domain) briefs, rolled up from stored cluster briefs.
+	storedProjects, err := storedByKey(ctx, st, store.BriefLevelProject)
+	if err != nil {
+		return nil, err
+	}
+	for project, keys := range p.projects {
+		if !inScope(project) {
+			continue
+		}
+		var children []store.Brief
+		facts := 0
+		for _, key := range keys {
+			if b, ok := stored[key]; ok {
+				children = append(children, b)
+				facts += b.FactCount
+			}
+		}
+		if len(children) == 0 {
+			continue
+		}
+		fp := rollupFingerprint(children)
+		if old, ok := storedProjects[project]; ok && old.Fingerprint == fp && !opts.Force {
+			res.Unchanged++
+			continue
+		}
+		summary, err := generate(ctx, provider, projectPrompt(project, children))
+		if err != nil {
+			if ctx.Err() != nil {
+				return res, ctx.Err()
+			}
+			res.Errors = append(res.Errors, fmt.Sprintf("project %s: %v", projectLabel(project), err))
+			continue
+		}
+		b := &store.Brief{
+			Level: store.BriefLevelProject, Key: project, Project: project,
+			Title: projectLabel(project), Summary: summary, FactCount: facts,
+			Fingerprint: fp, Model: provider.Name(),
+		}
+		if err := st.UpsertBrief(ctx, b); err != nil {
+			return res, err
+		}
+		storedProjects[project] = *b
+		res.Generated[store.BriefLevelProject]++
+	}
+	for project := range storedProjects {
+		if _, ok := p.projects[project]; !ok && inScope(project) {
+			if err := st.DeleteBrief(ctx, store.BriefLevelProject, project); err != nil {
+				return res, err
+			}
+			delete(storedProjects, project)
+			res.Removed++
+		}
+	}
+
+	// Level 3: the store identity card. A project-scoped refresh leaves it
+	// for the next full refresh.
+	if opts.Project != "" || len(storedProjects) == 0 {
+		return res, nil
+	}
+	domains := make([]store.Brief, 0, len(storedProjects))
+	facts := 0
+	for _, b := range storedProjects {
+		domains = append(domains, b)
+		facts += b.FactCount
+	}
+	sort.Slice(domains, func(i, j int) bool { return domains[i].FactCount > domains[j].FactCount })
+	fp := rollupFingerprint(domains)
+	cards, err := st.ListBriefs(ctx, store.BriefLevelStore)
+	if err != nil {
+		return res, err
+	}
+	if len(cards) > 0 && cards[0].Fingerprint == fp && !opts.Force {
+		res.Unchanged++
+		return res, nil
+	}
+	summary, err := generate(ctx, provider, storePrompt(domains))
+	if err != nil {
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+		res.Errors = append(res.Errors, fmt.Sprintf("store: %v", err))
+		return res, nil
+	}
+	if err := st.UpsertBrief(ctx, &store.Brief{
+		Level: store.BriefLevelStore, Title: "Identity card", Summary: summary,
+		FactCount: facts, Fingerprint: fp, Model: provider.Name(),
+	}); err != nil {
+		return res, err
+	}
+	res.Generated[store.BriefLevelStore]++
+	return res, nil
+}
+
+// Stale counts briefs that Refresh would generate or remove at the cluster
+// level, without calling an LLM. Rollups are stale whenever a cluster is.
+func Stale(ctx context.Context, st Stored, opts Options) (int, error) {
+	if opts.MinFacts <= 0 {
+		opts.MinFacts = DefaultMinFacts
+	}
+	if opts.MaxClusters <= 0 {
+		opts.MaxClusters = DefaultMaxClusters
+	}
+	slices, err := st.BriefSlices(ctx, sliceFactLimit)
+	if err != nil {
+		return 0, err
+	}
+	p := buildPlan(slices, opts)
+	stored, err := storedByKey(ctx, st, store.BriefLevelCluster)
+	if err != nil {
+		return 0, err
+	}
+	stale := 0
+	for key, s := range p.slices {
+		if opts.Project != "" && !matchProject(s.Project, opts.Project) {
+			continue
+		}
+		if old, ok := stored[key]; !ok || old.Fingerprint != p.sliceFP[key] {
+			stale++
+		}
+	}
+	for key, old := range stored {
+		if _, ok := p.slices[key]; !ok && (opts.Project == "" || matchProject(old.Project, opts.Project)) {
+			stale++
+		}
+	}
+	return stale, nil
+}
+
+// Card is the stored brief hierarchy as shown to users and agents.
+type Card struct {
+	Identity *store.Brief `json:"identity,omitempty"`
+	Domains  []Domain     `json:"domains"`
+}
+
+// Domain is a project brief with its cluster briefs.
+type Domain struct {
+	store.Brief
+	Clusters []store.Brief `json:"clusters,omitempty"`
+}
+
+// Load reads the stored briefs. With project set, only that project's
+// domain is returned, with its cluster briefs; otherwise every domain is
+// returned without cluster briefs when withClusters is false.
+func Load(ctx context.Context, st Stored, project string, withClusters bool) (*Card, error) {
+	card := &Card{Domains: []Domain{}}
+	if project == "" {
+		cards, err := st.ListBriefs(ctx, store.BriefLevelStore)
+		if err != nil {
+			return nil, err
+		}
+		if len(cards) > 0 {
+			card.Identity = &cards[0]
+		}
+	}
+	projects, err := st.ListBriefs(ctx, store.BriefLevelProject)
+	if err != nil {
+		return nil, err
+	}
+	var clusters []store.Brief
+	if withClusters || project != "" {
+		if clusters, err = st.ListBriefs(ctx, store.BriefLevelCluster); err != nil {
+			return nil, err
+		}
+	}
+	for _, b := range projects {
+		if project != "" && !matchProject(b.Project, project) {
+			continue
+		}
+		d := Domain{Brief: b}
+		for _, c := range clusters {
+			if c.Project == b.Project {
+				d.Clusters = append(d.Clusters, c)
+			}
+		}
+		card.Domains = append(card.Domains, d)
+	}
+	sort.SliceStable(card.Domains, func(i, j int) bool { return card.Domains[i].FactCount > card.Domains[j].FactCount })
+	return card, nil
+}
+
+func storedByKey(ctx context.Context, st Stored, level string) (map[string]store.Brief, error) {
+	list, err := st.ListBriefs(ctx, level)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]store.Brief, len(list))
+	for _, b := range list {
+		out[b.Key] = b
+	}
+	return out, nil
+}
+
+func sliceFingerprint(s store.BriefSlice) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%d|%s|%d", s.Project, s.ClusterID, s.ClusterName, s.FactCount)
+	for _, f := range s.Facts {
+		fmt.Fprintf(h, "|%d:%s:%s:%s:%.2f", f.ID, f.Subject, f.Predicate, f.Object, f.Confidence)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func rollupFingerprint(children []store.Brief) string {
+	keys := make([]string, len(children))
+	for i, c := range children {
+		keys[i] = c.Key + "=" + c.Fingerprint
+	}
+	sort.Strings(keys)
+	h := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+	return hex.EncodeToString(h[:])[:16]
+}
+
+// matchProject reports whether a brief's project is the one the user asked
+// for, case-insensitively; UntaggedProject selects memories without one.
+func matchProject(project, want string) bool {
+	return strings.EqualFold(projectLabel(project), strings.TrimSpace(want))
+}
+
+func projectLabel(project string) string {
+	if project == "" {
+		return UntaggedProject
+	}
+	return project
+}
+
+func clusterTitle(s store.BriefSlice) string {
+	if s.ClusterName != "" {
+		return s.ClusterName
+	}
+	return fmt.Sprintf("cluster %d", s.ClusterID)
+}
+
+func generate(ctx context.Context, provider llm.Provider, prompt string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, briefTimeout)
+	defer cancel()
+	out, err := provider.Complete(ctx, prompt, llm.CompletionOpts{
+		System:      briefSystemPrompt,
+		Temperature: 0.2,
+		MaxTokens:   600,
+	})
+	if err != nil {
+		return "", err
+	}
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return "", fmt.Errorf("empty brief from %s", provider.Name())
+	}
+	return out, nil
+}
@@ -0,0 +1,168 @@
+package brief
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+type fakeStore struct {
+	slices []store.BriefSlice
+	briefs map[string]store.Brief // level + "/" + key
+}
+
+func newFakeStore(slices ...store.BriefSlice) *fakeStore {
+	return &fakeStore{slices: slices, briefs: map[string]store.Brief{}}
+}
+
+func (f *fakeStore) BriefSlices(context.Context, int) ([]store.BriefSlice, error) {
+	return f.slices, nil
+}
+
+func (f *fakeStore) ListBriefs(_ context.Context, level string) ([]store.Brief, error) {
+	var out []store.Brief
+	for _, b := range f.briefs {
+		if b.Level == level {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStore) UpsertBrief(_ context.Context, b *store.Brief) error {
+	f.briefs[b.Level+"/"+b.Key] = *b
+	return nil
+}
+
+func (f *fakeStore) DeleteBrief(_ context.Context, level, key string) error {
+	delete(f.briefs, level+"/"+key)
+	return nil
+}
+
+type stubProvider struct {
+	calls []string
+	fail  string // prompts containing this fail
+}
+
+func (p *stubProvider) Complete(_ context.Context, prompt string, _ llm.CompletionOpts) (string, error) {
+	p.calls = append(p.calls, prompt)
+	if p.fail != "" && strings.Contains(prompt, p.fail) {
+		return "", errors.New("boom")
+	}
+	return "brief #" + string(rune('0'+len(p.calls))), nil
+}
+
+func (p *stubProvider) Name() string { return "stub/model" }
+
+func slice(project string, cluster int64, name string, objects ...string) store.BriefSlice {
+	s := store.BriefSlice{Project: project, ClusterID: cluster, ClusterName: name, FactCount: len(objects)}
+	for i, o := range objects {
+		s.Facts = append(s.Facts, &store.Fact{ID: cluster*100 + int64(i), Subject: name, Predicate: "is", Object: o, Confidence: 0.9})
+	}
+	return s
+}
+
+func TestRefresh_BuildsHierarchyIncrementally(t *testing.T) {
+	ctx := context.Background()
+	st := newFakeStore(
+		slice("ops", 1, "deploy", "fly.io", "iad", "blue-green"),
+		slice("ops", 2, "alerts", "pagerduty", "slack", "sms"),
+		slice("trading", 3, "orb", "alpaca", "5m", "spy"),
+		slice("trading", 4, "tiny", "one"), // below MinFacts
+	)
+	p := &stubProvider{}
+
+	res, err := Refresh(ctx, st, p, Options{})
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if res.Generated[store.BriefLevelCluster] != 3 || res.Generated[store.BriefLevelProject] != 2 || res.Generated[store.BriefLevelStore] != 1 {
+		t.Fatalf("unexpected first refresh: %+v", res)
+	}
+
+	// Nothing changed: no LLM calls.
+	p.calls = nil
+	res, err = Refresh(ctx, st, p, Options{})
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if len(p.calls) != 0 || res.Unchanged != 6 {
+		t.Fatalf("expected an all-unchanged refresh, got %d calls, %+v", len(p.calls), res)
+	}
+	if n, _ := Stale(ctx, st, Options{}); n != 0 {
+		t.Fatalf("expected nothing stale, got %d", n)
+	}
+
+	// One cluster changes: that cluster, its project, and the store are redone.
+	st.slices[0] = slice("ops", 1, "deploy", "fly.io", "ord", "blue-green")
+	if n, _ := Stale(ctx, st, Options{}); n != 1 {
+		t.Fatalf("expected one stale cluster, got %d", n)
+	}
+	p.calls = nil
+	res, err = Refresh(ctx, st, p, Options{})
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if len(p.calls) != 3 || res.Generated[store.BriefLevelCluster] != 1 || res.Unchanged != 3 {
+		t.Fatalf("expected cluster+project+store regenerated, got %d calls, %+v", len(p.calls), res)
+	}
+
+	// A cluster disappears: its brief is removed.
+	st.slices = st.slices[:3]
+	st.slices = append(st.slices[:1], st.slices[2:]...)
+	res, err = Refresh(ctx, st, p, Options{})
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if res.Removed != 1 {
+		t.Fatalf("expected one removed brief, got %+v", res)
+	}
+
+	card, err := Load(ctx, st, "", false)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if card.Identity == nil || len(card.Domains) != 2 || len(card.Domains[0].Clusters) != 0 {
+		t.Fatalf("unexpected card: %+v", card)
+	}
+	ops, err := Load(ctx, st, "OPS", false)
+	if err != nil {
+		t.Fatalf("Load(ops): %v", err)
+	}
+	if ops.Identity != nil || len(ops.Domains) != 1 || len(ops.Domains[0].Clusters) != 1 {
+		t.Fatalf("unexpected project card: %+v", ops)
+	}
+}
+
+func TestRefresh_FailureKeepsOldBriefAndProjectScope(t *testing.T) {
+	ctx := context.Background()
+	st := newFakeStore(
+		slice("ops", 1, "deploy", "fly.io", "iad", "blue-green"),
+		slice("trading", 3, "orb", "alpaca", "5m", "spy"),
+	)
+	if _, err := Refresh(ctx, st, &stubProvider{}, Options{}); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	before := st.briefs[store.BriefLevelCluster+"/"+store.ClusterBriefKey("ops", 1)].Summary
+
+	st.slices[0] = slice("ops", 1, "deploy", "fly.io", "ord", "blue-green")
+	st.slices[1] = slice("trading", 3, "orb", "alpaca", "15m", "spy")
+	p := &stubProvider{fail: "ord"}
+	res, err := Refresh(ctx, st, p, Options{Project: "ops"})
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if len(res.Errors) != 1 || len(p.calls) != 1 {
+		t.Fatalf("expected only the ops cluster attempted and failed, got %d calls, %+v", len(p.calls), res)
+	}
+	if got := st.briefs[store.BriefLevelCluster+"/"+store.ClusterBriefKey("ops", 1)].Summary; got != before {
+		t.Fatalf("expected the old brief kept, got %q", got)
+	}
+	if n, _ := Stale(ctx, st, Options{}); n != 2 {
+		t.Fatalf("expected both clusters still stale, got %d", n)
+	}
+}
@@ -0,0 +1,43 @@
+package brief
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const briefSystemPrompt = `You write briefs of a personal knowledge store for AI agents that are about to work with it.
+Be concrete: name the people, projects, tools, decisions, and preferences the input mentions.
+Write plain prose, no headings, bullet lists, or preamble. Never invent facts that are not in the input.`
+
+func clusterPrompt(s store.BriefSlice) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Summarize this topic in 2-4 sentences.\nProject: %s\nTopic: %s (%d facts", projectLabel(s.Project), clusterTitle(s), s.FactCount)
+	if len(s.Facts) < s.FactCount {
+		fmt.Fprintf(&b, ", %d highest-confidence shown", len(s.Facts))
+	}
+	b.WriteString(")\n\nFacts:\n")
+	for _, f := range s.Facts {
+		fmt.Fprintf(&b, "- %s %s %s\n", f.Subject, f.Predicate, f.Object)
+	}
+	return b.String()
+}
+
+func projectPrompt(project string, clusters []store.Brief) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Write a 3-6 sentence domain brief for the project %q from its topic briefs below: what the project is, its main threads, and what an agent should know first.\n\n", projectLabel(project))
+	for _, c := range clusters {
+		fmt.Fprintf(&b, "## %s (%d facts)\n%s\n\n", c.Title, c.FactCount, c.Summary)
+	}
+	return b.String()
+}
+
+func storePrompt(domains []store.Brief) string {
+	var b strings.Builder
+	b.WriteString("Write an identity card for this knowledge store in 4-8 sentences: whose memory it is, what they work on, and how the domains below relate. An agent will read it first, before anything else.\n\n")
+	for _, d := range domains {
+		fmt.Fprintf(&b, "## %s (%d facts)\n%s\n\n", d.Title, d.FactCount, d.Summary)
+	}
+	return b.String()
+}
@@ -0,0 +1,160 @@
+// Package circuit provides the circuit breakers Cortex's LLM and embedding
+// fallback chains use to stop calling a provider that keeps failing.
+//
+// Breakers live in a process-wide registry keyed by name (e.g.
+// "llm:openrouter/openai/gpt-4o-mini"), so every chain built in the process
+// — and `cortex doctor` — sees the same state. A breaker opens after
+// Threshold consecutive failures, rejects calls for Cooldown, then lets
+// calls through again (half-open): the next success closes it, the next
+// failure reopens it.
+package circuit
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// Threshold is the number of consecutive failures that opens a breaker.
+	Threshold = 3
+	// Cooldown is how long an open breaker rejects calls.
+	Cooldown = time.Minute
+)
+
+// State is a breaker's state.
+type State string
+
+const (
+	Closed   State = "closed"
+	Open     State = "open"
+	HalfOpen State = "half-open"
+)
+
+// now is the clock; tests replace it.
+var now = time.Now
+
+var (
+	regMu    sync.Mutex
+	registry = map[string]*Breaker{}
+)
+
+// Breaker tracks the health of one provider.
+type Breaker struct {
+	name string
+
+	mu          sync.Mutex
+	consecutive int
+	openUntil   time.Time
+	successes   int
+	failures    int
+	lastErr     string
+	lastFailure time.Time
+}
+
+// Status is a point-in-time view of a breaker.
+type Status struct {
+	Name                string    `json:"name"`
+	State               State     `json:"state"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Successes           int       `json:"successes"`
+	Failures            int       `json:"failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastFailure         time.Time `json:"last_failure,omitempty"`
+	OpenUntil           time.Time `json:"open_until,omitempty"`
+}
+
+// For returns the breaker registered under name, creating it if needed.
+func For(name string) *Breaker {
+	regMu.Lock()
+	defer regMu.Unlock()
+	b, ok := registry[name]
+	if !ok {
+		b = &Breaker{name: name}
+		registry[name] = b
+	}
+	return b
+}
+
+// Snapshot returns the status of every breaker used in this process,
+// sorted by name.
+func Snapshot() []Status {
+	regMu.Lock()
+	breakers := make([]*Breaker, 0, len(registry))
+	for _, b := range registry {
+		breakers = append(breakers, b)
+	}
+	regMu.Unlock()
+
+	out := make([]Status, 0, len(breakers))
+	for _, b := range breakers {
+		out = append(out, b.Status())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Reset forgets all breakers. Intended for tests.
+func Reset() {
+	regMu.Lock()
+	registry = map[string]*Breaker{}
+	regMu.Unlock()
+}
+
+// Allow reports whether a call may be sent: always when closed or
+// half-open, never while open.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stateLocked() != Open
+}
+
+// Record notes the outcome of a call. A nil err closes the breaker.
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		b.successes++
+		b.consecutive = 0
+		b.openUntil = time.Time{}
+		return
+	}
+	halfOpen := b.stateLocked() == HalfOpen
+	b.failures++
+	b.consecutive++
+	b.lastErr = err.Error()
+	b.lastFailure = now()
+	if halfOpen || b.consecutive >= Threshold {
+		b.openUntil = b.lastFailure.Add(Cooldown)
+	}
+}
+
+// Status returns the breaker's current status.
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := Status{
+		Name:                b.name,
+		State:               b.stateLocked(),
+		ConsecutiveFailures: b.consecutive,
+		Successes:           b.successes,
+		Failures:            b.failures,
+		LastError:           b.lastErr,
+		LastFailure:         b.lastFailure,
+	}
+	if s.State == Open {
+		s.OpenUntil = b.openUntil
+	}
+	return s
+}
+
+func (b *Breaker) stateLocked() State {
+	switch {
+	case b.openUntil.IsZero():
+		return Closed
+	case now().Before(b.openUntil):
+		return Open
+	default:
+		return HalfOpen
+	}
+}
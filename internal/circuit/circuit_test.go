@@ -0,0 +1,63 @@
+package circuit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreaker_OpensAfterThresholdAndRecovers(t *testing.T) {
+	Reset()
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	t.Cleanup(func() { now = time.Now; Reset() })
+
+	b := For("llm:test/model")
+	failure := errors.New("status 503")
+	for i := 0; i < Threshold-1; i++ {
+		b.Record(failure)
+	}
+	if !b.Allow() {
+		t.Fatalf("breaker opened before %d failures", Threshold)
+	}
+	b.Record(failure)
+	if b.Allow() {
+		t.Fatal("expected breaker to open after threshold failures")
+	}
+	if st := b.Status(); st.State != Open || st.Failures != Threshold || st.LastError != "status 503" {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+
+	clock = clock.Add(Cooldown)
+	if !b.Allow() || b.Status().State != HalfOpen {
+		t.Fatalf("expected half-open after cooldown, got %+v", b.Status())
+	}
+	b.Record(failure)
+	if b.Allow() {
+		t.Fatal("expected a half-open failure to reopen the breaker")
+	}
+
+	clock = clock.Add(Cooldown)
+	b.Record(nil)
+	if st := b.Status(); st.State != Closed || st.ConsecutiveFailures != 0 || st.Successes != 1 {
+		t.Fatalf("expected closed breaker after success, got %+v", st)
+	}
+}
+
+func TestSnapshot_SharedRegistry(t *testing.T) {
+	Reset()
+	t.Cleanup(Reset)
+
+	For("llm:b").Record(nil)
+	For("embed:a").Record(errors.New("boom"))
+	if For("llm:b") != For("llm:b") {
+		t.Fatal("expected For to return the same breaker")
+	}
+	snap := Snapshot()
+	if len(snap) != 2 || snap[0].Name != "embed:a" || snap[1].Name != "llm:b" {
+		t.Fatalf("unexpected snapshot: %+v", snap)
+	}
+	if snap[0].Failures != 1 || snap[1].Successes != 1 {
+		t.Fatalf("unexpected counts: %+v", snap)
+	}
+}
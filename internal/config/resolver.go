@@ -1,12 +1,17 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hurttlocker/cortex/internal/schedule"
 	"gopkg.in/yaml.v3"
 )
 
@@ -18,6 +23,8 @@ const (
 	SourceEnv     ValueSource = "env"
 	SourceCLI     ValueSource = "cli"
 	SourceDefault ValueSource = "default"
+	// SourceWorkspace marks values taken from a named workspace profile.
+	SourceWorkspace ValueSource = "workspace"
 )
 
 type ResolvedValue struct {
@@ -55,10 +62,143 @@ func (d DenylistEntry) Matches(text string) bool {
 
 type ImportConfig struct {
 	Denylist []DenylistEntry `yaml:"denylist" json:"denylist"`
+	// Quotas caps connector ingestion (flood protection). A connector that
+	// exceeds its quota is paused and an alert is raised.
+	Quotas QuotaConfig `yaml:"quotas" json:"quotas,omitempty"`
+	// Webhook configures the daemon's signed inbound capture endpoint.
+	Webhook WebhookConfig `yaml:"webhook" json:"webhook"`
+}
+
+// WebhookConfig is import.webhook. The endpoint (POST /hooks/{name} on
+// cortex daemon) is off until a secret is set.
+type WebhookConfig struct {
+	// Secret is the HMAC-SHA256 key senders sign payloads with
+	// (CORTEX_WEBHOOK_SECRET overrides it).
+	Secret string `yaml:"secret" json:"-"`
+	// Secrets overrides Secret per hook name, for senders that issue their
+	// own signing secret (e.g. Slack).
+	Secrets map[string]string `yaml:"secrets" json:"-"`
+	// Project tags captures that do not name one.
+	Project string `yaml:"project" json:"project,omitempty"`
+	// Extract queues fact extraction for every capture.
+	Extract bool `yaml:"extract" json:"extract,omitempty"`
+}
+
+// SecretFor returns the signing secret for hook name.
+func (w WebhookConfig) SecretFor(name string) string {
+	if s := strings.TrimSpace(w.Secrets[name]); s != "" {
+		return s
+	}
+	return strings.TrimSpace(w.Secret)
+}
+
+// Enabled reports whether any signing secret is configured.
+func (w WebhookConfig) Enabled() bool {
+	if strings.TrimSpace(w.Secret) != "" {
+		return true
+	}
+	for _, s := range w.Secrets {
+		if strings.TrimSpace(s) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// IngestQuota limits imports per rolling hour and day (0 = unlimited).
+type IngestQuota struct {
+	Hourly int `yaml:"hourly" json:"hourly,omitempty"`
+	Daily  int `yaml:"daily" json:"daily,omitempty"`
+}
+
+// QuotaConfig is import.quotas: a default for every connector plus
+// per-connector overrides keyed by provider name. A connector's own
+// "quota" config takes precedence over both.
+type QuotaConfig struct {
+	IngestQuota `yaml:",inline"`
+	Connectors  map[string]IngestQuota `yaml:"connectors" json:"connectors,omitempty"`
 }
 
 type ExtractConfig struct {
-	SuppressPatterns []DenylistEntry `yaml:"suppress_patterns" json:"suppress_patterns"`
+	SuppressPatterns []DenylistEntry  `yaml:"suppress_patterns" json:"suppress_patterns"`
+	Taxonomy         []FactTypeConfig `yaml:"taxonomy" json:"taxonomy,omitempty"`
+	// ReviewThreshold holds LLM-enriched facts below this confidence for
+	// `cortex review` instead of storing them. nil means
+	// DefaultReviewThreshold; 0 disables the queue.
+	ReviewThreshold *float64 `yaml:"review_threshold" json:"review_threshold,omitempty"`
+}
+
+// DefaultReviewThreshold is the enrichment confidence below which facts go
+// to the review queue when extract.review_threshold is unset.
+const DefaultReviewThreshold = 0.5
+
+// EffectiveReviewThreshold returns the configured review threshold or the default.
+func (c ExtractConfig) EffectiveReviewThreshold() float64 {
+	if c.ReviewThreshold == nil {
+		return DefaultReviewThreshold
+	}
+	return *c.ReviewThreshold
+}
+
+// FactTypeConfig is one entry of the classification taxonomy
+// (extract.taxonomy). A custom type refines a built-in Base type (default
+// kv), which still drives decay and storage. An entry naming a built-in type
+// only replaces its description and examples in the classify prompt.
+type FactTypeConfig struct {
+	Name        string   `yaml:"name" json:"name"`
+	Description string   `yaml:"description" json:"description"`
+	Examples    []string `yaml:"examples" json:"examples,omitempty"`
+	Base        string   `yaml:"base" json:"base,omitempty"`
+}
+
+// BuiltinFactTypes are the fact types every store accepts.
+var BuiltinFactTypes = []string{"decision", "preference", "identity", "relationship", "temporal", "state", "location", "config", "kv"}
+
+var factTypeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,39}$`)
+
+func isBuiltinFactType(name string) bool {
+	for _, t := range BuiltinFactTypes {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTaxonomy lowercases names and bases, defaults bases to kv, and
+// rejects malformed, duplicate, or unbased entries.
+func normalizeTaxonomy(types []FactTypeConfig) error {
+	seen := make(map[string]bool, len(types))
+	for i := range types {
+		t := &types[i]
+		t.Name = strings.ToLower(strings.TrimSpace(t.Name))
+		t.Base = strings.ToLower(strings.TrimSpace(t.Base))
+		t.Description = strings.TrimSpace(t.Description)
+		if !factTypeNamePattern.MatchString(t.Name) {
+			return fmt.Errorf("[%d].name %q must be lowercase letters, digits, '-' or '_' (max 40)", i, t.Name)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("[%d].name %q is defined twice", i, t.Name)
+		}
+		seen[t.Name] = true
+		if isBuiltinFactType(t.Name) {
+			if t.Base != "" && t.Base != t.Name {
+				return fmt.Errorf("[%d] built-in type %q cannot change its base", i, t.Name)
+			}
+			t.Base = t.Name
+			continue
+		}
+		if t.Description == "" {
+			return fmt.Errorf("[%d].description is required for custom type %q", i, t.Name)
+		}
+		if t.Base == "" {
+			t.Base = "kv"
+		}
+		if !isBuiltinFactType(t.Base) {
+			return fmt.Errorf("[%d].base %q must be one of: %s", i, t.Base, strings.Join(BuiltinFactTypes, ", "))
+		}
+	}
+	return nil
 }
 
 type QualityProfile string
@@ -77,6 +217,84 @@ type SearchSourceBoostConfig struct {
 
 type SearchConfig struct {
 	SourceBoosts []SearchSourceBoostConfig `yaml:"source_boosts" json:"source_boosts"`
+	Profiles     SearchProfiles            `yaml:"profiles" json:"profiles,omitempty"`
+	Freshness    SearchFreshnessConfig     `yaml:"freshness" json:"freshness"`
+	// GraphBoost weights the ranking boost for hits linked by fact edges
+	// to the top hits (0 = off, max 1).
+	GraphBoost float64 `yaml:"graph_boost" json:"graph_boost,omitempty"`
+}
+
+// SearchFreshnessConfig enables memory-level recency decay in search
+// scoring. HalfLifeDays is keyed by memory class, with "default" covering
+// unclassified memories; 0 means the class never decays. Floor is the
+// lowest multiplier an old memory can reach (default 0.5).
+type SearchFreshnessConfig struct {
+	Enabled      bool               `yaml:"enabled" json:"enabled"`
+	Floor        float64            `yaml:"floor" json:"floor,omitempty"`
+	HalfLifeDays map[string]float64 `yaml:"half_life_days" json:"half_life_days,omitempty"`
+}
+
+// SearchProfile is a named set of search filters and boosts, selected with
+// `cortex search --profile <name>` or the MCP `profile` parameter. Empty
+// fields leave the corresponding option untouched, and explicit flags win.
+type SearchProfile struct {
+	Project      string                    `yaml:"project" json:"project,omitempty"`
+	Classes      []string                  `yaml:"classes" json:"classes,omitempty"`
+	Source       string                    `yaml:"source" json:"source,omitempty"`
+	Intent       string                    `yaml:"intent" json:"intent,omitempty"`
+	Mode         string                    `yaml:"mode" json:"mode,omitempty"`
+	MinScore     float64                   `yaml:"min_score" json:"min_score,omitempty"`
+	SourceBoosts []SearchSourceBoostConfig `yaml:"source_boosts" json:"source_boosts,omitempty"`
+	// Ranking names a built-in ranking profile, e.g. "agent-context".
+	Ranking string `yaml:"ranking" json:"ranking,omitempty"`
+}
+
+// SearchProfiles maps profile names to their settings.
+type SearchProfiles map[string]SearchProfile
+
+// Lookup returns the named profile. Names are matched case-insensitively.
+func (p SearchProfiles) Lookup(name string) (SearchProfile, error) {
+	name = strings.TrimSpace(name)
+	if profile, ok := p[name]; ok {
+		return profile, nil
+	}
+	for key, profile := range p {
+		if strings.EqualFold(key, name) {
+			return profile, nil
+		}
+	}
+	if len(p) == 0 {
+		return SearchProfile{}, fmt.Errorf("unknown search profile %q (no search.profiles configured)", name)
+	}
+	return SearchProfile{}, fmt.Errorf("unknown search profile %q (available: %s)", name, strings.Join(p.Names(), ", "))
+}
+
+// Names returns the profile names in sorted order.
+func (p SearchProfiles) Names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HNSWConfig exposes the HNSW index parameters. Zero values fall back to the
+// built-in defaults (M=16, ef_construction=200, ef_search=50).
+type HNSWConfig struct {
+	M              int `yaml:"m" json:"m"`
+	EfConstruction int `yaml:"ef_construction" json:"ef_construction"`
+	EfSearch       int `yaml:"ef_search" json:"ef_search"`
+}
+
+type IndexConfig struct {
+	HNSW HNSWConfig `yaml:"hnsw" json:"hnsw"`
+}
+
+// LLMAutoConfig configures `--llm auto` routing (llm.auto in config.yaml).
+type LLMAutoConfig struct {
+	Tier           string  `yaml:"tier" json:"tier"`                         // economy | balanced | quality
+	DailyBudgetUSD float64 `yaml:"daily_budget_usd" json:"daily_budget_usd"` // 0 = unlimited
 }
 
 type IntegrationMode string
@@ -103,6 +321,195 @@ type ResolveOptions struct {
 	CLILLM     string
 	CLIEmbed   string
 	CLIDBPath  string
+	// Workspace selects a named profile from workspaces:. Empty falls back
+	// to CORTEX_WORKSPACE, then to the config's active workspace.
+	Workspace string
+}
+
+// WorkspaceConfig is a named profile under workspaces: that bundles a
+// database with its model defaults.
+type WorkspaceConfig struct {
+	DB    string `yaml:"db" json:"db"`
+	Embed string `yaml:"embed,omitempty" json:"embed,omitempty"`
+	LLM   string `yaml:"llm,omitempty" json:"llm,omitempty"`
+	// Weight scales this workspace's hits when several are searched at
+	// once (cortex --workspace a,b search). 0 means 1.
+	Weight float64 `yaml:"weight,omitempty" json:"weight,omitempty"`
+}
+
+// ScheduleConfig is one entry of the schedules: list, run by
+// `cortex daemon --with-schedules`.
+type ScheduleConfig struct {
+	Name string `yaml:"name" json:"name"`
+	// Task is connector_sync, embed, maintain, or digest.
+	Task string `yaml:"task" json:"task"`
+	// Cron is a five-field cron expression, a macro such as @daily, or
+	// "@every 30m".
+	Cron string `yaml:"cron" json:"cron"`
+	// Jitter delays each run by a random duration up to this long ("5m").
+	Jitter string `yaml:"jitter,omitempty" json:"jitter,omitempty"`
+
+	// Provider limits connector_sync to one connector (default: all).
+	Provider string `yaml:"provider,omitempty" json:"provider,omitempty"`
+	// Extract runs fact extraction on records a connector_sync imports.
+	Extract bool `yaml:"extract,omitempty" json:"extract,omitempty"`
+	// Project scopes a digest; LLM overrides its model.
+	Project string `yaml:"project,omitempty" json:"project,omitempty"`
+	LLM     string `yaml:"llm,omitempty" json:"llm,omitempty"`
+}
+
+// JitterDuration returns the parsed jitter, or 0 when unset.
+func (s ScheduleConfig) JitterDuration() time.Duration {
+	d, _ := time.ParseDuration(strings.TrimSpace(s.Jitter))
+	return d
+}
+
+// minScheduleEvery is the shortest "@every" interval a schedule may use.
+const minScheduleEvery = time.Minute
+
+func validateSchedules(schedules []ScheduleConfig) error {
+	seen := map[string]bool{}
+	for i, sc := range schedules {
+		if !ValidWorkspaceName(sc.Name) {
+			return fmt.Errorf("schedules[%d].name: invalid name %q", i, sc.Name)
+		}
+		if seen[sc.Name] {
+			return fmt.Errorf("schedules[%d].name: %q is used twice", i, sc.Name)
+		}
+		seen[sc.Name] = true
+		if !schedule.ValidTask(sc.Task) {
+			return fmt.Errorf("schedules.%s.task: unknown task %q (valid: %s)", sc.Name, sc.Task, strings.Join(schedule.Tasks, ", "))
+		}
+		parsed, err := schedule.ParseCron(sc.Cron)
+		if err != nil {
+			return fmt.Errorf("schedules.%s.cron: %w", sc.Name, err)
+		}
+		if every := schedule.Interval(parsed); every > 0 && every < minScheduleEvery {
+			return fmt.Errorf("schedules.%s.cron: @every must be at least %s", sc.Name, minScheduleEvery)
+		}
+		if raw := strings.TrimSpace(sc.Jitter); raw != "" {
+			if d, err := time.ParseDuration(raw); err != nil || d < 0 {
+				return fmt.Errorf("schedules.%s.jitter: invalid duration %q", sc.Name, sc.Jitter)
+			}
+		}
+	}
+	return nil
+}
+
+// StorageConfig is storage:, the database size budget. Once the database
+// grows past Budget, automatic tiering shrinks it; cortex stats warns from
+// WarnAt of the budget on.
+type StorageConfig struct {
+	// Budget is a size such as "2GB"; empty means no budget.
+	Budget string `yaml:"budget" json:"budget,omitempty"`
+	// WarnAt is the fraction of the budget at which stats starts warning
+	// (default 0.8).
+	WarnAt  float64       `yaml:"warn_at" json:"warn_at,omitempty"`
+	Tiering TieringConfig `yaml:"tiering" json:"tiering"`
+}
+
+// TieringConfig is storage.tiering: what automatic tiering may do.
+type TieringConfig struct {
+	// Disabled keeps the budget a warning only.
+	Disabled bool `yaml:"disabled" json:"disabled,omitempty"`
+	// CompactAfterDays is the age past which memories are compacted
+	// (default 30).
+	CompactAfterDays int `yaml:"compact_after_days" json:"compact_after_days,omitempty"`
+	// CompactClasses are the memory classes compacted (default scratch).
+	CompactClasses []string `yaml:"compact_classes" json:"compact_classes,omitempty"`
+	// NoQuantize stops tiering from switching embeddings to int8.
+	NoQuantize bool `yaml:"no_quantize" json:"no_quantize,omitempty"`
+}
+
+const (
+	defaultStorageWarnAt    = 0.8
+	defaultCompactAfterDays = 30
+)
+
+// BudgetBytes returns the parsed budget, or 0 when none is set.
+func (s StorageConfig) BudgetBytes() int64 {
+	if strings.TrimSpace(s.Budget) == "" {
+		return 0
+	}
+	n, _ := ParseByteSize(s.Budget)
+	return n
+}
+
+// WarnRatio returns WarnAt, or its default when unset.
+func (s StorageConfig) WarnRatio() float64 {
+	if s.WarnAt <= 0 {
+		return defaultStorageWarnAt
+	}
+	return s.WarnAt
+}
+
+// CompactAge returns how old a memory must be before tiering compacts it.
+func (t TieringConfig) CompactAge() time.Duration {
+	days := t.CompactAfterDays
+	if days <= 0 {
+		days = defaultCompactAfterDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Classes returns the memory classes tiering compacts.
+func (t TieringConfig) Classes() []string {
+	if classes := cleanList(t.CompactClasses); len(classes) > 0 {
+		return classes
+	}
+	return []string{"scratch"}
+}
+
+func validateStorage(s StorageConfig) error {
+	if strings.TrimSpace(s.Budget) != "" {
+		if _, err := ParseByteSize(s.Budget); err != nil {
+			return fmt.Errorf("storage.budget: %w", err)
+		}
+	}
+	if s.WarnAt < 0 || s.WarnAt > 1 {
+		return fmt.Errorf("storage.warn_at: must be between 0 and 1, got %g", s.WarnAt)
+	}
+	if s.Tiering.CompactAfterDays < 0 {
+		return fmt.Errorf("storage.tiering.compact_after_days: must not be negative, got %d", s.Tiering.CompactAfterDays)
+	}
+	return nil
+}
+
+// ParseByteSize parses sizes such as "10MB", "512k", "1.5GiB", or plain bytes.
+// Units are binary (1KB = 1024 bytes).
+func ParseByteSize(raw string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(raw))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "IB"), "B")
+	mult := int64(1)
+	if n := len(s); n > 0 {
+		switch s[n-1] {
+		case 'K':
+			mult = 1 << 10
+		case 'M':
+			mult = 1 << 20
+		case 'G':
+			mult = 1 << 30
+		}
+		if mult > 1 {
+			s = s[:n-1]
+		}
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || f <= 0 {
+		return 0, fmt.Errorf("invalid size %q (examples: 500KB, 10MB, 1GB)", raw)
+	}
+	return int64(f * float64(mult)), nil
+}
+
+// ErrUnknownWorkspace is returned when the selected workspace is not
+// defined under workspaces:.
+var ErrUnknownWorkspace = errors.New("unknown workspace")
+
+var workspaceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// ValidWorkspaceName reports whether name can be used as a workspace key.
+func ValidWorkspaceName(name string) bool {
+	return workspaceNamePattern.MatchString(name)
 }
 
 type ReinforcePromotePolicy struct {
@@ -125,12 +532,24 @@ type ConflictSupersedePolicy struct {
 	MinConfidenceDelta   float64 `yaml:"min_confidence_delta" json:"min_confidence_delta"`
 }
 
+// ConflictSeverityPolicy tunes conflict severity scoring.
+type ConflictSeverityPolicy struct {
+	// CriticalThreshold is the severity score (0–1) at or above which a
+	// conflict is critical and escalated as an alert (and webhook).
+	CriticalThreshold float64 `yaml:"critical_threshold" json:"critical_threshold"`
+}
+
 type PolicyConfig struct {
 	ReinforcePromote  ReinforcePromotePolicy  `yaml:"reinforce_promote" json:"reinforce_promote"`
 	DecayRetire       DecayRetirePolicy       `yaml:"decay_retire" json:"decay_retire"`
 	ConflictSupersede ConflictSupersedePolicy `yaml:"conflict_supersede" json:"conflict_supersede"`
+	ConflictSeverity  ConflictSeverityPolicy  `yaml:"conflict_severity" json:"conflict_severity"`
 	DecayRates        map[string]float64      `yaml:"decay_rates" json:"decay_rates"`
 	PredicatePolicies map[string]string       `yaml:"predicate_policies" json:"predicate_policies"`
+	// PredicateAliases maps predicate phrasings to a canonical predicate on
+	// top of the built-in table (alias: canonical; canonical "" removes a
+	// built-in alias).
+	PredicateAliases map[string]string `yaml:"predicate_aliases,omitempty" json:"predicate_aliases,omitempty"`
 }
 
 type AgentTrustRule struct {
@@ -191,6 +610,9 @@ func DefaultPolicyConfig() PolicyConfig {
 			RequireStrictlyNewer: true,
 			MinConfidenceDelta:   0.02,
 		},
+		ConflictSeverity: ConflictSeverityPolicy{
+			CriticalThreshold: 0.8,
+		},
 		DecayRates: map[string]float64{
 			"identity":     0.01,
 			"preference":   0.02,
@@ -223,41 +645,64 @@ type ResolvedConfig struct {
 	LLMEnrichModel   ResolvedValue `json:"llm_enrich_model"`
 	LLMClassifyModel ResolvedValue `json:"llm_classify_model"`
 	LLMExpandModel   ResolvedValue `json:"llm_expand_model"`
+	LLMAuto          LLMAutoConfig `json:"llm_auto"`
+	// LLMFallback lists provider/model specs tried in order when the
+	// primary LLM provider fails (llm.fallback, CORTEX_LLM_FALLBACK).
+	LLMFallback []string `json:"llm_fallback,omitempty"`
 
 	EmbedProvider ResolvedValue `json:"embed_provider"`
 	EmbedAPIKey   ResolvedValue `json:"embed_api_key"`
 	EmbedEndpoint ResolvedValue `json:"embed_endpoint"`
+	// EmbedFallback lists embed provider/model specs tried in order when the
+	// primary embedder fails (embed.fallback, CORTEX_EMBED_FALLBACK). They
+	// must produce vectors in the same space as the primary.
+	EmbedFallback []string `json:"embed_fallback,omitempty"`
 
 	Policies       PolicyConfig             `json:"policies"`
 	ObsidianExport ObsidianExportConfig     `json:"obsidian_export"`
 	Import         ImportConfig             `json:"import"`
 	Extract        ExtractConfig            `json:"extract"`
 	Search         SearchConfig             `json:"search"`
+	Index          IndexConfig              `json:"index"`
 	Integrations   IntegrationsConfig       `json:"integrations"`
 	LLMKeys        map[string]ResolvedValue `json:"llm_keys,omitempty"`
+
+	Workspace  ResolvedValue              `json:"workspace"`
+	Workspaces map[string]WorkspaceConfig `json:"workspaces,omitempty"`
+
+	Schedules []ScheduleConfig `json:"schedules,omitempty"`
+	Storage   StorageConfig    `json:"storage"`
 }
 
 type fileConfig struct {
-	Profile string `yaml:"profile"`
-	DBPath  string `yaml:"db_path"`
-	LLM     struct {
-		Provider         string `yaml:"provider"`
-		APIKey           string `yaml:"api_key"`
-		EnrichModel      string `yaml:"enrich_model"`
-		EnrichProvider   string `yaml:"enrich_provider"`
-		ClassifyModel    string `yaml:"classify_model"`
-		ClassifyProvider string `yaml:"classify_provider"`
-		ExpandModel      string `yaml:"expand_model"`
-		ExpandProvider   string `yaml:"expand_provider"`
+	Profile    string                     `yaml:"profile"`
+	DBPath     string                     `yaml:"db_path"`
+	Workspace  string                     `yaml:"workspace"`
+	Workspaces map[string]WorkspaceConfig `yaml:"workspaces"`
+	Schedules  []ScheduleConfig           `yaml:"schedules"`
+	Storage    StorageConfig              `yaml:"storage"`
+	LLM        struct {
+		Provider         string        `yaml:"provider"`
+		APIKey           string        `yaml:"api_key"`
+		EnrichModel      string        `yaml:"enrich_model"`
+		EnrichProvider   string        `yaml:"enrich_provider"`
+		ClassifyModel    string        `yaml:"classify_model"`
+		ClassifyProvider string        `yaml:"classify_provider"`
+		ExpandModel      string        `yaml:"expand_model"`
+		ExpandProvider   string        `yaml:"expand_provider"`
+		Auto             LLMAutoConfig `yaml:"auto"`
+		Fallback         []string      `yaml:"fallback"`
 	} `yaml:"llm"`
 	Embed struct {
-		Provider string `yaml:"provider"`
-		APIKey   string `yaml:"api_key"`
-		Endpoint string `yaml:"endpoint"`
+		Provider string   `yaml:"provider"`
+		APIKey   string   `yaml:"api_key"`
+		Endpoint string   `yaml:"endpoint"`
+		Fallback []string `yaml:"fallback"`
 	} `yaml:"embed"`
 	Import       ImportConfig  `yaml:"import"`
 	Extract      ExtractConfig `yaml:"extract"`
 	Search       SearchConfig  `yaml:"search"`
+	Index        IndexConfig   `yaml:"index"`
 	Integrations struct {
 		OpenClaw struct {
 			Mode string `yaml:"mode"`
@@ -309,12 +754,16 @@ func ResolveConfig(opts ResolveOptions) (ResolvedConfig, error) {
 		out.Import = cfg.Import
 		out.Extract = cfg.Extract
 		out.Search = cfg.Search
+		out.Index = cfg.Index
 		applyIntegrationMode(&out.Integrations.OpenClaw.Mode, cfg.Integrations.OpenClaw.Mode, SourceConfig, path)
 		apply(&out.DBPath, cfg.DBPath, SourceConfig, path)
 		apply(&out.LLMProvider, cfg.LLM.Provider, SourceConfig, path)
 		apply(&out.LLMEnrichModel, firstNonEmpty(cfg.LLM.EnrichModel, cfg.LLM.EnrichProvider), SourceConfig, path)
 		apply(&out.LLMClassifyModel, firstNonEmpty(cfg.LLM.ClassifyModel, cfg.LLM.ClassifyProvider), SourceConfig, path)
 		apply(&out.LLMExpandModel, firstNonEmpty(cfg.LLM.ExpandModel, cfg.LLM.ExpandProvider), SourceConfig, path)
+		out.LLMAuto = cfg.LLM.Auto
+		out.LLMFallback = cleanList(cfg.LLM.Fallback)
+		out.EmbedFallback = cleanList(cfg.Embed.Fallback)
 		apply(&out.EmbedProvider, cfg.Embed.Provider, SourceConfig, path)
 		apply(&out.EmbedEndpoint, cfg.Embed.Endpoint, SourceConfig, path)
 
@@ -322,6 +771,11 @@ func ResolveConfig(opts ResolveOptions) (ResolvedConfig, error) {
 			out.EmbedAPIKey = ResolvedValue{Value: key, Source: SourceConfig, From: path}
 		}
 
+		out.Workspaces = cfg.Workspaces
+		out.Schedules = cfg.Schedules
+		out.Storage = cfg.Storage
+		apply(&out.Workspace, cfg.Workspace, SourceConfig, path)
+
 		if key := strings.TrimSpace(cfg.LLM.APIKey); key != "" {
 			providers := map[string]struct{}{}
 			for _, v := range []string{cfg.LLM.Provider, cfg.LLM.EnrichModel, cfg.LLM.ClassifyModel, cfg.LLM.ExpandModel} {
@@ -339,6 +793,23 @@ func ResolveConfig(opts ResolveOptions) (ResolvedConfig, error) {
 		}
 	}
 
+	// A workspace made active in config.yaml layers over the base config;
+	// one chosen per invocation (--workspace / CORTEX_WORKSPACE) also beats
+	// environment defaults. Explicit --db/--llm/--embed still win.
+	applyEnv(&out.Workspace, "CORTEX_WORKSPACE")
+	apply(&out.Workspace, opts.Workspace, SourceCLI, "--workspace")
+	var ws WorkspaceConfig
+	if name := out.Workspace.Value; name != "" {
+		var ok bool
+		if ws, ok = out.Workspaces[name]; !ok {
+			return out, fmt.Errorf("%w %q (selected by %s); run `cortex workspace list`", ErrUnknownWorkspace, name, out.Workspace.From)
+		}
+	}
+	wsFrom := "workspace " + out.Workspace.Value
+	if out.Workspace.Source == SourceConfig {
+		applyWorkspace(&out, ws, wsFrom)
+	}
+
 	applyEnv(&out.DBPath, "CORTEX_DB")
 	applyEnv(&out.DBPath, "CORTEX_DB_PATH")
 
@@ -346,12 +817,30 @@ func ResolveConfig(opts ResolveOptions) (ResolvedConfig, error) {
 	applyEnv(&out.LLMEnrichModel, "CORTEX_LLM_ENRICH")
 	applyEnv(&out.LLMClassifyModel, "CORTEX_LLM_CLASSIFY")
 	applyEnv(&out.LLMExpandModel, "CORTEX_LLM_EXPAND")
+	if v := strings.TrimSpace(os.Getenv("CORTEX_LLM_TIER")); v != "" {
+		out.LLMAuto.Tier = v
+	}
+	if v := strings.TrimSpace(os.Getenv("CORTEX_LLM_DAILY_BUDGET")); v != "" {
+		if budget, err := strconv.ParseFloat(v, 64); err == nil {
+			out.LLMAuto.DailyBudgetUSD = budget
+		}
+	}
+
+	if v := strings.TrimSpace(os.Getenv("CORTEX_LLM_FALLBACK")); v != "" {
+		out.LLMFallback = cleanList(strings.Split(v, ","))
+	}
 
 	applyEnv(&out.EmbedProvider, "CORTEX_EMBED")
+	if v := strings.TrimSpace(os.Getenv("CORTEX_EMBED_FALLBACK")); v != "" {
+		out.EmbedFallback = cleanList(strings.Split(v, ","))
+	}
 	applyEnv(&out.EmbedEndpoint, "CORTEX_EMBED_ENDPOINT")
 	if v := strings.TrimSpace(os.Getenv("CORTEX_EMBED_API_KEY")); v != "" {
 		out.EmbedAPIKey = ResolvedValue{Value: v, Source: SourceEnv, From: "CORTEX_EMBED_API_KEY"}
 	}
+	if v := strings.TrimSpace(os.Getenv("CORTEX_WEBHOOK_SECRET")); v != "" {
+		out.Import.Webhook.Secret = v
+	}
 	applyIntegrationMode(&out.Integrations.OpenClaw.Mode, os.Getenv("CORTEX_OPENCLAW_MODE"), SourceEnv, "CORTEX_OPENCLAW_MODE")
 	if enabled, ok := parseEnvBool(os.Getenv("CORTEX_OPENCLAW_ENABLED")); ok {
 		mode := string(IntegrationModeDisabled)
@@ -373,6 +862,10 @@ func ResolveConfig(opts ResolveOptions) (ResolvedConfig, error) {
 		}
 	}
 
+	if out.Workspace.Source == SourceEnv || out.Workspace.Source == SourceCLI {
+		applyWorkspace(&out, ws, wsFrom)
+	}
+
 	apply(&out.LLMProvider, opts.CLILLM, SourceCLI, "--llm")
 	apply(&out.EmbedProvider, opts.CLIEmbed, SourceCLI, "--embed")
 	apply(&out.DBPath, opts.CLIDBPath, SourceCLI, "--db")
@@ -471,7 +964,7 @@ func (r ResolvedConfig) EffectiveLLMModel(purpose, fallback string) ResolvedValu
 		if strings.TrimSpace(c.Value) == "" {
 			continue
 		}
-		if strings.Contains(c.Value, "/") {
+		if strings.Contains(c.Value, "/") || strings.EqualFold(strings.TrimSpace(c.Value), "auto") {
 			return c
 		}
 		if fallback != "" && strings.HasPrefix(strings.ToLower(fallback), strings.ToLower(strings.TrimSpace(c.Value))+"/") {
@@ -518,6 +1011,23 @@ func apply(dst *ResolvedValue, raw string, source ValueSource, from string) {
 	*dst = ResolvedValue{Value: v, Source: source, From: from}
 }
 
+func applyWorkspace(out *ResolvedConfig, ws WorkspaceConfig, from string) {
+	apply(&out.DBPath, ws.DB, SourceWorkspace, from)
+	apply(&out.LLMProvider, ws.LLM, SourceWorkspace, from)
+	apply(&out.EmbedProvider, ws.Embed, SourceWorkspace, from)
+}
+
+// cleanList trims entries and drops empty ones.
+func cleanList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 func applyEnv(dst *ResolvedValue, envKey string) {
 	if v := strings.TrimSpace(os.Getenv(envKey)); v != "" {
 		*dst = ResolvedValue{Value: v, Source: SourceEnv, From: envKey}
@@ -559,6 +1069,29 @@ func loadConfig(path string) (*fileConfig, error) {
 			return nil, fmt.Errorf("parsing %s extract.suppress_patterns[%d].pattern: %w", path, i, err)
 		}
 	}
+	for name, ws := range cfg.Workspaces {
+		if !ValidWorkspaceName(name) {
+			return nil, fmt.Errorf("parsing %s workspaces: invalid workspace name %q", path, name)
+		}
+		if strings.TrimSpace(ws.DB) == "" {
+			return nil, fmt.Errorf("parsing %s workspaces.%s.db is required", path, name)
+		}
+		if ws.Weight < 0 {
+			return nil, fmt.Errorf("parsing %s workspaces.%s.weight: must not be negative, got %g", path, name, ws.Weight)
+		}
+	}
+	if err := validateSchedules(cfg.Schedules); err != nil {
+		return nil, fmt.Errorf("parsing %s %w", path, err)
+	}
+	if err := validateStorage(cfg.Storage); err != nil {
+		return nil, fmt.Errorf("parsing %s %w", path, err)
+	}
+	if t := cfg.Extract.ReviewThreshold; t != nil && (*t < 0 || *t > 1) {
+		return nil, fmt.Errorf("parsing %s extract.review_threshold: must be between 0 and 1, got %g", path, *t)
+	}
+	if err := normalizeTaxonomy(cfg.Extract.Taxonomy); err != nil {
+		return nil, fmt.Errorf("parsing %s extract.taxonomy%w", path, err)
+	}
 	return &cfg, nil
 }
 
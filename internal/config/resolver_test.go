@@ -1,10 +1,12 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestResolveConfig_Precedence_ConfigEnvCLI(t *testing.T) {
@@ -107,6 +109,9 @@ func TestResolveConfig_PolicyDefaultsWhenMissing(t *testing.T) {
 	if !resolved.Policies.ConflictSupersede.Enabled {
 		t.Fatalf("expected default conflict-supersede enabled, got %+v", resolved.Policies.ConflictSupersede)
 	}
+	if resolved.Policies.ConflictSeverity.CriticalThreshold != 0.8 {
+		t.Fatalf("expected default critical threshold 0.8, got %+v", resolved.Policies.ConflictSeverity)
+	}
 }
 
 func TestResolveConfig_PolicyPartialOverrides(t *testing.T) {
@@ -255,6 +260,29 @@ policies:
 	}
 }
 
+func TestResolveConfig_IndexHNSWParams(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	yaml := `index:
+  hnsw:
+    m: 24
+    ef_construction: 256
+    ef_search: 96
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	resolved, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath})
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	got := resolved.Index.HNSW
+	if got.M != 24 || got.EfConstruction != 256 || got.EfSearch != 96 {
+		t.Fatalf("unexpected hnsw config: %+v", got)
+	}
+}
+
 func TestResolveConfig_QualityProfileSeedsDefaults(t *testing.T) {
 	tmp := t.TempDir()
 	cfgPath := filepath.Join(tmp, "config.yaml")
@@ -414,3 +442,338 @@ func containsAll(s string, needles ...string) bool {
 	}
 	return true
 }
+
+func TestResolveConfig_ExtractTaxonomy(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	yaml := `extract:
+  taxonomy:
+    - name: Medication
+      description: Drugs, doses, schedules
+      examples: ["takes 5mg daily"]
+      base: state
+    - name: recipe
+      description: Cooking steps
+    - name: config
+      description: Runtime knobs
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	resolved, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath})
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	tax := resolved.Extract.Taxonomy
+	if len(tax) != 3 || tax[0].Name != "medication" || tax[0].Base != "state" || tax[1].Base != "kv" || tax[2].Base != "config" {
+		t.Fatalf("unexpected taxonomy: %+v", tax)
+	}
+
+	for name, bad := range map[string]string{
+		"bad base":  "    - name: med\n      description: x\n      base: drugs\n",
+		"duplicate": "    - name: med\n      description: x\n    - name: MED\n      description: y\n",
+		"bad name":  "    - name: \"has space\"\n      description: x\n",
+		"no desc":   "    - name: med\n",
+		"rebase kv": "    - name: kv\n      base: state\n",
+	} {
+		if err := os.WriteFile(cfgPath, []byte("extract:\n  taxonomy:\n"+bad), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath}); err == nil || !strings.Contains(err.Error(), "extract.taxonomy[") {
+			t.Errorf("%s: expected extract.taxonomy error, got %v", name, err)
+		}
+	}
+}
+
+func TestResolveConfig_ExtractReviewThreshold(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	for body, want := range map[string]float64{
+		"db_path: /tmp/x.db\n":                 DefaultReviewThreshold,
+		"extract:\n  review_threshold: 0.65\n": 0.65,
+		"extract:\n  review_threshold: 0\n":    0,
+	} {
+		if err := os.WriteFile(cfgPath, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		resolved, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath})
+		if err != nil {
+			t.Fatalf("ResolveConfig(%q): %v", body, err)
+		}
+		if got := resolved.Extract.EffectiveReviewThreshold(); got != want {
+			t.Errorf("%q: threshold = %g, want %g", body, got, want)
+		}
+	}
+
+	if err := os.WriteFile(cfgPath, []byte("extract:\n  review_threshold: 1.5\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath}); err == nil || !strings.Contains(err.Error(), "extract.review_threshold") {
+		t.Fatalf("expected extract.review_threshold error, got %v", err)
+	}
+}
+
+func TestResolveConfig_PredicateAliases(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	body := "policies:\n  predicate_aliases:\n    leverages: uses\n    works for: \"\"\n"
+	if err := os.WriteFile(cfgPath, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath})
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	aliases := resolved.Policies.PredicateAliases
+	if aliases["leverages"] != "uses" {
+		t.Fatalf("expected leverages alias, got %+v", aliases)
+	}
+	if v, ok := aliases["works for"]; !ok || v != "" {
+		t.Fatalf("expected empty override to be kept for removal, got %+v", aliases)
+	}
+	if len(resolved.Policies.PredicatePolicies) == 0 {
+		t.Fatal("predicate_aliases must not drop default predicate_policies")
+	}
+}
+
+func TestResolveConfig_Workspaces(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	yaml := `db_path: ~/.cortex/base.db
+llm:
+  provider: google/gemini-2.5-flash
+workspace: personal
+workspaces:
+  personal:
+    db: ~/personal.db
+  work:
+    db: /srv/work.db
+    embed: ollama/nomic-embed-text
+    llm: openrouter/deepseek/deepseek-v3.2
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("CORTEX_WORKSPACE", "")
+	t.Setenv("CORTEX_DB", "")
+
+	resolved, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath})
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	if !strings.HasSuffix(resolved.DBPath.Value, "/personal.db") || resolved.DBPath.Source != SourceWorkspace {
+		t.Fatalf("active workspace should set the DB, got %+v", resolved.DBPath)
+	}
+	if resolved.LLMProvider.Value != "google/gemini-2.5-flash" {
+		t.Fatalf("workspace without llm should keep the base provider, got %+v", resolved.LLMProvider)
+	}
+
+	// An active workspace yields to env; an explicit one beats it.
+	t.Setenv("CORTEX_DB", "/env.db")
+	if resolved, _ = ResolveConfig(ResolveOptions{ConfigPath: cfgPath}); resolved.DBPath.Value != "/env.db" {
+		t.Fatalf("env should override the active workspace, got %+v", resolved.DBPath)
+	}
+	resolved, err = ResolveConfig(ResolveOptions{ConfigPath: cfgPath, Workspace: "work"})
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	if resolved.DBPath.Value != "/srv/work.db" || resolved.EmbedProvider.Value != "ollama/nomic-embed-text" ||
+		resolved.LLMProvider.From != "workspace work" {
+		t.Fatalf("--workspace should select work, got db=%+v embed=%+v llm=%+v", resolved.DBPath, resolved.EmbedProvider, resolved.LLMProvider)
+	}
+	if resolved, _ = ResolveConfig(ResolveOptions{ConfigPath: cfgPath, Workspace: "work", CLIDBPath: "/cli.db"}); resolved.DBPath.Value != "/cli.db" {
+		t.Fatalf("--db should win over --workspace, got %+v", resolved.DBPath)
+	}
+
+	t.Setenv("CORTEX_WORKSPACE", "nope")
+	if _, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath}); !errors.Is(err, ErrUnknownWorkspace) {
+		t.Fatalf("expected ErrUnknownWorkspace, got %v", err)
+	}
+
+	t.Setenv("CORTEX_WORKSPACE", "")
+	bad := strings.Replace(yaml, "db: /srv/work.db", "db: /srv/work.db\n    weight: -1", 1)
+	if err := os.WriteFile(cfgPath, []byte(bad), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath}); err == nil || !strings.Contains(err.Error(), "workspaces.work.weight") {
+		t.Fatalf("expected a negative weight to be rejected, got %v", err)
+	}
+}
+
+func TestResolveConfig_Schedules(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	yaml := `schedules:
+  - name: nightly-sync
+    task: connector_sync
+    cron: "15 3 * * *"
+    jitter: 10m
+    extract: true
+  - name: weekly-digest
+    task: digest
+    cron: "@weekly"
+    project: trading
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	resolved, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath})
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	if len(resolved.Schedules) != 2 {
+		t.Fatalf("expected 2 schedules, got %+v", resolved.Schedules)
+	}
+	sync := resolved.Schedules[0]
+	if sync.Task != "connector_sync" || !sync.Extract || sync.JitterDuration() != 10*time.Minute {
+		t.Fatalf("unexpected sync schedule: %+v", sync)
+	}
+	if resolved.Schedules[1].Project != "trading" || resolved.Schedules[1].JitterDuration() != 0 {
+		t.Fatalf("unexpected digest schedule: %+v", resolved.Schedules[1])
+	}
+
+	for _, tc := range []struct{ from, to, want string }{
+		{"task: digest", "task: backup", "schedules.weekly-digest.task"},
+		{`cron: "15 3 * * *"`, `cron: "61 3 * * *"`, "schedules.nightly-sync.cron"},
+		{`cron: "@weekly"`, `cron: "@every 10s"`, "at least"},
+		{"jitter: 10m", "jitter: soon", "schedules.nightly-sync.jitter"},
+		{"name: weekly-digest", "name: nightly-sync", "used twice"},
+	} {
+		bad := strings.Replace(yaml, tc.from, tc.to, 1)
+		if err := os.WriteFile(cfgPath, []byte(bad), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		if _, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath}); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s -> %s: expected error containing %q, got %v", tc.from, tc.to, tc.want, err)
+		}
+	}
+}
+
+func TestResolveConfig_Storage(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	yaml := `storage:
+  budget: 1.5GB
+  tiering:
+    compact_after_days: 60
+    compact_classes: [scratch, status]
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	resolved, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath})
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	st := resolved.Storage
+	if st.BudgetBytes() != 3<<29 || st.WarnRatio() != 0.8 {
+		t.Fatalf("unexpected budget: %d bytes, warn at %g", st.BudgetBytes(), st.WarnRatio())
+	}
+	if st.Tiering.CompactAge() != 60*24*time.Hour || len(st.Tiering.Classes()) != 2 || st.Tiering.NoQuantize {
+		t.Fatalf("unexpected tiering: %+v", st.Tiering)
+	}
+
+	var none StorageConfig
+	if none.BudgetBytes() != 0 || none.Tiering.CompactAge() != 30*24*time.Hour || none.Tiering.Classes()[0] != "scratch" {
+		t.Fatalf("unexpected defaults: %+v", none)
+	}
+
+	for _, tc := range []struct{ from, to, want string }{
+		{"budget: 1.5GB", "budget: lots", "storage.budget"},
+		{"budget: 1.5GB", "budget: 1GB\n  warn_at: 80", "storage.warn_at"},
+		{"compact_after_days: 60", "compact_after_days: -1", "compact_after_days"},
+	} {
+		bad := strings.Replace(yaml, tc.from, tc.to, 1)
+		if err := os.WriteFile(cfgPath, []byte(bad), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		if _, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath}); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s -> %s: expected error containing %q, got %v", tc.from, tc.to, tc.want, err)
+		}
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	for raw, want := range map[string]int64{"2GB": 2 << 30, "512k": 512 << 10, "1.5MiB": 3 << 19, "100": 100} {
+		if got, err := ParseByteSize(raw); err != nil || got != want {
+			t.Errorf("ParseByteSize(%q) = %d, %v; want %d", raw, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "GB", "-1MB", "ten"} {
+		if _, err := ParseByteSize(bad); err == nil {
+			t.Errorf("ParseByteSize(%q) should fail", bad)
+		}
+	}
+}
+
+func TestResolveConfig_SearchProfiles(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	yaml := `search:
+  profiles:
+    trading-ops:
+      project: trading
+      classes: [rule, decision]
+      source: github
+      source_boosts:
+        - prefix: "memory/trading"
+          weight: 1.5
+    personal:
+      intent: memory
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	resolved, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath})
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	profile, err := resolved.Search.Profiles.Lookup("Trading-Ops")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if profile.Project != "trading" || len(profile.Classes) != 2 || profile.Source != "github" || len(profile.SourceBoosts) != 1 {
+		t.Fatalf("unexpected profile: %+v", profile)
+	}
+	if _, err := resolved.Search.Profiles.Lookup("missing"); err == nil || !strings.Contains(err.Error(), "available: personal, trading-ops") {
+		t.Fatalf("expected unknown profile error listing names, got %v", err)
+	}
+}
+
+func TestResolveConfig_ProviderFallbacks(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	yaml := `llm:
+  provider: openrouter/openai/gpt-4o-mini
+  fallback:
+    - google/gemini-2.5-flash
+    - " "
+embed:
+  provider: ollama/all-minilm
+  fallback: [onnx/all-minilm-l6-v2]
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	resolved, err := ResolveConfig(ResolveOptions{ConfigPath: cfgPath})
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	if len(resolved.LLMFallback) != 1 || resolved.LLMFallback[0] != "google/gemini-2.5-flash" {
+		t.Fatalf("unexpected llm fallback: %v", resolved.LLMFallback)
+	}
+	if len(resolved.EmbedFallback) != 1 || resolved.EmbedFallback[0] != "onnx/all-minilm-l6-v2" {
+		t.Fatalf("unexpected embed fallback: %v", resolved.EmbedFallback)
+	}
+
+	t.Setenv("CORTEX_LLM_FALLBACK", "google/gemini-2.5-flash-lite, openrouter/anthropic/claude-haiku-4.5")
+	resolved, err = ResolveConfig(ResolveOptions{ConfigPath: cfgPath})
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	if got := strings.Join(resolved.LLMFallback, "|"); got != "google/gemini-2.5-flash-lite|openrouter/anthropic/claude-haiku-4.5" {
+		t.Fatalf("expected env to override llm fallback, got %q", got)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"sort"
 	"sync"
 	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
 )
 
 // Provider defines the interface that all connectors must implement.
@@ -39,6 +41,30 @@ type Provider interface {
 	Fetch(ctx context.Context, cfg json.RawMessage, since *time.Time) ([]Record, error)
 }
 
+// CursorProvider is implemented by providers that track their own sync
+// position (e.g. IMAP UIDs) rather than relying on the last sync time alone.
+// The sync engine passes the saved cursor to FetchCursor and stores the
+// returned one only when every fetched record was processed.
+type CursorProvider interface {
+	Provider
+
+	// FetchCursor is Fetch with the connector's saved cursor (nil on the
+	// first sync). It returns the records and the cursor to save.
+	FetchCursor(ctx context.Context, cfg json.RawMessage, since *time.Time, cursor json.RawMessage) ([]Record, json.RawMessage, error)
+}
+
+// SnapshotProvider is implemented by providers whose records describe
+// current state (presence, device states) rather than history. Each newly
+// imported record replaces earlier memories from the same source, and the
+// provider's memories older than SnapshotTTL are deleted whenever the
+// connector syncs.
+type SnapshotProvider interface {
+	Provider
+
+	// SnapshotTTL is how long a snapshot stays in memory; 0 disables expiry.
+	SnapshotTTL(cfg json.RawMessage) time.Duration
+}
+
 // Record represents a single piece of data fetched from an external provider.
 // Records are converted to Cortex memories during sync.
 type Record struct {
@@ -68,6 +94,25 @@ type Record struct {
 
 	// AgentID tags the imported memory with an agent identity.
 	AgentID string
+
+	// Facts are structured facts the provider reads straight from source
+	// metadata (an issue's status, assignee, blockers). They are stored with
+	// each new import of the record whether or not extraction is enabled.
+	Facts []RecordFact
+
+	// FactPredicates lists predicates that Facts describes completely for
+	// this source. Active facts from earlier imports of the same source that
+	// use one of them are superseded by the new value, or retired when the
+	// new import no longer asserts them (a resolved blocker).
+	FactPredicates []string
+}
+
+// RecordFact is a provider-supplied subject/predicate/object triple.
+type RecordFact struct {
+	Subject   string
+	Predicate string
+	Object    string
+	FactType  string
 }
 
 // Connector represents a configured and registered connector instance.
@@ -81,19 +126,39 @@ type Connector struct {
 	RecordsImported int64           `json:"records_imported"`
 	CreatedAt       time.Time       `json:"created_at"`
 	UpdatedAt       time.Time       `json:"updated_at"`
+	// PausedUntil is set when the connector exceeded its ingestion quota;
+	// syncs are skipped until then or until `cortex connect resume`.
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+	PauseReason string     `json:"pause_reason,omitempty"`
+	// Cursor is the saved sync position of a CursorProvider.
+	Cursor json.RawMessage `json:"cursor,omitempty"`
+}
+
+// Paused reports whether syncs of c are currently suspended.
+func (c *Connector) Paused(now time.Time) bool {
+	return c.PausedUntil != nil && now.Before(*c.PausedUntil)
 }
 
 // SyncResult holds the outcome of a connector sync operation.
 type SyncResult struct {
-	Provider        string        `json:"provider"`
-	RecordsFetched  int           `json:"records_fetched"`
-	RecordsImported int           `json:"records_imported"`
-	RecordsSkipped  int           `json:"records_skipped"`
-	FactsExtracted  int           `json:"facts_extracted,omitempty"`
-	EdgesInferred   int           `json:"edges_inferred,omitempty"`
-	Duration        time.Duration `json:"duration"`
-	Error           string        `json:"error,omitempty"`
-	SyncedAt        time.Time     `json:"synced_at"`
+	Provider        string `json:"provider"`
+	RecordsFetched  int    `json:"records_fetched"`
+	RecordsImported int    `json:"records_imported"`
+	RecordsSkipped  int    `json:"records_skipped"`
+	FactsExtracted  int    `json:"facts_extracted,omitempty"`
+	EdgesInferred   int    `json:"edges_inferred,omitempty"`
+	// RecordsExpired counts snapshot memories removed because they expired
+	// or were replaced by a newer snapshot (see SnapshotProvider).
+	RecordsExpired int           `json:"records_expired,omitempty"`
+	Duration       time.Duration `json:"duration"`
+	Error          string        `json:"error,omitempty"`
+	SyncedAt       time.Time     `json:"synced_at"`
+	// PausedUntil is set when the connector is paused, either before this
+	// sync or because this sync hit its ingestion quota.
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+	// SkipSpike is set when this sync skipped far more of what it fetched
+	// than the connector's recent syncs did.
+	SkipSpike *SkipSpike `json:"skip_spike,omitempty"`
 }
 
 // Registry holds all registered providers. Thread-safe.
@@ -119,6 +184,11 @@ func (r *Registry) Register(p Provider) {
 		panic(fmt.Sprintf("connect: duplicate provider registration: %s", name))
 	}
 	r.providers[name] = p
+	if r == DefaultRegistry {
+		// Built-in providers are connector sources ("name:...") for search
+		// filters, intent buckets, and `cortex sources`.
+		store.RegisterSource(store.Source{Key: name, Type: store.SourceTypeConnector, DisplayName: p.DisplayName()})
+	}
 }
 
 // Get returns a provider by name, or nil if not found.
@@ -0,0 +1,229 @@
+package connect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Sync statuses recorded in connector_syncs.
+const (
+	SyncStatusOK     = "ok"
+	SyncStatusError  = "error"
+	SyncStatusPaused = "paused" // skipped or stopped early by an ingestion quota
+)
+
+// syncHistoryRetention is how many history rows are kept per provider.
+const syncHistoryRetention = 1000
+
+// Skip-ratio spike thresholds. A sync that skips most of what it fetched is
+// normal for a connector that re-fetches overlapping windows; a sudden jump
+// from its usual ratio is not, and usually means broken dedup, a provider
+// re-sending old records, or imports failing.
+const (
+	skipSpikeMinFetched = 10  // ignore syncs too small to judge
+	skipSpikeMinBase    = 3   // prior syncs needed for a baseline
+	skipSpikeBaseWindow = 10  // prior syncs averaged into the baseline
+	skipSpikeMinRatio   = 0.5 // the latest sync must skip at least this much
+	skipSpikeMinJump    = 0.3 // ... and this much more than the baseline
+)
+
+// SyncHistoryEntry is one row of connector_syncs.
+type SyncHistoryEntry struct {
+	ID              int64         `json:"id"`
+	Provider        string        `json:"provider"`
+	StartedAt       time.Time     `json:"started_at"`
+	Duration        time.Duration `json:"duration"`
+	Status          string        `json:"status"`
+	RecordsFetched  int           `json:"records_fetched"`
+	RecordsImported int           `json:"records_imported"`
+	RecordsSkipped  int           `json:"records_skipped"`
+	RecordsExpired  int           `json:"records_expired,omitempty"`
+	FactsExtracted  int           `json:"facts_extracted,omitempty"`
+	Error           string        `json:"error,omitempty"`
+}
+
+// SkipRatio is the share of fetched records that were not imported.
+func (e SyncHistoryEntry) SkipRatio() float64 {
+	if e.RecordsFetched <= 0 {
+		return 0
+	}
+	return float64(e.RecordsSkipped) / float64(e.RecordsFetched)
+}
+
+// SkipSpike describes a sync whose skip ratio jumped above its usual level.
+type SkipSpike struct {
+	Provider string    `json:"provider"`
+	At       time.Time `json:"at"`
+	Ratio    float64   `json:"ratio"`
+	Baseline float64   `json:"baseline"`
+	Fetched  int       `json:"fetched"`
+	Skipped  int       `json:"skipped"`
+}
+
+// String renders the spike as a one-line alert.
+func (s SkipSpike) String() string {
+	return fmt.Sprintf("connector %s skipped %.0f%% of %d records (usually %.0f%%)",
+		s.Provider, s.Ratio*100, s.Fetched, s.Baseline*100)
+}
+
+// syncStatus classifies a finished sync for the history table.
+func syncStatus(r SyncResult) string {
+	switch {
+	case r.PausedUntil != nil:
+		return SyncStatusPaused
+	case r.Error != "":
+		return SyncStatusError
+	default:
+		return SyncStatusOK
+	}
+}
+
+// RecordSyncHistory appends r to connector_syncs and trims the provider's
+// history to the newest syncHistoryRetention rows.
+func (cs *ConnectorStore) RecordSyncHistory(ctx context.Context, r SyncResult) error {
+	startedAt := r.SyncedAt
+	if startedAt.IsZero() {
+		startedAt = time.Now()
+	}
+	if _, err := cs.db.ExecContext(ctx,
+		`INSERT INTO connector_syncs
+		   (provider, started_at, duration_ms, status, records_fetched, records_imported,
+		    records_skipped, records_expired, facts_extracted, error)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.Provider, startedAt.UTC(), r.Duration.Milliseconds(), syncStatus(r),
+		r.RecordsFetched, r.RecordsImported, r.RecordsSkipped, r.RecordsExpired, r.FactsExtracted, r.Error,
+	); err != nil {
+		return fmt.Errorf("recording sync history: %w", err)
+	}
+	if _, err := cs.db.ExecContext(ctx,
+		`DELETE FROM connector_syncs
+		 WHERE provider = ? AND id NOT IN (
+		   SELECT id FROM connector_syncs WHERE provider = ? ORDER BY started_at DESC, id DESC LIMIT ?)`,
+		r.Provider, r.Provider, syncHistoryRetention,
+	); err != nil {
+		return fmt.Errorf("trimming sync history: %w", err)
+	}
+	return nil
+}
+
+// History returns a provider's most recent syncs, newest first. limit <= 0
+// returns everything kept.
+func (cs *ConnectorStore) History(ctx context.Context, provider string, limit int) ([]SyncHistoryEntry, error) {
+	if limit <= 0 {
+		limit = syncHistoryRetention
+	}
+	rows, err := cs.db.QueryContext(ctx,
+		`SELECT id, provider, started_at, duration_ms, status, records_fetched, records_imported,
+		        records_skipped, records_expired, facts_extracted, error
+		 FROM connector_syncs WHERE provider = ?
+		 ORDER BY started_at DESC, id DESC LIMIT ?`,
+		provider, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("loading sync history: %w", err)
+	}
+	defer rows.Close()
+
+	var out []SyncHistoryEntry
+	for rows.Next() {
+		var e SyncHistoryEntry
+		var startedAt sql.NullTime
+		var durationMS int64
+		if err := rows.Scan(&e.ID, &e.Provider, &startedAt, &durationMS, &e.Status,
+			&e.RecordsFetched, &e.RecordsImported, &e.RecordsSkipped, &e.RecordsExpired,
+			&e.FactsExtracted, &e.Error); err != nil {
+			return nil, fmt.Errorf("scanning sync history: %w", err)
+		}
+		if startedAt.Valid {
+			e.StartedAt = startedAt.Time.UTC()
+		}
+		e.Duration = time.Duration(durationMS) * time.Millisecond
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// DeleteHistory removes a provider's sync history.
+func (cs *ConnectorStore) DeleteHistory(ctx context.Context, provider string) error {
+	if _, err := cs.db.ExecContext(ctx, `DELETE FROM connector_syncs WHERE provider = ?`, provider); err != nil {
+		return fmt.Errorf("deleting sync history: %w", err)
+	}
+	return nil
+}
+
+// DetectSkipSpike checks the newest entry of history (newest first, as
+// returned by History) against the average skip ratio of the successful
+// syncs before it. It returns nil when there is no spike or too little
+// history to tell.
+func DetectSkipSpike(history []SyncHistoryEntry) *SkipSpike {
+	if len(history) == 0 {
+		return nil
+	}
+	latest := history[0]
+	if latest.Status != SyncStatusOK || latest.RecordsFetched < skipSpikeMinFetched {
+		return nil
+	}
+
+	var sum float64
+	n := 0
+	for _, e := range history[1:] {
+		if n == skipSpikeBaseWindow {
+			break
+		}
+		if e.Status != SyncStatusOK || e.RecordsFetched == 0 {
+			continue
+		}
+		sum += e.SkipRatio()
+		n++
+	}
+	if n < skipSpikeMinBase {
+		return nil
+	}
+	baseline := sum / float64(n)
+	ratio := latest.SkipRatio()
+	if ratio < skipSpikeMinRatio || ratio-baseline < skipSpikeMinJump {
+		return nil
+	}
+	return &SkipSpike{
+		Provider: latest.Provider,
+		At:       latest.StartedAt,
+		Ratio:    ratio,
+		Baseline: baseline,
+		Fetched:  latest.RecordsFetched,
+		Skipped:  latest.RecordsSkipped,
+	}
+}
+
+// SyncTrend summarizes a connector's recent syncs for cortex stats.
+type SyncTrend struct {
+	Provider string `json:"provider"`
+	Syncs    int    `json:"syncs"`
+	Errors   int    `json:"errors"`
+	// Imported and SkipRatios hold one value per sync, oldest first.
+	Imported   []int      `json:"imported"`
+	SkipRatios []float64  `json:"skip_ratios"`
+	Spike      *SkipSpike `json:"skip_spike,omitempty"`
+}
+
+// TrendFromHistory builds a SyncTrend from history (newest first, as
+// returned by History).
+func TrendFromHistory(provider string, history []SyncHistoryEntry) SyncTrend {
+	t := SyncTrend{
+		Provider:   provider,
+		Syncs:      len(history),
+		Imported:   make([]int, len(history)),
+		SkipRatios: make([]float64, len(history)),
+		Spike:      DetectSkipSpike(history),
+	}
+	for i, e := range history {
+		j := len(history) - 1 - i
+		t.Imported[j] = e.RecordsImported
+		t.SkipRatios[j] = e.SkipRatio()
+		if e.Status == SyncStatusError {
+			t.Errors++
+		}
+	}
+	return t
+}
@@ -0,0 +1,107 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSyncOne_RecordsHistory(t *testing.T) {
+	mock := &mockProvider{
+		name: "test",
+		records: []Record{
+			{ExternalID: "r1", Content: "Alice is the CEO of Acme Corp", Source: "doc1.md"},
+			{ExternalID: "r2", Content: "Bob joined the team in 2024", Source: "doc2.md"},
+		},
+	}
+	engine, cs, _ := newTestSyncEngine(t, mock)
+	ctx := context.Background()
+	if _, err := cs.Add(ctx, "test", json.RawMessage(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		conn, _ := cs.Get(ctx, "test")
+		engine.SyncOne(ctx, conn)
+	}
+
+	history, err := cs.History(ctx, "test", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected one row per sync, got %+v", history)
+	}
+	// Newest first: the second sync only re-fetched what was imported.
+	if history[0].RecordsSkipped != 2 || history[1].RecordsImported != 2 || history[0].Status != SyncStatusOK {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+	if history[0].SkipRatio() != 1 {
+		t.Fatalf("expected a skip ratio of 1, got %v", history[0].SkipRatio())
+	}
+
+	if err := cs.Remove(ctx, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if history, _ := cs.History(ctx, "test", 0); len(history) != 0 {
+		t.Fatalf("removing a connector should drop its history, got %+v", history)
+	}
+}
+
+func TestRecordSyncHistory_Statuses(t *testing.T) {
+	cs := NewConnectorStore(newTestDB(t))
+	ctx := context.Background()
+	now := time.Now()
+	paused := now.Add(time.Hour)
+
+	for i, r := range []SyncResult{
+		{Provider: "gmail", SyncedAt: now.Add(-2 * time.Minute), RecordsFetched: 5, RecordsImported: 5, Duration: 1500 * time.Millisecond},
+		{Provider: "gmail", SyncedAt: now.Add(-time.Minute), Error: "fetch failed: 401"},
+		{Provider: "gmail", SyncedAt: now, RecordsFetched: 9, RecordsImported: 3, PausedUntil: &paused, Error: "quota"},
+	} {
+		if err := cs.RecordSyncHistory(ctx, r); err != nil {
+			t.Fatalf("RecordSyncHistory %d: %v", i, err)
+		}
+	}
+	history, err := cs.History(ctx, "gmail", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{history[0].Status, history[1].Status, history[2].Status}
+	if fmt.Sprint(got) != "[paused error ok]" {
+		t.Fatalf("unexpected statuses %v", got)
+	}
+	if history[2].Duration != 1500*time.Millisecond || history[1].Error != "fetch failed: 401" {
+		t.Fatalf("unexpected rows: %+v", history)
+	}
+}
+
+func TestDetectSkipSpike(t *testing.T) {
+	entry := func(fetched, skipped int) SyncHistoryEntry {
+		return SyncHistoryEntry{Provider: "slack", Status: SyncStatusOK, RecordsFetched: fetched, RecordsSkipped: skipped}
+	}
+	base := []SyncHistoryEntry{entry(20, 2), entry(30, 3), entry(10, 0)}
+
+	spike := DetectSkipSpike(append([]SyncHistoryEntry{entry(40, 36)}, base...))
+	if spike == nil || spike.Ratio != 0.9 || spike.Fetched != 40 {
+		t.Fatalf("expected a spike, got %+v", spike)
+	}
+	if spike.String() != "connector slack skipped 90% of 40 records (usually 7%)" {
+		t.Fatalf("unexpected alert: %s", spike)
+	}
+
+	cases := map[string][]SyncHistoryEntry{
+		"normal ratio":     append([]SyncHistoryEntry{entry(40, 4)}, base...),
+		"too few fetched":  append([]SyncHistoryEntry{entry(5, 5)}, base...),
+		"too little base":  {entry(40, 36), entry(20, 2)},
+		"usually skipping": {entry(40, 36), entry(20, 18), entry(30, 27), entry(10, 9)},
+		"empty":            nil,
+	}
+	for name, history := range cases {
+		if spike := DetectSkipSpike(history); spike != nil {
+			t.Fatalf("%s: unexpected spike %+v", name, spike)
+		}
+	}
+}
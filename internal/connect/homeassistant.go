@@ -0,0 +1,194 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// homeAssistantPresenceDomains are summarized together as "presence".
+var homeAssistantPresenceDomains = map[string]bool{"person": true, "device_tracker": true}
+
+// HomeAssistantProvider snapshots selected Home Assistant entity states
+// (presence, device states) as short-lived status memories.
+type HomeAssistantProvider struct{}
+
+// HomeAssistantConfig holds configuration for the Home Assistant connector.
+type HomeAssistantConfig struct {
+	// URL is the Home Assistant base URL, e.g. "http://homeassistant.local:8123".
+	URL string `json:"url"`
+
+	// Token is a long-lived access token. TokenEnv names an environment
+	// variable to read it from instead.
+	Token    string `json:"token,omitempty"`
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// Entities lists entity IDs or glob patterns ("person.*",
+	// "binary_sensor.*door*"). Default: ["person.*"].
+	Entities []string `json:"entities,omitempty"`
+
+	// TTLMinutes is how long a snapshot is kept. Default: 60; -1 keeps them.
+	TTLMinutes int `json:"ttl_minutes,omitempty"`
+
+	// Project is the Cortex project tag for imported memories.
+	Project string `json:"project,omitempty"`
+}
+
+func (c *HomeAssistantConfig) token() string {
+	if c.TokenEnv != "" {
+		return os.Getenv(c.TokenEnv)
+	}
+	return c.Token
+}
+
+func (c *HomeAssistantConfig) entities() []string {
+	if len(c.Entities) == 0 {
+		return []string{"person.*"}
+	}
+	return c.Entities
+}
+
+func (c *HomeAssistantConfig) matches(entityID string) bool {
+	for _, pattern := range c.entities() {
+		if ok, _ := path.Match(pattern, entityID); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	DefaultRegistry.Register(&HomeAssistantProvider{})
+}
+
+func (h *HomeAssistantProvider) Name() string        { return "homeassistant" }
+func (h *HomeAssistantProvider) DisplayName() string { return "Home Assistant" }
+
+func (h *HomeAssistantProvider) DefaultConfig() json.RawMessage {
+	return json.RawMessage(`{
+  "url": "http://homeassistant.local:8123",
+  "token_env": "HASS_TOKEN",
+  "entities": ["person.*", "binary_sensor.*door*", "light.*"],
+  "ttl_minutes": 60,
+  "project": ""
+}`)
+}
+
+func (h *HomeAssistantProvider) ValidateConfig(config json.RawMessage) error {
+	var cfg HomeAssistantConfig
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return fmt.Errorf("invalid config JSON: %w", err)
+	}
+	u, err := url.Parse(strings.TrimSpace(cfg.URL))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("url is required (e.g. http://homeassistant.local:8123)")
+	}
+	if cfg.Token == "" && cfg.TokenEnv == "" {
+		return fmt.Errorf("token or token_env is required (long-lived access token)")
+	}
+	if cfg.TokenEnv != "" && os.Getenv(cfg.TokenEnv) == "" {
+		return fmt.Errorf("environment variable %s (token_env) is not set", cfg.TokenEnv)
+	}
+	for _, pattern := range cfg.Entities {
+		if _, err := path.Match(pattern, ""); err != nil || strings.TrimSpace(pattern) == "" {
+			return fmt.Errorf("invalid entity pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// SnapshotTTL implements SnapshotProvider.
+func (h *HomeAssistantProvider) SnapshotTTL(config json.RawMessage) time.Duration {
+	var cfg HomeAssistantConfig
+	_ = json.Unmarshal(config, &cfg)
+	return snapshotTTL(cfg.TTLMinutes)
+}
+
+// Fetch reads the current state of matching entities. since is ignored:
+// every sync takes a fresh snapshot.
+func (h *HomeAssistantProvider) Fetch(ctx context.Context, config json.RawMessage, since *time.Time) ([]Record, error) {
+	if err := h.ValidateConfig(config); err != nil {
+		return nil, err
+	}
+	var cfg HomeAssistantConfig
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+
+	endpoint := strings.TrimRight(strings.TrimSpace(cfg.URL), "/") + "/api/states"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+cfg.token())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("Home Assistant API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var states []homeAssistantState
+	if err := json.NewDecoder(resp.Body).Decode(&states); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	groups := map[string]*snapshotGroup{}
+	for _, st := range states {
+		if !cfg.matches(st.EntityID) {
+			continue
+		}
+		domain, _, _ := strings.Cut(st.EntityID, ".")
+		key, title := domain, strings.ReplaceAll(domain, "_", " ")+" states"
+		if homeAssistantPresenceDomains[domain] {
+			key, title = "presence", "presence"
+		}
+		g := groups[key]
+		if g == nil {
+			g = &snapshotGroup{Key: key, Title: title}
+			groups[key] = g
+		}
+		g.Lines = append(g.Lines, st.summary())
+	}
+
+	list := make([]snapshotGroup, 0, len(groups))
+	for _, g := range groups {
+		list = append(list, *g)
+	}
+	return snapshotRecords("Home Assistant", list, cfg.Project, time.Now()), nil
+}
+
+type homeAssistantState struct {
+	EntityID    string                 `json:"entity_id"`
+	State       string                 `json:"state"`
+	LastChanged time.Time              `json:"last_changed"`
+	Attributes  map[string]interface{} `json:"attributes"`
+}
+
+// summary renders "Kitchen (light.kitchen): on since 2026-10-16 08:05".
+func (s homeAssistantState) summary() string {
+	name := s.EntityID
+	if friendly, ok := s.Attributes["friendly_name"].(string); ok && friendly != "" {
+		name = fmt.Sprintf("%s (%s)", friendly, s.EntityID)
+	}
+	value := s.State
+	if unit, ok := s.Attributes["unit_of_measurement"].(string); ok && unit != "" {
+		value += " " + unit
+	}
+	line := fmt.Sprintf("%s: %s", name, value)
+	if !s.LastChanged.IsZero() {
+		line += " since " + s.LastChanged.Local().Format("2006-01-02 15:04")
+	}
+	return line
+}
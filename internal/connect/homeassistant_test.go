@@ -0,0 +1,163 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func newFakeHomeAssistant(t *testing.T, states *string, mu *sync.Mutex) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer hass-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/states" {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprint(w, *states)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func hassStates(aliceState string) string {
+	return `[
+	{"entity_id":"person.alice","state":"` + aliceState + `","last_changed":"2026-10-16T08:05:00+00:00","attributes":{"friendly_name":"Alice"}},
+	{"entity_id":"device_tracker.bob_phone","state":"not_home","attributes":{"friendly_name":"Bob's phone"}},
+	{"entity_id":"sensor.living_room_temperature","state":"21.5","attributes":{"friendly_name":"Living room","unit_of_measurement":"°C"}},
+	{"entity_id":"light.kitchen","state":"on","attributes":{"friendly_name":"Kitchen"}},
+	{"entity_id":"switch.heater","state":"off","attributes":{}}
+]`
+}
+
+func hassConfig(url string) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"url":%q,"token":"hass-token","entities":["person.*","device_tracker.*","sensor.*temperature"],"project":"home"}`, url))
+}
+
+func TestHomeAssistantFetch(t *testing.T) {
+	var mu sync.Mutex
+	states := hassStates("home")
+	srv := newFakeHomeAssistant(t, &states, &mu)
+
+	records, err := (&HomeAssistantProvider{}).Fetch(context.Background(), hassConfig(srv.URL), nil)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected presence + sensor snapshots, got %d: %+v", len(records), records)
+	}
+	presence, sensors := records[0], records[1]
+	if presence.Source != "snapshot/presence" || presence.MemoryClass != "status" || presence.Project != "home" {
+		t.Errorf("unexpected presence record: %+v", presence)
+	}
+	for _, want := range []string{
+		"Current presence (Home Assistant snapshot)",
+		"- Alice (person.alice): home since ",
+		"- Bob's phone (device_tracker.bob_phone): not_home\n",
+	} {
+		if !strings.Contains(presence.Content, want) {
+			t.Errorf("presence missing %q:\n%s", want, presence.Content)
+		}
+	}
+	if sensors.Source != "snapshot/sensor" || !strings.Contains(sensors.Content, "Living room (sensor.living_room_temperature): 21.5 °C") {
+		t.Errorf("unexpected sensor record: %+v", sensors)
+	}
+	for _, r := range records {
+		if strings.Contains(r.Content, "light.kitchen") || strings.Contains(r.Content, "switch.heater") {
+			t.Errorf("unselected entity leaked into %s", r.Source)
+		}
+	}
+}
+
+func TestSnapshotSyncReplacesAndExpires(t *testing.T) {
+	var mu sync.Mutex
+	states := hassStates("home")
+	srv := newFakeHomeAssistant(t, &states, &mu)
+
+	engine, cs, st := newTestSyncEngine(t, &HomeAssistantProvider{})
+	ctx := context.Background()
+	if _, err := cs.Add(ctx, "homeassistant", hassConfig(srv.URL)); err != nil {
+		t.Fatalf("adding connector: %v", err)
+	}
+	runSync := func() SyncResult {
+		t.Helper()
+		conn, _ := cs.Get(ctx, "homeassistant")
+		res := engine.SyncOne(ctx, conn)
+		if res.Error != "" {
+			t.Fatalf("sync: %s", res.Error)
+		}
+		return res
+	}
+	presence := func() []*store.Memory {
+		t.Helper()
+		mems, err := st.ListMemories(ctx, store.ListOpts{SourceFile: "homeassistant:snapshot/presence", Limit: 10})
+		if err != nil {
+			t.Fatalf("ListMemories: %v", err)
+		}
+		return mems
+	}
+
+	if res := runSync(); res.RecordsImported != 2 {
+		t.Fatalf("first sync imported %d, want 2", res.RecordsImported)
+	}
+	// Unchanged state deduplicates.
+	if res := runSync(); res.RecordsImported != 0 || res.RecordsExpired != 0 {
+		t.Fatalf("unchanged sync: %+v", res)
+	}
+
+	// Alice leaves: the new presence snapshot replaces the old one.
+	mu.Lock()
+	states = hassStates("not_home")
+	mu.Unlock()
+	if res := runSync(); res.RecordsImported != 1 || res.RecordsExpired != 1 {
+		t.Fatalf("changed sync: %+v", res)
+	}
+	mems := presence()
+	if len(mems) != 1 || !strings.Contains(mems[0].Content, "Alice (person.alice): not_home") {
+		t.Fatalf("expected only the newest presence snapshot, got %+v", mems)
+	}
+
+	// Past the TTL everything expires and the current state is re-imported.
+	sqlStore := st.(*store.SQLiteStore)
+	if _, err := sqlStore.GetDB().ExecContext(ctx, `UPDATE memories SET imported_at = ?`, time.Now().Add(-2*time.Hour).UTC()); err != nil {
+		t.Fatalf("aging memories: %v", err)
+	}
+	if res := runSync(); res.RecordsExpired != 2 || res.RecordsImported != 2 {
+		t.Fatalf("post-TTL sync: %+v", res)
+	}
+	if got := len(presence()); got != 1 {
+		t.Errorf("expected 1 fresh presence snapshot, got %d", got)
+	}
+}
+
+func TestHomeAssistantValidateConfig(t *testing.T) {
+	p := &HomeAssistantProvider{}
+	for _, cfg := range []string{
+		`{"token":"x"}`,
+		`{"url":"http://ha.local:8123"}`,
+		`{"url":"http://ha.local:8123","token":"x","entities":["[bad"]}`,
+	} {
+		if err := p.ValidateConfig(json.RawMessage(cfg)); err == nil {
+			t.Errorf("expected error for %s", cfg)
+		}
+	}
+	if got := p.SnapshotTTL(json.RawMessage(`{"ttl_minutes":-1}`)); got != 0 {
+		t.Errorf("ttl_minutes -1 should disable expiry, got %s", got)
+	}
+	if got := p.SnapshotTTL(json.RawMessage(`{}`)); got != time.Hour {
+		t.Errorf("default TTL = %s, want 1h", got)
+	}
+}
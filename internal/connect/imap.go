@@ -0,0 +1,593 @@
+package connect
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	imapDefaultLookbackDays = 30
+	imapDefaultMaxMessages  = 200
+	imapMaxMessagesCap      = 5000
+	imapFetchBatch          = 50
+	imapMaxBodyChars        = 4000
+)
+
+// IMAPProvider imports email threads from any IMAP mailbox.
+type IMAPProvider struct{}
+
+// IMAPConfig holds configuration for the IMAP connector.
+type IMAPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username"`
+	// Password is the account (or app) password. PasswordEnv names an
+	// environment variable to read it from instead, keeping it out of the DB.
+	Password    string `json:"password,omitempty"`
+	PasswordEnv string `json:"password_env,omitempty"`
+	// TLS uses implicit TLS (port 993). Default: true. Plain connections are
+	// only for local bridges such as Proton Mail Bridge.
+	TLS *bool `json:"tls,omitempty"`
+	// Folders lists the mailboxes to sync. Default: ["INBOX"].
+	Folders []string `json:"folders,omitempty"`
+	// LookbackDays bounds the first sync of a folder. Default: 30.
+	LookbackDays int `json:"lookback_days,omitempty"`
+	// MaxMessages caps messages fetched per folder per sync. Default: 200.
+	MaxMessages int    `json:"max_messages,omitempty"`
+	Project     string `json:"project,omitempty"`
+}
+
+func (c *IMAPConfig) useTLS() bool { return c.TLS == nil || *c.TLS }
+
+func (c *IMAPConfig) port() int {
+	switch {
+	case c.Port > 0:
+		return c.Port
+	case c.useTLS():
+		return 993
+	default:
+		return 143
+	}
+}
+
+func (c *IMAPConfig) password() string {
+	if c.PasswordEnv != "" {
+		return os.Getenv(c.PasswordEnv)
+	}
+	return c.Password
+}
+
+func (c *IMAPConfig) folders() []string {
+	if len(c.Folders) == 0 {
+		return []string{"INBOX"}
+	}
+	return c.Folders
+}
+
+func (c *IMAPConfig) lookbackDays() int {
+	if c.LookbackDays <= 0 {
+		return imapDefaultLookbackDays
+	}
+	return c.LookbackDays
+}
+
+func (c *IMAPConfig) maxMessages() int {
+	if c.MaxMessages <= 0 {
+		return imapDefaultMaxMessages
+	}
+	if c.MaxMessages > imapMaxMessagesCap {
+		return imapMaxMessagesCap
+	}
+	return c.MaxMessages
+}
+
+// imapCursor is the IMAP connector's sync position: per folder, the
+// UIDVALIDITY the UIDs belong to and the highest UID imported.
+type imapCursor struct {
+	Folders map[string]imapFolderCursor `json:"folders"`
+}
+
+type imapFolderCursor struct {
+	UIDValidity uint32 `json:"uidvalidity"`
+	LastUID     uint32 `json:"last_uid"`
+}
+
+func init() {
+	DefaultRegistry.Register(&IMAPProvider{})
+}
+
+func (p *IMAPProvider) Name() string        { return "imap" }
+func (p *IMAPProvider) DisplayName() string { return "IMAP Email" }
+
+func (p *IMAPProvider) DefaultConfig() json.RawMessage {
+	return json.RawMessage(`{
+  "host": "imap.example.com",
+  "port": 993,
+  "username": "you@example.com",
+  "password_env": "CORTEX_IMAP_PASSWORD",
+  "folders": ["INBOX"],
+  "lookback_days": 30,
+  "max_messages": 200,
+  "project": ""
+}`)
+}
+
+func (p *IMAPProvider) ValidateConfig(config json.RawMessage) error {
+	var cfg IMAPConfig
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return fmt.Errorf("invalid config JSON: %w", err)
+	}
+	if strings.TrimSpace(cfg.Host) == "" {
+		return fmt.Errorf("host is required")
+	}
+	if strings.TrimSpace(cfg.Username) == "" {
+		return fmt.Errorf("username is required")
+	}
+	if cfg.Password == "" && cfg.PasswordEnv == "" {
+		return fmt.Errorf("password or password_env is required")
+	}
+	if cfg.PasswordEnv != "" && os.Getenv(cfg.PasswordEnv) == "" {
+		return fmt.Errorf("environment variable %s (password_env) is not set", cfg.PasswordEnv)
+	}
+	for _, f := range cfg.Folders {
+		if strings.TrimSpace(f) == "" {
+			return fmt.Errorf("folders cannot contain empty values")
+		}
+	}
+	return nil
+}
+
+// Fetch imports threads without a saved cursor (each folder's lookback window).
+func (p *IMAPProvider) Fetch(ctx context.Context, config json.RawMessage, since *time.Time) ([]Record, error) {
+	records, _, err := p.FetchCursor(ctx, config, since, nil)
+	return records, err
+}
+
+// FetchCursor imports messages above each folder's saved UID. When a folder's
+// UIDVALIDITY changed (the mailbox was rebuilt, so old UIDs mean nothing) it
+// falls back to a date search; content hashes keep re-imports from
+// duplicating memories.
+func (p *IMAPProvider) FetchCursor(ctx context.Context, config json.RawMessage, since *time.Time, cursor json.RawMessage) ([]Record, json.RawMessage, error) {
+	if err := p.ValidateConfig(config); err != nil {
+		return nil, nil, err
+	}
+	var cfg IMAPConfig
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return nil, nil, fmt.Errorf("parsing config: %w", err)
+	}
+	state := imapCursor{}
+	if len(cursor) > 0 {
+		if err := json.Unmarshal(cursor, &state); err != nil {
+			return nil, nil, fmt.Errorf("parsing sync cursor: %w", err)
+		}
+	}
+	if state.Folders == nil {
+		state.Folders = map[string]imapFolderCursor{}
+	}
+
+	client, err := dialIMAP(ctx, cfg.Host, cfg.port(), cfg.useTLS())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer client.Close()
+	if err := client.login(cfg.Username, cfg.password()); err != nil {
+		return nil, nil, err
+	}
+
+	var records []Record
+	for _, folder := range cfg.folders() {
+		msgs, next, err := fetchIMAPFolder(client, folder, state.Folders[folder], since, &cfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("folder %s: %w", folder, err)
+		}
+		state.Folders[folder] = next
+		records = append(records, imapThreadRecords(msgs, folder, cfg.Project)...)
+	}
+
+	next, err := json.Marshal(state)
+	if err != nil {
+		return nil, nil, err
+	}
+	return records, next, nil
+}
+
+// fetchIMAPFolder returns the folder's new messages and its advanced cursor.
+func fetchIMAPFolder(client *imapClient, folder string, cur imapFolderCursor, since *time.Time, cfg *IMAPConfig) ([]imapMessage, imapFolderCursor, error) {
+	validity, err := client.examine(folder)
+	if err != nil {
+		return nil, cur, err
+	}
+
+	var uids []uint32
+	if cur.UIDValidity == validity && cur.LastUID > 0 {
+		uids, err = client.searchUIDs(fmt.Sprintf("UID %d:*", cur.LastUID+1))
+		// "n:*" always matches the highest UID, even when it is below n.
+		kept := uids[:0]
+		for _, u := range uids {
+			if u > cur.LastUID {
+				kept = append(kept, u)
+			}
+		}
+		uids = kept
+	} else {
+		from := time.Now().AddDate(0, 0, -cfg.lookbackDays())
+		if since != nil && cur.UIDValidity == 0 && since.After(from) {
+			from = *since
+		}
+		cur = imapFolderCursor{UIDValidity: validity}
+		uids, err = client.searchUIDs("SINCE " + from.Format("02-Jan-2006"))
+	}
+	if err != nil {
+		return nil, cur, err
+	}
+	if len(uids) > cfg.maxMessages() {
+		// Oldest first, so the cursor moves forward and the rest follow on
+		// the next sync.
+		uids = uids[:cfg.maxMessages()]
+	}
+
+	var msgs []imapMessage
+	for start := 0; start < len(uids); start += imapFetchBatch {
+		batch := uids[start:min(start+imapFetchBatch, len(uids))]
+		raw, err := client.fetchMessages(batch)
+		if err != nil {
+			return nil, cur, err
+		}
+		for _, uid := range batch {
+			if data, ok := raw[uid]; ok {
+				if msg, err := parseIMAPMessage(data); err == nil {
+					msg.UID = uid
+					msgs = append(msgs, msg)
+				}
+			}
+			cur.LastUID = max(cur.LastUID, uid)
+		}
+	}
+	return msgs, cur, nil
+}
+
+// imapMessage is a parsed email reduced to what a memory needs.
+type imapMessage struct {
+	UID         uint32
+	MessageID   string
+	ThreadKey   string
+	Subject     string
+	From        string
+	To          []string
+	Cc          []string
+	Date        time.Time
+	Body        string
+	Attachments []string
+}
+
+var (
+	imapSubjectPrefix = regexp.MustCompile(`(?i)^\s*((re|fwd?|aw|sv)\s*(\[\d+\])?\s*:\s*)+`)
+	imapMessageID     = regexp.MustCompile(`<[^<>\s]+>`)
+)
+
+func parseIMAPMessage(raw []byte) (imapMessage, error) {
+	m, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return imapMessage{}, err
+	}
+	dec := &mime.WordDecoder{CharsetReader: imapCharsetReader}
+	header := func(key string) string {
+		v := m.Header.Get(key)
+		if d, err := dec.DecodeHeader(v); err == nil {
+			return strings.TrimSpace(d)
+		}
+		return strings.TrimSpace(v)
+	}
+	addrs := func(key string) []string {
+		parser := &mail.AddressParser{WordDecoder: dec}
+		list, err := parser.ParseList(m.Header.Get(key))
+		if err != nil {
+			if v := header(key); v != "" {
+				return []string{v}
+			}
+			return nil
+		}
+		out := make([]string, 0, len(list))
+		for _, a := range list {
+			out = append(out, formatIMAPAddress(a))
+		}
+		return out
+	}
+
+	msg := imapMessage{
+		MessageID: imapMessageID.FindString(m.Header.Get("Message-Id")),
+		Subject:   header("Subject"),
+		To:        addrs("To"),
+		Cc:        addrs("Cc"),
+	}
+	if from := addrs("From"); len(from) > 0 {
+		msg.From = from[0]
+	}
+	if d, err := m.Header.Date(); err == nil {
+		msg.Date = d
+	}
+
+	// A thread is keyed by its root message: the first References entry,
+	// else In-Reply-To, else the message itself; the normalized subject is
+	// the last resort for clients that send none of these.
+	switch {
+	case imapMessageID.MatchString(m.Header.Get("References")):
+		msg.ThreadKey = imapMessageID.FindString(m.Header.Get("References"))
+	case imapMessageID.MatchString(m.Header.Get("In-Reply-To")):
+		msg.ThreadKey = imapMessageID.FindString(m.Header.Get("In-Reply-To"))
+	case msg.MessageID != "" && !imapSubjectPrefix.MatchString(msg.Subject):
+		msg.ThreadKey = msg.MessageID
+	default:
+		msg.ThreadKey = "subject:" + strings.ToLower(strings.TrimSpace(imapSubjectPrefix.ReplaceAllString(msg.Subject, "")))
+	}
+
+	plain, htmlBody, attachments := extractIMAPBody(m.Header, m.Body)
+	msg.Attachments = attachments
+	body := plain
+	if strings.TrimSpace(body) == "" {
+		body = htmlToText(htmlBody)
+	}
+	msg.Body = cleanEmailBody(body)
+	return msg, nil
+}
+
+// extractIMAPBody walks a MIME entity and returns its text/plain and
+// text/html bodies plus the names of the attachments it skipped.
+func extractIMAPBody(header map[string][]string, body io.Reader) (plain, htmlBody string, attachments []string) {
+	get := func(key string) string {
+		if v := header[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	mediaType, params, err := mime.ParseMediaType(get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	disposition, dparams, _ := mime.ParseMediaType(get("Content-Disposition"))
+	name := dparams["filename"]
+	if name == "" {
+		name = params["name"]
+	}
+	if disposition == "attachment" || (name != "" && !strings.HasPrefix(mediaType, "multipart/")) {
+		return "", "", []string{valueOrDefault(name, mediaType)}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err != nil {
+				break
+			}
+			p, h, a := extractIMAPBody(part.Header, part)
+			if plain == "" {
+				plain = p
+			}
+			if htmlBody == "" {
+				htmlBody = h
+			}
+			attachments = append(attachments, a...)
+		}
+		return plain, htmlBody, attachments
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", "", []string{mediaType}
+	}
+	var r io.Reader = body
+	switch strings.ToLower(strings.TrimSpace(get("Content-Transfer-Encoding"))) {
+	case "quoted-printable":
+		r = quotedprintable.NewReader(r)
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, &newlineStripper{r: r})
+	}
+	if cr, err := imapCharsetReader(params["charset"], r); err == nil {
+		r = cr
+	}
+	data, _ := io.ReadAll(io.LimitReader(r, 1<<20))
+	if mediaType == "text/html" {
+		return "", string(data), nil
+	}
+	return string(data), "", nil
+}
+
+// newlineStripper drops CR and LF so base64 bodies wrapped at 76 columns
+// decode as one stream.
+type newlineStripper struct{ r io.Reader }
+
+func (n *newlineStripper) Read(p []byte) (int, error) {
+	for {
+		k, err := n.r.Read(p)
+		j := 0
+		for _, b := range p[:k] {
+			if b != '\r' && b != '\n' {
+				p[j] = b
+				j++
+			}
+		}
+		if j > 0 || err != nil {
+			return j, err
+		}
+	}
+}
+
+// imapCharsetReader decodes the charsets the standard library lacks but
+// mail commonly uses; other charsets pass through unchanged.
+func imapCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "latin1", "windows-1252", "cp1252":
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return strings.NewReader(string(runes)), nil
+	default:
+		return input, nil
+	}
+}
+
+var (
+	htmlDropBlocks = regexp.MustCompile(`(?is)<(script|style|head)\b.*?</(script|style|head)>`)
+	htmlBreaks     = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|tr|h[1-6]|blockquote)>`)
+	htmlListItem   = regexp.MustCompile(`(?i)<li\b[^>]*>`)
+	htmlTags       = regexp.MustCompile(`<[^>]*>`)
+	blankLines     = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText reduces an HTML email body to readable plain text.
+func htmlToText(s string) string {
+	s = htmlDropBlocks.ReplaceAllString(s, "")
+	s = htmlBreaks.ReplaceAllString(s, "\n")
+	s = htmlListItem.ReplaceAllString(s, "- ")
+	s = htmlTags.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.Join(strings.Fields(l), " ")
+	}
+	return blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+}
+
+var emailQuoteHeader = regexp.MustCompile(`(?i)^on .+ wrote:\s*$`)
+
+// cleanEmailBody drops quoted replies (earlier messages in the thread are
+// stored on their own) and trims to imapMaxBodyChars.
+func cleanEmailBody(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	var kept []string
+	for _, line := range strings.Split(s, "\n") {
+		t := strings.TrimSpace(line)
+		if strings.HasPrefix(t, ">") || emailQuoteHeader.MatchString(t) {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(line, " \t"))
+	}
+	s = strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(kept, "\n"), "\n\n"))
+	if len(s) > imapMaxBodyChars {
+		s = strings.TrimSpace(truncateUTF8(s, imapMaxBodyChars)) + " …"
+	}
+	return s
+}
+
+func truncateUTF8(s string, n int) string {
+	for n > 0 && n < len(s) && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n]
+}
+
+func formatIMAPAddress(a *mail.Address) string {
+	if a.Name == "" {
+		return a.Address
+	}
+	return fmt.Sprintf("%s <%s>", a.Name, a.Address)
+}
+
+func valueOrDefault(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// imapThreadRecords groups messages into one record per thread, listing its
+// participants and the messages in date order.
+func imapThreadRecords(msgs []imapMessage, folder, project string) []Record {
+	threads := map[string][]imapMessage{}
+	var order []string
+	for _, m := range msgs {
+		if _, ok := threads[m.ThreadKey]; !ok {
+			order = append(order, m.ThreadKey)
+		}
+		threads[m.ThreadKey] = append(threads[m.ThreadKey], m)
+	}
+
+	records := make([]Record, 0, len(order))
+	for _, key := range order {
+		ms := threads[key]
+		sort.SliceStable(ms, func(i, j int) bool { return ms[i].Date.Before(ms[j].Date) })
+
+		seen := map[string]bool{}
+		var participants []string
+		for _, m := range ms {
+			for _, p := range append(append([]string{m.From}, m.To...), m.Cc...) {
+				k := strings.ToLower(p)
+				if p != "" && !seen[k] {
+					seen[k] = true
+					participants = append(participants, p)
+				}
+			}
+		}
+
+		subject := valueOrDefault(imapSubjectPrefix.ReplaceAllString(ms[0].Subject, ""), "(no subject)")
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Email thread: %s\n", subject)
+		fmt.Fprintf(&sb, "Folder: %s\n", folder)
+		fmt.Fprintf(&sb, "Participants: %s\n", strings.Join(participants, ", "))
+		if len(ms) > 1 {
+			fmt.Fprintf(&sb, "Messages: %d\n", len(ms))
+		}
+		var uids []uint32
+		var ids []string
+		for _, m := range ms {
+			uids = append(uids, m.UID)
+			if m.MessageID != "" {
+				ids = append(ids, m.MessageID)
+			}
+			fmt.Fprintf(&sb, "\n--- %s", m.From)
+			if !m.Date.IsZero() {
+				fmt.Fprintf(&sb, " · %s", m.Date.UTC().Format("2006-01-02 15:04"))
+			}
+			sb.WriteString("\n")
+			if m.Body != "" {
+				sb.WriteString(m.Body + "\n")
+			}
+			if len(m.Attachments) > 0 {
+				fmt.Fprintf(&sb, "[attachments skipped: %s]\n", strings.Join(m.Attachments, ", "))
+			}
+		}
+
+		meta, _ := json.Marshal(map[string]any{
+			"folder":       folder,
+			"participants": participants,
+			"message_ids":  ids,
+			"uids":         uids,
+		})
+		sum := sha256.Sum256([]byte(key))
+		threadID := hex.EncodeToString(sum[:6])
+		records = append(records, Record{
+			Content:      strings.TrimRight(sb.String(), "\n"),
+			Source:       "thread/" + threadID,
+			Section:      subject,
+			Project:      project,
+			Timestamp:    ms[len(ms)-1].Date,
+			ExternalID:   fmt.Sprintf("imap:%s/%d", folder, uids[len(uids)-1]),
+			ProviderMeta: meta,
+		})
+	}
+	return records
+}
@@ -0,0 +1,214 @@
+package connect
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// imapCommandTimeout bounds each IMAP command round trip.
+const imapCommandTimeout = 2 * time.Minute
+
+var (
+	imapLiteralPattern     = regexp.MustCompile(`\{(\d+)\+?\}$`)
+	imapUIDValidityPattern = regexp.MustCompile(`\[UIDVALIDITY (\d+)\]`)
+	imapFetchUIDPattern    = regexp.MustCompile(`\bUID (\d+)\b`)
+)
+
+// imapClient is the small IMAP4rev1 subset the IMAP connector needs:
+// LOGIN, EXAMINE, UID SEARCH, UID FETCH, and LOGOUT. Mailboxes are opened
+// read-only, so syncing never changes \Seen flags.
+type imapClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+	stop func() bool
+}
+
+// imapResponse is one untagged server response. Literals ({N} strings) are
+// cut out of text and returned in order.
+type imapResponse struct {
+	text     string
+	literals [][]byte
+}
+
+func dialIMAP(ctx context.Context, host string, port int, useTLS bool) (*imapClient, error) {
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	var err error
+	if useTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+
+	c := &imapClient{conn: conn, r: bufio.NewReader(conn)}
+	// Unblock pending reads when the sync is cancelled.
+	c.stop = context.AfterFunc(ctx, func() { conn.Close() })
+
+	conn.SetDeadline(time.Now().Add(imapCommandTimeout))
+	greeting, _, err := c.readLine()
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("reading greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		c.Close()
+		return nil, fmt.Errorf("unexpected greeting: %s", greeting)
+	}
+	return c, nil
+}
+
+// Close ends the session; a failed LOGOUT is not worth reporting.
+func (c *imapClient) Close() error {
+	c.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	c.command("LOGOUT")
+	c.stop()
+	return c.conn.Close()
+}
+
+func (c *imapClient) login(user, password string) error {
+	_, err := c.command("LOGIN " + imapQuote(user) + " " + imapQuote(password))
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+	return nil
+}
+
+// examine opens mailbox read-only and returns its UIDVALIDITY.
+func (c *imapClient) examine(mailbox string) (uint32, error) {
+	resps, err := c.command("EXAMINE " + imapQuote(mailbox))
+	if err != nil {
+		return 0, fmt.Errorf("opening %s: %w", mailbox, err)
+	}
+	for _, r := range resps {
+		if m := imapUIDValidityPattern.FindStringSubmatch(r.text); m != nil {
+			v, err := strconv.ParseUint(m[1], 10, 32)
+			if err != nil {
+				return 0, fmt.Errorf("invalid UIDVALIDITY %q", m[1])
+			}
+			return uint32(v), nil
+		}
+	}
+	return 0, fmt.Errorf("server did not report UIDVALIDITY for %s", mailbox)
+}
+
+// searchUIDs runs UID SEARCH with criteria and returns the UIDs ascending.
+func (c *imapClient) searchUIDs(criteria string) ([]uint32, error) {
+	resps, err := c.command("UID SEARCH " + criteria)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	var uids []uint32
+	for _, r := range resps {
+		rest, ok := strings.CutPrefix(r.text, "* SEARCH")
+		if !ok {
+			continue
+		}
+		for _, f := range strings.Fields(rest) {
+			if v, err := strconv.ParseUint(f, 10, 32); err == nil {
+				uids = append(uids, uint32(v))
+			}
+		}
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	return uids, nil
+}
+
+// fetchMessages downloads the raw RFC 5322 source of each UID.
+func (c *imapClient) fetchMessages(uids []uint32) (map[uint32][]byte, error) {
+	set := make([]string, len(uids))
+	for i, u := range uids {
+		set[i] = strconv.FormatUint(uint64(u), 10)
+	}
+	resps, err := c.command("UID FETCH " + strings.Join(set, ",") + " (UID BODY.PEEK[])")
+	if err != nil {
+		return nil, fmt.Errorf("fetch failed: %w", err)
+	}
+	out := make(map[uint32][]byte, len(uids))
+	for _, r := range resps {
+		if !strings.Contains(r.text, " FETCH ") || len(r.literals) == 0 {
+			continue
+		}
+		m := imapFetchUIDPattern.FindStringSubmatch(r.text)
+		if m == nil {
+			continue
+		}
+		uid, _ := strconv.ParseUint(m[1], 10, 32)
+		out[uint32(uid)] = r.literals[len(r.literals)-1]
+	}
+	return out, nil
+}
+
+// command sends one tagged command and collects untagged responses until
+// its tagged completion. A NO or BAD completion is returned as an error.
+func (c *imapClient) command(cmd string) ([]imapResponse, error) {
+	c.tag++
+	tag := fmt.Sprintf("c%d", c.tag)
+	c.conn.SetDeadline(time.Now().Add(imapCommandTimeout))
+	if _, err := io.WriteString(c.conn, tag+" "+cmd+"\r\n"); err != nil {
+		return nil, err
+	}
+
+	var resps []imapResponse
+	for {
+		text, literals, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if rest, ok := strings.CutPrefix(text, tag+" "); ok {
+			if strings.HasPrefix(rest, "OK") {
+				return resps, nil
+			}
+			return nil, fmt.Errorf("%s", rest)
+		}
+		resps = append(resps, imapResponse{text: text, literals: literals})
+	}
+}
+
+// readLine reads one response line, pulling in any literals it announces.
+func (c *imapClient) readLine() (string, [][]byte, error) {
+	var text strings.Builder
+	var literals [][]byte
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return "", nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		m := imapLiteralPattern.FindStringSubmatchIndex(line)
+		if m == nil {
+			text.WriteString(line)
+			return text.String(), literals, nil
+		}
+		n, err := strconv.Atoi(line[m[2]:m[3]])
+		if err != nil || n < 0 {
+			return "", nil, fmt.Errorf("invalid literal length in %q", line)
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return "", nil, err
+		}
+		text.WriteString(line[:m[0]])
+		literals = append(literals, buf)
+	}
+}
+
+// imapQuote renders s as an IMAP quoted string.
+func imapQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
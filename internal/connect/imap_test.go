@@ -0,0 +1,273 @@
+package connect
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeIMAPServer speaks just enough IMAP for the connector: LOGIN, EXAMINE,
+// UID SEARCH (UID n:* and SINCE), UID FETCH, and LOGOUT.
+type fakeIMAPServer struct {
+	mu          sync.Mutex
+	ln          net.Listener
+	uidValidity uint32
+	messages    map[uint32]string
+}
+
+func newFakeIMAPServer(t *testing.T) *fakeIMAPServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeIMAPServer{ln: ln, uidValidity: 1, messages: map[uint32]string{}}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeIMAPServer) port() int { return s.ln.Addr().(*net.TCPAddr).Port }
+
+func (s *fakeIMAPServer) add(uid uint32, raw string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages[uid] = strings.ReplaceAll(raw, "\n", "\r\n")
+}
+
+var fakeUIDRange = regexp.MustCompile(`^UID (\d+):\*$`)
+
+func (s *fakeIMAPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "* OK fake IMAP ready\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		tag, cmd, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		s.mu.Lock()
+		switch {
+		case strings.HasPrefix(cmd, "LOGIN "):
+			if cmd != `LOGIN "me@example.com" "hunter2"` {
+				fmt.Fprintf(conn, "%s NO [AUTHENTICATIONFAILED] bad credentials\r\n", tag)
+			} else {
+				fmt.Fprintf(conn, "%s OK logged in\r\n", tag)
+			}
+		case strings.HasPrefix(cmd, "EXAMINE "):
+			fmt.Fprintf(conn, "* %d EXISTS\r\n* OK [UIDVALIDITY %d] UIDs valid\r\n%s OK [READ-ONLY] done\r\n", len(s.messages), s.uidValidity, tag)
+		case strings.HasPrefix(cmd, "UID SEARCH "):
+			criteria := strings.TrimPrefix(cmd, "UID SEARCH ")
+			var uids []string
+			var highest uint32
+			for uid := range s.messages {
+				highest = max(highest, uid)
+			}
+			for uid := range s.messages {
+				if m := fakeUIDRange.FindStringSubmatch(criteria); m != nil {
+					from, _ := strconv.Atoi(m[1])
+					// Real servers return the highest UID for "n:*" even when n is beyond it.
+					if int(uid) < from && uid != highest {
+						continue
+					}
+				}
+				uids = append(uids, strconv.Itoa(int(uid)))
+			}
+			fmt.Fprintf(conn, "* SEARCH %s\r\n%s OK search done\r\n", strings.Join(uids, " "), tag)
+		case strings.HasPrefix(cmd, "UID FETCH "):
+			set := strings.Fields(strings.TrimPrefix(cmd, "UID FETCH "))[0]
+			for i, f := range strings.Split(set, ",") {
+				uid, _ := strconv.Atoi(f)
+				raw, ok := s.messages[uint32(uid)]
+				if !ok {
+					continue
+				}
+				fmt.Fprintf(conn, "* %d FETCH (UID %d BODY[] {%d}\r\n%s)\r\n", i+1, uid, len(raw), raw)
+			}
+			fmt.Fprintf(conn, "%s OK fetch done\r\n", tag)
+		case cmd == "LOGOUT":
+			fmt.Fprintf(conn, "* BYE\r\n%s OK bye\r\n", tag)
+			s.mu.Unlock()
+			return
+		default:
+			fmt.Fprintf(conn, "%s BAD unknown command\r\n", tag)
+		}
+		s.mu.Unlock()
+	}
+}
+
+func imapTestConfig(port int) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"host":"127.0.0.1","port":%d,"tls":false,"username":"me@example.com","password":"hunter2","project":"mail"}`, port))
+}
+
+func imapTestDate(daysAgo int) string {
+	return time.Now().AddDate(0, 0, -daysAgo).Format(time.RFC1123Z)
+}
+
+func TestIMAPFetchCursorThreadsAndIncremental(t *testing.T) {
+	srv := newFakeIMAPServer(t)
+	srv.add(10, `From: Alice <alice@example.com>
+To: Bob <bob@example.com>
+Subject: Launch plan
+Message-ID: <root@example.com>
+Date: `+imapTestDate(2)+`
+Content-Type: text/plain; charset=utf-8
+
+We launch on the 14th.
+`)
+	srv.add(11, `From: Bob <bob@example.com>
+To: Alice <alice@example.com>
+Cc: Carol <carol@example.com>
+Subject: Re: Launch plan
+Message-ID: <reply@example.com>
+In-Reply-To: <root@example.com>
+References: <root@example.com>
+Date: `+imapTestDate(1)+`
+Content-Type: multipart/mixed; boundary="XYZ"
+
+--XYZ
+Content-Type: text/html; charset=utf-8
+Content-Transfer-Encoding: quoted-printable
+
+<p>Works for me.</p><p>I&#39;ll tell <b>Carol</b>.</p>
+--XYZ
+Content-Type: application/pdf; name="plan.pdf"
+Content-Disposition: attachment; filename="plan.pdf"
+Content-Transfer-Encoding: base64
+
+JVBERi0xLjQK
+--XYZ--
+`)
+
+	p := &IMAPProvider{}
+	ctx := context.Background()
+	records, cursor, err := p.FetchCursor(ctx, imapTestConfig(srv.port()), nil, nil)
+	if err != nil {
+		t.Fatalf("FetchCursor: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 thread record, got %d: %+v", len(records), records)
+	}
+	rec := records[0]
+	for _, want := range []string{
+		"Email thread: Launch plan",
+		"Participants: Alice <alice@example.com>, Bob <bob@example.com>, Carol <carol@example.com>",
+		"Messages: 2",
+		"We launch on the 14th.",
+		"Works for me.\nI'll tell Carol.",
+		"[attachments skipped: plan.pdf]",
+	} {
+		if !strings.Contains(rec.Content, want) {
+			t.Errorf("record missing %q:\n%s", want, rec.Content)
+		}
+	}
+	if strings.Contains(rec.Content, "JVBERi") {
+		t.Error("attachment content leaked into the record")
+	}
+	if rec.Project != "mail" || !strings.HasPrefix(rec.Source, "thread/") {
+		t.Errorf("unexpected record metadata: %+v", rec)
+	}
+
+	var state imapCursor
+	if err := json.Unmarshal(cursor, &state); err != nil {
+		t.Fatalf("cursor: %v", err)
+	}
+	if got := state.Folders["INBOX"]; got.UIDValidity != 1 || got.LastUID != 11 {
+		t.Fatalf("unexpected cursor: %+v", got)
+	}
+
+	// Nothing new: the "n:*" match of the highest UID is filtered out.
+	records, cursor, err = p.FetchCursor(ctx, imapTestConfig(srv.port()), nil, cursor)
+	if err != nil || len(records) != 0 {
+		t.Fatalf("expected no new records, got %d (%v)", len(records), err)
+	}
+
+	srv.add(12, `From: Carol <carol@example.com>
+To: Alice <alice@example.com>
+Subject: Budget
+Message-ID: <budget@example.com>
+Date: `+imapTestDate(0)+`
+
+Budget approved.
+`)
+	records, cursor, err = p.FetchCursor(ctx, imapTestConfig(srv.port()), nil, cursor)
+	if err != nil || len(records) != 1 || !strings.Contains(records[0].Content, "Budget approved.") {
+		t.Fatalf("incremental sync: %+v, %v", records, err)
+	}
+
+	// A new UIDVALIDITY invalidates the saved UIDs: fall back to a date search.
+	srv.mu.Lock()
+	srv.uidValidity = 2
+	srv.mu.Unlock()
+	records, cursor, err = p.FetchCursor(ctx, imapTestConfig(srv.port()), nil, cursor)
+	if err != nil || len(records) != 2 {
+		t.Fatalf("after UIDVALIDITY change: %d records, %v", len(records), err)
+	}
+	json.Unmarshal(cursor, &state)
+	if got := state.Folders["INBOX"]; got.UIDValidity != 2 || got.LastUID != 12 {
+		t.Errorf("cursor not reset to new UIDVALIDITY: %+v", got)
+	}
+}
+
+func TestIMAPLoginFailure(t *testing.T) {
+	srv := newFakeIMAPServer(t)
+	cfg := json.RawMessage(fmt.Sprintf(`{"host":"127.0.0.1","port":%d,"tls":false,"username":"me@example.com","password":"wrong"}`, srv.port()))
+	_, err := (&IMAPProvider{}).Fetch(context.Background(), cfg, nil)
+	if err == nil || !strings.Contains(err.Error(), "login failed") {
+		t.Fatalf("expected login failure, got %v", err)
+	}
+}
+
+func TestIMAPValidateConfig(t *testing.T) {
+	p := &IMAPProvider{}
+	t.Setenv("CORTEX_TEST_IMAP_PW", "")
+	for _, cfg := range []string{
+		`{"username":"me","password":"x"}`,
+		`{"host":"imap.example.com","password":"x"}`,
+		`{"host":"imap.example.com","username":"me"}`,
+		`{"host":"imap.example.com","username":"me","password_env":"CORTEX_TEST_IMAP_PW"}`,
+	} {
+		if err := p.ValidateConfig(json.RawMessage(cfg)); err == nil {
+			t.Errorf("expected error for %s", cfg)
+		}
+	}
+	if err := p.ValidateConfig(json.RawMessage(`{"host":"imap.example.com","username":"me","password":"x"}`)); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+}
+
+func TestSyncOneSavesCursor(t *testing.T) {
+	srv := newFakeIMAPServer(t)
+	srv.add(5, "From: a@example.com\nSubject: Hi\nMessage-ID: <hi@example.com>\nDate: "+imapTestDate(0)+"\n\nHello there.\n")
+
+	engine, cs, _ := newTestSyncEngine(t, &IMAPProvider{})
+	ctx := context.Background()
+	if _, err := cs.Add(ctx, "imap", imapTestConfig(srv.port())); err != nil {
+		t.Fatalf("adding connector: %v", err)
+	}
+	conn, _ := cs.Get(ctx, "imap")
+	if res := engine.SyncOne(ctx, conn); res.Error != "" || res.RecordsImported != 1 {
+		t.Fatalf("sync: %+v", res)
+	}
+	conn, _ = cs.Get(ctx, "imap")
+	if !strings.Contains(string(conn.Cursor), `"last_uid":5`) {
+		t.Errorf("cursor not saved: %s", conn.Cursor)
+	}
+}
@@ -0,0 +1,421 @@
+package connect
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	jiraDefaultMaxIssues = 500
+	jiraMaxIssuesCap     = 5000
+	jiraPageSize         = 100
+	// jiraSinceOverlap widens incremental windows: JQL evaluates dates in the
+	// account's time zone, and content hashes absorb the re-fetched issues.
+	jiraSinceOverlap = 24 * time.Hour
+	jiraTimeLayout   = "2006-01-02T15:04:05.000-0700"
+)
+
+// jiraSearchFields are the issue fields requested from the search API.
+var jiraSearchFields = "summary,description,status,assignee,priority,resolution,issuetype,labels,updated,issuelinks,comment"
+
+// JiraProvider imports issues and comments from Jira Cloud or Data Center.
+type JiraProvider struct{}
+
+// JiraConfig holds the configuration for the Jira connector.
+type JiraConfig struct {
+	// BaseURL is the Jira site, e.g. "https://acme.atlassian.net".
+	BaseURL string `json:"base_url"`
+
+	// Email and APIToken authenticate against Jira Cloud. Without an email
+	// the token is sent as a bearer token (Data Center personal access token).
+	Email       string `json:"email,omitempty"`
+	APIToken    string `json:"api_token,omitempty"`
+	APITokenEnv string `json:"api_token_env,omitempty"`
+
+	// Projects lists the Jira project keys to sync.
+	Projects []string `json:"projects,omitempty"`
+
+	// JQL is an extra filter ANDed with the project and update-time filters.
+	JQL string `json:"jql,omitempty"`
+
+	// IncludeComments controls whether issue comments are imported (default: true).
+	IncludeComments *bool `json:"include_comments,omitempty"`
+
+	// MaxIssues caps issues fetched per sync. Default: 500.
+	MaxIssues int `json:"max_issues,omitempty"`
+
+	// Project is the Cortex project tag for imported memories.
+	Project string `json:"project,omitempty"`
+}
+
+func (c *JiraConfig) includeComments() bool { return c.IncludeComments == nil || *c.IncludeComments }
+
+func (c *JiraConfig) token() string {
+	if c.APITokenEnv != "" {
+		return os.Getenv(c.APITokenEnv)
+	}
+	return c.APIToken
+}
+
+func (c *JiraConfig) maxIssues() int {
+	if c.MaxIssues <= 0 {
+		return jiraDefaultMaxIssues
+	}
+	if c.MaxIssues > jiraMaxIssuesCap {
+		return jiraMaxIssuesCap
+	}
+	return c.MaxIssues
+}
+
+// jql builds the search query: configured projects and filter, issues
+// updated since the last sync, oldest first.
+func (c *JiraConfig) jql(since *time.Time) string {
+	var clauses []string
+	if len(c.Projects) > 0 {
+		keys := make([]string, len(c.Projects))
+		for i, p := range c.Projects {
+			keys[i] = jqlQuote(strings.TrimSpace(p))
+		}
+		clauses = append(clauses, fmt.Sprintf("project in (%s)", strings.Join(keys, ", ")))
+	}
+	if strings.TrimSpace(c.JQL) != "" {
+		clauses = append(clauses, "("+strings.TrimSpace(c.JQL)+")")
+	}
+	if since != nil {
+		from := since.Add(-jiraSinceOverlap).UTC().Format("2006-01-02 15:04")
+		clauses = append(clauses, fmt.Sprintf("updated >= %s", jqlQuote(from)))
+	}
+	return strings.Join(clauses, " AND ") + " ORDER BY updated ASC"
+}
+
+func jqlQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func init() {
+	DefaultRegistry.Register(&JiraProvider{})
+}
+
+func (j *JiraProvider) Name() string        { return "jira" }
+func (j *JiraProvider) DisplayName() string { return "Jira" }
+
+func (j *JiraProvider) DefaultConfig() json.RawMessage {
+	return json.RawMessage(`{
+  "base_url": "https://your-site.atlassian.net",
+  "email": "you@example.com",
+  "api_token_env": "JIRA_API_TOKEN",
+  "projects": ["PROJ"],
+  "jql": "",
+  "include_comments": true,
+  "max_issues": 500,
+  "project": ""
+}`)
+}
+
+func (j *JiraProvider) ValidateConfig(config json.RawMessage) error {
+	var cfg JiraConfig
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return fmt.Errorf("invalid config JSON: %w", err)
+	}
+	u, err := url.Parse(strings.TrimSpace(cfg.BaseURL))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("base_url is required (e.g. https://your-site.atlassian.net)")
+	}
+	if cfg.APIToken == "" && cfg.APITokenEnv == "" {
+		return fmt.Errorf("api_token or api_token_env is required")
+	}
+	if cfg.APITokenEnv != "" && os.Getenv(cfg.APITokenEnv) == "" {
+		return fmt.Errorf("environment variable %s (api_token_env) is not set", cfg.APITokenEnv)
+	}
+	if len(cfg.Projects) == 0 && strings.TrimSpace(cfg.JQL) == "" {
+		return fmt.Errorf("at least one project key or a jql filter is required")
+	}
+	for _, p := range cfg.Projects {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("projects cannot contain empty values")
+		}
+	}
+	return nil
+}
+
+func (j *JiraProvider) Fetch(ctx context.Context, config json.RawMessage, since *time.Time) ([]Record, error) {
+	if err := j.ValidateConfig(config); err != nil {
+		return nil, err
+	}
+	var cfg JiraConfig
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+
+	client := &jiraClient{
+		baseURL:    strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/"),
+		email:      cfg.Email,
+		token:      cfg.token(),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	query := url.Values{}
+	query.Set("jql", cfg.jql(since))
+	query.Set("fields", jiraSearchFields)
+	query.Set("maxResults", fmt.Sprint(jiraPageSize))
+
+	var records []Record
+	fetched := 0
+	for fetched < cfg.maxIssues() {
+		var page jiraSearchResponse
+		if err := client.get(ctx, "/rest/api/3/search/jql", query, &page); err != nil {
+			return nil, err
+		}
+		for _, raw := range page.Issues {
+			if fetched >= cfg.maxIssues() {
+				break
+			}
+			fetched++
+			issue := raw.toTrackerIssue(client.baseURL)
+			records = append(records, issue.record("jira", cfg.Project))
+			if !cfg.includeComments() {
+				continue
+			}
+			for _, c := range raw.Fields.Comment.Comments {
+				if since != nil && c.Updated.Before(since.Add(-jiraSinceOverlap)) {
+					continue
+				}
+				comment := trackerComment{ID: c.ID, Author: c.Author.DisplayName, Body: adfText(c.Body), Updated: c.Updated.Time}
+				if comment.Body == "" {
+					continue
+				}
+				records = append(records, issue.commentRecord("jira", cfg.Project, comment))
+			}
+		}
+		if page.IsLast || page.NextPageToken == "" || len(page.Issues) == 0 {
+			break
+		}
+		query.Set("nextPageToken", page.NextPageToken)
+	}
+	return records, nil
+}
+
+// --- Jira API types ---
+
+type jiraSearchResponse struct {
+	Issues        []jiraIssue `json:"issues"`
+	NextPageToken string      `json:"nextPageToken"`
+	IsLast        bool        `json:"isLast"`
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string          `json:"summary"`
+		Description json.RawMessage `json:"description"`
+		Status      jiraStatus      `json:"status"`
+		Assignee    *jiraUser       `json:"assignee"`
+		Priority    *jiraNamed      `json:"priority"`
+		Resolution  *jiraNamed      `json:"resolution"`
+		IssueType   jiraNamed       `json:"issuetype"`
+		Labels      []string        `json:"labels"`
+		Updated     jiraTime        `json:"updated"`
+		IssueLinks  []jiraIssueLink `json:"issuelinks"`
+		Comment     struct {
+			Comments []jiraComment `json:"comments"`
+		} `json:"comment"`
+	} `json:"fields"`
+}
+
+type jiraStatus struct {
+	Name           string `json:"name"`
+	StatusCategory struct {
+		Key string `json:"key"` // new | indeterminate | done
+	} `json:"statusCategory"`
+}
+
+type jiraNamed struct {
+	Name string `json:"name"`
+}
+
+type jiraUser struct {
+	DisplayName string `json:"displayName"`
+}
+
+// jiraIssueLink is one issue link. When InwardIssue is set, this issue
+// relates to it by Type.Inward ("is blocked by").
+type jiraIssueLink struct {
+	Type struct {
+		Name    string `json:"name"`
+		Inward  string `json:"inward"`
+		Outward string `json:"outward"`
+	} `json:"type"`
+	InwardIssue *struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Status jiraStatus `json:"status"`
+		} `json:"fields"`
+	} `json:"inwardIssue"`
+}
+
+type jiraComment struct {
+	ID      string          `json:"id"`
+	Author  jiraUser        `json:"author"`
+	Body    json.RawMessage `json:"body"`
+	Updated jiraTime        `json:"updated"`
+}
+
+// jiraTime parses Jira's "2006-01-02T15:04:05.000-0700" timestamps.
+type jiraTime struct{ time.Time }
+
+func (t *jiraTime) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil || s == "" {
+		return nil
+	}
+	parsed, err := time.Parse(jiraTimeLayout, s)
+	if err != nil {
+		parsed, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("parsing Jira time %q: %w", s, err)
+		}
+	}
+	t.Time = parsed
+	return nil
+}
+
+func (ji jiraIssue) toTrackerIssue(baseURL string) trackerIssue {
+	f := ji.Fields
+	issue := trackerIssue{
+		Key:         ji.Key,
+		Title:       f.Summary,
+		Description: adfText(f.Description),
+		URL:         baseURL + "/browse/" + ji.Key,
+		Type:        f.IssueType.Name,
+		Status:      f.Status.Name,
+		Labels:      f.Labels,
+		Updated:     f.Updated.Time,
+	}
+	if f.Assignee != nil {
+		issue.Assignee = f.Assignee.DisplayName
+	}
+	if f.Priority != nil {
+		issue.Priority = f.Priority.Name
+	}
+	if f.Resolution != nil {
+		issue.Resolution = f.Resolution.Name
+	}
+	for _, link := range f.IssueLinks {
+		if link.InwardIssue == nil || !strings.Contains(strings.ToLower(link.Type.Inward), "blocked by") {
+			continue
+		}
+		if link.InwardIssue.Fields.Status.StatusCategory.Key == "done" {
+			continue // resolved blockers no longer block
+		}
+		issue.BlockedBy = append(issue.BlockedBy, link.InwardIssue.Key)
+	}
+	return issue
+}
+
+// adfText flattens an Atlassian Document Format body to plain text. API v2
+// responses (plain strings) pass through unchanged.
+func adfText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strings.TrimSpace(s)
+	}
+	var doc adfNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+	var sb strings.Builder
+	doc.write(&sb)
+	lines := strings.Split(sb.String(), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+type adfNode struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text"`
+	Content []adfNode `json:"content"`
+	Attrs   struct {
+		Text string `json:"text"`
+	} `json:"attrs"`
+}
+
+func (n adfNode) write(sb *strings.Builder) {
+	switch n.Type {
+	case "text":
+		sb.WriteString(n.Text)
+		return
+	case "hardBreak":
+		sb.WriteString("\n")
+		return
+	case "mention", "emoji", "status":
+		sb.WriteString(n.Attrs.Text)
+		return
+	case "listItem":
+		sb.WriteString("- ")
+	}
+	for _, c := range n.Content {
+		c.write(sb)
+	}
+	switch n.Type {
+	case "paragraph", "heading", "codeBlock", "blockquote", "rule":
+		sb.WriteString("\n\n")
+	case "listItem", "tableRow":
+		sb.WriteString("\n")
+	case "tableCell", "tableHeader":
+		sb.WriteString(" | ")
+	}
+}
+
+// --- HTTP client ---
+
+type jiraClient struct {
+	baseURL    string
+	email      string
+	token      string
+	httpClient *http.Client
+}
+
+func (c *jiraClient) get(ctx context.Context, path string, query url.Values, result interface{}) error {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.email != "" {
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.email+":"+c.token)))
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Jira API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
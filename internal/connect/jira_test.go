@@ -0,0 +1,196 @@
+package connect
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// fakeJira serves /rest/api/3/search/jql with a swappable issue list.
+type fakeJira struct {
+	mu     sync.Mutex
+	issues string
+	jql    []string
+}
+
+func newFakeJira(t *testing.T) (*fakeJira, *httptest.Server) {
+	t.Helper()
+	fj := &fakeJira{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "Basic " + base64.StdEncoding.EncodeToString([]byte("me@example.com:tok"))
+		if r.Header.Get("Authorization") != want {
+			http.Error(w, `{"errorMessages":["unauthorized"]}`, http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/rest/api/3/search/jql" {
+			http.NotFound(w, r)
+			return
+		}
+		fj.mu.Lock()
+		defer fj.mu.Unlock()
+		fj.jql = append(fj.jql, r.URL.Query().Get("jql"))
+		fmt.Fprintf(w, `{"issues":[%s],"isLast":true}`, fj.issues)
+	}))
+	t.Cleanup(srv.Close)
+	return fj, srv
+}
+
+func (fj *fakeJira) set(issues string) {
+	fj.mu.Lock()
+	defer fj.mu.Unlock()
+	fj.issues = issues
+}
+
+func jiraTestIssue(status, category, resolution string, blocked bool) string {
+	links := `[]`
+	if blocked {
+		links = `[{"type":{"name":"Blocks","inward":"is blocked by","outward":"blocks"},
+			"inwardIssue":{"key":"PROJ-2","fields":{"status":{"name":"In Progress","statusCategory":{"key":"indeterminate"}}}}},
+			{"type":{"name":"Blocks","inward":"is blocked by","outward":"blocks"},
+			"inwardIssue":{"key":"PROJ-3","fields":{"status":{"name":"Done","statusCategory":{"key":"done"}}}}}]`
+	}
+	res := `null`
+	if resolution != "" {
+		res = fmt.Sprintf(`{"name":%q}`, resolution)
+	}
+	return fmt.Sprintf(`{"key":"PROJ-1","fields":{
+		"summary":"Checkout times out",
+		"description":{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Payments API is slow."}]},
+			{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"p99 is 9s"}]}]}]}]},
+		"status":{"name":%q,"statusCategory":{"key":%q}},
+		"assignee":{"displayName":"Ana Lima"},
+		"priority":{"name":"High"},
+		"resolution":%s,
+		"issuetype":{"name":"Bug"},
+		"labels":["payments"],
+		"updated":"2026-10-01T09:30:00.000+0000",
+		"issuelinks":%s,
+		"comment":{"comments":[{"id":"100","author":{"displayName":"Bo"},
+			"body":{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Waiting on PROJ-2."}]}]},
+			"updated":"2026-10-01T10:00:00.000+0000"}]}}}`, status, category, res, links)
+}
+
+func jiraTestConfig(baseURL string) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"base_url":%q,"email":"me@example.com","api_token":"tok","projects":["PROJ"],"project":"shop"}`, baseURL))
+}
+
+func TestJiraFetch(t *testing.T) {
+	fj, srv := newFakeJira(t)
+	fj.set(jiraTestIssue("In Progress", "indeterminate", "", true))
+
+	records, err := (&JiraProvider{}).Fetch(context.Background(), jiraTestConfig(srv.URL), nil)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected issue + comment records, got %d", len(records))
+	}
+	issue := records[0]
+	for _, want := range []string{
+		"[PROJ-1] Checkout times out",
+		"Type: Bug | Status: In Progress | Assignee: Ana Lima | Priority: High | Labels: payments",
+		"Blocked by: PROJ-2\n",
+		"Payments API is slow.\n\n- p99 is 9s",
+	} {
+		if !strings.Contains(issue.Content, want) {
+			t.Errorf("issue content missing %q:\n%s", want, issue.Content)
+		}
+	}
+	if issue.Source != "issue/PROJ-1" || issue.Project != "shop" || issue.MemoryClass != "status" {
+		t.Errorf("unexpected issue record: %+v", issue)
+	}
+	if !containsFact(issue.Facts, "PROJ-1", "blocked by", "PROJ-2") || containsFact(issue.Facts, "PROJ-1", "blocked by", "PROJ-3") {
+		t.Errorf("blocker facts wrong (resolved PROJ-3 must be skipped): %+v", issue.Facts)
+	}
+	if c := records[1]; c.Source != "issue/PROJ-1/comment/100" || !strings.Contains(c.Content, "Bo:\nWaiting on PROJ-2.") {
+		t.Errorf("unexpected comment record: %+v", c)
+	}
+	if got := fj.jql[0]; got != `project in ("PROJ") ORDER BY updated ASC` {
+		t.Errorf("jql = %q", got)
+	}
+}
+
+func TestJiraSyncSupersedesStatusFacts(t *testing.T) {
+	fj, srv := newFakeJira(t)
+	fj.set(jiraTestIssue("In Progress", "indeterminate", "", true))
+
+	engine, cs, st := newTestSyncEngine(t, &JiraProvider{})
+	ctx := context.Background()
+	if _, err := cs.Add(ctx, "jira", jiraTestConfig(srv.URL)); err != nil {
+		t.Fatalf("adding connector: %v", err)
+	}
+	conn, _ := cs.Get(ctx, "jira")
+	if res := engine.SyncOne(ctx, conn); res.Error != "" || res.FactsExtracted != 4 {
+		t.Fatalf("first sync: %+v", res)
+	}
+
+	// The issue ships: status changes, the blocker link is gone.
+	fj.set(jiraTestIssue("Done", "done", "Done", false))
+	conn, _ = cs.Get(ctx, "jira")
+	if res := engine.SyncOne(ctx, conn); res.Error != "" || res.FactsExtracted != 2 {
+		t.Fatalf("second sync: %+v", res)
+	}
+	if !strings.Contains(fj.jql[1], `AND updated >= "`) {
+		t.Errorf("second sync should be incremental: %q", fj.jql[1])
+	}
+
+	facts, err := st.ListFacts(ctx, store.ListOpts{SourceFile: "jira:issue/PROJ-1", IncludeSuperseded: true, Limit: 100})
+	if err != nil {
+		t.Fatalf("ListFacts: %v", err)
+	}
+	state := map[string]string{}
+	for _, f := range facts {
+		state[f.Predicate+"="+f.Object] = f.State
+	}
+	want := map[string]string{
+		"status=In Progress":   store.FactStateSuperseded,
+		"status=Done":          store.FactStateActive,
+		"resolution=Done":      store.FactStateActive,
+		"assigned to=Ana Lima": store.FactStateActive,
+		"priority=High":        store.FactStateActive,
+		"blocked by=PROJ-2":    store.FactStateRetired,
+	}
+	for k, v := range want {
+		if state[k] != v {
+			t.Errorf("%s: state %q, want %q (all: %v)", k, state[k], v, state)
+		}
+	}
+	if len(facts) != len(want) {
+		t.Errorf("expected %d facts, got %d: %v", len(want), len(facts), state)
+	}
+}
+
+func TestJiraValidateConfig(t *testing.T) {
+	p := &JiraProvider{}
+	for _, cfg := range []string{
+		`{"api_token":"x","projects":["P"]}`,
+		`{"base_url":"acme.atlassian.net","api_token":"x","projects":["P"]}`,
+		`{"base_url":"https://acme.atlassian.net","projects":["P"]}`,
+		`{"base_url":"https://acme.atlassian.net","api_token":"x"}`,
+		`{"base_url":"https://acme.atlassian.net","api_token":"x","projects":[""]}`,
+	} {
+		if err := p.ValidateConfig(json.RawMessage(cfg)); err == nil {
+			t.Errorf("expected error for %s", cfg)
+		}
+	}
+	if err := p.ValidateConfig(json.RawMessage(`{"base_url":"https://acme.atlassian.net","api_token":"x","jql":"assignee = currentUser()"}`)); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+}
+
+func containsFact(facts []RecordFact, subject, predicate, object string) bool {
+	for _, f := range facts {
+		if f.Subject == subject && f.Predicate == predicate && f.Object == object {
+			return true
+		}
+	}
+	return false
+}
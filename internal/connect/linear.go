@@ -0,0 +1,330 @@
+package connect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	linearDefaultMaxIssues = 500
+	linearMaxIssuesCap     = 5000
+	linearPageSize         = 50
+)
+
+var linearAPIURL = "https://api.linear.app/graphql"
+
+// linearIssuesQuery pages through issues with the fields the connector
+// turns into records and status facts.
+const linearIssuesQuery = `query Issues($filter: IssueFilter, $after: String, $first: Int) {
+  issues(filter: $filter, after: $after, first: $first, orderBy: updatedAt) {
+    nodes {
+      identifier
+      title
+      description
+      url
+      priorityLabel
+      updatedAt
+      state { name type }
+      assignee { name }
+      labels { nodes { name } }
+      inverseRelations { nodes { type issue { identifier state { type } } } }
+      comments { nodes { id body updatedAt user { name } } }
+    }
+    pageInfo { hasNextPage endCursor }
+  }
+}`
+
+// LinearProvider imports issues and comments from a Linear workspace.
+type LinearProvider struct{}
+
+// LinearConfig holds the configuration for the Linear connector.
+type LinearConfig struct {
+	// APIKey is a Linear personal API key; it determines the workspace.
+	// APIKeyEnv names an environment variable to read it from instead.
+	APIKey    string `json:"api_key,omitempty"`
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+
+	// Teams limits the sync to these team keys (e.g. "ENG"). Default: all teams.
+	Teams []string `json:"teams,omitempty"`
+
+	// IncludeComments controls whether issue comments are imported (default: true).
+	IncludeComments *bool `json:"include_comments,omitempty"`
+
+	// MaxIssues caps issues fetched per sync. Default: 500.
+	MaxIssues int `json:"max_issues,omitempty"`
+
+	// Project is the Cortex project tag for imported memories.
+	Project string `json:"project,omitempty"`
+}
+
+func (c *LinearConfig) includeComments() bool { return c.IncludeComments == nil || *c.IncludeComments }
+
+func (c *LinearConfig) apiKey() string {
+	if c.APIKeyEnv != "" {
+		return os.Getenv(c.APIKeyEnv)
+	}
+	return c.APIKey
+}
+
+func (c *LinearConfig) maxIssues() int {
+	if c.MaxIssues <= 0 {
+		return linearDefaultMaxIssues
+	}
+	if c.MaxIssues > linearMaxIssuesCap {
+		return linearMaxIssuesCap
+	}
+	return c.MaxIssues
+}
+
+// filter builds the IssueFilter for the configured teams and sync window.
+func (c *LinearConfig) filter(since *time.Time) map[string]interface{} {
+	filter := map[string]interface{}{}
+	if len(c.Teams) > 0 {
+		filter["team"] = map[string]interface{}{"key": map[string]interface{}{"in": c.Teams}}
+	}
+	if since != nil {
+		filter["updatedAt"] = map[string]interface{}{"gte": since.UTC().Format(time.RFC3339)}
+	}
+	return filter
+}
+
+func init() {
+	DefaultRegistry.Register(&LinearProvider{})
+}
+
+func (l *LinearProvider) Name() string        { return "linear" }
+func (l *LinearProvider) DisplayName() string { return "Linear" }
+
+func (l *LinearProvider) DefaultConfig() json.RawMessage {
+	return json.RawMessage(`{
+  "api_key_env": "LINEAR_API_KEY",
+  "teams": ["ENG"],
+  "include_comments": true,
+  "max_issues": 500,
+  "project": ""
+}`)
+}
+
+func (l *LinearProvider) ValidateConfig(config json.RawMessage) error {
+	var cfg LinearConfig
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return fmt.Errorf("invalid config JSON: %w", err)
+	}
+	if cfg.APIKey == "" && cfg.APIKeyEnv == "" {
+		return fmt.Errorf("api_key or api_key_env is required")
+	}
+	if cfg.APIKeyEnv != "" && os.Getenv(cfg.APIKeyEnv) == "" {
+		return fmt.Errorf("environment variable %s (api_key_env) is not set", cfg.APIKeyEnv)
+	}
+	for _, t := range cfg.Teams {
+		if strings.TrimSpace(t) == "" {
+			return fmt.Errorf("teams cannot contain empty values")
+		}
+	}
+	return nil
+}
+
+func (l *LinearProvider) Fetch(ctx context.Context, config json.RawMessage, since *time.Time) ([]Record, error) {
+	if err := l.ValidateConfig(config); err != nil {
+		return nil, err
+	}
+	var cfg LinearConfig
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+
+	client := &linearClient{
+		apiKey:     cfg.apiKey(),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	vars := map[string]interface{}{
+		"filter": cfg.filter(since),
+		"first":  linearPageSize,
+	}
+
+	var records []Record
+	fetched := 0
+	for fetched < cfg.maxIssues() {
+		var data struct {
+			Issues struct {
+				Nodes    []linearIssue `json:"nodes"`
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `json:"issues"`
+		}
+		if err := client.query(ctx, linearIssuesQuery, vars, &data); err != nil {
+			return nil, err
+		}
+		for _, raw := range data.Issues.Nodes {
+			if fetched >= cfg.maxIssues() {
+				break
+			}
+			fetched++
+			issue := raw.toTrackerIssue()
+			records = append(records, issue.record("linear", cfg.Project))
+			if !cfg.includeComments() {
+				continue
+			}
+			for _, c := range raw.Comments.Nodes {
+				if since != nil && c.UpdatedAt.Before(*since) {
+					continue
+				}
+				if strings.TrimSpace(c.Body) == "" {
+					continue
+				}
+				author := "unknown"
+				if c.User != nil {
+					author = c.User.Name
+				}
+				records = append(records, issue.commentRecord("linear", cfg.Project, trackerComment{
+					ID: c.ID, Author: author, Body: c.Body, Updated: c.UpdatedAt,
+				}))
+			}
+		}
+		if !data.Issues.PageInfo.HasNextPage || data.Issues.PageInfo.EndCursor == "" {
+			break
+		}
+		vars["after"] = data.Issues.PageInfo.EndCursor
+	}
+	return records, nil
+}
+
+// --- Linear API types ---
+
+type linearState struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // triage | backlog | unstarted | started | completed | canceled
+}
+
+type linearIssue struct {
+	Identifier    string      `json:"identifier"`
+	Title         string      `json:"title"`
+	Description   string      `json:"description"`
+	URL           string      `json:"url"`
+	PriorityLabel string      `json:"priorityLabel"`
+	UpdatedAt     time.Time   `json:"updatedAt"`
+	State         linearState `json:"state"`
+	Assignee      *struct {
+		Name string `json:"name"`
+	} `json:"assignee"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	// InverseRelations are relations other issues hold to this one; type
+	// "blocks" means that issue blocks this one.
+	InverseRelations struct {
+		Nodes []struct {
+			Type  string `json:"type"`
+			Issue struct {
+				Identifier string      `json:"identifier"`
+				State      linearState `json:"state"`
+			} `json:"issue"`
+		} `json:"nodes"`
+	} `json:"inverseRelations"`
+	Comments struct {
+		Nodes []struct {
+			ID        string    `json:"id"`
+			Body      string    `json:"body"`
+			UpdatedAt time.Time `json:"updatedAt"`
+			User      *struct {
+				Name string `json:"name"`
+			} `json:"user"`
+		} `json:"nodes"`
+	} `json:"comments"`
+}
+
+func linearStateClosed(s linearState) bool {
+	return s.Type == "completed" || s.Type == "canceled"
+}
+
+func (li linearIssue) toTrackerIssue() trackerIssue {
+	issue := trackerIssue{
+		Key:         li.Identifier,
+		Title:       li.Title,
+		Description: li.Description,
+		URL:         li.URL,
+		Status:      li.State.Name,
+		Updated:     li.UpdatedAt,
+	}
+	if li.Assignee != nil {
+		issue.Assignee = li.Assignee.Name
+	}
+	if li.PriorityLabel != "" && li.PriorityLabel != "No priority" {
+		issue.Priority = li.PriorityLabel
+	}
+	// Linear has no separate resolution field: the closing state is it.
+	if linearStateClosed(li.State) {
+		issue.Resolution = li.State.Name
+	}
+	for _, l := range li.Labels.Nodes {
+		issue.Labels = append(issue.Labels, l.Name)
+	}
+	for _, rel := range li.InverseRelations.Nodes {
+		if rel.Type != "blocks" || linearStateClosed(rel.Issue.State) {
+			continue
+		}
+		issue.BlockedBy = append(issue.BlockedBy, rel.Issue.Identifier)
+	}
+	return issue
+}
+
+// --- HTTP client ---
+
+type linearClient struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// query runs a GraphQL query and decodes its data into result.
+func (c *linearClient) query(ctx context.Context, query string, vars map[string]interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": vars})
+	if err != nil {
+		return fmt.Errorf("encoding query: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, linearAPIURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Linear API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	if len(envelope.Errors) > 0 {
+		return fmt.Errorf("Linear API error: %s", envelope.Errors[0].Message)
+	}
+	if err := json.Unmarshal(envelope.Data, result); err != nil {
+		return fmt.Errorf("decoding data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLinearFetch(t *testing.T) {
+	var requests []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "lin_api_test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req.Variables)
+
+		if req.Variables["after"] == nil {
+			w.Write([]byte(`{"data":{"issues":{"nodes":[{
+				"identifier":"ENG-42","title":"Migrate auth to OIDC","description":"Decided to use Keycloak.",
+				"url":"https://linear.app/acme/issue/ENG-42","priorityLabel":"Urgent","updatedAt":"2026-10-02T08:00:00Z",
+				"state":{"name":"In Review","type":"started"},"assignee":{"name":"Kai"},
+				"labels":{"nodes":[{"name":"Decision"}]},
+				"inverseRelations":{"nodes":[
+					{"type":"blocks","issue":{"identifier":"ENG-7","state":{"type":"started"}}},
+					{"type":"blocks","issue":{"identifier":"ENG-8","state":{"type":"completed"}}},
+					{"type":"related","issue":{"identifier":"ENG-9","state":{"type":"started"}}}]},
+				"comments":{"nodes":[{"id":"c1","body":"LGTM","updatedAt":"2026-10-02T09:00:00Z","user":{"name":"Ria"}}]}
+			}],"pageInfo":{"hasNextPage":true,"endCursor":"cur1"}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"issues":{"nodes":[{
+			"identifier":"ENG-43","title":"Old flag","description":"","url":"","priorityLabel":"No priority",
+			"updatedAt":"2026-10-02T07:00:00Z","state":{"name":"Canceled","type":"canceled"},"assignee":null,
+			"labels":{"nodes":[]},"inverseRelations":{"nodes":[]},"comments":{"nodes":[]}
+		}],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}`))
+	}))
+	defer srv.Close()
+
+	prev := linearAPIURL
+	linearAPIURL = srv.URL
+	defer func() { linearAPIURL = prev }()
+
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	cfg := json.RawMessage(`{"api_key":"lin_api_test","teams":["ENG"],"project":"platform"}`)
+	records, err := (&LinearProvider{}).Fetch(context.Background(), cfg, &since)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records (2 issues + 1 comment), got %d", len(records))
+	}
+
+	issue := records[0]
+	if issue.ExternalID != "linear:ENG-42" || issue.MemoryClass != "decision" || !strings.Contains(issue.Content, "Blocked by: ENG-7\n") {
+		t.Errorf("unexpected issue record: %+v", issue)
+	}
+	if len(issue.Facts) != 4 || !containsFact(issue.Facts, "ENG-42", "blocked by", "ENG-7") || !containsFact(issue.Facts, "ENG-42", "status", "In Review") {
+		t.Errorf("unexpected facts: %+v", issue.Facts)
+	}
+	if records[1].Source != "issue/ENG-42/comment/c1" {
+		t.Errorf("unexpected comment record: %+v", records[1])
+	}
+	canceled := records[2]
+	if !containsFact(canceled.Facts, "ENG-43", "resolution", "Canceled") || canceled.MemoryClass != "decision" {
+		t.Errorf("canceled issue should carry a resolution decision: %+v", canceled)
+	}
+	if strings.Contains(canceled.Content, "No priority") {
+		t.Errorf("placeholder priority leaked: %s", canceled.Content)
+	}
+
+	filter, _ := json.Marshal(requests[0]["filter"])
+	if string(filter) != `{"team":{"key":{"in":["ENG"]}},"updatedAt":{"gte":"2026-10-01T00:00:00Z"}}` {
+		t.Errorf("filter = %s", filter)
+	}
+	if len(requests) != 2 || requests[1]["after"] != "cur1" {
+		t.Errorf("expected a second page after cur1: %v", requests)
+	}
+}
+
+func TestLinearAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors":[{"message":"Authentication required"}]}`))
+	}))
+	defer srv.Close()
+	prev := linearAPIURL
+	linearAPIURL = srv.URL
+	defer func() { linearAPIURL = prev }()
+
+	_, err := (&LinearProvider{}).Fetch(context.Background(), json.RawMessage(`{"api_key":"x"}`), nil)
+	if err == nil || !strings.Contains(err.Error(), "Authentication required") {
+		t.Fatalf("expected GraphQL error, got %v", err)
+	}
+}
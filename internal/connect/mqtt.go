@@ -0,0 +1,253 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	mqttDefaultListenSeconds = 5
+	mqttMaxListenSeconds     = 120
+	mqttKeepAlive            = 60 * time.Second
+)
+
+// MQTTProvider snapshots the latest payload of selected MQTT topics as
+// short-lived status memories.
+type MQTTProvider struct{}
+
+// MQTTConfig holds configuration for the MQTT connector.
+type MQTTConfig struct {
+	// Broker is "host:port", "mqtt://host:port", or "mqtts://host:port"
+	// (TLS). The port defaults to 1883, or 8883 for mqtts.
+	Broker string `json:"broker"`
+
+	Username string `json:"username,omitempty"`
+	// Password is the broker password. PasswordEnv names an environment
+	// variable to read it from instead.
+	Password    string `json:"password,omitempty"`
+	PasswordEnv string `json:"password_env,omitempty"`
+
+	// ClientID defaults to "cortex-<hostname>".
+	ClientID string `json:"client_id,omitempty"`
+
+	// Topics are the subscriptions; each one becomes a snapshot group.
+	Topics []MQTTTopic `json:"topics"`
+
+	// ListenSeconds is how long each sync listens after subscribing.
+	// Retained messages arrive immediately; the window catches sensors that
+	// publish without retain. Default: 5.
+	ListenSeconds int `json:"listen_seconds,omitempty"`
+
+	// TTLMinutes is how long a snapshot is kept. Default: 60; -1 keeps them.
+	TTLMinutes int `json:"ttl_minutes,omitempty"`
+
+	// Project is the Cortex project tag for imported memories.
+	Project string `json:"project,omitempty"`
+}
+
+// MQTTTopic is one subscription: a topic filter (wildcards + and # allowed)
+// and the label its snapshot is stored under (default: the filter).
+type MQTTTopic struct {
+	Filter string `json:"filter"`
+	Label  string `json:"label,omitempty"`
+}
+
+func (t MQTTTopic) label() string {
+	if strings.TrimSpace(t.Label) != "" {
+		return strings.TrimSpace(t.Label)
+	}
+	return t.Filter
+}
+
+func (c *MQTTConfig) password() string {
+	if c.PasswordEnv != "" {
+		return os.Getenv(c.PasswordEnv)
+	}
+	return c.Password
+}
+
+func (c *MQTTConfig) clientID() string {
+	if c.ClientID != "" {
+		return c.ClientID
+	}
+	host, _ := os.Hostname()
+	return "cortex-" + snapshotKey(host)
+}
+
+func (c *MQTTConfig) listenWindow() time.Duration {
+	switch {
+	case c.ListenSeconds <= 0:
+		return mqttDefaultListenSeconds * time.Second
+	case c.ListenSeconds > mqttMaxListenSeconds:
+		return mqttMaxListenSeconds * time.Second
+	default:
+		return time.Duration(c.ListenSeconds) * time.Second
+	}
+}
+
+// address parses Broker into a dial address and whether to use TLS.
+func (c *MQTTConfig) address() (string, bool, error) {
+	raw := strings.TrimSpace(c.Broker)
+	useTLS := false
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return "", false, fmt.Errorf("invalid broker %q: %w", raw, err)
+		}
+		switch u.Scheme {
+		case "mqtt", "tcp":
+		case "mqtts", "ssl", "tls":
+			useTLS = true
+		default:
+			return "", false, fmt.Errorf("unsupported broker scheme %q (use mqtt:// or mqtts://)", u.Scheme)
+		}
+		raw = u.Host
+	}
+	if raw == "" {
+		return "", false, fmt.Errorf("broker is required (e.g. mqtt://homeassistant.local:1883)")
+	}
+	if _, _, err := net.SplitHostPort(raw); err != nil {
+		port := 1883
+		if useTLS {
+			port = 8883
+		}
+		raw = net.JoinHostPort(raw, strconv.Itoa(port))
+	}
+	return raw, useTLS, nil
+}
+
+func init() {
+	DefaultRegistry.Register(&MQTTProvider{})
+}
+
+func (m *MQTTProvider) Name() string        { return "mqtt" }
+func (m *MQTTProvider) DisplayName() string { return "MQTT" }
+
+func (m *MQTTProvider) DefaultConfig() json.RawMessage {
+	return json.RawMessage(`{
+  "broker": "mqtt://homeassistant.local:1883",
+  "username": "cortex",
+  "password_env": "CORTEX_MQTT_PASSWORD",
+  "topics": [
+    {"filter": "zigbee2mqtt/+/occupancy", "label": "occupancy"},
+    {"filter": "home/presence/#", "label": "presence"}
+  ],
+  "listen_seconds": 5,
+  "ttl_minutes": 60,
+  "project": ""
+}`)
+}
+
+func (m *MQTTProvider) ValidateConfig(config json.RawMessage) error {
+	var cfg MQTTConfig
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return fmt.Errorf("invalid config JSON: %w", err)
+	}
+	if _, _, err := cfg.address(); err != nil {
+		return err
+	}
+	if cfg.PasswordEnv != "" && os.Getenv(cfg.PasswordEnv) == "" {
+		return fmt.Errorf("environment variable %s (password_env) is not set", cfg.PasswordEnv)
+	}
+	if len(cfg.Topics) == 0 {
+		return fmt.Errorf("at least one topic is required")
+	}
+	for _, t := range cfg.Topics {
+		f := strings.TrimSpace(t.Filter)
+		if f == "" {
+			return fmt.Errorf("topic filter cannot be empty")
+		}
+		if i := strings.Index(f, "#"); i >= 0 && (i != len(f)-1 || (i > 0 && f[i-1] != '/')) {
+			return fmt.Errorf("invalid topic filter %q: # must be the last level", f)
+		}
+	}
+	return nil
+}
+
+// SnapshotTTL implements SnapshotProvider.
+func (m *MQTTProvider) SnapshotTTL(config json.RawMessage) time.Duration {
+	var cfg MQTTConfig
+	_ = json.Unmarshal(config, &cfg)
+	return snapshotTTL(cfg.TTLMinutes)
+}
+
+// Fetch subscribes to the configured topics, listens for the configured
+// window, and summarizes the latest payload per topic. since is ignored:
+// every sync takes a fresh snapshot.
+func (m *MQTTProvider) Fetch(ctx context.Context, config json.RawMessage, since *time.Time) ([]Record, error) {
+	if err := m.ValidateConfig(config); err != nil {
+		return nil, err
+	}
+	var cfg MQTTConfig
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	addr, useTLS, _ := cfg.address()
+
+	client, err := dialMQTT(ctx, addr, useTLS)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	if err := client.connect(cfg.clientID(), cfg.Username, cfg.password(), mqttKeepAlive); err != nil {
+		return nil, err
+	}
+
+	filters := make([]string, len(cfg.Topics))
+	for i, t := range cfg.Topics {
+		filters[i] = strings.TrimSpace(t.Filter)
+	}
+	msgs, err := client.subscribe(filters)
+	if err != nil {
+		return nil, err
+	}
+	// A broker that drops the connection mid-window still leaves a usable
+	// snapshot of what arrived; only cancellation aborts the sync.
+	more, err := client.listen(time.Now().Add(cfg.listenWindow()))
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	msgs = append(msgs, more...)
+
+	// Keep the latest payload per topic; empty retained payloads clear a topic.
+	latest := map[string][]byte{}
+	for _, msg := range msgs {
+		if len(msg.Payload) == 0 {
+			delete(latest, msg.Topic)
+			continue
+		}
+		latest[msg.Topic] = msg.Payload
+	}
+
+	groups := map[string]*snapshotGroup{}
+	var order []string
+	for topic, payload := range latest {
+		for _, t := range cfg.Topics {
+			if !mqttTopicMatch(strings.TrimSpace(t.Filter), topic) {
+				continue
+			}
+			key := snapshotKey(t.label())
+			g := groups[key]
+			if g == nil {
+				g = &snapshotGroup{Key: key, Title: t.label()}
+				groups[key] = g
+				order = append(order, key)
+			}
+			g.Lines = append(g.Lines, fmt.Sprintf("%s: %s", topic, snapshotValue(payload)))
+			break
+		}
+	}
+
+	list := make([]snapshotGroup, 0, len(order))
+	for _, key := range order {
+		list = append(list, *groups[key])
+	}
+	return snapshotRecords("MQTT", list, cfg.Project, time.Now()), nil
+}
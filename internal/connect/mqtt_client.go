@@ -0,0 +1,278 @@
+package connect
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// MQTT 3.1.1 control packet types (high nibble of the fixed header).
+const (
+	mqttConnect    = 0x10
+	mqttConnAck    = 0x20
+	mqttPublish    = 0x30
+	mqttPubAck     = 0x40
+	mqttSubscribe  = 0x82 // type 8 with the required 0b0010 flags
+	mqttSubAck     = 0x90
+	mqttDisconnect = 0xE0
+)
+
+// mqttClient is the small MQTT 3.1.1 subset the MQTT connector needs:
+// connect, subscribe at QoS 0, and read PUBLISH packets until a deadline.
+type mqttClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+	stop func() bool
+}
+
+// mqttMessage is one received PUBLISH.
+type mqttMessage struct {
+	Topic   string
+	Payload []byte
+}
+
+func dialMQTT(ctx context.Context, addr string, useTLS bool) (*mqttClient, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	var err error
+	if useTLS {
+		host, _, _ := net.SplitHostPort(addr)
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+	c := &mqttClient{conn: conn, r: bufio.NewReader(conn)}
+	c.stop = context.AfterFunc(ctx, func() { conn.Close() })
+	return c, nil
+}
+
+// Close sends DISCONNECT and closes the connection.
+func (c *mqttClient) Close() error {
+	c.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	c.conn.Write([]byte{mqttDisconnect, 0})
+	c.stop()
+	return c.conn.Close()
+}
+
+// connect performs the CONNECT/CONNACK handshake with a clean session, so
+// the broker keeps no state for the connector between syncs.
+func (c *mqttClient) connect(clientID, username, password string, keepAlive time.Duration) error {
+	var flags byte = 0x02 // clean session
+	var payload []byte
+	payload = mqttAppendString(payload, clientID)
+	if username != "" {
+		flags |= 0x80
+		payload = mqttAppendString(payload, username)
+		if password != "" {
+			flags |= 0x40
+			payload = mqttAppendString(payload, password)
+		}
+	}
+	var body []byte
+	body = mqttAppendString(body, "MQTT")
+	body = append(body, 4, flags) // protocol level 4 = 3.1.1
+	body = binary.BigEndian.AppendUint16(body, uint16(keepAlive/time.Second))
+	body = append(body, payload...)
+
+	c.conn.SetDeadline(time.Now().Add(30 * time.Second))
+	if err := c.writePacket(mqttConnect, body); err != nil {
+		return err
+	}
+	typ, resp, err := c.readPacket()
+	if err != nil {
+		return fmt.Errorf("reading CONNACK: %w", err)
+	}
+	if typ&0xF0 != mqttConnAck || len(resp) < 2 {
+		return fmt.Errorf("expected CONNACK, got packet type 0x%x", typ)
+	}
+	switch resp[1] {
+	case 0:
+		return nil
+	case 4, 5:
+		return fmt.Errorf("broker refused connection: not authorized (bad username or password)")
+	default:
+		return fmt.Errorf("broker refused connection (code %d)", resp[1])
+	}
+}
+
+// subscribe subscribes to filters at QoS 0 and waits for the SUBACK.
+// Retained messages may arrive before it; they are returned.
+func (c *mqttClient) subscribe(filters []string) ([]mqttMessage, error) {
+	const packetID = 1
+	body := binary.BigEndian.AppendUint16(nil, packetID)
+	for _, f := range filters {
+		body = mqttAppendString(body, f)
+		body = append(body, 0)
+	}
+	c.conn.SetDeadline(time.Now().Add(30 * time.Second))
+	if err := c.writePacket(mqttSubscribe, body); err != nil {
+		return nil, err
+	}
+	var early []mqttMessage
+	for {
+		typ, resp, err := c.readPacket()
+		if err != nil {
+			return nil, fmt.Errorf("reading SUBACK: %w", err)
+		}
+		switch typ & 0xF0 {
+		case mqttPublish:
+			msg, err := c.handlePublish(typ, resp)
+			if err != nil {
+				return nil, err
+			}
+			early = append(early, msg)
+		case mqttSubAck:
+			if len(resp) < 2 {
+				return nil, fmt.Errorf("malformed SUBACK")
+			}
+			for i, code := range resp[2:] {
+				if code == 0x80 && i < len(filters) {
+					return nil, fmt.Errorf("broker rejected subscription to %q", filters[i])
+				}
+			}
+			return early, nil
+		}
+	}
+}
+
+// listen collects PUBLISH packets until the deadline passes.
+func (c *mqttClient) listen(until time.Time) ([]mqttMessage, error) {
+	var msgs []mqttMessage
+	for time.Now().Before(until) {
+		c.conn.SetReadDeadline(until)
+		typ, body, err := c.readPacket()
+		if err != nil {
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() || errors.Is(err, os.ErrDeadlineExceeded) {
+				break
+			}
+			return msgs, err
+		}
+		if typ&0xF0 != mqttPublish {
+			continue
+		}
+		msg, err := c.handlePublish(typ, body)
+		if err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// handlePublish decodes a PUBLISH and acknowledges QoS 1 deliveries.
+func (c *mqttClient) handlePublish(typ byte, body []byte) (mqttMessage, error) {
+	topic, rest, err := mqttReadString(body)
+	if err != nil {
+		return mqttMessage{}, fmt.Errorf("malformed PUBLISH: %w", err)
+	}
+	if qos := (typ >> 1) & 0x03; qos > 0 {
+		if len(rest) < 2 {
+			return mqttMessage{}, fmt.Errorf("malformed PUBLISH: missing packet id")
+		}
+		if qos == 1 {
+			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := c.writePacket(mqttPubAck, rest[:2]); err != nil {
+				return mqttMessage{}, err
+			}
+		}
+		rest = rest[2:]
+	}
+	return mqttMessage{Topic: topic, Payload: rest}, nil
+}
+
+func (c *mqttClient) writePacket(typ byte, body []byte) error {
+	pkt := []byte{typ}
+	pkt = mqttAppendLength(pkt, len(body))
+	pkt = append(pkt, body...)
+	_, err := c.conn.Write(pkt)
+	return err
+}
+
+// readPacket reads one control packet: its first header byte and body.
+func (c *mqttClient) readPacket() (byte, []byte, error) {
+	typ, err := c.r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, mult := 0, 1
+	for i := 0; ; i++ {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7F) * mult
+		if b&0x80 == 0 {
+			break
+		}
+		if i == 3 {
+			return 0, nil, fmt.Errorf("malformed remaining length")
+		}
+		mult *= 128
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return 0, nil, err
+	}
+	return typ, body, nil
+}
+
+func mqttAppendLength(b []byte, n int) []byte {
+	for {
+		digit := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			digit |= 0x80
+		}
+		b = append(b, digit)
+		if n == 0 {
+			return b
+		}
+	}
+}
+
+func mqttAppendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+func mqttReadString(b []byte) (string, []byte, error) {
+	if len(b) < 2 {
+		return "", nil, fmt.Errorf("short string")
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if len(b) < 2+n {
+		return "", nil, fmt.Errorf("short string")
+	}
+	return string(b[2 : 2+n]), b[2+n:], nil
+}
+
+// mqttTopicMatch reports whether topic matches filter, honoring the + and #
+// wildcards.
+func mqttTopicMatch(filter, topic string) bool {
+	fs := strings.Split(filter, "/")
+	ts := strings.Split(topic, "/")
+	for i, f := range fs {
+		if f == "#" {
+			return true
+		}
+		if i >= len(ts) {
+			return false
+		}
+		if f != "+" && f != ts[i] {
+			return false
+		}
+	}
+	return len(fs) == len(ts)
+}
@@ -0,0 +1,203 @@
+package connect
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeMQTTBroker accepts one connection, answers CONNACK with code, and on
+// SUBSCRIBE sends retained messages, the SUBACK, then live messages.
+type fakeMQTTBroker struct {
+	code     byte
+	retained map[string]string
+	live     map[string]string
+	gotUser  string
+	gotPass  string
+}
+
+func (b *fakeMQTTBroker) start(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		b.serve(conn)
+	}()
+	return ln.Addr().String()
+}
+
+func (b *fakeMQTTBroker) serve(conn net.Conn) {
+	c := &mqttClient{conn: conn, r: bufio.NewReader(conn)}
+	typ, body, err := c.readPacket()
+	if err != nil || typ != mqttConnect {
+		return
+	}
+	b.parseConnect(body)
+	if err := c.writePacket(mqttConnAck, []byte{0, b.code}); err != nil || b.code != 0 {
+		return
+	}
+	typ, body, err = c.readPacket()
+	if err != nil || typ != mqttSubscribe {
+		return
+	}
+	publish := func(flags byte, topic, payload string) {
+		c.writePacket(mqttPublish|flags, append(mqttAppendString(nil, topic), payload...))
+	}
+	for topic, payload := range b.retained {
+		publish(0x01, topic, payload)
+	}
+	c.writePacket(mqttSubAck, []byte{body[0], body[1], 0})
+	for topic, payload := range b.live {
+		publish(0, topic, payload)
+	}
+	io.Copy(io.Discard, conn)
+}
+
+func (b *fakeMQTTBroker) parseConnect(body []byte) {
+	_, rest, _ := mqttReadString(body) // protocol name
+	if len(rest) < 4 {
+		return
+	}
+	flags := rest[1]
+	rest = rest[4:]
+	_, rest, _ = mqttReadString(rest) // client id
+	if flags&0x80 != 0 {
+		b.gotUser, rest, _ = mqttReadString(rest)
+	}
+	if flags&0x40 != 0 {
+		b.gotPass, _, _ = mqttReadString(rest)
+	}
+}
+
+func mqttConfig(addr string) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{
+  "broker": "mqtt://%s",
+  "username": "cortex",
+  "password": "secret",
+  "topics": [
+    {"filter": "zigbee2mqtt/+/occupancy", "label": "Occupancy"},
+    {"filter": "home/presence/#", "label": "presence"}
+  ],
+  "listen_seconds": 1,
+  "project": "home"
+}`, addr))
+}
+
+func TestMQTTFetch(t *testing.T) {
+	broker := &fakeMQTTBroker{
+		retained: map[string]string{
+			"home/presence/alice":              "home",
+			"home/presence/bob":                "away",
+			"zigbee2mqtt/hall/occupancy":       `{"occupancy":true,"battery":91,"meta":{"x":1}}`,
+			"zigbee2mqtt/hall/occupancy/extra": "ignored",
+		},
+		live: map[string]string{
+			"home/presence/bob": "home",
+		},
+	}
+	addr := broker.start(t)
+
+	records, err := (&MQTTProvider{}).Fetch(context.Background(), mqttConfig(addr), nil)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if broker.gotUser != "cortex" || broker.gotPass != "secret" {
+		t.Errorf("credentials not sent: user=%q pass=%q", broker.gotUser, broker.gotPass)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 snapshot records, got %d: %+v", len(records), records)
+	}
+	occupancy, presence := records[0], records[1]
+	if occupancy.Source != "snapshot/occupancy" || occupancy.MemoryClass != "status" || occupancy.Project != "home" {
+		t.Errorf("unexpected occupancy record: %+v", occupancy)
+	}
+	if !strings.Contains(occupancy.Content, "- zigbee2mqtt/hall/occupancy: battery=91, occupancy=true\n") {
+		t.Errorf("occupancy content:\n%s", occupancy.Content)
+	}
+	if strings.Contains(occupancy.Content, "extra") {
+		t.Errorf("topic outside the filter leaked in:\n%s", occupancy.Content)
+	}
+	want := "Current presence (MQTT snapshot)\n- home/presence/alice: home\n- home/presence/bob: home\n"
+	if presence.Content != want {
+		t.Errorf("presence content = %q, want %q (live message should win over retained)", presence.Content, want)
+	}
+}
+
+func TestMQTTFetchNotAuthorized(t *testing.T) {
+	broker := &fakeMQTTBroker{code: 5}
+	addr := broker.start(t)
+
+	_, err := (&MQTTProvider{}).Fetch(context.Background(), mqttConfig(addr), nil)
+	if err == nil || !strings.Contains(err.Error(), "not authorized") {
+		t.Fatalf("expected not-authorized error, got %v", err)
+	}
+}
+
+func TestMQTTTopicMatch(t *testing.T) {
+	cases := []struct {
+		filter, topic string
+		want          bool
+	}{
+		{"home/presence/#", "home/presence/alice", true},
+		{"home/presence/#", "home/presence", true},
+		{"home/+/temp", "home/kitchen/temp", true},
+		{"home/+/temp", "home/kitchen/humidity", false},
+		{"home/+", "home/kitchen/temp", false},
+		{"#", "anything/at/all", true},
+		{"home/kitchen", "home/kitchen", true},
+		{"home/kitchen", "home/kitchen/temp", false},
+	}
+	for _, c := range cases {
+		if got := mqttTopicMatch(c.filter, c.topic); got != c.want {
+			t.Errorf("mqttTopicMatch(%q, %q) = %v, want %v", c.filter, c.topic, got, c.want)
+		}
+	}
+}
+
+func TestMQTTValidateConfig(t *testing.T) {
+	p := &MQTTProvider{}
+	for _, cfg := range []string{
+		`{"topics":[{"filter":"a/b"}]}`,
+		`{"broker":"http://broker:1883","topics":[{"filter":"a/b"}]}`,
+		`{"broker":"broker"}`,
+		`{"broker":"broker","topics":[{"filter":"a/#/b"}]}`,
+		`{"broker":"broker","topics":[{"filter":"a/b#"}]}`,
+		`{"broker":"broker","topics":[{"filter":" "}]}`,
+	} {
+		if err := p.ValidateConfig(json.RawMessage(cfg)); err == nil {
+			t.Errorf("expected error for %s", cfg)
+		}
+	}
+
+	for broker, want := range map[string]string{
+		"broker":                   "broker:1883",
+		"mqtts://broker":           "broker:8883",
+		"tcp://10.0.0.5:1884":      "10.0.0.5:1884",
+		"homeassistant.local:1883": "homeassistant.local:1883",
+	} {
+		cfg := MQTTConfig{Broker: broker}
+		got, _, err := cfg.address()
+		if err != nil || got != want {
+			t.Errorf("address(%q) = %q, %v; want %q", broker, got, err, want)
+		}
+	}
+
+	// Sanity-check the wire helpers used by the fake broker.
+	if got := mqttAppendLength(nil, 321); len(got) != 2 || binary.BigEndian.Uint16(got) != 0xC102 {
+		t.Errorf("mqttAppendLength(321) = %x", got)
+	}
+}
@@ -0,0 +1,164 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// Quota caps how many records a connector may import per rolling hour and
+// per rolling day. Zero means no limit for that window.
+type Quota struct {
+	Hourly int `json:"hourly,omitempty"`
+	Daily  int `json:"daily,omitempty"`
+}
+
+// IsZero reports whether q sets no limit.
+func (q Quota) IsZero() bool { return q.Hourly <= 0 && q.Daily <= 0 }
+
+// merge fills q's unset windows from fallback.
+func (q Quota) merge(fallback Quota) Quota {
+	if q.Hourly <= 0 {
+		q.Hourly = fallback.Hourly
+	}
+	if q.Daily <= 0 {
+		q.Daily = fallback.Daily
+	}
+	return q
+}
+
+// QuotaPolicy is the global quota configuration (import.quotas): a default
+// for every connector and per-connector overrides.
+type QuotaPolicy struct {
+	Default    Quota
+	Connectors map[string]Quota
+}
+
+// QuotaPolicyFromConfig converts import.quotas into a QuotaPolicy. It
+// returns nil when no quota is configured.
+func QuotaPolicyFromConfig(cfg cfgresolver.QuotaConfig) *QuotaPolicy {
+	policy := &QuotaPolicy{
+		Default:    Quota{Hourly: cfg.Hourly, Daily: cfg.Daily},
+		Connectors: make(map[string]Quota, len(cfg.Connectors)),
+	}
+	for name, q := range cfg.Connectors {
+		policy.Connectors[name] = Quota{Hourly: q.Hourly, Daily: q.Daily}
+	}
+	if policy.Default.IsZero() && len(policy.Connectors) == 0 {
+		return nil
+	}
+	return policy
+}
+
+// QuotaFromConfig reads the optional "quota" object of a connector's config,
+// e.g. {"quota": {"hourly": 500, "daily": 2000}}.
+func QuotaFromConfig(cfg json.RawMessage) Quota {
+	var wrapper struct {
+		Quota Quota `json:"quota"`
+	}
+	if len(cfg) == 0 || json.Unmarshal(cfg, &wrapper) != nil {
+		return Quota{}
+	}
+	return wrapper.Quota
+}
+
+// EffectiveQuota resolves c's quota: its own config first, then the
+// policy's entry for the connector, then the policy default — per window.
+func EffectiveQuota(c *Connector, policy *QuotaPolicy) Quota {
+	q := QuotaFromConfig(c.Config)
+	if policy != nil {
+		q = q.merge(policy.Connectors[c.Provider]).merge(policy.Default)
+	}
+	return q
+}
+
+// quotaBudget tracks how many more records a sync may import.
+type quotaBudget struct {
+	quota          Quota
+	hourly, daily  int // remaining; -1 = unlimited
+	exceededWindow time.Duration
+}
+
+// newQuotaBudget counts the connector's imports in the current windows.
+func newQuotaBudget(ctx context.Context, sqlStore *store.SQLiteStore, provider string, q Quota, now time.Time) (*quotaBudget, error) {
+	b := &quotaBudget{quota: q, hourly: -1, daily: -1}
+	if q.Hourly > 0 {
+		used, err := sqlStore.CountSourceImportsSince(ctx, provider, now.Add(-time.Hour))
+		if err != nil {
+			return nil, err
+		}
+		b.hourly = max(q.Hourly-used, 0)
+	}
+	if q.Daily > 0 {
+		used, err := sqlStore.CountSourceImportsSince(ctx, provider, now.Add(-24*time.Hour))
+		if err != nil {
+			return nil, err
+		}
+		b.daily = max(q.Daily-used, 0)
+	}
+	return b, nil
+}
+
+// allow reports whether one more record may be imported. Once it returns
+// false, exceededWindow names the window that ran out.
+func (b *quotaBudget) allow() bool {
+	switch {
+	case b.daily == 0:
+		b.exceededWindow = 24 * time.Hour
+		return false
+	case b.hourly == 0:
+		b.exceededWindow = time.Hour
+		return false
+	}
+	return true
+}
+
+// spend records one imported record.
+func (b *quotaBudget) spend() {
+	if b.hourly > 0 {
+		b.hourly--
+	}
+	if b.daily > 0 {
+		b.daily--
+	}
+}
+
+func (b *quotaBudget) reason() string {
+	if b.exceededWindow == time.Hour {
+		return fmt.Sprintf("hourly ingestion quota of %d records exceeded", b.quota.Hourly)
+	}
+	return fmt.Sprintf("daily ingestion quota of %d records exceeded", b.quota.Daily)
+}
+
+// pauseForQuota pauses a connector that ran out of quota for the length of
+// the exhausted window and raises a quota alert.
+func (se *SyncEngine) pauseForQuota(ctx context.Context, c *Connector, b *quotaBudget, result *SyncResult, remaining int) {
+	until := time.Now().UTC().Add(b.exceededWindow)
+	reason := b.reason()
+	result.PausedUntil = &until
+	result.Error = fmt.Sprintf("%s; paused until %s (%d records not imported)", reason, until.Local().Format("2006-01-02 15:04"), remaining)
+
+	_ = se.connStore.Pause(ctx, c.Provider, until, reason)
+	_ = se.connStore.RecordSyncPartial(ctx, c.Provider, int64(result.RecordsImported), result.Error)
+
+	if sqlStore, ok := se.memStore.(*store.SQLiteStore); ok {
+		details, _ := json.Marshal(map[string]any{
+			"provider":         c.Provider,
+			"quota":            b.quota,
+			"records_fetched":  result.RecordsFetched,
+			"records_imported": result.RecordsImported,
+			"records_held":     remaining,
+			"paused_until":     until,
+		})
+		_ = sqlStore.CreateAlert(ctx, &store.Alert{
+			AlertType: store.AlertTypeQuota,
+			Severity:  store.AlertSeverityWarning,
+			Message:   fmt.Sprintf("Connector %s paused: %s", c.Provider, reason),
+			Details:   string(details),
+		})
+	}
+}
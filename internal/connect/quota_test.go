@@ -0,0 +1,149 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestEffectiveQuotaPrecedence(t *testing.T) {
+	policy := &QuotaPolicy{
+		Default:    Quota{Hourly: 100, Daily: 1000},
+		Connectors: map[string]Quota{"gmail": {Daily: 500}},
+	}
+
+	c := &Connector{Provider: "gmail", Config: json.RawMessage(`{"quota":{"hourly":10}}`)}
+	if got, want := EffectiveQuota(c, policy), (Quota{Hourly: 10, Daily: 500}); got != want {
+		t.Errorf("gmail quota = %+v, want %+v", got, want)
+	}
+
+	c = &Connector{Provider: "slack", Config: json.RawMessage(`{}`)}
+	if got, want := EffectiveQuota(c, policy), (Quota{Hourly: 100, Daily: 1000}); got != want {
+		t.Errorf("slack quota = %+v, want %+v", got, want)
+	}
+
+	if got := EffectiveQuota(c, nil); !got.IsZero() {
+		t.Errorf("quota without policy = %+v, want none", got)
+	}
+}
+
+func TestQuotaPolicyFromConfig(t *testing.T) {
+	if p := QuotaPolicyFromConfig(cfgresolver.QuotaConfig{}); p != nil {
+		t.Fatalf("empty config should yield nil policy, got %+v", p)
+	}
+
+	p := QuotaPolicyFromConfig(cfgresolver.QuotaConfig{
+		Connectors: map[string]cfgresolver.IngestQuota{"github": {Hourly: 50}},
+	})
+	if p == nil || p.Connectors["github"].Hourly != 50 {
+		t.Fatalf("unexpected policy: %+v", p)
+	}
+}
+
+func TestSyncOneQuotaPausesConnector(t *testing.T) {
+	mock := &mockProvider{
+		name: "test",
+		records: []Record{
+			{ExternalID: "r1", Content: "first record", Source: "a.md"},
+			{ExternalID: "r2", Content: "second record", Source: "b.md"},
+			{ExternalID: "r3", Content: "third record", Source: "c.md"},
+			{ExternalID: "r4", Content: "fourth record", Source: "d.md"},
+			{ExternalID: "r5", Content: "fifth record", Source: "e.md"},
+		},
+	}
+	engine, cs, st := newTestSyncEngine(t, mock)
+	ctx := context.Background()
+
+	if _, err := cs.Add(ctx, "test", json.RawMessage(`{"quota":{"hourly":2}}`)); err != nil {
+		t.Fatalf("adding connector: %v", err)
+	}
+	conn, err := cs.Get(ctx, "test")
+	if err != nil {
+		t.Fatalf("getting connector: %v", err)
+	}
+
+	result := engine.SyncOne(ctx, conn)
+	if result.RecordsImported != 2 {
+		t.Errorf("expected 2 imported, got %d", result.RecordsImported)
+	}
+	if result.PausedUntil == nil {
+		t.Fatal("expected sync to pause the connector")
+	}
+	if !strings.Contains(result.Error, "hourly ingestion quota of 2") {
+		t.Errorf("unexpected error: %q", result.Error)
+	}
+
+	conn, err = cs.Get(ctx, "test")
+	if err != nil {
+		t.Fatalf("getting connector: %v", err)
+	}
+	if !conn.Paused(time.Now()) {
+		t.Fatal("connector should be paused")
+	}
+	if conn.LastSyncAt != nil {
+		t.Error("a quota-cut sync must not advance last_sync_at")
+	}
+
+	alerts, err := st.(*store.SQLiteStore).ListAlerts(ctx, store.AlertFilter{Type: store.AlertTypeQuota})
+	if err != nil {
+		t.Fatalf("listing alerts: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 quota alert, got %d", len(alerts))
+	}
+
+	// While paused, syncs are skipped without fetching.
+	result = engine.SyncOne(ctx, conn)
+	if result.RecordsFetched != 0 || !strings.Contains(result.Error, "cortex connect resume test") {
+		t.Errorf("paused sync should be skipped, got %+v", result)
+	}
+
+	// Resuming lifts the pause; the hourly window is still spent, so the
+	// next sync imports nothing and pauses again.
+	if err := cs.Resume(ctx, "test"); err != nil {
+		t.Fatalf("resuming: %v", err)
+	}
+	conn, _ = cs.Get(ctx, "test")
+	if conn.Paused(time.Now()) {
+		t.Fatal("connector should no longer be paused")
+	}
+	result = engine.SyncOne(ctx, conn)
+	if result.RecordsFetched != 5 || result.RecordsImported != 0 || result.PausedUntil == nil {
+		t.Errorf("expected fetch then immediate pause, got %+v", result)
+	}
+}
+
+func TestSyncOneGlobalQuota(t *testing.T) {
+	mock := &mockProvider{
+		name: "test",
+		records: []Record{
+			{ExternalID: "r1", Content: "first record", Source: "a.md"},
+			{ExternalID: "r2", Content: "second record", Source: "b.md"},
+		},
+	}
+	engine, cs, _ := newTestSyncEngine(t, mock)
+	ctx := context.Background()
+
+	if _, err := cs.Add(ctx, "test", json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("adding connector: %v", err)
+	}
+	conn, _ := cs.Get(ctx, "test")
+
+	result := engine.SyncOne(ctx, conn, SyncOptions{Quotas: &QuotaPolicy{Default: Quota{Daily: 5}}})
+	if result.Error != "" || result.PausedUntil != nil {
+		t.Fatalf("sync under quota should succeed, got %+v", result)
+	}
+	if result.RecordsImported != 2 {
+		t.Errorf("expected 2 imported, got %d", result.RecordsImported)
+	}
+
+	conn, _ = cs.Get(ctx, "test")
+	if conn.LastSyncAt == nil {
+		t.Error("a full sync should advance last_sync_at")
+	}
+}
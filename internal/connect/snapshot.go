@@ -0,0 +1,116 @@
+package connect
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	snapshotDefaultTTLMinutes = 60
+	snapshotMaxValueChars     = 200
+)
+
+// snapshotTTL converts a connector's ttl_minutes setting; negative disables
+// expiry, zero means the default.
+func snapshotTTL(minutes int) time.Duration {
+	switch {
+	case minutes < 0:
+		return 0
+	case minutes == 0:
+		return snapshotDefaultTTLMinutes * time.Minute
+	default:
+		return time.Duration(minutes) * time.Minute
+	}
+}
+
+// snapshotGroup is one summarized slice of ambient state, e.g. presence or
+// the lights, stored as a single status memory.
+type snapshotGroup struct {
+	Key   string // stable source key, e.g. "presence"
+	Title string
+	Lines []string
+}
+
+// snapshotRecords turns groups into status-class records sourced at
+// "snapshot/<key>". The content carries no sync time, so an unchanged state
+// deduplicates instead of piling up a memory per sync.
+func snapshotRecords(origin string, groups []snapshotGroup, project string, now time.Time) []Record {
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	var records []Record
+	for _, g := range groups {
+		if len(g.Lines) == 0 {
+			continue
+		}
+		sort.Strings(g.Lines)
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Current %s (%s snapshot)\n", g.Title, origin)
+		for _, line := range g.Lines {
+			sb.WriteString("- ")
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+		records = append(records, Record{
+			Content:     sb.String(),
+			Source:      "snapshot/" + g.Key,
+			Section:     g.Title,
+			Project:     project,
+			MemoryClass: "status",
+			Timestamp:   now,
+			ExternalID:  "snapshot:" + g.Key,
+		})
+	}
+	return records
+}
+
+// snapshotKey makes a label usable as a source key.
+func snapshotKey(label string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(label)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+	if key := strings.TrimSuffix(sb.String(), "-"); key != "" {
+		return key
+	}
+	return "state"
+}
+
+// snapshotValue renders a sensor payload compactly: JSON objects become
+// sorted "key=value" pairs, anything else is trimmed text.
+func snapshotValue(payload []byte) string {
+	text := strings.TrimSpace(string(payload))
+	var obj map[string]interface{}
+	if strings.HasPrefix(text, "{") && json.Unmarshal(payload, &obj) == nil {
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			switch v := obj[k].(type) {
+			case map[string]interface{}, []interface{}:
+				continue // nested structures are noise in a summary
+			case nil:
+				continue
+			default:
+				parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+			}
+		}
+		text = strings.Join(parts, ", ")
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) > snapshotMaxValueChars {
+		text = text[:snapshotMaxValueChars] + "…"
+	}
+	return text
+}
@@ -51,7 +51,7 @@ func (cs *ConnectorStore) Add(ctx context.Context, provider string, config json.
 func (cs *ConnectorStore) Get(ctx context.Context, provider string) (*Connector, error) {
 	row := cs.db.QueryRowContext(ctx,
 		`SELECT id, provider, config, enabled, last_sync_at, last_error,
-		        records_imported, created_at, updated_at
+		        records_imported, created_at, updated_at, paused_until, pause_reason, sync_cursor
 		 FROM connectors WHERE provider = ?`, provider,
 	)
 	return scanConnector(row)
@@ -61,7 +61,7 @@ func (cs *ConnectorStore) Get(ctx context.Context, provider string) (*Connector,
 func (cs *ConnectorStore) GetByID(ctx context.Context, id int64) (*Connector, error) {
 	row := cs.db.QueryRowContext(ctx,
 		`SELECT id, provider, config, enabled, last_sync_at, last_error,
-		        records_imported, created_at, updated_at
+		        records_imported, created_at, updated_at, paused_until, pause_reason, sync_cursor
 		 FROM connectors WHERE id = ?`, id,
 	)
 	return scanConnector(row)
@@ -70,7 +70,7 @@ func (cs *ConnectorStore) GetByID(ctx context.Context, id int64) (*Connector, er
 // List returns all connectors, optionally filtered by enabled state.
 func (cs *ConnectorStore) List(ctx context.Context, enabledOnly bool) ([]*Connector, error) {
 	query := `SELECT id, provider, config, enabled, last_sync_at, last_error,
-	                  records_imported, created_at, updated_at
+	                  records_imported, created_at, updated_at, paused_until, pause_reason, sync_cursor
 	           FROM connectors`
 	if enabledOnly {
 		query += " WHERE enabled = 1"
@@ -157,6 +157,63 @@ func (cs *ConnectorStore) RecordSyncError(ctx context.Context, provider string,
 	return err
 }
 
+// RecordSyncPartial updates the connector after a sync that stopped early
+// (e.g. on its ingestion quota). last_sync_at is left alone so the next sync
+// fetches the records this one did not import.
+func (cs *ConnectorStore) RecordSyncPartial(ctx context.Context, provider string, recordsImported int64, reason string) error {
+	_, err := cs.db.ExecContext(ctx,
+		`UPDATE connectors
+		 SET last_error = ?,
+		     records_imported = records_imported + ?,
+		     updated_at = CURRENT_TIMESTAMP
+		 WHERE provider = ?`,
+		reason, recordsImported, provider,
+	)
+	return err
+}
+
+// SetCursor saves the sync position of a cursor-based provider.
+func (cs *ConnectorStore) SetCursor(ctx context.Context, provider string, cursor json.RawMessage) error {
+	_, err := cs.db.ExecContext(ctx,
+		`UPDATE connectors SET sync_cursor = ?, updated_at = CURRENT_TIMESTAMP WHERE provider = ?`,
+		string(cursor), provider,
+	)
+	if err != nil {
+		return fmt.Errorf("saving connector cursor: %w", err)
+	}
+	return nil
+}
+
+// Pause stops syncs of a connector until the given time.
+func (cs *ConnectorStore) Pause(ctx context.Context, provider string, until time.Time, reason string) error {
+	result, err := cs.db.ExecContext(ctx,
+		`UPDATE connectors SET paused_until = ?, pause_reason = ?, updated_at = CURRENT_TIMESTAMP WHERE provider = ?`,
+		until.UTC(), reason, provider,
+	)
+	if err != nil {
+		return fmt.Errorf("pausing connector: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("connector %q not found", provider)
+	}
+	return nil
+}
+
+// Resume lifts a connector's pause.
+func (cs *ConnectorStore) Resume(ctx context.Context, provider string) error {
+	result, err := cs.db.ExecContext(ctx,
+		`UPDATE connectors SET paused_until = NULL, pause_reason = '', updated_at = CURRENT_TIMESTAMP WHERE provider = ?`,
+		provider,
+	)
+	if err != nil {
+		return fmt.Errorf("resuming connector: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("connector %q not found", provider)
+	}
+	return nil
+}
+
 // Remove deletes a connector by provider name.
 func (cs *ConnectorStore) Remove(ctx context.Context, provider string) error {
 	result, err := cs.db.ExecContext(ctx,
@@ -169,7 +226,7 @@ func (cs *ConnectorStore) Remove(ctx context.Context, provider string) error {
 	if rows == 0 {
 		return fmt.Errorf("connector %q not found", provider)
 	}
-	return nil
+	return cs.DeleteHistory(ctx, provider)
 }
 
 // scanConnector scans a single row into a Connector.
@@ -179,11 +236,13 @@ func scanConnector(row *sql.Row) (*Connector, error) {
 	var enabled int
 	var lastSyncAt sql.NullString
 	var lastError sql.NullString
+	var pausedUntil sql.NullTime
+	var cursor string
 
 	err := row.Scan(
 		&c.ID, &c.Provider, &config, &enabled,
 		&lastSyncAt, &lastError, &c.RecordsImported,
-		&c.CreatedAt, &c.UpdatedAt,
+		&c.CreatedAt, &c.UpdatedAt, &pausedUntil, &c.PauseReason, &cursor,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -204,6 +263,13 @@ func scanConnector(row *sql.Row) (*Connector, error) {
 	if lastError.Valid {
 		c.LastError = lastError.String
 	}
+	if pausedUntil.Valid {
+		t := pausedUntil.Time.UTC()
+		c.PausedUntil = &t
+	}
+	if cursor != "" {
+		c.Cursor = json.RawMessage(cursor)
+	}
 	return &c, nil
 }
 
@@ -214,11 +280,13 @@ func scanConnectorRow(rows *sql.Rows) (*Connector, error) {
 	var enabled int
 	var lastSyncAt sql.NullString
 	var lastError sql.NullString
+	var pausedUntil sql.NullTime
+	var cursor string
 
 	err := rows.Scan(
 		&c.ID, &c.Provider, &config, &enabled,
 		&lastSyncAt, &lastError, &c.RecordsImported,
-		&c.CreatedAt, &c.UpdatedAt,
+		&c.CreatedAt, &c.UpdatedAt, &pausedUntil, &c.PauseReason, &cursor,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("scanning connector row: %w", err)
@@ -236,5 +304,12 @@ func scanConnectorRow(rows *sql.Rows) (*Connector, error) {
 	if lastError.Valid {
 		c.LastError = lastError.String
 	}
+	if pausedUntil.Valid {
+		t := pausedUntil.Time.UTC()
+		c.PausedUntil = &t
+	}
+	if cursor != "" {
+		c.Cursor = json.RawMessage(cursor)
+	}
 	return &c, nil
 }
@@ -9,7 +9,7 @@ import (
 	_ "modernc.org/sqlite"
 )
 
-// newTestDB creates an in-memory SQLite DB with the connectors table.
+// newTestDB creates an in-memory SQLite DB with the connector tables.
 func newTestDB(t *testing.T) *sql.DB {
 	t.Helper()
 	db, err := sql.Open("sqlite", ":memory:")
@@ -26,12 +26,31 @@ func newTestDB(t *testing.T) *sql.DB {
 		last_sync_at    DATETIME,
 		last_error      TEXT,
 		records_imported INTEGER NOT NULL DEFAULT 0,
+		paused_until    DATETIME,
+		pause_reason    TEXT NOT NULL DEFAULT '',
+		sync_cursor     TEXT NOT NULL DEFAULT '',
 		created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at      DATETIME DEFAULT CURRENT_TIMESTAMP
 	)`)
 	if err != nil {
 		t.Fatal(err)
 	}
+	_, err = db.Exec(`CREATE TABLE connector_syncs (
+		id               INTEGER PRIMARY KEY AUTOINCREMENT,
+		provider         TEXT NOT NULL,
+		started_at       DATETIME NOT NULL,
+		duration_ms      INTEGER NOT NULL DEFAULT 0,
+		status           TEXT NOT NULL DEFAULT 'ok',
+		records_fetched  INTEGER NOT NULL DEFAULT 0,
+		records_imported INTEGER NOT NULL DEFAULT 0,
+		records_skipped  INTEGER NOT NULL DEFAULT 0,
+		records_expired  INTEGER NOT NULL DEFAULT 0,
+		facts_extracted  INTEGER NOT NULL DEFAULT 0,
+		error            TEXT NOT NULL DEFAULT ''
+	)`)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	t.Cleanup(func() { db.Close() })
 	return db
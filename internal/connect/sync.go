@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -33,6 +34,10 @@ type SyncOptions struct {
 	// AgentID tags all imported memories/facts with this agent identity.
 	AgentID string
 
+	// Quotas is the global ingestion quota policy (import.quotas). A
+	// connector's own "quota" config applies even when nil.
+	Quotas *QuotaPolicy
+
 	// ProgressFn receives periodic per-provider progress updates during sync.
 	// Intended for CLI/UI progress output (e.g., stderr lines), not business logic.
 	ProgressFn func(SyncProgress)
@@ -88,6 +93,29 @@ func (se *SyncEngine) SyncAll(ctx context.Context, opts ...SyncOptions) ([]SyncR
 	return results, nil
 }
 
+// SyncSnapshots runs sync for enabled connectors whose provider is a
+// SnapshotProvider, letting ambient state refresh more often than the
+// connectors that import history.
+func (se *SyncEngine) SyncSnapshots(ctx context.Context, opts ...SyncOptions) ([]SyncResult, error) {
+	opt := SyncOptions{}
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	connectors, err := se.connStore.List(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("listing connectors: %w", err)
+	}
+	var results []SyncResult
+	for _, c := range connectors {
+		if _, ok := se.registry.Get(c.Provider).(SnapshotProvider); !ok {
+			continue
+		}
+		results = append(results, se.SyncOne(ctx, c, opt))
+	}
+	return results, nil
+}
+
 // SyncProvider runs sync for a specific provider by name.
 func (se *SyncEngine) SyncProvider(ctx context.Context, providerName string, opts ...SyncOptions) (SyncResult, error) {
 	opt := SyncOptions{}
@@ -105,13 +133,28 @@ func (se *SyncEngine) SyncProvider(ctx context.Context, providerName string, opt
 	return se.SyncOne(ctx, c, opt), nil
 }
 
-// SyncOne runs sync for a single connector.
+// SyncOne runs sync for a single connector and records it in the
+// connector's sync history.
 func (se *SyncEngine) SyncOne(ctx context.Context, c *Connector, opts ...SyncOptions) SyncResult {
 	opt := SyncOptions{}
 	if len(opts) > 0 {
 		opt = opts[0]
 	}
 
+	result := se.syncOne(ctx, c, opt)
+	if err := se.connStore.RecordSyncHistory(ctx, result); err != nil {
+		if se.verbose {
+			fmt.Printf("  warning: %v\n", err)
+		}
+		return result
+	}
+	if history, err := se.connStore.History(ctx, c.Provider, 2*skipSpikeBaseWindow+1); err == nil {
+		result.SkipSpike = DetectSkipSpike(history)
+	}
+	return result
+}
+
+func (se *SyncEngine) syncOne(ctx context.Context, c *Connector, opt SyncOptions) SyncResult {
 	start := time.Now()
 	result := SyncResult{
 		Provider: c.Provider,
@@ -127,6 +170,42 @@ func (se *SyncEngine) SyncOne(ctx context.Context, c *Connector, opts ...SyncOpt
 		return result
 	}
 
+	// Snapshot memories expire even while the connector is paused.
+	snapshots, isSnapshot := provider.(SnapshotProvider)
+	sqlStore, _ := se.memStore.(*store.SQLiteStore)
+	if isSnapshot && sqlStore != nil {
+		if ttl := snapshots.SnapshotTTL(c.Config); ttl > 0 {
+			n, err := sqlStore.DeleteMemoriesBySourcePrefix(ctx, c.Provider, start.Add(-ttl))
+			if err != nil && se.verbose {
+				fmt.Printf("  warning: expiring snapshots: %v\n", err)
+			}
+			result.RecordsExpired += int(n)
+		}
+	}
+
+	if c.Paused(start) {
+		result.PausedUntil = c.PausedUntil
+		result.Error = fmt.Sprintf("paused until %s: %s (cortex connect resume %s)",
+			c.PausedUntil.Local().Format("2006-01-02 15:04"), c.PauseReason, c.Provider)
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	// Flood protection: imports beyond the connector's quota pause it.
+	var budget *quotaBudget
+	if q := EffectiveQuota(c, opt.Quotas); !q.IsZero() {
+		if sqlStore, ok := se.memStore.(*store.SQLiteStore); ok {
+			b, err := newQuotaBudget(ctx, sqlStore, c.Provider, q, start)
+			if err != nil {
+				result.Error = fmt.Sprintf("checking quota: %v", err)
+				_ = se.connStore.RecordSyncError(ctx, c.Provider, result.Error)
+				result.Duration = time.Since(start)
+				return result
+			}
+			budget = b
+		}
+	}
+
 	// Determine sync window (incremental if we have a last sync time)
 	var since *time.Time
 	if c.LastSyncAt != nil {
@@ -134,7 +213,14 @@ func (se *SyncEngine) SyncOne(ctx context.Context, c *Connector, opts ...SyncOpt
 	}
 
 	// Fetch records from provider
-	records, err := provider.Fetch(ctx, c.Config, since)
+	var records []Record
+	var nextCursor json.RawMessage
+	var err error
+	if cp, ok := provider.(CursorProvider); ok {
+		records, nextCursor, err = cp.FetchCursor(ctx, c.Config, since, c.Cursor)
+	} else {
+		records, err = provider.Fetch(ctx, c.Config, since)
+	}
 	if err != nil {
 		result.Error = fmt.Sprintf("fetch failed: %v", err)
 		_ = se.connStore.RecordSyncError(ctx, c.Provider, result.Error)
@@ -149,12 +235,17 @@ func (se *SyncEngine) SyncOne(ctx context.Context, c *Connector, opts ...SyncOpt
 
 	// Import each record through the standard pipeline
 	var importedMemoryIDs []int64
+	var newFactIDs []int64
 	for i := range records {
 		// Tag records with agent identity from sync options
 		if opt.AgentID != "" && records[i].AgentID == "" {
 			records[i].AgentID = opt.AgentID
 		}
 		rec := records[i]
+		if budget != nil && !budget.allow() {
+			se.pauseForQuota(ctx, c, budget, &result, len(records)-i)
+			break
+		}
 		memID, imported, err := se.importRecord(ctx, c.Provider, rec)
 		if err != nil {
 			if se.verbose {
@@ -164,9 +255,27 @@ func (se *SyncEngine) SyncOne(ctx context.Context, c *Connector, opts ...SyncOpt
 			continue
 		}
 		if imported {
+			if budget != nil {
+				budget.spend()
+			}
 			result.RecordsImported++
 			if memID > 0 {
 				importedMemoryIDs = append(importedMemoryIDs, memID)
+				if isSnapshot && sqlStore != nil {
+					n, err := sqlStore.ReplaceSourceMemories(ctx, recordSourceFile(c.Provider, rec), memID)
+					if err != nil && se.verbose {
+						fmt.Printf("  warning: replacing snapshot %s: %v\n", rec.Source, err)
+					}
+					result.RecordsExpired += int(n)
+				}
+				if len(rec.Facts) > 0 || len(rec.FactPredicates) > 0 {
+					factIDs, err := se.storeRecordFacts(ctx, c.Provider, memID, rec)
+					if err != nil && se.verbose {
+						fmt.Printf("  warning: storing facts for %s: %v\n", rec.ExternalID, err)
+					}
+					result.FactsExtracted += len(factIDs)
+					newFactIDs = append(newFactIDs, factIDs...)
+				}
 			}
 		} else {
 			result.RecordsSkipped++ // deduplicated
@@ -184,24 +293,19 @@ func (se *SyncEngine) SyncOne(ctx context.Context, c *Connector, opts ...SyncOpt
 
 	// Run fact extraction on newly imported memories
 	if opt.Extract && len(importedMemoryIDs) > 0 {
-		factsExtracted, newFactIDs, err := se.extractFacts(ctx, importedMemoryIDs, opt.LLM)
+		factsExtracted, extractedIDs, err := se.extractFacts(ctx, importedMemoryIDs, opt.LLM)
 		if err != nil {
 			if se.verbose {
 				fmt.Printf("  warning: extraction error: %v\n", err)
 			}
 		} else {
-			result.FactsExtracted = factsExtracted
+			result.FactsExtracted += factsExtracted
+			newFactIDs = append(newFactIDs, extractedIDs...)
 			if se.verbose {
 				fmt.Printf("  Facts extracted: %d\n", factsExtracted)
 			}
 		}
 
-		if sqlStore, ok := se.memStore.(*store.SQLiteStore); ok && len(newFactIDs) > 0 {
-			if _, err := sqlStore.UpdateClusters(ctx, newFactIDs); err != nil && se.verbose {
-				fmt.Printf("  warning: cluster update error: %v\n", err)
-			}
-		}
-
 		// Run edge inference after extraction (unless --no-infer)
 		if !opt.NoInfer && factsExtracted > 0 {
 			edgesCreated, err := se.inferEdges(ctx)
@@ -218,9 +322,23 @@ func (se *SyncEngine) SyncOne(ctx context.Context, c *Connector, opts ...SyncOpt
 		}
 	}
 
-	// Update connector state
-	if err := se.connStore.RecordSyncSuccess(ctx, c.Provider, int64(result.RecordsImported)); err != nil {
-		result.Error = fmt.Sprintf("sync succeeded but state update failed: %v", err)
+	if sqlStore, ok := se.memStore.(*store.SQLiteStore); ok && len(newFactIDs) > 0 {
+		if _, err := sqlStore.UpdateClusters(ctx, newFactIDs); err != nil && se.verbose {
+			fmt.Printf("  warning: cluster update error: %v\n", err)
+		}
+	}
+
+	// Update connector state. A quota-paused sync already recorded its
+	// partial progress and must not advance last_sync_at.
+	if result.PausedUntil == nil {
+		if err := se.connStore.RecordSyncSuccess(ctx, c.Provider, int64(result.RecordsImported)); err != nil {
+			result.Error = fmt.Sprintf("sync succeeded but state update failed: %v", err)
+		}
+		if nextCursor != nil {
+			if err := se.connStore.SetCursor(ctx, c.Provider, nextCursor); err != nil {
+				result.Error = fmt.Sprintf("sync succeeded but cursor update failed: %v", err)
+			}
+		}
 	}
 
 	result.Duration = time.Since(start)
@@ -243,19 +361,14 @@ func (se *SyncEngine) importRecord(ctx context.Context, provider string, rec Rec
 		return 0, false, nil
 	}
 
-	// Build source identifier with provider prefix
-	source := fmt.Sprintf("%s:%s", provider, rec.Source)
-	if rec.Source == "" {
-		source = provider
-	}
-
+	sourceFile := recordSourceFile(provider, rec)
 	mem := &store.Memory{
 		Content:       rec.Content,
-		SourceFile:    source,
+		SourceFile:    sourceFile,
 		SourceSection: rec.Section,
 		ContentHash:   hash,
 		Project:       rec.Project,
-		MemoryClass:   rec.MemoryClass,
+		MemoryClass:   valueOrDefault(rec.MemoryClass, store.DefaultClassForSource(sourceFile)),
 	}
 	if rec.AgentID != "" {
 		mem.Metadata = &store.Metadata{AgentID: rec.AgentID}
@@ -273,6 +386,111 @@ func (se *SyncEngine) importRecord(ctx context.Context, provider string, rec Rec
 	return id, true, nil
 }
 
+// recordSourceFile builds a record's source identifier with provider prefix.
+func recordSourceFile(provider string, rec Record) string {
+	if rec.Source == "" {
+		return provider
+	}
+	return fmt.Sprintf("%s:%s", provider, rec.Source)
+}
+
+// storeRecordFacts stores rec.Facts against the memory just imported for it
+// and reconciles them with what earlier imports of the same source asserted:
+// an unchanged value keeps its original fact, a changed value supersedes it,
+// and a value that disappeared from a managed predicate is retired. This is
+// what keeps "PROJ-7 status In Progress" from outliving the issue's move to
+// Done. Returns the IDs of newly stored facts.
+func (se *SyncEngine) storeRecordFacts(ctx context.Context, provider string, memID int64, rec Record) ([]int64, error) {
+	sourceFile := recordSourceFile(provider, rec)
+	prior, err := se.memStore.ListFacts(ctx, store.ListOpts{SourceFile: sourceFile, Limit: 1000})
+	if err != nil {
+		return nil, fmt.Errorf("listing prior facts: %w", err)
+	}
+
+	managed := make(map[string]bool, len(rec.FactPredicates)+len(rec.Facts))
+	for _, p := range rec.FactPredicates {
+		managed[recordFactKey(p)] = true
+	}
+	for _, rf := range rec.Facts {
+		managed[recordFactKey(rf.Predicate)] = true
+	}
+
+	// Index earlier facts on managed predicates by subject+predicate.
+	type slotKey struct{ subject, predicate string }
+	slots := map[slotKey][]*store.Fact{}
+	for _, f := range prior {
+		if f.MemoryID == memID || !managed[recordFactKey(f.Predicate)] {
+			continue
+		}
+		k := slotKey{strings.ToLower(strings.TrimSpace(f.Subject)), recordFactKey(f.Predicate)}
+		slots[k] = append(slots[k], f)
+	}
+
+	mem := &store.Memory{Project: rec.Project}
+	if rec.AgentID != "" {
+		mem.Metadata = &store.Metadata{AgentID: rec.AgentID}
+	}
+
+	var ids []int64
+	kept := map[int64]bool{}
+	replacement := map[slotKey]int64{}
+	for _, rf := range rec.Facts {
+		if strings.TrimSpace(rf.Subject) == "" || strings.TrimSpace(rf.Object) == "" {
+			continue
+		}
+		k := slotKey{strings.ToLower(strings.TrimSpace(rf.Subject)), recordFactKey(rf.Predicate)}
+		unchanged := false
+		for _, f := range slots[k] {
+			if strings.EqualFold(strings.TrimSpace(f.Object), strings.TrimSpace(rf.Object)) {
+				kept[f.ID] = true
+				unchanged = true
+			}
+		}
+		if unchanged {
+			continue
+		}
+		fact := &store.Fact{
+			MemoryID:   memID,
+			Subject:    rf.Subject,
+			Predicate:  rf.Predicate,
+			Object:     rf.Object,
+			FactType:   rf.FactType,
+			Confidence: 1.0,
+		}
+		store.ApplyMemoryScopeToFact(mem, fact)
+		id, err := se.memStore.AddFact(ctx, fact)
+		if err != nil {
+			return ids, fmt.Errorf("storing fact %q: %w", rf.Subject+" "+rf.Predicate, err)
+		}
+		ids = append(ids, id)
+		if _, ok := replacement[k]; !ok {
+			replacement[k] = id
+		}
+	}
+
+	for k, facts := range slots {
+		for _, f := range facts {
+			if kept[f.ID] {
+				continue
+			}
+			if newID, ok := replacement[k]; ok {
+				err = se.memStore.SupersedeFact(ctx, f.ID, newID, provider+" sync: "+k.predicate+" changed")
+			} else {
+				err = se.memStore.UpdateFactState(ctx, f.ID, store.FactStateRetired)
+			}
+			if err != nil {
+				return ids, fmt.Errorf("updating fact %d: %w", f.ID, err)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// recordFactKey normalizes a predicate the way AddFact stores it.
+func recordFactKey(predicate string) string {
+	return strings.ToLower(strings.TrimSpace(store.CanonicalPredicate(predicate)))
+}
+
 // extractFacts runs the fact extraction pipeline on a set of memory IDs.
 // Returns the total number of facts extracted.
 func (se *SyncEngine) extractFacts(ctx context.Context, memoryIDs []int64, llmFlag string) (int, []int64, error) {
@@ -292,6 +510,10 @@ func (se *SyncEngine) extractFacts(ctx context.Context, memoryIDs []int64, llmFl
 	}
 
 	pipeline := extract.NewPipeline(llmConfig)
+	rulesOnly := pipeline
+	if llmConfig != nil && !llmConfig.IsLocal() {
+		rulesOnly = extract.NewPipeline(nil)
+	}
 	totalFacts := 0
 	newFactIDs := make([]int64, 0, len(memoryIDs)*4)
 
@@ -316,8 +538,12 @@ func (se *SyncEngine) extractFacts(ctx context.Context, memoryIDs []int64, llmFl
 			metadata["timestamp_start"] = mem.Metadata.TimestampStart
 		}
 
-		// Extract facts
-		facts, err := pipeline.Extract(ctx, mem.Content, metadata)
+		// Extract facts; local-llm-only projects never reach a remote model.
+		p := pipeline
+		if !store.ProjectAllows(mem.Project, store.PrivacyLocalLLMOnly) {
+			p = rulesOnly
+		}
+		facts, err := p.Extract(ctx, mem.Content, metadata)
 		if err != nil {
 			continue // skip extraction errors
 		}
@@ -334,6 +560,7 @@ func (se *SyncEngine) extractFacts(ctx context.Context, memoryIDs []int64, llmFl
 				DecayRate:    ef.DecayRate,
 				SourceQuote:  ef.SourceQuote,
 				TemporalNorm: ef.TemporalNorm,
+				Negated:      ef.Negated,
 			}
 			store.ApplyMemoryScopeToFact(mem, fact)
 			if !ingest.ShouldStoreExtractedFact(fact) {
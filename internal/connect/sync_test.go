@@ -9,7 +9,7 @@ import (
 )
 
 // newTestSyncEngine creates a SyncEngine with in-memory stores for testing.
-func newTestSyncEngine(t *testing.T, provider *mockProvider) (*SyncEngine, *ConnectorStore, store.Store) {
+func newTestSyncEngine(t *testing.T, provider Provider) (*SyncEngine, *ConnectorStore, store.Store) {
 	t.Helper()
 
 	// Create in-memory Cortex store (with full schema)
@@ -0,0 +1,147 @@
+package connect
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// trackerMaxBodyChars truncates issue descriptions and comments, matching
+// the GitHub connector.
+const trackerMaxBodyChars = 2000
+
+// trackerFactPredicates are the issue fields the Jira and Linear connectors
+// track as facts. Each import of an issue restates all of them, so a value
+// that changed or disappeared since the last sync supersedes or retires the
+// earlier fact.
+var trackerFactPredicates = []string{"status", "assigned to", "priority", "blocked by", "resolution"}
+
+// trackerIssue is the provider-neutral view of a Jira or Linear issue.
+type trackerIssue struct {
+	Key         string // PROJ-123 / ENG-42
+	Title       string
+	Description string
+	URL         string
+	Type        string
+	Status      string
+	Assignee    string
+	Priority    string
+	// Resolution is set once the issue is closed (Done, Won't Do, Duplicate).
+	Resolution string
+	Labels     []string
+	// BlockedBy lists keys of unresolved issues blocking this one.
+	BlockedBy []string
+	Updated   time.Time
+}
+
+// trackerComment is a comment on a trackerIssue.
+type trackerComment struct {
+	ID      string
+	Author  string
+	Body    string
+	Updated time.Time
+}
+
+// record converts the issue to a Record carrying its status facts.
+func (i trackerIssue) record(provider, project string) Record {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[%s] %s\n", i.Key, i.Title)
+	fields := []string{}
+	if i.Type != "" {
+		fields = append(fields, "Type: "+i.Type)
+	}
+	fields = append(fields, "Status: "+i.Status)
+	if i.Assignee != "" {
+		fields = append(fields, "Assignee: "+i.Assignee)
+	}
+	if i.Priority != "" {
+		fields = append(fields, "Priority: "+i.Priority)
+	}
+	if len(i.Labels) > 0 {
+		fields = append(fields, "Labels: "+strings.Join(i.Labels, ", "))
+	}
+	sb.WriteString(strings.Join(fields, " | "))
+	sb.WriteString("\n")
+	if len(i.BlockedBy) > 0 {
+		fmt.Fprintf(&sb, "Blocked by: %s\n", strings.Join(i.BlockedBy, ", "))
+	}
+	if i.Resolution != "" {
+		fmt.Fprintf(&sb, "Resolution: %s\n", i.Resolution)
+	}
+	if i.URL != "" {
+		fmt.Fprintf(&sb, "URL: %s\n", i.URL)
+	}
+	if body := truncateTrackerText(i.Description); body != "" {
+		sb.WriteString("\n")
+		sb.WriteString(body)
+	}
+
+	return Record{
+		Content:        sb.String(),
+		Source:         "issue/" + i.Key,
+		Section:        i.Title,
+		Project:        project,
+		MemoryClass:    i.memoryClass(),
+		Timestamp:      i.Updated,
+		ExternalID:     fmt.Sprintf("%s:%s", provider, i.Key),
+		Facts:          i.facts(),
+		FactPredicates: trackerFactPredicates,
+	}
+}
+
+// facts returns the issue's current status, ownership, and blockers, e.g.
+// "PROJ-123 blocked by PROJ-99".
+func (i trackerIssue) facts() []RecordFact {
+	facts := []RecordFact{{Subject: i.Key, Predicate: "status", Object: i.Status, FactType: "state"}}
+	if i.Assignee != "" {
+		facts = append(facts, RecordFact{Subject: i.Key, Predicate: "assigned to", Object: i.Assignee, FactType: "relationship"})
+	}
+	if i.Priority != "" {
+		facts = append(facts, RecordFact{Subject: i.Key, Predicate: "priority", Object: i.Priority, FactType: "kv"})
+	}
+	for _, b := range i.BlockedBy {
+		facts = append(facts, RecordFact{Subject: i.Key, Predicate: "blocked by", Object: b, FactType: "state"})
+	}
+	if i.Resolution != "" {
+		facts = append(facts, RecordFact{Subject: i.Key, Predicate: "resolution", Object: i.Resolution, FactType: "decision"})
+	}
+	return facts
+}
+
+// memoryClass marks issues closed as won't-do/duplicate, and issues labelled
+// as decisions or RFCs, as decisions; everything else is status.
+func (i trackerIssue) memoryClass() string {
+	for _, label := range i.Labels {
+		name := strings.ToLower(label)
+		if strings.Contains(name, "decision") || strings.Contains(name, "rfc") || strings.Contains(name, "proposal") {
+			return "decision"
+		}
+	}
+	res := strings.ToLower(i.Resolution)
+	for _, kw := range []string{"won't", "wont", "duplicate", "declined", "cancel", "obsolete"} {
+		if strings.Contains(res, kw) {
+			return "decision"
+		}
+	}
+	return "status"
+}
+
+// commentRecord converts a comment on the issue to a Record.
+func (i trackerIssue) commentRecord(provider, project string, c trackerComment) Record {
+	return Record{
+		Content:    fmt.Sprintf("[%s comment] %s:\n%s", i.Key, c.Author, truncateTrackerText(c.Body)),
+		Source:     fmt.Sprintf("issue/%s/comment/%s", i.Key, c.ID),
+		Section:    "Comment on: " + i.Title,
+		Project:    project,
+		Timestamp:  c.Updated,
+		ExternalID: fmt.Sprintf("%s:%s-comment-%s", provider, i.Key, c.ID),
+	}
+}
+
+func truncateTrackerText(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > trackerMaxBodyChars {
+		s = s[:trackerMaxBodyChars] + "\n... (truncated)"
+	}
+	return s
+}
@@ -0,0 +1,258 @@
+// Package daemon hosts Cortex's long-running subsystems (MCP server, graph
+// server, embed watcher, connector scheduler) in one process.
+//
+// A Supervisor runs each subsystem in its own goroutine, restarts it with
+// exponential backoff when it fails, logs lifecycle events through one
+// logger, and reports per-subsystem health for the /healthz endpoint.
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Subsystem states reported by Status.
+const (
+	StateStarting   = "starting"
+	StateRunning    = "running"
+	StateBackoff    = "backoff" // failed; waiting to restart
+	StateStopped    = "stopped" // returned without error, or shut down
+	StateFailed     = "failed"  // gave up after MaxRestarts
+	defaultMinDelay = time.Second
+	defaultMaxDelay = time.Minute
+)
+
+// Subsystem is one long-running unit of work. Run must block until ctx is
+// cancelled (returning nil or ctx.Err()) or the subsystem fails.
+type Subsystem struct {
+	Name string
+	Run  func(ctx context.Context, logger *log.Logger) error
+}
+
+// SubsystemStatus is the health snapshot of one subsystem.
+type SubsystemStatus struct {
+	Name      string    `json:"name"`
+	State     string    `json:"state"`
+	Restarts  int       `json:"restarts"`
+	LastError string    `json:"last_error,omitempty"`
+	Since     time.Time `json:"since"`
+}
+
+// Supervisor runs and restarts subsystems.
+type Supervisor struct {
+	// MaxRestarts bounds consecutive restarts per subsystem; 0 = unlimited.
+	MaxRestarts int
+	// MinDelay and MaxDelay bound the restart backoff (defaults 1s and 1m).
+	MinDelay time.Duration
+	MaxDelay time.Duration
+
+	logger     *log.Logger
+	subsystems []Subsystem
+	started    time.Time
+
+	mu     sync.RWMutex
+	status map[string]*SubsystemStatus
+}
+
+// NewSupervisor creates a supervisor that logs through logger.
+func NewSupervisor(logger *log.Logger) *Supervisor {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &Supervisor{
+		logger:  logger,
+		started: time.Now(),
+		status:  make(map[string]*SubsystemStatus),
+	}
+}
+
+// Add registers a subsystem. Call before Run.
+func (s *Supervisor) Add(sub Subsystem) {
+	s.subsystems = append(s.subsystems, sub)
+	s.setState(sub.Name, StateStarting, nil)
+}
+
+// Run starts every subsystem and blocks until ctx is cancelled and all
+// subsystems have returned. It returns an error only if every subsystem
+// ended up failed.
+func (s *Supervisor) Run(ctx context.Context) error {
+	if len(s.subsystems) == 0 {
+		return fmt.Errorf("no subsystems enabled")
+	}
+
+	var wg sync.WaitGroup
+	for _, sub := range s.subsystems {
+		wg.Add(1)
+		go func(sub Subsystem) {
+			defer wg.Done()
+			s.supervise(ctx, sub)
+		}(sub)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, st := range s.Status() {
+		if st.State == StateFailed {
+			failed++
+		}
+	}
+	if failed == len(s.subsystems) {
+		return fmt.Errorf("all %d subsystems failed", failed)
+	}
+	return nil
+}
+
+func (s *Supervisor) supervise(ctx context.Context, sub Subsystem) {
+	logger := log.New(s.logger.Writer(), s.logger.Prefix()+"["+sub.Name+"] ", s.logger.Flags())
+	minDelay, maxDelay := s.MinDelay, s.MaxDelay
+	if minDelay <= 0 {
+		minDelay = defaultMinDelay
+	}
+	if maxDelay < minDelay {
+		maxDelay = max(defaultMaxDelay, minDelay)
+	}
+
+	failures := 0
+	for {
+		s.setState(sub.Name, StateRunning, nil)
+		logger.Printf("started")
+		runStart := time.Now()
+		err := runRecovered(ctx, sub, logger)
+
+		if ctx.Err() != nil {
+			s.setState(sub.Name, StateStopped, nil)
+			logger.Printf("stopped")
+			return
+		}
+		if err == nil {
+			s.setState(sub.Name, StateStopped, nil)
+			logger.Printf("exited")
+			return
+		}
+
+		// A subsystem that stayed up longer than the backoff ceiling is
+		// considered recovered; start the backoff over.
+		if time.Since(runStart) > maxDelay {
+			failures = 0
+		}
+		failures++
+		if s.MaxRestarts > 0 && failures > s.MaxRestarts {
+			s.setState(sub.Name, StateFailed, err)
+			logger.Printf("failed permanently after %d restarts: %v", failures-1, err)
+			return
+		}
+
+		delay := minDelay << min(failures-1, 16)
+		if delay > maxDelay || delay <= 0 {
+			delay = maxDelay
+		}
+		s.setState(sub.Name, StateBackoff, err)
+		logger.Printf("failed: %v (restart %d in %s)", err, failures, delay)
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			s.setState(sub.Name, StateStopped, err)
+			logger.Printf("stopped")
+			return
+		case <-t.C:
+		}
+		s.bumpRestarts(sub.Name)
+	}
+}
+
+// runRecovered converts a subsystem panic into an error so one subsystem
+// cannot take the whole daemon down.
+func runRecovered(ctx context.Context, sub Subsystem, logger *log.Logger) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	err = sub.Run(ctx, logger)
+	if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+func (s *Supervisor) setState(name, state string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.status[name]
+	if !ok {
+		st = &SubsystemStatus{Name: name}
+		s.status[name] = st
+	}
+	if st.State != state {
+		st.Since = time.Now()
+	}
+	st.State = state
+	if err != nil {
+		st.LastError = err.Error()
+	}
+}
+
+func (s *Supervisor) bumpRestarts(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.status[name]; ok {
+		st.Restarts++
+	}
+}
+
+// Status returns a snapshot of every subsystem, sorted by name.
+func (s *Supervisor) Status() []SubsystemStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]SubsystemStatus, 0, len(s.status))
+	for _, st := range s.status {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Healthy reports whether every subsystem is running.
+func (s *Supervisor) Healthy() bool {
+	for _, st := range s.Status() {
+		if st.State != StateRunning {
+			return false
+		}
+	}
+	return true
+}
+
+// HealthReport is the /healthz payload.
+type HealthReport struct {
+	Status     string            `json:"status"` // "ok" or "degraded"
+	Uptime     string            `json:"uptime"`
+	Subsystems []SubsystemStatus `json:"subsystems"`
+}
+
+// HealthHandler serves /healthz: 200 when every subsystem is running,
+// 503 (status "degraded") otherwise.
+func (s *Supervisor) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := HealthReport{
+			Status:     "ok",
+			Uptime:     time.Since(s.started).Round(time.Second).String(),
+			Subsystems: s.Status(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if !s.Healthy() {
+			report.Status = "degraded"
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
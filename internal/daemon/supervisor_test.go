@@ -0,0 +1,144 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSupervisorRestartsFailingSubsystem(t *testing.T) {
+	var logs syncBuffer
+	sup := NewSupervisor(log.New(&logs, "", 0))
+	sup.MinDelay = time.Millisecond
+	sup.MaxDelay = 5 * time.Millisecond
+
+	var attempts atomic.Int32
+	sup.Add(Subsystem{Name: "flaky", Run: func(ctx context.Context, _ *log.Logger) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("boom")
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+	sup.Add(Subsystem{Name: "steady", Run: func(ctx context.Context, _ *log.Logger) error {
+		<-ctx.Done()
+		return nil
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- sup.Run(ctx) }()
+
+	waitFor(t, func() bool { return attempts.Load() >= 3 && sup.Healthy() })
+	for _, st := range sup.Status() {
+		if st.Name == "flaky" && (st.Restarts != 2 || st.LastError != "boom") {
+			t.Fatalf("flaky status = %+v", st)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	for _, st := range sup.Status() {
+		if st.State != StateStopped {
+			t.Fatalf("%s state after shutdown = %q", st.Name, st.State)
+		}
+	}
+	if out := logs.String(); !strings.Contains(out, "[flaky] failed: boom") || !strings.Contains(out, "[steady] stopped") {
+		t.Fatalf("unexpected log output:\n%s", out)
+	}
+}
+
+func TestSupervisorGivesUpAfterMaxRestartsAndRecoversPanics(t *testing.T) {
+	sup := NewSupervisor(log.New(&syncBuffer{}, "", 0))
+	sup.MinDelay = time.Millisecond
+	sup.MaxRestarts = 1
+	sup.Add(Subsystem{Name: "panics", Run: func(context.Context, *log.Logger) error {
+		panic("kaboom")
+	}})
+
+	err := sup.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected error when every subsystem failed")
+	}
+	st := sup.Status()[0]
+	if st.State != StateFailed || st.Restarts != 1 || !strings.Contains(st.LastError, "kaboom") {
+		t.Fatalf("status = %+v", st)
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	sup := NewSupervisor(log.New(&syncBuffer{}, "", 0))
+	sup.Add(Subsystem{Name: "mcp", Run: func(ctx context.Context, _ *log.Logger) error {
+		<-ctx.Done()
+		return nil
+	}})
+
+	probe := func() (int, HealthReport) {
+		rec := httptest.NewRecorder()
+		sup.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		var report HealthReport
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return rec.Code, report
+	}
+
+	if code, report := probe(); code != http.StatusServiceUnavailable || report.Status != "degraded" {
+		t.Fatalf("before start: %d %+v", code, report)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sup.Run(ctx)
+	waitFor(t, sup.Healthy)
+
+	code, report := probe()
+	if code != http.StatusOK || report.Status != "ok" || len(report.Subsystems) != 1 || report.Subsystems[0].State != StateRunning {
+		t.Fatalf("running: %d %+v", code, report)
+	}
+}
+
+func TestSupervisorRequiresSubsystems(t *testing.T) {
+	if err := NewSupervisor(nil).Run(context.Background()); err == nil {
+		t.Fatal("expected error with no subsystems")
+	}
+}
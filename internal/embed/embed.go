@@ -23,6 +23,7 @@ import (
 	"time"
 
 	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/timings"
 )
 
 // Embedder generates embedding vectors from text.
@@ -45,7 +46,8 @@ type EmbedConfig struct {
 	ModelPath      string
 	Local          bool
 	WillDownload   bool
-	dimensions     int // auto-detected on first call
+	Fallback       []string // embed specs tried in order when this provider fails (embed.fallback)
+	dimensions     int      // auto-detected on first call
 }
 
 // EmbedRequest represents an OpenAI-compatible embeddings request.
@@ -279,11 +281,50 @@ func (c *EmbedConfig) Validate() error {
 }
 
 // NewClient creates a new embedding client with the given configuration.
+// When config.Fallback lists other embedders, the client is wrapped in a
+// FailoverEmbedder; if the configured provider cannot be built (e.g. its API
+// key is missing) the first usable fallback serves instead.
 func NewClient(config *EmbedConfig) (Embedder, error) {
 	if config == nil {
 		return nil, fmt.Errorf("config is required")
 	}
+	primary, err := newSingleClient(config)
+	if len(config.Fallback) == 0 {
+		return primary, err
+	}
+
+	var members []FailoverMember
+	seen := map[string]bool{}
+	if primary != nil {
+		members = append(members, FailoverMember{Name: config.Provider + "/" + config.Model, Embedder: primary})
+		seen[members[0].Name] = true
+	}
+	for _, spec := range config.Fallback {
+		fbCfg, parseErr := ParseEmbedFlag(normalizeEmbedFlag(spec))
+		if parseErr != nil {
+			continue
+		}
+		name := fbCfg.Provider + "/" + fbCfg.Model
+		if seen[name] {
+			continue
+		}
+		fb, fbErr := newSingleClient(fbCfg)
+		if fbErr != nil {
+			continue
+		}
+		seen[name] = true
+		members = append(members, FailoverMember{Name: name, Embedder: fb})
+	}
+	switch len(members) {
+	case 0:
+		return nil, err
+	case 1:
+		return members[0].Embedder, nil
+	}
+	return NewFailoverEmbedder(ExpectedDimensions(config), members...), nil
+}
 
+func newSingleClient(config *EmbedConfig) (Embedder, error) {
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
@@ -325,10 +366,12 @@ func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
 }
 
 // EmbedBatch generates embedding vectors for multiple texts in a single API call.
-func (c *Client) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+func (c *Client) EmbedBatch(ctx context.Context, texts []string) (_ [][]float32, err error) {
 	if len(texts) == 0 {
 		return nil, nil
 	}
+	call := timings.Begin(timings.Embed, c.config.Provider, c.config.Model)
+	defer func() { call.Fail(err); call.End() }()
 
 	// Filter out empty texts
 	nonEmptyTexts := make([]string, 0, len(texts))
@@ -0,0 +1,185 @@
+package embed
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hurttlocker/cortex/internal/circuit"
+	"github.com/hurttlocker/cortex/internal/timings"
+)
+
+// FailoverMember is one embedder in a FailoverEmbedder chain.
+type FailoverMember struct {
+	Name     string // provider/model, e.g. "ollama/all-minilm"
+	Embedder Embedder
+}
+
+// FailoverEmbedder tries a primary embedder and then each fallback in order
+// until one succeeds, skipping members whose circuit breaker is open (unless
+// all are open, in which case the primary is tried anyway).
+//
+// Vectors from different models are not comparable, so fallbacks must serve
+// the same embedding space as the primary (e.g. ollama/all-minilm backed by
+// onnx/all-minilm-l6-v2). As a guard, a member returning vectors whose
+// dimensions differ from the chain's is treated as failed.
+type FailoverEmbedder struct {
+	members []FailoverMember
+
+	mu   sync.Mutex
+	dims int
+}
+
+// NewFailoverEmbedder chains members, primary first. dims is the expected
+// vector size, or 0 to lock it to the first successful result.
+func NewFailoverEmbedder(dims int, members ...FailoverMember) *FailoverEmbedder {
+	return &FailoverEmbedder{members: members, dims: dims}
+}
+
+// Members returns the chain in order, primary first.
+func (f *FailoverEmbedder) Members() []FailoverMember {
+	return append([]FailoverMember(nil), f.members...)
+}
+
+// Embed generates an embedding vector for a single text.
+func (f *FailoverEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if text == "" {
+		return nil, fmt.Errorf("empty text")
+	}
+	embeddings, err := f.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) != 1 {
+		return nil, fmt.Errorf("expected 1 embedding, got %d", len(embeddings))
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch embeds texts with the first healthy member that succeeds.
+func (f *FailoverEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	var failures []string
+	tried := false
+	for i, m := range f.members {
+		last := i == len(f.members)-1
+		if !BreakerFor(m.Name).Allow() {
+			failures = append(failures, m.Name+": circuit open")
+			if !last {
+				recordFailover(m.Name)
+			}
+			continue
+		}
+		tried = true
+		vecs, err := f.try(ctx, m, texts)
+		if err == nil {
+			return vecs, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", m.Name, err))
+		if !last {
+			recordFailover(m.Name)
+		}
+	}
+	if !tried {
+		vecs, err := f.try(ctx, f.members[0], texts)
+		if err == nil {
+			return vecs, nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", f.members[0].Name, err))
+	}
+	return nil, fmt.Errorf("all embedding providers failed: %s", strings.Join(failures, "; "))
+}
+
+func (f *FailoverEmbedder) try(ctx context.Context, m FailoverMember, texts []string) ([][]float32, error) {
+	vecs, err := m.Embedder.EmbedBatch(ctx, texts)
+	if err == nil {
+		err = f.checkDims(m.Name, vecs)
+	}
+	if err == nil || ctx.Err() == nil {
+		BreakerFor(m.Name).Record(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return vecs, nil
+}
+
+// checkDims locks the chain's dimensions on first use and rejects vectors of
+// any other size.
+func (f *FailoverEmbedder) checkDims(name string, vecs [][]float32) error {
+	n := 0
+	for _, v := range vecs {
+		if len(v) > 0 {
+			n = len(v)
+			break
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.dims == 0 {
+		f.dims = n
+		return nil
+	}
+	if n != f.dims {
+		return fmt.Errorf("returned %d-dimension vectors, chain expects %d (fallbacks must use the same embedding model)", n, f.dims)
+	}
+	return nil
+}
+
+// Dimensions returns the chain's vector size, or the primary's before the
+// first call.
+func (f *FailoverEmbedder) Dimensions() int {
+	f.mu.Lock()
+	dims := f.dims
+	f.mu.Unlock()
+	if dims > 0 {
+		return dims
+	}
+	return f.members[0].Embedder.Dimensions()
+}
+
+// HealthCheck probes every member and succeeds if any of them is healthy.
+func (f *FailoverEmbedder) HealthCheck(ctx context.Context) error {
+	var failures []string
+	for _, m := range f.members {
+		err := Probe(ctx, m)
+		if err == nil {
+			return nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", m.Name, err))
+	}
+	return fmt.Errorf("no healthy embedding provider: %s", strings.Join(failures, "; "))
+}
+
+// Probe health-checks one chain member and records the outcome on its
+// circuit breaker. Members without a health check are assumed healthy.
+func Probe(ctx context.Context, m FailoverMember) error {
+	checker, ok := m.Embedder.(interface{ HealthCheck(context.Context) error })
+	if !ok {
+		return nil
+	}
+	err := checker.HealthCheck(ctx)
+	if ctx.Err() == nil {
+		BreakerFor(m.Name).Record(err)
+	}
+	return err
+}
+
+// BreakerFor returns the circuit breaker tracking the named embedder.
+func BreakerFor(name string) *circuit.Breaker {
+	return circuit.For("embed:" + name)
+}
+
+func recordFailover(name string) {
+	provider, model, _ := strings.Cut(name, "/")
+	timings.RecordFailover(timings.Embed, provider, model)
+}
@@ -0,0 +1,128 @@
+package embed
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/circuit"
+	"github.com/hurttlocker/cortex/internal/timings"
+)
+
+type stubEmbedder struct {
+	dims  int
+	err   error
+	calls int
+}
+
+func (s *stubEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vecs, err := s.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vecs[0], nil
+}
+
+func (s *stubEmbedder) EmbedBatch(_ context.Context, texts []string) ([][]float32, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	out := make([][]float32, len(texts))
+	for i := range out {
+		out[i] = make([]float32, s.dims)
+	}
+	return out, nil
+}
+
+func (s *stubEmbedder) Dimensions() int { return s.dims }
+
+func TestFailoverEmbedder_FallsBackAndOpensBreaker(t *testing.T) {
+	circuit.Reset()
+	timings.Enable()
+	t.Cleanup(func() { circuit.Reset(); timings.Disable() })
+
+	primary := &stubEmbedder{dims: 384, err: errors.New("HTTP 503: unavailable")}
+	fallback := &stubEmbedder{dims: 384}
+	f := NewFailoverEmbedder(384,
+		FailoverMember{Name: "ollama/all-minilm", Embedder: primary},
+		FailoverMember{Name: "onnx/all-minilm-l6-v2", Embedder: fallback},
+	)
+
+	for i := 0; i < circuit.Threshold+2; i++ {
+		vec, err := f.Embed(context.Background(), "hello")
+		if err != nil {
+			t.Fatalf("Embed: %v", err)
+		}
+		if len(vec) != 384 {
+			t.Fatalf("expected 384 dims, got %d", len(vec))
+		}
+	}
+	if primary.calls != circuit.Threshold {
+		t.Fatalf("expected the open breaker to stop calls to the primary after %d failures, got %d calls", circuit.Threshold, primary.calls)
+	}
+	if st := BreakerFor("ollama/all-minilm").Status(); st.State != circuit.Open {
+		t.Fatalf("expected primary breaker open, got %+v", st)
+	}
+
+	var failovers int
+	for _, m := range timings.Snapshot().Models {
+		if m.Provider == "ollama" {
+			failovers = m.Failovers
+		}
+	}
+	if failovers != circuit.Threshold+2 {
+		t.Fatalf("expected every call to count as a failover, got %d", failovers)
+	}
+}
+
+func TestFailoverEmbedder_RejectsDimensionMismatch(t *testing.T) {
+	circuit.Reset()
+	t.Cleanup(circuit.Reset)
+
+	f := NewFailoverEmbedder(384,
+		FailoverMember{Name: "ollama/all-minilm", Embedder: &stubEmbedder{err: errors.New("connection refused")}},
+		FailoverMember{Name: "openai/text-embedding-3-small", Embedder: &stubEmbedder{dims: 1536}},
+	)
+	_, err := f.EmbedBatch(context.Background(), []string{"hello"})
+	if err == nil || !strings.Contains(err.Error(), "1536-dimension") {
+		t.Fatalf("expected dimension mismatch error, got %v", err)
+	}
+	if f.Dimensions() != 384 {
+		t.Fatalf("expected chain dimensions to stay 384, got %d", f.Dimensions())
+	}
+}
+
+func TestNewClient_FallbackReplacesUnusablePrimary(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := ParseEmbedFlag("openai/text-embedding-3-small")
+	if err != nil {
+		t.Fatalf("ParseEmbedFlag: %v", err)
+	}
+	if _, err := NewClient(cfg); err == nil {
+		t.Fatal("expected an error without an API key and no fallback")
+	}
+
+	cfg.Fallback = []string{"ollama/all-minilm"}
+	e, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient with fallback: %v", err)
+	}
+	c, ok := e.(*Client)
+	if !ok || c.config.Provider != "ollama" {
+		t.Fatalf("expected the ollama fallback client, got %T", e)
+	}
+
+	cfg.APIKey = "sk-test"
+	e, err = NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	chain, ok := e.(*FailoverEmbedder)
+	if !ok || len(chain.Members()) != 2 || chain.Members()[1].Name != "ollama/all-minilm" {
+		t.Fatalf("expected openai → ollama chain, got %T", e)
+	}
+}
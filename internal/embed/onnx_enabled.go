@@ -11,6 +11,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/hurttlocker/cortex/internal/timings"
 	"github.com/sugarme/tokenizer"
 	"github.com/sugarme/tokenizer/pretrained"
 	ort "github.com/yalue/onnxruntime_go"
@@ -127,10 +128,12 @@ func (o *onnxEmbedder) Embed(ctx context.Context, text string) ([]float32, error
 	return embeddings[0], nil
 }
 
-func (o *onnxEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+func (o *onnxEmbedder) EmbedBatch(ctx context.Context, texts []string) (_ [][]float32, err error) {
 	if len(texts) == 0 {
 		return nil, nil
 	}
+	call := timings.Begin(timings.Embed, "onnx", o.spec.Key)
+	defer func() { call.Fail(err); call.End() }()
 
 	nonEmptyTexts := make([]string, 0, len(texts))
 	indexMap := make([]int, 0, len(texts))
@@ -33,10 +33,19 @@ func ResolveEmbedConfig(cliFlag string) (*EmbedConfig, error) {
 	if err != nil {
 		return nil, err
 	}
+	cfg, err := resolveEmbedProvider(cliFlag, &resolved)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Fallback = resolved.EmbedFallback
+	return cfg, nil
+}
+
+func resolveEmbedProvider(cliFlag string, resolved *cfgresolver.ResolvedConfig) (*EmbedConfig, error) {
 
 	flag := strings.TrimSpace(cliFlag)
 	if flag != "" {
-		return parseResolvedEmbedFlag(flag, &resolved, "explicit", "CLI flag")
+		return parseResolvedEmbedFlag(flag, resolved, "explicit", "CLI flag")
 	}
 
 	if explicit := strings.TrimSpace(resolved.EmbedProvider.Value); explicit != "" {
@@ -44,7 +53,7 @@ func ResolveEmbedConfig(cliFlag string) (*EmbedConfig, error) {
 		if from == "" {
 			from = string(resolved.EmbedProvider.Source)
 		}
-		return parseResolvedEmbedFlag(explicit, &resolved, "explicit", fmt.Sprintf("from %s", from))
+		return parseResolvedEmbedFlag(explicit, resolved, "explicit", fmt.Sprintf("from %s", from))
 	}
 
 	if model, detail, ok := detectOllamaEmbedModel(); ok {
@@ -0,0 +1,239 @@
+// Package extract — rate-aware execution of LLM batch operations.
+//
+// classify, resolve, and summarize send many independent LLM calls. They run
+// them through runLLMBatches, which retries transient provider errors with
+// backoff, halves its concurrency when the provider rate-limits and grows it
+// back as calls succeed, and gives up on the remaining batches after a run of
+// consecutive failures so callers can resume later from their checkpoint.
+package extract
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+const (
+	// batchMaxRetries is how many times a retryable batch failure is retried.
+	batchMaxRetries = 4
+
+	// batchMaxBackoff caps the wait between retries.
+	batchMaxBackoff = 60 * time.Second
+
+	// maxConsecutiveBatchFailures stops a run whose provider keeps failing
+	// after retries, rather than burning through every remaining batch.
+	maxConsecutiveBatchFailures = 5
+)
+
+// batchRetryBase is the first retry delay; it doubles per attempt. A
+// variable so tests can shrink it.
+var batchRetryBase = 2 * time.Second
+
+// ErrBatchRunStopped is returned when a batch run stops early after too many
+// consecutive failed batches. The batches already done are kept.
+var ErrBatchRunStopped = errors.New("stopped after repeated LLM failures")
+
+// batchRunStats summarizes a runLLMBatches call.
+type batchRunStats struct {
+	Failed      int // batches that failed after retries
+	Skipped     int // batches never attempted because the run stopped
+	Retries     int // retried calls
+	RateLimited int // rate-limit responses seen
+	Concurrency int // concurrency in effect when the run ended
+}
+
+// runLLMBatches calls call(ctx, i) for i in [0, n) with at most concurrency
+// calls in flight, retrying retryable errors. done is invoked once per
+// attempted batch with its final error; calls to done are serialized. The
+// returned error is ctx's error or ErrBatchRunStopped when batches were
+// skipped.
+func runLLMBatches(ctx context.Context, n, concurrency int, call func(ctx context.Context, i int) error, done func(i int, err error)) (batchRunStats, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	lim := newAdaptiveLimiter(concurrency)
+
+	var (
+		wg      sync.WaitGroup
+		doneMu  sync.Mutex
+		stats   batchRunStats
+		stopErr error
+	)
+	for i := 0; i < n; i++ {
+		if err := lim.acquire(ctx); err != nil {
+			stopErr = err
+			stats.Skipped = n - i
+			break
+		}
+		if lim.stopped() {
+			lim.release()
+			stopErr = ErrBatchRunStopped
+			stats.Skipped = n - i
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer lim.release()
+			err := callWithRetry(ctx, lim, func() error { return call(ctx, i) })
+			lim.record(err)
+			doneMu.Lock()
+			defer doneMu.Unlock()
+			if err != nil {
+				stats.Failed++
+			}
+			if done != nil {
+				done(i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stats.Retries, stats.RateLimited, stats.Concurrency = lim.counters()
+	return stats, stopErr
+}
+
+// callWithRetry runs fn, retrying retryable errors with exponential backoff.
+// Rate-limit errors also tell the limiter to back off.
+func callWithRetry(ctx context.Context, lim *adaptiveLimiter, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil || attempt >= batchMaxRetries || !llm.IsRetryable(err) {
+			return err
+		}
+		delay := batchRetryBase << attempt
+		if delay > batchMaxBackoff {
+			delay = batchMaxBackoff
+		}
+		if llm.IsRateLimited(err) {
+			lim.rateLimited(delay)
+		}
+		lim.retried()
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
+
+// adaptiveLimiter is a concurrency limit that halves on rate limits (and
+// pauses new calls for the backoff period) and grows by one after limit
+// consecutive successes, up to max.
+type adaptiveLimiter struct {
+	max int
+
+	mu         sync.Mutex
+	limit      int
+	inFlight   int
+	successes  int
+	failures   int // consecutive failed batches
+	pauseUntil time.Time
+	retries    int
+	rateLimits int
+	wake       chan struct{}
+}
+
+func newAdaptiveLimiter(max int) *adaptiveLimiter {
+	return &adaptiveLimiter{max: max, limit: max, wake: make(chan struct{}, 1)}
+}
+
+// acquire blocks until a slot is free and no rate-limit pause is active.
+func (l *adaptiveLimiter) acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		wait := time.Until(l.pauseUntil)
+		if l.inFlight < l.limit && wait <= 0 {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			fire = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return ctx.Err()
+		case <-l.wake:
+		case <-fire:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+func (l *adaptiveLimiter) release() {
+	l.mu.Lock()
+	l.inFlight--
+	l.mu.Unlock()
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// rateLimited halves the limit and pauses new calls for delay.
+func (l *adaptiveLimiter) rateLimited(delay time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rateLimits++
+	l.successes = 0
+	if l.limit > 1 {
+		l.limit /= 2
+	}
+	if until := time.Now().Add(delay); until.After(l.pauseUntil) {
+		l.pauseUntil = until
+	}
+}
+
+func (l *adaptiveLimiter) retried() {
+	l.mu.Lock()
+	l.retries++
+	l.mu.Unlock()
+}
+
+// record notes a batch's final outcome.
+func (l *adaptiveLimiter) record(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err != nil {
+		l.failures++
+		l.successes = 0
+		return
+	}
+	l.failures = 0
+	l.successes++
+	if l.limit < l.max && l.successes >= l.limit {
+		l.limit++
+		l.successes = 0
+	}
+}
+
+func (l *adaptiveLimiter) stopped() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.failures >= maxConsecutiveBatchFailures
+}
+
+func (l *adaptiveLimiter) counters() (retries, rateLimits, limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.retries, l.rateLimits, l.limit
+}
@@ -0,0 +1,178 @@
+package extract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+func TestMain(m *testing.M) {
+	// Keep retry backoff out of test wall time.
+	batchRetryBase = time.Millisecond
+	os.Exit(m.Run())
+}
+
+func TestRunLLMBatches_RetriesRateLimitAndLowersConcurrency(t *testing.T) {
+	var calls sync.Map // batch index -> attempts
+	stats, err := runLLMBatches(context.Background(), 4, 4,
+		func(_ context.Context, i int) error {
+			n, _ := calls.LoadOrStore(i, new(int32))
+			if atomic.AddInt32(n.(*int32), 1) == 1 && i == 0 {
+				return errors.New("openrouter API error (status 429): rate limited")
+			}
+			return nil
+		}, nil)
+	if err != nil {
+		t.Fatalf("runLLMBatches: %v", err)
+	}
+	if stats.Failed != 0 || stats.Skipped != 0 {
+		t.Fatalf("expected all batches to succeed, got %+v", stats)
+	}
+	if stats.Retries != 1 || stats.RateLimited != 1 {
+		t.Fatalf("expected one rate-limited retry, got %+v", stats)
+	}
+	if stats.Concurrency >= 4 {
+		t.Fatalf("expected concurrency to drop below 4 after a rate limit, got %d", stats.Concurrency)
+	}
+}
+
+func TestRunLLMBatches_DoesNotRetryPermanentErrors(t *testing.T) {
+	var attempts int32
+	stats, err := runLLMBatches(context.Background(), 1, 1,
+		func(context.Context, int) error {
+			atomic.AddInt32(&attempts, 1)
+			return errors.New("openrouter API error (status 401): invalid key")
+		}, nil)
+	if err != nil {
+		t.Fatalf("runLLMBatches: %v", err)
+	}
+	if attempts != 1 || stats.Failed != 1 {
+		t.Fatalf("expected a single failed attempt, got attempts=%d stats=%+v", attempts, stats)
+	}
+}
+
+func TestRunLLMBatches_StopsAfterConsecutiveFailures(t *testing.T) {
+	var finished []int
+	stats, err := runLLMBatches(context.Background(), 20, 1,
+		func(context.Context, int) error {
+			return errors.New("openrouter API error (status 503): unavailable")
+		},
+		func(i int, err error) {
+			if err == nil {
+				t.Errorf("batch %d: expected error", i)
+			}
+			finished = append(finished, i)
+		})
+	if !errors.Is(err, ErrBatchRunStopped) {
+		t.Fatalf("expected ErrBatchRunStopped, got %v", err)
+	}
+	if len(finished) != maxConsecutiveBatchFailures {
+		t.Fatalf("expected %d attempted batches, got %d", maxConsecutiveBatchFailures, len(finished))
+	}
+	if stats.Skipped != 20-maxConsecutiveBatchFailures {
+		t.Fatalf("expected %d skipped batches, got %d", 20-maxConsecutiveBatchFailures, stats.Skipped)
+	}
+	if stats.Retries != maxConsecutiveBatchFailures*batchMaxRetries {
+		t.Fatalf("expected %d retries, got %d", maxConsecutiveBatchFailures*batchMaxRetries, stats.Retries)
+	}
+}
+
+func TestRunLLMBatches_CancelledContextSkipsRemaining(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stats, err := runLLMBatches(ctx, 5, 1,
+		func(context.Context, int) error {
+			cancel()
+			return nil
+		}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if stats.Skipped != 4 {
+		t.Fatalf("expected 4 skipped batches, got %d", stats.Skipped)
+	}
+}
+
+func TestAdaptiveLimiter_GrowsBackAfterSuccesses(t *testing.T) {
+	lim := newAdaptiveLimiter(4)
+	lim.rateLimited(0)
+	lim.rateLimited(0)
+	if _, _, limit := lim.counters(); limit != 1 {
+		t.Fatalf("expected limit 1 after two rate limits, got %d", limit)
+	}
+	for i := 0; i < 10; i++ {
+		lim.record(nil)
+	}
+	if _, _, limit := lim.counters(); limit != 4 {
+		t.Fatalf("expected limit to recover to 4, got %d", limit)
+	}
+}
+
+// flakyClassifyProvider fails every call to one batch (by fact ID) and
+// classifies everything else as a decision.
+type flakyClassifyProvider struct {
+	failFactID int64
+}
+
+func (p *flakyClassifyProvider) Complete(_ context.Context, prompt string, _ llm.CompletionOpts) (string, error) {
+	if containsID(prompt, p.failFactID) {
+		return "", errors.New("openrouter API error (status 400): bad request")
+	}
+	var ids []int64
+	for id := int64(1); id <= 100; id++ {
+		if containsID(prompt, id) {
+			ids = append(ids, id)
+		}
+	}
+	out := `{"classifications": [`
+	for i, id := range ids {
+		if i > 0 {
+			out += ","
+		}
+		out += fmt.Sprintf(`{"id": %d, "type": "decision", "confidence": 0.9}`, id)
+	}
+	return out + "]}", nil
+}
+
+func containsID(prompt string, id int64) bool {
+	return strings.Contains(prompt, fmt.Sprintf("id:%d |", id))
+}
+
+func (p *flakyClassifyProvider) Name() string { return "mock/flaky" }
+
+func TestClassifyFacts_OnBatchAppliesPartialResults(t *testing.T) {
+	facts := make([]ClassifyableFact, 6)
+	for i := range facts {
+		facts[i] = ClassifyableFact{ID: int64(i + 1), Subject: "s", Predicate: "p", Object: "o", FactType: "kv"}
+	}
+
+	var applied [][]int64
+	result, err := ClassifyFacts(context.Background(), &flakyClassifyProvider{failFactID: 4}, facts, ClassifyOpts{
+		BatchSize:   3,
+		Concurrency: 1,
+		OnBatch: func(batch []ClassifyableFact, classified []FactClassification) error {
+			ids := make([]int64, 0, len(classified))
+			for _, c := range classified {
+				ids = append(ids, c.FactID)
+			}
+			applied = append(applied, ids)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("ClassifyFacts: %v", err)
+	}
+	if len(applied) != 1 || len(applied[0]) != 3 || applied[0][0] != 1 {
+		t.Fatalf("expected only the first batch (facts 1-3) to be applied, got %v", applied)
+	}
+	if result.Errors != 3 || len(result.Classified) != 3 {
+		t.Fatalf("expected 3 classified and 3 errors, got %d classified, %d errors", len(result.Classified), result.Errors)
+	}
+}
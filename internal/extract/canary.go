@@ -0,0 +1,130 @@
+// Package extract — post-hoc review of a canary sample.
+//
+// With --canary, classify, resolve, and summarize first apply their changes
+// to a small random sample of the store. JudgeChanges then asks a model to
+// re-check each applied change; the caller combines the verdicts with the
+// change in conflict rate to decide whether to continue with the full batch
+// or roll the sample back.
+package extract
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+const (
+	// canaryTimeout bounds one judge call.
+	canaryTimeout = 90 * time.Second
+
+	// canaryJudgeBatch is how many changes go into one judge call.
+	canaryJudgeBatch = 20
+)
+
+const canarySystemPrompt = `You audit changes an automated pass just made to a personal knowledge base of facts (subject → predicate → object). For each numbered change, decide whether it is correct:
+
+- a new fact type must fit the fact's meaning better than the old one
+- a conflict resolution must keep the fact that is current and correct, and a merge must not lose information
+- a summary fact must say what the facts it replaces said, without inventing anything
+
+Judge each change on its own. When you cannot tell, answer false.
+
+Return ONLY a JSON object:
+{"verdicts": [{"index": 1, "correct": true, "reason": "one short sentence"}]}`
+
+const canaryUserTemplate = `OPERATION: {{.Operation}}
+
+CHANGES:
+{{range $i, $c := .Changes}}{{add $i 1}}. {{truncate 600 $c}}
+{{end}}
+Judge every change. Return JSON only.`
+
+// CanaryPromptData is the data for the canary templates.
+type CanaryPromptData struct {
+	Operation string   `json:"operation"`
+	Changes   []string `json:"changes"`
+}
+
+// CanaryVerdict is the judge's opinion of one applied change.
+type CanaryVerdict struct {
+	Index   int    `json:"index"` // 1-based position in the changes judged
+	Correct bool   `json:"correct"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+type canaryResponse struct {
+	Verdicts []CanaryVerdict `json:"verdicts"`
+}
+
+// JudgeChanges asks provider whether each of changes (one plain-language
+// description per applied change) is correct. It returns one verdict per
+// change, in order; a change the model does not answer for counts as not
+// correct, so a judge that skips half the list cannot pass a canary.
+func JudgeChanges(ctx context.Context, provider llm.Provider, operation string, changes []string) ([]CanaryVerdict, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("LLM provider is nil")
+	}
+	verdicts := make([]CanaryVerdict, len(changes))
+	for i := range verdicts {
+		verdicts[i] = CanaryVerdict{Index: i + 1, Reason: "no verdict"}
+	}
+
+	for start := 0; start < len(changes); start += canaryJudgeBatch {
+		end := start + canaryJudgeBatch
+		if end > len(changes) {
+			end = len(changes)
+		}
+		batch, err := judgeCanaryBatch(ctx, provider, CanaryPromptData{Operation: operation, Changes: changes[start:end]})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range batch {
+			if v.Index < 1 || v.Index > end-start {
+				continue
+			}
+			v.Index += start
+			verdicts[v.Index-1] = v
+		}
+	}
+	return verdicts, nil
+}
+
+func judgeCanaryBatch(ctx context.Context, provider llm.Provider, data CanaryPromptData) ([]CanaryVerdict, error) {
+	prompt, err := RenderPrompt(PromptCanary, data)
+	if err != nil {
+		return nil, err
+	}
+	judgeCtx, cancel := context.WithTimeout(ctx, canaryTimeout)
+	defer cancel()
+
+	var verdicts []CanaryVerdict
+	err = llm.CompleteStructured(judgeCtx, provider, prompt.User, llm.CompletionOpts{
+		Temperature: 0.1,
+		MaxTokens:   2048,
+		System:      prompt.System,
+		Format:      "json",
+		Task:        llm.TaskClassify,
+	}, func(reply string) (err error) {
+		verdicts, err = parseCanaryResponse(reply)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LLM canary judge call: %w", err)
+	}
+	return verdicts, nil
+}
+
+// parseCanaryResponse parses the judge's JSON (see llm.RepairJSON).
+func parseCanaryResponse(raw string) ([]CanaryVerdict, error) {
+	var resp canaryResponse
+	if err := llm.DecodeJSON(raw, &resp); err != nil {
+		return nil, fmt.Errorf("invalid JSON from LLM: %w\nraw: %s", err, truncateForError(raw, 300))
+	}
+	for i := range resp.Verdicts {
+		resp.Verdicts[i].Reason = strings.TrimSpace(resp.Verdicts[i].Reason)
+	}
+	return resp.Verdicts, nil
+}
@@ -0,0 +1,93 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+// canaryStubProvider confirms every change whose text contains "good" and
+// answers for at most answer changes per call (0 = all).
+type canaryStubProvider struct {
+	calls   int
+	answer  int
+	prompts []string
+}
+
+func (p *canaryStubProvider) Complete(_ context.Context, prompt string, _ llm.CompletionOpts) (string, error) {
+	p.calls++
+	p.prompts = append(p.prompts, prompt)
+	var verdicts []string
+	for _, line := range strings.Split(prompt, "\n") {
+		var n int
+		if _, err := fmt.Sscanf(line, "%d.", &n); err != nil || n == 0 {
+			continue
+		}
+		if p.answer > 0 && len(verdicts) >= p.answer {
+			break
+		}
+		verdicts = append(verdicts, fmt.Sprintf(`{"index": %d, "correct": %t, "reason": " checked "}`, n, strings.Contains(line, "good")))
+	}
+	return "```json\n{\"verdicts\": [" + strings.Join(verdicts, ",") + "]}\n```", nil
+}
+
+func (p *canaryStubProvider) Name() string { return "mock/canary" }
+
+func TestJudgeChanges_BatchesAndOrdersVerdicts(t *testing.T) {
+	usePromptDir(t)
+	changes := make([]string, canaryJudgeBatch+3)
+	for i := range changes {
+		changes[i] = fmt.Sprintf("change %d is bad", i)
+		if i%2 == 0 {
+			changes[i] = fmt.Sprintf("change %d is good", i)
+		}
+	}
+	provider := &canaryStubProvider{}
+
+	verdicts, err := JudgeChanges(context.Background(), provider, "classify", changes)
+	if err != nil {
+		t.Fatalf("JudgeChanges: %v", err)
+	}
+	if provider.calls != 2 {
+		t.Fatalf("expected 2 judge calls for %d changes, got %d", len(changes), provider.calls)
+	}
+	if !strings.Contains(provider.prompts[0], "OPERATION: classify") || !strings.Contains(provider.prompts[1], "1. change 20 is good") {
+		t.Fatalf("unexpected prompts:\n%s\n---\n%s", provider.prompts[0], provider.prompts[1])
+	}
+	if len(verdicts) != len(changes) {
+		t.Fatalf("got %d verdicts, want %d", len(verdicts), len(changes))
+	}
+	for i, v := range verdicts {
+		if v.Index != i+1 || v.Correct != (i%2 == 0) || v.Reason != "checked" {
+			t.Fatalf("verdict %d = %+v", i, v)
+		}
+	}
+}
+
+func TestJudgeChanges_MissingVerdictsAreNotCorrect(t *testing.T) {
+	usePromptDir(t)
+	provider := &canaryStubProvider{answer: 1}
+	verdicts, err := JudgeChanges(context.Background(), provider, "resolve", []string{"good one", "good two"})
+	if err != nil {
+		t.Fatalf("JudgeChanges: %v", err)
+	}
+	if !verdicts[0].Correct || verdicts[1].Correct || verdicts[1].Reason != "no verdict" {
+		t.Fatalf("unexpected verdicts: %+v", verdicts)
+	}
+
+	if _, err := JudgeChanges(context.Background(), nil, "resolve", []string{"x"}); err == nil {
+		t.Fatal("expected error for nil provider")
+	}
+}
+
+func TestParseCanaryResponse_Invalid(t *testing.T) {
+	if _, err := parseCanaryResponse("no verdicts here"); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+	if n, err := CheckPromptResponse(PromptCanary, `{"verdicts":[{"index":1,"correct":true}]}`); err != nil || n != 1 {
+		t.Fatalf("CheckPromptResponse = %d, %v", n, err)
+	}
+}
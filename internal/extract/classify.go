@@ -7,11 +7,8 @@ package extract
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
-	"sync"
 	"time"
 
 	"github.com/hurttlocker/cortex/internal/llm"
@@ -39,19 +36,13 @@ const (
 	DefaultClassifyConcurrency = 5
 )
 
+// classifySystemPrompt lists the active taxonomy (.Types); with the built-in
+// types it renders the original fixed prompt.
 const classifySystemPrompt = `You are a fact classification system for a personal knowledge base. Each fact has a subject, predicate, and object. Your job is to assign the most accurate TYPE to each fact.
 
 AVAILABLE TYPES:
-- decision: Choices, commitments, locked configs ("Q locked ORB config", "decided to use Alpaca")
-- preference: Likes, dislikes, style choices ("prefers dark mode", "dislikes tedious UI debugging")
-- identity: Personal identifiers, credentials, roles ("email is alice@test.com", "Q's DOB is 12/25/1994")
-- relationship: Connections between entities ("Niot works on Eyes Web", "SB is co-founder")
-- temporal: Time-bound facts, deadlines, expiry dates ("eBay token expires 2027-07-28", "meeting on Tuesday")
-- state: Current conditions, statuses ("ADA bot is LIVE", "disk usage at 92%")
-- location: Geographic or path references ("lives in Philadelphia", "binary at ~/bin/cortex")
-- config: Technical settings, parameters ("model is Haiku 4.5", "port 8090")
-- kv: Generic key-value when no better type fits (LAST RESORT only)
-
+{{range .Types}}- {{.Name}}: {{.Description}}{{if ne .Name .Base}} [a more specific {{.Base}}]{{end}}{{if .Examples}} ({{range $i, $e := .Examples}}{{if $i}}, {{end}}"{{$e}}"{{end}}){{end}}
+{{end}}
 RULES:
 - Classify based on the SEMANTIC MEANING, not just keyword matching
 - Use "decision" when someone CHOSE or LOCKED something (even if it looks like config)
@@ -85,6 +76,10 @@ type ClassifyResult struct {
 	Latency    time.Duration        // Total time
 	Model      string               // Model used
 	BatchCount int                  // Number of LLM batches
+	Skipped    int                  // Facts not attempted because the run stopped early
+	Retries    int                  // LLM calls retried after transient errors
+	RateLimits int                  // Rate-limit responses from the provider
+	StopReason string               // Why the run stopped early ("" = it finished)
 }
 
 // ClassifyOpts configures the classification run.
@@ -93,7 +88,13 @@ type ClassifyOpts struct {
 	MinConfidence float64 // Min confidence to apply reclassification (default: 0.8)
 	Limit         int     // Max facts to process (0 = all)
 	DryRun        bool    // Show changes without applying
-	Concurrency   int     // Parallel LLM batch requests (default: 5)
+	Concurrency   int     // Parallel LLM batch requests (default: 5; lowered automatically when rate-limited)
+
+	// OnBatch, if set, is called after each successful batch with the
+	// batch's facts and its reclassifications, so callers can apply results
+	// and checkpoint progress as they go. Calls are serialized. An error
+	// counts the batch's facts as errors.
+	OnBatch func(batch []ClassifyableFact, classified []FactClassification) error
 }
 
 // DefaultClassifyOpts returns sensible defaults.
@@ -117,11 +118,11 @@ type classifyEntry struct {
 
 // ClassifyableFact is a minimal fact struct for classification input.
 type ClassifyableFact struct {
-	ID        int64
-	Subject   string
-	Predicate string
-	Object    string
-	FactType  string
+	ID        int64  `json:"id"`
+	Subject   string `json:"subject"`
+	Predicate string `json:"predicate"`
+	Object    string `json:"object"`
+	FactType  string `json:"type"`
 }
 
 // ClassifyFacts reclassifies facts using an LLM. Only processes facts
@@ -162,28 +163,23 @@ func ClassifyFacts(ctx context.Context, provider llm.Provider, facts []Classifya
 		batches = append(batches, facts[i:end])
 	}
 
-	// Process batches concurrently with semaphore
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, concurrency)
-	completedBatches := 0
+	// Process batches concurrently; runLLMBatches serializes the callback.
 	totalBatches := len(batches)
+	responses := make([][]classifyEntry, totalBatches)
+	completedBatches := 0
+	processed := 0
 	reclassifiedSoFar := 0
 
-	for _, batch := range batches {
-		wg.Add(1)
-		sem <- struct{}{} // acquire semaphore slot
-
-		go func(batch []ClassifyableFact) {
-			defer wg.Done()
-			defer func() { <-sem }() // release semaphore slot
-
-			classifications, err := classifyBatch(ctx, provider, batch)
-
-			mu.Lock()
-			defer mu.Unlock()
-
+	stats, stopErr := runLLMBatches(ctx, totalBatches, concurrency,
+		func(ctx context.Context, i int) error {
+			classifications, err := classifyBatch(ctx, provider, batches[i])
+			responses[i] = classifications
+			return err
+		},
+		func(i int, err error) {
+			batch := batches[i]
 			completedBatches++
+			processed += len(batch)
 
 			if err != nil {
 				result.Errors += len(batch)
@@ -192,139 +188,125 @@ func ClassifyFacts(ctx context.Context, provider llm.Provider, facts []Classifya
 			}
 
 			result.BatchCount++
-			batchReclassified := 0
-
-			// Build lookup for this batch
-			factMap := make(map[int64]*ClassifyableFact, len(batch))
-			for idx := range batch {
-				factMap[batch[idx].ID] = &batch[idx]
-			}
-
-			for _, c := range classifications {
-				original, ok := factMap[c.ID]
-				if !ok {
-					continue // LLM returned an ID not in the batch
+			classified, unchanged, errs := collectClassifications(batch, responses[i], opts.MinConfidence)
+			responses[i] = nil
+			if opts.OnBatch != nil {
+				if err := opts.OnBatch(batch, classified); err != nil {
+					result.Errors += len(batch)
+					fmt.Fprintf(os.Stderr, "  [%d/%d] applying batch: %v\n", completedBatches, totalBatches, err)
+					return
 				}
-
-				// Skip if type didn't change
-				if c.FactType == original.FactType {
-					result.Unchanged++
-					continue
-				}
-
-				// Skip invalid types
-				if !isValidFactType(c.FactType) {
-					result.Errors++
-					continue
-				}
-
-				// Skip low confidence
-				if c.Confidence < opts.MinConfidence {
-					result.Unchanged++
-					continue
-				}
-
-				result.Classified = append(result.Classified, FactClassification{
-					FactID:     c.ID,
-					OldType:    original.FactType,
-					NewType:    c.FactType,
-					Confidence: c.Confidence,
-				})
-				batchReclassified++
 			}
+			result.Classified = append(result.Classified, classified...)
+			result.Unchanged += unchanged
+			result.Errors += errs
 
-			// Facts in batch not returned by LLM
-			returned := make(map[int64]bool, len(classifications))
-			for _, c := range classifications {
-				returned[c.ID] = true
-			}
-			for _, f := range batch {
-				if !returned[f.ID] {
-					result.Unchanged++
-				}
-			}
-
-			reclassifiedSoFar += batchReclassified
-			processed := completedBatches * opts.BatchSize
-			if processed > result.TotalFacts {
-				processed = result.TotalFacts
-			}
+			reclassifiedSoFar += len(classified)
 			elapsed := time.Since(start)
 			fmt.Fprintf(os.Stderr, "  [%d/%d] %d/%d facts processed, %d reclassified (%.0fs elapsed)\n",
 				completedBatches, totalBatches, processed, result.TotalFacts, reclassifiedSoFar, elapsed.Seconds())
-		}(batch)
+		})
+
+	result.Retries = stats.Retries
+	result.RateLimits = stats.RateLimited
+	if stopErr != nil {
+		result.Skipped = result.TotalFacts - processed
+		result.StopReason = stopErr.Error()
+		fmt.Fprintf(os.Stderr, "  %v; %d facts not attempted\n", stopErr, result.Skipped)
 	}
 
-	wg.Wait()
-
 	result.Latency = time.Since(start)
 	return result, nil
 }
 
+// collectClassifications keeps the LLM's classifications for batch whose
+// type changed to a taxonomy type with at least minConfidence. Facts the LLM
+// skipped count as unchanged.
+func collectClassifications(batch []ClassifyableFact, classifications []classifyEntry, minConfidence float64) (classified []FactClassification, unchanged, errs int) {
+	factMap := make(map[int64]*ClassifyableFact, len(batch))
+	for idx := range batch {
+		factMap[batch[idx].ID] = &batch[idx]
+	}
+
+	returned := make(map[int64]bool, len(classifications))
+	for _, c := range classifications {
+		original, ok := factMap[c.ID]
+		if !ok {
+			continue // LLM returned an ID not in the batch
+		}
+		returned[c.ID] = true
+
+		// Skip if type didn't change
+		if c.FactType == original.FactType {
+			unchanged++
+			continue
+		}
+
+		// Skip types outside the active taxonomy
+		if !isTaxonomyType(c.FactType) {
+			errs++
+			continue
+		}
+
+		// Skip low confidence
+		if c.Confidence < minConfidence {
+			unchanged++
+			continue
+		}
+
+		classified = append(classified, FactClassification{
+			FactID:     c.ID,
+			OldType:    original.FactType,
+			NewType:    c.FactType,
+			Confidence: c.Confidence,
+		})
+	}
+
+	// Facts in batch not returned by LLM
+	for _, f := range batch {
+		if !returned[f.ID] {
+			unchanged++
+		}
+	}
+	return classified, unchanged, errs
+}
+
 // classifyBatch sends one batch of facts to the LLM for classification.
 func classifyBatch(ctx context.Context, provider llm.Provider, facts []ClassifyableFact) ([]classifyEntry, error) {
-	prompt := buildClassifyPrompt(facts)
+	prompt, err := buildClassifyPrompt(facts)
+	if err != nil {
+		return nil, err
+	}
 
 	classifyCtx, cancel := context.WithTimeout(ctx, classifyTimeout)
 	defer cancel()
 
-	response, err := provider.Complete(classifyCtx, prompt, llm.CompletionOpts{
+	var entries []classifyEntry
+	err = llm.CompleteStructured(classifyCtx, provider, prompt.User, llm.CompletionOpts{
 		Temperature: 0.1,
 		MaxTokens:   2048,
-		System:      classifySystemPrompt,
+		System:      prompt.System,
+		Task:        llm.TaskClassify,
+	}, func(reply string) (err error) {
+		entries, err = parseClassifyResponse(reply)
+		return err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("LLM classify call: %w", err)
 	}
-
-	return parseClassifyResponse(response)
+	return entries, nil
 }
 
-// buildClassifyPrompt constructs the user message with a batch of facts.
-func buildClassifyPrompt(facts []ClassifyableFact) string {
-	var sb strings.Builder
-	sb.WriteString("Classify each fact into the correct type. Return JSON only.\n\n")
-	sb.WriteString("FACTS:\n")
-
-	for _, f := range facts {
-		subj := f.Subject
-		if subj == "" {
-			subj = "(none)"
-		}
-		sb.WriteString(fmt.Sprintf("- id:%d | %s → %s → %s (current: %s)\n",
-			f.ID, subj, f.Predicate, truncateForPrompt(f.Object, 80), f.FactType))
-	}
-
-	return sb.String()
+// buildClassifyPrompt renders the classify prompt for a batch of facts,
+// applying any template overrides.
+func buildClassifyPrompt(facts []ClassifyableFact) (*RenderedPrompt, error) {
+	return RenderPrompt(PromptClassify, ClassifyPromptData{Types: Taxonomy(), Facts: facts})
 }
 
-// parseClassifyResponse parses the LLM's JSON (with markdown stripping).
+// parseClassifyResponse parses the LLM's JSON (see llm.RepairJSON).
 func parseClassifyResponse(raw string) ([]classifyEntry, error) {
-	cleaned := strings.TrimSpace(raw)
-
-	// Strip markdown code fences
-	if strings.HasPrefix(cleaned, "```") {
-		lines := strings.Split(cleaned, "\n")
-		start, end := 0, len(lines)
-		for i, line := range lines {
-			if strings.HasPrefix(strings.TrimSpace(line), "```") {
-				if start == 0 {
-					start = i + 1
-				} else {
-					end = i
-					break
-				}
-			}
-		}
-		if start > 0 && end > start {
-			cleaned = strings.Join(lines[start:end], "\n")
-		}
-	}
-
-	cleaned = strings.TrimSpace(cleaned)
-
 	var resp classifyResponse
-	if err := json.Unmarshal([]byte(cleaned), &resp); err != nil {
+	if err := llm.DecodeJSON(raw, &resp); err != nil {
 		return nil, fmt.Errorf("invalid JSON from LLM: %w\nraw: %s", err, truncateForError(raw, 300))
 	}
 
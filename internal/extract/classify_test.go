@@ -291,7 +291,11 @@ func TestBuildClassifyPrompt(t *testing.T) {
 		{ID: 43, Subject: "", Predicate: "port", Object: "8090", FactType: "kv"},
 	}
 
-	prompt := buildClassifyPrompt(facts)
+	rendered, err := buildClassifyPrompt(facts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prompt := rendered.User
 
 	if !strings.Contains(prompt, "id:42") {
 		t.Error("prompt should contain fact ID")
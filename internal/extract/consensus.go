@@ -0,0 +1,272 @@
+// Package extract — multi-model consensus for conflict resolution.
+//
+// A single model sometimes resolves a conflict confidently and wrongly.
+// ResolveConflictsConsensus asks several models about every pair and only
+// returns a supersede or merge when at least a quorum of them proposes the
+// same one; everything else is flagged for human review with each model's
+// vote attached.
+package extract
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+// ConsensusVote is one model's answer for a conflict pair.
+type ConsensusVote struct {
+	Model      string  `json:"model"`
+	Action     string  `json:"action"` // supersede, merge, flag-human, or "" for no answer
+	WinnerID   int64   `json:"winner_id,omitempty"`
+	LoserID    int64   `json:"loser_id,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+	Reason     string  `json:"reason,omitempty"`
+
+	mergedFact *MergedFact
+}
+
+// ConsensusOpts configures ResolveConflictsConsensus.
+type ConsensusOpts struct {
+	ResolveOpts
+	Quorum int // models that must propose the same resolution (default: a strict majority)
+}
+
+// MajorityQuorum is the smallest strict majority of n models.
+func MajorityQuorum(n int) int { return n/2 + 1 }
+
+// ResolveConflictsConsensus asks every provider to resolve each pair and
+// keeps a supersede or merge only when at least opts.Quorum providers
+// propose it: the same winner and loser for supersede, any merge for merge
+// (the merged fact comes from the first agreeing provider, in provider
+// order). Other pairs are flagged for human review. Each resolution carries
+// the individual votes, and its PairIndex is the pair's Index.
+func ResolveConflictsConsensus(ctx context.Context, providers []llm.Provider, pairs []ConflictPair, opts ConsensusOpts) (*ResolveResult, error) {
+	if len(providers) < 2 {
+		return nil, fmt.Errorf("consensus needs at least two models, got %d", len(providers))
+	}
+	names := make([]string, len(providers))
+	for i, p := range providers {
+		if p == nil {
+			return nil, fmt.Errorf("LLM provider is nil")
+		}
+		names[i] = p.Name()
+	}
+	if opts.Quorum <= 0 {
+		opts.Quorum = MajorityQuorum(len(providers))
+	}
+	if opts.Quorum > len(providers) {
+		return nil, fmt.Errorf("quorum %d exceeds the %d models asked", opts.Quorum, len(providers))
+	}
+	if len(pairs) == 0 {
+		return &ResolveResult{}, nil
+	}
+	if opts.MinConfidence <= 0 {
+		opts.MinConfidence = resolveMinConfidence
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultResolveConcurrency
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 5
+	}
+
+	start := time.Now()
+	result := &ResolveResult{
+		TotalPairs: len(pairs),
+		Model:      strings.Join(names, ","),
+	}
+
+	var batches [][]ConflictPair
+	for i := 0; i < len(pairs); i += opts.BatchSize {
+		end := i + opts.BatchSize
+		if end > len(pairs) {
+			end = len(pairs)
+		}
+		batches = append(batches, pairs[i:end])
+	}
+
+	// Call i asks provider i%m about batch i/m. A batch is decided once
+	// all m answers are in; runLLMBatches serializes the callback.
+	m := len(providers)
+	responses := make([][]resolveEntry, len(batches)*m)
+	failed := make([]bool, len(batches)*m)
+	answered := make([]int, len(batches))
+	processed := 0
+	stats, stopErr := runLLMBatches(ctx, len(batches)*m, opts.Concurrency,
+		func(ctx context.Context, i int) error {
+			entries, err := resolveBatch(ctx, providers[i%m], batches[i/m])
+			responses[i] = entries
+			return err
+		},
+		func(i int, err error) {
+			failed[i] = err != nil
+			b := i / m
+			answered[b]++
+			if answered[b] < m {
+				return
+			}
+			batch := batches[b]
+			processed += len(batch)
+
+			votes := make([][]ConsensusVote, len(batch))
+			answers := 0
+			for k := 0; k < m; k++ {
+				j := b*m + k
+				if failed[j] {
+					continue
+				}
+				answers++
+				resolutions, _ := validateResolveEntries(responses[j], opts.MinConfidence)
+				responses[j] = nil
+				for _, r := range resolutions {
+					pos := consensusPairPosition(batch, r)
+					if pos < 0 || hasVoteFrom(votes[pos], names[k]) {
+						continue
+					}
+					votes[pos] = append(votes[pos], ConsensusVote{
+						Model:      names[k],
+						Action:     r.Action,
+						WinnerID:   r.WinnerID,
+						LoserID:    r.LoserID,
+						Confidence: r.Confidence,
+						Reason:     r.Reason,
+						mergedFact: r.MergedFact,
+					})
+				}
+			}
+			if answers == 0 {
+				result.Errors += len(batch)
+				return
+			}
+
+			resolutions := make([]ConflictResolution, len(batch))
+			for pos, p := range batch {
+				resolutions[pos] = decideConsensus(p, votes[pos], names, opts.Quorum)
+			}
+			if opts.OnBatch != nil {
+				if err := opts.OnBatch(batch, resolutions); err != nil {
+					result.Errors += len(batch)
+					return
+				}
+			}
+			result.add(resolutions)
+		})
+
+	result.Retries = stats.Retries
+	result.RateLimits = stats.RateLimited
+	if stopErr != nil {
+		result.Skipped = result.TotalPairs - processed
+		result.StopReason = stopErr.Error()
+	}
+	result.Latency = time.Since(start)
+	return result, nil
+}
+
+// consensusPairPosition finds the pair in batch a resolution is about: by
+// fact IDs for supersede (which must name the pair's two facts), otherwise
+// by the batch-relative pair_index the prompt asks for.
+func consensusPairPosition(batch []ConflictPair, r ConflictResolution) int {
+	if r.Action == "supersede" {
+		for pos, p := range batch {
+			if (r.WinnerID == p.Fact1.ID && r.LoserID == p.Fact2.ID) || (r.WinnerID == p.Fact2.ID && r.LoserID == p.Fact1.ID) {
+				return pos
+			}
+		}
+		return -1
+	}
+	if r.PairIndex >= 0 && r.PairIndex < len(batch) {
+		return r.PairIndex
+	}
+	return -1
+}
+
+func hasVoteFrom(votes []ConsensusVote, model string) bool {
+	for _, v := range votes {
+		if v.Model == model {
+			return true
+		}
+	}
+	return false
+}
+
+// consensusKey groups votes that propose the same resolution.
+func consensusKey(v ConsensusVote) string {
+	if v.Action == "supersede" {
+		return fmt.Sprintf("supersede:%d>%d", v.WinnerID, v.LoserID)
+	}
+	return v.Action
+}
+
+// decideConsensus turns the votes for one pair into a resolution. Models
+// with no vote are listed with an empty action.
+func decideConsensus(pair ConflictPair, votes []ConsensusVote, models []string, quorum int) ConflictResolution {
+	all := make([]ConsensusVote, 0, len(models))
+	for _, model := range models {
+		found := false
+		for _, v := range votes {
+			if v.Model == model {
+				all = append(all, v)
+				found = true
+				break
+			}
+		}
+		if !found {
+			all = append(all, ConsensusVote{Model: model})
+		}
+	}
+	res := ConflictResolution{PairIndex: pair.Index, Action: "flag-human", Votes: all}
+
+	groups := map[string][]ConsensusVote{}
+	var order []string
+	for _, v := range votes {
+		k := consensusKey(v)
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], v)
+	}
+	best, tie := "", false
+	for _, k := range order {
+		switch {
+		case best == "" || len(groups[k]) > len(groups[best]):
+			best, tie = k, false
+		case len(groups[k]) == len(groups[best]):
+			tie = true
+		}
+	}
+
+	if best != "" && !tie && len(groups[best]) >= quorum {
+		agreeing := groups[best]
+		if agreeing[0].Action == "flag-human" {
+			res.Reason = fmt.Sprintf("%d of %d models flagged it for review: %s", len(agreeing), len(models), agreeing[0].Reason)
+			return res
+		}
+		sum := 0.0
+		for _, v := range agreeing {
+			sum += v.Confidence
+		}
+		res.Action = agreeing[0].Action
+		res.WinnerID, res.LoserID = agreeing[0].WinnerID, agreeing[0].LoserID
+		res.MergedFact = agreeing[0].mergedFact
+		res.Confidence = sum / float64(len(agreeing))
+		res.Reason = fmt.Sprintf("%d of %d models agree: %s", len(agreeing), len(models), agreeing[0].Reason)
+		return res
+	}
+
+	parts := make([]string, len(all))
+	for i, v := range all {
+		switch v.Action {
+		case "":
+			parts[i] = v.Model + ": no answer"
+		case "supersede":
+			parts[i] = fmt.Sprintf("%s: keep %d over %d", v.Model, v.WinnerID, v.LoserID)
+		default:
+			parts[i] = v.Model + ": " + v.Action
+		}
+	}
+	res.Reason = fmt.Sprintf("models disagree, %d of %d needed (%s)", quorum, len(models), strings.Join(parts, "; "))
+	return res
+}
@@ -0,0 +1,112 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+func TestResolveConflictsConsensus_AppliesOnlyWhenQuorumAgrees(t *testing.T) {
+	usePromptDir(t)
+	// Pair 0: two of three keep 200 over 100. Pair 1: everyone disagrees.
+	// Pair 2: two of three merge.
+	answer := func(p1, p2 string) string {
+		return `{"resolutions": [
+			{"pair_index": 0, ` + p1 + `},
+			{"pair_index": 1, ` + p2 + `},
+			{"pair_index": 2, "action": "merge", "reason": "complementary", "confidence": 0.8, "merged_fact": {"subject": "s", "predicate": "p", "object": "both", "type": "bogus"}}
+		]}`
+	}
+	providers := []llm.Provider{
+		&mockResolveProvider{name: "m1", response: answer(`"action": "supersede", "winner_id": 200, "loser_id": 100, "reason": "newer", "confidence": 0.9`,
+			`"action": "supersede", "winner_id": 300, "loser_id": 400, "reason": "x", "confidence": 0.9`)},
+		&mockResolveProvider{name: "m2", response: answer(`"action": "supersede", "winner_id": 200, "loser_id": 100, "reason": "more specific", "confidence": 0.8`,
+			`"action": "supersede", "winner_id": 400, "loser_id": 300, "reason": "y", "confidence": 0.9`)},
+		&mockResolveProvider{name: "m3", response: `{"resolutions": [
+			{"pair_index": 0, "action": "supersede", "winner_id": 100, "loser_id": 200, "reason": "older is right", "confidence": 0.9},
+			{"pair_index": 1, "action": "merge", "reason": "z", "confidence": 0.9, "merged_fact": {"subject": "a", "predicate": "b", "object": "c", "type": "kv"}},
+			{"pair_index": 2, "action": "flag-human", "reason": "unsure", "confidence": 0.4}
+		]}`},
+	}
+	pairs := []ConflictPair{
+		makePair(0, 100, 200, "old", "new"),
+		makePair(1, 300, 400, "a", "b"),
+		makePair(2, 500, 600, "c", "d"),
+	}
+
+	var applied []ConflictResolution
+	opts := ConsensusOpts{ResolveOpts: ResolveOpts{Concurrency: 2, BatchSize: 5, OnBatch: func(_ []ConflictPair, rs []ConflictResolution) error {
+		applied = append(applied, rs...)
+		return nil
+	}}}
+	result, err := ResolveConflictsConsensus(context.Background(), providers, pairs, opts)
+	if err != nil {
+		t.Fatalf("ResolveConflictsConsensus: %v", err)
+	}
+	if result.Model != "m1,m2,m3" || result.Superseded != 1 || result.Merged != 1 || result.Flagged != 1 || len(applied) != 3 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	sup := applied[0]
+	if sup.Action != "supersede" || sup.WinnerID != 200 || sup.LoserID != 100 || sup.PairIndex != 0 || len(sup.Votes) != 3 {
+		t.Fatalf("pair 0 = %+v", sup)
+	}
+	if sup.Confidence < 0.849 || sup.Confidence > 0.851 || !strings.HasPrefix(sup.Reason, "2 of 3 models agree") {
+		t.Fatalf("pair 0 confidence/reason = %.3f %q", sup.Confidence, sup.Reason)
+	}
+	if flag := applied[1]; flag.Action != "flag-human" || !strings.Contains(flag.Reason, "models disagree") || !strings.Contains(flag.Reason, "m3: merge") {
+		t.Fatalf("pair 1 = %+v", flag)
+	}
+	if merge := applied[2]; merge.Action != "merge" || merge.MergedFact == nil || merge.MergedFact.Object != "both" || merge.MergedFact.FactType != "kv" {
+		t.Fatalf("pair 2 = %+v", merge)
+	}
+
+	// Unanimity turns the 2-of-3 calls into flags.
+	result, err = ResolveConflictsConsensus(context.Background(), providers, pairs, ConsensusOpts{Quorum: 3})
+	if err != nil || result.Flagged != 3 {
+		t.Fatalf("unanimous quorum: %+v, %v", result, err)
+	}
+}
+
+func TestResolveConflictsConsensus_FailedModelAbstains(t *testing.T) {
+	usePromptDir(t)
+	agree := `{"resolutions": [{"pair_index": 0, "action": "supersede", "winner_id": 200, "loser_id": 100, "reason": "newer", "confidence": 0.9}]}`
+	providers := []llm.Provider{
+		&mockResolveProvider{name: "m1", response: agree},
+		&mockResolveProvider{name: "m2", err: fmt.Errorf("model not found")},
+		&mockResolveProvider{name: "m3", response: agree},
+	}
+	pairs := []ConflictPair{makePair(0, 100, 200, "old", "new")}
+
+	result, err := ResolveConflictsConsensus(context.Background(), providers, pairs, ConsensusOpts{})
+	if err != nil {
+		t.Fatalf("ResolveConflictsConsensus: %v", err)
+	}
+	if result.Superseded != 1 || result.Resolutions[0].Votes[1].Action != "" {
+		t.Fatalf("two agreeing models should still reach a majority of three: %+v", result.Resolutions)
+	}
+
+	// With two of three failing, the lone answer falls short.
+	providers[2] = &mockResolveProvider{name: "m3", err: fmt.Errorf("model not found")}
+	result, err = ResolveConflictsConsensus(context.Background(), providers, pairs, ConsensusOpts{})
+	if err != nil || result.Flagged != 1 || !strings.Contains(result.Resolutions[0].Reason, "m2: no answer") {
+		t.Fatalf("lone answer: %+v, %v", result, err)
+	}
+}
+
+func TestResolveConflictsConsensus_Validation(t *testing.T) {
+	one := []llm.Provider{&mockResolveProvider{}}
+	if _, err := ResolveConflictsConsensus(context.Background(), one, nil, ConsensusOpts{}); err == nil {
+		t.Fatal("expected error for a single model")
+	}
+	two := []llm.Provider{&mockResolveProvider{name: "a"}, &mockResolveProvider{name: "b"}}
+	if _, err := ResolveConflictsConsensus(context.Background(), two, nil, ConsensusOpts{Quorum: 3}); err == nil {
+		t.Fatal("expected error for a quorum larger than the model count")
+	}
+	if MajorityQuorum(2) != 2 || MajorityQuorum(3) != 2 || MajorityQuorum(4) != 3 {
+		t.Fatal("unexpected majority quorum")
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hurttlocker/cortex/internal/config"
 	"github.com/hurttlocker/cortex/internal/llm"
 	"github.com/hurttlocker/cortex/internal/temporal"
 )
@@ -29,6 +30,12 @@ const (
 	// enrichMaxRuleFacts caps how many rule facts are included in the prompt.
 	enrichMaxRuleFacts = 30
 
+	// MaxEnrichExamples caps the few-shot examples included in the prompt.
+	MaxEnrichExamples = 3
+
+	// enrichMaxExampleLen caps each example's content in the prompt.
+	enrichMaxExampleLen = 1200
+
 	// DefaultEnrichModel is the recommended model for enrichment (best at finding new facts).
 	// Benchmarked Feb 2026: Grok 4.1 Fast found +26 facts across 3 files; all others found ≤9.
 	DefaultEnrichModel = "openrouter/x-ai/grok-4.1-fast"
@@ -102,44 +109,53 @@ type enrichFact struct {
 	TemporalNorm *temporal.Norm  `json:"temporal_norm,omitempty"`
 }
 
+// FewShotExample is a curated (content → facts) pair shown to the LLM so it
+// matches the extraction style and granularity wanted for a domain.
+type FewShotExample struct {
+	Content string          `json:"content"`
+	Facts   []ExtractedFact `json:"facts"`
+}
+
 // EnrichFacts uses an LLM to find facts that rule-based extraction missed.
 // It is additive-only: ruleFacts are never modified or removed.
 // On LLM error, returns nil (graceful fallback to rule-only).
 func EnrichFacts(ctx context.Context, provider llm.Provider, chunk string, ruleFacts []ExtractedFact, anchor string) (*EnrichResult, error) {
+	return EnrichFactsWithExamples(ctx, provider, chunk, ruleFacts, anchor, nil)
+}
+
+// EnrichFactsWithExamples is EnrichFacts with few-shot examples (at most
+// MaxEnrichExamples are used) prepended to the prompt.
+func EnrichFactsWithExamples(ctx context.Context, provider llm.Provider, chunk string, ruleFacts []ExtractedFact, anchor string, examples []FewShotExample) (*EnrichResult, error) {
 	if provider == nil {
 		return nil, fmt.Errorf("LLM provider is nil")
 	}
 
 	start := time.Now()
 
-	// Truncate chunk if too long
-	truncatedChunk := chunk
-	if len(truncatedChunk) > enrichMaxChunkLen {
-		truncatedChunk = truncateAtWordBoundary(truncatedChunk, enrichMaxChunkLen)
+	// Build the prompt from the (truncated) chunk and existing facts context
+	prompt, err := buildEnrichPrompt(truncateChunkForEnrich(chunk), ruleFacts, anchor, examples...)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build the user prompt with existing facts context
-	prompt := buildEnrichPrompt(truncatedChunk, ruleFacts, anchor)
-
 	// Call LLM with timeout
 	enrichCtx, cancel := context.WithTimeout(ctx, enrichTimeout)
 	defer cancel()
 
-	response, err := provider.Complete(enrichCtx, prompt, llm.CompletionOpts{
+	var parsed *enrichResponse
+	err = llm.CompleteStructured(enrichCtx, provider, prompt.User, llm.CompletionOpts{
 		Temperature: 0.1,
 		MaxTokens:   8192,
-		System:      enrichSystemPrompt,
+		System:      prompt.System,
+		Task:        llm.TaskEnrich,
+	}, func(reply string) (err error) {
+		parsed, err = parseEnrichResponse(reply)
+		return err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("LLM enrichment call failed: %w", err)
 	}
 
-	// Parse response
-	parsed, err := parseEnrichResponse(response)
-	if err != nil {
-		return nil, fmt.Errorf("parsing enrichment response: %w", err)
-	}
-
 	// Convert to ExtractedFact and validate
 	newFacts := make([]ExtractedFact, 0, len(parsed.Facts))
 	for _, f := range parsed.Facts {
@@ -153,6 +169,7 @@ func EnrichFacts(ctx context.Context, provider llm.Provider, chunk string, ruleF
 			ExtractionMethod: "llm-enrich",
 			TemporalNorm:     f.TemporalNorm,
 		}
+		ef.Negated = IsNegated(ef.Predicate, ef.Object)
 
 		// Validate
 		if ef.Predicate == "" || ef.Object == "" {
@@ -196,68 +213,18 @@ func EnrichFacts(ctx context.Context, provider llm.Provider, chunk string, ruleF
 	}, nil
 }
 
-// buildEnrichPrompt constructs the user message with chunk + existing facts.
-func buildEnrichPrompt(chunk string, ruleFacts []ExtractedFact, anchor string) string {
-	var sb strings.Builder
-
-	sb.WriteString("SESSION ANCHOR DATE:\n")
-	sb.WriteString(anchor)
-	sb.WriteString("\n\n")
-	sb.WriteString("TEXT CHUNK:\n---\n")
-	sb.WriteString(chunk)
-	sb.WriteString("\n---\n\n")
-
-	if len(ruleFacts) > 0 {
-		sb.WriteString("EXISTING FACTS (already extracted by rules — do NOT duplicate these):\n")
-		limit := len(ruleFacts)
-		if limit > enrichMaxRuleFacts {
-			limit = enrichMaxRuleFacts
-		}
-		for i := 0; i < limit; i++ {
-			f := ruleFacts[i]
-			sb.WriteString(fmt.Sprintf("- [%s] %s → %s → %s (%.1f)\n",
-				f.FactType, f.Subject, f.Predicate, f.Object, f.Confidence))
-		}
-		if len(ruleFacts) > enrichMaxRuleFacts {
-			sb.WriteString(fmt.Sprintf("... and %d more facts\n", len(ruleFacts)-enrichMaxRuleFacts))
-		}
-		sb.WriteString("\n")
-	} else {
-		sb.WriteString("EXISTING FACTS: none (rules found nothing — look carefully for any facts)\n\n")
-	}
-
-	sb.WriteString("Find additional facts the rules missed. Return JSON only.")
-	return sb.String()
+// buildEnrichPrompt renders the enrich prompt (system + user) for a chunk
+// and its rule-extracted facts, applying any template overrides.
+func buildEnrichPrompt(chunk string, ruleFacts []ExtractedFact, anchor string, examples ...FewShotExample) (*RenderedPrompt, error) {
+	data := newEnrichPromptData(chunk, ruleFacts, anchor)
+	data.Examples = capEnrichExamples(examples)
+	return RenderPrompt(PromptEnrich, data)
 }
 
-// parseEnrichResponse parses the LLM's JSON (with markdown stripping).
+// parseEnrichResponse parses the LLM's JSON (see llm.RepairJSON).
 func parseEnrichResponse(raw string) (*enrichResponse, error) {
-	cleaned := strings.TrimSpace(raw)
-
-	// Strip markdown code fences if present
-	if strings.HasPrefix(cleaned, "```") {
-		lines := strings.Split(cleaned, "\n")
-		// Find opening and closing fence
-		start, end := 0, len(lines)
-		for i, line := range lines {
-			if strings.HasPrefix(strings.TrimSpace(line), "```") {
-				if start == 0 {
-					start = i + 1
-				} else {
-					end = i
-					break
-				}
-			}
-		}
-		if start > 0 && end > start {
-			cleaned = strings.Join(lines[start:end], "\n")
-		}
-	}
-
-	cleaned = strings.TrimSpace(cleaned)
-
 	var resp enrichResponse
-	if err := json.Unmarshal([]byte(cleaned), &resp); err != nil {
+	if err := llm.DecodeJSON(raw, &resp); err != nil {
 		return nil, fmt.Errorf("invalid JSON from LLM: %w\nraw response: %s", err, truncateForError(raw, 300))
 	}
 
@@ -340,12 +307,12 @@ func isDuplicateOfRuleFact(candidate ExtractedFact, ruleFacts []ExtractedFact) b
 
 // isValidFactType checks if a fact type is one of the recognized types.
 func isValidFactType(ft string) bool {
-	switch ft {
-	case "kv", "relationship", "preference", "temporal", "identity", "location", "decision", "state", "config":
-		return true
-	default:
-		return false
+	for _, t := range config.BuiltinFactTypes {
+		if ft == t {
+			return true
+		}
 	}
+	return false
 }
 
 // truncateForError truncates a string for error messages.
@@ -13,10 +13,11 @@ import (
 
 // mockEnrichProvider implements llm.Provider for testing enrichment.
 type mockEnrichProvider struct {
-	response string
-	err      error
-	calls    int
-	lastOpts llm.CompletionOpts
+	response  string
+	responses []string // returned in order before response, when set
+	err       error
+	calls     int
+	lastOpts  llm.CompletionOpts
 }
 
 func (m *mockEnrichProvider) Complete(_ context.Context, prompt string, opts llm.CompletionOpts) (string, error) {
@@ -25,6 +26,9 @@ func (m *mockEnrichProvider) Complete(_ context.Context, prompt string, opts llm
 	if m.err != nil {
 		return "", m.err
 	}
+	if m.calls <= len(m.responses) {
+		return m.responses[m.calls-1], nil
+	}
 	return m.response, nil
 }
 
@@ -323,6 +327,21 @@ func TestEnrichFacts_MarkdownFencedResponse(t *testing.T) {
 	}
 }
 
+func TestEnrichFacts_ReasksOnceOnInvalidJSON(t *testing.T) {
+	provider := &mockEnrichProvider{responses: []string{
+		`{"facts": [{"subject": "Cortex", "predicate": "written in"`, // cut off
+		`{"facts": [{"subject": "Cortex", "predicate": "written in", "object": "Go", "type": "kv", "confidence": 0.9,},],}`,
+	}}
+
+	result, err := EnrichFacts(context.Background(), provider, "Cortex is written in Go", nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.calls != 2 || len(result.NewFacts) != 1 {
+		t.Fatalf("calls = %d, facts = %d; want the reply re-asked once and repaired", provider.calls, len(result.NewFacts))
+	}
+}
+
 func TestEnrichFacts_LongSubjectTruncated(t *testing.T) {
 	longSubject := strings.Repeat("A", 100) // Way over MaxSubjectLength
 	response := fmt.Sprintf(`{
@@ -421,7 +440,11 @@ func TestBuildEnrichPrompt_WithFacts(t *testing.T) {
 		{Subject: "SB", Predicate: "role", Object: "co-founder", FactType: "relationship"},
 	}
 
-	prompt := buildEnrichPrompt("Q and SB founded Spear in Philadelphia.", facts, "")
+	rendered, err := buildEnrichPrompt("Q and SB founded Spear in Philadelphia.", facts, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prompt := rendered.User
 
 	if !strings.Contains(prompt, "TEXT CHUNK:") {
 		t.Error("prompt should contain TEXT CHUNK header")
@@ -438,7 +461,11 @@ func TestBuildEnrichPrompt_WithFacts(t *testing.T) {
 }
 
 func TestBuildEnrichPrompt_NoFacts(t *testing.T) {
-	prompt := buildEnrichPrompt("Some text.", nil, "")
+	rendered, err := buildEnrichPrompt("Some text.", nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prompt := rendered.User
 
 	if !strings.Contains(prompt, "none") {
 		t.Error("prompt should indicate no existing facts")
@@ -453,7 +480,11 @@ func TestBuildEnrichPrompt_ManyFacts_Truncates(t *testing.T) {
 		}
 	}
 
-	prompt := buildEnrichPrompt("text", facts, "")
+	rendered, err := buildEnrichPrompt("text", facts, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prompt := rendered.User
 
 	if !strings.Contains(prompt, "and 20 more facts") {
 		t.Error("prompt should indicate truncated facts")
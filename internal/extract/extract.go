@@ -19,6 +19,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/hurttlocker/cortex/internal/llm"
 	"github.com/hurttlocker/cortex/internal/temporal"
 )
 
@@ -33,6 +34,7 @@ type ExtractedFact struct {
 	ExtractionMethod string         `json:"extraction_method"` // always "rules" for Tier 1
 	DecayRate        float64        `json:"decay_rate"`        // Assigned based on fact type
 	TemporalNorm     *temporal.Norm `json:"temporal_norm,omitempty"`
+	Negated          bool           `json:"negated,omitempty"` // states the opposite of its positive form ("is not enabled")
 }
 
 // BaseDecayRates holds the built-in default decay rates by fact type.
@@ -112,6 +114,12 @@ func NewPipeline(llmConfig ...*LLMConfig) *Pipeline {
 	return p
 }
 
+// ScrubReport returns what the pipeline's LLM client has masked from
+// prompts so far (empty without a remote LLM).
+func (p *Pipeline) ScrubReport() llm.ScrubReport {
+	return p.llmClient.ScrubReport()
+}
+
 // NewPipelineWithOptions creates a pipeline with functional options.
 func NewPipelineWithOptions(opts ...PipelineOption) *Pipeline {
 	p := &Pipeline{
@@ -380,6 +388,9 @@ func (p *Pipeline) Extract(ctx context.Context, text string, metadata map[string
 	// 4. Merge and deduplicate facts from both tiers
 	allFacts := append(facts, llmFacts...)
 	allFacts = deduplicateFacts(allFacts)
+	for i := range allFacts {
+		allFacts[i].Negated = IsNegated(allFacts[i].Predicate, allFacts[i].Object)
+	}
 
 	// 5. Quality governor: filter noise, rank, and cap
 	// Use stricter governor for auto-capture sources (conversation transcripts)
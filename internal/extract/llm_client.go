@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hurttlocker/cortex/internal/llm"
 	"github.com/hurttlocker/cortex/internal/temporal"
 )
 
@@ -98,8 +99,9 @@ Output:
 
 // LLMClient handles communication with OpenAI-compatible APIs.
 type LLMClient struct {
-	config LLMConfig
-	http   *http.Client
+	config   LLMConfig
+	http     *http.Client
+	scrubber *llm.Scrubber // masks secrets for remote endpoints; nil when local
 }
 
 // ChatRequest represents an OpenAI-compatible chat completion request.
@@ -140,12 +142,25 @@ type LLMExtractionResponse struct {
 
 // NewLLMClient creates a new LLM client with the given configuration.
 func NewLLMClient(config *LLMConfig) *LLMClient {
-	return &LLMClient{
+	c := &LLMClient{
 		config: *config,
 		http: &http.Client{
 			Timeout: time.Duration(config.TimeoutSecs) * time.Second,
 		},
 	}
+	if !config.IsLocal() {
+		c.scrubber = llm.NewScrubber()
+	}
+	return c
+}
+
+// ScrubReport returns what has been masked from prompts sent by this client.
+// The report is empty for local endpoints, which are never scrubbed.
+func (c *LLMClient) ScrubReport() llm.ScrubReport {
+	if c == nil || c.scrubber == nil {
+		return llm.ScrubReport{}
+	}
+	return c.scrubber.Report()
 }
 
 // Extract extracts facts from text using the configured LLM.
@@ -275,6 +290,19 @@ func (c *LLMClient) attemptExtraction(ctx context.Context, req ChatRequest) ([]E
 
 // sendChatRequest sends a chat completion request to the LLM API.
 func (c *LLMClient) sendChatRequest(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	// Mask secrets before they leave the machine
+	if c.scrubber != nil {
+		contents := make([]string, len(req.Messages))
+		for i, m := range req.Messages {
+			contents[i] = m.Content
+		}
+		contents = c.scrubber.ScrubParts(contents...)
+		req.Messages = append([]ChatMessage(nil), req.Messages...)
+		for i := range req.Messages {
+			req.Messages[i].Content = contents[i]
+		}
+	}
+
 	// Marshal request
 	requestBody, err := json.Marshal(req)
 	if err != nil {
@@ -333,16 +361,19 @@ func (c *LLMClient) sendChatRequest(ctx context.Context, req ChatRequest) (*Chat
 	if err := json.Unmarshal(body, &chatResp); err != nil {
 		return nil, fmt.Errorf("parsing response JSON: %w", err)
 	}
+	if c.scrubber != nil {
+		for i := range chatResp.Choices {
+			chatResp.Choices[i].Message.Content = c.scrubber.RestoreJSON(chatResp.Choices[i].Message.Content)
+		}
+	}
 
 	return &chatResp, nil
 }
 
 // parseExtractionResponse parses the LLM's JSON response into facts.
 func (c *LLMClient) parseExtractionResponse(content string) ([]ExtractedFact, error) {
-	content = strings.TrimSpace(content)
-
 	var response LLMExtractionResponse
-	if err := json.Unmarshal([]byte(content), &response); err != nil {
+	if err := llm.DecodeJSON(content, &response); err != nil {
 		return nil, fmt.Errorf("invalid JSON: %w", err)
 	}
 
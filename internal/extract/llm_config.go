@@ -2,10 +2,12 @@ package extract
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
 
 	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/llm"
 )
 
 // LLMConfig holds LLM provider configuration.
@@ -102,6 +104,11 @@ func ResolveLLMConfig(cliFlag string) (*LLMConfig, error) {
 	if candidate == "" {
 		return nil, nil
 	}
+	// Routed models are only available through internal/llm (enrichment);
+	// the legacy tier-2 chat path needs a fixed endpoint, so it is skipped.
+	if strings.EqualFold(candidate, llm.AutoProvider) {
+		return nil, nil
+	}
 
 	candidate = normalizeProviderModel(candidate, "openrouter/x-ai/grok-4.1-fast")
 	config, err := ParseLLMFlag(candidate)
@@ -183,3 +190,20 @@ func (c *LLMConfig) Validate() error {
 
 	return nil
 }
+
+// IsLocal reports whether the endpoint runs on this machine, so content from
+// local-llm-only projects may be sent to it.
+func (c *LLMConfig) IsLocal() bool {
+	if c == nil {
+		return false
+	}
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Hostname()) {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
 )
 
 // Mock LLM server helpers
@@ -261,6 +263,28 @@ func TestParseLLMFlag_Empty(t *testing.T) {
 	}
 }
 
+func TestLLMConfig_IsLocal(t *testing.T) {
+	cases := []struct {
+		endpoint string
+		want     bool
+	}{
+		{"http://localhost:11434/v1/chat/completions", true},
+		{"http://127.0.0.1:8080/v1/chat/completions", true},
+		{"http://[::1]:11434/v1/chat/completions", true},
+		{"https://openrouter.ai/api/v1/chat/completions", false},
+		{"https://localhost.example.com/v1", false},
+		{"", false},
+	}
+	for _, tc := range cases {
+		if got := (&LLMConfig{Endpoint: tc.endpoint}).IsLocal(); got != tc.want {
+			t.Errorf("IsLocal(%q) = %v, want %v", tc.endpoint, got, tc.want)
+		}
+	}
+	if (*LLMConfig)(nil).IsLocal() {
+		t.Error("nil config should not be local")
+	}
+}
+
 func TestParseLLMFlag_EnvironmentOverrides(t *testing.T) {
 	os.Setenv("CORTEX_LLM_ENDPOINT", "custom-endpoint")
 	os.Setenv("CORTEX_LLM_API_KEY", "custom-key")
@@ -369,6 +393,50 @@ func TestLLMClient_ValidResponse(t *testing.T) {
 	}
 }
 
+func TestLLMClient_ScrubsSecretsForRemoteEndpoints(t *testing.T) {
+	var sent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		for _, m := range req.Messages {
+			sent += m.Content
+		}
+		resp := ChatResponse{
+			Choices: []struct {
+				Message ChatMessage `json:"message"`
+			}{
+				{Message: ChatMessage{Role: "assistant", Content: strings.ReplaceAll(validFactsResponse, "alice@company.com", "[REDACTED_EMAIL_1]")}},
+			},
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	if NewLLMClient(&LLMConfig{Endpoint: server.URL}).scrubber != nil {
+		t.Fatal("local endpoints should not be scrubbed")
+	}
+	if NewLLMClient(&LLMConfig{Endpoint: "https://openrouter.ai/api/v1/chat/completions"}).scrubber == nil {
+		t.Fatal("remote endpoints should be scrubbed")
+	}
+
+	client := NewLLMClient(&LLMConfig{Provider: "openrouter", Model: "test", Endpoint: server.URL, ContextWindow: 4096, MaxRetries: 1, TimeoutSecs: 30})
+	client.scrubber = llm.NewScrubber() // the test server is local; scrub as if remote
+
+	facts, err := client.Extract(context.Background(), "Alice (alice@company.com) is the project manager", nil)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if strings.Contains(sent, "alice@company.com") || !strings.Contains(sent, "[REDACTED_EMAIL_1]") {
+		t.Fatalf("email reached the endpoint unmasked")
+	}
+	if len(facts) == 0 || facts[0].Object != "alice@company.com" {
+		t.Fatalf("placeholder not restored in facts: %+v", facts)
+	}
+	if r := client.ScrubReport(); r.ByKind["email"] != 1 {
+		t.Fatalf("unexpected scrub report: %+v", r)
+	}
+}
+
 func TestLLMClient_InvalidJSON(t *testing.T) {
 	server := newMockLLMServer(t, invalidJSONResponse)
 	defer server.Close()
@@ -0,0 +1,122 @@
+package extract
+
+import "strings"
+
+// negatedAuxiliaries maps negated auxiliaries to their positive form.
+var negatedAuxiliaries = map[string]string{
+	"isn't":     "is",
+	"aren't":    "are",
+	"wasn't":    "was",
+	"weren't":   "were",
+	"hasn't":    "has",
+	"haven't":   "have",
+	"hadn't":    "had",
+	"can't":     "can",
+	"cannot":    "can",
+	"won't":     "will",
+	"wouldn't":  "would",
+	"shouldn't": "should",
+	"couldn't":  "could",
+}
+
+// doSupport marks tokens whose negation drops the auxiliary and inflects
+// the verb that follows ("does not use" → "uses").
+var doSupport = map[string]bool{
+	"does": true, "do": true, "did": true,
+	"doesn't": true, "don't": true, "didn't": true,
+}
+
+// ParseNegation splits a (predicate, object) pair into its positive form and
+// polarity. "is not" + "enabled", "is" + "not enabled", and "doesn't use" +
+// "redis" all come back negated, as "is enabled" / "uses redis". The positive
+// form is lowercased with collapsed whitespace so it can be compared against
+// other facts directly; pairs without a negation are returned normalized and
+// with negated=false. Only the first negation in each part counts, and a
+// negated predicate with a negated object cancels out.
+func ParseNegation(predicate, object string) (posPredicate, posObject string, negated bool) {
+	predTokens := strings.Fields(strings.ToLower(strings.NewReplacer("_", " ", "’", "'").Replace(predicate)))
+	predTokens, predNeg := stripPredicateNegation(predTokens)
+
+	objTokens := strings.Fields(strings.ToLower(strings.ReplaceAll(object, "’", "'")))
+	objNeg := false
+	switch {
+	case len(objTokens) > 1 && (objTokens[0] == "not" || objTokens[0] == "never"):
+		objTokens, objNeg = objTokens[1:], true
+	case len(objTokens) > 2 && objTokens[0] == "no" && objTokens[1] == "longer":
+		objTokens, objNeg = objTokens[2:], true
+	}
+
+	return strings.Join(predTokens, " "), strings.Join(objTokens, " "), predNeg != objNeg
+}
+
+// IsNegated reports whether the fact states a negation.
+func IsNegated(predicate, object string) bool {
+	_, _, negated := ParseNegation(predicate, object)
+	return negated
+}
+
+func stripPredicateNegation(tokens []string) ([]string, bool) {
+	for i, tok := range tokens {
+		if positive, ok := negatedAuxiliaries[tok]; ok {
+			out := append(append([]string{}, tokens[:i]...), positive)
+			return append(out, tokens[i+1:]...), true
+		}
+		var skip int
+		switch {
+		case tok == "not" || tok == "never":
+			skip = 1
+		case tok == "no" && i+1 < len(tokens) && tokens[i+1] == "longer":
+			skip = 2
+		case tok == "doesn't" || tok == "don't" || tok == "didn't":
+			return inflectAfterDoSupport(tokens[:i], tokens[i+1:]), true
+		default:
+			continue
+		}
+		rest := tokens[i+skip:]
+		if tok == "not" && i > 0 && doSupport[tokens[i-1]] {
+			return inflectAfterDoSupport(tokens[:i-1], rest), true
+		}
+		if len(rest) == 0 && i == 0 {
+			// A bare "not" predicate has no positive counterpart.
+			return tokens, false
+		}
+		out := append([]string{}, tokens[:i]...)
+		return append(out, rest...), true
+	}
+	return tokens, false
+}
+
+func inflectAfterDoSupport(head, rest []string) []string {
+	out := append([]string{}, head...)
+	if len(rest) == 0 {
+		return append(out, "does")
+	}
+	out = append(out, thirdPersonSingular(rest[0]))
+	return append(out, rest[1:]...)
+}
+
+// thirdPersonSingular inflects a base-form verb ("use" → "uses").
+func thirdPersonSingular(verb string) string {
+	switch verb {
+	case "be":
+		return "is"
+	case "have":
+		return "has"
+	case "do":
+		return "does"
+	case "go":
+		return "goes"
+	}
+	if verb == "" || strings.HasSuffix(verb, "s") && !strings.HasSuffix(verb, "ss") {
+		return verb
+	}
+	for _, suffix := range []string{"ss", "sh", "ch", "x", "z"} {
+		if strings.HasSuffix(verb, suffix) {
+			return verb + "es"
+		}
+	}
+	if n := len(verb); n > 1 && verb[n-1] == 'y' && !strings.ContainsRune("aeiou", rune(verb[n-2])) {
+		return verb[:n-1] + "ies"
+	}
+	return verb + "s"
+}
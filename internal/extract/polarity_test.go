@@ -0,0 +1,55 @@
+package extract
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseNegation(t *testing.T) {
+	cases := []struct {
+		predicate, object string
+		wantPred, wantObj string
+		negated           bool
+	}{
+		{"is", "enabled", "is", "enabled", false},
+		{"is not", "enabled", "is", "enabled", true},
+		{"Is_Not", "Enabled", "is", "enabled", true},
+		{"isn't", "enabled", "is", "enabled", true},
+		{"is", "not enabled", "is", "enabled", true},
+		{"status", "no longer active", "status", "active", true},
+		{"does not use", "redis", "uses", "redis", true},
+		{"doesn’t use", "redis", "uses", "redis", true},
+		{"don't have", "access", "has", "access", true},
+		{"did not approve", "budget", "approves", "budget", true},
+		{"never watches", "tv", "watches", "tv", true},
+		{"no longer works at", "Acme", "works at", "acme", true},
+		{"cannot", "swim", "can", "swim", true},
+		{"is not", "not enabled", "is", "enabled", false}, // double negation
+		{"not", "applicable", "not", "applicable", false},
+		{"notes", "draft", "notes", "draft", false},
+	}
+	for _, tc := range cases {
+		pred, obj, neg := ParseNegation(tc.predicate, tc.object)
+		if pred != tc.wantPred || obj != tc.wantObj || neg != tc.negated {
+			t.Errorf("ParseNegation(%q, %q) = (%q, %q, %v), want (%q, %q, %v)",
+				tc.predicate, tc.object, pred, obj, neg, tc.wantPred, tc.wantObj, tc.negated)
+		}
+	}
+}
+
+func TestExtract_SetsNegatedFlag(t *testing.T) {
+	facts, err := NewPipeline().Extract(context.Background(), "**Broker:** TradeStation\n**Dark Mode:** not enabled", map[string]string{"source_file": "/tmp/settings.md"})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, f := range facts {
+		seen[f.Object] = f.Negated
+	}
+	if neg, ok := seen["not enabled"]; !ok || !neg {
+		t.Fatalf("expected negated status fact, got %+v", facts)
+	}
+	if seen["TradeStation"] {
+		t.Fatalf("positive fact flagged as negated: %+v", facts)
+	}
+}
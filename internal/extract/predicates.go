@@ -0,0 +1,127 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+const predicatesTimeout = 60 * time.Second
+
+// MaxPredicateSuggestionInput caps how many distinct predicates are sent to
+// the LLM in one mapping request.
+const MaxPredicateSuggestionInput = 200
+
+const predicatesSystemPrompt = `You normalize the predicate vocabulary of a personal knowledge graph. Facts are (subject, predicate, object) triples, and the same relation is often written several ways ("uses", "is using", "utilizes").
+
+Map each predicate that is a paraphrase of another predicate to a single canonical form.
+
+RULES:
+- Prefer a canonical predicate that already appears in the list, usually the most frequent phrasing
+- Canonical predicates are short, lowercase, present tense, without auxiliaries ("uses", not "is using")
+- Only merge predicates with the SAME meaning and direction ("owns" and "owned by" are different)
+- Leave predicates alone when unsure; omit predicates that are already canonical
+- Never map a predicate to itself
+
+Return ONLY a JSON object:
+{
+  "aliases": [
+    {"from": "is using", "to": "uses"},
+    {"from": "works for", "to": "works at"}
+  ]
+}`
+
+const predicatesUserTemplate = `{{if .Canonical}}CANONICAL PREDICATES ALREADY IN USE (map onto these where they fit):
+{{range .Canonical}}- {{.}}
+{{end}}
+{{end}}PREDICATES (with fact counts):
+{{range .Predicates}}- {{.Predicate}} ({{.Facts}})
+{{end}}
+Return JSON only.`
+
+// PredicateCount is a predicate and how many facts use it.
+type PredicateCount struct {
+	Predicate string `json:"predicate"`
+	Facts     int    `json:"facts"`
+}
+
+// PredicatesPromptData is the data for the predicates templates.
+type PredicatesPromptData struct {
+	Canonical  []string         `json:"canonical"`
+	Predicates []PredicateCount `json:"predicates"`
+}
+
+type predicateAliasEntry struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type predicatesResponse struct {
+	Aliases []predicateAliasEntry `json:"aliases"`
+}
+
+// SuggestPredicateAliases asks the LLM which of predicates are paraphrases
+// and returns alias → canonical mappings. canonical lists predicates the
+// rule table already maps onto, so suggestions converge on them. Mappings
+// for predicates that were not in the input, or onto themselves, are dropped.
+func SuggestPredicateAliases(ctx context.Context, provider llm.Provider, predicates []PredicateCount, canonical []string) (map[string]string, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("LLM provider is required")
+	}
+	if len(predicates) == 0 {
+		return map[string]string{}, nil
+	}
+	if len(predicates) > MaxPredicateSuggestionInput {
+		predicates = predicates[:MaxPredicateSuggestionInput]
+	}
+	canonical = append([]string(nil), canonical...)
+	sort.Strings(canonical)
+
+	prompt, err := RenderPrompt(PromptPredicates, PredicatesPromptData{Canonical: canonical, Predicates: predicates})
+	if err != nil {
+		return nil, err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, predicatesTimeout)
+	defer cancel()
+	var entries []predicateAliasEntry
+	err = llm.CompleteStructured(callCtx, provider, prompt.User, llm.CompletionOpts{
+		Temperature: 0.1,
+		MaxTokens:   2048,
+		System:      prompt.System,
+		Task:        llm.TaskClassify,
+	}, func(reply string) (err error) {
+		entries, err = parsePredicatesResponse(reply)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LLM predicate mapping call: %w", err)
+	}
+	known := make(map[string]bool, len(predicates))
+	for _, p := range predicates {
+		known[strings.ToLower(strings.TrimSpace(p.Predicate))] = true
+	}
+	out := map[string]string{}
+	for _, e := range entries {
+		from := strings.TrimSpace(e.From)
+		to := strings.TrimSpace(e.To)
+		if from == "" || to == "" || strings.EqualFold(from, to) || !known[strings.ToLower(from)] {
+			continue
+		}
+		out[from] = to
+	}
+	return out, nil
+}
+
+// parsePredicatesResponse parses the LLM's JSON (see llm.RepairJSON).
+func parsePredicatesResponse(raw string) ([]predicateAliasEntry, error) {
+	var resp predicatesResponse
+	if err := llm.DecodeJSON(raw, &resp); err != nil {
+		return nil, fmt.Errorf("invalid JSON from LLM: %w\nraw: %s", err, truncateForError(raw, 300))
+	}
+	return resp.Aliases, nil
+}
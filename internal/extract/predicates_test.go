@@ -0,0 +1,43 @@
+package extract
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSuggestPredicateAliases_FiltersMappings(t *testing.T) {
+	usePromptDir(t)
+	provider := &mockSummarizeProvider{response: "```json\n" + `{"aliases": [
+		{"from": "leverages", "to": "uses"},
+		{"from": "Harnesses", "to": "uses"},
+		{"from": "uses", "to": "uses"},
+		{"from": "invented", "to": "created by"},
+		{"from": "", "to": "uses"}
+	]}` + "\n```"}
+
+	predicates := []PredicateCount{{"uses", 12}, {"leverages", 3}, {"harnesses", 1}}
+	got, err := SuggestPredicateAliases(context.Background(), provider, predicates, []string{"works at", "uses"})
+	if err != nil {
+		t.Fatalf("SuggestPredicateAliases: %v", err)
+	}
+	if len(got) != 2 || got["leverages"] != "uses" || got["Harnesses"] != "uses" {
+		t.Fatalf("expected only mappings for known, non-identical predicates, got %+v", got)
+	}
+
+	rendered, err := RenderPrompt(PromptPredicates, PredicatesPromptData{Canonical: []string{"uses"}, Predicates: predicates})
+	if err != nil {
+		t.Fatalf("RenderPrompt: %v", err)
+	}
+	if !strings.Contains(rendered.User, "- leverages (3)") || !strings.Contains(rendered.User, "CANONICAL PREDICATES") {
+		t.Fatalf("unexpected user prompt:\n%s", rendered.User)
+	}
+
+	empty := &mockSummarizeProvider{}
+	if got, err := SuggestPredicateAliases(context.Background(), empty, nil, nil); err != nil || len(got) != 0 || empty.calls != 0 {
+		t.Fatalf("no predicates should skip the LLM call, got %+v, %v, %d calls", got, err, empty.calls)
+	}
+	if _, err := SuggestPredicateAliases(context.Background(), &mockSummarizeProvider{response: "not json"}, predicates, nil); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
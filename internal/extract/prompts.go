@@ -0,0 +1,536 @@
+// Package extract — overridable prompt templates for the LLM passes.
+//
+// The system and user prompts for enrich, classify, resolve, summarize,
+// predicates, triage, translate, and canary are text/template templates. Built-in templates reproduce the stock
+// prompts; files in ~/.cortex/prompts/ replace them without recompiling:
+//
+//	~/.cortex/prompts/<name>.system.tmpl   system prompt
+//	~/.cortex/prompts/<name>.user.tmpl     user message
+//
+// Either file may be omitted, in which case the built-in is used for that half.
+package extract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+// Prompt template names.
+const (
+	PromptEnrich     = "enrich"
+	PromptClassify   = "classify"
+	PromptResolve    = "resolve"
+	PromptSummarize  = "summarize"
+	PromptPredicates = "predicates"
+	PromptTriage     = "triage"
+	PromptTranslate  = "translate"
+	PromptCanary     = "canary"
+)
+
+// PromptVariable documents one field available to a prompt template.
+type PromptVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// PromptSpec describes one overridable prompt: its built-in templates and
+// the data they are rendered with.
+type PromptSpec struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	System      string           `json:"system"`
+	User        string           `json:"user"`
+	Variables   []PromptVariable `json:"variables"`
+	Task        string           `json:"task"`       // llm.Task* passed to the provider
+	MaxTokens   int              `json:"max_tokens"` // completion budget used by the real pass
+
+	// decode builds template data from a JSON (or, for enrich, plain text)
+	// test input; used by `cortex prompts test`.
+	decode func(raw []byte) (interface{}, error)
+	// check parses a raw LLM response the way the real pass would and
+	// returns how many items it produced.
+	check func(raw string) (int, error)
+}
+
+// RenderedPrompt is a fully rendered system + user prompt pair.
+type RenderedPrompt struct {
+	Name         string `json:"name"`
+	System       string `json:"system"`
+	User         string `json:"user"`
+	SystemSource string `json:"system_source"` // "built-in" or override file path
+	UserSource   string `json:"user_source"`
+}
+
+// EnrichPromptData is the data for the enrich templates.
+type EnrichPromptData struct {
+	Examples         []FewShotExample `json:"examples"`
+	AnchorDate       string           `json:"anchor_date"`
+	Chunk            string           `json:"chunk"`
+	RuleFacts        []ExtractedFact  `json:"rule_facts"`
+	OmittedRuleFacts int              `json:"omitted_rule_facts"`
+}
+
+// ClassifyPromptData is the data for the classify templates.
+type ClassifyPromptData struct {
+	Types []TaxonomyType     `json:"types"`
+	Facts []ClassifyableFact `json:"facts"`
+}
+
+// ResolvePromptData is the data for the resolve templates.
+type ResolvePromptData struct {
+	Pairs []ConflictPair `json:"pairs"`
+}
+
+// SummarizePromptData is the data for the summarize templates.
+type SummarizePromptData struct {
+	ClusterName string             `json:"cluster_name"`
+	Facts       []ClusterFactInput `json:"facts"`
+}
+
+const enrichUserTemplate = `{{if .Examples}}CURATED EXAMPLES (text → the facts a careful extractor found; match this style and granularity):
+{{range $i, $e := .Examples}}--- EXAMPLE {{add $i 1}} ---
+{{$e.Content}}
+FACTS:
+{{range $e.Facts}}- [{{.FactType}}] {{.Subject}} → {{.Predicate}} → {{.Object}}
+{{end}}{{end}}
+{{end}}SESSION ANCHOR DATE:
+{{.AnchorDate}}
+
+TEXT CHUNK:
+---
+{{.Chunk}}
+---
+
+{{if .RuleFacts}}EXISTING FACTS (already extracted by rules — do NOT duplicate these):
+{{range .RuleFacts}}- [{{.FactType}}] {{.Subject}} → {{.Predicate}} → {{.Object}} ({{printf "%.1f" .Confidence}})
+{{end}}{{if .OmittedRuleFacts}}... and {{.OmittedRuleFacts}} more facts
+{{end}}
+{{else}}EXISTING FACTS: none (rules found nothing — look carefully for any facts)
+
+{{end}}Find additional facts the rules missed. Return JSON only.`
+
+const classifyUserTemplate = `Classify each fact into the correct type. Return JSON only.
+
+FACTS:
+{{range .Facts}}- id:{{.ID}} | {{or .Subject "(none)"}} → {{.Predicate}} → {{truncate 80 .Object}} (current: {{.FactType}})
+{{end}}`
+
+const resolveUserTemplate = `Resolve {{len .Pairs}} conflict pairs. Return JSON only.
+
+{{range $i, $p := .Pairs}}--- CONFLICT PAIR {{$i}} ---
+FACT A (id:{{$p.Fact1.ID}}): {{$p.Fact1.Subject}} → {{$p.Fact1.Predicate}} → {{truncate 120 $p.Fact1.Object}}
+  type:{{$p.Fact1.FactType}}, conf:{{printf "%.2f" $p.Fact1.Confidence}}, source:{{truncate 50 $p.Fact1.Source}}, created:{{date $p.Fact1.CreatedAt}}
+FACT B (id:{{$p.Fact2.ID}}): {{$p.Fact2.Subject}} → {{$p.Fact2.Predicate}} → {{truncate 120 $p.Fact2.Object}}
+  type:{{$p.Fact2.FactType}}, conf:{{printf "%.2f" $p.Fact2.Confidence}}, source:{{truncate 50 $p.Fact2.Source}}, created:{{date $p.Fact2.CreatedAt}}
+
+{{end}}`
+
+const summarizeUserTemplate = `CLUSTER: {{.ClusterName}} ({{len .Facts}} facts)
+
+FACTS:
+{{range .Facts}}- id:{{.ID}} [{{.FactType}}] {{or .Subject "(none)"}} → {{.Predicate}} → {{truncate 100 .Object}} (conf:{{printf "%.2f" .Confidence}}{{if .Source}}, src:{{truncate 40 .Source}}{{end}})
+{{end}}
+Consolidate redundant facts. Preserve all unique information. Return JSON only.`
+
+var promptFuncs = template.FuncMap{
+	"truncate": func(n int, s string) string { return truncateForPrompt(s, n) },
+	"add":      func(a, b int) int { return a + b },
+	"date":     func(t time.Time) string { return t.Format("2006-01-02") },
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"join":     strings.Join,
+}
+
+var promptSpecs = map[string]PromptSpec{
+	PromptEnrich: {
+		Name:        PromptEnrich,
+		Description: "LLM enrichment of rule-extracted facts during import (--enrich)",
+		System:      enrichSystemPrompt,
+		User:        enrichUserTemplate,
+		Task:        llm.TaskEnrich,
+		MaxTokens:   8192,
+		Variables: []PromptVariable{
+			{".Examples", "curated few-shot examples most similar to the chunk (max 3); each has .Content and .Facts (same fields as .RuleFacts)"},
+			{".AnchorDate", "session anchor date (YYYY-MM-DD) for resolving relative dates; may be empty"},
+			{".Chunk", "source text chunk, truncated to 3000 characters"},
+			{".RuleFacts", "facts found by rules (max 30); each has .Subject .Predicate .Object .FactType .Confidence .SourceQuote"},
+			{".OmittedRuleFacts", "number of rule facts left out of .RuleFacts"},
+		},
+		decode: decodeEnrichPromptInput,
+		check: func(raw string) (int, error) {
+			resp, err := parseEnrichResponse(raw)
+			if err != nil {
+				return 0, err
+			}
+			return len(resp.Facts), nil
+		},
+	},
+	PromptClassify: {
+		Name:        PromptClassify,
+		Description: "fact type reclassification (cortex classify)",
+		System:      classifySystemPrompt,
+		User:        classifyUserTemplate,
+		Task:        llm.TaskClassify,
+		MaxTokens:   2048,
+		Variables: []PromptVariable{
+			{".Types", "active taxonomy (built-in types plus extract.taxonomy); each has .Name .Description .Examples .Base"},
+			{".Facts", "batch of facts; each has .ID .Subject .Predicate .Object .FactType (current type)"},
+		},
+		decode: func(raw []byte) (interface{}, error) {
+			var data ClassifyPromptData
+			if err := json.Unmarshal(raw, &data); err != nil {
+				return nil, err
+			}
+			if len(data.Types) == 0 {
+				data.Types = Taxonomy()
+			}
+			return data, nil
+		},
+		check: func(raw string) (int, error) {
+			entries, err := parseClassifyResponse(raw)
+			return len(entries), err
+		},
+	},
+	PromptResolve: {
+		Name:        PromptResolve,
+		Description: "LLM conflict resolution (cortex conflicts --resolve llm)",
+		System:      resolveSystemPrompt,
+		User:        resolveUserTemplate,
+		Task:        llm.TaskResolve,
+		MaxTokens:   4096,
+		Variables: []PromptVariable{
+			{".Pairs", "conflict pairs; each has .Index .Fact1 .Fact2"},
+			{".Fact1 / .Fact2", "each has .ID .Subject .Predicate .Object .FactType .Confidence .Source .CreatedAt .LastReinforced"},
+		},
+		decode: func(raw []byte) (interface{}, error) {
+			var data ResolvePromptData
+			if err := json.Unmarshal(raw, &data); err != nil {
+				return nil, err
+			}
+			return data, nil
+		},
+		check: func(raw string) (int, error) {
+			entries, err := parseResolveResponse(raw)
+			return len(entries), err
+		},
+	},
+	PromptSummarize: {
+		Name:        PromptSummarize,
+		Description: "cluster consolidation (cortex summarize)",
+		System:      summarizeSystemPrompt,
+		User:        summarizeUserTemplate,
+		Task:        llm.TaskSummarize,
+		MaxTokens:   4096,
+		Variables: []PromptVariable{
+			{".ClusterName", "name of the topic cluster"},
+			{".Facts", "cluster facts (max 100); each has .ID .Subject .Predicate .Object .FactType .Confidence .Source"},
+		},
+		decode: func(raw []byte) (interface{}, error) {
+			var data SummarizePromptData
+			if err := json.Unmarshal(raw, &data); err != nil {
+				return nil, err
+			}
+			return data, nil
+		},
+		check: func(raw string) (int, error) {
+			resp, err := parseSummarizeResponse(raw)
+			if err != nil {
+				return 0, err
+			}
+			return len(resp.SummaryFacts), nil
+		},
+	},
+	PromptPredicates: {
+		Name:        PromptPredicates,
+		Description: "predicate alias suggestions (cortex normalize predicates --llm)",
+		System:      predicatesSystemPrompt,
+		User:        predicatesUserTemplate,
+		Task:        llm.TaskClassify,
+		MaxTokens:   2048,
+		Variables: []PromptVariable{
+			{".Canonical", "canonical predicates from the alias table, sorted"},
+			{".Predicates", "distinct predicates, most used first (max 200); each has .Predicate .Facts"},
+		},
+		decode: func(raw []byte) (interface{}, error) {
+			var data PredicatesPromptData
+			if err := json.Unmarshal(raw, &data); err != nil {
+				return nil, err
+			}
+			return data, nil
+		},
+		check: func(raw string) (int, error) {
+			entries, err := parsePredicatesResponse(raw)
+			return len(entries), err
+		},
+	},
+	PromptTriage: {
+		Name:        PromptTriage,
+		Description: "memory-worthiness gate during import (cortex import --triage)",
+		System:      triageSystemPrompt,
+		User:        triageUserTemplate,
+		Task:        llm.TaskClassify,
+		MaxTokens:   256,
+		Variables: []PromptVariable{
+			{".Source", "source file of the chunk"},
+			{".Chunk", "chunk text, truncated to 2000 characters"},
+			{".Similar", "contents of the most similar stored memories (max 3)"},
+		},
+		decode: func(raw []byte) (interface{}, error) {
+			trimmed := bytes.TrimSpace(raw)
+			if len(trimmed) == 0 || trimmed[0] != '{' {
+				return NewTriagePromptData("", string(raw), nil), nil
+			}
+			var data TriagePromptData
+			if err := json.Unmarshal(trimmed, &data); err != nil {
+				return nil, err
+			}
+			return NewTriagePromptData(data.Source, data.Chunk, data.Similar), nil
+		},
+		check: func(raw string) (int, error) {
+			if _, err := parseTriageResponse(raw); err != nil {
+				return 0, err
+			}
+			return 1, nil
+		},
+	},
+	PromptTranslate: {
+		Name:        PromptTranslate,
+		Description: "memory and fact translation (cortex translate)",
+		System:      translateSystemPrompt,
+		User:        translateUserTemplate,
+		Task:        llm.TaskTranslate,
+		MaxTokens:   4096,
+		Variables: []PromptVariable{
+			{".Target", "target language code, e.g. en"},
+			{".Text", "memory content, truncated to 6000 characters"},
+			{".Facts", "the memory's facts (max 40); each has .ID .Subject .Predicate .Object"},
+		},
+		decode: func(raw []byte) (interface{}, error) {
+			trimmed := bytes.TrimSpace(raw)
+			if len(trimmed) == 0 || trimmed[0] != '{' {
+				return NewTranslatePromptData("en", string(raw), nil), nil
+			}
+			var data TranslatePromptData
+			if err := json.Unmarshal(trimmed, &data); err != nil {
+				return nil, err
+			}
+			return NewTranslatePromptData(data.Target, data.Text, data.Facts), nil
+		},
+		check: func(raw string) (int, error) {
+			t, err := parseTranslateResponse(raw)
+			if err != nil {
+				return 0, err
+			}
+			return len(t.Facts), nil
+		},
+	},
+	PromptCanary: {
+		Name:        PromptCanary,
+		Description: "post-hoc review of a --canary sample (classify, summarize, conflicts --resolve llm)",
+		System:      canarySystemPrompt,
+		User:        canaryUserTemplate,
+		Task:        llm.TaskClassify,
+		MaxTokens:   2048,
+		Variables: []PromptVariable{
+			{".Operation", "the pass being checked: classify, resolve, or summarize"},
+			{".Changes", "plain-language descriptions of the applied changes (max 20 per call)"},
+		},
+		decode: func(raw []byte) (interface{}, error) {
+			var data CanaryPromptData
+			if err := json.Unmarshal(raw, &data); err != nil {
+				return nil, err
+			}
+			return data, nil
+		},
+		check: func(raw string) (int, error) {
+			verdicts, err := parseCanaryResponse(raw)
+			return len(verdicts), err
+		},
+	},
+}
+
+var (
+	promptDirMu       sync.RWMutex
+	promptDirOverride string
+)
+
+// SetPromptDir points override lookup at dir instead of ~/.cortex/prompts.
+// An empty dir restores the default.
+func SetPromptDir(dir string) {
+	promptDirMu.Lock()
+	defer promptDirMu.Unlock()
+	promptDirOverride = dir
+}
+
+// PromptDir returns the directory searched for prompt overrides.
+func PromptDir() (string, error) {
+	promptDirMu.RLock()
+	dir := promptDirOverride
+	promptDirMu.RUnlock()
+	if dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cortex", "prompts"), nil
+}
+
+// PromptSpecs returns every overridable prompt, sorted by name.
+func PromptSpecs() []PromptSpec {
+	specs := make([]PromptSpec, 0, len(promptSpecs))
+	for _, spec := range promptSpecs {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+	return specs
+}
+
+// LookupPromptSpec returns the prompt named name.
+func LookupPromptSpec(name string) (PromptSpec, error) {
+	spec, ok := promptSpecs[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return PromptSpec{}, fmt.Errorf("unknown prompt %q (valid: classify, enrich, predicates, resolve, summarize, translate, triage)", name)
+	}
+	return spec, nil
+}
+
+// PromptOverridePaths returns the system and user override paths for name.
+func PromptOverridePaths(name string) (systemPath, userPath string, err error) {
+	dir, err := PromptDir()
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(dir, name+".system.tmpl"), filepath.Join(dir, name+".user.tmpl"), nil
+}
+
+// DecodePromptInput builds template data for name from a test input file:
+// JSON matching the prompt's data fields, or plain text for enrich (used as
+// the chunk).
+func DecodePromptInput(name string, raw []byte) (interface{}, error) {
+	spec, err := LookupPromptSpec(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := spec.decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s input: %w", spec.Name, err)
+	}
+	return data, nil
+}
+
+// CheckPromptResponse parses raw the way the named pass would and returns
+// the number of items it produced.
+func CheckPromptResponse(name, raw string) (int, error) {
+	spec, err := LookupPromptSpec(name)
+	if err != nil {
+		return 0, err
+	}
+	return spec.check(raw)
+}
+
+// RenderPrompt renders the named prompt with data, applying any overrides
+// found in PromptDir.
+func RenderPrompt(name string, data interface{}) (*RenderedPrompt, error) {
+	spec, err := LookupPromptSpec(name)
+	if err != nil {
+		return nil, err
+	}
+	systemPath, userPath, err := PromptOverridePaths(spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &RenderedPrompt{Name: spec.Name}
+	out.System, out.SystemSource, err = renderPromptPart(spec.Name+".system", spec.System, systemPath, data)
+	if err != nil {
+		return nil, err
+	}
+	out.User, out.UserSource, err = renderPromptPart(spec.Name+".user", spec.User, userPath, data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// renderPromptPart renders the override at path when it exists, otherwise
+// the built-in text. It returns the output and where the template came from.
+func renderPromptPart(name, builtin, path string, data interface{}) (string, string, error) {
+	text, source := builtin, "built-in"
+	raw, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		text, source = string(raw), path
+	case !os.IsNotExist(err):
+		return "", "", fmt.Errorf("reading prompt override %s: %w", path, err)
+	}
+
+	tmpl, err := template.New(name).Funcs(promptFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", "", fmt.Errorf("parsing prompt template %s (%s): %w", name, source, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", "", fmt.Errorf("rendering prompt template %s (%s): %w", name, source, err)
+	}
+	return buf.String(), source, nil
+}
+
+// newEnrichPromptData caps rule facts the same way for real runs and tests.
+func newEnrichPromptData(chunk string, ruleFacts []ExtractedFact, anchor string) EnrichPromptData {
+	data := EnrichPromptData{AnchorDate: anchor, Chunk: chunk, RuleFacts: ruleFacts}
+	if len(ruleFacts) > enrichMaxRuleFacts {
+		data.RuleFacts = ruleFacts[:enrichMaxRuleFacts]
+		data.OmittedRuleFacts = len(ruleFacts) - enrichMaxRuleFacts
+	}
+	return data
+}
+
+func decodeEnrichPromptInput(raw []byte) (interface{}, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return newEnrichPromptData(truncateChunkForEnrich(string(raw)), nil, ""), nil
+	}
+	var in EnrichPromptData
+	if err := json.Unmarshal(trimmed, &in); err != nil {
+		return nil, err
+	}
+	data := newEnrichPromptData(truncateChunkForEnrich(in.Chunk), in.RuleFacts, in.AnchorDate)
+	data.Examples = capEnrichExamples(in.Examples)
+	return data, nil
+}
+
+// capEnrichExamples limits examples to MaxEnrichExamples and truncates
+// their content so a long example can't crowd out the chunk.
+func capEnrichExamples(examples []FewShotExample) []FewShotExample {
+	if len(examples) > MaxEnrichExamples {
+		examples = examples[:MaxEnrichExamples]
+	}
+	out := make([]FewShotExample, 0, len(examples))
+	for _, ex := range examples {
+		ex.Content = strings.TrimSpace(ex.Content)
+		if len(ex.Content) > enrichMaxExampleLen {
+			ex.Content = truncateAtWordBoundary(ex.Content, enrichMaxExampleLen)
+		}
+		out = append(out, ex)
+	}
+	return out
+}
+
+func truncateChunkForEnrich(chunk string) string {
+	if len(chunk) > enrichMaxChunkLen {
+		return truncateAtWordBoundary(chunk, enrichMaxChunkLen)
+	}
+	return chunk
+}
@@ -0,0 +1,196 @@
+package extract
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func usePromptDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	SetPromptDir(dir)
+	t.Cleanup(func() { SetPromptDir("") })
+	return dir
+}
+
+func TestRenderPrompt_BuiltInSystemPromptsUnchanged(t *testing.T) {
+	usePromptDir(t)
+	builtins := map[string]string{
+		PromptEnrich:     enrichSystemPrompt,
+		PromptClassify:   builtinClassifySystemPrompt,
+		PromptResolve:    resolveSystemPrompt,
+		PromptSummarize:  summarizeSystemPrompt,
+		PromptPredicates: predicatesSystemPrompt,
+		PromptTriage:     triageSystemPrompt,
+	}
+	data := map[string]interface{}{
+		PromptEnrich:     newEnrichPromptData("chunk", nil, ""),
+		PromptClassify:   ClassifyPromptData{Types: builtinTaxonomy},
+		PromptResolve:    ResolvePromptData{},
+		PromptSummarize:  SummarizePromptData{},
+		PromptPredicates: PredicatesPromptData{},
+		PromptTriage:     TriagePromptData{},
+	}
+	for name, want := range builtins {
+		rendered, err := RenderPrompt(name, data[name])
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if rendered.System != want {
+			t.Errorf("%s: built-in system prompt changed when rendered as a template", name)
+		}
+		if rendered.SystemSource != "built-in" || rendered.UserSource != "built-in" {
+			t.Errorf("%s: sources = %q/%q, want built-in", name, rendered.SystemSource, rendered.UserSource)
+		}
+	}
+}
+
+// builtinClassifySystemPrompt is the classify system prompt as it was before
+// the type list became a template; the built-in taxonomy must reproduce it.
+const builtinClassifySystemPrompt = `You are a fact classification system for a personal knowledge base. Each fact has a subject, predicate, and object. Your job is to assign the most accurate TYPE to each fact.
+
+AVAILABLE TYPES:
+- decision: Choices, commitments, locked configs ("Q locked ORB config", "decided to use Alpaca")
+- preference: Likes, dislikes, style choices ("prefers dark mode", "dislikes tedious UI debugging")
+- identity: Personal identifiers, credentials, roles ("email is alice@test.com", "Q's DOB is 12/25/1994")
+- relationship: Connections between entities ("Niot works on Eyes Web", "SB is co-founder")
+- temporal: Time-bound facts, deadlines, expiry dates ("eBay token expires 2027-07-28", "meeting on Tuesday")
+- state: Current conditions, statuses ("ADA bot is LIVE", "disk usage at 92%")
+- location: Geographic or path references ("lives in Philadelphia", "binary at ~/bin/cortex")
+- config: Technical settings, parameters ("model is Haiku 4.5", "port 8090")
+- kv: Generic key-value when no better type fits (LAST RESORT only)
+
+RULES:
+- Classify based on the SEMANTIC MEANING, not just keyword matching
+- Use "decision" when someone CHOSE or LOCKED something (even if it looks like config)
+- Use "relationship" when two entities are connected (even if stated as key-value)
+- Use "temporal" when a date/time is the key information
+- Only use "kv" when genuinely no other type fits
+- Return confidence 0.0-1.0 for each classification
+
+Return ONLY a JSON object:
+{
+  "classifications": [
+    {"id": 123, "type": "decision", "confidence": 0.9},
+    {"id": 456, "type": "relationship", "confidence": 0.85}
+  ]
+}`
+
+func TestRenderPrompt_UsesOverrides(t *testing.T) {
+	dir := usePromptDir(t)
+	userPath := filepath.Join(dir, "classify.user.tmpl")
+	if err := os.WriteFile(userPath, []byte(`Trading desk facts:{{range .Facts}} {{.ID}}={{upper .Predicate}}{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rendered, err := buildClassifyPrompt([]ClassifyableFact{{ID: 7, Predicate: "locked"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rendered.User != "Trading desk facts: 7=LOCKED" {
+		t.Errorf("user = %q", rendered.User)
+	}
+	if rendered.UserSource != userPath {
+		t.Errorf("user source = %q, want %q", rendered.UserSource, userPath)
+	}
+	if rendered.System != builtinClassifySystemPrompt {
+		t.Error("system prompt should stay built-in when only the user template is overridden")
+	}
+}
+
+func TestRenderPrompt_BadOverrideIsAnError(t *testing.T) {
+	dir := usePromptDir(t)
+	if err := os.WriteFile(filepath.Join(dir, "summarize.system.tmpl"), []byte(`{{.NoSuchField}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := buildSummarizePrompt("C", nil)
+	if err == nil || !strings.Contains(err.Error(), "summarize.system") {
+		t.Fatalf("expected render error naming the template, got %v", err)
+	}
+
+	provider := &mockSummarizeProvider{response: `{"summary_facts": []}`}
+	if _, err := summarizeCluster(context.Background(), provider, ClusterInput{Name: "C"}); err == nil {
+		t.Fatal("expected summarizeCluster to surface the template error")
+	}
+	if provider.calls != 0 {
+		t.Error("LLM should not be called when the prompt fails to render")
+	}
+}
+
+func TestLookupPromptSpec_Unknown(t *testing.T) {
+	if _, err := LookupPromptSpec("nope"); err == nil {
+		t.Fatal("expected error for unknown prompt")
+	}
+	if len(PromptSpecs()) != 8 {
+		t.Errorf("expected 8 prompt specs, got %d", len(PromptSpecs()))
+	}
+}
+
+func TestDecodePromptInput(t *testing.T) {
+	data, err := DecodePromptInput(PromptEnrich, []byte("Alice joined Acme in March."))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := data.(EnrichPromptData); d.Chunk != "Alice joined Acme in March." {
+		t.Errorf("plain-text enrich input should become the chunk, got %q", d.Chunk)
+	}
+
+	data, err = DecodePromptInput(PromptClassify, []byte(`{"facts":[{"id":3,"subject":"Q","predicate":"port","object":"8090","type":"kv"}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := data.(ClassifyPromptData); len(d.Facts) != 1 || d.Facts[0].FactType != "kv" {
+		t.Errorf("classify input decoded wrong: %+v", d)
+	}
+
+	if _, err := DecodePromptInput(PromptResolve, []byte("not json")); err == nil {
+		t.Fatal("expected error for non-JSON resolve input")
+	}
+}
+
+func TestCheckPromptResponse(t *testing.T) {
+	n, err := CheckPromptResponse(PromptClassify, "```json\n{\"classifications\":[{\"id\":1,\"type\":\"state\",\"confidence\":0.9}]}\n```")
+	if err != nil || n != 1 {
+		t.Fatalf("got %d, %v", n, err)
+	}
+	if _, err := CheckPromptResponse(PromptEnrich, "no json here"); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
+func TestBuildEnrichPrompt_FewShotExamples(t *testing.T) {
+	usePromptDir(t)
+	var examples []FewShotExample
+	for i := 0; i < 5; i++ {
+		examples = append(examples, FewShotExample{
+			Content: strings.Repeat("ORB config locked. ", 100),
+			Facts:   []ExtractedFact{{Subject: "Q", Predicate: "orb window", Object: "15m", FactType: "decision"}},
+		})
+	}
+
+	rendered, err := buildEnrichPrompt("chunk", nil, "", examples...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(rendered.User, "CURATED EXAMPLES") {
+		t.Error("examples should lead the prompt")
+	}
+	if strings.Count(rendered.User, "--- EXAMPLE") != MaxEnrichExamples {
+		t.Errorf("expected %d examples, got %d", MaxEnrichExamples, strings.Count(rendered.User, "--- EXAMPLE"))
+	}
+	if !strings.Contains(rendered.User, "- [decision] Q → orb window → 15m") {
+		t.Error("example facts should be listed")
+	}
+	if strings.Contains(rendered.User, strings.Repeat("ORB config locked. ", 80)) {
+		t.Error("long example content should be truncated")
+	}
+
+	plain, _ := buildEnrichPrompt("chunk", nil, "")
+	if strings.Contains(plain.User, "CURATED EXAMPLES") {
+		t.Error("no examples section expected without examples")
+	}
+}
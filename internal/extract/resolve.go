@@ -7,10 +7,7 @@ package extract
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"strings"
-	"sync"
 	"time"
 
 	"github.com/hurttlocker/cortex/internal/llm"
@@ -84,23 +81,23 @@ For merge actions, include the merged fact:
 
 // ConflictPair represents two contradictory facts for resolution.
 type ConflictPair struct {
-	Index int
-	Fact1 ConflictFact
-	Fact2 ConflictFact
+	Index int          `json:"index"`
+	Fact1 ConflictFact `json:"fact1"`
+	Fact2 ConflictFact `json:"fact2"`
 }
 
 // ConflictFact is a fact with metadata needed for resolution.
 type ConflictFact struct {
-	ID             int64
-	Subject        string
-	Predicate      string
-	Object         string
-	FactType       string
-	Confidence     float64
-	DecayRate      float64
-	Source         string
-	CreatedAt      time.Time
-	LastReinforced time.Time
+	ID             int64     `json:"id"`
+	Subject        string    `json:"subject"`
+	Predicate      string    `json:"predicate"`
+	Object         string    `json:"object"`
+	FactType       string    `json:"type"`
+	Confidence     float64   `json:"confidence"`
+	DecayRate      float64   `json:"decay_rate"`
+	Source         string    `json:"source"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastReinforced time.Time `json:"last_reinforced"`
 }
 
 // ConflictResolution is the LLM's decision for one conflict pair.
@@ -112,6 +109,10 @@ type ConflictResolution struct {
 	Reason     string      `json:"reason"`
 	Confidence float64     `json:"confidence"`
 	MergedFact *MergedFact `json:"merged_fact,omitempty"`
+
+	// Votes holds each model's answer when the pair was resolved by
+	// ResolveConflictsConsensus.
+	Votes []ConsensusVote `json:"votes,omitempty"`
 }
 
 // MergedFact is a new fact combining two conflicting originals.
@@ -130,6 +131,10 @@ type ResolveResult struct {
 	Flagged     int // Conflicts flagged for human review
 	Errors      int // Errors during resolution
 	TotalPairs  int // Total conflict pairs processed
+	Skipped     int // Pairs not attempted because the run stopped early
+	Retries     int // LLM calls retried after transient errors
+	RateLimits  int // Rate-limit responses from the provider
+	StopReason  string
 	Latency     time.Duration
 	Model       string
 }
@@ -140,6 +145,12 @@ type ResolveOpts struct {
 	DryRun        bool    // Show resolutions without applying
 	Concurrency   int     // Parallel LLM requests (default: 5)
 	BatchSize     int     // Pairs per LLM call (default: 5)
+
+	// OnBatch, if set, is called after each successful batch with its
+	// pairs and resolutions, so callers can apply them and checkpoint
+	// progress as they go. Calls are serialized. An error counts the
+	// batch's pairs as errors.
+	OnBatch func(batch []ConflictPair, resolutions []ConflictResolution) error
 }
 
 // DefaultResolveOpts returns sensible defaults.
@@ -202,150 +213,139 @@ func ResolveConflictsLLM(ctx context.Context, provider llm.Provider, pairs []Con
 		batches = append(batches, pairs[i:end])
 	}
 
-	// Process concurrently
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, opts.Concurrency)
-
-	for _, batch := range batches {
-		wg.Add(1)
-		sem <- struct{}{}
-
-		go func(batch []ConflictPair) {
-			defer wg.Done()
-			defer func() { <-sem }()
-
-			resolutions, err := resolveBatch(ctx, provider, batch)
-
-			mu.Lock()
-			defer mu.Unlock()
-
+	// Process concurrently; runLLMBatches serializes the callback.
+	responses := make([][]resolveEntry, len(batches))
+	processed := 0
+	stats, stopErr := runLLMBatches(ctx, len(batches), opts.Concurrency,
+		func(ctx context.Context, i int) error {
+			resolutions, err := resolveBatch(ctx, provider, batches[i])
+			responses[i] = resolutions
+			return err
+		},
+		func(i int, err error) {
+			batch := batches[i]
+			processed += len(batch)
 			if err != nil {
 				result.Errors += len(batch)
 				return
 			}
 
-			for _, r := range resolutions {
-				// Validate action
-				switch r.Action {
-				case "supersede", "merge", "flag-human":
-					// ok
-				default:
-					result.Errors++
-					continue
-				}
-
-				// Force flag-human if confidence too low
-				if r.Confidence < opts.MinConfidence && r.Action != "flag-human" {
-					r.Action = "flag-human"
-					r.Reason += " (confidence below threshold, flagged for review)"
-				}
+			resolutions, errs := validateResolveEntries(responses[i], opts.MinConfidence)
+			responses[i] = nil
 
-				resolution := ConflictResolution{
-					PairIndex:  r.PairIndex,
-					Action:     r.Action,
-					WinnerID:   r.WinnerID,
-					LoserID:    r.LoserID,
-					Reason:     r.Reason,
-					Confidence: r.Confidence,
-					MergedFact: r.MergedFact,
+			if opts.OnBatch != nil {
+				if err := opts.OnBatch(batch, resolutions); err != nil {
+					result.Errors += len(batch)
+					return
 				}
+			}
+			result.Errors += errs
+			result.add(resolutions)
+		})
+
+	result.Retries = stats.Retries
+	result.RateLimits = stats.RateLimited
+	if stopErr != nil {
+		result.Skipped = result.TotalPairs - processed
+		result.StopReason = stopErr.Error()
+	}
+	result.Latency = time.Since(start)
+	return result, nil
+}
 
-				// Validate merged fact if merge action
-				if r.Action == "merge" && r.MergedFact != nil {
-					if !isValidFactType(r.MergedFact.FactType) {
-						r.MergedFact.FactType = "kv"
-					}
-				}
+// validateResolveEntries turns the LLM's entries into resolutions. Entries
+// with an unknown action are dropped and counted as errors; resolutions
+// below minConfidence are flagged for human review.
+func validateResolveEntries(entries []resolveEntry, minConfidence float64) ([]ConflictResolution, int) {
+	var resolutions []ConflictResolution
+	errs := 0
+	for _, r := range entries {
+		// Validate action
+		switch r.Action {
+		case "supersede", "merge", "flag-human":
+			// ok
+		default:
+			errs++
+			continue
+		}
 
-				result.Resolutions = append(result.Resolutions, resolution)
+		// Force flag-human if confidence too low
+		if r.Confidence < minConfidence && r.Action != "flag-human" {
+			r.Action = "flag-human"
+			r.Reason += " (confidence below threshold, flagged for review)"
+		}
 
-				switch r.Action {
-				case "supersede":
-					result.Superseded++
-				case "merge":
-					result.Merged++
-				case "flag-human":
-					result.Flagged++
-				}
+		// Validate merged fact if merge action
+		if r.Action == "merge" && r.MergedFact != nil {
+			if !isValidFactType(r.MergedFact.FactType) {
+				r.MergedFact.FactType = "kv"
 			}
-		}(batch)
+		}
+
+		resolutions = append(resolutions, ConflictResolution{
+			PairIndex:  r.PairIndex,
+			Action:     r.Action,
+			WinnerID:   r.WinnerID,
+			LoserID:    r.LoserID,
+			Reason:     r.Reason,
+			Confidence: r.Confidence,
+			MergedFact: r.MergedFact,
+		})
 	}
+	return resolutions, errs
+}
 
-	wg.Wait()
-	result.Latency = time.Since(start)
-	return result, nil
+// add appends resolutions to the result and counts them by action.
+func (result *ResolveResult) add(resolutions []ConflictResolution) {
+	for _, r := range resolutions {
+		result.Resolutions = append(result.Resolutions, r)
+		switch r.Action {
+		case "supersede":
+			result.Superseded++
+		case "merge":
+			result.Merged++
+		case "flag-human":
+			result.Flagged++
+		}
+	}
 }
 
 // resolveBatch sends a batch of conflict pairs to the LLM.
 func resolveBatch(ctx context.Context, provider llm.Provider, pairs []ConflictPair) ([]resolveEntry, error) {
-	prompt := buildResolvePrompt(pairs)
+	prompt, err := buildResolvePrompt(pairs)
+	if err != nil {
+		return nil, err
+	}
 
 	resolveCtx, cancel := context.WithTimeout(ctx, resolveTimeout)
 	defer cancel()
 
-	response, err := provider.Complete(resolveCtx, prompt, llm.CompletionOpts{
+	var entries []resolveEntry
+	err = llm.CompleteStructured(resolveCtx, provider, prompt.User, llm.CompletionOpts{
 		Temperature: 0.1,
 		MaxTokens:   4096,
-		System:      resolveSystemPrompt,
+		System:      prompt.System,
+		Task:        llm.TaskResolve,
+	}, func(reply string) (err error) {
+		entries, err = parseResolveResponse(reply)
+		return err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("LLM resolve call: %w", err)
 	}
-
-	return parseResolveResponse(response)
+	return entries, nil
 }
 
-// buildResolvePrompt constructs the user message with conflict pairs.
-func buildResolvePrompt(pairs []ConflictPair) string {
-	var sb strings.Builder
-
-	sb.WriteString(fmt.Sprintf("Resolve %d conflict pairs. Return JSON only.\n\n", len(pairs)))
-
-	for i, p := range pairs {
-		sb.WriteString(fmt.Sprintf("--- CONFLICT PAIR %d ---\n", i))
-		sb.WriteString(fmt.Sprintf("FACT A (id:%d): %s → %s → %s\n",
-			p.Fact1.ID, p.Fact1.Subject, p.Fact1.Predicate, truncateForPrompt(p.Fact1.Object, 120)))
-		sb.WriteString(fmt.Sprintf("  type:%s, conf:%.2f, source:%s, created:%s\n",
-			p.Fact1.FactType, p.Fact1.Confidence, truncateForPrompt(p.Fact1.Source, 50),
-			p.Fact1.CreatedAt.Format("2006-01-02")))
-		sb.WriteString(fmt.Sprintf("FACT B (id:%d): %s → %s → %s\n",
-			p.Fact2.ID, p.Fact2.Subject, p.Fact2.Predicate, truncateForPrompt(p.Fact2.Object, 120)))
-		sb.WriteString(fmt.Sprintf("  type:%s, conf:%.2f, source:%s, created:%s\n\n",
-			p.Fact2.FactType, p.Fact2.Confidence, truncateForPrompt(p.Fact2.Source, 50),
-			p.Fact2.CreatedAt.Format("2006-01-02")))
-	}
-
-	return sb.String()
+// buildResolvePrompt renders the resolve prompt for a batch of conflict
+// pairs, applying any template overrides.
+func buildResolvePrompt(pairs []ConflictPair) (*RenderedPrompt, error) {
+	return RenderPrompt(PromptResolve, ResolvePromptData{Pairs: pairs})
 }
 
-// parseResolveResponse parses the LLM's JSON response.
+// parseResolveResponse parses the LLM's JSON response (see llm.RepairJSON).
 func parseResolveResponse(raw string) ([]resolveEntry, error) {
-	cleaned := strings.TrimSpace(raw)
-
-	// Strip markdown code fences
-	if strings.HasPrefix(cleaned, "```") {
-		lines := strings.Split(cleaned, "\n")
-		start, end := 0, len(lines)
-		for i, line := range lines {
-			if strings.HasPrefix(strings.TrimSpace(line), "```") {
-				if start == 0 {
-					start = i + 1
-				} else {
-					end = i
-					break
-				}
-			}
-		}
-		if start > 0 && end > start {
-			cleaned = strings.Join(lines[start:end], "\n")
-		}
-	}
-
-	cleaned = strings.TrimSpace(cleaned)
-
 	var resp resolveResponse
-	if err := json.Unmarshal([]byte(cleaned), &resp); err != nil {
+	if err := llm.DecodeJSON(raw, &resp); err != nil {
 		return nil, fmt.Errorf("invalid JSON from LLM: %w\nraw: %s", err, truncateForError(raw, 300))
 	}
 
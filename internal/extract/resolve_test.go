@@ -308,7 +308,11 @@ func TestResolveConflictsLLM_MergedFactInvalidType(t *testing.T) {
 
 func TestBuildResolvePrompt(t *testing.T) {
 	pairs := []ConflictPair{makePair(0, 100, 200, "old value", "new value")}
-	prompt := buildResolvePrompt(pairs)
+	rendered, err := buildResolvePrompt(pairs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prompt := rendered.User
 
 	if !containsStr(prompt, "CONFLICT PAIR 0") {
 		t.Error("prompt missing pair header")
@@ -7,9 +7,7 @@ package extract
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/hurttlocker/cortex/internal/llm"
@@ -87,6 +85,10 @@ type SummarizeResult struct {
 	TotalOriginal  int              `json:"total_original"`
 	TotalNew       int              `json:"total_new"`
 	TotalSupersede int              `json:"total_superseded"`
+	Errors         int              `json:"errors,omitempty"`      // clusters whose LLM call failed
+	Skipped        int              `json:"skipped,omitempty"`     // clusters not attempted because the run stopped early
+	Retries        int              `json:"retries,omitempty"`     // LLM calls retried after transient errors
+	StopReason     string           `json:"stop_reason,omitempty"` // why the run stopped early
 	Latency        time.Duration    `json:"latency"`
 	Model          string           `json:"model"`
 }
@@ -96,6 +98,11 @@ type SummarizeOpts struct {
 	MinClusterSize int   // Min facts in cluster to consider (default: 5)
 	ClusterID      int64 // Summarize specific cluster (0 = all)
 	DryRun         bool  // Show plan without applying
+
+	// OnCluster, if set, is called after each cluster is summarized so
+	// callers can apply it and checkpoint progress as they go. An error
+	// counts the cluster as failed.
+	OnCluster func(summary ClusterSummary) error
 }
 
 // DefaultSummarizeOpts returns sensible defaults.
@@ -114,13 +121,13 @@ type ClusterInput struct {
 
 // ClusterFactInput is a fact within a cluster for summarization.
 type ClusterFactInput struct {
-	ID         int64
-	Subject    string
-	Predicate  string
-	Object     string
-	FactType   string
-	Confidence float64
-	Source     string
+	ID         int64   `json:"id"`
+	Subject    string  `json:"subject"`
+	Predicate  string  `json:"predicate"`
+	Object     string  `json:"object"`
+	FactType   string  `json:"type"`
+	Confidence float64 `json:"confidence"`
+	Source     string  `json:"source"`
 }
 
 // summarizeResponse is the JSON the LLM returns.
@@ -145,6 +152,7 @@ func SummarizeClusters(ctx context.Context, provider llm.Provider, clusters []Cl
 		Model: provider.Name(),
 	}
 
+	var eligible []ClusterInput
 	for _, cluster := range clusters {
 		// Skip small clusters
 		if len(cluster.Facts) < opts.MinClusterSize {
@@ -155,17 +163,41 @@ func SummarizeClusters(ctx context.Context, provider llm.Provider, clusters []Cl
 		if opts.ClusterID > 0 && cluster.ID != opts.ClusterID {
 			continue
 		}
+		eligible = append(eligible, cluster)
+	}
 
-		summary, err := summarizeCluster(ctx, provider, cluster)
-		if err != nil {
-			// Log but continue with next cluster
-			continue
-		}
-
-		result.Summaries = append(result.Summaries, *summary)
-		result.TotalOriginal += summary.OriginalCount
-		result.TotalNew += summary.NewCount
-		result.TotalSupersede += len(summary.SupersededIDs)
+	// One cluster at a time; failures are counted and the run continues.
+	summaries := make([]*ClusterSummary, len(eligible))
+	attempted := 0
+	stats, stopErr := runLLMBatches(ctx, len(eligible), 1,
+		func(ctx context.Context, i int) error {
+			summary, err := summarizeCluster(ctx, provider, eligible[i])
+			summaries[i] = summary
+			return err
+		},
+		func(i int, err error) {
+			attempted++
+			if err != nil {
+				result.Errors++
+				return
+			}
+			summary := summaries[i]
+			if opts.OnCluster != nil {
+				if err := opts.OnCluster(*summary); err != nil {
+					result.Errors++
+					return
+				}
+			}
+			result.Summaries = append(result.Summaries, *summary)
+			result.TotalOriginal += summary.OriginalCount
+			result.TotalNew += summary.NewCount
+			result.TotalSupersede += len(summary.SupersededIDs)
+		})
+
+	result.Retries = stats.Retries
+	if stopErr != nil {
+		result.Skipped = len(eligible) - attempted
+		result.StopReason = stopErr.Error()
 	}
 
 	result.Latency = time.Since(start)
@@ -182,25 +214,28 @@ func summarizeCluster(ctx context.Context, provider llm.Provider, cluster Cluste
 		facts = facts[:summarizeMaxFacts]
 	}
 
-	prompt := buildSummarizePrompt(cluster.Name, facts)
+	prompt, err := buildSummarizePrompt(cluster.Name, facts)
+	if err != nil {
+		return nil, err
+	}
 
 	sumCtx, cancel := context.WithTimeout(ctx, summarizeTimeout)
 	defer cancel()
 
-	response, err := provider.Complete(sumCtx, prompt, llm.CompletionOpts{
+	var parsed *summarizeResponse
+	err = llm.CompleteStructured(sumCtx, provider, prompt.User, llm.CompletionOpts{
 		Temperature: 0.1,
 		MaxTokens:   4096,
-		System:      summarizeSystemPrompt,
+		System:      prompt.System,
+		Task:        llm.TaskSummarize,
+	}, func(reply string) (err error) {
+		parsed, err = parseSummarizeResponse(reply)
+		return err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("LLM summarize call: %w", err)
 	}
 
-	parsed, err := parseSummarizeResponse(response)
-	if err != nil {
-		return nil, fmt.Errorf("parsing summarize response: %w", err)
-	}
-
 	// Validate summary facts
 	validFacts := make([]SummaryFact, 0, len(parsed.SummaryFacts))
 	supersededSet := make(map[int64]bool)
@@ -254,57 +289,16 @@ func summarizeCluster(ctx context.Context, provider llm.Provider, cluster Cluste
 	}, nil
 }
 
-// buildSummarizePrompt constructs the user message with cluster facts.
-func buildSummarizePrompt(clusterName string, facts []ClusterFactInput) string {
-	var sb strings.Builder
-
-	sb.WriteString(fmt.Sprintf("CLUSTER: %s (%d facts)\n\n", clusterName, len(facts)))
-	sb.WriteString("FACTS:\n")
-
-	for _, f := range facts {
-		subj := f.Subject
-		if subj == "" {
-			subj = "(none)"
-		}
-		sb.WriteString(fmt.Sprintf("- id:%d [%s] %s → %s → %s (conf:%.2f",
-			f.ID, f.FactType, subj, f.Predicate, truncateForPrompt(f.Object, 100), f.Confidence))
-		if f.Source != "" {
-			sb.WriteString(fmt.Sprintf(", src:%s", truncateForPrompt(f.Source, 40)))
-		}
-		sb.WriteString(")\n")
-	}
-
-	sb.WriteString("\nConsolidate redundant facts. Preserve all unique information. Return JSON only.")
-	return sb.String()
+// buildSummarizePrompt renders the summarize prompt for one cluster,
+// applying any template overrides.
+func buildSummarizePrompt(clusterName string, facts []ClusterFactInput) (*RenderedPrompt, error) {
+	return RenderPrompt(PromptSummarize, SummarizePromptData{ClusterName: clusterName, Facts: facts})
 }
 
-// parseSummarizeResponse parses the LLM's JSON (with markdown stripping).
+// parseSummarizeResponse parses the LLM's JSON (see llm.RepairJSON).
 func parseSummarizeResponse(raw string) (*summarizeResponse, error) {
-	cleaned := strings.TrimSpace(raw)
-
-	// Strip markdown code fences
-	if strings.HasPrefix(cleaned, "```") {
-		lines := strings.Split(cleaned, "\n")
-		start, end := 0, len(lines)
-		for i, line := range lines {
-			if strings.HasPrefix(strings.TrimSpace(line), "```") {
-				if start == 0 {
-					start = i + 1
-				} else {
-					end = i
-					break
-				}
-			}
-		}
-		if start > 0 && end > start {
-			cleaned = strings.Join(lines[start:end], "\n")
-		}
-	}
-
-	cleaned = strings.TrimSpace(cleaned)
-
 	var resp summarizeResponse
-	if err := json.Unmarshal([]byte(cleaned), &resp); err != nil {
+	if err := llm.DecodeJSON(raw, &resp); err != nil {
 		return nil, fmt.Errorf("invalid JSON: %w\nraw: %s", err, truncateForError(raw, 300))
 	}
 
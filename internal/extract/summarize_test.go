@@ -258,7 +258,11 @@ func TestBuildSummarizePrompt(t *testing.T) {
 		{ID: 2, Subject: "", Predicate: "port", Object: "8090", FactType: "config", Confidence: 0.9},
 	}
 
-	prompt := buildSummarizePrompt("Q Personal", facts)
+	rendered, err := buildSummarizePrompt("Q Personal", facts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prompt := rendered.User
 
 	if !strings.Contains(prompt, "CLUSTER: Q Personal") {
 		t.Error("prompt should contain cluster name")
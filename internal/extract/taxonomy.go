@@ -0,0 +1,106 @@
+package extract
+
+import (
+	"sync"
+
+	"github.com/hurttlocker/cortex/internal/config"
+)
+
+// TaxonomyType is one fact type offered to the classifier.
+type TaxonomyType struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Examples    []string `json:"examples,omitempty"`
+	Base        string   `json:"base"` // built-in type stored in fact_type
+}
+
+// Custom reports whether t is a user-defined type rather than a built-in.
+func (t TaxonomyType) Custom() bool { return t.Name != t.Base }
+
+// builtinTaxonomy is the default classify type list, in prompt order. kv
+// stays last; custom types are inserted before it.
+var builtinTaxonomy = []TaxonomyType{
+	{Name: "decision", Base: "decision", Description: "Choices, commitments, locked configs", Examples: []string{"Q locked ORB config", "decided to use Alpaca"}},
+	{Name: "preference", Base: "preference", Description: "Likes, dislikes, style choices", Examples: []string{"prefers dark mode", "dislikes tedious UI debugging"}},
+	{Name: "identity", Base: "identity", Description: "Personal identifiers, credentials, roles", Examples: []string{"email is alice@test.com", "Q's DOB is 12/25/1994"}},
+	{Name: "relationship", Base: "relationship", Description: "Connections between entities", Examples: []string{"Niot works on Eyes Web", "SB is co-founder"}},
+	{Name: "temporal", Base: "temporal", Description: "Time-bound facts, deadlines, expiry dates", Examples: []string{"eBay token expires 2027-07-28", "meeting on Tuesday"}},
+	{Name: "state", Base: "state", Description: "Current conditions, statuses", Examples: []string{"ADA bot is LIVE", "disk usage at 92%"}},
+	{Name: "location", Base: "location", Description: "Geographic or path references", Examples: []string{"lives in Philadelphia", "binary at ~/bin/cortex"}},
+	{Name: "config", Base: "config", Description: "Technical settings, parameters", Examples: []string{"model is Haiku 4.5", "port 8090"}},
+	{Name: "kv", Base: "kv", Description: "Generic key-value when no better type fits (LAST RESORT only)"},
+}
+
+var (
+	taxonomyMu     sync.RWMutex
+	activeTaxonomy = builtinTaxonomy
+)
+
+// SetTaxonomy applies the configured extract.taxonomy on top of the built-in
+// types. Entries are expected to be normalized by the config loader; entries
+// naming a built-in type replace its description and examples, the rest are
+// added as custom types. Passing nil restores the built-in taxonomy.
+func SetTaxonomy(types []config.FactTypeConfig) {
+	next := make([]TaxonomyType, len(builtinTaxonomy))
+	copy(next, builtinTaxonomy)
+	var custom []TaxonomyType
+	for _, t := range types {
+		if isValidFactType(t.Name) {
+			for i := range next {
+				if next[i].Name != t.Name {
+					continue
+				}
+				if t.Description != "" {
+					next[i].Description = t.Description
+				}
+				if len(t.Examples) > 0 {
+					next[i].Examples = t.Examples
+				}
+			}
+			continue
+		}
+		if t.Name == "" || !isValidFactType(t.Base) {
+			continue
+		}
+		custom = append(custom, TaxonomyType{Name: t.Name, Description: t.Description, Examples: t.Examples, Base: t.Base})
+	}
+	if len(custom) > 0 {
+		kv := next[len(next)-1]
+		next = append(append(next[:len(next)-1], custom...), kv)
+	}
+
+	taxonomyMu.Lock()
+	activeTaxonomy = next
+	taxonomyMu.Unlock()
+}
+
+// Taxonomy returns the active classify types, in prompt order.
+func Taxonomy() []TaxonomyType {
+	taxonomyMu.RLock()
+	defer taxonomyMu.RUnlock()
+	out := make([]TaxonomyType, len(activeTaxonomy))
+	copy(out, activeTaxonomy)
+	return out
+}
+
+// CustomTypeBases maps each active custom type to its built-in base, in the
+// form store.SetCustomFactTypes expects.
+func CustomTypeBases() map[string]string {
+	out := map[string]string{}
+	for _, t := range Taxonomy() {
+		if t.Custom() {
+			out[t.Name] = t.Base
+		}
+	}
+	return out
+}
+
+// isTaxonomyType reports whether name is in the active taxonomy.
+func isTaxonomyType(name string) bool {
+	for _, t := range Taxonomy() {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package extract
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/config"
+)
+
+func useTaxonomy(t *testing.T, types []config.FactTypeConfig) {
+	t.Helper()
+	SetTaxonomy(types)
+	t.Cleanup(func() { SetTaxonomy(nil) })
+}
+
+func TestSetTaxonomy_CustomTypesBeforeKV(t *testing.T) {
+	useTaxonomy(t, []config.FactTypeConfig{
+		{Name: "medication", Description: "Drugs, doses, schedules", Examples: []string{"takes 5mg daily"}, Base: "state"},
+		{Name: "config", Description: "Runtime knobs"},
+	})
+
+	types := Taxonomy()
+	if len(types) != len(builtinTaxonomy)+1 {
+		t.Fatalf("expected %d types, got %d", len(builtinTaxonomy)+1, len(types))
+	}
+	if types[len(types)-1].Name != "kv" || types[len(types)-2].Name != "medication" {
+		t.Errorf("custom types should sit just before kv, got %+v", types[len(types)-2:])
+	}
+	for _, ty := range types {
+		if ty.Name == "config" && (ty.Description != "Runtime knobs" || len(ty.Examples) != 2) {
+			t.Errorf("built-in override should replace only the description, got %+v", ty)
+		}
+	}
+	if bases := CustomTypeBases(); len(bases) != 1 || bases["medication"] != "state" {
+		t.Errorf("CustomTypeBases = %v", bases)
+	}
+
+	usePromptDir(t)
+	rendered, err := buildClassifyPrompt(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(rendered.System, `- medication: Drugs, doses, schedules [a more specific state] ("takes 5mg daily")`) {
+		t.Errorf("custom type missing from classify prompt:\n%s", rendered.System)
+	}
+}
+
+func TestClassifyFacts_AcceptsCustomTypes(t *testing.T) {
+	usePromptDir(t)
+	useTaxonomy(t, []config.FactTypeConfig{{Name: "medication", Description: "Drugs and doses", Base: "state"}})
+
+	provider := &mockClassifyProvider{response: `{"classifications":[{"id":1,"type":"medication","confidence":0.95},{"id":2,"type":"recipe","confidence":0.95}]}`}
+	result, err := ClassifyFacts(context.Background(), provider, []ClassifyableFact{
+		{ID: 1, Predicate: "dose", Object: "5mg", FactType: "kv"},
+		{ID: 2, Predicate: "bake", Object: "20m", FactType: "kv"},
+	}, DefaultClassifyOpts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Classified) != 1 || result.Classified[0].NewType != "medication" {
+		t.Fatalf("expected medication reclassification, got %+v", result.Classified)
+	}
+	if result.Errors != 1 {
+		t.Errorf("type outside the taxonomy should count as an error, got %d", result.Errors)
+	}
+}
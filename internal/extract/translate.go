@@ -0,0 +1,148 @@
+// Package extract — LLM translation of memories and their facts.
+//
+// TranslateMemory asks a model to detect a memory's language and, unless it
+// is already in the target language, translate the memory and its facts.
+// cortex translate stores the results next to the originals.
+package extract
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+const (
+	// translateTimeout bounds one translation call.
+	translateTimeout = 90 * time.Second
+
+	// translateMaxText and translateMaxFacts keep one call within a
+	// modest completion budget; longer memories are truncated.
+	translateMaxText  = 6000
+	translateMaxFacts = 40
+)
+
+const translateSystemPrompt = `You translate entries in a personal knowledge base. You receive one memory (free text) and the facts extracted from it (subject → predicate → object).
+
+1. Detect the language the memory is written in and report it as an ISO 639-1 code ("de", "es", "ja").
+2. If it is already in the TARGET language, return only {"source_lang": "<code>"}.
+3. Otherwise translate the memory text and every fact into the TARGET language:
+   - keep names of people, places, products, code, paths, URLs, numbers, and dates exactly as written
+   - keep markdown structure and line breaks
+   - translate predicates as short snake_case phrases (e.g. "wohnt_in" → "lives_in")
+   - keep each fact's id
+
+Return ONLY a JSON object:
+{
+  "source_lang": "de",
+  "text": "translated memory text",
+  "facts": [{"id": 1, "subject": "...", "predicate": "...", "object": "..."}]
+}`
+
+const translateUserTemplate = `TARGET LANGUAGE: {{.Target}}
+
+MEMORY:
+---
+{{.Text}}
+---
+{{if .Facts}}
+FACTS:
+{{range .Facts}}- id:{{.ID}} {{or .Subject "(none)"}} → {{.Predicate}} → {{.Object}}
+{{end}}{{end}}
+Translate into {{.Target}}. Return JSON only.`
+
+// TranslateFactInput is a fact sent along with its memory for translation.
+type TranslateFactInput struct {
+	ID        int64  `json:"id"`
+	Subject   string `json:"subject"`
+	Predicate string `json:"predicate"`
+	Object    string `json:"object"`
+}
+
+// TranslatePromptData is the data for the translate templates.
+type TranslatePromptData struct {
+	Target string               `json:"target"`
+	Text   string               `json:"text"`
+	Facts  []TranslateFactInput `json:"facts"`
+}
+
+// NewTranslatePromptData caps the text and facts the same way for real runs
+// and `cortex prompts test`.
+func NewTranslatePromptData(target, text string, facts []TranslateFactInput) TranslatePromptData {
+	if len(facts) > translateMaxFacts {
+		facts = facts[:translateMaxFacts]
+	}
+	return TranslatePromptData{Target: target, Text: truncateForPrompt(text, translateMaxText), Facts: facts}
+}
+
+// MemoryTranslation is a model's translation of one memory and its facts.
+// Text is empty when the memory is already in the target language.
+type MemoryTranslation struct {
+	SourceLang string               `json:"source_lang"`
+	Text       string               `json:"text,omitempty"`
+	Facts      []TranslateFactInput `json:"facts,omitempty"`
+}
+
+// TranslateMemory translates one memory and its facts with provider.
+// Translated facts whose ID was not in the input, or that lack a predicate
+// or object, are dropped.
+func TranslateMemory(ctx context.Context, provider llm.Provider, data TranslatePromptData) (*MemoryTranslation, error) {
+	prompt, err := RenderPrompt(PromptTranslate, data)
+	if err != nil {
+		return nil, err
+	}
+	translateCtx, cancel := context.WithTimeout(ctx, translateTimeout)
+	defer cancel()
+
+	var out *MemoryTranslation
+	err = llm.CompleteStructured(translateCtx, provider, prompt.User, llm.CompletionOpts{
+		Temperature: 0.1,
+		MaxTokens:   4096,
+		System:      prompt.System,
+		Format:      "json",
+		Task:        llm.TaskTranslate,
+	}, func(reply string) (err error) {
+		out, err = parseTranslateResponse(reply)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LLM translate call: %w", err)
+	}
+
+	sent := make(map[int64]bool, len(data.Facts))
+	for _, f := range data.Facts {
+		sent[f.ID] = true
+	}
+	kept := out.Facts[:0]
+	for _, f := range out.Facts {
+		if sent[f.ID] && f.Predicate != "" && f.Object != "" {
+			if len(f.Subject) > MaxSubjectLength {
+				f.Subject = truncateAtWordBoundary(f.Subject, MaxSubjectLength)
+			}
+			kept = append(kept, f)
+		}
+	}
+	out.Facts = kept
+	return out, nil
+}
+
+// parseTranslateResponse parses the model's JSON (see llm.RepairJSON).
+func parseTranslateResponse(raw string) (*MemoryTranslation, error) {
+	var t MemoryTranslation
+	if err := llm.DecodeJSON(raw, &t); err != nil {
+		return nil, fmt.Errorf("invalid JSON from LLM: %w\nraw: %s", err, truncateForError(raw, 300))
+	}
+	t.SourceLang = strings.ToLower(strings.TrimSpace(t.SourceLang))
+	if t.SourceLang == "" {
+		return nil, fmt.Errorf("LLM response has no source_lang\nraw: %s", truncateForError(raw, 300))
+	}
+	t.Text = strings.TrimSpace(t.Text)
+	for i := range t.Facts {
+		t.Facts[i].Subject = strings.TrimSpace(t.Facts[i].Subject)
+		t.Facts[i].Predicate = strings.TrimSpace(t.Facts[i].Predicate)
+		t.Facts[i].Object = strings.TrimSpace(t.Facts[i].Object)
+	}
+	return &t, nil
+}
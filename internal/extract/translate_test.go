@@ -0,0 +1,56 @@
+package extract
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslateMemory_KeepsOnlySentFacts(t *testing.T) {
+	provider := &mockSummarizeProvider{response: "```json\n" + `{
+		"source_lang": "DE",
+		"text": "Alice lives in Berlin.",
+		"facts": [
+			{"id": 7, "subject": "Alice", "predicate": "lives_in", "object": "Berlin"},
+			{"id": 99, "subject": "Bob", "predicate": "lives_in", "object": "Paris"},
+			{"id": 8, "subject": "Alice", "predicate": "", "object": "x"}
+		]
+	}` + "\n```"}
+	data := NewTranslatePromptData("en", "Alice wohnt in Berlin.", []TranslateFactInput{
+		{ID: 7, Subject: "Alice", Predicate: "wohnt_in", Object: "Berlin"},
+		{ID: 8, Subject: "Alice", Predicate: "mag", Object: "Kaffee"},
+	})
+	got, err := TranslateMemory(context.Background(), provider, data)
+	if err != nil {
+		t.Fatalf("TranslateMemory: %v", err)
+	}
+	if got.SourceLang != "de" || got.Text != "Alice lives in Berlin." {
+		t.Fatalf("unexpected translation: %+v", got)
+	}
+	if len(got.Facts) != 1 || got.Facts[0].ID != 7 {
+		t.Fatalf("expected only fact 7 kept, got %+v", got.Facts)
+	}
+}
+
+func TestParseTranslateResponse(t *testing.T) {
+	got, err := parseTranslateResponse(`{"source_lang": "en"}`)
+	if err != nil || got.SourceLang != "en" || got.Text != "" {
+		t.Fatalf("already-in-language reply: %+v, %v", got, err)
+	}
+	if _, err := parseTranslateResponse(`{"text": "hi"}`); err == nil || !strings.Contains(err.Error(), "source_lang") {
+		t.Fatalf("expected a missing source_lang error, got %v", err)
+	}
+	if _, err := parseTranslateResponse(`not json`); err == nil {
+		t.Fatal("expected invalid JSON error")
+	}
+}
+
+func TestTranslatePrompt_RendersFacts(t *testing.T) {
+	prompt, err := RenderPrompt(PromptTranslate, NewTranslatePromptData("en", "Hallo", []TranslateFactInput{{ID: 3, Predicate: "sagt", Object: "Hallo"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(prompt.User, "TARGET LANGUAGE: en") || !strings.Contains(prompt.User, "id:3 (none) → sagt → Hallo") {
+		t.Fatalf("unexpected prompt:\n%s", prompt.User)
+	}
+}
@@ -0,0 +1,115 @@
+// Package extract — LLM import triage: "is this chunk worth remembering?"
+//
+// TriageChunk asks a model to rate a chunk for novelty against similar
+// stored memories, durability, and specificity. Import uses the mean of the
+// three as a gate; chunks below the threshold never become memories.
+package extract
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+const (
+	// DefaultTriageThreshold is the triage score a chunk needs to be kept.
+	DefaultTriageThreshold = 0.5
+
+	// triageTimeout bounds one triage call; chunks are small.
+	triageTimeout = 45 * time.Second
+
+	// triageMaxChunk and triageMaxSimilar keep the prompt short.
+	triageMaxChunk   = 2000
+	triageMaxSimilar = 3
+)
+
+const triageSystemPrompt = `You decide whether a chunk of text is worth storing in a long-term memory for an AI agent. Rate it on three axes from 0.0 to 1.0:
+
+- novelty: does it add information beyond the SIMILAR MEMORIES already stored? 0 = says nothing new, 1 = entirely new.
+- durability: will it still matter in weeks or months? 0 = transient chatter, logs of routine success, acknowledgements; 1 = decisions, preferences, identities, rules, lasting facts.
+- specificity: is it concrete? 0 = vague or generic; 1 = names, numbers, dates, settings, explicit choices.
+
+Return ONLY a JSON object:
+{"novelty": 0.8, "durability": 0.6, "specificity": 0.9, "reason": "one short sentence"}`
+
+const triageUserTemplate = `SOURCE: {{or .Source "(unknown)"}}
+
+{{if .Similar}}SIMILAR MEMORIES ALREADY STORED:
+{{range .Similar}}- {{truncate 300 .}}
+{{end}}{{else}}SIMILAR MEMORIES ALREADY STORED: none
+
+{{end}}CHUNK:
+---
+{{.Chunk}}
+---
+
+Rate the chunk. Return JSON only.`
+
+// TriagePromptData is the data for the triage templates.
+type TriagePromptData struct {
+	Source  string   `json:"source"`
+	Chunk   string   `json:"chunk"`
+	Similar []string `json:"similar"`
+}
+
+// TriageScore is a model's rating of one chunk. Score is the mean of the
+// three axes, each clamped to [0, 1].
+type TriageScore struct {
+	Novelty     float64 `json:"novelty"`
+	Durability  float64 `json:"durability"`
+	Specificity float64 `json:"specificity"`
+	Score       float64 `json:"score"`
+	Reason      string  `json:"reason,omitempty"`
+}
+
+// NewTriagePromptData caps the chunk and similar memories the same way for
+// real runs and `cortex prompts test`.
+func NewTriagePromptData(source, chunk string, similar []string) TriagePromptData {
+	if len(similar) > triageMaxSimilar {
+		similar = similar[:triageMaxSimilar]
+	}
+	return TriagePromptData{Source: source, Chunk: truncateForPrompt(chunk, triageMaxChunk), Similar: similar}
+}
+
+// TriageChunk rates one chunk with provider.
+func TriageChunk(ctx context.Context, provider llm.Provider, data TriagePromptData) (*TriageScore, error) {
+	prompt, err := RenderPrompt(PromptTriage, data)
+	if err != nil {
+		return nil, err
+	}
+	triageCtx, cancel := context.WithTimeout(ctx, triageTimeout)
+	defer cancel()
+
+	var score *TriageScore
+	err = llm.CompleteStructured(triageCtx, provider, prompt.User, llm.CompletionOpts{
+		Temperature: 0.1,
+		MaxTokens:   256,
+		System:      prompt.System,
+		Format:      "json",
+		Task:        llm.TaskClassify,
+	}, func(reply string) (err error) {
+		score, err = parseTriageResponse(reply)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LLM triage call: %w", err)
+	}
+	return score, nil
+}
+
+// parseTriageResponse parses the model's JSON (see llm.RepairJSON).
+func parseTriageResponse(raw string) (*TriageScore, error) {
+	var s TriageScore
+	if err := llm.DecodeJSON(raw, &s); err != nil {
+		return nil, fmt.Errorf("invalid JSON from LLM: %w\nraw: %s", err, truncateForError(raw, 300))
+	}
+	clamp := func(v float64) float64 { return math.Max(0, math.Min(1, v)) }
+	s.Novelty, s.Durability, s.Specificity = clamp(s.Novelty), clamp(s.Durability), clamp(s.Specificity)
+	s.Score = math.Round((s.Novelty+s.Durability+s.Specificity)/3*1000) / 1000
+	s.Reason = strings.TrimSpace(s.Reason)
+	return &s, nil
+}
@@ -0,0 +1,51 @@
+package extract
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
+
+type mockTriageProvider struct {
+	response string
+	prompt   string
+	opts     llm.CompletionOpts
+}
+
+func (m *mockTriageProvider) Complete(_ context.Context, prompt string, opts llm.CompletionOpts) (string, error) {
+	m.prompt, m.opts = prompt, opts
+	return m.response, nil
+}
+
+func (m *mockTriageProvider) Name() string { return "mock/triage" }
+
+func TestTriageChunk_ScoresAndRendersSimilar(t *testing.T) {
+	usePromptDir(t)
+	provider := &mockTriageProvider{response: "```json\n{\"novelty\": 0.9, \"durability\": 0.6, \"specificity\": 1.4, \"reason\": \" names the DB \"}\n```"}
+	data := NewTriagePromptData("notes.md", "We chose Postgres 16 for the ledger.",
+		[]string{"Ledger uses SQLite", "a", "b", "dropped"})
+
+	score, err := TriageChunk(context.Background(), provider, data)
+	if err != nil {
+		t.Fatalf("TriageChunk: %v", err)
+	}
+	if score.Specificity != 1 || score.Score != 0.833 || score.Reason != "names the DB" {
+		t.Fatalf("unexpected score: %+v", score)
+	}
+	if !strings.Contains(provider.prompt, "- Ledger uses SQLite") || strings.Contains(provider.prompt, "dropped") {
+		t.Fatalf("prompt should list at most %d similar memories:\n%s", triageMaxSimilar, provider.prompt)
+	}
+	if provider.opts.Task != llm.TaskClassify || provider.opts.System != triageSystemPrompt {
+		t.Fatalf("unexpected completion opts: %+v", provider.opts)
+	}
+
+	provider.response = "not json"
+	if _, err := TriageChunk(context.Background(), provider, NewTriagePromptData("", "x", nil)); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+	if !strings.Contains(provider.prompt, "SIMILAR MEMORIES ALREADY STORED: none") {
+		t.Fatalf("prompt without similar memories:\n%s", provider.prompt)
+	}
+}
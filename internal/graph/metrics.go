@@ -0,0 +1,519 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const (
+	defaultMetricsTop = 10
+	// Betweenness is exact up to this many nodes; larger graphs sample
+	// this many BFS sources, which keeps the pass O(pivots × edges).
+	defaultMetricsPivots = 1000
+	metricsClusterHubs   = 3
+)
+
+// MetricsOptions tunes ComputeMetrics.
+type MetricsOptions struct {
+	MinConfidence float64 // ignore edges below this confidence
+	Top           int     // hubs and bridges to report (default 10)
+	Pivots        int     // betweenness sources to sample once the graph is larger (default 1000)
+}
+
+// FactMetric is one fact's place in the graph.
+type FactMetric struct {
+	FactID           int64   `json:"fact_id"`
+	Subject          string  `json:"subject"`
+	Predicate        string  `json:"predicate"`
+	Object           string  `json:"object"`
+	Degree           int     `json:"degree"`
+	DegreeCentrality float64 `json:"degree_centrality"`
+	Betweenness      float64 `json:"betweenness"`
+	Component        int     `json:"component"`
+	ClusterID        int64   `json:"cluster_id,omitempty"`
+	Cluster          string  `json:"cluster,omitempty"`
+}
+
+// BridgeEdge is an edge whose removal splits its component. Cuts is the
+// size of the smaller side it would cut off.
+type BridgeEdge struct {
+	EdgeID       int64   `json:"edge_id"`
+	SourceFactID int64   `json:"source_fact_id"`
+	TargetFactID int64   `json:"target_fact_id"`
+	EdgeType     string  `json:"edge_type"`
+	Confidence   float64 `json:"confidence"`
+	Cuts         int     `json:"cuts"`
+}
+
+// ComponentSummary is one connected component.
+type ComponentSummary struct {
+	ID    int   `json:"id"`
+	Facts int   `json:"facts"`
+	Edges int   `json:"edges"`
+	Hub   int64 `json:"hub_fact_id"`
+}
+
+// ClusterHubs lists the most central facts of one topic cluster.
+type ClusterHubs struct {
+	ClusterID int64        `json:"cluster_id"`
+	Name      string       `json:"name"`
+	Facts     int          `json:"facts"`
+	Hubs      []FactMetric `json:"hubs"`
+}
+
+// Metrics summarizes the structure of the fact graph: which facts the rest
+// depend on (hubs, by betweenness then degree), which single edges hold
+// parts of it together (bridges), and how it splits into components.
+type Metrics struct {
+	Facts               int                `json:"facts"`
+	Edges               int                `json:"edges"`
+	ComponentCount      int                `json:"component_count"`
+	Components          []ComponentSummary `json:"components"`
+	BetweennessSampled  bool               `json:"betweenness_sampled"`
+	BetweennessSources  int                `json:"betweenness_sources"`
+	Hubs                []FactMetric       `json:"hubs"`
+	Bridges             []BridgeEdge       `json:"bridges"`
+	BridgeCount         int                `json:"bridge_count"`
+	Clusters            []ClusterHubs      `json:"clusters"`
+	SkippedSuperseded   int                `json:"skipped_superseded_edges,omitempty"`
+	SkippedLowConfident int                `json:"skipped_low_confidence_edges,omitempty"`
+}
+
+// ComputeMetrics loads the active fact graph in ctx's namespace and computes
+// its centrality, components, and bridges. Edges touching superseded facts
+// are left out, and the graph is treated as undirected.
+func ComputeMetrics(ctx context.Context, st *store.SQLiteStore, opts MetricsOptions) (*Metrics, error) {
+	if opts.Top <= 0 {
+		opts.Top = defaultMetricsTop
+	}
+	if opts.Pivots <= 0 {
+		opts.Pivots = defaultMetricsPivots
+	}
+
+	edges, err := st.FindEdges(ctx, store.EdgeFilter{})
+	if err != nil {
+		return nil, err
+	}
+	idSet := map[int64]bool{}
+	for _, e := range edges {
+		idSet[e.SourceFactID] = true
+		idSet[e.TargetFactID] = true
+	}
+	ids := make([]int64, 0, len(idSet))
+	for id := range idSet {
+		ids = append(ids, id)
+	}
+	facts, err := loadMetricFacts(ctx, st, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Metrics{}
+	var kept []store.FactEdge
+	for _, e := range edges {
+		src, dst := facts[e.SourceFactID], facts[e.TargetFactID]
+		switch {
+		case src == nil || dst == nil:
+			m.SkippedSuperseded++
+		case e.Confidence < opts.MinConfidence:
+			m.SkippedLowConfident++
+		default:
+			kept = append(kept, e)
+		}
+	}
+
+	g := newMetricGraph(kept)
+	m.Facts, m.Edges = len(g.ids), len(kept)
+	betweenness, sources := g.betweenness(opts.Pivots)
+	m.BetweennessSources = sources
+	m.BetweennessSampled = sources < len(g.ids)
+	comp, compSizes := g.components()
+	m.ComponentCount = len(compSizes)
+
+	metrics := make([]FactMetric, len(g.ids))
+	for i, id := range g.ids {
+		f := facts[id]
+		fm := FactMetric{
+			FactID:      id,
+			Subject:     f.subject,
+			Predicate:   f.predicate,
+			Object:      f.object,
+			Degree:      len(g.adj[i]),
+			Betweenness: betweenness[i],
+			Component:   comp[i] + 1,
+			ClusterID:   f.clusterID,
+			Cluster:     f.cluster,
+		}
+		if n := len(g.ids); n > 1 {
+			fm.DegreeCentrality = float64(fm.Degree) / float64(n-1)
+		}
+		metrics[i] = fm
+	}
+	ranked := make([]FactMetric, len(metrics))
+	copy(ranked, metrics)
+	sort.SliceStable(ranked, func(a, b int) bool { return hubLess(ranked[a], ranked[b]) })
+
+	m.Hubs = firstN(ranked, opts.Top)
+
+	m.Components = make([]ComponentSummary, len(compSizes))
+	for c := range compSizes {
+		m.Components[c] = ComponentSummary{ID: c + 1, Facts: compSizes[c]}
+	}
+	for _, e := range kept {
+		m.Components[comp[g.index[e.SourceFactID]]].Edges++
+	}
+	for _, fm := range ranked {
+		if c := &m.Components[fm.Component-1]; c.Hub == 0 {
+			c.Hub = fm.FactID
+		}
+	}
+	sort.SliceStable(m.Components, func(a, b int) bool {
+		if m.Components[a].Facts != m.Components[b].Facts {
+			return m.Components[a].Facts > m.Components[b].Facts
+		}
+		return m.Components[a].ID < m.Components[b].ID
+	})
+	m.Components = firstN(m.Components, opts.Top)
+
+	bridges := g.bridges(compSizes, comp)
+	m.BridgeCount = len(bridges)
+	sort.SliceStable(bridges, func(a, b int) bool {
+		if bridges[a].Cuts != bridges[b].Cuts {
+			return bridges[a].Cuts > bridges[b].Cuts
+		}
+		return bridges[a].EdgeID < bridges[b].EdgeID
+	})
+	m.Bridges = firstN(bridges, opts.Top)
+
+	byCluster := map[int64]*ClusterHubs{}
+	var clusterOrder []int64
+	for _, fm := range ranked {
+		if fm.ClusterID == 0 {
+			continue
+		}
+		ch := byCluster[fm.ClusterID]
+		if ch == nil {
+			ch = &ClusterHubs{ClusterID: fm.ClusterID, Name: fm.Cluster}
+			byCluster[fm.ClusterID] = ch
+			clusterOrder = append(clusterOrder, fm.ClusterID)
+		}
+		ch.Facts++
+		if len(ch.Hubs) < metricsClusterHubs {
+			ch.Hubs = append(ch.Hubs, fm)
+		}
+	}
+	m.Clusters = make([]ClusterHubs, 0, len(clusterOrder))
+	for _, id := range clusterOrder {
+		m.Clusters = append(m.Clusters, *byCluster[id])
+	}
+	return m, nil
+}
+
+func hubLess(a, b FactMetric) bool {
+	if a.Betweenness != b.Betweenness {
+		return a.Betweenness > b.Betweenness
+	}
+	if a.Degree != b.Degree {
+		return a.Degree > b.Degree
+	}
+	return a.FactID < b.FactID
+}
+
+func firstN[T any](items []T, n int) []T {
+	if len(items) > n {
+		items = items[:n]
+	}
+	if items == nil {
+		items = []T{}
+	}
+	return items
+}
+
+type metricFact struct {
+	subject, predicate, object string
+	clusterID                  int64
+	cluster                    string
+}
+
+// loadMetricFacts returns the active facts among ids with the cluster each
+// belongs to most strongly. Superseded facts are absent from the map.
+func loadMetricFacts(ctx context.Context, st *store.SQLiteStore, ids []int64) (map[int64]*metricFact, error) {
+	facts := make(map[int64]*metricFact, len(ids))
+	db := st.GetDB()
+	clusters := st.ClusterTablesAvailable(ctx)
+	for start := 0; start < len(ids); start += 500 {
+		end := start + 500
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk := ids[start:end]
+		ph := strings.TrimSuffix(strings.Repeat("?,", len(chunk)), ",")
+		args := make([]interface{}, len(chunk))
+		for i, id := range chunk {
+			args[i] = id
+		}
+
+		rows, err := db.QueryContext(ctx,
+			`SELECT id, subject, predicate, object FROM facts WHERE superseded_by IS NULL AND id IN (`+ph+`)`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("loading graph facts: %w", err)
+		}
+		for rows.Next() {
+			var id int64
+			f := &metricFact{}
+			if err := rows.Scan(&id, &f.subject, &f.predicate, &f.object); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scanning graph fact: %w", err)
+			}
+			facts[id] = f
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		if !clusters {
+			continue
+		}
+
+		rows, err = db.QueryContext(ctx,
+			`SELECT fc.fact_id, c.id, c.name FROM fact_clusters fc JOIN clusters c ON c.id = fc.cluster_id
+			 WHERE fc.fact_id IN (`+ph+`) ORDER BY fc.relevance ASC, c.id DESC`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("loading fact clusters: %w", err)
+		}
+		for rows.Next() {
+			var factID, clusterID int64
+			var name string
+			if err := rows.Scan(&factID, &clusterID, &name); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scanning fact cluster: %w", err)
+			}
+			// Rows come weakest first, so the strongest membership wins.
+			if f := facts[factID]; f != nil {
+				f.clusterID, f.cluster = clusterID, name
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return facts, nil
+}
+
+// metricGraph is the undirected simple graph behind the edges. Parallel
+// edges between two facts collapse into one adjacency, counted in mult so
+// they are never reported as bridges.
+type metricGraph struct {
+	ids   []int64
+	index map[int64]int
+	adj   [][]int
+	mult  map[[2]int]int
+	edge  map[[2]int]store.FactEdge
+}
+
+func newMetricGraph(edges []store.FactEdge) *metricGraph {
+	g := &metricGraph{index: map[int64]int{}, mult: map[[2]int]int{}, edge: map[[2]int]store.FactEdge{}}
+	var ids []int64
+	for _, e := range edges {
+		for _, id := range []int64{e.SourceFactID, e.TargetFactID} {
+			if _, ok := g.index[id]; !ok {
+				g.index[id] = -1
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+	g.ids = ids
+	for i, id := range ids {
+		g.index[id] = i
+	}
+	g.adj = make([][]int, len(ids))
+	for _, e := range edges {
+		a, b := g.index[e.SourceFactID], g.index[e.TargetFactID]
+		if a == b {
+			continue
+		}
+		key := pairKey(a, b)
+		if g.mult[key] == 0 {
+			g.adj[a] = append(g.adj[a], b)
+			g.adj[b] = append(g.adj[b], a)
+			g.edge[key] = e
+		}
+		g.mult[key]++
+	}
+	return g
+}
+
+func pairKey(a, b int) [2]int {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int{a, b}
+}
+
+// components labels each node with its component, numbered in node order,
+// and returns each component's size.
+func (g *metricGraph) components() ([]int, []int) {
+	comp := make([]int, len(g.ids))
+	for i := range comp {
+		comp[i] = -1
+	}
+	var sizes []int
+	for start := range g.ids {
+		if comp[start] >= 0 {
+			continue
+		}
+		c := len(sizes)
+		sizes = append(sizes, 0)
+		queue := []int{start}
+		comp[start] = c
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			sizes[c]++
+			for _, w := range g.adj[v] {
+				if comp[w] < 0 {
+					comp[w] = c
+					queue = append(queue, w)
+				}
+			}
+		}
+	}
+	return comp, sizes
+}
+
+// betweenness runs Brandes' algorithm from every node, or from maxSources
+// evenly spaced nodes (scaled up to estimate the full sum) when the graph is
+// larger. Scores are normalized to [0, 1] and the number of sources used is
+// returned.
+func (g *metricGraph) betweenness(maxSources int) ([]float64, int) {
+	n := len(g.ids)
+	cb := make([]float64, n)
+	if n < 3 {
+		return cb, n
+	}
+	sources := make([]int, 0, n)
+	if n <= maxSources {
+		for i := 0; i < n; i++ {
+			sources = append(sources, i)
+		}
+	} else {
+		for k := 0; k < maxSources; k++ {
+			sources = append(sources, k*n/maxSources)
+		}
+	}
+
+	sigma := make([]float64, n)
+	dist := make([]int, n)
+	delta := make([]float64, n)
+	preds := make([][]int, n)
+	order := make([]int, 0, n)
+	for _, s := range sources {
+		for i := 0; i < n; i++ {
+			sigma[i], dist[i], delta[i] = 0, -1, 0
+			preds[i] = preds[i][:0]
+		}
+		order = order[:0]
+		sigma[s], dist[s] = 1, 0
+		queue := []int{s}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			order = append(order, v)
+			for _, w := range g.adj[v] {
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+		for i := len(order) - 1; i >= 0; i-- {
+			w := order[i]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != s {
+				cb[w] += delta[w]
+			}
+		}
+	}
+
+	// Each undirected pair was counted from both ends; sampling sees
+	// len(sources)/n of them.
+	scale := float64(n) / float64(len(sources)) / 2
+	norm := float64(n-1) * float64(n-2) / 2
+	for i := range cb {
+		cb[i] = cb[i] * scale / norm
+	}
+	return cb, len(sources)
+}
+
+// bridges finds the edges whose removal disconnects their component, using
+// an iterative Tarjan DFS so deep chains can't overflow the stack.
+func (g *metricGraph) bridges(compSizes, comp []int) []BridgeEdge {
+	n := len(g.ids)
+	disc := make([]int, n)
+	low := make([]int, n)
+	sub := make([]int, n)
+	for i := range disc {
+		disc[i] = -1
+	}
+	type frame struct{ v, parent, next int }
+	var out []BridgeEdge
+	timer := 0
+	for root := 0; root < n; root++ {
+		if disc[root] >= 0 {
+			continue
+		}
+		disc[root], low[root], sub[root] = timer, timer, 1
+		timer++
+		stack := []frame{{v: root, parent: -1}}
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.next < len(g.adj[top.v]) {
+				w := g.adj[top.v][top.next]
+				top.next++
+				if w == top.parent {
+					continue
+				}
+				if disc[w] >= 0 {
+					low[top.v] = min(low[top.v], disc[w])
+					continue
+				}
+				disc[w], low[w], sub[w] = timer, timer, 1
+				timer++
+				stack = append(stack, frame{v: w, parent: top.v})
+				continue
+			}
+			v, p := top.v, top.parent
+			stack = stack[:len(stack)-1]
+			if p < 0 {
+				continue
+			}
+			low[p] = min(low[p], low[v])
+			sub[p] += sub[v]
+			key := pairKey(p, v)
+			if low[v] > disc[p] && g.mult[key] == 1 {
+				e := g.edge[key]
+				out = append(out, BridgeEdge{
+					EdgeID:       e.ID,
+					SourceFactID: e.SourceFactID,
+					TargetFactID: e.TargetFactID,
+					EdgeType:     string(e.EdgeType),
+					Confidence:   e.Confidence,
+					Cuts:         min(sub[v], compSizes[comp[v]]-sub[v]),
+				})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestComputeMetrics(t *testing.T) {
+	st := newTestStore(t)
+	defer st.Close()
+	ctx := context.Background()
+
+	memID, err := st.AddMemory(ctx, &store.Memory{Content: "metrics seed", SourceFile: "metrics.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]int64, 10)
+	for i := range ids {
+		ids[i], err = st.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: fmt.Sprintf("s%d", i), Predicate: "is", Object: "x", FactType: "kv"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := func(a, b int, typ store.EdgeType, conf float64) {
+		t.Helper()
+		if err := st.AddEdge(ctx, &store.FactEdge{SourceFactID: ids[a], TargetFactID: ids[b], EdgeType: typ, Confidence: conf}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Two triangles joined by one bridge (2–3), the second with a doubled
+	// edge; a separate pair; an edge to a superseded fact; a weak edge.
+	link(0, 1, store.EdgeTypeSupports, 0.9)
+	link(1, 2, store.EdgeTypeSupports, 0.9)
+	link(2, 0, store.EdgeTypeSupports, 0.9)
+	link(2, 3, store.EdgeTypeRelatesTo, 0.9)
+	link(3, 4, store.EdgeTypeSupports, 0.9)
+	link(4, 5, store.EdgeTypeSupports, 0.9)
+	link(5, 4, store.EdgeTypeRelatesTo, 0.9)
+	link(5, 3, store.EdgeTypeSupports, 0.9)
+	link(6, 7, store.EdgeTypeSupports, 0.9)
+	link(8, 0, store.EdgeTypeSupports, 0.9)
+	link(9, 1, store.EdgeTypeSupports, 0.1)
+	if err := st.SupersedeFact(ctx, ids[8], ids[1], "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := ComputeMetrics(ctx, st, MetricsOptions{MinConfidence: 0.5})
+	if err != nil {
+		t.Fatalf("ComputeMetrics: %v", err)
+	}
+	if m.Facts != 8 || m.Edges != 9 || m.ComponentCount != 2 || m.SkippedLowConfident != 1 || m.SkippedSuperseded == 0 {
+		t.Fatalf("summary = %+v", m)
+	}
+	if m.BetweennessSampled {
+		t.Fatal("a small graph should get exact betweenness")
+	}
+	if m.Hubs[0].FactID != ids[2] || m.Hubs[1].FactID != ids[3] || m.Hubs[0].Degree != 3 {
+		t.Fatalf("hubs = %+v", m.Hubs[:2])
+	}
+	// Fact 2 lies on every path between {0,1} and {3,4,5}: 6 of 21 pairs.
+	if got := m.Hubs[0].Betweenness; math.Abs(got-6.0/21) > 1e-9 {
+		t.Fatalf("betweenness = %v, want %v", got, 6.0/21)
+	}
+	if m.BridgeCount != 2 || m.Bridges[0].SourceFactID != ids[2] || m.Bridges[0].Cuts != 3 || m.Bridges[1].Cuts != 1 {
+		t.Fatalf("bridges = %+v", m.Bridges)
+	}
+	if c := m.Components[0]; c.Facts != 6 || c.Edges != 8 || c.Hub != ids[2] {
+		t.Fatalf("largest component = %+v", c)
+	}
+
+	sampled, err := ComputeMetrics(ctx, st, MetricsOptions{MinConfidence: 0.5, Pivots: 4, Top: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sampled.BetweennessSampled || sampled.BetweennessSources != 4 || len(sampled.Hubs) != 1 || len(sampled.Bridges) != 1 {
+		t.Fatalf("sampled = %+v", sampled)
+	}
+}
+
+func TestMetricGraphBridgesOnLongChain(t *testing.T) {
+	// A chain deep enough to overflow a recursive DFS.
+	const n = 200000
+	edges := make([]store.FactEdge, n-1)
+	for i := range edges {
+		edges[i] = store.FactEdge{ID: int64(i + 1), SourceFactID: int64(i + 1), TargetFactID: int64(i + 2)}
+	}
+	g := newMetricGraph(edges)
+	comp, sizes := g.components()
+	bridges := g.bridges(sizes, comp)
+	if len(sizes) != 1 || len(bridges) != n-1 {
+		t.Fatalf("components = %d, bridges = %d", len(sizes), len(bridges))
+	}
+}
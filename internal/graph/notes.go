@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// attachNodeNotes fills ExportNode.Notes with the annotations on each fact
+// node. Databases created before the annotations table are left untouched.
+func attachNodeNotes(ctx context.Context, db *sql.DB, nodes []ExportNode) {
+	if len(nodes) == 0 {
+		return
+	}
+	index := make(map[int64][]int, len(nodes))
+	args := make([]interface{}, 0, len(nodes))
+	for i, n := range nodes {
+		if _, seen := index[n.ID]; !seen {
+			args = append(args, n.ID)
+		}
+		index[n.ID] = append(index[n.ID], i)
+	}
+
+	rows, err := db.QueryContext(ctx,
+		`SELECT id, target_type, target_id, body, label, author, created_at
+		 FROM annotations
+		 WHERE target_type = 'fact' AND target_id IN (`+strings.TrimSuffix(strings.Repeat("?,", len(args)), ",")+`)
+		 ORDER BY id ASC`,
+		args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a store.Annotation
+		if err := rows.Scan(&a.ID, &a.TargetType, &a.TargetID, &a.Body, &a.Label, &a.Author, &a.CreatedAt); err != nil {
+			continue
+		}
+		for _, i := range index[a.TargetID] {
+			nodes[i].Notes = append(nodes[i].Notes, a)
+		}
+	}
+}
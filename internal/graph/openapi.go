@@ -0,0 +1,134 @@
+package graph
+
+import (
+	"github.com/hurttlocker/cortex/internal/openapi"
+)
+
+// ErrorResponse is the body of every non-2xx API response.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// StatsResponse is the /api/stats payload.
+type StatsResponse struct {
+	Facts         int     `json:"facts" doc:"Active (not superseded) facts"`
+	Memories      int     `json:"memories"`
+	Edges         int     `json:"edges"`
+	AvgConfidence float64 `json:"avg_confidence" doc:"Mean confidence of active facts"`
+}
+
+// OpenAPISpec describes the graph API routes served by NewHandler. Response
+// schemas are reflected from the handlers' Go types; parameter bounds come
+// from the same constants the handlers clamp to.
+func OpenAPISpec(version string) *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:       "Cortex HTTP API",
+		Description: "Read-only knowledge graph, search, and stats API served by `cortex serve`, `cortex graph --serve`, and `cortex daemon`.",
+		Version:     version,
+	})
+	doc.Servers = []openapi.Server{{URL: "http://localhost:8090", Description: "cortex serve default port"}}
+	errs := func(codes ...string) map[string]*openapi.Response {
+		out := map[string]*openapi.Response{}
+		for _, code := range codes {
+			desc := map[string]string{"400": "Invalid or missing parameter", "404": "Not found", "500": "Store error"}[code]
+			out[code] = doc.JSONResponse(desc, ErrorResponse{})
+		}
+		return out
+	}
+	op := func(id, summary string, params []openapi.Parameter, ok any, codes ...string) *openapi.Operation {
+		responses := errs(codes...)
+		responses["200"] = doc.JSONResponse("OK", ok)
+		return &openapi.Operation{OperationID: id, Summary: summary, Tags: []string{"graph"}, Parameters: params, Responses: responses}
+	}
+	offset := intParam("offset", "Number of results to skip", 0, 0, maxGraphOffset)
+
+	doc.Get("/api/graph", op("getGraph", "Graph around a fact (fact_id) or a subject", []openapi.Parameter{
+		{Name: "fact_id", In: "query", Description: "Root fact; required unless subject is set", Schema: &openapi.Schema{Type: "integer", Format: "int64"}},
+		{Name: "subject", In: "query", Description: "Subject to graph instead of a single fact", Schema: &openapi.Schema{Type: "string"}},
+		intParam("depth", "Hops from the root fact", defaultGraphDepth, 1, maxGraphDepth),
+		floatParam("min_confidence", "Drop facts and edges below this confidence", 0),
+		intParam("limit", "Facts per page in subject mode", defaultSubjectGraphLimit, 1, maxSubjectGraphLimit),
+		offset,
+		{Name: "agent", In: "query", Description: "Only facts from this agent (subject mode)", Schema: &openapi.Schema{Type: "string"}},
+	}, ExportResult{}, "400", "500"))
+
+	doc.Get("/api/search", op("searchFacts", "Keyword search over memories, returning the facts they hold", []openapi.Parameter{
+		{Name: "q", In: "query", Required: true, Description: "Search query", Schema: &openapi.Schema{Type: "string"}},
+		intParam("limit", "Maximum memories to search", defaultSearchLimit, 1, maxSearchLimit),
+		offset,
+		cursorParam(),
+	}, SearchResult{}, "400", "500"))
+
+	doc.Get("/api/facts", op("listFacts", "Facts for a subject or a memory", []openapi.Parameter{
+		{Name: "subject", In: "query", Description: "Subject to list facts for; required unless memory_id is set", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "memory_id", In: "query", Description: "Memory to list facts for", Schema: &openapi.Schema{Type: "integer", Format: "int64"}},
+		intParam("limit", "Facts per page (all when unset)", 0, 1, maxSearchLimit),
+		offset,
+		cursorParam(),
+	}, FactsResponse{}, "400", "500"))
+
+	doc.Get("/api/cluster", op("getSampleCluster", "Subjects with several facts, as a graph", []openapi.Parameter{
+		{Name: "q", In: "query", Description: "Only subjects containing this text", Schema: &openapi.Schema{Type: "string"}},
+		intParam("limit", "Maximum facts", defaultClusterLimit, 1, maxClusterLimit),
+		offset,
+	}, ExportResult{}, "500"))
+
+	doc.Get("/api/clusters", op("listClusters", "Topic clusters", []openapi.Parameter{
+		{Name: "q", In: "query", Description: "Only clusters whose name or aliases contain this text", Schema: &openapi.Schema{Type: "string"}},
+		intParam("limit", "Clusters per page", defaultClustersListLimit, 1, maxClustersListLimit),
+		offset,
+	}, ClustersResponse{}, "500"))
+
+	doc.Get("/api/clusters/{id}", op("getCluster", "One topic cluster with its facts and graph", []openapi.Parameter{
+		{Name: "id", In: "path", Required: true, Description: "Cluster ID", Schema: &openapi.Schema{Type: "integer", Format: "int64"}},
+		intParam("limit", "Facts per page", defaultClusterFactsLimit, 1, maxClusterFactsLimit),
+		offset,
+	}, ClusterDetailResponse{}, "400", "404", "500"))
+
+	doc.Get("/api/stats", op("getStats", "Store totals", nil, StatsResponse{}))
+
+	doc.Get("/api/impact", op("getImpact", "Blast radius of a subject, grouped by relationship", []openapi.Parameter{
+		{Name: "subject", In: "query", Required: true, Schema: &openapi.Schema{Type: "string"}},
+		intParam("depth", "Hops from the subject's facts", defaultImpactDepth, 1, maxGraphDepth),
+		intParam("limit", "Maximum facts", defaultImpactLimit, 1, maxImpactLimit),
+		offset,
+		floatParam("min_confidence", "Drop facts below this confidence", 0),
+	}, ImpactResult{}, "400", "404", "500"))
+
+	doc.Get("/api/timeline", op("getTimeline", "How a subject's facts changed over time", []openapi.Parameter{
+		{Name: "subject", In: "query", Required: true, Schema: &openapi.Schema{Type: "string"}},
+		{Name: "from", In: "query", Description: "First day, YYYY-MM-DD (default 30 days ago)", Schema: &openapi.Schema{Type: "string", Format: "date"}},
+		{Name: "to", In: "query", Description: "Last day, YYYY-MM-DD (default today)", Schema: &openapi.Schema{Type: "string", Format: "date"}},
+		{Name: "bucket", In: "query", Schema: &openapi.Schema{Type: "string", Enum: []string{"day", "week", "month"}, Default: "day"}},
+		floatParam("min_confidence", "Drop facts below this confidence", defaultTimelineMinConfidence),
+		{Name: "related", In: "query", Description: "Include related subjects per bucket", Schema: &openapi.Schema{Type: "boolean", Default: true}},
+	}, TimelineResponse{}, "400", "404", "500"))
+
+	spec := &openapi.Operation{OperationID: "getOpenAPISpec", Summary: "This OpenAPI document", Tags: []string{"meta"},
+		Responses: map[string]*openapi.Response{"200": {Description: "OpenAPI 3 document", Content: map[string]openapi.MediaType{"application/json": {Schema: &openapi.Schema{Type: "object"}}}}}}
+	doc.Get("/api/openapi.json", spec)
+
+	return doc
+}
+
+func intParam(name, desc string, def, min, max int) openapi.Parameter {
+	lo, hi := float64(min), float64(max)
+	s := &openapi.Schema{Type: "integer", Minimum: &lo, Maximum: &hi}
+	if def > 0 {
+		s.Default = def
+	}
+	return openapi.Parameter{Name: name, In: "query", Description: desc, Schema: s}
+}
+
+func floatParam(name, desc string, def float64) openapi.Parameter {
+	lo, hi := 0.0, 1.0
+	s := &openapi.Schema{Type: "number", Minimum: &lo, Maximum: &hi}
+	if def > 0 {
+		s.Default = def
+	}
+	return openapi.Parameter{Name: name, In: "query", Description: desc, Schema: s}
+}
+
+func cursorParam() openapi.Parameter {
+	return openapi.Parameter{Name: "cursor", In: "query", Description: "Opaque next_cursor from the previous page", Schema: &openapi.Schema{Type: "string"}}
+}
@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/openapi"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestOpenAPISpec_CoversHandlerRoutes(t *testing.T) {
+	st := newTestStore(t)
+	defer st.Close()
+	mux := NewHandler(ServerConfig{Store: st})
+
+	spec := OpenAPISpec("test")
+	for path := range spec.Paths {
+		req := httptest.NewRequest(http.MethodGet, strings.ReplaceAll(path, "{id}", "1"), nil)
+		if _, pattern := mux.Handler(req); pattern == "/" || pattern == "" {
+			t.Errorf("spec path %s is not routed by NewHandler", path)
+		}
+	}
+	for _, route := range []string{"/api/graph", "/api/search", "/api/facts", "/api/cluster", "/api/clusters", "/api/clusters/{id}", "/api/stats", "/api/impact", "/api/timeline"} {
+		if spec.Paths[route] == nil || spec.Paths[route].Get == nil {
+			t.Errorf("spec is missing GET %s", route)
+		}
+	}
+}
+
+// Every key a handler actually returns must be a documented property.
+func TestOpenAPISpec_MatchesResponses(t *testing.T) {
+	st := newTestStore(t)
+	defer st.Close()
+	ctx := context.Background()
+	memID, _ := st.AddMemory(ctx, &store.Memory{Content: "Alice works at Acme", SourceFile: "team.md"})
+	if _, err := st.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "Alice", Predicate: "works at", Object: "Acme", FactType: "relationship", Confidence: 0.9}); err != nil {
+		t.Fatal(err)
+	}
+
+	mux := NewHandler(ServerConfig{Store: st})
+	spec := OpenAPISpec("test")
+	for _, tc := range []struct{ path, url string }{
+		{"/api/stats", "/api/stats"},
+		{"/api/facts", "/api/facts?subject=Alice"},
+		{"/api/search", "/api/search?q=Alice"},
+		{"/api/graph", "/api/graph?fact_id=1"},
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.url, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", tc.url, rec.Code, rec.Body.String())
+		}
+		var body map[string]json.RawMessage
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: %v", tc.url, err)
+		}
+		schema := resolveSchema(spec, spec.Paths[tc.path].Get.Responses["200"].Content["application/json"].Schema)
+		for key := range body {
+			if schema.Properties[key] == nil {
+				t.Errorf("%s returns %q, which the spec does not document", tc.path, key)
+			}
+		}
+		for _, key := range schema.Required {
+			if _, ok := body[key]; !ok {
+				t.Errorf("%s: required property %q missing from response", tc.path, key)
+			}
+		}
+	}
+}
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	st := newTestStore(t)
+	defer st.Close()
+	rec := httptest.NewRecorder()
+	NewHandler(ServerConfig{Store: st, Version: "9.9.9"}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	var doc openapi.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decoding spec: %v", err)
+	}
+	if doc.OpenAPI != openapi.Version || doc.Info.Version != "9.9.9" || doc.Components.Schemas["ExportResult"] == nil {
+		t.Fatalf("unexpected spec: %+v", doc.Info)
+	}
+}
+
+func resolveSchema(doc *openapi.Document, s *openapi.Schema) *openapi.Schema {
+	if s.Ref != "" {
+		return doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
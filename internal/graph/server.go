@@ -26,7 +26,10 @@ var visualizerFS embed.FS
 type ServerConfig struct {
 	Store       *store.SQLiteStore
 	Port        int
-	AgentFilter string // if set, all API responses are scoped to this agent
+	AgentFilter string       // if set, all API responses are scoped to this agent
+	Readiness   http.Handler // optional GET /readyz probe
+	Status      http.Handler // optional GET /status store summary
+	Version     string       // reported in /api/openapi.json
 }
 
 // ExportNode is the visualization-friendly format for a fact.
@@ -46,6 +49,8 @@ type ExportNode struct {
 	LastUpdated  string   `json:"last_updated,omitempty"`
 	SourceTypes  []string `json:"source_types,omitempty"`
 	Depth        int      `json:"depth,omitempty"`
+
+	Notes []store.Annotation `json:"notes,omitempty"`
 }
 
 // ExportEdge is the visualization-friendly format for an edge.
@@ -92,6 +97,15 @@ const (
 
 // Serve starts the graph visualization web server.
 func Serve(cfg ServerConfig) error {
+	addr := fmt.Sprintf(":%d", cfg.Port)
+	fmt.Printf("🧠 Cortex graph visualizer: http://localhost%s\n", addr)
+	fmt.Printf("   Open in browser to explore your knowledge graph in 2D/3D.\n")
+	return http.ListenAndServe(addr, NewHandler(cfg))
+}
+
+// NewHandler returns the graph visualizer UI and API routes without binding a
+// port, so other servers (e.g. cortex daemon) can mount them. cfg.Port is ignored.
+func NewHandler(cfg ServerConfig) *http.ServeMux {
 	mux := http.NewServeMux()
 
 	// Middleware: if server-level agent filter is set, inject it as default query param
@@ -176,10 +190,29 @@ func Serve(cfg ServerConfig) error {
 		handleTimelineAPI(w, r, cfg.Store)
 	}))
 
-	addr := fmt.Sprintf(":%d", cfg.Port)
-	fmt.Printf("🧠 Cortex graph visualizer: http://localhost%s\n", addr)
-	fmt.Printf("   Open in browser to explore your knowledge graph in 2D/3D.\n")
-	return http.ListenAndServe(addr, mux)
+	// OpenAPI document for the routes above, for generated clients and
+	// API gateways.
+	mux.HandleFunc("/api/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		data, err := OpenAPISpec(cfg.Version).JSON()
+		if err != nil {
+			writeJSON(w, 500, ErrorResponse{Error: err.Error()})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Write(data)
+	})
+
+	// Readiness probe — the graph API reads SQLite directly, so it is ready
+	// as soon as the store answers.
+	if cfg.Readiness != nil {
+		mux.Handle("/readyz", cfg.Readiness)
+	}
+	if cfg.Status != nil {
+		mux.Handle("/status", cfg.Status)
+	}
+
+	return mux
 }
 
 func handleGraphAPI(w http.ResponseWriter, r *http.Request, st *store.SQLiteStore) {
@@ -287,6 +320,8 @@ func handleGraphAPI(w http.ResponseWriter, r *http.Request, st *store.SQLiteStor
 		}
 	}
 
+	attachNodeNotes(ctx, st.GetDB(), result.Nodes)
+
 	// Keep only edges where both endpoints are present.
 	filteredEdges := result.Edges[:0]
 	for _, e := range result.Edges {
@@ -351,16 +386,22 @@ type SearchMemory struct {
 }
 
 type FactsResponse struct {
-	Facts []SearchFact `json:"facts"`
-	Total int          `json:"total"`
+	Facts      []SearchFact `json:"facts"`
+	Total      int          `json:"total"`
+	NextCursor string       `json:"next_cursor,omitempty"`
 }
 
+// graphFactsOrdering names the /api/facts sort (confidence, then newest ID)
+// inside its page cursors.
+const graphFactsOrdering = "graph:facts"
+
 // SearchResult is the search API response.
 type SearchResult struct {
 	Facts          []SearchFact   `json:"facts"`
 	Memories       []SearchMemory `json:"memories,omitempty"`
 	MatchedNodeIDs []int64        `json:"matched_node_ids,omitempty"`
 	Total          int            `json:"total"`
+	NextCursor     string         `json:"next_cursor,omitempty"`
 }
 
 func handleFactsAPI(w http.ResponseWriter, r *http.Request, st *store.SQLiteStore) {
@@ -448,7 +489,38 @@ func handleFactsAPI(w http.ResponseWriter, r *http.Request, st *store.SQLiteStor
 		return facts[i].Confidence > facts[j].Confidence
 	})
 
-	writeJSON(w, 200, FactsResponse{Facts: facts, Total: len(facts)})
+	// Paging is opt-in: without limit/offset/cursor every fact is returned.
+	total := len(facts)
+	start := parseBoundedInt(r.URL.Query().Get("offset"), 0, 0, maxGraphOffset)
+	if cursor := strings.TrimSpace(r.URL.Query().Get("cursor")); cursor != "" {
+		afterID, err := store.DecodeCursor(cursor, graphFactsOrdering)
+		if err != nil {
+			writeJSON(w, 400, map[string]string{"error": err.Error()})
+			return
+		}
+		start = -1
+		for i, f := range facts {
+			if f.ID == afterID {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			writeJSON(w, 400, map[string]string{"error": store.ErrStaleCursor.Error()})
+			return
+		}
+	}
+	if start > len(facts) {
+		start = len(facts)
+	}
+	facts = facts[start:]
+	nextCursor := ""
+	if limit := parseBoundedInt(r.URL.Query().Get("limit"), 0, 1, maxSearchLimit); limit > 0 && len(facts) > limit {
+		facts = facts[:limit]
+		nextCursor = store.EncodeCursor(graphFactsOrdering, facts[limit-1].ID)
+	}
+
+	writeJSON(w, 200, FactsResponse{Facts: facts, Total: total, NextCursor: nextCursor})
 }
 
 func handleSearchAPI(w http.ResponseWriter, r *http.Request, st *store.SQLiteStore) {
@@ -470,12 +542,20 @@ func handleSearchAPI(w http.ResponseWriter, r *http.Request, st *store.SQLiteSto
 
 	ctx := context.Background()
 	engine := searchpkg.NewEngine(st)
-	memResults, err := engine.Search(ctx, query, searchpkg.Options{
-		Mode:  searchpkg.ModeKeyword,
-		Limit: limit,
-	})
+	opts := searchpkg.Options{
+		Mode:   searchpkg.ModeKeyword,
+		Limit:  limit,
+		Offset: parseBoundedInt(r.URL.Query().Get("offset"), 0, 0, maxGraphOffset),
+		Cursor: strings.TrimSpace(r.URL.Query().Get("cursor")),
+	}
+	paged := opts.Offset > 0 || opts.Cursor != ""
+	memResults, err := engine.Search(ctx, query, opts)
 	if err != nil {
-		writeJSON(w, 500, map[string]string{"error": err.Error()})
+		status := 500
+		if paged {
+			status = 400 // bad or stale cursor
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
 		return
 	}
 
@@ -530,7 +610,7 @@ func handleSearchAPI(w http.ResponseWriter, r *http.Request, st *store.SQLiteSto
 		matchedNodeIDs = append(matchedNodeIDs, f.ID)
 	}
 
-	if len(facts) == 0 {
+	if len(facts) == 0 && !paged {
 		// Fallback to direct fact search so graph browse still works when memories match weakly.
 		db := st.GetDB()
 		q := "%" + query + "%"
@@ -573,6 +653,7 @@ func handleSearchAPI(w http.ResponseWriter, r *http.Request, st *store.SQLiteSto
 		Memories:       memories,
 		MatchedNodeIDs: matchedNodeIDs,
 		Total:          len(facts),
+		NextCursor:     opts.NextCursor(memResults),
 	})
 }
 
@@ -867,6 +948,7 @@ func enrichNodeMetadata(ctx context.Context, db *sql.DB, nodes []ExportNode) {
 	if len(nodes) == 0 {
 		return
 	}
+	attachNodeNotes(ctx, db, nodes)
 
 	subjectSet := make(map[string]bool, len(nodes))
 	subjects := make([]string, 0, len(nodes))
@@ -1193,11 +1275,11 @@ func handleStatsAPI(w http.ResponseWriter, r *http.Request, st *store.SQLiteStor
 	db.QueryRowContext(ctx, "SELECT COUNT(*) FROM fact_edges_v1").Scan(&edges)
 	db.QueryRowContext(ctx, "SELECT COALESCE(AVG(confidence), 0) FROM facts WHERE superseded_by IS NULL OR superseded_by = 0").Scan(&avgConf)
 
-	writeJSON(w, 200, map[string]interface{}{
-		"facts":          facts,
-		"memories":       memories,
-		"edges":          edges,
-		"avg_confidence": avgConf,
+	writeJSON(w, 200, StatsResponse{
+		Facts:         facts,
+		Memories:      memories,
+		Edges:         edges,
+		AvgConfidence: avgConf,
 	})
 }
 
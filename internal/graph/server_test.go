@@ -393,6 +393,85 @@ func TestFactsAPIBySubject(t *testing.T) {
 	}
 }
 
+func TestFactsAPICursorPaging(t *testing.T) {
+	st := newTestStore(t)
+	defer st.Close()
+
+	ctx := context.Background()
+	memID, err := st.AddMemory(ctx, &store.Memory{Content: "Cortex paging facts", SourceFile: "paging.md"})
+	if err != nil {
+		t.Fatalf("add memory: %v", err)
+	}
+	for i, object := range []string{"Go", "SQLite", "FTS5", "MCP", "YAML"} {
+		if _, err := st.AddFact(ctx, &store.Fact{
+			MemoryID:   memID,
+			Subject:    "Cortex",
+			Predicate:  "uses",
+			Object:     object,
+			Confidence: 0.9 - float64(i)*0.1,
+			FactType:   "kv",
+		}); err != nil {
+			t.Fatalf("add fact: %v", err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/facts", func(w http.ResponseWriter, r *http.Request) {
+		handleFactsAPI(w, r, st)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	get := func(query string) (int, FactsResponse) {
+		t.Helper()
+		resp, err := http.Get(ts.URL + "/api/facts?subject=cortex&limit=2" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var out FactsResponse
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out
+	}
+
+	var objects []string
+	cursor := ""
+	for i := 0; i < 4; i++ {
+		status, page := get("&cursor=" + cursor)
+		if status != 200 {
+			t.Fatalf("page %d: status %d", i, status)
+		}
+		if i == 0 && page.Total != 5 {
+			t.Fatalf("expected total 5, got %d", page.Total)
+		}
+		for _, f := range page.Facts {
+			objects = append(objects, f.Object)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+		if i == 0 {
+			// A new top-confidence fact must not shift the next page.
+			if _, err := st.AddFact(ctx, &store.Fact{
+				MemoryID: memID, Subject: "Cortex", Predicate: "uses", Object: "Ollama", Confidence: 0.99, FactType: "kv",
+			}); err != nil {
+				t.Fatalf("add fact: %v", err)
+			}
+		}
+	}
+	if got := strings.Join(objects, ","); got != "Go,SQLite,FTS5,MCP,YAML" {
+		t.Fatalf("unexpected paging order: %s", got)
+	}
+
+	if status, page := get("&offset=4"); status != 200 || len(page.Facts) != 2 || page.NextCursor != "" {
+		t.Fatalf("unexpected offset page: status=%d %+v", status, page)
+	}
+	if status, _ := get("&cursor=bogus"); status != 400 {
+		t.Fatalf("expected 400 for bad cursor, got %d", status)
+	}
+}
+
 func TestFactsAPIByMemoryID(t *testing.T) {
 	st := newTestStore(t)
 	defer st.Close()
@@ -1049,3 +1128,50 @@ func seedClustersForGraphAPI(t *testing.T, ctx context.Context, st *store.SQLite
 		}
 	}
 }
+
+func TestGraphAPIIncludesFactNotes(t *testing.T) {
+	st := newTestStore(t)
+	defer st.Close()
+
+	ctx := context.Background()
+	memID, _ := st.AddMemory(ctx, &store.Memory{Content: "notes seed", SourceFile: "notes.md"})
+	noted, _ := st.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "Cortex", Predicate: "language", Object: "Go", Confidence: 0.9, FactType: "kv"})
+	plain, _ := st.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "Cortex", Predicate: "database", Object: "SQLite", Confidence: 0.8, FactType: "kv"})
+	if _, err := st.AddAnnotation(ctx, &store.Annotation{TargetType: store.AnnotationTargetFact, TargetID: noted, Body: "verified manually 2026-02", Label: "verified"}); err != nil {
+		t.Fatalf("add annotation: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/graph", func(w http.ResponseWriter, r *http.Request) {
+		handleGraphAPI(w, r, st)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, query := range []string{"subject=cortex", fmt.Sprintf("fact_id=%d", noted)} {
+		resp, err := http.Get(ts.URL + "/api/graph?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var result ExportResult
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("%s: decode result: %v", query, err)
+		}
+		found := false
+		for _, n := range result.Nodes {
+			switch n.ID {
+			case noted:
+				found = len(n.Notes) == 1 && n.Notes[0].Body == "verified manually 2026-02" && n.Notes[0].Label == "verified"
+			case plain:
+				if len(n.Notes) != 0 {
+					t.Fatalf("%s: expected no notes on fact %d, got %+v", query, plain, n.Notes)
+				}
+			}
+		}
+		if !found {
+			t.Fatalf("%s: expected note on fact %d, got %+v", query, noted, result.Nodes)
+		}
+	}
+}
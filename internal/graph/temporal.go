@@ -59,7 +59,7 @@ type TimelineBucket struct {
 type TimelineTransition struct {
 	FromDate    string         `json:"from_date"`
 	ToDate      string         `json:"to_date"`
-	Type        TransitionType `json:"type"`
+	Type        TransitionType `json:"type" enum:"superseded,refined,decayed,expanded,contradicted"`
 	FromFact    int64          `json:"from_fact,omitempty"`
 	ToFact      int64          `json:"to_fact,omitempty"`
 	Description string         `json:"description"`
@@ -75,7 +75,7 @@ type TimelineTrendPoint struct {
 type TimelineResponse struct {
 	Subject            string               `json:"subject"`
 	Range              TimelineRange        `json:"range"`
-	Bucket             string               `json:"bucket"`
+	Bucket             string               `json:"bucket" enum:"day,week,month"`
 	Buckets            []TimelineBucket     `json:"buckets"`
 	Transitions        []TimelineTransition `json:"transitions"`
 	SubjectFirstSeen   string               `json:"subject_first_seen,omitempty"`
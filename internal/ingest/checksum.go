@@ -0,0 +1,58 @@
+package ingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+)
+
+// fileChecksumStore is implemented by stores that remember a checksum per
+// imported file (SQLiteStore), so re-importing an unchanged file can skip
+// parsing and per-chunk dedup entirely.
+type fileChecksumStore interface {
+	UnchangedSourceFile(ctx context.Context, sourceFile, sha256 string) (int, error)
+	RecordFileChecksum(ctx context.Context, sourceFile, sha256 string, size int64) error
+}
+
+// fileSHA256 hashes a file's bytes without reading it into memory at once.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// skipUnchangedFile reports whether absPath can be skipped because its
+// checksum matches the last import and its memories are still there, and
+// returns the checksum to record once the file has been imported. The
+// shortcut is off with Rescan, and with Metadata, which is meant to be
+// applied to memories that already exist.
+func (e *Engine) skipUnchangedFile(ctx context.Context, absPath string, opts ImportOptions, result *ImportResult) (skip bool, checksum string) {
+	cs, ok := e.store.(fileChecksumStore)
+	if !ok {
+		return false, ""
+	}
+	checksum, err := fileSHA256(absPath)
+	if err != nil {
+		return false, ""
+	}
+	if opts.Rescan || opts.Metadata != nil {
+		return false, checksum
+	}
+	live, err := cs.UnchangedSourceFile(ctx, absPath, checksum)
+	if err != nil || live == 0 {
+		return false, checksum
+	}
+	result.FilesSkipped++
+	result.FilesUnchanged++
+	result.MemoriesUnchanged += live
+	return true, checksum
+}
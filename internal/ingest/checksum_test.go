@@ -0,0 +1,101 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestEngine_ImportFile_SkipsUnchangedFiles(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	e := NewEngine(s)
+
+	path := filepath.Join(t.TempDir(), "notes.md")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("# Deploys\n\nDeploys run on Tuesday mornings.\n\n# Oncall\n\nThe oncall rotation changes every Monday.\n")
+
+	first, err := e.ImportFile(ctx, path, ImportOptions{})
+	if err != nil || first.MemoriesNew == 0 || first.FilesUnchanged != 0 {
+		t.Fatalf("first import = %+v, %v", first, err)
+	}
+
+	second, err := e.ImportFile(ctx, path, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.FilesUnchanged != 1 || second.FilesSkipped != 1 || second.FilesImported != 0 || second.MemoriesUnchanged != first.MemoriesNew {
+		t.Fatalf("unchanged file should be skipped: %+v", second)
+	}
+
+	// Rescan processes the file chunk by chunk again.
+	rescan, _ := e.ImportFile(ctx, path, ImportOptions{Rescan: true})
+	if rescan.FilesUnchanged != 0 || rescan.FilesImported != 1 || rescan.MemoriesUnchanged != first.MemoriesNew {
+		t.Fatalf("rescan = %+v", rescan)
+	}
+
+	// An edit changes the checksum.
+	write("# Deploys\n\nDeploys moved to Thursday afternoons.\n\n# Oncall\n\nThe oncall rotation changes every Monday.\n")
+	edited, _ := e.ImportFile(ctx, path, ImportOptions{})
+	if edited.FilesUnchanged != 0 || edited.MemoriesNew != 1 {
+		t.Fatalf("edited file should be imported: %+v", edited)
+	}
+
+	// A file whose memories are gone is imported again even if unchanged.
+	sqlStore := s.(*store.SQLiteStore)
+	if _, err := sqlStore.DeleteMemoriesBySourceFile(ctx, path); err != nil {
+		t.Fatal(err)
+	}
+	restored, _ := e.ImportFile(ctx, path, ImportOptions{})
+	if restored.FilesUnchanged != 0 || restored.MemoriesNew == 0 {
+		t.Fatalf("file without memories should be re-imported: %+v", restored)
+	}
+}
+
+func TestEngine_ImportFile_DryRunDoesNotRecordChecksum(t *testing.T) {
+	ctx := context.Background()
+	e := NewEngine(newTestStore(t))
+	path := filepath.Join(t.TempDir(), "notes.md")
+	if err := os.WriteFile(path, []byte("# Notes\n\nThe staging database is rebuilt nightly.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := e.ImportFile(ctx, path, ImportOptions{DryRun: true}); err != nil {
+		t.Fatal(err)
+	}
+	res, _ := e.ImportFile(ctx, path, ImportOptions{})
+	if res.FilesUnchanged != 0 || res.MemoriesNew != 1 {
+		t.Fatalf("a dry run must not mark the file as imported: %+v", res)
+	}
+}
+
+func TestEngine_ImportFile_UnchangedIsPerNamespace(t *testing.T) {
+	ctx := context.Background()
+	work := store.WithNamespace(ctx, "work")
+	e := NewEngine(newTestStore(t))
+	path := filepath.Join(t.TempDir(), "notes.md")
+	if err := os.WriteFile(path, []byte("# Notes\n\nThe staging database is rebuilt nightly.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if res, err := e.ImportFile(ctx, path, ImportOptions{}); err != nil || res.MemoriesNew != 1 {
+		t.Fatalf("default import = %+v, %v", res, err)
+	}
+	res, err := e.ImportFile(work, path, ImportOptions{})
+	if err != nil || res.FilesUnchanged != 0 || res.MemoriesNew != 1 {
+		t.Fatalf("the same file must be imported into another namespace: %+v, %v", res, err)
+	}
+	for _, c := range []context.Context{ctx, work} {
+		if res, _ := e.ImportFile(c, path, ImportOptions{}); res.FilesUnchanged != 1 {
+			t.Fatalf("re-import in %s should be unchanged: %+v", store.EffectiveNamespace(c, e.store), res)
+		}
+	}
+}
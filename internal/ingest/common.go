@@ -17,6 +17,14 @@ type RawMemory struct {
 	SourceLine    int               // Starting line number (1-indexed)
 	SourceSection string            // Section header or key path
 	Metadata      map[string]string // Additional metadata (dates, front matter, etc.)
+
+	// ByteStart and ByteEnd locate the chunk in the source file as the byte
+	// range [ByteStart, ByteEnd); both are 0 when the format has no stable
+	// byte layout. HeadingPath is the full heading trail above the chunk,
+	// document title included ("Title > Section > Subsection").
+	ByteStart   int64
+	ByteEnd     int64
+	HeadingPath string
 }
 
 // Importer handles a specific file format.
@@ -33,13 +41,18 @@ type ImportResult struct {
 	FilesScanned      int
 	FilesImported     int
 	FilesSkipped      int
+	FilesUnchanged    int // Skipped because the file's checksum matched the last import
 	MemoriesNew       int
 	MemoriesUpdated   int
 	MemoriesUnchanged int
 	MemoriesNearDuped int // Suppressed by near-duplicate hygiene
 	MemoriesDenied    int
+	MemoriesTriaged   int // Scored by the LLM import gate
+	TriageDropped     int // Scored below the triage threshold
+	TriageFailures    int // LLM triage calls that failed (chunk kept)
 	NewMemoryIDs      []int64
 	DeniedDetails     []DeniedImport
+	Oversized         []OversizedFile
 	Errors            []ImportError
 }
 
@@ -48,13 +61,18 @@ func (r *ImportResult) Add(other *ImportResult) {
 	r.FilesScanned += other.FilesScanned
 	r.FilesImported += other.FilesImported
 	r.FilesSkipped += other.FilesSkipped
+	r.FilesUnchanged += other.FilesUnchanged
 	r.MemoriesNew += other.MemoriesNew
 	r.MemoriesUpdated += other.MemoriesUpdated
 	r.MemoriesUnchanged += other.MemoriesUnchanged
 	r.MemoriesNearDuped += other.MemoriesNearDuped
 	r.MemoriesDenied += other.MemoriesDenied
+	r.MemoriesTriaged += other.MemoriesTriaged
+	r.TriageDropped += other.TriageDropped
+	r.TriageFailures += other.TriageFailures
 	r.NewMemoryIDs = append(r.NewMemoryIDs, other.NewMemoryIDs...)
 	r.DeniedDetails = append(r.DeniedDetails, other.DeniedDetails...)
+	r.Oversized = append(r.Oversized, other.Oversized...)
 	r.Errors = append(r.Errors, other.Errors...)
 }
 
@@ -77,6 +95,7 @@ type ImportOptions struct {
 	Recursive          bool
 	DryRun             bool
 	MaxFileSize        int64       // bytes, default 10MB
+	Oversized          string      // what to do with files over MaxFileSize: skip (default), head, tail, sample
 	Project            string      // Project tag to assign to imported memories
 	MemoryClass        string      // Optional class to assign (rule, decision, preference, identity, status, scratch)
 	AutoTag            bool        // Infer project from file paths using default rules
@@ -86,6 +105,9 @@ type ImportOptions struct {
 	Exclude            []string // Skip files with these extensions (e.g. ".go", ".js")
 	Denylist           []cfgresolver.DenylistEntry
 	ImportKeepDropGate *ImportKeepDropGate
+	Triage             *ImportTriage // LLM memory-worthiness gate; nil disables it
+	Workers            int           // Files ImportDir imports concurrently; <= 1 imports one at a time
+	Rescan             bool          // Re-process files even when their checksum is unchanged
 
 	// Capture hygiene controls (Issue #36).
 	// Conservative defaults are applied by Normalize().
@@ -116,5 +138,8 @@ func (o *ImportOptions) Normalize() {
 	}
 }
 
+// DefaultImportWorkers is the CLI's default for ImportOptions.Workers.
+const DefaultImportWorkers = 4
+
 // DefaultMaxFileSize is 10MB.
 const DefaultMaxFileSize = 10 * 1024 * 1024
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/extract"
 	"github.com/hurttlocker/cortex/internal/store"
 	"github.com/hurttlocker/cortex/internal/temporal"
 )
@@ -99,6 +100,7 @@ type activeFactMatch struct {
 	ID         int64
 	Object     string
 	Confidence float64
+	Negated    bool
 }
 
 func findActiveFactMatchesBySubjectPredicate(ctx context.Context, s store.Store, fact *store.Fact) ([]activeFactMatch, error) {
@@ -118,7 +120,7 @@ func findActiveFactMatchesBySubjectPredicate(ctx context.Context, s store.Store,
 	projectID := strings.TrimSpace(fact.ProjectID)
 
 	rows, err := sqlStore.GetDB().QueryContext(ctx,
-		`SELECT id, object, confidence
+		`SELECT id, object, confidence, negated
 		   FROM facts
 		  WHERE superseded_by IS NULL
 		    AND LOWER(TRIM(subject)) = LOWER(TRIM(?))
@@ -139,7 +141,7 @@ func findActiveFactMatchesBySubjectPredicate(ctx context.Context, s store.Store,
 	matches := make([]activeFactMatch, 0)
 	for rows.Next() {
 		var match activeFactMatch
-		if err := rows.Scan(&match.ID, &match.Object, &match.Confidence); err != nil {
+		if err := rows.Scan(&match.ID, &match.Object, &match.Confidence, &match.Negated); err != nil {
 			return nil, fmt.Errorf("scanning active fact match: %w", err)
 		}
 		matches = append(matches, match)
@@ -184,6 +186,63 @@ func objectWordOverlap(a, b string) float64 {
 	return float64(intersection) / denom
 }
 
+// ReextractMemoryFacts replaces the facts of memory m with those the rule
+// pipeline extracts from content, e.g. after the memory was edited. It
+// returns how many facts were stored.
+func ReextractMemoryFacts(ctx context.Context, s store.Store, m *store.Memory, content string) (int, error) {
+	if _, err := s.DeleteFactsByMemoryID(ctx, m.ID); err != nil {
+		return 0, err
+	}
+	_, stored, err := extractAndStoreFacts(ctx, s, m, content)
+	return stored, err
+}
+
+// ExtractMemoryFacts runs the rule pipeline over memory m and stores the
+// results alongside any facts it already has. It returns the extracted facts,
+// the baseline for LLM enrichment, and how many were stored.
+func ExtractMemoryFacts(ctx context.Context, s store.Store, m *store.Memory) ([]extract.ExtractedFact, int, error) {
+	return extractAndStoreFacts(ctx, s, m, m.Content)
+}
+
+func extractAndStoreFacts(ctx context.Context, s store.Store, m *store.Memory, content string) ([]extract.ExtractedFact, int, error) {
+	metadata := map[string]string{"source_file": m.SourceFile}
+	if strings.HasSuffix(strings.ToLower(m.SourceFile), ".md") {
+		metadata["format"] = "markdown"
+	}
+	if m.SourceSection != "" {
+		metadata["source_section"] = m.SourceSection
+	}
+	if m.Metadata != nil && strings.TrimSpace(m.Metadata.TimestampStart) != "" {
+		metadata["timestamp_start"] = m.Metadata.TimestampStart
+	}
+
+	extractedFacts, err := extract.NewPipeline().Extract(ctx, content, metadata)
+	if err != nil {
+		return nil, 0, fmt.Errorf("extracting facts: %w", err)
+	}
+
+	stored := 0
+	for _, extractedFact := range extractedFacts {
+		fact := &store.Fact{
+			MemoryID:     m.ID,
+			Subject:      extractedFact.Subject,
+			Predicate:    extractedFact.Predicate,
+			Object:       extractedFact.Object,
+			FactType:     extractedFact.FactType,
+			Confidence:   extractedFact.Confidence,
+			DecayRate:    extractedFact.DecayRate,
+			SourceQuote:  extractedFact.SourceQuote,
+			TemporalNorm: extractedFact.TemporalNorm,
+			Negated:      extractedFact.Negated,
+		}
+		store.ApplyMemoryScopeToFact(m, fact)
+		if _, ok, err := StoreExtractedFact(ctx, s, fact); err == nil && ok {
+			stored++
+		}
+	}
+	return extractedFacts, stored, nil
+}
+
 // StoreExtractedFact writes an extracted fact through the ingest-layer conflict-prevention policy.
 //
 // Rules:
@@ -214,10 +273,18 @@ func StoreExtractedFact(ctx context.Context, s store.Store, fact *store.Fact) (f
 	}
 
 	candidateObject := strings.TrimSpace(fact.Object)
+	if !fact.Negated {
+		fact.Negated = extract.IsNegated(fact.Predicate, fact.Object)
+	}
 	var winnerID int64
 	bestOverlap := 0.0
 	bestConfidence := -1.0
 	for _, match := range matches {
+		// "not enabled" overlaps "enabled" word for word; opposite polarity
+		// is a contradiction, not a reinforcement.
+		if match.Negated != fact.Negated {
+			continue
+		}
 		overlap := objectWordOverlap(strings.TrimSpace(match.Object), candidateObject)
 		if overlap >= 0.80 {
 			if winnerID == 0 || overlap > bestOverlap || (overlap == bestOverlap && match.Confidence > bestConfidence) {
@@ -172,3 +172,27 @@ func TestStoreExtractedFact_DropsDeniedTemporalSubject(t *testing.T) {
 		t.Fatalf("expected no stored facts, got %+v", facts)
 	}
 }
+
+func TestStoreExtractedFact_NegationIsNotReinforcement(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	memoryID, err := s.AddMemory(ctx, &store.Memory{Content: "dark mode: enabled", SourceFile: "a.md"})
+	if err != nil {
+		t.Fatalf("AddMemory: %v", err)
+	}
+	if _, err := s.AddFact(ctx, &store.Fact{MemoryID: memoryID, Subject: "dark mode", Predicate: "status", Object: "enabled", FactType: "state"}); err != nil {
+		t.Fatalf("AddFact: %v", err)
+	}
+
+	negID, stored, err := StoreExtractedFact(ctx, s, &store.Fact{MemoryID: memoryID, Subject: "dark mode", Predicate: "status", Object: "not enabled", FactType: "state"})
+	if err != nil {
+		t.Fatalf("StoreExtractedFact: %v", err)
+	}
+	if !stored {
+		t.Fatal("negated object must be stored, not merged into the positive fact")
+	}
+	if f, _ := s.GetFact(ctx, negID); f == nil || !f.Negated {
+		t.Fatalf("expected stored fact to be flagged negated, got %+v", f)
+	}
+}
@@ -102,6 +102,22 @@ func shouldSkipLowSignalCapture(content string, opts ImportOptions) bool {
 	return matchesLowSignalPattern(normalized, opts)
 }
 
+// recordCaptureRejection persists a capture filter rejection so `cortex
+// capture report` can show how the filters behave. Dry runs record nothing.
+func (e *Engine) recordCaptureRejection(ctx context.Context, reason, sourceFile string, opts ImportOptions) error {
+	if opts.DryRun {
+		return nil
+	}
+	recorder, ok := e.store.(interface {
+		RecordCaptureRejection(context.Context, string, string, *store.Metadata) error
+	})
+	if !ok {
+		return nil
+	}
+	meta, _ := opts.Metadata.(*store.Metadata)
+	return recorder.RecordCaptureRejection(ctx, reason, sourceFile, meta)
+}
+
 // findNearDuplicate checks the recent memory window and returns true when
 // cosine similarity meets/exceeds threshold.
 func findNearDuplicate(ctx context.Context, s store.Store, content string, opts ImportOptions) (bool, float64, *store.Memory, error) {
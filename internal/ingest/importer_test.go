@@ -138,3 +138,41 @@ func TestProcessMemory_LowSignalSuppressed(t *testing.T) {
 		t.Fatalf("expected unchanged counter to increment, got %d", result.MemoriesUnchanged)
 	}
 }
+
+func TestProcessMemory_RecordsCaptureRejections(t *testing.T) {
+	s := newTestStore(t)
+	engine := NewEngine(s)
+	ctx := context.Background()
+
+	raw := RawMemory{
+		Content:       "### User\nok\n\n### Assistant\nok",
+		SourceFile:    "auto-capture.md",
+		SourceLine:    1,
+		SourceSection: "capture",
+	}
+	opts := ImportOptions{
+		CaptureLowSignalEnabled: true,
+		CaptureMinChars:         20,
+		Metadata:                &store.Metadata{SessionKey: "agent:main", Channel: "discord"},
+	}
+
+	dry := opts
+	dry.DryRun = true
+	if err := engine.processMemory(ctx, raw, dry, &ImportResult{}); err != nil {
+		t.Fatalf("processMemory (dry run): %v", err)
+	}
+	if err := engine.processMemory(ctx, raw, opts, &ImportResult{}); err != nil {
+		t.Fatalf("processMemory: %v", err)
+	}
+
+	rep, err := s.(*store.SQLiteStore).CaptureReport(ctx, time.Now().Add(-time.Hour), 0)
+	if err != nil {
+		t.Fatalf("CaptureReport: %v", err)
+	}
+	if rep.LowSignal != 1 || rep.Deduped != 0 {
+		t.Fatalf("expected one recorded low-signal rejection, got %+v", rep)
+	}
+	if len(rep.Channels) != 1 || rep.Channels[0].Channel != "discord" {
+		t.Fatalf("expected rejection attributed to discord, got %+v", rep.Channels)
+	}
+}
@@ -73,12 +73,7 @@ func (e *Engine) ImportFile(ctx context.Context, path string, opts ImportOptions
 		maxSize = DefaultMaxFileSize
 	}
 	if info.Size() > maxSize {
-		result.FilesSkipped++
-		result.Errors = append(result.Errors, ImportError{
-			File:    absPath,
-			Message: fmt.Sprintf("file too large (%d bytes, max %d)", info.Size(), maxSize),
-		})
-		return result, nil
+		return e.importOversized(ctx, absPath, info.Size(), maxSize, opts, result)
 	}
 
 	// Reject binary files
@@ -91,6 +86,11 @@ func (e *Engine) ImportFile(ctx context.Context, path string, opts ImportOptions
 		return result, nil
 	}
 
+	skip, checksum := e.skipUnchangedFile(ctx, absPath, opts, result)
+	if skip {
+		return result, nil
+	}
+
 	// Find appropriate importer
 	importer := e.detectImporter(absPath)
 	if importer == nil {
@@ -129,9 +129,11 @@ func (e *Engine) ImportFile(ctx context.Context, path string, opts ImportOptions
 	result.FilesImported++
 
 	// Process each memory chunk: dedup + store
+	stored := true
 	for _, raw := range rawMemories {
 		err := e.processMemory(ctx, raw, opts, result)
 		if err != nil {
+			stored = false
 			result.Errors = append(result.Errors, ImportError{
 				File:    raw.SourceFile,
 				Line:    raw.SourceLine,
@@ -140,6 +142,13 @@ func (e *Engine) ImportFile(ctx context.Context, path string, opts ImportOptions
 		}
 	}
 
+	// Only a file whose every chunk was handled may be skipped next time.
+	if stored && checksum != "" && !opts.DryRun {
+		if err := e.store.(fileChecksumStore).RecordFileChecksum(ctx, absPath, checksum, info.Size()); err != nil {
+			result.Errors = append(result.Errors, ImportError{File: absPath, Message: err.Error()})
+		}
+	}
+
 	return result, nil
 }
 
@@ -227,35 +236,51 @@ func (e *Engine) ImportDir(ctx context.Context, dir string, opts ImportOptions)
 		files = filterByExtension(files, opts.Include, opts.Exclude)
 	}
 
+	if opts.Workers > 1 && len(files) > 1 {
+		e.importFilesParallel(ctx, files, opts, result)
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		return result, nil
+	}
+
 	total := len(files)
 
 	for i, file := range files {
 		if opts.ProgressFn != nil {
 			opts.ProgressFn(i+1, total, file)
 		}
+		result.Add(e.importDirFile(ctx, file, opts))
+	}
 
-		// Check if binary
-		if isBinaryFile(file) {
-			result.FilesScanned++
-			result.FilesSkipped++
-			continue
-		}
+	return result, nil
+}
 
-		fileResult, err := e.ImportFile(ctx, file, opts)
-		if err != nil {
-			result.FilesScanned++
-			result.FilesSkipped++
-			result.Errors = append(result.Errors, ImportError{
-				File:    file,
-				Message: fmt.Sprintf("import error: %v", err),
-			})
-			continue
+// importDirFile imports one file found by ImportDir. Failures, including a
+// panicking importer, are recorded in the returned result instead of being
+// returned, so one bad file never stops the rest of the directory.
+func (e *Engine) importDirFile(ctx context.Context, file string, opts ImportOptions) (result *ImportResult) {
+	skipped := func(msg string) *ImportResult {
+		r := &ImportResult{FilesScanned: 1, FilesSkipped: 1}
+		if msg != "" {
+			r.Errors = []ImportError{{File: file, Message: msg}}
 		}
-
-		result.Add(fileResult)
+		return r
 	}
+	defer func() {
+		if p := recover(); p != nil {
+			result = skipped(fmt.Sprintf("import panic: %v", p))
+		}
+	}()
 
-	return result, nil
+	if isBinaryFile(file) {
+		return skipped("")
+	}
+	fileResult, err := e.ImportFile(ctx, file, opts)
+	if err != nil {
+		return skipped(fmt.Sprintf("import error: %v", err))
+	}
+	return fileResult
 }
 
 // normalizeExt ensures an extension starts with "." and is lowercase.
@@ -358,7 +383,7 @@ func (e *Engine) processMemory(ctx context.Context, raw RawMemory, opts ImportOp
 
 	if shouldSkipLowSignalCapture(raw.Content, opts) {
 		result.MemoriesUnchanged++
-		return nil
+		return e.recordCaptureRejection(ctx, store.CaptureRejectLowSignal, raw.SourceFile, opts)
 	}
 
 	if opts.CaptureDedupeEnabled {
@@ -369,7 +394,7 @@ func (e *Engine) processMemory(ctx context.Context, raw RawMemory, opts ImportOp
 		if isNearDup {
 			result.MemoriesNearDuped++
 			result.MemoriesUnchanged++
-			return nil
+			return e.recordCaptureRejection(ctx, store.CaptureRejectDedupe, raw.SourceFile, opts)
 		}
 	}
 
@@ -398,6 +423,24 @@ func (e *Engine) processMemory(ctx context.Context, raw RawMemory, opts ImportOp
 		}
 	}
 
+	var triageScore *extract.TriageScore
+	if opts.Triage != nil {
+		triageScore = e.triage(ctx, raw, opts.Triage, result)
+		if triageScore != nil && triageScore.Score < opts.Triage.threshold() {
+			result.TriageDropped++
+			if opts.DryRun {
+				result.DeniedDetails = append(result.DeniedDetails, DeniedImport{
+					File:    raw.SourceFile,
+					Line:    raw.SourceLine,
+					Pattern: "llm_triage",
+					Reason:  fmt.Sprintf("score=%.3f below %.2f: %s", triageScore.Score, opts.Triage.threshold(), triageScore.Reason),
+				})
+				return nil
+			}
+			return e.recordTriage(ctx, raw, hash, opts.Triage, triageScore, store.TriageDropped, 0)
+		}
+	}
+
 	if opts.DryRun {
 		result.MemoriesNew++
 		return nil
@@ -406,7 +449,7 @@ func (e *Engine) processMemory(ctx context.Context, raw RawMemory, opts ImportOp
 	// Determine project tag
 	project := opts.Project
 	if project == "" && opts.AutoTag {
-		project = store.InferProject(raw.SourceFile, store.DefaultProjectRules)
+		project = store.InferProject(raw.SourceFile, store.ActiveProjectRules())
 	}
 
 	// Determine memory class
@@ -418,6 +461,9 @@ func (e *Engine) processMemory(ctx context.Context, raw RawMemory, opts ImportOp
 	} else {
 		memoryClass = extract.AutoClassifyMemoryClass(raw.Content, raw.SourceSection)
 	}
+	if memoryClass == "" {
+		memoryClass = store.DefaultClassForSource(raw.SourceFile)
+	}
 
 	// Build store Memory
 	mem := &store.Memory{
@@ -429,6 +475,9 @@ func (e *Engine) processMemory(ctx context.Context, raw RawMemory, opts ImportOp
 		Project:       project,
 		MemoryClass:   memoryClass,
 	}
+	if raw.ByteEnd > raw.ByteStart || raw.HeadingPath != "" {
+		mem.Anchor = &store.ChunkAnchor{ByteStart: raw.ByteStart, ByteEnd: raw.ByteEnd, HeadingPath: raw.HeadingPath}
+	}
 
 	// Attach metadata if provided (Issue #30)
 	if opts.Metadata != nil {
@@ -469,6 +518,9 @@ func (e *Engine) processMemory(ctx context.Context, raw RawMemory, opts ImportOp
 
 	result.MemoriesNew++
 	result.NewMemoryIDs = append(result.NewMemoryIDs, newID)
+	if triageScore != nil {
+		return e.recordTriage(ctx, raw, hash, opts.Triage, triageScore, store.TriageKept, newID)
+	}
 	return nil
 }
 
@@ -583,8 +635,11 @@ func FormatImportResult(r *ImportResult) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("Import complete (%s)\n", time.Now().Format("2006-01-02 15:04:05")))
-	sb.WriteString(fmt.Sprintf("  Files:    %d scanned, %d imported, %d skipped\n",
-		r.FilesScanned, r.FilesImported, r.FilesSkipped))
+	files := fmt.Sprintf("  Files:    %d scanned, %d imported, %d skipped", r.FilesScanned, r.FilesImported, r.FilesSkipped)
+	if r.FilesUnchanged > 0 {
+		files += fmt.Sprintf(" (%d unchanged since last import)", r.FilesUnchanged)
+	}
+	sb.WriteString(files + "\n")
 	sb.WriteString(fmt.Sprintf("  Memories: %d new, %d updated, %d unchanged\n",
 		r.MemoriesNew, r.MemoriesUpdated, r.MemoriesUnchanged))
 	if r.MemoriesDenied > 0 {
@@ -593,6 +648,13 @@ func FormatImportResult(r *ImportResult) string {
 	if r.MemoriesNearDuped > 0 {
 		sb.WriteString(fmt.Sprintf("  Hygiene:  %d near-duplicates suppressed\n", r.MemoriesNearDuped))
 	}
+	if r.MemoriesTriaged > 0 || r.TriageFailures > 0 {
+		line := fmt.Sprintf("  Triage:   %d scored, %d dropped below threshold", r.MemoriesTriaged, r.TriageDropped)
+		if r.TriageFailures > 0 {
+			line += fmt.Sprintf(", %d kept unscored (LLM errors)", r.TriageFailures)
+		}
+		sb.WriteString(line + "\n")
+	}
 	if len(r.DeniedDetails) > 0 {
 		sb.WriteString(fmt.Sprintf("  Denylist: %d matches\n", len(r.DeniedDetails)))
 		for _, d := range r.DeniedDetails {
@@ -604,6 +666,21 @@ func FormatImportResult(r *ImportResult) string {
 		}
 	}
 
+	if len(r.Oversized) > 0 {
+		sb.WriteString(fmt.Sprintf("  Oversized: %d over the size limit\n", len(r.Oversized)))
+		for _, o := range r.Oversized {
+			sizes := fmt.Sprintf("%s (limit %s)", formatFileSize(o.Size), formatFileSize(o.Limit))
+			switch {
+			case o.Action != "skipped":
+				sb.WriteString(fmt.Sprintf("    - %s: %s, imported %s via %s\n", o.File, sizes, formatFileSize(o.BytesRead), o.Action))
+			case o.Reason != "":
+				sb.WriteString(fmt.Sprintf("    - %s: %s, skipped: %s\n", o.File, sizes, o.Reason))
+			default:
+				sb.WriteString(fmt.Sprintf("    - %s: %s, skipped (raise --max-file-size or pass --oversized head|tail|sample)\n", o.File, sizes))
+			}
+		}
+	}
+
 	if len(r.Errors) > 0 {
 		sb.WriteString(fmt.Sprintf("  Errors:   %d\n", len(r.Errors)))
 		for _, e := range r.Errors {
@@ -1313,3 +1313,63 @@ func TestEngine_ImportDir_Exclude(t *testing.T) {
 		t.Errorf("expected 2 files scanned (skip .json), got %d (imported=%d, skipped=%d)", result.FilesScanned, result.FilesImported, result.FilesSkipped)
 	}
 }
+
+// ==================== Provenance Anchor Tests ====================
+
+func TestMarkdownImport_Anchors(t *testing.T) {
+	ctx := context.Background()
+	content := "---\ntags: [ops]\n---\n# Runbook\n\nIntro paragraph about the runbook and who maintains it day to day.\n\n## Deploys\n\nDeploys run on Tuesday mornings after the change review meeting.\n\n### Rollback\n\nRoll back with the previous image tag and page the owning team.\n"
+	path := filepath.Join(t.TempDir(), "runbook.md")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	raws, err := (&MarkdownImporter{}).Import(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := map[string]bool{}
+	for _, raw := range raws {
+		if raw.ByteEnd <= raw.ByteStart || raw.ByteEnd > int64(len(content)) {
+			t.Fatalf("chunk %q has bad byte range [%d,%d)", raw.Content, raw.ByteStart, raw.ByteEnd)
+		}
+		quoted := content[raw.ByteStart:raw.ByteEnd]
+		lines := strings.Split(strings.TrimSpace(raw.Content), "\n")
+		if !strings.HasPrefix(quoted, strings.TrimSpace(lines[0])) || !strings.HasSuffix(quoted, strings.TrimSpace(lines[len(lines)-1])) {
+			t.Fatalf("byte range quotes %q, chunk is %q", quoted, raw.Content)
+		}
+		paths[raw.HeadingPath] = true
+	}
+	if !paths["Runbook > Deploys > Rollback"] {
+		t.Fatalf("expected full heading path including the H1, got %v", paths)
+	}
+}
+
+func TestImportFile_StoresAnchors(t *testing.T) {
+	ctx := context.Background()
+	content := "First paragraph about the deploy window.\n\nSecond paragraph about the oncall rotation.\n"
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := newTestStore(t)
+	res, err := NewEngine(s).ImportFile(ctx, path, ImportOptions{})
+	if err != nil || len(res.NewMemoryIDs) == 0 {
+		t.Fatalf("import = %+v, %v", res, err)
+	}
+
+	anchors, err := s.(*store.SQLiteStore).GetMemoryAnchors(ctx, res.NewMemoryIDs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range res.NewMemoryIDs {
+		a, ok := anchors[id]
+		if !ok {
+			t.Fatalf("memory %d has no anchor", id)
+		}
+		m, _ := s.GetMemory(ctx, id)
+		if quoted := content[a.ByteStart:a.ByteEnd]; strings.TrimSpace(quoted) != strings.TrimSpace(m.Content) {
+			t.Fatalf("anchor quotes %q, memory is %q", quoted, m.Content)
+		}
+	}
+}
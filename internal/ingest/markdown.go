@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 // MarkdownImporter handles .md and .markdown files.
@@ -62,7 +63,62 @@ func (m *MarkdownImporter) Import(ctx context.Context, path string) ([]RawMemory
 	}
 
 	// Normalize: split oversized chunks, merge tiny fragments
-	return normalizeChunks(chunks, 50, 1500), nil
+	chunks = normalizeChunks(chunks, 50, 1500)
+
+	// Past front matter, body is a suffix of the whitespace-trimmed content.
+	bodyOffset := 0
+	if body != content {
+		bodyOffset = len(strings.TrimRightFunc(content, unicode.IsSpace)) - len(body)
+	}
+	anchorChunks(body, int64(bodyOffset), chunks)
+	return chunks, nil
+}
+
+// anchorChunks sets ByteStart and ByteEnd on chunks cut from text, which
+// starts offset bytes into the file. Chunks are trimmed, split, and merged
+// after cutting, so rather than tracking offsets through those steps each
+// chunk's lines are found in order starting from its SourceLine. The range
+// runs from its first line to its last, covering any headings in between.
+func anchorChunks(text string, offset int64, chunks []RawMemory) {
+	lineStarts := []int{0}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	for i := range chunks {
+		var lines []string
+		for _, l := range strings.Split(chunks[i].Content, "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				lines = append(lines, l)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		from := 0
+		if n := chunks[i].SourceLine; n >= 1 && n <= len(lineStarts) {
+			from = lineStarts[n-1]
+		}
+		k := strings.Index(text[from:], lines[0])
+		if k < 0 {
+			from = 0
+			if k = strings.Index(text, lines[0]); k < 0 {
+				continue
+			}
+		}
+		start := from + k
+		end := start + len(lines[0])
+		for _, l := range lines[1:] {
+			k := strings.Index(text[end:], l)
+			if k < 0 {
+				break
+			}
+			end += k + len(l)
+		}
+		chunks[i].ByteStart = offset + int64(start)
+		chunks[i].ByteEnd = offset + int64(end)
+	}
 }
 
 // stripFrontMatter removes YAML front matter (--- delimited) from content.
@@ -116,6 +172,7 @@ func splitOnAllHeaders(content string, absPath string, metadata map[string]strin
 	var currentLines []string
 	var sectionStartLine int
 	var currentSectionPath string
+	var title string
 	lineNum := 0
 	inCodeBlock := false
 
@@ -127,12 +184,17 @@ func splitOnAllHeaders(content string, absPath string, metadata map[string]strin
 		if text == "" {
 			return
 		}
+		headingPath := currentSectionPath
+		if title != "" {
+			headingPath = buildSectionPath([]string{title, currentSectionPath})
+		}
 		mem := RawMemory{
 			Content:       text,
 			SourceFile:    absPath,
 			SourceLine:    sectionStartLine,
 			SourceSection: currentSectionPath,
 			Metadata:      copyMetadata(metadata),
+			HeadingPath:   headingPath,
 		}
 		memories = append(memories, mem)
 		currentLines = nil
@@ -154,14 +216,15 @@ func splitOnAllHeaders(content string, absPath string, metadata map[string]strin
 
 		if match := headerRe.FindStringSubmatch(line); match != nil {
 			level := len(match[1]) // number of # characters
-			title := strings.TrimSpace(match[2])
+			heading := strings.TrimSpace(match[2])
 
 			if level == 1 {
 				// h1 = document title, store as metadata, not a section boundary
 				if metadata == nil {
 					metadata = make(map[string]string)
 				}
-				metadata["title"] = title
+				metadata["title"] = heading
+				title = heading
 				if sectionStartLine == 0 {
 					sectionStartLine = lineNum + 1
 				}
@@ -175,7 +238,7 @@ func splitOnAllHeaders(content string, absPath string, metadata map[string]strin
 			// h2 = index 0, h3 = index 1, h4 = index 2, etc.
 			stackIdx := level - 2
 			if stackIdx >= 0 && stackIdx < len(headerStack) {
-				headerStack[stackIdx] = title
+				headerStack[stackIdx] = heading
 				// Clear deeper levels
 				for i := stackIdx + 1; i < len(headerStack); i++ {
 					headerStack[i] = ""
@@ -323,6 +386,7 @@ func normalizeChunks(memories []RawMemory, minChars, maxChars int) []RawMemory {
 				SourceLine:    mem.SourceLine + lineOffset,
 				SourceSection: mem.SourceSection,
 				Metadata:      copyMetadata(mem.Metadata),
+				HeadingPath:   mem.HeadingPath,
 			})
 			lineOffset += countLines(strings.Join(current, "\n\n"))
 			current = nil
@@ -357,6 +421,7 @@ func normalizeChunks(memories []RawMemory, minChars, maxChars int) []RawMemory {
 							SourceLine:    mem.SourceLine + lineOffset,
 							SourceSection: mem.SourceSection,
 							Metadata:      copyMetadata(mem.Metadata),
+							HeadingPath:   mem.HeadingPath,
 						})
 						line = strings.TrimSpace(line[cut:])
 					}
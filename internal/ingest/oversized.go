@@ -0,0 +1,241 @@
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hurttlocker/cortex/internal/config"
+)
+
+// Oversized-file modes for ImportOptions.Oversized.
+const (
+	OversizedSkip   = "skip"   // report the file and import nothing (default)
+	OversizedHead   = "head"   // import the first MaxFileSize bytes
+	OversizedTail   = "tail"   // import the last MaxFileSize bytes (newest log lines)
+	OversizedSample = "sample" // import evenly spaced windows totalling MaxFileSize bytes
+)
+
+// oversizedSampleWindows is how many windows OversizedSample spreads its
+// byte budget across.
+const oversizedSampleWindows = 8
+
+// OversizedFile records a file over the size limit and what was done with it.
+type OversizedFile struct {
+	File      string
+	Size      int64
+	Limit     int64
+	Action    string // skipped, or the mode that imported part of it
+	BytesRead int64
+	Reason    string // why a partial-import mode fell back to skipping
+}
+
+// ValidOversizedMode reports whether mode is a known oversized-file mode.
+// The empty string means OversizedSkip.
+func ValidOversizedMode(mode string) bool {
+	switch mode {
+	case "", OversizedSkip, OversizedHead, OversizedTail, OversizedSample:
+		return true
+	}
+	return false
+}
+
+// ParseFileSize parses sizes such as "10MB", "512k", "1.5GiB", or plain bytes.
+// Units are binary (1KB = 1024 bytes).
+func ParseFileSize(raw string) (int64, error) {
+	return config.ParseByteSize(raw)
+}
+
+// importOversized handles a file larger than the size limit according to
+// opts.Oversized. Partial modes only apply to line-oriented text (plain
+// text, logs, markdown); structured formats cannot be cut and are skipped.
+// Skips are reported in result.Oversized, not as errors.
+func (e *Engine) importOversized(ctx context.Context, path string, size, limit int64, opts ImportOptions, result *ImportResult) (*ImportResult, error) {
+	report := OversizedFile{File: path, Size: size, Limit: limit, Action: "skipped"}
+	mode := opts.Oversized
+	if mode == "" || mode == OversizedSkip {
+		result.FilesSkipped++
+		result.Oversized = append(result.Oversized, report)
+		return result, nil
+	}
+	if isBinaryFile(path) {
+		result.FilesSkipped++
+		report.Reason = "binary file"
+		result.Oversized = append(result.Oversized, report)
+		return result, nil
+	}
+	switch e.detectImporter(path).(type) {
+	case nil, *PlainTextImporter, *MarkdownImporter:
+	default:
+		result.FilesSkipped++
+		report.Reason = "structured format cannot be partially imported"
+		result.Oversized = append(result.Oversized, report)
+		return result, nil
+	}
+
+	raws, read, err := readTextWindows(path, oversizedWindows(mode, size, limit))
+	if err != nil {
+		result.FilesSkipped++
+		result.Errors = append(result.Errors, ImportError{File: path, Message: fmt.Sprintf("import error: %v", err)})
+		return result, nil
+	}
+	report.Action = mode
+	report.BytesRead = read
+	result.Oversized = append(result.Oversized, report)
+	if len(raws) == 0 {
+		result.FilesSkipped++
+		return result, nil
+	}
+
+	result.FilesImported++
+	for _, raw := range raws {
+		if err := e.processMemory(ctx, raw, opts, result); err != nil {
+			result.Errors = append(result.Errors, ImportError{
+				File:    raw.SourceFile,
+				Line:    raw.SourceLine,
+				Message: fmt.Sprintf("storage error: %v", err),
+			})
+		}
+	}
+	return result, nil
+}
+
+// textWindow is a byte range [Start, End) of a file.
+type textWindow struct {
+	Start, End int64
+}
+
+// oversizedWindows picks the byte ranges a partial-import mode reads.
+func oversizedWindows(mode string, size, limit int64) []textWindow {
+	switch mode {
+	case OversizedTail:
+		return []textWindow{{Start: size - limit, End: size}}
+	case OversizedSample:
+		n := int64(oversizedSampleWindows)
+		width := limit / n
+		if width == 0 {
+			return []textWindow{{Start: 0, End: limit}}
+		}
+		stride := size / n
+		windows := make([]textWindow, 0, n)
+		for i := int64(0); i < n; i++ {
+			start := i * stride
+			windows = append(windows, textWindow{Start: start, End: start + width})
+		}
+		return windows
+	default:
+		return []textWindow{{Start: 0, End: limit}}
+	}
+}
+
+// readTextWindows streams the windows of a text file into paragraph
+// memories without reading the rest of the file into memory. Windows are
+// trimmed to whole lines, and line numbers are counted while skipping ahead
+// so SourceLine stays accurate. Returns the bytes read from windows.
+func readTextWindows(path string, windows []textWindow) ([]RawMemory, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var (
+		memories    []RawMemory
+		read        int64
+		pos         int64
+		line        = 1
+		atLineStart = true
+	)
+	r := bufio.NewReaderSize(f, 64*1024)
+	for _, w := range windows {
+		if w.Start < pos {
+			w.Start = pos
+		}
+		if w.End <= w.Start {
+			continue
+		}
+		// Count lines up to the window, then drop the partial line it starts in.
+		if w.Start > pos {
+			skipped, newlines, last, err := countNewlines(r, w.Start-pos)
+			pos += skipped
+			line += newlines
+			atLineStart = last == '\n'
+			if err != nil {
+				break
+			}
+		}
+		if !atLineStart {
+			partial, err := r.ReadString('\n')
+			pos += int64(len(partial))
+			if err != nil {
+				break
+			}
+			line++
+		}
+		if pos >= w.End {
+			continue
+		}
+
+		bufStart := pos
+		buf := make([]byte, w.End-pos)
+		n, err := io.ReadFull(r, buf)
+		buf = buf[:n]
+		pos += int64(n)
+		// Finish the last line so a window never ends mid-line.
+		if err == nil {
+			rest, _ := r.ReadString('\n')
+			buf = append(buf, rest...)
+			pos += int64(len(rest))
+		}
+		read += int64(len(buf))
+		chunk, scanErr := scanParagraphs(bytes.NewReader(buf), path, line, bufStart)
+		if scanErr != nil {
+			return nil, read, scanErr
+		}
+		memories = append(memories, chunk...)
+		line += bytes.Count(buf, []byte{'\n'})
+		atLineStart = len(buf) > 0 && buf[len(buf)-1] == '\n'
+		if err != nil {
+			break
+		}
+	}
+	return memories, read, nil
+}
+
+// countNewlines discards n bytes from r, counting the newlines in them and
+// returning the last byte discarded.
+func countNewlines(r *bufio.Reader, n int64) (skipped int64, newlines int, last byte, err error) {
+	for skipped < n {
+		want := n - skipped
+		if want > int64(r.Size()) {
+			want = int64(r.Size())
+		}
+		chunk, peekErr := r.Peek(int(want))
+		if len(chunk) > 0 {
+			newlines += bytes.Count(chunk, []byte{'\n'})
+			last = chunk[len(chunk)-1]
+		}
+		d, _ := r.Discard(len(chunk))
+		skipped += int64(d)
+		if peekErr != nil {
+			return skipped, newlines, last, peekErr
+		}
+	}
+	return skipped, newlines, last, nil
+}
+
+// formatFileSize renders a byte count for import reports.
+func formatFileSize(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
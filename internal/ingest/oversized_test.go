@@ -0,0 +1,138 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFileSize(t *testing.T) {
+	for raw, want := range map[string]int64{
+		"2048":   2048,
+		"500KB":  500 << 10,
+		"10mb":   10 << 20,
+		"1.5GiB": 3 << 29,
+		" 3M ":   3 << 20,
+	} {
+		got, err := ParseFileSize(raw)
+		if err != nil || got != want {
+			t.Errorf("ParseFileSize(%q) = %d, %v; want %d", raw, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "MB", "-5MB", "ten"} {
+		if _, err := ParseFileSize(bad); err == nil {
+			t.Errorf("ParseFileSize(%q) should fail", bad)
+		}
+	}
+}
+
+func TestScanParagraphs_SplitsBlankLinesAndLongRuns(t *testing.T) {
+	input := "first para\nstill first\r\n\n\nsecond\n"
+	got, err := scanParagraphs(strings.NewReader(input), "/tmp/x.txt", 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Content != "first para\nstill first" || got[0].SourceLine != 10 ||
+		got[1].Content != "second" || got[1].SourceLine != 14 {
+		t.Fatalf("unexpected paragraphs: %+v", got)
+	}
+
+	line := strings.Repeat("x", 1000)
+	var log strings.Builder
+	for i := 0; i < 40; i++ {
+		log.WriteString(line + "\n")
+	}
+	got, err = scanParagraphs(strings.NewReader(log.String()), "/tmp/app.log", 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) < 3 {
+		t.Fatalf("a 40KB run without blank lines should be split, got %d chunks", len(got))
+	}
+	for _, m := range got {
+		if len(m.Content) > maxParagraphBytes {
+			t.Fatalf("chunk of %d bytes exceeds cap", len(m.Content))
+		}
+	}
+	if got[1].SourceLine != 17 {
+		t.Fatalf("second chunk should start at line 17, got %d", got[1].SourceLine)
+	}
+}
+
+func writeLogFile(t *testing.T, lines int) string {
+	t.Helper()
+	var b strings.Builder
+	for i := 1; i <= lines; i++ {
+		fmt.Fprintf(&b, "entry %04d status ok\n\n", i)
+	}
+	path := filepath.Join(t.TempDir(), "big.log")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportFile_OversizedModes(t *testing.T) {
+	ctx := context.Background()
+	path := writeLogFile(t, 500) // 21 bytes per entry, ~10KB
+	const limit = 1024
+
+	e := NewEngine(newTestStore(t))
+	res, err := e.ImportFile(ctx, path, ImportOptions{MaxFileSize: limit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.FilesSkipped != 1 || res.MemoriesNew != 0 || len(res.Errors) != 0 ||
+		len(res.Oversized) != 1 || res.Oversized[0].Action != "skipped" {
+		t.Fatalf("default mode should skip with a report, not an error: %+v", res)
+	}
+	if !strings.Contains(FormatImportResult(res), "--oversized") {
+		t.Fatalf("skip report should say how to import the file:\n%s", FormatImportResult(res))
+	}
+
+	s := newTestStore(t)
+	e = NewEngine(s)
+	res, err = e.ImportFile(ctx, path, ImportOptions{MaxFileSize: limit, Oversized: OversizedTail})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.FilesImported != 1 || res.MemoriesNew == 0 || res.Oversized[0].Action != OversizedTail {
+		t.Fatalf("tail should import part of the file: %+v", res)
+	}
+	if got := res.Oversized[0].BytesRead; got > limit || got < limit-22 {
+		t.Fatalf("tail read %d bytes, want close to %d", got, limit)
+	}
+	m, err := s.GetMemory(ctx, res.NewMemoryIDs[len(res.NewMemoryIDs)-1])
+	if err != nil || m.Content != "entry 0500 status ok" || m.SourceLine != 999 {
+		t.Fatalf("last tail memory = %+v (err %v), want entry 0500 at line 999", m, err)
+	}
+	first, _ := s.GetMemory(ctx, res.NewMemoryIDs[0])
+	var n int
+	if _, err := fmt.Sscanf(first.Content, "entry %d status ok", &n); err != nil || first.SourceLine != 2*n-1 {
+		t.Fatalf("first tail memory %q should be a whole entry at its true line, got line %d", first.Content, first.SourceLine)
+	}
+
+	e = NewEngine(newTestStore(t))
+	res, err = e.ImportFile(ctx, path, ImportOptions{MaxFileSize: limit, Oversized: OversizedSample})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.MemoriesNew < oversizedSampleWindows || res.Oversized[0].BytesRead > limit+oversizedSampleWindows*22 {
+		t.Fatalf("sample should read a bounded slice from across the file: %+v", res)
+	}
+
+	jsonPath := filepath.Join(t.TempDir(), "big.json")
+	if err := os.WriteFile(jsonPath, []byte(`[`+strings.Repeat(`{"k":"v"},`, 200)+`{"k":"v"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err = e.ImportFile(ctx, jsonPath, ImportOptions{MaxFileSize: limit, Oversized: OversizedHead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.MemoriesNew != 0 || len(res.Oversized) != 1 || !strings.Contains(res.Oversized[0].Reason, "structured") {
+		t.Fatalf("structured files should be skipped, not cut: %+v", res)
+	}
+}
@@ -0,0 +1,74 @@
+package ingest
+
+import (
+	"context"
+	"sync"
+)
+
+// importFilesParallel imports files with opts.Workers workers and merges the
+// per-file results into result in walk order, so counts, errors, and
+// NewMemoryIDs come out the same way however the work was scheduled.
+// Storage stays serialized by the store; parsing, chunking, and LLM triage
+// overlap. At most twice the worker count of files is in flight or waiting
+// to be merged, which bounds memory on very large directories. ProgressFn
+// reports files as they are merged. A cancelled ctx stops new files from
+// starting; files already running finish and are merged.
+func (e *Engine) importFilesParallel(ctx context.Context, files []string, opts ImportOptions, result *ImportResult) {
+	workers := opts.Workers
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	type fileResult struct {
+		index  int
+		result *ImportResult
+	}
+	window := make(chan struct{}, 2*workers)
+	jobs := make(chan int)
+	results := make(chan fileResult, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results <- fileResult{index: i, result: e.importDirFile(ctx, files[i], opts)}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := range files {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			jobs <- i
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]*ImportResult, 2*workers)
+	next := 0
+	for fr := range results {
+		pending[fr.index] = fr.result
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			result.Add(r)
+			<-window
+			next++
+			if opts.ProgressFn != nil {
+				opts.ProgressFn(next, len(files), files[next-1])
+			}
+		}
+	}
+}
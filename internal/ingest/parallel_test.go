@@ -0,0 +1,107 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// writeImportCorpus fills a directory with n markdown files, one empty file,
+// and one binary file.
+func writeImportCorpus(t *testing.T, n int) string {
+	t.Helper()
+	dir := t.TempDir()
+	for i := 0; i < n; i++ {
+		content := fmt.Sprintf("# Note %d\n\nThe deploy window for service %d is Tuesday at %d:00 UTC.\n", i, i, i%24)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("note-%03d.md", i)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "empty.md"), []byte("   \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "blob.md"), []byte{0x00, 0x01, 0x02, 0xff}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestEngine_ImportDir_ParallelMatchesSequential(t *testing.T) {
+	ctx := context.Background()
+	dir := writeImportCorpus(t, 40)
+
+	run := func(workers int) (*ImportResult, []string) {
+		s := newTestStore(t)
+		var progress []string
+		res, err := NewEngine(s).ImportDir(ctx, dir, ImportOptions{
+			Workers:    workers,
+			ProgressFn: func(current, total int, file string) { progress = append(progress, filepath.Base(file)) },
+		})
+		if err != nil {
+			t.Fatalf("ImportDir(workers=%d): %v", workers, err)
+		}
+		memories, _ := s.ListMemories(ctx, store.ListOpts{Limit: 100})
+		if len(memories) != res.MemoriesNew {
+			t.Fatalf("workers=%d: stored %d memories, result says %d", workers, len(memories), res.MemoriesNew)
+		}
+		return res, progress
+	}
+
+	seq, seqProgress := run(1)
+	par, parProgress := run(8)
+	if seq.FilesScanned != 42 || seq.FilesImported != 40 || seq.FilesSkipped != 2 || seq.MemoriesNew != 40 {
+		t.Fatalf("sequential result = %+v", seq)
+	}
+	if par.FilesScanned != seq.FilesScanned || par.FilesImported != seq.FilesImported ||
+		par.FilesSkipped != seq.FilesSkipped || par.MemoriesNew != seq.MemoriesNew || len(par.NewMemoryIDs) != len(seq.NewMemoryIDs) {
+		t.Fatalf("parallel result %+v differs from sequential %+v", par, seq)
+	}
+	if !reflect.DeepEqual(par.Errors, seq.Errors) {
+		t.Fatalf("errors should be aggregated in walk order:\nparallel:   %+v\nsequential: %+v", par.Errors, seq.Errors)
+	}
+	if !reflect.DeepEqual(parProgress, seqProgress) {
+		t.Fatalf("progress should follow walk order:\nparallel:   %v\nsequential: %v", parProgress, seqProgress)
+	}
+}
+
+func TestEngine_ImportDir_ParallelCancelled(t *testing.T) {
+	dir := writeImportCorpus(t, 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res, err := NewEngine(newTestStore(t)).ImportDir(ctx, dir, ImportOptions{Workers: 4})
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if res == nil || res.FilesScanned > 8 {
+		t.Fatalf("a cancelled import should stop early, got %+v", res)
+	}
+}
+
+func TestEngine_ImportDirFile_RecoversPanics(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.md")
+	if err := os.WriteFile(path, []byte("# A\n\nsome content worth keeping around\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngine(newTestStore(t))
+	e.importers = []Importer{panicImporter{}}
+
+	res := e.importDirFile(context.Background(), path, ImportOptions{})
+	if res.FilesSkipped != 1 || len(res.Errors) != 1 || res.Errors[0].File != path {
+		t.Fatalf("panicking importer should be recorded as a skipped file: %+v", res)
+	}
+}
+
+type panicImporter struct{}
+
+func (panicImporter) CanHandle(string) bool { return true }
+
+func (panicImporter) Import(context.Context, string) ([]RawMemory, error) {
+	panic("importer bug")
+}
@@ -1,12 +1,19 @@
 package ingest
 
 import (
+	"bufio"
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode"
 )
 
+// maxParagraphBytes caps a streamed paragraph. Logs rarely have blank lines,
+// so without a cap a whole file would become a single memory.
+const maxParagraphBytes = 16 * 1024
+
 // PlainTextImporter handles .txt, .log, and any unrecognized text format.
 type PlainTextImporter struct{}
 
@@ -17,51 +24,79 @@ func (t *PlainTextImporter) CanHandle(path string) bool {
 }
 
 // Import parses a plain text file into memory chunks.
-// Splits on double newlines (paragraphs). Each paragraph becomes one memory unit.
+// Splits on blank lines (paragraphs). Each paragraph becomes one memory unit.
+// The file is streamed rather than read whole.
 func (t *PlainTextImporter) Import(ctx context.Context, path string) ([]RawMemory, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := os.ReadFile(path)
+	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	defer f.Close()
 
-	content := string(data)
-	if strings.TrimSpace(content) == "" {
-		return nil, nil
-	}
-
-	return splitTextIntoParagraphs(content, absPath), nil
+	return scanParagraphs(f, absPath, 1, 0)
 }
 
-// splitTextIntoParagraphs splits text on double newlines and tracks line numbers.
-func splitTextIntoParagraphs(content string, absPath string) []RawMemory {
-	var memories []RawMemory
-
-	// Normalize line endings
-	content = strings.ReplaceAll(content, "\r\n", "\n")
-
-	paragraphs := strings.Split(content, "\n\n")
-	lineNum := 1
-
-	for _, para := range paragraphs {
-		text := strings.TrimSpace(para)
-		if text == "" {
-			lineNum += strings.Count(para, "\n") + 2
-			continue
+// scanParagraphs streams r into paragraph memories, splitting on blank lines
+// and, for runs of text without any, every maxParagraphBytes at a line
+// boundary. firstLine and firstByte are the file line and byte offset r
+// starts at.
+func scanParagraphs(r io.Reader, absPath string, firstLine int, firstByte int64) ([]RawMemory, error) {
+	var (
+		memories  []RawMemory
+		para      strings.Builder
+		start     int
+		byteStart int64 // first non-space byte of the paragraph, -1 until seen
+		byteEnd   int64
+	)
+	flush := func() {
+		if text := strings.TrimSpace(para.String()); text != "" {
+			memories = append(memories, RawMemory{Content: text, SourceFile: absPath, SourceLine: start, ByteStart: byteStart, ByteEnd: byteEnd})
 		}
+		para.Reset()
+	}
 
-		mem := RawMemory{
-			Content:    text,
-			SourceFile: absPath,
-			SourceLine: lineNum,
+	br := bufio.NewReader(r)
+	line := firstLine
+	offset := firstByte
+	for {
+		raw, err := br.ReadString('\n')
+		if raw != "" {
+			text := strings.TrimRight(raw, "\r\n")
+			if text == "" {
+				flush()
+			} else {
+				if para.Len() > 0 && para.Len()+len(text) > maxParagraphBytes {
+					flush()
+				}
+				if para.Len() == 0 {
+					start = line
+					byteStart = -1
+				} else {
+					para.WriteByte('\n')
+				}
+				para.WriteString(text)
+				if trimmed := strings.TrimRightFunc(text, unicode.IsSpace); strings.TrimSpace(trimmed) != "" {
+					if byteStart < 0 {
+						byteStart = offset + int64(len(text)-len(strings.TrimLeftFunc(text, unicode.IsSpace)))
+					}
+					byteEnd = offset + int64(len(trimmed))
+				}
+			}
+			offset += int64(len(raw))
+			line++
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
 		}
-		memories = append(memories, mem)
-		lineNum += strings.Count(para, "\n") + 2
 	}
-
-	return memories
+	flush()
+	return memories, nil
 }
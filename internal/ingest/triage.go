@@ -0,0 +1,137 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/extract"
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// ImportTriage is the LLM import gate: each chunk that survives the cheaper
+// hygiene filters is scored for novelty, durability, and specificity, and
+// only chunks scoring at least Threshold become memories.
+type ImportTriage struct {
+	Provider  llm.Provider
+	Threshold float64 // default extract.DefaultTriageThreshold
+	Model     string  // recorded in the audit log; defaults to Provider.Name()
+}
+
+// triageSimilarCandidates is how many keyword matches are ranked to pick the
+// similar memories shown to the model.
+const triageSimilarCandidates = 10
+
+// triageRecorder is implemented by stores that keep the triage audit log.
+type triageRecorder interface {
+	RecordImportTriage(ctx context.Context, r *store.ImportTriageRecord) error
+}
+
+func (t *ImportTriage) threshold() float64 {
+	if t.Threshold <= 0 {
+		return extract.DefaultTriageThreshold
+	}
+	return t.Threshold
+}
+
+func (t *ImportTriage) model() string {
+	if t.Model != "" {
+		return t.Model
+	}
+	return t.Provider.Name()
+}
+
+// triage scores raw. A failed LLM call returns a nil score: the gate fails
+// open so an outage never silently drops an import.
+func (e *Engine) triage(ctx context.Context, raw RawMemory, t *ImportTriage, result *ImportResult) *extract.TriageScore {
+	similar := e.triageSimilar(ctx, raw.Content)
+	score, err := extract.TriageChunk(ctx, t.Provider, extract.NewTriagePromptData(raw.SourceFile, raw.Content, similar))
+	if err != nil {
+		result.TriageFailures++
+		return nil
+	}
+	result.MemoriesTriaged++
+	return score
+}
+
+// triageSimilar finds the stored memories closest to content, so the model
+// can judge novelty against what the store already holds. Keyword search
+// narrows the store; bag-of-words cosine ranks the matches.
+func (e *Engine) triageSimilar(ctx context.Context, content string) []string {
+	query := triageQuery(content)
+	if query == "" {
+		return nil
+	}
+	results, err := e.store.SearchFTS(ctx, query, triageSimilarCandidates)
+	if err != nil || len(results) == 0 {
+		return nil
+	}
+	vec := vectorizeText(content)
+	type ranked struct {
+		content string
+		score   float64
+	}
+	matches := make([]ranked, 0, len(results))
+	for _, r := range results {
+		matches = append(matches, ranked{r.Memory.Content, cosineTextSimilarity(vec, vectorizeText(r.Memory.Content))})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	similar := make([]string, 0, 3)
+	for _, m := range matches {
+		if len(similar) == cap(similar) {
+			break
+		}
+		similar = append(similar, m.content)
+	}
+	return similar
+}
+
+// triageQuery ORs the longest distinct words of content into an FTS query.
+func triageQuery(content string) string {
+	seen := map[string]bool{}
+	var words []string
+	for _, tok := range tokenSplitRE.Split(strings.ToLower(content), -1) {
+		if len(tok) < 4 || seen[tok] {
+			continue
+		}
+		seen[tok] = true
+		words = append(words, tok)
+	}
+	sort.SliceStable(words, func(i, j int) bool { return len(words[i]) > len(words[j]) })
+	if len(words) > 8 {
+		words = words[:8]
+	}
+	for i, w := range words {
+		words[i] = `"` + w + `"`
+	}
+	return strings.Join(words, " OR ")
+}
+
+// recordTriage writes the audit row for a triaged chunk when the store keeps
+// the log. memoryID is 0 for dropped chunks.
+func (e *Engine) recordTriage(ctx context.Context, raw RawMemory, hash string, t *ImportTriage, score *extract.TriageScore, decision string, memoryID int64) error {
+	recorder, ok := e.store.(triageRecorder)
+	if !ok {
+		return nil
+	}
+	if err := recorder.RecordImportTriage(ctx, &store.ImportTriageRecord{
+		MemoryID:    memoryID,
+		SourceFile:  raw.SourceFile,
+		SourceLine:  raw.SourceLine,
+		ContentHash: hash,
+		Excerpt:     raw.Content,
+		Novelty:     score.Novelty,
+		Durability:  score.Durability,
+		Specificity: score.Specificity,
+		Score:       score.Score,
+		Threshold:   t.threshold(),
+		Decision:    decision,
+		Reason:      score.Reason,
+		Model:       t.model(),
+	}); err != nil {
+		return fmt.Errorf("recording import triage: %w", err)
+	}
+	return nil
+}
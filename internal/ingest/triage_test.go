@@ -0,0 +1,112 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// triageProvider scores chunks mentioning "decided" as worth keeping.
+type triageProvider struct {
+	prompts []string
+	err     error
+}
+
+func (p *triageProvider) Complete(_ context.Context, prompt string, _ llm.CompletionOpts) (string, error) {
+	p.prompts = append(p.prompts, prompt)
+	if p.err != nil {
+		return "", p.err
+	}
+	if strings.Contains(prompt, "decided") {
+		return `{"novelty": 0.9, "durability": 0.9, "specificity": 0.6, "reason": "a decision"}`, nil
+	}
+	return `{"novelty": 0.2, "durability": 0.1, "specificity": 0.3, "reason": "chatter"}`, nil
+}
+
+func (p *triageProvider) Name() string { return "fake/triage" }
+
+func writeTriageFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	content := "We decided to run the ledger on Postgres 16 in production.\n\n" +
+		"Morning standup went fine, nothing much to report today.\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportFile_TriageGateKeepsAndAudits(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	if _, err := s.AddMemory(ctx, &store.Memory{Content: "The ledger database is Postgres 15.", SourceFile: "old.md"}); err != nil {
+		t.Fatal(err)
+	}
+	provider := &triageProvider{}
+	e := NewEngine(s)
+
+	res, err := e.ImportFile(ctx, writeTriageFile(t), ImportOptions{Triage: &ImportTriage{Provider: provider}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.MemoriesNew != 1 || res.MemoriesTriaged != 2 || res.TriageDropped != 1 {
+		t.Fatalf("expected one kept and one dropped chunk: %+v", res)
+	}
+	if !strings.Contains(provider.prompts[0], "- The ledger database is Postgres 15.") {
+		t.Fatalf("novelty should be judged against similar stored memories:\n%s", provider.prompts[0])
+	}
+	if !strings.Contains(FormatImportResult(res), "Triage:   2 scored, 1 dropped") {
+		t.Fatalf("missing triage line:\n%s", FormatImportResult(res))
+	}
+
+	records, err := s.(*store.SQLiteStore).ListImportTriage(ctx, store.ImportTriageFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 audit rows, got %+v", records)
+	}
+	for _, r := range records {
+		switch r.Decision {
+		case store.TriageKept:
+			if r.MemoryID != res.NewMemoryIDs[0] || r.Score != 0.8 || r.Model != "fake/triage" {
+				t.Fatalf("unexpected kept row: %+v", r)
+			}
+		case store.TriageDropped:
+			if r.MemoryID != 0 || r.Reason != "chatter" || r.Threshold != 0.5 || !strings.HasPrefix(r.Excerpt, "Morning standup") {
+				t.Fatalf("unexpected dropped row: %+v", r)
+			}
+		}
+	}
+}
+
+func TestImportFile_TriageDryRunAndFailOpen(t *testing.T) {
+	ctx := context.Background()
+	path := writeTriageFile(t)
+
+	s := newTestStore(t)
+	res, err := NewEngine(s).ImportFile(ctx, path, ImportOptions{DryRun: true, Triage: &ImportTriage{Provider: &triageProvider{}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.MemoriesNew != 1 || len(res.DeniedDetails) != 1 || res.DeniedDetails[0].Pattern != "llm_triage" {
+		t.Fatalf("dry run should preview the drop: %+v", res)
+	}
+	if records, _ := s.(*store.SQLiteStore).ListImportTriage(ctx, store.ImportTriageFilter{}); len(records) != 0 {
+		t.Fatalf("dry run should not write the audit log: %+v", records)
+	}
+
+	res, err = NewEngine(s).ImportFile(ctx, path, ImportOptions{Triage: &ImportTriage{Provider: &triageProvider{err: errors.New("503")}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.MemoriesNew != 2 || res.TriageFailures != 2 || res.TriageDropped != 0 {
+		t.Fatalf("LLM errors should keep chunks unscored: %+v", res)
+	}
+}
@@ -0,0 +1,248 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/embed"
+	"github.com/hurttlocker/cortex/internal/extract"
+	"github.com/hurttlocker/cortex/internal/ingest"
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// Built-in job kinds.
+const (
+	KindExtract        = "extract"         // rule extraction, optionally enriched
+	KindEnrich         = "enrich"          // rule extraction plus LLM enrichment
+	KindEmbed          = "embed"           // embed memories that have no embedding
+	KindClusterRebuild = "cluster_rebuild" // recompute topic clusters
+)
+
+// enrichInterval is the minimum gap between LLM enrichment calls across all
+// workers, so a burst of imports can't flood the provider.
+const enrichInterval = 500 * time.Millisecond
+
+// BuiltinOptions supplies the providers the built-in handlers need. Kinds
+// whose provider is missing are not registered.
+type BuiltinOptions struct {
+	Enricher        llm.Provider   // enables enrich, and extract with enrich=true
+	ReviewThreshold float64        // enriched facts below this go to the review queue; 0 = off
+	Embedder        embed.Embedder // enables embed
+}
+
+// ExtractParams are the parameters of extract and enrich jobs.
+type ExtractParams struct {
+	MemoryIDs []int64 `json:"memory_ids"`
+	Enrich    bool    `json:"enrich,omitempty"`
+}
+
+// ExtractResult is the result of extract and enrich jobs.
+type ExtractResult struct {
+	Processed      int    `json:"processed"`
+	FactsExtracted int    `json:"facts_extracted"`
+	FactsEnriched  int    `json:"facts_enriched,omitempty"`
+	FactsQueued    int    `json:"facts_queued_for_review,omitempty"`
+	EnrichSkipped  string `json:"enrich_skipped,omitempty"`
+}
+
+// EmbedParams are the parameters of embed jobs.
+type EmbedParams struct {
+	Source string `json:"source,omitempty"` // limit to one source_file
+}
+
+// RegisterBuiltins registers the handlers for the built-in job kinds.
+func RegisterBuiltins(r *Runner, opts BuiltinOptions) {
+	limiter := &intervalLimiter{interval: enrichInterval}
+	r.Register(KindExtract, extractHandler(opts, limiter, false))
+	if opts.Enricher != nil {
+		r.Register(KindEnrich, extractHandler(opts, limiter, true))
+	}
+	if opts.Embedder != nil {
+		r.Register(KindEmbed, embedHandler(opts.Embedder))
+	}
+	r.Register(KindClusterRebuild, clusterRebuildHandler)
+}
+
+func extractHandler(opts BuiltinOptions, limiter *intervalLimiter, forceEnrich bool) Handler {
+	return func(ctx context.Context, run *Run) (any, error) {
+		var p ExtractParams
+		if err := run.Params(&p); err != nil {
+			return nil, err
+		}
+		if len(p.MemoryIDs) == 0 {
+			return nil, fmt.Errorf("memory_ids is required")
+		}
+		enrich := p.Enrich || forceEnrich
+		res := &ExtractResult{}
+		if enrich && opts.Enricher == nil {
+			enrich = false
+			res.EnrichSkipped = "no LLM configured for the job runner"
+		}
+		st := run.runner.store
+
+		for i, id := range p.MemoryIDs {
+			if err := ctx.Err(); err != nil {
+				return res, err
+			}
+			var mem *store.Memory
+			var ruleFacts []extract.ExtractedFact
+			err := run.Exclusive(func() error {
+				m, err := st.GetMemory(ctx, id)
+				if err != nil {
+					return fmt.Errorf("loading memory %d: %w", id, err)
+				}
+				if m == nil || m.DeletedAt != nil {
+					return nil
+				}
+				mem = m
+				facts, stored, err := ingest.ExtractMemoryFacts(ctx, st, m)
+				ruleFacts = facts
+				res.FactsExtracted += stored
+				return err
+			})
+			if err != nil {
+				return res, err
+			}
+
+			if enrich && mem != nil && len(strings.TrimSpace(mem.Content)) >= 50 {
+				if err := limiter.wait(ctx); err != nil {
+					return res, err
+				}
+				stored, queued, err := enrichMemory(ctx, run, opts, mem, ruleFacts)
+				if err != nil {
+					return res, fmt.Errorf("enriching memory %d: %w", id, err)
+				}
+				res.FactsEnriched += stored
+				res.FactsQueued += queued
+			}
+			if enrich {
+				// Done with this memory; `cortex import --async-enrich`
+				// listed it as pending enrichment.
+				if err := run.Exclusive(func() error { return st.ClearEnrichmentPending(ctx, id) }); err != nil {
+					return res, err
+				}
+			}
+
+			res.Processed++
+			if err := run.Progress(i+1, len(p.MemoryIDs)); err != nil {
+				return res, err
+			}
+		}
+		return res, nil
+	}
+}
+
+// enrichMemory asks the LLM for facts the rules missed and stores them,
+// routing low-confidence ones to the review queue like `cortex import`.
+func enrichMemory(ctx context.Context, run *Run, opts BuiltinOptions, mem *store.Memory, ruleFacts []extract.ExtractedFact) (stored, queued int, err error) {
+	anchor := ""
+	if mem.Metadata != nil {
+		anchor = mem.Metadata.TimestampStart
+	}
+	result, err := extract.EnrichFacts(ctx, opts.Enricher, mem.Content, ruleFacts, anchor)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	st := run.runner.store
+	err = run.Exclusive(func() error {
+		if err := st.RecordExtractionYield(ctx, store.YieldStageEnrich, mem.Content, len(result.NewFacts)); err != nil {
+			return err
+		}
+		for _, ef := range result.NewFacts {
+			fact := &store.Fact{
+				MemoryID:    mem.ID,
+				Subject:     ef.Subject,
+				Predicate:   ef.Predicate,
+				Object:      ef.Object,
+				FactType:    ef.FactType,
+				Confidence:  ef.Confidence,
+				DecayRate:   ef.DecayRate,
+				SourceQuote: ef.SourceQuote,
+				Negated:     ef.Negated,
+			}
+			if !ingest.ShouldStoreExtractedFact(fact) {
+				continue
+			}
+			if fact.Confidence < opts.ReviewThreshold {
+				if _, err := st.QueueFactReview(ctx, &store.FactReview{
+					MemoryID:    fact.MemoryID,
+					Subject:     fact.Subject,
+					Predicate:   fact.Predicate,
+					Object:      fact.Object,
+					FactType:    fact.FactType,
+					Confidence:  fact.Confidence,
+					DecayRate:   fact.DecayRate,
+					SourceQuote: fact.SourceQuote,
+					Method:      ef.ExtractionMethod,
+					Model:       result.Model,
+				}); err == nil {
+					queued++
+				}
+				continue
+			}
+			if _, err := st.AddFact(ctx, fact); err == nil {
+				stored++
+			}
+		}
+		return nil
+	})
+	return stored, queued, err
+}
+
+func embedHandler(embedder embed.Embedder) Handler {
+	return func(ctx context.Context, run *Run) (any, error) {
+		var p EmbedParams
+		if err := run.Params(&p); err != nil {
+			return nil, err
+		}
+		opts := ingest.DefaultEmbedOptions()
+		opts.SourceFile = p.Source
+		opts.ProgressFn = func(current, total int) {
+			_ = run.Progress(current, total)
+		}
+		return ingest.NewEmbedEngine(run.runner.store, embedder).EmbedMemories(ctx, opts)
+	}
+}
+
+func clusterRebuildHandler(ctx context.Context, run *Run) (any, error) {
+	var res *store.ClusterRebuildResult
+	err := run.Exclusive(func() error {
+		var err error
+		res, err = run.runner.store.RebuildClusters(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	_ = run.Progress(1, 1)
+	return res, nil
+}
+
+// intervalLimiter spaces calls at least interval apart.
+type intervalLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func (l *intervalLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if d := l.interval - time.Since(l.last); d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	l.last = time.Now()
+	return nil
+}
@@ -0,0 +1,364 @@
+// Package jobs runs Cortex's background work — fact extraction, LLM
+// enrichment, embedding, cluster rebuilds — from the persisted jobs table.
+//
+// Any process can enqueue a job; a Runner (hosted by the daemon, `cortex
+// mcp`, or `cortex jobs start --run`) claims queued jobs of the kinds it has
+// handlers for, runs them on a small worker pool, records progress, and
+// stops a job when cancellation is requested through the store.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const (
+	defaultWorkers      = 2
+	defaultPollInterval = 2 * time.Second
+	// staleAfter is how long a running job may go without a progress update
+	// before a starting runner assumes its process died and requeues it.
+	staleAfter = 10 * time.Minute
+)
+
+// ErrCancelled is returned by Run.Progress once the job has been cancelled;
+// handlers should stop and return it (or ctx.Err()).
+var ErrCancelled = errors.New("job cancelled")
+
+// Handler runs one job. The context is cancelled when the job is cancelled
+// or the runner shuts down. The returned value is stored as the job's JSON
+// result, also when the handler stops early with an error.
+type Handler func(ctx context.Context, run *Run) (any, error)
+
+// Runner claims and executes jobs from the store.
+type Runner struct {
+	// Workers is the number of jobs run concurrently (default 2).
+	Workers int
+	// PollInterval is how often idle workers look for new jobs and running
+	// jobs check for cancellation (default 2s).
+	PollInterval time.Duration
+	// Locker, if set, is held around each Run.Exclusive step so handler
+	// writes serialize with other users of the store (e.g. MCP tool calls).
+	Locker sync.Locker
+	// Logger receives job lifecycle messages (default: discarded).
+	Logger *log.Logger
+
+	store    *store.SQLiteStore
+	handlers map[string]Handler
+	wake     chan struct{}
+
+	mu      sync.Mutex
+	running bool
+}
+
+// NewRunner creates a runner over st. Register handlers before starting it.
+func NewRunner(st *store.SQLiteStore) *Runner {
+	return &Runner{
+		store:    st,
+		handlers: make(map[string]Handler),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Register adds the handler for a job kind.
+func (r *Runner) Register(kind string, h Handler) {
+	r.handlers[kind] = h
+}
+
+// Kinds returns the registered job kinds, sorted.
+func (r *Runner) Kinds() []string {
+	kinds := make([]string, 0, len(r.handlers))
+	for k := range r.handlers {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Store returns the store the runner works on.
+func (r *Runner) Store() *store.SQLiteStore { return r.store }
+
+// Enqueue stores a new job of a registered kind and wakes an idle worker.
+func (r *Runner) Enqueue(ctx context.Context, kind string, params any, createdBy string) (*store.Job, error) {
+	if _, ok := r.handlers[kind]; !ok {
+		return nil, fmt.Errorf("unknown job kind %q (valid: %s)", kind, strings.Join(r.Kinds(), ", "))
+	}
+	job, err := Enqueue(ctx, r.store, kind, params, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+// Enqueue stores a new job without a runner, for processes that only hand
+// work to a daemon. params is encoded as JSON.
+func Enqueue(ctx context.Context, st *store.SQLiteStore, kind string, params any, createdBy string) (*store.Job, error) {
+	job := &store.Job{Kind: kind, CreatedBy: createdBy}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("encoding job params: %w", err)
+		}
+		job.Params = string(data)
+	}
+	if _, err := st.EnqueueJob(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Start runs the worker pool in the background until ctx is cancelled. It
+// is a no-op while the runner is already running.
+func (r *Runner) Start(ctx context.Context) {
+	if !r.claimRunning() {
+		return
+	}
+	go func() {
+		defer r.releaseRunning()
+		if err := r.run(ctx); err != nil {
+			r.logf("runner stopped: %v", err)
+		}
+	}()
+}
+
+// Run runs the worker pool until ctx is cancelled. Jobs left running by a
+// process that died are requeued first.
+func (r *Runner) Run(ctx context.Context) error {
+	if !r.claimRunning() {
+		return fmt.Errorf("job runner already running")
+	}
+	defer r.releaseRunning()
+	return r.run(ctx)
+}
+
+func (r *Runner) claimRunning() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running {
+		return false
+	}
+	r.running = true
+	return true
+}
+
+func (r *Runner) releaseRunning() {
+	r.mu.Lock()
+	r.running = false
+	r.mu.Unlock()
+}
+
+func (r *Runner) run(ctx context.Context) error {
+	if n, err := r.store.RequeueStaleJobs(ctx, time.Now().Add(-staleAfter)); err != nil {
+		return err
+	} else if n > 0 {
+		r.logf("requeued %d stale job(s)", n)
+	}
+
+	workers := r.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.work(ctx)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+func (r *Runner) work(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval())
+	defer ticker.Stop()
+	for {
+		job, err := r.store.ClaimNextJob(ctx, r.Kinds())
+		if err != nil && ctx.Err() == nil {
+			r.logf("claiming job: %v", err)
+		}
+		if job != nil {
+			r.execute(ctx, job)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunJob claims one queued job and runs it in the calling goroutine, e.g.
+// for `cortex jobs start --run` when no daemon is up.
+func (r *Runner) RunJob(ctx context.Context, id int64) (*store.Job, error) {
+	job, err := r.store.ClaimJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job %d is not queued", id)
+	}
+	if _, ok := r.handlers[job.Kind]; !ok {
+		return nil, fmt.Errorf("no handler for job kind %q", job.Kind)
+	}
+	r.execute(ctx, job)
+	return r.store.GetJob(ctx, id)
+}
+
+func (r *Runner) execute(parent context.Context, job *store.Job) {
+	handler, ok := r.handlers[job.Kind]
+	if !ok {
+		_ = r.store.FinishJob(parent, job.ID, store.JobFailed, "", "no handler for job kind "+job.Kind)
+		return
+	}
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	run := &Run{Job: job, runner: r, ctx: ctx, cancel: cancel}
+
+	// Watch for cancel requests made while the handler is between
+	// progress updates.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(r.pollInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if flagged, err := r.store.JobCancelRequested(ctx, job.ID); err == nil && flagged {
+					run.markCancelled()
+				}
+			}
+		}
+	}()
+
+	r.logf("job %d (%s) started", job.ID, job.Kind)
+	result, err := runHandler(ctx, handler, run)
+
+	status, errMsg := store.JobDone, ""
+	switch {
+	case run.wasCancelled():
+		status = store.JobCancelled
+	case err != nil && parent.Err() != nil:
+		status, errMsg = store.JobFailed, "interrupted by shutdown; start it again to retry"
+	case err != nil:
+		status, errMsg = store.JobFailed, err.Error()
+	}
+	var resultJSON string
+	if result != nil {
+		if data, mErr := json.Marshal(result); mErr == nil {
+			resultJSON = string(data)
+		}
+	}
+	// Record the outcome even when the runner itself is shutting down.
+	if fErr := r.store.FinishJob(context.WithoutCancel(parent), job.ID, status, resultJSON, errMsg); fErr != nil {
+		r.logf("job %d: %v", job.ID, fErr)
+	}
+	if errMsg != "" {
+		r.logf("job %d (%s) %s: %s", job.ID, job.Kind, status, errMsg)
+	} else {
+		r.logf("job %d (%s) %s", job.ID, job.Kind, status)
+	}
+}
+
+// runHandler calls h, turning a panic into a job failure.
+func runHandler(ctx context.Context, h Handler, run *Run) (result any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return h(ctx, run)
+}
+
+func (r *Runner) pollInterval() time.Duration {
+	if r.PollInterval > 0 {
+		return r.PollInterval
+	}
+	return defaultPollInterval
+}
+
+func (r *Runner) logf(format string, args ...any) {
+	if r.Logger != nil {
+		r.Logger.Printf(format, args...)
+	}
+}
+
+// Run is the handle a Handler uses to read its parameters, report
+// progress, and serialize store writes.
+type Run struct {
+	Job *store.Job
+
+	runner *Runner
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	cancelled bool
+}
+
+// Params decodes the job's JSON parameters into v. Empty params leave v
+// untouched.
+func (run *Run) Params(v any) error {
+	if strings.TrimSpace(run.Job.Params) == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(run.Job.Params), v); err != nil {
+		return fmt.Errorf("decoding %s job params: %w", run.Job.Kind, err)
+	}
+	return nil
+}
+
+// Progress records done of total units of work. It returns ErrCancelled once
+// the job has been cancelled.
+func (run *Run) Progress(done, total int) error {
+	flagged, err := run.runner.store.UpdateJobProgress(context.WithoutCancel(run.ctx), run.Job.ID, done, total)
+	if err == nil && flagged {
+		run.markCancelled()
+	}
+	if run.wasCancelled() {
+		return ErrCancelled
+	}
+	return nil
+}
+
+// Exclusive runs fn while holding the runner's Locker, if any.
+func (run *Run) Exclusive(fn func() error) error {
+	if l := run.runner.Locker; l != nil {
+		l.Lock()
+		defer l.Unlock()
+	}
+	return fn()
+}
+
+func (run *Run) markCancelled() {
+	run.mu.Lock()
+	run.cancelled = true
+	run.mu.Unlock()
+	run.cancel()
+}
+
+func (run *Run) wasCancelled() bool {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	return run.cancelled
+}
@@ -0,0 +1,192 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func newTestStore(t *testing.T) *store.SQLiteStore {
+	t.Helper()
+	s, err := store.NewStore(store.StoreConfig{DBPath: ":memory:"})
+	if err != nil {
+		t.Fatalf("creating test store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s.(*store.SQLiteStore)
+}
+
+func waitForJob(t *testing.T, s *store.SQLiteStore, id int64) *store.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		j, err := s.GetJob(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if j.Finished() {
+			return j
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %d did not finish: %+v", id, j)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRunner_ExtractJob(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	memID, err := s.AddMemory(ctx, &store.Memory{Content: "Database: PostgreSQL 16\nDeploy target: fly.io", SourceFile: "infra.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRunner(s)
+	r.PollInterval = 10 * time.Millisecond
+	RegisterBuiltins(r, BuiltinOptions{})
+	if got := r.Kinds(); len(got) != 2 || got[0] != KindClusterRebuild || got[1] != KindExtract {
+		t.Fatalf("kinds without providers = %v, want cluster_rebuild and extract", got)
+	}
+	if _, err := r.Enqueue(ctx, KindEmbed, nil, "test"); err == nil {
+		t.Fatal("embed should be rejected without an embedder")
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	r.Start(runCtx)
+
+	job, err := r.Enqueue(ctx, KindExtract, ExtractParams{MemoryIDs: []int64{memID}, Enrich: true}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := waitForJob(t, s, job.ID)
+	if done.Status != store.JobDone || done.Progress != 1 || done.Total != 1 {
+		t.Fatalf("unexpected finished job: %+v", done)
+	}
+	var res ExtractResult
+	if err := json.Unmarshal([]byte(done.Result), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Processed != 1 || res.FactsExtracted == 0 || res.EnrichSkipped == "" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	bad, _ := r.Enqueue(ctx, KindExtract, ExtractParams{}, "test")
+	if j := waitForJob(t, s, bad.ID); j.Status != store.JobFailed || j.Error == "" {
+		t.Fatalf("extract without memory_ids should fail, got %+v", j)
+	}
+}
+
+// stubEnricher answers every enrichment prompt with one fact.
+type stubEnricher struct{}
+
+func (stubEnricher) Complete(context.Context, string, llm.CompletionOpts) (string, error) {
+	return `{"facts": [{"subject": "deploys", "predicate": "run on", "object": "fridays", "type": "temporal", "confidence": 0.9, "source_quote": "Deploys run on fridays"}]}`, nil
+}
+
+func (stubEnricher) Name() string { return "stub/enricher" }
+
+func TestRunner_EnrichJobClearsPendingEnrichment(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	memID, err := s.AddMemory(ctx, &store.Memory{Content: "Deploys run on fridays after the checklist is reviewed by the on-call engineer.", SourceFile: "ops.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRunner(s)
+	RegisterBuiltins(r, BuiltinOptions{Enricher: stubEnricher{}})
+	job, err := Enqueue(ctx, s, KindEnrich, ExtractParams{MemoryIDs: []int64{memID}, Enrich: true}, "import")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MarkEnrichmentPending(ctx, job.ID, []int64{memID}); err != nil {
+		t.Fatal(err)
+	}
+
+	done, err := r.RunJob(ctx, job.ID)
+	if err != nil || done.Status != store.JobDone {
+		t.Fatalf("job = %+v, %v", done, err)
+	}
+	var res ExtractResult
+	if err := json.Unmarshal([]byte(done.Result), &res); err != nil || res.FactsEnriched != 1 {
+		t.Fatalf("result = %s, %v", done.Result, err)
+	}
+	if st, err := s.GetEnrichmentState(ctx, memID); err != nil || st != nil {
+		t.Fatalf("enrichment state after job = %+v, %v", st, err)
+	}
+}
+
+func TestRunner_CancelRunningJob(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	r := NewRunner(s)
+	r.PollInterval = 10 * time.Millisecond
+	started := make(chan struct{})
+	r.Register("slow", func(ctx context.Context, run *Run) (any, error) {
+		close(started)
+		for i := 0; ; i++ {
+			if err := run.Progress(i, 1000); err != nil {
+				return map[string]int{"steps": i}, err
+			}
+			select {
+			case <-ctx.Done():
+				return map[string]int{"steps": i}, ctx.Err()
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+	})
+	r.Register("boom", func(ctx context.Context, run *Run) (any, error) {
+		panic("kaboom")
+	})
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	r.Start(runCtx)
+	if err := r.Run(runCtx); err == nil {
+		t.Fatal("Run should refuse to start a second pool")
+	}
+
+	job, _ := r.Enqueue(ctx, "slow", nil, "test")
+	<-started
+	if _, err := s.CancelJob(ctx, job.ID); err != nil {
+		t.Fatal(err)
+	}
+	j := waitForJob(t, s, job.ID)
+	if j.Status != store.JobCancelled || j.Result == "" || j.Error != "" {
+		t.Fatalf("expected a cancelled job with partial result, got %+v", j)
+	}
+
+	boom, _ := r.Enqueue(ctx, "boom", nil, "test")
+	if j := waitForJob(t, s, boom.ID); j.Status != store.JobFailed || j.Error != "panic: kaboom" {
+		t.Fatalf("a panicking handler should fail the job, got %+v", j)
+	}
+}
+
+func TestRunner_RunJob(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	r := NewRunner(s)
+	RegisterBuiltins(r, BuiltinOptions{})
+	job, err := Enqueue(ctx, s, KindClusterRebuild, nil, "cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done, err := r.RunJob(ctx, job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.Status != store.JobDone || done.CreatedBy != "cli" {
+		t.Fatalf("unexpected job: %+v", done)
+	}
+	if _, err := r.RunJob(ctx, job.ID); err == nil {
+		t.Fatal("a finished job must not run again")
+	}
+}
@@ -125,11 +125,20 @@ func (r *Runner) applyReinforcePromote(ctx context.Context, dryRun bool) ([]Acti
 	actions := []Action{}
 	stats := PolicySkipStats{SkipReasons: make(map[string]int)}
 	rows, err := r.sqlite.GetDB().QueryContext(ctx, `
-		WITH source_counts AS (
-			SELECT LOWER(subject) AS lsub, LOWER(predicate) AS lpred, LOWER(object) AS lobj,
-			       COUNT(DISTINCT memory_id) AS source_count
+		WITH fact_sources AS (
+			SELECT subject, predicate, object, memory_id
 			FROM facts
 			WHERE superseded_by IS NULL
+			UNION
+			-- Independent sources folded into an existing fact by corroboration.
+			SELECT f.subject, f.predicate, f.object, c.memory_id
+			FROM fact_corroborations c JOIN facts f ON f.id = c.fact_id
+			WHERE f.superseded_by IS NULL
+		),
+		source_counts AS (
+			SELECT LOWER(subject) AS lsub, LOWER(predicate) AS lpred, LOWER(object) AS lobj,
+			       COUNT(DISTINCT memory_id) AS source_count
+			FROM fact_sources
 			GROUP BY LOWER(subject), LOWER(predicate), LOWER(object)
 		)
 		SELECT f.id, f.state,
@@ -203,6 +212,10 @@ func (r *Runner) applyDecayRetire(ctx context.Context, dryRun bool) ([]Action, P
 	cfg := r.policies.DecayRetire
 	actions := []Action{}
 	stats := PolicySkipStats{SkipReasons: make(map[string]int)}
+	held, err := r.sqlite.HeldFactIDs(ctx)
+	if err != nil {
+		return nil, stats, fmt.Errorf("load archive holds: %w", err)
+	}
 	rows, err := r.sqlite.GetDB().QueryContext(ctx, `
 		SELECT f.id, f.state, f.confidence,
 		       COALESCE(MAX(a.created_at), f.last_reinforced) AS last_access
@@ -227,6 +240,11 @@ func (r *Runner) applyDecayRetire(ctx context.Context, dryRun bool) ([]Action, P
 			return nil, stats, fmt.Errorf("parse decay-retire last_access %q: %w", lastAccessRaw, err)
 		}
 		stats.Scanned++
+		if held[factID] {
+			stats.SkipReasons["archive_hold"]++
+			stats.Skipped++
+			continue
+		}
 		days := int(r.now.Sub(lastAccess).Hours() / 24)
 		tooFresh := days < cfg.InactiveDays
 		confTooHigh := confidence >= cfg.ConfidenceBelow
@@ -326,7 +344,8 @@ func (r *Runner) applyConflictSupersede(ctx context.Context, dryRun bool) ([]Act
 func (r *Runner) pickConflictWinner(cfg cfgresolver.ConflictSupersedePolicy, f1, f2 store.Fact) (winner, loser store.Fact, reason string, ok bool, skipReason string) {
 	obj1 := strings.ToLower(strings.TrimSpace(f1.Object))
 	obj2 := strings.ToLower(strings.TrimSpace(f2.Object))
-	if obj1 != "" && obj2 != "" && obj1 != obj2 {
+	// "not enabled" contains "enabled" but is its opposite, not a refinement.
+	if obj1 != "" && obj2 != "" && obj1 != obj2 && f1.Negated == f2.Negated {
 		switch {
 		case strings.Contains(obj1, obj2) && len(obj1) > len(obj2):
 			return f1, f2, fmt.Sprintf("fact %d object is more specific than fact %d (substring match)", f1.ID, f2.ID), true, ""
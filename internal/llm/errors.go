@@ -0,0 +1,43 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// IsRateLimited reports whether err is a provider rate-limit or quota
+// rejection (HTTP 429, RESOURCE_EXHAUSTED, "rate limit" messages). Providers
+// return plain wrapped errors, so this matches on the message.
+func IsRateLimited(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"status 429", "code 429", "rate limit", "rate_limit", "ratelimit", "too many requests", "resource_exhausted", "quota exceeded"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRetryable reports whether a failed completion is worth retrying: rate
+// limits, server-side (5xx) errors, per-call timeouts, and dropped
+// connections. Caller cancellation is never retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if IsRateLimited(err) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"status 500", "status 502", "status 503", "status 504", "status 529",
+		"code 500", "code 503", "overloaded", "timeout", "connection reset", "connection refused", "eof"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/circuit"
+	"github.com/hurttlocker/cortex/internal/timings"
+)
+
+// ErrNoHealthCheck is returned by Probe for providers that cannot be
+// checked without spending tokens.
+var ErrNoHealthCheck = errors.New("provider has no health check")
+
+// HealthChecker is implemented by providers that can verify their endpoint
+// and credentials without running a completion.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// Probe runs p's health check and records the outcome on p's circuit
+// breaker, so a provider found down is skipped by failover chains until its
+// cooldown passes.
+func Probe(ctx context.Context, p Provider) error {
+	hc, ok := p.(HealthChecker)
+	if !ok {
+		return ErrNoHealthCheck
+	}
+	err := hc.HealthCheck(ctx)
+	if ctx.Err() == nil {
+		BreakerFor(p).Record(err)
+	}
+	return err
+}
+
+// BreakerFor returns the circuit breaker tracking p.
+func BreakerFor(p Provider) *circuit.Breaker {
+	return circuit.For("llm:" + p.Name())
+}
+
+// Failover is a Provider that tries a primary provider and then each
+// fallback in order until one succeeds. Providers whose circuit breaker is
+// open are skipped, unless every provider in the chain is open, in which
+// case the primary is tried anyway. Each hand-off to the next provider is
+// recorded in timings as a failover against the provider that was passed
+// over.
+type Failover struct {
+	members []Provider
+}
+
+// NewFailover chains primary with fallbacks.
+func NewFailover(primary Provider, fallbacks ...Provider) *Failover {
+	members := append([]Provider{primary}, fallbacks...)
+	return &Failover{members: members}
+}
+
+// Name returns the primary provider's name.
+func (f *Failover) Name() string { return f.members[0].Name() }
+
+// Members returns the chain in order, primary first.
+func (f *Failover) Members() []Provider {
+	return append([]Provider(nil), f.members...)
+}
+
+// Complete sends the prompt to the first healthy provider that answers.
+// Per-request model overrides only apply to the primary; fallbacks use
+// their own configured model.
+func (f *Failover) Complete(ctx context.Context, prompt string, opts CompletionOpts) (string, error) {
+	var failures []string
+	tried := false
+	for i, p := range f.members {
+		breaker := BreakerFor(p)
+		if !breaker.Allow() {
+			failures = append(failures, p.Name()+": circuit open")
+			if i < len(f.members)-1 {
+				recordFailover(p)
+			}
+			continue
+		}
+		tried = true
+		out, err := f.try(ctx, i, p, prompt, opts)
+		if err == nil {
+			return out, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", p.Name(), err))
+		if i < len(f.members)-1 {
+			recordFailover(p)
+		}
+	}
+	if !tried {
+		// Every breaker is open; give the primary a chance rather than
+		// failing without a single call.
+		out, err := f.try(ctx, 0, f.members[0], prompt, opts)
+		if err == nil {
+			return out, nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", f.members[0].Name(), err))
+	}
+	return "", fmt.Errorf("all LLM providers failed: %s", strings.Join(failures, "; "))
+}
+
+func (f *Failover) try(ctx context.Context, i int, p Provider, prompt string, opts CompletionOpts) (string, error) {
+	if i > 0 {
+		opts.Model = ""
+	}
+	out, err := p.Complete(ctx, prompt, opts)
+	if err == nil || ctx.Err() == nil {
+		BreakerFor(p).Record(err)
+	}
+	return out, err
+}
+
+// HealthCheck probes every provider in the chain and succeeds if any of
+// them is healthy.
+func (f *Failover) HealthCheck(ctx context.Context) error {
+	var failures []string
+	for _, p := range f.members {
+		err := Probe(ctx, p)
+		if err == nil || errors.Is(err, ErrNoHealthCheck) {
+			return nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", p.Name(), err))
+	}
+	return fmt.Errorf("no healthy LLM provider: %s", strings.Join(failures, "; "))
+}
+
+func recordFailover(p Provider) {
+	provider, model, _ := strings.Cut(p.Name(), "/")
+	timings.RecordFailover(timings.LLM, provider, model)
+}
@@ -0,0 +1,183 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/circuit"
+	"github.com/hurttlocker/cortex/internal/timings"
+)
+
+type failingProvider struct {
+	name  string
+	err   error
+	calls int
+	opts  []CompletionOpts
+}
+
+func (p *failingProvider) Complete(_ context.Context, _ string, opts CompletionOpts) (string, error) {
+	p.calls++
+	p.opts = append(p.opts, opts)
+	if p.err != nil {
+		return "", p.err
+	}
+	return "ok from " + p.name, nil
+}
+
+func (p *failingProvider) Name() string { return p.name }
+
+func TestFailover_FallsBackAndSkipsOpenBreaker(t *testing.T) {
+	circuit.Reset()
+	timings.Enable()
+	t.Cleanup(func() { circuit.Reset(); timings.Disable() })
+
+	primary := &failingProvider{name: "openrouter/openai/gpt-4o-mini", err: errors.New("openrouter API error (status 503): down")}
+	fallback := &failingProvider{name: "google/gemini-2.5-flash"}
+	f := NewFailover(primary, fallback)
+	if f.Name() != primary.name {
+		t.Fatalf("Name() = %q, want primary name", f.Name())
+	}
+
+	for i := 0; i < circuit.Threshold+2; i++ {
+		out, err := f.Complete(context.Background(), "prompt", CompletionOpts{Model: "openrouter/openai/gpt-4o"})
+		if err != nil {
+			t.Fatalf("Complete: %v", err)
+		}
+		if out != "ok from google/gemini-2.5-flash" {
+			t.Fatalf("unexpected output %q", out)
+		}
+	}
+	if primary.calls != circuit.Threshold {
+		t.Fatalf("expected primary to be skipped once its breaker opened, got %d calls", primary.calls)
+	}
+	if fallback.opts[0].Model != "" {
+		t.Fatalf("per-request model override leaked to fallback: %q", fallback.opts[0].Model)
+	}
+
+	var primaryUsage timings.ModelUsage
+	for _, m := range timings.Snapshot().Models {
+		if m.Provider == "openrouter" {
+			primaryUsage = m
+		}
+	}
+	if primaryUsage.Failovers != circuit.Threshold+2 {
+		t.Fatalf("expected %d failovers recorded, got %+v", circuit.Threshold+2, primaryUsage)
+	}
+}
+
+func TestFailover_AllFailAndCancellation(t *testing.T) {
+	circuit.Reset()
+	t.Cleanup(circuit.Reset)
+
+	a := &failingProvider{name: "openrouter/a", err: errors.New("status 500")}
+	b := &failingProvider{name: "google/b", err: errors.New("status 429")}
+	_, err := NewFailover(a, b).Complete(context.Background(), "p", CompletionOpts{})
+	if err == nil || !strings.Contains(err.Error(), "openrouter/a: status 500") || !IsRateLimited(err) {
+		t.Fatalf("expected aggregated error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := &failingProvider{name: "openrouter/c", err: context.Canceled}
+	d := &failingProvider{name: "google/d"}
+	if _, err := NewFailover(c, d).Complete(ctx, "p", CompletionOpts{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation to stop the chain, got %v", err)
+	}
+	if d.calls != 0 {
+		t.Fatal("fallback must not run after the caller cancelled")
+	}
+	if st := circuit.For("llm:openrouter/c").Status(); st.Failures != 0 {
+		t.Fatalf("cancellation must not count against the breaker: %+v", st)
+	}
+}
+
+func TestFailover_AllBreakersOpenStillTriesPrimary(t *testing.T) {
+	circuit.Reset()
+	t.Cleanup(circuit.Reset)
+
+	a := &failingProvider{name: "openrouter/a"}
+	b := &failingProvider{name: "google/b"}
+	for _, p := range []Provider{a, b} {
+		for i := 0; i < circuit.Threshold; i++ {
+			BreakerFor(p).Record(errors.New("down"))
+		}
+	}
+	out, err := NewFailover(a, b).Complete(context.Background(), "p", CompletionOpts{})
+	if err != nil || out != "ok from openrouter/a" {
+		t.Fatalf("expected the primary to be retried, got %q, %v", out, err)
+	}
+	if BreakerFor(a).Status().State != circuit.Closed {
+		t.Fatal("expected a success to close the primary's breaker")
+	}
+}
+
+func TestProbe_OpenRouterKeyEndpoint(t *testing.T) {
+	circuit.Reset()
+	t.Cleanup(circuit.Reset)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/key" {
+			t.Errorf("unexpected probe path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	good := &openrouterProvider{apiKey: "good", model: "m", baseURL: srv.URL}
+	if err := Probe(context.Background(), good); err != nil {
+		t.Fatalf("Probe(good): %v", err)
+	}
+	bad := &openrouterProvider{apiKey: "bad", model: "m2", baseURL: srv.URL}
+	if err := Probe(context.Background(), bad); err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Fatalf("expected 401 from probe, got %v", err)
+	}
+	if st := BreakerFor(bad).Status(); st.Failures != 1 {
+		t.Fatalf("expected the failed probe on the breaker, got %+v", st)
+	}
+	if err := Probe(context.Background(), &failingProvider{name: "x/y"}); !errors.Is(err, ErrNoHealthCheck) {
+		t.Fatalf("expected ErrNoHealthCheck, got %v", err)
+	}
+}
+
+func TestNewProvider_ConfiguredFallbackChain(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "")
+	t.Setenv("OPENROUTER_API_KEY", "or-key")
+	t.Setenv("CORTEX_LLM_FALLBACK", "openrouter/openai/gpt-4o-mini, google/gemini-2.5-flash")
+
+	// Primary has no key: the first usable fallback serves alone.
+	p, err := NewProvider(Config{Provider: "google", Model: "gemini-2.5-flash"})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	if p.Name() != "openrouter/openai/gpt-4o-mini" {
+		t.Fatalf("expected openrouter fallback, got %s", p.Name())
+	}
+
+	t.Setenv("GEMINI_API_KEY", "g-key")
+	p, err = NewProvider(Config{Provider: "google", Model: "gemini-2.5-flash"})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	chain, ok := p.(*Failover)
+	if !ok {
+		t.Fatalf("expected a failover chain, got %T", p)
+	}
+	// The duplicate google entry is dropped.
+	if members := chain.Members(); len(members) != 2 || members[1].Name() != "openrouter/openai/gpt-4o-mini" {
+		t.Fatalf("unexpected chain: %v", members)
+	}
+
+	if _, err := NewProvider(Config{Provider: "bogus"}); err == nil {
+		t.Fatal("unknown providers must still be rejected")
+	}
+}
@@ -0,0 +1,183 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Gemini context caching: a conversation prefix (system prompt, preset
+// instructions, large reason context) is uploaded once as a cachedContents
+// resource and later requests reference it by name, paying the cached-token
+// rate instead of the full input rate. Caches live for geminiCacheTTL, which
+// covers a daily-digest style run firing several presets back to back.
+
+// geminiCacheTTL is how long created caches live server-side.
+var geminiCacheTTL = 10 * time.Minute
+
+// geminiCacheMargin keeps us from referencing a cache that is about to
+// expire mid-request.
+const geminiCacheMargin = 30 * time.Second
+
+// Gemini rejects caches below a per-model minimum token count. Estimated at
+// ~4 characters per token.
+const (
+	geminiMinCacheTokens    = 1024
+	geminiMinCacheTokensPro = 4096
+)
+
+type geminiCacheEntry struct {
+	key     string
+	name    string // "cachedContents/..."
+	prefix  int    // messages covered by the cache
+	expires time.Time
+}
+
+var geminiCaches = struct {
+	sync.Mutex
+	entries map[string]*geminiCacheEntry
+	failed  map[string]bool // prefixes the API refused to cache
+}{entries: map[string]*geminiCacheEntry{}, failed: map[string]bool{}}
+
+// cachedPrefix returns a live cache for the first prefix messages, creating
+// it if needed. hit reports whether the cache already existed. It returns nil
+// when the prefix is too small to cache or creation fails; the caller then
+// sends the full conversation.
+func (g *googleProvider) cachedPrefix(ctx context.Context, model string, messages []Message, prefix int) (entry *geminiCacheEntry, hit bool) {
+	if prefix >= len(messages) {
+		// Something has to follow the cache.
+		prefix = len(messages) - 1
+	}
+	if prefix <= 0 {
+		return nil, false
+	}
+	chars := 0
+	for _, m := range messages[:prefix] {
+		chars += len(m.Content)
+	}
+	minTokens := geminiMinCacheTokens
+	if strings.Contains(model, "pro") {
+		minTokens = geminiMinCacheTokensPro
+	}
+	if chars/4 < minTokens {
+		return nil, false
+	}
+
+	key := geminiCacheKey(g.baseURL, g.apiKey, model, messages[:prefix])
+	geminiCaches.Lock()
+	if e, ok := geminiCaches.entries[key]; ok && time.Until(e.expires) > geminiCacheMargin {
+		geminiCaches.Unlock()
+		return e, true
+	}
+	if geminiCaches.failed[key] {
+		geminiCaches.Unlock()
+		return nil, false
+	}
+	geminiCaches.Unlock()
+
+	e, err := g.createCache(ctx, model, messages[:prefix])
+	geminiCaches.Lock()
+	defer geminiCaches.Unlock()
+	if err != nil {
+		if ctx.Err() == nil {
+			geminiCaches.failed[key] = true
+		}
+		return nil, false
+	}
+	e.key, e.prefix = key, prefix
+	geminiCaches.entries[key] = e
+	return e, false
+}
+
+// createCache uploads messages as a cachedContents resource.
+func (g *googleProvider) createCache(ctx context.Context, model string, messages []Message) (*geminiCacheEntry, error) {
+	system, contents := googleContents(messages)
+	body, err := json.Marshal(struct {
+		Model             string          `json:"model"`
+		SystemInstruction *googleContent  `json:"systemInstruction,omitempty"`
+		Contents          []googleContent `json:"contents,omitempty"`
+		TTL               string          `json:"ttl"`
+	}{
+		Model:             "models/" + model,
+		SystemInstruction: system,
+		Contents:          contents,
+		TTL:               fmt.Sprintf("%ds", int(geminiCacheTTL/time.Second)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling cache request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/cachedContents?key=%s", g.baseURL, g.apiKey)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google cache API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var created struct {
+		Name       string    `json:"name"`
+		ExpireTime time.Time `json:"expireTime"`
+	}
+	if err := json.Unmarshal(respBody, &created); err != nil {
+		return nil, fmt.Errorf("parsing cache response: %w", err)
+	}
+	if created.Name == "" {
+		return nil, fmt.Errorf("google cache API returned no cache name")
+	}
+	if created.ExpireTime.IsZero() {
+		created.ExpireTime = time.Now().Add(geminiCacheTTL)
+	}
+	return &geminiCacheEntry{name: created.Name, expires: created.ExpireTime}, nil
+}
+
+func forgetGeminiCache(e *geminiCacheEntry) {
+	geminiCaches.Lock()
+	defer geminiCaches.Unlock()
+	if cur, ok := geminiCaches.entries[e.key]; ok && cur == e {
+		delete(geminiCaches.entries, e.key)
+	}
+}
+
+// isGeminiCacheGone reports whether a generateContent error means the
+// referenced cache no longer exists.
+func isGeminiCacheGone(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "cachedcontent") || strings.Contains(msg, "cached content")
+}
+
+func geminiCacheKey(baseURL, apiKey, model string, messages []Message) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%s", baseURL, apiKey, model)
+	for _, m := range messages {
+		fmt.Fprintf(h, "|%s:%d:%s", m.Role, len(m.Content), m.Content)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// resetGeminiCaches forgets every known cache. Used by tests.
+func resetGeminiCaches() {
+	geminiCaches.Lock()
+	defer geminiCaches.Unlock()
+	geminiCaches.entries = map[string]*geminiCacheEntry{}
+	geminiCaches.failed = map[string]bool{}
+}
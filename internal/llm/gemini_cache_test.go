@@ -0,0 +1,108 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGeminiChat_CachesLargePrefix(t *testing.T) {
+	resetGeminiCaches()
+	t.Cleanup(resetGeminiCaches)
+
+	var creates, stale atomic.Int32
+	var lastReq googleRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/cachedContents") {
+			creates.Add(1)
+			json.NewEncoder(w).Encode(map[string]any{
+				"name":       "cachedContents/abc",
+				"expireTime": time.Now().Add(10 * time.Minute).Format(time.RFC3339),
+			})
+			return
+		}
+		lastReq = googleRequest{}
+		json.NewDecoder(r.Body).Decode(&lastReq)
+		if lastReq.CachedContent != "" && stale.Load() > 0 {
+			stale.Add(-1)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"message":"CachedContent not found"}}`))
+			return
+		}
+		usage := map[string]int{"promptTokenCount": 1500, "candidatesTokenCount": 10}
+		if lastReq.CachedContent != "" {
+			usage["cachedContentTokenCount"] = 1400
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"candidates":    []any{map[string]any{"content": map[string]any{"parts": []any{map[string]string{"text": "ok"}}}}},
+			"usageMetadata": usage,
+		})
+	}))
+	defer server.Close()
+
+	p := &googleProvider{apiKey: "k", model: "gemini-2.5-flash", baseURL: server.URL}
+	system := strings.Repeat("preset instructions ", 300) // ~1500 tokens
+	messages := []Message{{Role: "system", Content: system}, {Role: "user", Content: "today"}}
+
+	first, err := p.Chat(context.Background(), messages, CompletionOpts{CachePrefix: 1})
+	if err != nil {
+		t.Fatalf("first Chat: %v", err)
+	}
+	if first.CacheHit || first.CachedTokens != 1400 {
+		t.Fatalf("first call should create (not hit) the cache: %+v", first)
+	}
+	if lastReq.SystemInstruction != nil || len(lastReq.Contents) != 1 || lastReq.CachedContent != "cachedContents/abc" {
+		t.Fatalf("cached request should only carry the suffix: %+v", lastReq)
+	}
+
+	second, err := p.Complete(context.Background(), "tomorrow", CompletionOpts{System: system, CachePrefix: 1})
+	if err != nil || second != "ok" {
+		t.Fatalf("second call: %q, %v", second, err)
+	}
+	if creates.Load() != 1 {
+		t.Fatalf("expected the cache to be reused, created %d", creates.Load())
+	}
+
+	// A cache deleted server-side is dropped and the request resent in full.
+	stale.Store(1)
+	res, err := p.Chat(context.Background(), messages, CompletionOpts{CachePrefix: 1})
+	if err != nil {
+		t.Fatalf("Chat after stale cache: %v", err)
+	}
+	if res.CacheHit || lastReq.CachedContent != "" || lastReq.SystemInstruction == nil {
+		t.Fatalf("expected an uncached retry, got %+v / %+v", res, lastReq)
+	}
+}
+
+func TestGeminiChat_SmallPrefixNotCached(t *testing.T) {
+	resetGeminiCaches()
+	t.Cleanup(resetGeminiCaches)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/cachedContents") {
+			t.Error("small prefixes must not create caches")
+		}
+		var req googleRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if len(req.Contents) != 2 || req.Contents[1].Role != "model" {
+			t.Errorf("expected assistant turns sent as model role: %+v", req.Contents)
+		}
+		w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"ok"}]}}]}`))
+	}))
+	defer server.Close()
+
+	p := &googleProvider{apiKey: "k", model: "gemini-2.5-pro", baseURL: server.URL}
+	messages := []Message{
+		{Role: "system", Content: strings.Repeat("x", 8000)}, // ~2000 tokens, under the pro minimum
+		{Role: "user", Content: "q"},
+		{Role: "assistant", Content: "a"},
+	}
+	if _, err := p.Chat(context.Background(), messages, CompletionOpts{CachePrefix: 2}); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"strings"
+
+	"github.com/hurttlocker/cortex/internal/timings"
 )
 
 // googleProvider implements Provider using Google AI Studio (Gemini) REST API.
@@ -23,6 +25,7 @@ type googleRequest struct {
 	Contents          []googleContent  `json:"contents"`
 	SystemInstruction *googleContent   `json:"systemInstruction,omitempty"`
 	GenerationConfig  *googleGenConfig `json:"generationConfig,omitempty"`
+	CachedContent     string           `json:"cachedContent,omitempty"`
 }
 
 type googleContent struct {
@@ -51,9 +54,10 @@ type googleResponse struct {
 }
 
 type googleUsage struct {
-	PromptTokenCount     int `json:"promptTokenCount"`
-	CandidatesTokenCount int `json:"candidatesTokenCount"`
-	TotalTokenCount      int `json:"totalTokenCount"`
+	PromptTokenCount        int `json:"promptTokenCount"`
+	CandidatesTokenCount    int `json:"candidatesTokenCount"`
+	TotalTokenCount         int `json:"totalTokenCount"`
+	CachedContentTokenCount int `json:"cachedContentTokenCount"`
 }
 
 type googleError struct {
@@ -67,25 +71,30 @@ func (g *googleProvider) Name() string {
 }
 
 func (g *googleProvider) Complete(ctx context.Context, prompt string, opts CompletionOpts) (string, error) {
-	model := g.model
-	if opts.Model != "" {
-		model = normalizeModelForProvider("google", opts.Model)
+	messages := make([]Message, 0, 2)
+	if opts.System != "" {
+		messages = append(messages, Message{Role: "system", Content: opts.System})
+	} else if opts.CachePrefix > 0 {
+		opts.CachePrefix-- // there is no system message to count
 	}
-
-	req := googleRequest{
-		Contents: []googleContent{
-			{
-				Parts: []googlePart{{Text: prompt}},
-				Role:  "user",
-			},
-		},
+	messages = append(messages, Message{Role: "user", Content: prompt})
+	res, err := g.Chat(ctx, messages, opts)
+	if err != nil {
+		return "", err
 	}
+	return res.Text, nil
+}
 
-	if opts.System != "" {
-		req.SystemInstruction = &googleContent{
-			Parts: []googlePart{{Text: opts.System}},
-		}
+// Chat sends a conversation. opts.System is ignored; pass system messages
+// instead. With opts.CachePrefix set, the leading messages are served from
+// a Gemini context cache (see gemini_cache.go) when they are large enough.
+func (g *googleProvider) Chat(ctx context.Context, messages []Message, opts CompletionOpts) (_ ChatResult, err error) {
+	model := g.model
+	if opts.Model != "" {
+		model = normalizeModelForProvider("google", opts.Model)
 	}
+	call := timings.Begin(timings.LLM, "google", model)
+	defer func() { call.Fail(err); call.End() }()
 
 	genConfig := &googleGenConfig{
 		Temperature: opts.Temperature,
@@ -96,58 +105,142 @@ func (g *googleProvider) Complete(ctx context.Context, prompt string, opts Compl
 	if strings.ToLower(opts.Format) == "json" {
 		genConfig.ResponseMimeType = "application/json"
 	}
-	req.GenerationConfig = genConfig
 
+	system, contents := googleContents(messages)
+	if len(contents) == 0 {
+		return ChatResult{}, fmt.Errorf("google API: conversation has no user message")
+	}
+	req := googleRequest{Contents: contents, SystemInstruction: system, GenerationConfig: genConfig}
+
+	var cache *geminiCacheEntry
+	cacheHit := false
+	if opts.CachePrefix > 0 {
+		cache, cacheHit = g.cachedPrefix(ctx, model, messages, opts.CachePrefix)
+	}
+	var gResp *googleResponse
+	if cache != nil {
+		_, rest := googleContents(messages[cache.prefix:])
+		cached := req
+		cached.SystemInstruction = nil
+		cached.Contents = rest
+		cached.CachedContent = cache.name
+		gResp, err = g.generate(ctx, model, cached)
+		if err != nil && isGeminiCacheGone(err) {
+			// The cache expired or was deleted server-side; send it all.
+			forgetGeminiCache(cache)
+			cache, cacheHit = nil, false
+			gResp, err = g.generate(ctx, model, req)
+		}
+	} else {
+		gResp, err = g.generate(ctx, model, req)
+	}
+	if err != nil {
+		return ChatResult{}, err
+	}
+
+	var res ChatResult
+	if u := gResp.UsageMetadata; u != nil {
+		res.TokensIn, res.TokensOut, res.CachedTokens = u.PromptTokenCount, u.CandidatesTokenCount, u.CachedContentTokenCount
+		call.Tokens(u.PromptTokenCount, u.CandidatesTokenCount)
+	}
+	res.CacheHit = cacheHit
+	call.Cache(cacheHit, res.CachedTokens)
+
+	if gResp.Error != nil {
+		return res, fmt.Errorf("google API error: %s (code %d)", gResp.Error.Message, gResp.Error.Code)
+	}
+
+	if len(gResp.Candidates) == 0 || len(gResp.Candidates[0].Content.Parts) == 0 {
+		return res, fmt.Errorf("empty response from google API")
+	}
+
+	var b strings.Builder
+	for _, part := range gResp.Candidates[0].Content.Parts {
+		if part.Text == "" {
+			continue
+		}
+		b.WriteString(part.Text)
+	}
+	res.Text = strings.TrimSpace(b.String())
+	if res.Text == "" {
+		return res, fmt.Errorf("empty response from google API")
+	}
+	return res, nil
+}
+
+// generate posts one generateContent request.
+func (g *googleProvider) generate(ctx context.Context, model string, req googleRequest) (*googleResponse, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
-		return "", fmt.Errorf("marshaling request: %w", err)
+		return nil, fmt.Errorf("marshaling request: %w", err)
 	}
 
 	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", g.baseURL, model, g.apiKey)
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return nil, fmt.Errorf("creating request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	resp, err := g.client.Do(httpReq)
 	if err != nil {
-		return "", fmt.Errorf("sending request: %w", err)
+		return nil, fmt.Errorf("sending request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("reading response: %w", err)
+		return nil, fmt.Errorf("reading response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("google API error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, fmt.Errorf("google API error (status %d): %s", resp.StatusCode, string(respBody))
 	}
 
 	var gResp googleResponse
 	if err := json.Unmarshal(respBody, &gResp); err != nil {
-		return "", fmt.Errorf("parsing response: %w", err)
+		return nil, fmt.Errorf("parsing response: %w", err)
 	}
+	return &gResp, nil
+}
 
-	if gResp.Error != nil {
-		return "", fmt.Errorf("google API error: %s (code %d)", gResp.Error.Message, gResp.Error.Code)
+// googleContents converts messages to Gemini contents. System messages are
+// joined into the system instruction; assistant turns become "model" turns.
+func googleContents(messages []Message) (*googleContent, []googleContent) {
+	var system []string
+	contents := make([]googleContent, 0, len(messages))
+	for _, m := range messages {
+		switch m.Role {
+		case "system":
+			system = append(system, m.Content)
+		case "assistant", "model":
+			contents = append(contents, googleContent{Role: "model", Parts: []googlePart{{Text: m.Content}}})
+		default:
+			contents = append(contents, googleContent{Role: "user", Parts: []googlePart{{Text: m.Content}}})
+		}
 	}
-
-	if len(gResp.Candidates) == 0 || len(gResp.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("empty response from google API")
+	if len(system) == 0 {
+		return nil, contents
 	}
+	return &googleContent{Parts: []googlePart{{Text: strings.Join(system, "\n\n")}}}, contents
+}
 
-	var b strings.Builder
-	for _, part := range gResp.Candidates[0].Content.Parts {
-		if part.Text == "" {
-			continue
-		}
-		b.WriteString(part.Text)
+// HealthCheck looks up the configured model, which verifies the API key and
+// the model name without generating anything.
+func (g *googleProvider) HealthCheck(ctx context.Context) error {
+	url := fmt.Sprintf("%s/models/%s?key=%s", g.baseURL, g.model, g.apiKey)
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
 	}
-	text := strings.TrimSpace(b.String())
-	if text == "" {
-		return "", fmt.Errorf("empty response from google API")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("google API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
 	}
-	return text, nil
+	return nil
 }
@@ -3,6 +3,8 @@ package llm
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -23,6 +25,7 @@ func TestParseLLMFlag(t *testing.T) {
 		{"openrouter model", "openrouter/openai/gpt-5.1-codex-mini", "openrouter", "openai/gpt-5.1-codex-mini", false},
 		{"unknown provider", "anthropic/claude-4", "", "", true},
 		{"no slash", "gemini-3-flash", "", "", true},
+		{"auto router", "auto", "auto", "", false},
 	}
 
 	for _, tt := range tests {
@@ -453,3 +456,29 @@ func TestContextCancellation(t *testing.T) {
 		t.Fatal("expected context cancellation error")
 	}
 }
+
+func TestIsRateLimitedAndRetryable(t *testing.T) {
+	tests := []struct {
+		err         error
+		rateLimited bool
+		retryable   bool
+	}{
+		{nil, false, false},
+		{errors.New(`openrouter API error (status 429): {"error":"slow down"}`), true, true},
+		{errors.New("google API error: Resource has been exhausted (code 429)"), true, true},
+		{errors.New("LLM classify call: RESOURCE_EXHAUSTED"), true, true},
+		{errors.New("openrouter API error (status 503): upstream unavailable"), false, true},
+		{fmt.Errorf("sending request: %w", context.DeadlineExceeded), false, true},
+		{fmt.Errorf("sending request: %w", context.Canceled), false, false},
+		{errors.New("openrouter API error (status 401): invalid key"), false, false},
+		{errors.New("invalid JSON from LLM"), false, false},
+	}
+	for _, tt := range tests {
+		if got := IsRateLimited(tt.err); got != tt.rateLimited {
+			t.Errorf("IsRateLimited(%v) = %t, want %t", tt.err, got, tt.rateLimited)
+		}
+		if got := IsRetryable(tt.err); got != tt.retryable {
+			t.Errorf("IsRetryable(%v) = %t, want %t", tt.err, got, tt.retryable)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"strings"
+
+	"github.com/hurttlocker/cortex/internal/timings"
 )
 
 // openrouterProvider implements Provider using the OpenRouter API (OpenAI-compatible).
@@ -64,11 +66,13 @@ func (o *openrouterProvider) Name() string {
 	return "openrouter/" + o.model
 }
 
-func (o *openrouterProvider) Complete(ctx context.Context, prompt string, opts CompletionOpts) (string, error) {
+func (o *openrouterProvider) Complete(ctx context.Context, prompt string, opts CompletionOpts) (_ string, err error) {
 	model := o.model
 	if opts.Model != "" {
 		model = normalizeModelForProvider("openrouter", opts.Model)
 	}
+	call := timings.Begin(timings.LLM, "openrouter", model)
+	defer func() { call.Fail(err); call.End() }()
 
 	messages := make([]orMessage, 0, 2)
 	if opts.System != "" {
@@ -122,6 +126,9 @@ func (o *openrouterProvider) Complete(ctx context.Context, prompt string, opts C
 	if err := json.Unmarshal(respBody, &orResp); err != nil {
 		return "", fmt.Errorf("parsing response: %w", err)
 	}
+	if u := orResp.Usage; u != nil {
+		call.Tokens(u.PromptTokens, u.CompletionTokens)
+	}
 
 	if orResp.Error != nil {
 		return "", fmt.Errorf("openrouter API error: %s", orResp.Error.Message)
@@ -133,3 +140,23 @@ func (o *openrouterProvider) Complete(ctx context.Context, prompt string, opts C
 
 	return strings.TrimSpace(orResp.Choices[0].Message.Content), nil
 }
+
+// HealthCheck verifies the API key against OpenRouter's key endpoint, which
+// costs no tokens.
+func (o *openrouterProvider) HealthCheck(ctx context.Context) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", o.baseURL+"/key", nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+o.apiKey)
+	resp, err := o.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("openrouter API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
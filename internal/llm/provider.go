@@ -27,11 +27,40 @@ type CompletionOpts struct {
 	Model       string  // Override model for this request (empty = use provider default)
 	Format      string  // "json" for structured output, empty for plain text
 	System      string  // System prompt (optional)
+	Task        string  // Task type (TaskClassify, TaskEnrich, ...) for --llm auto routing
+	// CachePrefix asks providers with context caching (Gemini) to cache the
+	// first CachePrefix messages of the conversation — for Complete, 1 means
+	// the system prompt — because they will be resent within minutes.
+	// Prefixes too small to cache are sent normally. 0 = no caching.
+	CachePrefix int
+}
+
+// Message is one turn of a multi-turn conversation.
+type Message struct {
+	Role    string // "system", "user", or "assistant"
+	Content string
+}
+
+// ChatResult is the outcome of a ChatProvider call.
+type ChatResult struct {
+	Text         string
+	TokensIn     int  // prompt tokens, including cached ones
+	TokensOut    int  // generated tokens
+	CachedTokens int  // prompt tokens read from a context cache
+	CacheHit     bool // the call reused a cache created by an earlier call
+}
+
+// ChatProvider is a Provider that also takes multi-turn conversations and
+// reports token usage, for callers such as reason that manage their own
+// message history.
+type ChatProvider interface {
+	Provider
+	Chat(ctx context.Context, messages []Message, opts CompletionOpts) (ChatResult, error)
 }
 
 // Config holds provider configuration.
 type Config struct {
-	Provider string // "google", "openrouter"
+	Provider string // "google" (alias "gemini"), "openrouter", "auto"
 	Model    string // e.g., "gemini-3-flash", "openai/gpt-5.1-codex-mini"
 	APIKey   string // API key (empty = read from env/config)
 	BaseURL  string // Optional URL override
@@ -50,12 +79,74 @@ func normalizeModelForProvider(provider, model string) string {
 	return model
 }
 
-// NewProvider creates an LLM provider from the given config.
+// NewProvider creates an LLM provider from the given config. When a
+// fallback order is configured (llm.fallback or CORTEX_LLM_FALLBACK), the
+// provider is wrapped in a Failover chain; if the requested provider cannot
+// be built (e.g. its API key is missing) the first usable fallback serves
+// instead.
 func NewProvider(cfg Config) (Provider, error) {
 	resolved, _ := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+	primary, err := newSingleProvider(cfg, resolved)
+	if len(resolved.LLMFallback) == 0 || !knownProvider(cfg.Provider) {
+		return primary, err
+	}
+
+	members := make([]Provider, 0, 1+len(resolved.LLMFallback))
+	seen := map[string]bool{}
+	if primary != nil {
+		members = append(members, primary)
+		seen[primary.Name()] = true
+	}
+	for _, spec := range resolved.LLMFallback {
+		fbCfg, parseErr := ParseLLMFlag(spec)
+		if parseErr != nil {
+			continue
+		}
+		fb, fbErr := newSingleProvider(fbCfg, resolved)
+		if fbErr != nil || seen[fb.Name()] {
+			continue
+		}
+		seen[fb.Name()] = true
+		members = append(members, fb)
+	}
+	switch len(members) {
+	case 0:
+		return nil, err
+	case 1:
+		return members[0], nil
+	}
+	return NewFailover(members[0], members[1:]...), nil
+}
+
+// NewGemini creates the native Gemini (Google AI Studio) provider, which
+// supports context caching through Chat. cfg.Provider is ignored.
+func NewGemini(cfg Config) (ChatProvider, error) {
+	resolved, _ := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+	cfg.Provider = "google"
+	p, err := newSingleProvider(cfg, resolved)
+	if err != nil {
+		return nil, err
+	}
+	return p.(*googleProvider), nil
+}
 
+// newDirectProvider creates a provider without a fallback chain.
+func newDirectProvider(cfg Config) (Provider, error) {
+	resolved, _ := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{})
+	return newSingleProvider(cfg, resolved)
+}
+
+func knownProvider(name string) bool {
+	switch strings.ToLower(name) {
+	case "google", "gemini", "openrouter", AutoProvider:
+		return true
+	}
+	return false
+}
+
+func newSingleProvider(cfg Config, resolved cfgresolver.ResolvedConfig) (Provider, error) {
 	switch strings.ToLower(cfg.Provider) {
-	case "google":
+	case "google", "gemini":
 		key := strings.TrimSpace(cfg.APIKey)
 		if key == "" {
 			key = strings.TrimSpace(os.Getenv("GEMINI_API_KEY"))
@@ -69,7 +160,7 @@ func NewProvider(cfg Config) (Provider, error) {
 		if key == "" {
 			return nil, fmt.Errorf("google provider requires GEMINI_API_KEY or GOOGLE_API_KEY env var")
 		}
-		model := normalizeModelForProvider("google", cfg.Model)
+		model := normalizeModelForProvider("google", normalizeModelForProvider("gemini", cfg.Model))
 		if model == "" {
 			model = "gemini-2.5-flash"
 		}
@@ -108,17 +199,27 @@ func NewProvider(cfg Config) (Provider, error) {
 			baseURL: baseURL,
 		}, nil
 
+	case AutoProvider:
+		return NewRouter(RouterConfig{
+			Tier:           resolved.LLMAuto.Tier,
+			DailyBudgetUSD: resolved.LLMAuto.DailyBudgetUSD,
+		})
+
 	default:
-		return nil, fmt.Errorf("unknown LLM provider: %q (supported: google, openrouter)", cfg.Provider)
+		return nil, fmt.Errorf("unknown LLM provider: %q (supported: google, openrouter, auto)", cfg.Provider)
 	}
 }
 
 // ParseLLMFlag parses a --llm flag value into a Config.
-// Format: "provider/model" e.g., "google/gemini-3-flash", "openrouter/openai/gpt-5.1-codex-mini"
+// Format: "provider/model" e.g., "google/gemini-3-flash", "openrouter/openai/gpt-5.1-codex-mini",
+// or "auto" for budget-aware routing.
 func ParseLLMFlag(flag string) (Config, error) {
 	if flag == "" {
 		return Config{Provider: "google", Model: "gemini-2.5-flash"}, nil
 	}
+	if strings.EqualFold(strings.TrimSpace(flag), AutoProvider) {
+		return Config{Provider: AutoProvider}, nil
+	}
 
 	parts := strings.SplitN(flag, "/", 2)
 	if len(parts) < 2 {
@@ -131,7 +232,9 @@ func ParseLLMFlag(flag string) (Config, error) {
 	switch provider {
 	case "google", "openrouter":
 		return Config{Provider: provider, Model: model}, nil
+	case "gemini":
+		return Config{Provider: "google", Model: model}, nil
 	default:
-		return Config{}, fmt.Errorf("unknown provider %q in --llm flag (supported: google, openrouter)", provider)
+		return Config{}, fmt.Errorf("unknown provider %q in --llm flag (supported: google, gemini, openrouter)", provider)
 	}
 }
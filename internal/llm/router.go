@@ -0,0 +1,318 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/timings"
+	"github.com/hurttlocker/cortex/internal/tokens"
+)
+
+// AutoProvider is the --llm value ("--llm auto") that selects the routing
+// provider instead of a fixed provider/model.
+const AutoProvider = "auto"
+
+// Task types carried in CompletionOpts.Task. The auto router picks a model
+// per task; fixed providers ignore it.
+const (
+	TaskClassify  = "classify"
+	TaskEnrich    = "enrich"
+	TaskResolve   = "resolve"
+	TaskSummarize = "summarize"
+	TaskExpand    = "expand"
+	TaskAnswer    = "answer"
+	TaskTranslate = "translate"
+)
+
+// Quality tiers for the auto router, cheapest first.
+const (
+	TierEconomy  = "economy"
+	TierBalanced = "balanced"
+	TierQuality  = "quality"
+)
+
+var routerTiers = []string{TierEconomy, TierBalanced, TierQuality}
+
+// budgetLowShare is the fraction of the daily budget below which the router
+// drops one quality tier.
+const budgetLowShare = 0.2
+
+// routeModel is one model the router can pick, with the pricing it uses to
+// enforce the daily budget.
+type routeModel struct {
+	inPerM, outPerM float64 // USD per 1M tokens (OpenRouter list price)
+	contextTokens   int
+	googleModel     string // model name on Google AI Studio, if served there
+}
+
+var routeModels = map[string]routeModel{
+	"deepseek/deepseek-v3.2":  {inPerM: 0.14, outPerM: 0.28, contextTokens: 128_000},
+	"google/gemini-2.5-flash": {inPerM: 0.15, outPerM: 0.60, contextTokens: 1_000_000, googleModel: "gemini-2.5-flash"},
+	"x-ai/grok-4.1-fast":      {inPerM: 0.20, outPerM: 0.50, contextTokens: 2_000_000},
+	"google/gemini-2.5-pro":   {inPerM: 1.25, outPerM: 10.00, contextTokens: 1_000_000, googleModel: "gemini-2.5-pro"},
+}
+
+// routeTable lists candidate models per tier and task, in preference order.
+// The "" task is the fallback for unknown or unset tasks.
+var routeTable = map[string]map[string][]string{
+	TierEconomy: {
+		"": {"deepseek/deepseek-v3.2", "google/gemini-2.5-flash", "x-ai/grok-4.1-fast"},
+	},
+	TierBalanced: {
+		TaskClassify:  {"deepseek/deepseek-v3.2", "google/gemini-2.5-flash"},
+		TaskEnrich:    {"x-ai/grok-4.1-fast", "google/gemini-2.5-flash"},
+		TaskResolve:   {"deepseek/deepseek-v3.2", "google/gemini-2.5-flash"},
+		TaskSummarize: {"google/gemini-2.5-flash", "x-ai/grok-4.1-fast"},
+		TaskExpand:    {"google/gemini-2.5-flash", "deepseek/deepseek-v3.2"},
+		"":            {"google/gemini-2.5-flash", "x-ai/grok-4.1-fast"},
+	},
+	TierQuality: {
+		TaskClassify: {"google/gemini-2.5-flash", "deepseek/deepseek-v3.2"},
+		TaskExpand:   {"google/gemini-2.5-flash", "deepseek/deepseek-v3.2"},
+		"":           {"google/gemini-2.5-pro", "x-ai/grok-4.1-fast"},
+	},
+}
+
+// SpendToday, when set, reports the estimated LLM spend already recorded
+// today (the CLI reads it from the telemetry table). The router adds its own
+// in-process spend on top when enforcing the daily budget.
+var SpendToday func() float64
+
+// OnRoute, when set, is called with every routing decision (the CLI logs
+// them under --verbose). Decisions are always recorded in timings/telemetry.
+var OnRoute func(RouteDecision)
+
+// RouteDecision records why the router sent one request where it did.
+type RouteDecision struct {
+	Task         string  `json:"task"`
+	Tier         string  `json:"tier"`
+	Provider     string  `json:"provider"`
+	Model        string  `json:"model"`
+	Reason       string  `json:"reason"`
+	PromptTokens int     `json:"prompt_tokens"`
+	EstCostUSD   float64 `json:"est_cost_usd"`
+	SpentUSD     float64 `json:"spent_usd"`
+	BudgetUSD    float64 `json:"budget_usd,omitempty"`
+}
+
+// RouterConfig configures the auto router.
+type RouterConfig struct {
+	Tier           string  // economy | balanced (default) | quality
+	DailyBudgetUSD float64 // 0 = unlimited
+}
+
+// Router is a Provider that picks a model per request from the task type,
+// prompt size, quality tier, and remaining daily budget.
+type Router struct {
+	tier   string
+	budget float64
+
+	newProvider func(Config) (Provider, error)
+
+	mu         sync.Mutex
+	providers  map[string]Provider // "provider|model" → provider; nil = unavailable
+	baseSpent  float64
+	baseLoaded bool
+	spent      float64 // estimated spend of this process's routed calls
+}
+
+// NewRouter creates an auto router.
+func NewRouter(cfg RouterConfig) (*Router, error) {
+	tier := strings.ToLower(strings.TrimSpace(cfg.Tier))
+	if tier == "" {
+		tier = TierBalanced
+	}
+	if _, ok := routeTable[tier]; !ok {
+		return nil, fmt.Errorf("unknown LLM tier %q (valid: economy, balanced, quality)", cfg.Tier)
+	}
+	if cfg.DailyBudgetUSD < 0 {
+		return nil, fmt.Errorf("daily LLM budget cannot be negative")
+	}
+	return &Router{
+		tier:        tier,
+		budget:      cfg.DailyBudgetUSD,
+		newProvider: newDirectProvider,
+		providers:   make(map[string]Provider),
+	}, nil
+}
+
+// Name returns "auto/<tier>".
+func (r *Router) Name() string {
+	return AutoProvider + "/" + r.tier
+}
+
+// Complete routes the request and forwards it to the chosen model.
+func (r *Router) Complete(ctx context.Context, prompt string, opts CompletionOpts) (string, error) {
+	decision, provider, err := r.Route(opts.Task, prompt, opts)
+	if err != nil {
+		return "", err
+	}
+	timings.RecordRoute(decision.Provider, decision.Model, decision.Task, decision.Reason)
+	if OnRoute != nil {
+		OnRoute(decision)
+	}
+
+	opts.Model = ""
+	resp, err := provider.Complete(ctx, prompt, opts)
+	if err == nil {
+		m := routeModels[decision.Model]
+		cost := estimateRouteCost(m, decision.PromptTokens, tokens.CountModel(decision.Model, resp))
+		r.mu.Lock()
+		r.spent += cost
+		r.mu.Unlock()
+	}
+	return resp, err
+}
+
+// Route picks a model for task without calling it.
+func (r *Router) Route(task, prompt string, opts CompletionOpts) (RouteDecision, Provider, error) {
+	task = strings.ToLower(strings.TrimSpace(task))
+	// The model isn't chosen yet, so the prompt is counted with the default family.
+	promptTokens := tokens.Count(opts.System) + tokens.Count(prompt)
+	outTokens := 1024
+	if opts.MaxTokens > 0 {
+		outTokens = opts.MaxTokens / 4
+	}
+
+	spent := r.spentToday()
+	d := RouteDecision{Task: task, Tier: r.tier, PromptTokens: promptTokens, SpentUSD: spent, BudgetUSD: r.budget}
+	if d.Task == "" {
+		d.Task = "general"
+	}
+
+	tier := r.tier
+	var notes []string
+	remaining := r.budget - spent
+	if r.budget > 0 {
+		if remaining <= 0 {
+			return d, nil, fmt.Errorf("daily LLM budget of $%.2f exhausted ($%.4f spent today); raise llm.auto.daily_budget_usd or CORTEX_LLM_DAILY_BUDGET", r.budget, spent)
+		}
+		if remaining < r.budget*budgetLowShare && tier != TierEconomy {
+			tier = lowerTier(tier)
+			notes = append(notes, "budget low → "+tier)
+		}
+	}
+
+	// Walk the tier's candidates, then cheaper tiers if nothing fits.
+	for t := tierIndex(tier); t >= 0; t-- {
+		tierName := routerTiers[t]
+		for _, model := range tierCandidates(tierName, task) {
+			m := routeModels[model]
+			if promptTokens+outTokens > m.contextTokens {
+				notes = appendOnce(notes, "too large for "+model)
+				continue
+			}
+			est := estimateRouteCost(m, promptTokens, outTokens)
+			if r.budget > 0 && est > remaining {
+				notes = appendOnce(notes, "over budget: "+model)
+				continue
+			}
+			providerName, provider := r.providerFor(model, m)
+			if provider == nil {
+				notes = appendOnce(notes, "no key for "+model)
+				continue
+			}
+			if tierName != tier {
+				notes = append(notes, "fell back to "+tierName)
+			}
+			d.Provider, d.Model, d.EstCostUSD = providerName, model, est
+			if providerName == "google" {
+				d.Model = m.googleModel
+			}
+			d.Reason = tierName + "/" + d.Task
+			if len(notes) > 0 {
+				d.Reason += " (" + strings.Join(notes, "; ") + ")"
+			}
+			return d, provider, nil
+		}
+	}
+	if len(notes) == 0 {
+		notes = append(notes, "no candidates")
+	}
+	return d, nil, fmt.Errorf("--llm auto found no usable model for %s: %s (set OPENROUTER_API_KEY or GEMINI_API_KEY)", d.Task, strings.Join(notes, "; "))
+}
+
+// providerFor returns a provider serving model, preferring OpenRouter (whose
+// prices the budget is based on) and falling back to Google AI Studio.
+func (r *Router) providerFor(model string, m routeModel) (string, Provider) {
+	type option struct{ provider, model string }
+	options := []option{{"openrouter", model}}
+	if m.googleModel != "" {
+		options = append(options, option{"google", m.googleModel})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, o := range options {
+		key := o.provider + "|" + o.model
+		p, seen := r.providers[key]
+		if !seen {
+			p, _ = r.newProvider(Config{Provider: o.provider, Model: o.model})
+			r.providers[key] = p
+		}
+		if p != nil {
+			return o.provider, p
+		}
+	}
+	return "", nil
+}
+
+func (r *Router) spentToday() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.budget <= 0 {
+		return r.spent
+	}
+	if !r.baseLoaded {
+		r.baseLoaded = true
+		if SpendToday != nil {
+			r.baseSpent = SpendToday()
+		}
+	}
+	return r.baseSpent + r.spent
+}
+
+func tierCandidates(tier, task string) []string {
+	if c, ok := routeTable[tier][task]; ok {
+		return c
+	}
+	return routeTable[tier][""]
+}
+
+func tierIndex(tier string) int {
+	for i, t := range routerTiers {
+		if t == tier {
+			return i
+		}
+	}
+	return 0
+}
+
+func lowerTier(tier string) string {
+	if i := tierIndex(tier); i > 0 {
+		return routerTiers[i-1]
+	}
+	return TierEconomy
+}
+
+func appendOnce(notes []string, note string) []string {
+	for _, n := range notes {
+		if n == note {
+			return notes
+		}
+	}
+	return append(notes, note)
+}
+
+func estimateRouteCost(m routeModel, tokensIn, tokensOut int) float64 {
+	return float64(tokensIn)*m.inPerM/1_000_000 + float64(tokensOut)*m.outPerM/1_000_000
+}
+
+// StartOfToday returns local midnight, the start of the router's budget day.
+func StartOfToday() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
@@ -0,0 +1,150 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/timings"
+)
+
+type stubProvider struct {
+	name  string
+	calls int
+}
+
+func (s *stubProvider) Complete(ctx context.Context, prompt string, opts CompletionOpts) (string, error) {
+	s.calls++
+	return "ok", nil
+}
+
+func (s *stubProvider) Name() string { return s.name }
+
+// newTestRouter returns a router whose providers are stubs; keys lists the
+// providers that have an API key.
+func newTestRouter(t *testing.T, cfg RouterConfig, keys ...string) *Router {
+	t.Helper()
+	r, err := NewRouter(cfg)
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	r.newProvider = func(c Config) (Provider, error) {
+		for _, k := range keys {
+			if k == c.Provider {
+				return &stubProvider{name: c.Provider + "/" + c.Model}, nil
+			}
+		}
+		return nil, fmt.Errorf("no key for %s", c.Provider)
+	}
+	return r
+}
+
+func TestRouter_PicksModelPerTaskAndTier(t *testing.T) {
+	r := newTestRouter(t, RouterConfig{}, "openrouter", "google")
+	tests := []struct{ task, want string }{
+		{TaskClassify, "deepseek/deepseek-v3.2"},
+		{TaskEnrich, "x-ai/grok-4.1-fast"},
+		{TaskSummarize, "google/gemini-2.5-flash"},
+		{"", "google/gemini-2.5-flash"},
+	}
+	for _, tt := range tests {
+		d, _, err := r.Route(tt.task, "short prompt", CompletionOpts{})
+		if err != nil {
+			t.Fatalf("Route(%q): %v", tt.task, err)
+		}
+		if d.Model != tt.want || d.Provider != "openrouter" || d.Tier != TierBalanced {
+			t.Fatalf("Route(%q) = %+v, want openrouter %s", tt.task, d, tt.want)
+		}
+	}
+
+	q := newTestRouter(t, RouterConfig{Tier: TierQuality}, "openrouter")
+	d, _, err := q.Route(TaskResolve, "short prompt", CompletionOpts{})
+	if err != nil || d.Model != "google/gemini-2.5-pro" {
+		t.Fatalf("quality resolve = %+v, %v", d, err)
+	}
+
+	if _, err := NewRouter(RouterConfig{Tier: "platinum"}); err == nil {
+		t.Fatal("expected error for unknown tier")
+	}
+}
+
+func TestRouter_FallsBackToGoogleDirectWithoutOpenRouterKey(t *testing.T) {
+	r := newTestRouter(t, RouterConfig{}, "google")
+	d, p, err := r.Route(TaskClassify, "short prompt", CompletionOpts{})
+	if err != nil {
+		t.Fatalf("Route: %v", err)
+	}
+	if d.Provider != "google" || d.Model != "gemini-2.5-flash" || p.Name() != "google/gemini-2.5-flash" {
+		t.Fatalf("decision = %+v (provider %s)", d, p.Name())
+	}
+	if !strings.Contains(d.Reason, "no key for deepseek/deepseek-v3.2") {
+		t.Fatalf("reason should explain the skip: %q", d.Reason)
+	}
+
+	none := newTestRouter(t, RouterConfig{})
+	if _, _, err := none.Route(TaskClassify, "x", CompletionOpts{}); err == nil {
+		t.Fatal("expected error with no provider keys")
+	}
+}
+
+func TestRouter_SkipsModelsThatCannotFitThePrompt(t *testing.T) {
+	r := newTestRouter(t, RouterConfig{}, "openrouter")
+	huge := strings.Repeat("x ", 200_000) // ~200k tokens > deepseek's window
+	d, _, err := r.Route(TaskClassify, huge, CompletionOpts{})
+	if err != nil {
+		t.Fatalf("Route: %v", err)
+	}
+	if d.Model != "google/gemini-2.5-flash" || !strings.Contains(d.Reason, "too large") {
+		t.Fatalf("decision = %+v", d)
+	}
+}
+
+func TestRouter_DailyBudget(t *testing.T) {
+	oldSpend := SpendToday
+	t.Cleanup(func() { SpendToday = oldSpend })
+
+	// 85% of the budget spent → quality drops to balanced.
+	SpendToday = func() float64 { return 0.85 }
+	r := newTestRouter(t, RouterConfig{Tier: TierQuality, DailyBudgetUSD: 1}, "openrouter")
+	d, _, err := r.Route(TaskEnrich, "short prompt", CompletionOpts{})
+	if err != nil {
+		t.Fatalf("Route: %v", err)
+	}
+	if d.Model != "x-ai/grok-4.1-fast" || !strings.Contains(d.Reason, "budget low") || d.SpentUSD != 0.85 {
+		t.Fatalf("decision = %+v", d)
+	}
+
+	// Budget exhausted → no call at all.
+	SpendToday = func() float64 { return 1.5 }
+	r = newTestRouter(t, RouterConfig{DailyBudgetUSD: 1}, "openrouter")
+	if _, _, err := r.Route(TaskEnrich, "short prompt", CompletionOpts{}); err == nil || !strings.Contains(err.Error(), "budget") {
+		t.Fatalf("expected budget error, got %v", err)
+	}
+}
+
+func TestRouter_CompleteRecordsRoute(t *testing.T) {
+	timings.Enable()
+	t.Cleanup(timings.Disable)
+	var decisions []RouteDecision
+	oldOnRoute := OnRoute
+	OnRoute = func(d RouteDecision) { decisions = append(decisions, d) }
+	t.Cleanup(func() { OnRoute = oldOnRoute })
+
+	r := newTestRouter(t, RouterConfig{Tier: TierEconomy}, "openrouter")
+	for i := 0; i < 2; i++ {
+		if _, err := r.Complete(context.Background(), "classify these", CompletionOpts{Task: TaskClassify, Model: "ignored"}); err != nil {
+			t.Fatalf("Complete: %v", err)
+		}
+	}
+	if len(decisions) != 2 || r.Name() != "auto/economy" {
+		t.Fatalf("decisions = %+v", decisions)
+	}
+	models := timings.Snapshot().Models
+	if len(models) != 1 || len(models[0].Routes) != 1 || models[0].Routes[0].Count != 2 || models[0].Routes[0].Task != TaskClassify {
+		t.Fatalf("recorded routes = %+v", models)
+	}
+	if r.spent <= 0 {
+		t.Fatal("router should track its own estimated spend")
+	}
+}
@@ -0,0 +1,268 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// secretDetector finds one kind of sensitive value. group selects the
+// submatch to mask (0 = the whole match), so "password: hunter22" keeps its
+// key and loses only the value.
+type secretDetector struct {
+	kind  string
+	re    *regexp.Regexp
+	group int
+	valid func(string) bool
+}
+
+// secretDetectors run in order; earlier, more specific patterns claim a
+// value before the generic ones see it.
+var secretDetectors = []secretDetector{
+	{kind: "private_key", re: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{kind: "api_key", re: regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`)},
+	{kind: "api_key", re: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{kind: "aws_key", re: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{kind: "github_token", re: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{kind: "slack_token", re: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{kind: "jwt", re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{kind: "token", re: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9._~+/-]{20,}=*)`), group: 1},
+	{kind: "credential", re: regexp.MustCompile(`(?i)\b(?:password|passwd|pwd|secret|client[_-]?secret|token|api[_-]?key|access[_-]?key)\b\s*[:=]\s*["']?([^\s"'<>,;]{6,})`), group: 1},
+	{kind: "email", re: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+	{kind: "ssn", re: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+	{kind: "card", re: regexp.MustCompile(`\b\d{4}[ -]?\d{4}[ -]?\d{4}[ -]?\d{1,7}\b`), valid: luhnValid},
+}
+
+const placeholderPrefix = "[REDACTED_"
+
+// ScrubReport summarizes what a Scrubber masked during a run.
+type ScrubReport struct {
+	Prompts  int            `json:"prompts"`  // prompts checked
+	Scrubbed int            `json:"scrubbed"` // prompts that had something masked
+	ByKind   map[string]int `json:"by_kind"`  // distinct values masked, by kind
+}
+
+// Masked returns the number of distinct values masked.
+func (r ScrubReport) Masked() int {
+	n := 0
+	for _, c := range r.ByKind {
+		n += c
+	}
+	return n
+}
+
+// String renders the report as "3 values in 2 of 10 prompts (api_key 2, email 1)".
+func (r ScrubReport) String() string {
+	kinds := make([]string, 0, len(r.ByKind))
+	for k := range r.ByKind {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	parts := make([]string, 0, len(kinds))
+	for _, k := range kinds {
+		parts = append(parts, fmt.Sprintf("%s %d", k, r.ByKind[k]))
+	}
+	values, prompts := "values", "prompts"
+	if r.Masked() == 1 {
+		values = "value"
+	}
+	if r.Prompts == 1 {
+		prompts = "prompt"
+	}
+	s := fmt.Sprintf("%d %s in %d of %d %s", r.Masked(), values, r.Scrubbed, r.Prompts, prompts)
+	if len(parts) > 0 {
+		s += " (" + strings.Join(parts, ", ") + ")"
+	}
+	return s
+}
+
+// Scrubber masks credentials and personal data in text bound for a remote
+// model and puts them back in the model's reply. Placeholders are stable
+// for the Scrubber's lifetime: the same value always maps to the same
+// placeholder, so repeated prompts stay cacheable and the model can relate
+// mentions across prompts. A Scrubber is safe for concurrent use.
+type Scrubber struct {
+	mu       sync.Mutex
+	byValue  map[string]string // original → placeholder
+	byHolder map[string]string // placeholder → original
+	report   ScrubReport
+}
+
+// NewScrubber returns an empty Scrubber.
+func NewScrubber() *Scrubber {
+	return &Scrubber{
+		byValue:  map[string]string{},
+		byHolder: map[string]string{},
+		report:   ScrubReport{ByKind: map[string]int{}},
+	}
+}
+
+// Scrub returns text with every detected secret replaced by its placeholder.
+func (s *Scrubber) Scrub(text string) string {
+	return s.ScrubParts(text)[0]
+}
+
+// ScrubParts scrubs the parts of one prompt (system prompt, messages) and
+// counts them as a single prompt in the report.
+func (s *Scrubber) ScrubParts(parts ...string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report.Prompts++
+	out := make([]string, len(parts))
+	masked := false
+	for i, p := range parts {
+		var m bool
+		out[i], m = s.scrubLocked(p)
+		masked = masked || m
+	}
+	if masked {
+		s.report.Scrubbed++
+	}
+	return out
+}
+
+func (s *Scrubber) scrubLocked(text string) (string, bool) {
+	masked := false
+	for _, d := range secretDetectors {
+		matches := d.re.FindAllStringSubmatchIndex(text, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			start, end := m[2*d.group], m[2*d.group+1]
+			if start < 0 {
+				continue
+			}
+			value := text[start:end]
+			if strings.HasPrefix(value, placeholderPrefix) || (d.valid != nil && !d.valid(value)) {
+				continue
+			}
+			b.WriteString(text[last:start])
+			b.WriteString(s.placeholderLocked(d.kind, value))
+			last = end
+			masked = true
+		}
+		b.WriteString(text[last:])
+		text = b.String()
+	}
+	return text, masked
+}
+
+// Restore replaces placeholders in text with the values they stand for.
+func (s *Scrubber) Restore(text string) string {
+	return s.restore(text, false)
+}
+
+// RestoreJSON is Restore for JSON replies: values are escaped as JSON string
+// content, so a restored private key's newlines don't break parsing.
+func (s *Scrubber) RestoreJSON(text string) string {
+	return s.restore(text, true)
+}
+
+func (s *Scrubber) restore(text string, escape bool) string {
+	if !strings.Contains(text, placeholderPrefix) {
+		return text
+	}
+	s.mu.Lock()
+	pairs := make([]string, 0, 2*len(s.byHolder))
+	for holder, value := range s.byHolder {
+		if escape {
+			quoted, _ := json.Marshal(value)
+			value = string(quoted[1 : len(quoted)-1])
+		}
+		pairs = append(pairs, holder, value)
+	}
+	s.mu.Unlock()
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// Report returns a copy of what the Scrubber has masked so far.
+func (s *Scrubber) Report() ScrubReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := s.report
+	out.ByKind = make(map[string]int, len(s.report.ByKind))
+	for k, v := range s.report.ByKind {
+		out.ByKind[k] = v
+	}
+	return out
+}
+
+func (s *Scrubber) placeholderLocked(kind, value string) string {
+	if holder, ok := s.byValue[value]; ok {
+		return holder
+	}
+	s.report.ByKind[kind]++
+	holder := fmt.Sprintf("%s%s_%d]", placeholderPrefix, strings.ToUpper(kind), s.report.ByKind[kind])
+	s.byValue[value] = holder
+	s.byHolder[holder] = value
+	return holder
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum, which
+// separates card numbers from other long digit runs.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+// Scrubbed is a Provider that masks secrets in prompts before they reach
+// the wrapped provider and restores them in its responses.
+type Scrubbed struct {
+	inner    Provider
+	scrubber *Scrubber
+}
+
+// Scrub wraps p with a fresh Scrubber.
+func Scrub(p Provider) *Scrubbed {
+	return &Scrubbed{inner: p, scrubber: NewScrubber()}
+}
+
+// Name returns the wrapped provider's name.
+func (s *Scrubbed) Name() string { return s.inner.Name() }
+
+// Unwrap returns the wrapped provider.
+func (s *Scrubbed) Unwrap() Provider { return s.inner }
+
+// Report returns what has been masked so far.
+func (s *Scrubbed) Report() ScrubReport { return s.scrubber.Report() }
+
+// Complete scrubs the prompt and system prompt, sends them, and restores
+// placeholders in the reply.
+func (s *Scrubbed) Complete(ctx context.Context, prompt string, opts CompletionOpts) (string, error) {
+	parts := s.scrubber.ScrubParts(prompt, opts.System)
+	prompt, opts.System = parts[0], parts[1]
+	out, err := s.inner.Complete(ctx, prompt, opts)
+	if opts.Format == "json" || looksLikeJSON(out) {
+		return s.scrubber.RestoreJSON(out), err
+	}
+	return s.scrubber.Restore(out), err
+}
+
+// looksLikeJSON reports whether a reply is (possibly fenced) JSON; most
+// extraction prompts ask for JSON without setting Format.
+func looksLikeJSON(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") || strings.HasPrefix(s, "```")
+}
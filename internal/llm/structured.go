@@ -0,0 +1,164 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/timings"
+)
+
+// Structured replies. Models asked for JSON sometimes wrap it in a markdown
+// fence, add a sentence around it, or leave a trailing comma, and one such
+// reply used to fail a whole enrich or classify batch. DecodeJSON repairs
+// those slips locally; CompleteStructured re-asks the model once when a
+// reply still can't be used.
+
+// repromptSuffix is appended to the original prompt when a reply is re-asked.
+const repromptSuffix = "\n\nYour previous reply could not be used: %s\nReply again with only the corrected JSON: no markdown fences, no commentary."
+
+// RepairJSON returns raw with common formatting slips fixed: a markdown code
+// fence or prose around the JSON value, and trailing commas before a closing
+// bracket. Valid JSON is returned unchanged (trimmed).
+func RepairJSON(raw string) string {
+	s := strings.TrimSpace(strings.TrimPrefix(raw, "\ufeff"))
+	if json.Valid([]byte(s)) {
+		return s
+	}
+	s = stripCodeFence(s)
+	if start := strings.IndexAny(s, "{["); start >= 0 {
+		s = s[start:]
+		if end := jsonValueEnd(s); end > 0 {
+			s = s[:end]
+		}
+	}
+	return strings.TrimSpace(stripTrailingCommas(s))
+}
+
+// DecodeJSON unmarshals a model's JSON reply into v after RepairJSON.
+func DecodeJSON(raw string, v any) error {
+	return json.Unmarshal([]byte(RepairJSON(raw)), v)
+}
+
+// CompleteStructured asks p for a structured reply and hands it to parse,
+// which decodes and validates it (normally with DecodeJSON). If parse
+// rejects the reply, the prompt is sent once more with the error appended
+// so the model can correct itself, and the second reply's parse result is
+// returned. Replies that needed local repair and replies that had to be
+// re-asked are counted against the model in timings, and so in telemetry.
+func CompleteStructured(ctx context.Context, p Provider, prompt string, opts CompletionOpts, parse func(reply string) error) error {
+	reply, err := p.Complete(ctx, prompt, opts)
+	if err != nil {
+		return err
+	}
+	parseErr := parse(reply)
+	if parseErr == nil {
+		if !json.Valid([]byte(strings.TrimSpace(reply))) {
+			recordStructured(p, timings.RecordRepair)
+		}
+		return nil
+	}
+
+	recordStructured(p, timings.RecordReprompt)
+	reason, _, _ := strings.Cut(parseErr.Error(), "\n")
+	reply, err = p.Complete(ctx, prompt+fmt.Sprintf(repromptSuffix, reason), opts)
+	if err != nil {
+		return fmt.Errorf("re-asking after invalid reply (%s): %w", reason, err)
+	}
+	return parse(reply)
+}
+
+func recordStructured(p Provider, record func(cat timings.Category, provider, model string)) {
+	provider, model, _ := strings.Cut(p.Name(), "/")
+	record(timings.LLM, provider, model)
+}
+
+// stripCodeFence returns the body of the first ``` fence in s, or s when it
+// has none. An unterminated fence (a reply cut off mid-way) keeps the rest.
+func stripCodeFence(s string) string {
+	start := strings.Index(s, "```")
+	if start < 0 {
+		return s
+	}
+	body := s[start+3:]
+	if nl := strings.IndexByte(body, '\n'); nl >= 0 {
+		body = body[nl+1:] // drop the language tag line
+	} else {
+		body = strings.TrimPrefix(body, "json")
+	}
+	if end := strings.Index(body, "```"); end >= 0 {
+		body = body[:end]
+	}
+	return strings.TrimSpace(body)
+}
+
+// jsonValueEnd returns the length of the object or array s starts with, or
+// 0 when its brackets never balance.
+func jsonValueEnd(s string) int {
+	depth := 0
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// stripTrailingCommas drops commas that are followed only by whitespace and
+// a closing bracket, leaving string contents alone.
+func stripTrailingCommas(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			b.WriteByte(c)
+			continue
+		}
+		if c == ',' {
+			j := i + 1
+			for j < len(s) && strings.IndexByte(" \t\r\n", s[j]) >= 0 {
+				j++
+			}
+			if j < len(s) && (s[j] == '}' || s[j] == ']') {
+				continue
+			}
+		}
+		if c == '"' {
+			inString = true
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
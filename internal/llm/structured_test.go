@@ -0,0 +1,121 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/timings"
+)
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name, raw, want string
+	}{
+		{"valid", `  {"a": 1}  `, `{"a": 1}`},
+		{"fenced", "```json\n{\"a\": 1}\n```", `{"a": 1}`},
+		{"prose and fence", "Here are the facts:\n```\n[1, 2]\n```\nLet me know!", `[1, 2]`},
+		{"unterminated fence", "```json\n{\"a\": [1, 2]}", `{"a": [1, 2]}`},
+		{"prose around", `Sure! {"a": "b"} Hope this helps.`, `{"a": "b"}`},
+		{"trailing commas", "{\"a\": [1, 2,],\n \"b\": {\"c\": 3,\n},\n}", "{\"a\": [1, 2],\n \"b\": {\"c\": 3\n}\n}"},
+		{"commas and brackets in strings", `{"a": "x,}", "b": "[y,]",}`, `{"a": "x,}", "b": "[y,]"}`},
+		{"escaped quote", `{"a": "say \"hi\",}",}`, `{"a": "say \"hi\",}"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RepairJSON(tt.raw)
+			if got != tt.want {
+				t.Fatalf("RepairJSON(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Fatalf("repaired JSON is invalid: %q", got)
+			}
+		})
+	}
+
+	var v map[string]any
+	if err := DecodeJSON("not json at all", &v); err == nil {
+		t.Fatal("expected an error for a reply with no JSON")
+	}
+}
+
+// scriptedProvider returns its replies in order and keeps the prompts.
+type scriptedProvider struct {
+	name    string
+	replies []string
+	prompts []string
+}
+
+func (p *scriptedProvider) Complete(_ context.Context, prompt string, _ CompletionOpts) (string, error) {
+	p.prompts = append(p.prompts, prompt)
+	if len(p.prompts) > len(p.replies) {
+		return "", fmt.Errorf("unexpected call %d", len(p.prompts))
+	}
+	return p.replies[len(p.prompts)-1], nil
+}
+
+func (p *scriptedProvider) Name() string { return p.name }
+
+func TestCompleteStructured(t *testing.T) {
+	timings.Enable()
+	t.Cleanup(timings.Disable)
+
+	parse := func(out *[]int) func(string) error {
+		return func(reply string) error {
+			var resp struct {
+				IDs []int `json:"ids"`
+			}
+			if err := DecodeJSON(reply, &resp); err != nil {
+				return fmt.Errorf("invalid JSON from LLM: %w\nraw: %s", err, reply)
+			}
+			if len(resp.IDs) == 0 {
+				return fmt.Errorf("reply has no ids")
+			}
+			*out = resp.IDs
+			return nil
+		}
+	}
+
+	// A fenced reply with a trailing comma is repaired without a second call.
+	var ids []int
+	p := &scriptedProvider{name: "openrouter/a/model", replies: []string{"```json\n{\"ids\": [1, 2,]}\n```"}}
+	if err := CompleteStructured(context.Background(), p, "prompt", CompletionOpts{}, parse(&ids)); err != nil {
+		t.Fatalf("CompleteStructured: %v", err)
+	}
+	if len(p.prompts) != 1 || len(ids) != 2 {
+		t.Fatalf("calls = %d, ids = %v", len(p.prompts), ids)
+	}
+
+	// A reply that fails validation is re-asked once, quoting the error.
+	p = &scriptedProvider{name: "openrouter/a/model", replies: []string{`{"ids": []}`, `{"ids": [7]}`}}
+	if err := CompleteStructured(context.Background(), p, "prompt", CompletionOpts{}, parse(&ids)); err != nil {
+		t.Fatalf("CompleteStructured after re-ask: %v", err)
+	}
+	if len(p.prompts) != 2 || !strings.HasPrefix(p.prompts[1], "prompt\n\n") || !strings.Contains(p.prompts[1], "reply has no ids") {
+		t.Fatalf("re-ask prompts = %q", p.prompts)
+	}
+	if len(ids) != 1 || ids[0] != 7 {
+		t.Fatalf("ids = %v", ids)
+	}
+
+	// Only one re-ask: a second bad reply is an error.
+	p = &scriptedProvider{name: "openrouter/a/model", replies: []string{"nope", "still nope"}}
+	if err := CompleteStructured(context.Background(), p, "prompt", CompletionOpts{}, parse(&ids)); err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+		t.Fatalf("err = %v, want invalid JSON", err)
+	}
+	if len(p.prompts) != 2 {
+		t.Fatalf("calls = %d, want 2", len(p.prompts))
+	}
+
+	var usage timings.ModelUsage
+	for _, m := range timings.Snapshot().Models {
+		if m.Provider == "openrouter" && m.Model == "a/model" {
+			usage = m
+		}
+	}
+	if usage.Repairs != 1 || usage.Reprompts != 2 {
+		t.Fatalf("usage = %+v, want 1 repair and 2 reprompts", usage)
+	}
+}
@@ -0,0 +1,385 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/extract"
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/observe"
+	"github.com/hurttlocker/cortex/internal/store"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Conflict tools mirror `cortex conflicts` and its --keep/--drop and
+// --resolve modes. Conflicts touching a no-mcp project are never listed or
+// resolved, and resolving refuses to run when the server is read-only.
+
+const (
+	defaultMCPConflictLimit = 20
+	maxMCPConflictLimit     = 100
+)
+
+// strategyLLM labels resolutions decided by extract.ResolveConflictsLLM.
+// observe.ParseStrategy doesn't accept it: the CLI routes --resolve llm
+// separately too.
+const strategyLLM observe.Strategy = "llm"
+
+// newResolveProvider builds the LLM for cortex_resolve strategy=llm.
+// Replaced in tests.
+var newResolveProvider = func(model string) (llm.Provider, error) {
+	cfg, err := llm.ParseLLMFlag(model)
+	if err != nil {
+		return nil, err
+	}
+	p, err := llm.NewProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return llm.Scrub(p), nil
+}
+
+func registerConflictsTool(s *server.MCPServer, st store.Store, engine *observe.Engine) {
+	tool := mcp.NewTool("cortex_conflicts",
+		mcp.WithDescription("List contradictions in memory: pairs of active facts with the same subject and predicate but different values, with a severity (critical, high, medium, low) and the reasons for it. Use before cortex_resolve to decide which fact to keep."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum conflicts to return (default %d, max %d)", defaultMCPConflictLimit, maxMCPConflictLimit)),
+		),
+		mcp.WithString("min_severity",
+			mcp.Description("Only conflicts at or above this severity"),
+			mcp.Enum(observe.SeverityCritical, observe.SeverityHigh, observe.SeverityMedium, observe.SeverityLow),
+		),
+		mcp.WithString("agent_id",
+			mcp.Description("Only conflicts where at least one fact belongs to this agent"),
+		),
+		mcp.WithBoolean("include_superseded",
+			mcp.Description("Also compare facts that are already superseded (default false)"),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		dbMu.Lock()
+		defer dbMu.Unlock()
+
+		minSeverity := ""
+		if v, err := req.RequireString("min_severity"); err == nil && strings.TrimSpace(v) != "" {
+			minSeverity = strings.ToLower(strings.TrimSpace(v))
+			if observe.SeverityRank(minSeverity) < 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid min_severity %q (valid: critical, high, medium, low)", v)), nil
+			}
+		}
+		agentID := ""
+		if a, err := req.RequireString("agent_id"); err == nil {
+			agentID = strings.TrimSpace(a)
+		}
+		includeSuperseded, _ := req.RequireBool("include_superseded")
+
+		conflicts, err := mcpConflicts(ctx, st, engine, req, includeSuperseded)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		kept := []observe.Conflict{}
+		for _, c := range conflicts {
+			if agentID != "" && c.Fact1.AgentID != agentID && c.Fact2.AgentID != agentID {
+				continue
+			}
+			if minSeverity != "" && observe.SeverityRank(c.Severity) < observe.SeverityRank(minSeverity) {
+				continue
+			}
+			kept = append(kept, c)
+		}
+
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"count":     len(kept),
+			"conflicts": kept,
+		}, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
+
+func registerResolveTool(s *server.MCPServer, st store.Store, engine *observe.Engine, readOnly bool) {
+	tool := mcp.NewTool("cortex_resolve",
+		mcp.WithDescription("Resolve contradictions found by cortex_conflicts. Either keep one fact and supersede the other (keep_fact_id + drop_fact_id), or resolve the current conflicts with a strategy: last-write-wins, highest-confidence, newest, manual (flag only), or llm (a model picks a winner or merges; low-confidence calls are flagged). Losing facts are superseded, never deleted. Returns a batch with one result per conflict; use dry_run to preview."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithNumber("keep_fact_id",
+			mcp.Description("Fact to keep (with drop_fact_id)"),
+		),
+		mcp.WithNumber("drop_fact_id",
+			mcp.Description("Fact to supersede by keep_fact_id"),
+		),
+		mcp.WithString("strategy",
+			mcp.Description("Resolve every current conflict (up to limit) with this strategy"),
+			mcp.Enum(string(observe.StrategyLastWrite), string(observe.StrategyHighestConfidence), string(observe.StrategyNewest), string(observe.StrategyManual), string(strategyLLM)),
+		),
+		mcp.WithString("model",
+			mcp.Description(fmt.Sprintf("LLM for strategy=llm as provider/model (default %s)", extract.DefaultResolveModel)),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum conflicts to resolve with a strategy (default %d, max %d)", defaultMCPConflictLimit, maxMCPConflictLimit)),
+		),
+		mcp.WithBoolean("include_superseded",
+			mcp.Description("Also resolve conflicts involving already-superseded facts (default false)"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Compute resolutions without applying them (default false)"),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		dryRun, _ := req.RequireBool("dry_run")
+		if !dryRun {
+			if res := readOnlyGuard(readOnly, "cortex_resolve"); res != nil {
+				return res, nil
+			}
+		}
+		dbMu.Lock()
+		defer dbMu.Unlock()
+
+		sqlStore, ok := st.(*store.SQLiteStore)
+		if !ok {
+			return mcp.NewToolResultError("resolving conflicts requires SQLiteStore"), nil
+		}
+		strategyArg, _ := req.RequireString("strategy")
+		strategyArg = strings.TrimSpace(strategyArg)
+		byID := hasMCPArg(req, "keep_fact_id") || hasMCPArg(req, "drop_fact_id")
+
+		var batch *observe.ResolveBatch
+		var err error
+		switch {
+		case byID && strategyArg != "":
+			return mcp.NewToolResultError("pass keep_fact_id/drop_fact_id or strategy, not both"), nil
+		case byID:
+			batch, err = resolveMCPByID(ctx, sqlStore, engine, req, dryRun)
+		case strings.EqualFold(strategyArg, string(strategyLLM)):
+			batch, err = resolveMCPWithLLM(ctx, sqlStore, engine, req, dryRun)
+		case strategyArg != "":
+			var strategy observe.Strategy
+			if strategy, err = observe.ParseStrategy(strategyArg); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			includeSuperseded, _ := req.RequireBool("include_superseded")
+			var conflicts []observe.Conflict
+			if conflicts, err = mcpConflicts(ctx, st, engine, req, includeSuperseded); err == nil {
+				batch, err = observe.NewResolver(st, engine).ResolveConflicts(ctx, conflicts, strategy, dryRun)
+			}
+		default:
+			return mcp.NewToolResultError("pass keep_fact_id and drop_fact_id, or a strategy"), nil
+		}
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"dry_run": dryRun,
+			"batch":   batch,
+		}, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
+
+// mcpConflicts detects up to limit conflicts, leaving out any that touch a
+// no-mcp project.
+func mcpConflicts(ctx context.Context, st store.Store, engine *observe.Engine, req mcp.CallToolRequest, includeSuperseded bool) ([]observe.Conflict, error) {
+	limit := defaultMCPConflictLimit
+	if v, err := req.RequireFloat("limit"); err == nil && v > 0 {
+		limit = int(v)
+		if limit > maxMCPConflictLimit {
+			limit = maxMCPConflictLimit
+		}
+	}
+	conflicts, err := engine.GetConflictsLimitWithSuperseded(ctx, limit, includeSuperseded)
+	if err != nil {
+		return nil, fmt.Errorf("detecting conflicts: %w", err)
+	}
+	return conflictsRestricting(ctx, st, conflicts, store.PrivacyNoMCP)
+}
+
+// conflictsRestricting drops conflicts with a fact in a project whose
+// privacy zone applies restriction r.
+func conflictsRestricting(ctx context.Context, st store.Store, conflicts []observe.Conflict, r string) ([]observe.Conflict, error) {
+	sqlStore, ok := st.(*store.SQLiteStore)
+	if !ok || len(store.ProjectsRestricting(r)) == 0 {
+		return conflicts, nil
+	}
+	facts := make([]*store.Fact, 0, 2*len(conflicts))
+	for i := range conflicts {
+		facts = append(facts, &conflicts[i].Fact1, &conflicts[i].Fact2)
+	}
+	visible, _, err := sqlStore.FilterFactsByPrivacy(ctx, facts, r)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make(map[int64]bool, len(visible))
+	for _, f := range visible {
+		allowed[f.ID] = true
+	}
+	kept := make([]observe.Conflict, 0, len(conflicts))
+	for _, c := range conflicts {
+		if allowed[c.Fact1.ID] && allowed[c.Fact2.ID] {
+			kept = append(kept, c)
+		}
+	}
+	return kept, nil
+}
+
+// resolveMCPByID keeps keep_fact_id and supersedes drop_fact_id, as
+// `cortex conflicts --keep X --drop Y` does.
+func resolveMCPByID(ctx context.Context, sqlStore *store.SQLiteStore, engine *observe.Engine, req mcp.CallToolRequest, dryRun bool) (*observe.ResolveBatch, error) {
+	keepID, err := requirePositiveID(req, "keep_fact_id")
+	if err != nil {
+		return nil, err
+	}
+	dropID, err := requirePositiveID(req, "drop_fact_id")
+	if err != nil {
+		return nil, err
+	}
+	if keepID == dropID {
+		return nil, fmt.Errorf("keep_fact_id and drop_fact_id must differ")
+	}
+	for _, id := range []int64{keepID, dropID} {
+		if res := requireVisibleFact(ctx, sqlStore, id); res != nil {
+			return nil, fmt.Errorf("fact %d not found", id)
+		}
+	}
+
+	var res *observe.Resolution
+	if dryRun {
+		res = &observe.Resolution{
+			Strategy: observe.StrategyManual,
+			Winner:   "manual",
+			WinnerID: keepID,
+			LoserID:  dropID,
+			Reason:   fmt.Sprintf("Would keep fact %d and supersede fact %d", keepID, dropID),
+		}
+	} else if res, err = observe.NewResolver(sqlStore, engine).ResolveByID(ctx, keepID, dropID); err != nil {
+		return nil, err
+	}
+	return &observe.ResolveBatch{Total: 1, Resolved: 1, Results: []observe.Resolution{*res}}, nil
+}
+
+// resolveMCPWithLLM resolves current conflicts with an LLM, as `cortex
+// conflicts --resolve llm` does: confident picks supersede the loser,
+// merges replace both facts with the merged one, and the rest are skipped
+// for human review. Conflicts in local-llm-only projects are left alone.
+func resolveMCPWithLLM(ctx context.Context, sqlStore *store.SQLiteStore, engine *observe.Engine, req mcp.CallToolRequest, dryRun bool) (*observe.ResolveBatch, error) {
+	model := extract.DefaultResolveModel
+	if m, err := req.RequireString("model"); err == nil && strings.TrimSpace(m) != "" {
+		model = strings.TrimSpace(m)
+	}
+	includeSuperseded, _ := req.RequireBool("include_superseded")
+	conflicts, err := mcpConflicts(ctx, sqlStore, engine, req, includeSuperseded)
+	if err != nil {
+		return nil, err
+	}
+	if conflicts, err = conflictsRestricting(ctx, sqlStore, conflicts, store.PrivacyLocalLLMOnly); err != nil {
+		return nil, err
+	}
+	batch := &observe.ResolveBatch{Total: len(conflicts), Results: []observe.Resolution{}}
+	if len(conflicts) == 0 {
+		return batch, nil
+	}
+	provider, err := newResolveProvider(model)
+	if err != nil {
+		return nil, fmt.Errorf("creating LLM provider: %w", err)
+	}
+
+	pairs := make([]extract.ConflictPair, len(conflicts))
+	for i, c := range conflicts {
+		pairs[i] = extract.ConflictPair{Index: i, Fact1: conflictFact(c.Fact1), Fact2: conflictFact(c.Fact2)}
+	}
+	opts := extract.DefaultResolveOpts()
+	opts.DryRun = dryRun
+	result, err := extract.ResolveConflictsLLM(ctx, provider, pairs, opts)
+	if err != nil {
+		return nil, fmt.Errorf("LLM resolution failed: %w", err)
+	}
+	batch.Errors = result.Errors
+
+	for _, r := range result.Resolutions {
+		if r.PairIndex < 0 || r.PairIndex >= len(conflicts) {
+			continue
+		}
+		c := conflicts[r.PairIndex]
+		res := observe.Resolution{Conflict: c, Strategy: strategyLLM, WinnerID: r.WinnerID, LoserID: r.LoserID, Reason: r.Reason}
+		switch r.Action {
+		case "supersede":
+			res.Winner = "fact1"
+			if r.WinnerID == c.Fact2.ID {
+				res.Winner = "fact2"
+			}
+			if !dryRun {
+				if err := sqlStore.SupersedeFact(ctx, r.LoserID, r.WinnerID, r.Reason); err != nil {
+					batch.Errors++
+					res.Reason += fmt.Sprintf(" (apply error: %v)", err)
+				} else {
+					_ = sqlStore.ReinforceFact(ctx, r.WinnerID)
+					res.Applied = true
+				}
+			}
+			batch.Resolved++
+		case "merge":
+			res.Winner = "merged"
+			if r.MergedFact == nil {
+				continue
+			}
+			res.Reason = fmt.Sprintf("Merged into %s %s %s: %s", r.MergedFact.Subject, r.MergedFact.Predicate, r.MergedFact.Object, r.Reason)
+			if !dryRun {
+				if err := applyMergedResolution(ctx, sqlStore, c, r); err != nil {
+					batch.Errors++
+					res.Reason += fmt.Sprintf(" (apply error: %v)", err)
+				} else {
+					res.Applied = true
+				}
+			}
+			batch.Resolved++
+		default:
+			res.Winner = "manual"
+			batch.Skipped++
+		}
+		batch.Results = append(batch.Results, res)
+	}
+	return batch, nil
+}
+
+// applyMergedResolution adds the LLM's merged fact to the first fact's
+// memory and supersedes both originals with it.
+func applyMergedResolution(ctx context.Context, sqlStore *store.SQLiteStore, c observe.Conflict, r extract.ConflictResolution) error {
+	newID, err := sqlStore.AddFact(ctx, &store.Fact{
+		MemoryID:   c.Fact1.MemoryID,
+		Subject:    r.MergedFact.Subject,
+		Predicate:  r.MergedFact.Predicate,
+		Object:     r.MergedFact.Object,
+		FactType:   r.MergedFact.FactType,
+		Confidence: r.Confidence,
+	})
+	if err != nil {
+		return fmt.Errorf("adding merged fact: %w", err)
+	}
+	for _, id := range []int64{c.Fact1.ID, c.Fact2.ID} {
+		if err := sqlStore.SupersedeFact(ctx, id, newID, "merged: "+r.Reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func conflictFact(f store.Fact) extract.ConflictFact {
+	return extract.ConflictFact{
+		ID:             f.ID,
+		Subject:        f.Subject,
+		Predicate:      f.Predicate,
+		Object:         f.Object,
+		FactType:       f.FactType,
+		Confidence:     f.Confidence,
+		DecayRate:      f.DecayRate,
+		Source:         f.SourceQuote,
+		CreatedAt:      f.CreatedAt,
+		LastReinforced: f.LastReinforced,
+	}
+}
@@ -0,0 +1,219 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/observe"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+type stubResolveProvider struct{ response string }
+
+func (p stubResolveProvider) Complete(ctx context.Context, prompt string, opts llm.CompletionOpts) (string, error) {
+	return p.response, nil
+}
+
+func (p stubResolveProvider) Name() string { return "stub/resolver" }
+
+// addConflictingFact adds a second "wedding venue" fact contradicting fact 1.
+func addConflictingFact(t *testing.T, s store.Store) int64 {
+	t.Helper()
+	id, err := s.AddFact(context.Background(), &store.Fact{MemoryID: 1, Subject: "wedding", Predicate: "venue", Object: "Villa Cimbrone",
+		FactType: "kv", Confidence: 0.8, DecayRate: 0.01, LastReinforced: time.Now().UTC()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+type resolveResponse struct {
+	DryRun bool                 `json:"dry_run"`
+	Batch  observe.ResolveBatch `json:"batch"`
+}
+
+func TestConflictsAndResolveTools(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+	ctx := context.Background()
+	newID := addConflictingFact(t, s)
+
+	var listed struct {
+		Count     int                `json:"count"`
+		Conflicts []observe.Conflict `json:"conflicts"`
+	}
+	result := callTool(t, srv, "cortex_conflicts", map[string]interface{}{})
+	if err := json.Unmarshal([]byte(getTextContent(t, result)), &listed); err != nil {
+		t.Fatalf("parse conflicts: %v", err)
+	}
+	if listed.Count != 1 || listed.Conflicts[0].Fact1.Subject != "wedding" {
+		t.Fatalf("conflicts = %+v", listed)
+	}
+	if result := callTool(t, srv, "cortex_conflicts", map[string]interface{}{"min_severity": "urgent"}); !result.IsError {
+		t.Fatal("expected an error for an unknown severity")
+	}
+
+	resolve := func(args map[string]interface{}) resolveResponse {
+		t.Helper()
+		result := callTool(t, srv, "cortex_resolve", args)
+		text := getTextContent(t, result)
+		if result.IsError {
+			t.Fatalf("cortex_resolve %v: %s", args, text)
+		}
+		var resp resolveResponse
+		if err := json.Unmarshal([]byte(text), &resp); err != nil {
+			t.Fatalf("parse resolve: %v", err)
+		}
+		return resp
+	}
+
+	dry := resolve(map[string]interface{}{"strategy": "last-write-wins", "dry_run": true})
+	if !dry.DryRun || dry.Batch.Total != 1 || dry.Batch.Results[0].WinnerID != newID || dry.Batch.Results[0].Applied {
+		t.Fatalf("dry run batch = %+v", dry.Batch)
+	}
+	if f, _ := s.GetFact(ctx, 1); f.SupersededBy != nil {
+		t.Fatal("dry run superseded a fact")
+	}
+
+	dry = resolve(map[string]interface{}{"keep_fact_id": float64(1), "drop_fact_id": float64(newID), "dry_run": true})
+	if dry.Batch.Results[0].Applied || dry.Batch.Results[0].LoserID != newID {
+		t.Fatalf("keep/drop dry run = %+v", dry.Batch)
+	}
+	applied := resolve(map[string]interface{}{"keep_fact_id": float64(1), "drop_fact_id": float64(newID)})
+	if !applied.Batch.Results[0].Applied {
+		t.Fatalf("keep/drop = %+v", applied.Batch)
+	}
+	if f, _ := s.GetFact(ctx, newID); f.SupersededBy == nil || *f.SupersededBy != 1 {
+		t.Fatalf("fact %d not superseded by fact 1: %+v", newID, f)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"strategy": "coin-flip"},
+		{"strategy": "newest", "keep_fact_id": float64(1)},
+		{"keep_fact_id": float64(1), "drop_fact_id": float64(1)},
+		{"keep_fact_id": float64(1), "drop_fact_id": float64(999)},
+	} {
+		if result := callTool(t, srv, "cortex_resolve", args); !result.IsError {
+			t.Fatalf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestResolveTool_LLMStrategy(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+	ctx := context.Background()
+	newID := addConflictingFact(t, s)
+
+	saved := newResolveProvider
+	t.Cleanup(func() { newResolveProvider = saved })
+	var gotModel string
+	newResolveProvider = func(model string) (llm.Provider, error) {
+		gotModel = model
+		return stubResolveProvider{response: fmt.Sprintf(`{"resolutions":[{"pair_index":0,"action":"supersede","winner_id":%d,"loser_id":1,"reason":"newer","confidence":0.95}]}`, newID)}, nil
+	}
+
+	result := callTool(t, srv, "cortex_resolve", map[string]interface{}{"strategy": "llm", "model": "stub/resolver"})
+	var resp resolveResponse
+	if err := json.Unmarshal([]byte(getTextContent(t, result)), &resp); err != nil {
+		t.Fatalf("parse resolve: %v", err)
+	}
+	if gotModel != "stub/resolver" {
+		t.Fatalf("model = %q", gotModel)
+	}
+	if resp.Batch.Resolved != 1 || len(resp.Batch.Results) != 1 {
+		t.Fatalf("batch = %+v", resp.Batch)
+	}
+	r := resp.Batch.Results[0]
+	wantWinner := "fact2"
+	if r.Conflict.Fact1.ID == newID {
+		wantWinner = "fact1"
+	}
+	if r.Strategy != strategyLLM || r.Winner != wantWinner || r.WinnerID != newID || !r.Applied {
+		t.Fatalf("resolution = %+v", r)
+	}
+	if f, _ := s.GetFact(ctx, 1); f.SupersededBy == nil || *f.SupersededBy != newID {
+		t.Fatalf("fact 1 not superseded: %+v", f)
+	}
+}
+
+func TestResolveTool_ReadOnly(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:", ReadOnly: true})
+	addConflictingFact(t, s)
+
+	result := callTool(t, srv, "cortex_resolve", map[string]interface{}{"strategy": "newest"})
+	if !result.IsError || !strings.Contains(getTextContent(t, result), "read-only") {
+		t.Fatal("expected cortex_resolve to be refused in read-only mode")
+	}
+	if result := callTool(t, srv, "cortex_resolve", map[string]interface{}{"strategy": "newest", "dry_run": true}); result.IsError {
+		t.Fatalf("dry run should work read-only: %s", getTextContent(t, result))
+	}
+}
+
+func TestConflictsAndResolveTools_StayInNamespace(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+	ctx := context.Background()
+
+	// Two work facts that disagree with each other and with default fact 1.
+	memID, err := s.AddMemory(store.WithNamespace(ctx, "work"), &store.Memory{Content: "Offsite planning", SourceFile: "work.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var workFacts []int64
+	for _, venue := range []string{"Villa Cimbrone", "Hotel Caruso"} {
+		id, err := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "wedding", Predicate: "venue", Object: venue,
+			FactType: "kv", Confidence: 0.8, DecayRate: 0.01, LastReinforced: time.Now().UTC()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		workFacts = append(workFacts, id)
+	}
+
+	count := func(args map[string]interface{}) int {
+		t.Helper()
+		var listed struct {
+			Count int `json:"count"`
+		}
+		if err := json.Unmarshal([]byte(getTextContent(t, callTool(t, srv, "cortex_conflicts", args))), &listed); err != nil {
+			t.Fatalf("parse conflicts: %v", err)
+		}
+		return listed.Count
+	}
+	if n := count(map[string]interface{}{}); n != 0 {
+		t.Fatalf("default namespace conflicts = %d, want 0", n)
+	}
+	if n := count(map[string]interface{}{"namespace": "work"}); n != 1 {
+		t.Fatalf("work namespace conflicts = %d, want 1", n)
+	}
+
+	result := callTool(t, srv, "cortex_resolve", map[string]interface{}{"strategy": "newest"})
+	if result.IsError || !strings.Contains(getTextContent(t, result), `"total": 0`) {
+		t.Fatalf("default namespace resolve = %s", getTextContent(t, result))
+	}
+	for _, args := range []map[string]interface{}{
+		{"keep_fact_id": float64(1), "drop_fact_id": float64(workFacts[0])},
+		{"keep_fact_id": float64(workFacts[0]), "drop_fact_id": float64(1), "namespace": "work"},
+	} {
+		result := callTool(t, srv, "cortex_resolve", args)
+		if !result.IsError || !strings.Contains(getTextContent(t, result), "not found") {
+			t.Fatalf("cortex_resolve %v crossed namespaces: %s", args, getTextContent(t, result))
+		}
+	}
+	for _, id := range append([]int64{1}, workFacts...) {
+		if f, _ := s.GetFact(ctx, id); f.SupersededBy != nil {
+			t.Fatalf("fact %d was superseded across namespaces", id)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Edge management tools mirror `cortex edge list|remove` and `cortex infer`,
+// next to cortex_edge_add and cortex_graph. Removing edges and applying
+// inference refuse to run when the server is read-only.
+
+// maxMCPInferEdges caps max_edges for cortex_infer.
+const maxMCPInferEdges = 500
+
+func registerEdgeListTool(s *server.MCPServer, st store.Store) {
+	tool := mcp.NewTool("cortex_edge_list",
+		mcp.WithDescription("List the relationship edges touching one fact, in both directions, with edge IDs, types, confidence, and origin (explicit, detected, inferred). Use before cortex_edge_remove to find the edge to drop, or to see a fact's direct neighbors without a full cortex_graph traversal."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithNumber("fact_id", mcp.Required(),
+			mcp.Description("Fact whose edges to list"),
+		),
+		mcp.WithString("edge_type",
+			mcp.Description("Only edges of this type: supports, contradicts, relates_to, supersedes, derived_from"),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		dbMu.Lock()
+		defer dbMu.Unlock()
+
+		sqlStore, ok := st.(*store.SQLiteStore)
+		if !ok {
+			return mcp.NewToolResultError("edges require SQLiteStore"), nil
+		}
+		factID, err := requirePositiveID(req, "fact_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		var edgeType store.EdgeType
+		if t, err := req.RequireString("edge_type"); err == nil && strings.TrimSpace(t) != "" {
+			if edgeType, err = store.ParseEdgeType(t); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		if res := requireVisibleFact(ctx, sqlStore, factID); res != nil {
+			return res, nil
+		}
+
+		edges, err := sqlStore.GetEdgesForFact(ctx, factID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		kept := []store.FactEdge{}
+		for _, e := range edges {
+			if edgeType == "" || e.EdgeType == edgeType {
+				kept = append(kept, e)
+			}
+		}
+
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"fact_id": factID,
+			"count":   len(kept),
+			"edges":   kept,
+		}, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
+
+func registerEdgeRemoveTool(s *server.MCPServer, st store.Store, readOnly bool) {
+	tool := mcp.NewTool("cortex_edge_remove",
+		mcp.WithDescription("Remove relationship edges from the knowledge graph: one edge by edge_id, or every edge matching a filter (source_fact_id, target_fact_id, edge_type, origin). Use dry_run to see what a filter matches first. Facts are never touched."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithNumber("edge_id",
+			mcp.Description("Edge to remove (from cortex_edge_list or cortex_graph). Cannot be combined with filters."),
+		),
+		mcp.WithNumber("source_fact_id",
+			mcp.Description("Filter: edges leaving this fact"),
+		),
+		mcp.WithNumber("target_fact_id",
+			mcp.Description("Filter: edges pointing at this fact"),
+		),
+		mcp.WithString("edge_type",
+			mcp.Description("Filter: supports, contradicts, relates_to, supersedes, derived_from"),
+		),
+		mcp.WithString("origin",
+			mcp.Description("Filter: how the edge was made"),
+			mcp.Enum(string(store.EdgeSourceExplicit), string(store.EdgeSourceDetected), string(store.EdgeSourceInferred)),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("List the matching edges without removing them (default false)"),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if res := readOnlyGuard(readOnly, "cortex_edge_remove"); res != nil {
+			return res, nil
+		}
+		dbMu.Lock()
+		defer dbMu.Unlock()
+
+		sqlStore, ok := st.(*store.SQLiteStore)
+		if !ok {
+			return mcp.NewToolResultError("edges require SQLiteStore"), nil
+		}
+		filter, err := mcpEdgeFilter(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		dryRun, _ := req.RequireBool("dry_run")
+
+		if hasMCPArg(req, "edge_id") {
+			if !filter.Empty() {
+				return mcp.NewToolResultError("pass edge_id or filters, not both"), nil
+			}
+			edgeID, err := requirePositiveID(req, "edge_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			edge, err := sqlStore.GetEdge(ctx, edgeID)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if edge == nil || requireVisibleFact(ctx, sqlStore, edge.SourceFactID) != nil || requireVisibleFact(ctx, sqlStore, edge.TargetFactID) != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("edge %d not found", edgeID)), nil
+			}
+			if dryRun {
+				data, _ := json.MarshalIndent(map[string]interface{}{"dry_run": true, "removed": 1, "edges": []store.FactEdge{*edge}}, "", "  ")
+				return mcp.NewToolResultText(string(data)), nil
+			}
+			if err := sqlStore.RemoveEdge(ctx, edgeID); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"removed": 1,
+				"edge_id": edgeID,
+				"message": fmt.Sprintf("Edge #%d removed", edgeID),
+			}, "", "  ")
+			return mcp.NewToolResultText(string(data)), nil
+		}
+
+		if filter.Empty() {
+			return mcp.NewToolResultError("pass edge_id, or at least one of source_fact_id, target_fact_id, edge_type, origin"), nil
+		}
+		if dryRun {
+			edges, err := sqlStore.FindEdges(ctx, filter)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if edges == nil {
+				edges = []store.FactEdge{}
+			}
+			data, _ := json.MarshalIndent(map[string]interface{}{"dry_run": true, "removed": len(edges), "edges": edges}, "", "  ")
+			return mcp.NewToolResultText(string(data)), nil
+		}
+		n, err := sqlStore.RemoveEdges(ctx, filter)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		data, _ := json.MarshalIndent(map[string]interface{}{"dry_run": false, "removed": n}, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
+
+// mcpEdgeFilter reads the bulk edge filter arguments of cortex_edge_remove.
+func mcpEdgeFilter(req mcp.CallToolRequest) (store.EdgeFilter, error) {
+	var f store.EdgeFilter
+	if hasMCPArg(req, "source_fact_id") {
+		id, err := requirePositiveID(req, "source_fact_id")
+		if err != nil {
+			return f, err
+		}
+		f.SourceFactID = id
+	}
+	if hasMCPArg(req, "target_fact_id") {
+		id, err := requirePositiveID(req, "target_fact_id")
+		if err != nil {
+			return f, err
+		}
+		f.TargetFactID = id
+	}
+	if t, err := req.RequireString("edge_type"); err == nil && strings.TrimSpace(t) != "" {
+		edgeType, err := store.ParseEdgeType(t)
+		if err != nil {
+			return f, err
+		}
+		f.Type = edgeType
+	}
+	if o, err := req.RequireString("origin"); err == nil && strings.TrimSpace(o) != "" {
+		switch src := store.EdgeSource(strings.ToLower(strings.TrimSpace(o))); src {
+		case store.EdgeSourceExplicit, store.EdgeSourceDetected, store.EdgeSourceInferred:
+			f.Source = src
+		default:
+			return f, fmt.Errorf("invalid origin %q (use explicit, detected, or inferred)", o)
+		}
+	}
+	return f, nil
+}
+
+func registerInferTool(s *server.MCPServer, st store.Store, readOnly bool) {
+	tool := mcp.NewTool("cortex_infer",
+		mcp.WithDescription("Run the graph inference rules (co-occurrence, subject clustering, supersession chains) to propose new relationship edges between facts. Defaults to a dry run that returns proposals with the rule and reason for each; pass dry_run=false to create them as inferred edges."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Only propose edges (default true)"),
+		),
+		mcp.WithNumber("min_confidence",
+			mcp.Description("Minimum confidence for inferred edges (default 0.3, range 0-1)"),
+		),
+		mcp.WithNumber("max_edges",
+			mcp.Description(fmt.Sprintf("Maximum edges to propose or create (default 100, max %d)", maxMCPInferEdges)),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		opts := store.DefaultInferenceOpts()
+		opts.DryRun = true
+		if d, err := req.RequireBool("dry_run"); err == nil {
+			opts.DryRun = d
+		}
+		if !opts.DryRun {
+			if res := readOnlyGuard(readOnly, "cortex_infer"); res != nil {
+				return res, nil
+			}
+		}
+		if c, err := req.RequireFloat("min_confidence"); err == nil {
+			if c < 0 || c > 1 {
+				return mcp.NewToolResultError("min_confidence must be between 0 and 1"), nil
+			}
+			opts.MinConfidence = c
+		}
+		if m, err := req.RequireFloat("max_edges"); err == nil && m > 0 {
+			opts.MaxEdges = int(m)
+			if opts.MaxEdges > maxMCPInferEdges {
+				opts.MaxEdges = maxMCPInferEdges
+			}
+		}
+
+		dbMu.Lock()
+		defer dbMu.Unlock()
+
+		sqlStore, ok := st.(*store.SQLiteStore)
+		if !ok {
+			return mcp.NewToolResultError("infer requires SQLiteStore"), nil
+		}
+		result, err := sqlStore.RunInference(ctx, opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("inference error: %v", err)), nil
+		}
+		result.Proposals = hideMCPEdgeProposals(ctx, sqlStore, result.Proposals)
+
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"dry_run":       opts.DryRun,
+			"edges_created": result.EdgesCreated,
+			"edges_skipped": result.EdgesSkipped,
+			"rules_applied": result.RulesApplied,
+			"proposals":     result.Proposals,
+		}, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
+
+// requireVisibleFact returns an error result unless factID exists, belongs
+// to the request's namespace, and is not in a no-mcp project.
+func requireVisibleFact(ctx context.Context, sqlStore *store.SQLiteStore, factID int64) *mcp.CallToolResult {
+	fact, err := sqlStore.GetFact(ctx, factID)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+	if fact != nil && sqlStore.FactInNamespace(ctx, factID) {
+		visible, err := hideMCPFacts(ctx, sqlStore, []*store.Fact{fact})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error())
+		}
+		if len(visible) == 1 {
+			return nil
+		}
+	}
+	return mcp.NewToolResultError(fmt.Sprintf("fact %d not found", factID))
+}
+
+// hideMCPEdgeProposals drops inference proposals touching a fact outside the
+// request's namespace or in a no-mcp project, since their reasons quote fact
+// subjects. A fact that can't be checked counts as hidden.
+func hideMCPEdgeProposals(ctx context.Context, sqlStore *store.SQLiteStore, proposals []store.EdgeProposal) []store.EdgeProposal {
+	kept := []store.EdgeProposal{}
+	cache := map[int64]bool{}
+	visible := func(id int64) bool {
+		v, seen := cache[id]
+		if !seen {
+			v = requireVisibleFact(ctx, sqlStore, id) == nil
+			cache[id] = v
+		}
+		return v
+	}
+	for _, p := range proposals {
+		if visible(p.SourceFactID) && visible(p.TargetFactID) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
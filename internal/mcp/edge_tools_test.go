@@ -0,0 +1,181 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestEdgeListAndRemoveTools(t *testing.T) {
+	s, srv, sqlStore := setupGraphToolServer(t)
+	defer s.Close()
+	ctx := context.Background()
+
+	var listed struct {
+		Count int              `json:"count"`
+		Edges []store.FactEdge `json:"edges"`
+	}
+	result := callTool(t, srv, "cortex_edge_list", map[string]interface{}{"fact_id": float64(2)})
+	if err := json.Unmarshal([]byte(getTextContent(t, result)), &listed); err != nil {
+		t.Fatalf("parse edge list: %v", err)
+	}
+	if listed.Count != 2 {
+		t.Fatalf("fact 2 edges = %+v", listed.Edges)
+	}
+	result = callTool(t, srv, "cortex_edge_list", map[string]interface{}{"fact_id": float64(2), "edge_type": "supports"})
+	json.Unmarshal([]byte(getTextContent(t, result)), &listed)
+	if listed.Count != 1 || listed.Edges[0].EdgeType != store.EdgeTypeSupports {
+		t.Fatalf("supports edges = %+v", listed.Edges)
+	}
+	if result := callTool(t, srv, "cortex_edge_list", map[string]interface{}{"fact_id": float64(999)}); !result.IsError {
+		t.Fatal("expected not-found error for a missing fact")
+	}
+
+	result = callTool(t, srv, "cortex_edge_remove", map[string]interface{}{"edge_type": "relates_to", "dry_run": true})
+	if text := getTextContent(t, result); result.IsError || !strings.Contains(text, `"removed": 2`) {
+		t.Fatalf("dry run = %s", text)
+	}
+	if edges, _ := sqlStore.FindEdges(ctx, store.EdgeFilter{}); len(edges) != 3 {
+		t.Fatalf("dry run removed edges: %d left", len(edges))
+	}
+	if result := callTool(t, srv, "cortex_edge_remove", map[string]interface{}{}); !result.IsError {
+		t.Fatal("expected an error without edge_id or filters")
+	}
+	if result := callTool(t, srv, "cortex_edge_remove", map[string]interface{}{"edge_id": float64(1), "edge_type": "supports"}); !result.IsError {
+		t.Fatal("expected an error combining edge_id with filters")
+	}
+
+	result = callTool(t, srv, "cortex_edge_remove", map[string]interface{}{"edge_id": float64(listed.Edges[0].ID)})
+	if result.IsError {
+		t.Fatalf("remove by id: %s", getTextContent(t, result))
+	}
+	result = callTool(t, srv, "cortex_edge_remove", map[string]interface{}{"edge_type": "relates_to", "origin": "explicit"})
+	if text := getTextContent(t, result); !strings.Contains(text, `"removed": 2`) {
+		t.Fatalf("bulk remove = %s", text)
+	}
+	if edges, _ := sqlStore.FindEdges(ctx, store.EdgeFilter{}); len(edges) != 0 {
+		t.Fatalf("edges left: %+v", edges)
+	}
+}
+
+func TestInferTool_DryRunByDefault(t *testing.T) {
+	s, srv, sqlStore := setupGraphToolServer(t)
+	defer s.Close()
+	ctx := context.Background()
+
+	before, _ := sqlStore.FindEdges(ctx, store.EdgeFilter{Source: store.EdgeSourceInferred})
+	result := callTool(t, srv, "cortex_infer", map[string]interface{}{"min_confidence": 0.1})
+	var payload struct {
+		DryRun       bool                 `json:"dry_run"`
+		EdgesCreated int                  `json:"edges_created"`
+		Proposals    []store.EdgeProposal `json:"proposals"`
+	}
+	if err := json.Unmarshal([]byte(getTextContent(t, result)), &payload); err != nil {
+		t.Fatalf("parse infer result: %v", err)
+	}
+	if !payload.DryRun || payload.EdgesCreated != 0 || payload.Proposals == nil {
+		t.Fatalf("infer defaults = %+v", payload)
+	}
+	if after, _ := sqlStore.FindEdges(ctx, store.EdgeFilter{Source: store.EdgeSourceInferred}); len(after) != len(before) {
+		t.Fatal("dry run created inferred edges")
+	}
+	if result := callTool(t, srv, "cortex_infer", map[string]interface{}{"min_confidence": 2.0}); !result.IsError {
+		t.Fatal("expected an error for min_confidence out of range")
+	}
+}
+
+func TestEdgeWriteTools_ReadOnly(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:", ReadOnly: true})
+
+	if result := callTool(t, srv, "cortex_edge_remove", map[string]interface{}{"edge_id": float64(1)}); !result.IsError || !strings.Contains(getTextContent(t, result), "read-only") {
+		t.Fatal("expected cortex_edge_remove to be refused in read-only mode")
+	}
+	if result := callTool(t, srv, "cortex_infer", map[string]interface{}{"dry_run": false}); !result.IsError {
+		t.Fatal("expected applying inference to be refused in read-only mode")
+	}
+	if result := callTool(t, srv, "cortex_infer", map[string]interface{}{}); result.IsError {
+		t.Fatalf("dry-run inference should work read-only: %s", getTextContent(t, result))
+	}
+}
+
+func TestEdgeRemoveTool_ByIDChecksEndpoints(t *testing.T) {
+	si, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer si.Close()
+	s := si.(*store.SQLiteStore)
+	ctx := context.Background()
+
+	addFact := func(ctx context.Context, project, subject string) int64 {
+		t.Helper()
+		memID, err := s.AddMemory(ctx, &store.Memory{Content: "notes about " + subject, SourceFile: subject + ".md", Project: project})
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: subject, Predicate: "status", Object: "active", FactType: "kv", Confidence: 0.9})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	addEdge := func(from, to int64) int64 {
+		t.Helper()
+		if err := s.AddEdge(ctx, &store.FactEdge{SourceFactID: from, TargetFactID: to, EdgeType: store.EdgeTypeRelatesTo, Confidence: 0.8, Source: store.EdgeSourceExplicit}); err != nil {
+			t.Fatal(err)
+		}
+		edges, _ := s.FindEdges(store.WithNamespace(ctx, store.AllNamespaces), store.EdgeFilter{SourceFactID: from, TargetFactID: to})
+		return edges[0].ID
+	}
+
+	roadmap := addFact(ctx, "work", "roadmap")
+	budget := addFact(ctx, "work", "budget")
+	vacation := addFact(ctx, "personal", "vacation")
+	team := store.WithNamespace(ctx, "team")
+	sprint, standup := addFact(team, "", "sprint"), addFact(team, "", "standup")
+	visible := addEdge(roadmap, budget)
+	private := addEdge(roadmap, vacation)
+	otherNamespace := addEdge(sprint, standup)
+
+	if err := s.SetProjectPrivacy(ctx, store.ProjectPrivacy{Project: "personal", NoMCP: true}); err != nil {
+		t.Fatalf("SetProjectPrivacy: %v", err)
+	}
+	defer s.SetProjectPrivacy(ctx, store.ProjectPrivacy{Project: "personal"})
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+
+	result := callTool(t, srv, "cortex_edge_remove", map[string]interface{}{"edge_id": float64(visible), "dry_run": true})
+	var dry struct {
+		DryRun  bool             `json:"dry_run"`
+		Removed int              `json:"removed"`
+		Edges   []store.FactEdge `json:"edges"`
+	}
+	if err := json.Unmarshal([]byte(getTextContent(t, result)), &dry); err != nil {
+		t.Fatalf("parse dry run: %v", err)
+	}
+	if !dry.DryRun || dry.Removed != 1 || len(dry.Edges) != 1 || dry.Edges[0].SourceFactID != roadmap || dry.Edges[0].TargetFactID != budget {
+		t.Fatalf("dry run = %+v", dry)
+	}
+
+	for _, id := range []int64{private, otherNamespace, 9999} {
+		for _, dryRun := range []bool{true, false} {
+			result := callTool(t, srv, "cortex_edge_remove", map[string]interface{}{"edge_id": float64(id), "dry_run": dryRun})
+			if !result.IsError || !strings.Contains(getTextContent(t, result), "not found") {
+				t.Fatalf("edge %d (dry_run=%v) = %s, want not found", id, dryRun, getTextContent(t, result))
+			}
+		}
+	}
+	if edges, _ := s.FindEdges(store.WithNamespace(ctx, store.AllNamespaces), store.EdgeFilter{Type: store.EdgeTypeRelatesTo}); len(edges) != 3 {
+		t.Fatalf("edges = %d, want all 3 kept", len(edges))
+	}
+
+	result = callTool(t, srv, "cortex_edge_remove", map[string]interface{}{"edge_id": float64(otherNamespace), "namespace": "team"})
+	if result.IsError {
+		t.Fatalf("removing an edge in its own namespace: %s", getTextContent(t, result))
+	}
+}
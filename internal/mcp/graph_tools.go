@@ -340,6 +340,9 @@ func buildGraphExploreResult(
 
 	for _, seedID := range seedFactIDs {
 		graphNodes, err := sqlStore.TraverseGraph(ctx, seedID, depth, minConfidence)
+		if err == nil {
+			graphNodes, err = hideMCPGraphNodes(ctx, sqlStore, graphNodes)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -521,3 +521,45 @@ func TestGraphExploreMCPTypes(t *testing.T) {
 	// Compile-time guard against accidental local type drift.
 	var _ = mcplib.CallToolResult{}
 }
+
+func TestBriefResources(t *testing.T) {
+	s, srv, sqlStore := setupGraphToolServer(t)
+	defer s.Close()
+
+	text := callResource(t, srv, "cortex://brief")
+	if !strings.Contains(text, "no briefs yet") {
+		t.Fatalf("expected an empty-brief message, got %s", text)
+	}
+
+	ctx := context.Background()
+	for _, b := range []*store.Brief{
+		{Level: store.BriefLevelStore, Title: "Identity card", Summary: "Builds cortex.", FactCount: 3, Fingerprint: "s"},
+		{Level: store.BriefLevelProject, Key: "my project", Project: "my project", Title: "my project", Summary: "Memory engine.", FactCount: 3, Fingerprint: "p"},
+		{Level: store.BriefLevelCluster, Key: store.ClusterBriefKey("my project", 1), Project: "my project", ClusterID: 1, Title: "search", Summary: "Uses HNSW.", FactCount: 3, Fingerprint: "c"},
+	} {
+		if err := sqlStore.UpsertBrief(ctx, b); err != nil {
+			t.Fatalf("UpsertBrief: %v", err)
+		}
+	}
+
+	var payload struct {
+		Identity *store.Brief `json:"identity"`
+		Domains  []struct {
+			Summary  string        `json:"summary"`
+			Clusters []store.Brief `json:"clusters"`
+		} `json:"domains"`
+	}
+	if err := json.Unmarshal([]byte(callResource(t, srv, "cortex://brief")), &payload); err != nil {
+		t.Fatalf("parse brief resource: %v", err)
+	}
+	if payload.Identity == nil || payload.Identity.Summary != "Builds cortex." || len(payload.Domains) != 1 || len(payload.Domains[0].Clusters) != 0 {
+		t.Fatalf("unexpected brief payload: %+v", payload)
+	}
+
+	if err := json.Unmarshal([]byte(callResource(t, srv, "cortex://brief/my%20project")), &payload); err != nil {
+		t.Fatalf("parse project brief resource: %v", err)
+	}
+	if len(payload.Domains) != 1 || len(payload.Domains[0].Clusters) != 1 || payload.Domains[0].Clusters[0].Summary != "Uses HNSW." {
+		t.Fatalf("unexpected project brief payload: %+v", payload)
+	}
+}
@@ -0,0 +1,218 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/jobs"
+	"github.com/hurttlocker/cortex/internal/store"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// jobView is a job as returned by the job tools, with its result decoded.
+type jobView struct {
+	*store.Job
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+func newJobView(j *store.Job) jobView {
+	v := jobView{Job: j}
+	if json.Valid([]byte(j.Result)) {
+		v.Result = json.RawMessage(j.Result)
+	}
+	return v
+}
+
+// serverJobRunner returns the runner the job tools use: cfg.Jobs, or one
+// with the built-in handlers when the store supports jobs. Tool handlers
+// start it on first use so servers that never queue work spawn no workers.
+func serverJobRunner(cfg ServerConfig) *jobs.Runner {
+	runner := cfg.Jobs
+	if runner == nil {
+		sqlStore, ok := cfg.Store.(*store.SQLiteStore)
+		if !ok {
+			return nil
+		}
+		runner = jobs.NewRunner(sqlStore)
+		jobs.RegisterBuiltins(runner, jobs.BuiltinOptions{Embedder: cfg.Embedder})
+	}
+	if runner.Locker == nil {
+		runner.Locker = &dbMu
+	}
+	return runner
+}
+
+func hasJobKind(runner *jobs.Runner, kind string) bool {
+	for _, k := range runner.Kinds() {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIDList parses a comma-separated list of positive IDs.
+func parseIDList(s string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid ID %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func registerJobStartTool(s *server.MCPServer, runner *jobs.Runner, readOnly bool) {
+	tool := mcp.NewTool("cortex_job_start",
+		mcp.WithDescription("Start a long-running maintenance task in the background and return its job ID immediately. Kinds: extract (facts from given memories), enrich (extract plus LLM enrichment), embed (memories without embeddings), cluster_rebuild (topic clusters). Poll with cortex_job_status; stop with cortex_job_cancel."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("kind", mcp.Required(),
+			mcp.Description("Job kind; available: "+strings.Join(runner.Kinds(), ", ")),
+			mcp.Enum(runner.Kinds()...),
+		),
+		mcp.WithString("memory_ids",
+			mcp.Description("Comma-separated memory IDs (required for extract and enrich)"),
+		),
+		mcp.WithBoolean("enrich",
+			mcp.Description("For extract: also run LLM enrichment when the server has an LLM"),
+		),
+		mcp.WithString("source",
+			mcp.Description("For embed: only embed memories from this source file"),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if res := readOnlyGuard(readOnly, "cortex_job_start"); res != nil {
+			return res, nil
+		}
+		kind, err := req.RequireString("kind")
+		if err != nil || strings.TrimSpace(kind) == "" {
+			return mcp.NewToolResultError("kind is required"), nil
+		}
+		kind = strings.TrimSpace(kind)
+
+		var params any
+		switch kind {
+		case jobs.KindExtract, jobs.KindEnrich:
+			idsArg, _ := req.RequireString("memory_ids")
+			ids, err := parseIDList(idsArg)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if len(ids) == 0 {
+				return mcp.NewToolResultError("memory_ids is required for " + kind + " jobs"), nil
+			}
+			params = jobs.ExtractParams{MemoryIDs: ids, Enrich: boolArg(req, "enrich")}
+		case jobs.KindEmbed:
+			source, _ := req.RequireString("source")
+			params = jobs.EmbedParams{Source: strings.TrimSpace(source)}
+		}
+
+		runner.Start(context.Background())
+		job, err := runner.Enqueue(ctx, kind, params, "mcp")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"job_id":  job.ID,
+			"kind":    job.Kind,
+			"status":  job.Status,
+			"message": fmt.Sprintf("Started %s job %d; check it with cortex_job_status", job.Kind, job.ID),
+		}, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
+
+func registerJobStatusTool(s *server.MCPServer, runner *jobs.Runner) {
+	tool := mcp.NewTool("cortex_job_status",
+		mcp.WithDescription("Check on background jobs (started by cortex_job_start, cortex_import with background=true, or `cortex jobs start`). Pass job_id for one job's progress and result, or omit it to list recent jobs."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithNumber("job_id",
+			mcp.Description("Job to inspect (omit to list jobs)"),
+		),
+		mcp.WithString("status",
+			mcp.Description("When listing: queued, running, done, failed, cancelled, or active (queued+running)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("When listing: maximum jobs to return (default: 20)"),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		st := runner.Store()
+		if id, err := req.RequireFloat("job_id"); err == nil {
+			job, err := st.GetJob(ctx, int64(id))
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if job == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("job %d not found", int64(id))), nil
+			}
+			data, _ := json.MarshalIndent(newJobView(job), "", "  ")
+			return mcp.NewToolResultText(string(data)), nil
+		}
+
+		status, _ := req.RequireString("status")
+		limit := 20
+		if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+			limit = int(l)
+		}
+		list, err := st.ListJobs(ctx, store.JobListOpts{Status: status, Limit: limit})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		views := make([]jobView, 0, len(list))
+		for _, j := range list {
+			views = append(views, newJobView(j))
+		}
+		data, _ := json.MarshalIndent(map[string]interface{}{"jobs": views}, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
+
+func registerJobCancelTool(s *server.MCPServer, runner *jobs.Runner, readOnly bool) {
+	tool := mcp.NewTool("cortex_job_cancel",
+		mcp.WithDescription("Cancel a background job. Queued jobs are cancelled immediately; running jobs stop at their next checkpoint, keeping the work already done."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithNumber("job_id", mcp.Required(),
+			mcp.Description("Job to cancel"),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if res := readOnlyGuard(readOnly, "cortex_job_cancel"); res != nil {
+			return res, nil
+		}
+		id, err := requirePositiveID(req, "job_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		job, err := runner.Store().CancelJob(ctx, id)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		msg := fmt.Sprintf("Job %d cancelled", id)
+		if job.Status == store.JobRunning {
+			msg = fmt.Sprintf("Cancellation requested; job %d stops at its next checkpoint", id)
+		}
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"job_id":  id,
+			"status":  job.Status,
+			"message": msg,
+		}, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
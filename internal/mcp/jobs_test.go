@@ -0,0 +1,152 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const extractableContent = "Database: PostgreSQL 16\nDeploy target: fly.io\nOwner: platform team"
+
+func waitForJobTool(t *testing.T, call func(args map[string]interface{}) string, id int64) map[string]interface{} {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var job map[string]interface{}
+		if err := json.Unmarshal([]byte(call(map[string]interface{}{"job_id": float64(id)})), &job); err != nil {
+			t.Fatal(err)
+		}
+		switch job["status"] {
+		case store.JobDone, store.JobFailed, store.JobCancelled:
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %d did not finish: %v", id, job)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestImportTool_BackgroundExtraction(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+
+	result := callTool(t, srv, "cortex_import", map[string]interface{}{
+		"content":    extractableContent,
+		"source":     "infra.md",
+		"extract":    true,
+		"background": true,
+		"enrich":     true,
+	})
+	var imported map[string]interface{}
+	if err := json.Unmarshal([]byte(getTextContent(t, result)), &imported); err != nil {
+		t.Fatal(err)
+	}
+	jobID, _ := imported["extraction_job"].(float64)
+	if jobID == 0 {
+		t.Fatalf("expected an extraction job, got %v", imported)
+	}
+	if _, ok := imported["facts_extracted"]; ok {
+		t.Fatal("background import should not report facts before the job runs")
+	}
+	if imported["enrich_skipped"] == nil {
+		t.Fatal("enrich without a configured LLM should be reported as skipped")
+	}
+
+	status := func(args map[string]interface{}) string {
+		res := callTool(t, srv, "cortex_job_status", args)
+		if res.IsError {
+			t.Fatalf("status failed: %s", getTextContent(t, res))
+		}
+		return getTextContent(t, res)
+	}
+	job := waitForJobTool(t, status, int64(jobID))
+	res, _ := job["result"].(map[string]interface{})
+	if job["status"] != store.JobDone || job["kind"] != "extract" || res == nil || res["facts_extracted"].(float64) == 0 {
+		t.Fatalf("unexpected finished job: %v", job)
+	}
+
+	if list := status(map[string]interface{}{"status": "done"}); !strings.Contains(list, `"kind": "extract"`) {
+		t.Fatalf("job list should include the finished job: %s", list)
+	}
+	missing := callTool(t, srv, "cortex_job_status", map[string]interface{}{"job_id": float64(999)})
+	if !missing.IsError {
+		t.Fatal("expected an error for an unknown job")
+	}
+}
+
+func TestJobStartAndCancelTools(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+
+	if res := callTool(t, srv, "cortex_job_start", map[string]interface{}{"kind": "extract"}); !res.IsError {
+		t.Fatal("extract without memory_ids should be rejected")
+	}
+	if res := callTool(t, srv, "cortex_job_start", map[string]interface{}{"kind": "embed"}); !res.IsError {
+		t.Fatal("embed should be unavailable without an embedder")
+	}
+
+	res := callTool(t, srv, "cortex_job_start", map[string]interface{}{"kind": "cluster_rebuild"})
+	if res.IsError {
+		t.Fatalf("start failed: %s", getTextContent(t, res))
+	}
+	var started map[string]interface{}
+	if err := json.Unmarshal([]byte(getTextContent(t, res)), &started); err != nil {
+		t.Fatal(err)
+	}
+	id := started["job_id"].(float64)
+	status := func(args map[string]interface{}) string {
+		return getTextContent(t, callTool(t, srv, "cortex_job_status", args))
+	}
+	if job := waitForJobTool(t, status, int64(id)); job["status"] != store.JobDone {
+		t.Fatalf("unexpected cluster job: %v", job)
+	}
+
+	cancel := callTool(t, srv, "cortex_job_cancel", map[string]interface{}{"job_id": id})
+	if !cancel.IsError || !strings.Contains(getTextContent(t, cancel), "already done") {
+		t.Fatal("cancelling a finished job should fail")
+	}
+
+	queued, err := s.(*store.SQLiteStore).EnqueueJob(t.Context(), &store.Job{Kind: "not_handled_here"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel = callTool(t, srv, "cortex_job_cancel", map[string]interface{}{"job_id": float64(queued)})
+	if cancel.IsError || !strings.Contains(getTextContent(t, cancel), fmt.Sprintf("Job %d cancelled", queued)) {
+		t.Fatalf("queued job should be cancelled: %s", getTextContent(t, cancel))
+	}
+
+	ro := NewServer(ServerConfig{Store: s, DBPath: ":memory:", ReadOnly: true})
+	for _, name := range []string{"cortex_job_start", "cortex_job_cancel"} {
+		res := callTool(t, ro, name, map[string]interface{}{"kind": "cluster_rebuild", "job_id": float64(1)})
+		if !res.IsError || !strings.Contains(getTextContent(t, res), "read-only") {
+			t.Fatalf("%s should be refused in read-only mode", name)
+		}
+	}
+}
+
+func TestImportTool_ExtractAcceptsStringFlag(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+
+	for i, extract := range []interface{}{true, "true"} {
+		result := callTool(t, srv, "cortex_import", map[string]interface{}{
+			"content": extractableContent + fmt.Sprintf("\nRegion: us-east-%d", i+1),
+			"extract": extract,
+		})
+		var imported map[string]interface{}
+		if err := json.Unmarshal([]byte(getTextContent(t, result)), &imported); err != nil {
+			t.Fatal(err)
+		}
+		if n, _ := imported["facts_extracted"].(float64); n == 0 {
+			t.Fatalf("extract=%#v: expected inline extraction, got %v", extract, imported)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/ingest"
+	"github.com/hurttlocker/cortex/internal/store"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Memory maintenance tools let agents fix what they stored: edit a memory,
+// retire a fact in favor of a newer one, and confirm a fact is still true.
+// Every change is written to memory_events with an "mcp:<tool>[:<agent>]"
+// source, and all of them refuse to run when the server is read-only.
+
+// readOnlyGuard returns the error result for a write tool called on a
+// read-only server, or nil when writes are allowed.
+func readOnlyGuard(readOnly bool, tool string) *mcp.CallToolResult {
+	if !readOnly {
+		return nil
+	}
+	return mcp.NewToolResultError(fmt.Sprintf("%s is unavailable: the MCP server is running in read-only mode", tool))
+}
+
+// maintenanceSource is the memory_events source for a maintenance tool call.
+func maintenanceSource(tool, agentID string) string {
+	if agentID = strings.TrimSpace(agentID); agentID != "" {
+		return "mcp:" + tool + ":" + agentID
+	}
+	return "mcp:" + tool
+}
+
+// callerAgent is the agent_id argument, falling back to the server scope.
+func callerAgent(req mcp.CallToolRequest, defaultAgent string) string {
+	if a, err := req.RequireString("agent_id"); err == nil && strings.TrimSpace(a) != "" {
+		return strings.TrimSpace(a)
+	}
+	return defaultAgent
+}
+
+// excerpt shortens s to at most n runes for audit values and responses.
+func excerpt(s string, n int) string {
+	r := []rune(strings.TrimSpace(s))
+	if len(r) <= n {
+		return string(r)
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// requirePositiveID reads a required numeric ID argument.
+func requirePositiveID(req mcp.CallToolRequest, name string) (int64, error) {
+	v, err := req.RequireFloat(name)
+	if err != nil || v <= 0 || v != float64(int64(v)) {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return int64(v), nil
+}
+
+func registerUpdateMemoryTool(s *server.MCPServer, st store.Store, defaultAgent string, readOnly bool) {
+	tool := mcp.NewTool("cortex_update_memory",
+		mcp.WithDescription("Correct a stored memory: replace its content and/or change its class. Use when a memory is wrong or outdated and you know the right text. Changing content re-extracts the memory's facts unless extract is false. Every change is recorded in the audit log. Returns what changed."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithNumber("memory_id", mcp.Required(),
+			mcp.Description("ID of the memory to update (from cortex_search results)"),
+		),
+		mcp.WithString("content",
+			mcp.Description("New content for the memory"),
+		),
+		mcp.WithString("class",
+			mcp.Description("New class: rule, decision, preference, identity, status, scratch, or 'none' to clear"),
+		),
+		mcp.WithBoolean("extract",
+			mcp.Description("Re-extract facts from the new content (default: true)"),
+		),
+		mcp.WithString("agent_id",
+			mcp.Description("Agent making the change, recorded in the audit log"),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if res := readOnlyGuard(readOnly, "cortex_update_memory"); res != nil {
+			return res, nil
+		}
+		dbMu.Lock()
+		defer dbMu.Unlock()
+
+		sqlStore, ok := st.(*store.SQLiteStore)
+		if !ok {
+			return mcp.NewToolResultError("memory updates require SQLiteStore"), nil
+		}
+		id, err := requirePositiveID(req, "memory_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		content, _ := req.RequireString("content")
+		classArg, _ := req.RequireString("class")
+		if strings.TrimSpace(content) == "" && strings.TrimSpace(classArg) == "" {
+			return mcp.NewToolResultError("provide content and/or class to change"), nil
+		}
+		reextract := true
+		if v, err := req.RequireBool("extract"); err == nil {
+			reextract = v
+		}
+		agentID := callerAgent(req, defaultAgent)
+
+		m, err := st.GetMemory(ctx, id)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("loading memory %d: %v", id, err)), nil
+		}
+		if m == nil || m.DeletedAt != nil || !store.NamespaceVisible(sqlStore.Namespace(ctx), m.Namespace) {
+			return mcp.NewToolResultError(fmt.Sprintf("memory %d not found", id)), nil
+		}
+		if defaultAgent != "" && m.Metadata != nil && m.Metadata.AgentID != "" && m.Metadata.AgentID != defaultAgent {
+			return mcp.NewToolResultError(fmt.Sprintf("memory %d belongs to agent %q", id, m.Metadata.AgentID)), nil
+		}
+
+		result := map[string]interface{}{"memory_id": id}
+		var changes []store.MemoryMetaChange
+		if class := strings.TrimSpace(classArg); class != "" {
+			if strings.EqualFold(class, "none") {
+				class = ""
+			}
+			actor := "mcp"
+			if agentID != "" {
+				actor += ":" + agentID
+			}
+			changes, err = sqlStore.EditMemoryMeta(ctx, id, store.MemoryMetaEdit{Class: &class, Actor: actor})
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		if content != "" && content != m.Content {
+			if err := st.UpdateMemory(ctx, id, content); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			_ = st.LogEvent(ctx, &store.MemoryEvent{
+				EventType: "update",
+				FactID:    id,
+				OldValue:  "memory content=" + excerpt(m.Content, 200),
+				NewValue:  "memory content=" + excerpt(content, 200),
+				Source:    maintenanceSource("update_memory", agentID),
+			})
+			changes = append(changes, store.MemoryMetaChange{Field: "content", Old: excerpt(m.Content, 80), New: excerpt(content, 80)})
+			if reextract {
+				n, err := ingest.ReextractMemoryFacts(ctx, st, m, content)
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("memory updated, but %v", err)), nil
+				}
+				result["facts_extracted"] = n
+			}
+		}
+
+		if changes == nil {
+			changes = []store.MemoryMetaChange{}
+		}
+		result["changes"] = changes
+		result["message"] = fmt.Sprintf("Updated memory %d (%d change(s))", id, len(changes))
+		data, _ := json.MarshalIndent(result, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
+
+func registerSupersedeFactTool(s *server.MCPServer, st store.Store, defaultAgent string, readOnly bool) {
+	tool := mcp.NewTool("cortex_supersede_fact",
+		mcp.WithDescription("Retire an outdated fact in favor of a newer one. The old fact is kept for history but hidden from search and fact listings by default, and a 'supersedes' graph edge links the two. Use when cortex_facts shows two facts that disagree and you know which is current. Recorded in the audit log."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithNumber("old_fact_id", mcp.Required(),
+			mcp.Description("Fact being retired"),
+		),
+		mcp.WithNumber("new_fact_id", mcp.Required(),
+			mcp.Description("Fact that replaces it"),
+		),
+		mcp.WithString("reason",
+			mcp.Description("Why the old fact no longer holds"),
+		),
+		mcp.WithString("agent_id",
+			mcp.Description("Agent making the change, recorded in the audit log"),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if res := readOnlyGuard(readOnly, "cortex_supersede_fact"); res != nil {
+			return res, nil
+		}
+		dbMu.Lock()
+		defer dbMu.Unlock()
+
+		sqlStore, ok := st.(*store.SQLiteStore)
+		if !ok {
+			return mcp.NewToolResultError("superseding facts requires SQLiteStore"), nil
+		}
+		oldID, err := requirePositiveID(req, "old_fact_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		newID, err := requirePositiveID(req, "new_fact_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		reason, _ := req.RequireString("reason")
+		agentID := callerAgent(req, defaultAgent)
+		for _, id := range []int64{oldID, newID} {
+			if !sqlStore.FactInNamespace(ctx, id) {
+				return mcp.NewToolResultError(fmt.Sprintf("fact %d not found", id)), nil
+			}
+		}
+
+		oldFact, err := st.GetFact(ctx, oldID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if oldFact != nil && oldFact.SupersededBy != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("fact %d is already superseded by fact %d", oldID, *oldFact.SupersededBy)), nil
+		}
+		if oldFact != nil && defaultAgent != "" && oldFact.AgentID != "" && oldFact.AgentID != defaultAgent {
+			return mcp.NewToolResultError(fmt.Sprintf("fact %d belongs to agent %q", oldID, oldFact.AgentID)), nil
+		}
+		if err := sqlStore.SupersedeFactWithSource(ctx, oldID, newID, strings.TrimSpace(reason), maintenanceSource("supersede_fact", agentID)); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"old_fact_id": oldID,
+			"new_fact_id": newID,
+			"message":     fmt.Sprintf("Fact %d superseded by fact %d", oldID, newID),
+		}, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
+
+func registerReinforceFactTool(s *server.MCPServer, st store.Store, defaultAgent string, readOnly bool) {
+	tool := mcp.NewTool("cortex_reinforce_fact",
+		mcp.WithDescription("Confirm a single fact is still accurate, fully resetting its decay timer and recording which agent confirmed it. Use after verifying a fact from cortex_stale. Recorded in the audit log. For bulk resets without attribution, use cortex_reinforce."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithNumber("fact_id", mcp.Required(),
+			mcp.Description("Fact to reinforce"),
+		),
+		mcp.WithString("agent_id",
+			mcp.Description("Agent confirming the fact, recorded in the audit log"),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if res := readOnlyGuard(readOnly, "cortex_reinforce_fact"); res != nil {
+			return res, nil
+		}
+		dbMu.Lock()
+		defer dbMu.Unlock()
+
+		sqlStore, ok := st.(*store.SQLiteStore)
+		if !ok {
+			return mcp.NewToolResultError("reinforcing facts requires SQLiteStore"), nil
+		}
+		id, err := requirePositiveID(req, "fact_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		agentID := callerAgent(req, defaultAgent)
+
+		fact, err := st.GetFact(ctx, id)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if fact == nil || !sqlStore.FactInNamespace(ctx, id) {
+			return mcp.NewToolResultError(fmt.Sprintf("fact %d not found", id)), nil
+		}
+		if fact.SupersededBy != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("fact %d is superseded by fact %d; reinforce that one instead", id, *fact.SupersededBy)), nil
+		}
+		if err := sqlStore.RecordFactAccess(ctx, id, agentID, store.AccessTypeReinforce); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		now := time.Now().UTC()
+		_ = st.LogEvent(ctx, &store.MemoryEvent{
+			EventType: "reinforce",
+			FactID:    id,
+			OldValue:  "last_reinforced=" + fact.LastReinforced.UTC().Format(time.RFC3339),
+			NewValue:  "last_reinforced=" + now.Format(time.RFC3339),
+			Source:    maintenanceSource("reinforce_fact", agentID),
+		})
+
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"fact_id":         id,
+			"last_reinforced": now.Format(time.RFC3339),
+			"message":         fmt.Sprintf("Reinforced fact %d: %s %s %s", id, fact.Subject, fact.Predicate, fact.Object),
+		}, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func eventSource(t *testing.T, s store.Store, eventType string, id int64) string {
+	t.Helper()
+	var source string
+	err := s.(*store.SQLiteStore).QueryRowContext(context.Background(),
+		`SELECT source FROM memory_events WHERE event_type = ? AND fact_id = ? ORDER BY id DESC LIMIT 1`,
+		eventType, id).Scan(&source)
+	if err != nil {
+		t.Fatalf("reading %s event for %d: %v", eventType, id, err)
+	}
+	return source
+}
+
+func TestUpdateMemoryTool(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+	ctx := context.Background()
+
+	result := callTool(t, srv, "cortex_update_memory", map[string]interface{}{
+		"memory_id": float64(1),
+		"content":   "The wedding venue is Villa Cimbrone in Ravello, Italy",
+		"class":     "decision",
+		"extract":   false,
+		"agent_id":  "planner",
+	})
+	if result.IsError {
+		t.Fatalf("update failed: %s", getTextContent(t, result))
+	}
+	text := getTextContent(t, result)
+	if !strings.Contains(text, `"content"`) || !strings.Contains(text, `"memory_class"`) {
+		t.Fatalf("expected content and class changes, got %s", text)
+	}
+
+	m, err := s.GetMemory(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(m.Content, "Villa Cimbrone") || m.MemoryClass != "decision" {
+		t.Fatalf("memory not updated: %+v", m)
+	}
+	if got := eventSource(t, s, "update", 1); got != "mcp:update_memory:planner" {
+		t.Fatalf("audit source = %q", got)
+	}
+
+	result = callTool(t, srv, "cortex_update_memory", map[string]interface{}{"memory_id": float64(1)})
+	if !result.IsError {
+		t.Fatal("expected an error when nothing is being changed")
+	}
+	result = callTool(t, srv, "cortex_update_memory", map[string]interface{}{"memory_id": float64(99), "content": "x"})
+	if !result.IsError || !strings.Contains(getTextContent(t, result), "not found") {
+		t.Fatal("expected not-found error for a missing memory")
+	}
+}
+
+func TestSupersedeFactTool(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+	ctx := context.Background()
+
+	newID, err := s.AddFact(ctx, &store.Fact{MemoryID: 1, Subject: "wedding", Predicate: "venue", Object: "Villa Cimbrone",
+		FactType: "kv", Confidence: 0.9, DecayRate: 0.01, LastReinforced: time.Now().UTC()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := map[string]interface{}{"old_fact_id": float64(1), "new_fact_id": float64(newID), "reason": "venue changed", "agent_id": "planner"}
+	result := callTool(t, srv, "cortex_supersede_fact", args)
+	if result.IsError {
+		t.Fatalf("supersede failed: %s", getTextContent(t, result))
+	}
+	old, err := s.GetFact(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.SupersededBy == nil || *old.SupersededBy != newID {
+		t.Fatalf("fact 1 not superseded: %+v", old)
+	}
+	if got := eventSource(t, s, "update", 1); got != "mcp:supersede_fact:planner" {
+		t.Fatalf("audit source = %q", got)
+	}
+
+	result = callTool(t, srv, "cortex_supersede_fact", args)
+	if !result.IsError || !strings.Contains(getTextContent(t, result), "already superseded") {
+		t.Fatal("expected an error when superseding twice")
+	}
+}
+
+func TestReinforceFactTool(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:", AgentID: "planner"})
+
+	result := callTool(t, srv, "cortex_reinforce_fact", map[string]interface{}{"fact_id": float64(2)})
+	if result.IsError {
+		t.Fatalf("reinforce failed: %s", getTextContent(t, result))
+	}
+	if !strings.Contains(getTextContent(t, result), "cortex language Go") {
+		t.Fatalf("unexpected result: %s", getTextContent(t, result))
+	}
+	if got := eventSource(t, s, "reinforce", 2); got != "mcp:reinforce_fact:planner" {
+		t.Fatalf("audit source = %q", got)
+	}
+
+	result = callTool(t, srv, "cortex_reinforce_fact", map[string]interface{}{"fact_id": float64(42)})
+	if !result.IsError {
+		t.Fatal("expected an error for a missing fact")
+	}
+}
+
+func TestMaintenanceTools_ReadOnly(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:", ReadOnly: true})
+
+	for name, args := range map[string]map[string]interface{}{
+		"cortex_update_memory":  {"memory_id": float64(1), "content": "changed"},
+		"cortex_supersede_fact": {"old_fact_id": float64(1), "new_fact_id": float64(2)},
+		"cortex_reinforce_fact": {"fact_id": float64(1)},
+		"cortex_reinforce":      {"fact_ids": "1"},
+	} {
+		result := callTool(t, srv, name, args)
+		if !result.IsError || !strings.Contains(getTextContent(t, result), "read-only") {
+			t.Fatalf("%s should be refused in read-only mode", name)
+		}
+	}
+	m, _ := s.GetMemory(context.Background(), 1)
+	if m.Content == "changed" {
+		t.Fatal("read-only server modified a memory")
+	}
+}
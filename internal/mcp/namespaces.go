@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hurttlocker/cortex/internal/store"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// namespaceArgDescription documents the namespace argument every tool accepts.
+const namespaceArgDescription = `Namespace to read and write (default: the server's namespace; "*" reads every namespace, only on a server started with "*"). Lets one server keep several agents' memories apart.`
+
+// namespaceMiddleware scopes each tool call to its "namespace" argument, if
+// given, so store calls made with the request context stay in it. Only a
+// server that itself runs in store.AllNamespaces lets callers ask for "*";
+// otherwise one agent could read every other agent's memories.
+func namespaceMiddleware(serverNS string) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ns := req.GetString("namespace", "")
+			if ns == "" {
+				return next(ctx, req)
+			}
+			if err := store.ValidateNamespace(ns); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if store.NormalizeNamespace(ns) == store.AllNamespaces && serverNS != store.AllNamespaces {
+				return mcp.NewToolResultError(fmt.Sprintf("namespace %q is not allowed: this server runs in namespace %q", ns, serverNS)), nil
+			}
+			return next(store.WithNamespace(ctx, ns), req)
+		}
+	}
+}
+
+// factInRequestNamespace reports whether factID belongs to the request's
+// namespace. Store lookups by ID aren't scoped, so tools that write to a
+// fact by ID check this first.
+func factInRequestNamespace(ctx context.Context, st store.Store, factID int64) bool {
+	if sqlStore, ok := st.(*store.SQLiteStore); ok {
+		return sqlStore.FactInNamespace(ctx, factID)
+	}
+	return true
+}
+
+// addNamespaceArgument advertises the namespace argument on every
+// registered tool. Call it after all tools are registered.
+func addNamespaceArgument(s *server.MCPServer) {
+	var tools []server.ServerTool
+	for _, t := range s.ListTools() {
+		if t.Tool.RawInputSchema != nil {
+			continue
+		}
+		props := make(map[string]any, len(t.Tool.InputSchema.Properties)+1)
+		for k, v := range t.Tool.InputSchema.Properties {
+			props[k] = v
+		}
+		if _, taken := props["namespace"]; taken {
+			continue
+		}
+		props["namespace"] = map[string]any{"type": "string", "description": namespaceArgDescription}
+		t.Tool.InputSchema.Properties = props
+		tools = append(tools, *t)
+	}
+	s.AddTools(tools...)
+}
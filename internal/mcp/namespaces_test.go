@@ -0,0 +1,240 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestNamespaceArgumentScopesToolCalls(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	work := store.WithNamespace(context.Background(), "work")
+	if _, err := s.AddMemory(work, &store.Memory{
+		Content:    "The quarterly planning offsite is in Lisbon",
+		SourceFile: "work.md",
+		ImportedAt: time.Now().UTC(),
+		UpdatedAt:  time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("AddMemory: %v", err)
+	}
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+
+	searchFor := func(args map[string]interface{}) []search.Result {
+		t.Helper()
+		result := callTool(t, srv, "cortex_search", args)
+		if result.IsError {
+			t.Fatalf("cortex_search %v: %s", args, getTextContent(t, result))
+		}
+		var results []search.Result
+		if err := json.Unmarshal([]byte(getTextContent(t, result)), &results); err != nil {
+			t.Fatalf("parsing search results: %v", err)
+		}
+		return results
+	}
+
+	if got := searchFor(map[string]interface{}{"query": "Lisbon offsite"}); len(got) != 0 {
+		t.Fatalf("default namespace saw work memory: %+v", got)
+	}
+	got := searchFor(map[string]interface{}{"query": "Lisbon offsite", "namespace": "work"})
+	if len(got) == 0 || !strings.Contains(got[0].Content, "Lisbon") {
+		t.Fatalf("work namespace search = %+v", got)
+	}
+	if got := searchFor(map[string]interface{}{"query": "wedding venue", "namespace": "work"}); len(got) != 0 {
+		t.Fatalf("work namespace saw default memory: %+v", got)
+	}
+	result := callTool(t, srv, "cortex_search", map[string]interface{}{"query": "x", "namespace": "Not Valid!"})
+	if !result.IsError || !strings.Contains(getTextContent(t, result), "invalid namespace") {
+		t.Fatalf("invalid namespace: %+v", result)
+	}
+}
+
+func TestNamespaceArgumentAdvertisedOnEveryTool(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+
+	tools := srv.ListTools()
+	if len(tools) == 0 {
+		t.Fatal("no tools registered")
+	}
+	for name, tool := range tools {
+		if _, ok := tool.Tool.InputSchema.Properties["namespace"]; !ok {
+			t.Errorf("%s has no namespace argument", name)
+		}
+	}
+}
+
+func TestNamespaceArgumentGuardsCrossNamespaceAccess(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	ctx := context.Background()
+
+	memID, err := s.AddMemory(store.WithNamespace(ctx, "work"), &store.Memory{Content: "Release train leaves Thursday", SourceFile: "work.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	workFact, err := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "release", Predicate: "day", Object: "thursday",
+		FactType: "kv", Confidence: 0.9, DecayRate: 0.01, LastReinforced: time.Now().UTC()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+
+	result := callTool(t, srv, "cortex_search", map[string]interface{}{"query": "release", "namespace": "*"})
+	if !result.IsError || !strings.Contains(getTextContent(t, result), "not allowed") {
+		t.Fatalf(`"*" on a default-namespace server: %s`, getTextContent(t, result))
+	}
+
+	// Default-namespace callers can't write to work rows by ID, and work
+	// callers can't write to default rows.
+	for _, c := range []struct {
+		tool string
+		args map[string]interface{}
+	}{
+		{"cortex_update_memory", map[string]interface{}{"memory_id": float64(memID), "content": "hijacked"}},
+		{"cortex_update_memory", map[string]interface{}{"memory_id": float64(1), "content": "hijacked", "namespace": "work"}},
+		{"cortex_reinforce_fact", map[string]interface{}{"fact_id": float64(workFact)}},
+		{"cortex_supersede_fact", map[string]interface{}{"old_fact_id": float64(workFact), "new_fact_id": float64(1)}},
+		{"cortex_supersede_fact", map[string]interface{}{"old_fact_id": float64(1), "new_fact_id": float64(workFact)}},
+	} {
+		result := callTool(t, srv, c.tool, c.args)
+		if !result.IsError || !strings.Contains(getTextContent(t, result), "not found") {
+			t.Fatalf("%s %v crossed namespaces: %s", c.tool, c.args, getTextContent(t, result))
+		}
+	}
+	result = callTool(t, srv, "cortex_reinforce", map[string]interface{}{"fact_ids": strconv.FormatInt(workFact, 10)})
+	if !strings.Contains(getTextContent(t, result), `"reinforced": 0`) {
+		t.Fatalf("cortex_reinforce reached a work fact: %s", getTextContent(t, result))
+	}
+	if m, _ := s.GetMemory(ctx, memID); m.Content != "Release train leaves Thursday" {
+		t.Fatalf("work memory changed: %q", m.Content)
+	}
+	if f, _ := s.GetFact(ctx, workFact); f.SupersededBy != nil {
+		t.Fatal("work fact was superseded")
+	}
+
+	result = callTool(t, srv, "cortex_reinforce_fact", map[string]interface{}{"fact_id": float64(workFact), "namespace": "work"})
+	if result.IsError {
+		t.Fatalf("reinforcing in its own namespace: %s", getTextContent(t, result))
+	}
+
+	allStore, err := store.NewStore(store.StoreConfig{DBPath: ":memory:", Namespace: store.AllNamespaces})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer allStore.Close()
+	all := NewServer(ServerConfig{Store: allStore, DBPath: ":memory:"})
+	if _, err := allStore.AddMemory(store.WithNamespace(ctx, "work"), &store.Memory{Content: "Release train leaves Thursday", SourceFile: "work.md"}); err != nil {
+		t.Fatal(err)
+	}
+	result = callTool(t, all, "cortex_search", map[string]interface{}{"query": "release train", "namespace": "*"})
+	if result.IsError || !strings.Contains(getTextContent(t, result), "Thursday") {
+		t.Fatalf(`"*" on an all-namespace server: %s`, getTextContent(t, result))
+	}
+}
+
+func TestGraphToolsStayInNamespace(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	ctx := context.Background()
+	sqlStore := s.(*store.SQLiteStore)
+
+	memID, err := s.AddMemory(store.WithNamespace(ctx, "work"), &store.Memory{Content: "Release train leaves Thursday", SourceFile: "work.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	workFact, err := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "release", Predicate: "day", Object: "thursday",
+		FactType: "kv", Confidence: 0.9, DecayRate: 0.01, LastReinforced: time.Now().UTC()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An edge that already crosses namespaces (e.g. from before they were
+	// scoped) must not be walked.
+	if err := sqlStore.AddEdge(ctx, &store.FactEdge{SourceFactID: workFact, TargetFactID: 1, EdgeType: store.EdgeTypeRelatesTo, Confidence: 0.9, Source: store.EdgeSourceExplicit}); err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+
+	for _, c := range []struct {
+		tool string
+		args map[string]interface{}
+	}{
+		{"cortex_edge_add", map[string]interface{}{"source_fact_id": float64(1), "target_fact_id": float64(workFact), "edge_type": "supports"}},
+		{"cortex_edge_add", map[string]interface{}{"source_fact_id": float64(workFact), "target_fact_id": float64(2), "edge_type": "supports", "namespace": "work"}},
+	} {
+		result := callTool(t, srv, c.tool, c.args)
+		if !result.IsError || !strings.Contains(getTextContent(t, result), "not found") {
+			t.Fatalf("%s %v crossed namespaces: %s", c.tool, c.args, getTextContent(t, result))
+		}
+	}
+	// A start fact in another namespace has no graph, like a missing one.
+	for _, tool := range []string{"cortex_graph", "cortex_graph_export"} {
+		result := callTool(t, srv, tool, map[string]interface{}{"fact_id": float64(workFact)})
+		if text := getTextContent(t, result); result.IsError || strings.Contains(text, "release") || strings.Contains(text, "Villa Rosa") {
+			t.Fatalf("%s from work fact in default: %s", tool, text)
+		}
+	}
+	if edges, _ := sqlStore.FindEdges(store.WithNamespace(ctx, store.AllNamespaces), store.EdgeFilter{Type: store.EdgeTypeSupports}); len(edges) != 0 {
+		t.Fatalf("cross-namespace edges created: %+v", edges)
+	}
+
+	nodes, err := sqlStore.TraverseGraph(store.WithNamespace(ctx, "work"), workFact, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0].Fact.ID != workFact {
+		t.Fatalf("work traversal = %+v, want only fact %d", nodes, workFact)
+	}
+	result := callTool(t, srv, "cortex_graph", map[string]interface{}{"fact_id": float64(workFact), "namespace": "work"})
+	if result.IsError || strings.Contains(getTextContent(t, result), "Villa Rosa") {
+		t.Fatalf("work graph = %s", getTextContent(t, result))
+	}
+}
+
+func TestInferToolStaysInNamespace(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	ctx := context.Background()
+
+	memID, err := s.AddMemory(store.WithNamespace(ctx, "work"), &store.Memory{Content: "Work wedding venue notes", SourceFile: "work.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Shares subject and predicate with default fact 1.
+	workFact, err := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "wedding", Predicate: "venue", Object: "Harbor Hall",
+		FactType: "kv", Confidence: 0.9, DecayRate: 0.01, LastReinforced: time.Now().UTC()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+
+	for _, ns := range []string{"", "work"} {
+		args := map[string]interface{}{"dry_run": true}
+		if ns != "" {
+			args["namespace"] = ns
+		}
+		result := callTool(t, srv, "cortex_infer", args)
+		if result.IsError {
+			t.Fatalf("cortex_infer %q: %s", ns, getTextContent(t, result))
+		}
+		var out struct {
+			Proposals []store.EdgeProposal `json:"proposals"`
+		}
+		if err := json.Unmarshal([]byte(getTextContent(t, result)), &out); err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range out.Proposals {
+			if (p.SourceFactID == workFact) != (p.TargetFactID == workFact) {
+				t.Fatalf("cortex_infer %q proposed across namespaces: %+v", ns, p)
+			}
+		}
+	}
+}
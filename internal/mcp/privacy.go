@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// mcpHiddenProjects returns the projects whose privacy zone keeps them out
+// of MCP tools and resources.
+func mcpHiddenProjects() []string {
+	return store.ProjectsRestricting(store.PrivacyNoMCP)
+}
+
+// hideMCPFacts drops facts from no-mcp projects.
+func hideMCPFacts(ctx context.Context, st store.Store, facts []*store.Fact) ([]*store.Fact, error) {
+	sqlStore, ok := st.(*store.SQLiteStore)
+	if !ok {
+		return facts, nil
+	}
+	kept, _, err := sqlStore.FilterFactsByPrivacy(ctx, facts, store.PrivacyNoMCP)
+	return kept, err
+}
+
+// hideMCPGraphNodes drops graph nodes whose fact belongs to a no-mcp
+// project. Edges to hidden facts stay, so callers see that a neighbor exists
+// without its content.
+func hideMCPGraphNodes(ctx context.Context, sqlStore *store.SQLiteStore, nodes []store.GraphNode) ([]store.GraphNode, error) {
+	if len(mcpHiddenProjects()) == 0 {
+		return nodes, nil
+	}
+	facts := make([]*store.Fact, 0, len(nodes))
+	for _, n := range nodes {
+		if n.Fact != nil {
+			facts = append(facts, n.Fact)
+		}
+	}
+	visible, _, err := sqlStore.FilterFactsByPrivacy(ctx, facts, store.PrivacyNoMCP)
+	if err != nil {
+		return nil, err
+	}
+	keep := make(map[int64]bool, len(visible))
+	for _, f := range visible {
+		keep[f.ID] = true
+	}
+	kept := nodes[:0:0]
+	for _, n := range nodes {
+		if n.Fact != nil && keep[n.Fact.ID] {
+			kept = append(kept, n)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestNoMCPProjectsHidden(t *testing.T) {
+	si, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer si.Close()
+	s := si.(*store.SQLiteStore)
+	ctx := context.Background()
+
+	work, _ := s.AddMemory(ctx, &store.Memory{Content: "Quarterly budget review for the work roadmap", SourceFile: "work.md", Project: "work"})
+	private, _ := s.AddMemory(ctx, &store.Memory{Content: "Quarterly budget for the family vacation fund", SourceFile: "home.md", Project: "personal"})
+	s.AddFact(ctx, &store.Fact{MemoryID: work, Subject: "roadmap", Predicate: "budget", Object: "quarterly", FactType: "kv", Confidence: 0.9})
+	s.AddFact(ctx, &store.Fact{MemoryID: private, Subject: "vacation", Predicate: "budget", Object: "quarterly", FactType: "kv", Confidence: 0.9})
+
+	if err := s.SetProjectPrivacy(ctx, store.ProjectPrivacy{Project: "personal", NoMCP: true}); err != nil {
+		t.Fatalf("SetProjectPrivacy: %v", err)
+	}
+	defer s.SetProjectPrivacy(ctx, store.ProjectPrivacy{Project: "personal"})
+
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+
+	result := callTool(t, srv, "cortex_search", map[string]interface{}{"query": "quarterly budget", "mode": "keyword"})
+	var results []search.Result
+	if err := json.Unmarshal([]byte(getTextContent(t, result)), &results); err != nil {
+		t.Fatalf("parsing search results: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("expected the work memory")
+	}
+	for _, r := range results {
+		if r.Project == "personal" {
+			t.Fatalf("no-mcp memory returned by cortex_search: %+v", r)
+		}
+	}
+
+	result = callTool(t, srv, "cortex_facts", map[string]interface{}{"limit": float64(10)})
+	var facts []store.Fact
+	if err := json.Unmarshal([]byte(getTextContent(t, result)), &facts); err != nil {
+		t.Fatalf("parsing facts: %v", err)
+	}
+	if len(facts) != 1 || facts[0].Subject != "roadmap" {
+		t.Fatalf("expected only the work fact, got %+v", facts)
+	}
+
+	result = callTool(t, srv, "cortex_context", map[string]interface{}{"query": "quarterly budget"})
+	var pc search.PromptContext
+	if err := json.Unmarshal([]byte(getTextContent(t, result)), &pc); err != nil {
+		t.Fatalf("parsing context: %v", err)
+	}
+	for _, p := range pc.Passages {
+		if p.Project == "personal" {
+			t.Fatalf("no-mcp memory packed by cortex_context: %+v", p)
+		}
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strings"
 
+	"github.com/hurttlocker/cortex/internal/brief"
 	"github.com/hurttlocker/cortex/internal/store"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -70,6 +73,36 @@ func registerGraphSubjectsResource(s *server.MCPServer, st store.Store) {
 	})
 }
 
+// registerStatusResource exposes the same summary as the HTTP /status
+// endpoint, so supervising agents can fold memory health into their own
+// heartbeats without running the full stats resource.
+func registerStatusResource(s *server.MCPServer, st store.Store) {
+	resource := mcp.NewResource(
+		"cortex://status",
+		"Store Status",
+		mcp.WithResourceDescription("Lightweight heartbeat: memory and fact counts, last import and connector sync, pending conflicts, alert backlog, and a one-line summary."),
+		mcp.WithMIMEType("application/json"),
+	)
+
+	s.AddResource(resource, func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		dbMu.Lock()
+		defer dbMu.Unlock()
+
+		sqlStore, ok := st.(*store.SQLiteStore)
+		if !ok {
+			return nil, fmt.Errorf("status resource requires SQLiteStore")
+		}
+		status, err := sqlStore.Status(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("getting status: %w", err)
+		}
+		data, _ := json.MarshalIndent(status, "", "  ")
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "application/json", Text: string(data)},
+		}, nil
+	})
+}
+
 func registerGraphClustersResource(s *server.MCPServer, st store.Store) {
 	resource := mcp.NewResource(
 		"cortex://graph/clusters",
@@ -133,3 +166,68 @@ func registerGraphClustersResource(s *server.MCPServer, st store.Store) {
 		}, nil
 	})
 }
+
+// registerBriefResources exposes the stored hierarchical briefs so an agent
+// can bootstrap from one read: cortex://brief is the identity card plus every
+// project's domain brief, cortex://brief/{project} one domain with its
+// cluster briefs. Briefs are generated by `cortex brief`; reading them never
+// calls an LLM, so "stale" reports how many cluster briefs lag the facts.
+func registerBriefResources(s *server.MCPServer, st store.Store) {
+	resource := mcp.NewResource(
+		"cortex://brief",
+		"Brief",
+		mcp.WithResourceDescription("Store identity card and per-project domain briefs. Read this first to learn whose memory this is and what it covers."),
+		mcp.WithMIMEType("application/json"),
+	)
+	s.AddResource(resource, func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return readBriefResource(ctx, st, req.Params.URI, "")
+	})
+
+	template := mcp.NewResourceTemplate(
+		"cortex://brief/{project}",
+		"Project Brief",
+		mcp.WithTemplateDescription("One project's domain brief with its per-cluster briefs. Use \"(untagged)\" for memories without a project."),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.AddResourceTemplate(template, func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		project, err := url.PathUnescape(strings.TrimPrefix(req.Params.URI, "cortex://brief/"))
+		if err != nil || strings.TrimSpace(project) == "" {
+			return nil, fmt.Errorf("invalid brief URI: %s", req.Params.URI)
+		}
+		return readBriefResource(ctx, st, req.Params.URI, project)
+	})
+}
+
+func readBriefResource(ctx context.Context, st store.Store, uri, project string) ([]mcp.ResourceContents, error) {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+
+	sqlStore, ok := st.(*store.SQLiteStore)
+	if !ok {
+		return nil, fmt.Errorf("brief resource requires SQLiteStore")
+	}
+	card, err := brief.Load(ctx, sqlStore, project, false)
+	if err != nil {
+		return nil, fmt.Errorf("loading briefs: %w", err)
+	}
+	stale, err := brief.Stale(ctx, sqlStore, brief.Options{Project: project})
+	if err != nil {
+		return nil, fmt.Errorf("checking briefs: %w", err)
+	}
+
+	payload := map[string]interface{}{
+		"identity": card.Identity,
+		"domains":  card.Domains,
+		"stale":    stale,
+	}
+	if project != "" {
+		payload["project"] = project
+	}
+	if card.Identity == nil && len(card.Domains) == 0 {
+		payload["message"] = "no briefs yet; run `cortex brief` with an LLM configured"
+	}
+	data, _ := json.MarshalIndent(payload, "", "  ")
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: uri, MIMEType: "application/json", Text: string(data)},
+	}, nil
+}
@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerReviewTools exposes the low-confidence fact review queue:
+// cortex_review_list, cortex_review_approve, and cortex_review_reject. Unlike
+// directive proposals, approval is open to agents so a stronger model can
+// gate a weaker extractor's output. Input schemas are flat for OpenAI
+// strict-mode clients.
+func registerReviewTools(s *server.MCPServer, st store.Store) {
+	listTool := mcp.NewTool("cortex_review_list",
+		mcp.WithDescription("List LLM-extracted facts held for review because their confidence fell below extract.review_threshold. Queued facts are not searchable until approved. Lowest confidence first; defaults to pending."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("status",
+			mcp.Description("Which reviews to return: pending (default), approved, rejected, or all."),
+			mcp.Enum("pending", "approved", "rejected", "all"),
+		),
+		mcp.WithNumber("below",
+			mcp.Description("Only reviews with confidence below this value (0-1)."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum reviews to return (default 50)."),
+		),
+	)
+	s.AddTool(listTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		dbMu.Lock()
+		defer dbMu.Unlock()
+
+		sqlStore, ok := st.(*store.SQLiteStore)
+		if !ok {
+			return mcp.NewToolResultError("fact review requires SQLiteStore"), nil
+		}
+		opts := store.FactReviewListOpts{Limit: 50}
+		if status, err := req.RequireString("status"); err == nil && strings.TrimSpace(status) != "" {
+			opts.Status = status
+		}
+		if v, err := req.RequireFloat("below"); err == nil && v > 0 {
+			opts.BelowConfidence = v
+		}
+		if v, err := req.RequireFloat("limit"); err == nil && v > 0 {
+			opts.Limit = int(v)
+		}
+
+		reviews, err := sqlStore.ListFactReviews(ctx, opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("list reviews error: %v", err)), nil
+		}
+		if reviews == nil {
+			reviews = []*store.FactReview{}
+		}
+		data, _ := json.MarshalIndent(reviews, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+
+	approveTool := mcp.NewTool("cortex_review_approve",
+		mcp.WithDescription("Approve queued facts (from cortex_review_list) so they become active memory. Pass review IDs as comma-separated values. Returns the new fact IDs."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("review_ids",
+			mcp.Required(),
+			mcp.Description("Comma-separated review IDs to approve (e.g. '3,7,12')"),
+		),
+		mcp.WithString("reviewer",
+			mcp.Description("Who approved the facts (optional, recorded on the review)."),
+		),
+	)
+	s.AddTool(approveTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return resolveReviews(ctx, st, req, true)
+	})
+
+	rejectTool := mcp.NewTool("cortex_review_reject",
+		mcp.WithDescription("Reject queued facts (from cortex_review_list); nothing is written to memory. Pass review IDs as comma-separated values."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("review_ids",
+			mcp.Required(),
+			mcp.Description("Comma-separated review IDs to reject (e.g. '3,7,12')"),
+		),
+		mcp.WithString("reason",
+			mcp.Description("Why the facts were rejected (optional)."),
+		),
+		mcp.WithString("reviewer",
+			mcp.Description("Who rejected the facts (optional, recorded on the review)."),
+		),
+	)
+	s.AddTool(rejectTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return resolveReviews(ctx, st, req, false)
+	})
+}
+
+func resolveReviews(ctx context.Context, st store.Store, req mcp.CallToolRequest, approve bool) (*mcp.CallToolResult, error) {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+
+	sqlStore, ok := st.(*store.SQLiteStore)
+	if !ok {
+		return mcp.NewToolResultError("fact review requires SQLiteStore"), nil
+	}
+	idsStr, err := req.RequireString("review_ids")
+	if err != nil || strings.TrimSpace(idsStr) == "" {
+		return mcp.NewToolResultError("review_ids is required"), nil
+	}
+	reviewer, _ := req.RequireString("reviewer")
+	reason, _ := req.RequireString("reason")
+
+	var resolved []int64
+	var factIDs []int64
+	var errors []string
+	for _, part := range strings.Split(idsStr, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("invalid ID %q", part))
+			continue
+		}
+		if approve {
+			factID, err := sqlStore.ApproveFactReview(ctx, id, reviewer)
+			if err != nil {
+				errors = append(errors, err.Error())
+				continue
+			}
+			factIDs = append(factIDs, factID)
+		} else if err := sqlStore.RejectFactReview(ctx, id, reviewer, reason); err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+		resolved = append(resolved, id)
+	}
+
+	result := map[string]interface{}{}
+	if approve {
+		result["approved"] = resolved
+		result["fact_ids"] = factIDs
+		result["message"] = fmt.Sprintf("Approved %d fact(s)", len(resolved))
+	} else {
+		result["rejected"] = resolved
+		result["message"] = fmt.Sprintf("Rejected %d fact(s)", len(resolved))
+	}
+	if len(errors) > 0 {
+		result["errors"] = errors
+	}
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
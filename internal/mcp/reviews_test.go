@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestMCPReviewTools_ListApproveReject(t *testing.T) {
+	s, srv := setupProposalToolServer(t)
+	ctx := context.Background()
+
+	memID, err := s.AddMemory(ctx, &store.Memory{Content: "We might try Postgres next quarter", SourceFile: "plans.md"})
+	if err != nil {
+		t.Fatalf("AddMemory: %v", err)
+	}
+	var ids []int64
+	for _, obj := range []string{"Postgres", "next quarter"} {
+		id, err := s.QueueFactReview(ctx, &store.FactReview{MemoryID: memID, Subject: "team", Predicate: "might try", Object: obj, Confidence: 0.4, Method: "llm-enrich"})
+		if err != nil {
+			t.Fatalf("QueueFactReview: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	var listed []store.FactReview
+	text := getTextContent(t, callTool(t, srv, "cortex_review_list", map[string]interface{}{}))
+	if err := json.Unmarshal([]byte(text), &listed); err != nil || len(listed) != 2 {
+		t.Fatalf("expected 2 pending reviews, got %v / %s", err, text)
+	}
+
+	var approved struct {
+		Approved []int64 `json:"approved"`
+		FactIDs  []int64 `json:"fact_ids"`
+	}
+	text = getTextContent(t, callTool(t, srv, "cortex_review_approve", map[string]interface{}{"review_ids": fmt.Sprint(ids[0]), "reviewer": "gpt-judge"}))
+	if err := json.Unmarshal([]byte(text), &approved); err != nil || len(approved.FactIDs) != 1 {
+		t.Fatalf("unexpected approve result: %v / %s", err, text)
+	}
+	if f, _ := s.GetFact(ctx, approved.FactIDs[0]); f == nil || f.Object != "Postgres" {
+		t.Fatalf("approved review should become a fact, got %+v", f)
+	}
+
+	callTool(t, srv, "cortex_review_reject", map[string]interface{}{"review_ids": fmt.Sprintf("%d, 9999", ids[1]), "reason": "speculative"})
+	r, _ := s.GetFactReview(ctx, ids[1])
+	if r == nil || r.Status != store.FactReviewRejected || r.Reason != "speculative" {
+		t.Fatalf("expected rejected review, got %+v", r)
+	}
+	if n, _ := s.CountPendingFactReviews(ctx); n != 0 {
+		t.Fatalf("expected empty queue, got %d pending", n)
+	}
+}
@@ -12,14 +12,17 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/hurttlocker/cortex/internal/answer"
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
 	"github.com/hurttlocker/cortex/internal/connect"
 	"github.com/hurttlocker/cortex/internal/embed"
 	"github.com/hurttlocker/cortex/internal/extract"
+	"github.com/hurttlocker/cortex/internal/jobs"
 	"github.com/hurttlocker/cortex/internal/observe"
 	"github.com/hurttlocker/cortex/internal/reason"
 	"github.com/hurttlocker/cortex/internal/search"
@@ -35,6 +38,35 @@ type ServerConfig struct {
 	Version  string         // version string for MCP server info
 	Embedder embed.Embedder // optional, for semantic/hybrid search
 	AgentID  string         // if set, all operations are scoped to this agent
+
+	// SearchProfiles are the named filter sets cortex_search accepts via its
+	// profile parameter (search.profiles in config).
+	SearchProfiles cfgresolver.SearchProfiles
+
+	// SearchFreshness, if set, applies per-class memory recency decay to
+	// cortex_search ranking (search.freshness in config).
+	SearchFreshness *search.Freshness
+
+	// SearchGraphBoost is the graph proximity boost weight for cortex_search
+	// (search.graph_boost in config).
+	SearchGraphBoost float64
+
+	// Jobs runs background work for cortex_import with background=true and
+	// the cortex_job_* tools. NewServer sets its Locker (if unset) so job
+	// writes serialize with tool calls, so start it after NewServer. If nil,
+	// NewServer builds a runner with the built-in handlers (no LLM
+	// enrichment) that starts on first use.
+	Jobs *jobs.Runner
+
+	// ReadOnly disables the write tools (cortex_update_memory,
+	// cortex_supersede_fact, cortex_reinforce, cortex_reinforce_fact,
+	// cortex_edge_remove, cortex_infer with dry_run=false, and cortex_resolve
+	// unless dry_run).
+	ReadOnly bool
+
+	// SearchEngine, if set, is used instead of an engine built from Store and
+	// Embedder — e.g. one whose HNSW index is warming up in the background.
+	SearchEngine *search.Engine
 }
 
 // dbMu serializes all MCP tool calls that touch the database.
@@ -56,11 +88,15 @@ func NewServer(cfg ServerConfig) *server.MCPServer {
 		ver,
 		server.WithToolCapabilities(false),
 		server.WithResourceCapabilities(true, false),
+		server.WithToolHandlerMiddleware(namespaceMiddleware(store.EffectiveNamespace(context.Background(), cfg.Store))),
 	)
 
-	searchEngine := search.NewEngine(cfg.Store)
-	if cfg.Embedder != nil {
-		searchEngine = search.NewEngineWithEmbedder(cfg.Store, cfg.Embedder)
+	searchEngine := cfg.SearchEngine
+	if searchEngine == nil {
+		searchEngine = search.NewEngine(cfg.Store)
+		if cfg.Embedder != nil {
+			searchEngine = search.NewEngineWithEmbedder(cfg.Store, cfg.Embedder)
+		}
 	}
 
 	dbPath := cfg.DBPath
@@ -73,15 +109,20 @@ func NewServer(cfg ServerConfig) *server.MCPServer {
 	defaultAgent := cfg.AgentID
 
 	// Register tools
-	registerSearchTool(s, searchEngine, defaultAgent)
+	registerSearchTool(s, searchEngine, defaultAgent, cfg.SearchProfiles, cfg.SearchFreshness, cfg.SearchGraphBoost)
 	registerAnswerTool(s, searchEngine, defaultAgent)
-	registerImportTool(s, cfg.Store, defaultAgent)
+	registerContextTool(s, searchEngine, defaultAgent)
+	jobRunner := serverJobRunner(cfg)
+	registerImportTool(s, cfg.Store, defaultAgent, jobRunner)
 	registerStatsTool(s, observeEngine)
 	registerFactsTool(s, cfg.Store, defaultAgent)
 	registerStaleTool(s, observeEngine)
-	registerReinforceTool(s, cfg.Store)
+	registerReinforceTool(s, cfg.Store, cfg.ReadOnly)
 	registerReasonTool(s, searchEngine, cfg.Store)
 	registerEdgeAddTool(s, cfg.Store)
+	registerEdgeListTool(s, cfg.Store)
+	registerEdgeRemoveTool(s, cfg.Store, cfg.ReadOnly)
+	registerInferTool(s, cfg.Store, cfg.ReadOnly)
 	registerGraphTool(s, cfg.Store)
 	registerGraphExportTool(s, cfg.Store)
 	registerGraphExploreTool(s, cfg.Store)
@@ -89,6 +130,22 @@ func NewServer(cfg ServerConfig) *server.MCPServer {
 	registerListClustersTool(s, cfg.Store)
 	registerLedgerRecordTool(s, cfg.Store)
 
+	// Background jobs: start, inspect, and cancel long-running work.
+	if jobRunner != nil {
+		registerJobStartTool(s, jobRunner, cfg.ReadOnly)
+		registerJobStatusTool(s, jobRunner)
+		registerJobCancelTool(s, jobRunner, cfg.ReadOnly)
+	}
+
+	// Memory maintenance: edit memories, supersede and reinforce facts.
+	registerUpdateMemoryTool(s, cfg.Store, defaultAgent, cfg.ReadOnly)
+	registerSupersedeFactTool(s, cfg.Store, defaultAgent, cfg.ReadOnly)
+	registerReinforceFactTool(s, cfg.Store, defaultAgent, cfg.ReadOnly)
+
+	// Subject subscriptions: notify agents when knowledge about a subject changes.
+	registerWatchSubjectTool(s, cfg.Store, defaultAgent, cfg.ReadOnly)
+	pushSubscriptionAlerts(s, cfg.Store)
+
 	// Governance directives (v2 M1)
 	registerDirectiveAddTool(s, cfg.Store)
 	registerDirectiveListTool(s, cfg.Store)
@@ -96,6 +153,13 @@ func NewServer(cfg ServerConfig) *server.MCPServer {
 	// Directive proposals (v2 M3) — read-only; accept/dismiss/scan stay CLI-only.
 	registerProposeListTool(s, cfg.Store)
 
+	// Low-confidence fact review queue.
+	registerReviewTools(s, cfg.Store)
+
+	// Contradictions: list them and resolve by keep/drop, strategy, or LLM.
+	registerConflictsTool(s, cfg.Store, observeEngine)
+	registerResolveTool(s, cfg.Store, observeEngine, cfg.ReadOnly)
+
 	// Register connector management tools
 	if sqlStore, ok := cfg.Store.(*store.SQLiteStore); ok {
 		connStore := connect.NewConnectorStore(sqlStore.GetDB())
@@ -105,18 +169,23 @@ func NewServer(cfg ServerConfig) *server.MCPServer {
 		registerConnectStatusTool(s, connStore)
 	}
 
+	// Every tool takes an optional namespace (see namespaceMiddleware).
+	addNamespaceArgument(s)
+
 	// Register resources
 	registerStatsResource(s, observeEngine)
+	registerStatusResource(s, cfg.Store)
 	registerRecentResource(s, cfg.Store)
 	registerGraphSubjectsResource(s, cfg.Store)
 	registerGraphClustersResource(s, cfg.Store)
+	registerBriefResources(s, cfg.Store)
 
 	return s
 }
 
 // --- Tools ---
 
-func registerSearchTool(s *server.MCPServer, engine *search.Engine, defaultAgent string) {
+func registerSearchTool(s *server.MCPServer, engine *search.Engine, defaultAgent string, profiles cfgresolver.SearchProfiles, freshness *search.Freshness, graphBoost float64) {
 	tool := mcp.NewTool("cortex_search",
 		mcp.WithDescription("Search your memory for information. Use when you need to recall past decisions, facts, preferences, or context. Returns ranked results with confidence scores and source provenance. Default mode is hybrid (keyword + semantic); use 'bm25' for exact keyword matching. NOT for exploring relationships between topics (use cortex_graph_explore) or synthesizing answers (use cortex_reason)."),
 		mcp.WithReadOnlyHintAnnotation(true),
@@ -128,19 +197,37 @@ func registerSearchTool(s *server.MCPServer, engine *search.Engine, defaultAgent
 		mcp.WithBoolean("facts",
 			mcp.Description("When true, return direct fact hits instead of memory chunks."),
 		),
+		mcp.WithBoolean("expand_summaries",
+			mcp.Description("With facts=true, list the original facts behind cluster-summary hits (drill-down)."),
+		),
 		mcp.WithString("mode",
-			mcp.Description("Search mode: bm25, semantic, hybrid, or rrf (default: keyword)"),
-			mcp.Enum("keyword", "bm25", "semantic", "hybrid", "rrf"),
+			mcp.Description("Search mode: bm25, semantic, hybrid, rrf, or auto (keyword for IDs, quoted phrases, and short queries; hybrid otherwise) (default: keyword)"),
+			mcp.Enum("keyword", "bm25", "semantic", "hybrid", "rrf", "auto"),
 		),
 		mcp.WithNumber("limit",
 			mcp.Description("Maximum number of results (default: 10, max: 50)"),
 		),
+		mcp.WithNumber("offset",
+			mcp.Description("Skip this many ranked results (max 1000). When offset or cursor is given, the response is {results, next_cursor}."),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("Continue from next_cursor of a previous page. Pages stay stable when new memories are written between calls."),
+		),
+		mcp.WithBoolean("highlight",
+			mcp.Description("When true, each result's snippet is the passage around the query terms, with matches wrapped in <mark></mark>."),
+		),
+		mcp.WithNumber("snippet_tokens",
+			mcp.Description("Snippet window in tokens for highlight (default 32, max 64). Implies highlight."),
+		),
 		mcp.WithString("project",
 			mcp.Description("Scope search to a specific project (e.g., 'trading', 'eyes-web'). Empty = search all."),
 		),
 		mcp.WithString("agent_id",
 			mcp.Description("Filter and boost results for a specific agent (e.g., 'mister', 'hawk'). Agent's facts rank higher; global facts still visible."),
 		),
+		mcp.WithString("profile",
+			mcp.Description("Saved search profile from config (search.profiles), e.g. 'trading-ops'. Fills project, classes, source, intent, mode, and boosts not given explicitly. 'agent-context' ranks for prompt injection: pinned rules, fresh status, and high-confidence decisions first, transcripts last."),
+		),
 		mcp.WithString("source",
 			mcp.Description("Filter results by source prefix (e.g., 'github', 'gmail'). Matches connector-imported records by provider name."),
 		),
@@ -172,6 +259,19 @@ func registerSearchTool(s *server.MCPServer, engine *search.Engine, defaultAgent
 		}
 
 		opts := search.DefaultOptions()
+		opts.Freshness = freshness
+		opts.GraphBoost = graphBoost
+		opts.ExcludeProjects = mcpHiddenProjects()
+
+		var profile *cfgresolver.SearchProfile
+		if name, err := req.RequireString("profile"); err == nil && strings.TrimSpace(name) != "" {
+			p, err := search.LookupProfile(profiles, name)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			profile = &p
+			opts.Mode = "" // let the profile pick unless mode is given
+		}
 
 		if modeStr, err := req.RequireString("mode"); err == nil && modeStr != "" {
 			mode, err := search.ParseMode(modeStr)
@@ -214,8 +314,19 @@ func registerSearchTool(s *server.MCPServer, engine *search.Engine, defaultAgent
 		} else {
 			return mcp.NewToolResultError(fmt.Sprintf("invalid source_boosts: %v", err)), nil
 		}
+		if profile != nil {
+			if err := search.ApplyProfile(&opts, *profile); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid profile: %v", err)), nil
+			}
+			if opts.Mode == "" {
+				opts.Mode = search.ModeKeyword
+			}
+		}
 
 		if factsMode, err := req.RequireBool("facts"); err == nil && factsMode {
+			if expand, err := req.RequireBool("expand_summaries"); err == nil {
+				opts.ExpandSummaries = expand
+			}
 			results, err := engine.SearchFacts(ctx, query, opts)
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("search error: %v", err)), nil
@@ -224,16 +335,53 @@ func registerSearchTool(s *server.MCPServer, engine *search.Engine, defaultAgent
 			return mcp.NewToolResultText(string(data)), nil
 		}
 
+		highlight, _ := req.RequireBool("highlight")
+		if tokensVal, err := req.RequireFloat("snippet_tokens"); err == nil {
+			if err := search.ValidateSnippetTokens(int(tokensVal)); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			opts.Highlight = &search.Highlight{Style: search.HighlightMark, Tokens: int(tokensVal)}
+		} else if highlight {
+			opts.Highlight = &search.Highlight{Style: search.HighlightMark}
+		}
+
+		paged := hasMCPArg(req, "offset") || hasMCPArg(req, "cursor")
+		if paged {
+			if offsetVal, err := req.RequireFloat("offset"); err == nil && offsetVal > 0 {
+				opts.Offset = int(offsetVal)
+			}
+			if cursor, err := req.RequireString("cursor"); err == nil {
+				opts.Cursor = strings.TrimSpace(cursor)
+			}
+		}
+
 		results, err := engine.Search(ctx, query, opts)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("search error: %v", err)), nil
 		}
 
+		if paged {
+			if results == nil {
+				results = []search.Result{}
+			}
+			data, _ := json.MarshalIndent(map[string]any{
+				"results":     results,
+				"next_cursor": opts.NextCursor(results),
+			}, "", "  ")
+			return mcp.NewToolResultText(string(data)), nil
+		}
 		data, _ := json.MarshalIndent(results, "", "  ")
 		return mcp.NewToolResultText(string(data)), nil
 	})
 }
 
+// hasMCPArg reports whether the caller passed the named argument at all.
+// Paging arguments switch tools to an envelope response, so absence matters.
+func hasMCPArg(req mcp.CallToolRequest, name string) bool {
+	v, ok := req.GetArguments()[name]
+	return ok && v != nil
+}
+
 type mcpSourceBoost struct {
 	Prefix string  `json:"prefix"`
 	Weight float64 `json:"weight,omitempty"`
@@ -311,7 +459,7 @@ func registerAnswerTool(s *server.MCPServer, searchEngine *search.Engine, defaul
 			return mcp.NewToolResultError("query is required"), nil
 		}
 
-		sopts := search.Options{Mode: search.ModeHybrid, Limit: 5}
+		sopts := search.Options{Mode: search.ModeHybrid, Limit: 5, ExcludeProjects: mcpHiddenProjects()}
 		if modeStr, err := req.RequireString("mode"); err == nil && modeStr != "" {
 			mode, err := search.ParseMode(modeStr)
 			if err != nil {
@@ -386,9 +534,72 @@ func registerAnswerTool(s *server.MCPServer, searchEngine *search.Engine, defaul
 	})
 }
 
-func registerImportTool(s *server.MCPServer, st store.Store, defaultAgent string) {
+func registerContextTool(s *server.MCPServer, searchEngine *search.Engine, defaultAgent string) {
+	tool := mcp.NewTool("cortex_context",
+		mcp.WithDescription("Retrieve memories packed into a token budget for your own prompt: deduplicated passages in rank order, each headed by a citation marker like [1], plus the rendered text and token counts. No LLM call, no memory writes."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("query", mcp.Required(), mcp.Description("What the prompt needs context about")),
+		mcp.WithNumber("max_tokens", mcp.Description("Token budget for the passages (default 2000, max 32000)")),
+		mcp.WithNumber("max_passages", mcp.Description("Max passages (optional)")),
+		mcp.WithString("mode", mcp.Description("Search mode (default: keyword)"), mcp.Enum("keyword", "bm25", "semantic", "hybrid", "rrf", "auto")),
+		mcp.WithString("project", mcp.Description("Project scope (optional)")),
+		mcp.WithString("agent_id", mcp.Description("Agent scope/filter (optional)")),
+		mcp.WithString("source", mcp.Description("Filter by source key, display name, or type (optional)")),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		dbMu.Lock()
+		defer dbMu.Unlock()
+
+		query, err := req.RequireString("query")
+		if err != nil || strings.TrimSpace(query) == "" {
+			return mcp.NewToolResultError("query is required"), nil
+		}
+
+		budget := search.TokenBudget{Search: search.Options{Mode: search.ModeKeyword, MinScore: -1, ExcludeProjects: mcpHiddenProjects()}}
+		if v, err := req.RequireFloat("max_tokens"); err == nil && v > 0 {
+			budget.MaxTokens = int(v)
+			if budget.MaxTokens > 32000 {
+				budget.MaxTokens = 32000
+			}
+		}
+		if v, err := req.RequireFloat("max_passages"); err == nil && v > 0 {
+			budget.MaxPassages = int(v)
+		}
+		if modeStr, err := req.RequireString("mode"); err == nil && modeStr != "" {
+			mode, err := search.ParseMode(modeStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid mode: %v", err)), nil
+			}
+			budget.Search.Mode = mode
+		}
+		if project, err := req.RequireString("project"); err == nil && project != "" {
+			budget.Search.Project = project
+		}
+		if agentID, err := req.RequireString("agent_id"); err == nil && agentID != "" {
+			budget.Search.Agent = agentID
+			budget.Search.BoostAgent = agentID
+		} else if defaultAgent != "" {
+			budget.Search.Agent = defaultAgent
+			budget.Search.BoostAgent = defaultAgent
+		}
+		if source, err := req.RequireString("source"); err == nil && source != "" {
+			budget.Search.Source = source
+		}
+
+		pc, err := searchEngine.RetrieveForPrompt(ctx, query, budget)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("context error: %v", err)), nil
+		}
+		data, _ := json.MarshalIndent(pc, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
+
+func registerImportTool(s *server.MCPServer, st store.Store, defaultAgent string, runner *jobs.Runner) {
 	tool := mcp.NewTool("cortex_import",
-		mcp.WithDescription("Save new information to memory. Use when the user shares important facts, decisions, preferences, or context worth remembering. Content is chunked automatically. Set extract=true to pull out structured facts (people, dates, configs, decisions). Returns the IDs of created memories."),
+		mcp.WithDescription("Save new information to memory. Use when the user shares important facts, decisions, preferences, or context worth remembering. Content is chunked automatically. Set extract=true to pull out structured facts (people, dates, configs, decisions); add background=true to return immediately and extract in the background (check progress with cortex_job_status). Returns the IDs of created memories."),
 		mcp.WithReadOnlyHintAnnotation(false),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithString("content",
@@ -401,6 +612,12 @@ func registerImportTool(s *server.MCPServer, st store.Store, defaultAgent string
 		mcp.WithBoolean("extract",
 			mcp.Description("Extract facts from imported content using rule-based extraction (default: false)"),
 		),
+		mcp.WithBoolean("background",
+			mcp.Description("With extract=true, queue extraction and return a job_id instead of waiting (default: false)"),
+		),
+		mcp.WithBoolean("enrich",
+			mcp.Description("With background extraction, also ask the server's LLM for facts the rules missed (ignored when no LLM is configured)"),
+		),
 		mcp.WithString("project",
 			mcp.Description("Project tag for imported memories (e.g., 'trading', 'eyes-web'). Empty = untagged."),
 		),
@@ -435,10 +652,9 @@ func registerImportTool(s *server.MCPServer, st store.Store, defaultAgent string
 			}
 		}
 
-		enableExtract := false
-		if ext, err := req.RequireString("extract"); err == nil {
-			enableExtract = ext == "true"
-		}
+		enableExtract := boolArg(req, "extract")
+		background := boolArg(req, "background")
+		enrich := boolArg(req, "enrich")
 
 		project := ""
 		if p, err := req.RequireString("project"); err == nil && p != "" {
@@ -456,12 +672,13 @@ func registerImportTool(s *server.MCPServer, st store.Store, defaultAgent string
 		var ids []int64
 		for i, chunk := range chunks {
 			mem := &store.Memory{
-				Content:    chunk,
-				SourceFile: source,
-				SourceLine: i + 1,
-				Project:    project,
-				ImportedAt: time.Now().UTC(),
-				UpdatedAt:  time.Now().UTC(),
+				Content:     chunk,
+				SourceFile:  source,
+				SourceLine:  i + 1,
+				Project:     project,
+				MemoryClass: store.DefaultClassForSource(source),
+				ImportedAt:  time.Now().UTC(),
+				UpdatedAt:   time.Now().UTC(),
 			}
 			if agentID != "" {
 				mem.Metadata = &store.Metadata{AgentID: agentID}
@@ -478,12 +695,6 @@ func registerImportTool(s *server.MCPServer, st store.Store, defaultAgent string
 			ids = append(ids, id)
 		}
 
-		// Extract facts if requested
-		factsExtracted := 0
-		if enableExtract && len(ids) > 0 {
-			factsExtracted = extractFactsFromMemories(ctx, st, ids)
-		}
-
 		result := map[string]interface{}{
 			"ids":     ids,
 			"chunks":  len(chunks),
@@ -491,8 +702,30 @@ func registerImportTool(s *server.MCPServer, st store.Store, defaultAgent string
 			"source":  source,
 			"message": fmt.Sprintf("Imported %d memory chunk(s)", len(ids)),
 		}
-		if enableExtract {
-			result["facts_extracted"] = factsExtracted
+
+		// Extract facts if requested, inline or on the background queue
+		switch {
+		case !enableExtract:
+		case background && len(ids) > 0 && runner != nil:
+			kind := jobs.KindExtract
+			if enrich && hasJobKind(runner, jobs.KindEnrich) {
+				kind = jobs.KindEnrich
+			} else if enrich {
+				result["enrich_skipped"] = "no LLM configured for the MCP server"
+			}
+			runner.Start(context.Background())
+			job, err := runner.Enqueue(ctx, kind, jobs.ExtractParams{MemoryIDs: ids}, "mcp")
+			if err != nil {
+				result["extraction_error"] = err.Error()
+				break
+			}
+			result["extraction_job"] = job.ID
+			result["message"] = fmt.Sprintf("Imported %d memory chunk(s); extraction queued as job %d", len(ids), job.ID)
+		default:
+			result["facts_extracted"] = 0
+			if len(ids) > 0 {
+				result["facts_extracted"] = extractFactsFromMemories(ctx, st, ids)
+			}
 		}
 		data, _ := json.MarshalIndent(result, "", "  ")
 		return mcp.NewToolResultText(string(data)), nil
@@ -534,6 +767,12 @@ func registerFactsTool(s *server.MCPServer, st store.Store, defaultAgent string)
 		mcp.WithNumber("limit",
 			mcp.Description("Maximum number of facts to return (default: 20, max: 100)"),
 		),
+		mcp.WithNumber("offset",
+			mcp.Description("Skip this many facts. When offset or cursor is given, the response is {facts, next_cursor}."),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("Continue from next_cursor of a previous page. Newly extracted facts never shift later pages."),
+		),
 		mcp.WithString("agent_id",
 			mcp.Description("Filter facts by agent (returns agent's facts + global facts). Empty = all facts."),
 		),
@@ -565,6 +804,16 @@ func registerFactsTool(s *server.MCPServer, st store.Store, defaultAgent string)
 			opts.Agent = defaultAgent
 		}
 
+		paged := hasMCPArg(req, "offset") || hasMCPArg(req, "cursor")
+		if paged {
+			if offsetVal, err := req.RequireFloat("offset"); err == nil && offsetVal > 0 {
+				opts.Offset = int(offsetVal)
+			}
+			if cursor, err := req.RequireString("cursor"); err == nil {
+				opts.Cursor = strings.TrimSpace(cursor)
+			}
+		}
+
 		facts, err := st.ListFacts(ctx, opts)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("facts error: %v", err)), nil
@@ -586,7 +835,26 @@ func registerFactsTool(s *server.MCPServer, st store.Store, defaultAgent string)
 		} else {
 			filtered = facts
 		}
+		if filtered, err = hideMCPFacts(ctx, st, filtered); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("facts error: %v", err)), nil
+		}
 
+		if paged {
+			// The cursor follows the unfiltered page so a subject filter
+			// never skips facts on the next call.
+			nextCursor := ""
+			if len(facts) == opts.Limit {
+				nextCursor = store.FactsCursor(opts.SortBy, facts[len(facts)-1])
+			}
+			if filtered == nil {
+				filtered = []*store.Fact{}
+			}
+			data, _ := json.MarshalIndent(map[string]any{
+				"facts":       filtered,
+				"next_cursor": nextCursor,
+			}, "", "  ")
+			return mcp.NewToolResultText(string(data)), nil
+		}
 		data, _ := json.MarshalIndent(filtered, "", "  ")
 		return mcp.NewToolResultText(string(data)), nil
 	})
@@ -642,7 +910,7 @@ func registerStaleTool(s *server.MCPServer, engine *observe.Engine) {
 	})
 }
 
-func registerReinforceTool(s *server.MCPServer, st store.Store) {
+func registerReinforceTool(s *server.MCPServer, st store.Store, readOnly bool) {
 	tool := mcp.NewTool("cortex_reinforce",
 		mcp.WithDescription("Reset the decay timer on important facts to keep them fresh. Pass fact IDs (from cortex_stale or cortex_facts) as comma-separated values. Use when a fact has been confirmed as still accurate and relevant. Returns count of reinforced facts."),
 		mcp.WithReadOnlyHintAnnotation(false),
@@ -654,6 +922,9 @@ func registerReinforceTool(s *server.MCPServer, st store.Store) {
 	)
 
 	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if res := readOnlyGuard(readOnly, "cortex_reinforce"); res != nil {
+			return res, nil
+		}
 		dbMu.Lock()
 		defer dbMu.Unlock()
 
@@ -680,6 +951,10 @@ func registerReinforceTool(s *server.MCPServer, st store.Store) {
 				errors = append(errors, fmt.Sprintf("invalid ID %q", part))
 				continue
 			}
+			if !factInRequestNamespace(ctx, st, id) {
+				errors = append(errors, fmt.Sprintf("fact %d: not found", id))
+				continue
+			}
 			if err := st.ReinforceFact(ctx, id); err != nil {
 				errors = append(errors, fmt.Sprintf("fact %d: %v", id, err))
 				continue
@@ -702,9 +977,85 @@ func registerReinforceTool(s *server.MCPServer, st store.Store) {
 
 // --- Resources ---
 
+// Reason context budget, in tokens, for the memories and facts sent to the
+// LLM. The engine budgets characters, at roughly 4 per token.
+const (
+	defaultReasonBudget = 2000
+	maxReasonBudget     = 32000
+)
+
+// reasonConfigDir is where custom reason presets (presets.yaml, presets/) live.
+func reasonConfigDir() string {
+	if h, err := os.UserHomeDir(); err == nil {
+		return h + "/.cortex"
+	}
+	return ""
+}
+
+// reasonPresetNames lists builtin and custom preset names, sorted.
+func reasonPresetNames(configDir string) []string {
+	seen := map[string]bool{}
+	for name := range reason.BuiltinPresets {
+		seen[name] = true
+	}
+	if custom, err := reason.LoadCustomPresets(configDir); err == nil {
+		for name := range custom {
+			seen[name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseReasonRequest reads the cortex_reason arguments into reason options
+// and the model to use, checking the preset exists before any LLM is set up.
+func parseReasonRequest(req mcp.CallToolRequest, configDir string) (reason.ReasonOptions, string, error) {
+	query, err := req.RequireString("query")
+	if err != nil || strings.TrimSpace(query) == "" {
+		return reason.ReasonOptions{}, "", fmt.Errorf("query is required")
+	}
+	opts := reason.ReasonOptions{Query: query, Preset: "daily-digest", ContextTokens: defaultReasonBudget}
+
+	if p, err := req.RequireString("preset"); err == nil && p != "" {
+		opts.Preset = p
+	}
+	if _, err := reason.GetPreset(opts.Preset, configDir); err != nil {
+		return reason.ReasonOptions{}, "", err
+	}
+	if p, err := req.RequireString("project"); err == nil && p != "" {
+		opts.Project = p
+	}
+	if budget, err := req.RequireFloat("budget"); err == nil {
+		if budget < 100 || budget > maxReasonBudget {
+			return reason.ReasonOptions{}, "", fmt.Errorf("budget must be between 100 and %d tokens", maxReasonBudget)
+		}
+		opts.ContextTokens = int(budget)
+	}
+	if maxTokens, err := req.RequireFloat("max_tokens"); err == nil && maxTokens > 0 {
+		opts.MaxTokens = int(maxTokens)
+	}
+	if noCache, err := req.RequireBool("no_cache"); err == nil {
+		opts.NoCache = noCache
+	}
+
+	// Smart defaults: deepseek for deep analysis, gemini for interactive
+	modelStr := reason.DefaultInteractiveModel
+	if m, err := req.RequireString("model"); err == nil && m != "" {
+		modelStr = m
+	} else if opts.Preset == "weekly-dive" || opts.Preset == "fact-audit" {
+		modelStr = reason.DefaultCronModel
+	}
+	return opts, modelStr, nil
+}
+
 func registerReasonTool(s *server.MCPServer, searchEngine *search.Engine, st store.Store) {
+	configDir := reasonConfigDir()
 	tool := mcp.NewTool("cortex_reason",
-		mcp.WithDescription("Synthesize an answer by reasoning over multiple memories. Searches for relevant context, weighs by confidence, and produces a narrative answer. Use for complex questions that need multiple facts combined, not simple lookups (use cortex_search for those). Requires an LLM API key. Returns a reasoned analysis with citations."),
+		mcp.WithDescription("Synthesize an answer by reasoning over multiple memories. Searches for relevant context, weighs by confidence, and produces a narrative answer. Use for complex questions that need multiple facts combined, not simple lookups (use cortex_search for those). Requires an LLM API key. Returns the analysis with model, token, and timing details plus citations for the memories it was given."),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithString("query",
@@ -712,7 +1063,7 @@ func registerReasonTool(s *server.MCPServer, searchEngine *search.Engine, st sto
 			mcp.Description("The question or topic to reason about"),
 		),
 		mcp.WithString("preset",
-			mcp.Description("Reasoning preset: daily-digest, fact-audit, weekly-dive, conflict-check, agent-review (default: daily-digest)"),
+			mcp.Description("Reasoning preset: "+strings.Join(reasonPresetNames(configDir), ", ")+" (default: daily-digest). Custom presets come from ~/.cortex/presets.yaml."),
 		),
 		mcp.WithString("model",
 			mcp.Description("LLM model to use (e.g., 'google/gemini-2.5-flash', 'deepseek/deepseek-v3.2', 'phi4-mini'). Default: auto-selects based on preset."),
@@ -720,40 +1071,26 @@ func registerReasonTool(s *server.MCPServer, searchEngine *search.Engine, st sto
 		mcp.WithString("project",
 			mcp.Description("Scope reasoning to a specific project (e.g., 'trading', 'wedding'). Empty = all."),
 		),
+		mcp.WithNumber("budget",
+			mcp.Description(fmt.Sprintf("Context budget in tokens for the memories and facts sent to the LLM (default: %d, range 100-%d)", defaultReasonBudget, maxReasonBudget)),
+		),
+		mcp.WithNumber("max_tokens",
+			mcp.Description("Maximum tokens in the response (default: the preset's max_tokens)"),
+		),
+		mcp.WithBoolean("no_cache",
+			mcp.Description("Always call the LLM. By default an identical request over an unchanged store returns the cached answer (\"cached\": true)."),
+		),
 	)
 
 	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		dbMu.Lock()
 		defer dbMu.Unlock()
 
-		query, err := req.RequireString("query")
+		opts, modelStr, err := parseReasonRequest(req, configDir)
 		if err != nil {
-			return mcp.NewToolResultError("query is required"), nil
-		}
-
-		presetName := "daily-digest"
-		if p, err := req.RequireString("preset"); err == nil && p != "" {
-			presetName = p
-		}
-
-		project := ""
-		if p, err := req.RequireString("project"); err == nil && p != "" {
-			project = p
-		}
-
-		// Determine model
-		modelStr := ""
-		if m, err := req.RequireString("model"); err == nil && m != "" {
-			modelStr = m
-		} else {
-			// Smart defaults: deepseek for deep analysis, gemini for interactive
-			switch presetName {
-			case "weekly-dive", "fact-audit":
-				modelStr = reason.DefaultCronModel
-			default:
-				modelStr = reason.DefaultInteractiveModel
-			}
+			return mcp.NewToolResultError(err.Error()), nil
 		}
+		opts.ExcludeProjects = mcpHiddenProjects()
 
 		provider, model := reason.ParseProviderModel(modelStr)
 		llm, err := reason.NewLLM(reason.LLMConfig{
@@ -764,23 +1101,14 @@ func registerReasonTool(s *server.MCPServer, searchEngine *search.Engine, st sto
 			return mcp.NewToolResultError(fmt.Sprintf("LLM init error: %v", err)), nil
 		}
 
-		homeDir := ""
-		if h, err := os.UserHomeDir(); err == nil {
-			homeDir = h + "/.cortex"
-		}
-
 		engine := reason.NewEngine(reason.EngineConfig{
 			SearchEngine: searchEngine,
 			Store:        st,
 			LLM:          llm,
-			ConfigDir:    homeDir,
+			ConfigDir:    configDir,
 		})
 
-		result, err := engine.Reason(ctx, reason.ReasonOptions{
-			Query:   query,
-			Preset:  presetName,
-			Project: project,
-		})
+		result, err := engine.Reason(ctx, opts)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("reason error: %v", err)), nil
 		}
@@ -788,14 +1116,30 @@ func registerReasonTool(s *server.MCPServer, searchEngine *search.Engine, st sto
 		// Format response with metadata
 		output := map[string]interface{}{
 			"content":       result.Content,
+			"query":         result.Query,
+			"project":       result.Project,
 			"model":         result.Model,
 			"provider":      result.Provider,
 			"preset":        result.Preset,
+			"budget":        opts.ContextTokens,
 			"memories_used": result.MemoriesUsed,
 			"facts_used":    result.FactsUsed,
 			"duration_ms":   result.Duration.Milliseconds(),
+			"search_ms":     result.SearchTime.Milliseconds(),
+			"llm_ms":        result.LLMTime.Milliseconds(),
 			"tokens_in":     result.TokensIn,
 			"tokens_out":    result.TokensOut,
+			"citations":     result.Citations,
+		}
+		if result.Confidence != nil {
+			output["confidence"] = result.Confidence
+		}
+		if result.CacheHits > 0 || result.CachedTokens > 0 {
+			output["cache_hits"] = result.CacheHits
+			output["cached_tokens"] = result.CachedTokens
+		}
+		if result.Cached {
+			output["cached"] = true
 		}
 
 		data, _ := json.MarshalIndent(output, "", "  ")
@@ -839,6 +1183,11 @@ func registerEdgeAddTool(s *server.MCPServer, st store.Store) {
 		if err != nil {
 			return mcp.NewToolResultError("target_fact_id required"), nil
 		}
+		for _, id := range []int64{int64(sourceID), int64(targetID)} {
+			if res := requireVisibleFact(ctx, sqlStore, id); res != nil {
+				return res, nil
+			}
+		}
 		edgeTypeStr, err := req.RequireString("edge_type")
 		if err != nil {
 			return mcp.NewToolResultError("edge_type required"), nil
@@ -910,6 +1259,11 @@ func registerGraphTool(s *server.MCPServer, st store.Store) {
 		if err != nil {
 			return mcp.NewToolResultError("fact_id required"), nil
 		}
+		// A fact in another namespace or a no-mcp project has no graph, the
+		// same as a missing one.
+		if requireVisibleFact(ctx, sqlStore, int64(factID)) != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("No graph found from fact #%d", int64(factID))), nil
+		}
 
 		depth := 2
 		if d, err := req.RequireFloat("depth"); err == nil && d > 0 {
@@ -925,6 +1279,9 @@ func registerGraphTool(s *server.MCPServer, st store.Store) {
 		}
 
 		nodes, err := sqlStore.TraverseGraph(ctx, int64(factID), depth, minConf)
+		if err == nil {
+			nodes, err = hideMCPGraphNodes(ctx, sqlStore, nodes)
+		}
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("graph error: %v", err)), nil
 		}
@@ -1005,7 +1362,6 @@ func registerGraphExportTool(s *server.MCPServer, st store.Store) {
 		if err != nil {
 			return mcp.NewToolResultError("fact_id required"), nil
 		}
-
 		depth := 2
 		if d, err := req.RequireFloat("depth"); err == nil && d > 0 {
 			depth = int(d)
@@ -1024,8 +1380,15 @@ func registerGraphExportTool(s *server.MCPServer, st store.Store) {
 			agentFilter = a
 		}
 
-		// Traverse graph
-		graphNodes, err := sqlStore.TraverseGraph(ctx, int64(factID), depth, minConf)
+		// Traverse graph. A fact in another namespace or a no-mcp project
+		// exports as empty, the same as a missing one.
+		var graphNodes []store.GraphNode
+		if requireVisibleFact(ctx, sqlStore, int64(factID)) == nil {
+			graphNodes, err = sqlStore.TraverseGraph(ctx, int64(factID), depth, minConf)
+			if err == nil {
+				graphNodes, err = hideMCPGraphNodes(ctx, sqlStore, graphNodes)
+			}
+		}
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("graph error: %v", err)), nil
 		}
@@ -1169,6 +1532,7 @@ func registerRecentResource(s *server.MCPServer, st store.Store) {
 		if err != nil {
 			return nil, fmt.Errorf("listing recent memories: %w", err)
 		}
+		memories, _ = store.FilterMemoriesByPrivacy(memories, store.PrivacyNoMCP)
 
 		// Build compact representation
 		type recentMemory struct {
@@ -1289,37 +1653,55 @@ func extractFactsFromMemories(ctx context.Context, st store.Store, memoryIDs []i
 		return 0
 	}
 
-	pipeline := extract.NewPipeline(nil)
 	totalFacts := 0
-
 	for _, mem := range memories {
-		metadata := map[string]string{
-			"source_file":    mem.SourceFile,
-			"source_section": mem.SourceSection,
-		}
-		facts, err := pipeline.Extract(ctx, mem.Content, metadata)
-		if err != nil || len(facts) == 0 {
-			continue
-		}
+		_, n := extractMemoryFacts(ctx, st, mem)
+		totalFacts += n
+	}
+	return totalFacts
+}
 
-		for _, ef := range facts {
-			f := &store.Fact{
-				MemoryID:    mem.ID,
-				Subject:     ef.Subject,
-				Predicate:   ef.Predicate,
-				Object:      ef.Object,
-				FactType:    ef.FactType,
-				Confidence:  ef.Confidence,
-				DecayRate:   ef.DecayRate,
-				SourceQuote: ef.SourceQuote,
-			}
-			if _, err := st.AddFact(ctx, f); err == nil {
-				totalFacts++
-			}
+// extractMemoryFacts runs rule-based extraction on one memory and stores the
+// results. It returns the extracted facts (the baseline for LLM enrichment)
+// and how many were stored.
+func extractMemoryFacts(ctx context.Context, st store.Store, mem *store.Memory) ([]extract.ExtractedFact, int) {
+	metadata := map[string]string{
+		"source_file":    mem.SourceFile,
+		"source_section": mem.SourceSection,
+	}
+	facts, err := extract.NewPipeline(nil).Extract(ctx, mem.Content, metadata)
+	if err != nil || len(facts) == 0 {
+		return nil, 0
+	}
+
+	stored := 0
+	for _, ef := range facts {
+		f := &store.Fact{
+			MemoryID:    mem.ID,
+			Subject:     ef.Subject,
+			Predicate:   ef.Predicate,
+			Object:      ef.Object,
+			FactType:    ef.FactType,
+			Confidence:  ef.Confidence,
+			DecayRate:   ef.DecayRate,
+			SourceQuote: ef.SourceQuote,
+			Negated:     ef.Negated,
+		}
+		if _, err := st.AddFact(ctx, f); err == nil {
+			stored++
 		}
 	}
+	return facts, stored
+}
 
-	return totalFacts
+// boolArg reads an optional boolean argument, also accepting the string
+// "true" that older clients send.
+func boolArg(req mcp.CallToolRequest, name string) bool {
+	if v, err := req.RequireBool(name); err == nil {
+		return v
+	}
+	v, err := req.RequireString(name)
+	return err == nil && strings.EqualFold(strings.TrimSpace(v), "true")
 }
 
 func containsInsensitive(s, substr string) bool {
@@ -1510,6 +1892,9 @@ func registerConnectSyncTool(s *server.MCPServer, connStore *connect.ConnectorSt
 			Extract: extractEnabled,
 			NoInfer: noInfer,
 		}
+		if resolvedCfg, err := cfgresolver.ResolveConfig(cfgresolver.ResolveOptions{}); err == nil {
+			opts.Quotas = connect.QuotaPolicyFromConfig(resolvedCfg.Import.Quotas)
+		}
 
 		if providerName != "" {
 			result, err := engine.SyncProvider(ctx, providerName, opts)
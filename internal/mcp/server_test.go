@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
 	"github.com/hurttlocker/cortex/internal/search"
 	"github.com/hurttlocker/cortex/internal/store"
 	mcplib "github.com/mark3labs/mcp-go/mcp"
@@ -182,6 +183,32 @@ func TestSearchTool(t *testing.T) {
 	}
 }
 
+func TestContextTool(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+
+	result := callTool(t, srv, "cortex_context", map[string]interface{}{
+		"query":      "wedding venue",
+		"max_tokens": float64(400),
+	})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", getTextContent(t, result))
+	}
+
+	var pc search.PromptContext
+	if err := json.Unmarshal([]byte(getTextContent(t, result)), &pc); err != nil {
+		t.Fatalf("parsing context result: %v", err)
+	}
+	if len(pc.Passages) == 0 || pc.TokensUsed > 400 || pc.Budget != 400 {
+		t.Fatalf("unexpected context: %+v", pc)
+	}
+	if !strings.HasPrefix(pc.Text, "[1] ") || !containsInsensitive(pc.Text, "villa rosa") {
+		t.Errorf("expected cited villa rosa passage, got:\n%s", pc.Text)
+	}
+}
+
 func TestSearchToolWithMode(t *testing.T) {
 	s := setupTestStore(t)
 	defer s.Close()
@@ -205,6 +232,52 @@ func TestSearchToolWithMode(t *testing.T) {
 	}
 }
 
+func TestSearchToolWithProfile(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:", SearchProfiles: cfgresolver.SearchProfiles{
+		"docs": {Source: "readme", Mode: "bm25"},
+	}})
+
+	result := callTool(t, srv, "cortex_search", map[string]interface{}{
+		"query":   "Villa Rosa",
+		"profile": "docs",
+	})
+	text := getTextContent(t, result)
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", text)
+	}
+	var results []search.Result
+	if err := json.Unmarshal([]byte(text), &results); err != nil {
+		t.Fatalf("parsing search results: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected profile source filter to exclude notes.md, got %d results", len(results))
+	}
+
+	// An explicit source overrides the profile's.
+	result = callTool(t, srv, "cortex_search", map[string]interface{}{
+		"query":   "Villa Rosa",
+		"profile": "docs",
+		"source":  "notes.md",
+	})
+	if err := json.Unmarshal([]byte(getTextContent(t, result)), &results); err != nil {
+		t.Fatalf("parsing search results: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("expected explicit source to override the profile")
+	}
+
+	result = callTool(t, srv, "cortex_search", map[string]interface{}{
+		"query":   "Villa Rosa",
+		"profile": "missing",
+	})
+	if !result.IsError || !strings.Contains(getTextContent(t, result), "available: docs") {
+		t.Fatalf("expected unknown profile error, got %q", getTextContent(t, result))
+	}
+}
+
 func TestSearchToolWithRRFModeAccepted(t *testing.T) {
 	s := setupTestStore(t)
 	defer s.Close()
@@ -344,6 +417,53 @@ func TestFactsToolWithSubjectFilter(t *testing.T) {
 	}
 }
 
+func TestFactsToolCursorPaging(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+
+	type page struct {
+		Facts      []store.Fact `json:"facts"`
+		NextCursor string       `json:"next_cursor"`
+	}
+	seen := map[int64]bool{}
+	args := map[string]interface{}{"limit": float64(2), "cursor": ""}
+	for i := 0; i < 3; i++ {
+		result := callTool(t, srv, "cortex_facts", args)
+		text := getTextContent(t, result)
+		if result.IsError {
+			t.Fatalf("unexpected error: %s", text)
+		}
+		var p page
+		if err := json.Unmarshal([]byte(text), &p); err != nil {
+			t.Fatalf("parsing paged facts: %v", err)
+		}
+		for _, f := range p.Facts {
+			if seen[f.ID] {
+				t.Fatalf("fact %d returned twice", f.ID)
+			}
+			seen[f.ID] = true
+		}
+		if p.NextCursor == "" {
+			break
+		}
+		args["cursor"] = p.NextCursor
+	}
+	if len(seen) != 3 {
+		t.Fatalf("expected all 3 facts across pages, got %d", len(seen))
+	}
+
+	result := callTool(t, srv, "cortex_search", map[string]interface{}{
+		"query":  "Villa Rosa",
+		"mode":   "bm25",
+		"cursor": "not-a-cursor",
+	})
+	if !result.IsError || !strings.Contains(getTextContent(t, result), "cursor") {
+		t.Fatalf("expected invalid cursor error, got %q", getTextContent(t, result))
+	}
+}
+
 func TestStaleTool(t *testing.T) {
 	s := setupTestStore(t)
 	defer s.Close()
@@ -473,3 +593,72 @@ func TestReinforceToolEmptyIDs(t *testing.T) {
 		t.Error("expected non-empty response for empty fact_ids")
 	}
 }
+
+func TestParseReasonRequest(t *testing.T) {
+	configDir := t.TempDir()
+	req := func(args map[string]interface{}) mcplib.CallToolRequest {
+		r := mcplib.CallToolRequest{}
+		r.Params.Arguments = args
+		return r
+	}
+
+	opts, model, err := parseReasonRequest(req(map[string]interface{}{"query": "what changed?"}), configDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Preset != "daily-digest" || opts.ContextTokens != defaultReasonBudget || model == "" {
+		t.Fatalf("unexpected defaults: %+v model=%q", opts, model)
+	}
+
+	opts, _, err = parseReasonRequest(req(map[string]interface{}{
+		"query": "audit", "preset": "fact-audit", "project": "ops", "budget": 500.0, "max_tokens": 300.0,
+	}), configDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Preset != "fact-audit" || opts.Project != "ops" || opts.ContextTokens != 500 || opts.MaxTokens != 300 {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+
+	for name, args := range map[string]map[string]interface{}{
+		"missing query":  {},
+		"unknown preset": {"query": "q", "preset": "nope"},
+		"budget too big": {"query": "q", "budget": 1e6},
+	} {
+		if _, _, err := parseReasonRequest(req(args), configDir); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if names := reasonPresetNames(configDir); len(names) == 0 || !strings.Contains(strings.Join(names, ","), "daily-digest") {
+		t.Fatalf("expected builtin presets, got %v", names)
+	}
+}
+
+func TestReasonTool_UnknownPresetFailsBeforeLLM(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+
+	result := callTool(t, srv, "cortex_reason", map[string]interface{}{"query": "status", "preset": "no-such-preset"})
+	if !result.IsError || !strings.Contains(getTextContent(t, result), "unknown preset") {
+		t.Fatalf("expected unknown preset error, got %+v", result)
+	}
+}
+
+func TestStatusResource(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:", Version: "test"})
+
+	var status store.StoreStatus
+	if err := json.Unmarshal([]byte(callResource(t, srv, "cortex://status")), &status); err != nil {
+		t.Fatalf("parse status resource: %v", err)
+	}
+	if status.Memories != 3 || status.Facts != 3 || status.LastImport == nil {
+		t.Fatalf("status = %+v", status)
+	}
+	if !strings.HasPrefix(status.Summary, "cortex: 3 memories, 3 facts") {
+		t.Fatalf("summary = %q", status.Summary)
+	}
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// subscriptionNotification is the method of the notification pushed to
+// connected clients when a subscribed subject's facts change.
+const subscriptionNotification = "notifications/cortex/subscription"
+
+// pushSubscriptionAlerts forwards subscription alerts raised through st to
+// every connected client as subscriptionNotification. Other alert hooks on
+// the store keep running.
+func pushSubscriptionAlerts(s *server.MCPServer, st store.Store) {
+	sqlStore, ok := st.(*store.SQLiteStore)
+	if !ok {
+		return
+	}
+	prev := sqlStore.OnAlert
+	sqlStore.OnAlert = func(a *store.Alert) {
+		if prev != nil {
+			prev(a)
+		}
+		if a.AlertType != store.AlertTypeSubscription {
+			return
+		}
+		params := map[string]any{
+			"alert_id":   a.ID,
+			"message":    a.Message,
+			"created_at": a.CreatedAt,
+		}
+		if a.AgentID != "" {
+			params["agent_id"] = a.AgentID
+		}
+		var detail store.SubscriptionAlertDetail
+		if err := json.Unmarshal([]byte(a.Details), &detail); err == nil {
+			params["subscription"] = detail
+		}
+		s.SendNotificationToAllClients(subscriptionNotification, params)
+	}
+}
+
+func registerWatchSubjectTool(s *server.MCPServer, st store.Store, defaultAgent string, readOnly bool) {
+	tool := mcp.NewTool("cortex_watch_subject",
+		mcp.WithDescription("Subscribe to changes in what Cortex knows about a subject. Whenever a fact whose subject contains the given text is added, superseded, or found in conflict, Cortex raises a 'subscription' alert, POSTs it to the alert webhook, and pushes a "+subscriptionNotification+" notification to connected clients. Use instead of re-querying cortex_facts on a timer. action=list shows subscriptions; action=remove deletes one by id."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("action",
+			mcp.Description("subscribe (default), list, or remove"),
+			mcp.Enum("subscribe", "list", "remove"),
+		),
+		mcp.WithString("subject",
+			mcp.Description("Subject to watch, matched case-insensitively as a substring of fact subjects (required for subscribe)"),
+		),
+		mcp.WithNumber("id",
+			mcp.Description("Subscription to remove (required for remove)"),
+		),
+		mcp.WithString("agent_id",
+			mcp.Description("Only notify about this agent's facts and global facts. Defaults to the server's agent."),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		action := req.GetString("action", "subscribe")
+		if action != "list" {
+			if res := readOnlyGuard(readOnly, "cortex_watch_subject"); res != nil {
+				return res, nil
+			}
+		}
+		dbMu.Lock()
+		defer dbMu.Unlock()
+
+		sqlStore, ok := st.(*store.SQLiteStore)
+		if !ok {
+			return mcp.NewToolResultError("subject subscriptions require SQLiteStore"), nil
+		}
+
+		var result any
+		switch action {
+		case "subscribe":
+			subject := strings.TrimSpace(req.GetString("subject", ""))
+			if subject == "" {
+				return mcp.NewToolResultError("subject is required"), nil
+			}
+			sub := &store.SubjectSubscription{Subject: subject, AgentID: req.GetString("agent_id", defaultAgent)}
+			if err := sqlStore.CreateSubscription(ctx, sub); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("subscribing: %v", err)), nil
+			}
+			result = sub
+		case "list":
+			subs, err := sqlStore.ListSubscriptions(ctx)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("listing subscriptions: %v", err)), nil
+			}
+			if subs == nil {
+				subs = []*store.SubjectSubscription{}
+			}
+			result = subs
+		case "remove":
+			id := int64(req.GetFloat("id", 0))
+			if id <= 0 {
+				return mcp.NewToolResultError("id is required"), nil
+			}
+			if err := sqlStore.RemoveSubscription(ctx, id); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			result = map[string]any{"removed": id}
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("unknown action %q (use subscribe, list, or remove)", action)), nil
+		}
+		data, _ := json.MarshalIndent(result, "", "  ")
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
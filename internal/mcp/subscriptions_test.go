@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+type testSession struct {
+	ch chan mcplib.JSONRPCNotification
+}
+
+func (s *testSession) Initialize()                                            {}
+func (s *testSession) Initialized() bool                                      { return true }
+func (s *testSession) NotificationChannel() chan<- mcplib.JSONRPCNotification { return s.ch }
+func (s *testSession) SessionID() string                                      { return "test-session" }
+
+func TestWatchSubjectToolPushesNotifications(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	srv := NewServer(ServerConfig{Store: s, DBPath: ":memory:"})
+	session := &testSession{ch: make(chan mcplib.JSONRPCNotification, 8)}
+	if err := srv.RegisterSession(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+
+	result := callTool(t, srv, "cortex_watch_subject", map[string]interface{}{"subject": "Deployment Policy"})
+	if result.IsError {
+		t.Fatalf("subscribe: %s", getTextContent(t, result))
+	}
+	var sub store.SubjectSubscription
+	if err := json.Unmarshal([]byte(getTextContent(t, result)), &sub); err != nil || sub.ID == 0 || sub.Subject != "deployment policy" {
+		t.Fatalf("subscription = %+v, %v", sub, err)
+	}
+
+	ctx := context.Background()
+	m, _ := s.AddMemory(ctx, &store.Memory{Content: "deploys need approval", SourceFile: "ops.md"})
+	if _, err := s.AddFact(ctx, &store.Fact{MemoryID: m, Subject: "deployment policy", Predicate: "requires", Object: "approval", FactType: "kv"}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case n := <-session.ch:
+		if n.Method != subscriptionNotification {
+			t.Fatalf("notification method = %q", n.Method)
+		}
+		detail, ok := n.Params.AdditionalFields["subscription"].(store.SubscriptionAlertDetail)
+		if !ok || detail.SubscriptionID != sub.ID || detail.Event != store.SubscriptionEventAdded {
+			t.Fatalf("notification params = %+v", n.Params.AdditionalFields)
+		}
+	default:
+		t.Fatal("no notification pushed")
+	}
+
+	result = callTool(t, srv, "cortex_watch_subject", map[string]interface{}{"action": "list"})
+	if text := getTextContent(t, result); !strings.Contains(text, `"notify_count": 1`) {
+		t.Fatalf("list = %s", text)
+	}
+	result = callTool(t, srv, "cortex_watch_subject", map[string]interface{}{"action": "remove", "id": float64(sub.ID)})
+	if result.IsError {
+		t.Fatalf("remove: %s", getTextContent(t, result))
+	}
+	result = callTool(t, srv, "cortex_watch_subject", map[string]interface{}{"action": "subscribe"})
+	if !result.IsError {
+		t.Fatal("subscribe without a subject should fail")
+	}
+
+	ro := NewServer(ServerConfig{Store: s, DBPath: ":memory:", ReadOnly: true})
+	if result := callTool(t, ro, "cortex_watch_subject", map[string]interface{}{"subject": "x"}); !result.IsError {
+		t.Fatal("read-only server should refuse to subscribe")
+	}
+}
@@ -30,9 +30,49 @@ type Stats struct {
 	Freshness              Freshness                     `json:"freshness"`
 	Growth                 Growth                        `json:"growth"`
 	Alerts                 []string                      `json:"alerts,omitempty"`
+	Budget                 *BudgetStatus                 `json:"budget,omitempty"`
 	ConfidenceDistribution *store.ConfidenceDistribution `json:"confidence_distribution,omitempty"`
 }
 
+// Budget levels reported in BudgetStatus.
+const (
+	BudgetOK       = "ok"
+	BudgetWarning  = "warning"
+	BudgetExceeded = "exceeded"
+)
+
+// BudgetStatus compares storage with the configured size budget.
+type BudgetStatus struct {
+	BudgetBytes int64   `json:"budget_bytes"`
+	UsedBytes   int64   `json:"used_bytes"`
+	Percent     float64 `json:"percent"`
+	Level       string  `json:"level"`
+}
+
+// ApplyBudget records how storage compares with budget bytes and adds an
+// alert from warnAt (a fraction of the budget) on, well before tiering
+// kicks in at the limit. A zero budget does nothing.
+func (s *Stats) ApplyBudget(budget int64, warnAt float64) {
+	if budget <= 0 {
+		return
+	}
+	b := &BudgetStatus{
+		BudgetBytes: budget,
+		UsedBytes:   s.StorageBytes,
+		Percent:     math.Round(float64(s.StorageBytes)/float64(budget)*1000) / 10,
+		Level:       BudgetOK,
+	}
+	switch {
+	case s.StorageBytes > budget:
+		b.Level = BudgetExceeded
+		s.Alerts = append(s.Alerts, fmt.Sprintf("db_budget_exceeded: storage is %.0f%% of the size budget; tiering runs on the next import or maintain (or cortex tier)", b.Percent))
+	case float64(s.StorageBytes) >= warnAt*float64(budget):
+		b.Level = BudgetWarning
+		s.Alerts = append(s.Alerts, fmt.Sprintf("db_budget_warning: storage is %.0f%% of the size budget; consider cortex compact or cortex summarize", b.Percent))
+	}
+	s.Budget = b
+}
+
 // Growth holds short-window growth metrics for ops guardrails.
 type Growth struct {
 	Memories24h int `json:"memories_24h"`
@@ -100,15 +140,20 @@ type StaleOpts struct {
 type Conflict struct {
 	Fact1        store.Fact `json:"fact1"`
 	Fact2        store.Fact `json:"fact2"`
-	ConflictType string     `json:"conflict_type"` // "attribute"
+	ConflictType string     `json:"conflict_type"` // "attribute" or "negation"
 	Similarity   float64    `json:"similarity"`
-	CrossAgent   bool       `json:"cross_agent,omitempty"`
+	// Severity is critical|high|medium|low; see ScoreConflict.
+	Severity        string   `json:"severity,omitempty"`
+	SeverityScore   float64  `json:"severity_score"`
+	SeverityReasons []string `json:"severity_reasons,omitempty"`
+	CrossAgent      bool     `json:"cross_agent,omitempty"`
 }
 
 // Engine provides memory observability capabilities.
 type Engine struct {
-	store  store.Store
-	dbPath string
+	store             store.Store
+	dbPath            string
+	criticalThreshold float64
 }
 
 // NewEngine creates a new observability engine.
@@ -464,8 +509,28 @@ func buildGrowthRecommendation(w24h GrowthWindowReport, w7d GrowthWindowReport)
 	return recommendation, guidance
 }
 
+// staleScanLimit caps how many facts a stale scan examines.
+const staleScanLimit = 10000
+
 // GetStaleFacts returns facts that have decayed below the confidence threshold.
 func (e *Engine) GetStaleFacts(ctx context.Context, opts StaleOpts) ([]StaleFact, error) {
+	staleFacts, _, err := e.scanStaleFacts(ctx, opts)
+	return staleFacts, err
+}
+
+// CountStaleFacts returns how many facts GetStaleFacts would report without
+// a limit. estimated is true when the scan hit staleScanLimit facts, so
+// older facts past the cap were not examined.
+func (e *Engine) CountStaleFacts(ctx context.Context, opts StaleOpts) (total int, estimated bool, err error) {
+	opts.Limit = math.MaxInt
+	staleFacts, truncated, err := e.scanStaleFacts(ctx, opts)
+	if err != nil {
+		return 0, false, err
+	}
+	return len(staleFacts), truncated, nil
+}
+
+func (e *Engine) scanStaleFacts(ctx context.Context, opts StaleOpts) ([]StaleFact, bool, error) {
 	// Set defaults
 	if opts.MaxConfidence == 0 {
 		opts.MaxConfidence = 0.5
@@ -478,10 +543,11 @@ func (e *Engine) GetStaleFacts(ctx context.Context, opts StaleOpts) ([]StaleFact
 	}
 
 	// Get all facts to calculate effective confidence
-	facts, err := e.store.ListFacts(ctx, store.ListOpts{Limit: 10000, IncludeSuperseded: opts.IncludeSuperseded, Agent: opts.AgentID}) // Large limit to get all facts
+	facts, err := e.store.ListFacts(ctx, store.ListOpts{Limit: staleScanLimit, IncludeSuperseded: opts.IncludeSuperseded, Agent: opts.AgentID}) // Large limit to get all facts
 	if err != nil {
-		return nil, fmt.Errorf("listing facts: %w", err)
+		return nil, false, fmt.Errorf("listing facts: %w", err)
 	}
+	truncated := len(facts) >= staleScanLimit
 
 	now := time.Now().UTC()
 	staleFacts := make([]StaleFact, 0)
@@ -525,7 +591,7 @@ func (e *Engine) GetStaleFacts(ctx context.Context, opts StaleOpts) ([]StaleFact
 		}
 	}
 
-	return staleFacts, nil
+	return staleFacts, truncated, nil
 }
 
 // GetConflicts detects attribute conflicts between active (non-superseded) facts.
@@ -539,6 +605,7 @@ func (e *Engine) GetConflictsLimit(ctx context.Context, limit int) ([]Conflict,
 }
 
 // GetConflictsLimitWithSuperseded allows historical conflicts to be included when requested.
+// Conflicts are scored and returned most severe first.
 func (e *Engine) GetConflictsLimitWithSuperseded(ctx context.Context, limit int, includeSuperseded bool) ([]Conflict, error) {
 	storeConflicts, err := e.store.GetAttributeConflictsLimitWithSuperseded(ctx, limit, includeSuperseded)
 	if err != nil {
@@ -553,9 +620,11 @@ func (e *Engine) GetConflictsLimitWithSuperseded(ctx context.Context, limit int,
 			Fact2:        sc.Fact2,
 			ConflictType: sc.ConflictType,
 			Similarity:   sc.Similarity,
+			Severity:     sc.Severity,
 			CrossAgent:   sc.CrossAgent,
 		}
 	}
+	e.scoreConflicts(ctx, conflicts)
 
 	return conflicts, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -218,6 +219,36 @@ func TestBuildGrowthAlerts(t *testing.T) {
 	}
 }
 
+func TestStatsApplyBudget(t *testing.T) {
+	for _, tc := range []struct {
+		used  int64
+		level string
+		alert string
+	}{
+		{used: 500, level: BudgetOK},
+		{used: 850, level: BudgetWarning, alert: "db_budget_warning"},
+		{used: 1200, level: BudgetExceeded, alert: "db_budget_exceeded"},
+	} {
+		stats := &Stats{StorageBytes: tc.used}
+		stats.ApplyBudget(1000, 0.8)
+		if stats.Budget == nil || stats.Budget.Level != tc.level {
+			t.Fatalf("used %d: budget = %+v, want level %s", tc.used, stats.Budget, tc.level)
+		}
+		if tc.alert == "" && len(stats.Alerts) != 0 {
+			t.Fatalf("used %d: unexpected alerts %v", tc.used, stats.Alerts)
+		}
+		if tc.alert != "" && (len(stats.Alerts) != 1 || !strings.HasPrefix(stats.Alerts[0], tc.alert)) {
+			t.Fatalf("used %d: alerts = %v, want %s", tc.used, stats.Alerts, tc.alert)
+		}
+	}
+
+	stats := &Stats{StorageBytes: 5000}
+	stats.ApplyBudget(0, 0.8)
+	if stats.Budget != nil || len(stats.Alerts) != 0 {
+		t.Fatalf("no budget should leave stats alone, got %+v", stats)
+	}
+}
+
 // --- Stale Detection Tests ---
 
 func TestGetStaleFacts_NoStale(t *testing.T) {
@@ -286,6 +317,34 @@ func TestGetStaleFacts_DecayedFacts(t *testing.T) {
 	}
 }
 
+func TestCountStaleFacts_IgnoresLimit(t *testing.T) {
+	engine := newTestEngine(t)
+	ctx := context.Background()
+
+	m1 := addTestMemory(t, engine, "Old content", "old.md")
+	sqliteStore := engine.store.(*store.SQLiteStore)
+	oldTime := time.Now().UTC().AddDate(0, 0, -60)
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		factID := addTestFact(t, engine, m1, "user", "name", name, "identity", 0.8)
+		if _, err := sqliteStore.ExecContext(ctx, "UPDATE facts SET last_reinforced = ? WHERE id = ?", oldTime, factID); err != nil {
+			t.Fatalf("failed to update last_reinforced: %v", err)
+		}
+	}
+
+	opts := StaleOpts{MaxConfidence: 0.5, MaxDays: 30, Limit: 1}
+	page, err := engine.GetStaleFacts(ctx, opts)
+	if err != nil {
+		t.Fatalf("GetStaleFacts failed: %v", err)
+	}
+	total, estimated, err := engine.CountStaleFacts(ctx, opts)
+	if err != nil {
+		t.Fatalf("CountStaleFacts failed: %v", err)
+	}
+	if len(page) != 1 || total != 3 || estimated {
+		t.Fatalf("page=%d total=%d estimated=%v, want 1/3/false", len(page), total, estimated)
+	}
+}
+
 func TestGetStaleFacts_EffectiveConfidenceCalculation(t *testing.T) {
 	engine := newTestEngine(t)
 	ctx := context.Background()
@@ -0,0 +1,188 @@
+package observe
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// Conflict severity labels, most to least urgent.
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+)
+
+// SeverityRank orders severity labels (low=0 … critical=3); -1 for unknown.
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 3
+	case SeverityHigh:
+		return 2
+	case SeverityMedium:
+		return 1
+	case SeverityLow:
+		return 0
+	default:
+		return -1
+	}
+}
+
+// DefaultCriticalThreshold is the severity score at which a conflict is
+// critical when policies.conflict_severity.critical_threshold is unset.
+const DefaultCriticalThreshold = 0.8
+
+// Severity model weights. Without a rule-class fact the components sum to 1.
+const (
+	severityConfidenceWeight = 0.4
+	severityRecencyWeight    = 0.2
+	severityCrossAgentWeight = 0.2
+	severityNegationWeight   = 0.2
+	severityRuleWeight       = 0.4
+	// severityRecencyDays is the e-folding age of the newer fact: a conflict
+	// whose newer side is 30 days old keeps ~37% of the recency weight.
+	severityRecencyDays = 30.0
+)
+
+// SetCriticalThreshold overrides the score at which conflicts become
+// critical. Values outside (0, 1] restore the default.
+func (e *Engine) SetCriticalThreshold(threshold float64) {
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultCriticalThreshold
+	}
+	e.criticalThreshold = threshold
+}
+
+// CriticalThreshold returns the score at which conflicts become critical.
+func (e *Engine) CriticalThreshold() float64 {
+	if e.criticalThreshold <= 0 {
+		return DefaultCriticalThreshold
+	}
+	return e.criticalThreshold
+}
+
+// ScoreConflict rates how urgently a conflict needs attention. The score
+// combines the confidence of both facts (two confident facts disagreeing is
+// worse than a shaky one), the age of the newer fact, cross-agent
+// disagreement, and direct negation. Conflicts involving a rule-class memory
+// are always critical; negations are never below high.
+func ScoreConflict(c Conflict, ruleClass bool, criticalThreshold float64, now time.Time) (score float64, severity string, reasons []string) {
+	conf := math.Sqrt(clamp01(c.Fact1.Confidence) * clamp01(c.Fact2.Confidence))
+	score += severityConfidenceWeight * conf
+	if conf >= 0.8 {
+		reasons = append(reasons, "confident facts")
+	}
+
+	newest := c.Fact1.CreatedAt
+	if c.Fact2.CreatedAt.After(newest) {
+		newest = c.Fact2.CreatedAt
+	}
+	if !newest.IsZero() {
+		ageDays := math.Max(0, now.Sub(newest).Hours()/24)
+		recency := math.Exp(-ageDays / severityRecencyDays)
+		score += severityRecencyWeight * recency
+		if ageDays <= 7 {
+			reasons = append(reasons, "recent")
+		}
+	}
+
+	if c.CrossAgent {
+		score += severityCrossAgentWeight
+		reasons = append(reasons, "cross-agent")
+	}
+	if c.ConflictType == "negation" {
+		score += severityNegationWeight
+		reasons = append(reasons, "direct negation")
+	}
+	if ruleClass {
+		score += severityRuleWeight
+		reasons = append(reasons, "rule")
+	}
+	score = math.Round(clamp01(score)*1000) / 1000
+
+	if criticalThreshold <= 0 || criticalThreshold > 1 {
+		criticalThreshold = DefaultCriticalThreshold
+	}
+	switch {
+	case ruleClass || score >= criticalThreshold:
+		severity = SeverityCritical
+	case score >= 0.55 || c.ConflictType == "negation":
+		severity = SeverityHigh
+	case score >= 0.3:
+		severity = SeverityMedium
+	default:
+		severity = SeverityLow
+	}
+	return score, severity, reasons
+}
+
+// scoreConflicts fills in severity for each conflict and orders them most
+// severe first.
+func (e *Engine) scoreConflicts(ctx context.Context, conflicts []Conflict) {
+	now := time.Now().UTC()
+	classes := map[int64]string{}
+	memoryClass := func(memoryID int64) string {
+		if class, ok := classes[memoryID]; ok {
+			return class
+		}
+		class := ""
+		if m, err := e.store.GetMemory(ctx, memoryID); err == nil && m != nil {
+			class = store.NormalizeMemoryClass(m.MemoryClass)
+		}
+		classes[memoryID] = class
+		return class
+	}
+
+	threshold := e.CriticalThreshold()
+	for i := range conflicts {
+		c := &conflicts[i]
+		rule := memoryClass(c.Fact1.MemoryID) == store.MemoryClassRule ||
+			memoryClass(c.Fact2.MemoryID) == store.MemoryClassRule
+		c.SeverityScore, c.Severity, c.SeverityReasons = ScoreConflict(*c, rule, threshold, now)
+	}
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		return conflicts[i].SeverityScore > conflicts[j].SeverityScore
+	})
+}
+
+// EscalateCriticalConflicts records a critical conflict alert for each
+// critical conflict that does not already have an unacknowledged one. Alerts
+// go through the store's webhook notifier when one is configured. Returns
+// the number of alerts created.
+func (e *Engine) EscalateCriticalConflicts(ctx context.Context, conflicts []Conflict) (int, error) {
+	ss, ok := e.store.(*store.SQLiteStore)
+	if !ok {
+		return 0, nil
+	}
+	created := 0
+	for _, c := range conflicts {
+		if c.Severity != SeverityCritical {
+			continue
+		}
+		ok, err := ss.RecordConflictAlert(ctx, store.Conflict{
+			Fact1:        c.Fact1,
+			Fact2:        c.Fact2,
+			ConflictType: c.ConflictType,
+			Similarity:   c.Similarity,
+			Severity:     c.Severity,
+			CrossAgent:   c.CrossAgent,
+		}, c.SeverityScore, strings.Join(c.SeverityReasons, ", "))
+		if err != nil {
+			return created, err
+		}
+		if ok {
+			created++
+		}
+	}
+	return created, nil
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
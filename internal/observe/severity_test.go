@@ -0,0 +1,99 @@
+package observe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestScoreConflict_Components(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	fact := func(conf float64, age time.Duration) store.Fact {
+		return store.Fact{Confidence: conf, CreatedAt: now.Add(-age)}
+	}
+
+	old := Conflict{ConflictType: "attribute", Fact1: fact(0.4, 400*24*time.Hour), Fact2: fact(0.5, 365*24*time.Hour)}
+	score, sev, _ := ScoreConflict(old, false, DefaultCriticalThreshold, now)
+	if sev != SeverityLow || score >= 0.3 {
+		t.Fatalf("old, shaky conflict should be low, got %s %.3f", sev, score)
+	}
+
+	fresh := Conflict{ConflictType: "attribute", Fact1: fact(0.95, time.Hour), Fact2: fact(0.9, 2*time.Hour)}
+	freshScore, sev, reasons := ScoreConflict(fresh, false, DefaultCriticalThreshold, now)
+	if sev != SeverityHigh || freshScore <= score {
+		t.Fatalf("fresh confident conflict should be high, got %s %.3f", sev, freshScore)
+	}
+	if len(reasons) != 2 || reasons[0] != "confident facts" || reasons[1] != "recent" {
+		t.Fatalf("unexpected reasons: %v", reasons)
+	}
+
+	fresh.CrossAgent = true
+	crossScore, sev, _ := ScoreConflict(fresh, false, DefaultCriticalThreshold, now)
+	if sev != SeverityHigh || crossScore <= freshScore {
+		t.Fatalf("cross-agent should raise the score, got %s %.3f", sev, crossScore)
+	}
+	if _, sev, _ := ScoreConflict(fresh, false, 0.75, now); sev != SeverityCritical {
+		t.Fatalf("lowered threshold should make it critical, got %s", sev)
+	}
+	fresh.ConflictType = "negation"
+	if _, sev, _ := ScoreConflict(fresh, false, DefaultCriticalThreshold, now); sev != SeverityCritical {
+		t.Fatalf("fresh cross-agent negation should be critical, got %s", sev)
+	}
+
+	negation := Conflict{ConflictType: "negation", Fact1: fact(0.3, 200*24*time.Hour), Fact2: fact(0.3, 200*24*time.Hour)}
+	if _, sev, _ := ScoreConflict(negation, false, DefaultCriticalThreshold, now); sev != SeverityHigh {
+		t.Fatalf("negations should never drop below high, got %s", sev)
+	}
+	if _, sev, _ := ScoreConflict(old, true, DefaultCriticalThreshold, now); sev != SeverityCritical {
+		t.Fatalf("rule conflicts should be critical, got %s", sev)
+	}
+}
+
+func TestGetConflicts_OrderedBySeverityAndEscalated(t *testing.T) {
+	engine := newTestEngine(t)
+	ctx := context.Background()
+	ss := engine.store.(*store.SQLiteStore)
+
+	notes := addTestMemory(t, engine, "notes", "notes.md")
+	rules, err := ss.AddMemory(ctx, &store.Memory{Content: "deploy rules", SourceFile: "rules.md", MemoryClass: store.MemoryClassRule})
+	if err != nil {
+		t.Fatalf("AddMemory: %v", err)
+	}
+	ss.AddFact(ctx, &store.Fact{MemoryID: notes, Subject: "editor", Predicate: "theme", Object: "dark", FactType: "preference", Confidence: 0.5})
+	ss.AddFact(ctx, &store.Fact{MemoryID: notes, Subject: "editor", Predicate: "theme", Object: "light", FactType: "preference", Confidence: 0.5})
+	ss.AddFact(ctx, &store.Fact{MemoryID: rules, Subject: "deploys", Predicate: "window", Object: "weekdays", FactType: "decision", Confidence: 0.5})
+	ss.AddFact(ctx, &store.Fact{MemoryID: notes, Subject: "deploys", Predicate: "window", Object: "any time", FactType: "decision", Confidence: 0.5})
+
+	conflicts, err := engine.GetConflicts(ctx)
+	if err != nil {
+		t.Fatalf("GetConflicts: %v", err)
+	}
+	if len(conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts, got %d", len(conflicts))
+	}
+	if conflicts[0].Fact1.Subject != "deploys" || conflicts[0].Severity != SeverityCritical {
+		t.Fatalf("rule conflict should sort first as critical, got %+v", conflicts[0])
+	}
+	if conflicts[1].Severity == SeverityCritical || conflicts[1].SeverityScore >= conflicts[0].SeverityScore {
+		t.Fatalf("unexpected second conflict: %+v", conflicts[1])
+	}
+
+	for i := 0; i < 2; i++ {
+		created, err := engine.EscalateCriticalConflicts(ctx, conflicts)
+		if err != nil {
+			t.Fatalf("EscalateCriticalConflicts: %v", err)
+		}
+		if want := 1 - i; created != want {
+			t.Fatalf("run %d: expected %d alerts created, got %d", i, want, created)
+		}
+	}
+	alerts, err := ss.ListAlerts(ctx, store.AlertFilter{Type: store.AlertTypeConflict})
+	if err != nil {
+		t.Fatalf("ListAlerts: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Severity != store.AlertSeverityCritical {
+		t.Fatalf("expected one critical conflict alert, got %+v", alerts)
+	}
+}
@@ -0,0 +1,92 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type inner struct {
+	Note string `json:"note"`
+}
+
+type base struct {
+	ID int64 `json:"id"`
+}
+
+type node struct {
+	base
+	Name     string            `json:"name" doc:"Display name"`
+	Kind     string            `json:"kind,omitempty" enum:"a,b"`
+	Parent   *node             `json:"parent,omitempty"`
+	Inner    inner             `json:"inner" doc:"Nested"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Meta     map[string]any    `json:"meta,omitempty"`
+	Seen     time.Time         `json:"seen"`
+	Skipped  string            `json:"-"`
+	internal string
+	Untagged bool
+}
+
+func TestSchema_ReflectsStructTags(t *testing.T) {
+	doc := New(Info{Title: "t", Version: "1"})
+	ref := doc.Schema(node{})
+	if ref.Ref != "#/components/schemas/node" {
+		t.Fatalf("expected a $ref to node, got %+v", ref)
+	}
+	s := doc.Components.Schemas["node"]
+	for _, name := range []string{"id", "name", "kind", "parent", "inner", "tags", "labels", "meta", "seen", "Untagged"} {
+		if s.Properties[name] == nil {
+			t.Errorf("missing property %q", name)
+		}
+	}
+	if s.Properties["Skipped"] != nil || s.Properties["internal"] != nil || s.Properties["base"] != nil {
+		t.Errorf("unexpected properties: %v", s.Properties)
+	}
+	if got := strings.Join(s.Required, ","); got != "Untagged,id,inner,name,seen,tags" {
+		t.Errorf("required = %s", got)
+	}
+	if p := s.Properties["name"]; p.Type != "string" || p.Description != "Display name" {
+		t.Errorf("name = %+v", p)
+	}
+	if p := s.Properties["kind"]; len(p.Enum) != 2 || p.Enum[1] != "b" {
+		t.Errorf("kind = %+v", p)
+	}
+	if p := s.Properties["parent"]; !p.Nullable || len(p.AllOf) != 1 || p.AllOf[0].Ref != "#/components/schemas/node" {
+		t.Errorf("parent = %+v", p)
+	}
+	if p := s.Properties["inner"]; p.Description != "Nested" || len(p.AllOf) != 1 || doc.Components.Schemas["inner"] == nil {
+		t.Errorf("inner = %+v", p)
+	}
+	if p := s.Properties["seen"]; p.Type != "string" || p.Format != "date-time" {
+		t.Errorf("seen = %+v", p)
+	}
+	if p := s.Properties["labels"]; p.AdditionalProperties.(*Schema).Type != "string" {
+		t.Errorf("labels = %+v", p)
+	}
+	if p := s.Properties["meta"]; p.AdditionalProperties != true {
+		t.Errorf("meta = %+v", p)
+	}
+	if p := s.Properties["id"]; p.Type != "integer" || p.Format != "int64" {
+		t.Errorf("id = %+v", p)
+	}
+}
+
+func TestYAML_RoundTrips(t *testing.T) {
+	doc := New(Info{Title: "t", Version: "1"})
+	doc.Get("/things", &Operation{OperationID: "listThings", Responses: map[string]*Response{"200": doc.JSONResponse("OK", []node{})}})
+	data, err := doc.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back map[string]any
+	if err := yaml.Unmarshal(data, &back); err != nil {
+		t.Fatalf("spec is not valid YAML: %v\n%s", err, data)
+	}
+	if back["openapi"] != Version || !strings.Contains(string(data), "$ref: '#/components/schemas/node'") {
+		t.Fatalf("unexpected YAML:\n%s", data)
+	}
+}
@@ -0,0 +1,80 @@
+package reason
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// resultCacheMaxAge bounds how long a cached result is served even when the
+// store has not changed, so time-sensitive presets (daily-digest) still
+// refresh once a day.
+const resultCacheMaxAge = 24 * time.Hour
+
+// resultCache is implemented by stores that can cache reason results
+// against their change sequence (SQLiteStore).
+type resultCache interface {
+	ChangeSeq(ctx context.Context) (int64, error)
+	GetReasonCache(ctx context.Context, key string, seq int64, maxAge time.Duration) (*store.ReasonCacheEntry, error)
+	PutReasonCache(ctx context.Context, e store.ReasonCacheEntry) error
+}
+
+// cacheKey hashes everything that shapes a reason result apart from the
+// store contents, which the change sequence covers: the preset as loaded
+// (so editing a preset file invalidates it), the query, scope, budgets,
+// and model.
+func (e *Engine) cacheKey(preset *Preset, opts ReasonOptions, maxTokens, contextBudget int, excluded []string) string {
+	excluded = append([]string(nil), excluded...)
+	sort.Strings(excluded)
+	provider, model := "", ""
+	if e.llm != nil {
+		provider, model = e.llm.provider, e.llm.model
+	}
+	data, _ := json.Marshal(struct {
+		Preset        Preset
+		Query         string
+		Project       string
+		MaxTokens     int
+		ContextBudget int
+		Excluded      []string
+		Provider      string
+		Model         string
+	}{*preset, opts.Query, opts.Project, maxTokens, contextBudget, excluded, provider, model})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// cachedResult returns the cached result for key at seq, or nil.
+func cachedResult(ctx context.Context, cache resultCache, key string, seq int64) *ReasonResult {
+	entry, err := cache.GetReasonCache(ctx, key, seq, resultCacheMaxAge)
+	if err != nil || entry == nil {
+		return nil
+	}
+	var result ReasonResult
+	if err := json.Unmarshal([]byte(entry.Result), &result); err != nil {
+		return nil
+	}
+	result.Cached = true
+	return &result
+}
+
+// storeResult caches result. Failures (e.g. a read-only store) only cost
+// the next run a fresh LLM call, so they are ignored.
+func storeResult(ctx context.Context, cache resultCache, key string, seq int64, result *ReasonResult) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	_ = cache.PutReasonCache(ctx, store.ReasonCacheEntry{
+		Key:       key,
+		Preset:    result.Preset,
+		Query:     result.Query,
+		ChangeSeq: seq,
+		Result:    string(data),
+	})
+}
@@ -0,0 +1,65 @@
+package reason
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+type countingGemini struct{ calls int }
+
+func (g *countingGemini) Name() string { return "google/gemini-2.5-flash" }
+
+func (g *countingGemini) Complete(context.Context, string, llm.CompletionOpts) (string, error) {
+	return "", nil
+}
+
+func (g *countingGemini) Chat(context.Context, []llm.Message, llm.CompletionOpts) (llm.ChatResult, error) {
+	g.calls++
+	return llm.ChatResult{Text: "## Summary\nDeploys are on Tuesday.", TokensIn: 500, TokensOut: 20}, nil
+}
+
+func TestReason_CachesUntilStoreChanges(t *testing.T) {
+	s, err := store.NewStore(store.StoreConfig{DBPath: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+	s.AddMemory(ctx, &store.Memory{Content: "Deploys happen on Tuesday", SourceFile: "ops.md", ContentHash: "a"})
+
+	stub := &countingGemini{}
+	engine := NewEngine(EngineConfig{
+		SearchEngine: search.NewEngine(s),
+		Store:        s,
+		LLM:          &LLM{provider: "gemini", model: "gemini-2.5-flash", gemini: stub},
+	})
+	opts := ReasonOptions{Query: "deploy day", Preset: "conflict-check"}
+
+	first, err := engine.Reason(ctx, opts)
+	if err != nil {
+		t.Fatalf("Reason: %v", err)
+	}
+	second, err := engine.Reason(ctx, opts)
+	if err != nil {
+		t.Fatalf("Reason: %v", err)
+	}
+	if stub.calls != 1 || first.Cached || !second.Cached || second.Content != first.Content || second.TokensIn != first.TokensIn {
+		t.Fatalf("expected the second run from cache: calls=%d first=%+v second=%+v", stub.calls, first, second)
+	}
+
+	if _, err := engine.Reason(ctx, ReasonOptions{Query: "deploy day?", Preset: "conflict-check"}); err != nil || stub.calls != 2 {
+		t.Fatalf("a different query should miss the cache: calls=%d err=%v", stub.calls, err)
+	}
+	if r, err := engine.Reason(ctx, ReasonOptions{Query: "deploy day", Preset: "conflict-check", NoCache: true}); err != nil || r.Cached || stub.calls != 3 {
+		t.Fatalf("NoCache should call the LLM: calls=%d err=%v", stub.calls, err)
+	}
+
+	s.AddMemory(ctx, &store.Memory{Content: "Deploys moved to Wednesday", SourceFile: "ops.md", ContentHash: "b"})
+	if r, err := engine.Reason(ctx, opts); err != nil || r.Cached || stub.calls != 4 {
+		t.Fatalf("a store change should invalidate the cache: calls=%d err=%v", stub.calls, err)
+	}
+}
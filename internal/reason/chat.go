@@ -0,0 +1,206 @@
+package reason
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const chatSystemPrompt = `You are a personal assistant with access to the user's memory store (Cortex).
+Each user turn comes with the memories and facts retrieved for it, plus anything the user pinned.
+Answer conversationally and concisely. Ground claims in the memories and name the source when
+you rely on one. If the memories do not cover the question, say so rather than guessing.
+Confidence scores precede each memory; treat low-confidence or STALE entries with caution.`
+
+// Chat defaults.
+const (
+	defaultChatHistoryTurns  = 6
+	defaultChatMaxTokens     = 768
+	defaultChatContextTokens = 2000
+	defaultChatSearchLimit   = 12
+	// followUpWords is the length at or under which a message is treated as
+	// a follow-up ("why?", "and the week before?") and searched together
+	// with the previous question.
+	followUpWords = 5
+)
+
+// ChatSession is a conversation with the reason engine: the turns so far,
+// pinned context, and how each turn is run.
+type ChatSession struct {
+	Project       string
+	MaxTokens     int // per reply (default 768)
+	ContextTokens int // retrieved-context budget per turn (default 2000)
+	HistoryTurns  int // prior exchanges sent with each turn (default 6)
+	SearchLimit   int // memories retrieved per turn (default 12)
+
+	// Capture stores each exchange as a memory under Source.
+	Capture bool
+	Source  string // default "chat/<start time>"
+
+	History []ChatMessage // user and assistant messages, oldest first
+	Pins    []string      // text included in every turn's context
+}
+
+// ChatReply is the answer to one chat turn.
+type ChatReply struct {
+	Content      string        `json:"content"`
+	Model        string        `json:"model"`
+	Provider     string        `json:"provider"`
+	MemoriesUsed int           `json:"memories_used"`
+	FactsUsed    int           `json:"facts_used"`
+	TokensIn     int           `json:"tokens_in"`
+	TokensOut    int           `json:"tokens_out"`
+	Duration     time.Duration `json:"duration"`
+	Citations    []Citation    `json:"citations"`
+	MemoryID     int64         `json:"memory_id,omitempty"` // captured exchange, when Capture is set
+
+	Confidence *store.AnswerConfidence `json:"confidence,omitempty"`
+}
+
+// ChatTurn answers message in the context of the session: it retrieves
+// memories for the message, sends them with the pins and recent history to
+// the LLM, and appends the exchange to the session history.
+func (e *Engine) ChatTurn(ctx context.Context, s *ChatSession, message string) (*ChatReply, error) {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, fmt.Errorf("message is empty")
+	}
+	start := time.Now()
+
+	limit := s.SearchLimit
+	if limit <= 0 {
+		limit = defaultChatSearchLimit
+	}
+	excluded := e.excludedProjects(nil)
+	results, err := e.searchEngine.Search(ctx, chatSearchQuery(s.History, message), search.Options{
+		Limit:           limit,
+		Project:         s.Project,
+		ExcludeProjects: excluded,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	if s.Source != "" {
+		// This session's captured turns are already in the history.
+		kept := results[:0]
+		for _, r := range results {
+			if r.SourceFile != s.Source {
+				kept = append(kept, r)
+			}
+		}
+		results = kept
+	}
+
+	family := e.tokenFamily()
+	budget := s.ContextTokens
+	if budget <= 0 {
+		budget = defaultChatContextTokens
+	}
+	contextStr, memoriesUsed := buildConfidenceContext(ctx, e.store, results, budget, family)
+	factsStr, factsUsed := gatherFacts(ctx, e.store, results, budget-family.Count(contextStr), family)
+
+	var prompt strings.Builder
+	if len(s.Pins) > 0 {
+		prompt.WriteString("--- Pinned ---\n")
+		for _, p := range s.Pins {
+			prompt.WriteString("- " + p + "\n")
+		}
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString("--- Memories ---\n" + contextStr)
+	if factsStr != "" {
+		prompt.WriteString("\n--- Extracted Facts ---\n" + factsStr)
+	}
+	prompt.WriteString("\n--- Message ---\n" + message)
+
+	turns := s.HistoryTurns
+	if turns <= 0 {
+		turns = defaultChatHistoryTurns
+	}
+	messages := []ChatMessage{{Role: "system", Content: chatSystemPrompt}}
+	messages = append(messages, recentHistory(s.History, turns)...)
+	messages = append(messages, ChatMessage{Role: "user", Content: prompt.String()})
+
+	maxTokens := s.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultChatMaxTokens
+	}
+	res, err := e.llm.ChatCached(ctx, messages, maxTokens, 1)
+	if err != nil {
+		return nil, fmt.Errorf("LLM call failed: %w", err)
+	}
+	content := strings.TrimSpace(res.Content)
+
+	// History keeps the bare message: the retrieved context is rebuilt for
+	// every turn and would only crowd out earlier exchanges.
+	s.History = append(s.History,
+		ChatMessage{Role: "user", Content: message},
+		ChatMessage{Role: "assistant", Content: content},
+	)
+
+	reply := &ChatReply{
+		Content:      content,
+		Model:        res.Model,
+		Provider:     res.Provider,
+		MemoriesUsed: memoriesUsed,
+		FactsUsed:    factsUsed,
+		TokensIn:     res.PromptTokens,
+		TokensOut:    res.CompletionTokens,
+		Citations:    buildCitations(results[:memoriesUsed]),
+	}
+	reply.Confidence = e.answerConfidence(ctx, citationMemoryIDs(reply.Citations))
+	if s.Capture {
+		id, err := e.captureExchange(ctx, s, message, content)
+		if err != nil {
+			return nil, err
+		}
+		reply.MemoryID = id
+	}
+	reply.Duration = time.Since(start)
+	return reply, nil
+}
+
+// chatSearchQuery is what a turn retrieves with. Short follow-ups carry too
+// little to search on alone, so they are joined to the previous question.
+func chatSearchQuery(history []ChatMessage, message string) string {
+	if len(strings.Fields(message)) > followUpWords {
+		return message
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role == "user" {
+			return history[i].Content + " " + message
+		}
+	}
+	return message
+}
+
+// recentHistory returns the last turns exchanges of history.
+func recentHistory(history []ChatMessage, turns int) []ChatMessage {
+	if n := 2 * turns; len(history) > n {
+		history = history[len(history)-n:]
+	}
+	return append([]ChatMessage(nil), history...)
+}
+
+// captureExchange stores one question and answer as a memory.
+func (e *Engine) captureExchange(ctx context.Context, s *ChatSession, message, reply string) (int64, error) {
+	if s.Source == "" {
+		s.Source = "chat/" + time.Now().UTC().Format("2006-01-02T15-04-05Z")
+	}
+	line := len(s.History) / 2
+	id, err := e.store.AddMemory(ctx, &store.Memory{
+		Content:     "User: " + message + "\n\nAssistant: " + reply,
+		SourceFile:  s.Source,
+		SourceLine:  line,
+		Project:     s.Project,
+		MemoryClass: store.DefaultClassForSource(s.Source),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("capturing chat turn: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,138 @@
+package reason
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// recordingGemini answers every chat with a fixed reply and keeps the
+// messages it was sent.
+type recordingGemini struct {
+	reply string
+	sent  [][]llm.Message
+}
+
+func (g *recordingGemini) Name() string { return "google/gemini-2.5-flash" }
+
+func (g *recordingGemini) Complete(context.Context, string, llm.CompletionOpts) (string, error) {
+	return "", nil
+}
+
+func (g *recordingGemini) Chat(_ context.Context, msgs []llm.Message, _ llm.CompletionOpts) (llm.ChatResult, error) {
+	g.sent = append(g.sent, msgs)
+	return llm.ChatResult{Text: g.reply, TokensIn: 100, TokensOut: 10}, nil
+}
+
+func newChatTestEngine(t *testing.T, stub *recordingGemini) (*Engine, store.Store) {
+	t.Helper()
+	s, err := store.NewStore(store.StoreConfig{DBPath: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	s.AddMemory(context.Background(), &store.Memory{Content: "Deploys happen on Tuesday after the standup", SourceFile: "ops.md"})
+	return NewEngine(EngineConfig{
+		SearchEngine: search.NewEngine(s),
+		Store:        s,
+		LLM:          &LLM{provider: "gemini", model: "gemini-2.5-flash", gemini: stub},
+	}), s
+}
+
+func TestChatTurn_HistoryPinsAndContext(t *testing.T) {
+	stub := &recordingGemini{reply: "Tuesdays [1]."}
+	engine, _ := newChatTestEngine(t, stub)
+	ctx := context.Background()
+	sess := &ChatSession{Pins: []string{"I am on call this week"}}
+
+	reply, err := engine.ChatTurn(ctx, sess, "Which day do deploys happen?")
+	if err != nil {
+		t.Fatalf("ChatTurn: %v", err)
+	}
+	if reply.Content != "Tuesdays [1]." || reply.MemoriesUsed != 1 || len(reply.Citations) != 1 {
+		t.Fatalf("unexpected reply: %+v", reply)
+	}
+	if reply.Confidence == nil || reply.Confidence.Band != store.ConfidenceBandUnknown {
+		t.Fatalf("a memory without facts should grade as unknown, got %+v", reply.Confidence)
+	}
+	first := stub.sent[0]
+	if len(first) != 2 || first[0].Role != "system" {
+		t.Fatalf("first turn should be system + user, got %+v", first)
+	}
+	user := first[1].Content
+	for _, want := range []string{"I am on call this week", "Deploys happen on Tuesday", "Which day do deploys happen?"} {
+		if !strings.Contains(user, want) {
+			t.Errorf("prompt missing %q:\n%s", want, user)
+		}
+	}
+
+	if _, err := engine.ChatTurn(ctx, sess, "why?"); err != nil {
+		t.Fatalf("second turn: %v", err)
+	}
+	second := stub.sent[1]
+	if len(second) != 4 || second[1].Content != "Which day do deploys happen?" || second[2].Content != "Tuesdays [1]." {
+		t.Fatalf("second turn should carry the first exchange without its context, got %+v", second)
+	}
+	if !strings.Contains(second[3].Content, "Deploys happen on Tuesday") {
+		t.Fatalf("follow-up should retrieve with the previous question:\n%s", second[3].Content)
+	}
+	if len(sess.History) != 4 {
+		t.Fatalf("history has %d messages, want 4", len(sess.History))
+	}
+
+	if _, err := engine.ChatTurn(ctx, sess, "  "); err == nil {
+		t.Fatal("expected error for an empty message")
+	}
+}
+
+func TestChatTurn_Capture(t *testing.T) {
+	stub := &recordingGemini{reply: "Tuesday."}
+	engine, s := newChatTestEngine(t, stub)
+	ctx := context.Background()
+	sess := &ChatSession{Capture: true, Project: "ops"}
+
+	reply, err := engine.ChatTurn(ctx, sess, "When are deploys?")
+	if err != nil {
+		t.Fatalf("ChatTurn: %v", err)
+	}
+	if reply.MemoryID == 0 || !strings.HasPrefix(sess.Source, "chat/") {
+		t.Fatalf("exchange not captured: reply=%+v source=%q", reply, sess.Source)
+	}
+	m, err := s.GetMemory(ctx, reply.MemoryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Content != "User: When are deploys?\n\nAssistant: Tuesday." || m.Project != "ops" || m.SourceFile != sess.Source {
+		t.Fatalf("unexpected captured memory: %+v", m)
+	}
+
+	// The captured turn matches the next question, but it is already in
+	// the history, so it is not retrieved again.
+	sess.Project = ""
+	if _, err := engine.ChatTurn(ctx, sess, "When are deploys again this week?"); err != nil {
+		t.Fatalf("second turn: %v", err)
+	}
+	if prompt := stub.sent[1][len(stub.sent[1])-1].Content; strings.Contains(prompt, "User: When are deploys?") {
+		t.Fatalf("session's own captured turns should not be retrieved:\n%s", prompt)
+	}
+}
+
+func TestRecentHistory(t *testing.T) {
+	var history []ChatMessage
+	for i := 0; i < 10; i++ {
+		history = append(history, ChatMessage{Role: "user", Content: "q"}, ChatMessage{Role: "assistant", Content: "a"})
+	}
+	if got := recentHistory(history, 3); len(got) != 6 {
+		t.Fatalf("recentHistory kept %d messages, want 6", len(got))
+	}
+	if got := chatSearchQuery([]ChatMessage{{Role: "user", Content: "deploy schedule"}, {Role: "assistant", Content: "Tuesday"}}, "why?"); got != "deploy schedule why?" {
+		t.Fatalf("chatSearchQuery = %q", got)
+	}
+	if got := chatSearchQuery(nil, "a question long enough to search on its own"); got != "a question long enough to search on its own" {
+		t.Fatalf("chatSearchQuery = %q", got)
+	}
+}
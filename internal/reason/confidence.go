@@ -0,0 +1,38 @@
+package reason
+
+import (
+	"context"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// answerConfidencer is implemented by stores that can grade the facts
+// behind an answer (*store.SQLiteStore).
+type answerConfidencer interface {
+	AnswerConfidence(ctx context.Context, memoryIDs []int64) (*store.AnswerConfidence, error)
+}
+
+// answerConfidence grades the facts extracted from the evidence memories.
+// It returns nil when the store cannot, or the evidence is empty: a missing
+// grade is better than a failed answer.
+func (e *Engine) answerConfidence(ctx context.Context, memoryIDs []int64) *store.AnswerConfidence {
+	ac, ok := e.store.(answerConfidencer)
+	if !ok || len(memoryIDs) == 0 {
+		return nil
+	}
+	conf, err := ac.AnswerConfidence(ctx, memoryIDs)
+	if err != nil {
+		return nil
+	}
+	return conf
+}
+
+func citationMemoryIDs(citations []Citation) []int64 {
+	ids := make([]int64, 0, len(citations))
+	for _, c := range citations {
+		if c.MemoryID > 0 {
+			ids = append(ids, c.MemoryID)
+		}
+	}
+	return ids
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/hurttlocker/cortex/internal/search"
 	"github.com/hurttlocker/cortex/internal/store"
+	"github.com/hurttlocker/cortex/internal/tokens"
 )
 
 // Engine orchestrates the search → prompt → LLM → output pipeline.
@@ -40,12 +41,17 @@ type EngineConfig struct {
 
 // ReasonOptions configures a reasoning request.
 type ReasonOptions struct {
-	Query      string // The question or topic
-	Preset     string // Preset name (default: "daily-digest")
-	Project    string // Scope to project (empty = all)
-	MaxTokens  int    // Override preset max_tokens
-	MaxContext int    // Max context chars to send to LLM (default: 8000)
-	JSONOutput bool   // Output as JSON
+	Query         string // The question or topic
+	Preset        string // Preset name (default: "daily-digest")
+	Project       string // Scope to project (empty = all)
+	MaxTokens     int    // Override preset max_tokens
+	MaxContext    int    // Max context chars to send to LLM (default: 8000); converted to tokens at chars/4
+	ContextTokens int    // Context token budget; overrides MaxContext when set
+	JSONOutput    bool   // Output as JSON
+	NoCache       bool   // Skip the result cache and always call the LLM
+	// ExcludeProjects keeps these projects' memories out of the context.
+	// Local-llm-only projects are always excluded for remote models.
+	ExcludeProjects []string
 }
 
 // ReasonResult holds the output of a reasoning run.
@@ -63,6 +69,24 @@ type ReasonResult struct {
 	LLMTime      time.Duration `json:"llm_time"`
 	TokensIn     int           `json:"tokens_in"`
 	TokensOut    int           `json:"tokens_out"`
+	CacheHits    int           `json:"cache_hits,omitempty"`    // LLM calls served from a context cache
+	CachedTokens int           `json:"cached_tokens,omitempty"` // prompt tokens served from cache
+	Cached       bool          `json:"cached,omitempty"`        // whole result served from the reason cache; no LLM call
+	Citations    []Citation    `json:"citations"`
+	// Confidence grades the facts behind the evidence memories, flagging
+	// answers that rest on stale or conflicting knowledge.
+	Confidence *store.AnswerConfidence `json:"confidence,omitempty"`
+}
+
+// Citation is a memory that was included in the reasoning context, in the
+// order it was given to the LLM.
+type Citation struct {
+	Index    int     `json:"index"`
+	Source   string  `json:"source"`
+	Title    string  `json:"title"`
+	Score    float64 `json:"score"`
+	MemoryID int64   `json:"memory_id"`
+	Project  string  `json:"project,omitempty"`
 }
 
 // NewEngine creates a new reasoning engine.
@@ -95,16 +119,33 @@ func (e *Engine) Reason(ctx context.Context, opts ReasonOptions) (*ReasonResult,
 		maxTokens = opts.MaxTokens
 	}
 
-	maxContext := opts.MaxContext
-	if maxContext <= 0 {
-		maxContext = 8000 // Safe default for small models
+	contextBudget := contextTokenBudget(opts.ContextTokens, opts.MaxContext)
+	family := e.tokenFamily()
+
+	excluded := e.excludedProjects(opts.ExcludeProjects)
+
+	// An identical run over an unchanged store gives the same answer, so
+	// serve it from the cache instead of paying for the LLM again.
+	var cache resultCache
+	var cacheSeq int64
+	var cacheKey string
+	if c, ok := e.store.(resultCache); ok && !opts.NoCache {
+		if seq, err := c.ChangeSeq(ctx); err == nil {
+			cache, cacheSeq = c, seq
+			cacheKey = e.cacheKey(preset, opts, maxTokens, contextBudget, excluded)
+			if hit := cachedResult(ctx, cache, cacheKey, cacheSeq); hit != nil {
+				hit.Duration = time.Since(start)
+				return hit, nil
+			}
+		}
 	}
 
 	// 2. Search for relevant context
 	searchStart := time.Now()
 	searchOpts := search.Options{
-		Limit:   preset.SearchLimit,
-		Project: opts.Project,
+		Limit:           preset.SearchLimit,
+		Project:         opts.Project,
+		ExcludeProjects: excluded,
 	}
 
 	// Parse search mode
@@ -130,14 +171,15 @@ func (e *Engine) Reason(ctx context.Context, opts ReasonOptions) (*ReasonResult,
 		if err != nil {
 			return nil, fmt.Errorf("loading fallback context: %w", err)
 		}
+		results = filterResultsByProject(results, excluded)
 	}
 	searchTime := time.Since(searchStart)
 
 	// 3. Build confidence-aware context
-	contextStr, memoriesUsed := buildConfidenceContext(ctx, e.store, results, maxContext)
+	contextStr, memoriesUsed := buildConfidenceContext(ctx, e.store, results, contextBudget, family)
 
 	// 4. Gather relevant facts
-	factsStr, factsUsed := gatherFacts(ctx, e.store, results, maxContext-len(contextStr))
+	factsStr, factsUsed := gatherFacts(ctx, e.store, results, contextBudget-family.Count(contextStr), family)
 
 	// 5. Build the prompt
 	fullContext := contextStr
@@ -160,7 +202,8 @@ func (e *Engine) Reason(ctx context.Context, opts ReasonOptions) (*ReasonResult,
 	}
 
 	llmStart := time.Now()
-	llmResult, err := e.llm.Chat(ctx, messages, maxTokens)
+	// The system prompt is the same for every run of a preset.
+	llmResult, err := e.llm.ChatCached(ctx, messages, maxTokens, 1)
 	if err != nil {
 		return nil, fmt.Errorf("LLM call failed: %w", err)
 	}
@@ -168,7 +211,7 @@ func (e *Engine) Reason(ctx context.Context, opts ReasonOptions) (*ReasonResult,
 
 	finalContent := enforceResponseQualityContract(llmResult.Content, query)
 
-	return &ReasonResult{
+	result := &ReasonResult{
 		Content:      finalContent,
 		Preset:       presetName,
 		Query:        query,
@@ -182,7 +225,44 @@ func (e *Engine) Reason(ctx context.Context, opts ReasonOptions) (*ReasonResult,
 		LLMTime:      llmTime,
 		TokensIn:     llmResult.PromptTokens,
 		TokensOut:    llmResult.CompletionTokens,
-	}, nil
+		CacheHits:    boolToInt(llmResult.CacheHit),
+		CachedTokens: llmResult.CachedTokens,
+		Citations:    buildCitations(results[:memoriesUsed]),
+	}
+	result.Confidence = e.answerConfidence(ctx, citationMemoryIDs(result.Citations))
+	if cache != nil {
+		storeResult(ctx, cache, cacheKey, cacheSeq, result)
+	}
+	return result, nil
+}
+
+// buildCitations lists the memories buildConfidenceContext put in the
+// prompt. It stops at the first entry over budget, so they are a prefix of
+// the search results.
+func buildCitations(results []search.Result) []Citation {
+	citations := make([]Citation, 0, len(results))
+	for i, r := range results {
+		source := r.SourceFile
+		if source == "" {
+			source = "(unknown source)"
+		}
+		if r.SourceLine > 0 {
+			source = fmt.Sprintf("%s:%d", source, r.SourceLine)
+		}
+		title := search.CitationTitleForResult(r)
+		if title == "" {
+			title = source
+		}
+		citations = append(citations, Citation{
+			Index:    i + 1,
+			Source:   source,
+			Title:    title,
+			Score:    r.Score,
+			MemoryID: r.MemoryID,
+			Project:  r.Project,
+		})
+	}
+	return citations
 }
 
 func (e *Engine) fallbackRecentResults(ctx context.Context, limit int, project string) ([]search.Result, error) {
@@ -218,16 +298,37 @@ func (e *Engine) fallbackRecentResults(ctx context.Context, limit int, project s
 	return results, nil
 }
 
+// contextTokenBudget returns the context budget in tokens: contextTokens when
+// set, otherwise the legacy character limit (default 8000) at chars/4.
+func contextTokenBudget(contextTokens, maxContextChars int) int {
+	if contextTokens > 0 {
+		return contextTokens
+	}
+	if maxContextChars <= 0 {
+		maxContextChars = 8000 // Safe default for small models
+	}
+	return maxContextChars / 4
+}
+
+// tokenFamily is the tokenizer family of the engine's model, used to count
+// the context against its budget.
+func (e *Engine) tokenFamily() tokens.Family {
+	if e.llm == nil {
+		return tokens.Default
+	}
+	return tokens.ForModel(e.llm.model)
+}
+
 // buildConfidenceContext creates a context string with confidence annotations.
 // This is the key differentiator — the LLM sees confidence scores and can
 // weight its reasoning accordingly.
-func buildConfidenceContext(ctx context.Context, st store.Store, results []search.Result, maxChars int) (string, int) {
+func buildConfidenceContext(ctx context.Context, st store.Store, results []search.Result, maxTokens int, family tokens.Family) (string, int) {
 	if len(results) == 0 {
 		return "(no memories found)", 0
 	}
 
 	var sb strings.Builder
-	used := 0
+	used, usedTokens := 0, 0
 
 	for _, r := range results {
 		// Get confidence for this memory's facts
@@ -250,10 +351,12 @@ func buildConfidenceContext(ctx context.Context, st store.Store, results []searc
 		}
 		entry += "\n\n"
 
-		if sb.Len()+len(entry) > maxChars {
+		entryTokens := family.Count(entry)
+		if usedTokens+entryTokens > maxTokens {
 			break
 		}
 		sb.WriteString(entry)
+		usedTokens += entryTokens
 		used++
 	}
 
@@ -285,8 +388,8 @@ func estimateConfidence(ctx context.Context, st store.Store, memoryID int64) flo
 }
 
 // gatherFacts collects relevant extracted facts for additional context.
-func gatherFacts(ctx context.Context, st store.Store, results []search.Result, maxChars int) (string, int) {
-	if maxChars <= 0 || len(results) == 0 {
+func gatherFacts(ctx context.Context, st store.Store, results []search.Result, maxTokens int, family tokens.Family) (string, int) {
+	if maxTokens <= 0 || len(results) == 0 {
 		return "", 0
 	}
 
@@ -318,14 +421,16 @@ func gatherFacts(ctx context.Context, st store.Store, results []search.Result, m
 	})
 
 	var sb strings.Builder
-	used := 0
+	used, usedTokens := 0, 0
 	for _, sf := range relevant {
 		entry := fmt.Sprintf("[%.2f] %s: %s %s %s\n",
 			sf.fact.Confidence, sf.fact.FactType, sf.fact.Subject, sf.fact.Predicate, sf.fact.Object)
-		if sb.Len()+len(entry) > maxChars {
+		entryTokens := family.Count(entry)
+		if usedTokens+entryTokens > maxTokens {
 			break
 		}
 		sb.WriteString(entry)
+		usedTokens += entryTokens
 		used++
 	}
 
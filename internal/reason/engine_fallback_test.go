@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/hurttlocker/cortex/internal/search"
 	"github.com/hurttlocker/cortex/internal/store"
 )
 
@@ -39,3 +40,19 @@ func TestFallbackRecentResults(t *testing.T) {
 		t.Fatal("expected non-empty fallback content")
 	}
 }
+
+func TestBuildCitations(t *testing.T) {
+	citations := buildCitations([]search.Result{
+		{MemoryID: 7, SourceFile: "notes/ops.md", SourceLine: 12, SourceSection: "Deploys", Score: 0.8, Project: "ops"},
+		{MemoryID: 9, Score: 0.4},
+	})
+	if len(citations) != 2 {
+		t.Fatalf("expected 2 citations, got %d", len(citations))
+	}
+	if c := citations[0]; c.Index != 1 || c.Source != "notes/ops.md:12" || c.Title != "Deploys" || c.MemoryID != 7 || c.Project != "ops" {
+		t.Fatalf("unexpected first citation: %+v", c)
+	}
+	if c := citations[1]; c.Index != 2 || c.Source != "(unknown source)" || c.Title != c.Source {
+		t.Fatalf("unexpected second citation: %+v", c)
+	}
+}
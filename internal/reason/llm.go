@@ -1,5 +1,6 @@
 // Package reason provides LLM-powered reasoning over Cortex memories.
-// Supports local (ollama) and cloud (openrouter) providers via OpenAI-compatible chat API.
+// Supports local (ollama) and cloud (openrouter) providers via OpenAI-compatible chat API,
+// and native Gemini ("gemini/<model>") with context caching.
 package reason
 
 import (
@@ -12,15 +13,20 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+	"github.com/hurttlocker/cortex/internal/timings"
 )
 
 // LLM is a chat completion client for reasoning tasks.
 type LLM struct {
-	provider string // "ollama", "openrouter"
+	provider string // "ollama", "openrouter", "gemini"
 	model    string
 	endpoint string
 	apiKey   string
 	client   *http.Client
+	gemini   llm.ChatProvider // native Gemini; nil for OpenAI-compatible providers
+	scrubber *llm.Scrubber    // masks secrets for cloud providers; nil for ollama
 }
 
 // Default models for different use cases.
@@ -32,7 +38,7 @@ const (
 
 // LLMConfig configures an LLM provider.
 type LLMConfig struct {
-	Provider string // "ollama" or "openrouter"
+	Provider string // "ollama", "openrouter", or "gemini"
 	Model    string // e.g., "phi4-mini", "minimax/minimax-m2.5"
 	APIKey   string // required for openrouter
 }
@@ -74,6 +80,8 @@ type LLMResult struct {
 	Provider         string        `json:"provider"`
 	PromptTokens     int           `json:"prompt_tokens"`
 	CompletionTokens int           `json:"completion_tokens"`
+	CachedTokens     int           `json:"cached_tokens,omitempty"` // prompt tokens served from a context cache
+	CacheHit         bool          `json:"cache_hit,omitempty"`
 	Duration         time.Duration `json:"duration"`
 }
 
@@ -103,18 +111,71 @@ func NewLLM(cfg LLMConfig) (*LLM, error) {
 		if l.apiKey == "" {
 			return nil, fmt.Errorf("openrouter requires OPENROUTER_API_KEY env var or --api-key flag")
 		}
+	case "gemini":
+		g, err := llm.NewGemini(llm.Config{Model: cfg.Model, APIKey: cfg.APIKey})
+		if err != nil {
+			return nil, err
+		}
+		l.gemini = g
 	default:
-		return nil, fmt.Errorf("unsupported LLM provider: %q (use 'ollama' or 'openrouter')", cfg.Provider)
+		return nil, fmt.Errorf("unsupported LLM provider: %q (use 'ollama', 'openrouter', or 'gemini')", cfg.Provider)
+	}
+	if !l.IsLocal() {
+		l.scrubber = llm.NewScrubber()
 	}
 
 	return l, nil
 }
 
+// ScrubReport returns what has been masked from prompts sent to a cloud
+// provider so far.
+func (l *LLM) ScrubReport() llm.ScrubReport {
+	if l == nil || l.scrubber == nil {
+		return llm.ScrubReport{}
+	}
+	return l.scrubber.Report()
+}
+
 // Chat sends a chat completion request and returns the result.
 func (l *LLM) Chat(ctx context.Context, messages []ChatMessage, maxTokens int) (*LLMResult, error) {
+	return l.ChatCached(ctx, messages, maxTokens, 0)
+}
+
+// ChatCached is Chat with a hint that the first cachePrefix messages will be
+// resent soon (a preset's system prompt, a recursive run's initial context).
+// Providers with context caching serve that prefix from cache; others
+// ignore the hint.
+//
+// For cloud providers, secrets in the messages are replaced by stable
+// placeholders before sending and restored in the reply.
+func (l *LLM) ChatCached(ctx context.Context, messages []ChatMessage, maxTokens, cachePrefix int) (*LLMResult, error) {
+	if l.scrubber == nil {
+		return l.chat(ctx, messages, maxTokens, cachePrefix)
+	}
+	contents := make([]string, len(messages))
+	for i, m := range messages {
+		contents[i] = m.Content
+	}
+	contents = l.scrubber.ScrubParts(contents...)
+	scrubbed := make([]ChatMessage, len(messages))
+	for i, m := range messages {
+		m.Content = contents[i]
+		scrubbed[i] = m
+	}
+	result, err := l.chat(ctx, scrubbed, maxTokens, cachePrefix)
+	if result != nil {
+		result.Content = l.scrubber.Restore(result.Content)
+	}
+	return result, err
+}
+
+func (l *LLM) chat(ctx context.Context, messages []ChatMessage, maxTokens, cachePrefix int) (_ *LLMResult, err error) {
 	if maxTokens <= 0 {
 		maxTokens = 1024
 	}
+	if l.gemini != nil {
+		return l.chatGemini(ctx, messages, maxTokens, cachePrefix)
+	}
 
 	req := ChatRequest{
 		Model:       l.model,
@@ -143,6 +204,8 @@ func (l *LLM) Chat(ctx context.Context, messages []ChatMessage, maxTokens int) (
 		httpReq.Header.Set("X-Title", "Cortex Reason")
 	}
 
+	call := timings.Begin(timings.LLM, l.provider, l.model)
+	defer func() { call.Fail(err); call.End() }()
 	start := time.Now()
 	resp, err := l.client.Do(httpReq)
 	if err != nil {
@@ -169,6 +232,7 @@ func (l *LLM) Chat(ctx context.Context, messages []ChatMessage, maxTokens int) (
 		return nil, fmt.Errorf("LLM returned no choices")
 	}
 
+	call.Tokens(chatResp.Usage.PromptTokens, chatResp.Usage.CompletionTokens)
 	content := chatResp.Choices[0].Message.Content
 
 	// Strip qwen3's <think>...</think> blocks if present
@@ -184,6 +248,34 @@ func (l *LLM) Chat(ctx context.Context, messages []ChatMessage, maxTokens int) (
 	}, nil
 }
 
+// chatGemini runs the request through the native Gemini provider, which
+// records its own timings.
+func (l *LLM) chatGemini(ctx context.Context, messages []ChatMessage, maxTokens, cachePrefix int) (*LLMResult, error) {
+	converted := make([]llm.Message, len(messages))
+	for i, m := range messages {
+		converted[i] = llm.Message{Role: m.Role, Content: m.Content}
+	}
+	start := time.Now()
+	res, err := l.gemini.Chat(ctx, converted, llm.CompletionOpts{
+		MaxTokens:   maxTokens,
+		Temperature: 0.3,
+		CachePrefix: cachePrefix,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LLM request failed: %w", err)
+	}
+	return &LLMResult{
+		Content:          strings.TrimSpace(stripThinkingTags(res.Text)),
+		Model:            l.model,
+		Provider:         l.provider,
+		PromptTokens:     res.TokensIn,
+		CompletionTokens: res.TokensOut,
+		CachedTokens:     res.CachedTokens,
+		CacheHit:         res.CacheHit,
+		Duration:         time.Since(start),
+	}, nil
+}
+
 // ParseProviderModel splits "provider/model" into provider and model.
 // If no "/" is found, assumes ollama as the provider.
 func ParseProviderModel(s string) (provider, model string) {
@@ -191,7 +283,7 @@ func ParseProviderModel(s string) (provider, model string) {
 		provider = s[:i]
 		model = s[i+1:]
 		// Handle openrouter's double-slash models like "minimax/minimax-m2.5"
-		if provider != "ollama" && provider != "openrouter" {
+		if provider != "ollama" && provider != "openrouter" && provider != "gemini" {
 			// Likely an openrouter model like "minimax/minimax-m2.5"
 			provider = "openrouter"
 			model = s
@@ -227,3 +319,10 @@ func truncate(s string, max int) string {
 	}
 	return s[:max] + "..."
 }
+
+func boolToInt(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
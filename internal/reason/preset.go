@@ -128,13 +128,20 @@ Be specific and cite evidence from the context.`,
 	},
 }
 
-// LoadCustomPresets reads user-defined presets from ~/.cortex/presets.yaml.
+// LoadCustomPresets reads user-defined presets: installed presets from
+// ~/.cortex/presets/*.yaml, then ~/.cortex/presets.yaml, whose entries win
+// on a name clash.
 func LoadCustomPresets(configDir string) (map[string]Preset, error) {
+	installed, err := loadInstalledPresets(PresetsDir(configDir))
+	if err != nil {
+		return nil, err
+	}
+
 	path := filepath.Join(configDir, "presets.yaml")
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, nil // No custom presets
+			return installed, nil // No presets.yaml
 		}
 		return nil, fmt.Errorf("reading %s: %w", path, err)
 	}
@@ -143,7 +150,33 @@ func LoadCustomPresets(configDir string) (map[string]Preset, error) {
 	if err := yaml.Unmarshal(data, &presets); err != nil {
 		return nil, fmt.Errorf("parsing %s: %w", path, err)
 	}
+	if len(installed) == 0 {
+		return presets, nil
+	}
+	for name, p := range presets {
+		installed[name] = p
+	}
+	return installed, nil
+}
 
+// loadInstalledPresets reads one preset per *.yaml file in dir.
+func loadInstalledPresets(dir string) (map[string]Preset, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil || len(paths) == 0 {
+		return nil, err
+	}
+	presets := make(map[string]Preset, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		p, err := ParsePreset(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		presets[p.Name] = p
+	}
 	return presets, nil
 }
 
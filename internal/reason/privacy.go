@@ -0,0 +1,61 @@
+package reason
+
+import (
+	"context"
+
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// IsLocal reports whether the model runs on this machine (ollama), so
+// content from local-llm-only projects may be sent to it.
+func (l *LLM) IsLocal() bool {
+	return l != nil && l.provider == "ollama"
+}
+
+// excludedProjects returns the projects whose memories must not reach the
+// engine's model: local-llm-only zones unless the model is local, plus extra
+// (e.g. no-mcp zones when called over MCP).
+func (e *Engine) excludedProjects(extra []string) []string {
+	out := append([]string(nil), extra...)
+	if !e.llm.IsLocal() {
+		out = append(out, store.ProjectsRestricting(store.PrivacyLocalLLMOnly)...)
+	}
+	return out
+}
+
+// filterResultsByProject drops results from excluded projects.
+func filterResultsByProject(results []search.Result, excluded []string) []search.Result {
+	if len(excluded) == 0 {
+		return results
+	}
+	skip := make(map[string]bool, len(excluded))
+	for _, p := range excluded {
+		skip[p] = true
+	}
+	kept := results[:0:0]
+	for _, r := range results {
+		if !skip[r.Project] {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// memoryAllowed reports whether memory id may be shown to the model.
+// Unknown memories are allowed; the caller reports them as not found.
+func (e *Engine) memoryAllowed(ctx context.Context, id int64, excluded []string) bool {
+	if len(excluded) == 0 {
+		return true
+	}
+	mem, err := e.store.GetMemory(ctx, id)
+	if err != nil || mem == nil {
+		return true
+	}
+	for _, p := range excluded {
+		if mem.Project == p {
+			return false
+		}
+	}
+	return true
+}
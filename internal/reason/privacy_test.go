@@ -0,0 +1,60 @@
+package reason
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/search"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestExcludedProjects_LocalLLMOnly(t *testing.T) {
+	si, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("creating store: %v", err)
+	}
+	defer si.Close()
+	s := si.(*store.SQLiteStore)
+	ctx := context.Background()
+
+	if err := s.SetProjectPrivacy(ctx, store.ProjectPrivacy{Project: "client-x", LocalLLMOnly: true}); err != nil {
+		t.Fatalf("SetProjectPrivacy: %v", err)
+	}
+	defer s.SetProjectPrivacy(ctx, store.ProjectPrivacy{Project: "client-x"})
+
+	local, err := NewLLM(LLMConfig{Provider: "ollama", Model: "phi4-mini"})
+	if err != nil {
+		t.Fatalf("NewLLM: %v", err)
+	}
+	if got := (&Engine{llm: local}).excludedProjects([]string{"personal"}); len(got) != 1 || got[0] != "personal" {
+		t.Fatalf("local model should only exclude extra projects, got %v", got)
+	}
+
+	remote := &LLM{provider: "openrouter"}
+	got := (&Engine{llm: remote}).excludedProjects(nil)
+	if len(got) != 1 || got[0] != "client-x" {
+		t.Fatalf("remote model should exclude local-llm-only projects, got %v", got)
+	}
+	if got := (&Engine{}).excludedProjects(nil); len(got) != 1 {
+		t.Fatalf("engine without a model should exclude local-llm-only projects, got %v", got)
+	}
+}
+
+func TestFilterResultsByProject(t *testing.T) {
+	results := []search.Result{
+		{MemoryID: 1, Project: "work"},
+		{MemoryID: 2, Project: "client-x"},
+		{MemoryID: 3},
+	}
+	if got := filterResultsByProject(results, nil); len(got) != 3 {
+		t.Fatalf("no exclusions should keep everything, got %d", len(got))
+	}
+	got := filterResultsByProject(results, []string{"client-x"})
+	if len(got) != 2 || got[0].MemoryID != 1 || got[1].MemoryID != 3 {
+		t.Fatalf("unexpected filtered results: %+v", got)
+	}
+	if len(results) != 3 || results[1].MemoryID != 2 {
+		t.Fatal("filterResultsByProject modified its input")
+	}
+}
@@ -1,6 +1,15 @@
 package reason
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/llm"
+)
 
 func TestParseProviderModel(t *testing.T) {
 	tests := []struct {
@@ -13,6 +22,7 @@ func TestParseProviderModel(t *testing.T) {
 		{"ollama/phi4-mini", "ollama", "phi4-mini"},
 		{"openrouter/deepseek/deepseek-chat", "openrouter", "deepseek/deepseek-chat"},
 		{"google/gemini-2.5-flash", "openrouter", "google/gemini-2.5-flash"},
+		{"gemini/gemini-2.5-flash", "gemini", "gemini-2.5-flash"},
 		{"minimax/minimax-m2.5", "openrouter", "minimax/minimax-m2.5"},
 		{"deepseek/deepseek-chat", "openrouter", "deepseek/deepseek-chat"},
 		{"x-ai/grok-4.1-fast", "openrouter", "x-ai/grok-4.1-fast"},
@@ -127,3 +137,67 @@ func TestListPresets(t *testing.T) {
 		t.Errorf("ListPresets returned %d, want %d", len(presets), len(BuiltinPresets))
 	}
 }
+
+type stubGemini struct {
+	opts     llm.CompletionOpts
+	messages []llm.Message
+}
+
+func (g *stubGemini) Name() string { return "google/gemini-2.5-flash" }
+
+func (g *stubGemini) Complete(context.Context, string, llm.CompletionOpts) (string, error) {
+	return "", nil
+}
+
+func (g *stubGemini) Chat(_ context.Context, messages []llm.Message, opts llm.CompletionOpts) (llm.ChatResult, error) {
+	g.messages, g.opts = messages, opts
+	return llm.ChatResult{Text: "<think>x</think> answer", TokensIn: 2000, TokensOut: 50, CachedTokens: 1800, CacheHit: true}, nil
+}
+
+func TestLLMChatCached_Gemini(t *testing.T) {
+	stub := &stubGemini{}
+	l := &LLM{provider: "gemini", model: "gemini-2.5-flash", gemini: stub}
+	res, err := l.ChatCached(context.Background(), []ChatMessage{
+		{Role: "system", Content: "sys"},
+		{Role: "user", Content: "q"},
+	}, 0, 1)
+	if err != nil {
+		t.Fatalf("ChatCached: %v", err)
+	}
+	if stub.opts.CachePrefix != 1 || stub.opts.MaxTokens != 1024 || len(stub.messages) != 2 || stub.messages[0].Role != "system" {
+		t.Fatalf("unexpected request: %+v %+v", stub.opts, stub.messages)
+	}
+	if res.Content != "answer" || !res.CacheHit || res.CachedTokens != 1800 || res.Provider != "gemini" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestChatCached_ScrubsCloudPrompts(t *testing.T) {
+	var sent ChatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Write([]byte(`{"choices":[{"message":{"content":"Rotate [REDACTED_API_KEY_1] now."}}]}`))
+	}))
+	defer srv.Close()
+
+	l := &LLM{provider: "openrouter", model: "test", endpoint: srv.URL, client: srv.Client(), scrubber: llm.NewScrubber()}
+	key := "sk-or-v1-0123456789abcdef0123456789abcdef"
+	res, err := l.Chat(context.Background(), []ChatMessage{{Role: "user", Content: "Our key is " + key}}, 100)
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if strings.Contains(sent.Messages[0].Content, key) {
+		t.Fatalf("key reached the provider: %q", sent.Messages[0].Content)
+	}
+	if res.Content != "Rotate "+key+" now." {
+		t.Fatalf("placeholder not restored: %q", res.Content)
+	}
+	if r := l.ScrubReport(); r.ByKind["api_key"] != 1 {
+		t.Fatalf("unexpected scrub report: %+v", r)
+	}
+
+	local, _ := NewLLM(LLMConfig{Provider: "ollama", Model: "phi4-mini"})
+	if local.scrubber != nil {
+		t.Fatal("ollama prompts should not be scrubbed")
+	}
+}
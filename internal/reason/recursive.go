@@ -28,9 +28,12 @@ type RecursiveOptions struct {
 	MaxIterations int    // Max loop iterations (default: 8)
 	MaxDepth      int    // Max recursion depth for SUB_QUERY (default: 1)
 	MaxTokens     int    // Max tokens per LLM call
-	MaxContext    int    // Max context chars
+	MaxContext    int    // Max context chars; converted to tokens at chars/4
 	JSONOutput    bool   // Output as JSON
 	Verbose       bool   // Print iteration progress
+	// ExcludeProjects keeps these projects' memories out of the context.
+	// Local-llm-only projects are always excluded for remote models.
+	ExcludeProjects []string
 }
 
 // RecursiveResult extends ReasonResult with recursion metadata.
@@ -135,6 +138,8 @@ func (e *Engine) reasonRecursiveAtDepth(ctx context.Context, opts RecursiveOptio
 	if maxContext <= 0 {
 		maxContext = 8000
 	}
+	contextBudget := contextTokenBudget(0, maxContext)
+	family := e.tokenFamily()
 
 	// Load preset
 	presetName := opts.Preset
@@ -153,9 +158,11 @@ func (e *Engine) reasonRecursiveAtDepth(ctx context.Context, opts RecursiveOptio
 
 	// Initial search
 	searchStart := time.Now()
+	excluded := e.excludedProjects(opts.ExcludeProjects)
 	searchOpts := search.Options{
-		Limit:   preset.SearchLimit,
-		Project: opts.Project,
+		Limit:           preset.SearchLimit,
+		Project:         opts.Project,
+		ExcludeProjects: excluded,
 	}
 	if preset.SearchMode != "" {
 		mode, err := search.ParseMode(preset.SearchMode)
@@ -176,8 +183,9 @@ func (e *Engine) reasonRecursiveAtDepth(ctx context.Context, opts RecursiveOptio
 	searchTime := time.Since(searchStart)
 
 	// Build initial context
-	contextStr, memoriesUsed := buildConfidenceContext(ctx, e.store, initialResults, maxContext)
-	factsStr, factsUsed := gatherFacts(ctx, e.store, initialResults, maxContext-len(contextStr))
+	contextStr, memoriesUsed := buildConfidenceContext(ctx, e.store, initialResults, contextBudget, family)
+	factsStr, factsUsed := gatherFacts(ctx, e.store, initialResults, contextBudget-family.Count(contextStr), family)
+	evidence := citationMemoryIDs(buildCitations(initialResults[:memoriesUsed]))
 
 	initialContext := contextStr
 	if factsStr != "" {
@@ -207,6 +215,7 @@ func (e *Engine) reasonRecursiveAtDepth(ctx context.Context, opts RecursiveOptio
 	var actions []ActionRecord
 	var subQueries []SubQueryResult
 	var totalTokensIn, totalTokensOut int
+	var cacheHits, cachedTokens int
 	var totalLLMTime time.Duration
 	var finalContent string
 	totalCalls := 0
@@ -221,7 +230,8 @@ func (e *Engine) reasonRecursiveAtDepth(ctx context.Context, opts RecursiveOptio
 
 		// Call LLM
 		llmStart := time.Now()
-		llmResult, err := e.llm.Chat(ctx, messages, maxTokens)
+		// Every iteration resends the system prompt and initial context.
+		llmResult, err := e.llm.ChatCached(ctx, messages, maxTokens, 2)
 		if err != nil {
 			return nil, fmt.Errorf("LLM call %d failed: %w", iteration+1, err)
 		}
@@ -230,6 +240,8 @@ func (e *Engine) reasonRecursiveAtDepth(ctx context.Context, opts RecursiveOptio
 		totalCalls++
 		totalTokensIn += llmResult.PromptTokens
 		totalTokensOut += llmResult.CompletionTokens
+		cacheHits += boolToInt(llmResult.CacheHit)
+		cachedTokens += llmResult.CachedTokens
 
 		response := strings.TrimSpace(llmResult.Content)
 
@@ -289,8 +301,9 @@ func (e *Engine) reasonRecursiveAtDepth(ctx context.Context, opts RecursiveOptio
 			if err != nil {
 				newContext = fmt.Sprintf("(search error: %v)", err)
 			} else {
-				newContext, newMem = buildConfidenceContext(ctx, e.store, newResults, maxContext/2)
+				newContext, newMem = buildConfidenceContext(ctx, e.store, newResults, contextBudget/2, family)
 				memoriesUsed += newMem
+				evidence = append(evidence, citationMemoryIDs(buildCitations(newResults[:newMem]))...)
 			}
 
 			actions = append(actions, ActionRecord{
@@ -325,7 +338,7 @@ func (e *Engine) reasonRecursiveAtDepth(ctx context.Context, opts RecursiveOptio
 				argLower := strings.ToLower(argument)
 				for _, f := range facts {
 					combined := strings.ToLower(f.Subject + " " + f.Predicate + " " + f.Object)
-					if strings.Contains(combined, argLower) || fuzzyMatch(argLower, combined) {
+					if (strings.Contains(combined, argLower) || fuzzyMatch(argLower, combined)) && e.memoryAllowed(ctx, f.MemoryID, excluded) {
 						matched = append(matched, fmt.Sprintf("[%.2f] %s: %s %s %s",
 							f.Confidence, f.FactType, f.Subject, f.Predicate, f.Object))
 					}
@@ -366,10 +379,14 @@ func (e *Engine) reasonRecursiveAtDepth(ctx context.Context, opts RecursiveOptio
 				mem, err := e.store.GetMemory(ctx, memID)
 				if err != nil {
 					peekResult = fmt.Sprintf("(memory %d not found: %v)", memID, err)
-				} else if mem == nil {
+				} else if mem == nil || !e.memoryAllowed(ctx, memID, excluded) {
 					peekResult = fmt.Sprintf("(memory %d not found)", memID)
 				} else {
-					peekResult = truncateContent(mem.Content, maxContext/2)
+					peek, cut := family.Truncate(strings.ReplaceAll(mem.Content, "\n", " "), contextBudget/2)
+					if cut {
+						peek += "..."
+					}
+					peekResult = peek
 				}
 			} else {
 				peekResult = fmt.Sprintf("(invalid memory ID: %q — use numeric ID from search results)", argument)
@@ -405,14 +422,15 @@ func (e *Engine) reasonRecursiveAtDepth(ctx context.Context, opts RecursiveOptio
 			} else {
 				// Recursive call!
 				subOpts := RecursiveOptions{
-					Query:         argument,
-					Preset:        opts.Preset,
-					Project:       opts.Project,
-					MaxIterations: maxIter / 2, // Sub-queries get half the budget
-					MaxDepth:      maxDepth,
-					MaxTokens:     maxTokens,
-					MaxContext:    maxContext,
-					Verbose:       opts.Verbose,
+					Query:           argument,
+					Preset:          opts.Preset,
+					Project:         opts.Project,
+					MaxIterations:   maxIter / 2, // Sub-queries get half the budget
+					MaxDepth:        maxDepth,
+					MaxTokens:       maxTokens,
+					MaxContext:      maxContext,
+					Verbose:         opts.Verbose,
+					ExcludeProjects: opts.ExcludeProjects,
 				}
 
 				if opts.Verbose {
@@ -427,6 +445,8 @@ func (e *Engine) reasonRecursiveAtDepth(ctx context.Context, opts RecursiveOptio
 					subResponse = subResult.Content
 					totalTokensIn += subResult.TokensIn
 					totalTokensOut += subResult.TokensOut
+					cacheHits += subResult.CacheHits
+					cachedTokens += subResult.CachedTokens
 					totalCalls += subResult.TotalCalls
 					totalLLMTime += subResult.LLMTime
 					searchTime += subResult.SearchTime
@@ -515,6 +535,9 @@ done:
 			LLMTime:      totalLLMTime,
 			TokensIn:     totalTokensIn,
 			TokensOut:    totalTokensOut,
+			CacheHits:    cacheHits,
+			CachedTokens: cachedTokens,
+			Confidence:   e.answerConfidence(ctx, evidence),
 		},
 		Iterations: iteration + 1,
 		TotalCalls: totalCalls,
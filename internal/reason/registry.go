@@ -0,0 +1,380 @@
+package reason
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPresetRegistry is the community preset index in the Cortex repo.
+const DefaultPresetRegistry = "https://raw.githubusercontent.com/hurttlocker/cortex/main/presets/index.yaml"
+
+// maxPresetBytes caps a fetched preset or index; presets are a few KB.
+const maxPresetBytes = 1 << 20
+
+const installedManifest = "installed.json"
+
+var presetNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,63}$`)
+
+// PresetRegistry is a preset index: the presets it offers and the checksum
+// each file must have.
+type PresetRegistry struct {
+	Presets []RegistryEntry `yaml:"presets" json:"presets"`
+}
+
+// RegistryEntry is one preset in a registry. URL may be relative to the
+// index.
+type RegistryEntry struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Author      string `yaml:"author,omitempty" json:"author,omitempty"`
+	URL         string `yaml:"url" json:"url"`
+	SHA256      string `yaml:"sha256" json:"sha256"`
+}
+
+// InstalledPreset records where an installed preset came from.
+type InstalledPreset struct {
+	Name        string    `json:"name"`
+	Source      string    `json:"source"`
+	SHA256      string    `json:"sha256"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// InstallOptions says what to install and how to check it.
+type InstallOptions struct {
+	// Source is a registry preset name, an http(s) URL, a git repository
+	// (git+https://host/repo.git#path/preset.yaml), or a local file.
+	Source string
+	// SHA256 is the expected checksum of the preset file. Registry installs
+	// take it from the index; URL and git installs require it.
+	SHA256     string
+	Registry   string // index URL (default DefaultPresetRegistry)
+	Force      bool   // overwrite an installed preset or shadow a builtin
+	HTTPClient *http.Client
+}
+
+// PresetsDir is where installed presets live (~/.cortex/presets).
+func PresetsDir(configDir string) string {
+	return filepath.Join(configDir, "presets")
+}
+
+// ParsePreset decodes and validates a single-preset YAML file.
+func ParsePreset(data []byte) (Preset, error) {
+	var p Preset
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return Preset{}, fmt.Errorf("parsing preset: %w", err)
+	}
+	p.Name = strings.TrimSpace(p.Name)
+	if !presetNameRe.MatchString(p.Name) {
+		return Preset{}, fmt.Errorf("invalid preset name %q (use lowercase letters, digits, and dashes)", p.Name)
+	}
+	if !strings.Contains(p.Template, "{{context}}") {
+		return Preset{}, fmt.Errorf("preset %q: template must contain {{context}}", p.Name)
+	}
+	if p.SearchMode != "" {
+		switch p.SearchMode {
+		case "hybrid", "rrf", "bm25", "semantic":
+		default:
+			return Preset{}, fmt.Errorf("preset %q: unknown search_mode %q", p.Name, p.SearchMode)
+		}
+	}
+	return p, nil
+}
+
+// InstallPreset fetches a preset, verifies its checksum, and writes it to
+// PresetsDir(configDir). Builtins and already-installed presets are only
+// replaced with Force.
+func InstallPreset(ctx context.Context, configDir string, opts InstallOptions) (*InstalledPreset, error) {
+	source := strings.TrimSpace(opts.Source)
+	if source == "" {
+		return nil, errors.New("preset source is required")
+	}
+	client := opts.HTTPClient
+	want := strings.ToLower(strings.TrimSpace(opts.SHA256))
+
+	var data []byte
+	var err error
+	switch {
+	case isGitSource(source):
+		if want == "" {
+			return nil, fmt.Errorf("git installs need --sha256 (the preset file's checksum)")
+		}
+		data, err = fetchGitPreset(ctx, source)
+	case strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://"):
+		if want == "" {
+			return nil, fmt.Errorf("URL installs need --sha256 (the preset file's checksum)")
+		}
+		data, err = fetchURL(ctx, client, source)
+	case fileExists(source):
+		data, err = os.ReadFile(source)
+	default:
+		registry := opts.Registry
+		if registry == "" {
+			registry = DefaultPresetRegistry
+		}
+		entry, err := lookupRegistry(ctx, client, registry, source)
+		if err != nil {
+			return nil, err
+		}
+		if want != "" && want != strings.ToLower(entry.SHA256) {
+			return nil, fmt.Errorf("--sha256 does not match the registry checksum for %q", source)
+		}
+		want = strings.ToLower(entry.SHA256)
+		source = entry.URL
+		data, err = fetchURL(ctx, client, entry.URL)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	got := checksum(data)
+	if want != "" && got != want {
+		return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", source, want, got)
+	}
+	p, err := ParsePreset(data)
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := InstalledPresets(configDir)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.Force {
+		if _, ok := BuiltinPresets[p.Name]; ok {
+			return nil, fmt.Errorf("preset %q would shadow the builtin preset (use --force)", p.Name)
+		}
+		if _, ok := installed[p.Name]; ok {
+			return nil, fmt.Errorf("preset %q is already installed (use --force to replace it)", p.Name)
+		}
+	}
+
+	dir := PresetsDir(configDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, p.Name+".yaml"), data, 0o644); err != nil {
+		return nil, fmt.Errorf("writing preset: %w", err)
+	}
+	rec := InstalledPreset{Name: p.Name, Source: source, SHA256: got, InstalledAt: time.Now().UTC()}
+	installed[p.Name] = rec
+	if err := writeInstalled(configDir, installed); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// RemovePreset deletes an installed preset.
+func RemovePreset(configDir, name string) error {
+	installed, err := InstalledPresets(configDir)
+	if err != nil {
+		return err
+	}
+	if _, ok := installed[name]; !ok {
+		return fmt.Errorf("preset %q is not installed", name)
+	}
+	if err := os.Remove(filepath.Join(PresetsDir(configDir), name+".yaml")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing preset: %w", err)
+	}
+	delete(installed, name)
+	return writeInstalled(configDir, installed)
+}
+
+// InstalledPresets returns the install records, keyed by preset name.
+func InstalledPresets(configDir string) (map[string]InstalledPreset, error) {
+	path := filepath.Join(PresetsDir(configDir), installedManifest)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]InstalledPreset{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	out := map[string]InstalledPreset{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return out, nil
+}
+
+func writeInstalled(configDir string, installed map[string]InstalledPreset) error {
+	data, err := json.MarshalIndent(installed, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(PresetsDir(configDir), installedManifest)
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// PublishPreset renders p as a preset file and the registry entry that
+// points at it under fileURL, checksum included, ready for a registry PR.
+func PublishPreset(p Preset, author, fileURL string) ([]byte, RegistryEntry, error) {
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, RegistryEntry{}, err
+	}
+	if _, err := ParsePreset(data); err != nil {
+		return nil, RegistryEntry{}, err
+	}
+	return data, RegistryEntry{
+		Name:        p.Name,
+		Description: p.Description,
+		Author:      author,
+		URL:         fileURL,
+		SHA256:      checksum(data),
+	}, nil
+}
+
+// FetchRegistry downloads and parses a registry index. Relative entry URLs
+// are resolved against the index URL (or directory, for a local index).
+func FetchRegistry(ctx context.Context, client *http.Client, index string) (*PresetRegistry, error) {
+	var data []byte
+	var err error
+	if fileExists(index) {
+		data, err = os.ReadFile(index)
+	} else {
+		data, err = fetchURL(ctx, client, index)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var reg PresetRegistry
+	if err := yaml.Unmarshal(data, &reg); err != nil {
+		return nil, fmt.Errorf("parsing registry %s: %w", index, err)
+	}
+	for i, e := range reg.Presets {
+		reg.Presets[i].URL = resolveRef(index, e.URL)
+	}
+	sort.Slice(reg.Presets, func(i, j int) bool { return reg.Presets[i].Name < reg.Presets[j].Name })
+	return &reg, nil
+}
+
+func lookupRegistry(ctx context.Context, client *http.Client, index, name string) (*RegistryEntry, error) {
+	if !presetNameRe.MatchString(name) {
+		return nil, fmt.Errorf("%q is not a preset name, URL, git repository, or file", name)
+	}
+	reg, err := FetchRegistry(ctx, client, index)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range reg.Presets {
+		if e.Name == name {
+			if e.SHA256 == "" {
+				return nil, fmt.Errorf("registry entry %q has no sha256", name)
+			}
+			return &e, nil
+		}
+	}
+	return nil, fmt.Errorf("preset %q not found in registry %s", name, index)
+}
+
+func resolveRef(index, ref string) string {
+	if ref == "" || strings.Contains(ref, "://") || filepath.IsAbs(ref) {
+		return ref
+	}
+	if base, err := url.Parse(index); err == nil && base.Scheme != "" {
+		if r, err := base.Parse(ref); err == nil {
+			return r.String()
+		}
+	}
+	return filepath.Join(filepath.Dir(index), ref)
+}
+
+func fetchURL(ctx context.Context, client *http.Client, rawURL string) ([]byte, error) {
+	if fileExists(rawURL) {
+		return os.ReadFile(rawURL)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", rawURL, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: HTTP %d", rawURL, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPresetBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", rawURL, err)
+	}
+	if len(data) > maxPresetBytes {
+		return nil, fmt.Errorf("fetching %s: larger than %d bytes", rawURL, maxPresetBytes)
+	}
+	return data, nil
+}
+
+// isGitSource reports whether source names a git repository:
+// git+https://host/repo.git#path, git@host:repo.git#path, or an https URL
+// ending in .git#path.
+func isGitSource(source string) bool {
+	repo, _, _ := strings.Cut(source, "#")
+	return strings.HasPrefix(source, "git+") || strings.HasPrefix(source, "git@") || strings.HasSuffix(repo, ".git")
+}
+
+// fetchGitPreset shallow-clones the repository and reads the preset file
+// named after '#'.
+func fetchGitPreset(ctx context.Context, source string) ([]byte, error) {
+	repo, path, ok := strings.Cut(strings.TrimPrefix(source, "git+"), "#")
+	if !ok || strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("git source needs the preset path after '#', e.g. %s#presets/name.yaml", repo)
+	}
+	// A leading '-' would reach git as an option (--upload-pack runs a command).
+	if strings.HasPrefix(repo, "-") {
+		return nil, fmt.Errorf("invalid git repository %q", repo)
+	}
+	path = filepath.Clean(path)
+	if filepath.IsAbs(path) || strings.HasPrefix(path, "..") {
+		return nil, fmt.Errorf("invalid preset path %q", path)
+	}
+	dir, err := os.MkdirTemp("", "cortex-preset-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	cmd := exec.CommandContext(ctx, "git", "clone", "--depth", "1", "--quiet", "--", repo, dir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("git clone %s: %v: %s", repo, err, strings.TrimSpace(string(out)))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s from %s: %w", path, repo, err)
+	}
+	return data, nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,214 @@
+package reason
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPresetYAML = `name: standup
+description: Yesterday, today, blockers
+system: You write standup notes.
+template: "Standup for {{.Query}}\n\n{{context}}"
+max_tokens: 256
+search_limit: 10
+search_mode: bm25
+`
+
+func presetRegistryServer(t *testing.T, presetBody string, indexSHA string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/registry/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("presets:\n  - name: standup\n    description: Yesterday, today, blockers\n    url: presets/standup.yaml\n    sha256: " + indexSHA + "\n"))
+	})
+	mux.HandleFunc("/registry/presets/standup.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(presetBody))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestInstallPreset_FromRegistry(t *testing.T) {
+	srv := presetRegistryServer(t, testPresetYAML, checksum([]byte(testPresetYAML)))
+	dir := t.TempDir()
+	ctx := context.Background()
+	opts := InstallOptions{Source: "standup", Registry: srv.URL + "/registry/index.yaml"}
+
+	rec, err := InstallPreset(ctx, dir, opts)
+	if err != nil {
+		t.Fatalf("InstallPreset: %v", err)
+	}
+	if rec.Name != "standup" || rec.Source != srv.URL+"/registry/presets/standup.yaml" {
+		t.Fatalf("unexpected install record: %+v", rec)
+	}
+
+	p, err := GetPreset("standup", dir)
+	if err != nil {
+		t.Fatalf("GetPreset: %v", err)
+	}
+	if p.SearchMode != "bm25" || p.MaxTokens != 256 {
+		t.Fatalf("installed preset not loaded: %+v", p)
+	}
+
+	if _, err := InstallPreset(ctx, dir, opts); err == nil || !strings.Contains(err.Error(), "already installed") {
+		t.Fatalf("expected already-installed error, got %v", err)
+	}
+	opts.Force = true
+	if _, err := InstallPreset(ctx, dir, opts); err != nil {
+		t.Fatalf("forced reinstall: %v", err)
+	}
+
+	if err := RemovePreset(dir, "standup"); err != nil {
+		t.Fatalf("RemovePreset: %v", err)
+	}
+	if _, err := GetPreset("standup", dir); err == nil {
+		t.Fatal("preset still resolvable after remove")
+	}
+	if err := RemovePreset(dir, "standup"); err == nil {
+		t.Fatal("expected error removing a preset that is not installed")
+	}
+}
+
+func TestInstallPreset_ChecksumMismatch(t *testing.T) {
+	srv := presetRegistryServer(t, testPresetYAML+"# tampered\n", checksum([]byte(testPresetYAML)))
+	dir := t.TempDir()
+	_, err := InstallPreset(context.Background(), dir, InstallOptions{Source: "standup", Registry: srv.URL + "/registry/index.yaml"})
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(PresetsDir(dir), "standup.yaml")); !os.IsNotExist(err) {
+		t.Fatal("tampered preset was written")
+	}
+}
+
+func TestInstallPreset_URLRequiresChecksum(t *testing.T) {
+	srv := presetRegistryServer(t, testPresetYAML, "")
+	dir := t.TempDir()
+	url := srv.URL + "/registry/presets/standup.yaml"
+
+	if _, err := InstallPreset(context.Background(), dir, InstallOptions{Source: url}); err == nil || !strings.Contains(err.Error(), "--sha256") {
+		t.Fatalf("expected --sha256 requirement, got %v", err)
+	}
+	if _, err := InstallPreset(context.Background(), dir, InstallOptions{Source: url, SHA256: strings.ToUpper(checksum([]byte(testPresetYAML)))}); err != nil {
+		t.Fatalf("URL install with checksum: %v", err)
+	}
+	if _, err := InstallPreset(context.Background(), t.TempDir(), InstallOptions{Source: "git+https://example.com/presets.git#standup.yaml"}); err == nil || !strings.Contains(err.Error(), "--sha256") {
+		t.Fatalf("expected --sha256 requirement for git, got %v", err)
+	}
+}
+
+func TestInstallPreset_GitRejectsOptionRepo(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "pwned")
+	source := "git+--upload-pack=touch " + marker + ".git#p.yaml"
+	_, err := InstallPreset(context.Background(), t.TempDir(), InstallOptions{Source: source, SHA256: checksum([]byte(testPresetYAML))})
+	if err == nil || !strings.Contains(err.Error(), "invalid git repository") {
+		t.Fatalf("expected invalid git repository, got %v", err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatalf("git ran the --upload-pack command (stat %v)", err)
+	}
+}
+
+func TestInstallPreset_RefusesToShadowBuiltin(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "digest.yaml")
+	body := strings.Replace(testPresetYAML, "name: standup", "name: daily-digest", 1)
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := InstallPreset(context.Background(), dir, InstallOptions{Source: path}); err == nil || !strings.Contains(err.Error(), "builtin") {
+		t.Fatalf("expected builtin shadow error, got %v", err)
+	}
+	if _, err := InstallPreset(context.Background(), dir, InstallOptions{Source: path, Force: true}); err != nil {
+		t.Fatalf("forced install: %v", err)
+	}
+	p, err := GetPreset("daily-digest", dir)
+	if err != nil || p.SearchMode != "bm25" {
+		t.Fatalf("installed preset should override builtin, got %+v, %v", p, err)
+	}
+}
+
+func TestParsePreset_Validation(t *testing.T) {
+	cases := map[string]string{
+		"bad name":     strings.Replace(testPresetYAML, "name: standup", "name: Stand Up", 1),
+		"no context":   strings.Replace(testPresetYAML, "{{context}}", "", 1),
+		"bad mode":     strings.Replace(testPresetYAML, "search_mode: bm25", "search_mode: magic", 1),
+		"invalid yaml": "name: [",
+	}
+	for name, body := range cases {
+		if _, err := ParsePreset([]byte(body)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoadCustomPresets_PresetsYAMLWins(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(PresetsDir(dir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(PresetsDir(dir), "standup.yaml"), []byte(testPresetYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	override := "standup:\n  name: standup\n  description: mine\n  template: \"{{context}}\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "presets.yaml"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	presets, err := LoadCustomPresets(dir)
+	if err != nil {
+		t.Fatalf("LoadCustomPresets: %v", err)
+	}
+	if presets["standup"].Description != "mine" {
+		t.Fatalf("presets.yaml should win, got %+v", presets["standup"])
+	}
+}
+
+func TestPublishPreset_RoundTrip(t *testing.T) {
+	p := BuiltinPresets["conflict-check"]
+	data, entry, err := PublishPreset(p, "someone", "conflict-check.yaml")
+	if err != nil {
+		t.Fatalf("PublishPreset: %v", err)
+	}
+	if entry.SHA256 != checksum(data) || entry.Name != "conflict-check" || entry.Author != "someone" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	got, err := ParsePreset(data)
+	if err != nil {
+		t.Fatalf("ParsePreset: %v", err)
+	}
+	if got != p {
+		t.Fatalf("round trip changed preset:\n%+v\n%+v", got, p)
+	}
+}
+
+// The registry shipped in presets/ must stay installable: every entry's
+// checksum has to match its file.
+func TestRepoPresetRegistry(t *testing.T) {
+	reg, err := FetchRegistry(context.Background(), nil, filepath.Join("..", "..", "presets", "index.yaml"))
+	if err != nil {
+		t.Fatalf("FetchRegistry: %v", err)
+	}
+	if len(reg.Presets) == 0 {
+		t.Fatal("registry lists no presets")
+	}
+	for _, e := range reg.Presets {
+		data, err := os.ReadFile(e.URL)
+		if err != nil {
+			t.Fatalf("%s: %v", e.Name, err)
+		}
+		if got := checksum(data); got != e.SHA256 {
+			t.Errorf("%s: index sha256 %s, file has %s", e.Name, e.SHA256, got)
+		}
+		p, err := ParsePreset(data)
+		if err != nil {
+			t.Errorf("%s: %v", e.Name, err)
+		} else if p.Name != e.Name {
+			t.Errorf("index name %q, file name %q", e.Name, p.Name)
+		}
+	}
+}
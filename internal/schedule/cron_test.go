@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	loc := time.UTC
+	base := time.Date(2026, 3, 4, 10, 17, 30, 0, loc) // a Wednesday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 4, 10, 18, 0, 0, loc)},
+		{"*/15 * * * *", time.Date(2026, 3, 4, 10, 30, 0, 0, loc)},
+		{"5/20 * * * *", time.Date(2026, 3, 4, 10, 25, 0, 0, loc)},
+		{"0 3 * * *", time.Date(2026, 3, 5, 3, 0, 0, 0, loc)},
+		{"@hourly", time.Date(2026, 3, 4, 11, 0, 0, 0, loc)},
+		{"@daily", time.Date(2026, 3, 5, 0, 0, 0, 0, loc)},
+		{"@weekly", time.Date(2026, 3, 8, 0, 0, 0, 0, loc)},
+		{"@monthly", time.Date(2026, 4, 1, 0, 0, 0, 0, loc)},
+		{"@yearly", time.Date(2027, 1, 1, 0, 0, 0, 0, loc)},
+		{"30 9 * * mon-fri", time.Date(2026, 3, 5, 9, 30, 0, 0, loc)},
+		{"0 0 * * 7", time.Date(2026, 3, 8, 0, 0, 0, 0, loc)},
+		{"0 12 1,15 feb,mar *", time.Date(2026, 3, 15, 12, 0, 0, 0, loc)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, loc)},
+		// Both day fields restricted: either may match (the 10th, or a Friday).
+		{"0 0 10 * fri", time.Date(2026, 3, 6, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		s, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := s.Next(base); !got.Equal(tt.want) {
+			t.Errorf("%q: Next = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseCron_Every(t *testing.T) {
+	s, err := ParseCron("@every 90m")
+	if err != nil {
+		t.Fatalf("ParseCron: %v", err)
+	}
+	base := time.Date(2026, 3, 4, 10, 17, 30, 0, time.UTC)
+	if got := s.Next(base); !got.Equal(base.Add(90 * time.Minute)) {
+		t.Fatalf("Next = %v", got)
+	}
+	if Interval(s) != 90*time.Minute {
+		t.Fatalf("Interval = %v", Interval(s))
+	}
+	cron, _ := ParseCron("@daily")
+	if Interval(cron) != 0 {
+		t.Fatal("cron schedules have no fixed interval")
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *",
+		"* * * * 8", "*/0 * * * *", "5-1 * * * *", "* * * smarch *", "@every", "@every -5m", "@sometimes",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q): expected error", expr)
+		}
+	}
+}
+
+func TestCronNext_NeverFires(t *testing.T) {
+	s, err := ParseCron("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron: %v", err)
+	}
+	if got := s.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Fatalf("Feb 31 should never fire, got %v", got)
+	}
+}
@@ -0,0 +1,220 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Tasks a schedule can run.
+const (
+	TaskConnectorSync = "connector_sync"
+	TaskEmbed         = "embed"
+	TaskMaintain      = "maintain"
+	TaskDigest        = "digest"
+	TaskLearnEdges    = "learn_edges"
+)
+
+// Tasks lists every task name, in display order.
+var Tasks = []string{TaskConnectorSync, TaskEmbed, TaskMaintain, TaskDigest, TaskLearnEdges}
+
+// ValidTask reports whether name is a known task.
+func ValidTask(name string) bool {
+	for _, t := range Tasks {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Run statuses recorded in History.
+const (
+	StatusRunning = "running"
+	StatusOK      = "ok"
+	StatusError   = "error"
+	StatusSkipped = "skipped"
+)
+
+// defaultStaleAfter is how long a run recorded as running blocks new runs
+// of the same job; after that its process is presumed dead.
+const defaultStaleAfter = 6 * time.Hour
+
+// Job is one scheduled task.
+type Job struct {
+	Name     string
+	Task     string
+	Schedule Schedule
+	// Jitter delays each firing by a random duration in [0, Jitter), so
+	// several daemons (or several jobs on the same minute) spread out.
+	Jitter time.Duration
+	// Run does the work and returns a one-line summary for the run history.
+	Run func(ctx context.Context) (string, error)
+}
+
+// History records runs. BeginScheduleRun must refuse (ok=false) while
+// another run of the same job started less than staleAfter ago and has not
+// finished, which keeps two daemons on one database from overlapping.
+type History interface {
+	BeginScheduleRun(ctx context.Context, name, task string, startedAt time.Time, staleAfter time.Duration) (id int64, ok bool, err error)
+	FinishScheduleRun(ctx context.Context, id int64, status, detail, errMsg string, finishedAt time.Time) error
+	RecordScheduleSkip(ctx context.Context, name, task, reason string, at time.Time) error
+}
+
+// Scheduler fires jobs on their schedules.
+type Scheduler struct {
+	// StaleAfter overrides defaultStaleAfter.
+	StaleAfter time.Duration
+
+	jobs    []Job
+	history History
+	logger  *log.Logger
+
+	now    func() time.Time
+	jitter func(max time.Duration) time.Duration
+
+	mu      sync.Mutex
+	running map[string]*atomic.Bool
+}
+
+// New returns a Scheduler for jobs. history may be nil.
+func New(jobs []Job, history History, logger *log.Logger) *Scheduler {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &Scheduler{
+		jobs:    jobs,
+		history: history,
+		logger:  logger,
+		now:     time.Now,
+		jitter: func(max time.Duration) time.Duration {
+			if max <= 0 {
+				return 0
+			}
+			return time.Duration(rand.Int63n(int64(max)))
+		},
+		running: make(map[string]*atomic.Bool),
+	}
+}
+
+// Run fires every job on its schedule until ctx is cancelled, then waits
+// for in-flight runs to return.
+func (s *Scheduler) Run(ctx context.Context) error {
+	var loops, runs sync.WaitGroup
+	for _, job := range s.jobs {
+		loops.Add(1)
+		go func(job Job) {
+			defer loops.Done()
+			s.loop(ctx, job, &runs)
+		}(job)
+	}
+	loops.Wait()
+	runs.Wait()
+	return nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job, runs *sync.WaitGroup) {
+	for {
+		now := s.now()
+		next := job.Schedule.Next(now)
+		if next.IsZero() {
+			s.logger.Printf("%s: schedule never fires again; stopping", job.Name)
+			return
+		}
+		delay := next.Sub(now) + s.jitter(job.Jitter)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		flag := s.flag(job.Name)
+		if !flag.CompareAndSwap(false, true) {
+			s.skip(ctx, job, "previous run still in progress")
+			continue
+		}
+		runs.Add(1)
+		go func() {
+			defer runs.Done()
+			defer flag.Store(false)
+			s.RunOnce(ctx, job)
+		}()
+	}
+}
+
+func (s *Scheduler) flag(name string) *atomic.Bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.running[name]
+	if !ok {
+		f = &atomic.Bool{}
+		s.running[name] = f
+	}
+	return f
+}
+
+// RunOnce runs job now, recording it in the history. It returns false when
+// the history reports another run of the job in progress.
+func (s *Scheduler) RunOnce(ctx context.Context, job Job) (ran bool, err error) {
+	start := s.now()
+	var id int64
+	if s.history != nil {
+		staleAfter := s.StaleAfter
+		if staleAfter <= 0 {
+			staleAfter = defaultStaleAfter
+		}
+		var ok bool
+		id, ok, err = s.history.BeginScheduleRun(ctx, job.Name, job.Task, start, staleAfter)
+		if err != nil {
+			s.logger.Printf("%s: recording start: %v", job.Name, err)
+			return false, err
+		}
+		if !ok {
+			s.skip(ctx, job, "already running in another process")
+			return false, nil
+		}
+	}
+
+	detail, runErr := s.call(ctx, job)
+	status, errMsg := StatusOK, ""
+	if runErr != nil {
+		status, errMsg = StatusError, runErr.Error()
+		s.logger.Printf("%s (%s) failed after %s: %v", job.Name, job.Task, s.now().Sub(start).Round(time.Millisecond), runErr)
+	} else {
+		s.logger.Printf("%s (%s) ok in %s: %s", job.Name, job.Task, s.now().Sub(start).Round(time.Millisecond), detail)
+	}
+	if s.history != nil {
+		// Record the outcome even when ctx was cancelled mid-run.
+		if err := s.history.FinishScheduleRun(context.WithoutCancel(ctx), id, status, detail, errMsg, s.now()); err != nil {
+			s.logger.Printf("%s: recording result: %v", job.Name, err)
+		}
+	}
+	return true, runErr
+}
+
+// call runs job.Run, turning a panic into an error so one broken task
+// cannot take the other schedules down with it.
+func (s *Scheduler) call(ctx context.Context, job Job) (detail string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+func (s *Scheduler) skip(ctx context.Context, job Job, reason string) {
+	s.logger.Printf("%s: skipped (%s)", job.Name, reason)
+	if s.history == nil {
+		return
+	}
+	if err := s.history.RecordScheduleSkip(ctx, job.Name, job.Task, reason, s.now()); err != nil {
+		s.logger.Printf("%s: recording skip: %v", job.Name, err)
+	}
+}
@@ -0,0 +1,179 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeHistory struct {
+	mu      sync.Mutex
+	nextID  int64
+	running map[string]bool
+	names   map[int64]string
+	block   bool // refuse every BeginScheduleRun
+	results []string
+	skips   []string
+}
+
+func (h *fakeHistory) BeginScheduleRun(_ context.Context, name, _ string, _ time.Time, _ time.Duration) (int64, bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.block || h.running[name] {
+		return 0, false, nil
+	}
+	if h.running == nil {
+		h.running = map[string]bool{}
+		h.names = map[int64]string{}
+	}
+	h.running[name] = true
+	h.nextID++
+	h.names[h.nextID] = name
+	return h.nextID, true, nil
+}
+
+func (h *fakeHistory) FinishScheduleRun(_ context.Context, id int64, status, detail, errMsg string, _ time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.running, h.names[id])
+	h.results = append(h.results, status+":"+detail+errMsg)
+	return nil
+}
+
+func (h *fakeHistory) RecordScheduleSkip(_ context.Context, name, _, reason string, _ time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.skips = append(h.skips, name+":"+reason)
+	return nil
+}
+
+func quietLogger() *log.Logger { return log.New(io.Discard, "", 0) }
+
+func TestRunOnce_RecordsOutcome(t *testing.T) {
+	h := &fakeHistory{}
+	s := New(nil, h, quietLogger())
+	ctx := context.Background()
+
+	ran, err := s.RunOnce(ctx, Job{Name: "a", Task: TaskEmbed, Run: func(context.Context) (string, error) {
+		return "42 embedded", nil
+	}})
+	if !ran || err != nil {
+		t.Fatalf("RunOnce = %v, %v", ran, err)
+	}
+	ran, err = s.RunOnce(ctx, Job{Name: "b", Task: TaskDigest, Run: func(context.Context) (string, error) {
+		return "", errors.New("no llm")
+	}})
+	if !ran || err == nil {
+		t.Fatalf("RunOnce(failing) = %v, %v", ran, err)
+	}
+	ran, err = s.RunOnce(ctx, Job{Name: "c", Task: TaskMaintain, Run: func(context.Context) (string, error) {
+		panic("boom")
+	}})
+	if !ran || err == nil {
+		t.Fatalf("RunOnce(panicking) = %v, %v", ran, err)
+	}
+
+	want := []string{"ok:42 embedded", "error:no llm", "error:panic: boom"}
+	if len(h.results) != len(want) {
+		t.Fatalf("results = %v, want %v", h.results, want)
+	}
+	for i := range want {
+		if h.results[i] != want[i] {
+			t.Fatalf("results = %v, want %v", h.results, want)
+		}
+	}
+}
+
+func TestRunOnce_SkipsWhenRunningElsewhere(t *testing.T) {
+	h := &fakeHistory{block: true}
+	s := New(nil, h, quietLogger())
+	called := false
+	ran, err := s.RunOnce(context.Background(), Job{Name: "sync", Task: TaskConnectorSync, Run: func(context.Context) (string, error) {
+		called = true
+		return "", nil
+	}})
+	if ran || err != nil || called {
+		t.Fatalf("RunOnce = %v, %v (called=%v); want a recorded skip", ran, err, called)
+	}
+	if len(h.skips) != 1 || h.skips[0] != "sync:already running in another process" {
+		t.Fatalf("skips = %v", h.skips)
+	}
+}
+
+func TestScheduler_RunFiresAndPreventsOverlap(t *testing.T) {
+	h := &fakeHistory{}
+	every, err := ParseCron("@every 10ms")
+	if err != nil {
+		t.Fatalf("ParseCron: %v", err)
+	}
+
+	var fast, slow atomic.Int32
+	release := make(chan struct{})
+	jobs := []Job{
+		{Name: "fast", Task: TaskEmbed, Schedule: every, Run: func(context.Context) (string, error) {
+			fast.Add(1)
+			return "", nil
+		}},
+		{Name: "slow", Task: TaskMaintain, Schedule: every, Run: func(ctx context.Context) (string, error) {
+			slow.Add(1)
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+			return "", nil
+		}},
+	}
+	s := New(jobs, h, quietLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+
+	if fast.Load() < 2 {
+		t.Fatalf("fast job ran %d times, want several", fast.Load())
+	}
+	if slow.Load() != 1 {
+		t.Fatalf("slow job ran %d times, want 1 (later firings overlap it)", slow.Load())
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.skips) == 0 {
+		t.Fatal("expected the overlapping firings to be recorded as skips")
+	}
+	for _, skip := range h.skips {
+		if skip != "slow:previous run still in progress" {
+			t.Fatalf("unexpected skip %q", skip)
+		}
+	}
+}
+
+func TestScheduler_JitterDelaysFiring(t *testing.T) {
+	every, _ := ParseCron("@every 1ms")
+	var got time.Duration
+	s := New([]Job{{Name: "j", Task: TaskEmbed, Schedule: every, Jitter: time.Hour, Run: func(context.Context) (string, error) {
+		return "", nil
+	}}}, nil, quietLogger())
+	s.jitter = func(max time.Duration) time.Duration {
+		got = max
+		return time.Hour
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_ = s.Run(ctx)
+	if got != time.Hour {
+		t.Fatalf("jitter called with %v, want 1h", got)
+	}
+}
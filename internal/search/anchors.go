@@ -0,0 +1,37 @@
+package search
+
+import (
+	"context"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// anchorStore is implemented by stores that record where each chunk sits in
+// its source file (SQLiteStore).
+type anchorStore interface {
+	GetMemoryAnchors(ctx context.Context, memoryIDs []int64) (map[int64]store.ChunkAnchor, error)
+}
+
+// attachAnchors sets Anchor on results whose memory has one. Anchors are
+// best-effort provenance: a lookup failure leaves results unchanged.
+func (e *Engine) attachAnchors(ctx context.Context, results []Result) {
+	as, ok := e.store.(anchorStore)
+	if !ok || len(results) == 0 {
+		return
+	}
+	ids := make([]int64, 0, len(results))
+	for _, r := range results {
+		if r.MemoryID > 0 {
+			ids = append(ids, r.MemoryID)
+		}
+	}
+	anchors, err := as.GetMemoryAnchors(ctx, ids)
+	if err != nil {
+		return
+	}
+	for i := range results {
+		if a, ok := anchors[results[i].MemoryID]; ok {
+			results[i].Anchor = &a
+		}
+	}
+}
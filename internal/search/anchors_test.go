@@ -0,0 +1,46 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestSearch_AttachesAnchors(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	anchored, err := s.AddMemory(ctx, &store.Memory{
+		Content:    "deploys to production run through argo on tuesdays",
+		SourceFile: "/runbook.md",
+		Anchor:     &store.ChunkAnchor{ByteStart: 40, ByteEnd: 91, HeadingPath: "Runbook > Deploys"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, _ := s.AddMemory(ctx, &store.Memory{Content: "argo dashboards live behind the vpn", SourceFile: "/misc.json"})
+
+	results, err := NewEngine(s).Search(ctx, "argo", Options{Mode: ModeKeyword, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := 0
+	for _, r := range results {
+		switch r.MemoryID {
+		case anchored:
+			seen++
+			if r.Anchor == nil || r.Anchor.ByteStart != 40 || r.Anchor.ByteEnd != 91 || r.Anchor.HeadingPath != "Runbook > Deploys" {
+				t.Fatalf("anchored result = %+v", r.Anchor)
+			}
+		case plain:
+			seen++
+			if r.Anchor != nil {
+				t.Fatalf("memory without anchor got %+v", r.Anchor)
+			}
+		}
+	}
+	if seen != 2 {
+		t.Fatalf("expected both memories in results, got %+v", results)
+	}
+}
@@ -0,0 +1,199 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// diagnoseCandidateLimit caps how many FTS matches a no-result diagnosis
+// walks through the filters.
+const diagnoseCandidateLimit = 1000
+
+// DiagnosisStage is one filtering step of a no-result diagnosis.
+type DiagnosisStage struct {
+	Stage     string `json:"stage"`
+	Removed   int    `json:"removed"`
+	Remaining int    `json:"remaining"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// NoResultDiagnosis explains why a search returned nothing: how many
+// memories the store holds, how many FTS matched, and how many each filter
+// stage removed. Cause names the stage that emptied the candidate set.
+type NoResultDiagnosis struct {
+	Query         string           `json:"query"`
+	Mode          Mode             `json:"mode"`
+	StoreMemories int64            `json:"store_memories"`
+	Candidates    int              `json:"candidates"`
+	Capped        bool             `json:"capped,omitempty"` // Candidates hit diagnoseCandidateLimit
+	Stages        []DiagnosisStage `json:"stages"`
+	Cause         string           `json:"cause"`
+}
+
+// DiagnoseNoResults replays the filter stages of Search for a query that
+// returned nothing and counts what each one removed. Candidates come from
+// keyword (FTS) matching in every mode, so for semantic modes the counts
+// show whether the text matches at all, not what the embedding search saw.
+func (e *Engine) DiagnoseNoResults(ctx context.Context, query string, opts Options) (*NoResultDiagnosis, error) {
+	query = strings.TrimSpace(query)
+	ctx = store.WithNamespace(ctx, opts.Namespace)
+	d := &NoResultDiagnosis{Query: query, Mode: opts.Mode, Stages: []DiagnosisStage{}}
+	if d.Mode == "" {
+		d.Mode = ModeKeyword
+	}
+
+	stats, err := e.store.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading store stats: %w", err)
+	}
+	d.StoreMemories = stats.MemoryCount
+	if d.StoreMemories == 0 {
+		d.Cause = "the store has no memories; import something first"
+		return d, nil
+	}
+
+	retrievalQuery := query
+	if shape := shapeOperatorIntentQuery(query); shape.Applied {
+		retrievalQuery = shape.Shaped
+	}
+	if sanitizeFTSQuery(retrievalQuery) == "" {
+		d.Cause = "the query has no searchable terms"
+		return d, nil
+	}
+
+	matches, err := e.ftsCandidates(ctx, retrievalQuery, diagnoseCandidateLimit, "", "")
+	if err != nil {
+		return nil, err
+	}
+	d.Candidates = len(matches)
+	d.Capped = d.Candidates >= diagnoseCandidateLimit
+	if d.Candidates == 0 {
+		d.Cause = "no memory contains the query terms; try other words"
+		if e.embedder == nil {
+			d.Cause += " or a semantic mode with --embed"
+		}
+		return d, nil
+	}
+	remaining := d.Candidates
+	stage := func(name string, after int, detail string) {
+		d.Stages = append(d.Stages, DiagnosisStage{Stage: name, Removed: remaining - after, Remaining: after, Detail: detail})
+		remaining = after
+	}
+
+	// Store-level filters, applied inside the FTS query.
+	if opts.Project != "" {
+		scoped, err := e.ftsCandidates(ctx, retrievalQuery, diagnoseCandidateLimit, opts.Project, "")
+		if err != nil {
+			return nil, err
+		}
+		stage("project", len(scoped), "project="+opts.Project)
+		matches = scoped
+	}
+	if opts.Source != "" {
+		scoped, err := e.ftsCandidates(ctx, retrievalQuery, diagnoseCandidateLimit, opts.Project, opts.Source)
+		if err != nil {
+			return nil, err
+		}
+		kept := scoped[:0]
+		for _, sr := range scoped {
+			if store.MatchesSource(sr.Memory.SourceFile, opts.Source) {
+				kept = append(kept, sr)
+			}
+		}
+		scoped = kept
+		stage("source", len(scoped), "source="+opts.Source)
+		matches = scoped
+	}
+
+	results := make([]Result, 0, len(matches))
+	best := 0.0
+	for _, sr := range matches {
+		r := bm25Result(sr, false)
+		if r.Score > best {
+			best = r.Score
+		}
+		results = append(results, r)
+	}
+	if opts.MinScore >= 0 && d.Mode == ModeKeyword && len(results) > 0 {
+		kept := results[:0]
+		for _, r := range results {
+			if r.Score >= opts.MinScore {
+				kept = append(kept, r)
+			}
+		}
+		results = kept
+		stage("min_score", len(results), fmt.Sprintf("min %.2f, best keyword score %.3f", opts.MinScore, best))
+	}
+
+	if intent, err := normalizeIntent(opts.Intent); err == nil && intent != IntentAll {
+		results = filterByIntent(results, intent)
+		stage("intent", len(results), "intent="+intent)
+	}
+	if opts.Agent != "" || opts.Channel != "" || opts.SessionKey != "" || opts.After != "" || opts.Before != "" {
+		results = filterByMetadata(results, opts)
+		stage("metadata", len(results), describeMetadataFilters(opts))
+	}
+	if !opts.Scope.Empty() {
+		results = e.filterByFactScope(ctx, results, opts.Scope, opts.IncludeSuperseded)
+		stage("scope", len(results), "")
+	}
+	if len(opts.Classes) > 0 {
+		results = filterByClass(results, opts.Classes)
+		stage("class", len(results), "class="+strings.Join(opts.Classes, ","))
+	}
+	before := len(results)
+	results = applyLowSignalIntentSuppression(retrievalQuery, results, false)
+	results = applyOffTopicLowSignalSuppression(retrievalQuery, results, false)
+	results = applyWrapperNoiseSuppression(retrievalQuery, results, false)
+	results = applyLexicalOverlapFilter(retrievalQuery, results, false)
+	if len(results) != before {
+		stage("noise", len(results), "low-signal and off-topic suppression")
+	}
+	if !opts.IncludeSuperseded {
+		results = e.filterSupersededMemories(ctx, results)
+		stage("superseded", len(results), "memories backed only by superseded facts")
+	}
+
+	for _, s := range d.Stages {
+		if s.Remaining == 0 && s.Removed > 0 {
+			d.Cause = fmt.Sprintf("the %s filter removed the last %d %s", s.Stage, s.Removed, pluralCandidates(s.Removed))
+			if s.Stage == "min_score" {
+				d.Cause += "; lower --min-score"
+			}
+			return d, nil
+		}
+	}
+	switch {
+	case d.Mode != ModeKeyword:
+		d.Cause = fmt.Sprintf("%d keyword %s pass every filter, but %s search ranked none above its threshold; try --mode keyword",
+			remaining, pluralCandidates(remaining), d.Mode)
+	case opts.Offset > 0 || opts.Cursor != "":
+		d.Cause = fmt.Sprintf("%d %s pass every filter; the page starts past the last of them", remaining, pluralCandidates(remaining))
+	default:
+		d.Cause = fmt.Sprintf("%d %s pass every filter", remaining, pluralCandidates(remaining))
+	}
+	return d, nil
+}
+
+func pluralCandidates(n int) string {
+	if n == 1 {
+		return "candidate"
+	}
+	return "candidates"
+}
+
+func describeMetadataFilters(opts Options) string {
+	var parts []string
+	for _, kv := range [][2]string{
+		{"agent", opts.Agent}, {"channel", opts.Channel}, {"session", opts.SessionKey},
+		{"after", opts.After}, {"before", opts.Before},
+	} {
+		if kv[1] != "" {
+			parts = append(parts, kv[0]+"="+kv[1])
+		}
+	}
+	return strings.Join(parts, " ")
+}
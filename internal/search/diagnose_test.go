@@ -0,0 +1,71 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestDiagnoseNoResults(t *testing.T) {
+	s := newTestStore(t)
+	engine := NewEngine(s)
+	ctx := context.Background()
+
+	d, err := engine.DiagnoseNoResults(ctx, "deploy", Options{Mode: ModeKeyword, MinScore: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.StoreMemories != 0 || !strings.Contains(d.Cause, "no memories") {
+		t.Fatalf("empty store should be diagnosed as such: %+v", d)
+	}
+
+	for _, m := range []*store.Memory{
+		{Content: "deploy checklist for the payments service", SourceFile: "ops/deploy.md", Project: "ops", MemoryClass: "rule"},
+		{Content: "deploy notes from the staging rollout", SourceFile: "ops/staging.md", Project: "ops", MemoryClass: "status"},
+		{Content: "deploy the blog with hugo", SourceFile: "blog/notes.md", Project: "blog"},
+	} {
+		if _, err := s.AddMemory(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err = engine.DiagnoseNoResults(ctx, "kubernetes", Options{Mode: ModeKeyword, MinScore: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.StoreMemories != 3 || d.Candidates != 0 || !strings.Contains(d.Cause, "no memory contains the query terms") {
+		t.Fatalf("unexpected diagnosis for unmatched terms: %+v", d)
+	}
+
+	opts := Options{Mode: ModeKeyword, MinScore: -1, Project: "ops", Classes: []string{"decision"}}
+	if results, err := engine.Search(ctx, "deploy", opts); err != nil || len(results) != 0 {
+		t.Fatalf("expected no results, got %d (%v)", len(results), err)
+	}
+	d, err = engine.DiagnoseNoResults(ctx, "deploy", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Candidates != 3 || len(d.Stages) < 2 {
+		t.Fatalf("unexpected diagnosis: %+v", d)
+	}
+	if st := d.Stages[0]; st.Stage != "project" || st.Removed != 1 || st.Remaining != 2 {
+		t.Fatalf("unexpected project stage: %+v", st)
+	}
+	if st := d.Stages[1]; st.Stage != "class" || st.Removed != 2 || st.Remaining != 0 {
+		t.Fatalf("unexpected class stage: %+v", st)
+	}
+	if !strings.Contains(d.Cause, "class filter removed the last 2 candidates") {
+		t.Fatalf("unexpected cause: %q", d.Cause)
+	}
+
+	d, err = engine.DiagnoseNoResults(ctx, "deploy", Options{Mode: ModeKeyword, MinScore: 0.99})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Stages) == 0 || d.Stages[0].Stage != "min_score" || d.Stages[0].Remaining != 0 ||
+		!strings.Contains(d.Cause, "lower --min-score") {
+		t.Fatalf("expected min-score diagnosis, got %+v", d)
+	}
+}
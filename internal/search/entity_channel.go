@@ -661,6 +661,9 @@ func (e *Engine) getMemoryCached(ctx context.Context, memoryID int64, memoryCach
 	if err != nil {
 		return nil, fmt.Errorf("loading memory %d for entity graph: %w", memoryID, err)
 	}
+	if memory != nil && !store.NamespaceVisible(store.EffectiveNamespace(ctx, e.store), memory.Namespace) {
+		memory = nil // entity facts span namespaces; skip other tenants' memories
+	}
 	memoryCache[memoryID] = memory
 	return memory, nil
 }
@@ -136,6 +136,7 @@ func ExpandQuery(ctx context.Context, provider llm.Provider, query string) (*Exp
 		System:      expandSystemPrompt,
 		MaxTokens:   200,
 		Temperature: 0.3,
+		Task:        llm.TaskExpand,
 		// NOTE: We do NOT use Format:"json" here because thinking models
 		// (Gemini 2.5/3) consume the JSON in their thinking phase and return
 		// empty/placeholder text. The prompt instructs JSON-only output, and
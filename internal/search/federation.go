@@ -0,0 +1,176 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FederatedMember is one store taking part in a federated search: a
+// workspace database behind its own Searcher.
+type FederatedMember struct {
+	Name     string  // label carried on each result as Result.Workspace
+	Weight   float64 // RRF weight; 0 means 1
+	Searcher Searcher
+}
+
+// FederatedMemberStatus reports how one member fared in a federated search.
+type FederatedMemberStatus struct {
+	Name     string        `json:"name"`
+	Weight   float64       `json:"weight"`
+	Hits     int           `json:"hits"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// FederatedResult is the output of Federation.SearchFederated.
+type FederatedResult struct {
+	Results []Result                `json:"results"`
+	Members []FederatedMemberStatus `json:"members"`
+}
+
+// Federation searches several stores with one query and merges their hits
+// with weighted Reciprocal Rank Fusion. Each member ranks its own results
+// with its full pipeline; only the rankings cross store boundaries, so
+// scores from differently-sized stores never have to be compared.
+type Federation struct {
+	Members []FederatedMember
+	K       int // RRF constant (default 60)
+}
+
+// NewFederation returns a Federation over members.
+func NewFederation(members ...FederatedMember) *Federation {
+	return &Federation{Members: members, K: defaultRRFK}
+}
+
+// Search implements Searcher. It fails only when every member fails; use
+// SearchFederated to see per-member errors.
+func (f *Federation) Search(ctx context.Context, query string, opts Options) ([]Result, error) {
+	res, err := f.SearchFederated(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	return res.Results, nil
+}
+
+// SearchFederated runs query against every member concurrently and fuses
+// the rankings: a hit at rank r in member m scores weight(m)/(K+r). A
+// memory found in more than one member (same source and content) is merged
+// and labeled with the member that ranked it best; the others are listed in
+// AlsoIn. Results are trimmed to opts.Limit.
+func (f *Federation) SearchFederated(ctx context.Context, query string, opts Options) (*FederatedResult, error) {
+	if len(f.Members) == 0 {
+		return nil, fmt.Errorf("federated search needs at least one member")
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	lists := make([][]Result, len(f.Members))
+	statuses := make([]FederatedMemberStatus, len(f.Members))
+	errs := make([]error, len(f.Members))
+	var wg sync.WaitGroup
+	for i, m := range f.Members {
+		statuses[i] = FederatedMemberStatus{Name: m.Name, Weight: memberWeight(m)}
+		wg.Add(1)
+		go func(i int, m FederatedMember) {
+			defer wg.Done()
+			start := time.Now()
+			results, err := m.Searcher.Search(ctx, query, opts)
+			statuses[i].Duration = time.Since(start)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", m.Name, err)
+				statuses[i].Error = err.Error()
+				return
+			}
+			lists[i] = results
+			statuses[i].Hits = len(results)
+		}(i, m)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == len(f.Members) {
+		return nil, errors.Join(errs...)
+	}
+
+	k := f.K
+	if k <= 0 {
+		k = defaultRRFK
+	}
+	type fused struct {
+		result Result
+		best   float64 // the largest single contribution, which picks the label
+		score  float64
+		order  int
+	}
+	byKey := map[string]*fused{}
+	var entries []*fused
+	for i, m := range f.Members {
+		weight := statuses[i].Weight
+		for rank, r := range lists[i] {
+			contribution := weight / float64(k+rank+1)
+			key := r.SourceFile + "\x00" + strings.TrimSpace(r.Content)
+			e, ok := byKey[key]
+			if !ok {
+				r.Workspace = m.Name
+				e = &fused{result: r, best: contribution, order: len(entries)}
+				byKey[key] = e
+				entries = append(entries, e)
+			} else {
+				if contribution > e.best {
+					also := appendUnique(e.result.AlsoIn, e.result.Workspace)
+					r.Workspace = m.Name
+					e.result, e.best = r, contribution
+					e.result.AlsoIn = also
+				} else if m.Name != e.result.Workspace {
+					e.result.AlsoIn = appendUnique(e.result.AlsoIn, m.Name)
+				}
+			}
+			e.score += contribution
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].score == entries[j].score {
+			return entries[i].order < entries[j].order
+		}
+		return entries[i].score > entries[j].score
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	out := &FederatedResult{Results: make([]Result, len(entries)), Members: statuses}
+	for i, e := range entries {
+		e.result.Score = e.score
+		e.result.MatchType = "federated"
+		out.Results[i] = e.result
+	}
+	return out, nil
+}
+
+func memberWeight(m FederatedMember) float64 {
+	if m.Weight <= 0 {
+		return 1
+	}
+	return m.Weight
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
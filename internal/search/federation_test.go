@@ -0,0 +1,86 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type staticSearcher struct {
+	results []Result
+	err     error
+}
+
+func (s staticSearcher) Search(ctx context.Context, query string, opts Options) ([]Result, error) {
+	return s.results, s.err
+}
+
+func TestFederation_WeightedRRFWithProvenance(t *testing.T) {
+	work := staticSearcher{results: []Result{
+		{MemoryID: 1, Content: "deploy runbook", SourceFile: "ops.md"},
+		{MemoryID: 2, Content: "shared note", SourceFile: "shared.md"},
+	}}
+	personal := staticSearcher{results: []Result{
+		{MemoryID: 7, Content: "shared note", SourceFile: "shared.md"},
+		{MemoryID: 8, Content: "grocery list", SourceFile: "home.md"},
+	}}
+
+	res, err := NewFederation(
+		FederatedMember{Name: "work", Weight: 2, Searcher: work},
+		FederatedMember{Name: "personal", Searcher: personal},
+	).SearchFederated(context.Background(), "q", Options{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Results) != 3 {
+		t.Fatalf("expected the shared note merged into 3 results, got %+v", res.Results)
+	}
+
+	// shared: 2/62 + 1/61 beats runbook's 2/61. Work's weighted 2/62
+	// outranks personal's 1/61, so work supplies and labels the hit.
+	top := res.Results[0]
+	if top.Content != "shared note" || top.Workspace != "work" || len(top.AlsoIn) != 1 || top.AlsoIn[0] != "personal" {
+		t.Fatalf("unexpected top result: %+v", top)
+	}
+	if top.MemoryID != 2 {
+		t.Fatalf("the best-ranked copy should supply the result, got memory %d", top.MemoryID)
+	}
+	if res.Results[1].Workspace != "work" || res.Results[2].Workspace != "personal" {
+		t.Fatalf("unexpected order: %+v", res.Results)
+	}
+	for _, r := range res.Results {
+		if r.MatchType != "federated" || r.Score <= 0 {
+			t.Fatalf("expected fused scores, got %+v", r)
+		}
+	}
+	if res.Members[0].Weight != 2 || res.Members[1].Weight != 1 || res.Members[1].Hits != 2 {
+		t.Fatalf("unexpected member status: %+v", res.Members)
+	}
+}
+
+func TestFederation_PartialFailureAndLimit(t *testing.T) {
+	ok := staticSearcher{results: []Result{
+		{MemoryID: 1, Content: "a"}, {MemoryID: 2, Content: "b"}, {MemoryID: 3, Content: "c"},
+	}}
+	broken := staticSearcher{err: errors.New("disk gone")}
+
+	f := NewFederation(
+		FederatedMember{Name: "ok", Searcher: ok},
+		FederatedMember{Name: "broken", Searcher: broken},
+	)
+	res, err := f.SearchFederated(context.Background(), "q", Options{Limit: 2})
+	if err != nil {
+		t.Fatalf("one healthy member should be enough: %v", err)
+	}
+	if len(res.Results) != 2 || res.Results[0].Content != "a" {
+		t.Fatalf("expected the top 2 of the healthy member, got %+v", res.Results)
+	}
+	if res.Members[1].Error == "" {
+		t.Fatalf("expected the failure reported, got %+v", res.Members)
+	}
+
+	f.Members = f.Members[1:]
+	if _, err := f.Search(context.Background(), "q", Options{}); err == nil {
+		t.Fatal("expected an error when every member fails")
+	}
+}
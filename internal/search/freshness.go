@@ -0,0 +1,109 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// DefaultFreshnessFloor is the lowest multiplier freshness decay applies:
+// very old memories keep at least this share of their score.
+const DefaultFreshnessFloor = 0.5
+
+// DefaultFreshnessHalfLives are the per-class half-lives used when
+// search.freshness is enabled without its own half_life_days. The ""
+// entry covers unclassified memories and classes not listed. Rules,
+// decisions, and identity notes don't go stale with age.
+var DefaultFreshnessHalfLives = map[string]time.Duration{
+	"":                          180 * 24 * time.Hour,
+	store.MemoryClassStatus:     30 * 24 * time.Hour,
+	store.MemoryClassScratch:    7 * 24 * time.Hour,
+	store.MemoryClassPreference: 365 * 24 * time.Hour,
+	store.MemoryClassRule:       0,
+	store.MemoryClassDecision:   0,
+	store.MemoryClassIdentity:   0,
+}
+
+// Freshness configures memory-level recency decay. When set on Options it
+// replaces the tiered recency boost: a memory's score is multiplied by
+// Floor + (1-Floor)·0.5^(age/half-life), so a memory one half-life old sits
+// halfway between full score and the floor.
+type Freshness struct {
+	HalfLife map[string]time.Duration // by memory class; "" = default, 0 = never decays
+	Floor    float64                  // 0–1; values outside the range use DefaultFreshnessFloor
+}
+
+// FreshnessFromConfig builds the decay settings from search.freshness, or
+// returns nil when it is disabled. Configured half-lives override the
+// defaults class by class; the "default" key sets the "" entry.
+func FreshnessFromConfig(cfg cfgresolver.SearchFreshnessConfig) *Freshness {
+	if !cfg.Enabled {
+		return nil
+	}
+	f := &Freshness{HalfLife: make(map[string]time.Duration, len(DefaultFreshnessHalfLives)), Floor: cfg.Floor}
+	for class, halfLife := range DefaultFreshnessHalfLives {
+		f.HalfLife[class] = halfLife
+	}
+	for class, days := range cfg.HalfLifeDays {
+		class = store.NormalizeMemoryClass(class)
+		if class == "default" {
+			class = ""
+		}
+		f.HalfLife[class] = time.Duration(math.Max(0, days) * float64(24*time.Hour))
+	}
+	return f
+}
+
+// halfLifeFor returns the half-life for a memory class.
+func (f *Freshness) halfLifeFor(class string) time.Duration {
+	if halfLife, ok := f.HalfLife[store.NormalizeMemoryClass(class)]; ok {
+		return halfLife
+	}
+	return f.HalfLife[""]
+}
+
+func (f *Freshness) floor() float64 {
+	if f.Floor <= 0 || f.Floor >= 1 {
+		return DefaultFreshnessFloor
+	}
+	return f.Floor
+}
+
+// Multiplier returns the decay multiplier for a memory of the given class
+// and age, and the half-life applied (0 when the class never decays).
+func (f *Freshness) Multiplier(class string, age time.Duration) (float64, time.Duration) {
+	halfLife := f.halfLifeFor(class)
+	if halfLife <= 0 || age <= 0 {
+		return 1.0, halfLife
+	}
+	floor := f.floor()
+	return floor + (1-floor)*math.Pow(0.5, float64(age)/float64(halfLife)), halfLife
+}
+
+// applyFreshnessDecay scales each memory's score by its freshness
+// multiplier. Pinned directives and results without an import time are
+// left alone.
+func applyFreshnessDecay(results []Result, f *Freshness, explain bool) []Result {
+	now := timeNow()
+	for i := range results {
+		if results[i].ImportedAt.IsZero() || strings.EqualFold(results[i].Kind, "directive") {
+			continue
+		}
+		multiplier, halfLife := f.Multiplier(results[i].MemoryClass, now.Sub(results[i].ImportedAt))
+		results[i].Score *= multiplier
+		if explain {
+			ensureExplain(&results[i])
+			results[i].Explain.RankComponents.FreshnessMultiplier = multiplier
+			results[i].Explain.RankComponents.FreshnessHalfLifeDays = halfLife.Hours() / 24
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
@@ -0,0 +1,91 @@
+package search
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+)
+
+func TestFreshnessFromConfig(t *testing.T) {
+	if f := FreshnessFromConfig(cfgresolver.SearchFreshnessConfig{}); f != nil {
+		t.Fatalf("disabled config should yield nil, got %+v", f)
+	}
+	f := FreshnessFromConfig(cfgresolver.SearchFreshnessConfig{
+		Enabled:      true,
+		Floor:        0.2,
+		HalfLifeDays: map[string]float64{"default": 60, "Status": 14, "rule": 90},
+	})
+	day := 24 * time.Hour
+	for class, want := range map[string]time.Duration{
+		"":         60 * day,
+		"status":   14 * day,
+		"rule":     90 * day,
+		"scratch":  7 * day, // untouched default
+		"decision": 0,
+		"unknown":  60 * day,
+	} {
+		if got := f.halfLifeFor(class); got != want {
+			t.Errorf("half-life for %q = %v, want %v", class, got, want)
+		}
+	}
+	if DefaultFreshnessHalfLives[""] != 180*day {
+		t.Fatal("FreshnessFromConfig must not mutate the defaults")
+	}
+}
+
+func TestFreshnessMultiplier(t *testing.T) {
+	day := 24 * time.Hour
+	f := &Freshness{HalfLife: map[string]time.Duration{"": 10 * day, "rule": 0}}
+
+	if m, _ := f.Multiplier("", 0); m != 1.0 {
+		t.Fatalf("brand-new memory multiplier = %f, want 1", m)
+	}
+	if m, _ := f.Multiplier("", 10*day); math.Abs(m-0.75) > 1e-9 {
+		t.Fatalf("one half-life multiplier = %f, want 0.75 (halfway to the 0.5 floor)", m)
+	}
+	if m, _ := f.Multiplier("", 1000*day); m < DefaultFreshnessFloor || m > DefaultFreshnessFloor+1e-6 {
+		t.Fatalf("ancient memory multiplier = %f, want the floor", m)
+	}
+	if m, halfLife := f.Multiplier("rule", 1000*day); m != 1.0 || halfLife != 0 {
+		t.Fatalf("rule should never decay, got %f (half-life %v)", m, halfLife)
+	}
+}
+
+func TestApplyFreshnessDecay_ReranksStaleStatus(t *testing.T) {
+	now := time.Now().UTC()
+	oldTimeNow := timeNow
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = oldTimeNow })
+
+	f := &Freshness{HalfLife: DefaultFreshnessHalfLives}
+	results := []Result{
+		{Content: "status from two years ago", Score: 1.0, MemoryClass: "status", ImportedAt: now.AddDate(-2, 0, 0)},
+		{Content: "rule from two years ago", Score: 0.9, MemoryClass: "rule", ImportedAt: now.AddDate(-2, 0, 0)},
+		{Content: "status from yesterday", Score: 0.8, MemoryClass: "status", ImportedAt: now.AddDate(0, 0, -1)},
+		{Content: "pinned", Score: 0.1, Kind: "directive", ImportedAt: now.AddDate(-2, 0, 0)},
+	}
+
+	ranked := applyFreshnessDecay(results, f, true)
+	if ranked[0].Content != "rule from two years ago" || ranked[1].Content != "status from yesterday" {
+		t.Fatalf("unexpected order: %q, %q, %q", ranked[0].Content, ranked[1].Content, ranked[2].Content)
+	}
+	for _, r := range ranked {
+		switch r.Content {
+		case "status from yesterday":
+			rc := r.Explain.RankComponents
+			if rc.FreshnessHalfLifeDays != 30 || rc.FreshnessMultiplier >= 1 || rc.FreshnessMultiplier < 0.98 {
+				t.Fatalf("unexpected explain for fresh status: %+v", rc)
+			}
+		case "rule from two years ago":
+			if r.Explain.RankComponents.FreshnessMultiplier != 1.0 || r.Score != 0.9 {
+				t.Fatalf("rule should not decay: score=%f explain=%+v", r.Score, r.Explain.RankComponents)
+			}
+		case "pinned":
+			if r.Score != 0.1 || r.Explain != nil {
+				t.Fatalf("directives should be left alone: %+v", r)
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// Graph boost bridges text search and the fact graph: the top hits are
+// taken as what the query matched, and other hits whose facts share an
+// edge with theirs move up. A hit linked to a seed by an edge of
+// confidence c is multiplied by 1 + weight·c, using its strongest link.
+const (
+	// graphBoostSeeds is how many top hits seed the boost.
+	graphBoostSeeds = 5
+	// graphBoostWindow is how many hits are checked for links to a seed.
+	graphBoostWindow = 50
+	// MaxGraphBoost caps Options.GraphBoost.
+	MaxGraphBoost = 1.0
+)
+
+// graphEdgeStore is implemented by stores that can list the edges among a
+// set of facts (SQLiteStore).
+type graphEdgeStore interface {
+	EdgesAmongFacts(ctx context.Context, factIDs []int64) ([]store.FactEdge, error)
+}
+
+// graphLink is a hit's strongest edge to a seed hit.
+type graphLink struct {
+	seedMemory int64
+	edgeType   store.EdgeType
+	confidence float64
+}
+
+// applyGraphBoost raises hits linked through fact edges to the top
+// graphBoostSeeds hits. Edges between facts of the same memory don't
+// count. Stores without edges, or a weight <= 0, leave results unchanged.
+func (e *Engine) applyGraphBoost(ctx context.Context, results []Result, weight float64, explain bool) []Result {
+	if weight <= 0 || len(results) < 2 {
+		return results
+	}
+	if weight > MaxGraphBoost {
+		weight = MaxGraphBoost
+	}
+	es, ok := e.store.(graphEdgeStore)
+	if !ok {
+		return results
+	}
+
+	window := results
+	if len(window) > graphBoostWindow {
+		window = window[:graphBoostWindow]
+	}
+	memoryIDs := make([]int64, 0, len(window))
+	for _, r := range window {
+		if r.MemoryID > 0 {
+			memoryIDs = append(memoryIDs, r.MemoryID)
+		}
+	}
+	facts, err := e.store.GetFactsByMemoryIDs(ctx, memoryIDs)
+	if err != nil || len(facts) < 2 {
+		return results
+	}
+	memoryOf := make(map[int64]int64, len(facts))
+	factIDs := make([]int64, 0, len(facts))
+	for _, f := range facts {
+		memoryOf[f.ID] = f.MemoryID
+		factIDs = append(factIDs, f.ID)
+	}
+	edges, err := es.EdgesAmongFacts(ctx, factIDs)
+	if err != nil || len(edges) == 0 {
+		return results
+	}
+
+	seeds := make(map[int64]bool, graphBoostSeeds)
+	for i := 0; i < len(window) && len(seeds) < graphBoostSeeds; i++ {
+		if window[i].MemoryID > 0 {
+			seeds[window[i].MemoryID] = true
+		}
+	}
+	links := map[int64]graphLink{}
+	link := func(memoryID, seed int64, edge store.FactEdge) {
+		if best, ok := links[memoryID]; !ok || edge.Confidence > best.confidence {
+			links[memoryID] = graphLink{seedMemory: seed, edgeType: edge.EdgeType, confidence: edge.Confidence}
+		}
+	}
+	for _, edge := range edges {
+		a, b := memoryOf[edge.SourceFactID], memoryOf[edge.TargetFactID]
+		if a == 0 || b == 0 || a == b {
+			continue
+		}
+		if seeds[a] {
+			link(b, a, edge)
+		}
+		if seeds[b] {
+			link(a, b, edge)
+		}
+	}
+	if len(links) == 0 {
+		return results
+	}
+
+	for i := range results {
+		l, ok := links[results[i].MemoryID]
+		if !ok {
+			continue
+		}
+		multiplier := 1 + weight*l.confidence
+		results[i].Score *= multiplier
+		if explain {
+			ensureExplain(&results[i])
+			results[i].Explain.RankComponents.GraphBoostMultiplier = multiplier
+			results[i].Explain.RankComponents.GraphBoostSeedMemory = l.seedMemory
+			msg := fmt.Sprintf("graph boost ×%.2f: %s edge (%.2f) to memory #%d", multiplier, l.edgeType, l.confidence, l.seedMemory)
+			if results[i].Explain.Why == "" {
+				results[i].Explain.Why = msg
+			} else {
+				results[i].Explain.Why += "; " + msg
+			}
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results
+}
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestApplyGraphBoost_RaisesHitsLinkedToTopHits(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	sqlStore := s.(*store.SQLiteStore)
+
+	addMemoryFact := func(content, subject string) (int64, int64) {
+		t.Helper()
+		memID, err := s.AddMemory(ctx, &store.Memory{Content: content, SourceFile: subject + ".md"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		factID, err := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: subject, Predicate: "is", Object: content, FactType: "kv"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return memID, factID
+	}
+	seedMem, seedFact := addMemoryFact("deploys run through argo", "deploy")
+	linkedMem, linkedFact := addMemoryFact("argo needs the staging kubeconfig", "kubeconfig")
+	otherMem, _ := addMemoryFact("lunch is at noon", "lunch")
+	if err := sqlStore.AddEdge(ctx, &store.FactEdge{SourceFactID: linkedFact, TargetFactID: seedFact, EdgeType: store.EdgeTypeRelatesTo, Confidence: 0.8}); err != nil {
+		t.Fatal(err)
+	}
+
+	results := func() []Result {
+		return []Result{
+			{MemoryID: seedMem, Score: 1.0},
+			{MemoryID: otherMem, Score: 0.5},
+			{MemoryID: linkedMem, Score: 0.45},
+		}
+	}
+	engine := NewEngine(s)
+
+	if got := engine.applyGraphBoost(ctx, results(), 0, true); got[2].MemoryID != linkedMem || got[2].Explain != nil {
+		t.Fatalf("weight 0 should leave results alone: %+v", got)
+	}
+
+	got := engine.applyGraphBoost(ctx, results(), 0.5, true)
+	if got[1].MemoryID != linkedMem {
+		t.Fatalf("linked hit should overtake the unrelated one: %+v", got)
+	}
+	linked := got[1]
+	if linked.Score < 0.629 || linked.Score > 0.631 {
+		t.Fatalf("linked score = %.3f, want 0.45 × 1.4", linked.Score)
+	}
+	rc := linked.Explain.RankComponents
+	if rc.GraphBoostMultiplier < 1.399 || rc.GraphBoostMultiplier > 1.401 || rc.GraphBoostSeedMemory != seedMem {
+		t.Fatalf("explain = %+v", rc)
+	}
+	if !strings.Contains(linked.Explain.Why, "graph boost ×1.40: relates_to edge (0.80)") {
+		t.Fatalf("why = %q", linked.Explain.Why)
+	}
+	// With three hits the linked one is a seed too, so the seed gets the
+	// same boost back.
+	if got[0].MemoryID != seedMem || got[0].Score <= 1.0 {
+		t.Fatalf("seed = %+v", got[0])
+	}
+	if got[2].Explain != nil {
+		t.Fatalf("unlinked hit should not be boosted: %+v", got[2])
+	}
+}
+
+func TestApplyGraphBoost_IgnoresEdgesWithinOneMemory(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	sqlStore := s.(*store.SQLiteStore)
+
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "two facts", SourceFile: "a.md"})
+	f1, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "a", Predicate: "p", Object: "1", FactType: "kv"})
+	f2, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "a", Predicate: "q", Object: "2", FactType: "kv"})
+	otherID, _ := s.AddMemory(ctx, &store.Memory{Content: "other", SourceFile: "b.md"})
+	if err := sqlStore.AddEdge(ctx, &store.FactEdge{SourceFactID: f1, TargetFactID: f2, EdgeType: store.EdgeTypeSupports}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := NewEngine(s).applyGraphBoost(ctx, []Result{{MemoryID: memID, Score: 1}, {MemoryID: otherID, Score: 0.5}}, 1, false)
+	if got[0].Score != 1 || got[1].Score != 0.5 {
+		t.Fatalf("edges inside one memory should not boost: %+v", got)
+	}
+}
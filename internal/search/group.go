@@ -0,0 +1,96 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GroupBy selects how search results are clustered.
+type GroupBy string
+
+const (
+	GroupBySource  GroupBy = "source"
+	GroupByProject GroupBy = "project"
+	GroupByClass   GroupBy = "class"
+)
+
+// GroupCandidateMultiplier is how many more hits to fetch when grouping, so
+// one document with many matching chunks does not crowd out the rest.
+const GroupCandidateMultiplier = 5
+
+// ParseGroupBy converts a --group-by value to a GroupBy.
+func ParseGroupBy(s string) (GroupBy, error) {
+	switch GroupBy(strings.ToLower(strings.TrimSpace(s))) {
+	case GroupBySource:
+		return GroupBySource, nil
+	case GroupByProject:
+		return GroupByProject, nil
+	case GroupByClass:
+		return GroupByClass, nil
+	default:
+		return "", fmt.Errorf("invalid group-by %q (valid: source, project, class)", s)
+	}
+}
+
+// ResultGroup is a set of hits sharing a source document, project, or class.
+type ResultGroup struct {
+	Key       string   `json:"key"`
+	BestScore float64  `json:"best_score"`
+	Count     int      `json:"count"`
+	Results   []Result `json:"results"`
+}
+
+// GroupResults clusters ranked results by the given key. Groups are ordered
+// by their best score (ties keep first-seen order) and hits keep their rank
+// within a group. limit caps the number of groups; 0 keeps all.
+func GroupResults(results []Result, by GroupBy, limit int) []ResultGroup {
+	index := map[string]int{}
+	var groups []ResultGroup
+	for _, r := range results {
+		key := groupKey(r, by)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ResultGroup{Key: key, BestScore: r.Score})
+		}
+		g := &groups[i]
+		g.Results = append(g.Results, r)
+		g.Count++
+		if r.Score > g.BestScore {
+			g.BestScore = r.Score
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].BestScore > groups[j].BestScore
+	})
+	if limit > 0 && len(groups) > limit {
+		groups = groups[:limit]
+	}
+	return groups
+}
+
+func groupKey(r Result, by GroupBy) string {
+	var key string
+	switch by {
+	case GroupByProject:
+		key = r.Project
+	case GroupByClass:
+		key = r.MemoryClass
+	default:
+		key = r.SourceFile
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		switch by {
+		case GroupByProject:
+			return "(no project)"
+		case GroupByClass:
+			return "(unclassified)"
+		default:
+			return "(unknown source)"
+		}
+	}
+	return key
+}
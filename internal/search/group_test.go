@@ -0,0 +1,55 @@
+package search
+
+import "testing"
+
+func TestGroupResults_BySource(t *testing.T) {
+	results := []Result{
+		{SourceFile: "deploy.md", Score: 0.9},
+		{SourceFile: "deploy.md", Score: 0.8},
+		{SourceFile: "notes.md", Score: 0.7},
+		{SourceFile: "deploy.md", Score: 0.6},
+		{SourceFile: "", Score: 0.95},
+	}
+	groups := GroupResults(results, GroupBySource, 0)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(groups))
+	}
+	if groups[0].Key != "(unknown source)" || groups[0].BestScore != 0.95 {
+		t.Fatalf("unexpected first group: %+v", groups[0])
+	}
+	if groups[1].Key != "deploy.md" || groups[1].Count != 3 || groups[1].BestScore != 0.9 {
+		t.Fatalf("unexpected deploy group: %+v", groups[1])
+	}
+	if groups[1].Results[2].Score != 0.6 {
+		t.Fatalf("expected hits to keep rank order, got %+v", groups[1].Results)
+	}
+
+	if limited := GroupResults(results, GroupBySource, 2); len(limited) != 2 || limited[1].Key != "deploy.md" {
+		t.Fatalf("expected limit to cap groups, got %+v", limited)
+	}
+}
+
+func TestGroupResults_ByProjectAndClass(t *testing.T) {
+	results := []Result{
+		{Project: "trading", MemoryClass: "rule", Score: 0.5},
+		{Project: "", MemoryClass: "", Score: 0.4},
+		{Project: "trading", MemoryClass: "decision", Score: 0.3},
+	}
+	byProject := GroupResults(results, GroupByProject, 0)
+	if len(byProject) != 2 || byProject[0].Key != "trading" || byProject[0].Count != 2 || byProject[1].Key != "(no project)" {
+		t.Fatalf("unexpected project groups: %+v", byProject)
+	}
+	byClass := GroupResults(results, GroupByClass, 0)
+	if len(byClass) != 3 || byClass[1].Key != "(unclassified)" {
+		t.Fatalf("unexpected class groups: %+v", byClass)
+	}
+}
+
+func TestParseGroupBy(t *testing.T) {
+	if by, err := ParseGroupBy(" Source "); err != nil || by != GroupBySource {
+		t.Fatalf("ParseGroupBy(Source) = %q, %v", by, err)
+	}
+	if _, err := ParseGroupBy("agent"); err == nil {
+		t.Fatal("expected error for unsupported group-by")
+	}
+}
@@ -0,0 +1,119 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// Highlight styles, selected with Highlight.Style.
+const (
+	HighlightMark  = "mark"  // <mark>term</mark>, for JSON and HTML consumers
+	HighlightANSI  = "ansi"  // bold yellow, for terminals
+	HighlightPlain = "plain" // the snippet window without markup
+)
+
+const (
+	ansiHighlightOpen  = "\x1b[1;33m"
+	ansiHighlightClose = "\x1b[0m"
+)
+
+// Highlight asks Search to replace each result's Snippet with an FTS5
+// snippet of the memory around the query terms.
+type Highlight struct {
+	Style  string // mark (default), ansi, or plain
+	Tokens int    // snippet window in tokens, 1–64 (0 = 32)
+}
+
+// ParseHighlightStyle normalizes a highlight style name. Empty selects mark.
+func ParseHighlightStyle(style string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(style)) {
+	case "", HighlightMark:
+		return HighlightMark, nil
+	case HighlightANSI:
+		return HighlightANSI, nil
+	case HighlightPlain, "none":
+		return HighlightPlain, nil
+	default:
+		return "", fmt.Errorf("invalid highlight style %q (valid: mark, ansi, plain)", style)
+	}
+}
+
+// ValidateSnippetTokens checks a snippet window size; 0 means the default.
+func ValidateSnippetTokens(tokens int) error {
+	if tokens < 0 || tokens > store.MaxSnippetTokens {
+		return fmt.Errorf("snippet tokens must be between 1 and %d", store.MaxSnippetTokens)
+	}
+	return nil
+}
+
+// highlightStore is implemented by stores with an FTS5 index (SQLiteStore).
+type highlightStore interface {
+	HighlightSnippets(ctx context.Context, ftsQuery string, memoryIDs []int64, tokens int) (map[int64]string, error)
+}
+
+// attachHighlights replaces result snippets with highlighted FTS5 snippets.
+// Results the query has no lexical match in (semantic-only hits, fact
+// triples) keep their snippet, restyled if it came from BM25. Highlighting is
+// cosmetic: a lookup failure leaves snippets as they were.
+func (e *Engine) attachHighlights(ctx context.Context, query string, results []Result, h Highlight) {
+	if len(results) == 0 {
+		return
+	}
+	style, err := ParseHighlightStyle(h.Style)
+	if err != nil {
+		style = HighlightMark
+	}
+
+	var snippets map[int64]string
+	if hs, ok := e.store.(highlightStore); ok {
+		ids := make([]int64, 0, len(results))
+		for _, r := range results {
+			if r.MemoryID > 0 {
+				ids = append(ids, r.MemoryID)
+			}
+		}
+		if ftsQuery := highlightFTSQuery(query); ftsQuery != "" {
+			snippets, _ = hs.HighlightSnippets(ctx, ftsQuery, ids, h.Tokens)
+		}
+	}
+
+	for i := range results {
+		if snippet, ok := snippets[results[i].MemoryID]; ok {
+			results[i].Snippet = styleHighlights(snippet, store.HighlightOpen, store.HighlightClose, style)
+			continue
+		}
+		results[i].Snippet = styleHighlights(results[i].Snippet, "<b>", "</b>", style)
+	}
+}
+
+// highlightFTSQuery turns a search query into an FTS5 query matching any of
+// its terms, leaving out stopwords unless the query is nothing but.
+func highlightFTSQuery(query string) string {
+	words := strings.Fields(sanitizeFTSQuery(query))
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		if _, stop := rerankStopwords[strings.ToLower(strings.Trim(w, `"`))]; !stop {
+			terms = append(terms, w)
+		}
+	}
+	if len(terms) == 0 {
+		terms = words
+	}
+	escaped := strings.Fields(escapeFTSQuery(strings.Join(terms, " ")))
+	return strings.Join(escaped, " OR ")
+}
+
+// styleHighlights rewrites open/close highlight markers in the given style.
+func styleHighlights(snippet, open, close, style string) string {
+	switch style {
+	case HighlightANSI:
+		return strings.NewReplacer(open, ansiHighlightOpen, close, ansiHighlightClose).Replace(snippet)
+	case HighlightPlain:
+		return strings.NewReplacer(open, "", close, "").Replace(snippet)
+	default:
+		return strings.NewReplacer(open, "<mark>", close, "</mark>").Replace(snippet)
+	}
+}
@@ -0,0 +1,62 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestSearch_Highlight(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	content := strings.Repeat("background notes about the team offsite ", 10) + "we deploy the api through argo every friday " + strings.Repeat("and other unrelated planning ", 10)
+	if _, err := s.AddMemory(ctx, &store.Memory{Content: content, SourceFile: "ops.md"}); err != nil {
+		t.Fatal(err)
+	}
+	engine := NewEngine(s)
+
+	search := func(h *Highlight) string {
+		t.Helper()
+		opts := DefaultOptions()
+		opts.Mode = ModeKeyword
+		opts.Highlight = h
+		results, err := engine.Search(ctx, "how do we deploy the api", opts)
+		if err != nil || len(results) != 1 {
+			t.Fatalf("Search = %d results, %v", len(results), err)
+		}
+		return results[0].Snippet
+	}
+
+	if got := search(nil); strings.Contains(got, "<mark>") {
+		t.Fatalf("snippet highlighted without Highlight: %q", got)
+	}
+	got := search(&Highlight{Style: HighlightMark, Tokens: 12})
+	if !strings.Contains(got, "<mark>deploy</mark>") || !strings.Contains(got, "<mark>api</mark>") {
+		t.Fatalf("mark snippet = %q", got)
+	}
+	if strings.Contains(got, "<mark>how</mark>") || strings.Contains(got, "<mark>the</mark>") {
+		t.Fatalf("stopwords highlighted: %q", got)
+	}
+	if got := search(&Highlight{Style: HighlightANSI}); !strings.Contains(got, ansiHighlightOpen+"deploy"+ansiHighlightClose) {
+		t.Fatalf("ansi snippet = %q", got)
+	}
+	if got := search(&Highlight{Style: HighlightPlain}); strings.ContainsAny(got, "<\x1b\x02") || !strings.Contains(got, "deploy") {
+		t.Fatalf("plain snippet = %q", got)
+	}
+}
+
+func TestParseHighlightStyle(t *testing.T) {
+	for in, want := range map[string]string{"": HighlightMark, "ANSI": HighlightANSI, "none": HighlightPlain} {
+		if got, err := ParseHighlightStyle(in); err != nil || got != want {
+			t.Fatalf("ParseHighlightStyle(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseHighlightStyle("bold"); err == nil {
+		t.Fatal("expected error for unknown style")
+	}
+	if err := ValidateSnippetTokens(65); err == nil {
+		t.Fatal("expected error for 65 snippet tokens")
+	}
+}
@@ -0,0 +1,88 @@
+package search
+
+import (
+	"context"
+	"time"
+)
+
+// HNSW warm-up states reported by HNSWStatus.
+const (
+	HNSWCold    = "cold"    // no index attached and no warm-up started
+	HNSWWarming = "warming" // background load/build in progress
+	HNSWReady   = "ready"   // index attached; semantic search uses HNSW
+	HNSWEmpty   = "empty"   // warm-up finished but the store has no embeddings
+	HNSWFailed  = "failed"  // warm-up failed; semantic search stays brute-force
+)
+
+// HNSWStatus describes the vector index readiness of an engine. Servers use
+// it for readiness probes: BM25 is served as soon as the store is open, while
+// semantic search upgrades from brute force to HNSW once State is "ready".
+type HNSWStatus struct {
+	State     string    `json:"state"`
+	Vectors   int       `json:"vectors"`
+	Mapped    bool      `json:"mapped"` // vectors are paged in from a file mapping
+	Error     string    `json:"error,omitempty"`
+	StartedAt time.Time `json:"started_at,omitzero"`
+	ElapsedMs int64     `json:"elapsed_ms,omitempty"`
+}
+
+// WarmHNSW loads (or, if missing or stale, builds) the HNSW index at path in
+// the background via LoadOrBuildHNSW, which memory-maps persisted indexes.
+// The engine keeps answering searches meanwhile: BM25 is unaffected and
+// semantic search falls back to brute force until the index is attached.
+// The returned channel is closed when warm-up finishes.
+func (e *Engine) WarmHNSW(ctx context.Context, path string, staleThresholdSec int64) <-chan struct{} {
+	done := make(chan struct{})
+	started := time.Now()
+
+	e.hnswMu.Lock()
+	e.hnswWarm = HNSWStatus{State: HNSWWarming, StartedAt: started}
+	e.hnswMu.Unlock()
+
+	go func() {
+		defer close(done)
+		count, err := e.LoadOrBuildHNSW(ctx, path, staleThresholdSec)
+
+		status := HNSWStatus{StartedAt: started, ElapsedMs: time.Since(started).Milliseconds(), Vectors: count}
+		switch {
+		case err != nil:
+			status.State = HNSWFailed
+			status.Error = err.Error()
+		case count == 0:
+			status.State = HNSWEmpty
+		default:
+			status.State = HNSWReady
+			if idx := e.index(); idx != nil {
+				status.Mapped = idx.Mapped()
+			}
+		}
+
+		e.hnswMu.Lock()
+		e.hnswWarm = status
+		e.hnswMu.Unlock()
+	}()
+	return done
+}
+
+// HNSWStatus reports vector index readiness. Engines whose index was attached
+// synchronously (SetHNSW, BuildHNSW, LoadOrBuildHNSW) report "ready".
+func (e *Engine) HNSWStatus() HNSWStatus {
+	e.hnswMu.RLock()
+	status := e.hnswWarm
+	idx := e.hnsw
+	e.hnswMu.RUnlock()
+
+	if status.State == HNSWWarming {
+		status.ElapsedMs = time.Since(status.StartedAt).Milliseconds()
+		return status
+	}
+	if idx != nil {
+		status.State = HNSWReady
+		status.Vectors = idx.Len()
+		status.Mapped = idx.Mapped()
+		status.Error = ""
+	} else if status.State == "" {
+		status.State = HNSWCold
+	}
+	return status
+}
@@ -0,0 +1,56 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestWarmHNSW_MapsPersistedIndexAndReportsReady(t *testing.T) {
+	s, dbPath := newFileBackedTestStore(t)
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		id, err := s.AddMemory(ctx, &store.Memory{
+			Content:    fmt.Sprintf("warm-up memory %d", i),
+			SourceFile: "warm.md",
+			SourceLine: i + 1,
+		})
+		if err != nil {
+			t.Fatalf("AddMemory: %v", err)
+		}
+		if err := s.AddEmbedding(ctx, id, []float32{float32(i + 1), 0.5, 0.1}); err != nil {
+			t.Fatalf("AddEmbedding: %v", err)
+		}
+	}
+	hnswPath := filepath.Join(filepath.Dir(dbPath), "hnsw.idx")
+	if _, err := NewEngine(s).LoadOrBuildHNSW(ctx, hnswPath, 3600); err != nil {
+		t.Fatalf("seed index: %v", err)
+	}
+
+	engine := NewEngine(s)
+	if got := engine.HNSWStatus().State; got != HNSWCold {
+		t.Fatalf("state before warm-up = %q, want %q", got, HNSWCold)
+	}
+
+	<-engine.WarmHNSW(ctx, hnswPath, 3600)
+	status := engine.HNSWStatus()
+	if status.State != HNSWReady || status.Vectors != 5 {
+		t.Fatalf("status after warm-up = %+v", status)
+	}
+	if !status.Mapped {
+		t.Fatal("expected persisted index to be memory-mapped")
+	}
+}
+
+func TestWarmHNSW_EmptyStore(t *testing.T) {
+	s, dbPath := newFileBackedTestStore(t)
+	engine := NewEngine(s)
+
+	<-engine.WarmHNSW(context.Background(), filepath.Join(filepath.Dir(dbPath), "hnsw.idx"), 3600)
+	if status := engine.HNSWStatus(); status.State != HNSWEmpty {
+		t.Fatalf("status = %+v, want empty", status)
+	}
+}
@@ -1,6 +1,7 @@
 package search
 
 import (
+	"context"
 	"math"
 	"testing"
 	"time"
@@ -214,6 +215,40 @@ func TestFilterBySource_NoMatch(t *testing.T) {
 	}
 }
 
+func TestFilterBySource_TypeAndDisplayName(t *testing.T) {
+	results := []Result{
+		{Content: "issue", SourceFile: "github:issues/1"},
+		{Content: "event", SourceFile: "calendar:event/9"},
+		{Content: "note", SourceFile: "memory/notes.md"},
+		{Content: "capture", SourceFile: "/tmp/cortex-capture-1/auto-capture.md"},
+	}
+	if got := filterBySource(results, "connector"); len(got) != 2 {
+		t.Errorf("--source connector kept %d, want 2", len(got))
+	}
+	if got := filterBySource(results, "Google Calendar"); len(got) != 1 || got[0].Content != "event" {
+		t.Errorf("--source by display name: %+v", got)
+	}
+	if got := filterBySource(results, "file"); len(got) != 1 || got[0].Content != "note" {
+		t.Errorf("--source file: %+v", got)
+	}
+}
+
+func TestSourceWeightForFile_TrustWeight(t *testing.T) {
+	s := newTestStore(t).(*store.SQLiteStore)
+	ctx := context.Background()
+	if err := s.SetSource(ctx, store.Source{Key: "slack", Type: store.SourceTypeConnector, DisplayName: "Slack", TrustWeight: 0.5}); err != nil {
+		t.Fatalf("SetSource: %v", err)
+	}
+	t.Cleanup(func() { s.ResetSource(ctx, "slack") })
+
+	if got, want := sourceWeightForFile("slack:general/1"), sourceWeightConnector*0.5; math.Abs(got-want) > 1e-9 {
+		t.Errorf("slack weight = %f, want %f", got, want)
+	}
+	if got := sourceWeightForFile("github:issues/1"); math.Abs(got-sourceWeightConnector) > 1e-9 {
+		t.Errorf("untouched source weight = %f, want %f", got, sourceWeightConnector)
+	}
+}
+
 func TestIsConnectorSource(t *testing.T) {
 	tests := []struct {
 		source string
@@ -228,6 +263,10 @@ func TestIsConnectorSource(t *testing.T) {
 		{"telegram:chats/789", true},
 		{"notion:pages/abc", true},
 		{"obsidian:notes/cortex.md", true},
+		{"jira:issue/PROJ-1", true},
+		{"imap:thread/abc", true},
+		{"webhook:github:push", false},
+		{"github/README.md", false},
 		{"memory/2026-02-23.md", false},
 		{"MEMORY.md", false},
 		{"/Users/q/notes.md", false},
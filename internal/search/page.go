@@ -0,0 +1,166 @@
+package search
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxPagedCandidates caps how deep paging can reach into a ranked list.
+const maxPagedCandidates = 1000
+
+// searchCursor marks where the previous page of a ranked search ended: the
+// last hit's score and memory, and how many hits have been returned so far.
+// Resuming from the hit itself rather than its position keeps pages from
+// repeating when new memories outrank it between requests.
+type searchCursor struct {
+	Score    float64
+	MemoryID int64
+	Seen     int
+}
+
+func (c searchCursor) encode() string {
+	raw := fmt.Sprintf("s1|%s|%d|%d", strconv.FormatFloat(c.Score, 'g', -1, 64), c.MemoryID, c.Seen)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSearchCursor(cursor string) (searchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(cursor))
+	if err != nil {
+		return searchCursor{}, fmt.Errorf("invalid search cursor")
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 4 || parts[0] != "s1" {
+		return searchCursor{}, fmt.Errorf("invalid search cursor")
+	}
+	score, err1 := strconv.ParseFloat(parts[1], 64)
+	memoryID, err2 := strconv.ParseInt(parts[2], 10, 64)
+	seen, err3 := strconv.Atoi(parts[3])
+	if err1 != nil || err2 != nil || err3 != nil || seen < 0 {
+		return searchCursor{}, fmt.Errorf("invalid search cursor")
+	}
+	return searchCursor{Score: score, MemoryID: memoryID, Seen: seen}, nil
+}
+
+// NextCursor returns the cursor for the page after results, which must be
+// the page Search returned for these options. It returns "" when the page
+// was not full, meaning there is nothing more to fetch.
+func (o Options) NextCursor(results []Result) string {
+	limit := o.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	ranked := withoutDirectives(results)
+	if len(ranked) < limit {
+		return ""
+	}
+	seen := o.Offset
+	if o.Cursor != "" {
+		if c, err := decodeSearchCursor(o.Cursor); err == nil {
+			seen = c.Seen
+		}
+	}
+	last := ranked[len(ranked)-1]
+	return searchCursor{Score: last.Score, MemoryID: last.MemoryID, Seen: seen + len(ranked)}.encode()
+}
+
+// searchPage serves Options.Offset and Options.Cursor: it ranks enough
+// candidates to cover the requested page and slices it out. Pinned
+// directives appear on the first page only.
+func (e *Engine) searchPage(ctx context.Context, query string, opts Options) ([]Result, error) {
+	pageSize := opts.Limit
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if opts.Offset < 0 {
+		return nil, fmt.Errorf("offset must be >= 0")
+	}
+
+	skip := opts.Offset
+	var cursor *searchCursor
+	if opts.Cursor != "" {
+		c, err := decodeSearchCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &c
+		skip = c.Seen
+	}
+
+	inner := opts
+	inner.Offset = 0
+	inner.Cursor = ""
+	inner.Limit = skip + pageSize
+	if cursor != nil {
+		// Slack for hits that moved above the cursor since the last page.
+		inner.Limit += pageSize
+	}
+	if inner.Limit > maxPagedCandidates {
+		inner.Limit = maxPagedCandidates
+	}
+
+	results, err := e.Search(ctx, query, inner)
+	if err != nil {
+		return nil, err
+	}
+	ranked := withoutDirectives(results)
+
+	start := skip
+	if cursor != nil {
+		start = cursorStart(ranked, *cursor)
+	}
+	if start > len(ranked) {
+		start = len(ranked)
+	}
+	end := start + pageSize
+	if end > len(ranked) {
+		end = len(ranked)
+	}
+	return ranked[start:end], nil
+}
+
+// cursorStart finds where the next page begins: just after the cursor's hit
+// if it is still in the list, otherwise at the first hit ranked below it.
+func cursorStart(ranked []Result, c searchCursor) int {
+	if c.MemoryID > 0 {
+		for i, r := range ranked {
+			if r.MemoryID == c.MemoryID {
+				return i + 1
+			}
+		}
+	}
+	for i, r := range ranked {
+		if r.Score < c.Score {
+			return i
+		}
+	}
+	return len(ranked)
+}
+
+func withoutDirectives(results []Result) []Result {
+	out := results[:0:0]
+	for _, r := range results {
+		if r.Kind != "directive" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// CountMatches returns how many hits the query has under opts, ignoring
+// Limit, Offset, and Cursor. Ranking is needed to apply score thresholds
+// and dedupe, so at most maxPagedCandidates hits are ranked; estimated is
+// true when that cap was reached and the real total may be higher.
+func (e *Engine) CountMatches(ctx context.Context, query string, opts Options) (total int, estimated bool, err error) {
+	opts.Offset = 0
+	opts.Cursor = ""
+	opts.Limit = maxPagedCandidates
+	results, err := e.Search(ctx, query, opts)
+	if err != nil {
+		return 0, false, err
+	}
+	total = len(withoutDirectives(results))
+	return total, total >= maxPagedCandidates, nil
+}
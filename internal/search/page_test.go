@@ -0,0 +1,97 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestSearch_CursorPagesMatchSingleQuery(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	for i := 0; i < 7; i++ {
+		content := fmt.Sprintf("rollback note %d: revert deploy step %d of the release", i, i)
+		if _, err := s.AddMemory(ctx, &store.Memory{Content: content, SourceFile: fmt.Sprintf("runbook-%d.md", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	engine := NewEngine(s)
+	base := Options{Mode: ModeKeyword, MinScore: -1, DisableDedupe: true}
+
+	all := base
+	all.Limit = 20
+	full, err := engine.Search(ctx, "rollback", all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(full) != 7 {
+		t.Fatalf("expected 7 hits, got %d", len(full))
+	}
+
+	var paged []int64
+	opts := base
+	opts.Limit = 3
+	for page := 0; page < 5; page++ {
+		results, err := engine.Search(ctx, "rollback", opts)
+		if err != nil {
+			t.Fatalf("page %d: %v", page, err)
+		}
+		for _, r := range results {
+			paged = append(paged, r.MemoryID)
+		}
+		next := opts.NextCursor(results)
+		if next == "" {
+			break
+		}
+		opts.Cursor = next
+	}
+	if len(paged) != len(full) {
+		t.Fatalf("paged %v, want %d hits", paged, len(full))
+	}
+	for i := range full {
+		if paged[i] != full[i].MemoryID {
+			t.Fatalf("page order %v differs from single query at %d", paged, i)
+		}
+	}
+
+	offset := base
+	offset.Limit = 2
+	offset.Offset = 4
+	results, err := engine.Search(ctx, "rollback", offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].MemoryID != full[4].MemoryID {
+		t.Fatalf("unexpected offset page: %+v", results)
+	}
+
+	bad := base
+	bad.Cursor = "garbage"
+	if _, err := engine.Search(ctx, "rollback", bad); err == nil {
+		t.Fatal("expected invalid cursor error")
+	}
+}
+
+func TestCountMatches_IgnoresPaging(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		if _, err := s.AddMemory(ctx, &store.Memory{Content: fmt.Sprintf("canary deploy %d notes", i), SourceFile: "deploys.md"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.AddMemory(ctx, &store.Memory{Content: "unrelated grocery list", SourceFile: "misc.md"}); err != nil {
+		t.Fatal(err)
+	}
+	engine := NewEngine(s)
+
+	total, estimated, err := engine.CountMatches(ctx, "canary", Options{Mode: ModeKeyword, Limit: 1, Offset: 2, MinScore: -1, DisableDedupe: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 4 || estimated {
+		t.Fatalf("CountMatches = %d (estimated=%v), want 4 exact", total, estimated)
+	}
+}
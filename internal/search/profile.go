@@ -0,0 +1,76 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// LookupProfile resolves a profile name from `cortex search --profile` or
+// the MCP profile parameter. Saved profiles in config come first; otherwise
+// a built-in ranking profile name such as "agent-context" selects that
+// ranking and nothing else.
+func LookupProfile(profiles cfgresolver.SearchProfiles, name string) (cfgresolver.SearchProfile, error) {
+	profile, err := profiles.Lookup(name)
+	if err == nil {
+		return profile, nil
+	}
+	if IsRankingProfile(name) {
+		return cfgresolver.SearchProfile{Ranking: strings.ToLower(strings.TrimSpace(name))}, nil
+	}
+	return cfgresolver.SearchProfile{}, fmt.Errorf("%w; built-in ranking profiles: %s", err, strings.Join(RankingProfileNames(), ", "))
+}
+
+// ApplyProfile fills options the caller left unset from a saved search
+// profile. Explicit values always win: Project, Source, and Classes are only
+// taken when empty, Intent when empty or "all", Mode and Profile when empty,
+// and MinScore when negative (mode default). Profile source boosts are added
+// to any already present.
+func ApplyProfile(opts *Options, profile cfgresolver.SearchProfile) error {
+	if opts.Mode == "" && strings.TrimSpace(profile.Mode) != "" {
+		mode, err := ParseMode(profile.Mode)
+		if err != nil {
+			return fmt.Errorf("profile mode: %w", err)
+		}
+		opts.Mode = mode
+	}
+	if opts.Profile == "" && strings.TrimSpace(profile.Ranking) != "" {
+		ranking, err := ParseRankingProfile(profile.Ranking)
+		if err != nil {
+			return fmt.Errorf("profile ranking: %w", err)
+		}
+		opts.Profile = ranking
+	}
+	if opts.Project == "" {
+		opts.Project = strings.TrimSpace(profile.Project)
+	}
+	if len(opts.Classes) == 0 && len(profile.Classes) > 0 {
+		classes, err := store.ParseMemoryClassList(strings.Join(profile.Classes, ","))
+		if err != nil {
+			return fmt.Errorf("profile classes: %w", err)
+		}
+		opts.Classes = classes
+	}
+	if opts.Source == "" {
+		opts.Source = strings.TrimSpace(profile.Source)
+	}
+	if (opts.Intent == "" || opts.Intent == IntentAll) && strings.TrimSpace(profile.Intent) != "" {
+		intent, err := normalizeIntent(profile.Intent)
+		if err != nil {
+			return fmt.Errorf("profile intent: %w", err)
+		}
+		opts.Intent = intent
+	}
+	if opts.MinScore < 0 && profile.MinScore > 0 {
+		opts.MinScore = profile.MinScore
+	}
+	for _, boost := range profile.SourceBoosts {
+		if strings.TrimSpace(boost.Prefix) == "" || boost.Weight == 0 {
+			continue
+		}
+		opts.SourceBoosts = append(opts.SourceBoosts, SourceBoost{Prefix: boost.Prefix, Weight: boost.Weight})
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package search
+
+import (
+	"testing"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+)
+
+func TestApplyProfile_FillsUnsetOptions(t *testing.T) {
+	profile := cfgresolver.SearchProfile{
+		Project:  "trading",
+		Classes:  []string{"rule", "decision"},
+		Source:   "github",
+		Intent:   "connector",
+		Mode:     "hybrid",
+		MinScore: 0.2,
+		SourceBoosts: []cfgresolver.SearchSourceBoostConfig{
+			{Prefix: "memory/", Weight: 1.4},
+			{Prefix: "", Weight: 2},
+		},
+	}
+	opts := Options{MinScore: -1, Intent: IntentAll, SourceBoosts: []SourceBoost{{Prefix: "MEMORY.md", Weight: 1.5}}}
+	if err := ApplyProfile(&opts, profile); err != nil {
+		t.Fatalf("ApplyProfile: %v", err)
+	}
+	if opts.Project != "trading" || opts.Source != "github" || opts.Intent != IntentConnector || opts.Mode != ModeHybrid {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+	if len(opts.Classes) != 2 || opts.Classes[0] != "rule" {
+		t.Fatalf("unexpected classes: %v", opts.Classes)
+	}
+	if opts.MinScore != 0.2 {
+		t.Fatalf("expected profile min score, got %v", opts.MinScore)
+	}
+	if len(opts.SourceBoosts) != 2 || opts.SourceBoosts[1].Prefix != "memory/" {
+		t.Fatalf("expected profile boost appended, got %+v", opts.SourceBoosts)
+	}
+}
+
+func TestApplyProfile_ExplicitOptionsWin(t *testing.T) {
+	profile := cfgresolver.SearchProfile{Project: "trading", Classes: []string{"rule"}, Source: "github", Intent: "connector", Mode: "semantic", MinScore: 0.4}
+	opts := Options{Mode: ModeKeyword, Project: "ops", Classes: []string{"status"}, Source: "gmail", Intent: IntentMemory, MinScore: 0.1}
+	if err := ApplyProfile(&opts, profile); err != nil {
+		t.Fatalf("ApplyProfile: %v", err)
+	}
+	if opts.Mode != ModeKeyword || opts.Project != "ops" || opts.Classes[0] != "status" || opts.Source != "gmail" || opts.Intent != IntentMemory || opts.MinScore != 0.1 {
+		t.Fatalf("explicit options overwritten: %+v", opts)
+	}
+}
+
+func TestApplyProfile_RejectsInvalidValues(t *testing.T) {
+	for _, profile := range []cfgresolver.SearchProfile{
+		{Mode: "fuzzy"},
+		{Classes: []string{"nonsense"}},
+		{Intent: "everything"},
+	} {
+		opts := Options{MinScore: -1}
+		if err := ApplyProfile(&opts, profile); err == nil {
+			t.Fatalf("expected error for %+v", profile)
+		}
+	}
+}
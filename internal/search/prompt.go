@@ -0,0 +1,172 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/tokens"
+)
+
+const (
+	defaultPromptMaxTokens = 2000
+	defaultPromptLimit     = 20
+	// minPromptClipTokens is the smallest remaining budget worth clipping a
+	// passage into; below it a clipped passage carries too little to cite.
+	minPromptClipTokens = 32
+)
+
+// TokenBudget bounds what RetrieveForPrompt packs into a prompt.
+type TokenBudget struct {
+	MaxTokens   int     // Total tokens for the rendered passages (default 2000)
+	MaxPassages int     // Optional cap on passages (0 = no cap)
+	Search      Options // Search options; Limit defaults to 20 candidates
+}
+
+// PromptContext is a token-counted, citation-marked set of passages ready to
+// paste into an LLM prompt.
+type PromptContext struct {
+	Query      string          `json:"query"`
+	Passages   []PromptPassage `json:"passages"`
+	Text       string          `json:"text"`
+	TokensUsed int             `json:"tokens_used"`
+	Budget     int             `json:"budget"`
+	Omitted    int             `json:"omitted"` // Candidates left out for budget or as duplicates
+}
+
+// PromptPassage is one cited passage. Marker ("[1]") is what the rendered
+// text uses, so answers can cite it back to MemoryID and Source.
+type PromptPassage struct {
+	Index      int       `json:"index"`
+	Marker     string    `json:"marker"`
+	MemoryID   int64     `json:"memory_id"`
+	Kind       string    `json:"kind,omitempty"`
+	Source     string    `json:"source"`
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+	Score      float64   `json:"score"`
+	Tokens     int       `json:"tokens"`
+	Clipped    bool      `json:"clipped,omitempty"`
+	Project    string    `json:"project,omitempty"`
+	Class      string    `json:"class,omitempty"`
+	ImportedAt time.Time `json:"imported_at,omitempty"`
+}
+
+// RetrieveForPrompt searches for query and packs the ranked hits into budget:
+// duplicates are dropped, passages keep rank order, and each is numbered with
+// a citation marker. A hit that does not fit is skipped so smaller, lower-ranked
+// ones can still use the remaining budget; the first hit is clipped rather than
+// dropped so a small budget never comes back empty.
+func (e *Engine) RetrieveForPrompt(ctx context.Context, query string, budget TokenBudget) (*PromptContext, error) {
+	maxTokens := budget.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultPromptMaxTokens
+	}
+	opts := budget.Search
+	if opts.Limit <= 0 {
+		opts.Limit = defaultPromptLimit
+	}
+	if opts.Mode == "" {
+		opts.Mode = ModeKeyword
+	}
+
+	results, err := e.Search(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := &PromptContext{Query: strings.TrimSpace(query), Passages: []PromptPassage{}, Budget: maxTokens}
+	seenMemory := make(map[int64]bool, len(results))
+	seenContent := make(map[string]bool, len(results))
+	var text strings.Builder
+	for _, r := range results {
+		content := strings.TrimSpace(r.Content)
+		contentKey := strings.Join(strings.Fields(strings.ToLower(content)), " ")
+		if content == "" || (r.MemoryID > 0 && seenMemory[r.MemoryID]) || seenContent[contentKey] {
+			pc.Omitted++
+			continue
+		}
+		if budget.MaxPassages > 0 && len(pc.Passages) >= budget.MaxPassages {
+			pc.Omitted++
+			continue
+		}
+
+		p := PromptPassage{
+			Index:      len(pc.Passages) + 1,
+			MemoryID:   r.MemoryID,
+			Kind:       r.Kind,
+			Source:     promptSourceLocator(r),
+			Content:    content,
+			Score:      r.Score,
+			Project:    r.Project,
+			Class:      r.MemoryClass,
+			ImportedAt: r.ImportedAt,
+		}
+		p.Marker = fmt.Sprintf("[%d]", p.Index)
+		p.Title = CitationTitleForResult(r)
+		if p.Title == "" {
+			p.Title = p.Source
+		}
+
+		block := renderPromptPassage(p)
+		p.Tokens = estimatePromptTokens(block)
+		remaining := maxTokens - pc.TokensUsed
+		if p.Tokens > remaining {
+			if len(pc.Passages) > 0 || remaining < minPromptClipTokens {
+				pc.Omitted++
+				continue
+			}
+			p.Content, p.Clipped = clipPromptContent(p, remaining)
+			block = renderPromptPassage(p)
+			p.Tokens = estimatePromptTokens(block)
+		}
+
+		if r.MemoryID > 0 {
+			seenMemory[r.MemoryID] = true
+		}
+		seenContent[contentKey] = true
+		text.WriteString(block)
+		pc.TokensUsed += p.Tokens
+		pc.Passages = append(pc.Passages, p)
+	}
+	pc.Text = strings.TrimRight(text.String(), "\n")
+	return pc, nil
+}
+
+// renderPromptPassage formats a passage as "[n] Title (source)" followed by
+// its content and a blank line.
+func renderPromptPassage(p PromptPassage) string {
+	header := p.Marker + " " + p.Title
+	if p.Source != p.Title {
+		header += " (" + p.Source + ")"
+	}
+	return header + "\n" + p.Content + "\n\n"
+}
+
+// clipPromptContent trims a passage's content on a rune boundary so the
+// rendered block fits in budget tokens.
+func clipPromptContent(p PromptPassage, budget int) (string, bool) {
+	overhead := estimatePromptTokens(renderPromptPassage(PromptPassage{Marker: p.Marker, Title: p.Title, Source: p.Source, Content: "..."}))
+	// One token of slack: the cut point can merge with "..." differently.
+	clipped, cut := tokens.Default.Truncate(p.Content, budget-overhead-1)
+	if !cut || clipped == "" {
+		return p.Content, false
+	}
+	return strings.TrimSpace(clipped) + "...", true
+}
+
+func promptSourceLocator(r Result) string {
+	source := r.SourceFile
+	if source == "" {
+		source = "(unknown source)"
+	}
+	if r.SourceLine > 0 {
+		source = fmt.Sprintf("%s:%d", source, r.SourceLine)
+	}
+	return source
+}
+
+func estimatePromptTokens(s string) int {
+	return tokens.Count(s)
+}
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRetrieveForPrompt_DedupesAndMarksCitations(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	for _, m := range []*store.Memory{
+		{Content: "Rollback runbook: revert the deploy with make rollback", SourceFile: "ops/runbook.md", SourceLine: 12, SourceSection: "Rollback"},
+		{Content: "rollback runbook:  revert the deploy with make ROLLBACK", SourceFile: "notes/copy.md"},
+		{Content: "Rollback drills run every quarter", SourceFile: "notes/drills.md"},
+	} {
+		if _, err := s.AddMemory(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	engine := NewEngine(s)
+
+	pc, err := engine.RetrieveForPrompt(ctx, "rollback", TokenBudget{MaxTokens: 500, Search: Options{MinScore: -1, DisableDedupe: true}})
+	if err != nil {
+		t.Fatalf("RetrieveForPrompt: %v", err)
+	}
+	if len(pc.Passages) != 2 || pc.Omitted != 1 {
+		t.Fatalf("expected 2 passages and 1 duplicate omitted, got %d/%d: %+v", len(pc.Passages), pc.Omitted, pc.Passages)
+	}
+	total := 0
+	for i, p := range pc.Passages {
+		if p.Index != i+1 || p.Marker != "["+string(rune('1'+i))+"]" {
+			t.Errorf("passage %d has index %d marker %q", i, p.Index, p.Marker)
+		}
+		if !strings.Contains(pc.Text, p.Marker+" "+p.Title) {
+			t.Errorf("text missing header for %s:\n%s", p.Marker, pc.Text)
+		}
+		if i > 0 && p.Score > pc.Passages[i-1].Score {
+			t.Errorf("passages out of rank order: %+v", pc.Passages)
+		}
+		total += p.Tokens
+	}
+	if pc.TokensUsed != total || pc.TokensUsed > pc.Budget || pc.Budget != 500 {
+		t.Errorf("token accounting: used=%d sum=%d budget=%d", pc.TokensUsed, total, pc.Budget)
+	}
+	for _, p := range pc.Passages {
+		if strings.HasPrefix(p.Source, "ops/runbook.md") && (p.Source != "ops/runbook.md:12" || p.Title != "Rollback") {
+			t.Errorf("unexpected citation for runbook: %+v", p)
+		}
+	}
+}
+
+func TestRetrieveForPrompt_ClipsFirstPassageToBudget(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	long := "Incident timeline " + strings.Repeat("the primary database failed over to the replica ", 40)
+	if _, err := s.AddMemory(ctx, &store.Memory{Content: long, SourceFile: "incidents/db.md"}); err != nil {
+		t.Fatal(err)
+	}
+	engine := NewEngine(s)
+
+	pc, err := engine.RetrieveForPrompt(ctx, "incident", TokenBudget{MaxTokens: 60, Search: Options{MinScore: -1}})
+	if err != nil {
+		t.Fatalf("RetrieveForPrompt: %v", err)
+	}
+	if len(pc.Passages) != 1 {
+		t.Fatalf("expected 1 passage, got %+v", pc.Passages)
+	}
+	p := pc.Passages[0]
+	if p.Tokens > 60 || pc.TokensUsed > 60 {
+		t.Errorf("passage exceeds budget: %d tokens", p.Tokens)
+	}
+	if !p.Clipped || !strings.HasSuffix(p.Content, "...") || !strings.HasPrefix(p.Content, "Incident timeline") {
+		t.Errorf("long passage should be clipped: %+v", p)
+	}
+}
+
+func TestClipPromptContent_RuneBoundary(t *testing.T) {
+	p := PromptPassage{Marker: "[1]", Title: "t", Source: "s", Content: strings.Repeat("日本語", 100)}
+	got, clipped := clipPromptContent(p, 40)
+	if !clipped || !strings.HasSuffix(got, "...") {
+		t.Fatalf("expected clip, got %q", got)
+	}
+	if !utf8.ValidString(got) {
+		t.Fatalf("clip split a rune: %q", got)
+	}
+}
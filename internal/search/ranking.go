@@ -0,0 +1,125 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// Ranking profiles, selected with Options.Profile. The default profile is
+// tuned for a person exploring the store. agent-context is tuned for the
+// handful of results an agent injects into its prompt: rules and decisions
+// first, status notes only while fresh, transcripts last.
+const (
+	RankingProfileDefault      = "default"
+	RankingProfileAgentContext = "agent-context"
+)
+
+// rankingProfile is the set of weights one ranking profile applies.
+type rankingProfile struct {
+	classBoosts map[string]float64
+	// freshness replaces the tiered recency boost when Options.Freshness is
+	// nil. A configured search.freshness always wins.
+	freshness        *Freshness
+	confidenceWeight float64
+	// transcriptPenalty multiplies auto-capture and transcript-like results
+	// (1 = no penalty).
+	transcriptPenalty float64
+}
+
+var rankingProfiles = map[string]rankingProfile{
+	RankingProfileDefault: {
+		classBoosts:       classBoostMultipliers,
+		confidenceWeight:  ConfidenceWeight,
+		transcriptPenalty: 1.0,
+	},
+	RankingProfileAgentContext: {
+		classBoosts: map[string]float64{
+			store.MemoryClassRule:       1.80,
+			store.MemoryClassDecision:   1.50,
+			store.MemoryClassPreference: 1.20,
+			store.MemoryClassIdentity:   1.20,
+			store.MemoryClassStatus:     1.15,
+			store.MemoryClassScratch:    0.60,
+		},
+		freshness: &Freshness{
+			HalfLife: map[string]time.Duration{
+				"":                          90 * 24 * time.Hour,
+				store.MemoryClassStatus:     3 * 24 * time.Hour,
+				store.MemoryClassScratch:    24 * time.Hour,
+				store.MemoryClassPreference: 0,
+				store.MemoryClassRule:       0,
+				store.MemoryClassDecision:   0,
+				store.MemoryClassIdentity:   0,
+			},
+			Floor: 0.25,
+		},
+		// Weigh effective confidence more, so well-supported decisions
+		// outrank ones resting on fading facts.
+		confidenceWeight:  0.5,
+		transcriptPenalty: 0.5,
+	},
+}
+
+// ParseRankingProfile normalizes a ranking profile name. Empty selects the
+// default profile.
+func ParseRankingProfile(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return RankingProfileDefault, nil
+	}
+	if _, ok := rankingProfiles[name]; !ok {
+		return "", fmt.Errorf("unknown ranking profile %q (valid: %s)", name, strings.Join(RankingProfileNames(), ", "))
+	}
+	return name, nil
+}
+
+// IsRankingProfile reports whether name is a built-in ranking profile.
+func IsRankingProfile(name string) bool {
+	_, ok := rankingProfiles[strings.ToLower(strings.TrimSpace(name))]
+	return ok
+}
+
+// RankingProfileNames returns the built-in ranking profiles in sorted order.
+func RankingProfileNames() []string {
+	names := make([]string, 0, len(rankingProfiles))
+	for name := range rankingProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var transcriptRoleLineRE = regexp.MustCompile(`(?im)^\s*(assistant|user|system)\s*:`)
+
+// isTranscriptResult reports whether a result is conversation transcript:
+// an auto-capture memory, or content with at least two role-prefixed lines.
+func isTranscriptResult(r Result) bool {
+	if isAutoCaptureSourceFile(r.SourceFile) {
+		return true
+	}
+	return len(transcriptRoleLineRE.FindAllStringIndex(r.Content, 2)) >= 2
+}
+
+// applyTranscriptPenalty scales transcript results by penalty.
+func applyTranscriptPenalty(results []Result, penalty float64, explain bool) []Result {
+	for i := range results {
+		if !isTranscriptResult(results[i]) {
+			continue
+		}
+		results[i].Score *= penalty
+		if explain {
+			ensureExplain(&results[i])
+			results[i].Explain.RankComponents.TranscriptPenalty = penalty
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
@@ -0,0 +1,95 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	cfgresolver "github.com/hurttlocker/cortex/internal/config"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestSearch_AgentContextProfile(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	for _, m := range []*store.Memory{
+		{Content: "user: how do we deploy the api?\nassistant: the api deploy runs through argo every friday", SourceFile: "chat.md"},
+		{Content: "always run the api deploy checklist before merging", SourceFile: "rules.md", MemoryClass: store.MemoryClassRule},
+		{Content: "scratch idea: api deploy could use canaries", SourceFile: "scratch.md", MemoryClass: store.MemoryClassScratch},
+	} {
+		if _, err := s.AddMemory(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	engine := NewEngine(s)
+
+	search := func(profile string) []Result {
+		t.Helper()
+		opts := DefaultOptions()
+		opts.Profile = profile
+		opts.Explain = true
+		results, err := engine.Search(ctx, "api deploy", opts)
+		if err != nil {
+			t.Fatalf("Search(%q): %v", profile, err)
+		}
+		if len(results) != 3 {
+			t.Fatalf("Search(%q) returned %d results", profile, len(results))
+		}
+		return results
+	}
+
+	for _, r := range search("") {
+		if rc := r.Explain.RankComponents; rc.RankingProfile != "" || rc.TranscriptPenalty != 0 || rc.ConfidenceWeight != ConfidenceWeight {
+			t.Fatalf("default profile applied agent-context weights to %s: %+v", r.SourceFile, rc)
+		}
+	}
+
+	got := search("Agent-Context")
+	if got[0].SourceFile != "rules.md" || got[2].SourceFile != "chat.md" {
+		t.Fatalf("agent-context order = %s, %s, %s", got[0].SourceFile, got[1].SourceFile, got[2].SourceFile)
+	}
+	for _, r := range got {
+		rc := r.Explain.RankComponents
+		if rc.RankingProfile != RankingProfileAgentContext || rc.ConfidenceWeight != 0.5 {
+			t.Fatalf("explain for %s = %+v", r.SourceFile, rc)
+		}
+		if (r.SourceFile == "chat.md") != (rc.TranscriptPenalty == 0.5) {
+			t.Fatalf("transcript penalty for %s = %v", r.SourceFile, rc.TranscriptPenalty)
+		}
+	}
+
+	if _, err := engine.Search(ctx, "api deploy", Options{Profile: "bogus"}); err == nil {
+		t.Fatal("expected error for unknown ranking profile")
+	}
+}
+
+func TestLookupProfile_FallsBackToRankingProfiles(t *testing.T) {
+	saved := cfgresolver.SearchProfiles{"ops": {Project: "ops", Ranking: "agent-context"}}
+
+	p, err := LookupProfile(saved, "agent-context")
+	if err != nil || p.Ranking != RankingProfileAgentContext || p.Project != "" {
+		t.Fatalf("built-in lookup = %+v, %v", p, err)
+	}
+
+	opts := DefaultOptions()
+	p, _ = LookupProfile(saved, "ops")
+	if err := ApplyProfile(&opts, p); err != nil || opts.Profile != RankingProfileAgentContext || opts.Project != "ops" {
+		t.Fatalf("saved profile applied as %+v, %v", opts, err)
+	}
+
+	opts = DefaultOptions()
+	opts.Profile = RankingProfileDefault
+	if err := ApplyProfile(&opts, p); err != nil || opts.Profile != RankingProfileDefault {
+		t.Fatalf("explicit ranking profile should win, got %q", opts.Profile)
+	}
+
+	if _, err := LookupProfile(saved, "nope"); err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+	if err := ApplyProfile(&opts, cfgresolver.SearchProfile{Ranking: "nope"}); err != nil {
+		t.Fatalf("ranking is ignored when Profile is set: %v", err)
+	}
+	opts.Profile = ""
+	if err := ApplyProfile(&opts, cfgresolver.SearchProfile{Ranking: "nope"}); err == nil {
+		t.Fatal("expected error for invalid ranking in saved profile")
+	}
+}
@@ -0,0 +1,133 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// modeRouteDecision records which concrete mode ModeAuto picked and why.
+type modeRouteDecision struct {
+	Selected Mode
+	Reason   string
+}
+
+// routeQueryMode picks keyword or hybrid retrieval from the shape of the
+// query. Quoted phrases, exact identifiers (IDs, hashes, file names,
+// snake_case/camelCase names, ticket keys), and one- or two-word queries go
+// to keyword search, where embeddings tend to blur the exact match. Longer
+// natural-language queries go to hybrid when an embedder is available.
+func routeQueryMode(query string, hasEmbedder bool) modeRouteDecision {
+	query = strings.TrimSpace(query)
+	if hasQuotedPhrase(query) {
+		return modeRouteDecision{Selected: ModeKeyword, Reason: "quoted phrase"}
+	}
+	words := strings.Fields(query)
+	for _, word := range words {
+		if token := strings.Trim(word, ",;:!?()[]{}'\"`"); looksLikeIdentifier(token) {
+			return modeRouteDecision{Selected: ModeKeyword, Reason: fmt.Sprintf("exact identifier %q", token)}
+		}
+	}
+	if len(words) <= 2 {
+		return modeRouteDecision{Selected: ModeKeyword, Reason: fmt.Sprintf("short query (%d %s)", len(words), pluralTerms(len(words)))}
+	}
+	if !hasEmbedder {
+		return modeRouteDecision{Selected: ModeKeyword, Reason: "no embedder configured"}
+	}
+	return modeRouteDecision{Selected: ModeHybrid, Reason: fmt.Sprintf("natural-language query (%d terms)", len(words))}
+}
+
+func hasQuotedPhrase(query string) bool {
+	if strings.Count(query, `"`) >= 2 {
+		return true
+	}
+	return strings.ContainsRune(query, '“') && strings.ContainsRune(query, '”')
+}
+
+// looksLikeIdentifier reports whether a token reads as an exact identifier
+// rather than a word: mixed letters and digits ("a1b2c3d", "PR-42", "v1.2"),
+// issue refs ("#123"), snake_case, camelCase, paths, dotted names, and
+// emails.
+func looksLikeIdentifier(token string) bool {
+	if len(token) < 2 {
+		return false
+	}
+	if token[0] == '#' && len(token) > 1 && isAllDigits(token[1:]) {
+		return true
+	}
+	if strings.ContainsAny(token, "_/@") || strings.Contains(token, "::") {
+		return hasLetterOrDigit(token)
+	}
+	var letters, digits int
+	camel := false
+	prevLower := false
+	for _, r := range token {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case unicode.IsLetter(r):
+			letters++
+			if prevLower && unicode.IsUpper(r) {
+				camel = true
+			}
+		}
+		prevLower = unicode.IsLower(r)
+	}
+	if letters > 0 && digits > 0 {
+		return true
+	}
+	if camel {
+		return true
+	}
+	// Dotted names such as config.yaml or internal.search, but not a
+	// trailing period.
+	if i := strings.Index(token, "."); i > 0 && i < len(token)-1 && letters > 0 {
+		return true
+	}
+	return false
+}
+
+func isAllDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasLetterOrDigit(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func pluralTerms(n int) string {
+	if n == 1 {
+		return "term"
+	}
+	return "terms"
+}
+
+func attachModeRouteExplain(results []Result, route modeRouteDecision) {
+	for i := range results {
+		ensureExplain(&results[i])
+		results[i].Explain.ModeRoute = &ExplainModeRoute{
+			Requested: ModeAuto,
+			Selected:  route.Selected,
+			Reason:    route.Reason,
+		}
+		msg := fmt.Sprintf("auto mode routed to %s (%s)", route.Selected, route.Reason)
+		if results[i].Explain.Why == "" {
+			results[i].Explain.Why = msg
+		} else {
+			results[i].Explain.Why += "; " + msg
+		}
+	}
+}
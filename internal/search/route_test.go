@@ -0,0 +1,76 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestRouteQueryMode(t *testing.T) {
+	tests := []struct {
+		query       string
+		hasEmbedder bool
+		want        Mode
+		reason      string
+	}{
+		{query: "a1b2c3d", hasEmbedder: true, want: ModeKeyword, reason: "exact identifier"},
+		{query: "what changed in PR-482 last week", hasEmbedder: true, want: ModeKeyword, reason: "exact identifier"},
+		{query: "where is max_retries configured", hasEmbedder: true, want: ModeKeyword, reason: "exact identifier"},
+		{query: "who owns config.yaml", hasEmbedder: true, want: ModeKeyword, reason: "exact identifier"},
+		{query: "status of #1234", hasEmbedder: true, want: ModeKeyword, reason: "exact identifier"},
+		{query: "why does searchHybrid fall back", hasEmbedder: true, want: ModeKeyword, reason: "exact identifier"},
+		{query: `notes about "wedding venue" decisions`, hasEmbedder: true, want: ModeKeyword, reason: "quoted phrase"},
+		{query: "positano", hasEmbedder: true, want: ModeKeyword, reason: "short query (1 term)"},
+		{query: "deploy checklist", hasEmbedder: true, want: ModeKeyword, reason: "short query (2 terms)"},
+		{query: "how do we handle deploy rollbacks", hasEmbedder: true, want: ModeHybrid, reason: "natural-language query"},
+		{query: "how do we handle deploy rollbacks?", hasEmbedder: false, want: ModeKeyword, reason: "no embedder"},
+		{query: "what did we decide about the venue.", hasEmbedder: true, want: ModeHybrid, reason: "natural-language query"},
+	}
+	for _, tc := range tests {
+		got := routeQueryMode(tc.query, tc.hasEmbedder)
+		if got.Selected != tc.want || !strings.Contains(got.Reason, tc.reason) {
+			t.Errorf("routeQueryMode(%q, %v) = %s (%s), want %s (%s)", tc.query, tc.hasEmbedder, got.Selected, got.Reason, tc.want, tc.reason)
+		}
+	}
+}
+
+func TestParseMode_Auto(t *testing.T) {
+	mode, err := ParseMode("AUTO")
+	if err != nil || mode != ModeAuto {
+		t.Fatalf("ParseMode(AUTO) = %q, %v", mode, err)
+	}
+}
+
+func TestSearch_AutoModeRecordsRouteInExplain(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	if _, err := s.AddMemory(ctx, &store.Memory{Content: "Rollback runbook: revert the deploy with make rollback", SourceFile: "ops.md"}); err != nil {
+		t.Fatal(err)
+	}
+	engine := NewEngineWithEmbedder(s, newMockEmbedder())
+
+	results, err := engine.Search(ctx, "rollback", Options{Mode: ModeAuto, Limit: 5, MinScore: -1, Explain: true})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("expected a keyword hit")
+	}
+	route := results[0].Explain.ModeRoute
+	if route == nil || route.Requested != ModeAuto || route.Selected != ModeKeyword || !strings.Contains(route.Reason, "short query") {
+		t.Fatalf("unexpected mode route: %+v", route)
+	}
+	if !strings.Contains(results[0].Explain.Why, "auto mode routed to keyword") {
+		t.Fatalf("expected routing in why, got %q", results[0].Explain.Why)
+	}
+
+	results, err = engine.Search(ctx, "rollback", Options{Mode: ModeKeyword, Limit: 5, MinScore: -1, Explain: true})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) == 0 || results[0].Explain.ModeRoute != nil {
+		t.Fatal("expected no mode route for an explicit mode")
+	}
+}
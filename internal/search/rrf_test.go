@@ -172,7 +172,7 @@ func TestRRFConfidenceDecay(t *testing.T) {
 
 	before := scoresByID(fused)
 	engine := NewEngine(s)
-	after, _ := engine.applyConfidenceDecay(ctx, fused, false, false)
+	after, _ := engine.applyConfidenceDecay(ctx, fused, ConfidenceWeight, false, false)
 	afterScores := scoresByID(after)
 
 	if afterScores[id2] >= before[id2] {
@@ -17,6 +17,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
@@ -159,6 +160,8 @@ const (
 	ModeSemantic Mode = "semantic"
 	ModeHybrid   Mode = "hybrid"
 	ModeRRF      Mode = "rrf"
+	// ModeAuto picks keyword or hybrid per query; see routeQueryMode.
+	ModeAuto Mode = "auto"
 )
 
 // ParseMode converts a string to a Mode, returning an error for invalid values.
@@ -172,8 +175,10 @@ func ParseMode(s string) (Mode, error) {
 		return ModeHybrid, nil
 	case "rrf":
 		return ModeRRF, nil
+	case "auto":
+		return ModeAuto, nil
 	default:
-		return "", fmt.Errorf("invalid search mode %q (valid: keyword, semantic, hybrid, rrf)", s)
+		return "", fmt.Errorf("invalid search mode %q (valid: auto, keyword, semantic, hybrid, rrf)", s)
 	}
 }
 
@@ -224,10 +229,12 @@ func (s ScopeFilters) Empty() bool {
 // Options configures a search query.
 type Options struct {
 	Mode              Mode     // Search mode (default: keyword)
+	Profile           string   // Ranking profile: "default" (human exploration) or "agent-context"
 	Limit             int      // Max results (default: 10)
 	MinScore          float64  // Minimum search score threshold (default: mode-dependent, -1 = use default)
 	EntityGraph       bool     // Enable entity-profile/entity-graph retrieval augmentations
 	Project           string   // Scope search to a specific project (empty = all)
+	Namespace         string   // Scope search to a namespace (empty = the store's; "*" = all)
 	Classes           []string // Filter by memory class (rule, decision, preference, identity, status, scratch)
 	DisableClassBoost bool     // Disable class-aware weighting (default: false)
 	Agent             string   // Filter by metadata agent_id (Issue #30)
@@ -245,11 +252,24 @@ type Options struct {
 	Intent            string        // Convenience source bucket: memory|import|connector|all
 	Scope             ScopeFilters  // Directional fact scope filters (Issue #252)
 	SourceBoosts      []SourceBoost // Optional score boosts by source prefix
+	Freshness         *Freshness    // Per-class recency decay; nil keeps the tiered recency boost
+	GraphBoost        float64       // Weight of the boost for hits linked by fact edges to the top hits (0 = off, max 1)
 	IncludeSuperseded bool          // Include memories backed only by superseded facts
+	ExpandSummaries   bool          // Fact search: list the originals behind cluster-summary hits
 	Explain           bool          // Attach explainability/provenance payloads to results
 	DisableDedupe     bool          // Keep overlapping same-source results instead of collapsing them
+	Highlight         *Highlight    // Replace snippets with highlighted FTS5 snippets; nil keeps BM25's default
 	TemporalQuery     *temporal.Query
 	RerankMode        rerank.Mode
+	Trace             *Trace // Records per-stage scores for every candidate, including filtered ones
+	// ExcludeProjects drops results from these projects, e.g. the projects
+	// whose privacy zone keeps them out of MCP.
+	ExcludeProjects []string
+
+	// Paging: Offset skips ranked hits; Cursor (from NextCursor) resumes after
+	// the last hit of the previous page and takes precedence over Offset.
+	Offset int
+	Cursor string
 }
 
 // Default minimum score thresholds by mode.
@@ -308,6 +328,19 @@ type Result struct {
 	Truncated      bool            `json:"truncated,omitempty"`
 	RerankScore    *float64        `json:"rerank_score,omitempty"`
 	Explain        *ExplainDetails `json:"explain,omitempty"`
+
+	// Anchor is the chunk's byte range and heading path in SourceFile, for
+	// deep links and exact quotes. Nil for memories imported without one.
+	Anchor *store.ChunkAnchor `json:"anchor,omitempty"`
+
+	// MatchedTranslation is the language of the stored translation that
+	// matched the query, when the hit was folded onto its original.
+	MatchedTranslation string `json:"matched_translation,omitempty"`
+
+	// Workspace and AlsoIn label federated results: the workspace whose
+	// hit ranked best, and any others holding the same memory.
+	Workspace string   `json:"workspace,omitempty"`
+	AlsoIn    []string `json:"also_in,omitempty"`
 }
 
 // FactResult is a direct fact-level search hit.
@@ -326,6 +359,23 @@ type FactResult struct {
 	SourceTier    string  `json:"source_tier,omitempty"`
 	Score         float64 `json:"score"`
 	MatchType     string  `json:"match_type,omitempty"`
+
+	// Summarizes lists the original facts a cluster-summary hit replaced,
+	// when Options.ExpandSummaries is set.
+	Summarizes []SummarizedFact `json:"summarizes,omitempty"`
+
+	// MatchedTranslation is the language of the stored translation that
+	// matched the query, when the hit was folded onto its original.
+	MatchedTranslation string `json:"matched_translation,omitempty"`
+}
+
+// SummarizedFact is an original fact behind a cluster summary.
+type SummarizedFact struct {
+	FactID     int64   `json:"fact_id"`
+	Subject    string  `json:"subject"`
+	Predicate  string  `json:"predicate"`
+	Object     string  `json:"object"`
+	Confidence float64 `json:"confidence"`
 }
 
 // ExplainDetails surfaces provenance and ranking factors for operator trust/debugging.
@@ -335,7 +385,9 @@ type ExplainDetails struct {
 	RankComponents RankComponents        `json:"rank_components"`
 	QueryShape     *ExplainQueryShape    `json:"query_shape,omitempty"`
 	QueryStrategy  *ExplainQueryStrategy `json:"query_strategy,omitempty"`
+	ModeRoute      *ExplainModeRoute     `json:"mode_route,omitempty"`
 	Why            string                `json:"why,omitempty"`
+	Notes          []store.Annotation    `json:"notes,omitempty"`
 }
 
 // ExplainQueryShape captures raw-vs-shaped retrieval query context when query shaping is applied.
@@ -358,6 +410,13 @@ type ExplainQueryStrategy struct {
 	Reason         string        `json:"reason,omitempty"`
 }
 
+// ExplainModeRoute captures which mode auto routing picked for the query.
+type ExplainModeRoute struct {
+	Requested Mode   `json:"requested"`
+	Selected  Mode   `json:"selected"`
+	Reason    string `json:"reason"`
+}
+
 type ExplainProvenance struct {
 	Source    string    `json:"source"`
 	Timestamp time.Time `json:"timestamp,omitempty"`
@@ -393,10 +452,22 @@ type RankComponents struct {
 	SessionKeyBoost float64 `json:"session_key_boost,omitempty"`
 	RecencyBoost    float64 `json:"recency_boost,omitempty"`
 
+	// Memory freshness decay (search.freshness)
+	FreshnessMultiplier   float64 `json:"freshness_multiplier,omitempty"`
+	FreshnessHalfLifeDays float64 `json:"freshness_half_life_days,omitempty"`
+
 	// Source weighting (Issue #199)
 	SourceWeight          float64 `json:"source_weight,omitempty"`
 	SourceBoostMultiplier float64 `json:"source_boost_multiplier,omitempty"`
 	SourceBoostPrefix     string  `json:"source_boost_prefix,omitempty"`
+
+	// Graph proximity boost (search.graph_boost)
+	GraphBoostMultiplier float64 `json:"graph_boost_multiplier,omitempty"`
+	GraphBoostSeedMemory int64   `json:"graph_boost_seed_memory,omitempty"`
+
+	// Ranking profile (Options.Profile); empty for the default profile
+	RankingProfile    string  `json:"ranking_profile,omitempty"`
+	TranscriptPenalty float64 `json:"transcript_penalty,omitempty"`
 }
 
 type confidenceDetail struct {
@@ -423,6 +494,11 @@ type Engine struct {
 	embedder embed.Embedder // nil = BM25 only
 	hnsw     *ann.Index     // nil = brute-force semantic search
 	reranker *rerank.Service
+
+	hnswParams ann.Params // zero fields = ann defaults
+
+	hnswMu   sync.RWMutex // guards hnsw and warm once background warm-up is in play
+	hnswWarm HNSWStatus
 }
 
 // NewEngine creates a search engine backed by the given store.
@@ -442,7 +518,54 @@ func (e *Engine) SetReranker(service *rerank.Service) {
 // SetHNSW attaches an HNSW index for fast approximate nearest neighbor search.
 // When set, semantic search uses HNSW instead of brute-force O(N) scan.
 func (e *Engine) SetHNSW(idx *ann.Index) {
+	e.hnswMu.Lock()
 	e.hnsw = idx
+	e.hnswMu.Unlock()
+}
+
+// index returns the attached HNSW index, or nil.
+func (e *Engine) index() *ann.Index {
+	e.hnswMu.RLock()
+	defer e.hnswMu.RUnlock()
+	return e.hnsw
+}
+
+// SetHNSWParams configures the parameters used when building HNSW indexes.
+// Zero fields fall back to the ann package defaults. A non-zero EfSearch is
+// also applied to an already-attached index, since it is a query-time knob.
+func (e *Engine) SetHNSWParams(p ann.Params) {
+	e.hnswParams = p
+	if idx := e.index(); idx != nil && p.EfSearch > 0 {
+		idx.SetEfSearch(p.EfSearch)
+	}
+}
+
+// HNSWParams returns the parameters of the attached HNSW index, or the
+// configured build parameters when no index is attached.
+func (e *Engine) HNSWParams() ann.Params {
+	if idx := e.index(); idx != nil {
+		return idx.Params()
+	}
+	return e.hnswParams.WithDefaults()
+}
+
+// HNSWQuantization reports the vector representation of the attached HNSW
+// index (or of the store when no index is attached).
+func (e *Engine) HNSWQuantization() ann.Quantization {
+	if idx := e.index(); idx != nil {
+		return idx.Quantization()
+	}
+	return e.storeQuantization(context.Background())
+}
+
+// EstimateHNSWRecall measures recall@k of the attached HNSW index against
+// brute force over a sample of indexed vectors. Returns 0 when no index is attached.
+func (e *Engine) EstimateHNSWRecall(k, sampleSize int) float64 {
+	idx := e.index()
+	if idx == nil {
+		return 0
+	}
+	return idx.EstimateRecall(k, sampleSize, 0)
 }
 
 // BuildHNSW constructs an HNSW index from all stored embeddings.
@@ -477,7 +600,8 @@ func (e *Engine) BuildHNSW(ctx context.Context) (int, error) {
 
 		if idx == nil {
 			expectedDims = len(vec)
-			idx = ann.New(expectedDims)
+			idx = ann.NewFromParams(expectedDims, e.hnswParams)
+			idx.SetQuantization(e.storeQuantization(ctx))
 		}
 
 		if len(vec) != expectedDims {
@@ -489,7 +613,7 @@ func (e *Engine) BuildHNSW(ctx context.Context) (int, error) {
 	}
 
 	if idx == nil || idx.Len() == 0 {
-		e.hnsw = nil
+		e.SetHNSW(nil)
 		if skippedLoadErrorCount > 0 {
 			return 0, fmt.Errorf("building HNSW: no usable embeddings (load errors=%d)", skippedLoadErrorCount)
 		}
@@ -507,7 +631,7 @@ func (e *Engine) BuildHNSW(ctx context.Context) (int, error) {
 		)
 	}
 
-	e.hnsw = idx
+	e.SetHNSW(idx)
 	return idx.Len(), nil
 }
 
@@ -519,13 +643,20 @@ func (e *Engine) LoadOrBuildHNSW(ctx context.Context, path string, staleThreshol
 	if info, err := os.Stat(path); err == nil {
 		age := time.Now().Unix() - info.ModTime().Unix()
 		if staleThresholdSec == 0 || age < staleThresholdSec {
-			loaded, err := ann.Load(path)
-			if err == nil {
-				e.hnsw = loaded
+			loaded, err := ann.LoadMmap(path)
+			if err == nil && e.hnswBuildParamsMatch(loaded.Params()) && loaded.Quantization() == e.storeQuantization(ctx) {
+				if e.hnswParams.EfSearch > 0 {
+					loaded.SetEfSearch(e.hnswParams.EfSearch)
+				}
+				e.SetHNSW(loaded)
 				return loaded.Len(), nil
 			}
-			fmt.Fprintf(os.Stderr, "warning: could not load HNSW index %s: %v; rebuilding\n", path, err)
-			// Fall through to rebuild on load error
+			if err == nil {
+				loaded.Close()
+			} else {
+				fmt.Fprintf(os.Stderr, "warning: could not load HNSW index %s: %v; rebuilding\n", path, err)
+			}
+			// Fall through to rebuild on load error or changed build parameters
 		}
 	}
 
@@ -534,12 +665,13 @@ func (e *Engine) LoadOrBuildHNSW(ctx context.Context, path string, staleThreshol
 	if err != nil {
 		return 0, err
 	}
-	if e.hnsw == nil {
+	idx := e.index()
+	if idx == nil {
 		return 0, nil // no embeddings
 	}
 
 	// Save for next time
-	if err := e.hnsw.Save(path); err != nil {
+	if err := idx.Save(path); err != nil {
 		// Non-fatal: index works in memory even if save fails
 		fmt.Fprintf(os.Stderr, "warning: could not save HNSW index: %v\n", err)
 	}
@@ -547,6 +679,42 @@ func (e *Engine) LoadOrBuildHNSW(ctx context.Context, path string, staleThreshol
 	return count, nil
 }
 
+// quantizedStore is implemented by stores that support per-store embedding
+// quantization (SQLiteStore).
+type quantizedStore interface {
+	EmbeddingQuantization(ctx context.Context) (string, error)
+}
+
+// storeQuantization returns the store's embedding quantization so the HNSW
+// index mirrors it; stores without the capability are float32.
+func (e *Engine) storeQuantization(ctx context.Context) ann.Quantization {
+	qs, ok := e.store.(quantizedStore)
+	if !ok {
+		return ann.QuantizationNone
+	}
+	raw, err := qs.EmbeddingQuantization(ctx)
+	if err != nil {
+		return ann.QuantizationNone
+	}
+	q, err := ann.ParseQuantization(raw)
+	if err != nil {
+		return ann.QuantizationNone
+	}
+	return q
+}
+
+// hnswBuildParamsMatch reports whether a persisted index was built with the
+// configured M/efConstruction. Unset config fields accept whatever was persisted.
+func (e *Engine) hnswBuildParamsMatch(p ann.Params) bool {
+	if e.hnswParams.M > 0 && e.hnswParams.M != p.M {
+		return false
+	}
+	if e.hnswParams.EfConstruction > 0 && e.hnswParams.EfConstruction != p.EfConstruction {
+		return false
+	}
+	return true
+}
+
 // Search performs a search using the specified mode.
 // After retrieving results, it applies confidence decay weighting and
 // reinforces facts linked to the returned memories (Ebbinghaus reinforcement-on-recall).
@@ -555,6 +723,10 @@ func (e *Engine) Search(ctx context.Context, query string, opts Options) ([]Resu
 	if rawQuery == "" {
 		return nil, nil
 	}
+	ctx = store.WithNamespace(ctx, opts.Namespace)
+	if opts.Offset != 0 || opts.Cursor != "" {
+		return e.searchPage(ctx, rawQuery, opts)
+	}
 
 	queryShape := shapeOperatorIntentQuery(rawQuery)
 	retrievalQuery := rawQuery
@@ -563,6 +735,13 @@ func (e *Engine) Search(ctx context.Context, query string, opts Options) ([]Resu
 	}
 	strategy := e.classifyQueryStrategy(ctx, retrievalQuery)
 
+	var route *modeRouteDecision
+	if opts.Mode == ModeAuto {
+		decision := routeQueryMode(rawQuery, e.embedder != nil)
+		route = &decision
+		opts.Mode = decision.Selected
+	}
+
 	requestedLimit := opts.Limit
 	if requestedLimit <= 0 {
 		requestedLimit = 10
@@ -605,10 +784,16 @@ func (e *Engine) Search(ctx context.Context, query string, opts Options) ([]Resu
 	if intentErr != nil {
 		return nil, intentErr
 	}
+	profileName, profileErr := ParseRankingProfile(opts.Profile)
+	if profileErr != nil {
+		return nil, profileErr
+	}
+	profile := rankingProfiles[profileName]
 	if opts.TemporalQuery == nil {
 		opts.TemporalQuery = strategy.TemporalQuery
 	}
 	opts.EntityGraph = e.shouldUseEntityGraph(ctx, retrievalQuery, opts, strategy)
+	opts.Trace.begin(rawQuery, opts.Mode)
 
 	var results []Result
 	var err error
@@ -639,74 +824,139 @@ func (e *Engine) Search(ctx context.Context, query string, opts Options) ([]Resu
 		}
 	}
 
+	results = e.foldTranslations(ctx, results)
+
+	// In keyword mode, candidates missing here fell below the min score.
+	opts.Trace.observe("retrieved", results)
 	if len(results) == 0 {
 		return results, err
 	}
 
+	if len(opts.ExcludeProjects) > 0 {
+		results = excludeProjects(results, opts.ExcludeProjects)
+		opts.Trace.observe("privacy_filter", results)
+	}
+
 	// Apply source filter (Issue #199)
 	if opts.Source != "" {
 		results = filterBySource(results, opts.Source)
+		opts.Trace.observe("source_filter", results)
 	}
 
 	if intent != IntentAll {
 		results = filterByIntent(results, intent)
+		opts.Trace.observe("intent_filter", results)
 	}
 
 	// Apply metadata filters (Issue #30 / #230)
 	if opts.Agent != "" || opts.Channel != "" || opts.SessionKey != "" || opts.After != "" || opts.Before != "" {
 		results = filterByMetadata(results, opts)
+		opts.Trace.observe("metadata_filter", results)
 	}
 	if !opts.Scope.Empty() {
 		results = e.filterByFactScope(ctx, results, opts.Scope, opts.IncludeSuperseded)
+		opts.Trace.observe("scope_filter", results)
 	}
 
 	// Apply class filters / weighting (Issue #34)
 	if len(opts.Classes) > 0 {
 		results = filterByClass(results, opts.Classes)
+		opts.Trace.observe("class_filter", results)
 	}
 	if !opts.DisableClassBoost {
-		results = applyClassBoost(results, opts.Explain)
+		results = applyClassBoost(results, profile.classBoosts, opts.Explain)
+		opts.Trace.observe("class_boost", results)
+	}
+	if profile.transcriptPenalty < 1 {
+		results = applyTranscriptPenalty(results, profile.transcriptPenalty, opts.Explain)
+		opts.Trace.observe("transcript_penalty", results)
 	}
 
 	results = applyIntentBucketPriors(retrievalQuery, results, opts.Explain)
+	opts.Trace.observe("intent_prior", results)
 	results = applyCaptureNoisePenalty(results, opts.Explain)
+	opts.Trace.observe("capture_noise", results)
 	results = applyLowSignalIntentSuppression(retrievalQuery, results, opts.Explain)
+	opts.Trace.observe("low_signal", results)
 	results = applyOffTopicLowSignalSuppression(retrievalQuery, results, opts.Explain)
+	opts.Trace.observe("off_topic", results)
 	results = applyWrapperNoiseSuppression(retrievalQuery, results, opts.Explain)
+	opts.Trace.observe("wrapper_noise", results)
 	results = applyLexicalOverlapFilter(retrievalQuery, results, opts.Explain)
+	opts.Trace.observe("lexical_overlap", results)
 
 	// Metadata-aware ranking boosts (Issue #148)
 	results = applyMetadataBoosts(results, opts)
-	results = applyRecencyBoost(results, opts.Explain)
+	opts.Trace.observe("metadata_boost", results)
+	freshness := opts.Freshness
+	if freshness == nil {
+		freshness = profile.freshness
+	}
+	if freshness != nil {
+		results = applyFreshnessDecay(results, freshness, opts.Explain)
+		opts.Trace.observe("freshness", results)
+	} else {
+		results = applyRecencyBoost(results, opts.Explain)
+		opts.Trace.observe("recency", results)
+	}
 	results = applySourceWeight(results, opts.SourceBoosts, opts.Explain)
+	opts.Trace.observe("source_weight", results)
 	results = e.applyTemporalBoost(ctx, retrievalQuery, results, opts)
+	opts.Trace.observe("temporal_boost", results)
+	if opts.GraphBoost > 0 {
+		results = e.applyGraphBoost(ctx, results, opts.GraphBoost, opts.Explain)
+		opts.Trace.observe("graph_boost", results)
+	}
 
 	if !opts.IncludeSuperseded {
 		results = e.filterSupersededMemories(ctx, results)
+		opts.Trace.observe("superseded_filter", results)
 	}
 
 	// Apply confidence decay weighting and reinforce-on-recall
 	var confidenceDetails map[int64]confidenceDetail
-	results, confidenceDetails = e.applyConfidenceDecay(ctx, results, opts.IncludeSuperseded, opts.Explain)
+	results, confidenceDetails = e.applyConfidenceDecay(ctx, results, profile.confidenceWeight, opts.IncludeSuperseded, opts.Explain)
+	opts.Trace.observe("confidence", results)
 
 	if opts.Explain {
 		e.addExplainability(results, confidenceDetails)
+		if profileName != RankingProfileDefault {
+			for i := range results {
+				ensureExplain(&results[i])
+				results[i].Explain.RankComponents.RankingProfile = profileName
+			}
+		}
 	}
 
 	results = applyOperatorTop1TieBreak(queryShape, retrievalQuery, results, opts.Explain)
+	opts.Trace.observe("top1_tiebreak", results)
 
 	if opts.Explain {
 		attachQueryShapeExplain(results, queryShape)
 		attachQueryStrategyExplain(results, strategy)
+		if route != nil {
+			attachModeRouteExplain(results, *route)
+		}
 	}
 
 	if !opts.DisableDedupe {
 		results = dedupeSameSourceOverlap(results, opts.Explain)
+		opts.Trace.observe("dedupe", results)
 	}
 
 	if len(results) > requestedLimit {
 		results = results[:requestedLimit]
 	}
+	opts.Trace.observe("limit", results)
+	e.attachAnchors(ctx, results)
+	if opts.Highlight != nil {
+		e.attachHighlights(ctx, retrievalQuery, results, *opts.Highlight)
+	}
+
+	if opts.Explain {
+		e.attachNotesExplain(ctx, results)
+	}
+	opts.Trace.finish(results)
 
 	// Governance pinning (v2 M1): prepend active directives ABOVE the ranked
 	// results so human-authored rules never lose to BM25/semantic ranking noise.
@@ -763,6 +1013,7 @@ func (e *Engine) SearchFacts(ctx context.Context, query string, opts Options) ([
 	if rawQuery == "" {
 		return nil, nil
 	}
+	ctx = store.WithNamespace(ctx, opts.Namespace)
 
 	limit := opts.Limit
 	if limit <= 0 {
@@ -809,7 +1060,7 @@ func (e *Engine) SearchFacts(ctx context.Context, query string, opts Options) ([
 		if !matchesFactScope(memory, fact, opts.Scope) {
 			continue
 		}
-		if opts.Source != "" && !matchesSourcePrefix(strings.ToLower(strings.TrimSpace(memory.SourceFile)), strings.ToLower(strings.TrimSpace(opts.Source))) {
+		if opts.Source != "" && !store.MatchesSource(memory.SourceFile, opts.Source) {
 			continue
 		}
 		if opts.SessionKey != "" && (memory.Metadata == nil || !strings.EqualFold(memory.Metadata.SessionKey, opts.SessionKey)) {
@@ -842,7 +1093,7 @@ func (e *Engine) SearchFacts(ctx context.Context, query string, opts Options) ([
 			Subject:       fact.Subject,
 			Predicate:     fact.Predicate,
 			Object:        fact.Object,
-			Content:       strings.TrimSpace(strings.Join([]string{fact.Subject, fact.Predicate, fact.Object}, " ")),
+			Content:       joinFactContent(fact),
 			FactType:      fact.FactType,
 			Confidence:    fact.Confidence,
 			SourceFile:    memory.SourceFile,
@@ -854,6 +1105,7 @@ func (e *Engine) SearchFacts(ctx context.Context, query string, opts Options) ([
 		})
 	}
 
+	results = e.foldFactTranslations(ctx, results)
 	sort.Slice(results, func(i, j int) bool {
 		if results[i].Score == results[j].Score {
 			return results[i].FactID > results[j].FactID
@@ -863,9 +1115,51 @@ func (e *Engine) SearchFacts(ctx context.Context, query string, opts Options) ([
 	if len(results) > limit {
 		results = results[:limit]
 	}
+	if opts.ExpandSummaries {
+		e.expandSummaries(ctx, results)
+	}
 	return results, nil
 }
 
+// joinFactContent is a fact's subject, predicate, and object as one line.
+func joinFactContent(f *store.Fact) string {
+	return strings.TrimSpace(strings.Join([]string{f.Subject, f.Predicate, f.Object}, " "))
+}
+
+// summaryStore is implemented by stores that record cluster summaries
+// (SQLiteStore).
+type summaryStore interface {
+	SummarizedFacts(ctx context.Context, factIDs []int64) (map[int64][]*store.Fact, error)
+}
+
+// expandSummaries attaches the originals behind any cluster-summary hits.
+// Failures are non-fatal.
+func (e *Engine) expandSummaries(ctx context.Context, results []FactResult) {
+	ss, ok := e.store.(summaryStore)
+	if !ok || len(results) == 0 {
+		return
+	}
+	ids := make([]int64, len(results))
+	for i, r := range results {
+		ids[i] = r.FactID
+	}
+	originals, err := ss.SummarizedFacts(ctx, ids)
+	if err != nil {
+		return
+	}
+	for i := range results {
+		for _, f := range originals[results[i].FactID] {
+			results[i].Summarizes = append(results[i].Summarizes, SummarizedFact{
+				FactID:     f.ID,
+				Subject:    f.Subject,
+				Predicate:  f.Predicate,
+				Object:     f.Object,
+				Confidence: f.Confidence,
+			})
+		}
+	}
+}
+
 // filterByMetadata applies metadata-based filters to search results.
 func filterByMetadata(results []Result, opts Options) []Result {
 	var filtered []Result
@@ -996,14 +1290,14 @@ func filterByClass(results []Result, allowed []string) []Result {
 	return filtered
 }
 
-func applyClassBoost(results []Result, explain bool) []Result {
+func applyClassBoost(results []Result, multipliers map[string]float64, explain bool) []Result {
 	if len(results) == 0 {
 		return results
 	}
 
 	for i := range results {
 		class := store.NormalizeMemoryClass(results[i].MemoryClass)
-		multiplier, ok := classBoostMultipliers[class]
+		multiplier, ok := multipliers[class]
 		if !ok {
 			multiplier = 1.0
 		}
@@ -1241,8 +1535,7 @@ func applyOffTopicLowSignalSuppression(query string, results []Result, explain b
 }
 
 func isAutoCaptureSourceFile(sourceFile string) bool {
-	source := strings.ToLower(strings.TrimSpace(sourceFile))
-	return strings.Contains(source, "auto-capture") || strings.Contains(source, "cortex-capture-")
+	return store.ResolveSource(sourceFile).Type == store.SourceTypeCapture
 }
 
 func isWrapperNoiseContent(content string) bool {
@@ -1762,15 +2055,30 @@ func applyRecencyBoost(results []Result, explain bool) []Result {
 	return results
 }
 
-// filterBySource filters results to those whose SourceFile starts with the given prefix.
-// For connector imports, SourceFile is "provider:path" (e.g., "github:issues").
-// Matches case-insensitively on the provider portion.
+// filterBySource filters results to those from the given source: a source
+// type ("connector"), a registry key or display name ("github"), or a
+// source_file prefix. For connector imports, SourceFile is "provider:path"
+// (e.g., "github:issues"). Matching is case-insensitive.
+// excludeProjects drops results tagged with any of projects. Directives are
+// not memories and always pass.
+func excludeProjects(results []Result, projects []string) []Result {
+	excluded := make(map[string]bool, len(projects))
+	for _, p := range projects {
+		excluded[p] = true
+	}
+	var filtered []Result
+	for _, r := range results {
+		if r.Kind == "directive" || !excluded[r.Project] {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
 func filterBySource(results []Result, source string) []Result {
 	var filtered []Result
-	lowerSource := strings.ToLower(source)
 	for _, r := range results {
-		lowerSrc := strings.ToLower(r.SourceFile)
-		if strings.HasPrefix(lowerSrc, lowerSource+":") || strings.HasPrefix(lowerSrc, lowerSource+"/") || strings.EqualFold(r.SourceFile, source) {
+		if store.MatchesSource(r.SourceFile, source) {
 			filtered = append(filtered, r)
 		}
 	}
@@ -1952,18 +2260,10 @@ func isMemorySource(sourceFile string) bool {
 	return false
 }
 
-// isConnectorSource returns true if the source file looks like a connector import.
+// isConnectorSource returns true if the source file is a connector import.
 // Connector sources use "provider:path" format (e.g., "github:issues/123").
 func isConnectorSource(sourceFile string) bool {
-	// Known connector provider prefixes
-	connectorPrefixes := []string{"github:", "gmail:", "calendar:", "drive:", "slack:", "discord:", "telegram:", "notion:", "obsidian:"}
-	lower := strings.ToLower(sourceFile)
-	for _, prefix := range connectorPrefixes {
-		if strings.HasPrefix(lower, prefix) {
-			return true
-		}
-	}
-	return false
+	return store.ResolveSource(sourceFile).Type == store.SourceTypeConnector
 }
 
 // applySourceWeight gives manual imports a slight boost over connector imports.
@@ -2083,7 +2383,14 @@ func attachTemporalContext(ctx context.Context, st store.Store, results []Result
 	return results
 }
 
+// sourceWeightForFile is the built-in weight for a source file's tier and
+// kind, scaled by its source's registry trust weight (`cortex sources set
+// <key> --trust`).
 func sourceWeightForFile(sourceFile string) float64 {
+	return tierSourceWeight(sourceFile) * store.ResolveSource(sourceFile).TrustWeight
+}
+
+func tierSourceWeight(sourceFile string) float64 {
 	switch SourceTierForFile(sourceFile) {
 	case "core":
 		return sourceWeightManual * sourceWeightMemoryMD
@@ -2375,7 +2682,7 @@ func containsFold(values []string, candidate string) bool {
 
 // applyConfidenceDecay adjusts search result scores based on the effective confidence
 // of facts linked to each memory, and reinforces those facts (Ebbinghaus recall).
-func (e *Engine) applyConfidenceDecay(ctx context.Context, results []Result, includeSuperseded bool, explain bool) ([]Result, map[int64]confidenceDetail) {
+func (e *Engine) applyConfidenceDecay(ctx context.Context, results []Result, weight float64, includeSuperseded bool, explain bool) ([]Result, map[int64]confidenceDetail) {
 	// Collect memory IDs from results
 	memoryIDs := make([]int64, 0, len(results))
 	for _, r := range results {
@@ -2401,7 +2708,7 @@ func (e *Engine) applyConfidenceDecay(ctx context.Context, results []Result, inc
 		// Blend: score = (1 - weight) * original_score + weight * (original_score * effective_confidence)
 		// This gently penalizes stale memories without completely suppressing them.
 		preConfidenceScore := results[i].Score
-		results[i].Score = (1-ConfidenceWeight)*results[i].Score + ConfidenceWeight*(results[i].Score*detail.effectiveConfidence)
+		results[i].Score = (1-weight)*results[i].Score + weight*(results[i].Score*detail.effectiveConfidence)
 
 		if explain {
 			ensureExplain(&results[i])
@@ -2414,7 +2721,7 @@ func (e *Engine) applyConfidenceDecay(ctx context.Context, results []Result, inc
 			if results[i].Explain.RankComponents.PreConfidenceScore == 0 {
 				results[i].Explain.RankComponents.PreConfidenceScore = preConfidenceScore
 			}
-			results[i].Explain.RankComponents.ConfidenceWeight = ConfidenceWeight
+			results[i].Explain.RankComponents.ConfidenceWeight = weight
 			results[i].Explain.RankComponents.FinalScore = results[i].Score
 			results[i].Explain.Confidence.Confidence = detail.confidence
 			results[i].Explain.Confidence.EffectiveConfidence = detail.effectiveConfidence
@@ -2505,6 +2812,37 @@ func (e *Engine) getMemoryConfidenceMap(ctx context.Context, memoryIDs []int64,
 	return confidenceMap
 }
 
+// annotatedStore is implemented by stores that keep free-text notes on
+// facts and memories (SQLiteStore).
+type annotatedStore interface {
+	AnnotationsForMemories(ctx context.Context, memoryIDs []int64) (map[int64][]store.Annotation, error)
+}
+
+// attachNotesExplain copies notes on each result's memory (and the facts
+// extracted from it) into the explain block. Failures are non-fatal.
+func (e *Engine) attachNotesExplain(ctx context.Context, results []Result) {
+	as, ok := e.store.(annotatedStore)
+	if !ok || len(results) == 0 {
+		return
+	}
+	ids := make([]int64, 0, len(results))
+	for _, r := range results {
+		if r.MemoryID > 0 {
+			ids = append(ids, r.MemoryID)
+		}
+	}
+	notes, err := as.AnnotationsForMemories(ctx, ids)
+	if err != nil || len(notes) == 0 {
+		return
+	}
+	for i := range results {
+		if n := notes[results[i].MemoryID]; len(n) > 0 {
+			ensureExplain(&results[i])
+			results[i].Explain.Notes = n
+		}
+	}
+}
+
 func ensureExplain(result *Result) {
 	if result.Explain != nil {
 		return
@@ -2586,18 +2924,60 @@ var timeNow = func() time.Time { return time.Now().UTC() }
 // Uses AND-first-then-OR strategy: tries implicit AND for precision,
 // falls back to OR for recall when AND returns zero results.
 func (e *Engine) searchBM25(ctx context.Context, query string, opts Options) ([]Result, error) {
+	storeResults, err := e.ftsCandidates(ctx, query, opts.Limit, opts.Project, opts.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	minScore := effectiveMinScore(ModeKeyword, opts.MinScore)
+	results := make([]Result, 0, len(storeResults))
+	allFiltered := make([]Result, 0, len(storeResults))
+
+	for _, sr := range storeResults {
+		r := bm25Result(sr, opts.Explain)
+		allFiltered = append(allFiltered, r)
+
+		if r.Score >= minScore {
+			results = append(results, r)
+		}
+	}
+	opts.Trace.observe("fts", allFiltered)
+
+	// Small-DB rescue: if FTS5 returned matches but all scores fell below
+	// the DEFAULT threshold (common with <50 docs where IDF is very low),
+	// return the matches anyway. A low-confidence result beats no result.
+	// Only applies when user hasn't set an explicit MinScore.
+	if len(results) == 0 && len(allFiltered) > 0 && opts.MinScore < 0 {
+		results = allFiltered
+	}
+
+	// Sort by score descending (should already be sorted from FTS5, but ensure it)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	return results, nil
+}
+
+// ftsCandidates runs the FTS5 query behind keyword search: the sanitized
+// query, an escaped retry on syntax errors, and an OR retry when a
+// multi-word AND query matches nothing.
+func (e *Engine) ftsCandidates(ctx context.Context, query string, limit int, project, source string) ([]*store.SearchResult, error) {
 	// Sanitize query to prevent FTS5 syntax errors from crashing
 	sanitized := sanitizeFTSQuery(query)
 	if sanitized == "" {
 		return nil, nil
 	}
 
-	storeResults, err := e.store.SearchFTSWithFilters(ctx, sanitized, opts.Limit, opts.Project, opts.Source)
+	// Type filters ("--source connector") can't be expressed as a prefix;
+	// callers apply them with filterBySource.
+	source = store.SourceFilterPrefix(source)
+	storeResults, err := e.store.SearchFTSWithFilters(ctx, sanitized, limit, project, source)
 	if err != nil {
 		// If the query has bad FTS5 syntax, try a simpler fallback
 		if isFTSSyntaxError(err) {
 			escaped := escapeFTSQuery(query)
-			storeResults, err = e.store.SearchFTSWithFilters(ctx, escaped, opts.Limit, opts.Project, opts.Source)
+			storeResults, err = e.store.SearchFTSWithFilters(ctx, escaped, limit, project, source)
 			if err != nil {
 				return nil, fmt.Errorf("search failed: %w", err)
 			}
@@ -2611,72 +2991,51 @@ func (e *Engine) searchBM25(ctx context.Context, query string, opts Options) ([]
 	if len(storeResults) == 0 && hasMultipleSearchTerms(sanitized) {
 		orQuery := buildORQuery(sanitized)
 		if orQuery != "" {
-			storeResults, err = e.store.SearchFTSWithFilters(ctx, orQuery, opts.Limit, opts.Project, opts.Source)
+			storeResults, err = e.store.SearchFTSWithFilters(ctx, orQuery, limit, project, source)
 			if err != nil {
 				// OR fallback failed — not fatal, just return empty
 				storeResults = nil
 			}
 		}
 	}
+	return storeResults, nil
+}
 
-	minScore := effectiveMinScore(ModeKeyword, opts.MinScore)
-	results := make([]Result, 0, len(storeResults))
-	allFiltered := make([]Result, 0, len(storeResults))
-
-	for _, sr := range storeResults {
-		// FTS5 rank is negative (more negative = better match).
-		// Convert to positive score where higher = better.
-		score := normalizeBM25Score(sr.Score)
-
-		r := Result{
-			Content:       sr.Memory.Content,
-			SourceFile:    sr.Memory.SourceFile,
-			SourceTier:    SourceTierForFile(sr.Memory.SourceFile),
-			SourceLine:    sr.Memory.SourceLine,
-			SourceSection: sr.Memory.SourceSection,
-			Project:       sr.Memory.Project,
-			MemoryClass:   sr.Memory.MemoryClass,
-			Metadata:      sr.Memory.Metadata,
-			Score:         score,
-			Snippet:       sr.Snippet,
-			MatchType:     "bm25",
-			MemoryID:      sr.Memory.ID,
-			ImportedAt:    sr.Memory.ImportedAt,
-		}
-		if opts.Explain {
-			r.Explain = &ExplainDetails{
-				RankComponents: RankComponents{
-					BaseScore:            score,
-					PreConfidenceScore:   score,
-					FinalScore:           score,
-					ClassBoostMultiplier: 1.0,
-					ConfidenceWeight:     ConfidenceWeight,
-					BM25Raw:              floatPtr(sr.Score),
-					BM25Score:            floatPtr(score),
-				},
-			}
-		}
-		allFiltered = append(allFiltered, r)
+// bm25Result converts an FTS5 match into a keyword search result.
+func bm25Result(sr *store.SearchResult, explain bool) Result {
+	// FTS5 rank is negative (more negative = better match).
+	// Convert to positive score where higher = better.
+	score := normalizeBM25Score(sr.Score)
 
-		if score >= minScore {
-			results = append(results, r)
-		}
+	r := Result{
+		Content:       sr.Memory.Content,
+		SourceFile:    sr.Memory.SourceFile,
+		SourceTier:    SourceTierForFile(sr.Memory.SourceFile),
+		SourceLine:    sr.Memory.SourceLine,
+		SourceSection: sr.Memory.SourceSection,
+		Project:       sr.Memory.Project,
+		MemoryClass:   sr.Memory.MemoryClass,
+		Metadata:      sr.Memory.Metadata,
+		Score:         score,
+		Snippet:       sr.Snippet,
+		MatchType:     "bm25",
+		MemoryID:      sr.Memory.ID,
+		ImportedAt:    sr.Memory.ImportedAt,
 	}
-
-	// Small-DB rescue: if FTS5 returned matches but all scores fell below
-	// the DEFAULT threshold (common with <50 docs where IDF is very low),
-	// return the matches anyway. A low-confidence result beats no result.
-	// Only applies when user hasn't set an explicit MinScore.
-	if len(results) == 0 && len(allFiltered) > 0 && opts.MinScore < 0 {
-		results = allFiltered
+	if explain {
+		r.Explain = &ExplainDetails{
+			RankComponents: RankComponents{
+				BaseScore:            score,
+				PreConfidenceScore:   score,
+				FinalScore:           score,
+				ClassBoostMultiplier: 1.0,
+				ConfidenceWeight:     ConfidenceWeight,
+				BM25Raw:              floatPtr(sr.Score),
+				BM25Score:            floatPtr(score),
+			},
+		}
 	}
-
-	// Sort by score descending (should already be sorted from FTS5, but ensure it)
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
-	})
-
-	return results, nil
+	return r
 }
 
 // normalizeBM25Score converts FTS5's negative rank to a 0-1 score.
@@ -2920,8 +3279,8 @@ func (e *Engine) searchSemantic(ctx context.Context, query string, opts Options)
 	minScore := effectiveMinScore(ModeSemantic, opts.MinScore)
 
 	// Use HNSW index if available (O(log N)), otherwise fall back to brute-force (O(N))
-	if e.hnsw != nil && opts.Project == "" {
-		return e.searchSemanticHNSW(ctx, queryEmbedding, opts, minScore)
+	if idx := e.index(); idx != nil && opts.Project == "" {
+		return e.searchSemanticHNSW(ctx, idx, queryEmbedding, opts, minScore)
 	}
 
 	// Brute-force fallback (also used when project filter is active,
@@ -2968,22 +3327,25 @@ func (e *Engine) searchSemantic(ctx context.Context, query string, opts Options)
 
 // SaveHNSW persists the current HNSW index to disk.
 func (e *Engine) SaveHNSW(path string) error {
-	if e.hnsw == nil {
+	idx := e.index()
+	if idx == nil {
 		return fmt.Errorf("no HNSW index loaded")
 	}
-	return e.hnsw.Save(path)
+	return idx.Save(path)
 }
 
 // searchSemanticHNSW performs semantic search using the HNSW index.
 // Converts cosine distance to similarity, fetches memory details from store.
-func (e *Engine) searchSemanticHNSW(ctx context.Context, queryVec []float32, opts Options, minScore float64) ([]Result, error) {
+func (e *Engine) searchSemanticHNSW(ctx context.Context, hnsw *ann.Index, queryVec []float32, opts Options, minScore float64) ([]Result, error) {
 	// HNSW returns cosine distance; we need extra candidates since we filter by minScore after
 	ef := opts.Limit * 3
-	if ef < 50 {
-		ef = 50
+	if floor := hnsw.Params().EfSearch; ef < floor {
+		ef = floor
 	}
 
-	annResults := e.hnsw.SearchEf(queryVec, opts.Limit*2, ef)
+	annResults := hnsw.SearchEf(queryVec, opts.Limit*2, ef)
+	// The index spans every namespace; GetMemory is not scoped.
+	namespace := store.EffectiveNamespace(ctx, e.store)
 
 	var results []Result
 	for _, ar := range annResults {
@@ -2997,6 +3359,9 @@ func (e *Engine) searchSemanticHNSW(ctx context.Context, queryVec []float32, opt
 		if err != nil || mem == nil {
 			continue // memory may have been deleted since index was built
 		}
+		if !store.NamespaceVisible(namespace, mem.Namespace) {
+			continue
+		}
 
 		r := Result{
 			Content:       mem.Content,
@@ -3048,6 +3413,7 @@ func (e *Engine) searchHybrid(ctx context.Context, query string, opts Options) (
 	// Fetch more candidates than requested so fusion has a wider pool.
 	// With only opts.Limit from each, overlap is sparse and ranking is noisy.
 	candidateOpts := opts
+	candidateOpts.Trace = nil // the channels run concurrently; the fused result is traced
 	candidateOpts.Limit = opts.Limit * 3
 	if candidateOpts.Limit < 15 {
 		candidateOpts.Limit = 15
@@ -3115,6 +3481,7 @@ func (e *Engine) searchRRF(ctx context.Context, query string, opts Options, stra
 	}
 
 	candidateOpts := opts
+	candidateOpts.Trace = nil // the channels run concurrently; the fused result is traced
 	candidateOpts.Limit = opts.Limit * 3
 	if candidateOpts.Limit < 15 {
 		candidateOpts.Limit = 15
@@ -1000,6 +1000,35 @@ func TestSearchExplain_Enabled(t *testing.T) {
 	}
 }
 
+func TestSearchExplain_IncludesNotes(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "The deploy window is Tuesday at noon", SourceFile: "ops.md"})
+	factID, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "deploy", Predicate: "window", Object: "Tuesday noon", FactType: "temporal"})
+	sqlStore := s.(*store.SQLiteStore)
+	if _, err := sqlStore.AddAnnotation(ctx, &store.Annotation{TargetType: store.AnnotationTargetFact, TargetID: factID, Body: "confirmed with ops 2026-02"}); err != nil {
+		t.Fatalf("AddAnnotation: %v", err)
+	}
+	engine := NewEngine(s)
+
+	results, err := engine.Search(ctx, "deploy window", Options{Mode: ModeKeyword, Limit: 5, Explain: true})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	r, ok := findResultByID(results, memID)
+	if !ok || r.Explain == nil {
+		t.Fatalf("expected explained result for memory %d, got %+v", memID, results)
+	}
+	if len(r.Explain.Notes) != 1 || r.Explain.Notes[0].TargetID != factID {
+		t.Fatalf("expected the fact note in explain, got %+v", r.Explain.Notes)
+	}
+
+	plain, _ := engine.Search(ctx, "deploy window", Options{Mode: ModeKeyword, Limit: 5})
+	if r, ok := findResultByID(plain, memID); !ok || r.Explain != nil {
+		t.Fatal("notes should only be attached with explain")
+	}
+}
+
 // --- MinScore Filter ---
 
 func TestSearchBM25_MinScore(t *testing.T) {
@@ -1934,6 +1963,37 @@ func TestDedupeSameSourceOverlap(t *testing.T) {
 	}
 }
 
+func TestSearchFacts_ExpandSummaries(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	ss := s.(*store.SQLiteStore)
+
+	memID, _ := s.AddMemory(ctx, &store.Memory{Content: "release process notes", SourceFile: "ops.md"})
+	a, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "release", Predicate: "cadence", Object: "weekly", FactType: "kv"})
+	b, _ := s.AddFact(ctx, &store.Fact{MemoryID: memID, Subject: "release", Predicate: "day", Object: "thursday", FactType: "kv"})
+	summaryID, err := ss.ApplyClusterSummary(ctx, 1, &store.Fact{MemoryID: memID, Subject: "release", Predicate: "schedule", Object: "weekly on thursday", FactType: "kv"}, []int64{a, b}, "")
+	if err != nil {
+		t.Fatalf("ApplyClusterSummary: %v", err)
+	}
+
+	engine := NewEngine(s)
+	results, err := engine.SearchFacts(ctx, "release weekly thursday", Options{Limit: 5})
+	if err != nil || len(results) != 1 || results[0].FactID != summaryID {
+		t.Fatalf("expected only the summary fact, got %+v, %v", results, err)
+	}
+	if len(results[0].Summarizes) != 0 {
+		t.Fatal("originals must only be attached on drill-down")
+	}
+
+	results, err = engine.SearchFacts(ctx, "release weekly thursday", Options{Limit: 5, ExpandSummaries: true})
+	if err != nil {
+		t.Fatalf("SearchFacts: %v", err)
+	}
+	if got := results[0].Summarizes; len(got) != 2 || got[0].FactID != a || got[1].Object != "thursday" {
+		t.Fatalf("expected both originals, got %+v", got)
+	}
+}
+
 func TestSearchFacts_ReturnsDirectFactHits(t *testing.T) {
 	s := newTestStore(t)
 	ctx := context.Background()
@@ -1975,3 +2035,51 @@ func TestDetectIntentBucket(t *testing.T) {
 		t.Fatalf("expected spear bucket, got %q", got)
 	}
 }
+
+func TestExcludeProjects_KeepsDirectives(t *testing.T) {
+	results := []Result{
+		{MemoryID: 1, Project: "work"},
+		{MemoryID: 2, Project: "personal"},
+		{Kind: "directive", Content: "always cite sources", SourceSection: "global"},
+	}
+	got := excludeProjects(results, []string{"personal"})
+	if len(got) != 2 || got[0].MemoryID != 1 || got[1].Kind != "directive" {
+		t.Fatalf("unexpected results after excluding personal: %+v", got)
+	}
+}
+
+func TestSearch_NamespaceOption(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	for ns, content := range map[string]string{"": "retro notes: the build cache saved an hour", "agent-b": "retro notes: flaky tests blocked the merge"} {
+		id, err := s.AddMemory(store.WithNamespace(ctx, ns), &store.Memory{Content: content, SourceFile: "retro.md"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.AddFact(ctx, &store.Fact{MemoryID: id, Subject: "retro", Predicate: "note", Object: content, FactType: "kv"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	engine := NewEngine(s)
+
+	for _, tc := range []struct {
+		namespace string
+		want      int
+	}{{"", 1}, {"agent-b", 1}, {"*", 2}, {"empty", 0}} {
+		opts := Options{Mode: ModeKeyword, Limit: 10, MinScore: -1, DisableDedupe: true, Namespace: tc.namespace}
+		results, err := engine.Search(ctx, "retro notes", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != tc.want {
+			t.Errorf("Search namespace %q: %d results, want %d", tc.namespace, len(results), tc.want)
+		}
+		facts, err := engine.SearchFacts(ctx, "retro", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(facts) != tc.want {
+			t.Errorf("SearchFacts namespace %q: %d results, want %d", tc.namespace, len(facts), tc.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Trace records every candidate's score at each stage of the ranking
+// pipeline, including candidates a filter removed, so ranking behavior can be
+// analyzed offline. Set Options.Trace to collect one; a nil Trace is a no-op.
+type Trace struct {
+	query      string
+	mode       Mode
+	candidates []*TraceCandidate
+	byKey      map[string]*TraceCandidate
+}
+
+// TraceCandidate is one candidate's path through the pipeline. Rank is 1-based
+// for returned results and 0 for candidates dropped at FilteredAt.
+type TraceCandidate struct {
+	Query      string          `json:"query"`
+	Mode       Mode            `json:"mode"`
+	MemoryID   int64           `json:"memory_id"`
+	SourceFile string          `json:"source_file"`
+	SourceLine int             `json:"source_line,omitempty"`
+	Class      string          `json:"class,omitempty"`
+	Project    string          `json:"project,omitempty"`
+	MatchType  string          `json:"match_type"`
+	ImportedAt time.Time       `json:"imported_at,omitempty"`
+	Rank       int             `json:"rank"`
+	FinalScore float64         `json:"final_score"`
+	FilteredAt string          `json:"filtered_at,omitempty"`
+	Stages     []TraceStage    `json:"stages"`
+	Explain    *ExplainDetails `json:"explain,omitempty"`
+}
+
+// TraceStage is a candidate's score after one pipeline stage.
+type TraceStage struct {
+	Stage string  `json:"stage"`
+	Score float64 `json:"score"`
+}
+
+// NewTrace returns an empty trace ready to pass in Options.Trace.
+func NewTrace() *Trace {
+	return &Trace{byKey: make(map[string]*TraceCandidate)}
+}
+
+// Candidates returns returned results in rank order, followed by filtered
+// candidates in the order they were first seen.
+func (t *Trace) Candidates() []TraceCandidate {
+	if t == nil {
+		return nil
+	}
+	out := make([]TraceCandidate, 0, len(t.candidates))
+	for _, c := range t.candidates {
+		if c.Rank > 0 {
+			out = append(out, *c)
+		}
+	}
+	// Ranked candidates were first seen in retrieval order, not rank order.
+	for i := 1; i < len(out); i++ {
+		for j := i; j > 0 && out[j].Rank < out[j-1].Rank; j-- {
+			out[j], out[j-1] = out[j-1], out[j]
+		}
+	}
+	for _, c := range t.candidates {
+		if c.Rank == 0 {
+			out = append(out, *c)
+		}
+	}
+	return out
+}
+
+// WriteJSONL writes one JSON object per candidate.
+func (t *Trace) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, c := range t.Candidates() {
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func traceKey(r Result) string {
+	if r.MemoryID > 0 {
+		return fmt.Sprintf("m%d", r.MemoryID)
+	}
+	return fmt.Sprintf("%s:%d#%s", r.SourceFile, r.SourceLine, r.SourceSection)
+}
+
+// begin resets the trace for a new query.
+func (t *Trace) begin(query string, mode Mode) {
+	if t == nil {
+		return
+	}
+	t.query = query
+	t.mode = mode
+	t.candidates = nil
+	t.byKey = make(map[string]*TraceCandidate)
+}
+
+// observe records each result's score after stage and marks candidates that
+// were alive before it but are missing now as filtered at stage.
+func (t *Trace) observe(stage string, results []Result) {
+	if t == nil {
+		return
+	}
+	seen := make(map[string]bool, len(results))
+	for _, r := range results {
+		key := traceKey(r)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		c, ok := t.byKey[key]
+		if !ok {
+			c = &TraceCandidate{
+				Query:      t.query,
+				Mode:       t.mode,
+				MemoryID:   r.MemoryID,
+				SourceFile: r.SourceFile,
+				SourceLine: r.SourceLine,
+				Class:      r.MemoryClass,
+				Project:    r.Project,
+				MatchType:  r.MatchType,
+				ImportedAt: r.ImportedAt,
+			}
+			t.byKey[key] = c
+			t.candidates = append(t.candidates, c)
+		}
+		c.FilteredAt = ""
+		c.Stages = append(c.Stages, TraceStage{Stage: stage, Score: r.Score})
+		if r.Explain != nil {
+			c.Explain = r.Explain
+		}
+	}
+	for key, c := range t.byKey {
+		if !seen[key] && c.FilteredAt == "" {
+			c.FilteredAt = stage
+		}
+	}
+}
+
+// finish assigns ranks and final scores to the returned results.
+func (t *Trace) finish(results []Result) {
+	if t == nil {
+		return
+	}
+	for i, r := range results {
+		c, ok := t.byKey[traceKey(r)]
+		if !ok || c.Rank > 0 {
+			continue
+		}
+		c.Rank = i + 1
+		c.FinalScore = r.Score
+		c.Explain = r.Explain
+	}
+}
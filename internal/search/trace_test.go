@@ -0,0 +1,92 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestSearch_TraceRecordsFilteredCandidates(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	for _, m := range []*store.Memory{
+		{Content: "Deploy checklist: run migrations before the rollout", SourceFile: "ops/deploy.md", MemoryClass: "rule"},
+		{Content: "Deploy retro: the rollout stalled on migrations", SourceFile: "notes/retro.md", MemoryClass: "status"},
+		{Content: "Deploy freeze starts friday", SourceFile: "github:issues/7", MemoryClass: "status"},
+	} {
+		if _, err := s.AddMemory(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	engine := NewEngine(s)
+
+	trace := NewTrace()
+	results, err := engine.Search(ctx, "deploy", Options{Mode: ModeKeyword, Limit: 1, MinScore: -1, Explain: true, Source: "file", Trace: trace})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	cands := trace.Candidates()
+	if len(cands) != 3 {
+		t.Fatalf("expected 3 traced candidates, got %d: %+v", len(cands), cands)
+	}
+	top := cands[0]
+	if top.Rank != 1 || top.MemoryID != results[0].MemoryID || top.FinalScore != results[0].Score || top.Explain == nil {
+		t.Errorf("unexpected ranked candidate: %+v", top)
+	}
+	if top.Query != "deploy" || top.Mode != ModeKeyword || top.Stages[0].Stage != "fts" || top.Stages[len(top.Stages)-1].Stage != "limit" {
+		t.Errorf("unexpected trace header/stages: %+v", top)
+	}
+	filteredAt := map[string]string{}
+	for _, c := range cands[1:] {
+		if c.Rank != 0 || c.FinalScore != 0 || len(c.Stages) == 0 {
+			t.Errorf("filtered candidate should be unranked with stages: %+v", c)
+		}
+		filteredAt[c.SourceFile] = c.FilteredAt
+	}
+	if filteredAt["github:issues/7"] != "source_filter" {
+		t.Errorf("connector memory filtered at %q, want source_filter", filteredAt["github:issues/7"])
+	}
+	other := "notes/retro.md"
+	if top.SourceFile == other {
+		other = "ops/deploy.md"
+	}
+	if got := filteredAt[other]; got != "limit" {
+		t.Errorf("second file memory filtered at %q, want limit", got)
+	}
+
+	var buf bytes.Buffer
+	if err := trace.WriteJSONL(&buf); err != nil {
+		t.Fatalf("WriteJSONL: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 JSONL lines, got %d", len(lines))
+	}
+	var row map[string]any
+	if err := json.Unmarshal([]byte(lines[2]), &row); err != nil {
+		t.Fatalf("decode line: %v", err)
+	}
+	for _, key := range []string{"query", "memory_id", "rank", "filtered_at", "stages"} {
+		if _, ok := row[key]; !ok {
+			t.Errorf("JSONL line missing %q: %s", key, lines[2])
+		}
+	}
+}
+
+func TestTrace_NilIsNoOp(t *testing.T) {
+	var trace *Trace
+	trace.begin("q", ModeKeyword)
+	trace.observe("fts", []Result{{MemoryID: 1}})
+	trace.finish([]Result{{MemoryID: 1}})
+	if trace.Candidates() != nil {
+		t.Fatal("nil trace should have no candidates")
+	}
+}
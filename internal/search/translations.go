@@ -0,0 +1,116 @@
+package search
+
+import (
+	"context"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// translationStore is implemented by stores that keep translations
+// (SQLiteStore, via cortex translate).
+type translationStore interface {
+	TranslationOriginals(ctx context.Context, kind string, ids []int64) (map[int64]store.Translation, error)
+}
+
+// foldTranslations replaces hits on translated memories with their
+// originals, so a query matches across languages but results still point
+// at what was imported. An original hit both directly and through its
+// translation appears once, at the better score. Failures are non-fatal.
+func (e *Engine) foldTranslations(ctx context.Context, results []Result) []Result {
+	ts, ok := e.store.(translationStore)
+	if !ok || len(results) == 0 {
+		return results
+	}
+	ids := make([]int64, len(results))
+	for i, r := range results {
+		ids[i] = r.MemoryID
+	}
+	translations, err := ts.TranslationOriginals(ctx, store.TranslationKindMemory, ids)
+	if err != nil || len(translations) == 0 {
+		return results
+	}
+	originalIDs := make([]int64, 0, len(translations))
+	for _, t := range translations {
+		originalIDs = append(originalIDs, t.OriginalID)
+	}
+	memories, err := e.store.GetMemoriesByIDs(ctx, originalIDs)
+	if err != nil {
+		return results
+	}
+	originals := make(map[int64]*store.Memory, len(memories))
+	for _, m := range memories {
+		originals[m.ID] = m
+	}
+
+	out := results[:0]
+	position := make(map[int64]int, len(results))
+	for _, r := range results {
+		if t, ok := translations[r.MemoryID]; ok {
+			if m := originals[t.OriginalID]; m != nil {
+				r.Content = m.Content
+				r.SourceFile = m.SourceFile
+				r.SourceLine = m.SourceLine
+				r.SourceSection = m.SourceSection
+				r.Project = m.Project
+				r.MemoryClass = m.MemoryClass
+				r.Metadata = m.Metadata
+				r.ImportedAt = m.ImportedAt
+				r.MemoryID = m.ID
+				r.FactIDs = nil
+				r.MatchedTranslation = t.Lang
+			}
+		}
+		if i, seen := position[r.MemoryID]; seen {
+			if r.Score > out[i].Score {
+				out[i] = r
+			}
+			continue
+		}
+		position[r.MemoryID] = len(out)
+		out = append(out, r)
+	}
+	return out
+}
+
+// foldFactTranslations is foldTranslations for fact hits.
+func (e *Engine) foldFactTranslations(ctx context.Context, results []FactResult) []FactResult {
+	ts, ok := e.store.(translationStore)
+	if !ok || len(results) == 0 {
+		return results
+	}
+	ids := make([]int64, len(results))
+	for i, r := range results {
+		ids[i] = r.FactID
+	}
+	translations, err := ts.TranslationOriginals(ctx, store.TranslationKindFact, ids)
+	if err != nil || len(translations) == 0 {
+		return results
+	}
+
+	out := results[:0]
+	position := make(map[int64]int, len(results))
+	for _, r := range results {
+		if t, ok := translations[r.FactID]; ok {
+			if f, err := e.store.GetFact(ctx, t.OriginalID); err == nil && f != nil {
+				r.FactID = f.ID
+				r.MemoryID = f.MemoryID
+				r.Subject = f.Subject
+				r.Predicate = f.Predicate
+				r.Object = f.Object
+				r.Content = joinFactContent(f)
+				r.FactType = f.FactType
+				r.Confidence = f.Confidence
+				r.MatchedTranslation = t.Lang
+			}
+		}
+		if i, seen := position[r.FactID]; seen {
+			if r.Score > out[i].Score {
+				out[i] = r
+			}
+			continue
+		}
+		position[r.FactID] = len(out)
+		out = append(out, r)
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func TestSearch_MatchesAcrossLanguagesThroughTranslations(t *testing.T) {
+	s := newTestStore(t)
+	sqlStore := s.(*store.SQLiteStore)
+	ctx := context.Background()
+
+	origID, _ := s.AddMemory(ctx, &store.Memory{Content: "Die Wohnung in Kreuzberg hat einen Balkon.", SourceFile: "notes/wohnung.md", Project: "home"})
+	trID, err := sqlStore.AddMemoryTranslation(ctx, origID, "en", "de", "test/model", "The apartment in Kreuzberg has a balcony.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	factID, _ := s.AddFact(ctx, &store.Fact{MemoryID: origID, Subject: "Wohnung", Predicate: "hat", Object: "Balkon", FactType: "kv", Confidence: 0.9})
+	if _, err := sqlStore.AddFactTranslation(ctx, factID, &store.Fact{MemoryID: trID, Subject: "apartment", Predicate: "has", Object: "balcony"}, "en", "de", "test/model"); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := NewEngine(s)
+	results, err := engine.Search(ctx, "apartment balcony", Options{Mode: ModeKeyword, Limit: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected the original once, got %+v", results)
+	}
+	if r := results[0]; r.MemoryID != origID || r.MatchedTranslation != "en" || r.Content != "Die Wohnung in Kreuzberg hat einen Balkon." {
+		t.Fatalf("expected the hit folded onto the original, got %+v", r)
+	}
+
+	// Matching both the original and its translation still yields one hit.
+	results, _ = engine.Search(ctx, "Kreuzberg", Options{Mode: ModeKeyword, Limit: 5})
+	if len(results) != 1 || results[0].MemoryID != origID {
+		t.Fatalf("expected one result for the original, got %+v", results)
+	}
+
+	facts, err := engine.SearchFacts(ctx, "balcony", Options{Limit: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(facts) != 1 || facts[0].FactID != factID || facts[0].Object != "Balkon" || facts[0].MatchedTranslation != "en" {
+		t.Fatalf("expected the fact hit folded onto the original, got %+v", facts)
+	}
+}
@@ -0,0 +1,366 @@
+// Package seed applies hand-written "profile facts" to a store.
+//
+// A seed file is YAML listing subject/predicate/object triples with a memory
+// class and confidence. Applying it stores each triple as a fact backed by a
+// one-line memory under the source "seed:<name>", so operators can bootstrap
+// identity, preference, and rule facts deterministically instead of hoping
+// extraction derives them from prose. Applying the same file again changes
+// nothing; changing an object supersedes the fact it replaces.
+package seed
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/extract"
+	"github.com/hurttlocker/cortex/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+// SourcePrefix marks the source_file of memories created from seed files.
+const SourcePrefix = "seed:"
+
+// classFactTypes is the fact type a seed fact gets from its class when it
+// does not name one.
+var classFactTypes = map[string]string{
+	store.MemoryClassIdentity:   "identity",
+	store.MemoryClassPreference: "preference",
+	store.MemoryClassDecision:   "decision",
+	store.MemoryClassStatus:     "state",
+	store.MemoryClassRule:       "kv",
+	store.MemoryClassScratch:    "kv",
+}
+
+// File is a parsed seed file.
+type File struct {
+	// Name labels the seed; memories are stored under "seed:<name>".
+	// Defaults to the file name without its extension.
+	Name string `yaml:"name"`
+	// Project and Agent scope every fact that does not set its own.
+	Project string `yaml:"project,omitempty"`
+	Agent   string `yaml:"agent,omitempty"`
+	// Defaults apply to facts that leave the field empty.
+	Defaults struct {
+		Class      string  `yaml:"class,omitempty"`
+		Confidence float64 `yaml:"confidence,omitempty"`
+	} `yaml:"defaults,omitempty"`
+	Facts []Fact `yaml:"facts"`
+}
+
+// Fact is one seeded triple.
+type Fact struct {
+	Subject   string `yaml:"subject"`
+	Predicate string `yaml:"predicate"`
+	Object    string `yaml:"object"`
+	// Class is the memory class (identity, preference, rule, decision,
+	// status, scratch).
+	Class string `yaml:"class,omitempty"`
+	// Type is the fact type; defaults from Class, else kv.
+	Type       string  `yaml:"type,omitempty"`
+	Confidence float64 `yaml:"confidence,omitempty"`
+	// Core pins the fact (state core) so lifecycle decay never retires it.
+	Core    bool   `yaml:"core,omitempty"`
+	Project string `yaml:"project,omitempty"`
+	Agent   string `yaml:"agent,omitempty"`
+}
+
+// Load reads and validates a seed file.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading seed file: %w", err)
+	}
+	f, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if f.Name == "" {
+		f.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return f, nil
+}
+
+// Parse decodes and validates seed YAML, filling in defaults. Unknown keys
+// are errors so a typo cannot silently drop a field.
+func Parse(data []byte) (*File, error) {
+	var f File
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing seed: %w", err)
+	}
+	f.Name = strings.TrimSpace(f.Name)
+	if len(f.Facts) == 0 {
+		return nil, fmt.Errorf("seed has no facts")
+	}
+	defaultClass := store.NormalizeMemoryClass(f.Defaults.Class)
+	if defaultClass != "" && !store.IsValidMemoryClass(defaultClass) {
+		return nil, fmt.Errorf("defaults.class: invalid memory class %q (valid: %s)", f.Defaults.Class, strings.Join(store.AvailableMemoryClasses(), ", "))
+	}
+	if c := f.Defaults.Confidence; c < 0 || c > 1 {
+		return nil, fmt.Errorf("defaults.confidence: must be between 0 and 1, got %g", c)
+	}
+
+	for i := range f.Facts {
+		sf := &f.Facts[i]
+		sf.Subject = strings.TrimSpace(sf.Subject)
+		sf.Predicate = strings.TrimSpace(sf.Predicate)
+		sf.Object = strings.TrimSpace(sf.Object)
+		if sf.Subject == "" || sf.Predicate == "" || sf.Object == "" {
+			return nil, fmt.Errorf("facts[%d]: subject, predicate, and object are required", i)
+		}
+		if sf.Class = store.NormalizeMemoryClass(sf.Class); sf.Class == "" {
+			sf.Class = defaultClass
+		}
+		if sf.Class != "" && !store.IsValidMemoryClass(sf.Class) {
+			return nil, fmt.Errorf("facts[%d].class: invalid memory class %q (valid: %s)", i, sf.Class, strings.Join(store.AvailableMemoryClasses(), ", "))
+		}
+		if sf.Type = strings.ToLower(strings.TrimSpace(sf.Type)); sf.Type == "" {
+			if sf.Type = classFactTypes[sf.Class]; sf.Type == "" {
+				sf.Type = "kv"
+			}
+		}
+		if _, ok := extract.BaseDecayRates[sf.Type]; !ok {
+			return nil, fmt.Errorf("facts[%d].type: unknown fact type %q", i, sf.Type)
+		}
+		if sf.Confidence == 0 {
+			sf.Confidence = f.Defaults.Confidence
+		}
+		if sf.Confidence < 0 || sf.Confidence > 1 {
+			return nil, fmt.Errorf("facts[%d].confidence: must be between 0 and 1, got %g", i, sf.Confidence)
+		}
+		if sf.Project == "" {
+			sf.Project = f.Project
+		}
+		if sf.Agent == "" {
+			sf.Agent = f.Agent
+		}
+	}
+	return &f, nil
+}
+
+// Store is the subset of SQLiteStore seeding needs.
+type Store interface {
+	FindByHash(ctx context.Context, hash string) (*store.Memory, error)
+	ListMemories(ctx context.Context, opts store.ListOpts) ([]*store.Memory, error)
+	AddMemory(ctx context.Context, m *store.Memory) (int64, error)
+	AddFact(ctx context.Context, f *store.Fact) (int64, error)
+	GetFactsByMemoryIDs(ctx context.Context, memoryIDs []int64) ([]*store.Fact, error)
+	GetFactsByMemoryIDsIncludingSuperseded(ctx context.Context, memoryIDs []int64) ([]*store.Fact, error)
+	SupersedeFactWithSource(ctx context.Context, oldFactID, newFactID int64, reason, source string) error
+}
+
+// Actions reported per seeded fact.
+const (
+	ActionAdded     = "added"
+	ActionUpdated   = "updated" // superseded an earlier value from the same seed
+	ActionUnchanged = "unchanged"
+)
+
+// Outcome is what Apply did with one seeded fact.
+type Outcome struct {
+	Subject    string  `json:"subject"`
+	Predicate  string  `json:"predicate"`
+	Object     string  `json:"object"`
+	Action     string  `json:"action"`
+	FactID     int64   `json:"fact_id,omitempty"`
+	Superseded []int64 `json:"superseded,omitempty"`
+}
+
+// Result summarizes Apply.
+type Result struct {
+	Source    string    `json:"source"`
+	DryRun    bool      `json:"dry_run,omitempty"`
+	Added     int       `json:"added"`
+	Updated   int       `json:"updated"`
+	Unchanged int       `json:"unchanged"`
+	Facts     []Outcome `json:"facts"`
+}
+
+// Content renders the one-line memory a seeded fact is stored under.
+func (sf Fact) Content() string {
+	return fmt.Sprintf("%s %s %s", sf.Subject, sf.Predicate, sf.Object)
+}
+
+func factKey(subject, predicate string) string {
+	return strings.ToLower(subject) + "\x00" + strings.ToLower(store.CanonicalPredicate(predicate))
+}
+
+// Apply stores the seed's facts. A fact whose memory already exists is
+// unchanged unless its fact was superseded since, in which case it is
+// restored. A new fact supersedes earlier facts from the same seed with the
+// same subject and predicate whose object the file no longer lists. With
+// dryRun nothing is written.
+func Apply(ctx context.Context, st Store, f *File, dryRun bool) (*Result, error) {
+	source := SourcePrefix + f.Name
+	res := &Result{Source: source, DryRun: dryRun, Facts: make([]Outcome, 0, len(f.Facts))}
+
+	// Active facts this seed stored on earlier runs, by subject+predicate.
+	existing, err := st.ListMemories(ctx, store.ListOpts{SourceFile: source, Limit: 1 << 20})
+	if err != nil {
+		return nil, fmt.Errorf("listing seed memories: %w", err)
+	}
+	prior := map[string][]*store.Fact{}
+	if len(existing) > 0 {
+		ids := make([]int64, len(existing))
+		for i, m := range existing {
+			ids[i] = m.ID
+		}
+		facts, err := st.GetFactsByMemoryIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("loading seed facts: %w", err)
+		}
+		for _, fact := range facts {
+			if fact.State == store.FactStateActive || fact.State == store.FactStateCore {
+				k := factKey(fact.Subject, fact.Predicate)
+				prior[k] = append(prior[k], fact)
+			}
+		}
+	}
+	// Objects the file lists per subject+predicate: multi-valued predicates
+	// ("likes: coffee", "likes: tea") must not supersede each other.
+	listed := map[string]map[string]bool{}
+	for _, sf := range f.Facts {
+		k := factKey(sf.Subject, sf.Predicate)
+		if listed[k] == nil {
+			listed[k] = map[string]bool{}
+		}
+		listed[k][strings.ToLower(sf.Object)] = true
+	}
+
+	for _, sf := range f.Facts {
+		out := Outcome{Subject: sf.Subject, Predicate: sf.Predicate, Object: sf.Object}
+		content := sf.Content()
+		mem, err := st.FindByHash(ctx, store.HashMemoryContent(content, source))
+		if err != nil {
+			return nil, err
+		}
+		if mem != nil {
+			facts, err := st.GetFactsByMemoryIDsIncludingSuperseded(ctx, []int64{mem.ID})
+			if err != nil {
+				return nil, fmt.Errorf("loading seed facts: %w", err)
+			}
+			// A memory without facts was corroborated onto an equal fact
+			// from another source on an earlier run; seeding it again
+			// would only inflate that fact's confidence.
+			if id := activeFactID(facts); id > 0 || len(facts) == 0 {
+				out.Action, out.FactID = ActionUnchanged, id
+				res.Unchanged++
+				res.Facts = append(res.Facts, out)
+				continue
+			}
+		}
+
+		k := factKey(sf.Subject, sf.Predicate)
+		var stale []*store.Fact
+		for _, old := range prior[k] {
+			if !listed[k][strings.ToLower(old.Object)] {
+				stale = append(stale, old)
+			}
+		}
+		out.Action = ActionAdded
+		if len(stale) > 0 {
+			out.Action = ActionUpdated
+		}
+		if dryRun {
+			for _, old := range stale {
+				out.Superseded = append(out.Superseded, old.ID)
+			}
+			res.count(out.Action)
+			res.Facts = append(res.Facts, out)
+			continue
+		}
+
+		if mem == nil {
+			mem = &store.Memory{
+				Content:       content,
+				SourceFile:    source,
+				SourceSection: sf.Subject,
+				Project:       sf.Project,
+				MemoryClass:   sf.Class,
+			}
+			if sf.Agent != "" {
+				mem.Metadata = &store.Metadata{AgentID: sf.Agent}
+			}
+			if _, err := st.AddMemory(ctx, mem); err != nil {
+				return nil, fmt.Errorf("storing seed memory for %q: %w", content, err)
+			}
+		}
+		fact := &store.Fact{
+			MemoryID:    mem.ID,
+			Subject:     sf.Subject,
+			Predicate:   sf.Predicate,
+			Object:      sf.Object,
+			FactType:    sf.Type,
+			Confidence:  sf.Confidence,
+			DecayRate:   extract.DecayRates[sf.Type],
+			SourceQuote: content,
+			AgentID:     sf.Agent,
+			ProjectID:   sf.Project,
+		}
+		if sf.Core {
+			fact.State = store.FactStateCore
+		}
+		id, err := st.AddFact(ctx, fact)
+		if err != nil {
+			return nil, fmt.Errorf("storing seed fact %q: %w", content, err)
+		}
+		out.FactID = id
+		for _, old := range stale {
+			if old.ID == id {
+				continue
+			}
+			if err := st.SupersedeFactWithSource(ctx, old.ID, id, "seed updated", "seed:apply"); err != nil {
+				return nil, err
+			}
+			out.Superseded = append(out.Superseded, old.ID)
+		}
+		// Once superseded, a stale fact must not be superseded again by a
+		// later entry with the same subject and predicate.
+		prior[k] = remaining(prior[k], stale)
+		res.count(out.Action)
+		res.Facts = append(res.Facts, out)
+	}
+	return res, nil
+}
+
+func (r *Result) count(action string) {
+	switch action {
+	case ActionAdded:
+		r.Added++
+	case ActionUpdated:
+		r.Updated++
+	}
+}
+
+func activeFactID(facts []*store.Fact) int64 {
+	for _, f := range facts {
+		if f.State == store.FactStateActive || f.State == store.FactStateCore {
+			return f.ID
+		}
+	}
+	return 0
+}
+
+func remaining(facts, drop []*store.Fact) []*store.Fact {
+	var out []*store.Fact
+	for _, f := range facts {
+		keep := true
+		for _, d := range drop {
+			if d.ID == f.ID {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			out = append(out, f)
+		}
+	}
+	return out
+}
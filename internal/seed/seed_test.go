@@ -0,0 +1,175 @@
+package seed
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const profileYAML = `name: profile
+agent: main
+defaults:
+  confidence: 0.95
+facts:
+  - subject: Q
+    predicate: timezone
+    object: America/New_York
+    class: identity
+    core: true
+  - subject: Q
+    predicate: prefers
+    object: dark mode
+    class: preference
+  - subject: Q
+    predicate: drinks
+    object: coffee
+  - subject: Q
+    predicate: drinks
+    object: tea
+  - subject: deploys
+    predicate: require
+    object: a passing CI run
+    class: rule
+    confidence: 1
+`
+
+func newSeedStore(t *testing.T) *store.SQLiteStore {
+	t.Helper()
+	s, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s.(*store.SQLiteStore)
+}
+
+func TestParse_Defaults(t *testing.T) {
+	f, err := Parse([]byte(profileYAML))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(f.Facts) != 5 {
+		t.Fatalf("expected 5 facts, got %d", len(f.Facts))
+	}
+	tz, prefers, coffee, rule := f.Facts[0], f.Facts[1], f.Facts[2], f.Facts[4]
+	if tz.Type != "identity" || !tz.Core || tz.Confidence != 0.95 || tz.Agent != "main" {
+		t.Fatalf("unexpected identity fact: %+v", tz)
+	}
+	if prefers.Type != "preference" || coffee.Type != "kv" || coffee.Class != "" {
+		t.Fatalf("unexpected class defaults: %+v / %+v", prefers, coffee)
+	}
+	if rule.Type != "kv" || rule.Confidence != 1 {
+		t.Fatalf("unexpected rule fact: %+v", rule)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for name, doc := range map[string]string{
+		"empty":          "name: x\n",
+		"missing object": "facts:\n  - subject: a\n    predicate: b\n",
+		"bad class":      "facts:\n  - {subject: a, predicate: b, object: c, class: hobby}\n",
+		"bad type":       "facts:\n  - {subject: a, predicate: b, object: c, type: vibe}\n",
+		"bad confidence": "facts:\n  - {subject: a, predicate: b, object: c, confidence: 2}\n",
+		"unknown key":    "facts:\n  - {subject: a, predicate: b, object: c, confidnce: 0.5}\n",
+	} {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoad_NameFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "team-rules.yaml")
+	if err := os.WriteFile(path, []byte("facts:\n  - {subject: a, predicate: b, object: c}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if f.Name != "team-rules" {
+		t.Fatalf("Name = %q", f.Name)
+	}
+}
+
+func TestApply_IdempotentAndSupersedes(t *testing.T) {
+	ctx := context.Background()
+	st := newSeedStore(t)
+	f, err := Parse([]byte(profileYAML))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	dry, err := Apply(ctx, st, f, true)
+	if err != nil || dry.Added != 5 || !dry.DryRun {
+		t.Fatalf("dry run = %+v, %v", dry, err)
+	}
+	if n, _ := st.ListFacts(ctx, store.ListOpts{Limit: 100}); len(n) != 0 {
+		t.Fatalf("dry run wrote %d facts", len(n))
+	}
+
+	res, err := Apply(ctx, st, f, false)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if res.Added != 5 || res.Source != "seed:profile" {
+		t.Fatalf("first apply = %+v", res)
+	}
+	tz, err := st.GetFact(ctx, res.Facts[0].FactID)
+	if err != nil || tz == nil {
+		t.Fatalf("GetFact: %v", err)
+	}
+	if tz.State != store.FactStateCore || tz.FactType != "identity" || tz.Confidence != 0.95 || tz.AgentID != "main" {
+		t.Fatalf("unexpected stored fact: %+v", tz)
+	}
+	mem, _ := st.GetMemory(ctx, tz.MemoryID)
+	if mem == nil || mem.MemoryClass != "identity" || mem.SourceFile != "seed:profile" {
+		t.Fatalf("unexpected seed memory: %+v", mem)
+	}
+
+	again, err := Apply(ctx, st, f, false)
+	if err != nil || again.Unchanged != 5 || again.Added+again.Updated != 0 {
+		t.Fatalf("re-apply should change nothing, got %+v, %v", again, err)
+	}
+
+	// Moving to a new timezone supersedes the old value; tea and coffee,
+	// both still listed, stay.
+	changed, err := Parse([]byte(strings.Replace(profileYAML, "America/New_York", "Europe/Lisbon", 1)))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	res, err = Apply(ctx, st, changed, false)
+	if err != nil {
+		t.Fatalf("Apply (changed): %v", err)
+	}
+	if res.Updated != 1 || res.Unchanged != 4 || len(res.Facts[0].Superseded) != 1 || res.Facts[0].Superseded[0] != tz.ID {
+		t.Fatalf("expected the timezone to be superseded, got %+v", res)
+	}
+	old, _ := st.GetFact(ctx, tz.ID)
+	if old.SupersededBy == nil || *old.SupersededBy != res.Facts[0].FactID {
+		t.Fatalf("old timezone not superseded: %+v", old)
+	}
+
+	// Reverting restores the original value over the new one.
+	res, err = Apply(ctx, st, f, false)
+	if err != nil {
+		t.Fatalf("Apply (revert): %v", err)
+	}
+	if res.Updated != 1 || res.Facts[0].Action != ActionUpdated {
+		t.Fatalf("expected the reverted timezone to be restored, got %+v", res)
+	}
+	active, _ := st.ListFacts(ctx, store.ListOpts{Limit: 100})
+	objects := map[string]int{}
+	for _, fact := range active {
+		if fact.Predicate == res.Facts[0].Predicate || strings.EqualFold(fact.Predicate, "timezone") {
+			objects[fact.Object]++
+		}
+	}
+	if objects["America/New_York"] != 1 || objects["Europe/Lisbon"] != 0 {
+		t.Fatalf("expected only the restored timezone active, got %v", objects)
+	}
+}
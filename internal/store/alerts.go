@@ -14,9 +14,13 @@ import (
 type AlertType string
 
 const (
-	AlertTypeConflict AlertType = "conflict"
-	AlertTypeDecay    AlertType = "decay"
-	AlertTypeMatch    AlertType = "match" // For future watch queries (#164)
+	AlertTypeConflict  AlertType = "conflict"
+	AlertTypeDecay     AlertType = "decay"
+	AlertTypeMatch     AlertType = "match" // For future watch queries (#164)
+	AlertTypeIntegrity AlertType = "integrity"
+	// AlertTypeSubscription reports a change to facts about a subscribed
+	// subject (see CreateSubscription).
+	AlertTypeSubscription AlertType = "subscription"
 )
 
 // AlertSeverity represents the urgency of an alert.
@@ -77,9 +81,17 @@ func (s *SQLiteStore) CreateAlert(ctx context.Context, alert *Alert) error {
 	alert.ID = id
 	alert.CreatedAt = now
 
-	// Fire webhook notification (non-blocking, best-effort)
+	// Fire webhook notification (non-blocking, best-effort). Alerts about
+	// facts in no-export projects stay local.
 	if s.Webhook != nil {
-		s.Webhook.Notify(alert)
+		if allowed, err := s.factsAllow(ctx, PrivacyNoExport, alert.FactID, alert.RelatedFactID); err == nil && allowed {
+			s.Webhook.Notify(alert)
+		}
+	}
+	if s.OnAlert != nil {
+		if allowed, err := s.factsAllow(ctx, PrivacyNoMCP, alert.FactID, alert.RelatedFactID); err == nil && allowed {
+			s.OnAlert(alert)
+		}
 	}
 
 	return nil
@@ -111,7 +123,7 @@ func (s *SQLiteStore) ListAlerts(ctx context.Context, filter AlertFilter) ([]Ale
 	}
 	if filter.SinceCreatedAt != nil {
 		conditions = append(conditions, "created_at > ?")
-		args = append(args, filter.SinceCreatedAt.UTC().Format(time.RFC3339))
+		args = append(args, filter.SinceCreatedAt.UTC())
 	}
 
 	where := ""
@@ -128,7 +140,9 @@ func (s *SQLiteStore) ListAlerts(ctx context.Context, filter AlertFilter) ([]Ale
 	query := fmt.Sprintf(
 		`SELECT id, alert_type, severity, fact_id, related_fact_id, agent_id,
 		        message, details, acknowledged, acknowledged_at, created_at
-		 FROM alerts %s ORDER BY created_at DESC LIMIT ?`, where)
+		 FROM alerts %s
+		 ORDER BY CASE severity WHEN 'critical' THEN 0 WHEN 'warning' THEN 1 ELSE 2 END, created_at DESC
+		 LIMIT ?`, where)
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -227,19 +241,24 @@ func (s *SQLiteStore) CheckConflictsForFact(ctx context.Context, fact *Fact) ([]
 	if fact.Subject == "" {
 		return nil, nil
 	}
+	ignored, err := s.ignoredConflictPairs(ctx)
+	if err != nil {
+		return nil, err
+	}
 
+	nsClause, nsArgs := s.factNamespaceFilter(ctx, "id")
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT id, memory_id, subject, predicate, object, fact_type,
-		        confidence, decay_rate, last_reinforced, source_quote, created_at, state, superseded_by, agent_id
+		        confidence, decay_rate, last_reinforced, source_quote, created_at, state, superseded_by, agent_id, negated
 		 FROM facts
 		 WHERE LOWER(subject) = LOWER(?)
-		   AND LOWER(predicate) = LOWER(?)
+		   AND (LOWER(predicate) = LOWER(?) OR negated != ?)
 		   AND id != ?
 		   AND superseded_by IS NULL
 		   AND state NOT IN ('retired', 'superseded')
-		   AND confidence > 0
+		   AND confidence > 0`+nsClause+`
 		 LIMIT 20`,
-		fact.Subject, fact.Predicate, fact.ID,
+		append([]interface{}{fact.Subject, fact.Predicate, fact.Negated, fact.ID}, nsArgs...)...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("checking conflicts for fact: %w", err)
@@ -254,7 +273,7 @@ func (s *SQLiteStore) CheckConflictsForFact(ctx context.Context, fact *Fact) ([]
 			&existing.ID, &existing.MemoryID, &existing.Subject, &existing.Predicate,
 			&existing.Object, &existing.FactType, &existing.Confidence, &existing.DecayRate,
 			&existing.LastReinforced, &existing.SourceQuote, &existing.CreatedAt, &existing.State, &supersededBy,
-			&existing.AgentID,
+			&existing.AgentID, &existing.Negated,
 		); err != nil {
 			return nil, fmt.Errorf("scanning existing fact: %w", err)
 		}
@@ -262,15 +281,35 @@ func (s *SQLiteStore) CheckConflictsForFact(ctx context.Context, fact *Fact) ([]
 			v := supersededBy.Int64
 			existing.SupersededBy = &v
 		}
-
-		// Only conflict if objects differ
-		if strings.EqualFold(existing.Object, fact.Object) {
+		if ignored[conflictPairKey(existing.ID, fact.ID)] {
 			continue
 		}
 
 		crossAgent := fact.AgentID != existing.AgentID &&
 			(fact.AgentID != "" || existing.AgentID != "")
 
+		if existing.Negated != fact.Negated {
+			if polarityKey(existing) == polarityKey(*fact) {
+				conflicts = append(conflicts, Conflict{
+					Fact1:        *fact,
+					Fact2:        existing,
+					ConflictType: "negation",
+					Similarity:   1.0,
+					Severity:     ConflictSeverityHigh,
+					CrossAgent:   crossAgent,
+				})
+				continue
+			}
+			if !strings.EqualFold(existing.Predicate, fact.Predicate) {
+				continue
+			}
+		}
+
+		// Only conflict if objects differ
+		if strings.EqualFold(existing.Object, fact.Object) {
+			continue
+		}
+
 		conflicts = append(conflicts, Conflict{
 			Fact1:        *fact,
 			Fact2:        existing,
@@ -283,6 +322,66 @@ func (s *SQLiteStore) CheckConflictsForFact(ctx context.Context, fact *Fact) ([]
 	return conflicts, rows.Err()
 }
 
+// ConflictAlertDetail is the JSON detail stored with each conflict alert.
+type ConflictAlertDetail struct {
+	ConflictType  string  `json:"conflict_type"`
+	SeverityScore float64 `json:"severity_score"`
+	Reasons       string  `json:"reasons,omitempty"`
+	Fact1         string  `json:"fact1"`
+	Fact2         string  `json:"fact2"`
+	CrossAgent    bool    `json:"cross_agent,omitempty"`
+}
+
+// RecordConflictAlert creates a critical alert for a conflict pair unless an
+// unacknowledged conflict alert for the same two facts already exists.
+// Returns whether an alert was created.
+func (s *SQLiteStore) RecordConflictAlert(ctx context.Context, c Conflict, score float64, reasons string) (bool, error) {
+	var existing int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM alerts
+		 WHERE alert_type = 'conflict' AND acknowledged = 0
+		   AND ((fact_id = ? AND related_fact_id = ?) OR (fact_id = ? AND related_fact_id = ?))`,
+		c.Fact1.ID, c.Fact2.ID, c.Fact2.ID, c.Fact1.ID,
+	).Scan(&existing); err != nil {
+		return false, fmt.Errorf("checking existing conflict alerts: %w", err)
+	}
+	if existing > 0 {
+		return false, nil
+	}
+
+	text := func(f Fact) string {
+		return strings.TrimSpace(fmt.Sprintf("%s %s %s", f.Subject, f.Predicate, truncate(f.Object, 40)))
+	}
+	detail, _ := json.Marshal(ConflictAlertDetail{
+		ConflictType:  c.ConflictType,
+		SeverityScore: score,
+		Reasons:       reasons,
+		Fact1:         text(c.Fact1),
+		Fact2:         text(c.Fact2),
+		CrossAgent:    c.CrossAgent,
+	})
+	fact1, fact2 := c.Fact1.ID, c.Fact2.ID
+	agent := ""
+	if c.Fact1.AgentID == c.Fact2.AgentID {
+		agent = c.Fact1.AgentID
+	}
+	alert := &Alert{
+		AlertType:     AlertTypeConflict,
+		Severity:      AlertSeverityCritical,
+		FactID:        &fact1,
+		RelatedFactID: &fact2,
+		AgentID:       agent,
+		Message: fmt.Sprintf("Critical %s conflict (severity %.2f): #%d \"%s\" vs #%d \"%s\"",
+			c.ConflictType, score, fact1, text(c.Fact1), fact2, text(c.Fact2)),
+		Details: string(detail),
+	}
+	if err := s.CreateAlert(ctx, alert); err != nil {
+		return false, err
+	}
+	s.notifySubscribers(ctx, SubscriptionEventConflict, &c.Fact1, &c.Fact2)
+	return true, nil
+}
+
 // DecayThresholds configures when decay alerts fire.
 type DecayThresholds struct {
 	Warning  float64 // Alert when effective confidence drops below this (default 0.5)
@@ -319,17 +418,19 @@ type DecayFactDetail struct {
 	DaysSinceReinforced float64 `json:"days_since_reinforced"`
 }
 
-// CheckDecayAlerts scans all active facts, computes effective confidence via
+// CheckDecayAlerts scans all live facts, computes effective confidence via
 // Ebbinghaus decay, and creates alerts for facts that have crossed the thresholds.
-// It deduplicates: if a fact already has an unacknowledged decay alert, it won't create another.
+// Retired facts are skipped. It deduplicates: if a fact already has an
+// unacknowledged decay alert, it won't create another.
 func (s *SQLiteStore) CheckDecayAlerts(ctx context.Context, thresholds DecayThresholds) (*DecayAlertResult, error) {
-	// Get all active facts with their decay parameters
+	// Get all live facts with their decay parameters
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT id, memory_id, subject, predicate, object, fact_type,
 		        confidence, decay_rate, last_reinforced, source_quote, created_at, state, agent_id
 		 FROM facts
 		 WHERE superseded_by IS NULL
-		   AND confidence > 0`)
+		   AND state != ?
+		   AND confidence > 0`, FactStateRetired)
 	if err != nil {
 		return nil, fmt.Errorf("querying facts for decay check: %w", err)
 	}
@@ -209,6 +209,21 @@ func TestAlertFilterByType(t *testing.T) {
 	if len(alerts) != 1 {
 		t.Fatalf("Expected 1 decay alert, got %d", len(alerts))
 	}
+
+	// Unfiltered listing puts the most severe first
+	alerts, _ = s.ListAlerts(ctx, AlertFilter{})
+	if len(alerts) != 3 || alerts[0].Message != "conflict2" || alerts[1].Message != "decay1" {
+		t.Fatalf("Expected alerts ordered by severity, got %+v", alerts)
+	}
+
+	// Since filters on creation time within the same day
+	hourAgo, inAMinute := time.Now().Add(-time.Hour), time.Now().Add(time.Minute)
+	if alerts, _ = s.ListAlerts(ctx, AlertFilter{SinceCreatedAt: &hourAgo}); len(alerts) != 3 {
+		t.Fatalf("Expected 3 alerts in the last hour, got %d", len(alerts))
+	}
+	if alerts, _ = s.ListAlerts(ctx, AlertFilter{SinceCreatedAt: &inAMinute}); len(alerts) != 0 {
+		t.Fatalf("Expected no future alerts, got %d", len(alerts))
+	}
 }
 
 // --- Decay Notification Tests ---
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ChunkAnchor locates a memory's chunk in its source file: the byte range
+// [ByteStart, ByteEnd) it was cut from and the full heading path above it
+// ("Title > Section > Subsection"), so tools can deep-link and quote the
+// original text exactly. Memories imported before anchors were recorded,
+// and chunks whose format has no stable byte layout (JSON, YAML, CSV), have
+// none.
+type ChunkAnchor struct {
+	ByteStart   int64  `json:"byte_start"`
+	ByteEnd     int64  `json:"byte_end"`
+	HeadingPath string `json:"heading_path,omitempty"`
+}
+
+// migrateMemoryAnchors adds memory_anchors, one row per anchored memory.
+func (s *SQLiteStore) migrateMemoryAnchors() error {
+	done, err := s.isMetaFlagEnabled("memory_anchors_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS memory_anchors (
+			memory_id    INTEGER PRIMARY KEY REFERENCES memories(id) ON DELETE CASCADE,
+			byte_start   INTEGER NOT NULL,
+			byte_end     INTEGER NOT NULL,
+			heading_path TEXT NOT NULL DEFAULT ''
+		)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('memory_anchors_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("migrating memory anchors: %w", err)
+		}
+	}
+	return nil
+}
+
+// insertMemoryAnchor stores a new memory's anchor in the transaction that
+// inserted the memory. A nil anchor is a no-op.
+func insertMemoryAnchor(ctx context.Context, tx *sql.Tx, memoryID int64, a *ChunkAnchor) error {
+	if a == nil {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT OR REPLACE INTO memory_anchors (memory_id, byte_start, byte_end, heading_path) VALUES (?, ?, ?, ?)`,
+		memoryID, a.ByteStart, a.ByteEnd, a.HeadingPath,
+	); err != nil {
+		return fmt.Errorf("inserting memory anchor: %w", err)
+	}
+	return nil
+}
+
+// GetMemoryAnchors returns the anchors of the given memories, keyed by
+// memory ID. Memories without an anchor are absent from the map.
+func (s *SQLiteStore) GetMemoryAnchors(ctx context.Context, memoryIDs []int64) (map[int64]ChunkAnchor, error) {
+	anchors := make(map[int64]ChunkAnchor, len(memoryIDs))
+	for start := 0; start < len(memoryIDs); start += 500 {
+		end := start + 500
+		if end > len(memoryIDs) {
+			end = len(memoryIDs)
+		}
+		batch := memoryIDs[start:end]
+		args := make([]interface{}, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT memory_id, byte_start, byte_end, heading_path FROM memory_anchors
+			 WHERE memory_id IN (?`+strings.Repeat(",?", len(batch)-1)+`)`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("querying memory anchors: %w", err)
+		}
+		for rows.Next() {
+			var id int64
+			var a ChunkAnchor
+			if err := rows.Scan(&id, &a.ByteStart, &a.ByteEnd, &a.HeadingPath); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scanning memory anchor: %w", err)
+			}
+			anchors[id] = a
+		}
+		if err := rows.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return anchors, nil
+}
+
+// AttachMemoryAnchors fills Anchor on each memory that has one.
+func (s *SQLiteStore) AttachMemoryAnchors(ctx context.Context, memories []*Memory) error {
+	ids := make([]int64, 0, len(memories))
+	for _, m := range memories {
+		if m != nil {
+			ids = append(ids, m.ID)
+		}
+	}
+	anchors, err := s.GetMemoryAnchors(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, m := range memories {
+		if m == nil {
+			continue
+		}
+		if a, ok := anchors[m.ID]; ok {
+			m.Anchor = &a
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryAnchors_RoundTrip(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	anchored, err := s.AddMemory(ctx, &Memory{
+		Content:    "deploys happen on Tuesday",
+		SourceFile: "/notes.md",
+		Anchor:     &ChunkAnchor{ByteStart: 12, ByteEnd: 37, HeadingPath: "Runbook > Deploys"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, _ := s.AddMemory(ctx, &Memory{Content: "no anchor here", SourceFile: "/notes.json"})
+
+	anchors, err := s.GetMemoryAnchors(ctx, []int64{anchored, plain})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anchors) != 1 {
+		t.Fatalf("anchors = %+v, want only the anchored memory", anchors)
+	}
+	if a := anchors[anchored]; a.ByteStart != 12 || a.ByteEnd != 37 || a.HeadingPath != "Runbook > Deploys" {
+		t.Fatalf("anchor = %+v", a)
+	}
+
+	a, _ := s.GetMemory(ctx, anchored)
+	p, _ := s.GetMemory(ctx, plain)
+	if err := s.AttachMemoryAnchors(ctx, []*Memory{a, p}); err != nil {
+		t.Fatal(err)
+	}
+	if a.Anchor == nil || a.Anchor.HeadingPath != "Runbook > Deploys" || p.Anchor != nil {
+		t.Fatalf("attached anchors: %+v / %+v", a.Anchor, p.Anchor)
+	}
+}
+
+func TestMemoryAnchors_BatchInsert(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	ids, err := s.AddMemoryBatch(ctx, []*Memory{
+		{Content: "first chunk", SourceFile: "/a.md", Anchor: &ChunkAnchor{ByteStart: 0, ByteEnd: 11}},
+		{Content: "second chunk", SourceFile: "/a.md", Anchor: &ChunkAnchor{ByteStart: 13, ByteEnd: 25, HeadingPath: "A"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	anchors, err := s.GetMemoryAnchors(ctx, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anchors) != 2 || anchors[ids[1]].ByteStart != 13 || anchors[ids[1]].HeadingPath != "A" {
+		t.Fatalf("batch anchors = %+v", anchors)
+	}
+}
@@ -0,0 +1,263 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Annotation target types.
+const (
+	AnnotationTargetFact   = "fact"
+	AnnotationTargetMemory = "memory"
+)
+
+// Annotation is a free-text note attached to a fact or memory by a person
+// or agent ("verified manually 2026-02"). Label is an optional short tag
+// such as "verified" or "disputed".
+type Annotation struct {
+	ID         int64     `json:"id"`
+	TargetType string    `json:"target_type"`
+	TargetID   int64     `json:"target_id"`
+	Body       string    `json:"body"`
+	Label      string    `json:"label,omitempty"`
+	Author     string    `json:"author,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (s *SQLiteStore) migrateAnnotationsTable() error {
+	done, err := s.isMetaFlagEnabled("annotations_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS annotations (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			target_type TEXT NOT NULL CHECK (target_type IN ('fact', 'memory')),
+			target_id   INTEGER NOT NULL,
+			body        TEXT NOT NULL,
+			label       TEXT NOT NULL DEFAULT '',
+			author      TEXT NOT NULL DEFAULT '',
+			created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_annotations_target ON annotations(target_type, target_id)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('annotations_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating annotations table: %w", err)
+		}
+	}
+	return nil
+}
+
+// AddAnnotation attaches a note to an existing fact or memory and returns
+// its id.
+func (s *SQLiteStore) AddAnnotation(ctx context.Context, a *Annotation) (int64, error) {
+	body := strings.TrimSpace(a.Body)
+	if body == "" {
+		return 0, fmt.Errorf("note cannot be empty")
+	}
+	switch a.TargetType {
+	case AnnotationTargetFact:
+		f, err := s.GetFact(ctx, a.TargetID)
+		if err != nil {
+			return 0, err
+		}
+		if f == nil {
+			return 0, fmt.Errorf("fact %d not found", a.TargetID)
+		}
+	case AnnotationTargetMemory:
+		m, err := s.GetMemory(ctx, a.TargetID)
+		if err != nil {
+			return 0, err
+		}
+		if m == nil || m.DeletedAt != nil {
+			return 0, fmt.Errorf("memory %d not found", a.TargetID)
+		}
+	default:
+		return 0, fmt.Errorf("invalid annotation target %q (must be fact or memory)", a.TargetType)
+	}
+
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO annotations (target_type, target_id, body, label, author, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		a.TargetType, a.TargetID, body, strings.ToLower(strings.TrimSpace(a.Label)), strings.TrimSpace(a.Author), time.Now().UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("inserting annotation: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+// ListAnnotations returns the notes on one fact or memory, oldest first.
+func (s *SQLiteStore) ListAnnotations(ctx context.Context, targetType string, targetID int64) ([]Annotation, error) {
+	return s.queryAnnotations(ctx,
+		`SELECT id, target_type, target_id, body, label, author, created_at
+		 FROM annotations WHERE target_type = ? AND target_id = ? ORDER BY id ASC`,
+		targetType, targetID)
+}
+
+// RecentAnnotations returns the newest notes across all targets.
+func (s *SQLiteStore) RecentAnnotations(ctx context.Context, limit int) ([]Annotation, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return s.queryAnnotations(ctx,
+		`SELECT id, target_type, target_id, body, label, author, created_at
+		 FROM annotations ORDER BY id DESC LIMIT ?`, limit)
+}
+
+// AnnotationsForTargets returns notes for the given memories and facts in
+// one pass, keyed by "fact:<id>" / "memory:<id>" (see AnnotationKey).
+func (s *SQLiteStore) AnnotationsForTargets(ctx context.Context, memoryIDs, factIDs []int64) (map[string][]Annotation, error) {
+	out := map[string][]Annotation{}
+	for _, group := range []struct {
+		targetType string
+		ids        []int64
+	}{
+		{AnnotationTargetMemory, memoryIDs},
+		{AnnotationTargetFact, factIDs},
+	} {
+		for start := 0; start < len(group.ids); start += 500 {
+			end := start + 500
+			if end > len(group.ids) {
+				end = len(group.ids)
+			}
+			chunk := group.ids[start:end]
+			placeholders := strings.TrimSuffix(strings.Repeat("?,", len(chunk)), ",")
+			args := []interface{}{group.targetType}
+			for _, id := range chunk {
+				args = append(args, id)
+			}
+			notes, err := s.queryAnnotations(ctx,
+				`SELECT id, target_type, target_id, body, label, author, created_at
+				 FROM annotations WHERE target_type = ? AND target_id IN (`+placeholders+`) ORDER BY id ASC`,
+				args...)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range notes {
+				key := AnnotationKey(n.TargetType, n.TargetID)
+				out[key] = append(out[key], n)
+			}
+		}
+	}
+	return out, nil
+}
+
+// AnnotationsForMemories returns, per memory id, the notes on that memory
+// followed by the notes on facts extracted from it.
+func (s *SQLiteStore) AnnotationsForMemories(ctx context.Context, memoryIDs []int64) (map[int64][]Annotation, error) {
+	out := map[int64][]Annotation{}
+	for start := 0; start < len(memoryIDs); start += 500 {
+		end := start + 500
+		if end > len(memoryIDs) {
+			end = len(memoryIDs)
+		}
+		chunk := memoryIDs[start:end]
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(chunk)), ",")
+		args := make([]interface{}, 0, 2*len(chunk))
+		for _, id := range chunk {
+			args = append(args, id)
+		}
+		args = append(args, args...)
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT a.target_id, a.id, a.target_type, a.target_id, a.body, a.label, a.author, a.created_at, 0 AS ord
+			 FROM annotations a
+			 WHERE a.target_type = 'memory' AND a.target_id IN (`+placeholders+`)
+			 UNION ALL
+			 SELECT f.memory_id, a.id, a.target_type, a.target_id, a.body, a.label, a.author, a.created_at, 1 AS ord
+			 FROM annotations a JOIN facts f ON f.id = a.target_id
+			 WHERE a.target_type = 'fact' AND f.memory_id IN (`+placeholders+`)
+			 ORDER BY ord, id`,
+			args...)
+		if err != nil {
+			return nil, fmt.Errorf("querying annotations: %w", err)
+		}
+		for rows.Next() {
+			var memoryID int64
+			var ord int
+			var a Annotation
+			if err := rows.Scan(&memoryID, &a.ID, &a.TargetType, &a.TargetID, &a.Body, &a.Label, &a.Author, &a.CreatedAt, &ord); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scanning annotation: %w", err)
+			}
+			out[memoryID] = append(out[memoryID], a)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// AnnotationKey is the map key used by AnnotationsForTargets.
+func AnnotationKey(targetType string, targetID int64) string {
+	return fmt.Sprintf("%s:%d", targetType, targetID)
+}
+
+// SearchAnnotations finds notes whose body or label contains every word of
+// query (case-insensitive), newest first.
+func (s *SQLiteStore) SearchAnnotations(ctx context.Context, query string, limit int) ([]Annotation, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search query cannot be empty")
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	var where []string
+	var args []interface{}
+	for _, term := range terms {
+		where = append(where, `(LOWER(body) LIKE ? ESCAPE '\' OR LOWER(label) LIKE ? ESCAPE '\')`)
+		pattern := "%" + escapeLikePattern(term) + "%"
+		args = append(args, pattern, pattern)
+	}
+	args = append(args, limit)
+	return s.queryAnnotations(ctx,
+		`SELECT id, target_type, target_id, body, label, author, created_at
+		 FROM annotations WHERE `+strings.Join(where, " AND ")+` ORDER BY id DESC LIMIT ?`,
+		args...)
+}
+
+// DeleteAnnotation removes a note.
+func (s *SQLiteStore) DeleteAnnotation(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM annotations WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("deleting note %d: %w", id, err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("note %d not found", id)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) queryAnnotations(ctx context.Context, query string, args ...interface{}) ([]Annotation, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying annotations: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Annotation
+	for rows.Next() {
+		var a Annotation
+		if err := rows.Scan(&a.ID, &a.TargetType, &a.TargetID, &a.Body, &a.Label, &a.Author, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning annotation: %w", err)
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
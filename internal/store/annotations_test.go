@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAnnotations_AddListSearchDelete(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+
+	if err := s.migrateAnnotationsTable(); err != nil {
+		t.Fatalf("second migrateAnnotationsTable call failed: %v", err)
+	}
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "Q lives in Philadelphia"})
+	factID, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "Q", Predicate: "lives_in", Object: "Philadelphia", FactType: "location"})
+
+	factNote, err := s.AddAnnotation(ctx, &Annotation{TargetType: AnnotationTargetFact, TargetID: factID, Body: " verified manually 2026-02 ", Label: "Verified", Author: "q"})
+	if err != nil {
+		t.Fatalf("AddAnnotation(fact): %v", err)
+	}
+	if _, err := s.AddAnnotation(ctx, &Annotation{TargetType: AnnotationTargetMemory, TargetID: memID, Body: "imported from old notes_app"}); err != nil {
+		t.Fatalf("AddAnnotation(memory): %v", err)
+	}
+
+	if _, err := s.AddAnnotation(ctx, &Annotation{TargetType: AnnotationTargetFact, TargetID: 9999, Body: "x"}); err == nil {
+		t.Fatal("expected error for missing fact")
+	}
+	if _, err := s.AddAnnotation(ctx, &Annotation{TargetType: "entity", TargetID: factID, Body: "x"}); err == nil {
+		t.Fatal("expected error for invalid target type")
+	}
+	if _, err := s.AddAnnotation(ctx, &Annotation{TargetType: AnnotationTargetFact, TargetID: factID, Body: "  "}); err == nil {
+		t.Fatal("expected error for empty body")
+	}
+
+	notes, err := s.ListAnnotations(ctx, AnnotationTargetFact, factID)
+	if err != nil {
+		t.Fatalf("ListAnnotations: %v", err)
+	}
+	if len(notes) != 1 || notes[0].Body != "verified manually 2026-02" || notes[0].Label != "verified" || notes[0].Author != "q" {
+		t.Fatalf("unexpected fact notes: %+v", notes)
+	}
+
+	byTarget, err := s.AnnotationsForTargets(ctx, []int64{memID}, []int64{factID, 12345})
+	if err != nil {
+		t.Fatalf("AnnotationsForTargets: %v", err)
+	}
+	if len(byTarget[AnnotationKey(AnnotationTargetFact, factID)]) != 1 || len(byTarget[AnnotationKey(AnnotationTargetMemory, memID)]) != 1 {
+		t.Fatalf("unexpected notes by target: %+v", byTarget)
+	}
+
+	byMemory, err := s.AnnotationsForMemories(ctx, []int64{memID})
+	if err != nil {
+		t.Fatalf("AnnotationsForMemories: %v", err)
+	}
+	if got := byMemory[memID]; len(got) != 2 || got[0].TargetType != AnnotationTargetMemory || got[1].TargetID != factID {
+		t.Fatalf("expected memory note then fact note, got %+v", got)
+	}
+
+	hits, err := s.SearchAnnotations(ctx, "VERIFIED 2026", 10)
+	if err != nil {
+		t.Fatalf("SearchAnnotations: %v", err)
+	}
+	if len(hits) != 1 || hits[0].ID != factNote {
+		t.Fatalf("expected the fact note, got %+v", hits)
+	}
+	if hits, _ := s.SearchAnnotations(ctx, "notes_", 10); len(hits) != 1 {
+		t.Fatalf("expected LIKE wildcards to be escaped and match literally, got %+v", hits)
+	}
+	if hits, _ := s.SearchAnnotations(ctx, "verified philadelphia", 10); len(hits) != 0 {
+		t.Fatalf("all terms must match, got %+v", hits)
+	}
+
+	if recent, _ := s.RecentAnnotations(ctx, 1); len(recent) != 1 || recent[0].TargetType != AnnotationTargetMemory {
+		t.Fatalf("expected newest note first, got %+v", recent)
+	}
+
+	if err := s.DeleteAnnotation(ctx, factNote); err != nil {
+		t.Fatalf("DeleteAnnotation: %v", err)
+	}
+	if err := s.DeleteAnnotation(ctx, factNote); err == nil {
+		t.Fatal("expected error deleting a missing note")
+	}
+}
@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Answer confidence bands.
+const (
+	ConfidenceBandHigh    = "high"
+	ConfidenceBandMedium  = "medium"
+	ConfidenceBandLow     = "low"
+	ConfidenceBandUnknown = "unknown" // the evidence holds no facts to judge by
+)
+
+// Thresholds on effective (decayed) confidence. They match the markers the
+// reason context puts on memories: >= 0.8 plain, >= 0.5 ⚡, below ⚠️ STALE.
+const (
+	answerHighConfidence  = 0.8
+	answerStaleConfidence = 0.5
+)
+
+// AnswerConfidence grades the facts an answer rests on.
+type AnswerConfidence struct {
+	Band             string   `json:"band" enum:"high,medium,low,unknown"`
+	Score            float64  `json:"score" doc:"Mean effective confidence of the evidence facts, discounted for conflicts"`
+	Facts            int      `json:"facts"`
+	StaleFacts       []int64  `json:"stale_facts,omitempty" doc:"Evidence facts whose effective confidence has decayed below 0.5"`
+	ConflictingFacts []int64  `json:"conflicting_facts,omitempty" doc:"Evidence facts another active fact contradicts"`
+	Flags            []string `json:"flags,omitempty" enum:"stale,conflicting,no_facts"`
+}
+
+// Summary is a one-line description, e.g.
+// "medium (0.62): 1 of 4 facts stale, 2 in conflict".
+func (c *AnswerConfidence) Summary() string {
+	if c.Band == ConfidenceBandUnknown {
+		return "unknown: no extracted facts behind the cited memories"
+	}
+	out := fmt.Sprintf("%s (%.2f)", c.Band, c.Score)
+	var notes []string
+	if n := len(c.StaleFacts); n > 0 {
+		notes = append(notes, fmt.Sprintf("%d of %d facts stale", n, c.Facts))
+	}
+	if n := len(c.ConflictingFacts); n > 0 {
+		notes = append(notes, fmt.Sprintf("%d in conflict", n))
+	}
+	if len(notes) == 0 {
+		return out + fmt.Sprintf(": %d facts, none stale or conflicting", c.Facts)
+	}
+	return out + ": " + strings.Join(notes, ", ")
+}
+
+// AnswerConfidence grades the active facts extracted from memoryIDs — the
+// memories an answer cites. The score is their mean effective confidence,
+// discounted by the share of facts another active fact contradicts (same
+// subject and predicate, different object, not an ignored pair). The band
+// is at most medium when any evidence is in conflict, and low when most of
+// it is stale.
+func (s *SQLiteStore) AnswerConfidence(ctx context.Context, memoryIDs []int64) (*AnswerConfidence, error) {
+	out := &AnswerConfidence{Band: ConfidenceBandUnknown, Flags: []string{"no_facts"}}
+	facts, err := s.ListFactsByMemoryIDs(ctx, memoryIDs, "", 500)
+	if err != nil {
+		return nil, err
+	}
+	active := facts[:0]
+	for _, f := range facts {
+		if f.State != FactStateRetired && f.State != FactStateSuperseded {
+			active = append(active, f)
+		}
+	}
+	if len(active) == 0 {
+		return out, nil
+	}
+
+	conflicting, err := s.conflictingFacts(ctx, active)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var total float64
+	out.Flags = nil
+	out.Facts = len(active)
+	for _, f := range active {
+		eff := f.Confidence
+		if !f.LastReinforced.IsZero() && f.LastReinforced.Before(now) {
+			eff = EffectiveConfidence(f.Confidence, f.DecayRate, f.LastReinforced)
+		}
+		total += eff
+		if eff < answerStaleConfidence {
+			out.StaleFacts = append(out.StaleFacts, f.ID)
+		}
+		if conflicting[f.ID] {
+			out.ConflictingFacts = append(out.ConflictingFacts, f.ID)
+		}
+	}
+	sort.Slice(out.ConflictingFacts, func(i, j int) bool { return out.ConflictingFacts[i] < out.ConflictingFacts[j] })
+
+	n := float64(len(active))
+	conflictShare := float64(len(out.ConflictingFacts)) / n
+	staleShare := float64(len(out.StaleFacts)) / n
+	out.Score = roundScore(total / n * (1 - conflictShare/2))
+
+	switch {
+	case out.Score >= answerHighConfidence:
+		out.Band = ConfidenceBandHigh
+	case out.Score >= answerStaleConfidence:
+		out.Band = ConfidenceBandMedium
+	default:
+		out.Band = ConfidenceBandLow
+	}
+	if len(out.StaleFacts) > 0 {
+		out.Flags = append(out.Flags, "stale")
+		if staleShare > 0.5 {
+			out.Band = ConfidenceBandLow
+		}
+	}
+	if len(out.ConflictingFacts) > 0 {
+		out.Flags = append(out.Flags, "conflicting")
+		if out.Band == ConfidenceBandHigh {
+			out.Band = ConfidenceBandMedium
+		}
+	}
+	return out, nil
+}
+
+// conflictingFacts returns which of facts another active fact with the same
+// subject and predicate contradicts.
+func (s *SQLiteStore) conflictingFacts(ctx context.Context, facts []*Fact) (map[int64]bool, error) {
+	ignored, err := s.ignoredConflictPairs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	type key struct{ subject, predicate string }
+	byKey := map[key][]*Fact{}
+	for _, f := range facts {
+		if f.Subject == "" {
+			continue
+		}
+		k := key{strings.ToLower(f.Subject), strings.ToLower(f.Predicate)}
+		byKey[k] = append(byKey[k], f)
+	}
+
+	out := map[int64]bool{}
+	for k, cited := range byKey {
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT id, object FROM facts
+			 WHERE LOWER(subject) = ? AND LOWER(predicate) = ?
+			   AND superseded_by IS NULL
+			   AND state NOT IN ('retired', 'superseded')
+			   AND confidence > 0
+			 LIMIT 50`,
+			k.subject, k.predicate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("checking evidence conflicts: %w", err)
+		}
+		type peer struct {
+			id     int64
+			object string
+		}
+		var peers []peer
+		for rows.Next() {
+			var p peer
+			if err := rows.Scan(&p.id, &p.object); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scanning evidence conflicts: %w", err)
+			}
+			p.object = strings.ToLower(strings.TrimSpace(p.object))
+			peers = append(peers, p)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		for _, f := range cited {
+			object := strings.ToLower(strings.TrimSpace(f.Object))
+			for _, p := range peers {
+				if p.id != f.ID && p.object != object && !ignored[conflictPairKey(p.id, f.ID)] {
+					out[f.ID] = true
+					break
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+func roundScore(v float64) float64 {
+	return float64(int(v*100+0.5)) / 100
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnswerConfidence_Bands(t *testing.T) {
+	s := newTestStore(t).(*SQLiteStore)
+	ctx := context.Background()
+
+	addFact := func(memID int64, subject, predicate, object string, conf float64) int64 {
+		t.Helper()
+		id, err := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: subject, Predicate: predicate, Object: object, FactType: "kv", Confidence: conf})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	solid, _ := s.AddMemory(ctx, &Memory{Content: "Deploys happen on Tuesday", SourceFile: "ops.md"})
+	addFact(solid, "deploys", "happen on", "Tuesday", 0.95)
+	addFact(solid, "deploy window", "is", "2pm", 0.9)
+
+	conf, err := s.AnswerConfidence(ctx, []int64{solid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Band != ConfidenceBandHigh || conf.Facts != 2 || len(conf.Flags) != 0 {
+		t.Fatalf("expected high confidence, got %+v", conf)
+	}
+
+	// A newer note says Wednesday: both sides are now in conflict.
+	other, _ := s.AddMemory(ctx, &Memory{Content: "Deploys moved to Wednesday", SourceFile: "chat.md"})
+	wed := addFact(other, "Deploys", "happen on", "Wednesday", 0.9)
+	conf, err = s.AnswerConfidence(ctx, []int64{solid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Band != ConfidenceBandMedium || len(conf.ConflictingFacts) != 1 || conf.Flags[0] != "conflicting" {
+		t.Fatalf("expected a conflicting medium answer, got %+v", conf)
+	}
+	if !strings.Contains(conf.Summary(), "1 in conflict") {
+		t.Fatalf("unexpected summary %q", conf.Summary())
+	}
+
+	// Ignoring the pair clears the conflict.
+	tuesday := conf.ConflictingFacts[0]
+	if _, err := s.IgnoreConflict(ctx, tuesday, wed, "schedule changed per team"); err != nil {
+		t.Fatal(err)
+	}
+	if conf, _ = s.AnswerConfidence(ctx, []int64{solid}); len(conf.ConflictingFacts) != 0 {
+		t.Fatalf("ignored pair still counted: %+v", conf)
+	}
+
+	// Facts last reinforced a year ago have decayed below the stale line.
+	old, _ := s.AddMemory(ctx, &Memory{Content: "Old runbook", SourceFile: "runbook.md"})
+	addFact(old, "runbook", "owner", "Bob", 0.9)
+	addFact(old, "runbook", "location", "wiki", 0.9)
+	if _, err := s.db.ExecContext(ctx, `UPDATE facts SET last_reinforced = ? WHERE memory_id = ?`, time.Now().AddDate(-1, 0, 0), old); err != nil {
+		t.Fatal(err)
+	}
+	conf, err = s.AnswerConfidence(ctx, []int64{old})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Band != ConfidenceBandLow || len(conf.StaleFacts) != 2 || conf.Flags[0] != "stale" {
+		t.Fatalf("expected a stale low answer, got %+v", conf)
+	}
+
+	// Memories without facts cannot be graded.
+	bare, _ := s.AddMemory(ctx, &Memory{Content: "just prose", SourceFile: "notes.md"})
+	if conf, _ = s.AnswerConfidence(ctx, []int64{bare}); conf.Band != ConfidenceBandUnknown {
+		t.Fatalf("expected unknown band, got %+v", conf)
+	}
+}
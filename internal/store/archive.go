@@ -0,0 +1,366 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Archive hold kinds. A hold on a memory also covers every fact extracted
+// from it.
+const (
+	HoldKindMemory = "memory"
+	HoldKindFact   = "fact"
+)
+
+// ErrArchiveHold is returned when a write would delete or rewrite a record
+// under archive hold.
+var ErrArchiveHold = errors.New("under archive hold")
+
+// ArchiveHold is a legal hold placed with `cortex archive set`. Held records
+// cannot be deleted, purged, retired, or have their content rewritten;
+// superseding a held fact is allowed. Every other change is recorded in
+// archive_audit.
+type ArchiveHold struct {
+	Kind      string    `json:"kind"`
+	TargetID  int64     `json:"target_id"`
+	Reason    string    `json:"reason,omitempty"`
+	Summary   string    `json:"summary,omitempty"` // memory preview or fact triple
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ArchiveAuditEntry is one row of the archive audit trail: a hold placed or
+// released, or a change to a held record.
+type ArchiveAuditEntry struct {
+	ID        int64     `json:"id"`
+	Kind      string    `json:"kind"`
+	TargetID  int64     `json:"target_id"`
+	Action    string    `json:"action"` // hold, release, update
+	OldValue  string    `json:"old_value,omitempty"`
+	NewValue  string    `json:"new_value,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Where-clause fragments that leave held rows out of bulk maintenance
+// statements on memories and facts. A memory with a held fact counts as
+// held, since deleting it would delete the fact.
+const (
+	NotHeldMemoryClause = `id NOT IN (SELECT target_id FROM archive_holds WHERE kind = 'memory')
+		AND id NOT IN (SELECT memory_id FROM facts WHERE id IN (SELECT target_id FROM archive_holds WHERE kind = 'fact'))`
+	NotHeldFactClause = `id NOT IN (SELECT target_id FROM archive_holds WHERE kind = 'fact')
+		AND memory_id NOT IN (SELECT target_id FROM archive_holds WHERE kind = 'memory')`
+)
+
+const (
+	heldMemorySQL = `EXISTS (SELECT 1 FROM archive_holds WHERE kind = 'memory' AND target_id = OLD.id)`
+	heldFactSQL   = `EXISTS (SELECT 1 FROM archive_holds WHERE (kind = 'fact' AND target_id = OLD.id) OR (kind = 'memory' AND target_id = OLD.memory_id))`
+)
+
+// migrateArchiveTables creates the hold and audit tables and the triggers
+// that enforce holds, so every write path — including raw maintenance SQL —
+// is covered.
+func (s *SQLiteStore) migrateArchiveTables() error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS archive_holds (
+			kind       TEXT NOT NULL CHECK(kind IN ('memory','fact')),
+			target_id  INTEGER NOT NULL,
+			reason     TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (kind, target_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS archive_audit (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			kind       TEXT NOT NULL,
+			target_id  INTEGER NOT NULL,
+			action     TEXT NOT NULL,
+			old_value  TEXT NOT NULL DEFAULT '',
+			new_value  TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_archive_audit_target ON archive_audit(kind, target_id)`,
+
+		`CREATE TRIGGER IF NOT EXISTS memories_hold_bd BEFORE DELETE ON memories
+		WHEN ` + heldMemorySQL + ` BEGIN
+			SELECT RAISE(ABORT, 'archive hold: memory cannot be deleted');
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS memories_hold_bu BEFORE UPDATE ON memories
+		WHEN ` + heldMemorySQL + ` AND (
+			NEW.content IS NOT OLD.content
+			OR NEW.source_file IS NOT OLD.source_file
+			OR (NEW.deleted_at IS NOT NULL AND OLD.deleted_at IS NULL)
+		) BEGIN
+			SELECT RAISE(ABORT, 'archive hold: memory content cannot change');
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS memories_hold_au AFTER UPDATE ON memories
+		WHEN ` + heldMemorySQL + ` AND (
+			NEW.project IS NOT OLD.project
+			OR NEW.memory_class IS NOT OLD.memory_class
+			OR NEW.metadata IS NOT OLD.metadata
+			OR NEW.source_section IS NOT OLD.source_section
+		) BEGIN
+			INSERT INTO archive_audit (kind, target_id, action, old_value, new_value)
+			VALUES ('memory', OLD.id, 'update',
+				json_object('project', OLD.project, 'memory_class', OLD.memory_class, 'metadata', OLD.metadata, 'source_section', OLD.source_section),
+				json_object('project', NEW.project, 'memory_class', NEW.memory_class, 'metadata', NEW.metadata, 'source_section', NEW.source_section));
+		END`,
+
+		`CREATE TRIGGER IF NOT EXISTS facts_hold_bd BEFORE DELETE ON facts
+		WHEN ` + heldFactSQL + ` BEGIN
+			SELECT RAISE(ABORT, 'archive hold: fact cannot be deleted');
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS facts_hold_bu BEFORE UPDATE ON facts
+		WHEN ` + heldFactSQL + ` AND (
+			NEW.subject IS NOT OLD.subject
+			OR NEW.predicate IS NOT OLD.predicate
+			OR NEW.object IS NOT OLD.object
+			OR NEW.source_quote IS NOT OLD.source_quote
+			OR NEW.memory_id IS NOT OLD.memory_id
+			OR (NEW.state = 'retired' AND OLD.state != 'retired')
+		) BEGIN
+			SELECT RAISE(ABORT, 'archive hold: fact content cannot change');
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS facts_hold_au AFTER UPDATE ON facts
+		WHEN ` + heldFactSQL + ` AND (
+			NEW.state IS NOT OLD.state
+			OR NEW.superseded_by IS NOT OLD.superseded_by
+			OR NEW.confidence IS NOT OLD.confidence
+			OR NEW.fact_type IS NOT OLD.fact_type
+			OR NEW.custom_type IS NOT OLD.custom_type
+			OR NEW.entity_id IS NOT OLD.entity_id
+			OR NEW.decay_rate IS NOT OLD.decay_rate
+		) BEGIN
+			INSERT INTO archive_audit (kind, target_id, action, old_value, new_value)
+			VALUES ('fact', OLD.id, 'update',
+				json_object('state', OLD.state, 'superseded_by', OLD.superseded_by, 'confidence', OLD.confidence, 'fact_type', OLD.fact_type, 'custom_type', OLD.custom_type, 'entity_id', OLD.entity_id, 'decay_rate', OLD.decay_rate),
+				json_object('state', NEW.state, 'superseded_by', NEW.superseded_by, 'confidence', NEW.confidence, 'fact_type', NEW.fact_type, 'custom_type', NEW.custom_type, 'entity_id', NEW.entity_id, 'decay_rate', NEW.decay_rate));
+		END`,
+	}
+	for _, stmt := range stmts {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating archive schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// IsHoldKind reports whether kind names an archive hold kind.
+func IsHoldKind(kind string) bool {
+	return kind == HoldKindMemory || kind == HoldKindFact
+}
+
+// SetArchiveHold places a hold on a memory or fact. Holding an already held
+// record updates its reason.
+func (s *SQLiteStore) SetArchiveHold(ctx context.Context, kind string, id int64, reason string) error {
+	if !IsHoldKind(kind) {
+		return fmt.Errorf("unknown hold kind %q (use memory or fact)", kind)
+	}
+	if err := s.holdTargetExists(ctx, kind, id); err != nil {
+		return err
+	}
+	reason = strings.TrimSpace(reason)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin archive hold tx: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO archive_holds (kind, target_id, reason, created_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(kind, target_id) DO UPDATE SET reason = excluded.reason`,
+		kind, id, reason, time.Now().UTC()); err != nil {
+		return fmt.Errorf("placing hold on %s %d: %w", kind, id, err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO archive_audit (kind, target_id, action, new_value, created_at) VALUES (?, ?, 'hold', ?, ?)`,
+		kind, id, reason, time.Now().UTC()); err != nil {
+		return fmt.Errorf("auditing hold on %s %d: %w", kind, id, err)
+	}
+	return tx.Commit()
+}
+
+// ReleaseArchiveHold lifts a hold. The release is audited; the audit trail
+// itself is never pruned.
+func (s *SQLiteStore) ReleaseArchiveHold(ctx context.Context, kind string, id int64, reason string) error {
+	if !IsHoldKind(kind) {
+		return fmt.Errorf("unknown hold kind %q (use memory or fact)", kind)
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin archive release tx: %w", err)
+	}
+	defer tx.Rollback()
+	var old string
+	err = tx.QueryRowContext(ctx,
+		`SELECT reason FROM archive_holds WHERE kind = ? AND target_id = ?`, kind, id).Scan(&old)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%s %d is not under archive hold", kind, id)
+	}
+	if err != nil {
+		return fmt.Errorf("reading hold on %s %d: %w", kind, id, err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM archive_holds WHERE kind = ? AND target_id = ?`, kind, id); err != nil {
+		return fmt.Errorf("releasing hold on %s %d: %w", kind, id, err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO archive_audit (kind, target_id, action, old_value, new_value, created_at) VALUES (?, ?, 'release', ?, ?, ?)`,
+		kind, id, old, strings.TrimSpace(reason), time.Now().UTC()); err != nil {
+		return fmt.Errorf("auditing release of %s %d: %w", kind, id, err)
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) holdTargetExists(ctx context.Context, kind string, id int64) error {
+	query := `SELECT 1 FROM memories WHERE id = ? AND deleted_at IS NULL`
+	if kind == HoldKindFact {
+		query = `SELECT 1 FROM facts WHERE id = ?`
+	}
+	var one int
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&one)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%s %d not found", kind, id)
+	}
+	if err != nil {
+		return fmt.Errorf("checking %s %d: %w", kind, id, err)
+	}
+	return nil
+}
+
+// ListArchiveHolds returns the holds of the given kind ("" for both), newest
+// first, with a short summary of each record.
+func (s *SQLiteStore) ListArchiveHolds(ctx context.Context, kind string) ([]ArchiveHold, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT h.kind, h.target_id, h.reason, h.created_at,
+			CASE h.kind
+				WHEN 'memory' THEN COALESCE((SELECT substr(content, 1, 80) FROM memories WHERE id = h.target_id), '')
+				ELSE COALESCE((SELECT subject || ' ' || predicate || ' ' || object FROM facts WHERE id = h.target_id), '')
+			END
+		FROM archive_holds h
+		WHERE ? = '' OR h.kind = ?
+		ORDER BY h.created_at DESC, h.kind, h.target_id`, kind, kind)
+	if err != nil {
+		return nil, fmt.Errorf("listing archive holds: %w", err)
+	}
+	defer rows.Close()
+	var out []ArchiveHold
+	for rows.Next() {
+		var h ArchiveHold
+		if err := rows.Scan(&h.Kind, &h.TargetID, &h.Reason, &h.CreatedAt, &h.Summary); err != nil {
+			return nil, fmt.Errorf("scanning archive hold: %w", err)
+		}
+		h.Summary = strings.Join(strings.Fields(h.Summary), " ")
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+// ListArchiveAudit returns audit entries, newest first. kind and id narrow
+// the trail to one record when set; limit <= 0 means no limit.
+func (s *SQLiteStore) ListArchiveAudit(ctx context.Context, kind string, id int64, limit int) ([]ArchiveAuditEntry, error) {
+	query := `SELECT id, kind, target_id, action, old_value, new_value, created_at FROM archive_audit WHERE 1=1`
+	var args []any
+	if kind != "" {
+		query += ` AND kind = ?`
+		args = append(args, kind)
+	}
+	if id > 0 {
+		query += ` AND target_id = ?`
+		args = append(args, id)
+	}
+	query += ` ORDER BY id DESC`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing archive audit: %w", err)
+	}
+	defer rows.Close()
+	var out []ArchiveAuditEntry
+	for rows.Next() {
+		var e ArchiveAuditEntry
+		if err := rows.Scan(&e.ID, &e.Kind, &e.TargetID, &e.Action, &e.OldValue, &e.NewValue, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning archive audit: %w", err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// HeldFactIDs returns the IDs of every fact under hold, directly or through
+// its memory.
+func (s *SQLiteStore) HeldFactIDs(ctx context.Context) (map[int64]bool, error) {
+	held := map[int64]bool{}
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM facts WHERE NOT (`+NotHeldFactClause+`)`)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such table") {
+			return held, nil
+		}
+		return nil, fmt.Errorf("listing held facts: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning held fact: %w", err)
+		}
+		held[id] = true
+	}
+	return held, rows.Err()
+}
+
+// withoutHeldFacts drops held facts from ids.
+func (s *SQLiteStore) withoutHeldFacts(ctx context.Context, ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	held, err := s.HeldFactIDs(ctx)
+	if err != nil || len(held) == 0 {
+		return ids, err
+	}
+	kept := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !held[id] {
+			kept = append(kept, id)
+		}
+	}
+	return kept, nil
+}
+
+// withoutHeldMemories drops from ids the memories that are held or have a
+// held fact, since purging a memory deletes its facts.
+func (s *SQLiteStore) withoutHeldMemories(ctx context.Context, ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	held, err := s.queryMemoryIDs(ctx, `
+		SELECT target_id FROM archive_holds WHERE kind = 'memory'
+		UNION
+		SELECT memory_id FROM facts WHERE id IN (SELECT target_id FROM archive_holds WHERE kind = 'fact')`)
+	if err != nil || len(held) == 0 {
+		return ids, err
+	}
+	skip := make(map[int64]bool, len(held))
+	for _, id := range held {
+		skip[id] = true
+	}
+	kept := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !skip[id] {
+			kept = append(kept, id)
+		}
+	}
+	return kept, nil
+}
+
+// archiveHoldError turns a hold trigger's abort into an ErrArchiveHold error
+// naming the record; other errors pass through.
+func archiveHoldError(err error, kind string, id int64) error {
+	if err != nil && strings.Contains(err.Error(), "archive hold:") {
+		return fmt.Errorf("%s %d is %w", kind, id, ErrArchiveHold)
+	}
+	return err
+}
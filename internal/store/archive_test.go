@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArchiveHold_BlocksDeleteAllowsSupersede(t *testing.T) {
+	si, err := NewStore(StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer si.Close()
+	s := si.(*SQLiteStore)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "Board approved the Q3 vendor contract", SourceFile: "decisions.md"})
+	held, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "vendor contract", Predicate: "status", Object: "approved", FactType: "state", Confidence: 0.9})
+	loose, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "vendor", Predicate: "name", Object: "Acme", FactType: "kv", Confidence: 0.9})
+
+	if err := s.SetArchiveHold(ctx, HoldKindFact, 9999, ""); err == nil {
+		t.Fatal("expected error holding a missing fact")
+	}
+	if err := s.SetArchiveHold(ctx, HoldKindFact, held, "board decision"); err != nil {
+		t.Fatalf("SetArchiveHold: %v", err)
+	}
+
+	if err := s.UpdateFactState(ctx, held, FactStateRetired); !errors.Is(err, ErrArchiveHold) {
+		t.Fatalf("retiring a held fact: got %v, want ErrArchiveHold", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `UPDATE facts SET object = 'rejected' WHERE id = ?`, held); err == nil {
+		t.Fatal("rewriting a held fact's object should fail")
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM facts WHERE id = ?`, held); err == nil {
+		t.Fatal("raw delete of a held fact should fail")
+	}
+	if n, err := s.DeleteFactsByIDs(ctx, []int64{held, loose}); err != nil || n != 1 {
+		t.Fatalf("DeleteFactsByIDs = %d, %v; want only the unheld fact deleted", n, err)
+	}
+
+	// A memory with a held fact survives a purge.
+	if n, err := s.ReplaceSourceMemories(ctx, "decisions.md", 0); err != nil || n != 0 {
+		t.Fatalf("ReplaceSourceMemories = %d, %v; want memory kept", n, err)
+	}
+
+	// Superseding is allowed and audited.
+	newer, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "vendor contract", Predicate: "status", Object: "renewed", FactType: "state", Confidence: 0.9})
+	if err := s.SupersedeFact(ctx, held, newer, "renewal"); err != nil {
+		t.Fatalf("SupersedeFact on held fact: %v", err)
+	}
+	entries, err := s.ListArchiveAudit(ctx, HoldKindFact, held, 0)
+	if err != nil {
+		t.Fatalf("ListArchiveAudit: %v", err)
+	}
+	if len(entries) < 2 || entries[0].Action != "update" || !strings.Contains(entries[0].NewValue, `"superseded_by"`) {
+		t.Fatalf("supersede not audited: %+v", entries)
+	}
+	if last := entries[len(entries)-1]; last.Action != "hold" || last.NewValue != "board decision" {
+		t.Fatalf("hold not audited: %+v", last)
+	}
+
+	if err := s.ReleaseArchiveHold(ctx, HoldKindFact, held, "matter closed"); err != nil {
+		t.Fatalf("ReleaseArchiveHold: %v", err)
+	}
+	if err := s.ReleaseArchiveHold(ctx, HoldKindFact, held, ""); err == nil {
+		t.Fatal("expected error releasing a fact that is not held")
+	}
+	if err := s.UpdateFactState(ctx, held, FactStateRetired); err != nil {
+		t.Fatalf("retiring a released fact: %v", err)
+	}
+	entries, _ = s.ListArchiveAudit(ctx, "", 0, 1)
+	if len(entries) != 1 || entries[0].Action != "release" || entries[0].OldValue != "board decision" {
+		t.Fatalf("release not audited: %+v", entries)
+	}
+}
+
+func TestArchiveHold_MemoryCoversFacts(t *testing.T) {
+	si, err := NewStore(StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer si.Close()
+	s := si.(*SQLiteStore)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "Decision log: migrate billing to the new ledger", SourceFile: "log.md"})
+	other, _ := s.AddMemory(ctx, &Memory{Content: "Scratch notes for the standup", SourceFile: "log.md"})
+	factID, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "billing", Predicate: "target", Object: "new ledger", FactType: "decision", Confidence: 0.9})
+
+	if err := s.SetArchiveHold(ctx, HoldKindMemory, memID, "audit 2026"); err != nil {
+		t.Fatalf("SetArchiveHold: %v", err)
+	}
+	if err := s.DeleteMemory(ctx, memID); !errors.Is(err, ErrArchiveHold) {
+		t.Fatalf("DeleteMemory on held memory: got %v, want ErrArchiveHold", err)
+	}
+	if err := s.UpdateMemory(ctx, memID, "rewritten"); !errors.Is(err, ErrArchiveHold) {
+		t.Fatalf("UpdateMemory on held memory: got %v, want ErrArchiveHold", err)
+	}
+	if err := s.UpdateFactState(ctx, factID, FactStateRetired); !errors.Is(err, ErrArchiveHold) {
+		t.Fatalf("retiring a fact of a held memory: got %v, want ErrArchiveHold", err)
+	}
+	if n, err := s.DeleteFactsByMemoryID(ctx, memID); err != nil || n != 0 {
+		t.Fatalf("DeleteFactsByMemoryID = %d, %v; want 0", n, err)
+	}
+	if ids, _ := s.HeldFactIDs(ctx); !ids[factID] {
+		t.Fatalf("HeldFactIDs should include facts of held memories: %v", ids)
+	}
+
+	if n, err := s.ReplaceSourceMemories(ctx, "log.md", 0); err != nil || n != 1 {
+		t.Fatalf("ReplaceSourceMemories = %d, %v; want only the unheld memory purged", n, err)
+	}
+	if m, _ := s.GetMemory(ctx, other); m != nil {
+		t.Fatal("unheld memory should be purged")
+	}
+
+	// Metadata changes are allowed but audited.
+	if _, err := s.db.ExecContext(ctx, `UPDATE memories SET project = 'finance' WHERE id = ?`, memID); err != nil {
+		t.Fatalf("retagging a held memory: %v", err)
+	}
+	entries, _ := s.ListArchiveAudit(ctx, HoldKindMemory, memID, 0)
+	if len(entries) != 2 || entries[0].Action != "update" || !strings.Contains(entries[0].NewValue, `"finance"`) {
+		t.Fatalf("project change not audited: %+v", entries)
+	}
+
+	holds, err := s.ListArchiveHolds(ctx, "")
+	if err != nil || len(holds) != 1 || holds[0].Reason != "audit 2026" || !strings.HasPrefix(holds[0].Summary, "Decision log") {
+		t.Fatalf("ListArchiveHolds = %+v, %v", holds, err)
+	}
+}
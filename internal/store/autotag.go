@@ -2,6 +2,7 @@ package store
 
 import (
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -41,6 +42,7 @@ type ContentRule struct {
 	Keywords []string // Lowercase keywords to look for in content
 	MinHits  int      // Minimum distinct keyword matches required (default: 2)
 	Project  string   // Project to assign when matched
+	Origin   string   // Where the rule came from (empty = built-in)
 }
 
 // DefaultContentRules maps content keywords to project names.
@@ -102,10 +104,14 @@ var DefaultContentRules = []ContentRule{
 	},
 }
 
-// ProjectRule maps a path substring to a project name.
+// ProjectRule maps a path substring or glob to a project name.
 type ProjectRule struct {
 	Pattern string // Substring to match in file path (case-insensitive)
+	Glob    string // Path glob (* within a segment, ** across segments); used instead of Pattern when set
 	Project string // Project to assign when matched
+	Origin  string // Where the rule came from (empty = built-in)
+
+	glob *regexp.Regexp
 }
 
 // InferProject attempts to determine the project from a source file path.
@@ -119,7 +125,7 @@ func InferProject(sourceFile string, rules []ProjectRule) string {
 	normalized := strings.ToLower(filepath.ToSlash(sourceFile))
 
 	for _, rule := range rules {
-		if strings.Contains(normalized, strings.ToLower(rule.Pattern)) {
+		if rule.matchesPath(normalized) {
 			return rule.Project
 		}
 	}
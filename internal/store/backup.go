@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BackupMethodVacuumInto names how BackupTo copies the database.
+const BackupMethodVacuumInto = "vacuum_into"
+
+// BackupInfo describes a point-in-time copy of the database. It is embedded
+// in the JSON metadata of exports and backups so readers know what the copy
+// guarantees.
+type BackupInfo struct {
+	Path       string    `json:"path,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	TakenAt    time.Time `json:"taken_at"`
+	Method     string    `json:"method"`
+	Consistent bool      `json:"consistent"`
+	Guarantee  string    `json:"guarantee"`
+	Bytes      int64     `json:"bytes"`
+}
+
+// backupGuarantee is the consistency guarantee recorded in BackupInfo.
+const backupGuarantee = "point-in-time: each write committed during the copy is either fully in it or fully out of it"
+
+// BackupTo writes a point-in-time copy of the database to dst. The copy is
+// taken with VACUUM INTO, which reads the whole database inside a single read
+// transaction, so writers committing in the meantime (the MCP server, the
+// daemon) never leave it half-updated. The copy is written next to dst and
+// renamed into place, replacing any existing file only once it is complete.
+func (s *SQLiteStore) BackupTo(ctx context.Context, dst string) (*BackupInfo, error) {
+	if dst == "" {
+		return nil, fmt.Errorf("backup destination is required")
+	}
+	abs, err := filepath.Abs(dst)
+	if err != nil {
+		return nil, fmt.Errorf("resolving backup path: %w", err)
+	}
+	if s.dbPath != "" && s.dbPath != ":memory:" {
+		if src, err := filepath.Abs(s.dbPath); err == nil && src == abs {
+			return nil, fmt.Errorf("backup destination is the live database: %s", dst)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
+		return nil, fmt.Errorf("creating backup directory: %w", err)
+	}
+
+	partial := abs + ".partial"
+	os.Remove(partial)
+	takenAt := time.Now().UTC()
+	if _, err := s.db.ExecContext(ctx, "VACUUM INTO ?", partial); err != nil {
+		os.Remove(partial)
+		return nil, fmt.Errorf("copying database: %w", err)
+	}
+	if err := os.Rename(partial, abs); err != nil {
+		os.Remove(partial)
+		return nil, fmt.Errorf("moving backup into place: %w", err)
+	}
+	st, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("reading backup: %w", err)
+	}
+
+	info := &BackupInfo{
+		Path:       abs,
+		TakenAt:    takenAt,
+		Method:     BackupMethodVacuumInto,
+		Consistent: true,
+		Guarantee:  backupGuarantee,
+		Bytes:      st.Size(),
+	}
+	if s.dbPath != ":memory:" {
+		info.Source = s.dbPath
+	}
+	return info, nil
+}
@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestBackupTo_PointInTimeCopy(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	if _, err := s.AddMemory(ctx, &Memory{Content: "before the backup", SourceFile: "a.md"}); err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(t.TempDir(), "nested", "backup.db")
+	info, err := s.BackupTo(ctx, dst)
+	if err != nil {
+		t.Fatalf("BackupTo: %v", err)
+	}
+	if !info.Consistent || info.Method != BackupMethodVacuumInto || info.Bytes == 0 || info.TakenAt.IsZero() {
+		t.Fatalf("unexpected backup info: %+v", info)
+	}
+	if _, err := s.AddMemory(ctx, &Memory{Content: "after the backup", SourceFile: "b.md"}); err != nil {
+		t.Fatal(err)
+	}
+
+	copyStore, err := NewStore(StoreConfig{DBPath: dst, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("opening backup: %v", err)
+	}
+	defer copyStore.Close()
+	memories, err := copyStore.ListMemories(ctx, ListOpts{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(memories) != 1 || memories[0].Content != "before the backup" {
+		t.Fatalf("backup should hold only the first memory, got %d", len(memories))
+	}
+
+	// A second backup replaces the first.
+	if _, err := s.BackupTo(ctx, dst); err != nil {
+		t.Fatalf("second BackupTo: %v", err)
+	}
+}
+
+func TestBackupTo_DuringConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "live.db")
+	live, err := NewStore(StoreConfig{DBPath: dbPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer live.Close()
+	reader, err := NewStore(StoreConfig{DBPath: dbPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	// Each write adds a memory and its fact in one transaction; a torn copy
+	// would show a memory without its fact.
+	db := live.(*SQLiteStore).db
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			tx, err := db.Begin()
+			if err != nil {
+				continue
+			}
+			res, err := tx.Exec(`INSERT INTO memories (content, source_file, content_hash) VALUES (?, 'w.md', ?)`,
+				fmt.Sprintf("memory %d", i), fmt.Sprintf("hash-%d", i))
+			if err != nil {
+				tx.Rollback()
+				continue
+			}
+			id, _ := res.LastInsertId()
+			if _, err := tx.Exec(`INSERT INTO facts (memory_id, subject, predicate, object, fact_type) VALUES (?, 's', 'p', 'o', 'kv')`, id); err != nil {
+				tx.Rollback()
+				continue
+			}
+			tx.Commit()
+		}
+	}()
+
+	dst := filepath.Join(t.TempDir(), "backup.db")
+	_, err = reader.(*SQLiteStore).BackupTo(ctx, dst)
+	close(stop)
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("BackupTo: %v", err)
+	}
+
+	copyStore, err := NewStore(StoreConfig{DBPath: dst, ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer copyStore.Close()
+	var orphans int
+	if err := copyStore.(*SQLiteStore).db.QueryRow(
+		`SELECT COUNT(*) FROM memories m WHERE NOT EXISTS (SELECT 1 FROM facts f WHERE f.memory_id = m.id)`,
+	).Scan(&orphans); err != nil {
+		t.Fatal(err)
+	}
+	if orphans != 0 {
+		t.Fatalf("backup caught %d memories without their facts", orphans)
+	}
+}
+
+func TestBackupTo_RejectsLiveDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "live.db")
+	s, err := NewStore(StoreConfig{DBPath: dbPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if _, err := s.(*SQLiteStore).BackupTo(context.Background(), dbPath); err == nil {
+		t.Fatal("expected error when backing up onto the live database")
+	}
+}
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// migrateBatchCheckpointsTable creates batch_checkpoints, which records which
+// items a long LLM batch operation (classify,
+// conflict resolution, summarize) has already processed, so a run that dies
+// part-way resumes where it stopped instead of starting over. Each operation
+// names its own checkpoint (e.g. "classify" or "classify:agent=mister") and
+// keys items however it likes ("fact:42", "pair:3-9"). Callers clear the
+// checkpoint once every item has been processed.
+func (s *SQLiteStore) migrateBatchCheckpointsTable() error {
+	done, err := s.isMetaFlagEnabled("batch_checkpoints_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS batch_checkpoints (
+			operation  TEXT NOT NULL,
+			item_key   TEXT NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (operation, item_key)
+		)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('batch_checkpoints_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating batch_checkpoints table: %w", err)
+		}
+	}
+	return nil
+}
+
+// RecordCheckpoint marks items as processed for operation.
+func (s *SQLiteStore) RecordCheckpoint(ctx context.Context, operation string, keys ...string) error {
+	operation = strings.TrimSpace(operation)
+	if operation == "" {
+		return fmt.Errorf("checkpoint operation is required")
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("recording checkpoint: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	for _, key := range keys {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT OR IGNORE INTO batch_checkpoints (operation, item_key, created_at) VALUES (?, ?, ?)`,
+			operation, key, now,
+		); err != nil {
+			return fmt.Errorf("recording checkpoint: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("recording checkpoint: %w", err)
+	}
+	return nil
+}
+
+// CheckpointedItems returns the item keys already processed for operation.
+func (s *SQLiteStore) CheckpointedItems(ctx context.Context, operation string) (map[string]bool, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT item_key FROM batch_checkpoints WHERE operation = ?`, strings.TrimSpace(operation))
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint: %w", err)
+	}
+	defer rows.Close()
+
+	items := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("scanning checkpoint: %w", err)
+		}
+		items[key] = true
+	}
+	return items, rows.Err()
+}
+
+// ClearCheckpoint discards operation's checkpoint and returns how many items
+// it held.
+func (s *SQLiteStore) ClearCheckpoint(ctx context.Context, operation string) (int64, error) {
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM batch_checkpoints WHERE operation = ?`, strings.TrimSpace(operation))
+	if err != nil {
+		return 0, fmt.Errorf("clearing checkpoint: %w", err)
+	}
+	return res.RowsAffected()
+}
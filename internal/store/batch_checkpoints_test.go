@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBatchCheckpoints_RecordReadClear(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+
+	if err := s.migrateBatchCheckpointsTable(); err != nil {
+		t.Fatalf("second migrateBatchCheckpointsTable call failed: %v", err)
+	}
+	if err := s.RecordCheckpoint(ctx, " ", "fact:1"); err == nil {
+		t.Fatal("expected error for missing operation")
+	}
+
+	if err := s.RecordCheckpoint(ctx, "classify", "fact:1", "fact:2"); err != nil {
+		t.Fatalf("RecordCheckpoint: %v", err)
+	}
+	// Re-recording an item is a no-op.
+	if err := s.RecordCheckpoint(ctx, "classify", "fact:2", "fact:3"); err != nil {
+		t.Fatalf("RecordCheckpoint (repeat): %v", err)
+	}
+	if err := s.RecordCheckpoint(ctx, "summarize", "cluster:7"); err != nil {
+		t.Fatalf("RecordCheckpoint (other operation): %v", err)
+	}
+
+	items, err := s.CheckpointedItems(ctx, "classify")
+	if err != nil {
+		t.Fatalf("CheckpointedItems: %v", err)
+	}
+	if len(items) != 3 || !items["fact:1"] || !items["fact:3"] || items["cluster:7"] {
+		t.Fatalf("unexpected classify checkpoint: %v", items)
+	}
+
+	n, err := s.ClearCheckpoint(ctx, "classify")
+	if err != nil || n != 3 {
+		t.Fatalf("ClearCheckpoint = %d, %v; want 3", n, err)
+	}
+	if items, _ := s.CheckpointedItems(ctx, "classify"); len(items) != 0 {
+		t.Fatalf("expected empty checkpoint after clear, got %v", items)
+	}
+	if items, _ := s.CheckpointedItems(ctx, "summarize"); len(items) != 1 {
+		t.Fatalf("clearing one operation must keep others, got %v", items)
+	}
+}
@@ -0,0 +1,267 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BatchDelete selects the memories or facts removed by DeleteMemories and
+// DeleteFacts. Set filters are combined with AND; IDs, when given, restrict
+// the match to those records. Facts are matched on their memory's source,
+// project, and class, and on their own creation time.
+type BatchDelete struct {
+	IDs     []int64
+	Source  string    // source_file GLOB pattern (*, ?, [...])
+	Project string    // exact project tag
+	Before  time.Time // imported (memories) or created (facts) before this time
+	Class   string    // memory class
+	// Purge removes memories outright, with their facts, edges, and
+	// embeddings, instead of soft-deleting them. Facts are always removed.
+	Purge  bool
+	DryRun bool
+}
+
+// Empty reports whether no IDs or filters are set. Deleting with an empty
+// selection is refused rather than treated as "everything".
+func (b BatchDelete) Empty() bool {
+	return len(b.IDs) == 0 && b.Source == "" && b.Project == "" && b.Before.IsZero() && b.Class == ""
+}
+
+// BatchDeleteResult reports what a batch delete removed, or would remove
+// when DryRun is set.
+type BatchDeleteResult struct {
+	DryRun     bool    `json:"dry_run,omitempty"`
+	Purge      bool    `json:"purge,omitempty"`
+	Matched    int     `json:"matched"`    // records selected, held ones included
+	Held       int     `json:"held"`       // kept because of an archive hold
+	Memories   int64   `json:"memories"`   // memories deleted
+	Facts      int64   `json:"facts"`      // facts deleted
+	Edges      int64   `json:"edges"`      // fact edges removed with them
+	Embeddings int64   `json:"embeddings"` // memory embeddings removed
+	Clusters   int64   `json:"clusters"`   // clusters left empty and removed
+	IDs        []int64 `json:"ids"`        // the records deleted
+}
+
+// batchDeleteWhere builds the WHERE clause shared by both selections. col
+// maps a logical column to its qualified name.
+func batchDeleteWhere(b BatchDelete, col func(string) string) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+	if len(b.IDs) > 0 {
+		clauses = append(clauses, fmt.Sprintf("%s IN (%s)", col("id"), int64List(b.IDs)))
+	}
+	if b.Source != "" {
+		clauses = append(clauses, col("source_file")+" GLOB ?")
+		args = append(args, b.Source)
+	}
+	if b.Project != "" {
+		clauses = append(clauses, col("project")+" = ?")
+		args = append(args, b.Project)
+	}
+	if !b.Before.IsZero() {
+		clauses = append(clauses, col("time")+" < ?")
+		args = append(args, b.Before.UTC())
+	}
+	if b.Class != "" {
+		clauses = append(clauses, col("memory_class")+" = ?")
+		args = append(args, NormalizeMemoryClass(b.Class))
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+// DeleteMemories soft-deletes, or with Purge removes, every memory matching
+// b. Summary and merge facts move onto another live source first, as with
+// DeleteMemory. A purge also deletes the memories' facts with their edges
+// and accesses, their embeddings, and clusters that end up empty. Memories
+// under an archive hold, or with a held fact, are kept and counted.
+func (s *SQLiteStore) DeleteMemories(ctx context.Context, b BatchDelete) (*BatchDeleteResult, error) {
+	if b.Empty() {
+		return nil, fmt.Errorf("batch delete needs IDs or at least one filter")
+	}
+	if b.Class != "" && !IsValidMemoryClass(NormalizeMemoryClass(b.Class)) {
+		return nil, fmt.Errorf("invalid memory class %q", b.Class)
+	}
+	where, args := batchDeleteWhere(b, func(c string) string {
+		if c == "time" {
+			return "imported_at"
+		}
+		return c
+	})
+	if !b.Purge {
+		where += " AND deleted_at IS NULL"
+	}
+	nsClause, nsArgs := s.namespaceFilter(ctx, "namespace")
+	matched, err := s.queryMemoryIDs(ctx, `SELECT id FROM memories WHERE `+where+nsClause+` ORDER BY id`, append(args, nsArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("selecting memories to delete: %w", err)
+	}
+	ids, err := s.withoutHeldMemories(ctx, matched)
+	if err != nil {
+		return nil, fmt.Errorf("checking archive holds: %w", err)
+	}
+	res := &BatchDeleteResult{DryRun: b.DryRun, Purge: b.Purge, Matched: len(matched), Held: len(matched) - len(ids), IDs: ids}
+	if res.IDs == nil {
+		res.IDs = []int64{}
+	}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	if !b.Purge {
+		res.Memories = int64(len(ids))
+		if b.DryRun {
+			return res, nil
+		}
+		for _, id := range ids {
+			if err := s.DeleteMemory(ctx, id); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+
+	list := int64List(ids)
+	factIDs, err := s.queryMemoryIDs(ctx, fmt.Sprintf(`SELECT id FROM facts WHERE memory_id IN (%s)`, list))
+	if err != nil {
+		return nil, fmt.Errorf("listing facts of deleted memories: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT COUNT(*) FROM embeddings WHERE memory_id IN (%s)`, list),
+	).Scan(&res.Embeddings); err != nil {
+		return nil, fmt.Errorf("counting embeddings: %w", err)
+	}
+	// A dry run counts every fact of the memories, including summary and
+	// merge facts that a real purge would move onto another live source.
+	if b.DryRun {
+		res.Memories = int64(len(ids))
+		res.Facts = int64(len(factIDs))
+		if res.Edges, err = s.countFactEdges(ctx, factIDs); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	for _, id := range ids {
+		if _, err := s.repointDerivedFacts(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	factIDs, err = s.queryMemoryIDs(ctx, fmt.Sprintf(`SELECT id FROM facts WHERE memory_id IN (%s)`, list))
+	if err != nil {
+		return nil, fmt.Errorf("listing facts of deleted memories: %w", err)
+	}
+	if err := s.deleteFactsCascade(ctx, factIDs, res); err != nil {
+		return nil, err
+	}
+	if res.Memories, err = s.purgeMemories(ctx, ids); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteFacts removes every fact matching b with its edges, accesses,
+// alerts, and cluster memberships; clusters left empty are removed and the
+// rest get fresh counts. Held facts are kept and counted.
+func (s *SQLiteStore) DeleteFacts(ctx context.Context, b BatchDelete) (*BatchDeleteResult, error) {
+	if b.Empty() {
+		return nil, fmt.Errorf("batch delete needs IDs or at least one filter")
+	}
+	if b.Class != "" && !IsValidMemoryClass(NormalizeMemoryClass(b.Class)) {
+		return nil, fmt.Errorf("invalid memory class %q", b.Class)
+	}
+	where, args := batchDeleteWhere(b, func(c string) string {
+		switch c {
+		case "id":
+			return "f.id"
+		case "time":
+			return "f.created_at"
+		}
+		return "m." + c
+	})
+	nsClause, nsArgs := s.namespaceFilter(ctx, "m.namespace")
+	matched, err := s.queryMemoryIDs(ctx,
+		`SELECT f.id FROM facts f LEFT JOIN memories m ON m.id = f.memory_id WHERE `+where+nsClause+` ORDER BY f.id`, append(args, nsArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("selecting facts to delete: %w", err)
+	}
+	ids, err := s.withoutHeldFacts(ctx, matched)
+	if err != nil {
+		return nil, fmt.Errorf("checking archive holds: %w", err)
+	}
+	res := &BatchDeleteResult{DryRun: b.DryRun, Matched: len(matched), Held: len(matched) - len(ids), IDs: ids}
+	if res.IDs == nil {
+		res.IDs = []int64{}
+	}
+	if b.DryRun {
+		res.Facts = int64(len(ids))
+		if res.Edges, err = s.countFactEdges(ctx, ids); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+	if err := s.deleteFactsCascade(ctx, ids, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *SQLiteStore) countFactEdges(ctx context.Context, factIDs []int64) (int64, error) {
+	if len(factIDs) == 0 {
+		return 0, nil
+	}
+	list := int64List(factIDs)
+	var n int64
+	if err := s.db.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT COUNT(*) FROM fact_edges_v1 WHERE source_fact_id IN (%s) OR target_fact_id IN (%s)`, list, list),
+	).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting fact edges: %w", err)
+	}
+	return n, nil
+}
+
+// deleteFactsCascade deletes unheld facts through DeleteFactsByIDs and then
+// brings the clusters they belonged to up to date, adding the counts to res.
+func (s *SQLiteStore) deleteFactsCascade(ctx context.Context, factIDs []int64, res *BatchDeleteResult) error {
+	if len(factIDs) == 0 {
+		return nil
+	}
+	edges, err := s.countFactEdges(ctx, factIDs)
+	if err != nil {
+		return err
+	}
+	clusterIDs, err := s.queryMemoryIDs(ctx, fmt.Sprintf(
+		`SELECT DISTINCT cluster_id FROM fact_clusters WHERE fact_id IN (%s)`, int64List(factIDs)))
+	if err != nil {
+		return fmt.Errorf("listing clusters of deleted facts: %w", err)
+	}
+	n, err := s.DeleteFactsByIDs(ctx, factIDs)
+	if err != nil {
+		return err
+	}
+	res.Facts += n
+	res.Edges += edges
+	if len(clusterIDs) == 0 {
+		return nil
+	}
+
+	// fact_clusters rows go with their facts (ON DELETE CASCADE); the
+	// clusters' denormalized counts do not.
+	list := int64List(clusterIDs)
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE clusters SET
+			fact_count = (SELECT COUNT(*) FROM fact_clusters fc WHERE fc.cluster_id = clusters.id),
+			avg_confidence = COALESCE((SELECT AVG(f.confidence) FROM fact_clusters fc JOIN facts f ON f.id = fc.fact_id
+			                           WHERE fc.cluster_id = clusters.id), 0),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (%s)`, list)); err != nil {
+		return fmt.Errorf("updating cluster counts: %w", err)
+	}
+	removed, err := s.execCount(ctx, fmt.Sprintf(`DELETE FROM clusters WHERE id IN (%s) AND fact_count = 0`, list))
+	if err != nil {
+		return fmt.Errorf("removing empty clusters: %w", err)
+	}
+	res.Clusters += removed
+	return nil
+}
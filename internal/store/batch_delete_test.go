@@ -0,0 +1,191 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeleteMemories_FiltersAndSoftDelete(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	scratch, _ := s.AddMemory(ctx, &Memory{Content: "scratch note", SourceFile: "/notes/scratch/a.md", Project: "ops"})
+	kept, _ := s.AddMemory(ctx, &Memory{Content: "ops runbook", SourceFile: "/notes/ops.md", Project: "ops"})
+	other, _ := s.AddMemory(ctx, &Memory{Content: "other scratch", SourceFile: "/notes/scratch/b.md", Project: "home"})
+
+	if _, err := s.DeleteMemories(ctx, BatchDelete{}); err == nil {
+		t.Fatal("empty selection should be refused")
+	}
+
+	plan, err := s.DeleteMemories(ctx, BatchDelete{Source: "*/scratch/*", Project: "ops", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Matched != 1 || len(plan.IDs) != 1 || plan.IDs[0] != scratch {
+		t.Fatalf("dry run = %+v, want only memory %d", plan, scratch)
+	}
+	if m, _ := s.GetMemory(ctx, scratch); m.DeletedAt != nil {
+		t.Fatal("dry run must not delete")
+	}
+
+	res, err := s.DeleteMemories(ctx, BatchDelete{Source: "*/scratch/*", Project: "ops"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Memories != 1 {
+		t.Fatalf("deleted = %+v", res)
+	}
+	if m, _ := s.GetMemory(ctx, scratch); m == nil || m.DeletedAt == nil {
+		t.Fatal("scratch memory should be soft-deleted")
+	}
+	for _, id := range []int64{kept, other} {
+		if m, _ := s.GetMemory(ctx, id); m == nil || m.DeletedAt != nil {
+			t.Fatalf("memory %d should be untouched", id)
+		}
+	}
+
+	// Before is exclusive and compares against imported_at.
+	res, _ = s.DeleteMemories(ctx, BatchDelete{Before: time.Now().Add(-time.Hour), DryRun: true})
+	if res.Matched != 0 {
+		t.Fatalf("nothing was imported an hour ago, matched %d", res.Matched)
+	}
+	if _, err := s.DeleteMemories(ctx, BatchDelete{Class: "bogus"}); err == nil {
+		t.Fatal("invalid class should be rejected")
+	}
+}
+
+func TestDeleteMemories_PurgeCascades(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	m1, _ := s.AddMemory(ctx, &Memory{Content: "deploys use fly.io", SourceFile: "/notes/ops.md"})
+	m2, _ := s.AddMemory(ctx, &Memory{Content: "lunch is at noon", SourceFile: "/notes/life.md"})
+	held, _ := s.AddMemory(ctx, &Memory{Content: "board decision", SourceFile: "/notes/ops.md"})
+	f1, _ := s.AddFact(ctx, &Fact{MemoryID: m1, Subject: "deploy", Predicate: "uses", Object: "fly.io", FactType: "kv"})
+	f2, _ := s.AddFact(ctx, &Fact{MemoryID: m2, Subject: "lunch", Predicate: "at", Object: "noon", FactType: "kv"})
+	if err := s.AddEdge(ctx, &FactEdge{SourceFactID: f1, TargetFactID: f2, EdgeType: EdgeTypeRelatesTo}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddEmbedding(ctx, m1, []float32{0.1, 0.2, 0.3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetArchiveHold(ctx, HoldKindMemory, held, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.Exec(`INSERT INTO clusters (id, name, fact_count) VALUES (1, 'ops', 1), (2, 'life', 1)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.Exec(`INSERT INTO fact_clusters (fact_id, cluster_id) VALUES (?, 1), (?, 2)`, f1, f2); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.DeleteMemories(ctx, BatchDelete{Source: "/notes/ops.md", Purge: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Matched != 2 || res.Held != 1 || res.Memories != 1 || res.Facts != 1 || res.Edges != 1 || res.Embeddings != 1 || res.Clusters != 1 {
+		t.Fatalf("purge = %+v", res)
+	}
+
+	var n int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memories WHERE id = ?`, m1).Scan(&n)
+	if n != 0 {
+		t.Fatal("purged memory row should be gone")
+	}
+	s.db.QueryRow(`SELECT COUNT(*) FROM embeddings WHERE memory_id = ?`, m1).Scan(&n)
+	if n != 0 {
+		t.Fatal("embedding should be gone")
+	}
+	s.db.QueryRow(`SELECT COUNT(*) FROM clusters`).Scan(&n)
+	if n != 1 {
+		t.Fatalf("only the emptied cluster should be removed, %d left", n)
+	}
+	if m, _ := s.GetMemory(ctx, held); m == nil || m.DeletedAt != nil {
+		t.Fatal("held memory should be untouched")
+	}
+}
+
+func TestDeleteFacts_ByMemoryFilters(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	m1, _ := s.AddMemory(ctx, &Memory{Content: "scratch", SourceFile: "/s.md", MemoryClass: "scratch"})
+	m2, _ := s.AddMemory(ctx, &Memory{Content: "rule", SourceFile: "/r.md", MemoryClass: "rule"})
+	a, _ := s.AddFact(ctx, &Fact{MemoryID: m1, Subject: "tmp", Predicate: "is", Object: "x", FactType: "kv"})
+	b, _ := s.AddFact(ctx, &Fact{MemoryID: m1, Subject: "tmp", Predicate: "was", Object: "y", FactType: "kv"})
+	keep, _ := s.AddFact(ctx, &Fact{MemoryID: m2, Subject: "deploy", Predicate: "needs", Object: "review", FactType: "kv"})
+	if _, err := s.db.Exec(`INSERT INTO clusters (id, name, fact_count, avg_confidence) VALUES (1, 'mixed', 3, 1)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.Exec(`INSERT INTO fact_clusters (fact_id, cluster_id) VALUES (?, 1), (?, 1), (?, 1)`, a, b, keep); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetArchiveHold(ctx, HoldKindFact, b, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.DeleteFacts(ctx, BatchDelete{Class: "scratch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Matched != 2 || res.Held != 1 || res.Facts != 1 || len(res.IDs) != 1 || res.IDs[0] != a {
+		t.Fatalf("delete facts = %+v", res)
+	}
+	if f, _ := s.GetFact(ctx, a); f != nil {
+		t.Fatal("fact should be gone")
+	}
+	var count int
+	s.db.QueryRow(`SELECT fact_count FROM clusters WHERE id = 1`).Scan(&count)
+	if count != 2 {
+		t.Fatalf("cluster fact_count = %d, want 2", count)
+	}
+}
+
+func TestBatchDelete_StaysInNamespace(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+	work := WithNamespace(ctx, "work")
+	personal := WithNamespace(ctx, "personal")
+
+	var workMems, personalMems []int64
+	for _, c := range []struct {
+		ctx context.Context
+		ids *[]int64
+	}{{work, &workMems}, {personal, &personalMems}} {
+		for _, content := range []string{"region is us-east-1", "owner is sam"} {
+			id, err := s.AddMemory(c.ctx, &Memory{Content: content, SourceFile: "/notes/" + s.Namespace(c.ctx) + ".md"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.AddFact(ctx, &Fact{MemoryID: id, Subject: "infra", Predicate: "note", Object: content, FactType: "kv"}); err != nil {
+				t.Fatal(err)
+			}
+			*c.ids = append(*c.ids, id)
+		}
+	}
+
+	plan, err := s.DeleteFacts(work, BatchDelete{Source: "*", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Matched != 2 {
+		t.Fatalf("work fact dry run matched %d, want 2", plan.Matched)
+	}
+
+	res, err := s.DeleteMemories(work, BatchDelete{Source: "*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Memories != 2 || len(res.IDs) != 2 || res.IDs[0] != workMems[0] || res.IDs[1] != workMems[1] {
+		t.Fatalf("work delete = %+v, want memories %v", res, workMems)
+	}
+	for _, id := range personalMems {
+		if m, _ := s.GetMemory(ctx, id); m == nil || m.DeletedAt != nil {
+			t.Fatalf("personal memory %d should be untouched", id)
+		}
+	}
+	if n, err := s.CountMemories(personal, ListOpts{}); err != nil || n != 2 {
+		t.Fatalf("personal memories = %d, %v", n, err)
+	}
+}
@@ -0,0 +1,177 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Brief levels, from most to least detailed. A cluster brief summarizes one
+// topic cluster's facts within one project; a project brief rolls up that
+// project's cluster briefs; the store brief (the "identity card") rolls up
+// every project brief.
+const (
+	BriefLevelCluster = "cluster"
+	BriefLevelProject = "project"
+	BriefLevelStore   = "store"
+)
+
+// Brief is a stored LLM summary at one level of the brief hierarchy.
+// Fingerprint identifies the inputs it was generated from, so unchanged
+// briefs are not regenerated.
+type Brief struct {
+	Level       string    `json:"level"`
+	Key         string    `json:"key"`
+	Project     string    `json:"project,omitempty"`
+	ClusterID   int64     `json:"cluster_id,omitempty"`
+	Title       string    `json:"title"`
+	Summary     string    `json:"summary"`
+	FactCount   int       `json:"fact_count"`
+	Fingerprint string    `json:"-"`
+	Model       string    `json:"model,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ClusterBriefKey is the key of the cluster brief for project's slice of a
+// cluster.
+func ClusterBriefKey(project string, clusterID int64) string {
+	return project + "|" + strconv.FormatInt(clusterID, 10)
+}
+
+// migrateBriefsTable creates briefs, which holds the hierarchical summaries
+// shown by `cortex brief` and the cortex://brief MCP resources.
+func (s *SQLiteStore) migrateBriefsTable() error {
+	done, err := s.isMetaFlagEnabled("briefs_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS briefs (
+			level       TEXT NOT NULL,
+			key         TEXT NOT NULL,
+			project     TEXT NOT NULL DEFAULT '',
+			cluster_id  INTEGER NOT NULL DEFAULT 0,
+			title       TEXT NOT NULL DEFAULT '',
+			summary     TEXT NOT NULL,
+			fact_count  INTEGER NOT NULL DEFAULT 0,
+			fingerprint TEXT NOT NULL,
+			model       TEXT NOT NULL DEFAULT '',
+			updated_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (level, key)
+		)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('briefs_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating briefs table: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpsertBrief stores b, replacing any brief with the same level and key.
+func (s *SQLiteStore) UpsertBrief(ctx context.Context, b *Brief) error {
+	if b.UpdatedAt.IsZero() {
+		b.UpdatedAt = time.Now().UTC()
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT OR REPLACE INTO briefs (level, key, project, cluster_id, title, summary, fact_count, fingerprint, model, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		b.Level, b.Key, b.Project, b.ClusterID, b.Title, b.Summary, b.FactCount, b.Fingerprint, b.Model, b.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("saving %s brief %q: %w", b.Level, b.Key, err)
+	}
+	return nil
+}
+
+// ListBriefs returns the briefs at level, ordered by project then title.
+func (s *SQLiteStore) ListBriefs(ctx context.Context, level string) ([]Brief, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT level, key, project, cluster_id, title, summary, fact_count, fingerprint, model, updated_at
+		 FROM briefs WHERE level = ? ORDER BY project, fact_count DESC, title`, level)
+	if err != nil {
+		return nil, fmt.Errorf("listing %s briefs: %w", level, err)
+	}
+	defer rows.Close()
+
+	var out []Brief
+	for rows.Next() {
+		var b Brief
+		if err := rows.Scan(&b.Level, &b.Key, &b.Project, &b.ClusterID, &b.Title, &b.Summary,
+			&b.FactCount, &b.Fingerprint, &b.Model, &b.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning brief: %w", err)
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// DeleteBrief removes one brief. Missing briefs are not an error.
+func (s *SQLiteStore) DeleteBrief(ctx context.Context, level, key string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM briefs WHERE level = ? AND key = ?`, level, key); err != nil {
+		return fmt.Errorf("deleting %s brief %q: %w", level, key, err)
+	}
+	return nil
+}
+
+// BriefSlice is the active facts of one cluster within one project, the
+// input to a cluster brief.
+type BriefSlice struct {
+	Project     string
+	ClusterID   int64
+	ClusterName string
+	Facts       []*Fact // highest confidence first, capped by BriefSlices' limit
+	FactCount   int     // all active facts in the slice
+}
+
+// BriefSlices groups active clustered facts by (memory project, cluster).
+// Each slice carries at most limit facts.
+func (s *SQLiteStore) BriefSlices(ctx context.Context, limit int) ([]BriefSlice, error) {
+	if limit <= 0 {
+		limit = 60
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT COALESCE(m.project, ''), c.id, c.name,
+		        f.id, f.memory_id, COALESCE(f.subject, ''), COALESCE(f.predicate, ''), COALESCE(f.object, ''),
+		        COALESCE(f.fact_type, ''), f.confidence
+		 FROM facts f
+		 JOIN fact_clusters fc ON fc.fact_id = f.id
+		 JOIN clusters c ON c.id = fc.cluster_id
+		 JOIN memories m ON m.id = f.memory_id
+		 WHERE (f.superseded_by IS NULL OR f.superseded_by = 0)
+		   AND m.deleted_at IS NULL
+		 ORDER BY COALESCE(m.project, ''), c.id, f.confidence DESC, f.id`)
+	if err != nil {
+		return nil, fmt.Errorf("querying brief slices: %w", err)
+	}
+	defer rows.Close()
+
+	var out []BriefSlice
+	for rows.Next() {
+		var project, clusterName string
+		var clusterID int64
+		f := &Fact{}
+		if err := rows.Scan(&project, &clusterID, &clusterName,
+			&f.ID, &f.MemoryID, &f.Subject, &f.Predicate, &f.Object, &f.FactType, &f.Confidence); err != nil {
+			return nil, fmt.Errorf("scanning brief slice: %w", err)
+		}
+		if n := len(out); n == 0 || out[n-1].Project != project || out[n-1].ClusterID != clusterID {
+			out = append(out, BriefSlice{Project: project, ClusterID: clusterID, ClusterName: strings.TrimSpace(clusterName)})
+		}
+		last := &out[len(out)-1]
+		last.FactCount++
+		if len(last.Facts) < limit {
+			last.Facts = append(last.Facts, f)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading brief slices: %w", err)
+	}
+	return out, nil
+}
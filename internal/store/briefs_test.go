@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBriefsUpsertListDelete(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	b := &Brief{Level: BriefLevelProject, Key: "ops", Project: "ops", Title: "ops", Summary: "v1", FactCount: 4, Fingerprint: "aa"}
+	if err := s.UpsertBrief(ctx, b); err != nil {
+		t.Fatalf("UpsertBrief: %v", err)
+	}
+	b.Summary, b.Fingerprint, b.UpdatedAt = "v2", "bb", b.UpdatedAt.Add(1)
+	if err := s.UpsertBrief(ctx, b); err != nil {
+		t.Fatalf("UpsertBrief (replace): %v", err)
+	}
+	list, err := s.ListBriefs(ctx, BriefLevelProject)
+	if err != nil {
+		t.Fatalf("ListBriefs: %v", err)
+	}
+	if len(list) != 1 || list[0].Summary != "v2" || list[0].Fingerprint != "bb" {
+		t.Fatalf("expected one replaced brief, got %+v", list)
+	}
+	if other, _ := s.ListBriefs(ctx, BriefLevelStore); len(other) != 0 {
+		t.Fatalf("expected no store briefs, got %+v", other)
+	}
+
+	if err := s.DeleteBrief(ctx, BriefLevelProject, "ops"); err != nil {
+		t.Fatalf("DeleteBrief: %v", err)
+	}
+	if list, _ := s.ListBriefs(ctx, BriefLevelProject); len(list) != 0 {
+		t.Fatalf("expected brief deleted, got %+v", list)
+	}
+}
+
+func TestBriefSlices_GroupsByProjectAndCluster(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	seedClusterFacts(t, ctx, s)
+	if _, err := s.RebuildClusters(ctx); err != nil {
+		t.Fatalf("RebuildClusters: %v", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `UPDATE memories SET project = 'desk'`); err != nil {
+		t.Fatal(err)
+	}
+
+	slices, err := s.BriefSlices(ctx, 2)
+	if err != nil {
+		t.Fatalf("BriefSlices: %v", err)
+	}
+	if len(slices) < 2 {
+		t.Fatalf("expected a slice per cluster, got %d", len(slices))
+	}
+	for _, sl := range slices {
+		if sl.Project != "desk" || sl.ClusterID == 0 {
+			t.Fatalf("unexpected slice identity: %+v", sl)
+		}
+		if len(sl.Facts) > 2 || sl.FactCount < len(sl.Facts) {
+			t.Fatalf("expected facts capped at 2 of %d, got %d", sl.FactCount, len(sl.Facts))
+		}
+	}
+}
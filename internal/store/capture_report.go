@@ -0,0 +1,205 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Reasons an auto-captured chunk was not stored, as recorded in
+// capture_rejections.
+const (
+	CaptureRejectDedupe    = "dedupe"     // near-duplicate of a recent memory
+	CaptureRejectLowSignal = "low_signal" // too short or an acknowledgement
+)
+
+// CaptureReport summarizes auto-capture activity in a time window, so
+// operators can tell whether the capture filters are tuned correctly.
+type CaptureReport struct {
+	Since       time.Time        `json:"since"`
+	Sessions    int              `json:"sessions"`     // distinct session keys among captured memories
+	Captured    int              `json:"captured"`     // memories stored from capture sources
+	Deduped     int              `json:"deduped"`      // suppressed as near-duplicates
+	LowSignal   int              `json:"low_signal"`   // rejected as low-signal
+	Facts       int              `json:"facts"`        // facts extracted from captured memories
+	NoFactShare float64          `json:"no_fact_rate"` // share of captured memories with no facts
+	Channels    []CaptureChannel `json:"channels"`     // noisiest first
+}
+
+// CaptureChannel is one channel's share of the capture traffic.
+type CaptureChannel struct {
+	Channel   string  `json:"channel"`
+	Captured  int     `json:"captured"`
+	Deduped   int     `json:"deduped"`
+	LowSignal int     `json:"low_signal"`
+	Facts     int     `json:"facts"`
+	NoiseRate float64 `json:"noise_rate"` // rejected / (captured + rejected)
+}
+
+// Rejected is the number of the channel's chunks that were not stored.
+func (c CaptureChannel) Rejected() int { return c.Deduped + c.LowSignal }
+
+// migrateCaptureRejections adds capture_rejections, one row per captured
+// chunk the dedupe or low-signal filters turned away.
+func (s *SQLiteStore) migrateCaptureRejections() error {
+	done, err := s.isMetaFlagEnabled("capture_rejections_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS capture_rejections (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			reason      TEXT NOT NULL,
+			source_file TEXT NOT NULL DEFAULT '',
+			session_key TEXT NOT NULL DEFAULT '',
+			channel     TEXT NOT NULL DEFAULT '',
+			created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_capture_rejections_created ON capture_rejections(created_at)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('capture_rejections_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("migrating capture rejections: %w", err)
+		}
+	}
+	return nil
+}
+
+// RecordCaptureRejection notes that a captured chunk from sourceFile was
+// not stored for reason. meta supplies the session and channel, if known.
+func (s *SQLiteStore) RecordCaptureRejection(ctx context.Context, reason, sourceFile string, meta *Metadata) error {
+	var session, channel string
+	if meta != nil {
+		session, channel = meta.SessionKey, meta.Channel
+		if session == "" {
+			session = meta.SessionID
+		}
+		if channel == "" {
+			channel = meta.ChannelName
+		}
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO capture_rejections (reason, source_file, session_key, channel, created_at) VALUES (?, ?, ?, ?, ?)`,
+		reason, sourceFile, session, channel, time.Now().UTC(),
+	); err != nil {
+		return fmt.Errorf("recording capture rejection: %w", err)
+	}
+	return nil
+}
+
+// CaptureReport summarizes auto-capture since the given time: memories
+// stored from capture sources (see ResolveSource) with their sessions and
+// facts, chunks the dedupe and low-signal filters rejected, and the top
+// channels ranked by rejected chunks, then by volume.
+func (s *SQLiteStore) CaptureReport(ctx context.Context, since time.Time, topChannels int) (*CaptureReport, error) {
+	rep := &CaptureReport{Since: since.UTC(), Channels: []CaptureChannel{}}
+	channels := map[string]*CaptureChannel{}
+	channel := func(name string) *CaptureChannel {
+		if name == "" {
+			name = "(none)"
+		}
+		c := channels[name]
+		if c == nil {
+			c = &CaptureChannel{Channel: name}
+			channels[name] = c
+		}
+		return c
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT m.source_file,
+			COALESCE(NULLIF(json_extract(m.metadata, '$.session_key'), ''), json_extract(m.metadata, '$.session_id'), ''),
+			COALESCE(NULLIF(json_extract(m.metadata, '$.channel'), ''), json_extract(m.metadata, '$.channel_name'), ''),
+			(SELECT COUNT(*) FROM facts f WHERE f.memory_id = m.id)
+		FROM memories m
+		WHERE m.deleted_at IS NULL AND m.imported_at >= ?`, rep.Since)
+	if err != nil {
+		return nil, fmt.Errorf("reading captured memories: %w", err)
+	}
+	sessions := map[string]bool{}
+	noFacts := 0
+	for rows.Next() {
+		var sourceFile, session, ch string
+		var facts int
+		if err := rows.Scan(&sourceFile, &session, &ch, &facts); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning captured memory: %w", err)
+		}
+		if ResolveSource(sourceFile).Type != SourceTypeCapture {
+			continue
+		}
+		rep.Captured++
+		rep.Facts += facts
+		if facts == 0 {
+			noFacts++
+		}
+		if session != "" {
+			sessions[session] = true
+		}
+		c := channel(ch)
+		c.Captured++
+		c.Facts += facts
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rep.Sessions = len(sessions)
+	if rep.Captured > 0 {
+		rep.NoFactShare = float64(noFacts) / float64(rep.Captured)
+	}
+
+	rows, err = s.db.QueryContext(ctx,
+		`SELECT reason, channel, COUNT(*) FROM capture_rejections WHERE created_at >= ? GROUP BY reason, channel`, rep.Since)
+	if err != nil {
+		return nil, fmt.Errorf("reading capture rejections: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var reason, ch string
+		var n int
+		if err := rows.Scan(&reason, &ch, &n); err != nil {
+			return nil, fmt.Errorf("scanning capture rejections: %w", err)
+		}
+		c := channel(ch)
+		switch reason {
+		case CaptureRejectDedupe:
+			rep.Deduped += n
+			c.Deduped += n
+		case CaptureRejectLowSignal:
+			rep.LowSignal += n
+			c.LowSignal += n
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, c := range channels {
+		if total := c.Captured + c.Rejected(); total > 0 {
+			c.NoiseRate = float64(c.Rejected()) / float64(total)
+		}
+		rep.Channels = append(rep.Channels, *c)
+	}
+	sort.Slice(rep.Channels, func(i, j int) bool {
+		a, b := rep.Channels[i], rep.Channels[j]
+		if a.Rejected() != b.Rejected() {
+			return a.Rejected() > b.Rejected()
+		}
+		if a.Captured != b.Captured {
+			return a.Captured > b.Captured
+		}
+		return strings.Compare(a.Channel, b.Channel) < 0
+	})
+	if topChannels > 0 && len(rep.Channels) > topChannels {
+		rep.Channels = rep.Channels[:topChannels]
+	}
+	return rep, nil
+}
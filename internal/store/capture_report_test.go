@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCaptureReport(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	discord := &Metadata{SessionKey: "agent:main:1", Channel: "discord"}
+	m1, _ := s.AddMemory(ctx, &Memory{Content: "we deploy on fridays", SourceFile: "auto-capture.md", Metadata: discord})
+	s.AddMemory(ctx, &Memory{Content: "lunch plans", SourceFile: "auto-capture.md", Metadata: discord})
+	s.AddMemory(ctx, &Memory{Content: "tg note", SourceFile: "auto-capture.md", Metadata: &Metadata{SessionID: "s2", ChannelName: "#ops"}})
+	s.AddMemory(ctx, &Memory{Content: "a plain file", SourceFile: "/notes/ops.md"})
+	if _, err := s.AddFact(ctx, &Fact{MemoryID: m1, Subject: "deploy", Predicate: "day", Object: "friday", FactType: "kv"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, reason := range []string{CaptureRejectDedupe, CaptureRejectLowSignal, CaptureRejectLowSignal} {
+		if err := s.RecordCaptureRejection(ctx, reason, "auto-capture.md", &Metadata{Channel: "discord"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.RecordCaptureRejection(ctx, CaptureRejectLowSignal, "auto-capture.md", nil); err != nil {
+		t.Fatal(err)
+	}
+	// Outside the window.
+	if _, err := s.db.Exec(`INSERT INTO capture_rejections (reason, channel, created_at) VALUES ('dedupe', 'discord', ?)`,
+		time.Now().UTC().Add(-48*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := s.CaptureReport(ctx, time.Now().Add(-24*time.Hour), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Captured != 3 || rep.Sessions != 2 || rep.Facts != 1 || rep.Deduped != 1 || rep.LowSignal != 3 {
+		t.Fatalf("report = %+v", rep)
+	}
+	if len(rep.Channels) != 3 {
+		t.Fatalf("channels = %+v", rep.Channels)
+	}
+	top := rep.Channels[0]
+	if top.Channel != "discord" || top.Captured != 2 || top.Rejected() != 3 || top.Facts != 1 {
+		t.Fatalf("noisiest channel = %+v", top)
+	}
+	if top.NoiseRate != 0.6 {
+		t.Fatalf("noise rate = %v, want 0.6", top.NoiseRate)
+	}
+
+	rep, err = s.CaptureReport(ctx, time.Now().Add(-24*time.Hour), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Channels) != 1 {
+		t.Fatalf("top=1 returned %d channels", len(rep.Channels))
+	}
+}
@@ -225,10 +225,18 @@ func (s *SQLiteStore) UpdateClusters(ctx context.Context, newFactIDs []int64) (*
 
 // ListClusters returns all clusters ordered by size/cohesion.
 func (s *SQLiteStore) ListClusters(ctx context.Context) ([]Cluster, error) {
+	// Clusters are built across namespaces; a scoped caller sees those
+	// with at least one fact in its namespace.
+	nsClause, nsArgs := s.factNamespaceFilter(ctx, "cf.fact_id")
+	where := ""
+	if nsClause != "" {
+		where = ` WHERE EXISTS (SELECT 1 FROM fact_clusters cf WHERE cf.cluster_id = clusters.id` + nsClause + `)`
+	}
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT id, name, COALESCE(aliases, '[]'), cohesion, fact_count, avg_confidence
-		 FROM clusters
+		 FROM clusters`+where+`
 		 ORDER BY fact_count DESC, cohesion DESC, name ASC`,
+		nsArgs...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("listing clusters: %w", err)
@@ -332,17 +340,17 @@ func (s *SQLiteStore) ListClusterFacts(ctx context.Context, clusterID int64, lim
 	if limit <= 0 {
 		limit = 1000
 	}
+	nsClause, nsArgs := s.factNamespaceFilter(ctx, "f.id")
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT f.id, f.memory_id, f.subject, f.predicate, f.object, f.fact_type,
 		        f.confidence, f.decay_rate, f.last_reinforced, f.source_quote,
 		        f.created_at, f.state, f.superseded_by, COALESCE(f.agent_id, '')
 		 FROM facts f
 		 JOIN fact_clusters fc ON fc.fact_id = f.id
-		 WHERE fc.cluster_id = ?
+		 WHERE fc.cluster_id = ?`+nsClause+`
 		 ORDER BY f.confidence DESC, f.id DESC
 		 LIMIT ?`,
-		clusterID,
-		limit,
+		append(append([]interface{}{clusterID}, nsArgs...), limit)...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("listing cluster facts: %w", err)
@@ -0,0 +1,348 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Co-access edge learning turns fact pairs that keep being retrieved
+// together into weighted relates_to edges, and lets those edges fade once
+// the pair stops showing up together. learned_edges remembers which edges
+// the learner owns, so it never reweights or removes an edge someone else
+// created.
+
+// LearnEdgesOpts tunes LearnCoaccessEdges. Zero values take the defaults.
+type LearnEdgesOpts struct {
+	MinCount   int           // co-accesses before a pair earns an edge (default 5)
+	MinSpan    time.Duration // first to last co-access, so one burst doesn't count (default 24h)
+	DecayAfter time.Duration // idle time before a learned edge starts to fade (default 30 days)
+	HalfLife   time.Duration // idle time that halves a fading edge's weight (default 30 days)
+	MinWeight  float64       // learned edges below this weight are removed (default 0.2)
+	MaxNew     int           // edges created per run (default 100)
+	DryRun     bool          // report changes without writing them
+	Now        time.Time     // clock for idle time (default time.Now)
+}
+
+// DefaultLearnEdgesOpts returns the defaults LearnCoaccessEdges applies.
+func DefaultLearnEdgesOpts() LearnEdgesOpts {
+	return LearnEdgesOpts{
+		MinCount:   5,
+		MinSpan:    24 * time.Hour,
+		DecayAfter: 30 * 24 * time.Hour,
+		HalfLife:   30 * 24 * time.Hour,
+		MinWeight:  0.2,
+		MaxNew:     100,
+	}
+}
+
+// maxLearnedWeight caps learned weights below an explicit edge's 1.0.
+const maxLearnedWeight = 0.95
+
+// LearnedEdgeChange is one edge the learner created, reweighted, or removed.
+type LearnedEdgeChange struct {
+	Action    string  `json:"action"` // create, strengthen, decay, remove
+	EdgeID    int64   `json:"edge_id,omitempty"`
+	FactIDA   int64   `json:"fact_id_a"`
+	FactIDB   int64   `json:"fact_id_b"`
+	Count     int     `json:"count"`
+	OldWeight float64 `json:"old_weight,omitempty"`
+	NewWeight float64 `json:"new_weight"`
+}
+
+// LearnEdgesResult summarizes a LearnCoaccessEdges run.
+type LearnEdgesResult struct {
+	Created      int                 `json:"created"`
+	Strengthened int                 `json:"strengthened"`
+	Decayed      int                 `json:"decayed"`
+	Removed      int                 `json:"removed"`
+	Learned      int                 `json:"learned"` // learned edges left after the run
+	DryRun       bool                `json:"dry_run,omitempty"`
+	Changes      []LearnedEdgeChange `json:"changes,omitempty"`
+}
+
+// migrateCoaccessEdges adds fact_cooccurrence_v1.first_seen, which tells a
+// sustained co-access pattern from a single burst, and creates
+// learned_edges. Existing pairs start with first_seen = last_seen.
+func (s *SQLiteStore) migrateCoaccessEdges() error {
+	done, err := s.isMetaFlagEnabled("coaccess_edges_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`ALTER TABLE fact_cooccurrence_v1 ADD COLUMN first_seen DATETIME`,
+		`UPDATE fact_cooccurrence_v1 SET first_seen = last_seen WHERE first_seen IS NULL`,
+		`CREATE TABLE IF NOT EXISTS learned_edges (
+			fact_id_a  INTEGER NOT NULL,
+			fact_id_b  INTEGER NOT NULL,
+			edge_id    INTEGER, -- NULL once the edge was removed by hand
+			weight     REAL NOT NULL,
+			count      INTEGER NOT NULL,
+			learned_at DATETIME NOT NULL,
+			PRIMARY KEY (fact_id_a, fact_id_b)
+		)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('coaccess_edges_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			if isDuplicateColumnError(err) {
+				continue
+			}
+			return fmt.Errorf("migrating co-access edges: %w", err)
+		}
+	}
+	return nil
+}
+
+// coaccessWeight is the weight of a learned edge for a pair seen together
+// count times and idle since its last co-access: MinCount co-accesses give
+// 0.5, more approach maxLearnedWeight, and past DecayAfter the weight halves
+// every HalfLife. It depends only on stored state, so running the learner
+// more often doesn't make edges fade faster.
+func coaccessWeight(count int, idle time.Duration, opts LearnEdgesOpts) float64 {
+	w := float64(count) / float64(count+opts.MinCount)
+	if w > maxLearnedWeight {
+		w = maxLearnedWeight
+	}
+	if idle > opts.DecayAfter {
+		w *= math.Pow(0.5, float64(idle-opts.DecayAfter)/float64(opts.HalfLife))
+	}
+	return math.Round(w*100) / 100
+}
+
+type learnedEdgeRow struct {
+	edgeID     int64
+	a, b       int64
+	weight     float64
+	count      int
+	lastSeen   time.Time
+	edgeExists bool
+	active     bool
+}
+
+// LearnCoaccessEdges updates the learned relates_to edges from the
+// co-occurrence table. It reweights every edge it learned earlier from its
+// pair's current count and idle time, removing edges whose facts are no
+// longer active or whose weight fell below MinWeight, then creates edges
+// for active pairs seen together at least MinCount times over at least
+// MinSpan. Pairs already linked by a relates_to edge it doesn't own are
+// skipped, and an edge it learned but someone removed is not recreated.
+func (s *SQLiteStore) LearnCoaccessEdges(ctx context.Context, opts LearnEdgesOpts) (*LearnEdgesResult, error) {
+	def := DefaultLearnEdgesOpts()
+	if opts.MinCount <= 0 {
+		opts.MinCount = def.MinCount
+	}
+	if opts.MinSpan <= 0 {
+		opts.MinSpan = def.MinSpan
+	}
+	if opts.DecayAfter <= 0 {
+		opts.DecayAfter = def.DecayAfter
+	}
+	if opts.HalfLife <= 0 {
+		opts.HalfLife = def.HalfLife
+	}
+	if opts.MinWeight <= 0 {
+		opts.MinWeight = def.MinWeight
+	}
+	if opts.MaxNew <= 0 {
+		opts.MaxNew = def.MaxNew
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	now := opts.Now.UTC()
+	result := &LearnEdgesResult{DryRun: opts.DryRun}
+
+	learned, err := s.loadLearnedEdges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, le := range learned {
+		if !le.edgeExists {
+			// Removed by hand: keep the pair as a tombstone so it stays gone.
+			if !opts.DryRun {
+				if _, err := s.db.ExecContext(ctx,
+					`UPDATE learned_edges SET edge_id = NULL WHERE fact_id_a = ? AND fact_id_b = ?`, le.a, le.b,
+				); err != nil {
+					return nil, fmt.Errorf("forgetting removed edge %d: %w", le.edgeID, err)
+				}
+			}
+			continue
+		}
+		weight := 0.0
+		if le.active {
+			weight = coaccessWeight(le.count, now.Sub(le.lastSeen), opts)
+		}
+		change := LearnedEdgeChange{EdgeID: le.edgeID, FactIDA: le.a, FactIDB: le.b, Count: le.count, OldWeight: le.weight, NewWeight: weight}
+		switch {
+		case weight < opts.MinWeight:
+			change.Action = "remove"
+			result.Removed++
+		case weight > le.weight:
+			change.Action = "strengthen"
+			result.Strengthened++
+		case weight < le.weight:
+			change.Action = "decay"
+			result.Decayed++
+		default:
+			result.Learned++
+			continue
+		}
+		if change.Action != "remove" {
+			result.Learned++
+		}
+		result.Changes = append(result.Changes, change)
+		if !opts.DryRun {
+			if err := s.applyLearnedEdgeChange(ctx, change); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	candidates, err := s.coaccessCandidates(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range candidates {
+		if result.Created >= opts.MaxNew {
+			break
+		}
+		weight := coaccessWeight(p.Count, now.Sub(p.LastSeen), opts)
+		if weight < opts.MinWeight {
+			continue
+		}
+		change := LearnedEdgeChange{Action: "create", FactIDA: p.FactIDA, FactIDB: p.FactIDB, Count: p.Count, NewWeight: weight}
+		if !opts.DryRun {
+			id, err := s.createLearnedEdge(ctx, p, weight, now)
+			if err != nil {
+				return nil, err
+			}
+			change.EdgeID = id
+		}
+		result.Created++
+		result.Learned++
+		result.Changes = append(result.Changes, change)
+	}
+	return result, nil
+}
+
+// loadLearnedEdges returns the learned_edges rows that still have an edge,
+// with the pair's current co-occurrence, whether the edge still exists
+// (edge IDs are reused, so the pair and type must match too), and whether
+// both facts are active.
+func (s *SQLiteStore) loadLearnedEdges(ctx context.Context) ([]learnedEdgeRow, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT l.edge_id, l.fact_id_a, l.fact_id_b, l.weight,
+		        COALESCE(c.count, l.count), COALESCE(c.last_seen, l.learned_at),
+		        EXISTS (SELECT 1 FROM fact_edges_v1 e WHERE e.id = l.edge_id AND e.edge_type = 'relates_to'
+		                  AND e.source_fact_id = l.fact_id_a AND e.target_fact_id = l.fact_id_b),
+		        (SELECT COUNT(*) FROM facts f WHERE f.id IN (l.fact_id_a, l.fact_id_b)
+		           AND f.superseded_by IS NULL AND f.state NOT IN ('retired', 'superseded')) = 2
+		 FROM learned_edges l
+		 LEFT JOIN fact_cooccurrence_v1 c ON c.fact_id_a = l.fact_id_a AND c.fact_id_b = l.fact_id_b
+		 WHERE l.edge_id IS NOT NULL
+		 ORDER BY l.edge_id`)
+	if err != nil {
+		return nil, fmt.Errorf("loading learned edges: %w", err)
+	}
+	defer rows.Close()
+
+	var out []learnedEdgeRow
+	for rows.Next() {
+		var le learnedEdgeRow
+		var lastSeen sql.NullString
+		if err := rows.Scan(&le.edgeID, &le.a, &le.b, &le.weight, &le.count, &lastSeen, &le.edgeExists, &le.active); err != nil {
+			return nil, fmt.Errorf("scanning learned edge: %w", err)
+		}
+		le.lastSeen = parseAggregateTime(lastSeen.String)
+		out = append(out, le)
+	}
+	return out, rows.Err()
+}
+
+// coaccessCandidates returns active pairs that qualify for a new learned
+// edge, most frequent first.
+func (s *SQLiteStore) coaccessCandidates(ctx context.Context, opts LearnEdgesOpts) ([]CooccurrencePair, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT c.fact_id_a, c.fact_id_b, c.count, c.last_seen
+		 FROM fact_cooccurrence_v1 c
+		 JOIN facts fa ON fa.id = c.fact_id_a
+		 JOIN facts fb ON fb.id = c.fact_id_b
+		 WHERE c.count >= ?
+		   AND (julianday(c.last_seen) - julianday(COALESCE(c.first_seen, c.last_seen))) * 86400 >= ?
+		   AND fa.superseded_by IS NULL AND fa.state NOT IN ('retired', 'superseded')
+		   AND fb.superseded_by IS NULL AND fb.state NOT IN ('retired', 'superseded')
+		   AND NOT EXISTS (SELECT 1 FROM learned_edges l WHERE l.fact_id_a = c.fact_id_a AND l.fact_id_b = c.fact_id_b)
+		   AND NOT EXISTS (
+		     SELECT 1 FROM fact_edges_v1 e
+		     WHERE e.edge_type = 'relates_to'
+		       AND ((e.source_fact_id = c.fact_id_a AND e.target_fact_id = c.fact_id_b)
+		         OR (e.source_fact_id = c.fact_id_b AND e.target_fact_id = c.fact_id_a))
+		   )
+		 ORDER BY c.count DESC, c.fact_id_a, c.fact_id_b`,
+		opts.MinCount, int64(opts.MinSpan/time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("finding co-access candidates: %w", err)
+	}
+	defer rows.Close()
+	return scanCooccurrences(rows)
+}
+
+func (s *SQLiteStore) createLearnedEdge(ctx context.Context, p CooccurrencePair, weight float64, now time.Time) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin learned edge: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO fact_edges_v1 (source_fact_id, target_fact_id, edge_type, confidence, source)
+		 VALUES (?, ?, 'relates_to', ?, 'inferred')`,
+		p.FactIDA, p.FactIDB, weight,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("creating learned edge %d-%d: %w", p.FactIDA, p.FactIDB, err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO learned_edges (fact_id_a, fact_id_b, edge_id, weight, count, learned_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		p.FactIDA, p.FactIDB, id, weight, p.Count, now,
+	); err != nil {
+		return 0, fmt.Errorf("recording learned edge %d: %w", id, err)
+	}
+	return id, tx.Commit()
+}
+
+func (s *SQLiteStore) applyLearnedEdgeChange(ctx context.Context, c LearnedEdgeChange) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin learned edge update: %w", err)
+	}
+	defer tx.Rollback()
+
+	var stmts []string
+	var args [][]interface{}
+	if c.Action == "remove" {
+		stmts = []string{`DELETE FROM fact_edges_v1 WHERE id = ?`, `DELETE FROM learned_edges WHERE fact_id_a = ? AND fact_id_b = ?`}
+		args = [][]interface{}{{c.EdgeID}, {c.FactIDA, c.FactIDB}}
+	} else {
+		stmts = []string{`UPDATE fact_edges_v1 SET confidence = ? WHERE id = ?`, `UPDATE learned_edges SET weight = ?, count = ? WHERE fact_id_a = ? AND fact_id_b = ?`}
+		args = [][]interface{}{{c.NewWeight, c.EdgeID}, {c.NewWeight, c.Count, c.FactIDA, c.FactIDB}}
+	}
+	for i, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt, args[i]...); err != nil {
+			return fmt.Errorf("updating learned edge %d: %w", c.EdgeID, err)
+		}
+	}
+	return tx.Commit()
+}
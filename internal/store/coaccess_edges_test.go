@@ -0,0 +1,175 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// setCooccurrence overwrites a pair's co-occurrence row with the given
+// count and first/last sighting.
+func setCooccurrence(t *testing.T, s *SQLiteStore, a, b int64, count int, first, last time.Time) {
+	t.Helper()
+	if a > b {
+		a, b = b, a
+	}
+	const layout = "2006-01-02 15:04:05"
+	if _, err := s.db.Exec(
+		`INSERT INTO fact_cooccurrence_v1 (fact_id_a, fact_id_b, count, first_seen, last_seen) VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(fact_id_a, fact_id_b) DO UPDATE SET count = excluded.count, first_seen = excluded.first_seen, last_seen = excluded.last_seen`,
+		a, b, count, first.UTC().Format(layout), last.UTC().Format(layout),
+	); err != nil {
+		t.Fatalf("setting co-occurrence: %v", err)
+	}
+}
+
+func edgeConfidence(t *testing.T, s *SQLiteStore, id int64) (float64, bool) {
+	t.Helper()
+	var c float64
+	if err := s.db.QueryRow(`SELECT confidence FROM fact_edges_v1 WHERE id = ?`, id).Scan(&c); err != nil {
+		return 0, false
+	}
+	return c, true
+}
+
+func TestLearnCoaccessEdges_CreateStrengthenDecayRemove(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "test", SourceFile: "t.md"})
+	f1, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "a", Predicate: "p", Object: "o1", FactType: "kv"})
+	f2, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "b", Predicate: "p", Object: "o2", FactType: "kv"})
+	f3, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "c", Predicate: "p", Object: "o3", FactType: "kv"})
+
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	// f1-f2: sustained over two days. f1-f3: just as frequent, but in one burst.
+	setCooccurrence(t, s, f1, f2, 5, now.Add(-48*time.Hour), now.Add(-time.Hour))
+	setCooccurrence(t, s, f1, f3, 9, now.Add(-2*time.Hour), now.Add(-time.Hour))
+
+	preview, err := s.LearnCoaccessEdges(ctx, LearnEdgesOpts{Now: now, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if preview.Created != 1 || len(preview.Changes) != 1 || preview.Changes[0].EdgeID != 0 {
+		t.Fatalf("dry run = %+v", preview)
+	}
+	if edges, _ := s.GetEdgesForFact(ctx, f1); len(edges) != 0 {
+		t.Fatalf("dry run should not create edges, got %d", len(edges))
+	}
+
+	res, err := s.LearnCoaccessEdges(ctx, LearnEdgesOpts{Now: now})
+	if err != nil {
+		t.Fatalf("LearnCoaccessEdges: %v", err)
+	}
+	if res.Created != 1 || res.Learned != 1 {
+		t.Fatalf("first run = %+v", res)
+	}
+	created := res.Changes[0]
+	if created.FactIDA != f1 || created.FactIDB != f2 || created.NewWeight != 0.5 {
+		t.Fatalf("created = %+v", created)
+	}
+	edges, _ := s.GetEdgesForFact(ctx, f2)
+	if len(edges) != 1 || edges[0].EdgeType != EdgeTypeRelatesTo || edges[0].Source != EdgeSourceInferred {
+		t.Fatalf("learned edge = %+v", edges)
+	}
+
+	// An unchanged pair is left alone; more co-access strengthens it.
+	if res, _ := s.LearnCoaccessEdges(ctx, LearnEdgesOpts{Now: now}); len(res.Changes) != 0 || res.Learned != 1 {
+		t.Fatalf("rerun = %+v", res)
+	}
+	setCooccurrence(t, s, f1, f2, 20, now.Add(-48*time.Hour), now)
+	res, _ = s.LearnCoaccessEdges(ctx, LearnEdgesOpts{Now: now})
+	if res.Strengthened != 1 {
+		t.Fatalf("strengthen = %+v", res)
+	}
+	if c, _ := edgeConfidence(t, s, created.EdgeID); c != 0.8 {
+		t.Fatalf("strengthened weight = %.2f, want 0.80", c)
+	}
+
+	// One half-life past the grace period halves the weight; far past it
+	// removes the edge and frees the pair to be learned again.
+	later := now.Add(60 * 24 * time.Hour)
+	res, _ = s.LearnCoaccessEdges(ctx, LearnEdgesOpts{Now: later})
+	if res.Decayed != 1 {
+		t.Fatalf("decay = %+v", res)
+	}
+	if c, _ := edgeConfidence(t, s, created.EdgeID); c != 0.4 {
+		t.Fatalf("decayed weight = %.2f, want 0.40", c)
+	}
+	res, _ = s.LearnCoaccessEdges(ctx, LearnEdgesOpts{Now: now.Add(120 * 24 * time.Hour)})
+	if res.Removed != 1 || res.Learned != 0 {
+		t.Fatalf("remove = %+v", res)
+	}
+	if _, ok := edgeConfidence(t, s, created.EdgeID); ok {
+		t.Fatal("faded edge should be removed")
+	}
+}
+
+func TestLearnCoaccessEdges_LeavesOtherEdgesAlone(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "test", SourceFile: "t.md"})
+	f1, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "a", Predicate: "p", Object: "o1", FactType: "kv"})
+	f2, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "b", Predicate: "p", Object: "o2", FactType: "kv"})
+	f3, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "c", Predicate: "p", Object: "o3", FactType: "kv"})
+
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	explicit := &FactEdge{SourceFactID: f2, TargetFactID: f1, EdgeType: EdgeTypeRelatesTo}
+	if err := s.AddEdge(ctx, explicit); err != nil {
+		t.Fatal(err)
+	}
+	setCooccurrence(t, s, f1, f2, 50, now.Add(-300*24*time.Hour), now.Add(-200*24*time.Hour))
+	setCooccurrence(t, s, f1, f3, 10, now.Add(-72*time.Hour), now)
+
+	res, err := s.LearnCoaccessEdges(ctx, LearnEdgesOpts{Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Created != 1 || res.Changes[0].FactIDB != f3 {
+		t.Fatalf("only f1-f3 should be learned: %+v", res)
+	}
+	if c, ok := edgeConfidence(t, s, explicit.ID); !ok || c != 1.0 {
+		t.Fatalf("explicit edge should be untouched, confidence %.2f ok=%v", c, ok)
+	}
+
+	// A learned edge removed by hand stays removed.
+	if err := s.RemoveEdge(ctx, res.Changes[0].EdgeID); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := s.LearnCoaccessEdges(ctx, LearnEdgesOpts{Now: now}); res.Created != 0 || res.Learned != 0 {
+		t.Fatalf("removed edge should not be relearned: %+v", res)
+	}
+
+	// Superseding a fact drops its learned edges.
+	f4, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "d", Predicate: "p", Object: "o4", FactType: "kv"})
+	setCooccurrence(t, s, f3, f4, 10, now.Add(-72*time.Hour), now)
+	if res, err := s.LearnCoaccessEdges(ctx, LearnEdgesOpts{Now: now}); err != nil || res.Created != 1 {
+		t.Fatalf("f3-f4 should be learned: %+v, %v", res, err)
+	}
+	if err := s.SupersedeFact(ctx, f4, f2, "replaced"); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := s.LearnCoaccessEdges(ctx, LearnEdgesOpts{Now: now}); res.Removed != 1 {
+		t.Fatalf("superseded fact's edge should be removed: %+v", res)
+	}
+}
+
+func TestRecordCooccurrence_SetsFirstSeen(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "test", SourceFile: "t.md"})
+	f1, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "a", Predicate: "p", Object: "o1", FactType: "kv"})
+	f2, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "b", Predicate: "p", Object: "o2", FactType: "kv"})
+	if err := s.RecordCooccurrence(ctx, f1, f2); err != nil {
+		t.Fatal(err)
+	}
+	var first, last string
+	if err := s.db.QueryRow(`SELECT first_seen, last_seen FROM fact_cooccurrence_v1`).Scan(&first, &last); err != nil {
+		t.Fatal(err)
+	}
+	if first == "" || first != last {
+		t.Fatalf("first_seen = %q, last_seen = %q", first, last)
+	}
+}
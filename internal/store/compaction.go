@@ -0,0 +1,304 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Compaction (cortex compact) moves the raw content of old memories whose
+// facts have already been extracted into memory_archive, gzip-compressed,
+// and leaves a one-line stub in memories.content. The memory row, its
+// content hash (so re-imports still dedupe), its facts, and its embedding
+// are kept; RestoreCompactedMemories puts the original content back.
+
+// DefaultCompactMinBytes is the smallest memory CompactMemories archives;
+// below it the stub and gzip overhead eat most of the saving.
+const DefaultCompactMinBytes = 512
+
+// compactPreviewRunes bounds the preview kept in a compacted memory's stub.
+const compactPreviewRunes = 120
+
+// CompactOptions selects the memories CompactMemories archives.
+type CompactOptions struct {
+	OlderThan time.Time // only memories imported before this
+	Classes   []string  // memory classes to compact; empty means any class
+	MinBytes  int       // skip smaller memories (0 = DefaultCompactMinBytes)
+	Limit     int       // 0 = no limit
+	DryRun    bool
+}
+
+// CompactionReport summarizes a CompactMemories run. ArchivedBytes is the
+// compressed size; SavedBytes is what the memories table and its keyword
+// index shrink by, net of the archive and the stubs.
+type CompactionReport struct {
+	DryRun        bool    `json:"dry_run,omitempty"`
+	Compacted     int     `json:"compacted"`
+	Skipped       int     `json:"skipped,omitempty"` // would not have saved space
+	OriginalBytes int64   `json:"original_bytes"`
+	ArchivedBytes int64   `json:"archived_bytes"`
+	SavedBytes    int64   `json:"saved_bytes"`
+	MemoryIDs     []int64 `json:"memory_ids,omitempty"`
+}
+
+// CompactionStats describes memory_archive as a whole.
+type CompactionStats struct {
+	Memories      int   `json:"memories"`
+	OriginalBytes int64 `json:"original_bytes"`
+	ArchivedBytes int64 `json:"archived_bytes"`
+}
+
+// migrateMemoryArchiveTable creates memory_archive, which holds the
+// compressed original content of compacted memories.
+func (s *SQLiteStore) migrateMemoryArchiveTable() error {
+	done, err := s.isMetaFlagEnabled("memory_archive_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS memory_archive (
+			memory_id      INTEGER PRIMARY KEY REFERENCES memories(id) ON DELETE CASCADE,
+			content        BLOB NOT NULL,
+			original_bytes INTEGER NOT NULL,
+			archived_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('memory_archive_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating memory_archive table: %w", err)
+		}
+	}
+	return nil
+}
+
+// compactedStub is the content a compacted memory keeps: the start of its
+// first line and a pointer to the restore command.
+func compactedStub(id int64, content string) string {
+	preview := strings.TrimSpace(content)
+	if i := strings.IndexByte(preview, '\n'); i >= 0 {
+		preview = strings.TrimSpace(preview[:i])
+	}
+	if utf8.RuneCountInString(preview) > compactPreviewRunes {
+		preview = string([]rune(preview)[:compactPreviewRunes]) + "…"
+	}
+	return fmt.Sprintf("%s [compacted: %d bytes archived; cortex compact restore %d]", preview, len(content), id)
+}
+
+func gzipContent(content string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write([]byte(content)); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzipContent(data []byte) (string, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// CompactMemories archives the raw content of live memories imported before
+// opts.OlderThan that have at least one extracted fact. Memories under
+// archive hold, already compacted, or smaller than opts.MinBytes are left
+// alone, as are memories that would not shrink. updated_at is not touched:
+// the memory's meaning has not changed, only where its text is kept.
+func (s *SQLiteStore) CompactMemories(ctx context.Context, opts CompactOptions) (*CompactionReport, error) {
+	if opts.OlderThan.IsZero() {
+		return nil, fmt.Errorf("compaction needs an age cutoff")
+	}
+	minBytes := opts.MinBytes
+	if minBytes <= 0 {
+		minBytes = DefaultCompactMinBytes
+	}
+
+	query := `SELECT id, content FROM memories
+		WHERE deleted_at IS NULL AND imported_at < ? AND LENGTH(CAST(content AS BLOB)) >= ?
+		  AND id NOT IN (SELECT memory_id FROM memory_archive)
+		  AND EXISTS (SELECT 1 FROM facts WHERE facts.memory_id = memories.id)
+		  AND ` + NotHeldMemoryClause
+	args := []interface{}{opts.OlderThan.UTC(), minBytes}
+	if len(opts.Classes) > 0 {
+		query += ` AND memory_class IN (` + strings.TrimSuffix(strings.Repeat("?,", len(opts.Classes)), ",") + `)`
+		for _, c := range opts.Classes {
+			args = append(args, NormalizeMemoryClass(c))
+		}
+	}
+	query += ` ORDER BY imported_at, id`
+	if opts.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, opts.Limit)
+	}
+
+	type candidate struct {
+		id      int64
+		content string
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("selecting memories to compact: %w", err)
+	}
+	var candidates []candidate
+	for rows.Next() {
+		var c candidate
+		if err := rows.Scan(&c.id, &c.content); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning memory to compact: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("selecting memories to compact: %w", err)
+	}
+
+	report := &CompactionReport{DryRun: opts.DryRun}
+	for _, c := range candidates {
+		archived, err := gzipContent(c.content)
+		if err != nil {
+			return report, fmt.Errorf("compressing memory %d: %w", c.id, err)
+		}
+		stub := compactedStub(c.id, c.content)
+		saved := int64(len(c.content)) - int64(len(stub)) - int64(len(archived))
+		if saved <= 0 {
+			report.Skipped++
+			continue
+		}
+		if !opts.DryRun {
+			if err := s.compactMemory(ctx, c.id, stub, archived, len(c.content)); err != nil {
+				return report, err
+			}
+		}
+		report.Compacted++
+		report.OriginalBytes += int64(len(c.content))
+		report.ArchivedBytes += int64(len(archived))
+		report.SavedBytes += saved
+		report.MemoryIDs = append(report.MemoryIDs, c.id)
+	}
+	return report, nil
+}
+
+func (s *SQLiteStore) compactMemory(ctx context.Context, id int64, stub string, archived []byte, originalBytes int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning compaction of memory %d: %w", id, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO memory_archive (memory_id, content, original_bytes, archived_at) VALUES (?, ?, ?, ?)`,
+		id, archived, originalBytes, time.Now().UTC()); err != nil {
+		return fmt.Errorf("archiving memory %d: %w", id, err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE memories SET content = ? WHERE id = ?`, stub, id); err != nil {
+		return archiveHoldError(fmt.Errorf("compacting memory %d: %w", id, err), HoldKindMemory, id)
+	}
+	return tx.Commit()
+}
+
+// RestoreCompactedMemories puts the archived content of the given compacted
+// memories back (all of them when ids is empty) and drops their archive
+// rows. It returns the IDs restored; IDs that are not compacted are ignored.
+func (s *SQLiteStore) RestoreCompactedMemories(ctx context.Context, ids []int64) ([]int64, error) {
+	query := `SELECT memory_id FROM memory_archive ORDER BY memory_id`
+	var args []interface{}
+	if len(ids) > 0 {
+		query = `SELECT memory_id FROM memory_archive WHERE memory_id IN (` +
+			strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",") + `) ORDER BY memory_id`
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	}
+	archived, err := s.queryMemoryIDs(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing compacted memories: %w", err)
+	}
+
+	var restored []int64
+	for _, id := range archived {
+		if err := s.restoreCompactedMemory(ctx, id); err != nil {
+			return restored, err
+		}
+		restored = append(restored, id)
+	}
+	return restored, nil
+}
+
+func (s *SQLiteStore) restoreCompactedMemory(ctx context.Context, id int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning restore of memory %d: %w", id, err)
+	}
+	defer tx.Rollback()
+
+	var data []byte
+	if err := tx.QueryRowContext(ctx, `SELECT content FROM memory_archive WHERE memory_id = ?`, id).Scan(&data); err != nil {
+		return fmt.Errorf("reading archive of memory %d: %w", id, err)
+	}
+	content, err := gunzipContent(data)
+	if err != nil {
+		return fmt.Errorf("decompressing memory %d: %w", id, err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE memories SET content = ? WHERE id = ?`, content, id); err != nil {
+		return fmt.Errorf("restoring memory %d: %w", id, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM memory_archive WHERE memory_id = ?`, id); err != nil {
+		return fmt.Errorf("dropping archive of memory %d: %w", id, err)
+	}
+	return tx.Commit()
+}
+
+// ArchivedMemoryContent returns the original content of a compacted memory
+// without restoring it, or ok=false when the memory is not compacted.
+func (s *SQLiteStore) ArchivedMemoryContent(ctx context.Context, id int64) (content string, ok bool, err error) {
+	var data []byte
+	err = s.db.QueryRowContext(ctx, `SELECT content FROM memory_archive WHERE memory_id = ?`, id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("reading archive of memory %d: %w", id, err)
+	}
+	content, err = gunzipContent(data)
+	if err != nil {
+		return "", false, fmt.Errorf("decompressing memory %d: %w", id, err)
+	}
+	return content, true, nil
+}
+
+// GetCompactionStats reports how many memories are compacted and their
+// original and archived sizes.
+func (s *SQLiteStore) GetCompactionStats(ctx context.Context) (*CompactionStats, error) {
+	var st CompactionStats
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*), COALESCE(SUM(original_bytes), 0), COALESCE(SUM(LENGTH(content)), 0) FROM memory_archive`,
+	).Scan(&st.Memories, &st.OriginalBytes, &st.ArchivedBytes); err != nil {
+		return nil, fmt.Errorf("reading compaction stats: %w", err)
+	}
+	return &st, nil
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompactMemories_ArchiveAndRestore(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+
+	if err := s.migrateMemoryArchiveTable(); err != nil {
+		t.Fatalf("second migrateMemoryArchiveTable call failed: %v", err)
+	}
+
+	transcript := "Standup transcript\n" + strings.Repeat("we talked about the deploy pipeline and the flaky test again. ", 40)
+	addWithFact := func(content, class string) int64 {
+		t.Helper()
+		id, err := s.AddMemory(ctx, &Memory{Content: content, SourceFile: "chat.md", MemoryClass: class})
+		if err != nil {
+			t.Fatalf("AddMemory: %v", err)
+		}
+		if _, err := s.AddFact(ctx, &Fact{MemoryID: id, Subject: "deploy", Predicate: "status", Object: "flaky", FactType: "state"}); err != nil {
+			t.Fatalf("AddFact: %v", err)
+		}
+		return id
+	}
+	scratch := addWithFact(transcript, MemoryClassScratch)
+	rule := addWithFact(transcript+" rule", MemoryClassRule)
+	small := addWithFact("short note about deploys", MemoryClassScratch)
+	noFacts, _ := s.AddMemory(ctx, &Memory{Content: transcript + " no facts", MemoryClass: MemoryClassScratch})
+	held := addWithFact(transcript+" held", MemoryClassScratch)
+	if err := s.SetArchiveHold(ctx, HoldKindMemory, held, "audit"); err != nil {
+		t.Fatalf("SetArchiveHold: %v", err)
+	}
+
+	cutoff := time.Now().Add(time.Hour)
+	if _, err := s.CompactMemories(ctx, CompactOptions{}); err == nil {
+		t.Fatal("expected an error without a cutoff")
+	}
+	if r, _ := s.CompactMemories(ctx, CompactOptions{OlderThan: time.Now().Add(-time.Hour)}); r.Compacted != 0 {
+		t.Fatalf("nothing is old enough yet, compacted %d", r.Compacted)
+	}
+
+	dry, err := s.CompactMemories(ctx, CompactOptions{OlderThan: cutoff, Classes: []string{"scratch", "status"}, DryRun: true})
+	if err != nil {
+		t.Fatalf("CompactMemories dry run: %v", err)
+	}
+	if dry.Compacted != 1 || dry.MemoryIDs[0] != scratch || dry.SavedBytes <= 0 {
+		t.Fatalf("dry run = %+v, want only memory %d", dry, scratch)
+	}
+	if m, _ := s.GetMemory(ctx, scratch); m.Content != transcript {
+		t.Fatal("dry run rewrote content")
+	}
+
+	report, err := s.CompactMemories(ctx, CompactOptions{OlderThan: cutoff, Classes: []string{"scratch", "status"}})
+	if err != nil {
+		t.Fatalf("CompactMemories: %v", err)
+	}
+	if report.Compacted != 1 || report.OriginalBytes != int64(len(transcript)) || report.ArchivedBytes >= report.OriginalBytes {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	m, _ := s.GetMemory(ctx, scratch)
+	if !strings.HasPrefix(m.Content, "Standup transcript [compacted:") || !strings.Contains(m.Content, "cortex compact restore") {
+		t.Fatalf("unexpected stub: %q", m.Content)
+	}
+	if facts, _ := s.GetFactsByMemoryIDs(ctx, []int64{scratch}); len(facts) != 1 {
+		t.Fatalf("compaction should keep facts, got %d", len(facts))
+	}
+	for _, id := range []int64{rule, small, noFacts, held} {
+		if _, ok, _ := s.ArchivedMemoryContent(ctx, id); ok {
+			t.Fatalf("memory %d should not be compacted", id)
+		}
+	}
+
+	// Compacting again finds nothing new.
+	if again, _ := s.CompactMemories(ctx, CompactOptions{OlderThan: cutoff, Classes: []string{"scratch"}}); again.Compacted != 0 {
+		t.Fatalf("re-compaction = %+v", again)
+	}
+
+	content, ok, err := s.ArchivedMemoryContent(ctx, scratch)
+	if err != nil || !ok || content != transcript {
+		t.Fatalf("ArchivedMemoryContent = %v, %v", ok, err)
+	}
+	stats, err := s.GetCompactionStats(ctx)
+	if err != nil || stats.Memories != 1 || stats.OriginalBytes != int64(len(transcript)) {
+		t.Fatalf("GetCompactionStats = %+v, %v", stats, err)
+	}
+
+	restored, err := s.RestoreCompactedMemories(ctx, []int64{scratch, rule})
+	if err != nil || len(restored) != 1 || restored[0] != scratch {
+		t.Fatalf("RestoreCompactedMemories = %v, %v", restored, err)
+	}
+	if m, _ := s.GetMemory(ctx, scratch); m.Content != transcript {
+		t.Fatal("restore did not bring the content back")
+	}
+	if stats, _ := s.GetCompactionStats(ctx); stats.Memories != 0 {
+		t.Fatalf("archive should be empty after restore, got %+v", stats)
+	}
+
+	// Without a class filter every eligible memory is compacted, and
+	// restoring with no IDs brings them all back.
+	all, err := s.CompactMemories(ctx, CompactOptions{OlderThan: cutoff})
+	if err != nil || all.Compacted != 2 {
+		t.Fatalf("CompactMemories (any class) = %+v, %v", all, err)
+	}
+	if restored, err := s.RestoreCompactedMemories(ctx, nil); err != nil || len(restored) != 2 {
+		t.Fatalf("RestoreCompactedMemories(all) = %v, %v", restored, err)
+	}
+}
@@ -0,0 +1,145 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ConflictException records an accepted disagreement: two facts that look
+// contradictory but are both true (different contexts). Excepted pairs are
+// left out of conflict detection, reports, lifecycle supersedes, and alerts.
+type ConflictException struct {
+	ID        int64     `json:"id"`
+	Fact1ID   int64     `json:"fact1_id"` // lower fact ID of the pair
+	Fact2ID   int64     `json:"fact2_id"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *SQLiteStore) migrateConflictExceptionsTable() error {
+	done, err := s.isMetaFlagEnabled("conflict_exceptions_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS conflict_exceptions (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			fact1_id   INTEGER NOT NULL REFERENCES facts(id) ON DELETE CASCADE,
+			fact2_id   INTEGER NOT NULL REFERENCES facts(id) ON DELETE CASCADE,
+			reason     TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (fact1_id, fact2_id),
+			CHECK (fact1_id < fact2_id)
+		)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('conflict_exceptions_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating conflict_exceptions table: %w", err)
+		}
+	}
+	return nil
+}
+
+// IgnoreConflict records that the two facts may disagree. Re-ignoring a pair
+// updates its reason. Open conflict alerts for the pair are acknowledged.
+func (s *SQLiteStore) IgnoreConflict(ctx context.Context, factA, factB int64, reason string) (*ConflictException, error) {
+	if factA == factB {
+		return nil, fmt.Errorf("a fact cannot conflict with itself")
+	}
+	for _, id := range []int64{factA, factB} {
+		f, err := s.GetFact(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if f == nil {
+			return nil, fmt.Errorf("fact %d not found", id)
+		}
+	}
+	pair := conflictPairKey(factA, factB)
+	reason = strings.TrimSpace(reason)
+	now := time.Now().UTC()
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO conflict_exceptions (fact1_id, fact2_id, reason, created_at) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(fact1_id, fact2_id) DO UPDATE SET reason = excluded.reason`,
+		pair[0], pair[1], reason, now,
+	); err != nil {
+		return nil, fmt.Errorf("recording conflict exception: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE alerts SET acknowledged = 1, acknowledged_at = ?
+		 WHERE alert_type = 'conflict' AND acknowledged = 0
+		   AND ((fact_id = ? AND related_fact_id = ?) OR (fact_id = ? AND related_fact_id = ?))`,
+		now, pair[0], pair[1], pair[1], pair[0],
+	); err != nil {
+		return nil, fmt.Errorf("acknowledging conflict alerts: %w", err)
+	}
+
+	e := &ConflictException{Fact1ID: pair[0], Fact2ID: pair[1]}
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT id, reason, created_at FROM conflict_exceptions WHERE fact1_id = ? AND fact2_id = ?`,
+		pair[0], pair[1],
+	).Scan(&e.ID, &e.Reason, &e.CreatedAt); err != nil {
+		return nil, fmt.Errorf("reading conflict exception: %w", err)
+	}
+	return e, nil
+}
+
+// UnignoreConflict removes a pair's exception so it is detected again.
+// Returns false when the pair was not ignored.
+func (s *SQLiteStore) UnignoreConflict(ctx context.Context, factA, factB int64) (bool, error) {
+	pair := conflictPairKey(factA, factB)
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM conflict_exceptions WHERE fact1_id = ? AND fact2_id = ?`, pair[0], pair[1])
+	if err != nil {
+		return false, fmt.Errorf("removing conflict exception: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// ListConflictExceptions returns every ignored pair, newest first.
+func (s *SQLiteStore) ListConflictExceptions(ctx context.Context) ([]ConflictException, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, fact1_id, fact2_id, reason, created_at FROM conflict_exceptions ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("listing conflict exceptions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ConflictException
+	for rows.Next() {
+		var e ConflictException
+		if err := rows.Scan(&e.ID, &e.Fact1ID, &e.Fact2ID, &e.Reason, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning conflict exception: %w", err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// ignoredConflictPairs returns the excepted pairs keyed by conflictPairKey.
+func (s *SQLiteStore) ignoredConflictPairs(ctx context.Context) (map[[2]int64]bool, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT fact1_id, fact2_id FROM conflict_exceptions`)
+	if err != nil {
+		return nil, fmt.Errorf("loading conflict exceptions: %w", err)
+	}
+	defer rows.Close()
+
+	pairs := map[[2]int64]bool{}
+	for rows.Next() {
+		var a, b int64
+		if err := rows.Scan(&a, &b); err != nil {
+			return nil, fmt.Errorf("scanning conflict exception: %w", err)
+		}
+		pairs[conflictPairKey(a, b)] = true
+	}
+	return pairs, rows.Err()
+}
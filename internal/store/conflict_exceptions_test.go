@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIgnoreConflict_SuppressesDetectionAndAlerts(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "Test memory", SourceFile: "test.md"})
+	home := &Fact{MemoryID: memID, Subject: "Q", Predicate: "lives_in", Object: "Philadelphia", FactType: "identity"}
+	s.AddFact(ctx, home)
+	work := &Fact{MemoryID: memID, Subject: "Q", Predicate: "lives_in", Object: "New York", FactType: "identity"}
+	s.AddFact(ctx, work)
+
+	conflicts, err := s.GetAttributeConflicts(ctx)
+	if err != nil {
+		t.Fatalf("GetAttributeConflicts: %v", err)
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict before ignoring, got %d", len(conflicts))
+	}
+	if _, err := s.RecordConflictAlert(ctx, conflicts[0], 0.9, "rule"); err != nil {
+		t.Fatalf("RecordConflictAlert: %v", err)
+	}
+
+	// Argument order does not matter; the pair is stored low ID first.
+	e, err := s.IgnoreConflict(ctx, work.ID, home.ID, "  weekday apartment  ")
+	if err != nil {
+		t.Fatalf("IgnoreConflict: %v", err)
+	}
+	if e.Fact1ID != home.ID || e.Fact2ID != work.ID || e.Reason != "weekday apartment" {
+		t.Fatalf("unexpected exception: %+v", e)
+	}
+
+	conflicts, err = s.GetAttributeConflicts(ctx)
+	if err != nil {
+		t.Fatalf("GetAttributeConflicts: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Fatalf("expected ignored pair to be skipped, got %d conflicts", len(conflicts))
+	}
+	live, err := s.CheckConflictsForFact(ctx, work)
+	if err != nil {
+		t.Fatalf("CheckConflictsForFact: %v", err)
+	}
+	if len(live) != 0 {
+		t.Fatalf("expected no live conflicts for ignored pair, got %d", len(live))
+	}
+	unacked := false
+	alerts, err := s.ListAlerts(ctx, AlertFilter{Type: AlertTypeConflict, Acknowledged: &unacked})
+	if err != nil {
+		t.Fatalf("ListAlerts: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected open conflict alert to be acknowledged, got %d", len(alerts))
+	}
+
+	// Re-ignoring updates the reason instead of adding a row.
+	if _, err := s.IgnoreConflict(ctx, home.ID, work.ID, "commutes"); err != nil {
+		t.Fatalf("IgnoreConflict again: %v", err)
+	}
+	list, err := s.ListConflictExceptions(ctx)
+	if err != nil {
+		t.Fatalf("ListConflictExceptions: %v", err)
+	}
+	if len(list) != 1 || list[0].Reason != "commutes" {
+		t.Fatalf("unexpected exceptions: %+v", list)
+	}
+
+	removed, err := s.UnignoreConflict(ctx, work.ID, home.ID)
+	if err != nil || !removed {
+		t.Fatalf("UnignoreConflict = %v, %v", removed, err)
+	}
+	conflicts, err = s.GetAttributeConflicts(ctx)
+	if err != nil {
+		t.Fatalf("GetAttributeConflicts: %v", err)
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("expected conflict to return after unignore, got %d", len(conflicts))
+	}
+	if removed, _ := s.UnignoreConflict(ctx, work.ID, home.ID); removed {
+		t.Fatal("expected second unignore to report nothing removed")
+	}
+}
+
+func TestIgnoreConflict_SuppressesNegation(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "Test memory", SourceFile: "test.md"})
+	pos := &Fact{MemoryID: memID, Subject: "api", Predicate: "uses", Object: "redis", FactType: "relationship"}
+	s.AddFact(ctx, pos)
+	neg := &Fact{MemoryID: memID, Subject: "api", Predicate: "does not use", Object: "redis", FactType: "relationship"}
+	s.AddFact(ctx, neg)
+
+	if _, err := s.IgnoreConflict(ctx, pos.ID, neg.ID, "staging only"); err != nil {
+		t.Fatalf("IgnoreConflict: %v", err)
+	}
+	conflicts, err := s.GetAttributeConflicts(ctx)
+	if err != nil {
+		t.Fatalf("GetAttributeConflicts: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Fatalf("expected ignored negation to be skipped, got %+v", conflicts)
+	}
+	live, err := s.CheckConflictsForFact(ctx, neg)
+	if err != nil {
+		t.Fatalf("CheckConflictsForFact: %v", err)
+	}
+	if len(live) != 0 {
+		t.Fatalf("expected no live conflicts, got %d", len(live))
+	}
+}
+
+func TestIgnoreConflict_Validates(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "Test memory", SourceFile: "test.md"})
+	f := &Fact{MemoryID: memID, Subject: "Q", Predicate: "lives_in", Object: "Philadelphia", FactType: "identity"}
+	s.AddFact(ctx, f)
+
+	if _, err := s.IgnoreConflict(ctx, f.ID, f.ID, ""); err == nil {
+		t.Fatal("expected error for self pair")
+	}
+	if _, err := s.IgnoreConflict(ctx, f.ID, f.ID+100, ""); err == nil {
+		t.Fatal("expected error for missing fact")
+	}
+}
@@ -28,8 +28,8 @@ func (s *SQLiteStore) RecordCooccurrence(ctx context.Context, factIDA, factIDB i
 	}
 
 	_, err := s.db.ExecContext(ctx,
-		`INSERT INTO fact_cooccurrence_v1 (fact_id_a, fact_id_b, count, last_seen)
-		 VALUES (?, ?, 1, CURRENT_TIMESTAMP)
+		`INSERT INTO fact_cooccurrence_v1 (fact_id_a, fact_id_b, count, first_seen, last_seen)
+		 VALUES (?, ?, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		 ON CONFLICT(fact_id_a, fact_id_b) DO UPDATE SET
 		   count = count + 1,
 		   last_seen = CURRENT_TIMESTAMP`,
@@ -73,8 +73,8 @@ func (s *SQLiteStore) RecordCooccurrenceBatch(ctx context.Context, factIDs []int
 	defer tx.Rollback()
 
 	stmt, err := tx.PrepareContext(ctx,
-		`INSERT INTO fact_cooccurrence_v1 (fact_id_a, fact_id_b, count, last_seen)
-		 VALUES (?, ?, 1, CURRENT_TIMESTAMP)
+		`INSERT INTO fact_cooccurrence_v1 (fact_id_a, fact_id_b, count, first_seen, last_seen)
+		 VALUES (?, ?, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		 ON CONFLICT(fact_id_a, fact_id_b) DO UPDATE SET
 		   count = count + 1,
 		   last_seen = CURRENT_TIMESTAMP`,
@@ -152,10 +152,13 @@ func (s *SQLiteStore) SuggestEdgesFromCooccurrence(ctx context.Context, minCount
 		minCount = 5
 	}
 
+	aClause, aArgs := s.factNamespaceFilter(ctx, "c.fact_id_a")
+	bClause, bArgs := s.factNamespaceFilter(ctx, "c.fact_id_b")
+	args := append(append([]interface{}{minCount}, aArgs...), bArgs...)
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT c.fact_id_a, c.fact_id_b, c.count, c.last_seen
 		 FROM fact_cooccurrence_v1 c
-		 WHERE c.count >= ?
+		 WHERE c.count >= ?`+aClause+bClause+`
 		   AND NOT EXISTS (
 		     SELECT 1 FROM fact_edges_v1 e
 		     WHERE e.edge_type = 'relates_to'
@@ -163,7 +166,7 @@ func (s *SQLiteStore) SuggestEdgesFromCooccurrence(ctx context.Context, minCount
 		         OR (e.source_fact_id = c.fact_id_b AND e.target_fact_id = c.fact_id_a))
 		   )
 		 ORDER BY c.count DESC
-		 LIMIT 50`, minCount,
+		 LIMIT 50`, args...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("suggesting edges: %w", err)
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// corroborationWeight scales how much of an incoming fact's confidence
+	// closes the gap to 1.0: new = old + (1-old) × incoming × weight. Each
+	// corroboration moves the fact less than the one before.
+	corroborationWeight = 0.5
+	// maxCorroboratedConfidence keeps corroborated facts short of certainty.
+	maxCorroboratedConfidence = 0.99
+)
+
+// FactCorroboration records one independent source that repeated a fact.
+type FactCorroboration struct {
+	ID               int64     `json:"id"`
+	FactID           int64     `json:"fact_id"`
+	MemoryID         int64     `json:"memory_id"`
+	SourceFile       string    `json:"source_file"`
+	AgentID          string    `json:"agent_id,omitempty"`
+	Confidence       float64   `json:"confidence"`        // Confidence the repeat arrived with
+	ConfidenceBefore float64   `json:"confidence_before"` // Fact confidence before the boost
+	ConfidenceAfter  float64   `json:"confidence_after"`  // Fact confidence after the boost
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func (s *SQLiteStore) migrateFactCorroborationsTable() error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS fact_corroborations (
+			id                INTEGER PRIMARY KEY AUTOINCREMENT,
+			fact_id           INTEGER NOT NULL REFERENCES facts(id) ON DELETE CASCADE,
+			memory_id         INTEGER NOT NULL,
+			source_file       TEXT NOT NULL DEFAULT '',
+			agent_id          TEXT NOT NULL DEFAULT '',
+			confidence        REAL NOT NULL,
+			confidence_before REAL NOT NULL,
+			confidence_after  REAL NOT NULL,
+			created_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_fact_corroborations_fact ON fact_corroborations(fact_id)`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating fact_corroborations table: %w", err)
+		}
+	}
+	return nil
+}
+
+// corroboratedConfidence raises current toward 1.0 by incoming's share, with
+// diminishing returns as current approaches the cap.
+func corroboratedConfidence(current, incoming float64) float64 {
+	if incoming <= 0 || incoming > 1 {
+		incoming = 1
+	}
+	next := current + (1-current)*incoming*corroborationWeight
+	if next > maxCorroboratedConfidence {
+		next = maxCorroboratedConfidence
+	}
+	if next < current {
+		return current
+	}
+	return next
+}
+
+// corroborateFact looks for an active fact with the same normalized triple,
+// owner, and project that came from a different source file than f's memory
+// and has not been corroborated by that source yet. If one exists its
+// confidence is raised, the repeat is recorded, and its ID is returned;
+// otherwise it returns 0 and f should be stored as a new fact. Repeats from
+// the same source are not independent and never count.
+func (s *SQLiteStore) corroborateFact(ctx context.Context, f *Fact) (int64, error) {
+	if f.MemoryID <= 0 || strings.TrimSpace(f.Subject) == "" || strings.TrimSpace(f.Object) == "" {
+		return 0, nil
+	}
+	var sourceFile string
+	err := s.db.QueryRowContext(ctx, `SELECT source_file FROM memories WHERE id = ?`, f.MemoryID).Scan(&sourceFile)
+	if err == sql.ErrNoRows || strings.TrimSpace(sourceFile) == "" {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("looking up source for fact memory %d: %w", f.MemoryID, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT f.id, f.subject, f.object, f.confidence, m.source_file
+		 FROM facts f JOIN memories m ON m.id = f.memory_id
+		 WHERE f.superseded_by IS NULL
+		   AND f.state NOT IN (?, ?)
+		   AND LOWER(f.predicate) = LOWER(?)
+		   AND f.negated = ?
+		   AND f.agent_id = ?
+		   AND f.project_id = ?
+		   AND m.deleted_at IS NULL
+		 ORDER BY f.confidence DESC, f.id ASC`,
+		FactStateRetired, FactStateSuperseded, f.Predicate, f.Negated, f.AgentID, f.ProjectID)
+	if err != nil {
+		return 0, fmt.Errorf("finding corroboration candidates: %w", err)
+	}
+	type candidate struct {
+		id         int64
+		confidence float64
+		sourceFile string
+	}
+	subject, object := normalizeFactObject(f.Subject), normalizeFactObject(f.Object)
+	var candidates []candidate
+	for rows.Next() {
+		var c candidate
+		var candSubject, candObject string
+		if err := rows.Scan(&c.id, &candSubject, &candObject, &c.confidence, &c.sourceFile); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scanning corroboration candidate: %w", err)
+		}
+		if normalizeFactObject(candSubject) == subject && normalizeFactObject(candObject) == object {
+			candidates = append(candidates, c)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("iterating corroboration candidates: %w", err)
+	}
+	rows.Close()
+
+	for _, c := range candidates {
+		if c.sourceFile == sourceFile {
+			// The same source repeating itself is not corroboration.
+			return 0, nil
+		}
+		var seen int
+		if err := s.db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM fact_corroborations WHERE fact_id = ? AND source_file = ?`, c.id, sourceFile,
+		).Scan(&seen); err != nil {
+			return 0, fmt.Errorf("checking corroboration sources: %w", err)
+		}
+		if seen > 0 {
+			return 0, nil
+		}
+
+		after := corroboratedConfidence(c.confidence, f.Confidence)
+		now := time.Now().UTC()
+		if _, err := s.db.ExecContext(ctx,
+			`UPDATE facts SET confidence = ?, last_reinforced = ? WHERE id = ?`, after, now, c.id,
+		); err != nil {
+			return 0, fmt.Errorf("boosting corroborated fact %d: %w", c.id, err)
+		}
+		if _, err := s.db.ExecContext(ctx,
+			`INSERT INTO fact_corroborations (fact_id, memory_id, source_file, agent_id, confidence, confidence_before, confidence_after, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			c.id, f.MemoryID, sourceFile, f.AgentID, f.Confidence, c.confidence, after, now,
+		); err != nil {
+			return 0, fmt.Errorf("recording corroboration of fact %d: %w", c.id, err)
+		}
+		if err := s.AddFactSources(ctx, c.id, []int64{f.MemoryID}, FactSourceCorroborated); err != nil {
+			return 0, err
+		}
+		return c.id, nil
+	}
+	return 0, nil
+}
+
+// ListFactCorroborations returns the independent sources that repeated a
+// fact, oldest first.
+func (s *SQLiteStore) ListFactCorroborations(ctx context.Context, factID int64) ([]FactCorroboration, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, fact_id, memory_id, source_file, agent_id, confidence, confidence_before, confidence_after, created_at
+		 FROM fact_corroborations WHERE fact_id = ? ORDER BY created_at ASC, id ASC`, factID)
+	if err != nil {
+		return nil, fmt.Errorf("listing corroborations for fact %d: %w", factID, err)
+	}
+	defer rows.Close()
+	var out []FactCorroboration
+	for rows.Next() {
+		var c FactCorroboration
+		if err := rows.Scan(&c.ID, &c.FactID, &c.MemoryID, &c.SourceFile, &c.AgentID, &c.Confidence, &c.ConfidenceBefore, &c.ConfidenceAfter, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning corroboration: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestAddFact_CorroboratesAcrossSources(t *testing.T) {
+	si, err := NewStore(StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer si.Close()
+	s := si.(*SQLiteStore)
+	ctx := context.Background()
+
+	addMem := func(source string) int64 {
+		t.Helper()
+		id, err := s.AddMemory(ctx, &Memory{Content: "Q lives in Philadelphia (" + source + ")", SourceFile: source})
+		if err != nil {
+			t.Fatalf("AddMemory %s: %v", source, err)
+		}
+		return id
+	}
+	m1, m2, m3 := addMem("notes/a.md"), addMem("notes/b.md"), addMem("notes/c.md")
+
+	first, err := s.AddFact(ctx, &Fact{MemoryID: m1, Subject: "Q", Predicate: "lives in", Object: "Philadelphia", Confidence: 0.6, FactType: "location"})
+	if err != nil {
+		t.Fatalf("AddFact first: %v", err)
+	}
+
+	// Same source repeating itself is stored as a separate row, not a vote.
+	sameSource, err := s.AddFact(ctx, &Fact{MemoryID: m1, Subject: "Q", Predicate: "lives in", Object: "Philadelphia", Confidence: 0.6, FactType: "location"})
+	if err != nil {
+		t.Fatalf("AddFact same source: %v", err)
+	}
+	if sameSource == first {
+		t.Fatalf("same-source repeat should not corroborate fact %d", first)
+	}
+
+	second, err := s.AddFact(ctx, &Fact{MemoryID: m2, Subject: "q", Predicate: "lives in", Object: " philadelphia ", Confidence: 0.6, FactType: "location"})
+	if err != nil {
+		t.Fatalf("AddFact second: %v", err)
+	}
+	if second != first {
+		t.Fatalf("expected corroboration of fact %d, got new fact %d", first, second)
+	}
+	f, err := s.GetFact(ctx, first)
+	if err != nil {
+		t.Fatalf("GetFact: %v", err)
+	}
+	if want := 0.6 + 0.4*0.6*corroborationWeight; math.Abs(f.Confidence-want) > 1e-9 {
+		t.Fatalf("confidence after first corroboration = %.4f, want %.4f", f.Confidence, want)
+	}
+	firstBoost := f.Confidence - 0.6
+
+	// The same independent source voting twice only counts once.
+	again, err := s.AddFact(ctx, &Fact{MemoryID: m2, Subject: "Q", Predicate: "lives in", Object: "Philadelphia", Confidence: 0.6, FactType: "location"})
+	if err != nil {
+		t.Fatalf("AddFact repeat vote: %v", err)
+	}
+	if again == first {
+		t.Fatalf("repeat vote from notes/b.md should not corroborate again")
+	}
+
+	third, err := s.AddFact(ctx, &Fact{MemoryID: m3, Subject: "Q", Predicate: "lives in", Object: "Philadelphia", Confidence: 0.6, FactType: "location"})
+	if err != nil {
+		t.Fatalf("AddFact third: %v", err)
+	}
+	if third != first {
+		t.Fatalf("expected third source to corroborate fact %d, got %d", first, third)
+	}
+	before := f.Confidence
+	f, err = s.GetFact(ctx, first)
+	if err != nil {
+		t.Fatalf("GetFact: %v", err)
+	}
+	if secondBoost := f.Confidence - before; secondBoost <= 0 || secondBoost >= firstBoost {
+		t.Fatalf("expected diminishing boost, first %.4f then %.4f", firstBoost, secondBoost)
+	}
+
+	list, err := s.ListFactCorroborations(ctx, first)
+	if err != nil {
+		t.Fatalf("ListFactCorroborations: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected 2 corroborations, got %d", len(list))
+	}
+	if list[0].SourceFile != "notes/b.md" || list[1].SourceFile != "notes/c.md" {
+		t.Fatalf("unexpected corroboration sources: %q, %q", list[0].SourceFile, list[1].SourceFile)
+	}
+	if list[1].ConfidenceBefore != before || list[1].ConfidenceAfter != f.Confidence {
+		t.Fatalf("unexpected confidence trail: %+v", list[1])
+	}
+}
+
+func TestAddFact_DifferentObjectNotCorroborated(t *testing.T) {
+	si, err := NewStore(StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer si.Close()
+	s := si.(*SQLiteStore)
+	ctx := context.Background()
+
+	m1, _ := s.AddMemory(ctx, &Memory{Content: "one", SourceFile: "a.md"})
+	m2, _ := s.AddMemory(ctx, &Memory{Content: "two", SourceFile: "b.md"})
+	first, err := s.AddFact(ctx, &Fact{MemoryID: m1, Subject: "Q", Predicate: "lives in", Object: "Philadelphia", Confidence: 0.7, FactType: "location"})
+	if err != nil {
+		t.Fatalf("AddFact: %v", err)
+	}
+	other, err := s.AddFact(ctx, &Fact{MemoryID: m2, Subject: "Q", Predicate: "lives in", Object: "Boston", Confidence: 0.7, FactType: "location"})
+	if err != nil {
+		t.Fatalf("AddFact: %v", err)
+	}
+	if other == first {
+		t.Fatalf("different object must not corroborate")
+	}
+}
+
+func TestCorroboratedConfidence_Capped(t *testing.T) {
+	c := 0.5
+	for i := 0; i < 50; i++ {
+		c = corroboratedConfidence(c, 1)
+	}
+	if c != maxCorroboratedConfidence {
+		t.Fatalf("expected cap %.2f, got %.4f", maxCorroboratedConfidence, c)
+	}
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Cursors are opaque keyset positions for paging through list results. A
+// cursor names the last row of the previous page, and the next page starts
+// strictly after that row in the list's sort order. Rows inserted or
+// removed elsewhere while a client pages therefore neither shift a page
+// nor repeat one, unlike LIMIT/OFFSET.
+
+const cursorVersion = "c1"
+
+// ErrStaleCursor is returned when the row a cursor points at is gone.
+var ErrStaleCursor = errors.New("cursor no longer matches a stored row; restart paging from the first page")
+
+// EncodeCursor builds a cursor positioned after the row with the given ID in
+// the named ordering.
+func EncodeCursor(ordering string, id int64) string {
+	raw := fmt.Sprintf("%s|%s|%d", cursorVersion, ordering, id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor returns the row ID encoded in cursor. Cursors made for a
+// different ordering (another list or sort) are rejected.
+func DecodeCursor(cursor, ordering string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(cursor))
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 || parts[0] != cursorVersion {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	if parts[1] != ordering {
+		return 0, fmt.Errorf("cursor was issued for %s, not %s", parts[1], ordering)
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return id, nil
+}
+
+// MemoriesCursor returns the cursor that continues ListMemories after m.
+func MemoriesCursor(m *Memory) string {
+	if m == nil {
+		return ""
+	}
+	return EncodeCursor(memoriesOrdering, m.ID)
+}
+
+// FactsCursor returns the cursor that continues ListFacts (with the given
+// SortBy) after f.
+func FactsCursor(sortBy string, f *Fact) string {
+	if f == nil {
+		return ""
+	}
+	return EncodeCursor(factsOrdering(sortBy), f.ID)
+}
+
+const memoriesOrdering = "memories:imported_at"
+
+func factsOrdering(sortBy string) string {
+	if sortBy == "confidence" {
+		return "facts:confidence"
+	}
+	return "facts:created_at"
+}
+
+// cursorRowExists reports whether the row a cursor points at still exists,
+// so a stale cursor fails loudly instead of returning an empty page.
+func (s *SQLiteStore) cursorRowExists(ctx context.Context, table string, id int64) error {
+	var one int
+	err := s.db.QueryRowContext(ctx, "SELECT 1 FROM "+table+" WHERE id = ?", id).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrStaleCursor
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestListMemories_CursorPagingSurvivesInserts(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	// Identical timestamps: the id tie-break must keep pages disjoint.
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var want []int64
+	for i := 0; i < 5; i++ {
+		id, err := s.AddMemory(ctx, &Memory{Content: "memory " + string(rune('a'+i)), SourceFile: "notes.md", ImportedAt: at})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append([]int64{id}, want...)
+	}
+
+	var got []int64
+	opts := ListOpts{Limit: 2}
+	for page := 0; ; page++ {
+		memories, err := s.ListMemories(ctx, opts)
+		if err != nil {
+			t.Fatalf("page %d: %v", page, err)
+		}
+		for _, m := range memories {
+			got = append(got, m.ID)
+		}
+		if len(memories) < opts.Limit {
+			break
+		}
+		if page == 0 {
+			// A write between pages lands before the cursor and is not seen.
+			if _, err := s.AddMemory(ctx, &Memory{Content: "late arrival", SourceFile: "notes.md", ImportedAt: at.Add(time.Hour)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		opts.Cursor = MemoriesCursor(memories[len(memories)-1])
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestListFacts_CursorBySortOrder(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "facts", SourceFile: "facts.md"})
+	for _, conf := range []float64{0.9, 0.5, 0.7, 0.5} {
+		if _, err := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "svc", Predicate: "p", Object: "o", FactType: "kv", Confidence: conf}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first, err := s.ListFacts(ctx, ListOpts{Limit: 2, SortBy: "confidence"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 2 || first[0].Confidence != 0.9 || first[1].Confidence != 0.7 {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	second, err := s.ListFacts(ctx, ListOpts{Limit: 2, SortBy: "confidence", Cursor: FactsCursor("confidence", first[1])})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second) != 2 || second[0].Confidence != 0.5 || second[0].ID <= second[1].ID {
+		t.Fatalf("unexpected second page: %+v", second)
+	}
+
+	// A cursor from one ordering is rejected by another.
+	if _, err := s.ListFacts(ctx, ListOpts{Limit: 2, Cursor: FactsCursor("confidence", first[1])}); err == nil {
+		t.Fatal("expected ordering mismatch error")
+	}
+	if _, err := s.ListFacts(ctx, ListOpts{Limit: 2, Cursor: "not-a-cursor"}); err == nil {
+		t.Fatal("expected invalid cursor error")
+	}
+	if _, err := s.ListFacts(ctx, ListOpts{Limit: 2, Cursor: EncodeCursor(factsOrdering(""), 9999)}); !errors.Is(err, ErrStaleCursor) {
+		t.Fatalf("expected stale cursor error, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DecaySweepOptions configures SweepDecay.
+type DecaySweepOptions struct {
+	Thresholds DecayThresholds
+	// ArchiveBelow retires active facts whose effective confidence has
+	// fallen below it (0 = never). Core facts and facts under archive hold
+	// are left alone.
+	ArchiveBelow float64
+	DryRun       bool // count what would be archived; alerts are still raised
+}
+
+// DecaySweepResult reports one decay sweep.
+type DecaySweepResult struct {
+	SweptAt       time.Time `json:"swept_at"`
+	FactsScanned  int       `json:"facts_scanned"`
+	AlertsCreated int       `json:"alerts_created"`
+	AlertsSkipped int       `json:"alerts_skipped"` // fact already had an open decay alert
+	WarningCount  int       `json:"warning_count"`
+	CriticalCount int       `json:"critical_count"`
+	Archived      []int64   `json:"archived,omitempty"`
+	Held          int       `json:"held,omitempty"` // below ArchiveBelow but under archive hold
+	DryRun        bool      `json:"dry_run,omitempty"`
+}
+
+// SweepDecay recomputes every live fact's effective confidence, raises
+// decay alerts for facts that crossed opts.Thresholds, and retires facts
+// below opts.ArchiveBelow. Decay is otherwise only applied at query time;
+// a periodic sweep (cortex decay --watch) turns it into alerts and cleanup.
+// Archived facts' open decay alerts are acknowledged.
+func (s *SQLiteStore) SweepDecay(ctx context.Context, opts DecaySweepOptions) (*DecaySweepResult, error) {
+	res := &DecaySweepResult{SweptAt: time.Now().UTC(), DryRun: opts.DryRun}
+	if opts.ArchiveBelow > 0 {
+		if err := s.archiveDecayedFacts(ctx, opts, res); err != nil {
+			return nil, err
+		}
+	}
+
+	alerts, err := s.CheckDecayAlerts(ctx, opts.Thresholds)
+	if err != nil {
+		return nil, err
+	}
+	res.FactsScanned = alerts.FactsScanned
+	res.AlertsCreated = alerts.AlertsCreated
+	res.AlertsSkipped = alerts.AlertsSkipped
+	res.WarningCount = alerts.WarningCount
+	res.CriticalCount = alerts.CriticalCount
+	return res, nil
+}
+
+// archiveDecayedFacts retires the active facts below opts.ArchiveBelow.
+func (s *SQLiteStore) archiveDecayedFacts(ctx context.Context, opts DecaySweepOptions, res *DecaySweepResult) error {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, confidence, decay_rate, last_reinforced, NOT (`+NotHeldFactClause+`) FROM facts
+		 WHERE superseded_by IS NULL AND state = ?`, FactStateActive)
+	if err != nil {
+		return fmt.Errorf("querying facts for decay sweep: %w", err)
+	}
+	var decayed []int64
+	for rows.Next() {
+		var id int64
+		var confidence, decayRate float64
+		var lastReinforced time.Time
+		var held bool
+		if err := rows.Scan(&id, &confidence, &decayRate, &lastReinforced, &held); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning fact: %w", err)
+		}
+		switch {
+		case EffectiveConfidence(confidence, decayRate, lastReinforced) >= opts.ArchiveBelow:
+		case held:
+			res.Held++
+		default:
+			decayed = append(decayed, id)
+		}
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("iterating facts: %w", err)
+	}
+
+	for _, id := range decayed {
+		if opts.DryRun {
+			res.Archived = append(res.Archived, id)
+			continue
+		}
+		if err := s.UpdateFactState(ctx, id, FactStateRetired); err != nil {
+			if errors.Is(err, ErrArchiveHold) {
+				res.Held++
+				continue
+			}
+			return err
+		}
+		if _, err := s.db.ExecContext(ctx,
+			`UPDATE alerts SET acknowledged = 1, acknowledged_at = ?
+			 WHERE alert_type = ? AND fact_id = ? AND acknowledged = 0`,
+			time.Now().UTC(), string(AlertTypeDecay), id); err != nil {
+			return fmt.Errorf("acknowledging decay alerts for fact %d: %w", id, err)
+		}
+		res.Archived = append(res.Archived, id)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSweepDecay_AlertsAndArchives(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "sweep", SourceFile: "test.md"})
+	add := func(subject string, daysOld int, state string) int64 {
+		t.Helper()
+		id, err := s.AddFact(ctx, &Fact{
+			MemoryID: memID, Subject: subject, Predicate: "value", Object: subject + "-v",
+			FactType: "kv", Confidence: 1.0, DecayRate: 0.01, State: state,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.db.ExecContext(ctx, "UPDATE facts SET last_reinforced = ? WHERE id = ?", time.Now().UTC().AddDate(0, 0, -daysOld), id)
+		return id
+	}
+	add("fresh", 0, "")
+	fading := add("fading", 80, "") // ~0.45: warning
+	gone := add("gone", 400, "")    // ~0.02: archived
+	core := add("pinned", 400, "core")
+	held := add("held", 400, "")
+	if err := s.SetArchiveHold(ctx, HoldKindFact, held, "legal"); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DecaySweepOptions{Thresholds: DefaultDecayThresholds(), ArchiveBelow: 0.05}
+	dry, err := s.SweepDecay(ctx, DecaySweepOptions{Thresholds: opts.Thresholds, ArchiveBelow: 0.05, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dry.Archived) != 1 || dry.Held != 1 || dry.AlertsCreated != 4 {
+		t.Fatalf("dry run = %+v", dry)
+	}
+	if f, _ := s.GetFact(ctx, gone); f.State != FactStateActive {
+		t.Fatalf("dry run retired fact %d", gone)
+	}
+
+	res, err := s.SweepDecay(ctx, opts)
+	if err != nil {
+		t.Fatalf("SweepDecay: %v", err)
+	}
+	if len(res.Archived) != 1 || res.Archived[0] != gone || res.Held != 1 {
+		t.Fatalf("archived %v, held %d", res.Archived, res.Held)
+	}
+	// The dry run's alerts stay open; the archived fact's is acknowledged
+	// and the retired fact is no longer scanned.
+	if res.FactsScanned != 4 || res.AlertsCreated != 0 || res.AlertsSkipped != 3 {
+		t.Fatalf("sweep = %+v", res)
+	}
+	if f, _ := s.GetFact(ctx, core); f.State != FactStateCore {
+		t.Fatalf("core fact state = %s", f.State)
+	}
+	unacked := false
+	alerts, _ := s.ListAlerts(ctx, AlertFilter{Type: AlertTypeDecay, Acknowledged: &unacked})
+	open := map[int64]bool{}
+	for _, a := range alerts {
+		open[*a.FactID] = true
+	}
+	if len(open) != 3 || !open[fading] || !open[core] || !open[held] || open[gone] {
+		t.Fatalf("open decay alerts for %v", open)
+	}
+}
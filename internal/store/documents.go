@@ -0,0 +1,226 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A document groups the memories chunked from one source file. Memories
+// keep their own rows; documents.id is a soft reference (memories.document_id)
+// so a file can be listed, deleted, refreshed, or exported as a unit. The
+// version hash is the file's checksum from its latest import.
+
+// Document is one source file and the chunks imported from it.
+type Document struct {
+	ID            int64     `json:"id"`
+	SourceFile    string    `json:"source_file"`
+	VersionHash   string    `json:"version_hash,omitempty"`
+	Chunks        int       `json:"chunks"`                   // live memories
+	DeletedChunks int       `json:"deleted_chunks,omitempty"` // soft-deleted memories
+	Facts         int       `json:"facts"`                    // facts of live memories, not superseded
+	Embedded      int       `json:"embedded"`                 // live memories with an embedding
+	Bytes         int64     `json:"bytes"`                    // content size of live memories
+	FirstImported time.Time `json:"first_imported,omitempty"`
+	LastImported  time.Time `json:"last_imported,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// DocumentDeletion reports what DeleteDocument did.
+type DocumentDeletion struct {
+	Deleted int `json:"deleted"` // memories soft-deleted
+	Held    int `json:"held"`    // memories kept because of an archive hold
+}
+
+// migrateDocuments adds documents and memories.document_id, and groups the
+// existing memories by source file.
+func (s *SQLiteStore) migrateDocuments() error {
+	done, err := s.isMetaFlagEnabled("documents_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS documents (
+			id           INTEGER PRIMARY KEY AUTOINCREMENT,
+			source_file  TEXT NOT NULL UNIQUE,
+			version_hash TEXT NOT NULL DEFAULT '',
+			created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+	}
+	var hasColumn int
+	if err := s.db.QueryRow(
+		"SELECT COUNT(*) FROM pragma_table_info('memories') WHERE name='document_id'",
+	).Scan(&hasColumn); err != nil {
+		return fmt.Errorf("checking memories.document_id: %w", err)
+	}
+	if hasColumn == 0 {
+		statements = append(statements,
+			`ALTER TABLE memories ADD COLUMN document_id INTEGER REFERENCES documents(id) ON DELETE SET NULL`)
+	}
+	statements = append(statements,
+		`CREATE INDEX IF NOT EXISTS idx_memories_document ON memories(document_id)`,
+		`INSERT OR IGNORE INTO documents (source_file, version_hash, created_at, updated_at)
+		 SELECT m.source_file, COALESCE(c.sha256, ''), MIN(m.imported_at), MAX(m.imported_at)
+		 FROM memories m LEFT JOIN import_file_checksums c ON c.source_file = m.source_file
+		 WHERE m.source_file != ''
+		 GROUP BY m.source_file`,
+		`UPDATE memories SET document_id = (SELECT d.id FROM documents d WHERE d.source_file = memories.source_file)
+		 WHERE document_id IS NULL AND source_file != ''`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('documents_v1', 'true')`,
+	)
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("migrating documents: %w", err)
+		}
+	}
+	return nil
+}
+
+// linkMemoryDocument attaches a new memory to the document of its source
+// file, creating the document on first use, in the transaction that
+// inserted the memory. Memories without a source file stay ungrouped.
+func linkMemoryDocument(ctx context.Context, tx *sql.Tx, memoryID int64, sourceFile string, now time.Time) error {
+	if sourceFile == "" {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO documents (source_file, created_at, updated_at) VALUES (?, ?, ?)
+		 ON CONFLICT(source_file) DO UPDATE SET updated_at = excluded.updated_at`,
+		sourceFile, now, now,
+	); err != nil {
+		return fmt.Errorf("recording document %s: %w", sourceFile, err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE memories SET document_id = (SELECT id FROM documents WHERE source_file = ?) WHERE id = ?`,
+		sourceFile, memoryID,
+	); err != nil {
+		return fmt.Errorf("linking memory %d to document: %w", memoryID, err)
+	}
+	return nil
+}
+
+// documentStatsQuery aggregates each document's memories. queryDocuments
+// appends the namespace condition on m, a WHERE clause on d, and the GROUP BY.
+const documentStatsQuery = `SELECT d.id, d.source_file, d.version_hash, d.created_at, d.updated_at,
+		COALESCE(SUM(m.id IS NOT NULL AND m.deleted_at IS NULL), 0),
+		COALESCE(SUM(m.deleted_at IS NOT NULL), 0),
+		COALESCE(SUM(CASE WHEN m.deleted_at IS NULL THEN
+			(SELECT COUNT(*) FROM facts f WHERE f.memory_id = m.id AND f.superseded_by IS NULL) END), 0),
+		COALESCE(SUM(m.deleted_at IS NULL AND EXISTS (SELECT 1 FROM embeddings e WHERE e.memory_id = m.id)), 0),
+		COALESCE(SUM(CASE WHEN m.deleted_at IS NULL THEN length(CAST(m.content AS BLOB)) END), 0),
+		MIN(CASE WHEN m.deleted_at IS NULL THEN m.imported_at END),
+		MAX(CASE WHEN m.deleted_at IS NULL THEN m.imported_at END)
+	FROM documents d LEFT JOIN memories m ON m.document_id = d.id`
+
+// queryDocuments returns the documents matching where (a condition on d, or
+// empty) with stats over their memories in ctx's namespace. Documents are
+// keyed by source file alone, so two namespaces that import the same path
+// share a row; a scoped caller only sees documents with a chunk in its
+// namespace, and liveOnly further requires a live one.
+func (s *SQLiteStore) queryDocuments(ctx context.Context, where string, liveOnly bool, args ...interface{}) ([]*Document, error) {
+	nsClause, nsArgs := s.namespaceFilter(ctx, "m.namespace")
+	query := documentStatsQuery + nsClause
+	if where != "" {
+		query += " WHERE " + where
+	}
+	query += " GROUP BY d.id"
+	switch {
+	case liveOnly:
+		query += " HAVING SUM(m.id IS NOT NULL AND m.deleted_at IS NULL) > 0"
+	case nsClause != "":
+		query += " HAVING COUNT(m.id) > 0"
+	}
+	rows, err := s.db.QueryContext(ctx, query+" ORDER BY d.source_file", append(nsArgs, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("querying documents: %w", err)
+	}
+	defer rows.Close()
+	var out []*Document
+	for rows.Next() {
+		d := &Document{}
+		var first, last sql.NullString
+		if err := rows.Scan(&d.ID, &d.SourceFile, &d.VersionHash, &d.CreatedAt, &d.UpdatedAt,
+			&d.Chunks, &d.DeletedChunks, &d.Facts, &d.Embedded, &d.Bytes, &first, &last); err != nil {
+			return nil, fmt.Errorf("scanning document: %w", err)
+		}
+		if first.Valid {
+			d.FirstImported = parseAggregateTime(first.String)
+		}
+		if last.Valid {
+			d.LastImported = parseAggregateTime(last.String)
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// ListDocuments returns documents with their stats, ordered by source file.
+// Documents with no live chunks are left out unless includeEmpty is set.
+func (s *SQLiteStore) ListDocuments(ctx context.Context, includeEmpty bool) ([]*Document, error) {
+	return s.queryDocuments(ctx, "", !includeEmpty)
+}
+
+// GetDocument returns a document with its stats, or nil if there is none
+// with that ID.
+func (s *SQLiteStore) GetDocument(ctx context.Context, id int64) (*Document, error) {
+	docs, err := s.queryDocuments(ctx, "d.id = ?", false, id)
+	if err != nil || len(docs) == 0 {
+		return nil, err
+	}
+	return docs[0], nil
+}
+
+// ResolveDocument finds a document by ID or by source file path. It
+// returns nil if neither matches.
+func (s *SQLiteStore) ResolveDocument(ctx context.Context, ref string) (*Document, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		d, err := s.GetDocument(ctx, id)
+		if d != nil || err != nil {
+			return d, err
+		}
+	}
+	docs, err := s.queryDocuments(ctx, "d.source_file = ?", false, ref)
+	if err != nil || len(docs) == 0 {
+		return nil, err
+	}
+	return docs[0], nil
+}
+
+// DeleteDocument soft-deletes every live memory of a document in ctx's
+// namespace, like DeleteMemory does one at a time. Memories under an archive
+// hold are kept and counted. The document row stays so its history can be
+// listed.
+func (s *SQLiteStore) DeleteDocument(ctx context.Context, id int64) (*DocumentDeletion, error) {
+	nsClause, nsArgs := s.namespaceFilter(ctx, "namespace")
+	args := append([]interface{}{id}, nsArgs...)
+	ids, err := s.queryMemoryIDs(ctx,
+		`SELECT id FROM memories WHERE document_id = ? AND deleted_at IS NULL AND `+NotHeldMemoryClause+nsClause+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying memories of document %d: %w", id, err)
+	}
+	var held int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM memories WHERE document_id = ? AND deleted_at IS NULL AND NOT (`+NotHeldMemoryClause+`)`+nsClause, args...,
+	).Scan(&held); err != nil {
+		return nil, fmt.Errorf("counting held memories of document %d: %w", id, err)
+	}
+
+	del := &DocumentDeletion{Held: held}
+	for _, memoryID := range ids {
+		if err := s.DeleteMemory(ctx, memoryID); err != nil {
+			return del, err
+		}
+		del.Deleted++
+	}
+	return del, nil
+}
@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+func TestDocuments_GroupAndStats(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	m1, _ := s.AddMemory(ctx, &Memory{Content: "alpha", SourceFile: "/notes.md"})
+	if _, err := s.AddMemoryBatch(ctx, []*Memory{
+		{Content: "beta", SourceFile: "/notes.md"},
+		{Content: "gamma", SourceFile: "/other.md"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	s.AddMemory(ctx, &Memory{Content: "no source"})
+	s.AddFact(ctx, &Fact{MemoryID: m1, Subject: "alpha", Predicate: "is", Object: "first", FactType: "kv"})
+	if err := s.RecordFileChecksum(ctx, "/notes.md", "abc", 9); err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := s.ListDocuments(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 || docs[0].SourceFile != "/notes.md" || docs[1].SourceFile != "/other.md" {
+		t.Fatalf("documents = %+v, want /notes.md and /other.md", docs)
+	}
+	d := docs[0]
+	if d.Chunks != 2 || d.Facts != 1 || d.Bytes != int64(len("alpha")+len("beta")) || d.VersionHash != "abc" {
+		t.Fatalf("notes stats = %+v", d)
+	}
+	if d.FirstImported.IsZero() || d.LastImported.Before(d.FirstImported) {
+		t.Fatalf("import times = %v .. %v", d.FirstImported, d.LastImported)
+	}
+
+	byPath, err := s.ResolveDocument(ctx, "/notes.md")
+	if err != nil || byPath == nil || byPath.ID != d.ID {
+		t.Fatalf("resolve by path = %+v, %v", byPath, err)
+	}
+	byID, err := s.ResolveDocument(ctx, strconv.FormatInt(d.ID, 10))
+	if err != nil || byID == nil || byID.SourceFile != "/notes.md" {
+		t.Fatalf("resolve by id = %+v, %v", byID, err)
+	}
+	if missing, err := s.ResolveDocument(ctx, "/nope.md"); err != nil || missing != nil {
+		t.Fatalf("resolve missing = %+v, %v", missing, err)
+	}
+}
+
+func TestDeleteDocument_SkipsHeldMemories(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	m1, _ := s.AddMemory(ctx, &Memory{Content: "one", SourceFile: "/notes.md"})
+	m2, _ := s.AddMemory(ctx, &Memory{Content: "two", SourceFile: "/notes.md"})
+	keep, _ := s.AddMemory(ctx, &Memory{Content: "other", SourceFile: "/other.md"})
+	if err := s.SetArchiveHold(ctx, HoldKindMemory, m2, "legal"); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, _ := s.ResolveDocument(ctx, "/notes.md")
+	del, err := s.DeleteDocument(ctx, doc.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if del.Deleted != 1 || del.Held != 1 {
+		t.Fatalf("deletion = %+v, want 1 deleted and 1 held", del)
+	}
+	if m, _ := s.GetMemory(ctx, m1); m == nil || m.DeletedAt == nil {
+		t.Fatal("memory 1 should be soft-deleted")
+	}
+	if m, _ := s.GetMemory(ctx, keep); m == nil || m.DeletedAt != nil {
+		t.Fatal("other document's memory should be untouched")
+	}
+
+	doc, _ = s.GetDocument(ctx, doc.ID)
+	if doc.Chunks != 1 || doc.DeletedChunks != 1 {
+		t.Fatalf("after delete = %+v", doc)
+	}
+}
+
+func TestListDocuments_HidesEmpty(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	id, _ := s.AddMemory(ctx, &Memory{Content: "one", SourceFile: "/gone.md"})
+	if err := s.DeleteMemory(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if docs, _ := s.ListDocuments(ctx, false); len(docs) != 0 {
+		t.Fatalf("empty document listed: %+v", docs)
+	}
+	docs, _ := s.ListDocuments(ctx, true)
+	if len(docs) != 1 || docs[0].Chunks != 0 || docs[0].DeletedChunks != 1 {
+		t.Fatalf("with empty = %+v", docs)
+	}
+}
+
+func TestMigrateDocuments_Backfill(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	a, _ := s.AddMemory(ctx, &Memory{Content: "one", SourceFile: "/a.md"})
+	b, _ := s.AddMemory(ctx, &Memory{Content: "two", SourceFile: "/a.md"})
+	s.RecordFileChecksum(ctx, "/a.md", "sum", 3)
+	for _, stmt := range []string{
+		`UPDATE memories SET document_id = NULL`,
+		`DELETE FROM documents`,
+		`DELETE FROM meta WHERE key = 'documents_v1'`,
+	} {
+		if _, err := s.db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.migrateDocuments(); err != nil {
+		t.Fatal(err)
+	}
+	var docA, docB int64
+	s.db.QueryRow(`SELECT document_id FROM memories WHERE id = ?`, a).Scan(&docA)
+	s.db.QueryRow(`SELECT document_id FROM memories WHERE id = ?`, b).Scan(&docB)
+	if docA == 0 || docA != docB {
+		t.Fatalf("backfilled document ids = %d, %d", docA, docB)
+	}
+	doc, _ := s.GetDocument(ctx, docA)
+	if doc == nil || doc.VersionHash != "sum" || doc.Chunks != 2 {
+		t.Fatalf("backfilled document = %+v", doc)
+	}
+}
+
+func TestDocuments_StayInNamespace(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+	work := WithNamespace(ctx, "work")
+	personal := WithNamespace(ctx, "personal")
+
+	workMem, _ := s.AddMemory(work, &Memory{Content: "sprint goals", SourceFile: "/shared/plan.md"})
+	personalMem, _ := s.AddMemory(personal, &Memory{Content: "garden plan", SourceFile: "/shared/plan.md"})
+	s.AddMemory(personal, &Memory{Content: "recipes", SourceFile: "/home/food.md"})
+
+	docs, err := s.ListDocuments(work, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].SourceFile != "/shared/plan.md" || docs[0].Chunks != 1 {
+		t.Fatalf("work documents = %+v, want only /shared/plan.md with one chunk", docs)
+	}
+	if d, _ := s.ResolveDocument(work, "/home/food.md"); d != nil {
+		t.Fatalf("work resolved a personal-only document: %+v", d)
+	}
+
+	del, err := s.DeleteDocument(work, docs[0].ID)
+	if err != nil || del.Deleted != 1 {
+		t.Fatalf("delete = %+v, %v", del, err)
+	}
+	if m, _ := s.GetMemory(ctx, workMem); m.DeletedAt == nil {
+		t.Fatal("work chunk should be deleted")
+	}
+	if m, _ := s.GetMemory(ctx, personalMem); m.DeletedAt != nil {
+		t.Fatal("personal chunk of the shared path should be untouched")
+	}
+	if docs, _ := s.ListDocuments(personal, false); len(docs) != 2 {
+		t.Fatalf("personal documents = %+v, want 2", docs)
+	}
+	if docs, _ := s.ListDocuments(WithNamespace(ctx, AllNamespaces), false); len(docs) != 2 || docs[1].Chunks != 1 {
+		t.Fatalf("all-namespace documents = %+v", docs)
+	}
+}
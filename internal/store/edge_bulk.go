@@ -0,0 +1,213 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// EdgeFilter selects edges for bulk operations. Zero fields match anything.
+type EdgeFilter struct {
+	SourceFactID int64
+	TargetFactID int64
+	Type         EdgeType
+	Source       EdgeSource
+}
+
+// Empty reports whether f matches every edge.
+func (f EdgeFilter) Empty() bool {
+	return f.SourceFactID == 0 && f.TargetFactID == 0 && f.Type == "" && f.Source == ""
+}
+
+func (s *SQLiteStore) edgeFilterClause(ctx context.Context, f EdgeFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	if f.SourceFactID != 0 {
+		conds = append(conds, "source_fact_id = ?")
+		args = append(args, f.SourceFactID)
+	}
+	if f.TargetFactID != 0 {
+		conds = append(conds, "target_fact_id = ?")
+		args = append(args, f.TargetFactID)
+	}
+	if f.Type != "" {
+		conds = append(conds, "edge_type = ?")
+		args = append(args, string(f.Type))
+	}
+	if f.Source != "" {
+		conds = append(conds, "source = ?")
+		args = append(args, string(f.Source))
+	}
+	where := " WHERE 1 = 1"
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+	nsClause, nsArgs := s.factNamespaceFilter(ctx, "source_fact_id")
+	return where + nsClause, append(args, nsArgs...)
+}
+
+// FindEdges returns the edges matching f, oldest first.
+func (s *SQLiteStore) FindEdges(ctx context.Context, f EdgeFilter) ([]FactEdge, error) {
+	where, args := s.edgeFilterClause(ctx, f)
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, source_fact_id, target_fact_id, edge_type, confidence, source, agent_id, created_at
+		 FROM fact_edges_v1`+where+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("finding edges: %w", err)
+	}
+	defer rows.Close()
+	return scanEdges(rows)
+}
+
+// RemoveEdges deletes every edge matching f and returns how many were
+// removed. An empty filter is rejected rather than clearing the graph.
+func (s *SQLiteStore) RemoveEdges(ctx context.Context, f EdgeFilter) (int, error) {
+	if f.Empty() {
+		return 0, fmt.Errorf("removing edges needs a source fact, target fact, type, or source")
+	}
+	where, args := s.edgeFilterClause(ctx, f)
+	result, err := s.db.ExecContext(ctx, `DELETE FROM fact_edges_v1`+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("removing edges: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// EdgeImportProblem explains why one edge in an import was rejected.
+// Index is the edge's position in the slice given to ImportEdges.
+type EdgeImportProblem struct {
+	Index  int    `json:"index"`
+	Reason string `json:"reason"`
+}
+
+// EdgeImportResult reports what ImportEdges did, or would do on a dry run.
+type EdgeImportResult struct {
+	Added    int                 `json:"added"`
+	Existing int                 `json:"existing"`
+	Invalid  []EdgeImportProblem `json:"invalid,omitempty"`
+	DryRun   bool                `json:"dry_run"`
+}
+
+// ImportEdges validates edges and adds them in one transaction. Both facts
+// must exist in the active namespace, the type must be valid, and the
+// confidence must be within (0, 1] (zero means 1.0). If any edge is invalid
+// nothing is written and the problems are returned in the result. Edges that
+// already exist are counted and skipped. dryRun validates and counts without
+// writing.
+func (s *SQLiteStore) ImportEdges(ctx context.Context, edges []FactEdge, dryRun bool) (*EdgeImportResult, error) {
+	res := &EdgeImportResult{DryRun: dryRun}
+	known := map[int64]bool{}
+	factVisible := func(id int64) (bool, error) {
+		if ok, seen := known[id]; seen {
+			return ok, nil
+		}
+		nsClause, nsArgs := s.factNamespaceFilter(ctx, "id")
+		var n int
+		err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM facts WHERE id = ?`+nsClause,
+			append([]interface{}{id}, nsArgs...)...).Scan(&n)
+		if err != nil {
+			return false, fmt.Errorf("checking fact %d: %w", id, err)
+		}
+		known[id] = n > 0
+		return n > 0, nil
+	}
+
+	for i := range edges {
+		e := &edges[i]
+		problem := func(format string, a ...interface{}) {
+			res.Invalid = append(res.Invalid, EdgeImportProblem{Index: i, Reason: fmt.Sprintf(format, a...)})
+		}
+		if e.SourceFactID <= 0 || e.TargetFactID <= 0 {
+			problem("source and target fact IDs are required")
+			continue
+		}
+		if e.SourceFactID == e.TargetFactID {
+			problem("edge from fact %d to itself", e.SourceFactID)
+			continue
+		}
+		edgeType, err := ParseEdgeType(string(e.EdgeType))
+		if err != nil {
+			problem("%v", err)
+			continue
+		}
+		e.EdgeType = edgeType
+		if e.Confidence < 0 || e.Confidence > 1 {
+			problem("confidence %g outside (0, 1]", e.Confidence)
+			continue
+		}
+		if e.Confidence == 0 {
+			e.Confidence = 1.0
+		}
+		if e.Source == "" {
+			e.Source = EdgeSourceExplicit
+		}
+		for _, id := range []int64{e.SourceFactID, e.TargetFactID} {
+			ok, err := factVisible(id)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				problem("fact %d not found", id)
+				break
+			}
+		}
+	}
+	if len(res.Invalid) > 0 {
+		return res, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("beginning edge import: %w", err)
+	}
+	defer tx.Rollback()
+	type edgeKey struct {
+		src, dst int64
+		typ      EdgeType
+	}
+	planned := map[edgeKey]bool{}
+	for i := range edges {
+		e := &edges[i]
+		if dryRun {
+			key := edgeKey{e.SourceFactID, e.TargetFactID, e.EdgeType}
+			if planned[key] {
+				res.Existing++
+				continue
+			}
+			planned[key] = true
+			var n int
+			if err := tx.QueryRowContext(ctx,
+				`SELECT COUNT(*) FROM fact_edges_v1 WHERE source_fact_id = ? AND target_fact_id = ? AND edge_type = ?`,
+				e.SourceFactID, e.TargetFactID, string(e.EdgeType)).Scan(&n); err != nil {
+				return nil, fmt.Errorf("checking edge: %w", err)
+			}
+			if n > 0 {
+				res.Existing++
+			} else {
+				res.Added++
+			}
+			continue
+		}
+		result, err := tx.ExecContext(ctx,
+			`INSERT OR IGNORE INTO fact_edges_v1 (source_fact_id, target_fact_id, edge_type, confidence, source, agent_id)
+			 VALUES (?, ?, ?, ?, ?, ?)`,
+			e.SourceFactID, e.TargetFactID, string(e.EdgeType), e.Confidence, string(e.Source), e.AgentID)
+		if err != nil {
+			return nil, fmt.Errorf("adding edge %d→%d: %w", e.SourceFactID, e.TargetFactID, err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			res.Existing++
+			continue
+		}
+		e.ID, _ = result.LastInsertId()
+		res.Added++
+	}
+	if dryRun {
+		return res, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing edge import: %w", err)
+	}
+	return res, nil
+}
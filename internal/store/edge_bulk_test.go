@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestImportEdges(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "test", SourceFile: "t.md"})
+	f1, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "a", Predicate: "p", Object: "o1", FactType: "kv"})
+	f2, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "b", Predicate: "p", Object: "o2", FactType: "kv"})
+	f3, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "c", Predicate: "p", Object: "o3", FactType: "kv"})
+	if err := s.AddEdge(ctx, &FactEdge{SourceFactID: f1, TargetFactID: f2, EdgeType: EdgeTypeSupports}); err != nil {
+		t.Fatal(err)
+	}
+
+	// One bad row rejects the whole import.
+	res, err := s.ImportEdges(ctx, []FactEdge{
+		{SourceFactID: f1, TargetFactID: f3, EdgeType: "relates_to"},
+		{SourceFactID: f2, TargetFactID: 999, EdgeType: "supports"},
+		{SourceFactID: f2, TargetFactID: f3, EdgeType: "causes"},
+		{SourceFactID: f3, TargetFactID: f3, EdgeType: "supports"},
+		{SourceFactID: f2, TargetFactID: f1, EdgeType: "supports", Confidence: 1.5},
+	}, false)
+	if err != nil {
+		t.Fatalf("ImportEdges: %v", err)
+	}
+	if len(res.Invalid) != 4 || res.Invalid[0].Index != 1 || !strings.Contains(res.Invalid[0].Reason, "fact 999 not found") {
+		t.Fatalf("invalid = %+v", res.Invalid)
+	}
+	if n, _ := s.CountEdges(ctx); n != 1 {
+		t.Fatalf("invalid import wrote edges: %d", n)
+	}
+
+	edges := []FactEdge{
+		{SourceFactID: f1, TargetFactID: f2, EdgeType: "supports"},
+		{SourceFactID: f1, TargetFactID: f3, EdgeType: "Relates_To", Confidence: 0.4},
+		{SourceFactID: f2, TargetFactID: f3, EdgeType: "supports"},
+		{SourceFactID: f2, TargetFactID: f3, EdgeType: "supports"},
+	}
+	res, err = s.ImportEdges(ctx, edges, true)
+	if err != nil || res.Added != 2 || res.Existing != 2 || !res.DryRun {
+		t.Fatalf("dry run = %+v, %v", res, err)
+	}
+	if n, _ := s.CountEdges(ctx); n != 1 {
+		t.Fatalf("dry run wrote edges: %d", n)
+	}
+
+	res, err = s.ImportEdges(ctx, edges, false)
+	if err != nil || res.Added != 2 || res.Existing != 2 {
+		t.Fatalf("import = %+v, %v", res, err)
+	}
+	got, err := s.FindEdges(ctx, EdgeFilter{SourceFactID: f1, Type: EdgeTypeRelatesTo})
+	if err != nil || len(got) != 1 || got[0].Confidence != 0.4 || got[0].Source != EdgeSourceExplicit {
+		t.Fatalf("imported relates_to edge = %+v, %v", got, err)
+	}
+}
+
+func TestRemoveEdgesByFilter(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "test", SourceFile: "t.md"})
+	var ids []int64
+	for _, obj := range []string{"o1", "o2", "o3", "o4"} {
+		id, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "s", Predicate: "p", Object: obj, FactType: "kv"})
+		ids = append(ids, id)
+	}
+	for _, e := range []FactEdge{
+		{SourceFactID: ids[0], TargetFactID: ids[1], EdgeType: EdgeTypeSupports},
+		{SourceFactID: ids[0], TargetFactID: ids[2], EdgeType: EdgeTypeSupports},
+		{SourceFactID: ids[0], TargetFactID: ids[3], EdgeType: EdgeTypeRelatesTo},
+		{SourceFactID: ids[1], TargetFactID: ids[2], EdgeType: EdgeTypeSupports},
+	} {
+		e := e
+		if err := s.AddEdge(ctx, &e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := s.RemoveEdges(ctx, EdgeFilter{}); err == nil {
+		t.Fatal("empty filter should be rejected")
+	}
+	filter := EdgeFilter{SourceFactID: ids[0], Type: EdgeTypeSupports}
+	if found, _ := s.FindEdges(ctx, filter); len(found) != 2 {
+		t.Fatalf("FindEdges = %+v", found)
+	}
+	n, err := s.RemoveEdges(ctx, filter)
+	if err != nil || n != 2 {
+		t.Fatalf("RemoveEdges = %d, %v", n, err)
+	}
+	if left, _ := s.CountEdges(ctx); left != 2 {
+		t.Fatalf("edges left = %d, want 2", left)
+	}
+}
@@ -7,15 +7,21 @@ import (
 	"fmt"
 	"math"
 	"strings"
+
+	"github.com/hurttlocker/cortex/internal/ann"
 )
 
 // AddEmbedding stores an embedding vector for a memory.
 // Replaces any existing embedding for the same memory_id.
 func (s *SQLiteStore) AddEmbedding(ctx context.Context, memoryID int64, vector []float32) error {
-	blob := float32ToBytes(vector)
+	quant, err := s.EmbeddingQuantization(ctx)
+	if err != nil {
+		return err
+	}
+	blob := encodeEmbedding(vector, ann.Quantization(quant))
 	dims := len(vector)
 
-	_, err := s.db.ExecContext(ctx,
+	_, err = s.db.ExecContext(ctx,
 		`INSERT INTO embeddings (memory_id, vector, dimensions) VALUES (?, ?, ?)
 		 ON CONFLICT(memory_id) DO UPDATE SET vector = excluded.vector, dimensions = excluded.dimensions`,
 		memoryID, blob, dims,
@@ -27,15 +33,17 @@ func (s *SQLiteStore) AddEmbedding(ctx context.Context, memoryID int64, vector [
 }
 
 // GetEmbedding retrieves the embedding vector for a memory.
+// Quantized vectors are dequantized transparently.
 func (s *SQLiteStore) GetEmbedding(ctx context.Context, memoryID int64) ([]float32, error) {
 	var blob []byte
+	var dims int
 	err := s.db.QueryRowContext(ctx,
-		"SELECT vector FROM embeddings WHERE memory_id = ?", memoryID,
-	).Scan(&blob)
+		"SELECT vector, dimensions FROM embeddings WHERE memory_id = ?", memoryID,
+	).Scan(&blob, &dims)
 	if err != nil {
 		return nil, fmt.Errorf("getting embedding for memory %d: %w", memoryID, err)
 	}
-	return bytesToFloat32(blob), nil
+	return decodeEmbedding(blob, dims), nil
 }
 
 // SearchEmbedding performs brute-force cosine similarity search across all embeddings.
@@ -54,19 +62,22 @@ func (s *SQLiteStore) SearchEmbeddingWithProject(ctx context.Context, query []fl
 	var querySQL string
 	var args []interface{}
 	if project != "" {
-		querySQL = `SELECT e.memory_id, e.vector, m.id, m.content, m.source_file, m.source_line,
+		querySQL = `SELECT e.memory_id, e.vector, e.dimensions, m.id, m.content, m.source_file, m.source_line,
 		        m.source_section, m.content_hash, m.project, m.memory_class, m.metadata, m.imported_at, m.updated_at
 		 FROM embeddings e
 		 JOIN memories m ON e.memory_id = m.id
 		 WHERE m.deleted_at IS NULL AND m.project = ?`
 		args = []interface{}{project}
 	} else {
-		querySQL = `SELECT e.memory_id, e.vector, m.id, m.content, m.source_file, m.source_line,
+		querySQL = `SELECT e.memory_id, e.vector, e.dimensions, m.id, m.content, m.source_file, m.source_line,
 		        m.source_section, m.content_hash, m.project, m.memory_class, m.metadata, m.imported_at, m.updated_at
 		 FROM embeddings e
 		 JOIN memories m ON e.memory_id = m.id
 		 WHERE m.deleted_at IS NULL`
 	}
+	nsClause, nsArgs := s.namespaceFilter(ctx, "m.namespace")
+	querySQL += nsClause
+	args = append(args, nsArgs...)
 
 	rows, err := s.db.QueryContext(ctx, querySQL, args...)
 	if err != nil {
@@ -84,11 +95,12 @@ func (s *SQLiteStore) SearchEmbeddingWithProject(ctx context.Context, query []fl
 	for rows.Next() {
 		var blob []byte
 		var memID int64
+		var dims int
 		var metadataStr sql.NullString
 		var memClass sql.NullString
 		m := &Memory{}
 
-		if err := rows.Scan(&memID, &blob, &m.ID, &m.Content, &m.SourceFile,
+		if err := rows.Scan(&memID, &blob, &dims, &m.ID, &m.Content, &m.SourceFile,
 			&m.SourceLine, &m.SourceSection, &m.ContentHash, &m.Project, &memClass,
 			&metadataStr, &m.ImportedAt, &m.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scanning embedding row: %w", err)
@@ -96,7 +108,7 @@ func (s *SQLiteStore) SearchEmbeddingWithProject(ctx context.Context, query []fl
 		m.MemoryClass = memClass.String
 		m.Metadata = unmarshalMetadata(metadataStr)
 
-		vec := bytesToFloat32(blob)
+		vec := decodeEmbedding(blob, dims)
 		sim := cosineSimilarity(query, vec)
 
 		if sim >= minSimilarity {
@@ -257,7 +269,7 @@ func (s *SQLiteStore) GetMemoriesByIDs(ctx context.Context, ids []int64) ([]*Mem
 	}
 
 	queryStr := fmt.Sprintf(
-		`SELECT id, content, source_file, source_line, source_section, content_hash, project, memory_class, metadata, imported_at, updated_at
+		`SELECT id, content, source_file, source_line, source_section, content_hash, project, memory_class, metadata, namespace, imported_at, updated_at
 		 FROM memories WHERE id IN (%s) AND deleted_at IS NULL`,
 		strings.Join(placeholders, ","),
 	)
@@ -274,7 +286,7 @@ func (s *SQLiteStore) GetMemoriesByIDs(ctx context.Context, ids []int64) ([]*Mem
 		var metadataStr sql.NullString
 		var memClass sql.NullString
 		if err := rows.Scan(&m.ID, &m.Content, &m.SourceFile, &m.SourceLine,
-			&m.SourceSection, &m.ContentHash, &m.Project, &memClass, &metadataStr, &m.ImportedAt, &m.UpdatedAt); err != nil {
+			&m.SourceSection, &m.ContentHash, &m.Project, &memClass, &metadataStr, &m.Namespace, &m.ImportedAt, &m.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scanning memory row: %w", err)
 		}
 		m.MemoryClass = memClass.String
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Enrichment states of a memory queued with MarkEnrichmentPending.
+const (
+	EnrichmentPending = "pending" // the enrich job is queued or running
+	EnrichmentStalled = "stalled" // the enrich job failed or was cancelled first
+)
+
+// EnrichmentState is where a memory's deferred LLM enrichment stands.
+type EnrichmentState struct {
+	Status   string    `json:"status"`
+	JobID    int64     `json:"job_id"`
+	QueuedAt time.Time `json:"queued_at"`
+}
+
+// migratePendingEnrichment adds pending_enrichment: memories whose LLM
+// enrichment was handed to a background job by `cortex import
+// --async-enrich`. Rows are removed as the job enriches each memory.
+func (s *SQLiteStore) migratePendingEnrichment() error {
+	done, err := s.isMetaFlagEnabled("pending_enrichment_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS pending_enrichment (
+			memory_id INTEGER PRIMARY KEY,
+			job_id    INTEGER NOT NULL,
+			queued_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_pending_enrichment_job ON pending_enrichment(job_id)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('pending_enrichment_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("migrating pending enrichment: %w", err)
+		}
+	}
+	return nil
+}
+
+// MarkEnrichmentPending records that jobID will enrich memoryIDs. A memory
+// already pending moves to the new job.
+func (s *SQLiteStore) MarkEnrichmentPending(ctx context.Context, jobID int64, memoryIDs []int64) error {
+	if len(memoryIDs) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	for _, id := range memoryIDs {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT OR REPLACE INTO pending_enrichment (memory_id, job_id, queued_at) VALUES (?, ?, ?)`,
+			id, jobID, now); err != nil {
+			return fmt.Errorf("marking memory %d pending enrichment: %w", id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ClearEnrichmentPending removes a memory from the pending list once it
+// has been enriched (or turned out to need no enrichment).
+func (s *SQLiteStore) ClearEnrichmentPending(ctx context.Context, memoryID int64) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM pending_enrichment WHERE memory_id = ?`, memoryID); err != nil {
+		return fmt.Errorf("clearing pending enrichment for memory %d: %w", memoryID, err)
+	}
+	return nil
+}
+
+// GetEnrichmentState returns a memory's deferred enrichment state, or nil
+// when nothing is waiting on it.
+func (s *SQLiteStore) GetEnrichmentState(ctx context.Context, memoryID int64) (*EnrichmentState, error) {
+	var st EnrichmentState
+	var jobStatus sql.NullString
+	err := s.db.QueryRowContext(ctx,
+		`SELECT p.job_id, p.queued_at, j.status
+		 FROM pending_enrichment p LEFT JOIN jobs j ON j.id = p.job_id
+		 WHERE p.memory_id = ?`, memoryID).Scan(&st.JobID, &st.QueuedAt, &jobStatus)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying enrichment state: %w", err)
+	}
+	st.Status = EnrichmentPending
+	if jobStatus.String != JobQueued && jobStatus.String != JobRunning {
+		st.Status = EnrichmentStalled
+	}
+	return &st, nil
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPendingEnrichment(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	a, _ := s.AddMemory(ctx, &Memory{Content: "queued for enrichment", SourceFile: "a.md"})
+	b, _ := s.AddMemory(ctx, &Memory{Content: "also queued", SourceFile: "b.md"})
+	jobID, err := s.EnqueueJob(ctx, &Job{Kind: "enrich"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MarkEnrichmentPending(ctx, jobID, []int64{a, b}); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := s.GetEnrichmentState(ctx, a)
+	if err != nil || st == nil || st.Status != EnrichmentPending || st.JobID != jobID {
+		t.Fatalf("state = %+v, %v", st, err)
+	}
+	status, _ := s.Status(ctx)
+	if status.PendingEnrichment != 2 || !strings.Contains(status.Summary, "2 memories awaiting enrichment") {
+		t.Fatalf("status = %+v", status)
+	}
+
+	if err := s.ClearEnrichmentPending(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if st, err := s.GetEnrichmentState(ctx, a); err != nil || st != nil {
+		t.Fatalf("cleared state = %+v, %v", st, err)
+	}
+
+	// A job that ends without reaching a memory leaves it stalled, and
+	// stalled memories no longer count as pending.
+	if _, err := s.CancelJob(ctx, jobID); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := s.GetEnrichmentState(ctx, b); st == nil || st.Status != EnrichmentStalled {
+		t.Fatalf("state after cancelled job = %+v", st)
+	}
+	if status, _ := s.Status(ctx); status.PendingEnrichment != 0 {
+		t.Fatalf("pending after cancelled job = %d", status.PendingEnrichment)
+	}
+}
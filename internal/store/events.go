@@ -44,9 +44,20 @@ func (s *SQLiteStore) Stats(ctx context.Context) (*StoreStats, error) {
 		{"SELECT COUNT(*) FROM embeddings", &stats.EmbeddingCount},
 		{"SELECT COUNT(*) FROM memory_events", &stats.EventCount},
 	}
-
-	for _, q := range queries {
-		if err := s.db.QueryRowContext(ctx, q.query).Scan(q.dest); err != nil {
+	var args []interface{}
+	if ns := s.Namespace(ctx); ns != AllNamespaces {
+		queries[0].query += " AND namespace = ?"
+		queries[1].query = "SELECT COUNT(*) FROM facts f JOIN memories m ON m.id = f.memory_id WHERE f.superseded_by IS NULL AND m.namespace = ?"
+		queries[2].query = "SELECT COUNT(*) FROM embeddings e JOIN memories m ON m.id = e.memory_id WHERE m.namespace = ?"
+		args = []interface{}{ns}
+	}
+
+	for i, q := range queries {
+		qargs := args
+		if i == 3 {
+			qargs = nil // the event log is shared across namespaces
+		}
+		if err := s.db.QueryRowContext(ctx, q.query, qargs...).Scan(q.dest); err != nil {
 			return nil, fmt.Errorf("querying stats (%s): %w", q.query, err)
 		}
 	}
@@ -138,6 +149,9 @@ func (s *SQLiteStore) SearchFTSWithFilters(ctx context.Context, query string, li
 		   AND m.deleted_at IS NULL`)
 
 	args := []any{query}
+	nsClause, nsArgs := s.namespaceFilter(ctx, "+m.namespace")
+	sqlBuilder.WriteString(nsClause)
+	args = append(args, nsArgs...)
 	if project != "" {
 		sqlBuilder.WriteString(`
 		   AND m.project = ?`)
@@ -210,6 +224,9 @@ func (s *SQLiteStore) searchLikeFallback(ctx context.Context, query string, limi
 	   AND deleted_at IS NULL`)
 
 	args := []any{likePattern}
+	nsClause, nsArgs := s.namespaceFilter(ctx, "namespace")
+	sqlBuilder.WriteString(nsClause)
+	args = append(args, nsArgs...)
 	if project != "" {
 		sqlBuilder.WriteString(`
 	   AND project = ?`)
@@ -303,11 +320,14 @@ func extractSnippet(content, query string) string {
 	return snippet
 }
 
-// GetSourceCount returns the number of distinct source files in memories.
+// GetSourceCount returns the number of distinct source files in memories
+// of the active namespace.
 func (s *SQLiteStore) GetSourceCount(ctx context.Context) (int, error) {
 	var count int
+	nsClause, nsArgs := s.namespaceFilter(ctx, "namespace")
 	err := s.db.QueryRowContext(ctx,
-		`SELECT COUNT(DISTINCT source_file) FROM memories WHERE deleted_at IS NULL AND source_file != ''`,
+		`SELECT COUNT(DISTINCT source_file) FROM memories WHERE deleted_at IS NULL AND source_file != ''`+nsClause,
+		nsArgs...,
 	).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("getting source count: %w", err)
@@ -315,11 +335,14 @@ func (s *SQLiteStore) GetSourceCount(ctx context.Context) (int, error) {
 	return count, nil
 }
 
-// GetAverageConfidence returns the average confidence across all facts.
+// GetAverageConfidence returns the average confidence across the active
+// namespace's facts.
 func (s *SQLiteStore) GetAverageConfidence(ctx context.Context) (float64, error) {
 	var avg float64
+	nsClause, nsArgs := s.factNamespaceFilter(ctx, "id")
 	err := s.db.QueryRowContext(ctx,
-		`SELECT COALESCE(AVG(confidence), 0.0) FROM facts WHERE superseded_by IS NULL`,
+		`SELECT COALESCE(AVG(confidence), 0.0) FROM facts WHERE superseded_by IS NULL`+nsClause,
+		nsArgs...,
 	).Scan(&avg)
 	if err != nil {
 		return 0, fmt.Errorf("getting average confidence: %w", err)
@@ -327,10 +350,14 @@ func (s *SQLiteStore) GetAverageConfidence(ctx context.Context) (float64, error)
 	return avg, nil
 }
 
-// GetFactsByType returns a distribution of facts grouped by type.
+// GetFactsByType returns a distribution of facts grouped by type; facts
+// with a custom type are counted under it rather than their base type.
 func (s *SQLiteStore) GetFactsByType(ctx context.Context) (map[string]int, error) {
+	nsClause, nsArgs := s.factNamespaceFilter(ctx, "id")
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT fact_type, COUNT(*) FROM facts WHERE superseded_by IS NULL GROUP BY fact_type ORDER BY COUNT(*) DESC`,
+		`SELECT CASE WHEN custom_type != '' THEN custom_type ELSE fact_type END AS effective_type, COUNT(*)
+		 FROM facts WHERE superseded_by IS NULL`+nsClause+` GROUP BY effective_type ORDER BY COUNT(*) DESC`,
+		nsArgs...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("getting facts by type: %w", err)
@@ -349,7 +376,8 @@ func (s *SQLiteStore) GetFactsByType(ctx context.Context) (map[string]int, error
 	return factsByType, rows.Err()
 }
 
-// GetFreshnessDistribution returns memory counts bucketed by import date.
+// GetFreshnessDistribution returns the active namespace's memory counts
+// bucketed by import date.
 func (s *SQLiteStore) GetFreshnessDistribution(ctx context.Context) (*Freshness, error) {
 	freshness := &Freshness{}
 
@@ -387,8 +415,9 @@ func (s *SQLiteStore) GetFreshnessDistribution(ctx context.Context) (*Freshness,
 		},
 	}
 
+	nsClause, nsArgs := s.namespaceFilter(ctx, "namespace")
 	for _, q := range queries {
-		if err := s.db.QueryRowContext(ctx, q.query).Scan(q.dest); err != nil {
+		if err := s.db.QueryRowContext(ctx, q.query+nsClause, nsArgs...).Scan(q.dest); err != nil {
 			return nil, fmt.Errorf("querying freshness distribution (%s): %w", q.query[:50], err)
 		}
 	}
@@ -574,6 +603,31 @@ func (s *SQLiteStore) GetAttributeConflictsLimitWithSuperseded(ctx context.Conte
 	const entitySubjectMaxLen = 40
 	entityLenClause := fmt.Sprintf("AND LENGTH(f.subject) <= %d", entitySubjectMaxLen)
 
+	// Facts only conflict with facts in the same namespace.
+	nsClause, nsArgs := s.namespaceFilter(ctx, "m.namespace")
+
+	// Direct negations first: they need no similarity judgement and are
+	// reported even for multi-valued predicates.
+	negationFilters := strings.Join([]string{supersededClause, subjDenyClause, prefixDenyClause, entityLenClause, nsClause}, "\n\t\t   ")
+	negations, err := s.negationConflicts(ctx, negationFilters, append(append(append([]any{}, subjArgs...), prefixArgs...), nsArgs...), limit)
+	if err != nil {
+		return nil, err
+	}
+	// Accepted disagreements (cortex conflicts ignore) are never reported.
+	reported, err := s.ignoredConflictPairs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []Conflict
+	for _, c := range negations {
+		key := conflictPairKey(c.Fact1.ID, c.Fact2.ID)
+		if reported[key] {
+			continue
+		}
+		reported[key] = true
+		conflicts = append(conflicts, c)
+	}
+
 	pairQuery := fmt.Sprintf(`SELECT LOWER(f.subject), LOWER(f.predicate), COUNT(DISTINCT f.object) as obj_count
 		 FROM facts f
 		 JOIN memories m ON f.memory_id = m.id AND m.deleted_at IS NULL
@@ -584,12 +638,13 @@ func (s *SQLiteStore) GetAttributeConflictsLimitWithSuperseded(ctx context.Conte
 		   %s
 		   %s
 		   %s
+		   %s
 		 GROUP BY LOWER(f.subject), LOWER(f.predicate)
 		 HAVING COUNT(DISTINCT f.object) > 1
 		 ORDER BY obj_count DESC
-		 LIMIT ?`, supersededClause, denyClause, subjDenyClause, prefixDenyClause, entityLenClause)
+		 LIMIT ?`, supersededClause, denyClause, subjDenyClause, prefixDenyClause, entityLenClause, nsClause)
 
-	pairQueryArgs := append(append(append(denyArgs, subjArgs...), prefixArgs...), limit)
+	pairQueryArgs := append(append(append(append(denyArgs, subjArgs...), prefixArgs...), nsArgs...), limit)
 	pairRows, err := s.db.QueryContext(ctx, pairQuery, pairQueryArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("finding conflicting pairs: %w", err)
@@ -614,11 +669,6 @@ func (s *SQLiteStore) GetAttributeConflictsLimitWithSuperseded(ctx context.Conte
 		return nil, err
 	}
 
-	if len(pairs) == 0 {
-		return nil, nil
-	}
-
-	var conflicts []Conflict
 	for _, p := range pairs {
 		factQuery := fmt.Sprintf(`SELECT f.id, f.memory_id, f.subject, f.predicate, f.object, f.fact_type,
 			        f.confidence, f.decay_rate, f.last_reinforced, f.source_quote, f.created_at, f.state, f.superseded_by, f.agent_id
@@ -626,11 +676,11 @@ func (s *SQLiteStore) GetAttributeConflictsLimitWithSuperseded(ctx context.Conte
 			 JOIN memories m ON f.memory_id = m.id AND m.deleted_at IS NULL
 			 WHERE LOWER(f.subject) = ? AND LOWER(f.predicate) = ?
 			   AND f.confidence > 0
-			   %s
+			   %s%s
 			 ORDER BY f.created_at DESC
-			 LIMIT 10`, supersededClause)
+			 LIMIT 10`, supersededClause, nsClause)
 
-		factRows, err := s.db.QueryContext(ctx, factQuery, p.subject, p.predicate)
+		factRows, err := s.db.QueryContext(ctx, factQuery, append([]any{p.subject, p.predicate}, nsArgs...)...)
 		if err != nil {
 			return nil, fmt.Errorf("fetching facts for pair: %w", err)
 		}
@@ -656,7 +706,7 @@ func (s *SQLiteStore) GetAttributeConflictsLimitWithSuperseded(ctx context.Conte
 
 		for i := 0; i < len(facts); i++ {
 			for j := i + 1; j < len(facts); j++ {
-				if facts[i].Object != facts[j].Object {
+				if facts[i].Object != facts[j].Object && !reported[conflictPairKey(facts[i].ID, facts[j].ID)] {
 					crossAgent := facts[i].AgentID != facts[j].AgentID &&
 						(facts[i].AgentID != "" || facts[j].AgentID != "")
 					conflicts = append(conflicts, Conflict{
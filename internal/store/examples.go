@@ -0,0 +1,181 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExtractionExample is a curated (content → facts) pair injected as a
+// few-shot example into enrichment prompts. Facts is the JSON array of
+// expected facts; the store does not interpret it.
+type ExtractionExample struct {
+	ID         int64
+	Content    string
+	Facts      string
+	Note       string
+	Embedding  []float32 // nil until embedded
+	EmbedModel string    // provider/model that produced Embedding
+	CreatedAt  time.Time
+}
+
+// ScoredExtractionExample is an example with its similarity to a query.
+type ScoredExtractionExample struct {
+	ExtractionExample
+	Similarity float64
+}
+
+func (s *SQLiteStore) migrateExtractionExamplesTable() error {
+	done, err := s.isMetaFlagEnabled("extraction_examples_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS extraction_examples (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		content     TEXT NOT NULL,
+		facts       TEXT NOT NULL DEFAULT '[]',
+		note        TEXT NOT NULL DEFAULT '',
+		embedding   BLOB,
+		embed_model TEXT NOT NULL DEFAULT '',
+		created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return fmt.Errorf("creating extraction_examples table: %w", err)
+	}
+
+	if _, err := s.db.Exec(`INSERT OR REPLACE INTO meta (key, value) VALUES ('extraction_examples_v1', 'true')`); err != nil {
+		return fmt.Errorf("setting extraction_examples_v1 flag: %w", err)
+	}
+	return nil
+}
+
+// AddExtractionExample stores a new example and returns its id.
+func (s *SQLiteStore) AddExtractionExample(ctx context.Context, ex *ExtractionExample) (int64, error) {
+	content := strings.TrimSpace(ex.Content)
+	if content == "" {
+		return 0, fmt.Errorf("example content cannot be empty")
+	}
+	facts := strings.TrimSpace(ex.Facts)
+	if facts == "" {
+		facts = "[]"
+	}
+	var blob []byte
+	if len(ex.Embedding) > 0 {
+		blob = float32ToBytes(ex.Embedding)
+	}
+
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO extraction_examples (content, facts, note, embedding, embed_model, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		content, facts, strings.TrimSpace(ex.Note), blob, strings.TrimSpace(ex.EmbedModel), time.Now().UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("inserting extraction example: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+// ListExtractionExamples returns every example, oldest first.
+func (s *SQLiteStore) ListExtractionExamples(ctx context.Context) ([]ExtractionExample, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, content, facts, note, embedding, embed_model, created_at
+		 FROM extraction_examples ORDER BY id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("listing extraction examples: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ExtractionExample
+	for rows.Next() {
+		var ex ExtractionExample
+		var blob []byte
+		if err := rows.Scan(&ex.ID, &ex.Content, &ex.Facts, &ex.Note, &blob, &ex.EmbedModel, &ex.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning extraction example: %w", err)
+		}
+		if len(blob) > 0 {
+			ex.Embedding = bytesToFloat32(blob)
+		}
+		out = append(out, ex)
+	}
+	return out, rows.Err()
+}
+
+// CountExtractionExamples returns how many examples are stored.
+func (s *SQLiteStore) CountExtractionExamples(ctx context.Context) (int, error) {
+	var n int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM extraction_examples`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting extraction examples: %w", err)
+	}
+	return n, nil
+}
+
+// SetExtractionExampleEmbedding stores the embedding for an example.
+func (s *SQLiteStore) SetExtractionExampleEmbedding(ctx context.Context, id int64, vec []float32, model string) error {
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE extraction_examples SET embedding = ?, embed_model = ? WHERE id = ?`,
+		float32ToBytes(vec), strings.TrimSpace(model), id)
+	if err != nil {
+		return fmt.Errorf("storing embedding for example %d: %w", id, err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("example %d not found", id)
+	}
+	return nil
+}
+
+// DeleteExtractionExample removes an example.
+func (s *SQLiteStore) DeleteExtractionExample(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM extraction_examples WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("deleting example %d: %w", id, err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("example %d not found", id)
+	}
+	return nil
+}
+
+// NearestExtractionExamples ranks examples embedded with model by cosine
+// similarity to query and returns up to limit of them. Examples without an
+// embedding, or embedded by a different model, are skipped.
+func (s *SQLiteStore) NearestExtractionExamples(ctx context.Context, query []float32, model string, limit int) ([]ScoredExtractionExample, error) {
+	if limit <= 0 || len(query) == 0 {
+		return nil, nil
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, content, facts, note, embedding, embed_model, created_at
+		 FROM extraction_examples WHERE embedding IS NOT NULL AND embed_model = ?`, strings.TrimSpace(model))
+	if err != nil {
+		return nil, fmt.Errorf("querying extraction examples: %w", err)
+	}
+	defer rows.Close()
+
+	var scored []ScoredExtractionExample
+	for rows.Next() {
+		var ex ExtractionExample
+		var blob []byte
+		if err := rows.Scan(&ex.ID, &ex.Content, &ex.Facts, &ex.Note, &blob, &ex.EmbedModel, &ex.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning extraction example: %w", err)
+		}
+		vec := bytesToFloat32(blob)
+		if len(vec) != len(query) {
+			continue
+		}
+		ex.Embedding = vec
+		scored = append(scored, ScoredExtractionExample{ExtractionExample: ex, Similarity: cosineSimilarity(query, vec)})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].Similarity > scored[j].Similarity })
+	if len(scored) > limit {
+		scored = scored[:limit]
+	}
+	return scored, nil
+}
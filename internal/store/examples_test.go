@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExtractionExamples_AddListNearestDelete(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+
+	if err := s.migrateExtractionExamplesTable(); err != nil {
+		t.Fatalf("second migrateExtractionExamplesTable call failed: %v", err)
+	}
+
+	trading, err := s.AddExtractionExample(ctx, &ExtractionExample{
+		Content: "ORB config locked at 15m", Facts: `[{"predicate":"config","object":"15m"}]`,
+		Embedding: []float32{1, 0, 0}, EmbedModel: "ollama/all-minilm",
+	})
+	if err != nil {
+		t.Fatalf("AddExtractionExample: %v", err)
+	}
+	medical, err := s.AddExtractionExample(ctx, &ExtractionExample{
+		Content: "Dose 5mg daily", Embedding: []float32{0, 1, 0}, EmbedModel: "ollama/all-minilm",
+	})
+	if err != nil {
+		t.Fatalf("AddExtractionExample: %v", err)
+	}
+	unembedded, err := s.AddExtractionExample(ctx, &ExtractionExample{Content: "no vector yet"})
+	if err != nil {
+		t.Fatalf("AddExtractionExample: %v", err)
+	}
+	if _, err := s.AddExtractionExample(ctx, &ExtractionExample{Content: "   "}); err == nil {
+		t.Fatal("expected error for empty content")
+	}
+
+	all, err := s.ListExtractionExamples(ctx)
+	if err != nil {
+		t.Fatalf("ListExtractionExamples: %v", err)
+	}
+	if len(all) != 3 || all[2].Facts != "[]" || all[2].Embedding != nil {
+		t.Fatalf("unexpected examples: %+v", all)
+	}
+
+	nearest, err := s.NearestExtractionExamples(ctx, []float32{0.9, 0.1, 0}, "ollama/all-minilm", 5)
+	if err != nil {
+		t.Fatalf("NearestExtractionExamples: %v", err)
+	}
+	if len(nearest) != 2 || nearest[0].ID != trading || nearest[1].ID != medical {
+		t.Fatalf("expected trading then medical, got %+v", nearest)
+	}
+	if other, _ := s.NearestExtractionExamples(ctx, []float32{1, 0, 0}, "onnx/other", 5); len(other) != 0 {
+		t.Fatalf("examples embedded by another model should be skipped, got %d", len(other))
+	}
+
+	if err := s.SetExtractionExampleEmbedding(ctx, unembedded, []float32{1, 0, 0}, "ollama/all-minilm"); err != nil {
+		t.Fatalf("SetExtractionExampleEmbedding: %v", err)
+	}
+	if nearest, _ := s.NearestExtractionExamples(ctx, []float32{1, 0, 0}, "ollama/all-minilm", 1); len(nearest) != 1 {
+		t.Fatalf("expected limit to apply, got %d", len(nearest))
+	}
+
+	if err := s.DeleteExtractionExample(ctx, medical); err != nil {
+		t.Fatalf("DeleteExtractionExample: %v", err)
+	}
+	if err := s.DeleteExtractionExample(ctx, medical); err == nil {
+		t.Fatal("expected not-found error on second delete")
+	}
+	if n, _ := s.CountExtractionExamples(ctx); n != 2 {
+		t.Fatalf("expected 2 examples left, got %d", n)
+	}
+}
@@ -0,0 +1,170 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Extraction stages tracked in extraction_yield.
+const (
+	YieldStageExtract = "extract" // rules-only extraction of imported memories
+	YieldStageEnrich  = "enrich"  // LLM enrichment on top of the rules
+)
+
+// ExtractionYieldStage is the extract stage for an extractor:
+// YieldStageExtract for the rules alone, or "extract:<provider/model>" when
+// an --llm model extracts too. Keeping them apart means empty rules-only
+// runs never skip content a model hasn't seen.
+func ExtractionYieldStage(llmModel string) string {
+	if llmModel = strings.TrimSpace(llmModel); llmModel == "" {
+		return YieldStageExtract
+	}
+	return YieldStageExtract + ":" + strings.ToLower(llmModel)
+}
+
+// ZeroYieldSkipAfter is how many extraction runs in a row must come back
+// empty before the same content is skipped by that stage.
+const ZeroYieldSkipAfter = 3
+
+// migrateExtractionYield adds extraction_yield, the zero-yield skip list:
+// per stage, how many runs in a row have produced no facts for a piece of
+// content. Rows are keyed by the content-only hash, so a reimport that
+// recreates a memory under a new ID keeps its history while edited content
+// starts over.
+func (s *SQLiteStore) migrateExtractionYield() error {
+	done, err := s.isMetaFlagEnabled("extraction_yield_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS extraction_yield (
+			content_hash TEXT NOT NULL,
+			stage        TEXT NOT NULL,
+			empty_runs   INTEGER NOT NULL DEFAULT 0,
+			last_facts   INTEGER NOT NULL DEFAULT 0,
+			updated_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (content_hash, stage)
+		)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('extraction_yield_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("migrating extraction yield: %w", err)
+		}
+	}
+	return nil
+}
+
+// RecordExtractionYield records that a stage found facts in content. An
+// empty run extends the content's streak of empty runs; any facts reset it.
+func (s *SQLiteStore) RecordExtractionYield(ctx context.Context, stage, content string, facts int) error {
+	stage = strings.TrimSpace(stage)
+	if stage == "" {
+		return fmt.Errorf("extraction stage is required")
+	}
+	empty := 0
+	if facts <= 0 {
+		empty = 1
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO extraction_yield (content_hash, stage, empty_runs, last_facts, updated_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(content_hash, stage) DO UPDATE SET
+			empty_runs = CASE WHEN excluded.empty_runs = 0 THEN 0 ELSE extraction_yield.empty_runs + 1 END,
+			last_facts = excluded.last_facts,
+			updated_at = excluded.updated_at`,
+		HashContentOnly(content), stage, empty, facts, time.Now().UTC()); err != nil {
+		return fmt.Errorf("recording extraction yield: %w", err)
+	}
+	return nil
+}
+
+// ZeroYieldMemories returns the IDs of memories whose content has come back
+// empty from stage at least ZeroYieldSkipAfter times in a row.
+func (s *SQLiteStore) ZeroYieldMemories(ctx context.Context, stage string, memories []*Memory) (map[int64]bool, error) {
+	skip := map[int64]bool{}
+	if len(memories) == 0 {
+		return skip, nil
+	}
+	byHash := make(map[string][]int64, len(memories))
+	for _, m := range memories {
+		if m != nil {
+			h := HashContentOnly(m.Content)
+			byHash[h] = append(byHash[h], m.ID)
+		}
+	}
+
+	hashes := make([]string, 0, len(byHash))
+	for h := range byHash {
+		hashes = append(hashes, h)
+	}
+	for start := 0; start < len(hashes); start += DefaultBatchSize {
+		chunk := hashes[start:min(start+DefaultBatchSize, len(hashes))]
+		args := []any{strings.TrimSpace(stage), ZeroYieldSkipAfter}
+		for _, h := range chunk {
+			args = append(args, h)
+		}
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT content_hash FROM extraction_yield
+			 WHERE stage = ? AND empty_runs >= ? AND content_hash IN (`+strings.TrimSuffix(strings.Repeat("?,", len(chunk)), ",")+`)`,
+			args...)
+		if err != nil {
+			return nil, fmt.Errorf("reading extraction yield: %w", err)
+		}
+		for rows.Next() {
+			var h string
+			if err := rows.Scan(&h); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scanning extraction yield: %w", err)
+			}
+			for _, id := range byHash[h] {
+				skip[id] = true
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading extraction yield: %w", err)
+		}
+	}
+	return skip, nil
+}
+
+// CarryOverExtractionYield copies the zero-yield skip list from the SQLite
+// database at path, such as the backup `cortex reimport` takes before it
+// wipes the store. A database without the table contributes nothing.
+func (s *SQLiteStore) CarryOverExtractionYield(ctx context.Context, path string) (int64, error) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("carrying over extraction yield: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `ATTACH DATABASE ? AS prior`, path); err != nil {
+		return 0, fmt.Errorf("attaching %s: %w", path, err)
+	}
+	defer conn.ExecContext(context.Background(), `DETACH DATABASE prior`)
+
+	var tables int
+	if err := conn.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM prior.sqlite_master WHERE type = 'table' AND name = 'extraction_yield'`,
+	).Scan(&tables); err != nil {
+		return 0, fmt.Errorf("carrying over extraction yield: %w", err)
+	}
+	if tables == 0 {
+		return 0, nil
+	}
+	res, err := conn.ExecContext(ctx,
+		`INSERT OR REPLACE INTO extraction_yield (content_hash, stage, empty_runs, last_facts, updated_at)
+		 SELECT content_hash, stage, empty_runs, last_facts, updated_at FROM prior.extraction_yield`)
+	if err != nil {
+		return 0, fmt.Errorf("carrying over extraction yield: %w", err)
+	}
+	return res.RowsAffected()
+}
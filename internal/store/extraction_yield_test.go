@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractionYieldSkipList(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	boiler := &Memory{ID: 1, Content: "--- generated log banner ---"}
+	renamed := &Memory{ID: 2, Content: boiler.Content} // same content, another file
+	edited := &Memory{ID: 3, Content: boiler.Content + " (edited)"}
+	memories := []*Memory{boiler, renamed, edited}
+
+	for i := 1; i <= ZeroYieldSkipAfter; i++ {
+		skip, err := s.ZeroYieldMemories(ctx, YieldStageExtract, memories)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(skip) != 0 {
+			t.Fatalf("skipped after %d empty runs: %v", i-1, skip)
+		}
+		if err := s.RecordExtractionYield(ctx, YieldStageExtract, boiler.Content, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	skip, err := s.ZeroYieldMemories(ctx, YieldStageExtract, memories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !skip[1] || !skip[2] || skip[3] {
+		t.Fatalf("skip = %v, want memories 1 and 2", skip)
+	}
+	if skip, _ := s.ZeroYieldMemories(ctx, YieldStageEnrich, memories); len(skip) != 0 {
+		t.Fatalf("enrich stage inherited the extract skip list: %v", skip)
+	}
+	if stage := ExtractionYieldStage("OpenRouter/x-ai/grok-4.1-fast"); stage != "extract:openrouter/x-ai/grok-4.1-fast" {
+		t.Fatalf("LLM extract stage = %q", stage)
+	} else if skip, _ := s.ZeroYieldMemories(ctx, stage, memories); len(skip) != 0 {
+		t.Fatalf("LLM extraction inherited the rules-only skip list: %v", skip)
+	}
+
+	// One productive run takes the content off the list.
+	if err := s.RecordExtractionYield(ctx, YieldStageExtract, boiler.Content, 2); err != nil {
+		t.Fatal(err)
+	}
+	if skip, _ := s.ZeroYieldMemories(ctx, YieldStageExtract, memories); len(skip) != 0 {
+		t.Fatalf("skip after a productive run = %v", skip)
+	}
+}
+
+func TestCarryOverExtractionYield(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	open := func(name string) *SQLiteStore {
+		st, err := NewStore(StoreConfig{DBPath: filepath.Join(dir, name)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { st.Close() })
+		return st.(*SQLiteStore)
+	}
+
+	prior := open("prior.db")
+	for i := 0; i < ZeroYieldSkipAfter; i++ {
+		if err := prior.RecordExtractionYield(ctx, YieldStageEnrich, "boilerplate", 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	prior.Close()
+
+	fresh := open("fresh.db")
+	n, err := fresh.CarryOverExtractionYield(ctx, filepath.Join(dir, "prior.db"))
+	if err != nil || n != 1 {
+		t.Fatalf("carried %d rows, %v", n, err)
+	}
+	skip, err := fresh.ZeroYieldMemories(ctx, YieldStageEnrich, []*Memory{{ID: 7, Content: "boilerplate"}})
+	if err != nil || !skip[7] {
+		t.Fatalf("skip = %v, %v", skip, err)
+	}
+}
@@ -27,11 +27,11 @@ var ReinforcementWeights = map[AccessType]float64{
 
 // FactAccess represents a recorded access to a fact.
 type FactAccess struct {
-	ID         int64
-	FactID     int64
-	AgentID    string
-	AccessType AccessType
-	CreatedAt  time.Time
+	ID         int64      `json:"id"`
+	FactID     int64      `json:"fact_id"`
+	AgentID    string     `json:"agent_id,omitempty"`
+	AccessType AccessType `json:"access_type"`
+	CreatedAt  time.Time  `json:"created_at"`
 }
 
 // FactAccessSummary provides an aggregate view of a fact's access patterns.
@@ -116,7 +116,7 @@ func (s *SQLiteStore) GetFactAccessSummary(ctx context.Context, factID int64) (*
 	var lastAccessStr *string
 	err := s.db.QueryRowContext(ctx,
 		`SELECT COUNT(*), 
-		        SUM(CASE WHEN access_type = 'search' THEN 1 ELSE 0 END),
+		        COALESCE(SUM(CASE WHEN access_type = 'search' THEN 1 ELSE 0 END), 0),
 		        MAX(created_at)
 		 FROM fact_accesses_v1 WHERE fact_id = ?`, factID,
 	).Scan(&summary.TotalAccess, &summary.SearchCount, &lastAccessStr)
@@ -12,11 +12,13 @@ import (
 type EdgeType string
 
 const (
-	EdgeTypeSupports    EdgeType = "supports"
-	EdgeTypeContradicts EdgeType = "contradicts"
-	EdgeTypeRelatesTo   EdgeType = "relates_to"
-	EdgeTypeSupersedes  EdgeType = "supersedes"
-	EdgeTypeDerivedFrom EdgeType = "derived_from"
+	EdgeTypeSupports      EdgeType = "supports"
+	EdgeTypeContradicts   EdgeType = "contradicts"
+	EdgeTypeRelatesTo     EdgeType = "relates_to"
+	EdgeTypeSupersedes    EdgeType = "supersedes"
+	EdgeTypeDerivedFrom   EdgeType = "derived_from"
+	EdgeTypeSummarizes    EdgeType = "summarizes"     // cluster summary → a fact it replaced
+	EdgeTypeTranslationOf EdgeType = "translation_of" // translated fact → its original
 )
 
 // EdgeSource defines how an edge was created.
@@ -89,12 +91,15 @@ func (s *SQLiteStore) AddEdge(ctx context.Context, edge *FactEdge) error {
 
 // GetEdgesForFact returns all edges where the given fact is source or target.
 func (s *SQLiteStore) GetEdgesForFact(ctx context.Context, factID int64) ([]FactEdge, error) {
+	srcClause, srcArgs := s.factNamespaceFilter(ctx, "source_fact_id")
+	dstClause, dstArgs := s.factNamespaceFilter(ctx, "target_fact_id")
+	args := append([]interface{}{factID, factID}, srcArgs...)
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT id, source_fact_id, target_fact_id, edge_type, confidence, source, agent_id, created_at
 		 FROM fact_edges_v1
-		 WHERE source_fact_id = ? OR target_fact_id = ?
+		 WHERE (source_fact_id = ? OR target_fact_id = ?)`+srcClause+dstClause+`
 		 ORDER BY created_at DESC`,
-		factID, factID,
+		append(args, dstArgs...)...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("getting edges for fact %d: %w", factID, err)
@@ -109,10 +114,12 @@ func (s *SQLiteStore) GetEdgesByType(ctx context.Context, edgeType EdgeType, lim
 	if limit <= 0 {
 		limit = 50
 	}
+	nsClause, nsArgs := s.factNamespaceFilter(ctx, "source_fact_id")
+	args := append([]interface{}{string(edgeType)}, nsArgs...)
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT id, source_fact_id, target_fact_id, edge_type, confidence, source, agent_id, created_at
-		 FROM fact_edges_v1 WHERE edge_type = ? ORDER BY created_at DESC LIMIT ?`,
-		string(edgeType), limit,
+		 FROM fact_edges_v1 WHERE edge_type = ?`+nsClause+` ORDER BY created_at DESC LIMIT ?`,
+		append(args, limit)...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("getting edges by type: %w", err)
@@ -122,6 +129,22 @@ func (s *SQLiteStore) GetEdgesByType(ctx context.Context, edgeType EdgeType, lim
 	return scanEdges(rows)
 }
 
+// GetEdge returns the edge with the given ID, or nil if there is none.
+func (s *SQLiteStore) GetEdge(ctx context.Context, edgeID int64) (*FactEdge, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, source_fact_id, target_fact_id, edge_type, confidence, source, agent_id, created_at
+		 FROM fact_edges_v1 WHERE id = ?`, edgeID)
+	if err != nil {
+		return nil, fmt.Errorf("getting edge %d: %w", edgeID, err)
+	}
+	defer rows.Close()
+	edges, err := scanEdges(rows)
+	if err != nil || len(edges) == 0 {
+		return nil, err
+	}
+	return &edges[0], nil
+}
+
 // RemoveEdge deletes an edge by ID.
 func (s *SQLiteStore) RemoveEdge(ctx context.Context, edgeID int64) error {
 	result, err := s.db.ExecContext(ctx, `DELETE FROM fact_edges_v1 WHERE id = ?`, edgeID)
@@ -136,7 +159,8 @@ func (s *SQLiteStore) RemoveEdge(ctx context.Context, edgeID int64) error {
 }
 
 // TraverseGraph performs a breadth-first traversal from a starting fact,
-// following edges up to maxDepth hops. Returns all reachable facts with their edges.
+// following edges up to maxDepth hops. Returns all reachable facts in ctx's
+// namespace with their edges.
 func (s *SQLiteStore) TraverseGraph(ctx context.Context, startFactID int64, maxDepth int, minConfidence float64) ([]GraphNode, error) {
 	if maxDepth <= 0 {
 		maxDepth = 2
@@ -173,8 +197,8 @@ func (s *SQLiteStore) TraverseGraph(ctx context.Context, startFactID int64, maxD
 			}
 			continue // Fact may have been deleted
 		}
-		if fact == nil {
-			continue
+		if fact == nil || !s.FactInNamespace(ctx, item.factID) {
+			continue // only facts in ctx's namespace, the start fact included
 		}
 
 		// Get edges
@@ -207,7 +231,7 @@ func (s *SQLiteStore) TraverseGraph(ctx context.Context, startFactID int64, maxD
 				if neighborID == item.factID {
 					neighborID = e.SourceFactID
 				}
-				if !visited[neighborID] {
+				if !visited[neighborID] && s.FactInNamespace(ctx, neighborID) {
 					visited[neighborID] = true
 					queue = append(queue, queueItem{neighborID, item.depth + 1})
 				}
@@ -223,7 +247,7 @@ func (s *SQLiteStore) TraverseGraph(ctx context.Context, startFactID int64, maxD
 				if neighborID == item.factID {
 					neighborID = c.FactIDA
 				}
-				if !visited[neighborID] {
+				if !visited[neighborID] && s.FactInNamespace(ctx, neighborID) {
 					visited[neighborID] = true
 					queue = append(queue, queueItem{neighborID, item.depth + 1})
 				}
@@ -237,7 +261,8 @@ func (s *SQLiteStore) TraverseGraph(ctx context.Context, startFactID int64, maxD
 // CountEdges returns the total number of edges in the graph.
 func (s *SQLiteStore) CountEdges(ctx context.Context) (int, error) {
 	var count int
-	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM fact_edges_v1`).Scan(&count)
+	nsClause, nsArgs := s.factNamespaceFilter(ctx, "source_fact_id")
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM fact_edges_v1 WHERE 1 = 1`+nsClause, nsArgs...).Scan(&count)
 	return count, err
 }
 
@@ -284,16 +309,102 @@ func ValidEdgeTypes() []string {
 	return []string{
 		string(EdgeTypeSupports), string(EdgeTypeContradicts),
 		string(EdgeTypeRelatesTo), string(EdgeTypeSupersedes),
-		string(EdgeTypeDerivedFrom),
+		string(EdgeTypeDerivedFrom), string(EdgeTypeSummarizes),
+		string(EdgeTypeTranslationOf),
 	}
 }
 
 // ParseEdgeType validates and returns an EdgeType.
 func ParseEdgeType(s string) (EdgeType, error) {
 	switch EdgeType(strings.ToLower(s)) {
-	case EdgeTypeSupports, EdgeTypeContradicts, EdgeTypeRelatesTo, EdgeTypeSupersedes, EdgeTypeDerivedFrom:
+	case EdgeTypeSupports, EdgeTypeContradicts, EdgeTypeRelatesTo, EdgeTypeSupersedes, EdgeTypeDerivedFrom, EdgeTypeSummarizes, EdgeTypeTranslationOf:
 		return EdgeType(strings.ToLower(s)), nil
 	default:
 		return "", fmt.Errorf("invalid edge type %q (valid: %s)", s, strings.Join(ValidEdgeTypes(), ", "))
 	}
 }
+
+// widenEdgeTypeCheck rebuilds fact_edges_v1 so its edge_type CHECK accepts
+// every ValidEdgeTypes entry, unless it already accepts t. SQLite cannot
+// alter a CHECK constraint, so the table is copied.
+func (s *SQLiteStore) widenEdgeTypeCheck(t EdgeType) error {
+	var ddl string
+	err := s.db.QueryRow(`SELECT sql FROM sqlite_master WHERE type='table' AND name='fact_edges_v1'`).Scan(&ddl)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading fact_edges_v1 schema: %w", err)
+	}
+	if strings.Contains(ddl, "'"+string(t)+"'") {
+		return nil
+	}
+
+	types := ValidEdgeTypes()
+	quoted := make([]string, len(types))
+	for i, v := range types {
+		quoted[i] = "'" + v + "'"
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("rebuilding fact_edges_v1: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`CREATE TABLE fact_edges_v1_rebuild (
+			id INTEGER PRIMARY KEY,
+			source_fact_id INTEGER NOT NULL,
+			target_fact_id INTEGER NOT NULL,
+			edge_type TEXT NOT NULL CHECK (edge_type IN (` + strings.Join(quoted, ",") + `)),
+			confidence REAL DEFAULT 1.0 CHECK (confidence >= 0 AND confidence <= 1.0),
+			source TEXT NOT NULL DEFAULT 'explicit' CHECK (source IN ('explicit','detected','inferred')),
+			agent_id TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (source_fact_id) REFERENCES facts(id),
+			FOREIGN KEY (target_fact_id) REFERENCES facts(id),
+			UNIQUE(source_fact_id, target_fact_id, edge_type),
+			CHECK (source_fact_id != target_fact_id)
+		)`,
+		`INSERT INTO fact_edges_v1_rebuild (id, source_fact_id, target_fact_id, edge_type, confidence, source, agent_id, created_at)
+		 SELECT id, source_fact_id, target_fact_id, edge_type, confidence, source, agent_id, created_at FROM fact_edges_v1`,
+		`DROP TABLE fact_edges_v1`,
+		`ALTER TABLE fact_edges_v1_rebuild RENAME TO fact_edges_v1`,
+		`CREATE INDEX IF NOT EXISTS idx_fact_edges_source ON fact_edges_v1(source_fact_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_fact_edges_target ON fact_edges_v1(target_fact_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_fact_edges_type ON fact_edges_v1(edge_type)`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("rebuilding fact_edges_v1: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// EdgesAmongFacts returns the edges whose source and target are both in
+// factIDs.
+func (s *SQLiteStore) EdgesAmongFacts(ctx context.Context, factIDs []int64) ([]FactEdge, error) {
+	if len(factIDs) < 2 {
+		return nil, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(factIDs)), ",")
+	args := make([]interface{}, 0, 2*len(factIDs))
+	for _, id := range factIDs {
+		args = append(args, id)
+	}
+	args = append(args, args...)
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, source_fact_id, target_fact_id, edge_type, confidence, source, agent_id, created_at
+		 FROM fact_edges_v1
+		 WHERE source_fact_id IN (`+placeholders+`) AND target_fact_id IN (`+placeholders+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting edges among facts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanEdges(rows)
+}
@@ -201,6 +201,41 @@ func TestSupersedeCreatesEdge(t *testing.T) {
 	}
 }
 
+func TestRestoreSupersededFact(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "test", SourceFile: "t.md"})
+	f1, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "config", Predicate: "value", Object: "old", FactType: "kv", Confidence: 0.7})
+	f2, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "config", Predicate: "value", Object: "new", FactType: "kv"})
+	f3, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "config", Predicate: "value", Object: "newer", FactType: "kv"})
+
+	if err := s.SupersedeFact(ctx, f1, f2, "updated"); err != nil {
+		t.Fatalf("SupersedeFact: %v", err)
+	}
+	// Only a fact still superseded by the given fact is restored.
+	if ok, err := s.RestoreSupersededFact(ctx, f1, f3, 0.7, FactStateCore); err != nil || ok {
+		t.Fatalf("RestoreSupersededFact(wrong superseder) = %v, %v", ok, err)
+	}
+	ok, err := s.RestoreSupersededFact(ctx, f1, f2, 0.7, FactStateCore)
+	if err != nil || !ok {
+		t.Fatalf("RestoreSupersededFact = %v, %v", ok, err)
+	}
+	f, _ := s.GetFact(ctx, f1)
+	if f.SupersededBy != nil || f.Confidence != 0.7 || f.State != FactStateCore {
+		t.Fatalf("restored fact = %+v", f)
+	}
+	edges, _ := s.GetEdgesForFact(ctx, f1)
+	for _, e := range edges {
+		if e.EdgeType == EdgeTypeSupersedes {
+			t.Fatal("supersedes edge should be removed on restore")
+		}
+	}
+	if ok, _ := s.RestoreSupersededFact(ctx, f1, f2, 0.7, FactStateActive); ok {
+		t.Fatal("restoring twice should be a no-op")
+	}
+}
+
 func TestDecayInferredEdges(t *testing.T) {
 	s := newTestSQLiteStore(t)
 	ctx := context.Background()
@@ -235,3 +270,26 @@ func TestParseEdgeType(t *testing.T) {
 		t.Error("Expected error for invalid edge type")
 	}
 }
+
+func TestEdgesAmongFacts(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "test", SourceFile: "t.md"})
+	f1, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "a", Predicate: "p", Object: "o1", FactType: "kv"})
+	f2, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "b", Predicate: "p", Object: "o2", FactType: "kv"})
+	f3, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "c", Predicate: "p", Object: "o3", FactType: "kv"})
+	s.AddEdge(ctx, &FactEdge{SourceFactID: f1, TargetFactID: f2, EdgeType: EdgeTypeSupports})
+	s.AddEdge(ctx, &FactEdge{SourceFactID: f2, TargetFactID: f3, EdgeType: EdgeTypeRelatesTo})
+
+	edges, err := s.EdgesAmongFacts(ctx, []int64{f1, f2})
+	if err != nil {
+		t.Fatalf("EdgesAmongFacts: %v", err)
+	}
+	if len(edges) != 1 || edges[0].SourceFactID != f1 || edges[0].TargetFactID != f2 {
+		t.Fatalf("expected only the f1→f2 edge, got %+v", edges)
+	}
+	if edges, _ := s.EdgesAmongFacts(ctx, []int64{f1}); len(edges) != 0 {
+		t.Fatalf("a single fact has no edges among itself, got %+v", edges)
+	}
+}
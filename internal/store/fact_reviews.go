@@ -0,0 +1,283 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Fact review lifecycle constants.
+const (
+	FactReviewPending  = "pending"
+	FactReviewApproved = "approved"
+	FactReviewRejected = "rejected"
+)
+
+// FactReview is a low-confidence extracted fact held for review. It is not a
+// fact: it never appears in search, recall, or the graph until approved, at
+// which point it is written to facts like any other extraction.
+type FactReview struct {
+	ID          int64      `json:"id"`
+	MemoryID    int64      `json:"memory_id"`
+	Subject     string     `json:"subject"`
+	Predicate   string     `json:"predicate"`
+	Object      string     `json:"object"`
+	FactType    string     `json:"type"`
+	Confidence  float64    `json:"confidence"`
+	DecayRate   float64    `json:"decay_rate"`
+	SourceQuote string     `json:"source_quote,omitempty"`
+	Method      string     `json:"method"` // extraction method, e.g. "llm-enrich"
+	Model       string     `json:"model,omitempty"`
+	Status      string     `json:"status"`
+	FactID      *int64     `json:"fact_id,omitempty"` // set once approved
+	Reviewer    string     `json:"reviewer,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+}
+
+// FactReviewListOpts filters ListFactReviews.
+type FactReviewListOpts struct {
+	// Status is "pending" (default when empty), "approved", "rejected", or "all".
+	Status string
+	// BelowConfidence keeps only reviews with confidence < this (0 = no filter).
+	BelowConfidence float64
+	// Limit caps the result count (0 = no limit).
+	Limit int
+}
+
+func (s *SQLiteStore) migrateFactReviewsTable() error {
+	done, err := s.isMetaFlagEnabled("fact_reviews_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS fact_reviews (
+			id           INTEGER PRIMARY KEY AUTOINCREMENT,
+			memory_id    INTEGER NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+			subject      TEXT NOT NULL DEFAULT '',
+			predicate    TEXT NOT NULL,
+			object       TEXT NOT NULL,
+			fact_type    TEXT NOT NULL DEFAULT 'kv',
+			confidence   REAL NOT NULL,
+			decay_rate   REAL NOT NULL DEFAULT 0,
+			source_quote TEXT NOT NULL DEFAULT '',
+			method       TEXT NOT NULL DEFAULT '',
+			model        TEXT NOT NULL DEFAULT '',
+			status       TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+			fact_id      INTEGER,
+			reviewer     TEXT NOT NULL DEFAULT '',
+			reason       TEXT NOT NULL DEFAULT '',
+			created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			resolved_at  DATETIME
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_fact_reviews_status ON fact_reviews(status, confidence)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('fact_reviews_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating fact_reviews table: %w", err)
+		}
+	}
+	return nil
+}
+
+// QueueFactReview holds a candidate fact for review instead of storing it.
+func (s *SQLiteStore) QueueFactReview(ctx context.Context, r *FactReview) (int64, error) {
+	if r == nil {
+		return 0, fmt.Errorf("review is required")
+	}
+	if strings.TrimSpace(r.Predicate) == "" || strings.TrimSpace(r.Object) == "" {
+		return 0, fmt.Errorf("predicate and object are required")
+	}
+	factType := r.FactType
+	if factType == "" {
+		factType = "kv"
+	}
+	now := time.Now().UTC()
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO fact_reviews (memory_id, subject, predicate, object, fact_type, confidence, decay_rate, source_quote, method, model, status, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'pending', ?)`,
+		r.MemoryID, r.Subject, r.Predicate, r.Object, factType, r.Confidence, r.DecayRate, r.SourceQuote, r.Method, r.Model, now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("queueing fact for review: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("getting review id: %w", err)
+	}
+	r.ID = id
+	r.FactType = factType
+	r.Status = FactReviewPending
+	r.CreatedAt = now
+	return id, nil
+}
+
+// GetFactReview returns one review by id, or (nil, nil) if not found.
+func (s *SQLiteStore) GetFactReview(ctx context.Context, id int64) (*FactReview, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+factReviewColumns+` FROM fact_reviews WHERE id = ?`, id)
+	r, err := scanFactReviewRow(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting review %d: %w", id, err)
+	}
+	return r, nil
+}
+
+// ListFactReviews returns reviews matching opts, lowest confidence first.
+func (s *SQLiteStore) ListFactReviews(ctx context.Context, opts FactReviewListOpts) ([]*FactReview, error) {
+	query := `SELECT ` + factReviewColumns + ` FROM fact_reviews`
+	var where []string
+	var args []any
+
+	status := strings.TrimSpace(strings.ToLower(opts.Status))
+	switch status {
+	case "":
+		where = append(where, "status = ?")
+		args = append(args, FactReviewPending)
+	case FactReviewPending, FactReviewApproved, FactReviewRejected:
+		where = append(where, "status = ?")
+		args = append(args, status)
+	case "all":
+	default:
+		return nil, fmt.Errorf("invalid status filter %q (valid: pending, approved, rejected, all)", opts.Status)
+	}
+	if opts.BelowConfidence > 0 {
+		where = append(where, "confidence < ?")
+		args = append(args, opts.BelowConfidence)
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY confidence ASC, id ASC"
+	if opts.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, opts.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*FactReview
+	for rows.Next() {
+		r, err := scanFactReviewRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning review row: %w", err)
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// CountPendingFactReviews returns how many reviews await a decision.
+func (s *SQLiteStore) CountPendingFactReviews(ctx context.Context) (int, error) {
+	var n int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM fact_reviews WHERE status = 'pending'`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting pending reviews: %w", err)
+	}
+	return n, nil
+}
+
+// ApproveFactReview writes a pending review to facts and marks it approved.
+// It returns the new fact id.
+func (s *SQLiteStore) ApproveFactReview(ctx context.Context, id int64, reviewer string) (int64, error) {
+	r, err := s.pendingFactReview(ctx, id, "approved")
+	if err != nil {
+		return 0, err
+	}
+
+	factID, err := s.AddFact(ctx, &Fact{
+		MemoryID:    r.MemoryID,
+		Subject:     r.Subject,
+		Predicate:   r.Predicate,
+		Object:      r.Object,
+		FactType:    r.FactType,
+		Confidence:  r.Confidence,
+		DecayRate:   r.DecayRate,
+		SourceQuote: r.SourceQuote,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("storing fact from review %d: %w", id, err)
+	}
+
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE fact_reviews SET status = 'approved', fact_id = ?, reviewer = ?, resolved_at = ?
+		 WHERE id = ? AND status = 'pending'`,
+		factID, strings.TrimSpace(reviewer), time.Now().UTC(), id,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("marking review %d approved: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return 0, fmt.Errorf("review %d was resolved concurrently", id)
+	}
+	return factID, nil
+}
+
+// RejectFactReview marks a pending review rejected; nothing is written to facts.
+func (s *SQLiteStore) RejectFactReview(ctx context.Context, id int64, reviewer, reason string) error {
+	if _, err := s.pendingFactReview(ctx, id, "rejected"); err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE fact_reviews SET status = 'rejected', reviewer = ?, reason = ?, resolved_at = ?
+		 WHERE id = ? AND status = 'pending'`,
+		strings.TrimSpace(reviewer), strings.TrimSpace(reason), time.Now().UTC(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("rejecting review %d: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("review %d was resolved concurrently", id)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) pendingFactReview(ctx context.Context, id int64, verb string) (*FactReview, error) {
+	r, err := s.GetFactReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, fmt.Errorf("review %d not found", id)
+	}
+	if r.Status != FactReviewPending {
+		return nil, fmt.Errorf("review %d is %s; only pending reviews can be %s", id, r.Status, verb)
+	}
+	return r, nil
+}
+
+const factReviewColumns = `id, memory_id, subject, predicate, object, fact_type, confidence, decay_rate,
+	source_quote, method, model, status, fact_id, reviewer, reason, created_at, resolved_at`
+
+// scanFactReviewRow scans one review from *sql.Row or *sql.Rows.
+func scanFactReviewRow(sc interface{ Scan(...any) error }) (*FactReview, error) {
+	var r FactReview
+	var factID sql.NullInt64
+	var resolvedAt sql.NullTime
+	if err := sc.Scan(&r.ID, &r.MemoryID, &r.Subject, &r.Predicate, &r.Object, &r.FactType, &r.Confidence, &r.DecayRate,
+		&r.SourceQuote, &r.Method, &r.Model, &r.Status, &factID, &r.Reviewer, &r.Reason, &r.CreatedAt, &resolvedAt); err != nil {
+		return nil, err
+	}
+	if factID.Valid {
+		v := factID.Int64
+		r.FactID = &v
+	}
+	if resolvedAt.Valid {
+		t := resolvedAt.Time
+		r.ResolvedAt = &t
+	}
+	return &r, nil
+}
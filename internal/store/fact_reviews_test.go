@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFactReviews_QueueApproveReject(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+
+	if err := s.migrateFactReviewsTable(); err != nil {
+		t.Fatalf("second migrateFactReviewsTable call failed: %v", err)
+	}
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "We might move to Denver, maybe next year"})
+	low, err := s.QueueFactReview(ctx, &FactReview{MemoryID: memID, Subject: "Q", Predicate: "might move to", Object: "Denver", FactType: "location", Confidence: 0.3, Method: "llm-enrich", Model: "openrouter/x"})
+	if err != nil {
+		t.Fatalf("QueueFactReview: %v", err)
+	}
+	mid, err := s.QueueFactReview(ctx, &FactReview{MemoryID: memID, Subject: "Q", Predicate: "move timing", Object: "next year", Confidence: 0.45})
+	if err != nil {
+		t.Fatalf("QueueFactReview: %v", err)
+	}
+	if _, err := s.QueueFactReview(ctx, &FactReview{MemoryID: memID, Predicate: "", Object: "x"}); err == nil {
+		t.Fatal("expected error for missing predicate")
+	}
+
+	pending, err := s.ListFactReviews(ctx, FactReviewListOpts{})
+	if err != nil {
+		t.Fatalf("ListFactReviews: %v", err)
+	}
+	if len(pending) != 2 || pending[0].ID != low || pending[1].FactType != "kv" {
+		t.Fatalf("expected lowest confidence first with kv default, got %+v", pending)
+	}
+	if below, _ := s.ListFactReviews(ctx, FactReviewListOpts{BelowConfidence: 0.4}); len(below) != 1 || below[0].ID != low {
+		t.Fatalf("expected only the 0.3 review below 0.4, got %+v", below)
+	}
+	if _, err := s.ListFactReviews(ctx, FactReviewListOpts{Status: "bogus"}); err == nil {
+		t.Fatal("expected error for invalid status filter")
+	}
+
+	// Queued facts are not facts until approved.
+	if facts, _ := s.ListFacts(ctx, ListOpts{Limit: 10}); len(facts) != 0 {
+		t.Fatalf("queued reviews must not be stored as facts, got %d", len(facts))
+	}
+
+	factID, err := s.ApproveFactReview(ctx, low, "q")
+	if err != nil {
+		t.Fatalf("ApproveFactReview: %v", err)
+	}
+	f, _ := s.GetFact(ctx, factID)
+	if f == nil || f.Object != "Denver" || f.FactType != "location" || f.Confidence != 0.3 {
+		t.Fatalf("unexpected approved fact: %+v", f)
+	}
+	r, _ := s.GetFactReview(ctx, low)
+	if r.Status != FactReviewApproved || r.FactID == nil || *r.FactID != factID || r.Reviewer != "q" || r.ResolvedAt == nil {
+		t.Fatalf("unexpected approved review: %+v", r)
+	}
+	if _, err := s.ApproveFactReview(ctx, low, ""); err == nil {
+		t.Fatal("expected error approving a resolved review")
+	}
+
+	if err := s.RejectFactReview(ctx, mid, "", "too vague"); err != nil {
+		t.Fatalf("RejectFactReview: %v", err)
+	}
+	if err := s.RejectFactReview(ctx, 9999, "", ""); err == nil {
+		t.Fatal("expected error rejecting a missing review")
+	}
+	if n, _ := s.CountPendingFactReviews(ctx); n != 0 {
+		t.Fatalf("expected no pending reviews, got %d", n)
+	}
+	if all, _ := s.ListFactReviews(ctx, FactReviewListOpts{Status: "all"}); len(all) != 2 {
+		t.Fatalf("expected 2 reviews with status=all, got %d", len(all))
+	}
+}
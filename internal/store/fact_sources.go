@@ -0,0 +1,241 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A fact's memory_id names one memory, but summaries and merges combine
+// facts from many. fact_sources records every memory that contributed to a
+// fact, with the role it played, so provenance survives consolidation and
+// deleting one of the originals. memory_id stays the primary backlink.
+const (
+	FactSourceExtracted    = "extracted"    // the fact was extracted from the memory
+	FactSourceCorroborated = "corroborated" // the memory repeated an existing fact
+	FactSourceSummarized   = "summarized"   // a cluster summary replaced facts from the memory
+	FactSourceMerged       = "merged"       // dedup merged a fact from the memory into this one
+)
+
+// FactSource is one memory that contributed to a fact.
+type FactSource struct {
+	FactID     int64     `json:"fact_id"`
+	MemoryID   int64     `json:"memory_id"`
+	Role       string    `json:"role"`
+	SourceFile string    `json:"source_file,omitempty"`
+	Deleted    bool      `json:"deleted,omitempty"` // the memory is soft-deleted
+	Primary    bool      `json:"primary,omitempty"` // the fact's memory_id
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// derivedFactSourceRoles are the roles that make a fact stand on its own
+// when its primary memory goes away: it was built from several memories.
+const derivedFactSourceRoles = `('` + FactSourceSummarized + `', '` + FactSourceMerged + `')`
+
+// factSourceBackfill links facts to the memories they came from, using the
+// records that existed before fact_sources: memory_id, corroborations, the
+// cluster summary journal, and dedup supersede events. Every statement is
+// INSERT OR IGNORE, so it is safe to run again (RepairFactSources does).
+var factSourceBackfill = []string{
+	`INSERT OR IGNORE INTO fact_sources (fact_id, memory_id, role, created_at)
+	 SELECT f.id, f.memory_id, '` + FactSourceExtracted + `', COALESCE(f.created_at, CURRENT_TIMESTAMP)
+	 FROM facts f JOIN memories m ON m.id = f.memory_id
+	 WHERE f.id NOT IN (SELECT summary_fact_id FROM fact_summaries)`,
+	`INSERT OR IGNORE INTO fact_sources (fact_id, memory_id, role, created_at)
+	 SELECT c.fact_id, c.memory_id, '` + FactSourceCorroborated + `', c.created_at
+	 FROM fact_corroborations c
+	 JOIN facts f ON f.id = c.fact_id JOIN memories m ON m.id = c.memory_id`,
+	`INSERT OR IGNORE INTO fact_sources (fact_id, memory_id, role, created_at)
+	 SELECT DISTINCT s.summary_fact_id, src.memory_id, '` + FactSourceSummarized + `', s.created_at
+	 FROM fact_summaries s
+	 JOIN facts f ON f.id = s.summary_fact_id
+	 JOIN fact_sources src ON src.fact_id = s.original_fact_id
+	 WHERE s.undone_at IS NULL`,
+	`INSERT OR IGNORE INTO fact_sources (fact_id, memory_id, role, created_at)
+	 SELECT f.id, f.memory_id, '` + FactSourceSummarized + `', COALESCE(f.created_at, CURRENT_TIMESTAMP)
+	 FROM facts f JOIN memories m ON m.id = f.memory_id
+	 WHERE f.id IN (SELECT summary_fact_id FROM fact_summaries)`,
+	`INSERT OR IGNORE INTO fact_sources (fact_id, memory_id, role, created_at)
+	 SELECT DISTINCT f.superseded_by, src.memory_id, '` + FactSourceMerged + `', COALESCE(e.created_at, CURRENT_TIMESTAMP)
+	 FROM facts f
+	 JOIN facts w ON w.id = f.superseded_by
+	 JOIN fact_sources src ON src.fact_id = f.id
+	 JOIN memory_events e ON e.fact_id = f.id
+	 WHERE e.new_value LIKE 'superseded_by:%reason:dedup-facts%'
+	    OR e.new_value LIKE 'superseded_by:%reason:semantic-dedup%'`,
+}
+
+// migrateFactSources creates fact_sources and backfills it.
+func (s *SQLiteStore) migrateFactSources() error {
+	done, err := s.isMetaFlagEnabled("fact_sources_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS fact_sources (
+			fact_id    INTEGER NOT NULL REFERENCES facts(id) ON DELETE CASCADE,
+			memory_id  INTEGER NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+			role       TEXT NOT NULL DEFAULT 'extracted',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (fact_id, memory_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_fact_sources_memory ON fact_sources(memory_id)`,
+	}
+	statements = append(statements, factSourceBackfill...)
+	statements = append(statements, `INSERT OR REPLACE INTO meta (key, value) VALUES ('fact_sources_v1', 'true')`)
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("migrating fact sources: %w", err)
+		}
+	}
+	return nil
+}
+
+// AddFactSources links a fact to contributing memories. Links that already
+// exist keep their role.
+func (s *SQLiteStore) AddFactSources(ctx context.Context, factID int64, memoryIDs []int64, role string) error {
+	now := time.Now().UTC()
+	for _, memoryID := range memoryIDs {
+		if memoryID <= 0 {
+			continue
+		}
+		if _, err := s.db.ExecContext(ctx,
+			`INSERT OR IGNORE INTO fact_sources (fact_id, memory_id, role, created_at) VALUES (?, ?, ?, ?)`,
+			factID, memoryID, role, now,
+		); err != nil {
+			return fmt.Errorf("linking fact %d to memory %d: %w", factID, memoryID, err)
+		}
+	}
+	return nil
+}
+
+// inheritFactSources links factID to every memory behind fromFactIDs, with
+// role. Memories already linked to factID keep their role.
+func (s *SQLiteStore) inheritFactSources(ctx context.Context, factID int64, fromFactIDs []int64, role string) error {
+	if len(fromFactIDs) == 0 {
+		return nil
+	}
+	args := []interface{}{factID, role, time.Now().UTC()}
+	for _, id := range fromFactIDs {
+		args = append(args, id)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT OR IGNORE INTO fact_sources (fact_id, memory_id, role, created_at)
+		 SELECT DISTINCT ?, memory_id, ?, ? FROM fact_sources
+		 WHERE fact_id IN (?`+strings.Repeat(",?", len(fromFactIDs)-1)+`)`, args...,
+	); err != nil {
+		return fmt.Errorf("inheriting sources for fact %d: %w", factID, err)
+	}
+	return nil
+}
+
+// ListFactSources returns the memories behind a fact, primary first, then
+// oldest first.
+func (s *SQLiteStore) ListFactSources(ctx context.Context, factID int64) ([]FactSource, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT src.fact_id, src.memory_id, src.role, m.source_file, m.deleted_at IS NOT NULL,
+		        src.memory_id = f.memory_id, src.created_at
+		 FROM fact_sources src
+		 JOIN facts f ON f.id = src.fact_id
+		 JOIN memories m ON m.id = src.memory_id
+		 WHERE src.fact_id = ?
+		 ORDER BY src.memory_id = f.memory_id DESC, src.created_at ASC, src.memory_id ASC`, factID)
+	if err != nil {
+		return nil, fmt.Errorf("listing sources for fact %d: %w", factID, err)
+	}
+	defer rows.Close()
+	var out []FactSource
+	for rows.Next() {
+		var fs FactSource
+		if err := rows.Scan(&fs.FactID, &fs.MemoryID, &fs.Role, &fs.SourceFile, &fs.Deleted, &fs.Primary, &fs.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning fact source: %w", err)
+		}
+		out = append(out, fs)
+	}
+	return out, rows.Err()
+}
+
+// liveDerivedSource picks the memory a fact falls back to when its primary
+// memory is gone: the lowest live memory linked as a summary or merge source.
+// The first placeholder is the memory being removed (0 for none).
+const liveDerivedSource = `SELECT MIN(src.memory_id) FROM fact_sources src JOIN memories m ON m.id = src.memory_id
+	WHERE src.fact_id = facts.id AND src.memory_id != ? AND m.deleted_at IS NULL
+	  AND src.role IN ` + derivedFactSourceRoles
+
+// repointDerivedFacts moves the primary backlink of summary and merge facts
+// off memoryID onto another live contributing memory, so removing one
+// original does not take a consolidated fact with it.
+func (s *SQLiteStore) repointDerivedFacts(ctx context.Context, memoryID int64) (int64, error) {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE facts SET memory_id = (`+liveDerivedSource+`)
+		 WHERE memory_id = ? AND (`+liveDerivedSource+`) IS NOT NULL`,
+		memoryID, memoryID, memoryID)
+	if err != nil {
+		return 0, fmt.Errorf("repointing facts of memory %d: %w", memoryID, err)
+	}
+	return res.RowsAffected()
+}
+
+// FactSourceRepair reports what RepairFactSources found and fixed.
+type FactSourceRepair struct {
+	LinksAdded int64 `json:"links_added"` // missing fact_sources rows recreated
+	Repointed  int64 `json:"repointed"`   // facts moved off a deleted primary memory
+	Dangling   int   `json:"dangling"`    // active summary/merge facts whose sources are all deleted
+	DryRun     bool  `json:"dry_run,omitempty"`
+}
+
+// RepairFactSources recreates missing fact_sources links from the older
+// provenance records, moves summary and merge facts whose primary memory was
+// soft-deleted onto a live contributing memory, and counts the active
+// summary and merge facts left with no live source. (Facts extracted from a
+// single deleted memory are expected to go with it and are not counted.) dryRun reports the same numbers without
+// changing anything.
+func (s *SQLiteStore) RepairFactSources(ctx context.Context, dryRun bool) (*FactSourceRepair, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("repairing fact sources: %w", err)
+	}
+	defer tx.Rollback()
+
+	r := &FactSourceRepair{DryRun: dryRun}
+	for _, stmt := range factSourceBackfill {
+		res, err := tx.ExecContext(ctx, stmt)
+		if err != nil {
+			return nil, fmt.Errorf("relinking fact sources: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		r.LinksAdded += n
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE facts SET memory_id = (`+liveDerivedSource+`)
+		 WHERE memory_id IN (SELECT id FROM memories WHERE deleted_at IS NOT NULL)
+		   AND (`+liveDerivedSource+`) IS NOT NULL`, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("repointing facts: %w", err)
+	}
+	r.Repointed, _ = res.RowsAffected()
+
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM facts f JOIN memories m ON m.id = f.memory_id
+		 WHERE m.deleted_at IS NOT NULL AND f.superseded_by IS NULL
+		   AND EXISTS (SELECT 1 FROM fact_sources d WHERE d.fact_id = f.id AND d.role IN `+derivedFactSourceRoles+`)
+		   AND NOT EXISTS (SELECT 1 FROM fact_sources src JOIN memories lm ON lm.id = src.memory_id
+		                   WHERE src.fact_id = f.id AND lm.deleted_at IS NULL)`,
+	).Scan(&r.Dangling); err != nil {
+		return nil, fmt.Errorf("counting dangling facts: %w", err)
+	}
+
+	if dryRun {
+		return r, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("repairing fact sources: %w", err)
+	}
+	return r, nil
+}
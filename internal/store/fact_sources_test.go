@@ -0,0 +1,164 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func factSourceRoles(t *testing.T, s *SQLiteStore, factID int64) map[int64]string {
+	t.Helper()
+	sources, err := s.ListFactSources(context.Background(), factID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roles := map[int64]string{}
+	for _, src := range sources {
+		roles[src.MemoryID] = src.Role
+	}
+	return roles
+}
+
+func TestFactSources_SummaryRecordsEveryOriginal(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	m1, _ := s.AddMemory(ctx, &Memory{Content: "deploys use fly.io", SourceFile: "ops.md"})
+	m2, _ := s.AddMemory(ctx, &Memory{Content: "deploy region is iad", SourceFile: "infra.md"})
+	a, _ := s.AddFact(ctx, &Fact{MemoryID: m1, Subject: "deploy", Predicate: "uses", Object: "fly.io", FactType: "kv"})
+	b, _ := s.AddFact(ctx, &Fact{MemoryID: m2, Subject: "deploy", Predicate: "region", Object: "iad", FactType: "kv"})
+	if roles := factSourceRoles(t, s, a); roles[m1] != FactSourceExtracted || len(roles) != 1 {
+		t.Fatalf("extracted fact sources = %v", roles)
+	}
+
+	summaryID, err := s.ApplyClusterSummary(ctx, 3, &Fact{MemoryID: m1, Subject: "deploy", Predicate: "setup", Object: "fly.io in iad", FactType: "kv"}, []int64{a, b}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	roles := factSourceRoles(t, s, summaryID)
+	if len(roles) != 2 || roles[m1] != FactSourceSummarized || roles[m2] != FactSourceSummarized {
+		t.Fatalf("summary sources = %v, want both memories as summarized", roles)
+	}
+
+	// Deleting the memory the summary points at moves it to the other one.
+	if err := s.DeleteMemory(ctx, m1); err != nil {
+		t.Fatal(err)
+	}
+	if f, _ := s.GetFact(ctx, summaryID); f.MemoryID != m2 {
+		t.Fatalf("summary memory_id = %d, want %d after its primary was deleted", f.MemoryID, m2)
+	}
+	// Purging it keeps the summary instead of cascading it away.
+	if _, err := s.DeleteMemoriesBySourceFile(ctx, "ops.md"); err != nil {
+		t.Fatal(err)
+	}
+	if f, _ := s.GetFact(ctx, summaryID); f == nil {
+		t.Fatal("summary fact should survive purging one of its originals")
+	}
+}
+
+func TestFactSources_CorroborationAndDedupMerge(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	m1, _ := s.AddMemory(ctx, &Memory{Content: "alice works at acme", SourceFile: "a.md"})
+	m2, _ := s.AddMemory(ctx, &Memory{Content: "alice works at acme too", SourceFile: "b.md"})
+	m3, _ := s.AddMemory(ctx, &Memory{Content: "alice works at acme corp", SourceFile: "c.md"})
+	id, _ := s.AddFact(ctx, &Fact{MemoryID: m1, Subject: "alice", Predicate: "works at", Object: "acme", FactType: "relationship", Confidence: 0.9})
+	if again, _ := s.AddFact(ctx, &Fact{MemoryID: m2, Subject: "alice", Predicate: "works at", Object: "acme", FactType: "relationship", Confidence: 0.7}); again != id {
+		t.Fatalf("repeat should corroborate fact %d, got %d", id, again)
+	}
+	loser, _ := s.AddFact(ctx, &Fact{MemoryID: m3, Subject: "alice", Predicate: "works at", Object: "acme corp", FactType: "relationship", Confidence: 0.5})
+
+	if _, err := s.DedupFacts(ctx, DedupFactOptions{Threshold: 0.4}); err != nil {
+		t.Fatal(err)
+	}
+	if f, _ := s.GetFact(ctx, loser); f.SupersededBy == nil || *f.SupersededBy != id {
+		t.Fatalf("expected %d merged into %d, got %+v", loser, id, f)
+	}
+	roles := factSourceRoles(t, s, id)
+	if roles[m1] != FactSourceExtracted || roles[m2] != FactSourceCorroborated || roles[m3] != FactSourceMerged {
+		t.Fatalf("sources = %v", roles)
+	}
+}
+
+func TestRepairFactSources(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	m1, _ := s.AddMemory(ctx, &Memory{Content: "deploys use fly.io", SourceFile: "ops.md"})
+	m2, _ := s.AddMemory(ctx, &Memory{Content: "deploy region is iad", SourceFile: "infra.md"})
+	a, _ := s.AddFact(ctx, &Fact{MemoryID: m1, Subject: "deploy", Predicate: "uses", Object: "fly.io", FactType: "kv"})
+	b, _ := s.AddFact(ctx, &Fact{MemoryID: m2, Subject: "deploy", Predicate: "region", Object: "iad", FactType: "kv"})
+	summaryID, _ := s.ApplyClusterSummary(ctx, 3, &Fact{MemoryID: m1, Subject: "deploy", Predicate: "setup", Object: "fly.io in iad", FactType: "kv"}, []int64{a, b}, "")
+
+	// Simulate a store from before fact_sources: links gone, and the
+	// summary's memory soft-deleted without being repointed.
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM fact_sources`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.ExecContext(ctx, `UPDATE memories SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, m1); err != nil {
+		t.Fatal(err)
+	}
+
+	dry, err := s.RepairFactSources(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dry.LinksAdded == 0 || dry.Repointed != 1 {
+		t.Fatalf("dry run = %+v", dry)
+	}
+	if roles := factSourceRoles(t, s, summaryID); len(roles) != 0 {
+		t.Fatalf("dry run must not change anything, got %v", roles)
+	}
+
+	r, err := s.RepairFactSources(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.LinksAdded != dry.LinksAdded || r.Repointed != 1 || r.Dangling != 0 {
+		t.Fatalf("repair = %+v, dry run was %+v", r, dry)
+	}
+	if roles := factSourceRoles(t, s, summaryID); roles[m1] != FactSourceSummarized || roles[m2] != FactSourceSummarized {
+		t.Fatalf("summary sources after repair = %v", roles)
+	}
+	if f, _ := s.GetFact(ctx, summaryID); f.MemoryID != m2 {
+		t.Fatalf("summary memory_id = %d, want %d", f.MemoryID, m2)
+	}
+
+	if again, _ := s.RepairFactSources(ctx, false); again.LinksAdded != 0 || again.Repointed != 0 {
+		t.Fatalf("second repair should be a no-op, got %+v", again)
+	}
+
+	// With every original gone the summary is reported as dangling.
+	if _, err := s.db.ExecContext(ctx, `UPDATE memories SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, m2); err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := s.RepairFactSources(ctx, true); r.Dangling != 1 {
+		t.Fatalf("dangling = %d, want 1", r.Dangling)
+	}
+}
+
+func TestMigrateFactSources_Backfills(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	m1, _ := s.AddMemory(ctx, &Memory{Content: "deploys use fly.io", SourceFile: "ops.md"})
+	m2, _ := s.AddMemory(ctx, &Memory{Content: "deploy region is iad", SourceFile: "infra.md"})
+	a, _ := s.AddFact(ctx, &Fact{MemoryID: m1, Subject: "deploy", Predicate: "uses", Object: "fly.io", FactType: "kv"})
+	b, _ := s.AddFact(ctx, &Fact{MemoryID: m2, Subject: "deploy", Predicate: "region", Object: "iad", FactType: "kv"})
+	summaryID, _ := s.ApplyClusterSummary(ctx, 3, &Fact{MemoryID: m1, Subject: "deploy", Predicate: "setup", Object: "fly.io in iad", FactType: "kv"}, []int64{a, b}, "")
+
+	for _, stmt := range []string{`DROP TABLE fact_sources`, `DELETE FROM meta WHERE key = 'fact_sources_v1'`} {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.migrateFactSources(); err != nil {
+		t.Fatal(err)
+	}
+	if roles := factSourceRoles(t, s, a); roles[m1] != FactSourceExtracted {
+		t.Fatalf("original sources = %v", roles)
+	}
+	if roles := factSourceRoles(t, s, summaryID); len(roles) != 2 || roles[m2] != FactSourceSummarized {
+		t.Fatalf("summary sources after backfill = %v", roles)
+	}
+}
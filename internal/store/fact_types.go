@@ -0,0 +1,109 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hurttlocker/cortex/internal/config"
+)
+
+var (
+	customFactTypesMu sync.RWMutex
+	customFactTypes   = map[string]string{} // custom type → built-in base type
+)
+
+// IsBuiltinFactType reports whether t is allowed by the facts.fact_type
+// CHECK constraint.
+func IsBuiltinFactType(t string) bool {
+	for _, b := range config.BuiltinFactTypes {
+		if t == b {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCustomFactTypes registers user-defined fact types (from the config
+// taxonomy), each mapped to the built-in type it refines. Facts given a
+// custom type keep the base in fact_type (so decay rates and the CHECK
+// constraint still apply) and record the custom name in custom_type.
+// Entries naming a built-in type or an unknown base are ignored.
+func SetCustomFactTypes(types map[string]string) {
+	next := make(map[string]string, len(types))
+	for name, base := range types {
+		name = normalizeFactTypeName(name)
+		base = normalizeFactTypeName(base)
+		if base == "" {
+			base = "kv"
+		}
+		if name == "" || IsBuiltinFactType(name) || !IsBuiltinFactType(base) {
+			continue
+		}
+		next[name] = base
+	}
+	customFactTypesMu.Lock()
+	customFactTypes = next
+	customFactTypesMu.Unlock()
+}
+
+// CustomFactTypes returns the registered custom types, sorted.
+func CustomFactTypes() []string {
+	customFactTypesMu.RLock()
+	defer customFactTypesMu.RUnlock()
+	out := make([]string, 0, len(customFactTypes))
+	for name := range customFactTypes {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ResolveFactType maps a built-in or registered custom type to the values
+// stored in fact_type and custom_type.
+func ResolveFactType(t string) (base, custom string, err error) {
+	t = normalizeFactTypeName(t)
+	if IsBuiltinFactType(t) {
+		return t, "", nil
+	}
+	customFactTypesMu.RLock()
+	base, ok := customFactTypes[t]
+	customFactTypesMu.RUnlock()
+	if !ok {
+		return "", "", fmt.Errorf("unknown fact type %q (valid: %s)", t, strings.Join(append(append([]string{}, config.BuiltinFactTypes...), CustomFactTypes()...), ", "))
+	}
+	return base, t, nil
+}
+
+// factTypeFilter returns a WHERE fragment matching facts whose effective
+// type (custom_type when set, else fact_type) is t.
+func factTypeFilter(alias, t string) (string, []interface{}) {
+	t = normalizeFactTypeName(t)
+	if IsBuiltinFactType(t) {
+		return fmt.Sprintf("%[1]s.fact_type = ? AND %[1]s.custom_type = ''", alias), []interface{}{t}
+	}
+	return alias + ".custom_type = ?", []interface{}{t}
+}
+
+func normalizeFactTypeName(t string) string {
+	return strings.ToLower(strings.TrimSpace(t))
+}
+
+func (s *SQLiteStore) migrateFactCustomTypeColumn() error {
+	var count int
+	if err := s.db.QueryRow(
+		"SELECT COUNT(*) FROM pragma_table_info('facts') WHERE name='custom_type'",
+	).Scan(&count); err != nil {
+		return fmt.Errorf("checking for custom_type column: %w", err)
+	}
+	if count == 0 {
+		if _, err := s.db.Exec(`ALTER TABLE facts ADD COLUMN custom_type TEXT NOT NULL DEFAULT ''`); err != nil && !isDuplicateColumnError(err) {
+			return fmt.Errorf("adding custom_type column: %w", err)
+		}
+	}
+	if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_facts_custom_type ON facts(custom_type) WHERE custom_type != ''`); err != nil {
+		return fmt.Errorf("creating custom_type index: %w", err)
+	}
+	return nil
+}
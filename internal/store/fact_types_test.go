@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func useCustomFactTypes(t *testing.T, types map[string]string) {
+	t.Helper()
+	SetCustomFactTypes(types)
+	t.Cleanup(func() { SetCustomFactTypes(nil) })
+}
+
+func TestUpdateFactType_CustomTypes(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+	useCustomFactTypes(t, map[string]string{"Medication": "state", "recipe": "", "kv": "state", "bogus": "drugs"})
+
+	if got := CustomFactTypes(); len(got) != 2 || got[0] != "medication" || got[1] != "recipe" {
+		t.Fatalf("CustomFactTypes = %v", got)
+	}
+
+	memID, err := s.AddMemory(ctx, &Memory{Content: "dose notes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dose, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Predicate: "dose", Object: "5mg", FactType: "kv"})
+	s.AddFact(ctx, &Fact{MemoryID: memID, Predicate: "port", Object: "8090", FactType: "kv"})
+
+	if err := s.UpdateFactType(ctx, dose, "medication"); err != nil {
+		t.Fatalf("UpdateFactType(custom): %v", err)
+	}
+	if err := s.UpdateFactType(ctx, dose, "bogus"); err == nil || !strings.Contains(err.Error(), "medication") {
+		t.Fatalf("expected unknown type error listing valid types, got %v", err)
+	}
+
+	f, err := s.GetFact(ctx, dose)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.FactType != "state" || f.CustomType != "medication" {
+		t.Fatalf("expected state/medication, got %s/%s", f.FactType, f.CustomType)
+	}
+
+	byType, err := s.GetFactsByType(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byType["medication"] != 1 || byType["kv"] != 1 || byType["state"] != 0 {
+		t.Fatalf("stats should count the custom type, got %v", byType)
+	}
+
+	if facts, _ := s.ListFacts(ctx, ListOpts{FactType: "medication"}); len(facts) != 1 || facts[0].ID != dose {
+		t.Fatalf("expected the medication fact, got %d facts", len(facts))
+	}
+	if facts, _ := s.ListFacts(ctx, ListOpts{FactType: "state"}); len(facts) != 0 {
+		t.Fatalf("built-in filter should exclude custom-typed facts, got %d", len(facts))
+	}
+
+	if err := s.UpdateFactType(ctx, dose, "kv"); err != nil {
+		t.Fatal(err)
+	}
+	if f, _ := s.GetFact(ctx, dose); f.FactType != "kv" || f.CustomType != "" {
+		t.Fatalf("resetting to a built-in should clear custom_type, got %s/%s", f.FactType, f.CustomType)
+	}
+}
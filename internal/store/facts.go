@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hurttlocker/cortex/internal/extract"
 	"github.com/hurttlocker/cortex/internal/temporal"
 )
 
@@ -118,6 +119,18 @@ func (s *SQLiteStore) AddFact(ctx context.Context, f *Fact) (int64, error) {
 		f.DecayRate = 0.01
 	}
 	normalizeFactScopeForWrite(f)
+	if !f.Negated {
+		f.Negated = extract.IsNegated(f.Predicate, f.Object)
+	}
+	f.Predicate = CanonicalPredicate(f.Predicate)
+	// A fact another source already stated raises that fact's confidence
+	// instead of being stored twice.
+	if id, err := s.corroborateFact(ctx, f); err != nil {
+		return 0, err
+	} else if id > 0 {
+		f.ID = id
+		return id, nil
+	}
 	if err := s.resolveEntityForFact(ctx, f); err != nil {
 		return 0, err
 	}
@@ -128,10 +141,10 @@ func (s *SQLiteStore) AddFact(ctx context.Context, f *Fact) (int64, error) {
 	}
 
 	result, err := s.db.ExecContext(ctx,
-		`INSERT INTO facts (memory_id, entity_id, subject, predicate, object, fact_type, confidence, decay_rate, last_reinforced, source_quote, temporal_norm, created_at, state, agent_id, observer_agent, observed_entity, session_id, project_id, token_estimate)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO facts (memory_id, entity_id, subject, predicate, object, fact_type, confidence, decay_rate, last_reinforced, source_quote, temporal_norm, created_at, state, agent_id, observer_agent, observed_entity, session_id, project_id, token_estimate, negated)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		f.MemoryID, nullableInt64Value(f.EntityID), f.Subject, f.Predicate, f.Object, f.FactType,
-		f.Confidence, f.DecayRate, now, f.SourceQuote, marshalTemporalNorm(f.TemporalNorm), now, state, f.AgentID, f.ObserverAgent, f.ObservedEntity, f.SessionID, f.ProjectID, f.TokenEstimate,
+		f.Confidence, f.DecayRate, now, f.SourceQuote, marshalTemporalNorm(f.TemporalNorm), now, state, f.AgentID, f.ObserverAgent, f.ObservedEntity, f.SessionID, f.ProjectID, f.TokenEstimate, f.Negated,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("inserting fact: %w", err)
@@ -146,6 +159,9 @@ func (s *SQLiteStore) AddFact(ctx context.Context, f *Fact) (int64, error) {
 	f.CreatedAt = now
 	f.LastReinforced = now
 	f.State = state
+	if err := s.AddFactSources(ctx, id, []int64{f.MemoryID}, FactSourceExtracted); err != nil {
+		return id, err
+	}
 	f.ObserverAgent = effectiveFactObserver(f)
 	if unresolved := unresolvedEntityForFact(f); unresolved != nil {
 		unresolved.FactID = id
@@ -158,6 +174,7 @@ func (s *SQLiteStore) AddFact(ctx context.Context, f *Fact) (int64, error) {
 			return id, fmt.Errorf("rebuilding entity profile for fact %d: %w", id, err)
 		}
 	}
+	s.notifySubscribers(ctx, SubscriptionEventAdded, f, nil)
 	return id, nil
 }
 
@@ -168,11 +185,11 @@ func (s *SQLiteStore) GetFact(ctx context.Context, id int64) (*Fact, error) {
 	var entityID sql.NullInt64
 	var temporalNorm sql.NullString
 	err := s.db.QueryRowContext(ctx,
-		`SELECT id, memory_id, entity_id, subject, predicate, object, fact_type, confidence, decay_rate, last_reinforced, source_quote, temporal_norm, created_at, state, superseded_by, agent_id, observer_agent, observed_entity, session_id, project_id, token_estimate
+		`SELECT id, memory_id, entity_id, subject, predicate, object, fact_type, confidence, decay_rate, last_reinforced, source_quote, temporal_norm, created_at, state, superseded_by, agent_id, observer_agent, observed_entity, session_id, project_id, token_estimate, custom_type, negated
 		 FROM facts WHERE id = ?`, id,
 	).Scan(&f.ID, &f.MemoryID, &entityID, &f.Subject, &f.Predicate, &f.Object,
 		&f.FactType, &f.Confidence, &f.DecayRate, &f.LastReinforced,
-		&f.SourceQuote, &temporalNorm, &f.CreatedAt, &f.State, &supersededBy, &f.AgentID, &f.ObserverAgent, &f.ObservedEntity, &f.SessionID, &f.ProjectID, &f.TokenEstimate)
+		&f.SourceQuote, &temporalNorm, &f.CreatedAt, &f.State, &supersededBy, &f.AgentID, &f.ObserverAgent, &f.ObservedEntity, &f.SessionID, &f.ProjectID, &f.TokenEstimate, &f.CustomType, &f.Negated)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -198,38 +215,28 @@ func (s *SQLiteStore) ListFacts(ctx context.Context, opts ListOpts) ([]*Fact, er
 		opts.Limit = 100
 	}
 
-	stateFilter, err := normalizeFactStateFilter(opts.State)
+	joins, where, args, err := s.factListFilter(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
-
 	query := `SELECT f.id, f.memory_id, f.entity_id, f.subject, f.predicate, f.object, f.fact_type, 
-			         f.confidence, f.decay_rate, f.last_reinforced, f.source_quote, f.temporal_norm, f.created_at, f.state, f.superseded_by, f.agent_id, f.observer_agent, f.observed_entity, f.session_id, f.project_id, f.token_estimate
-		      FROM facts f`
-	args := []interface{}{}
+			         f.confidence, f.decay_rate, f.last_reinforced, f.source_quote, f.temporal_norm, f.created_at, f.state, f.superseded_by, f.agent_id, f.observer_agent, f.observed_entity, f.session_id, f.project_id, f.token_estimate, f.custom_type, f.negated
+		      FROM facts f` + joins
 
-	// Build WHERE clause
-	var where []string
-	if opts.FactType != "" {
-		where = append(where, "f.fact_type = ?")
-		args = append(args, opts.FactType)
-	}
-	if !opts.IncludeSuperseded {
-		where = append(where, "f.superseded_by IS NULL")
-	}
-	if stateFilter != "" {
-		where = append(where, "LOWER(f.state) = ?")
-		args = append(args, stateFilter)
-	}
-	if opts.Agent != "" {
-		// Show agent-specific facts + global facts (empty agent_id)
-		where = append(where, "(f.agent_id = ? OR f.agent_id = '')")
-		args = append(args, opts.Agent)
+	sortColumn := "created_at"
+	if opts.SortBy == "confidence" {
+		sortColumn = "confidence"
 	}
-	if opts.SourceFile != "" {
-		query += " JOIN memories m ON f.memory_id = m.id"
-		where = append(where, "m.source_file = ?")
-		args = append(args, opts.SourceFile)
+	if opts.Cursor != "" {
+		afterID, err := DecodeCursor(opts.Cursor, factsOrdering(opts.SortBy))
+		if err != nil {
+			return nil, err
+		}
+		if err := s.cursorRowExists(ctx, "facts", afterID); err != nil {
+			return nil, err
+		}
+		where = append(where, fmt.Sprintf("(f.%[1]s, f.id) < (SELECT %[1]s, id FROM facts WHERE id = ?)", sortColumn))
+		args = append(args, afterID)
 	}
 
 	if len(where) > 0 {
@@ -239,10 +246,8 @@ func (s *SQLiteStore) ListFacts(ctx context.Context, opts ListOpts) ([]*Fact, er
 		}
 	}
 
-	orderBy := "f.created_at DESC"
-	if opts.SortBy == "confidence" {
-		orderBy = "f.confidence DESC"
-	}
+	// id breaks ties so keyset cursors see a total order.
+	orderBy := fmt.Sprintf("f.%s DESC, f.id DESC", sortColumn)
 	query += fmt.Sprintf(" ORDER BY %s LIMIT ? OFFSET ?", orderBy)
 	args = append(args, opts.Limit, opts.Offset)
 
@@ -260,7 +265,7 @@ func (s *SQLiteStore) ListFacts(ctx context.Context, opts ListOpts) ([]*Fact, er
 		var temporalNorm sql.NullString
 		if err := rows.Scan(&f.ID, &f.MemoryID, &entityID, &f.Subject, &f.Predicate, &f.Object,
 			&f.FactType, &f.Confidence, &f.DecayRate, &f.LastReinforced,
-			&f.SourceQuote, &temporalNorm, &f.CreatedAt, &f.State, &supersededBy, &f.AgentID, &f.ObserverAgent, &f.ObservedEntity, &f.SessionID, &f.ProjectID, &f.TokenEstimate); err != nil {
+			&f.SourceQuote, &temporalNorm, &f.CreatedAt, &f.State, &supersededBy, &f.AgentID, &f.ObserverAgent, &f.ObservedEntity, &f.SessionID, &f.ProjectID, &f.TokenEstimate, &f.CustomType, &f.Negated); err != nil {
 			return nil, fmt.Errorf("scanning fact row: %w", err)
 		}
 		if supersededBy.Valid {
@@ -277,6 +282,87 @@ func (s *SQLiteStore) ListFacts(ctx context.Context, opts ListOpts) ([]*Fact, er
 	return facts, rows.Err()
 }
 
+// CountFacts returns how many facts match opts' filters, ignoring Limit,
+// Offset, and Cursor. Used for totals alongside a page.
+func (s *SQLiteStore) CountFacts(ctx context.Context, opts ListOpts) (int, error) {
+	joins, where, args, err := s.factListFilter(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+	query := "SELECT COUNT(*) FROM facts f" + joins
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	var n int
+	if err := s.db.QueryRowContext(ctx, query, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting facts: %w", err)
+	}
+	return n, nil
+}
+
+// factListFilter builds the joins and WHERE clauses shared by ListFacts and
+// CountFacts.
+func (s *SQLiteStore) factListFilter(ctx context.Context, opts ListOpts) (string, []string, []interface{}, error) {
+	stateFilter, err := normalizeFactStateFilter(opts.State)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	joins := ""
+	args := []interface{}{}
+	var where []string
+	if opts.FactType != "" {
+		clause, clauseArgs := factTypeFilter("f", opts.FactType)
+		where = append(where, clause)
+		args = append(args, clauseArgs...)
+	}
+	if !opts.IncludeSuperseded {
+		where = append(where, "f.superseded_by IS NULL")
+	}
+	if stateFilter != "" {
+		where = append(where, "LOWER(f.state) = ?")
+		args = append(args, stateFilter)
+	}
+	if opts.Agent != "" {
+		// Show agent-specific facts + global facts (empty agent_id)
+		where = append(where, "(f.agent_id = ? OR f.agent_id = '')")
+		args = append(args, opts.Agent)
+	}
+	if opts.After != "" {
+		where = append(where, "f.created_at >= ?")
+		args = append(args, opts.After)
+	}
+	nsClause, nsArgs := s.namespaceFilter(ctx, "m.namespace")
+	if opts.SourceFile != "" || opts.Project != "" || len(opts.MemoryClasses) > 0 || nsClause != "" {
+		joins += " JOIN memories m ON f.memory_id = m.id"
+	}
+	if opts.SourceFile != "" {
+		where = append(where, "m.source_file = ?")
+		args = append(args, opts.SourceFile)
+	}
+	if opts.Project != "" {
+		where = append(where, "m.project = ?")
+		args = append(args, opts.Project)
+	}
+	if len(opts.MemoryClasses) > 0 {
+		var placeholders []string
+		for _, c := range opts.MemoryClasses {
+			normalized := NormalizeMemoryClass(c)
+			if !IsValidMemoryClass(normalized) {
+				return "", nil, nil, fmt.Errorf("invalid memory class %q", c)
+			}
+			placeholders = append(placeholders, "?")
+			args = append(args, normalized)
+		}
+		where = append(where, "m.memory_class IN ("+strings.Join(placeholders, ",")+")")
+	}
+	if nsClause != "" {
+		where = append(where, strings.TrimPrefix(nsClause, " AND "))
+		args = append(args, nsArgs...)
+	}
+	return joins, where, args, nil
+}
+
 // ListFactsByMemoryIDs returns facts for specific memory IDs, optionally filtered by type.
 func (s *SQLiteStore) ListFactsByMemoryIDs(ctx context.Context, memoryIDs []int64, factType string, limit int) ([]*Fact, error) {
 	if len(memoryIDs) == 0 {
@@ -294,14 +380,15 @@ func (s *SQLiteStore) ListFactsByMemoryIDs(ctx context.Context, memoryIDs []int6
 	}
 
 	query := fmt.Sprintf(`SELECT f.id, f.memory_id, f.entity_id, f.subject, f.predicate, f.object, f.fact_type,
-		f.confidence, f.decay_rate, f.last_reinforced, f.source_quote, f.temporal_norm, f.created_at, f.state, f.superseded_by, f.agent_id, f.observer_agent, f.observed_entity, f.session_id, f.project_id, f.token_estimate
+		f.confidence, f.decay_rate, f.last_reinforced, f.source_quote, f.temporal_norm, f.created_at, f.state, f.superseded_by, f.agent_id, f.observer_agent, f.observed_entity, f.session_id, f.project_id, f.token_estimate, f.custom_type, f.negated
 		FROM facts f
 		WHERE f.memory_id IN (%s) AND f.superseded_by IS NULL`,
 		strings.Join(placeholders, ","))
 
 	if factType != "" {
-		query += " AND f.fact_type = ?"
-		args = append(args, factType)
+		clause, clauseArgs := factTypeFilter("f", factType)
+		query += " AND " + clause
+		args = append(args, clauseArgs...)
 	}
 
 	query += fmt.Sprintf(" ORDER BY f.created_at DESC LIMIT %d", limit)
@@ -320,7 +407,7 @@ func (s *SQLiteStore) ListFactsByMemoryIDs(ctx context.Context, memoryIDs []int6
 		var temporalNorm sql.NullString
 		if err := rows.Scan(&f.ID, &f.MemoryID, &entityID, &f.Subject, &f.Predicate, &f.Object,
 			&f.FactType, &f.Confidence, &f.DecayRate, &f.LastReinforced,
-			&f.SourceQuote, &temporalNorm, &f.CreatedAt, &f.State, &supersededBy, &f.AgentID, &f.ObserverAgent, &f.ObservedEntity, &f.SessionID, &f.ProjectID, &f.TokenEstimate); err != nil {
+			&f.SourceQuote, &temporalNorm, &f.CreatedAt, &f.State, &supersededBy, &f.AgentID, &f.ObserverAgent, &f.ObservedEntity, &f.SessionID, &f.ProjectID, &f.TokenEstimate, &f.CustomType, &f.Negated); err != nil {
 			return nil, fmt.Errorf("scanning fact row: %w", err)
 		}
 		if supersededBy.Valid {
@@ -356,10 +443,16 @@ func (s *SQLiteStore) UpdateFactConfidence(ctx context.Context, id int64, confid
 	return nil
 }
 
-// UpdateFactType changes the fact_type of a fact. Used by `cortex classify`.
+// UpdateFactType changes the type of a fact. Used by `cortex classify`.
+// factType may be built-in or a custom type registered with
+// SetCustomFactTypes; anything else is rejected.
 func (s *SQLiteStore) UpdateFactType(ctx context.Context, id int64, factType string) error {
+	base, custom, err := ResolveFactType(factType)
+	if err != nil {
+		return err
+	}
 	result, err := s.db.ExecContext(ctx,
-		"UPDATE facts SET fact_type = ? WHERE id = ?", factType, id,
+		"UPDATE facts SET fact_type = ?, custom_type = ? WHERE id = ?", base, custom, id,
 	)
 	if err != nil {
 		return fmt.Errorf("updating fact type: %w", err)
@@ -388,6 +481,9 @@ func (s *SQLiteStore) UpdateFactState(ctx context.Context, id int64, state strin
 		norm, id,
 	)
 	if err != nil {
+		if herr := archiveHoldError(err, HoldKindFact, id); herr != err {
+			return herr
+		}
 		return fmt.Errorf("updating fact state: %w", err)
 	}
 
@@ -424,6 +520,12 @@ func (s *SQLiteStore) ReinforceFact(ctx context.Context, id int64) error {
 // SupersedeFact marks oldFactID as superseded by newFactID.
 // The old fact is preserved for audit history but excluded from active retrieval by default.
 func (s *SQLiteStore) SupersedeFact(ctx context.Context, oldFactID, newFactID int64, reason string) error {
+	return s.SupersedeFactWithSource(ctx, oldFactID, newFactID, reason, "supersede")
+}
+
+// SupersedeFactWithSource is SupersedeFact with the audit event attributed
+// to source (e.g. "mcp:supersede_fact:<agent>").
+func (s *SQLiteStore) SupersedeFactWithSource(ctx context.Context, oldFactID, newFactID int64, reason, source string) error {
 	if oldFactID <= 0 || newFactID <= 0 {
 		return fmt.Errorf("old and new fact IDs must be > 0")
 	}
@@ -462,7 +564,7 @@ func (s *SQLiteStore) SupersedeFact(ctx context.Context, oldFactID, newFactID in
 		FactID:    oldFactID,
 		OldValue:  fmt.Sprintf("active fact:%d", oldFactID),
 		NewValue:  fmt.Sprintf("superseded_by:%d reason:%s", newFactID, reason),
-		Source:    "supersede",
+		Source:    source,
 	})
 
 	// Auto-create 'supersedes' edge in knowledge graph
@@ -474,9 +576,49 @@ func (s *SQLiteStore) SupersedeFact(ctx context.Context, oldFactID, newFactID in
 		Source:       EdgeSourceDetected,
 	})
 
+	s.notifySubscribers(ctx, SubscriptionEventSuperseded, oldFact, newFact)
 	return nil
 }
 
+// RestoreSupersededFact reverts SupersedeFact: if oldFactID is still
+// superseded by newFactID it becomes live again with the given confidence
+// and state, and the supersedes edge is dropped. It reports whether the fact
+// was restored; a fact superseded by something else since is left alone.
+func (s *SQLiteStore) RestoreSupersededFact(ctx context.Context, oldFactID, newFactID int64, confidence float64, state string) (bool, error) {
+	if state == FactStateSuperseded {
+		state = FactStateActive
+	}
+	norm, err := normalizeFactStateForWrite(state)
+	if err != nil {
+		return false, err
+	}
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE facts SET superseded_by = NULL, confidence = ?, state = ? WHERE id = ? AND superseded_by = ?`,
+		confidence, norm, oldFactID, newFactID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("restoring fact %d: %w", oldFactID, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, nil
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM fact_edges_v1 WHERE source_fact_id = ? AND target_fact_id = ? AND edge_type = ?`,
+		newFactID, oldFactID, string(EdgeTypeSupersedes),
+	); err != nil {
+		return true, fmt.Errorf("removing supersedes edge %d → %d: %w", newFactID, oldFactID, err)
+	}
+
+	_ = s.LogEvent(ctx, &MemoryEvent{
+		EventType: "update",
+		FactID:    oldFactID,
+		OldValue:  fmt.Sprintf("superseded_by:%d", newFactID),
+		NewValue:  fmt.Sprintf("restored fact:%d", oldFactID),
+		Source:    "supersede:undo",
+	})
+	return true, nil
+}
+
 // GetFactsByMemoryIDs retrieves active (non-superseded) facts linked to the given memory IDs.
 func (s *SQLiteStore) GetFactsByMemoryIDs(ctx context.Context, memoryIDs []int64) ([]*Fact, error) {
 	return s.getFactsByMemoryIDs(ctx, memoryIDs, false)
@@ -542,8 +684,13 @@ func (s *SQLiteStore) getFactsByMemoryIDs(ctx context.Context, memoryIDs []int64
 
 // DeleteFactsByIDs removes a specific set of facts and their dependent rows.
 // It is intentionally scoped to SQLiteStore because it is used by maintenance
-// paths that need direct, destructive cleanup.
+// paths that need direct, destructive cleanup. Facts under archive hold are
+// skipped.
 func (s *SQLiteStore) DeleteFactsByIDs(ctx context.Context, factIDs []int64) (int64, error) {
+	factIDs, err := s.withoutHeldFacts(ctx, factIDs)
+	if err != nil {
+		return 0, fmt.Errorf("checking archive holds: %w", err)
+	}
 	if len(factIDs) == 0 {
 		return 0, nil
 	}
@@ -594,9 +741,13 @@ func (s *SQLiteStore) DeleteFactsByIDs(ctx context.Context, factIDs []int64) (in
 	return deleted, nil
 }
 
-// DeleteFactsByMemoryID removes all facts linked to a memory.
-// Returns number of rows deleted.
+// DeleteFactsByMemoryID removes all facts linked to a memory, except those
+// under archive hold. Returns number of rows deleted.
 func (s *SQLiteStore) DeleteFactsByMemoryID(ctx context.Context, memoryID int64) (int64, error) {
+	held, err := s.HeldFactIDs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("checking archive holds: %w", err)
+	}
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, fmt.Errorf("begin fact deletion tx for memory %d: %w", memoryID, err)
@@ -615,7 +766,9 @@ func (s *SQLiteStore) DeleteFactsByMemoryID(ctx context.Context, memoryID int64)
 		if err := rows.Scan(&id); err != nil {
 			return 0, fmt.Errorf("scanning fact id for memory %d: %w", memoryID, err)
 		}
-		factIDs = append(factIDs, id)
+		if !held[id] {
+			factIDs = append(factIDs, id)
+		}
 	}
 	if err := rows.Err(); err != nil {
 		return 0, fmt.Errorf("iterating facts for memory %d: %w", memoryID, err)
@@ -652,7 +805,7 @@ func (s *SQLiteStore) DeleteFactsByMemoryID(ctx context.Context, memoryID int64)
 		}
 	}
 
-	result, err := tx.ExecContext(ctx, `DELETE FROM facts WHERE memory_id = ?`, memoryID)
+	result, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM facts WHERE id IN (%s)`, inClause), args...)
 	if err != nil {
 		return 0, fmt.Errorf("deleting facts for memory %d: %w", memoryID, err)
 	}
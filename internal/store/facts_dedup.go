@@ -135,6 +135,9 @@ func (s *SQLiteStore) DedupFacts(ctx context.Context, opts DedupFactOptions) (*D
 		if err := s.SupersedeFact(ctx, m.LoserID, m.WinnerID, reason); err != nil {
 			return nil, fmt.Errorf("superseding loser fact %d with winner %d: %w", m.LoserID, m.WinnerID, err)
 		}
+		if err := s.inheritFactSources(ctx, m.WinnerID, []int64{m.LoserID}, FactSourceMerged); err != nil {
+			return nil, err
+		}
 	}
 
 	return report, nil
@@ -0,0 +1,312 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultSemanticDedupThreshold is the cosine similarity at which two facts
+// about the same subject are treated as paraphrases of each other.
+const DefaultSemanticDedupThreshold = 0.88
+
+// maxSemanticDedupConfidence caps the combined confidence of a merged fact so
+// repeated paraphrases never claim certainty.
+const maxSemanticDedupConfidence = 0.99
+
+// FactEmbedFunc embeds a batch of texts, returning vectors in input order.
+type FactEmbedFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// SemanticDedupOptions controls embedding-based fact deduplication.
+type SemanticDedupOptions struct {
+	Agent        string  `json:"agent,omitempty"`
+	Threshold    float64 `json:"threshold"` // cosine similarity, 0..1
+	DryRun       bool    `json:"dry_run"`
+	MaxPreview   int     `json:"max_preview"`
+	MaxGroupSize int     `json:"max_group_size"` // subjects with more active facts are skipped
+}
+
+// SemanticDedupMember is one fact folded into a cluster's canonical fact.
+type SemanticDedupMember struct {
+	ID         int64   `json:"id"`
+	Predicate  string  `json:"predicate"`
+	Object     string  `json:"object"`
+	Confidence float64 `json:"confidence"`
+	Similarity float64 `json:"similarity"` // to the canonical fact
+}
+
+// SemanticDedupCluster is a set of paraphrased facts and the one kept.
+type SemanticDedupCluster struct {
+	Subject             string                `json:"subject"`
+	CanonicalID         int64                 `json:"canonical_id"`
+	CanonicalPredicate  string                `json:"canonical_predicate"`
+	CanonicalObject     string                `json:"canonical_object"`
+	CanonicalConfidence float64               `json:"canonical_confidence"`
+	CombinedConfidence  float64               `json:"combined_confidence"`
+	Merged              []SemanticDedupMember `json:"merged"`
+}
+
+// SemanticDedupReport summarizes a semantic dedup pass.
+type SemanticDedupReport struct {
+	SubjectsScanned int                    `json:"subjects_scanned"`
+	SubjectsSkipped int                    `json:"subjects_skipped,omitempty"`
+	FactsEmbedded   int                    `json:"facts_embedded"`
+	PairsCompared   int                    `json:"pairs_compared"`
+	Clusters        int                    `json:"clusters"`
+	Merged          int                    `json:"merged"`
+	Preview         []SemanticDedupCluster `json:"preview,omitempty"`
+}
+
+type semanticDedupRow struct {
+	dedupFactRow
+	vec []float32
+}
+
+// SemanticDedupFacts finds facts about the same subject that say the same
+// thing in different words ("prefers dark mode" vs "likes dark themes") by
+// comparing embeddings of their triples. Each cluster keeps its best-phrased
+// fact — the one closest to the rest of the cluster, then the most confident —
+// raises it to the combined confidence of the cluster, and supersedes the
+// others. DedupFacts catches spelling-level duplicates; this catches meaning.
+func (s *SQLiteStore) SemanticDedupFacts(ctx context.Context, opts SemanticDedupOptions, embedFn FactEmbedFunc) (*SemanticDedupReport, error) {
+	if embedFn == nil {
+		return nil, fmt.Errorf("semantic dedup requires an embedder")
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultSemanticDedupThreshold
+	}
+	if opts.Threshold > 1 {
+		opts.Threshold = 1
+	}
+	if opts.MaxPreview <= 0 {
+		opts.MaxPreview = 25
+	}
+	if opts.MaxGroupSize <= 0 {
+		opts.MaxGroupSize = 500
+	}
+
+	query := `
+		SELECT id, subject, predicate, object, confidence, COALESCE(last_reinforced, '')
+		FROM facts
+		WHERE superseded_by IS NULL AND TRIM(subject) != ''
+	`
+	args := []interface{}{}
+	if strings.TrimSpace(opts.Agent) != "" {
+		query += ` AND agent_id = ?`
+		args = append(args, strings.TrimSpace(opts.Agent))
+	}
+	query += `
+		ORDER BY LOWER(TRIM(subject)), confidence DESC, last_reinforced DESC, id ASC
+	`
+
+	rows, err := s.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying facts for semantic dedup: %w", err)
+	}
+	groups := map[string][]dedupFactRow{}
+	groupOrder := []string{}
+	for rows.Next() {
+		var r dedupFactRow
+		if err := rows.Scan(&r.ID, &r.Subject, &r.Predicate, &r.Object, &r.Confidence, &r.LastReinforced); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning fact row: %w", err)
+		}
+		key := normalizeFactObject(r.Subject)
+		if _, ok := groups[key]; !ok {
+			groupOrder = append(groupOrder, key)
+		}
+		groups[key] = append(groups[key], r)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterating fact rows: %w", err)
+	}
+	rows.Close()
+
+	report := &SemanticDedupReport{SubjectsScanned: len(groupOrder)}
+	var clusters []SemanticDedupCluster
+
+	for _, key := range groupOrder {
+		facts := groups[key]
+		if len(facts) < 2 {
+			continue
+		}
+		if len(facts) > opts.MaxGroupSize {
+			report.SubjectsSkipped++
+			continue
+		}
+
+		texts := make([]string, len(facts))
+		for i, f := range facts {
+			texts[i] = semanticFactText(f)
+		}
+		vecs, err := embedFn(ctx, texts)
+		if err != nil {
+			return nil, fmt.Errorf("embedding facts for subject %q: %w", facts[0].Subject, err)
+		}
+		if len(vecs) != len(facts) {
+			return nil, fmt.Errorf("embedder returned %d vectors for %d facts", len(vecs), len(facts))
+		}
+		report.FactsEmbedded += len(facts)
+
+		candidates := make([]semanticDedupRow, len(facts))
+		for i := range facts {
+			candidates[i] = semanticDedupRow{dedupFactRow: facts[i], vec: vecs[i]}
+		}
+		for _, group := range clusterSemanticFacts(candidates, opts.Threshold, &report.PairsCompared) {
+			clusters = append(clusters, buildSemanticDedupCluster(group))
+		}
+	}
+
+	report.Clusters = len(clusters)
+	for _, c := range clusters {
+		report.Merged += len(c.Merged)
+	}
+	if len(clusters) > opts.MaxPreview {
+		report.Preview = append(report.Preview, clusters[:opts.MaxPreview]...)
+	} else {
+		report.Preview = append(report.Preview, clusters...)
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for _, c := range clusters {
+		if c.CombinedConfidence > c.CanonicalConfidence {
+			if err := s.UpdateFactConfidence(ctx, c.CanonicalID, c.CombinedConfidence); err != nil {
+				return nil, fmt.Errorf("raising confidence of canonical fact %d: %w", c.CanonicalID, err)
+			}
+		}
+		for _, m := range c.Merged {
+			reason := fmt.Sprintf("semantic-dedup similarity=%.3f", m.Similarity)
+			if err := s.SupersedeFact(ctx, m.ID, c.CanonicalID, reason); err != nil {
+				return nil, fmt.Errorf("superseding fact %d with canonical %d: %w", m.ID, c.CanonicalID, err)
+			}
+			if err := s.inheritFactSources(ctx, c.CanonicalID, []int64{m.ID}, FactSourceMerged); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// semanticFactText renders a fact triple as the phrase that gets embedded.
+func semanticFactText(f dedupFactRow) string {
+	predicate := strings.NewReplacer("_", " ", "-", " ").Replace(f.Predicate)
+	return strings.Join(strings.Fields(f.Subject+" "+predicate+" "+f.Object), " ")
+}
+
+// clusterSemanticFacts groups facts (already ordered most-confident first)
+// around leaders: each fact joins the most similar existing cluster whose
+// leader is at or above threshold, otherwise it starts a new one. Comparing
+// against leaders rather than any member keeps chains of loosely related
+// facts from collapsing into one cluster. Only clusters of two or more are
+// returned.
+func clusterSemanticFacts(facts []semanticDedupRow, threshold float64, pairs *int) [][]semanticDedupRow {
+	var groups [][]semanticDedupRow
+	for _, f := range facts {
+		best, bestSim := -1, 0.0
+		for i, g := range groups {
+			sim := cosineSimilarity(g[0].vec, f.vec)
+			*pairs++
+			if sim >= threshold && sim > bestSim {
+				best, bestSim = i, sim
+			}
+		}
+		if best == -1 {
+			groups = append(groups, []semanticDedupRow{f})
+			continue
+		}
+		groups[best] = append(groups[best], f)
+	}
+
+	out := groups[:0]
+	for _, g := range groups {
+		if len(g) > 1 {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// buildSemanticDedupCluster picks the canonical fact of a cluster and
+// combines the members' confidence as independent evidence (noisy-OR).
+func buildSemanticDedupCluster(group []semanticDedupRow) SemanticDedupCluster {
+	centrality := make([]float64, len(group))
+	for i := range group {
+		for j := range group {
+			if i != j {
+				centrality[i] += cosineSimilarity(group[i].vec, group[j].vec)
+			}
+		}
+	}
+	canonical := 0
+	for i := 1; i < len(group); i++ {
+		if betterSemanticCanonical(group[i], centrality[i], group[canonical], centrality[canonical]) {
+			canonical = i
+		}
+	}
+
+	winner := group[canonical]
+	c := SemanticDedupCluster{
+		Subject:             winner.Subject,
+		CanonicalID:         winner.ID,
+		CanonicalPredicate:  winner.Predicate,
+		CanonicalObject:     winner.Object,
+		CanonicalConfidence: winner.Confidence,
+	}
+	doubt := 1.0
+	for i, f := range group {
+		doubt *= 1 - clampUnit(f.Confidence)
+		if i == canonical {
+			continue
+		}
+		c.Merged = append(c.Merged, SemanticDedupMember{
+			ID:         f.ID,
+			Predicate:  f.Predicate,
+			Object:     f.Object,
+			Confidence: f.Confidence,
+			Similarity: cosineSimilarity(winner.vec, f.vec),
+		})
+	}
+	sort.SliceStable(c.Merged, func(i, j int) bool { return c.Merged[i].Similarity > c.Merged[j].Similarity })
+
+	c.CombinedConfidence = 1 - doubt
+	if c.CombinedConfidence > maxSemanticDedupConfidence {
+		c.CombinedConfidence = maxSemanticDedupConfidence
+	}
+	if c.CombinedConfidence < winner.Confidence {
+		c.CombinedConfidence = winner.Confidence
+	}
+	return c
+}
+
+// betterSemanticCanonical reports whether a is a better canonical phrasing
+// than b: more central to the cluster, then more confident, then more
+// recently reinforced, then older.
+func betterSemanticCanonical(a semanticDedupRow, aCentrality float64, b semanticDedupRow, bCentrality float64) bool {
+	const epsilon = 1e-9
+	if diff := aCentrality - bCentrality; diff > epsilon || diff < -epsilon {
+		return diff > 0
+	}
+	if a.Confidence != b.Confidence {
+		return a.Confidence > b.Confidence
+	}
+	if a.LastReinforced != b.LastReinforced {
+		return a.LastReinforced > b.LastReinforced
+	}
+	return a.ID < b.ID
+}
+
+func clampUnit(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
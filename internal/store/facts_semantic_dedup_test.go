@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// conceptEmbed maps text onto fixed concept axes so paraphrases share a vector.
+func conceptEmbed(_ context.Context, texts []string) ([][]float32, error) {
+	axes := [][]string{{"dark"}, {"coffee", "espresso"}, {"philadelphia"}}
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		lower := strings.ToLower(text)
+		vec := make([]float32, len(axes)+1)
+		vec[len(axes)] = 0.1 // keep unrelated texts from being zero vectors
+		for a, words := range axes {
+			for _, w := range words {
+				if strings.Contains(lower, w) {
+					vec[a] = 1
+				}
+			}
+		}
+		out[i] = vec
+	}
+	return out, nil
+}
+
+func TestSemanticDedupFacts_MergesParaphrases(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "UI preferences"})
+	darkMode, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "user", Predicate: "prefers", Object: "dark mode", Confidence: 0.8, FactType: "preference"})
+	darkThemes, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "User", Predicate: "likes", Object: "dark themes", Confidence: 0.6, FactType: "preference"})
+	coffee, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "user", Predicate: "drinks", Object: "coffee", Confidence: 0.7, FactType: "preference"})
+	otherSubject, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "bob", Predicate: "likes", Object: "dark themes", Confidence: 0.9, FactType: "preference"})
+
+	report, err := s.SemanticDedupFacts(ctx, SemanticDedupOptions{DryRun: true}, conceptEmbed)
+	if err != nil {
+		t.Fatalf("SemanticDedupFacts dry run: %v", err)
+	}
+	if report.SubjectsScanned != 2 || report.FactsEmbedded != 3 || report.Clusters != 1 || report.Merged != 1 {
+		t.Fatalf("unexpected dry-run report: %+v", report)
+	}
+	c := report.Preview[0]
+	if c.CanonicalID != darkMode || len(c.Merged) != 1 || c.Merged[0].ID != darkThemes {
+		t.Fatalf("expected dark mode to absorb dark themes, got %+v", c)
+	}
+	if math.Abs(c.CombinedConfidence-0.92) > 1e-9 {
+		t.Fatalf("combined confidence = %v, want 0.92", c.CombinedConfidence)
+	}
+	if f, _ := s.GetFact(ctx, darkThemes); f.SupersededBy != nil {
+		t.Fatal("dry run must not supersede facts")
+	}
+
+	if _, err := s.SemanticDedupFacts(ctx, SemanticDedupOptions{}, conceptEmbed); err != nil {
+		t.Fatalf("SemanticDedupFacts: %v", err)
+	}
+	canonical, _ := s.GetFact(ctx, darkMode)
+	if math.Abs(canonical.Confidence-0.92) > 1e-9 {
+		t.Fatalf("canonical confidence = %v, want 0.92", canonical.Confidence)
+	}
+	merged, _ := s.GetFact(ctx, darkThemes)
+	if merged.SupersededBy == nil || *merged.SupersededBy != darkMode {
+		t.Fatalf("expected fact %d superseded by %d, got %+v", darkThemes, darkMode, merged.SupersededBy)
+	}
+	for _, id := range []int64{coffee, otherSubject} {
+		if f, _ := s.GetFact(ctx, id); f.SupersededBy != nil {
+			t.Fatalf("fact %d should be untouched", id)
+		}
+	}
+
+	again, err := s.SemanticDedupFacts(ctx, SemanticDedupOptions{}, conceptEmbed)
+	if err != nil {
+		t.Fatalf("second pass: %v", err)
+	}
+	if again.Clusters != 0 {
+		t.Fatalf("second pass should find nothing, got %+v", again)
+	}
+}
+
+func TestSemanticDedupFacts_EmbedderErrors(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "coffee"})
+	s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "user", Predicate: "drinks", Object: "coffee", Confidence: 0.7, FactType: "preference"})
+	s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "user", Predicate: "likes", Object: "espresso", Confidence: 0.7, FactType: "preference"})
+
+	if _, err := s.SemanticDedupFacts(ctx, SemanticDedupOptions{}, nil); err == nil {
+		t.Fatal("expected error without an embedder")
+	}
+	failing := func(context.Context, []string) ([][]float32, error) { return nil, errors.New("provider down") }
+	if _, err := s.SemanticDedupFacts(ctx, SemanticDedupOptions{}, failing); err == nil || !strings.Contains(err.Error(), "provider down") {
+		t.Fatalf("expected embedder error, got %v", err)
+	}
+
+	report, err := s.SemanticDedupFacts(ctx, SemanticDedupOptions{DryRun: true, MaxGroupSize: 1}, conceptEmbed)
+	if err != nil {
+		t.Fatalf("SemanticDedupFacts: %v", err)
+	}
+	if report.SubjectsSkipped != 1 || report.FactsEmbedded != 0 {
+		t.Fatalf("expected oversized subject to be skipped, got %+v", report)
+	}
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migrateFileChecksums adds import_file_checksums, the whole-file content
+// hash of each imported file, which lets import skip files that haven't
+// changed since they were last imported without chunking them again.
+func (s *SQLiteStore) migrateFileChecksums() error {
+	done, err := s.isMetaFlagEnabled("file_checksums_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS import_file_checksums (
+			source_file TEXT PRIMARY KEY,
+			sha256      TEXT NOT NULL,
+			size        INTEGER NOT NULL,
+			imported_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_memories_source_file ON memories(source_file)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('file_checksums_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("migrating file checksums: %w", err)
+		}
+	}
+	return nil
+}
+
+// migrateFileChecksumNamespaces keys import_file_checksums by namespace as
+// well as source file, so importing a file into one namespace doesn't mark
+// it unchanged for another. Existing checksums belong to DefaultNamespace.
+func (s *SQLiteStore) migrateFileChecksumNamespaces() error {
+	done, err := s.isMetaFlagEnabled("file_checksums_namespace_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE import_file_checksums_v2 (
+			namespace   TEXT NOT NULL DEFAULT 'default',
+			source_file TEXT NOT NULL,
+			sha256      TEXT NOT NULL,
+			size        INTEGER NOT NULL,
+			imported_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (namespace, source_file)
+		)`,
+		`INSERT INTO import_file_checksums_v2 (namespace, source_file, sha256, size, imported_at)
+		 SELECT 'default', source_file, sha256, size, imported_at FROM import_file_checksums`,
+		`DROP TABLE import_file_checksums`,
+		`ALTER TABLE import_file_checksums_v2 RENAME TO import_file_checksums`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('file_checksums_namespace_v1', 'true')`,
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("migrating file checksum namespaces: %w", err)
+	}
+	defer tx.Rollback()
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("migrating file checksum namespaces: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// UnchangedSourceFile reports how many live memories in ctx's namespace
+// came from sourceFile when the checksum recorded for that namespace equals
+// sha256. It returns 0 when the file is new there, has changed, or has no
+// memories left (deleted, forgotten, or removed by refresh-source), so
+// those files are imported again.
+func (s *SQLiteStore) UnchangedSourceFile(ctx context.Context, sourceFile, sha256 string) (int, error) {
+	ns := s.writeNamespace(ctx, &Memory{})
+	var recorded string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT sha256 FROM import_file_checksums WHERE namespace = ? AND source_file = ?`, ns, sourceFile,
+	).Scan(&recorded)
+	if err == sql.ErrNoRows || (err == nil && recorded != sha256) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading checksum for %s: %w", sourceFile, err)
+	}
+
+	var live int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM memories WHERE source_file = ? AND namespace = ? AND deleted_at IS NULL`, sourceFile, ns,
+	).Scan(&live); err != nil {
+		return 0, fmt.Errorf("counting memories for %s: %w", sourceFile, err)
+	}
+	return live, nil
+}
+
+// RecordFileChecksum stores the checksum of a file that was just imported
+// into ctx's namespace.
+func (s *SQLiteStore) RecordFileChecksum(ctx context.Context, sourceFile, sha256 string, size int64) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO import_file_checksums (namespace, source_file, sha256, size, imported_at) VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+		 ON CONFLICT(namespace, source_file) DO UPDATE SET sha256 = excluded.sha256, size = excluded.size, imported_at = excluded.imported_at`,
+		s.writeNamespace(ctx, &Memory{}), sourceFile, sha256, size,
+	)
+	if err != nil {
+		return fmt.Errorf("recording checksum for %s: %w", sourceFile, err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE documents SET version_hash = ?, updated_at = ? WHERE source_file = ?`,
+		sha256, time.Now().UTC(), sourceFile,
+	); err != nil {
+		return fmt.Errorf("recording document version for %s: %w", sourceFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUnchangedSourceFile(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	if n, err := s.UnchangedSourceFile(ctx, "/notes.md", "abc"); err != nil || n != 0 {
+		t.Fatalf("unknown file = %d, %v", n, err)
+	}
+	id, _ := s.AddMemory(ctx, &Memory{Content: "one", SourceFile: "/notes.md"})
+	s.AddMemory(ctx, &Memory{Content: "two", SourceFile: "/notes.md"})
+	if err := s.RecordFileChecksum(ctx, "/notes.md", "abc", 42); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, _ := s.UnchangedSourceFile(ctx, "/notes.md", "abc"); n != 2 {
+		t.Fatalf("unchanged file = %d live memories, want 2", n)
+	}
+	if n, _ := s.UnchangedSourceFile(ctx, "/notes.md", "def"); n != 0 {
+		t.Fatalf("changed file = %d, want 0", n)
+	}
+	if err := s.DeleteMemory(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := s.UnchangedSourceFile(ctx, "/notes.md", "abc"); n != 1 {
+		t.Fatalf("after delete = %d, want 1", n)
+	}
+
+	// Re-recording replaces the checksum.
+	if err := s.RecordFileChecksum(ctx, "/notes.md", "def", 50); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := s.UnchangedSourceFile(ctx, "/notes.md", "abc"); n != 0 {
+		t.Fatalf("old checksum should no longer match, got %d", n)
+	}
+}
+
+func TestUnchangedSourceFilePerNamespace(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+	work := WithNamespace(ctx, "work")
+
+	s.AddMemory(ctx, &Memory{Content: "v1", SourceFile: "/notes.md"})
+	if err := s.RecordFileChecksum(ctx, "/notes.md", "v1", 2); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := s.UnchangedSourceFile(work, "/notes.md", "v1"); n != 0 {
+		t.Fatalf("work has no memories of the file, got %d", n)
+	}
+
+	s.AddMemory(work, &Memory{Content: "v2", SourceFile: "/notes.md"})
+	if err := s.RecordFileChecksum(work, "/notes.md", "v2", 2); err != nil {
+		t.Fatal(err)
+	}
+	// Importing v2 into work must not make default's v1 memories look current.
+	if n, _ := s.UnchangedSourceFile(ctx, "/notes.md", "v2"); n != 0 {
+		t.Fatalf("default holds v1, got unchanged %d for v2", n)
+	}
+	if n, _ := s.UnchangedSourceFile(ctx, "/notes.md", "v1"); n != 1 {
+		t.Fatalf("default v1 = %d, want 1", n)
+	}
+	if n, _ := s.UnchangedSourceFile(work, "/notes.md", "v2"); n != 1 {
+		t.Fatalf("work v2 = %d, want 1", n)
+	}
+}
@@ -0,0 +1,469 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ForgetRedaction replaces a forgotten subject in redacted text.
+const ForgetRedaction = "[REDACTED]"
+
+// ForgetOptions selects what `cortex forget` looks for.
+type ForgetOptions struct {
+	Subject string
+	// Vector, when set, is the embedded subject; memories whose embedding is
+	// at least MinSimilarity to it are matched too.
+	Vector        []float32
+	MinSimilarity float64
+}
+
+// ForgetMatch is one record that mentions the subject.
+type ForgetMatch struct {
+	Kind       string  `json:"kind"` // memory or fact
+	ID         int64   `json:"id"`
+	MemoryID   int64   `json:"memory_id,omitempty"`
+	Text       string  `json:"text"`  // memory preview or fact triple
+	Match      string  `json:"match"` // exact or semantic
+	Similarity float64 `json:"similarity,omitempty"`
+	Held       bool    `json:"held,omitempty"` // under archive hold; left as is
+}
+
+// ForgetPlan lists everything PlanForget found for a subject.
+type ForgetPlan struct {
+	Subject  string        `json:"subject"`
+	Memories []ForgetMatch `json:"memories"`
+	Facts    []ForgetMatch `json:"facts"`
+}
+
+// ForgetResult counts what ApplyForget removed or redacted.
+type ForgetResult struct {
+	Mode       string `json:"mode"` // delete or redact
+	Memories   int64  `json:"memories"`
+	Facts      int64  `json:"facts"`
+	Embeddings int64  `json:"embeddings"`
+	Events     int64  `json:"events"`
+	Alerts     int64  `json:"alerts"`
+	// Records derived from memories and facts.
+	Reviews     int64 `json:"reviews"`      // extraction review queue entries
+	Triage      int64 `json:"triage"`       // import triage decisions
+	Annotations int64 `json:"annotations"`  // notes on records
+	Briefs      int64 `json:"briefs"`       // always deleted; `cortex brief` rebuilds them
+	ReasonCache int64 `json:"reason_cache"` // always deleted; recomputed on the next run
+	Skipped     int   `json:"skipped"`      // held records, and semantic-only memories when redacting
+}
+
+// PlanForget finds every memory and fact that mentions opts.Subject, by
+// case-insensitive substring and, when opts.Vector is set, by embedding
+// similarity. Soft-deleted memories are included: they are still on disk,
+// and so are compacted ones, matched on their archived content. Nothing is
+// changed.
+func (s *SQLiteStore) PlanForget(ctx context.Context, opts ForgetOptions) (*ForgetPlan, error) {
+	subject := strings.TrimSpace(opts.Subject)
+	if subject == "" {
+		return nil, fmt.Errorf("forget: subject is required")
+	}
+	heldMemories, err := s.queryMemoryIDs(ctx, `SELECT id FROM memories WHERE NOT (`+NotHeldMemoryClause+`)`)
+	if err != nil {
+		return nil, fmt.Errorf("listing held memories: %w", err)
+	}
+	heldMemory := make(map[int64]bool, len(heldMemories))
+	for _, id := range heldMemories {
+		heldMemory[id] = true
+	}
+	heldFact, err := s.HeldFactIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ForgetPlan{Subject: subject}
+	seen := map[int64]bool{}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, content FROM memories WHERE instr(lower(content), lower(?)) > 0 ORDER BY id`, subject)
+	if err != nil {
+		return nil, fmt.Errorf("searching memories for %q: %w", subject, err)
+	}
+	for rows.Next() {
+		var id int64
+		var content string
+		if err := rows.Scan(&id, &content); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning memory: %w", err)
+		}
+		seen[id] = true
+		plan.Memories = append(plan.Memories, ForgetMatch{
+			Kind: "memory", ID: id, Text: forgetPreview(content), Match: "exact", Held: heldMemory[id],
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// A compacted memory keeps only a stub; its original text is archived.
+	archived, err := s.archivedMemoriesMentioning(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range archived {
+		if seen[a.id] {
+			continue
+		}
+		seen[a.id] = true
+		plan.Memories = append(plan.Memories, ForgetMatch{
+			Kind: "memory", ID: a.id, Text: forgetPreview(a.content), Match: "exact", Held: heldMemory[a.id],
+		})
+	}
+
+	if len(opts.Vector) > 0 {
+		results, err := s.SearchEmbedding(ctx, opts.Vector, 1000, opts.MinSimilarity)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			if seen[r.Memory.ID] {
+				continue
+			}
+			seen[r.Memory.ID] = true
+			plan.Memories = append(plan.Memories, ForgetMatch{
+				Kind: "memory", ID: r.Memory.ID, Text: forgetPreview(r.Memory.Content),
+				Match: "semantic", Similarity: r.Score, Held: heldMemory[r.Memory.ID],
+			})
+		}
+	}
+
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT id, memory_id, subject, predicate, object FROM facts
+		WHERE instr(lower(subject), lower(?1)) > 0
+		   OR instr(lower(object), lower(?1)) > 0
+		   OR instr(lower(source_quote), lower(?1)) > 0
+		ORDER BY id`, subject)
+	if err != nil {
+		return nil, fmt.Errorf("searching facts for %q: %w", subject, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m ForgetMatch
+		var subj, pred, obj string
+		if err := rows.Scan(&m.ID, &m.MemoryID, &subj, &pred, &obj); err != nil {
+			return nil, fmt.Errorf("scanning fact: %w", err)
+		}
+		m.Kind, m.Match, m.Held = "fact", "exact", heldFact[m.ID]
+		m.Text = subj + " " + pred + " " + obj
+		plan.Facts = append(plan.Facts, m)
+	}
+	return plan, rows.Err()
+}
+
+// ApplyForget carries out a plan. By default matched memories are purged
+// with all of their facts, embeddings, and compaction archives (the FTS
+// index follows through its triggers), and matched facts are deleted with
+// their edges, accesses, and alerts. With redact, the subject is replaced
+// by ForgetRedaction in memory content, archived content, and fact fields
+// instead, and redacted memories lose their embedding until re-embedded;
+// semantic-only memories have nothing literal to redact and are skipped.
+// Review queue entries, import triage excerpts, and annotations that
+// mention the subject (or, when deleting, belong to a purged record) are
+// deleted or redacted the same way. Either way, events, alerts (webhook
+// history), briefs, and cached reason answers that mention the subject are
+// deleted. Held records are skipped.
+func (s *SQLiteStore) ApplyForget(ctx context.Context, plan *ForgetPlan, redact bool) (*ForgetResult, error) {
+	res := &ForgetResult{Mode: "delete"}
+	if redact {
+		res.Mode = "redact"
+	}
+	var memoryIDs, factIDs []int64
+	for _, m := range plan.Memories {
+		if m.Held || (redact && m.Match != "exact") {
+			res.Skipped++
+			continue
+		}
+		memoryIDs = append(memoryIDs, m.ID)
+	}
+	for _, f := range plan.Facts {
+		if f.Held {
+			res.Skipped++
+			continue
+		}
+		factIDs = append(factIDs, f.ID)
+	}
+	if !redact && len(memoryIDs) > 0 {
+		// Purging a memory deletes all of its facts; clean up after them too.
+		owned, err := s.queryMemoryIDs(ctx,
+			fmt.Sprintf(`SELECT id FROM facts WHERE memory_id IN (%s)`, int64List(memoryIDs)))
+		if err != nil {
+			return nil, fmt.Errorf("listing facts of forgotten memories: %w", err)
+		}
+		factIDs = appendUnique(factIDs, owned...)
+	}
+
+	pattern := "%" + plan.Subject + "%"
+	if len(factIDs) > 0 {
+		n, err := s.execCount(ctx,
+			fmt.Sprintf(`DELETE FROM memory_events WHERE fact_id IN (%s)`, int64List(factIDs)))
+		if err != nil {
+			return nil, fmt.Errorf("deleting fact events: %w", err)
+		}
+		res.Events += n
+	}
+	n, err := s.execCount(ctx,
+		`DELETE FROM memory_events WHERE old_value LIKE ? OR new_value LIKE ?`, pattern, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("deleting events mentioning subject: %w", err)
+	}
+	res.Events += n
+
+	alertQuery := `DELETE FROM alerts WHERE message LIKE ? OR details LIKE ?`
+	alertArgs := []any{pattern, pattern}
+	if len(factIDs) > 0 {
+		list := int64List(factIDs)
+		alertQuery += fmt.Sprintf(` OR fact_id IN (%s) OR related_fact_id IN (%s)`, list, list)
+	}
+	if res.Alerts, err = s.execCount(ctx, alertQuery, alertArgs...); err != nil {
+		return nil, fmt.Errorf("deleting alerts: %w", err)
+	}
+
+	// Briefs and cached answers are regenerated on demand; there is nothing
+	// worth redacting in place.
+	if res.Briefs, err = s.execCount(ctx,
+		`DELETE FROM briefs WHERE title LIKE ? OR summary LIKE ?`, pattern, pattern); err != nil {
+		return nil, fmt.Errorf("deleting briefs: %w", err)
+	}
+	if res.ReasonCache, err = s.execCount(ctx,
+		`DELETE FROM reason_cache WHERE query LIKE ? OR result LIKE ?`, pattern, pattern); err != nil {
+		return nil, fmt.Errorf("deleting cached reason results: %w", err)
+	}
+
+	if len(memoryIDs) > 0 {
+		if res.Embeddings, err = s.execCount(ctx,
+			fmt.Sprintf(`DELETE FROM embeddings WHERE memory_id IN (%s)`, int64List(memoryIDs))); err != nil {
+			return nil, fmt.Errorf("deleting embeddings: %w", err)
+		}
+	}
+
+	if redact {
+		re := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(plan.Subject))
+		for _, id := range memoryIDs {
+			var content string
+			if err := s.db.QueryRowContext(ctx, `SELECT content FROM memories WHERE id = ?`, id).Scan(&content); err != nil {
+				return nil, fmt.Errorf("reading memory %d: %w", id, err)
+			}
+			redacted := re.ReplaceAllLiteralString(content, ForgetRedaction)
+			hashed := redacted
+			original, archived, err := s.ArchivedMemoryContent(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if archived {
+				// The content hash of a compacted memory is that of its original.
+				hashed = re.ReplaceAllLiteralString(original, ForgetRedaction)
+				data, err := gzipContent(hashed)
+				if err != nil {
+					return nil, fmt.Errorf("compressing memory %d: %w", id, err)
+				}
+				if _, err := s.db.ExecContext(ctx,
+					`UPDATE memory_archive SET content = ?, original_bytes = ? WHERE memory_id = ?`,
+					data, len(hashed), id); err != nil {
+					return nil, fmt.Errorf("redacting archive of memory %d: %w", id, err)
+				}
+			}
+			if _, err := s.db.ExecContext(ctx,
+				`UPDATE memories SET content = ?, content_hash = ? || `+namespaceHashSuffixSQL+`, updated_at = ? WHERE id = ?`,
+				redacted, HashContentOnly(hashed), time.Now().UTC(), id); err != nil {
+				return nil, fmt.Errorf("redacting memory %d: %w", id, err)
+			}
+			res.Memories++
+		}
+		for _, id := range factIDs {
+			var subj, obj, quote string
+			if err := s.db.QueryRowContext(ctx,
+				`SELECT subject, object, COALESCE(source_quote, '') FROM facts WHERE id = ?`, id).Scan(&subj, &obj, &quote); err != nil {
+				return nil, fmt.Errorf("reading fact %d: %w", id, err)
+			}
+			if _, err := s.db.ExecContext(ctx,
+				`UPDATE facts SET subject = ?, object = ?, source_quote = ? WHERE id = ?`,
+				re.ReplaceAllLiteralString(subj, ForgetRedaction),
+				re.ReplaceAllLiteralString(obj, ForgetRedaction),
+				re.ReplaceAllLiteralString(quote, ForgetRedaction), id); err != nil {
+				return nil, fmt.Errorf("redacting fact %d: %w", id, err)
+			}
+			res.Facts++
+		}
+		for _, r := range []struct {
+			table string
+			cols  []string
+			n     *int64
+		}{
+			{"fact_reviews", []string{"subject", "object", "source_quote"}, &res.Reviews},
+			{"import_triage", []string{"excerpt"}, &res.Triage},
+			{"annotations", []string{"body", "label"}, &res.Annotations},
+		} {
+			if *r.n, err = s.redactRows(ctx, r.table, r.cols, pattern, re); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+
+	reviewQuery := `DELETE FROM fact_reviews WHERE subject LIKE ?1 OR object LIKE ?1 OR source_quote LIKE ?1`
+	triageQuery := `DELETE FROM import_triage WHERE excerpt LIKE ?1`
+	annotationQuery := `DELETE FROM annotations WHERE body LIKE ?1 OR label LIKE ?1`
+	if len(memoryIDs) > 0 {
+		list := int64List(memoryIDs)
+		reviewQuery += fmt.Sprintf(` OR memory_id IN (%s)`, list)
+		triageQuery += fmt.Sprintf(` OR memory_id IN (%s)`, list)
+		annotationQuery += fmt.Sprintf(` OR (target_type = 'memory' AND target_id IN (%s))`, list)
+	}
+	if len(factIDs) > 0 {
+		list := int64List(factIDs)
+		reviewQuery += fmt.Sprintf(` OR fact_id IN (%s)`, list)
+		annotationQuery += fmt.Sprintf(` OR (target_type = 'fact' AND target_id IN (%s))`, list)
+	}
+	if res.Reviews, err = s.execCount(ctx, reviewQuery, pattern); err != nil {
+		return nil, fmt.Errorf("deleting fact reviews: %w", err)
+	}
+	if res.Triage, err = s.execCount(ctx, triageQuery, pattern); err != nil {
+		return nil, fmt.Errorf("deleting import triage: %w", err)
+	}
+	if res.Annotations, err = s.execCount(ctx, annotationQuery, pattern); err != nil {
+		return nil, fmt.Errorf("deleting annotations: %w", err)
+	}
+
+	if res.Facts, err = s.DeleteFactsByIDs(ctx, factIDs); err != nil {
+		return nil, err
+	}
+	if len(memoryIDs) > 0 {
+		if _, err := s.db.ExecContext(ctx,
+			fmt.Sprintf(`DELETE FROM memory_archive WHERE memory_id IN (%s)`, int64List(memoryIDs))); err != nil {
+			return nil, fmt.Errorf("deleting archived content: %w", err)
+		}
+	}
+	if res.Memories, err = s.purgeMemories(ctx, memoryIDs); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// redactRows replaces re's matches in cols of every row of table where one
+// of them is LIKE pattern, and returns the number of rows rewritten. The
+// table must have an integer id key.
+func (s *SQLiteStore) redactRows(ctx context.Context, table string, cols []string, pattern string, re *regexp.Regexp) (int64, error) {
+	where := make([]string, len(cols))
+	set := make([]string, len(cols))
+	args := make([]any, len(cols))
+	for i, c := range cols {
+		where[i] = c + " LIKE ?"
+		set[i] = c + " = ?"
+		args[i] = pattern
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, `+strings.Join(cols, ", ")+` FROM `+table+` WHERE `+strings.Join(where, " OR "), args...)
+	if err != nil {
+		return 0, fmt.Errorf("searching %s: %w", table, err)
+	}
+	type match struct {
+		id   int64
+		vals []any
+	}
+	var matched []match
+	for rows.Next() {
+		var id int64
+		vals := make([]string, len(cols))
+		dest := []any{&id}
+		for i := range vals {
+			dest = append(dest, &vals[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scanning %s: %w", table, err)
+		}
+		m := match{id: id}
+		for _, v := range vals {
+			m.vals = append(m.vals, re.ReplaceAllLiteralString(v, ForgetRedaction))
+		}
+		matched = append(matched, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	for _, m := range matched {
+		if _, err := s.db.ExecContext(ctx,
+			`UPDATE `+table+` SET `+strings.Join(set, ", ")+` WHERE id = ?`, append(m.vals, m.id)...); err != nil {
+			return 0, fmt.Errorf("redacting %s %d: %w", table, m.id, err)
+		}
+	}
+	return int64(len(matched)), nil
+}
+
+type archivedMemory struct {
+	id      int64
+	content string
+}
+
+// archivedMemoriesMentioning returns the compacted memories whose archived
+// original content contains subject, case-insensitively.
+func (s *SQLiteStore) archivedMemoriesMentioning(ctx context.Context, subject string) ([]archivedMemory, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT memory_id, content FROM memory_archive ORDER BY memory_id`)
+	if err != nil {
+		return nil, fmt.Errorf("searching archived memories for %q: %w", subject, err)
+	}
+	defer rows.Close()
+	needle := strings.ToLower(subject)
+	var out []archivedMemory
+	for rows.Next() {
+		var id int64
+		var data []byte
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("scanning archived memory: %w", err)
+		}
+		content, err := gunzipContent(data)
+		if err != nil {
+			return nil, fmt.Errorf("decompressing memory %d: %w", id, err)
+		}
+		if strings.Contains(strings.ToLower(content), needle) {
+			out = append(out, archivedMemory{id: id, content: content})
+		}
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLiteStore) execCount(ctx context.Context, query string, args ...any) (int64, error) {
+	r, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return r.RowsAffected()
+}
+
+func forgetPreview(content string) string {
+	content = strings.Join(strings.Fields(content), " ")
+	if r := []rune(content); len(r) > 100 {
+		return string(r[:99]) + "…"
+	}
+	return content
+}
+
+func int64List(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("%d", id)
+	}
+	return strings.Join(parts, ",")
+}
+
+func appendUnique(ids []int64, more ...int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	for _, id := range more {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,279 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newForgetTestStore(t *testing.T) (*SQLiteStore, int64, int64, int64) {
+	t.Helper()
+	si, err := NewStore(StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { si.Close() })
+	s := si.(*SQLiteStore)
+	ctx := context.Background()
+
+	mentions, _ := s.AddMemory(ctx, &Memory{Content: "Met John Doe about the lease renewal", SourceFile: "crm.md"})
+	related, _ := s.AddMemory(ctx, &Memory{Content: "Lease renewal paperwork for the tenant", SourceFile: "crm.md"})
+	unrelated, _ := s.AddMemory(ctx, &Memory{Content: "Grocery list: eggs, flour", SourceFile: "home.md"})
+	s.AddEmbedding(ctx, mentions, []float32{1, 0, 0})
+	s.AddEmbedding(ctx, related, []float32{0.9, 0.1, 0})
+	s.AddEmbedding(ctx, unrelated, []float32{0, 0, 1})
+	return s, mentions, related, unrelated
+}
+
+func TestForget_DeleteRemovesEverything(t *testing.T) {
+	s, mentions, related, unrelated := newForgetTestStore(t)
+	ctx := context.Background()
+
+	f1, _ := s.AddFact(ctx, &Fact{MemoryID: mentions, Subject: "john doe", Predicate: "role", Object: "tenant", FactType: "kv", Confidence: 0.9})
+	f2, _ := s.AddFact(ctx, &Fact{MemoryID: unrelated, Subject: "lease", Predicate: "signed by", Object: "John Doe", FactType: "kv", Confidence: 0.9})
+	keep, _ := s.AddFact(ctx, &Fact{MemoryID: unrelated, Subject: "list", Predicate: "item", Object: "eggs", FactType: "kv", Confidence: 0.9})
+	s.AddEdge(ctx, &FactEdge{SourceFactID: f2, TargetFactID: keep, EdgeType: EdgeTypeRelatesTo, Confidence: 0.8, Source: EdgeSourceExplicit})
+	s.CreateAlert(ctx, &Alert{AlertType: AlertTypeConflict, FactID: &f2, Message: "John Doe conflict"})
+
+	plan, err := s.PlanForget(ctx, ForgetOptions{Subject: "john doe", Vector: []float32{1, 0, 0}, MinSimilarity: 0.8})
+	if err != nil {
+		t.Fatalf("PlanForget: %v", err)
+	}
+	if len(plan.Memories) != 2 || plan.Memories[0].ID != mentions || plan.Memories[0].Match != "exact" ||
+		plan.Memories[1].ID != related || plan.Memories[1].Match != "semantic" {
+		t.Fatalf("unexpected memories: %+v", plan.Memories)
+	}
+	if len(plan.Facts) != 2 || plan.Facts[0].ID != f1 || plan.Facts[1].ID != f2 {
+		t.Fatalf("unexpected facts: %+v", plan.Facts)
+	}
+
+	res, err := s.ApplyForget(ctx, plan, false)
+	if err != nil {
+		t.Fatalf("ApplyForget: %v", err)
+	}
+	if res.Memories != 2 || res.Facts != 2 || res.Embeddings != 2 || res.Alerts != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if m, _ := s.GetMemory(ctx, mentions); m != nil {
+		t.Fatal("memory mentioning the subject should be purged")
+	}
+	if hits, _ := s.SearchFTS(ctx, "John", 10); len(hits) != 0 {
+		t.Fatalf("FTS still finds the subject: %+v", hits)
+	}
+	if edges, _ := s.GetEdgesForFact(ctx, keep); len(edges) != 0 {
+		t.Fatalf("edges to forgotten facts remain: %+v", edges)
+	}
+	if f, _ := s.GetFact(ctx, keep); f == nil {
+		t.Fatal("unrelated fact should be kept")
+	}
+
+	again, _ := s.PlanForget(ctx, ForgetOptions{Subject: "John Doe"})
+	if len(again.Memories) != 0 || len(again.Facts) != 0 {
+		t.Fatalf("subject still present after forget: %+v", again)
+	}
+}
+
+func TestForget_RedactAndHolds(t *testing.T) {
+	s, mentions, _, unrelated := newForgetTestStore(t)
+	ctx := context.Background()
+
+	fact, _ := s.AddFact(ctx, &Fact{MemoryID: mentions, Subject: "John Doe", Predicate: "lease", Object: "renewed", FactType: "kv", Confidence: 0.9, SourceQuote: "Met John Doe"})
+	heldFact, _ := s.AddFact(ctx, &Fact{MemoryID: unrelated, Subject: "contract", Predicate: "signer", Object: "john doe", FactType: "kv", Confidence: 0.9})
+	if err := s.SetArchiveHold(ctx, HoldKindFact, heldFact, "litigation"); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := s.PlanForget(ctx, ForgetOptions{Subject: "John Doe", Vector: []float32{1, 0, 0}, MinSimilarity: 0.8})
+	if err != nil {
+		t.Fatalf("PlanForget: %v", err)
+	}
+	if len(plan.Facts) != 2 || !plan.Facts[1].Held {
+		t.Fatalf("held fact should be flagged: %+v", plan.Facts)
+	}
+
+	res, err := s.ApplyForget(ctx, plan, true)
+	if err != nil {
+		t.Fatalf("ApplyForget: %v", err)
+	}
+	// Skipped: the semantic-only memory and the held fact.
+	if res.Mode != "redact" || res.Memories != 1 || res.Facts != 1 || res.Embeddings != 1 || res.Skipped != 2 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	m, _ := s.GetMemory(ctx, mentions)
+	if m == nil || m.Content != "Met [REDACTED] about the lease renewal" {
+		t.Fatalf("memory not redacted: %+v", m)
+	}
+	if v, _ := s.GetEmbedding(ctx, mentions); v != nil {
+		t.Fatal("redacted memory should lose its stale embedding")
+	}
+	f, _ := s.GetFact(ctx, fact)
+	if f.Subject != ForgetRedaction || strings.Contains(f.SourceQuote, "John") {
+		t.Fatalf("fact not redacted: %+v", f)
+	}
+	if f, _ := s.GetFact(ctx, heldFact); f.Object != "john doe" {
+		t.Fatalf("held fact changed: %+v", f)
+	}
+}
+
+func TestForget_CompactedMemories(t *testing.T) {
+	// The subject is past the first line, so the compacted stub doesn't
+	// mention it and only the archived original does.
+	content := "Lease notes\n" + strings.Repeat("The renewal paperwork is still with the landlord. ", 20) + "Met John Doe about it."
+	for _, redact := range []bool{false, true} {
+		s, _, _, _ := newForgetTestStore(t)
+		ctx := context.Background()
+		id, _ := s.AddMemory(ctx, &Memory{Content: content, SourceFile: "crm.md"})
+		s.AddFact(ctx, &Fact{MemoryID: id, Subject: "lease", Predicate: "status", Object: "renewal", FactType: "kv", Confidence: 0.9})
+		if r, err := s.CompactMemories(ctx, CompactOptions{OlderThan: time.Now().Add(time.Hour)}); err != nil || len(r.MemoryIDs) != 1 || r.MemoryIDs[0] != id {
+			t.Fatalf("CompactMemories = %+v, %v", r, err)
+		}
+
+		plan, err := s.PlanForget(ctx, ForgetOptions{Subject: "john doe"})
+		if err != nil {
+			t.Fatalf("PlanForget: %v", err)
+		}
+		var found bool
+		for _, m := range plan.Memories {
+			found = found || m.ID == id
+		}
+		if !found {
+			t.Fatalf("compacted memory not matched: %+v", plan.Memories)
+		}
+		if _, err := s.ApplyForget(ctx, plan, redact); err != nil {
+			t.Fatalf("ApplyForget: %v", err)
+		}
+
+		restored, err := s.RestoreCompactedMemories(ctx, nil)
+		if err != nil {
+			t.Fatalf("RestoreCompactedMemories: %v", err)
+		}
+		m, _ := s.GetMemory(ctx, id)
+		if !redact {
+			if len(restored) != 0 || m != nil {
+				t.Fatalf("forgotten memory came back: restored %v, memory %+v", restored, m)
+			}
+			continue
+		}
+		want := strings.Replace(content, "John Doe", ForgetRedaction, 1)
+		if len(restored) != 1 || m == nil || m.Content != want {
+			t.Fatalf("restore after redact = %v, %+v", restored, m)
+		}
+	}
+}
+
+func TestForget_DerivedRecords(t *testing.T) {
+	ctx := context.Background()
+	forget := func(t *testing.T, s *SQLiteStore, redact bool) *ForgetResult {
+		t.Helper()
+		plan, err := s.PlanForget(ctx, ForgetOptions{Subject: "John Doe"})
+		if err != nil {
+			t.Fatalf("PlanForget: %v", err)
+		}
+		res, err := s.ApplyForget(ctx, plan, redact)
+		if err != nil {
+			t.Fatalf("ApplyForget: %v", err)
+		}
+		return res
+	}
+
+	t.Run("fact_reviews", func(t *testing.T) {
+		for _, redact := range []bool{false, true} {
+			s, _, _, unrelated := newForgetTestStore(t)
+			id, err := s.QueueFactReview(ctx, &FactReview{MemoryID: unrelated, Subject: "john doe", Predicate: "buys", Object: "flour",
+				FactType: "kv", Confidence: 0.4, SourceQuote: "John Doe buys flour"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res := forget(t, s, redact); res.Reviews != 1 {
+				t.Fatalf("redact=%v: reviews = %d, want 1", redact, res.Reviews)
+			}
+			r, _ := s.GetFactReview(ctx, id)
+			if !redact && r != nil {
+				t.Fatalf("review kept: %+v", r)
+			}
+			if redact && (r == nil || r.Subject != ForgetRedaction || strings.Contains(r.SourceQuote, "John")) {
+				t.Fatalf("review not redacted: %+v", r)
+			}
+		}
+	})
+
+	t.Run("import_triage", func(t *testing.T) {
+		for _, redact := range []bool{false, true} {
+			s, _, _, _ := newForgetTestStore(t)
+			if err := s.RecordImportTriage(ctx, &ImportTriageRecord{SourceFile: "crm.md", Excerpt: "Called John Doe twice", Decision: "dropped"}); err != nil {
+				t.Fatal(err)
+			}
+			if res := forget(t, s, redact); res.Triage != 1 {
+				t.Fatalf("redact=%v: triage = %d, want 1", redact, res.Triage)
+			}
+			rows, _ := s.ListImportTriage(ctx, ImportTriageFilter{})
+			if !redact && len(rows) != 0 {
+				t.Fatalf("triage kept: %+v", rows)
+			}
+			if redact && (len(rows) != 1 || rows[0].Excerpt != "Called [REDACTED] twice") {
+				t.Fatalf("triage not redacted: %+v", rows)
+			}
+		}
+	})
+
+	t.Run("reason_cache", func(t *testing.T) {
+		for _, redact := range []bool{false, true} {
+			s, _, _, _ := newForgetTestStore(t)
+			seq, _ := s.ChangeSeq(ctx)
+			s.PutReasonCache(ctx, ReasonCacheEntry{Key: "a", Query: "who is john doe", ChangeSeq: seq, Result: `{"answer":"a tenant"}`})
+			s.PutReasonCache(ctx, ReasonCacheEntry{Key: "b", Query: "lease status", ChangeSeq: seq, Result: `{"answer":"John Doe renewed"}`})
+			s.PutReasonCache(ctx, ReasonCacheEntry{Key: "c", Query: "groceries", ChangeSeq: seq, Result: `{"answer":"eggs"}`})
+			if res := forget(t, s, redact); res.ReasonCache != 2 {
+				t.Fatalf("redact=%v: reason cache = %d, want 2", redact, res.ReasonCache)
+			}
+			var n int
+			s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM reason_cache WHERE result LIKE '%John%' OR query LIKE '%john%'`).Scan(&n)
+			if n != 0 {
+				t.Fatalf("redact=%v: %d cached results still mention the subject", redact, n)
+			}
+		}
+	})
+
+	t.Run("annotations", func(t *testing.T) {
+		for _, redact := range []bool{false, true} {
+			s, mentions, _, unrelated := newForgetTestStore(t)
+			onText, _ := s.AddAnnotation(ctx, &Annotation{TargetType: AnnotationTargetMemory, TargetID: unrelated, Body: "John Doe asked for this list"})
+			onPurged, _ := s.AddAnnotation(ctx, &Annotation{TargetType: AnnotationTargetMemory, TargetID: mentions, Body: "verified by phone"})
+			res := forget(t, s, redact)
+			byID := map[int64]Annotation{}
+			for _, id := range []int64{unrelated, mentions} {
+				list, _ := s.ListAnnotations(ctx, AnnotationTargetMemory, id)
+				for _, a := range list {
+					byID[a.ID] = a
+				}
+			}
+			if !redact {
+				if res.Annotations != 2 || len(byID) != 0 {
+					t.Fatalf("annotations = %d, left %+v", res.Annotations, byID)
+				}
+				continue
+			}
+			if res.Annotations != 1 || byID[onText].Body != "[REDACTED] asked for this list" || byID[onPurged].Body != "verified by phone" {
+				t.Fatalf("annotations = %d, %+v", res.Annotations, byID)
+			}
+		}
+	})
+
+	t.Run("briefs", func(t *testing.T) {
+		for _, redact := range []bool{false, true} {
+			s, _, _, _ := newForgetTestStore(t)
+			s.UpsertBrief(ctx, &Brief{Level: BriefLevelProject, Key: "crm", Title: "CRM", Summary: "Mostly about John Doe's lease.", Fingerprint: "x"})
+			s.UpsertBrief(ctx, &Brief{Level: BriefLevelProject, Key: "home", Title: "Home", Summary: "Groceries.", Fingerprint: "y"})
+			if res := forget(t, s, redact); res.Briefs != 1 {
+				t.Fatalf("redact=%v: briefs = %d, want 1", redact, res.Briefs)
+			}
+			briefs, _ := s.ListBriefs(ctx, BriefLevelProject)
+			if len(briefs) != 1 || briefs[0].Key != "home" {
+				t.Fatalf("redact=%v: briefs left %+v", redact, briefs)
+			}
+		}
+	})
+}
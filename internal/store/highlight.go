@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Markers HighlightSnippets wraps around matched terms. They are control
+// characters so they can't collide with memory text; callers replace them
+// with <mark> tags, ANSI colors, or nothing.
+const (
+	HighlightOpen  = "\x02"
+	HighlightClose = "\x03"
+)
+
+// Snippet window limits, in tokens. FTS5's snippet() accepts 1–64.
+const (
+	DefaultSnippetTokens = 32
+	MaxSnippetTokens     = 64
+)
+
+// HighlightSnippets returns an FTS5 snippet of each memory's content around
+// the terms of ftsQuery, with matches wrapped in HighlightOpen and
+// HighlightClose. tokens is the window size (0 = DefaultSnippetTokens).
+// Memories the query doesn't match are left out of the map.
+func (s *SQLiteStore) HighlightSnippets(ctx context.Context, ftsQuery string, memoryIDs []int64, tokens int) (map[int64]string, error) {
+	if strings.TrimSpace(ftsQuery) == "" || len(memoryIDs) == 0 {
+		return map[int64]string{}, nil
+	}
+	if tokens <= 0 {
+		tokens = DefaultSnippetTokens
+	}
+	if tokens > MaxSnippetTokens {
+		tokens = MaxSnippetTokens
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(memoryIDs)), ",")
+	args := []any{HighlightOpen, HighlightClose, tokens, ftsQuery}
+	for _, id := range memoryIDs {
+		args = append(args, id)
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT rowid, snippet(memories_fts, 0, ?, ?, '…', ?)
+		 FROM memories_fts
+		 WHERE memories_fts MATCH ? AND rowid IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("highlighting snippets: %w", err)
+	}
+	defer rows.Close()
+
+	snippets := make(map[int64]string, len(memoryIDs))
+	for rows.Next() {
+		var id int64
+		var snippet string
+		if err := rows.Scan(&id, &snippet); err != nil {
+			return nil, fmt.Errorf("scanning snippet: %w", err)
+		}
+		if strings.Contains(snippet, HighlightOpen) {
+			snippets[id] = snippet
+		}
+	}
+	return snippets, rows.Err()
+}
@@ -0,0 +1,35 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestHighlightSnippets(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	long := strings.Repeat("filler words pad the memory out ", 20) + "the deploy runs through argo " + strings.Repeat("more trailing filler text ", 20)
+	hit, _ := s.AddMemory(ctx, &Memory{Content: long, SourceFile: "ops.md"})
+	miss, _ := s.AddMemory(ctx, &Memory{Content: "nothing relevant here", SourceFile: "misc.md"})
+
+	snippets, err := s.HighlightSnippets(ctx, `"deploy" OR "argo"`, []int64{hit, miss}, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := snippets[miss]; ok || len(snippets) != 1 {
+		t.Fatalf("snippets = %q, want only memory %d", snippets, hit)
+	}
+	got := snippets[hit]
+	if !strings.Contains(got, HighlightOpen+"deploy"+HighlightClose) || !strings.Contains(got, HighlightOpen+"argo"+HighlightClose) {
+		t.Fatalf("snippet missing highlights: %q", got)
+	}
+	if n := len(strings.Fields(got)); n > 10 || !strings.HasPrefix(got, "…") {
+		t.Fatalf("snippet not windowed to 8 tokens: %q", got)
+	}
+
+	if snippets, err := s.HighlightSnippets(ctx, "", []int64{hit}, 0); err != nil || len(snippets) != 0 {
+		t.Fatalf("empty query = %v, %v", snippets, err)
+	}
+}
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Import triage decisions.
+const (
+	TriageKept    = "kept"
+	TriageDropped = "dropped"
+)
+
+// ImportTriageRecord is the audit row for one chunk scored by the LLM
+// import gate. MemoryID is 0 for dropped chunks.
+type ImportTriageRecord struct {
+	ID          int64     `json:"id"`
+	MemoryID    int64     `json:"memory_id,omitempty"`
+	SourceFile  string    `json:"source_file"`
+	SourceLine  int       `json:"source_line,omitempty"`
+	ContentHash string    `json:"content_hash"`
+	Excerpt     string    `json:"excerpt"`
+	Novelty     float64   `json:"novelty"`
+	Durability  float64   `json:"durability"`
+	Specificity float64   `json:"specificity"`
+	Score       float64   `json:"score"`
+	Threshold   float64   `json:"threshold"`
+	Decision    string    `json:"decision"`
+	Reason      string    `json:"reason,omitempty"`
+	Model       string    `json:"model,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ImportTriageFilter narrows ListImportTriage.
+type ImportTriageFilter struct {
+	Decision   string // kept | dropped | "" for both
+	SourceFile string // substring match
+	Limit      int
+}
+
+// importTriageExcerptLen caps the stored excerpt of a chunk, so dropped
+// chunks can be reviewed without keeping their full text.
+const importTriageExcerptLen = 200
+
+func (s *SQLiteStore) migrateImportTriageTable() error {
+	done, err := s.isMetaFlagEnabled("import_triage_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS import_triage (
+			id           INTEGER PRIMARY KEY AUTOINCREMENT,
+			memory_id    INTEGER REFERENCES memories(id) ON DELETE SET NULL,
+			source_file  TEXT NOT NULL DEFAULT '',
+			source_line  INTEGER NOT NULL DEFAULT 0,
+			content_hash TEXT NOT NULL DEFAULT '',
+			excerpt      TEXT NOT NULL DEFAULT '',
+			novelty      REAL NOT NULL DEFAULT 0,
+			durability   REAL NOT NULL DEFAULT 0,
+			specificity  REAL NOT NULL DEFAULT 0,
+			score        REAL NOT NULL DEFAULT 0,
+			threshold    REAL NOT NULL DEFAULT 0,
+			decision     TEXT NOT NULL CHECK (decision IN ('kept', 'dropped')),
+			reason       TEXT NOT NULL DEFAULT '',
+			model        TEXT NOT NULL DEFAULT '',
+			created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_import_triage_memory ON import_triage(memory_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_import_triage_created ON import_triage(created_at)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('import_triage_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating import_triage table: %w", err)
+		}
+	}
+	return nil
+}
+
+// RecordImportTriage stores the audit row for one triaged chunk. The
+// excerpt is truncated before it is written.
+func (s *SQLiteStore) RecordImportTriage(ctx context.Context, r *ImportTriageRecord) error {
+	if r.Decision != TriageKept && r.Decision != TriageDropped {
+		return fmt.Errorf("invalid triage decision %q", r.Decision)
+	}
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now().UTC()
+	}
+	r.Excerpt = truncate(strings.TrimSpace(r.Excerpt), importTriageExcerptLen)
+
+	var memoryID interface{}
+	if r.MemoryID > 0 {
+		memoryID = r.MemoryID
+	}
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO import_triage (memory_id, source_file, source_line, content_hash, excerpt,
+			novelty, durability, specificity, score, threshold, decision, reason, model, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		memoryID, r.SourceFile, r.SourceLine, r.ContentHash, r.Excerpt,
+		r.Novelty, r.Durability, r.Specificity, r.Score, r.Threshold, r.Decision, r.Reason, r.Model, r.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("recording import triage: %w", err)
+	}
+	r.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// ListImportTriage returns triage audit rows, newest first.
+func (s *SQLiteStore) ListImportTriage(ctx context.Context, f ImportTriageFilter) ([]ImportTriageRecord, error) {
+	query := `SELECT id, memory_id, source_file, source_line, content_hash, excerpt,
+			novelty, durability, specificity, score, threshold, decision, reason, model, created_at
+		 FROM import_triage WHERE 1=1`
+	var args []interface{}
+	if f.Decision != "" {
+		query += ` AND decision = ?`
+		args = append(args, f.Decision)
+	}
+	if f.SourceFile != "" {
+		query += ` AND source_file LIKE ?`
+		args = append(args, "%"+f.SourceFile+"%")
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+	if f.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, f.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing import triage: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ImportTriageRecord
+	for rows.Next() {
+		var r ImportTriageRecord
+		var memoryID sql.NullInt64
+		if err := rows.Scan(&r.ID, &memoryID, &r.SourceFile, &r.SourceLine, &r.ContentHash, &r.Excerpt,
+			&r.Novelty, &r.Durability, &r.Specificity, &r.Score, &r.Threshold, &r.Decision, &r.Reason, &r.Model, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning import triage: %w", err)
+		}
+		r.MemoryID = memoryID.Int64
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
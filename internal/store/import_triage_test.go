@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestImportTriage_RecordAndFilter(t *testing.T) {
+	s := newTestStore(t)
+	ss := s.(*SQLiteStore)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "kept chunk", SourceFile: "a.md"})
+	if err := ss.RecordImportTriage(ctx, &ImportTriageRecord{
+		MemoryID: memID, SourceFile: "a.md", Score: 0.8, Threshold: 0.5, Decision: TriageKept,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.RecordImportTriage(ctx, &ImportTriageRecord{
+		SourceFile: "b.log", Excerpt: strings.Repeat("x", 500), Score: 0.2, Threshold: 0.5, Decision: TriageDropped,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.RecordImportTriage(ctx, &ImportTriageRecord{Decision: "maybe"}); err == nil {
+		t.Fatal("expected error for invalid decision")
+	}
+
+	dropped, err := ss.ListImportTriage(ctx, ImportTriageFilter{Decision: TriageDropped})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dropped) != 1 || dropped[0].MemoryID != 0 || len(dropped[0].Excerpt) > importTriageExcerptLen+3 {
+		t.Fatalf("unexpected dropped rows: %+v", dropped)
+	}
+	kept, _ := ss.ListImportTriage(ctx, ImportTriageFilter{SourceFile: "a.md", Limit: 5})
+	if len(kept) != 1 || kept[0].MemoryID != memID || kept[0].Decision != TriageKept {
+		t.Fatalf("unexpected kept rows: %+v", kept)
+	}
+}
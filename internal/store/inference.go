@@ -49,7 +49,8 @@ func (r *InferenceResult) edgeCount(dryRun bool) int {
 	return r.EdgesCreated
 }
 
-// RunInference applies all inference rules and creates (or proposes) edges.
+// RunInference applies all inference rules and creates (or proposes) edges
+// between facts in ctx's namespace.
 func (s *SQLiteStore) RunInference(ctx context.Context, opts InferenceOpts) (*InferenceResult, error) {
 	if opts.MinConfidence <= 0 {
 		opts.MinConfidence = 0.3
@@ -144,14 +145,16 @@ func (s *SQLiteStore) inferFromCooccurrence(ctx context.Context, opts InferenceO
 // Facts with the same subject but different predicates are structurally related.
 func (s *SQLiteStore) inferFromSubjectClustering(ctx context.Context, opts InferenceOpts, result *InferenceResult) error {
 	// Find subjects with multiple distinct predicates
+	nsClause, nsArgs := s.factNamespaceFilter(ctx, "id")
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT LOWER(subject), COUNT(DISTINCT predicate) as pred_count
 		 FROM facts
-		 WHERE superseded_by IS NULL AND confidence > 0 AND subject != ''
+		 WHERE superseded_by IS NULL AND confidence > 0 AND subject != ''`+nsClause+`
 		 GROUP BY LOWER(subject)
 		 HAVING COUNT(DISTINCT predicate) >= 2
 		 ORDER BY pred_count DESC
 		 LIMIT 50`,
+		nsArgs...,
 	)
 	if err != nil {
 		return fmt.Errorf("querying subject clusters: %w", err)
@@ -179,9 +182,9 @@ func (s *SQLiteStore) inferFromSubjectClustering(ctx context.Context, opts Infer
 		// Get facts for this subject
 		factRows, err := s.db.QueryContext(ctx,
 			`SELECT id FROM facts
-			 WHERE LOWER(subject) = ? AND superseded_by IS NULL AND confidence > 0
+			 WHERE LOWER(subject) = ? AND superseded_by IS NULL AND confidence > 0`+nsClause+`
 			 ORDER BY created_at DESC LIMIT 10`,
-			subject,
+			append([]interface{}{subject}, nsArgs...)...,
 		)
 		if err != nil {
 			continue
@@ -251,14 +254,16 @@ func (s *SQLiteStore) inferFromSubjectClustering(ctx context.Context, opts Infer
 // Facts with same subject+predicate but different objects where one is newer.
 func (s *SQLiteStore) inferFromSupersession(ctx context.Context, opts InferenceOpts, result *InferenceResult) error {
 	// Find subject+predicate pairs with multiple active facts
+	nsClause, nsArgs := s.factNamespaceFilter(ctx, "id")
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT LOWER(subject), LOWER(predicate), COUNT(*) as cnt
 		 FROM facts
-		 WHERE superseded_by IS NULL AND confidence > 0 AND subject != ''
+		 WHERE superseded_by IS NULL AND confidence > 0 AND subject != ''`+nsClause+`
 		 GROUP BY LOWER(subject), LOWER(predicate)
 		 HAVING COUNT(DISTINCT object) > 1
 		 ORDER BY cnt DESC
 		 LIMIT 50`,
+		nsArgs...,
 	)
 	if err != nil {
 		return fmt.Errorf("querying supersession candidates: %w", err)
@@ -287,9 +292,9 @@ func (s *SQLiteStore) inferFromSupersession(ctx context.Context, opts InferenceO
 		factRows, err := s.db.QueryContext(ctx,
 			`SELECT id, object, created_at FROM facts
 			 WHERE LOWER(subject) = ? AND LOWER(predicate) = ?
-			   AND superseded_by IS NULL AND confidence > 0
+			   AND superseded_by IS NULL AND confidence > 0`+nsClause+`
 			 ORDER BY created_at DESC LIMIT 5`,
-			p.subject, p.predicate,
+			append([]interface{}{p.subject, p.predicate}, nsArgs...)...,
 		)
 		if err != nil {
 			continue
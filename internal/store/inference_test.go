@@ -222,3 +222,39 @@ func TestInferenceDryRunMaxEdges(t *testing.T) {
 		t.Fatalf("Dry-run should respect MaxEdges cap: got %d proposals", len(result.Proposals))
 	}
 }
+
+func TestInferenceStaysInNamespace(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+	work := WithNamespace(ctx, "work")
+
+	defMem, _ := s.AddMemory(ctx, &Memory{Content: "default", SourceFile: "d.md"})
+	workMem, _ := s.AddMemory(work, &Memory{Content: "work", SourceFile: "w.md"})
+
+	// Same subject (and subject+predicate) in both namespaces, plus a
+	// co-occurrence pair that spans them.
+	d1, _ := s.AddFact(ctx, &Fact{MemoryID: defMem, Subject: "cortex", Predicate: "language", Object: "Go", FactType: "kv"})
+	w1, _ := s.AddFact(ctx, &Fact{MemoryID: workMem, Subject: "cortex", Predicate: "database", Object: "SQLite", FactType: "kv"})
+	w2, _ := s.AddFact(ctx, &Fact{MemoryID: workMem, Subject: "cortex", Predicate: "language", Object: "Rust", FactType: "kv"})
+	for i := 0; i < 7; i++ {
+		s.RecordCooccurrence(ctx, d1, w1)
+	}
+
+	opts := DefaultInferenceOpts()
+	opts.DryRun = true
+	result, err := s.RunInference(work, opts)
+	if err != nil {
+		t.Fatalf("RunInference: %v", err)
+	}
+	if len(result.Proposals) == 0 {
+		t.Fatal("expected proposals between the work facts")
+	}
+	for _, p := range result.Proposals {
+		if p.SourceFactID == d1 || p.TargetFactID == d1 {
+			t.Fatalf("work inference proposed %+v touching default fact %d", p, d1)
+		}
+		if (p.SourceFactID != w1 && p.SourceFactID != w2) || (p.TargetFactID != w1 && p.TargetFactID != w2) {
+			t.Fatalf("unexpected proposal %+v", p)
+		}
+	}
+}
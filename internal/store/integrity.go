@@ -0,0 +1,224 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/ann"
+)
+
+// IntegrityProblem is one failed integrity check.
+type IntegrityProblem struct {
+	Check    string        `json:"check"` // quick_check | fts | hnsw | foreign_keys
+	Severity AlertSeverity `json:"severity"`
+	Detail   string        `json:"detail"`
+}
+
+// IntegrityReport is the outcome of VerifyIntegrity.
+type IntegrityReport struct {
+	QuickCheck   string             `json:"quick_check"`
+	Memories     int                `json:"memories"`      // live (not soft-deleted) memories
+	FTSRows      int                `json:"fts_rows"`      // documents in memories_fts
+	Embeddings   int                `json:"embeddings"`    // embeddings of live memories
+	HNSWVectors  int                `json:"hnsw_vectors"`  // -1 when no persisted index was checked
+	HNSWDangling int                `json:"hnsw_dangling"` // index vectors with no embedding row
+	Orphans      map[string]int     `json:"orphans,omitempty"`
+	Problems     []IntegrityProblem `json:"problems,omitempty"`
+	CheckedAt    time.Time          `json:"checked_at"`
+	ElapsedMs    int64              `json:"elapsed_ms"`
+}
+
+// OK reports whether every check passed.
+func (r *IntegrityReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// Severity is the most urgent problem severity, or "" when OK.
+func (r *IntegrityReport) Severity() AlertSeverity {
+	var worst AlertSeverity
+	for _, p := range r.Problems {
+		if p.Severity == AlertSeverityCritical {
+			return AlertSeverityCritical
+		}
+		worst = p.Severity
+	}
+	return worst
+}
+
+func (r *IntegrityReport) problem(check string, severity AlertSeverity, format string, args ...interface{}) {
+	r.Problems = append(r.Problems, IntegrityProblem{Check: check, Severity: severity, Detail: fmt.Sprintf(format, args...)})
+}
+
+// VerifyIntegrity runs PRAGMA quick_check, compares the memories_fts index
+// and the persisted HNSW index at hnswPath (skipped when empty or missing)
+// against their base tables, and looks for foreign-key orphans. Corruption
+// and FTS drift are critical; an HNSW index that disagrees with the
+// embeddings table is a warning because it is rebuilt on the next embed
+// pass. Only a failure to run the checks returns an error.
+func (s *SQLiteStore) VerifyIntegrity(ctx context.Context, hnswPath string) (*IntegrityReport, error) {
+	start := time.Now()
+	r := &IntegrityReport{CheckedAt: start.UTC(), HNSWVectors: -1}
+
+	rows, err := s.db.QueryContext(ctx, "PRAGMA quick_check")
+	if err != nil {
+		return nil, fmt.Errorf("running quick_check: %w", err)
+	}
+	var messages []string
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning quick_check: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("running quick_check: %w", err)
+	}
+	r.QuickCheck = strings.Join(messages, "; ")
+	if r.QuickCheck != "ok" {
+		r.problem("quick_check", AlertSeverityCritical, "PRAGMA quick_check: %s", truncate(r.QuickCheck, 300))
+	}
+
+	// memories_fts only indexes live memories (the update trigger drops
+	// soft-deleted rows), and its docsize shadow table holds one row per
+	// indexed document.
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM memories WHERE deleted_at IS NULL`).Scan(&r.Memories); err != nil {
+		return nil, fmt.Errorf("counting memories: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM memories_fts_docsize`).Scan(&r.FTSRows); err != nil {
+		return nil, fmt.Errorf("counting memories_fts rows: %w", err)
+	}
+	if r.FTSRows != r.Memories {
+		r.problem("fts", AlertSeverityCritical,
+			"memories_fts has %d rows but %d memories are live; keyword search is out of sync", r.FTSRows, r.Memories)
+	}
+
+	if err := s.verifyHNSW(ctx, r, hnswPath); err != nil {
+		return nil, err
+	}
+
+	orphans, err := s.foreignKeyOrphans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(orphans) > 0 {
+		r.Orphans = orphans
+		tables := make([]string, 0, len(orphans))
+		for table, n := range orphans {
+			tables = append(tables, fmt.Sprintf("%s=%d", table, n))
+		}
+		sort.Strings(tables)
+		r.problem("foreign_keys", AlertSeverityCritical, "rows referencing missing parents: %s", strings.Join(tables, ", "))
+	}
+
+	r.ElapsedMs = time.Since(start).Milliseconds()
+	return r, nil
+}
+
+func (s *SQLiteStore) verifyHNSW(ctx context.Context, r *IntegrityReport, hnswPath string) error {
+	ids, err := s.ListMemoryIDsWithEmbeddings(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("listing embeddings: %w", err)
+	}
+	r.Embeddings = len(ids)
+	if hnswPath == "" {
+		return nil
+	}
+	if _, err := os.Stat(hnswPath); err != nil {
+		return nil
+	}
+	idx, err := ann.LoadMmap(hnswPath)
+	if err != nil {
+		r.problem("hnsw", AlertSeverityWarning, "persisted HNSW index is unreadable: %v", err)
+		return nil
+	}
+	defer idx.Close()
+
+	r.HNSWVectors = idx.Len()
+	present := 0
+	for _, id := range ids {
+		if idx.Has(id) {
+			present++
+		}
+	}
+	r.HNSWDangling = r.HNSWVectors - present
+	if r.HNSWDangling > 0 || present != r.Embeddings {
+		r.problem("hnsw", AlertSeverityWarning,
+			"HNSW index has %d vectors (%d without an embedding row) for %d embeddings; it is rebuilt on the next embed pass",
+			r.HNSWVectors, r.HNSWDangling, r.Embeddings)
+	}
+	return nil
+}
+
+// foreignKeyOrphans counts PRAGMA foreign_key_check violations per table.
+func (s *SQLiteStore) foreignKeyOrphans(ctx context.Context) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("running foreign_key_check: %w", err)
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	orphans := map[string]int{}
+	dest := make([]interface{}, len(cols))
+	for i := range dest {
+		dest[i] = new(interface{})
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scanning foreign_key_check: %w", err)
+		}
+		table := fmt.Sprint(*dest[0].(*interface{}))
+		orphans[table]++
+	}
+	return orphans, rows.Err()
+}
+
+// RecordIntegrityAlert raises an alert for a failed report unless an
+// unacknowledged integrity alert for the same set of failed checks is already
+// open, so a nightly check does not repeat itself while counts drift.
+// Returns whether an alert was created.
+func (s *SQLiteStore) RecordIntegrityAlert(ctx context.Context, r *IntegrityReport) (bool, error) {
+	if r.OK() {
+		return false, nil
+	}
+	checks := make([]string, 0, len(r.Problems))
+	for _, p := range r.Problems {
+		checks = append(checks, p.Check)
+	}
+	prefix := fmt.Sprintf("Database integrity check failed (%s)", strings.Join(checks, ", "))
+
+	var existing int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM alerts
+		 WHERE alert_type = ? AND acknowledged = 0 AND substr(message, 1, length(?)) = ?`,
+		AlertTypeIntegrity, prefix, prefix,
+	).Scan(&existing); err != nil {
+		return false, fmt.Errorf("checking existing integrity alerts: %w", err)
+	}
+	if existing > 0 {
+		return false, nil
+	}
+
+	details, _ := json.Marshal(r)
+	if err := s.CreateAlert(ctx, &Alert{
+		AlertType: AlertTypeIntegrity,
+		Severity:  r.Severity(),
+		Message:   prefix + ": " + r.Problems[0].Detail,
+		Details:   string(details),
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
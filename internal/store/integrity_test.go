@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/ann"
+)
+
+func TestVerifyIntegrity_CleanStore(t *testing.T) {
+	s := newTestStore(t)
+	ss := s.(*SQLiteStore)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "healthy memory", SourceFile: "ok.md"})
+	if err := s.AddEmbedding(ctx, memID, []float32{1, 0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	deleted, _ := s.AddMemory(ctx, &Memory{Content: "gone memory", SourceFile: "ok.md"})
+	if err := s.DeleteMemory(ctx, deleted); err != nil {
+		t.Fatal(err)
+	}
+	idx := ann.New(3)
+	idx.Insert(memID, []float32{1, 0, 0})
+	hnswPath := filepath.Join(t.TempDir(), "hnsw.idx")
+	if err := idx.Save(hnswPath); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := ss.VerifyIntegrity(ctx, hnswPath)
+	if err != nil {
+		t.Fatalf("VerifyIntegrity: %v", err)
+	}
+	if !r.OK() || r.QuickCheck != "ok" || r.Memories != 1 || r.FTSRows != 1 || r.HNSWVectors != 1 {
+		t.Fatalf("expected clean report, got %+v", r)
+	}
+	created, err := ss.RecordIntegrityAlert(ctx, r)
+	if err != nil || created {
+		t.Fatalf("clean report should not alert: created=%v err=%v", created, err)
+	}
+}
+
+func TestVerifyIntegrity_DetectsDriftAndAlertsOnce(t *testing.T) {
+	s := newTestStore(t)
+	ss := s.(*SQLiteStore)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "indexed memory", SourceFile: "drift.md"})
+	// Drop the row from the FTS index behind the triggers' back.
+	if _, err := ss.db.ExecContext(ctx,
+		`INSERT INTO memories_fts(memories_fts, rowid, content, source_file, source_section)
+		 VALUES('delete', ?, 'indexed memory', 'drift.md', '')`, memID); err != nil {
+		t.Fatal(err)
+	}
+	// Orphan a fact the way a write with foreign keys off would.
+	if _, err := ss.db.ExecContext(ctx, `PRAGMA foreign_keys=OFF`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ss.db.ExecContext(ctx,
+		`INSERT INTO facts (memory_id, subject, predicate, object, fact_type, confidence) VALUES (99999, 'x', 'y', 'z', 'kv', 0.5)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ss.db.ExecContext(ctx, `PRAGMA foreign_keys=ON`); err != nil {
+		t.Fatal(err)
+	}
+	// An index holding a vector with no embedding row.
+	idx := ann.New(2)
+	idx.Insert(4242, []float32{0, 1})
+	hnswPath := filepath.Join(t.TempDir(), "hnsw.idx")
+	if err := idx.Save(hnswPath); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := ss.VerifyIntegrity(ctx, hnswPath)
+	if err != nil {
+		t.Fatalf("VerifyIntegrity: %v", err)
+	}
+	checks := map[string]IntegrityProblem{}
+	for _, p := range r.Problems {
+		checks[p.Check] = p
+	}
+	if _, ok := checks["fts"]; !ok || r.FTSRows != 0 || r.Memories != 1 {
+		t.Fatalf("expected fts drift, got %+v", r)
+	}
+	if r.Orphans["facts"] != 1 || checks["foreign_keys"].Severity != AlertSeverityCritical {
+		t.Fatalf("expected one orphaned fact, got %+v", r)
+	}
+	if p, ok := checks["hnsw"]; !ok || p.Severity != AlertSeverityWarning || r.HNSWDangling != 1 {
+		t.Fatalf("expected dangling hnsw warning, got %+v", r)
+	}
+	if r.Severity() != AlertSeverityCritical {
+		t.Fatalf("severity = %q, want critical", r.Severity())
+	}
+
+	created, err := ss.RecordIntegrityAlert(ctx, r)
+	if err != nil || !created {
+		t.Fatalf("first failing report should alert: created=%v err=%v", created, err)
+	}
+	r.Problems[0].Detail = "counts moved overnight"
+	if created, err := ss.RecordIntegrityAlert(ctx, r); err != nil || created {
+		t.Fatalf("open alert for the same checks should suppress a repeat: created=%v err=%v", created, err)
+	}
+	alerts, err := ss.ListAlerts(ctx, AlertFilter{Type: AlertTypeIntegrity})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || alerts[0].Severity != AlertSeverityCritical || !strings.Contains(alerts[0].Message, "fts") {
+		t.Fatalf("unexpected integrity alerts: %+v", alerts)
+	}
+}
@@ -0,0 +1,343 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Background job lifecycle constants.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobDone      = "done"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// Job is one unit of background work (extraction, embedding, cluster
+// rebuild, ...). Jobs are persisted so any process with a job runner — the
+// daemon, `cortex mcp`, or `cortex jobs start --run` — can pick them up, and
+// so the CLI can inspect and cancel them.
+type Job struct {
+	ID              int64      `json:"id"`
+	Kind            string     `json:"kind"`
+	Status          string     `json:"status"`
+	Params          string     `json:"params,omitempty"` // JSON, kind-specific
+	Progress        int        `json:"progress"`
+	Total           int        `json:"total"`
+	Result          string     `json:"result,omitempty"` // JSON, kind-specific
+	Error           string     `json:"error,omitempty"`
+	CancelRequested bool       `json:"cancel_requested,omitempty"`
+	CreatedBy       string     `json:"created_by,omitempty"` // "cli", "mcp", ...
+	CreatedAt       time.Time  `json:"created_at"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
+
+// Finished reports whether the job reached a terminal state.
+func (j *Job) Finished() bool {
+	switch j.Status {
+	case JobDone, JobFailed, JobCancelled:
+		return true
+	}
+	return false
+}
+
+// JobListOpts filters ListJobs.
+type JobListOpts struct {
+	// Status is a job status, "active" (queued or running), or "" for all.
+	Status string
+	// Kind keeps only jobs of this kind ("" = all).
+	Kind string
+	// Limit caps the result count (0 = no limit).
+	Limit int
+}
+
+const jobColumns = `id, kind, status, params, progress, total, result, error, cancel_requested,
+	created_by, created_at, started_at, updated_at, finished_at`
+
+func (s *SQLiteStore) migrateJobsTable() error {
+	done, err := s.isMetaFlagEnabled("jobs_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS jobs (
+			id               INTEGER PRIMARY KEY AUTOINCREMENT,
+			kind             TEXT NOT NULL,
+			status           TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'done', 'failed', 'cancelled')),
+			params           TEXT NOT NULL DEFAULT '',
+			progress         INTEGER NOT NULL DEFAULT 0,
+			total            INTEGER NOT NULL DEFAULT 0,
+			result           TEXT NOT NULL DEFAULT '',
+			error            TEXT NOT NULL DEFAULT '',
+			cancel_requested INTEGER NOT NULL DEFAULT 0,
+			created_by       TEXT NOT NULL DEFAULT '',
+			created_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			started_at       DATETIME,
+			updated_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			finished_at      DATETIME
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status, id)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('jobs_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating jobs table: %w", err)
+		}
+	}
+	return nil
+}
+
+// EnqueueJob stores a new queued job and returns its id.
+func (s *SQLiteStore) EnqueueJob(ctx context.Context, j *Job) (int64, error) {
+	if j == nil || strings.TrimSpace(j.Kind) == "" {
+		return 0, fmt.Errorf("job kind is required")
+	}
+	now := time.Now().UTC()
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO jobs (kind, status, params, created_by, created_at, updated_at) VALUES (?, 'queued', ?, ?, ?, ?)`,
+		strings.TrimSpace(j.Kind), j.Params, j.CreatedBy, now, now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("enqueueing job: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("getting job id: %w", err)
+	}
+	j.ID = id
+	j.Kind = strings.TrimSpace(j.Kind)
+	j.Status = JobQueued
+	j.CreatedAt = now
+	j.UpdatedAt = now
+	return id, nil
+}
+
+// GetJob returns one job by id, or (nil, nil) if not found.
+func (s *SQLiteStore) GetJob(ctx context.Context, id int64) (*Job, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = ?`, id)
+	j, err := scanJobRow(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting job %d: %w", id, err)
+	}
+	return j, nil
+}
+
+// ListJobs returns jobs matching opts, newest first.
+func (s *SQLiteStore) ListJobs(ctx context.Context, opts JobListOpts) ([]*Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs`
+	var where []string
+	var args []any
+
+	switch status := strings.TrimSpace(strings.ToLower(opts.Status)); status {
+	case "", "all":
+	case "active":
+		where = append(where, "status IN ('queued', 'running')")
+	case JobQueued, JobRunning, JobDone, JobFailed, JobCancelled:
+		where = append(where, "status = ?")
+		args = append(args, status)
+	default:
+		return nil, fmt.Errorf("invalid status filter %q (valid: queued, running, done, failed, cancelled, active, all)", opts.Status)
+	}
+	if kind := strings.TrimSpace(opts.Kind); kind != "" {
+		where = append(where, "kind = ?")
+		args = append(args, kind)
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC"
+	if opts.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, opts.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Job
+	for rows.Next() {
+		j, err := scanJobRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning job row: %w", err)
+		}
+		out = append(out, j)
+	}
+	return out, rows.Err()
+}
+
+// ClaimNextJob marks the oldest queued job of one of the given kinds as
+// running and returns it, or (nil, nil) when there is none. The claim is a
+// conditional update, so two runners never start the same job.
+func (s *SQLiteStore) ClaimNextJob(ctx context.Context, kinds []string) (*Job, error) {
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(kinds)), ",")
+	args := make([]any, 0, len(kinds))
+	for _, k := range kinds {
+		args = append(args, k)
+	}
+	for {
+		var id int64
+		err := s.db.QueryRowContext(ctx,
+			`SELECT id FROM jobs WHERE status = 'queued' AND kind IN (`+placeholders+`) ORDER BY id LIMIT 1`, args...,
+		).Scan(&id)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("finding queued job: %w", err)
+		}
+		j, err := s.ClaimJob(ctx, id)
+		if err != nil || j != nil {
+			return j, err
+		}
+		// Another runner claimed it first; look again.
+	}
+}
+
+// ClaimJob marks one queued job as running. It returns (nil, nil) if the job
+// is no longer queued.
+func (s *SQLiteStore) ClaimJob(ctx context.Context, id int64) (*Job, error) {
+	now := time.Now().UTC()
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE jobs SET status = 'running', started_at = ?, updated_at = ? WHERE id = ? AND status = 'queued'`,
+		now, now, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("claiming job %d: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, nil
+	}
+	return s.GetJob(ctx, id)
+}
+
+// UpdateJobProgress records progress on a running job and reports whether
+// cancellation has been requested.
+func (s *SQLiteStore) UpdateJobProgress(ctx context.Context, id int64, progress, total int) (cancelRequested bool, err error) {
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE jobs SET progress = ?, total = ?, updated_at = ? WHERE id = ? AND status = 'running'`,
+		progress, total, time.Now().UTC(), id,
+	); err != nil {
+		return false, fmt.Errorf("updating job %d: %w", id, err)
+	}
+	return s.JobCancelRequested(ctx, id)
+}
+
+// JobCancelRequested reports whether cancellation was requested for a job.
+func (s *SQLiteStore) JobCancelRequested(ctx context.Context, id int64) (bool, error) {
+	var flag int
+	if err := s.db.QueryRowContext(ctx, `SELECT cancel_requested FROM jobs WHERE id = ?`, id).Scan(&flag); err != nil {
+		return false, fmt.Errorf("reading job %d: %w", id, err)
+	}
+	return flag != 0, nil
+}
+
+// FinishJob moves a running job to a terminal status with its result or
+// error message.
+func (s *SQLiteStore) FinishJob(ctx context.Context, id int64, status, result, errMsg string) error {
+	switch status {
+	case JobDone, JobFailed, JobCancelled:
+	default:
+		return fmt.Errorf("invalid terminal job status %q", status)
+	}
+	now := time.Now().UTC()
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE jobs SET status = ?, result = ?, error = ?, updated_at = ?, finished_at = ? WHERE id = ? AND status = 'running'`,
+		status, result, errMsg, now, now, id,
+	); err != nil {
+		return fmt.Errorf("finishing job %d: %w", id, err)
+	}
+	return nil
+}
+
+// CancelJob cancels a queued job immediately, or flags a running one so its
+// runner stops it. It returns the job as it stands afterwards.
+func (s *SQLiteStore) CancelJob(ctx context.Context, id int64) (*Job, error) {
+	j, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if j == nil {
+		return nil, fmt.Errorf("job %d not found", id)
+	}
+	if j.Finished() {
+		return nil, fmt.Errorf("job %d is already %s", id, j.Status)
+	}
+
+	now := time.Now().UTC()
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE jobs SET cancel_requested = 1, updated_at = ? WHERE id = ? AND status IN ('queued', 'running')`,
+		now, id,
+	); err != nil {
+		return nil, fmt.Errorf("cancelling job %d: %w", id, err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE jobs SET status = 'cancelled', finished_at = ? WHERE id = ? AND status = 'queued'`,
+		now, id,
+	); err != nil {
+		return nil, fmt.Errorf("cancelling job %d: %w", id, err)
+	}
+	return s.GetJob(ctx, id)
+}
+
+// RequeueStaleJobs returns running jobs not updated since before to the
+// queue, e.g. after the process running them died. It returns how many were
+// requeued. Jobs already flagged for cancellation are cancelled instead.
+func (s *SQLiteStore) RequeueStaleJobs(ctx context.Context, before time.Time) (int, error) {
+	now := time.Now().UTC()
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE jobs SET status = 'cancelled', updated_at = ?, finished_at = ?
+		 WHERE status = 'running' AND cancel_requested = 1 AND updated_at < ?`,
+		now, now, before.UTC(),
+	); err != nil {
+		return 0, fmt.Errorf("cancelling stale jobs: %w", err)
+	}
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE jobs SET status = 'queued', started_at = NULL, updated_at = ?
+		 WHERE status = 'running' AND updated_at < ?`,
+		now, before.UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("requeueing stale jobs: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+func scanJobRow(row interface{ Scan(dest ...any) error }) (*Job, error) {
+	var j Job
+	var cancel int
+	var startedAt, finishedAt sql.NullTime
+	if err := row.Scan(&j.ID, &j.Kind, &j.Status, &j.Params, &j.Progress, &j.Total, &j.Result, &j.Error, &cancel,
+		&j.CreatedBy, &j.CreatedAt, &startedAt, &j.UpdatedAt, &finishedAt); err != nil {
+		return nil, err
+	}
+	j.CancelRequested = cancel != 0
+	if startedAt.Valid {
+		t := startedAt.Time
+		j.StartedAt = &t
+	}
+	if finishedAt.Valid {
+		t := finishedAt.Time
+		j.FinishedAt = &t
+	}
+	return &j, nil
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestJobs_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+
+	if err := s.migrateJobsTable(); err != nil {
+		t.Fatalf("second migrateJobsTable call failed: %v", err)
+	}
+	if _, err := s.EnqueueJob(ctx, &Job{}); err == nil {
+		t.Fatal("expected error for missing kind")
+	}
+
+	first, err := s.EnqueueJob(ctx, &Job{Kind: "extract", Params: `{"memory_ids":[1]}`, CreatedBy: "cli"})
+	if err != nil {
+		t.Fatalf("EnqueueJob: %v", err)
+	}
+	second, _ := s.EnqueueJob(ctx, &Job{Kind: "embed"})
+
+	if j, err := s.ClaimNextJob(ctx, []string{"cluster_rebuild"}); err != nil || j != nil {
+		t.Fatalf("no job of an unhandled kind should be claimed, got %+v (%v)", j, err)
+	}
+	j, err := s.ClaimNextJob(ctx, []string{"extract", "embed"})
+	if err != nil || j == nil || j.ID != first || j.Status != JobRunning || j.StartedAt == nil {
+		t.Fatalf("expected to claim the oldest job, got %+v (%v)", j, err)
+	}
+	if again, _ := s.ClaimJob(ctx, first); again != nil {
+		t.Fatal("a running job must not be claimed twice")
+	}
+
+	if cancel, err := s.UpdateJobProgress(ctx, first, 1, 4); err != nil || cancel {
+		t.Fatalf("UpdateJobProgress = %v, %v", cancel, err)
+	}
+	if _, err := s.CancelJob(ctx, first); err != nil {
+		t.Fatalf("CancelJob(running): %v", err)
+	}
+	if cancel, _ := s.UpdateJobProgress(ctx, first, 2, 4); !cancel {
+		t.Fatal("progress update should report the cancel request")
+	}
+	if err := s.FinishJob(ctx, first, JobCancelled, `{"processed":2}`, ""); err != nil {
+		t.Fatalf("FinishJob: %v", err)
+	}
+	got, _ := s.GetJob(ctx, first)
+	if got.Status != JobCancelled || got.Progress != 2 || got.Total != 4 || got.FinishedAt == nil || got.CreatedBy != "cli" {
+		t.Fatalf("unexpected finished job: %+v", got)
+	}
+	if _, err := s.CancelJob(ctx, first); err == nil {
+		t.Fatal("cancelling a finished job should fail")
+	}
+
+	queued, err := s.CancelJob(ctx, second)
+	if err != nil || queued.Status != JobCancelled {
+		t.Fatalf("queued job should be cancelled immediately, got %+v (%v)", queued, err)
+	}
+
+	active, _ := s.ListJobs(ctx, JobListOpts{Status: "active"})
+	all, _ := s.ListJobs(ctx, JobListOpts{})
+	if len(active) != 0 || len(all) != 2 || all[0].ID != second {
+		t.Fatalf("unexpected listings: active=%d all=%+v", len(active), all)
+	}
+	if _, err := s.ListJobs(ctx, JobListOpts{Status: "bogus"}); err == nil {
+		t.Fatal("expected error for invalid status filter")
+	}
+	if missing, err := s.GetJob(ctx, 999); err != nil || missing != nil {
+		t.Fatalf("GetJob(missing) = %+v, %v", missing, err)
+	}
+}
+
+func TestJobs_RequeueStale(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+
+	id, _ := s.EnqueueJob(ctx, &Job{Kind: "embed"})
+	if _, err := s.ClaimJob(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.RequeueStaleJobs(ctx, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Fatalf("a fresh running job is not stale: n=%d err=%v", n, err)
+	}
+	if n, err := s.RequeueStaleJobs(ctx, time.Now().Add(time.Minute)); err != nil || n != 1 {
+		t.Fatalf("expected one requeued job, got n=%d err=%v", n, err)
+	}
+	j, _ := s.GetJob(ctx, id)
+	if j.Status != JobQueued || j.StartedAt != nil {
+		t.Fatalf("unexpected requeued job: %+v", j)
+	}
+}
@@ -32,11 +32,19 @@ func (s *SQLiteStore) AddMemory(ctx context.Context, m *Memory) (int64, error) {
 	}
 
 	contentOnlyHash := HashContentOnly(m.Content)
+	m.Namespace = s.writeNamespace(ctx, m)
+	m.ContentHash = namespacedHash(m.Namespace, m.ContentHash)
 
-	result, err := s.db.ExecContext(ctx,
-		`INSERT INTO memories (content, source_file, source_line, source_section, content_hash, content_only_hash, project, memory_class, metadata, imported_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		m.Content, m.SourceFile, m.SourceLine, m.SourceSection, m.ContentHash, contentOnlyHash, m.Project, m.MemoryClass, metadataArg, now, now,
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO memories (content, source_file, source_line, source_section, content_hash, content_only_hash, project, memory_class, metadata, namespace, imported_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.Content, m.SourceFile, m.SourceLine, m.SourceSection, m.ContentHash, contentOnlyHash, m.Project, m.MemoryClass, metadataArg, m.Namespace, now, now,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("inserting memory: %w", err)
@@ -46,6 +54,15 @@ func (s *SQLiteStore) AddMemory(ctx context.Context, m *Memory) (int64, error) {
 	if err != nil {
 		return 0, fmt.Errorf("getting last insert id: %w", err)
 	}
+	if err := insertMemoryAnchor(ctx, tx, id, m.Anchor); err != nil {
+		return 0, err
+	}
+	if err := linkMemoryDocument(ctx, tx, id, m.SourceFile, now); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing memory: %w", err)
+	}
 
 	m.ID = id
 	m.ImportedAt = now
@@ -61,10 +78,10 @@ func (s *SQLiteStore) GetMemory(ctx context.Context, id int64) (*Memory, error)
 	var memoryClass sql.NullString
 
 	err := s.db.QueryRowContext(ctx,
-		`SELECT id, content, source_file, source_line, source_section, content_hash, project, memory_class, metadata, imported_at, updated_at, deleted_at
+		`SELECT id, content, source_file, source_line, source_section, content_hash, project, memory_class, metadata, namespace, imported_at, updated_at, deleted_at
 		 FROM memories WHERE id = ?`, id,
 	).Scan(&m.ID, &m.Content, &m.SourceFile, &m.SourceLine, &m.SourceSection,
-		&m.ContentHash, &m.Project, &memoryClass, &metadataStr, &m.ImportedAt, &m.UpdatedAt, &deletedAt)
+		&m.ContentHash, &m.Project, &memoryClass, &metadataStr, &m.Namespace, &m.ImportedAt, &m.UpdatedAt, &deletedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -89,17 +106,81 @@ func (s *SQLiteStore) ListMemories(ctx context.Context, opts ListOpts) ([]*Memor
 		opts.Limit = 100
 	}
 
-	query := `SELECT id, content, source_file, source_line, source_section, content_hash, project, memory_class, metadata, imported_at, updated_at
-			  FROM memories WHERE deleted_at IS NULL`
-	args := []interface{}{}
+	filter, args, err := s.memoryListFilter(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT id, content, source_file, source_line, source_section, content_hash, project, memory_class, metadata, namespace, imported_at, updated_at
+			  FROM memories WHERE deleted_at IS NULL` + filter
+
+	if opts.Cursor != "" {
+		afterID, err := DecodeCursor(opts.Cursor, memoriesOrdering)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.cursorRowExists(ctx, "memories", afterID); err != nil {
+			return nil, err
+		}
+		query += " AND (imported_at, id) < (SELECT imported_at, id FROM memories WHERE id = ?)"
+		args = append(args, afterID)
+	}
+
+	// id breaks ties so keyset cursors see a total order.
+	orderBy := "imported_at DESC, id DESC"
+	query += fmt.Sprintf(" ORDER BY %s LIMIT ? OFFSET ?", orderBy)
+	args = append(args, opts.Limit, opts.Offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing memories: %w", err)
+	}
+	defer rows.Close()
+
+	var memories []*Memory
+	for rows.Next() {
+		m := &Memory{}
+		var metadataStr sql.NullString
+		var memClass sql.NullString
+		if err := rows.Scan(&m.ID, &m.Content, &m.SourceFile, &m.SourceLine,
+			&m.SourceSection, &m.ContentHash, &m.Project, &memClass, &metadataStr, &m.Namespace, &m.ImportedAt, &m.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning memory row: %w", err)
+		}
+		m.MemoryClass = memClass.String
+		m.Metadata = unmarshalMetadata(metadataStr)
+		memories = append(memories, m)
+	}
+	return memories, rows.Err()
+}
+
+// CountMemories returns how many memories match opts' filters, ignoring
+// Limit, Offset, and Cursor. Used for totals alongside a page.
+func (s *SQLiteStore) CountMemories(ctx context.Context, opts ListOpts) (int, error) {
+	filter, args, err := s.memoryListFilter(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM memories WHERE deleted_at IS NULL"+filter, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting memories: %w", err)
+	}
+	return n, nil
+}
+
+// memoryListFilter builds the " AND ..." clauses shared by ListMemories and
+// CountMemories, starting with ctx's namespace.
+func (s *SQLiteStore) memoryListFilter(ctx context.Context, opts ListOpts) (string, []interface{}, error) {
+	filter, args := s.namespaceFilter(ctx, "namespace")
+	if args == nil {
+		args = []interface{}{}
+	}
 
 	if opts.SourceFile != "" {
-		query += " AND source_file = ?"
+		filter += " AND source_file = ?"
 		args = append(args, opts.SourceFile)
 	}
 
 	if opts.Project != "" {
-		query += " AND project = ?"
+		filter += " AND project = ?"
 		args = append(args, opts.Project)
 	}
 
@@ -111,61 +192,35 @@ func (s *SQLiteStore) ListMemories(ctx context.Context, opts ListOpts) ([]*Memor
 				continue
 			}
 			if !IsValidMemoryClass(normalized) {
-				return nil, fmt.Errorf("invalid memory class %q", c)
+				return "", nil, fmt.Errorf("invalid memory class %q", c)
 			}
 			placeholders = append(placeholders, "?")
 			args = append(args, normalized)
 		}
 		if len(placeholders) > 0 {
-			query += " AND memory_class IN (" + strings.Join(placeholders, ",") + ")"
+			filter += " AND memory_class IN (" + strings.Join(placeholders, ",") + ")"
 		}
 	}
 
 	// Metadata filters (Issue #30)
 	if opts.Agent != "" {
-		query += ` AND json_extract(metadata, '$.agent_id') = ?`
+		filter += ` AND json_extract(metadata, '$.agent_id') = ?`
 		args = append(args, opts.Agent)
 	}
 	if opts.Channel != "" {
-		query += ` AND json_extract(metadata, '$.channel') = ?`
+		filter += ` AND json_extract(metadata, '$.channel') = ?`
 		args = append(args, opts.Channel)
 	}
 	if opts.After != "" {
-		query += " AND imported_at >= ?"
+		filter += " AND imported_at >= ?"
 		args = append(args, opts.After)
 	}
 	if opts.Before != "" {
-		query += " AND imported_at < ?"
+		filter += " AND imported_at < ?"
 		args = append(args, opts.Before+" 23:59:59")
 	}
 
-	orderBy := "imported_at DESC"
-	if opts.SortBy == "date" {
-		orderBy = "imported_at DESC"
-	}
-	query += fmt.Sprintf(" ORDER BY %s LIMIT ? OFFSET ?", orderBy)
-	args = append(args, opts.Limit, opts.Offset)
-
-	rows, err := s.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("listing memories: %w", err)
-	}
-	defer rows.Close()
-
-	var memories []*Memory
-	for rows.Next() {
-		m := &Memory{}
-		var metadataStr sql.NullString
-		var memClass sql.NullString
-		if err := rows.Scan(&m.ID, &m.Content, &m.SourceFile, &m.SourceLine,
-			&m.SourceSection, &m.ContentHash, &m.Project, &memClass, &metadataStr, &m.ImportedAt, &m.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("scanning memory row: %w", err)
-		}
-		m.MemoryClass = memClass.String
-		m.Metadata = unmarshalMetadata(metadataStr)
-		memories = append(memories, m)
-	}
-	return memories, rows.Err()
+	return filter, args, nil
 }
 
 // DeleteMemory soft-deletes a memory by setting deleted_at.
@@ -175,6 +230,9 @@ func (s *SQLiteStore) DeleteMemory(ctx context.Context, id int64) error {
 		"UPDATE memories SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", now, id,
 	)
 	if err != nil {
+		if herr := archiveHoldError(err, HoldKindMemory, id); herr != err {
+			return herr
+		}
 		return fmt.Errorf("deleting memory %d: %w", id, err)
 	}
 
@@ -185,7 +243,8 @@ func (s *SQLiteStore) DeleteMemory(ctx context.Context, id int64) error {
 	if rows == 0 {
 		return fmt.Errorf("memory %d not found or already deleted", id)
 	}
-	return nil
+	_, err = s.repointDerivedFacts(ctx, id)
+	return err
 }
 
 // UpdateMemory updates a memory's content and recomputes its hash.
@@ -198,11 +257,14 @@ func (s *SQLiteStore) UpdateMemory(ctx context.Context, id int64, content string
 	newHash := HashContentOnly(content)
 	now := time.Now().UTC()
 	result, err := s.db.ExecContext(ctx,
-		`UPDATE memories SET content = ?, content_hash = ?, updated_at = ?
+		`UPDATE memories SET content = ?, content_hash = ? || `+namespaceHashSuffixSQL+`, updated_at = ?
 		 WHERE id = ? AND deleted_at IS NULL`,
 		content, newHash, now, id,
 	)
 	if err != nil {
+		if herr := archiveHoldError(err, HoldKindMemory, id); herr != err {
+			return herr
+		}
 		return fmt.Errorf("updating memory %d: %w", id, err)
 	}
 	rows, err := result.RowsAffected()
@@ -266,8 +328,8 @@ func (s *SQLiteStore) insertBatch(ctx context.Context, memories []*Memory) ([]in
 	defer tx.Rollback()
 
 	stmt, err := tx.PrepareContext(ctx,
-		`INSERT INTO memories (content, source_file, source_line, source_section, content_hash, project, memory_class, metadata, imported_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO memories (content, source_file, source_line, source_section, content_hash, project, memory_class, metadata, namespace, imported_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("preparing statement: %w", err)
@@ -281,6 +343,8 @@ func (s *SQLiteStore) insertBatch(ctx context.Context, memories []*Memory) ([]in
 		if m.ContentHash == "" {
 			m.ContentHash = HashMemoryContent(m.Content, m.SourceFile)
 		}
+		m.Namespace = s.writeNamespace(ctx, m)
+		m.ContentHash = namespacedHash(m.Namespace, m.ContentHash)
 		m.MemoryClass = NormalizeMemoryClass(m.MemoryClass)
 		if m.MemoryClass != "" && !IsValidMemoryClass(m.MemoryClass) {
 			return nil, fmt.Errorf("invalid memory class %q", m.MemoryClass)
@@ -291,7 +355,7 @@ func (s *SQLiteStore) insertBatch(ctx context.Context, memories []*Memory) ([]in
 			metadataArg = metadataJSON
 		}
 		result, err := stmt.ExecContext(ctx,
-			m.Content, m.SourceFile, m.SourceLine, m.SourceSection, m.ContentHash, m.Project, m.MemoryClass, metadataArg, now, now,
+			m.Content, m.SourceFile, m.SourceLine, m.SourceSection, m.ContentHash, m.Project, m.MemoryClass, metadataArg, m.Namespace, now, now,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("inserting memory in batch: %w", err)
@@ -300,6 +364,12 @@ func (s *SQLiteStore) insertBatch(ctx context.Context, memories []*Memory) ([]in
 		if err != nil {
 			return nil, fmt.Errorf("getting last insert id: %w", err)
 		}
+		if err := insertMemoryAnchor(ctx, tx, id, m.Anchor); err != nil {
+			return nil, err
+		}
+		if err := linkMemoryDocument(ctx, tx, id, m.SourceFile, now); err != nil {
+			return nil, err
+		}
 		m.ID = id
 		m.ImportedAt = now
 		m.UpdatedAt = now
@@ -312,15 +382,20 @@ func (s *SQLiteStore) insertBatch(ctx context.Context, memories []*Memory) ([]in
 	return ids, nil
 }
 
-// FindByHash looks up a memory by its content hash for deduplication.
+// FindByHash looks up a memory by its content hash for deduplication,
+// within ctx's namespace.
 func (s *SQLiteStore) FindByHash(ctx context.Context, hash string) (*Memory, error) {
+	if ns := s.Namespace(ctx); ns != AllNamespaces {
+		hash = namespacedHash(ns, hash)
+	}
+	nsClause, nsArgs := s.namespaceFilter(ctx, "namespace")
 	m := &Memory{}
 	var memClass sql.NullString
 	err := s.db.QueryRowContext(ctx,
-		`SELECT id, content, source_file, source_line, source_section, content_hash, project, memory_class, imported_at, updated_at
-		 FROM memories WHERE content_hash = ? AND deleted_at IS NULL`, hash,
+		`SELECT id, content, source_file, source_line, source_section, content_hash, project, memory_class, namespace, imported_at, updated_at
+		 FROM memories WHERE content_hash = ? AND deleted_at IS NULL`+nsClause, append([]interface{}{hash}, nsArgs...)...,
 	).Scan(&m.ID, &m.Content, &m.SourceFile, &m.SourceLine, &m.SourceSection,
-		&m.ContentHash, &m.Project, &memClass, &m.ImportedAt, &m.UpdatedAt)
+		&m.ContentHash, &m.Project, &memClass, &m.Namespace, &m.ImportedAt, &m.UpdatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -334,15 +409,16 @@ func (s *SQLiteStore) FindByHash(ctx context.Context, hash string) (*Memory, err
 
 // FindByContentOnly looks up a memory by content-only hash (ignoring source file).
 // This enables cross-source deduplication: same text from two different files is
-// recognized as a duplicate. Returns the first match or nil.
+// recognized as a duplicate. Returns the first match in ctx's namespace or nil.
 func (s *SQLiteStore) FindByContentOnly(ctx context.Context, contentHash string) (*Memory, error) {
 	m := &Memory{}
 	var memClass sql.NullString
+	filter, args := s.namespaceFilter(ctx, "namespace")
 	err := s.db.QueryRowContext(ctx,
-		`SELECT id, content, source_file, source_line, source_section, content_hash, project, memory_class, imported_at, updated_at
-		 FROM memories WHERE content_only_hash = ? AND deleted_at IS NULL LIMIT 1`, contentHash,
+		`SELECT id, content, source_file, source_line, source_section, content_hash, project, memory_class, namespace, imported_at, updated_at
+		 FROM memories WHERE content_only_hash = ? AND deleted_at IS NULL`+filter+` LIMIT 1`, append([]interface{}{contentHash}, args...)...,
 	).Scan(&m.ID, &m.Content, &m.SourceFile, &m.SourceLine, &m.SourceSection,
-		&m.ContentHash, &m.Project, &memClass, &m.ImportedAt, &m.UpdatedAt)
+		&m.ContentHash, &m.Project, &memClass, &m.Namespace, &m.ImportedAt, &m.UpdatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -382,7 +458,63 @@ func (s *SQLiteStore) DeleteMemoriesBySourceFile(ctx context.Context, sourceFile
 		return 0, nil
 	}
 
+	return s.purgeMemories(ctx, ids)
+}
+
+// DeleteMemoriesBySourcePrefix permanently removes memories imported before
+// cutoff whose source is source itself or starts with "source:", with their
+// facts. Connectors use it to expire state snapshots; the rows are purged
+// rather than tombstoned so an identical later snapshot can be re-imported.
+func (s *SQLiteStore) DeleteMemoriesBySourcePrefix(ctx context.Context, source string, cutoff time.Time) (int64, error) {
+	ids, err := s.queryMemoryIDs(ctx,
+		`SELECT id FROM memories
+		 WHERE imported_at < ? AND (source_file = ? OR substr(source_file, 1, ?) = ?)`,
+		cutoff.UTC(), source, len(source)+1, source+":")
+	if err != nil {
+		return 0, fmt.Errorf("querying memories for source %q: %w", source, err)
+	}
+	return s.purgeMemories(ctx, ids)
+}
+
+// ReplaceSourceMemories permanently removes every memory of sourceFile other
+// than keepID, so the newest snapshot of a source is the only one left.
+func (s *SQLiteStore) ReplaceSourceMemories(ctx context.Context, sourceFile string, keepID int64) (int64, error) {
+	ids, err := s.queryMemoryIDs(ctx,
+		`SELECT id FROM memories WHERE source_file = ? AND id != ?`, sourceFile, keepID)
+	if err != nil {
+		return 0, fmt.Errorf("querying memories for source %q: %w", sourceFile, err)
+	}
+	return s.purgeMemories(ctx, ids)
+}
+
+func (s *SQLiteStore) queryMemoryIDs(ctx context.Context, query string, args ...interface{}) ([]int64, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning memory id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// purgeMemories hard-deletes the given memories after their facts. Memories
+// under archive hold, or with a held fact, are kept.
+func (s *SQLiteStore) purgeMemories(ctx context.Context, ids []int64) (int64, error) {
+	ids, err := s.withoutHeldMemories(ctx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("checking archive holds: %w", err)
+	}
 	for _, id := range ids {
+		if _, err := s.repointDerivedFacts(ctx, id); err != nil {
+			return 0, err
+		}
 		if _, err := s.DeleteFactsByMemoryID(ctx, id); err != nil {
 			return 0, fmt.Errorf("deleting facts for memory %d: %w", id, err)
 		}
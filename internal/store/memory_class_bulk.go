@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MemoryClassFilter selects live memories for a bulk class change. Set
+// conditions are ANDed; at least one of SourcePattern, Project, or IDs is
+// required so a typo cannot reclassify the whole store.
+type MemoryClassFilter struct {
+	SourcePattern string  // SQL LIKE pattern on source_file
+	Project       string  // exact project tag
+	IDs           []int64 // e.g. the hits of a search query; a non-nil empty slice matches nothing
+	FromClass     string  // only memories currently in this class
+	Unclassified  bool    // only memories with no class
+}
+
+// MemoryClassPlan is what SetMemoryClassBulk would change. Matched counts
+// every memory the filter selects; Changes and ByClass (keyed by current
+// class, "" = unclassified) count those not already in the target class.
+type MemoryClassPlan struct {
+	Class   string         `json:"class"`
+	Matched int            `json:"matched"`
+	Changes int            `json:"changes"`
+	ByClass map[string]int `json:"by_class"`
+}
+
+// where builds the SQL condition for the filter, without the target class.
+func (f MemoryClassFilter) where() (string, []interface{}, error) {
+	if f.SourcePattern == "" && f.Project == "" && f.IDs == nil {
+		return "", nil, fmt.Errorf("a source pattern, project, or ID list is required")
+	}
+	if f.Unclassified && f.FromClass != "" {
+		return "", nil, fmt.Errorf("unclassified and a from-class are mutually exclusive")
+	}
+	clauses := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	if f.SourcePattern != "" {
+		clauses = append(clauses, "source_file LIKE ?")
+		args = append(args, f.SourcePattern)
+	}
+	if f.Project != "" {
+		clauses = append(clauses, "project = ?")
+		args = append(args, f.Project)
+	}
+	if f.IDs != nil {
+		if len(f.IDs) == 0 {
+			clauses = append(clauses, "0")
+		} else {
+			clauses = append(clauses, "id IN ("+strings.TrimSuffix(strings.Repeat("?,", len(f.IDs)), ",")+")")
+			for _, id := range f.IDs {
+				args = append(args, id)
+			}
+		}
+	}
+	if f.FromClass != "" {
+		clauses = append(clauses, "memory_class = ?")
+		args = append(args, NormalizeMemoryClass(f.FromClass))
+	}
+	if f.Unclassified {
+		clauses = append(clauses, "COALESCE(memory_class, '') = ''")
+	}
+	return strings.Join(clauses, " AND "), args, nil
+}
+
+func validateBulkClass(class string) (string, error) {
+	class = NormalizeMemoryClass(class)
+	if class != "" && !IsValidMemoryClass(class) {
+		return "", fmt.Errorf("invalid memory class %q (valid: %s)", class, strings.Join(AvailableMemoryClasses(), ","))
+	}
+	return class, nil
+}
+
+// PlanMemoryClassChange reports, without writing, how many memories
+// SetMemoryClassBulk(class, f) would change. An empty class clears it.
+func (s *SQLiteStore) PlanMemoryClassChange(ctx context.Context, class string, f MemoryClassFilter) (*MemoryClassPlan, error) {
+	class, err := validateBulkClass(class)
+	if err != nil {
+		return nil, err
+	}
+	where, args, err := f.where()
+	if err != nil {
+		return nil, err
+	}
+	plan := &MemoryClassPlan{Class: class, ByClass: map[string]int{}}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT COALESCE(memory_class, ''), COUNT(*) FROM memories WHERE `+where+` GROUP BY 1`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("planning class change: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var current string
+		var n int
+		if err := rows.Scan(&current, &n); err != nil {
+			return nil, fmt.Errorf("scanning class plan: %w", err)
+		}
+		plan.Matched += n
+		if current != class {
+			plan.ByClass[current] = n
+			plan.Changes += n
+		}
+	}
+	return plan, rows.Err()
+}
+
+// SetMemoryClassBulk sets the class of every memory matching f (an empty
+// class clears it) and records one summary event in memory_events, with
+// description naming the filter. Returns the number of memories changed.
+func (s *SQLiteStore) SetMemoryClassBulk(ctx context.Context, class string, f MemoryClassFilter, description string) (int64, error) {
+	class, err := validateBulkClass(class)
+	if err != nil {
+		return 0, err
+	}
+	where, args, err := f.where()
+	if err != nil {
+		return 0, err
+	}
+	args = append([]interface{}{class, time.Now().UTC()}, append(args, class)...)
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE memories SET memory_class = ?, updated_at = ? WHERE `+where+` AND COALESCE(memory_class, '') != ?`, args...)
+	if err != nil {
+		return 0, fmt.Errorf("setting memory class: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	if n > 0 {
+		_ = s.LogEvent(ctx, &MemoryEvent{
+			EventType: "update",
+			NewValue:  fmt.Sprintf("memory_class=%s on %d memories (%s)", class, n, description),
+			Source:    "memory:class",
+		})
+	}
+	return n, nil
+}
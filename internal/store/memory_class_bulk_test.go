@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSetMemoryClassBulk_FiltersAndPlan(t *testing.T) {
+	s := newTestStore(t)
+	ss := s.(*SQLiteStore)
+	ctx := context.Background()
+
+	add := func(source, project, class string) int64 {
+		id, err := s.AddMemory(ctx, &Memory{Content: "memory from " + source + " " + class, SourceFile: source, Project: project, MemoryClass: class})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	add("notes/rules.md", "ops", "")
+	add("notes/rules.md", "ops", "status")
+	add("notes/rules.md", "ops", "rule")
+	add("notes/other.md", "ops", "")
+	idOther := add("chat/log.md", "web", "")
+
+	filter := MemoryClassFilter{SourcePattern: "notes/rules%"}
+	plan, err := ss.PlanMemoryClassChange(ctx, "rule", filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Matched != 3 || plan.Changes != 2 || plan.ByClass[""] != 1 || plan.ByClass["status"] != 1 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+
+	n, err := ss.SetMemoryClassBulk(ctx, "rule", MemoryClassFilter{SourcePattern: "notes/%", Unclassified: true}, "source=notes/%")
+	if err != nil || n != 2 {
+		t.Fatalf("unclassified under notes/: n=%d err=%v", n, err)
+	}
+	if plan, _ := ss.PlanMemoryClassChange(ctx, "rule", filter); plan.Changes != 1 || plan.ByClass["status"] != 1 {
+		t.Fatalf("only the status memory should remain: %+v", plan)
+	}
+
+	if n, err := ss.SetMemoryClassBulk(ctx, "decision", MemoryClassFilter{Project: "web", IDs: []int64{idOther}}, "query"); err != nil || n != 1 {
+		t.Fatalf("project+IDs: n=%d err=%v", n, err)
+	}
+	if n, _ := ss.SetMemoryClassBulk(ctx, "decision", MemoryClassFilter{IDs: []int64{}}, "query"); n != 0 {
+		t.Fatalf("an empty ID list should match nothing, changed %d", n)
+	}
+	if m, _ := s.GetMemory(ctx, idOther); m.MemoryClass != "decision" {
+		t.Fatalf("class = %q, want decision", m.MemoryClass)
+	}
+
+	if _, err := ss.SetMemoryClassBulk(ctx, "rule", MemoryClassFilter{Unclassified: true}, ""); err == nil {
+		t.Fatal("a filter without a selector should be rejected")
+	}
+	if _, err := ss.PlanMemoryClassChange(ctx, "bogus", filter); err == nil {
+		t.Fatal("expected invalid class error")
+	}
+}
@@ -0,0 +1,197 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MemoryMetaEdit corrects a memory's provenance after import. Set merges
+// metadata keys (the JSON field names of Metadata, e.g. agent_id, channel)
+// and Unset clears them; a key may not appear in both. Class replaces the
+// memory class when non-nil (an empty string clears it).
+type MemoryMetaEdit struct {
+	Class *string
+	Set   map[string]interface{}
+	Unset []string
+	Actor string // appended to the audit event source, e.g. "cli" → memory:meta:cli
+}
+
+// memoryMetaEventSource tags memory_events rows written by EditMemoryMeta.
+// memory_events.fact_id holds the memory ID for these rows.
+const memoryMetaEventSource = "memory:meta"
+
+// MemoryMetaChange is one field changed by EditMemoryMeta.
+type MemoryMetaChange struct {
+	Field string `json:"field"` // metadata key or "memory_class"
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// MetadataKeys returns the metadata keys a memory can carry, sorted.
+func MetadataKeys() []string {
+	t := reflect.TypeOf(Metadata{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isMetadataKey(key string) bool {
+	for _, k := range MetadataKeys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// EditMemoryMeta applies edit to a live memory and records each change in
+// memory_events (event_type "update", fact_id = memory ID). Returns the
+// changes; an edit that changes nothing writes nothing.
+func (s *SQLiteStore) EditMemoryMeta(ctx context.Context, id int64, edit MemoryMetaEdit) ([]MemoryMetaChange, error) {
+	m, err := s.GetMemory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil || m.DeletedAt != nil {
+		return nil, fmt.Errorf("memory %d not found or already deleted", id)
+	}
+
+	fields := map[string]interface{}{}
+	if m.Metadata != nil {
+		raw, _ := json.Marshal(m.Metadata)
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, fmt.Errorf("reading metadata for memory %d: %w", id, err)
+		}
+	}
+	before := make(map[string]string, len(fields))
+	for k, v := range fields {
+		before[k] = metaValueString(v)
+	}
+
+	for _, key := range edit.Unset {
+		if !isMetadataKey(key) {
+			return nil, fmt.Errorf("unknown metadata key %q (valid: %s)", key, strings.Join(MetadataKeys(), ", "))
+		}
+		if _, ok := edit.Set[key]; ok {
+			return nil, fmt.Errorf("metadata key %q is both set and unset", key)
+		}
+		delete(fields, key)
+	}
+	for key, v := range edit.Set {
+		if !isMetadataKey(key) {
+			return nil, fmt.Errorf("unknown metadata key %q (valid: %s)", key, strings.Join(MetadataKeys(), ", "))
+		}
+		fields[key] = v
+	}
+
+	// Round-trip through Metadata so values are type-checked and zero values
+	// drop out the same way they do at import.
+	raw, _ := json.Marshal(fields)
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	var meta Metadata
+	if err := dec.Decode(&meta); err != nil {
+		return nil, fmt.Errorf("invalid metadata: %w", err)
+	}
+	after := map[string]interface{}{}
+	normalized, _ := json.Marshal(meta)
+	_ = json.Unmarshal(normalized, &after)
+
+	var changes []MemoryMetaChange
+	for _, key := range MetadataKeys() {
+		if n := metaValueString(after[key]); before[key] != n {
+			changes = append(changes, MemoryMetaChange{Field: key, Old: before[key], New: n})
+		}
+	}
+
+	class := m.MemoryClass
+	if edit.Class != nil {
+		class = NormalizeMemoryClass(*edit.Class)
+		if class != "" && !IsValidMemoryClass(class) {
+			return nil, fmt.Errorf("invalid memory class %q (valid: %s)", *edit.Class, strings.Join(AvailableMemoryClasses(), ","))
+		}
+		if class != m.MemoryClass {
+			changes = append(changes, MemoryMetaChange{Field: "memory_class", Old: m.MemoryClass, New: class})
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	metaJSON := ""
+	if meta != (Metadata{}) {
+		metaJSON = marshalMetadata(&meta)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE memories SET metadata = NULLIF(?, ''), memory_class = ?, updated_at = ?
+		 WHERE id = ? AND deleted_at IS NULL`,
+		metaJSON, class, time.Now().UTC(), id,
+	); err != nil {
+		return nil, fmt.Errorf("updating metadata for memory %d: %w", id, err)
+	}
+
+	source := memoryMetaEventSource
+	if edit.Actor != "" {
+		source += ":" + edit.Actor
+	}
+	for _, c := range changes {
+		if err := s.LogEvent(ctx, &MemoryEvent{
+			EventType: "update",
+			FactID:    id,
+			OldValue:  fmt.Sprintf("memory %s=%s", c.Field, c.Old),
+			NewValue:  fmt.Sprintf("memory %s=%s", c.Field, c.New),
+			Source:    source,
+		}); err != nil {
+			return changes, err
+		}
+	}
+	return changes, nil
+}
+
+// MemoryMetaHistory returns the audit events recorded by EditMemoryMeta for
+// a memory, oldest first.
+func (s *SQLiteStore) MemoryMetaHistory(ctx context.Context, id int64) ([]MemoryEvent, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, event_type, fact_id, COALESCE(old_value, ''), COALESCE(new_value, ''), COALESCE(source, ''), created_at
+		 FROM memory_events
+		 WHERE fact_id = ? AND (source = ? OR source LIKE ?)
+		 ORDER BY id`,
+		id, memoryMetaEventSource, memoryMetaEventSource+":%",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing metadata history for memory %d: %w", id, err)
+	}
+	defer rows.Close()
+	var events []MemoryEvent
+	for rows.Next() {
+		var e MemoryEvent
+		if err := rows.Scan(&e.ID, &e.EventType, &e.FactID, &e.OldValue, &e.NewValue, &e.Source, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning metadata history: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func metaValueString(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	default:
+		return fmt.Sprint(x)
+	}
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEditMemoryMeta_SetUnsetAndAudit(t *testing.T) {
+	s := newTestStore(t)
+	ss := s.(*SQLiteStore)
+	ctx := context.Background()
+
+	id, err := s.AddMemory(ctx, &Memory{
+		Content:     "deploys go out on Tuesdays",
+		SourceFile:  "chat.md",
+		MemoryClass: "status",
+		Metadata:    &Metadata{AgentID: "sage", Channel: "discord", Model: "m1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rule := "rule"
+	changes, err := ss.EditMemoryMeta(ctx, id, MemoryMetaEdit{
+		Class: &rule,
+		Set:   map[string]interface{}{"agent_id": "hawk", "channel": "discord", "message_count": 3},
+		Unset: []string{"model"},
+		Actor: "cli",
+	})
+	if err != nil {
+		t.Fatalf("EditMemoryMeta: %v", err)
+	}
+	got := map[string]MemoryMetaChange{}
+	for _, c := range changes {
+		got[c.Field] = c
+	}
+	if len(changes) != 4 || got["agent_id"].Old != "sage" || got["agent_id"].New != "hawk" ||
+		got["model"].New != "" || got["message_count"].New != "3" || got["memory_class"].New != "rule" {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+
+	m, _ := s.GetMemory(ctx, id)
+	if m.MemoryClass != "rule" || m.Metadata == nil || m.Metadata.AgentID != "hawk" ||
+		m.Metadata.Channel != "discord" || m.Metadata.Model != "" || m.Metadata.MessageCount != 3 {
+		t.Fatalf("memory not updated: class=%q meta=%+v", m.MemoryClass, m.Metadata)
+	}
+
+	history, err := ss.MemoryMetaHistory(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 4 || history[0].Source != "memory:meta:cli" || !strings.HasPrefix(history[0].NewValue, "memory ") {
+		t.Fatalf("unexpected audit events: %+v", history)
+	}
+
+	if changes, err := ss.EditMemoryMeta(ctx, id, MemoryMetaEdit{Set: map[string]interface{}{"agent_id": "hawk"}}); err != nil || len(changes) != 0 {
+		t.Fatalf("no-op edit should change nothing: %+v, %v", changes, err)
+	}
+	if _, err := ss.EditMemoryMeta(ctx, id, MemoryMetaEdit{Unset: []string{"agent_id", "channel", "message_count"}}); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := s.GetMemory(ctx, id); m.Metadata != nil {
+		t.Fatalf("clearing every key should leave no metadata, got %+v", m.Metadata)
+	}
+}
+
+func TestEditMemoryMeta_Rejects(t *testing.T) {
+	s := newTestStore(t)
+	ss := s.(*SQLiteStore)
+	ctx := context.Background()
+	id, _ := s.AddMemory(ctx, &Memory{Content: "some memory", SourceFile: "a.md"})
+
+	bogus := "bogus"
+	for name, edit := range map[string]MemoryMetaEdit{
+		"unknown key":   {Set: map[string]interface{}{"k": "v"}},
+		"wrong type":    {Set: map[string]interface{}{"input_tokens": "many"}},
+		"set and unset": {Set: map[string]interface{}{"channel": "x"}, Unset: []string{"channel"}},
+		"bad class":     {Class: &bogus},
+	} {
+		if _, err := ss.EditMemoryMeta(ctx, id, edit); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := ss.EditMemoryMeta(ctx, 9999, MemoryMetaEdit{Unset: []string{"channel"}}); err == nil {
+		t.Error("expected error for missing memory")
+	}
+	if history, _ := ss.MemoryMetaHistory(ctx, id); len(history) != 0 {
+		t.Fatalf("rejected edits should not be audited: %+v", history)
+	}
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SourceNeighbors returns up to n live memories on each side of m in its
+// source file, ordered by position (source line, then ID). before is in file
+// order, so before[len(before)-1] is the chunk directly above m.
+func (s *SQLiteStore) SourceNeighbors(ctx context.Context, m *Memory, n int) (before, after []*Memory, err error) {
+	if m == nil || m.SourceFile == "" || n <= 0 {
+		return nil, nil, nil
+	}
+	beforeIDs, err := s.queryMemoryIDs(ctx,
+		`SELECT id FROM memories
+		 WHERE source_file = ? AND deleted_at IS NULL AND (source_line, id) < (?, ?)
+		 ORDER BY source_line DESC, id DESC LIMIT ?`,
+		m.SourceFile, m.SourceLine, m.ID, n)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing chunks before memory %d: %w", m.ID, err)
+	}
+	afterIDs, err := s.queryMemoryIDs(ctx,
+		`SELECT id FROM memories
+		 WHERE source_file = ? AND deleted_at IS NULL AND (source_line, id) > (?, ?)
+		 ORDER BY source_line ASC, id ASC LIMIT ?`,
+		m.SourceFile, m.SourceLine, m.ID, n)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing chunks after memory %d: %w", m.ID, err)
+	}
+
+	for i := len(beforeIDs) - 1; i >= 0; i-- {
+		mem, err := s.GetMemory(ctx, beforeIDs[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		if mem != nil {
+			before = append(before, mem)
+		}
+	}
+	for _, id := range afterIDs {
+		mem, err := s.GetMemory(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if mem != nil {
+			after = append(after, mem)
+		}
+	}
+	return before, after, nil
+}
+
+// MemoryEmbeddingDimensions returns the dimensions of a memory's stored
+// embedding, or 0 when it has none.
+func (s *SQLiteStore) MemoryEmbeddingDimensions(ctx context.Context, memoryID int64) (int, error) {
+	var dims int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT dimensions FROM embeddings WHERE memory_id = ?`, memoryID,
+	).Scan(&dims)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("getting embedding status for memory %d: %w", memoryID, err)
+	}
+	return dims, nil
+}
+
+// MemoryAccessHistory returns the most recent accesses to facts extracted
+// from a memory, newest first, with the total number of accesses recorded.
+func (s *SQLiteStore) MemoryAccessHistory(ctx context.Context, memoryID int64, limit int) ([]FactAccess, int, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	var total int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM fact_accesses_v1 a JOIN facts f ON f.id = a.fact_id WHERE f.memory_id = ?`,
+		memoryID,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting accesses for memory %d: %w", memoryID, err)
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT a.id, a.fact_id, a.agent_id, a.access_type, a.created_at
+		 FROM fact_accesses_v1 a JOIN facts f ON f.id = a.fact_id
+		 WHERE f.memory_id = ?
+		 ORDER BY a.created_at DESC, a.id DESC LIMIT ?`,
+		memoryID, limit,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing accesses for memory %d: %w", memoryID, err)
+	}
+	defer rows.Close()
+
+	var accesses []FactAccess
+	for rows.Next() {
+		var a FactAccess
+		var agent sql.NullString
+		if err := rows.Scan(&a.ID, &a.FactID, &agent, &a.AccessType, &a.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("scanning access row: %w", err)
+		}
+		a.AgentID = agent.String
+		accesses = append(accesses, a)
+	}
+	return accesses, total, rows.Err()
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSourceNeighbors(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	var ids []int64
+	for i, content := range []string{"one", "two", "three", "four", "five"} {
+		id, err := s.AddMemory(ctx, &Memory{Content: content, SourceFile: "/doc.md", SourceLine: 10 * (i + 1)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	s.AddMemory(ctx, &Memory{Content: "elsewhere", SourceFile: "/other.md", SourceLine: 25})
+	if err := s.DeleteMemory(ctx, ids[3]); err != nil {
+		t.Fatal(err)
+	}
+
+	m, _ := s.GetMemory(ctx, ids[2])
+	before, after, err := s.SourceNeighbors(ctx, m, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(before) != 2 || before[0].ID != ids[0] || before[1].ID != ids[1] {
+		t.Fatalf("before = %+v, want ids %d, %d in file order", before, ids[0], ids[1])
+	}
+	if len(after) != 1 || after[0].ID != ids[4] {
+		t.Fatalf("after = %+v, want only %d (deleted chunk skipped)", after, ids[4])
+	}
+
+	first, _ := s.GetMemory(ctx, ids[0])
+	if before, _, _ := s.SourceNeighbors(ctx, first, 2); len(before) != 0 {
+		t.Fatalf("first chunk has nothing before it, got %+v", before)
+	}
+}
+
+func TestMemoryEmbeddingDimensionsAndAccessHistory(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	id, _ := s.AddMemory(ctx, &Memory{Content: "deploys run on tuesday", SourceFile: "/doc.md"})
+	if dims, err := s.MemoryEmbeddingDimensions(ctx, id); err != nil || dims != 0 {
+		t.Fatalf("unembedded memory = %d, %v", dims, err)
+	}
+	if err := s.AddEmbedding(ctx, id, []float32{1, 0, 0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if dims, _ := s.MemoryEmbeddingDimensions(ctx, id); dims != 4 {
+		t.Fatalf("dims = %d, want 4", dims)
+	}
+
+	factID, _ := s.AddFact(ctx, &Fact{MemoryID: id, Subject: "deploys", Predicate: "run on", Object: "tuesday", FactType: "temporal"})
+	for i := 0; i < 3; i++ {
+		if err := s.RecordFactAccess(ctx, factID, "hawk", AccessTypeSearch); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recent, total, err := s.MemoryAccessHistory(ctx, id, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 || len(recent) != 2 || recent[0].FactID != factID || recent[0].AccessType != AccessTypeSearch || recent[0].CreatedAt.IsZero() {
+		t.Fatalf("history = %+v (total %d)", recent, total)
+	}
+}
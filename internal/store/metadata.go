@@ -160,6 +160,9 @@ func (s *SQLiteStore) SearchFTSWithMetadata(ctx context.Context, query string, l
 	}
 
 	metaClause, metaArgs := buildMetadataWhereClause(filters)
+	nsClause, nsArgs := s.namespaceFilter(ctx, "+m.namespace")
+	metaClause += nsClause
+	metaArgs = append(metaArgs, nsArgs...)
 
 	var sqlQuery string
 	var args []interface{}
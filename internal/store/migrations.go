@@ -117,6 +117,18 @@ func (s *SQLiteStore) migrate() error {
 		return fmt.Errorf("migrating fact token estimate column: %w", err)
 	}
 
+	// Schema evolution: custom_type column — user-defined taxonomy types
+	// layered over the built-in fact_type (which keeps its CHECK constraint).
+	if err := s.migrateFactCustomTypeColumn(); err != nil {
+		return fmt.Errorf("migrating facts.custom_type column: %w", err)
+	}
+
+	// Schema evolution: negated column — fact polarity for negation-aware
+	// conflict detection ("X is enabled" vs "X is not enabled").
+	if err := s.migrateFactNegatedColumn(); err != nil {
+		return fmt.Errorf("migrating facts.negated column: %w", err)
+	}
+
 	// Schema evolution: fact accesses table (v1.0 — Issue #166)
 	if err := s.migrateFactAccessesTable(); err != nil {
 		return fmt.Errorf("migrating fact accesses table: %w", err)
@@ -161,6 +173,211 @@ func (s *SQLiteStore) migrate() error {
 		return fmt.Errorf("migrating directive_proposals table: %w", err)
 	}
 
+	// Schema evolution: telemetry_events table — per-command LLM/embedding
+	// usage, replacing the standalone reason-telemetry.jsonl file.
+	if err := s.migrateTelemetryTable(); err != nil {
+		return fmt.Errorf("migrating telemetry_events table: %w", err)
+	}
+
+	// Schema evolution: extraction_examples table — curated few-shot
+	// (content → facts) pairs injected into enrichment prompts.
+	if err := s.migrateExtractionExamplesTable(); err != nil {
+		return fmt.Errorf("migrating extraction_examples table: %w", err)
+	}
+
+	// Schema evolution: annotations table — free-text notes attached to
+	// facts and memories.
+	if err := s.migrateAnnotationsTable(); err != nil {
+		return fmt.Errorf("migrating annotations table: %w", err)
+	}
+
+	// Schema evolution: fact_reviews table — low-confidence extracted facts
+	// held for approval before they become facts.
+	if err := s.migrateFactReviewsTable(); err != nil {
+		return fmt.Errorf("migrating fact_reviews table: %w", err)
+	}
+
+	// Schema evolution: conflict_exceptions table — fact pairs accepted as
+	// both true, excluded from conflict detection and alerts.
+	if err := s.migrateConflictExceptionsTable(); err != nil {
+		return fmt.Errorf("migrating conflict_exceptions table: %w", err)
+	}
+
+	// Schema evolution: import_triage table — audit log of LLM import
+	// triage scores and keep/drop decisions.
+	if err := s.migrateImportTriageTable(); err != nil {
+		return fmt.Errorf("migrating import_triage table: %w", err)
+	}
+
+	// Schema evolution: jobs table — persisted background jobs with
+	// progress and cancellation.
+	if err := s.migrateJobsTable(); err != nil {
+		return fmt.Errorf("migrating jobs table: %w", err)
+	}
+
+	// Schema evolution: batch_checkpoints table — items already processed
+	// by resumable LLM batch operations.
+	if err := s.migrateBatchCheckpointsTable(); err != nil {
+		return fmt.Errorf("migrating batch_checkpoints table: %w", err)
+	}
+
+	// Schema evolution: telemetry_events.errors/failovers — per-provider
+	// failure stats for LLM and embedding fallback chains.
+	if err := s.migrateTelemetryFailureColumns(); err != nil {
+		return fmt.Errorf("migrating telemetry failure columns: %w", err)
+	}
+
+	// Schema evolution: telemetry_events.repairs/reprompts — per-model
+	// counts of malformed structured LLM replies.
+	if err := s.migrateTelemetryRepairColumns(); err != nil {
+		return fmt.Errorf("migrating telemetry repair columns: %w", err)
+	}
+
+	// Schema evolution: 'summarizes' fact edges and the fact_summaries undo
+	// journal — reversible cluster summaries.
+	if err := s.migrateSummarizesEdgeType(); err != nil {
+		return fmt.Errorf("migrating summarizes edge type: %w", err)
+	}
+	if err := s.migrateFactSummariesTable(); err != nil {
+		return fmt.Errorf("migrating fact_summaries table: %w", err)
+	}
+
+	// Schema evolution: briefs table — hierarchical cluster/project/store
+	// summaries for `cortex brief`.
+	if err := s.migrateBriefsTable(); err != nil {
+		return fmt.Errorf("migrating briefs table: %w", err)
+	}
+
+	// Schema evolution: connector pause columns for ingestion quotas.
+	if err := s.migrateConnectorPauseColumns(); err != nil {
+		return fmt.Errorf("migrating connector pause columns: %w", err)
+	}
+
+	// Schema evolution: connectors.sync_cursor for providers that track
+	// their own sync position (IMAP UIDs).
+	if err := s.migrateConnectorCursorColumn(); err != nil {
+		return fmt.Errorf("migrating connector cursor column: %w", err)
+	}
+
+	// Schema evolution: sources table — registry overrides set with
+	// `cortex sources set`.
+	if err := s.migrateSourcesTable(); err != nil {
+		return fmt.Errorf("migrating sources table: %w", err)
+	}
+
+	// Schema evolution: fact_corroborations — independent sources that
+	// repeated a fact and the confidence boost each one gave.
+	if err := s.migrateFactCorroborationsTable(); err != nil {
+		return fmt.Errorf("migrating fact corroborations table: %w", err)
+	}
+
+	// Schema evolution: project_privacy — per-project export, MCP, and LLM
+	// restrictions set with `cortex projects privacy set`.
+	if err := s.migrateProjectPrivacyTable(); err != nil {
+		return fmt.Errorf("migrating project privacy table: %w", err)
+	}
+
+	// Schema evolution: archive_holds + archive_audit — legal holds set with
+	// `cortex archive set`, enforced by triggers on memories and facts.
+	if err := s.migrateArchiveTables(); err != nil {
+		return fmt.Errorf("migrating archive tables: %w", err)
+	}
+
+	// Schema evolution: store_change_seq + reason_cache — a change counter
+	// bumped by triggers, and reason results cached against it.
+	if err := s.migrateReasonCacheTables(); err != nil {
+		return fmt.Errorf("migrating reason cache tables: %w", err)
+	}
+
+	// Schema evolution: 'translation_of' fact edges and the translations
+	// table (cortex translate).
+	if err := s.widenEdgeTypeCheck(EdgeTypeTranslationOf); err != nil {
+		return fmt.Errorf("migrating translation_of edge type: %w", err)
+	}
+	if err := s.migrateTranslationsTable(); err != nil {
+		return fmt.Errorf("migrating translations table: %w", err)
+	}
+
+	// Schema evolution: connector_syncs — one row per connector sync for
+	// `cortex connect history` and the stats trend lines.
+	if err := s.migrateConnectorSyncsTable(); err != nil {
+		return fmt.Errorf("migrating connector syncs table: %w", err)
+	}
+
+	if err := s.migrateScheduleRunsTable(); err != nil {
+		return fmt.Errorf("migrating schedule runs table: %w", err)
+	}
+
+	if err := s.migrateMemoryArchiveTable(); err != nil {
+		return fmt.Errorf("migrating memory archive table: %w", err)
+	}
+
+	// Schema evolution: learned_edges + co-occurrence first_seen — relates_to
+	// edges learned from sustained co-access.
+	if err := s.migrateCoaccessEdges(); err != nil {
+		return fmt.Errorf("migrating co-access edges: %w", err)
+	}
+
+	// Schema evolution: import_file_checksums — skip unchanged files on
+	// re-import.
+	if err := s.migrateFileChecksums(); err != nil {
+		return fmt.Errorf("migrating file checksums: %w", err)
+	}
+
+	// Schema evolution: memory_anchors — byte offsets and heading path per
+	// chunk.
+	if err := s.migrateMemoryAnchors(); err != nil {
+		return fmt.Errorf("migrating memory anchors: %w", err)
+	}
+
+	// Schema evolution: fact_sources — every memory behind a fact, so
+	// summaries and merges keep their provenance.
+	if err := s.migrateFactSources(); err != nil {
+		return fmt.Errorf("migrating fact sources: %w", err)
+	}
+
+	// Schema evolution: documents + memories.document_id — chunks grouped
+	// by source file for document-level operations.
+	if err := s.migrateDocuments(); err != nil {
+		return fmt.Errorf("migrating documents: %w", err)
+	}
+
+	// Schema evolution: capture_rejections — chunks the auto-capture dedupe
+	// and low-signal filters turned away, for `cortex capture report`.
+	if err := s.migrateCaptureRejections(); err != nil {
+		return fmt.Errorf("migrating capture rejections: %w", err)
+	}
+
+	// Schema evolution: memories.namespace — tenant partitions inside one
+	// database; facts, edges, and clusters follow their memories.
+	if err := s.migrateNamespaces(); err != nil {
+		return fmt.Errorf("migrating namespaces: %w", err)
+	}
+
+	// Schema evolution: import_file_checksums keyed by namespace — an
+	// unchanged file is only skipped in the namespace it was imported into.
+	if err := s.migrateFileChecksumNamespaces(); err != nil {
+		return fmt.Errorf("migrating file checksum namespaces: %w", err)
+	}
+
+	// Schema evolution: subject_subscriptions — `cortex watch subject`
+	// subscriptions notified when matching facts change.
+	if err := s.migrateSubjectSubscriptions(); err != nil {
+		return fmt.Errorf("migrating subject subscriptions: %w", err)
+	}
+
+	// Schema evolution: pending_enrichment — memories whose LLM enrichment
+	// was deferred to a background job by `cortex import --async-enrich`.
+	if err := s.migratePendingEnrichment(); err != nil {
+		return fmt.Errorf("migrating pending enrichment: %w", err)
+	}
+
+	// Schema evolution: extraction_yield — the zero-yield skip list of
+	// content that extraction or enrichment keeps finding nothing in.
+	if err := s.migrateExtractionYield(); err != nil {
+		return fmt.Errorf("migrating extraction yield: %w", err)
+	}
+
 	return nil
 }
 
@@ -252,7 +469,9 @@ func (s *SQLiteStore) runBootstrapDDL() error {
 			observed_entity TEXT NOT NULL DEFAULT '',
 			session_id      TEXT NOT NULL DEFAULT '',
 			project_id      TEXT NOT NULL DEFAULT '',
-			token_estimate  INTEGER NOT NULL DEFAULT 0
+			token_estimate  INTEGER NOT NULL DEFAULT 0,
+			custom_type     TEXT NOT NULL DEFAULT '',
+			negated         INTEGER NOT NULL DEFAULT 0
 		)`,
 
 		`CREATE INDEX IF NOT EXISTS idx_facts_memory_id ON facts(memory_id)`,
@@ -1024,6 +1243,60 @@ func (s *SQLiteStore) migrateConnectorsTable() error {
 	return nil
 }
 
+// migrateConnectorCursorColumn adds connectors.sync_cursor, an opaque JSON
+// position owned by providers that sync by cursor instead of by time.
+func (s *SQLiteStore) migrateConnectorCursorColumn() error {
+	var count int
+	if err := s.db.QueryRow(
+		"SELECT COUNT(*) FROM pragma_table_info('connectors') WHERE name = 'sync_cursor'",
+	).Scan(&count); err != nil {
+		return fmt.Errorf("checking for connectors.sync_cursor column: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	if _, err := s.db.Exec(`ALTER TABLE connectors ADD COLUMN sync_cursor TEXT NOT NULL DEFAULT ''`); err != nil && !isDuplicateColumnError(err) {
+		return fmt.Errorf("adding connectors.sync_cursor column: %w", err)
+	}
+	return nil
+}
+
+// migrateConnectorSyncsTable creates connector_syncs, the per-sync history
+// behind the connectors table's last_sync_* summary.
+func (s *SQLiteStore) migrateConnectorSyncsTable() error {
+	done, err := s.isMetaFlagEnabled("connector_syncs_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS connector_syncs (
+			id               INTEGER PRIMARY KEY AUTOINCREMENT,
+			provider         TEXT NOT NULL,
+			started_at       DATETIME NOT NULL,
+			duration_ms      INTEGER NOT NULL DEFAULT 0,
+			status           TEXT NOT NULL DEFAULT 'ok',
+			records_fetched  INTEGER NOT NULL DEFAULT 0,
+			records_imported INTEGER NOT NULL DEFAULT 0,
+			records_skipped  INTEGER NOT NULL DEFAULT 0,
+			records_expired  INTEGER NOT NULL DEFAULT 0,
+			facts_extracted  INTEGER NOT NULL DEFAULT 0,
+			error            TEXT NOT NULL DEFAULT ''
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_connector_syncs_provider ON connector_syncs(provider, started_at)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('connector_syncs_v1', 'true')`,
+	}
+	for _, stmt := range stmts {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating connector_syncs table: %w", err)
+		}
+	}
+	return nil
+}
+
 // migrateAlertsTable creates the alerts table for proactive notifications.
 func (s *SQLiteStore) migrateAlertsTable() error {
 	done, err := s.isMetaFlagEnabled("alerts_v1")
@@ -1571,7 +1844,7 @@ func (s *SQLiteStore) migrateFactEdgesTable() error {
 			id INTEGER PRIMARY KEY,
 			source_fact_id INTEGER NOT NULL,
 			target_fact_id INTEGER NOT NULL,
-			edge_type TEXT NOT NULL CHECK (edge_type IN ('supports','contradicts','relates_to','supersedes','derived_from')),
+			edge_type TEXT NOT NULL CHECK (edge_type IN ('supports','contradicts','relates_to','supersedes','derived_from','summarizes','translation_of')),
 			confidence REAL DEFAULT 1.0 CHECK (confidence >= 0 AND confidence <= 1.0),
 			source TEXT NOT NULL DEFAULT 'explicit' CHECK (source IN ('explicit','detected','inferred')),
 			agent_id TEXT NOT NULL DEFAULT '',
@@ -0,0 +1,239 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Namespaces partition one database between tenants, e.g. several agents
+// served by one daemon. Every memory belongs to exactly one namespace;
+// facts, edges, and clusters follow their memories. Writes land in the
+// active namespace, and listings, search, dedupe, and stats only see it.
+// Lookups by ID are not filtered, since background work (embedding, jobs)
+// runs across all namespaces; callers that take IDs from untrusted clients,
+// like the MCP write tools, check FactInNamespace or NamespaceVisible.
+const (
+	DefaultNamespace = "default"
+	AllNamespaces    = "*" // reads see every namespace; writes go to DefaultNamespace
+)
+
+var namespaceNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// NormalizeNamespace lowercases and trims a namespace name; empty means
+// DefaultNamespace.
+func NormalizeNamespace(ns string) string {
+	ns = strings.ToLower(strings.TrimSpace(ns))
+	if ns == "" {
+		return DefaultNamespace
+	}
+	return ns
+}
+
+// ValidateNamespace checks a namespace name: 1-64 characters of a-z, 0-9,
+// '.', '_', or '-', starting with a letter or digit. AllNamespaces is valid.
+func ValidateNamespace(ns string) error {
+	ns = NormalizeNamespace(ns)
+	if ns == AllNamespaces || namespaceNameRE.MatchString(ns) {
+		return nil
+	}
+	return fmt.Errorf("invalid namespace %q (use 1-64 of a-z, 0-9, '.', '_', '-')", ns)
+}
+
+type namespaceCtxKey struct{}
+
+// WithNamespace returns a context whose store calls are scoped to ns,
+// overriding the store's configured namespace. An empty ns leaves ctx as is.
+func WithNamespace(ctx context.Context, ns string) context.Context {
+	if strings.TrimSpace(ns) == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, namespaceCtxKey{}, NormalizeNamespace(ns))
+}
+
+// NamespaceFromContext returns the namespace set with WithNamespace.
+func NamespaceFromContext(ctx context.Context) (string, bool) {
+	ns, ok := ctx.Value(namespaceCtxKey{}).(string)
+	return ns, ok
+}
+
+// Namespace is the namespace store calls with ctx are scoped to: the one set
+// on ctx, else the store's configured namespace.
+func (s *SQLiteStore) Namespace(ctx context.Context) string {
+	if ns, ok := NamespaceFromContext(ctx); ok {
+		return ns
+	}
+	return NormalizeNamespace(s.namespace)
+}
+
+// EffectiveNamespace is st's namespace for ctx, for callers holding a Store.
+// Stores without namespaces see everything.
+func EffectiveNamespace(ctx context.Context, st Store) string {
+	if scoped, ok := st.(interface{ Namespace(context.Context) string }); ok {
+		return scoped.Namespace(ctx)
+	}
+	if ns, ok := NamespaceFromContext(ctx); ok {
+		return ns
+	}
+	return AllNamespaces
+}
+
+// NamespaceVisible reports whether a memory in namespace memNS is visible
+// to a caller scoped to ns.
+func NamespaceVisible(ns, memNS string) bool {
+	return ns == AllNamespaces || NormalizeNamespace(memNS) == ns
+}
+
+// writeNamespace is the namespace a new memory is stored in.
+func (s *SQLiteStore) writeNamespace(ctx context.Context, m *Memory) string {
+	if m.Namespace != "" && m.Namespace != AllNamespaces {
+		return NormalizeNamespace(m.Namespace)
+	}
+	if ns := s.Namespace(ctx); ns != AllNamespaces {
+		return ns
+	}
+	return DefaultNamespace
+}
+
+// namespacedHash keeps content_hash (UNIQUE) distinct per namespace so the
+// same chunk can be imported into two namespaces. Default-namespace hashes
+// are stored unchanged.
+func namespacedHash(ns, hash string) string {
+	if ns == DefaultNamespace || ns == AllNamespaces || hash == "" || strings.HasSuffix(hash, "@"+ns) {
+		return hash
+	}
+	return hash + "@" + ns
+}
+
+// namespaceHashSuffixSQL is namespacedHash's suffix for a memories row, for
+// UPDATEs that recompute content_hash.
+const namespaceHashSuffixSQL = `CASE WHEN namespace = 'default' THEN '' ELSE '@' || namespace END`
+
+// namespaceFilter returns an " AND <col> = ?" clause scoping a memories
+// column to ctx's namespace, or nothing when ctx sees every namespace.
+// FTS queries pass "+m.namespace" so SQLite drives the join from the MATCH
+// rather than from idx_memories_namespace.
+func (s *SQLiteStore) namespaceFilter(ctx context.Context, col string) (string, []interface{}) {
+	ns := s.Namespace(ctx)
+	if ns == AllNamespaces {
+		return "", nil
+	}
+	return " AND " + col + " = ?", []interface{}{ns}
+}
+
+// migrateNamespaces adds memories.namespace; existing rows land in
+// DefaultNamespace.
+func (s *SQLiteStore) migrateNamespaces() error {
+	done, err := s.isMetaFlagEnabled("namespaces_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	var n int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('memories') WHERE name = 'namespace'`).Scan(&n); err != nil {
+		return fmt.Errorf("checking memories.namespace: %w", err)
+	}
+	statements := []string{}
+	if n == 0 {
+		statements = append(statements, `ALTER TABLE memories ADD COLUMN namespace TEXT NOT NULL DEFAULT 'default'`)
+	}
+	statements = append(statements,
+		`CREATE INDEX IF NOT EXISTS idx_memories_namespace ON memories(namespace, deleted_at)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('namespaces_v1', 'true')`,
+	)
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("migrating namespaces: %w", err)
+		}
+	}
+	return nil
+}
+
+// NamespaceInfo is one namespace with its live memory and fact counts.
+type NamespaceInfo struct {
+	Name     string `json:"name"`
+	Memories int    `json:"memories"`
+	Facts    int    `json:"facts"`
+}
+
+// ListNamespaces returns every namespace that holds a live memory, largest
+// first. It ignores the active namespace.
+func (s *SQLiteStore) ListNamespaces(ctx context.Context) ([]NamespaceInfo, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT m.namespace, COUNT(DISTINCT m.id), COUNT(f.id)
+		FROM memories m
+		LEFT JOIN facts f ON f.memory_id = m.id AND f.superseded_by IS NULL
+		WHERE m.deleted_at IS NULL
+		GROUP BY m.namespace
+		ORDER BY COUNT(DISTINCT m.id) DESC, m.namespace`)
+	if err != nil {
+		return nil, fmt.Errorf("listing namespaces: %w", err)
+	}
+	defer rows.Close()
+	var out []NamespaceInfo
+	for rows.Next() {
+		var ni NamespaceInfo
+		if err := rows.Scan(&ni.Name, &ni.Memories, &ni.Facts); err != nil {
+			return nil, fmt.Errorf("scanning namespace: %w", err)
+		}
+		out = append(out, ni)
+	}
+	return out, rows.Err()
+}
+
+// MoveToNamespace moves live memories whose source_file matches the GLOB
+// pattern (every live memory when pattern is empty) from the active
+// namespace into ns. Their facts, edges, and cluster memberships follow.
+// Memories whose chunk ns already holds are left in place. Returns the
+// number of memories moved.
+func (s *SQLiteStore) MoveToNamespace(ctx context.Context, ns, sourcePattern string) (int64, error) {
+	if err := ValidateNamespace(ns); err != nil {
+		return 0, err
+	}
+	ns = NormalizeNamespace(ns)
+	if ns == AllNamespaces {
+		return 0, fmt.Errorf("cannot move memories into %q", AllNamespaces)
+	}
+	// content_hash keeps its namespace suffix in step (see namespacedHash);
+	// a memory whose chunk already exists in ns stays where it is.
+	newHash := `substr(memories.content_hash, 1, instr(memories.content_hash || '@', '@') - 1) || CASE WHEN ? = 'default' THEN '' ELSE '@' || ? END`
+	query := `UPDATE memories SET namespace = ?, content_hash = ` + newHash + `, updated_at = CURRENT_TIMESTAMP
+		WHERE deleted_at IS NULL AND namespace != ?
+		  AND NOT EXISTS (SELECT 1 FROM memories d WHERE d.content_hash = ` + newHash + `)`
+	args := []interface{}{ns, ns, ns, ns, ns, ns}
+	if sourcePattern != "" {
+		query += ` AND source_file GLOB ?`
+		args = append(args, sourcePattern)
+	}
+	filter, fargs := s.namespaceFilter(ctx, "namespace")
+	n, err := s.execCount(ctx, query+filter, append(args, fargs...)...)
+	if err != nil {
+		return 0, fmt.Errorf("moving memories to namespace %s: %w", ns, err)
+	}
+	return n, nil
+}
+
+// factNamespaceFilter is namespaceFilter for a column holding fact IDs:
+// facts belong to their memory's namespace.
+func (s *SQLiteStore) factNamespaceFilter(ctx context.Context, col string) (string, []interface{}) {
+	nsClause, nsArgs := s.namespaceFilter(ctx, "nm.namespace")
+	if nsClause == "" {
+		return "", nil
+	}
+	return " AND " + col + " IN (SELECT nf.id FROM facts nf JOIN memories nm ON nm.id = nf.memory_id WHERE 1 = 1" + nsClause + ")", nsArgs
+}
+
+// FactInNamespace reports whether factID is visible in ctx's namespace.
+func (s *SQLiteStore) FactInNamespace(ctx context.Context, factID int64) bool {
+	nsClause, nsArgs := s.factNamespaceFilter(ctx, "?")
+	if nsClause == "" {
+		return true
+	}
+	var ok bool
+	err := s.db.QueryRowContext(ctx, `SELECT 1 WHERE 1 = 1`+nsClause, append([]interface{}{factID}, nsArgs...)...).Scan(&ok)
+	return err == nil && ok
+}
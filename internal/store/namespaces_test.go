@@ -0,0 +1,158 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNamespacesPartitionMemoriesAndFacts(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	base := context.Background()
+	work := WithNamespace(base, "work")
+
+	home, err := s.AddMemory(base, &Memory{Content: "standup is at nine", SourceFile: "/notes/day.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The same chunk can live in two namespaces without tripping dedupe.
+	office, err := s.AddMemory(work, &Memory{Content: "standup is at nine", SourceFile: "/notes/day.md"})
+	if err != nil {
+		t.Fatalf("same chunk in a second namespace: %v", err)
+	}
+	for _, id := range []int64{home, office} {
+		if _, err := s.AddFact(base, &Fact{MemoryID: id, Subject: "standup", Predicate: "time", Object: "nine", FactType: "kv"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := s.GetMemory(base, office)
+	if err != nil || m.Namespace != "work" {
+		t.Fatalf("GetMemory(office) = %+v, %v", m, err)
+	}
+	for _, tc := range []struct {
+		ctx    context.Context
+		wantID int64
+	}{{base, home}, {work, office}} {
+		mems, err := s.ListMemories(tc.ctx, ListOpts{})
+		if err != nil || len(mems) != 1 || mems[0].ID != tc.wantID {
+			t.Fatalf("ListMemories(%s) = %v, %v", s.Namespace(tc.ctx), mems, err)
+		}
+		facts, err := s.ListFacts(tc.ctx, ListOpts{})
+		if err != nil || len(facts) != 1 || facts[0].MemoryID != tc.wantID {
+			t.Fatalf("ListFacts(%s) = %v, %v", s.Namespace(tc.ctx), facts, err)
+		}
+		results, err := s.SearchFTS(tc.ctx, "standup", 10)
+		if err != nil || len(results) != 1 || results[0].Memory.ID != tc.wantID {
+			t.Fatalf("SearchFTS(%s) = %v, %v", s.Namespace(tc.ctx), results, err)
+		}
+		hit, err := s.FindByHash(tc.ctx, HashMemoryContent("standup is at nine", "/notes/day.md"))
+		if err != nil || hit == nil || hit.ID != tc.wantID {
+			t.Fatalf("FindByHash(%s) = %v, %v", s.Namespace(tc.ctx), hit, err)
+		}
+		stats, err := s.Stats(tc.ctx)
+		if err != nil || stats.MemoryCount != 1 || stats.FactCount != 1 {
+			t.Fatalf("Stats(%s) = %+v, %v", s.Namespace(tc.ctx), stats, err)
+		}
+	}
+
+	all := WithNamespace(base, AllNamespaces)
+	if n, err := s.CountMemories(all, ListOpts{}); err != nil || n != 2 {
+		t.Fatalf("CountMemories(*) = %d, %v", n, err)
+	}
+	if hit, err := s.FindByHash(base, HashMemoryContent("standup is at nine", "/notes/day.md")+"@work"); err != nil || hit != nil {
+		t.Fatalf("default namespace found a work hash: %v, %v", hit, err)
+	}
+}
+
+func TestMoveToNamespace(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+	work := WithNamespace(ctx, "work")
+
+	keep, _ := s.AddMemory(ctx, &Memory{Content: "groceries: eggs", SourceFile: "/home/list.md"})
+	moved, _ := s.AddMemory(ctx, &Memory{Content: "ship the release", SourceFile: "/work/todo.md"})
+	dup, _ := s.AddMemory(ctx, &Memory{Content: "review the roadmap", SourceFile: "/work/plan.md"})
+	if _, err := s.AddMemory(work, &Memory{Content: "review the roadmap", SourceFile: "/work/plan.md"}); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := s.MoveToNamespace(ctx, "work", "/work/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("moved %d memories, want 1 (the duplicate stays)", n)
+	}
+	for id, want := range map[int64]string{keep: "default", moved: "work", dup: "default"} {
+		m, _ := s.GetMemory(ctx, id)
+		if m.Namespace != want {
+			t.Errorf("memory %d namespace = %q, want %q", id, m.Namespace, want)
+		}
+	}
+	if hit, _ := s.FindByHash(work, HashMemoryContent("ship the release", "/work/todo.md")); hit == nil || hit.ID != moved {
+		t.Fatalf("moved memory not found by hash in work: %v", hit)
+	}
+
+	// Moving back strips the namespace suffix again.
+	if n, err := s.MoveToNamespace(work, DefaultNamespace, "/work/todo.md"); err != nil || n != 1 {
+		t.Fatalf("move back = %d, %v", n, err)
+	}
+	if hit, _ := s.FindByHash(ctx, HashMemoryContent("ship the release", "/work/todo.md")); hit == nil || hit.ID != moved {
+		t.Fatalf("moved-back memory not found by hash: %v", hit)
+	}
+
+	if _, err := s.MoveToNamespace(ctx, "Bad Name", ""); err == nil {
+		t.Fatal("expected an error for an invalid namespace")
+	}
+
+	namespaces, err := s.ListNamespaces(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(namespaces) != 2 || namespaces[0].Name != "default" || namespaces[0].Memories != 3 || namespaces[1].Name != "work" || namespaces[1].Memories != 1 {
+		t.Fatalf("ListNamespaces = %+v", namespaces)
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	for _, ok := range []string{"", "default", "work", "Agent-7", "team.ops_1", "*"} {
+		if err := ValidateNamespace(ok); err != nil {
+			t.Errorf("ValidateNamespace(%q) = %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"-work", "two words", "a/b", "@x"} {
+		if err := ValidateNamespace(bad); err == nil {
+			t.Errorf("ValidateNamespace(%q) = nil, want error", bad)
+		}
+	}
+}
+
+func TestStatsHelpersStayInNamespace(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+	work := WithNamespace(ctx, "work")
+
+	workMem, _ := s.AddMemory(work, &Memory{Content: "deploys run on tuesdays", SourceFile: "/work/ops.md"})
+	homeA, _ := s.AddMemory(ctx, &Memory{Content: "dentist in march", SourceFile: "/home/a.md"})
+	homeB, _ := s.AddMemory(ctx, &Memory{Content: "car service due", SourceFile: "/home/b.md"})
+	s.AddFact(ctx, &Fact{MemoryID: workMem, Subject: "deploy", Predicate: "day", Object: "tuesday", FactType: "temporal", Confidence: 0.5})
+	s.AddFact(ctx, &Fact{MemoryID: homeA, Subject: "dentist", Predicate: "month", Object: "march", FactType: "kv", Confidence: 1})
+	s.AddFact(ctx, &Fact{MemoryID: homeB, Subject: "car", Predicate: "service", Object: "due", FactType: "kv", Confidence: 1})
+
+	if n, err := s.GetSourceCount(work); err != nil || n != 1 {
+		t.Fatalf("work sources = %d, %v", n, err)
+	}
+	if avg, err := s.GetAverageConfidence(work); err != nil || avg != 0.5 {
+		t.Fatalf("work average confidence = %v, %v", avg, err)
+	}
+	byType, err := s.GetFactsByType(work)
+	if err != nil || len(byType) != 1 || byType["temporal"] != 1 {
+		t.Fatalf("work facts by type = %v, %v", byType, err)
+	}
+	if fresh, err := s.GetFreshnessDistribution(work); err != nil || fresh.Today != 1 {
+		t.Fatalf("work freshness = %+v, %v", fresh, err)
+	}
+	if n, _ := s.GetSourceCount(WithNamespace(ctx, AllNamespaces)); n != 3 {
+		t.Fatalf("all-namespace sources = %d, want 3", n)
+	}
+}
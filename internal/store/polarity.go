@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/extract"
+)
+
+// ConflictSeverityHigh marks conflicts that are contradictions by
+// construction, such as a fact and its direct negation.
+const ConflictSeverityHigh = "high"
+
+// migrateFactNegatedColumn adds the polarity flag to facts. Existing rows
+// are backfilled from their predicate/object text so older databases get
+// negation-aware conflict detection without re-importing.
+func (s *SQLiteStore) migrateFactNegatedColumn() error {
+	var count int
+	if err := s.db.QueryRow(
+		"SELECT COUNT(*) FROM pragma_table_info('facts') WHERE name='negated'",
+	).Scan(&count); err != nil {
+		return fmt.Errorf("checking for negated column: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	if _, err := s.db.Exec(`ALTER TABLE facts ADD COLUMN negated INTEGER NOT NULL DEFAULT 0`); err != nil && !isDuplicateColumnError(err) {
+		return fmt.Errorf("adding negated column: %w", err)
+	}
+
+	// Only rows mentioning a negation word can be negated; skip the rest.
+	rows, err := s.db.Query(`SELECT id, COALESCE(predicate, ''), COALESCE(object, '') FROM facts
+		WHERE LOWER(predicate) LIKE '%n''t%' OR LOWER(predicate) LIKE '%not%'
+		   OR LOWER(predicate) LIKE '%never%' OR LOWER(predicate) LIKE '%no longer%'
+		   OR LOWER(object) LIKE 'not %' OR LOWER(object) LIKE 'never %' OR LOWER(object) LIKE 'no longer %'`)
+	if err != nil {
+		return fmt.Errorf("scanning facts for negations: %w", err)
+	}
+	var negatedIDs []int64
+	for rows.Next() {
+		var id int64
+		var predicate, object string
+		if err := rows.Scan(&id, &predicate, &object); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning fact polarity: %w", err)
+		}
+		if extract.IsNegated(predicate, object) {
+			negatedIDs = append(negatedIDs, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, id := range negatedIDs {
+		if _, err := s.db.Exec(`UPDATE facts SET negated = 1 WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("backfilling negated flag for fact %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// polarityKey is the positive form of a fact's predicate and object, so a
+// fact and its negation share a key.
+func polarityKey(f Fact) string {
+	predicate, object, _ := extract.ParseNegation(f.Predicate, f.Object)
+	return strings.ToLower(CanonicalPredicate(predicate)) + "\x00" + object
+}
+
+// negationConflicts pairs negated facts with positive facts about the same
+// subject that share their positive form. filters are extra AND clauses on
+// alias f (state, subject denylists); unlike attribute conflicts they do not
+// exclude multi-valued predicates, since "uses redis" and "does not use
+// redis" contradict each other regardless.
+func (s *SQLiteStore) negationConflicts(ctx context.Context, filters string, args []any, limit int) ([]Conflict, error) {
+	query := fmt.Sprintf(`SELECT f.id, f.memory_id, f.subject, f.predicate, f.object, f.fact_type,
+		        f.confidence, f.decay_rate, f.last_reinforced, f.source_quote, f.created_at, f.state, f.superseded_by, f.agent_id, f.negated
+		 FROM facts f
+		 JOIN memories m ON f.memory_id = m.id AND m.deleted_at IS NULL
+		 WHERE f.subject != '' AND f.subject IS NOT NULL
+		   AND f.confidence > 0
+		   AND LOWER(f.subject) IN (SELECT LOWER(n.subject) FROM facts n WHERE n.negated = 1 AND n.state != 'retired')
+		   %s
+		 ORDER BY LOWER(f.subject), f.created_at DESC, f.id DESC`, filters)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("finding negation candidates: %w", err)
+	}
+	defer rows.Close()
+
+	type bucket struct {
+		positive []Fact
+		negated  []Fact
+	}
+	bySubject := map[string]map[string]*bucket{}
+	var buckets []*bucket // first-seen order, for a stable limit cut-off
+	for rows.Next() {
+		var f Fact
+		var supersededBy sql.NullInt64
+		if err := rows.Scan(
+			&f.ID, &f.MemoryID, &f.Subject, &f.Predicate, &f.Object, &f.FactType,
+			&f.Confidence, &f.DecayRate, &f.LastReinforced, &f.SourceQuote, &f.CreatedAt, &f.State, &supersededBy, &f.AgentID, &f.Negated,
+		); err != nil {
+			return nil, fmt.Errorf("scanning negation candidate: %w", err)
+		}
+		if supersededBy.Valid {
+			v := supersededBy.Int64
+			f.SupersededBy = &v
+		}
+		subject := strings.ToLower(f.Subject)
+		keys, ok := bySubject[subject]
+		if !ok {
+			keys = map[string]*bucket{}
+			bySubject[subject] = keys
+		}
+		key := polarityKey(f)
+		b, ok := keys[key]
+		if !ok {
+			b = &bucket{}
+			keys[key] = b
+			buckets = append(buckets, b)
+		}
+		if f.Negated {
+			b.negated = append(b.negated, f)
+		} else {
+			b.positive = append(b.positive, f)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var conflicts []Conflict
+	for _, b := range buckets {
+		for _, pos := range b.positive {
+			for _, neg := range b.negated {
+				if len(conflicts) >= limit {
+					return conflicts, nil
+				}
+				conflicts = append(conflicts, Conflict{
+					Fact1:        pos,
+					Fact2:        neg,
+					ConflictType: "negation",
+					Similarity:   1.0,
+					Severity:     ConflictSeverityHigh,
+					CrossAgent:   pos.AgentID != neg.AgentID && (pos.AgentID != "" || neg.AgentID != ""),
+				})
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+func conflictPairKey(a, b int64) [2]int64 {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int64{a, b}
+}
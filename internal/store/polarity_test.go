@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetAttributeConflicts_DirectNegation(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "settings", SourceFile: "settings.md"})
+
+	posID, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "dark mode", Predicate: "is", Object: "enabled", FactType: "state"})
+	negID, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "Dark Mode", Predicate: "is not", Object: "enabled", FactType: "state", AgentID: "hawk"})
+	// Same predicate, negated object: caught as a negation, not an attribute conflict.
+	statusID, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "backup", Predicate: "status", Object: "active", FactType: "state"})
+	notStatusID, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "backup", Predicate: "status", Object: "no longer active", FactType: "state"})
+	// "uses" is multi-valued, so only the negation is reported.
+	s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "api", Predicate: "uses", Object: "redis", FactType: "relationship"})
+	s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "api", Predicate: "uses", Object: "postgres", FactType: "relationship"})
+	s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "api", Predicate: "does not use", Object: "redis", FactType: "relationship"})
+
+	if f, _ := s.GetFact(ctx, negID); !f.Negated {
+		t.Fatal("AddFact should flag negated facts")
+	}
+	if f, _ := s.GetFact(ctx, posID); f.Negated {
+		t.Fatal("positive fact flagged as negated")
+	}
+
+	conflicts, err := s.GetAttributeConflicts(ctx)
+	if err != nil {
+		t.Fatalf("GetAttributeConflicts: %v", err)
+	}
+	if len(conflicts) != 3 {
+		t.Fatalf("expected 3 negation conflicts, got %d: %+v", len(conflicts), conflicts)
+	}
+	pairs := map[[2]int64]Conflict{}
+	for _, c := range conflicts {
+		if c.ConflictType != "negation" || c.Severity != ConflictSeverityHigh || c.Fact1.Negated || !c.Fact2.Negated {
+			t.Fatalf("unexpected conflict: %+v", c)
+		}
+		pairs[conflictPairKey(c.Fact1.ID, c.Fact2.ID)] = c
+	}
+	if c, ok := pairs[conflictPairKey(posID, negID)]; !ok || !c.CrossAgent {
+		t.Fatalf("expected cross-agent dark mode negation, got %+v", pairs)
+	}
+	if _, ok := pairs[conflictPairKey(statusID, notStatusID)]; !ok {
+		t.Fatalf("expected status negation, got %+v", pairs)
+	}
+}
+
+func TestCheckConflictsForFact_Negation(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "settings", SourceFile: "settings.md"})
+
+	s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "sso", Predicate: "is", Object: "enabled", FactType: "state"})
+	fact := &Fact{MemoryID: memID, Subject: "sso", Predicate: "isn't", Object: "enabled", FactType: "state"}
+	fact.ID, _ = s.AddFact(ctx, fact)
+
+	conflicts, err := s.CheckConflictsForFact(ctx, fact)
+	if err != nil {
+		t.Fatalf("CheckConflictsForFact: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].ConflictType != "negation" || conflicts[0].Severity != ConflictSeverityHigh {
+		t.Fatalf("expected one high-severity negation conflict, got %+v", conflicts)
+	}
+}
+
+func TestMigrateFactNegatedColumn_Backfills(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "settings", SourceFile: "settings.md"})
+	negID, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "sso", Predicate: "is", Object: "not enabled", FactType: "state"})
+	posID, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "sso", Predicate: "notes", Object: "enabled", FactType: "state"})
+
+	if _, err := s.db.Exec(`ALTER TABLE facts DROP COLUMN negated`); err != nil {
+		t.Fatalf("dropping negated column: %v", err)
+	}
+	if err := s.migrateFactNegatedColumn(); err != nil {
+		t.Fatalf("migrateFactNegatedColumn: %v", err)
+	}
+	if f, _ := s.GetFact(ctx, negID); !f.Negated {
+		t.Fatal("expected existing negated fact to be backfilled")
+	}
+	if f, _ := s.GetFact(ctx, posID); f.Negated {
+		t.Fatal("positive fact backfilled as negated")
+	}
+}
+
+func TestGetAttributeConflicts_StaysInNamespace(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+	work := WithNamespace(ctx, "work")
+	personal := WithNamespace(ctx, "personal")
+
+	workMem, _ := s.AddMemory(work, &Memory{Content: "work infra", SourceFile: "infra.md"})
+	personalMem, _ := s.AddMemory(personal, &Memory{Content: "home lab", SourceFile: "infra.md"})
+	a, _ := s.AddFact(ctx, &Fact{MemoryID: workMem, Subject: "deploy", Predicate: "region", Object: "us-east-1", FactType: "kv"})
+	b, _ := s.AddFact(ctx, &Fact{MemoryID: workMem, Subject: "deploy", Predicate: "region", Object: "us-west-2", FactType: "kv"})
+	s.AddFact(ctx, &Fact{MemoryID: personalMem, Subject: "deploy", Predicate: "region", Object: "eu-west-2", FactType: "kv"})
+	s.AddFact(ctx, &Fact{MemoryID: workMem, Subject: "vpn", Predicate: "is", Object: "enabled", FactType: "state"})
+	neg := &Fact{MemoryID: personalMem, Subject: "vpn", Predicate: "is not", Object: "enabled", FactType: "state"}
+	neg.ID, _ = s.AddFact(ctx, neg)
+
+	conflicts, err := s.GetAttributeConflicts(work)
+	if err != nil {
+		t.Fatalf("GetAttributeConflicts: %v", err)
+	}
+	if len(conflicts) != 1 || conflictPairKey(conflicts[0].Fact1.ID, conflicts[0].Fact2.ID) != conflictPairKey(a, b) {
+		t.Fatalf("work conflicts = %+v, want only %d vs %d", conflicts, a, b)
+	}
+	if conflicts, _ := s.GetAttributeConflicts(personal); len(conflicts) != 0 {
+		t.Fatalf("personal conflicts = %+v, want none", conflicts)
+	}
+	if conflicts, _ := s.CheckConflictsForFact(personal, neg); len(conflicts) != 0 {
+		t.Fatalf("personal negation matched a work fact: %+v", conflicts)
+	}
+	if conflicts, _ := s.GetAttributeConflicts(WithNamespace(ctx, AllNamespaces)); len(conflicts) < 3 {
+		t.Fatalf("all-namespace conflicts = %d, want every pair", len(conflicts))
+	}
+}
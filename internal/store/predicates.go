@@ -0,0 +1,237 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultPredicateAliases maps predicate phrasings that extraction produces
+// interchangeably onto one canonical predicate, so graph edges and conflict
+// detection see "uses" rather than "uses", "is using", and "utilizes".
+// Keys are compared after predicateAliasKey normalization.
+var defaultPredicateAliases = map[string]string{
+	"is using":     "uses",
+	"using":        "uses",
+	"utilizes":     "uses",
+	"utilises":     "uses",
+	"makes use of": "uses",
+
+	"works for":            "works at",
+	"is working at":        "works at",
+	"is working for":       "works at",
+	"is employed at":       "works at",
+	"is employed by":       "works at",
+	"employed at":          "works at",
+	"employed by":          "works at",
+	"resides in":           "lives in",
+	"is living in":         "lives in",
+	"living in":            "lives in",
+	"is located in":        "located in",
+	"is situated in":       "located in",
+	"situated in":          "located in",
+	"relies on":            "depends on",
+	"relies upon":          "depends on",
+	"depends upon":         "depends on",
+	"is dependent on":      "depends on",
+	"is part of":           "part of",
+	"is a part of":         "part of",
+	"was created by":       "created by",
+	"is created by":        "created by",
+	"is related to":        "related to",
+	"relates to":           "related to",
+	"is married to":        "married to",
+	"is the owner of":      "owns",
+	"is owner of":          "owns",
+	"has preference for":   "prefers",
+	"has a preference for": "prefers",
+}
+
+var (
+	predicateAliasesMu sync.RWMutex
+	predicateAliases   = clonePredicateAliases(defaultPredicateAliases)
+)
+
+func clonePredicateAliases(in map[string]string) map[string]string {
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+// predicateAliasKey normalizes a predicate for alias lookup: lowercase, with
+// underscores, hyphens, and runs of whitespace collapsed to single spaces.
+func predicateAliasKey(predicate string) string {
+	p := strings.ToLower(strings.TrimSpace(predicate))
+	p = strings.NewReplacer("_", " ", "-", " ").Replace(p)
+	return strings.Join(strings.Fields(p), " ")
+}
+
+// SetPredicateAliases installs policies.predicate_aliases on top of the
+// built-in table. Mapping an alias to "" (or to itself) removes a built-in.
+func SetPredicateAliases(overrides map[string]string) {
+	aliases := clonePredicateAliases(defaultPredicateAliases)
+	for k, v := range overrides {
+		key := predicateAliasKey(k)
+		if key == "" {
+			continue
+		}
+		val := strings.TrimSpace(v)
+		if val == "" || predicateAliasKey(val) == key {
+			delete(aliases, key)
+			continue
+		}
+		aliases[key] = val
+	}
+	predicateAliasesMu.Lock()
+	predicateAliases = aliases
+	predicateAliasesMu.Unlock()
+}
+
+// PredicateAliases returns a copy of the active alias table.
+func PredicateAliases() map[string]string {
+	predicateAliasesMu.RLock()
+	defer predicateAliasesMu.RUnlock()
+	return clonePredicateAliases(predicateAliases)
+}
+
+// IsBuiltinPredicateAlias reports whether alias → canonical comes from the
+// built-in table rather than config.
+func IsBuiltinPredicateAlias(alias, canonical string) bool {
+	return defaultPredicateAliases[predicateAliasKey(alias)] == canonical
+}
+
+// CanonicalPredicate returns the canonical form of predicate, or predicate
+// unchanged when it has no alias.
+func CanonicalPredicate(predicate string) string {
+	predicateAliasesMu.RLock()
+	canonical, ok := predicateAliases[predicateAliasKey(predicate)]
+	predicateAliasesMu.RUnlock()
+	if ok {
+		return canonical
+	}
+	return predicate
+}
+
+// PredicateUsage is a distinct predicate and how many facts use it.
+type PredicateUsage struct {
+	Predicate string `json:"predicate"`
+	Facts     int    `json:"facts"`
+}
+
+// PredicateRename is one predicate rewrite applied (or planned) by
+// NormalizePredicates.
+type PredicateRename struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Facts int    `json:"facts"`
+}
+
+// PredicateNormalizeOptions controls NormalizePredicates.
+type PredicateNormalizeOptions struct {
+	DryRun bool `json:"dry_run"`
+	// Extra aliases applied for this run on top of the active table, e.g.
+	// mappings suggested by an LLM that have not been saved to config.
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// PredicateNormalizeReport summarizes a NormalizePredicates run.
+type PredicateNormalizeReport struct {
+	DistinctPredicates int               `json:"distinct_predicates"`
+	FactsUpdated       int               `json:"facts_updated"`
+	Renames            []PredicateRename `json:"renames"`
+}
+
+// ListPredicateUsage returns distinct active-fact predicates, most used first.
+func (s *SQLiteStore) ListPredicateUsage(ctx context.Context, limit int) ([]PredicateUsage, error) {
+	query := `SELECT predicate, COUNT(*) AS n FROM facts WHERE superseded_by IS NULL GROUP BY predicate ORDER BY n DESC, predicate ASC`
+	var args []interface{}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing predicates: %w", err)
+	}
+	defer rows.Close()
+
+	var out []PredicateUsage
+	for rows.Next() {
+		var u PredicateUsage
+		if err := rows.Scan(&u.Predicate, &u.Facts); err != nil {
+			return nil, fmt.Errorf("scanning predicate row: %w", err)
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// NormalizePredicates rewrites stored predicates to their canonical form —
+// the same mapping AddFact applies to new facts — so facts extracted before
+// an alias existed join the shared vocabulary. Superseded facts are rewritten
+// too, keeping fact history consistent.
+func (s *SQLiteStore) NormalizePredicates(ctx context.Context, opts PredicateNormalizeOptions) (*PredicateNormalizeReport, error) {
+	extra := map[string]string{}
+	for k, v := range opts.Extra {
+		if key, val := predicateAliasKey(k), strings.TrimSpace(v); key != "" && val != "" {
+			extra[key] = val
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT predicate, COUNT(*) FROM facts GROUP BY predicate`)
+	if err != nil {
+		return nil, fmt.Errorf("listing predicates: %w", err)
+	}
+	report := &PredicateNormalizeReport{Renames: []PredicateRename{}}
+	for rows.Next() {
+		var predicate string
+		var n int
+		if err := rows.Scan(&predicate, &n); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning predicate row: %w", err)
+		}
+		report.DistinctPredicates++
+		canonical, ok := extra[predicateAliasKey(predicate)]
+		if !ok {
+			canonical = CanonicalPredicate(predicate)
+		}
+		if canonical != predicate {
+			report.Renames = append(report.Renames, PredicateRename{From: predicate, To: canonical, Facts: n})
+			report.FactsUpdated += n
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterating predicates: %w", err)
+	}
+	rows.Close()
+
+	sort.Slice(report.Renames, func(i, j int) bool {
+		if report.Renames[i].Facts != report.Renames[j].Facts {
+			return report.Renames[i].Facts > report.Renames[j].Facts
+		}
+		return report.Renames[i].From < report.Renames[j].From
+	})
+	if opts.DryRun || len(report.Renames) == 0 {
+		return report, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("beginning predicate normalization: %w", err)
+	}
+	defer tx.Rollback()
+	for _, r := range report.Renames {
+		if _, err := tx.ExecContext(ctx, `UPDATE facts SET predicate = ? WHERE predicate = ? AND `+NotHeldFactClause, r.To, r.From); err != nil {
+			return nil, fmt.Errorf("renaming predicate %q to %q: %w", r.From, r.To, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing predicate normalization: %w", err)
+	}
+	return report, nil
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCanonicalPredicate_AliasesAndOverrides(t *testing.T) {
+	t.Cleanup(func() { SetPredicateAliases(nil) })
+
+	cases := map[string]string{
+		"is using":      "uses",
+		"Is_Using":      "uses",
+		" utilizes ":    "uses",
+		"works-for":     "works at",
+		"uses":          "uses",
+		"lives_in":      "lives_in", // no alias: left exactly as written
+		"favorite food": "favorite food",
+	}
+	for in, want := range cases {
+		if got := CanonicalPredicate(in); got != want {
+			t.Errorf("CanonicalPredicate(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	SetPredicateAliases(map[string]string{"Leverages": "uses", "works for": "", "lives_in": "lives in"})
+	if got := CanonicalPredicate("leverages"); got != "uses" {
+		t.Fatalf("config alias not applied, got %q", got)
+	}
+	if got := CanonicalPredicate("works for"); got != "works for" {
+		t.Fatalf("empty override should remove the built-in alias, got %q", got)
+	}
+	if got := CanonicalPredicate("lives_in"); got != "lives_in" {
+		t.Fatalf("alias to its own normalized form should be a no-op, got %q", got)
+	}
+	if !IsBuiltinPredicateAlias("utilizes", "uses") || IsBuiltinPredicateAlias("leverages", "uses") {
+		t.Fatal("IsBuiltinPredicateAlias misreported origin")
+	}
+}
+
+func TestNormalizePredicates_InsertAndRetroactive(t *testing.T) {
+	t.Cleanup(func() { SetPredicateAliases(nil) })
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "stack notes"})
+	insertID, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "api", Predicate: "is using", Object: "postgres", FactType: "relationship"})
+	if f, _ := s.GetFact(ctx, insertID); f.Predicate != "uses" {
+		t.Fatalf("AddFact should canonicalize predicates, got %q", f.Predicate)
+	}
+
+	// Simulate facts written before an alias existed.
+	legacyID, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "worker", Predicate: "leverages", Object: "redis", FactType: "relationship"})
+	s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "cli", Predicate: "harnesses", Object: "cobra", FactType: "relationship"})
+	SetPredicateAliases(map[string]string{"leverages": "uses"})
+
+	report, err := s.NormalizePredicates(ctx, PredicateNormalizeOptions{DryRun: true, Extra: map[string]string{"Harnesses": "uses"}})
+	if err != nil {
+		t.Fatalf("NormalizePredicates dry run: %v", err)
+	}
+	if report.DistinctPredicates != 3 || report.FactsUpdated != 2 || len(report.Renames) != 2 {
+		t.Fatalf("unexpected dry-run report: %+v", report)
+	}
+	if f, _ := s.GetFact(ctx, legacyID); f.Predicate != "leverages" {
+		t.Fatal("dry run must not rewrite predicates")
+	}
+
+	if _, err := s.NormalizePredicates(ctx, PredicateNormalizeOptions{}); err != nil {
+		t.Fatalf("NormalizePredicates: %v", err)
+	}
+	if f, _ := s.GetFact(ctx, legacyID); f.Predicate != "uses" {
+		t.Fatalf("expected legacy predicate rewritten, got %q", f.Predicate)
+	}
+	usage, err := s.ListPredicateUsage(ctx, 0)
+	if err != nil {
+		t.Fatalf("ListPredicateUsage: %v", err)
+	}
+	if len(usage) != 2 || usage[0].Predicate != "uses" || usage[0].Facts != 2 || usage[1].Predicate != "harnesses" {
+		t.Fatalf("unexpected usage after normalization: %+v", usage)
+	}
+}
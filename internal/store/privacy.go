@@ -0,0 +1,267 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Privacy restrictions a project can carry. Each one keeps the project's
+// memories and facts off one path out of the machine.
+const (
+	PrivacyNoExport     = "no-export"      // cortex export, alert webhooks
+	PrivacyNoMCP        = "no-mcp"         // MCP tools and resources
+	PrivacyLocalLLMOnly = "local-llm-only" // only local (ollama) models see the content
+)
+
+// PrivacyRestrictions lists the valid restrictions.
+var PrivacyRestrictions = []string{PrivacyNoExport, PrivacyNoMCP, PrivacyLocalLLMOnly}
+
+// ProjectPrivacy is a project's privacy zone, set with
+// `cortex projects privacy set`.
+type ProjectPrivacy struct {
+	Project      string    `json:"project"`
+	NoExport     bool      `json:"no_export"`
+	NoMCP        bool      `json:"no_mcp"`
+	LocalLLMOnly bool      `json:"local_llm_only"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+}
+
+// Restricts reports whether p carries restriction r.
+func (p ProjectPrivacy) Restricts(r string) bool {
+	switch r {
+	case PrivacyNoExport:
+		return p.NoExport
+	case PrivacyNoMCP:
+		return p.NoMCP
+	case PrivacyLocalLLMOnly:
+		return p.LocalLLMOnly
+	}
+	return false
+}
+
+// Restrictions returns the restrictions p carries, in PrivacyRestrictions
+// order.
+func (p ProjectPrivacy) Restrictions() []string {
+	var out []string
+	for _, r := range PrivacyRestrictions {
+		if p.Restricts(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// IsPrivacyRestriction reports whether r names a restriction.
+func IsPrivacyRestriction(r string) bool {
+	for _, known := range PrivacyRestrictions {
+		if r == known {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	privacyMu    sync.RWMutex
+	privacyZones = map[string]ProjectPrivacy{} // rows of the project_privacy table
+)
+
+// ProjectPrivacyFor returns the active privacy zone for project. Untagged
+// memories (project "") can be restricted too.
+func ProjectPrivacyFor(project string) ProjectPrivacy {
+	privacyMu.RLock()
+	defer privacyMu.RUnlock()
+	if p, ok := privacyZones[project]; ok {
+		return p
+	}
+	return ProjectPrivacy{Project: project}
+}
+
+// ProjectAllows reports whether project's content may take the path that
+// restriction r guards.
+func ProjectAllows(project, r string) bool {
+	return !ProjectPrivacyFor(project).Restricts(r)
+}
+
+// ProjectsRestricting returns the projects carrying restriction r, sorted.
+func ProjectsRestricting(r string) []string {
+	privacyMu.RLock()
+	defer privacyMu.RUnlock()
+	var out []string
+	for name, p := range privacyZones {
+		if p.Restricts(r) {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// migrateProjectPrivacyTable creates the table behind `cortex projects
+// privacy set`.
+func (s *SQLiteStore) migrateProjectPrivacyTable() error {
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS project_privacy (
+		project        TEXT PRIMARY KEY,
+		no_export      INTEGER NOT NULL DEFAULT 0,
+		no_mcp         INTEGER NOT NULL DEFAULT 0,
+		local_llm_only INTEGER NOT NULL DEFAULT 0,
+		updated_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return fmt.Errorf("creating project_privacy table: %w", err)
+	}
+	return nil
+}
+
+// loadProjectPrivacy installs the project_privacy table as the active zones.
+// A missing table (read-only access to an older database) means none.
+func (s *SQLiteStore) loadProjectPrivacy(ctx context.Context) error {
+	list, err := s.ListProjectPrivacy(ctx)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such table") {
+			return nil
+		}
+		return err
+	}
+	zones := make(map[string]ProjectPrivacy, len(list))
+	for _, p := range list {
+		zones[p.Project] = p
+	}
+	privacyMu.Lock()
+	privacyZones = zones
+	privacyMu.Unlock()
+	return nil
+}
+
+// ListProjectPrivacy returns every project with at least one restriction,
+// sorted by name.
+func (s *SQLiteStore) ListProjectPrivacy(ctx context.Context) ([]ProjectPrivacy, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT project, no_export, no_mcp, local_llm_only, updated_at FROM project_privacy ORDER BY project`)
+	if err != nil {
+		return nil, fmt.Errorf("listing project privacy: %w", err)
+	}
+	defer rows.Close()
+	var out []ProjectPrivacy
+	for rows.Next() {
+		var p ProjectPrivacy
+		if err := rows.Scan(&p.Project, &p.NoExport, &p.NoMCP, &p.LocalLLMOnly, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning project privacy: %w", err)
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// SetProjectPrivacy stores a project's privacy zone and makes it active. A
+// zone with no restrictions removes the project's entry.
+func (s *SQLiteStore) SetProjectPrivacy(ctx context.Context, p ProjectPrivacy) error {
+	p.Project = strings.TrimSpace(p.Project)
+	if len(p.Restrictions()) == 0 {
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM project_privacy WHERE project = ?`, p.Project); err != nil {
+			return fmt.Errorf("clearing privacy for project %q: %w", p.Project, err)
+		}
+		return s.loadProjectPrivacy(ctx)
+	}
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO project_privacy (project, no_export, no_mcp, local_llm_only, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(project) DO UPDATE SET
+			no_export = excluded.no_export,
+			no_mcp = excluded.no_mcp,
+			local_llm_only = excluded.local_llm_only,
+			updated_at = excluded.updated_at`,
+		p.Project, p.NoExport, p.NoMCP, p.LocalLLMOnly, time.Now().UTC(),
+	); err != nil {
+		return fmt.Errorf("saving privacy for project %q: %w", p.Project, err)
+	}
+	return s.loadProjectPrivacy(ctx)
+}
+
+// factsAllow reports whether none of the given facts belongs to a project
+// carrying restriction r. A fact's project is its memory's project, which
+// `cortex tag` can change after extraction.
+func (s *SQLiteStore) factsAllow(ctx context.Context, r string, factIDs ...*int64) (bool, error) {
+	if len(ProjectsRestricting(r)) == 0 {
+		return true, nil
+	}
+	for _, id := range factIDs {
+		if id == nil {
+			continue
+		}
+		var project string
+		err := s.db.QueryRowContext(ctx,
+			`SELECT m.project FROM facts f JOIN memories m ON m.id = f.memory_id WHERE f.id = ?`, *id,
+		).Scan(&project)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("looking up project of fact %d: %w", *id, err)
+		}
+		if !ProjectAllows(project, r) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// FilterMemoriesByPrivacy drops memories whose project carries restriction
+// r and reports how many were dropped.
+func FilterMemoriesByPrivacy(memories []*Memory, r string) ([]*Memory, int) {
+	if len(ProjectsRestricting(r)) == 0 {
+		return memories, 0
+	}
+	kept := memories[:0:0]
+	for _, m := range memories {
+		if m != nil && ProjectAllows(m.Project, r) {
+			kept = append(kept, m)
+		}
+	}
+	return kept, len(memories) - len(kept)
+}
+
+// FilterFactsByPrivacy drops facts whose memory's project carries
+// restriction r and reports how many were dropped.
+func (s *SQLiteStore) FilterFactsByPrivacy(ctx context.Context, facts []*Fact, r string) ([]*Fact, int, error) {
+	restricted := ProjectsRestricting(r)
+	if len(restricted) == 0 || len(facts) == 0 {
+		return facts, 0, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(restricted)), ",")
+	args := make([]interface{}, len(restricted))
+	for i, p := range restricted {
+		args[i] = p
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id FROM memories WHERE project IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing restricted memories: %w", err)
+	}
+	hidden := map[int64]bool{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, 0, fmt.Errorf("scanning restricted memory: %w", err)
+		}
+		hidden[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, 0, err
+	}
+	rows.Close()
+
+	kept := facts[:0:0]
+	for _, f := range facts {
+		if f != nil && !hidden[f.MemoryID] {
+			kept = append(kept, f)
+		}
+	}
+	return kept, len(facts) - len(kept), nil
+}
@@ -0,0 +1,145 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestProjectPrivacy_SetListClear(t *testing.T) {
+	si, err := NewStore(StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer si.Close()
+	s := si.(*SQLiteStore)
+	ctx := context.Background()
+
+	if err := s.SetProjectPrivacy(ctx, ProjectPrivacy{Project: "client-x", NoExport: true, LocalLLMOnly: true}); err != nil {
+		t.Fatalf("SetProjectPrivacy: %v", err)
+	}
+	if err := s.SetProjectPrivacy(ctx, ProjectPrivacy{Project: "personal", NoMCP: true}); err != nil {
+		t.Fatalf("SetProjectPrivacy: %v", err)
+	}
+
+	zones, err := s.ListProjectPrivacy(ctx)
+	if err != nil {
+		t.Fatalf("ListProjectPrivacy: %v", err)
+	}
+	if len(zones) != 2 || zones[0].Project != "client-x" || zones[1].Project != "personal" {
+		t.Fatalf("unexpected zones: %+v", zones)
+	}
+	if got := zones[0].Restrictions(); len(got) != 2 || got[0] != PrivacyNoExport || got[1] != PrivacyLocalLLMOnly {
+		t.Fatalf("unexpected client-x restrictions: %v", got)
+	}
+
+	if ProjectAllows("client-x", PrivacyNoExport) || !ProjectAllows("client-x", PrivacyNoMCP) {
+		t.Fatal("client-x zone not active")
+	}
+	if got := ProjectsRestricting(PrivacyNoMCP); len(got) != 1 || got[0] != "personal" {
+		t.Fatalf("ProjectsRestricting(no-mcp) = %v", got)
+	}
+	if !ProjectAllows("other", PrivacyNoExport) {
+		t.Fatal("unrestricted project should be allowed")
+	}
+
+	// A fresh handle loads the stored zones.
+	si2, err := NewStore(StoreConfig{DBPath: s.dbPath})
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if ProjectAllows("personal", PrivacyNoMCP) {
+		t.Fatal("zones not loaded on open")
+	}
+	si2.Close()
+
+	if err := s.SetProjectPrivacy(ctx, ProjectPrivacy{Project: "personal"}); err != nil {
+		t.Fatalf("clearing: %v", err)
+	}
+	if !ProjectAllows("personal", PrivacyNoMCP) {
+		t.Fatal("cleared zone still active")
+	}
+	if zones, _ := s.ListProjectPrivacy(ctx); len(zones) != 1 {
+		t.Fatalf("expected cleared zone to be removed, got %+v", zones)
+	}
+	if err := s.SetProjectPrivacy(ctx, ProjectPrivacy{Project: "client-x"}); err != nil {
+		t.Fatalf("clearing: %v", err)
+	}
+}
+
+func TestFilterByPrivacy(t *testing.T) {
+	si, err := NewStore(StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer si.Close()
+	s := si.(*SQLiteStore)
+	ctx := context.Background()
+
+	open, _ := s.AddMemory(ctx, &Memory{Content: "open memory", SourceFile: "a.md", Project: "work"})
+	secret, _ := s.AddMemory(ctx, &Memory{Content: "secret memory", SourceFile: "b.md", Project: "client-x"})
+	openFact, _ := s.AddFact(ctx, &Fact{MemoryID: open, Subject: "cortex", Predicate: "uses", Object: "sqlite", Confidence: 0.9, FactType: "kv"})
+	secretFact, _ := s.AddFact(ctx, &Fact{MemoryID: secret, Subject: "client", Predicate: "budget", Object: "$2M", Confidence: 0.9, FactType: "kv"})
+
+	if err := s.SetProjectPrivacy(ctx, ProjectPrivacy{Project: "client-x", NoExport: true}); err != nil {
+		t.Fatalf("SetProjectPrivacy: %v", err)
+	}
+	defer s.SetProjectPrivacy(ctx, ProjectPrivacy{Project: "client-x"})
+
+	memories, _ := s.GetMemoriesByIDs(ctx, []int64{open, secret})
+	kept, held := FilterMemoriesByPrivacy(memories, PrivacyNoExport)
+	if held != 1 || len(kept) != 1 || kept[0].ID != open {
+		t.Fatalf("FilterMemoriesByPrivacy kept %d, held %d", len(kept), held)
+	}
+	if _, held := FilterMemoriesByPrivacy(memories, PrivacyNoMCP); held != 0 {
+		t.Fatalf("no-mcp should not hold anything, held %d", held)
+	}
+
+	facts, _ := s.ListFacts(ctx, ListOpts{Limit: 10})
+	keptFacts, heldFacts, err := s.FilterFactsByPrivacy(ctx, facts, PrivacyNoExport)
+	if err != nil {
+		t.Fatalf("FilterFactsByPrivacy: %v", err)
+	}
+	if heldFacts != 1 || len(keptFacts) != 1 || keptFacts[0].ID != openFact {
+		t.Fatalf("FilterFactsByPrivacy kept %+v, held %d", keptFacts, heldFacts)
+	}
+
+	if ok, _ := s.factsAllow(ctx, PrivacyNoExport, &openFact, nil); !ok {
+		t.Fatal("open fact should be allowed")
+	}
+	if ok, _ := s.factsAllow(ctx, PrivacyNoExport, &openFact, &secretFact); ok {
+		t.Fatal("pair with a no-export fact should be held")
+	}
+}
+
+func TestCreateAlert_NoExportSkipsWebhook(t *testing.T) {
+	si, err := NewStore(StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer si.Close()
+	s := si.(*SQLiteStore)
+	ctx := context.Background()
+
+	mem, _ := s.AddMemory(ctx, &Memory{Content: "secret memory", SourceFile: "b.md", Project: "client-x"})
+	factID, _ := s.AddFact(ctx, &Fact{MemoryID: mem, Subject: "client", Predicate: "budget", Object: "$2M", Confidence: 0.9, FactType: "kv"})
+	if err := s.SetProjectPrivacy(ctx, ProjectPrivacy{Project: "client-x", NoExport: true}); err != nil {
+		t.Fatalf("SetProjectPrivacy: %v", err)
+	}
+	defer s.SetProjectPrivacy(ctx, ProjectPrivacy{Project: "client-x"})
+
+	s.Webhook = NewWebhookNotifier(&WebhookConfig{URL: "http://127.0.0.1:1/hook"})
+	if err := s.CreateAlert(ctx, &Alert{AlertType: AlertTypeConflict, Severity: AlertSeverityWarning, FactID: &factID, Message: "client budget conflict"}); err != nil {
+		t.Fatalf("CreateAlert: %v", err)
+	}
+	if pending := s.Webhook.takePending(); len(pending) != 0 {
+		t.Fatalf("alert about a no-export fact was queued for the webhook: %+v", pending)
+	}
+
+	if err := s.CreateAlert(ctx, &Alert{AlertType: AlertTypeConflict, Severity: AlertSeverityWarning, Message: "unrelated"}); err != nil {
+		t.Fatalf("CreateAlert: %v", err)
+	}
+	if pending := s.Webhook.takePending(); len(pending) != 1 {
+		t.Fatalf("expected unrelated alert queued, got %d", len(pending))
+	}
+}
@@ -8,6 +8,7 @@ import (
 // ListProjects returns all project tags with their memory and fact counts.
 // Projects with empty name (untagged) are included as name="".
 func (s *SQLiteStore) ListProjects(ctx context.Context) ([]ProjectInfo, error) {
+	nsClause, nsArgs := s.namespaceFilter(ctx, "m.namespace")
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT
 			m.project,
@@ -15,10 +16,10 @@ func (s *SQLiteStore) ListProjects(ctx context.Context) ([]ProjectInfo, error) {
 			COUNT(f.id) as fact_count
 		FROM memories m
 		LEFT JOIN facts f ON f.memory_id = m.id
-		WHERE m.deleted_at IS NULL
+		WHERE m.deleted_at IS NULL`+nsClause+`
 		GROUP BY m.project
 		ORDER BY memory_count DESC
-	`)
+	`, nsArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("listing projects: %w", err)
 	}
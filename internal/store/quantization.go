@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/hurttlocker/cortex/internal/ann"
+)
+
+// metaEmbeddingQuantization records the per-store embedding encoding.
+const metaEmbeddingQuantization = "embedding_quantization"
+
+// EmbeddingQuantization returns the store's embedding encoding ("none" or "int8").
+func (s *SQLiteStore) EmbeddingQuantization(ctx context.Context) (string, error) {
+	var value string
+	err := s.db.QueryRowContext(ctx, "SELECT value FROM meta WHERE key = ?", metaEmbeddingQuantization).Scan(&value)
+	if err != nil || value == "" {
+		// Missing key (or a pre-quantization store) means full float32.
+		return string(ann.QuantizationNone), nil
+	}
+	q, perr := ann.ParseQuantization(value)
+	if perr != nil {
+		return "", fmt.Errorf("reading %s: %w", metaEmbeddingQuantization, perr)
+	}
+	return string(q), nil
+}
+
+// SetEmbeddingQuantization switches the store's embedding encoding and
+// re-encodes every stored vector in one transaction. Returns the number of
+// vectors rewritten. Quantizing is lossy: switching back to "none" restores
+// float32 storage but not the original precision.
+func (s *SQLiteStore) SetEmbeddingQuantization(ctx context.Context, mode string) (int64, error) {
+	q, err := ann.ParseQuantization(mode)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("beginning quantization migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT memory_id, vector, dimensions FROM embeddings")
+	if err != nil {
+		return 0, fmt.Errorf("reading embeddings: %w", err)
+	}
+	type pendingRow struct {
+		id   int64
+		blob []byte
+	}
+	var pending []pendingRow
+	for rows.Next() {
+		var id int64
+		var blob []byte
+		var dims int
+		if err := rows.Scan(&id, &blob, &dims); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scanning embedding: %w", err)
+		}
+		pending = append(pending, pendingRow{id: id, blob: encodeEmbedding(decodeEmbedding(blob, dims), q)})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "UPDATE embeddings SET vector = ? WHERE memory_id = ?")
+	if err != nil {
+		return 0, fmt.Errorf("preparing re-encode: %w", err)
+	}
+	defer stmt.Close()
+	for _, r := range pending {
+		if _, err := stmt.ExecContext(ctx, r.blob, r.id); err != nil {
+			return 0, fmt.Errorf("re-encoding embedding %d: %w", r.id, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT OR REPLACE INTO meta (key, value) VALUES (?, ?)", metaEmbeddingQuantization, string(q),
+	); err != nil {
+		return 0, fmt.Errorf("setting %s: %w", metaEmbeddingQuantization, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing quantization migration: %w", err)
+	}
+	return int64(len(pending)), nil
+}
+
+// EmbeddingBytes returns the total size of stored embedding blobs.
+func (s *SQLiteStore) EmbeddingBytes(ctx context.Context) (int64, error) {
+	var total int64
+	if err := s.db.QueryRowContext(ctx, "SELECT COALESCE(SUM(LENGTH(vector)), 0) FROM embeddings").Scan(&total); err != nil {
+		return 0, fmt.Errorf("summing embedding bytes: %w", err)
+	}
+	return total, nil
+}
+
+// encodeEmbedding serializes vec for the embeddings.vector column.
+//
+// float32: dims*4 bytes, little-endian.
+// int8:    scale(4, float32 LE) + dims int8 codes — dims+4 bytes.
+//
+// The two lengths never coincide for a positive dimension, so blobs are
+// self-describing given the dimensions column and mixed stores decode safely.
+func encodeEmbedding(vec []float32, q ann.Quantization) []byte {
+	if q != ann.QuantizationInt8 {
+		return float32ToBytes(vec)
+	}
+	codes, scale := ann.QuantizeInt8(vec)
+	buf := make([]byte, 4+len(codes))
+	binary.LittleEndian.PutUint32(buf, math.Float32bits(scale))
+	for i, c := range codes {
+		buf[4+i] = byte(c)
+	}
+	return buf
+}
+
+// decodeEmbedding reverses encodeEmbedding using the stored dimension count.
+func decodeEmbedding(blob []byte, dims int) []float32 {
+	if dims > 0 && len(blob) == dims+4 {
+		scale := math.Float32frombits(binary.LittleEndian.Uint32(blob))
+		codes := make([]int8, dims)
+		for i := range codes {
+			codes[i] = int8(blob[4+i])
+		}
+		return ann.DequantizeInt8(codes, scale)
+	}
+	return bytesToFloat32(blob)
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestEmbeddingQuantization_DefaultNone(t *testing.T) {
+	s := newTestStore(t).(*SQLiteStore)
+	q, err := s.EmbeddingQuantization(context.Background())
+	if err != nil || q != "none" {
+		t.Fatalf("EmbeddingQuantization = %q, %v; want none", q, err)
+	}
+}
+
+func TestSetEmbeddingQuantization_ReencodesAndDecodes(t *testing.T) {
+	s := newTestStore(t).(*SQLiteStore)
+	ctx := context.Background()
+
+	vec := []float32{0.9, -0.3, 0.1, 0.0, 0.45, -0.8}
+	id, err := s.AddMemory(ctx, &Memory{Content: "quantize me", SourceFile: "q.md"})
+	if err != nil {
+		t.Fatalf("AddMemory: %v", err)
+	}
+	if err := s.AddEmbedding(ctx, id, vec); err != nil {
+		t.Fatalf("AddEmbedding: %v", err)
+	}
+	before, _ := s.EmbeddingBytes(ctx)
+	if before != int64(len(vec)*4) {
+		t.Fatalf("float32 blob = %d bytes, want %d", before, len(vec)*4)
+	}
+
+	n, err := s.SetEmbeddingQuantization(ctx, "int8")
+	if err != nil || n != 1 {
+		t.Fatalf("SetEmbeddingQuantization = %d, %v", n, err)
+	}
+	after, _ := s.EmbeddingBytes(ctx)
+	if after != int64(len(vec)+4) {
+		t.Fatalf("int8 blob = %d bytes, want %d", after, len(vec)+4)
+	}
+
+	got, err := s.GetEmbedding(ctx, id)
+	if err != nil {
+		t.Fatalf("GetEmbedding: %v", err)
+	}
+	if len(got) != len(vec) {
+		t.Fatalf("decoded dims = %d, want %d", len(got), len(vec))
+	}
+	for i := range vec {
+		if math.Abs(float64(got[i]-vec[i])) > 0.01 {
+			t.Fatalf("component %d: %f decoded as %f", i, vec[i], got[i])
+		}
+	}
+
+	// New embeddings follow the store mode.
+	id2, _ := s.AddMemory(ctx, &Memory{Content: "second", SourceFile: "q.md", SourceLine: 2})
+	if err := s.AddEmbedding(ctx, id2, vec); err != nil {
+		t.Fatalf("AddEmbedding after quantize: %v", err)
+	}
+	total, _ := s.EmbeddingBytes(ctx)
+	if total != 2*int64(len(vec)+4) {
+		t.Fatalf("new embedding not stored as int8: total %d bytes", total)
+	}
+
+	// Semantic search still ranks quantized vectors.
+	results, err := s.SearchEmbedding(ctx, vec, 5, 0.9)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("SearchEmbedding = %d results, %v", len(results), err)
+	}
+
+	// Switching back restores float32 storage.
+	if _, err := s.SetEmbeddingQuantization(ctx, "none"); err != nil {
+		t.Fatalf("SetEmbeddingQuantization none: %v", err)
+	}
+	total, _ = s.EmbeddingBytes(ctx)
+	if total != 2*int64(len(vec)*4) {
+		t.Fatalf("expected float32 blobs after revert, got %d bytes", total)
+	}
+}
+
+func TestSetEmbeddingQuantization_RejectsUnknownMode(t *testing.T) {
+	s := newTestStore(t).(*SQLiteStore)
+	if _, err := s.SetEmbeddingQuantization(context.Background(), "pq"); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AlertTypeQuota is raised when a connector exceeds its ingestion quota and
+// is paused.
+const AlertTypeQuota AlertType = "quota"
+
+// migrateConnectorPauseColumns adds the columns ingestion quotas use to
+// pause a flooding connector, and an index for counting recent imports.
+func (s *SQLiteStore) migrateConnectorPauseColumns() error {
+	columns := []struct {
+		name string
+		stmt string
+	}{
+		{name: "paused_until", stmt: `ALTER TABLE connectors ADD COLUMN paused_until DATETIME`},
+		{name: "pause_reason", stmt: `ALTER TABLE connectors ADD COLUMN pause_reason TEXT NOT NULL DEFAULT ''`},
+	}
+	for _, col := range columns {
+		var count int
+		if err := s.db.QueryRow(
+			"SELECT COUNT(*) FROM pragma_table_info('connectors') WHERE name = ?", col.name,
+		).Scan(&count); err != nil {
+			return fmt.Errorf("checking for connectors.%s column: %w", col.name, err)
+		}
+		if count > 0 {
+			continue
+		}
+		if _, err := s.db.Exec(col.stmt); err != nil && !isDuplicateColumnError(err) {
+			return fmt.Errorf("adding connectors.%s column: %w", col.name, err)
+		}
+	}
+	if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_memories_imported_at ON memories(imported_at)`); err != nil {
+		return fmt.Errorf("creating imported_at index: %w", err)
+	}
+	return nil
+}
+
+// CountSourceImportsSince counts memories imported since the given time
+// whose source is source itself or starts with "source:" (how connectors
+// prefix their records). Soft-deleted memories count: quotas limit what was
+// imported, not what is left.
+func (s *SQLiteStore) CountSourceImportsSince(ctx context.Context, source string, since time.Time) (int, error) {
+	var n int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM memories
+		 WHERE imported_at >= ? AND (source_file = ? OR substr(source_file, 1, ?) = ?)`,
+		since.UTC(), source, len(source)+1, source+":",
+	).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting imports for %s: %w", source, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCountSourceImportsSince(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	for _, m := range []*Memory{
+		{Content: "thread one", SourceFile: "gmail:inbox/1", ContentHash: "h1"},
+		{Content: "thread two", SourceFile: "gmail:inbox/2", ContentHash: "h2"},
+		{Content: "bare source", SourceFile: "gmail", ContentHash: "h3"},
+		{Content: "lookalike", SourceFile: "gmailbackup:x", ContentHash: "h4"},
+		{Content: "other", SourceFile: "slack:general", ContentHash: "h5"},
+	} {
+		if _, err := s.AddMemory(ctx, m); err != nil {
+			t.Fatalf("AddMemory: %v", err)
+		}
+	}
+
+	since := time.Now().Add(-time.Hour)
+	n, err := s.CountSourceImportsSince(ctx, "gmail", since)
+	if err != nil {
+		t.Fatalf("CountSourceImportsSince: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("gmail imports = %d, want 3", n)
+	}
+
+	n, err = s.CountSourceImportsSince(ctx, "gmail", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("CountSourceImportsSince: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("imports after now = %d, want 0", n)
+	}
+}
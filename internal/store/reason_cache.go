@@ -0,0 +1,152 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// migrateReasonCacheTables creates the store change sequence and the
+// reason_cache table.
+//
+// store_change_seq holds one counter that triggers bump whenever memories,
+// facts, or embeddings change in a way that can change what a reason run
+// sees: rows added or removed, content, project, class, metadata, soft
+// deletes, and fact content, confidence, or lifecycle state. Reinforcement
+// (last_reinforced) is deliberately not tracked, since every search
+// reinforces the facts it recalls.
+//
+// reason_cache stores finished reason results keyed by their inputs and
+// tagged with the change sequence they were computed at. An entry whose
+// sequence is behind the store's is stale and is never served.
+func (s *SQLiteStore) migrateReasonCacheTables() error {
+	done, err := s.isMetaFlagEnabled("reason_cache_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	bump := `UPDATE store_change_seq SET seq = seq + 1 WHERE id = 1;`
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS store_change_seq (
+			id  INTEGER PRIMARY KEY CHECK (id = 1),
+			seq INTEGER NOT NULL DEFAULT 0
+		)`,
+		`INSERT OR IGNORE INTO store_change_seq (id, seq) VALUES (1, 0)`,
+
+		`CREATE TRIGGER IF NOT EXISTS memories_changeseq_ai AFTER INSERT ON memories BEGIN ` + bump + ` END`,
+		`CREATE TRIGGER IF NOT EXISTS memories_changeseq_ad AFTER DELETE ON memories BEGIN ` + bump + ` END`,
+		`CREATE TRIGGER IF NOT EXISTS memories_changeseq_au
+			AFTER UPDATE OF content, source_file, deleted_at, project, memory_class, metadata ON memories
+			BEGIN ` + bump + ` END`,
+		`CREATE TRIGGER IF NOT EXISTS facts_changeseq_ai AFTER INSERT ON facts BEGIN ` + bump + ` END`,
+		`CREATE TRIGGER IF NOT EXISTS facts_changeseq_ad AFTER DELETE ON facts BEGIN ` + bump + ` END`,
+		`CREATE TRIGGER IF NOT EXISTS facts_changeseq_au
+			AFTER UPDATE OF subject, predicate, object, fact_type, confidence, state, superseded_by ON facts
+			BEGIN ` + bump + ` END`,
+		`CREATE TRIGGER IF NOT EXISTS embeddings_changeseq_ai AFTER INSERT ON embeddings BEGIN ` + bump + ` END`,
+		`CREATE TRIGGER IF NOT EXISTS embeddings_changeseq_ad AFTER DELETE ON embeddings BEGIN ` + bump + ` END`,
+
+		`CREATE TABLE IF NOT EXISTS reason_cache (
+			cache_key  TEXT PRIMARY KEY,
+			preset     TEXT NOT NULL DEFAULT '',
+			query      TEXT NOT NULL DEFAULT '',
+			change_seq INTEGER NOT NULL,
+			result     TEXT NOT NULL,
+			hits       INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('reason_cache_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating reason cache tables: %w", err)
+		}
+	}
+	return nil
+}
+
+// ChangeSeq returns the store change sequence. It grows whenever memories,
+// facts, or embeddings change; equal values mean nothing a reason run reads
+// has changed in between.
+func (s *SQLiteStore) ChangeSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	err := s.db.QueryRowContext(ctx, `SELECT seq FROM store_change_seq WHERE id = 1`).Scan(&seq)
+	if err != nil {
+		return 0, fmt.Errorf("reading change sequence: %w", err)
+	}
+	return seq, nil
+}
+
+// ReasonCacheEntry is a cached reason result.
+type ReasonCacheEntry struct {
+	Key       string    `json:"key"`
+	Preset    string    `json:"preset"`
+	Query     string    `json:"query"`
+	ChangeSeq int64     `json:"change_seq"`
+	Result    string    `json:"-"` // JSON-encoded result
+	Hits      int       `json:"hits"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetReasonCache returns the cached result for key if it was computed at
+// change sequence seq and is younger than maxAge (0 = no age limit), and
+// counts the hit. It returns nil when there is no usable entry.
+func (s *SQLiteStore) GetReasonCache(ctx context.Context, key string, seq int64, maxAge time.Duration) (*ReasonCacheEntry, error) {
+	e := &ReasonCacheEntry{Key: key}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT preset, query, change_seq, result, hits, created_at FROM reason_cache WHERE cache_key = ?`, key,
+	).Scan(&e.Preset, &e.Query, &e.ChangeSeq, &e.Result, &e.Hits, &e.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading reason cache: %w", err)
+	}
+	if e.ChangeSeq != seq || (maxAge > 0 && time.Since(e.CreatedAt) > maxAge) {
+		return nil, nil
+	}
+	if _, err := s.db.ExecContext(ctx, `UPDATE reason_cache SET hits = hits + 1 WHERE cache_key = ?`, key); err != nil {
+		return nil, fmt.Errorf("updating reason cache: %w", err)
+	}
+	e.Hits++
+	return e, nil
+}
+
+// PutReasonCache stores a result computed at change sequence seq, and drops
+// entries from earlier sequences, which can no longer be served.
+func (s *SQLiteStore) PutReasonCache(ctx context.Context, e ReasonCacheEntry) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("writing reason cache: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM reason_cache WHERE change_seq < ?`, e.ChangeSeq); err != nil {
+		return fmt.Errorf("pruning reason cache: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT OR REPLACE INTO reason_cache (cache_key, preset, query, change_seq, result, hits, created_at)
+		 VALUES (?, ?, ?, ?, ?, 0, ?)`,
+		e.Key, e.Preset, e.Query, e.ChangeSeq, e.Result, time.Now().UTC(),
+	); err != nil {
+		return fmt.Errorf("writing reason cache: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("writing reason cache: %w", err)
+	}
+	return nil
+}
+
+// ClearReasonCache deletes every cached reason result and returns how many
+// there were.
+func (s *SQLiteStore) ClearReasonCache(ctx context.Context) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM reason_cache`)
+	if err != nil {
+		return 0, fmt.Errorf("clearing reason cache: %w", err)
+	}
+	return res.RowsAffected()
+}
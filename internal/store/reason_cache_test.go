@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestChangeSeq_TracksContentNotReinforcement(t *testing.T) {
+	s := newTestStore(t).(*SQLiteStore)
+	ctx := context.Background()
+
+	seq := func() int64 {
+		t.Helper()
+		v, err := s.ChangeSeq(ctx)
+		if err != nil {
+			t.Fatalf("ChangeSeq: %v", err)
+		}
+		return v
+	}
+	start := seq()
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "Deploys on Tuesday", SourceFile: "ops.md"})
+	afterMemory := seq()
+	if afterMemory <= start {
+		t.Fatal("adding a memory should bump the sequence")
+	}
+	factID, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "deploy", Predicate: "day", Object: "Tuesday", FactType: "kv", Confidence: 0.9})
+	afterFact := seq()
+	if afterFact <= afterMemory {
+		t.Fatal("adding a fact should bump the sequence")
+	}
+	if err := s.ReinforceFact(ctx, factID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ReinforceFactsByMemoryIDs(ctx, []int64{memID}); err != nil {
+		t.Fatal(err)
+	}
+	if seq() != afterFact {
+		t.Fatal("reinforcement should not bump the sequence")
+	}
+	if err := s.UpdateFactState(ctx, factID, FactStateRetired); err != nil {
+		t.Fatal(err)
+	}
+	if seq() <= afterFact {
+		t.Fatal("a fact state change should bump the sequence")
+	}
+}
+
+func TestReasonCache_ServesOnlyCurrentSequence(t *testing.T) {
+	s := newTestStore(t).(*SQLiteStore)
+	ctx := context.Background()
+
+	if err := s.PutReasonCache(ctx, ReasonCacheEntry{Key: "k1", Preset: "daily-digest", ChangeSeq: 5, Result: `{"content":"old"}`}); err != nil {
+		t.Fatal(err)
+	}
+	if e, err := s.GetReasonCache(ctx, "k1", 5, time.Hour); err != nil || e == nil || e.Result != `{"content":"old"}` || e.Hits != 1 {
+		t.Fatalf("expected a hit, got %+v, %v", e, err)
+	}
+	if e, _ := s.GetReasonCache(ctx, "k1", 6, time.Hour); e != nil {
+		t.Fatal("an entry from an older sequence must not be served")
+	}
+	if e, _ := s.GetReasonCache(ctx, "missing", 5, 0); e != nil {
+		t.Fatal("unknown key should miss")
+	}
+
+	if err := s.PutReasonCache(ctx, ReasonCacheEntry{Key: "k2", ChangeSeq: 6, Result: `{}`}); err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	s.db.QueryRow(`SELECT COUNT(*) FROM reason_cache`).Scan(&n)
+	if n != 1 {
+		t.Fatalf("stale entries should be pruned on write, have %d", n)
+	}
+	if cleared, err := s.ClearReasonCache(ctx); err != nil || cleared != 1 {
+		t.Fatalf("ClearReasonCache = %d, %v", cleared, err)
+	}
+}
@@ -0,0 +1,274 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// RecordImporter merges exported records (memories, then facts, edges, and
+// embeddings) into the store, e.g. from `cortex export --format jsonl --all`
+// on another machine. Memories are deduplicated by content hash and facts by
+// memory and triple. Records keep their exported IDs only in the remapping
+// table: every ID a record refers to is translated to the row it became
+// here, so edges, supersessions, and embeddings land on the right facts and
+// memories. Feed records in export order and call Finish at the end.
+type RecordImporter struct {
+	s        *SQLiteStore
+	dryRun   bool
+	memories map[int64]int64 // exported memory ID → ID here
+	facts    map[int64]int64 // exported fact ID → ID here
+	fresh    map[int64]bool  // memories this import inserted
+	// supersessions are exported (old, new) fact ID pairs, applied by
+	// Finish once both facts have been seen.
+	supersessions [][2]int64
+	maxFactID     int64 // facts above this were added by the import
+	dims          int   // embedding dimensions in the store (0 = none yet)
+	nextDryRunID  int64
+	res           RecordImportResult
+}
+
+// RecordImportResult counts what a RecordImporter did. In a dry run it
+// counts what it would have done.
+type RecordImportResult struct {
+	DryRun            bool `json:"dry_run,omitempty"`
+	MemoriesAdded     int  `json:"memories_added"`
+	MemoriesDuplicate int  `json:"memories_duplicate"`
+	FactsAdded        int  `json:"facts_added"`
+	FactsDuplicate    int  `json:"facts_duplicate"` // already stored (same memory and triple, or corroborated)
+	FactsOrphaned     int  `json:"facts_orphaned"`  // their memory wasn't in the import
+	Supersessions     int  `json:"supersessions"`   // superseded_by links restored
+	EdgesAdded        int  `json:"edges_added"`
+	EdgesDuplicate    int  `json:"edges_duplicate"`
+	EdgesSkipped      int  `json:"edges_skipped"` // an endpoint wasn't imported, or both became one fact
+	EmbeddingsAdded   int  `json:"embeddings_added"`
+	EmbeddingsSkipped int  `json:"embeddings_skipped"` // no imported memory, already embedded, or other dimensions
+}
+
+// NewRecordImporter starts an import. A dry run reads the store to find
+// duplicates but writes nothing.
+func (s *SQLiteStore) NewRecordImporter(ctx context.Context, dryRun bool) (*RecordImporter, error) {
+	im := &RecordImporter{
+		s:        s,
+		dryRun:   dryRun,
+		memories: map[int64]int64{},
+		facts:    map[int64]int64{},
+		fresh:    map[int64]bool{},
+		res:      RecordImportResult{DryRun: dryRun},
+	}
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM facts`).Scan(&im.maxFactID); err != nil {
+		return nil, fmt.Errorf("reading fact IDs: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, `SELECT dimensions FROM embeddings LIMIT 1`).Scan(&im.dims); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("reading embedding dimensions: %w", err)
+	}
+	return im, nil
+}
+
+// dryRunID stands in for the ID a dry run would have inserted.
+func (im *RecordImporter) dryRunID() int64 {
+	im.nextDryRunID--
+	return im.nextDryRunID
+}
+
+// ImportMemory adds m unless a memory with the same content and source
+// file is already stored. When ctx reads every namespace the memory goes
+// back into the namespace it was exported from; otherwise it lands in
+// ctx's namespace.
+func (im *RecordImporter) ImportMemory(ctx context.Context, m *Memory) error {
+	mem := *m
+	mem.ID, mem.ContentHash, mem.DeletedAt = 0, "", nil
+	if im.s.Namespace(ctx) == AllNamespaces {
+		ctx = WithNamespace(ctx, NormalizeNamespace(m.Namespace))
+	}
+	mem.Namespace = ""
+
+	existing, err := im.s.FindByHash(ctx, HashMemoryContent(mem.Content, mem.SourceFile))
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		im.memories[m.ID] = existing.ID
+		im.res.MemoriesDuplicate++
+		return nil
+	}
+	im.res.MemoriesAdded++
+	if im.dryRun {
+		im.memories[m.ID] = im.dryRunID()
+		return nil
+	}
+	id, err := im.s.AddMemory(ctx, &mem)
+	if err != nil {
+		return fmt.Errorf("importing memory %d: %w", m.ID, err)
+	}
+	if !m.ImportedAt.IsZero() {
+		if _, err := im.s.db.ExecContext(ctx,
+			`UPDATE memories SET imported_at = ?, updated_at = ? WHERE id = ?`,
+			m.ImportedAt, m.UpdatedAt, id); err != nil {
+			return fmt.Errorf("restoring timestamps of memory %d: %w", id, err)
+		}
+	}
+	im.memories[m.ID] = id
+	im.fresh[id] = true
+	return nil
+}
+
+// ImportFact adds f under the memory its MemoryID was imported as. A fact
+// whose memory was already stored is matched against that memory's facts
+// first; a new fact may still be corroborated into an existing one by
+// AddFact. Facts of memories not imported are counted and dropped.
+func (im *RecordImporter) ImportFact(ctx context.Context, f *Fact) error {
+	memID, ok := im.memories[f.MemoryID]
+	if !ok {
+		im.res.FactsOrphaned++
+		return nil
+	}
+	if f.SupersededBy != nil {
+		im.supersessions = append(im.supersessions, [2]int64{f.ID, *f.SupersededBy})
+	}
+	if memID > 0 && !im.fresh[memID] {
+		var id int64
+		err := im.s.db.QueryRowContext(ctx,
+			`SELECT id FROM facts WHERE memory_id = ? AND subject = ? AND predicate = ? AND object = ? ORDER BY id LIMIT 1`,
+			memID, f.Subject, CanonicalPredicate(f.Predicate), f.Object).Scan(&id)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("matching fact %d: %w", f.ID, err)
+		}
+		if id > 0 {
+			im.facts[f.ID] = id
+			im.res.FactsDuplicate++
+			return nil
+		}
+	}
+	if im.dryRun {
+		im.facts[f.ID] = im.dryRunID()
+		im.res.FactsAdded++
+		return nil
+	}
+
+	fact := *f
+	fact.ID, fact.MemoryID, fact.EntityID, fact.SupersededBy = 0, memID, 0, nil
+	if fact.State == FactStateSuperseded {
+		fact.State = FactStateActive // Finish restores the supersession
+	}
+	id, err := im.s.AddFact(ctx, &fact)
+	if err != nil {
+		return fmt.Errorf("importing fact %d: %w", f.ID, err)
+	}
+	im.facts[f.ID] = id
+	if id <= im.maxFactID {
+		im.res.FactsDuplicate++
+		return nil
+	}
+	im.res.FactsAdded++
+	if !f.CreatedAt.IsZero() {
+		if _, err := im.s.db.ExecContext(ctx,
+			`UPDATE facts SET created_at = ?, last_reinforced = ?, custom_type = ? WHERE id = ?`,
+			f.CreatedAt, f.LastReinforced, f.CustomType, id); err != nil {
+			return fmt.Errorf("restoring timestamps of fact %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// ImportEdge adds e between the facts its endpoints were imported as.
+func (im *RecordImporter) ImportEdge(ctx context.Context, e FactEdge) error {
+	src, okSrc := im.facts[e.SourceFactID]
+	dst, okDst := im.facts[e.TargetFactID]
+	if !okSrc || !okDst || src == dst {
+		im.res.EdgesSkipped++
+		return nil
+	}
+	if im.dryRun {
+		if src > 0 && dst > 0 {
+			existing, err := im.s.FindEdges(ctx, EdgeFilter{SourceFactID: src, TargetFactID: dst, Type: e.EdgeType})
+			if err != nil {
+				return err
+			}
+			if len(existing) > 0 {
+				im.res.EdgesDuplicate++
+				return nil
+			}
+		}
+		im.res.EdgesAdded++
+		return nil
+	}
+	err := im.s.AddEdge(ctx, &FactEdge{
+		SourceFactID: src, TargetFactID: dst, EdgeType: e.EdgeType,
+		Confidence: e.Confidence, Source: e.Source, AgentID: e.AgentID,
+	})
+	switch {
+	case errors.Is(err, ErrEdgeExists):
+		im.res.EdgesDuplicate++
+	case err != nil:
+		return fmt.Errorf("importing edge %d: %w", e.ID, err)
+	default:
+		im.res.EdgesAdded++
+	}
+	return nil
+}
+
+// ImportEmbedding stores e for the memory it was exported with. Memories
+// that already had an embedding keep it, and vectors whose dimensions
+// differ from the store's are skipped rather than mixed in.
+func (im *RecordImporter) ImportEmbedding(ctx context.Context, e MemoryEmbedding) error {
+	memID, ok := im.memories[e.MemoryID]
+	if !ok || len(e.Vector) == 0 || (im.dims > 0 && len(e.Vector) != im.dims) {
+		im.res.EmbeddingsSkipped++
+		return nil
+	}
+	if memID > 0 && !im.fresh[memID] {
+		var n int
+		if err := im.s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM embeddings WHERE memory_id = ?`, memID).Scan(&n); err != nil {
+			return fmt.Errorf("checking embedding of memory %d: %w", memID, err)
+		}
+		if n > 0 {
+			im.res.EmbeddingsSkipped++
+			return nil
+		}
+	}
+	im.dims = len(e.Vector)
+	im.res.EmbeddingsAdded++
+	if im.dryRun {
+		return nil
+	}
+	return im.s.AddEmbedding(ctx, memID, e.Vector)
+}
+
+// Finish restores supersessions between imported facts and returns the
+// counts. A fact whose superseding fact wasn't imported is retired, so it
+// stays out of live results as it was in the export.
+func (im *RecordImporter) Finish(ctx context.Context) (*RecordImportResult, error) {
+	for _, pair := range im.supersessions {
+		oldID, okOld := im.facts[pair[0]]
+		newID, okNew := im.facts[pair[1]]
+		if im.dryRun {
+			if okOld && okNew && oldID < 0 && newID != oldID {
+				im.res.Supersessions++
+			}
+			continue
+		}
+		if !okOld || oldID <= im.maxFactID {
+			continue // a fact that was already here keeps its own state
+		}
+		if !okNew || newID <= 0 || newID == oldID {
+			if _, err := im.s.db.ExecContext(ctx, `UPDATE facts SET state = ? WHERE id = ?`, FactStateRetired, oldID); err != nil {
+				return nil, fmt.Errorf("retiring fact %d: %w", oldID, err)
+			}
+			continue
+		}
+		if err := im.s.SupersedeFactWithSource(ctx, oldID, newID, "import", "import"); err != nil {
+			return nil, err
+		}
+		im.res.Supersessions++
+	}
+	res := im.res
+	return &res, nil
+}
+
+// IDMap returns the remapping table: exported memory and fact IDs to the
+// IDs they were imported as. Dry runs map new records to negative IDs.
+func (im *RecordImporter) IDMap() (memories, facts map[int64]int64) {
+	return im.memories, im.facts
+}
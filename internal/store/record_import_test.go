@@ -0,0 +1,134 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+// exportRecords feeds every record of src to im in export order.
+func exportRecords(t *testing.T, ctx context.Context, src *SQLiteStore, im *RecordImporter) *RecordImportResult {
+	t.Helper()
+	err := src.StreamMemories(ctx, ListOpts{}, func(page []*Memory) error {
+		for _, m := range page {
+			if err := im.ImportMemory(ctx, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		err = src.StreamFacts(ctx, ListOpts{IncludeSuperseded: true}, func(page []*Fact) error {
+			for _, f := range page {
+				if err := im.ImportFact(ctx, f); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err == nil {
+		err = src.StreamEdges(ctx, func(page []FactEdge) error {
+			for _, e := range page {
+				if err := im.ImportEdge(ctx, e); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err == nil {
+		err = src.StreamEmbeddings(ctx, func(page []MemoryEmbedding) error {
+			for _, e := range page {
+				if err := im.ImportEmbedding(ctx, e); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		t.Fatalf("importing: %v", err)
+	}
+	res, err := im.Finish(ctx)
+	if err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	return res
+}
+
+func TestRecordImporterRoundTripAndMerge(t *testing.T) {
+	ctx := context.Background()
+	src := newTestSQLiteStore(t)
+	a, _ := src.AddMemory(ctx, &Memory{Content: "deploys run on argo", SourceFile: "ops.md", Project: "work"})
+	b, _ := src.AddMemory(ctx, &Memory{Content: "deploy day is friday", SourceFile: "ops.md"})
+	tool, _ := src.AddFact(ctx, &Fact{MemoryID: a, Subject: "deploy", Predicate: "tool", Object: "argo", FactType: "kv"})
+	oldDay, _ := src.AddFact(ctx, &Fact{MemoryID: b, Subject: "deploy", Predicate: "day", Object: "thursday", FactType: "kv"})
+	day, _ := src.AddFact(ctx, &Fact{MemoryID: b, Subject: "deploy", Predicate: "day", Object: "friday", FactType: "kv"})
+	// Superseding also adds a supersedes edge, so src has two edges.
+	if err := src.SupersedeFact(ctx, oldDay, day, "moved"); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.AddEdge(ctx, &FactEdge{SourceFactID: tool, TargetFactID: day, EdgeType: EdgeTypeRelatesTo, Confidence: 0.7}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.AddEmbedding(ctx, a, []float32{1, 0, 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The destination already holds memory b and one of its facts, and has
+	// used up the low fact IDs.
+	dst := newTestSQLiteStore(t)
+	for i := 0; i < 5; i++ {
+		dst.AddMemory(ctx, &Memory{Content: "filler " + string(rune('a'+i)), SourceFile: "x.md"})
+	}
+	localB, _ := dst.AddMemory(ctx, &Memory{Content: "deploy day is friday", SourceFile: "ops.md"})
+	localDay, _ := dst.AddFact(ctx, &Fact{MemoryID: localB, Subject: "deploy", Predicate: "day", Object: "friday", FactType: "kv"})
+
+	dry, err := dst.NewRecordImporter(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := exportRecords(t, ctx, src, dry)
+	if !res.DryRun || res.MemoriesAdded != 1 || res.MemoriesDuplicate != 1 || res.FactsAdded != 2 || res.FactsDuplicate != 1 || res.EdgesAdded != 2 || res.Supersessions != 1 {
+		t.Fatalf("dry run = %+v", res)
+	}
+	if stats, _ := dst.Stats(ctx); stats.MemoryCount != 6 || stats.FactCount != 1 {
+		t.Fatalf("dry run wrote: %+v", stats)
+	}
+
+	im, err := dst.NewRecordImporter(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res = exportRecords(t, ctx, src, im)
+	if res.MemoriesAdded != 1 || res.MemoriesDuplicate != 1 || res.FactsAdded != 2 || res.FactsDuplicate != 1 ||
+		res.EdgesAdded != 2 || res.Supersessions != 1 || res.EmbeddingsAdded != 1 {
+		t.Fatalf("import = %+v", res)
+	}
+
+	memIDs, factIDs := im.IDMap()
+	if memIDs[b] != localB || factIDs[day] != localDay || factIDs[tool] == tool {
+		t.Fatalf("id map: memories %v, facts %v", memIDs, factIDs)
+	}
+	edges, err := dst.GetEdgesForFact(ctx, factIDs[tool])
+	if err != nil || len(edges) != 1 || edges[0].TargetFactID != localDay || edges[0].Confidence != 0.7 {
+		t.Fatalf("edges = %+v, %v", edges, err)
+	}
+	old, _ := dst.GetFact(ctx, factIDs[oldDay])
+	if old == nil || old.SupersededBy == nil || *old.SupersededBy != localDay {
+		t.Fatalf("superseded fact = %+v", old)
+	}
+	if vec, err := dst.GetEmbedding(ctx, memIDs[a]); err != nil || len(vec) != 3 {
+		t.Fatalf("embedding = %v, %v", vec, err)
+	}
+	m, _ := dst.GetMemory(ctx, memIDs[a])
+	if m.Project != "work" {
+		t.Fatalf("imported memory = %+v", m)
+	}
+
+	again, _ := dst.NewRecordImporter(ctx, false)
+	res = exportRecords(t, ctx, src, again)
+	if res.MemoriesAdded != 0 || res.FactsAdded != 0 || res.EdgesAdded != 0 || res.EmbeddingsAdded != 0 || res.Supersessions != 0 {
+		t.Fatalf("re-import should change nothing: %+v", res)
+	}
+}
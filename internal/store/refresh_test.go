@@ -2,7 +2,9 @@ package store
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDeleteMemoriesBySourceFile(t *testing.T) {
@@ -247,3 +249,42 @@ func TestDeleteFactsByMemoryID_CleansDependentRows(t *testing.T) {
 		}
 	}
 }
+
+func TestDeleteMemoriesBySourcePrefixAndReplace(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	ids := map[string]int64{}
+	for _, src := range []string{"mqtt:snapshot/presence", "mqtt:snapshot/lights", "mqtt", "mqttx:snapshot/a", "imap:thread/1"} {
+		id, err := s.AddMemory(ctx, &Memory{Content: "state of " + src, SourceFile: src})
+		if err != nil {
+			t.Fatalf("AddMemory %s: %v", src, err)
+		}
+		ids[src] = id
+	}
+
+	// Nothing was imported before an hour ago.
+	if n, err := s.DeleteMemoriesBySourcePrefix(ctx, "mqtt", time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Fatalf("expected nothing expired, got %d (%v)", n, err)
+	}
+	if n, err := s.DeleteMemoriesBySourcePrefix(ctx, "mqtt", time.Now().Add(time.Minute)); err != nil || n != 3 {
+		t.Fatalf("expected 3 mqtt memories expired, got %d (%v)", n, err)
+	}
+	for src, id := range ids {
+		m, _ := s.GetMemory(ctx, id)
+		if wantGone := src == "mqtt" || strings.HasPrefix(src, "mqtt:"); (m == nil) != wantGone {
+			t.Errorf("%s: deleted=%v, want %v", src, m == nil, wantGone)
+		}
+	}
+
+	newer, err := s.AddMemory(ctx, &Memory{Content: "imap thread v2", SourceFile: "imap:thread/1"})
+	if err != nil {
+		t.Fatalf("AddMemory: %v", err)
+	}
+	if n, err := s.ReplaceSourceMemories(ctx, "imap:thread/1", newer); err != nil || n != 1 {
+		t.Fatalf("ReplaceSourceMemories = %d (%v), want 1", n, err)
+	}
+	if m, _ := s.GetMemory(ctx, newer); m == nil {
+		t.Error("kept memory was deleted")
+	}
+}
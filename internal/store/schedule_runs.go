@@ -0,0 +1,206 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// scheduleRunRetention is how many runs are kept per schedule.
+const scheduleRunRetention = 500
+
+// ScheduleRun is one row of schedule_runs: a firing of a daemon schedule.
+type ScheduleRun struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Task       string     `json:"task"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Status     string     `json:"status"` // running | ok | error | skipped
+	Detail     string     `json:"detail,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// Duration is how long a finished run took, or 0 while it is running.
+func (r ScheduleRun) Duration() time.Duration {
+	if r.FinishedAt == nil {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// migrateScheduleRunsTable creates schedule_runs, the run history of
+// `cortex daemon --with-schedules`. A row stays 'running' until its run
+// finishes, which is also how two daemons sharing a database avoid running
+// the same schedule at once.
+func (s *SQLiteStore) migrateScheduleRunsTable() error {
+	done, err := s.isMetaFlagEnabled("schedule_runs_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS schedule_runs (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			name        TEXT NOT NULL,
+			task        TEXT NOT NULL,
+			started_at  DATETIME NOT NULL,
+			finished_at DATETIME,
+			status      TEXT NOT NULL DEFAULT 'running',
+			detail      TEXT NOT NULL DEFAULT '',
+			error       TEXT NOT NULL DEFAULT ''
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_schedule_runs_name ON schedule_runs(name, started_at)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('schedule_runs_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating schedule_runs table: %w", err)
+		}
+	}
+	return nil
+}
+
+// BeginScheduleRun records the start of a run of schedule name. It returns
+// ok=false, recording nothing, when another run of name started less than
+// staleAfter ago and has not finished. Older unfinished runs are marked as
+// errors: their process died without recording a result.
+func (s *SQLiteStore) BeginScheduleRun(ctx context.Context, name, task string, startedAt time.Time, staleAfter time.Duration) (int64, bool, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, false, fmt.Errorf("schedule name is required")
+	}
+	startedAt = startedAt.UTC()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("starting schedule run: %w", err)
+	}
+	defer tx.Rollback()
+
+	cutoff := startedAt.Add(-staleAfter)
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE schedule_runs SET status = 'error', finished_at = ?, error = 'abandoned: no result recorded'
+		 WHERE name = ? AND status = 'running' AND started_at < ?`,
+		startedAt, name, cutoff,
+	); err != nil {
+		return 0, false, fmt.Errorf("expiring schedule runs: %w", err)
+	}
+	var running int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM schedule_runs WHERE name = ? AND status = 'running'`, name,
+	).Scan(&running); err != nil {
+		return 0, false, fmt.Errorf("checking schedule runs: %w", err)
+	}
+	if running > 0 {
+		return 0, false, tx.Commit()
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO schedule_runs (name, task, started_at, status) VALUES (?, ?, ?, 'running')`,
+		name, task, startedAt,
+	)
+	if err != nil {
+		return 0, false, fmt.Errorf("starting schedule run: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, false, fmt.Errorf("starting schedule run: %w", err)
+	}
+	if err := trimScheduleRuns(ctx, tx, name); err != nil {
+		return 0, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, false, fmt.Errorf("starting schedule run: %w", err)
+	}
+	return id, true, nil
+}
+
+// FinishScheduleRun records the outcome of a run begun by BeginScheduleRun.
+func (s *SQLiteStore) FinishScheduleRun(ctx context.Context, id int64, status, detail, errMsg string, finishedAt time.Time) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE schedule_runs SET status = ?, detail = ?, error = ?, finished_at = ? WHERE id = ?`,
+		status, detail, errMsg, finishedAt.UTC(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("finishing schedule run: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("schedule run %d not found", id)
+	}
+	return nil
+}
+
+// RecordScheduleSkip records a firing of schedule name that did not run.
+func (s *SQLiteStore) RecordScheduleSkip(ctx context.Context, name, task, reason string, at time.Time) error {
+	at = at.UTC()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("recording schedule skip: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO schedule_runs (name, task, started_at, finished_at, status, detail)
+		 VALUES (?, ?, ?, ?, 'skipped', ?)`,
+		name, task, at, at, reason,
+	); err != nil {
+		return fmt.Errorf("recording schedule skip: %w", err)
+	}
+	if err := trimScheduleRuns(ctx, tx, name); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("recording schedule skip: %w", err)
+	}
+	return nil
+}
+
+func trimScheduleRuns(ctx context.Context, tx *sql.Tx, name string) error {
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM schedule_runs
+		 WHERE name = ? AND id NOT IN (
+		   SELECT id FROM schedule_runs WHERE name = ? ORDER BY started_at DESC, id DESC LIMIT ?)`,
+		name, name, scheduleRunRetention,
+	); err != nil {
+		return fmt.Errorf("trimming schedule runs: %w", err)
+	}
+	return nil
+}
+
+// ListScheduleRuns returns the most recent runs, newest first. An empty
+// name lists every schedule; limit <= 0 returns everything kept.
+func (s *SQLiteStore) ListScheduleRuns(ctx context.Context, name string, limit int) ([]ScheduleRun, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, name, task, started_at, finished_at, status, detail, error
+		 FROM schedule_runs WHERE (? = '' OR name = ?)
+		 ORDER BY started_at DESC, id DESC LIMIT ?`,
+		name, name, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing schedule runs: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ScheduleRun
+	for rows.Next() {
+		var r ScheduleRun
+		var finishedAt sql.NullTime
+		if err := rows.Scan(&r.ID, &r.Name, &r.Task, &r.StartedAt, &finishedAt, &r.Status, &r.Detail, &r.Error); err != nil {
+			return nil, fmt.Errorf("scanning schedule run: %w", err)
+		}
+		r.StartedAt = r.StartedAt.UTC()
+		if finishedAt.Valid {
+			t := finishedAt.Time.UTC()
+			r.FinishedAt = &t
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScheduleRuns_BeginFinishSkip(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+
+	if err := s.migrateScheduleRunsTable(); err != nil {
+		t.Fatalf("second migrateScheduleRunsTable call failed: %v", err)
+	}
+	start := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	id, ok, err := s.BeginScheduleRun(ctx, "nightly", "connector_sync", start, time.Hour)
+	if err != nil || !ok {
+		t.Fatalf("BeginScheduleRun = %d, %v, %v", id, ok, err)
+	}
+
+	// A second run while the first is going is refused.
+	if _, ok, err := s.BeginScheduleRun(ctx, "nightly", "connector_sync", start.Add(time.Minute), time.Hour); err != nil || ok {
+		t.Fatalf("overlapping BeginScheduleRun = %v, %v; want refused", ok, err)
+	}
+	// Other schedules are independent.
+	if _, ok, err := s.BeginScheduleRun(ctx, "digest", "digest", start, time.Hour); err != nil || !ok {
+		t.Fatalf("BeginScheduleRun(digest) = %v, %v", ok, err)
+	}
+
+	if err := s.RecordScheduleSkip(ctx, "nightly", "connector_sync", "previous run still in progress", start.Add(2*time.Minute)); err != nil {
+		t.Fatalf("RecordScheduleSkip: %v", err)
+	}
+	if err := s.FinishScheduleRun(ctx, id, "ok", "3 connectors, 12 imported", "", start.Add(5*time.Minute)); err != nil {
+		t.Fatalf("FinishScheduleRun: %v", err)
+	}
+	if err := s.FinishScheduleRun(ctx, 9999, "ok", "", "", start); err == nil {
+		t.Fatal("expected error finishing an unknown run")
+	}
+
+	runs, err := s.ListScheduleRuns(ctx, "nightly", 0)
+	if err != nil {
+		t.Fatalf("ListScheduleRuns: %v", err)
+	}
+	if len(runs) != 2 || runs[0].Status != "skipped" || runs[1].Status != "ok" {
+		t.Fatalf("unexpected runs: %+v", runs)
+	}
+	if runs[1].Duration() != 5*time.Minute || runs[1].Detail != "3 connectors, 12 imported" {
+		t.Fatalf("unexpected finished run: %+v", runs[1])
+	}
+	if all, _ := s.ListScheduleRuns(ctx, "", 0); len(all) != 3 {
+		t.Fatalf("expected 3 runs across schedules, got %d", len(all))
+	}
+
+	// A run left 'running' past staleAfter is abandoned, not blocking.
+	if _, ok, _ := s.BeginScheduleRun(ctx, "digest", "digest", start.Add(2*time.Hour), time.Hour); !ok {
+		t.Fatal("a stale running row should not block a new run")
+	}
+	runs, _ = s.ListScheduleRuns(ctx, "digest", 0)
+	if len(runs) != 2 || runs[1].Status != "error" || runs[1].Error == "" {
+		t.Fatalf("expected the stale run marked as error, got %+v", runs)
+	}
+}
+
+func TestScheduleRuns_Retention(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < scheduleRunRetention+5; i++ {
+		if err := s.RecordScheduleSkip(ctx, "busy", "embed", "test", start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("RecordScheduleSkip: %v", err)
+		}
+	}
+	runs, err := s.ListScheduleRuns(ctx, "busy", 0)
+	if err != nil {
+		t.Fatalf("ListScheduleRuns: %v", err)
+	}
+	if len(runs) != scheduleRunRetention {
+		t.Fatalf("expected %d runs kept, got %d", scheduleRunRetention, len(runs))
+	}
+	if want := start.Add(time.Duration(scheduleRunRetention+4) * time.Minute); !runs[0].StartedAt.Equal(want) {
+		t.Fatalf("newest run = %v, want %v", runs[0].StartedAt, want)
+	}
+}
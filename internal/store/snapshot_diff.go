@@ -0,0 +1,175 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// DefaultConfidenceDelta is the smallest confidence change DiffSnapshots
+// reports; smaller moves are rounding noise from decay and reinforcement.
+const DefaultConfidenceDelta = 0.01
+
+// SnapshotFact is the slice of a fact that snapshot diffs compare.
+type SnapshotFact struct {
+	ID           int64   `json:"id"`
+	MemoryID     int64   `json:"memory_id"`
+	Subject      string  `json:"subject"`
+	Predicate    string  `json:"predicate"`
+	Object       string  `json:"object"`
+	Confidence   float64 `json:"confidence"`
+	SupersededBy *int64  `json:"superseded_by,omitempty"`
+}
+
+// SnapshotMemory is the slice of a memory that snapshot diffs compare.
+type SnapshotMemory struct {
+	ID          int64  `json:"id"`
+	SourceFile  string `json:"source_file"`
+	ContentHash string `json:"content_hash"`
+	Deleted     bool   `json:"deleted,omitempty"`
+}
+
+// Snapshot is the comparable state of a store at one point in time.
+type Snapshot struct {
+	Facts    map[int64]SnapshotFact
+	Memories map[int64]SnapshotMemory
+}
+
+// NewSnapshot returns an empty snapshot ready to be filled.
+func NewSnapshot() *Snapshot {
+	return &Snapshot{Facts: map[int64]SnapshotFact{}, Memories: map[int64]SnapshotMemory{}}
+}
+
+// Snapshot reads the store's facts and memories for diffing. Only columns
+// present since the first schema are read, so older backups opened
+// read-only (without migrations) load too.
+func (s *SQLiteStore) Snapshot(ctx context.Context) (*Snapshot, error) {
+	snap := NewSnapshot()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, memory_id, subject, predicate, object, confidence, superseded_by FROM facts`)
+	if err != nil {
+		return nil, fmt.Errorf("reading facts: %w", err)
+	}
+	for rows.Next() {
+		var f SnapshotFact
+		var supersededBy sql.NullInt64
+		if err := rows.Scan(&f.ID, &f.MemoryID, &f.Subject, &f.Predicate, &f.Object, &f.Confidence, &supersededBy); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning fact: %w", err)
+		}
+		if supersededBy.Valid && supersededBy.Int64 > 0 {
+			id := supersededBy.Int64
+			f.SupersededBy = &id
+		}
+		snap.Facts[f.ID] = f
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.db.QueryContext(ctx,
+		`SELECT id, source_file, content_hash, deleted_at IS NOT NULL FROM memories`)
+	if err != nil {
+		return nil, fmt.Errorf("reading memories: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m SnapshotMemory
+		if err := rows.Scan(&m.ID, &m.SourceFile, &m.ContentHash, &m.Deleted); err != nil {
+			return nil, fmt.Errorf("scanning memory: %w", err)
+		}
+		snap.Memories[m.ID] = m
+	}
+	return snap, rows.Err()
+}
+
+// ConfidenceChange is a fact whose confidence moved between snapshots.
+type ConfidenceChange struct {
+	SnapshotFact
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+}
+
+// SnapshotDiff lists what changed from an older snapshot to a newer one.
+// Facts and memories are matched by ID, so both sides must come from the
+// same database lineage (a backup and the live store it was taken from).
+type SnapshotDiff struct {
+	FactsAdded        []SnapshotFact     `json:"facts_added"`
+	FactsRemoved      []SnapshotFact     `json:"facts_removed"`
+	FactsSuperseded   []SnapshotFact     `json:"facts_superseded"`
+	ConfidenceChanged []ConfidenceChange `json:"confidence_changed"`
+	MemoriesAdded     []SnapshotMemory   `json:"memories_added"`
+	MemoriesRemoved   []SnapshotMemory   `json:"memories_removed"`
+	MemoriesChanged   []SnapshotMemory   `json:"memories_changed"`
+}
+
+// Empty reports whether the diff found no changes.
+func (d SnapshotDiff) Empty() bool {
+	return len(d.FactsAdded)+len(d.FactsRemoved)+len(d.FactsSuperseded)+len(d.ConfidenceChanged)+
+		len(d.MemoriesAdded)+len(d.MemoriesRemoved)+len(d.MemoriesChanged) == 0
+}
+
+// DiffSnapshots compares before against after. A fact counts as superseded
+// when it gained a superseded_by; it is not also reported as a confidence
+// change. Soft-deleted memories count as removed. Confidence moves smaller
+// than minDelta are ignored (<= 0 uses DefaultConfidenceDelta). Every list
+// is ordered by ID.
+func DiffSnapshots(before, after *Snapshot, minDelta float64) SnapshotDiff {
+	if minDelta <= 0 {
+		minDelta = DefaultConfidenceDelta
+	}
+	var d SnapshotDiff
+
+	for id, f := range after.Facts {
+		old, ok := before.Facts[id]
+		switch {
+		case !ok:
+			d.FactsAdded = append(d.FactsAdded, f)
+		case old.SupersededBy == nil && f.SupersededBy != nil:
+			d.FactsSuperseded = append(d.FactsSuperseded, f)
+		case math.Abs(f.Confidence-old.Confidence) >= minDelta:
+			d.ConfidenceChanged = append(d.ConfidenceChanged, ConfidenceChange{SnapshotFact: f, Before: old.Confidence, After: f.Confidence})
+		}
+	}
+	for id, f := range before.Facts {
+		if _, ok := after.Facts[id]; !ok {
+			d.FactsRemoved = append(d.FactsRemoved, f)
+		}
+	}
+
+	for id, m := range after.Memories {
+		old, ok := before.Memories[id]
+		switch {
+		case !ok || old.Deleted:
+			if !m.Deleted {
+				d.MemoriesAdded = append(d.MemoriesAdded, m)
+			}
+		case m.Deleted:
+			d.MemoriesRemoved = append(d.MemoriesRemoved, m)
+		case m.ContentHash != old.ContentHash:
+			d.MemoriesChanged = append(d.MemoriesChanged, m)
+		}
+	}
+	for id, m := range before.Memories {
+		if _, ok := after.Memories[id]; !ok && !m.Deleted {
+			d.MemoriesRemoved = append(d.MemoriesRemoved, m)
+		}
+	}
+
+	sortSnapshotFacts(d.FactsAdded)
+	sortSnapshotFacts(d.FactsRemoved)
+	sortSnapshotFacts(d.FactsSuperseded)
+	sort.Slice(d.ConfidenceChanged, func(i, j int) bool { return d.ConfidenceChanged[i].ID < d.ConfidenceChanged[j].ID })
+	for _, ms := range [][]SnapshotMemory{d.MemoriesAdded, d.MemoriesRemoved, d.MemoriesChanged} {
+		sort.Slice(ms, func(i, j int) bool { return ms[i].ID < ms[j].ID })
+	}
+	return d
+}
+
+func sortSnapshotFacts(fs []SnapshotFact) {
+	sort.Slice(fs, func(i, j int) bool { return fs[i].ID < fs[j].ID })
+}
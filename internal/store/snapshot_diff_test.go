@@ -0,0 +1,75 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDiffSnapshots_ReportsFactAndMemoryChanges(t *testing.T) {
+	s := newTestStore(t)
+	ss := s.(*SQLiteStore)
+	ctx := context.Background()
+
+	memA, _ := s.AddMemory(ctx, &Memory{Content: "alpha", SourceFile: "a.md"})
+	memB, _ := s.AddMemory(ctx, &Memory{Content: "beta", SourceFile: "b.md"})
+	kept, _ := s.AddFact(ctx, &Fact{MemoryID: memA, Subject: "api", Predicate: "port", Object: "8080", FactType: "kv", Confidence: 0.9})
+	drifted, _ := s.AddFact(ctx, &Fact{MemoryID: memA, Subject: "api", Predicate: "owner", Object: "ops", FactType: "kv", Confidence: 0.8})
+	nudged, _ := s.AddFact(ctx, &Fact{MemoryID: memB, Subject: "db", Predicate: "engine", Object: "sqlite", FactType: "kv", Confidence: 0.7})
+	gone, _ := s.AddFact(ctx, &Fact{MemoryID: memB, Subject: "db", Predicate: "size", Object: "small", FactType: "kv", Confidence: 0.6})
+
+	before, err := ss.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	memC, _ := s.AddMemory(ctx, &Memory{Content: "gamma", SourceFile: "c.md"})
+	added, _ := s.AddFact(ctx, &Fact{MemoryID: memC, Subject: "api", Predicate: "port", Object: "9090", FactType: "kv", Confidence: 0.9})
+	if err := s.SupersedeFact(ctx, kept, added, "port moved"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateFactConfidence(ctx, drifted, 0.4); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateFactConfidence(ctx, nudged, 0.705); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ss.db.ExecContext(ctx, `DELETE FROM facts WHERE id = ?`, gone); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteMemory(ctx, memB); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := ss.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	d := DiffSnapshots(before, after, 0)
+
+	if len(d.FactsAdded) != 1 || d.FactsAdded[0].ID != added {
+		t.Fatalf("facts added = %+v, want [%d]", d.FactsAdded, added)
+	}
+	if len(d.FactsRemoved) != 1 || d.FactsRemoved[0].ID != gone {
+		t.Fatalf("facts removed = %+v, want [%d]", d.FactsRemoved, gone)
+	}
+	if len(d.FactsSuperseded) != 1 || d.FactsSuperseded[0].ID != kept || *d.FactsSuperseded[0].SupersededBy != added {
+		t.Fatalf("facts superseded = %+v, want %d by %d", d.FactsSuperseded, kept, added)
+	}
+	if len(d.ConfidenceChanged) != 1 || d.ConfidenceChanged[0].ID != drifted ||
+		d.ConfidenceChanged[0].Before != 0.8 || d.ConfidenceChanged[0].After != 0.4 {
+		t.Fatalf("confidence changes = %+v, want only %d 0.8→0.4", d.ConfidenceChanged, drifted)
+	}
+	if len(d.MemoriesAdded) != 1 || d.MemoriesAdded[0].ID != memC {
+		t.Fatalf("memories added = %+v, want [%d]", d.MemoriesAdded, memC)
+	}
+	if len(d.MemoriesRemoved) != 1 || d.MemoriesRemoved[0].ID != memB {
+		t.Fatalf("memories removed = %+v, want soft-deleted %d", d.MemoriesRemoved, memB)
+	}
+
+	if !DiffSnapshots(after, after, 0).Empty() {
+		t.Fatal("diffing a snapshot against itself should be empty")
+	}
+	if got := DiffSnapshots(before, after, 0.001).ConfidenceChanged; len(got) != 2 {
+		t.Fatalf("lower min delta should include the small nudge, got %+v", got)
+	}
+}
@@ -0,0 +1,429 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source types group the loose source_file strings memories carry.
+const (
+	SourceTypeFile      = "file"      // local files from `cortex import`
+	SourceTypeConnector = "connector" // `cortex connect` providers ("github:issue/1")
+	SourceTypeCapture   = "capture"   // agent auto-capture hooks
+	SourceTypeAPI       = "api"       // MCP imports and inbound webhooks
+)
+
+// SourceTypes lists the valid source types.
+var SourceTypes = []string{SourceTypeFile, SourceTypeConnector, SourceTypeCapture, SourceTypeAPI}
+
+// DefaultSourceTrustWeight leaves a source's search ranking unchanged.
+const DefaultSourceTrustWeight = 1.0
+
+// Source is one entry in the source registry: what produced a memory and how
+// much to trust it. Key is what source_file starts with ("github" matches
+// "github:issue/1" and "github/..."), except for the "file" and "capture"
+// fallbacks, which match by shape.
+type Source struct {
+	Key         string `json:"key"`
+	Type        string `json:"type"`
+	DisplayName string `json:"display_name"`
+	// TrustWeight multiplies search scores for the source's memories on top
+	// of the built-in file/connector weighting. 1.0 is neutral.
+	TrustWeight float64 `json:"trust_weight"`
+	// DefaultClass is the memory class given to imports that arrive without
+	// one.
+	DefaultClass string `json:"default_class,omitempty"`
+	// Custom is set when the entry comes from `cortex sources set`.
+	Custom bool `json:"custom,omitempty"`
+}
+
+// SourceStat is a source with its memory counts.
+type SourceStat struct {
+	Source
+	Memories    int       `json:"memories"`
+	Facts       int       `json:"facts"`
+	SourceFiles int       `json:"source_files"`
+	LastImport  time.Time `json:"last_import,omitempty"`
+}
+
+const (
+	sourceKeyFile    = "file"
+	sourceKeyCapture = "capture"
+)
+
+var builtinSources = []Source{
+	{Key: sourceKeyFile, Type: SourceTypeFile, DisplayName: "Imported files"},
+	{Key: sourceKeyCapture, Type: SourceTypeCapture, DisplayName: "Auto-capture"},
+	{Key: "mcp-import", Type: SourceTypeAPI, DisplayName: "MCP imports"},
+	{Key: "webhook", Type: SourceTypeAPI, DisplayName: "Webhooks"},
+	{Key: "github", Type: SourceTypeConnector, DisplayName: "GitHub"},
+	{Key: "jira", Type: SourceTypeConnector, DisplayName: "Jira"},
+	{Key: "linear", Type: SourceTypeConnector, DisplayName: "Linear"},
+	{Key: "gmail", Type: SourceTypeConnector, DisplayName: "Gmail"},
+	{Key: "imap", Type: SourceTypeConnector, DisplayName: "IMAP Email"},
+	{Key: "calendar", Type: SourceTypeConnector, DisplayName: "Google Calendar"},
+	{Key: "drive", Type: SourceTypeConnector, DisplayName: "Google Drive"},
+	{Key: "slack", Type: SourceTypeConnector, DisplayName: "Slack"},
+	{Key: "discord", Type: SourceTypeConnector, DisplayName: "Discord"},
+	{Key: "telegram", Type: SourceTypeConnector, DisplayName: "Telegram"},
+	{Key: "notion", Type: SourceTypeConnector, DisplayName: "Notion"},
+	{Key: "obsidian", Type: SourceTypeConnector, DisplayName: "Obsidian"},
+	{Key: "homeassistant", Type: SourceTypeConnector, DisplayName: "Home Assistant", DefaultClass: "status"},
+	{Key: "mqtt", Type: SourceTypeConnector, DisplayName: "MQTT", DefaultClass: "status"},
+}
+
+var (
+	sourcesMu         sync.RWMutex
+	registeredSources = initialSources()
+	sourceOverrides   = map[string]Source{} // rows of the sources table
+)
+
+func initialSources() map[string]Source {
+	m := make(map[string]Source, len(builtinSources))
+	for _, src := range builtinSources {
+		src.TrustWeight = DefaultSourceTrustWeight
+		m[src.Key] = src
+	}
+	return m
+}
+
+// NormalizeSourceKey lowercases and trims a source key.
+func NormalizeSourceKey(key string) string {
+	return strings.ToLower(strings.TrimSpace(key))
+}
+
+// IsSourceType reports whether t names a source type.
+func IsSourceType(t string) bool {
+	t = NormalizeSourceKey(t)
+	for _, known := range SourceTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterSource adds a built-in source, e.g. a connector provider. Sources
+// already registered keep their entry.
+func RegisterSource(src Source) {
+	src.Key = NormalizeSourceKey(src.Key)
+	if src.Key == "" {
+		return
+	}
+	if src.TrustWeight <= 0 {
+		src.TrustWeight = DefaultSourceTrustWeight
+	}
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	if _, ok := registeredSources[src.Key]; !ok {
+		registeredSources[src.Key] = src
+	}
+}
+
+// activeSourcesLocked merges registered sources with stored overrides.
+// Callers hold sourcesMu.
+func activeSourcesLocked() map[string]Source {
+	m := make(map[string]Source, len(registeredSources)+len(sourceOverrides))
+	for k, v := range registeredSources {
+		m[k] = v
+	}
+	for k, v := range sourceOverrides {
+		m[k] = v
+	}
+	return m
+}
+
+// ListSources returns the active registry, grouped by type.
+func ListSources() []Source {
+	sourcesMu.RLock()
+	active := activeSourcesLocked()
+	sourcesMu.RUnlock()
+
+	out := make([]Source, 0, len(active))
+	for _, src := range active {
+		out = append(out, src)
+	}
+	sortSources(out)
+	return out
+}
+
+func sortSources(list []Source) {
+	rank := map[string]int{}
+	for i, t := range SourceTypes {
+		rank[t] = i
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Type != list[j].Type {
+			return rank[list[i].Type] < rank[list[j].Type]
+		}
+		return list[i].Key < list[j].Key
+	})
+}
+
+// LookupSource finds a source by key or, case-insensitively, display name.
+func LookupSource(name string) (Source, bool) {
+	name = NormalizeSourceKey(name)
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	active := activeSourcesLocked()
+	if src, ok := active[name]; ok {
+		return src, true
+	}
+	for _, src := range active {
+		if strings.EqualFold(src.DisplayName, name) {
+			return src, true
+		}
+	}
+	return Source{}, false
+}
+
+// ResolveSource classifies a memory's source_file: auto-capture paths are
+// "capture", "provider:..." and other registered keys resolve to their entry
+// (the longest key wins), and anything else is a "file" import.
+func ResolveSource(sourceFile string) Source {
+	lower := NormalizeSourceKey(sourceFile)
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	active := activeSourcesLocked()
+
+	if isAutoCaptureSource(lower) {
+		return active[sourceKeyCapture]
+	}
+	best := ""
+	for key, src := range active {
+		if key == sourceKeyFile || key == sourceKeyCapture || len(key) <= len(best) {
+			continue
+		}
+		// Connectors and APIs always write "key:..."; a local directory that
+		// happens to share the name ("github/README.md") stays a file.
+		if lower == key || strings.HasPrefix(lower, key+":") ||
+			(src.Type != SourceTypeConnector && src.Type != SourceTypeAPI && strings.HasPrefix(lower, key+"/")) {
+			best = key
+		}
+	}
+	if best != "" {
+		return active[best]
+	}
+	return active[sourceKeyFile]
+}
+
+// MatchesSource reports whether sourceFile satisfies a --source filter. The
+// filter is a source type ("connector"), a registry key or display name
+// ("github", "Google Calendar"), or a raw source_file prefix.
+func MatchesSource(sourceFile, filter string) bool {
+	filter = NormalizeSourceKey(filter)
+	if filter == "" {
+		return true
+	}
+	if IsSourceType(filter) {
+		return ResolveSource(sourceFile).Type == filter
+	}
+	if src, ok := LookupSource(filter); ok {
+		if src.Key == sourceKeyFile || src.Key == sourceKeyCapture {
+			return ResolveSource(sourceFile).Key == src.Key
+		}
+		filter = src.Key
+	}
+	return sourcePrefixMatch(NormalizeSourceKey(sourceFile), filter)
+}
+
+// SourceFilterPrefix returns the source_file prefix a --source filter can be
+// pushed down to SQL as, or "" when it names a type or fallback source that
+// must be matched with MatchesSource instead.
+func SourceFilterPrefix(filter string) string {
+	filter = NormalizeSourceKey(filter)
+	if filter == "" || IsSourceType(filter) {
+		return ""
+	}
+	if src, ok := LookupSource(filter); ok {
+		if src.Key == sourceKeyFile || src.Key == sourceKeyCapture {
+			return ""
+		}
+		return src.Key
+	}
+	return filter
+}
+
+// sourcePrefixMatch mirrors the SQL source filter: a prefix containing ":" or
+// "/" matches literally; a bare key matches "key", "key:..." and "key/...".
+func sourcePrefixMatch(lowerFile, prefix string) bool {
+	if strings.ContainsAny(prefix, ":/") {
+		return strings.HasPrefix(lowerFile, prefix)
+	}
+	return lowerFile == prefix || strings.HasPrefix(lowerFile, prefix+":") || strings.HasPrefix(lowerFile, prefix+"/")
+}
+
+func isAutoCaptureSource(lower string) bool {
+	return strings.Contains(lower, "auto-capture") || strings.Contains(lower, "cortex-capture-")
+}
+
+// DefaultClassForSource returns the default memory class of sourceFile's
+// source, or "".
+func DefaultClassForSource(sourceFile string) string {
+	return ResolveSource(sourceFile).DefaultClass
+}
+
+// migrateSourcesTable creates the table behind `cortex sources set`.
+func (s *SQLiteStore) migrateSourcesTable() error {
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS sources (
+		key           TEXT PRIMARY KEY,
+		type          TEXT NOT NULL,
+		display_name  TEXT NOT NULL DEFAULT '',
+		trust_weight  REAL NOT NULL DEFAULT 1.0,
+		default_class TEXT NOT NULL DEFAULT '',
+		updated_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return fmt.Errorf("creating sources table: %w", err)
+	}
+	return nil
+}
+
+// loadSourceOverrides installs the sources table as the active overrides. A
+// missing table (read-only access to an older database) means none.
+func (s *SQLiteStore) loadSourceOverrides(ctx context.Context) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT key, type, display_name, trust_weight, default_class FROM sources`)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such table") {
+			return nil
+		}
+		return fmt.Errorf("loading sources: %w", err)
+	}
+	defer rows.Close()
+	overrides := map[string]Source{}
+	for rows.Next() {
+		var src Source
+		if err := rows.Scan(&src.Key, &src.Type, &src.DisplayName, &src.TrustWeight, &src.DefaultClass); err != nil {
+			return fmt.Errorf("scanning source: %w", err)
+		}
+		src.Custom = true
+		overrides[src.Key] = src
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	sourcesMu.Lock()
+	sourceOverrides = overrides
+	sourcesMu.Unlock()
+	return nil
+}
+
+// SetSource stores a registry entry, replacing any built-in with the same
+// key, and makes it active.
+func (s *SQLiteStore) SetSource(ctx context.Context, src Source) error {
+	src.Key = NormalizeSourceKey(src.Key)
+	src.Type = NormalizeSourceKey(src.Type)
+	src.DefaultClass = NormalizeMemoryClass(src.DefaultClass)
+	switch {
+	case src.Key == "":
+		return fmt.Errorf("source key is required")
+	case !IsSourceType(src.Type):
+		return fmt.Errorf("invalid source type %q (valid: %s)", src.Type, strings.Join(SourceTypes, ", "))
+	case (src.Key == sourceKeyFile || src.Key == sourceKeyCapture) && src.Type != src.Key:
+		return fmt.Errorf("the %s source's type cannot be changed", src.Key)
+	case src.TrustWeight <= 0:
+		return fmt.Errorf("trust weight must be positive")
+	case src.DefaultClass != "" && !IsValidMemoryClass(src.DefaultClass):
+		return fmt.Errorf("invalid default class %q", src.DefaultClass)
+	}
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO sources (key, type, display_name, trust_weight, default_class, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET
+			type = excluded.type,
+			display_name = excluded.display_name,
+			trust_weight = excluded.trust_weight,
+			default_class = excluded.default_class,
+			updated_at = excluded.updated_at`,
+		src.Key, src.Type, src.DisplayName, src.TrustWeight, src.DefaultClass, time.Now().UTC(),
+	); err != nil {
+		return fmt.Errorf("saving source %s: %w", src.Key, err)
+	}
+	return s.loadSourceOverrides(ctx)
+}
+
+// ResetSource removes a stored entry, restoring the built-in (if any).
+// It reports whether an entry was removed.
+func (s *SQLiteStore) ResetSource(ctx context.Context, key string) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM sources WHERE key = ?`, NormalizeSourceKey(key))
+	if err != nil {
+		return false, fmt.Errorf("resetting source %s: %w", key, err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, s.loadSourceOverrides(ctx)
+}
+
+// SourceStats counts active memories and their facts per registry source.
+// Sources with no memories are included with zero counts.
+func (s *SQLiteStore) SourceStats(ctx context.Context) ([]SourceStat, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT COALESCE(m.source_file, ''), COUNT(*), MAX(m.imported_at),
+			COALESCE(SUM((SELECT COUNT(*) FROM facts f WHERE f.memory_id = m.id)), 0)
+		FROM memories m
+		WHERE m.deleted_at IS NULL
+		GROUP BY m.source_file`)
+	if err != nil {
+		return nil, fmt.Errorf("counting memories by source: %w", err)
+	}
+	defer rows.Close()
+
+	stats := map[string]*SourceStat{}
+	for _, src := range ListSources() {
+		stats[src.Key] = &SourceStat{Source: src}
+	}
+	for rows.Next() {
+		var (
+			sourceFile string
+			memories   int
+			last       sql.NullString
+			facts      int
+		)
+		if err := rows.Scan(&sourceFile, &memories, &last, &facts); err != nil {
+			return nil, fmt.Errorf("scanning source counts: %w", err)
+		}
+		src := ResolveSource(sourceFile)
+		st := stats[src.Key]
+		if st == nil {
+			st = &SourceStat{Source: src}
+			stats[src.Key] = st
+		}
+		st.Memories += memories
+		st.Facts += facts
+		st.SourceFiles++
+		if last.Valid {
+			if t := parseAggregateTime(last.String); t.After(st.LastImport) {
+				st.LastImport = t
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]SourceStat, 0, len(stats))
+	for _, st := range stats {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Memories != out[j].Memories {
+			return out[i].Memories > out[j].Memories
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out, nil
+}
+
+// parseAggregateTime parses a DATETIME that came back from an aggregate such
+// as MAX(), which loses the column type and yields the stored text.
+func parseAggregateTime(v string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04:05.999999999 -0700 MST", time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
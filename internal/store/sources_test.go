@@ -0,0 +1,136 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResolveSource(t *testing.T) {
+	cases := []struct {
+		sourceFile string
+		key, typ   string
+	}{
+		{"github:issues/123", "github", SourceTypeConnector},
+		{"JIRA:issue/PROJ-1", "jira", SourceTypeConnector},
+		{"homeassistant:snapshot/presence", "homeassistant", SourceTypeConnector},
+		{"webhook:github:push", "webhook", SourceTypeAPI},
+		{"mcp-import", "mcp-import", SourceTypeAPI},
+		{"/tmp/cortex-capture-abc/auto-capture.md", "capture", SourceTypeCapture},
+		{"memory/2026-02-23.md", "file", SourceTypeFile},
+		{"github/README.md", "file", SourceTypeFile}, // a local directory, not the connector
+		{"gmailbackup:x", "file", SourceTypeFile},
+		{"", "file", SourceTypeFile},
+	}
+	for _, c := range cases {
+		got := ResolveSource(c.sourceFile)
+		if got.Key != c.key || got.Type != c.typ {
+			t.Errorf("ResolveSource(%q) = %s/%s, want %s/%s", c.sourceFile, got.Key, got.Type, c.key, c.typ)
+		}
+	}
+	if got := ResolveSource("mqtt:snapshot/occupancy").DefaultClass; got != "status" {
+		t.Errorf("mqtt default class = %q, want status", got)
+	}
+}
+
+func TestMatchesSource(t *testing.T) {
+	cases := []struct {
+		sourceFile, filter string
+		want               bool
+	}{
+		{"github:issues/1", "github", true},
+		{"github:issues/1", "GitHub", true},
+		{"calendar:event/1", "Google Calendar", true},
+		{"github:issues/1", "connector", true},
+		{"memory/notes.md", "connector", false},
+		{"memory/notes.md", "file", true},
+		{"/tmp/auto-capture.md", "capture", true},
+		{"/tmp/auto-capture.md", "file", false},
+		{"notes/ops.md", "notes/", true},
+		{"gmailbackup:x", "gmail", false},
+	}
+	for _, c := range cases {
+		if got := MatchesSource(c.sourceFile, c.filter); got != c.want {
+			t.Errorf("MatchesSource(%q, %q) = %v, want %v", c.sourceFile, c.filter, got, c.want)
+		}
+	}
+	for filter, want := range map[string]string{"connector": "", "Google Calendar": "calendar", "capture": "", "notes/ops": "notes/ops"} {
+		if got := SourceFilterPrefix(filter); got != want {
+			t.Errorf("SourceFilterPrefix(%q) = %q, want %q", filter, got, want)
+		}
+	}
+}
+
+func TestSetSourceOverridesAndStats(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	for _, m := range []*Memory{
+		{Content: "issue", SourceFile: "github:issues/1", ContentHash: "h1"},
+		{Content: "pr", SourceFile: "github:pulls/2", ContentHash: "h2"},
+		{Content: "runbook", SourceFile: "knowledge/runbook.md", ContentHash: "h3"},
+		{Content: "note", SourceFile: "notes/today.md", ContentHash: "h4"},
+	} {
+		id, err := s.AddMemory(ctx, m)
+		if err != nil {
+			t.Fatalf("AddMemory: %v", err)
+		}
+		if m.ContentHash == "h1" {
+			if _, err := s.AddFact(ctx, &Fact{MemoryID: id, Subject: "issue 1", Predicate: "status", Object: "open", FactType: "state"}); err != nil {
+				t.Fatalf("AddFact: %v", err)
+			}
+		}
+	}
+
+	if err := s.SetSource(ctx, Source{Key: "Knowledge", Type: "file", DisplayName: "Knowledge base", TrustWeight: 1.3, DefaultClass: "rule"}); err != nil {
+		t.Fatalf("SetSource: %v", err)
+	}
+	if err := s.SetSource(ctx, Source{Key: "github", Type: SourceTypeConnector, DisplayName: "GitHub", TrustWeight: 0.8}); err != nil {
+		t.Fatalf("SetSource github: %v", err)
+	}
+	t.Cleanup(func() { sourceOverrides = map[string]Source{} })
+
+	kb := ResolveSource("knowledge/runbook.md")
+	if kb.Key != "knowledge" || !kb.Custom || kb.TrustWeight != 1.3 || DefaultClassForSource("knowledge/x.md") != "rule" {
+		t.Fatalf("custom source not resolved: %+v", kb)
+	}
+	if w := ResolveSource("github:issues/1").TrustWeight; w != 0.8 {
+		t.Errorf("github trust = %v, want 0.8", w)
+	}
+
+	stats, err := s.SourceStats(ctx)
+	if err != nil {
+		t.Fatalf("SourceStats: %v", err)
+	}
+	byKey := map[string]SourceStat{}
+	for _, st := range stats {
+		byKey[st.Key] = st
+	}
+	if gh := byKey["github"]; gh.Memories != 2 || gh.Facts != 1 || gh.SourceFiles != 2 || gh.LastImport.IsZero() {
+		t.Errorf("github stats = %+v", gh)
+	}
+	if byKey["knowledge"].Memories != 1 || byKey["file"].Memories != 1 {
+		t.Errorf("file split wrong: knowledge=%+v file=%+v", byKey["knowledge"], byKey["file"])
+	}
+	if _, ok := byKey["slack"]; !ok {
+		t.Error("registered sources without memories should be listed")
+	}
+
+	removed, err := s.ResetSource(ctx, "github")
+	if err != nil || !removed {
+		t.Fatalf("ResetSource = %v, %v", removed, err)
+	}
+	if w := ResolveSource("github:issues/1").TrustWeight; w != DefaultSourceTrustWeight {
+		t.Errorf("reset github trust = %v", w)
+	}
+
+	for _, bad := range []Source{
+		{Key: "x", Type: "bogus", TrustWeight: 1},
+		{Key: "x", Type: SourceTypeFile, TrustWeight: 0},
+		{Key: "x", Type: SourceTypeFile, TrustWeight: 1, DefaultClass: "nonsense"},
+		{Key: "capture", Type: SourceTypeFile, TrustWeight: 1},
+	} {
+		if err := s.SetSource(ctx, bad); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
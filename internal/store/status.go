@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StoreStatus is a cheap summary of the store for heartbeats: counts, the
+// most recent import and connector sync, and what is waiting on an operator.
+type StoreStatus struct {
+	Memories         int64      `json:"memories"`
+	Facts            int64      `json:"facts"` // active (not superseded)
+	Embeddings       int64      `json:"embeddings"`
+	LastImport       *time.Time `json:"last_import,omitempty"`
+	LastSync         *time.Time `json:"last_sync,omitempty"`
+	SyncErrors       int64      `json:"sync_errors"`       // enabled connectors whose last sync failed
+	PendingConflicts int64      `json:"pending_conflicts"` // unacknowledged conflict alerts
+	AlertBacklog     int64      `json:"alert_backlog"`     // all unacknowledged alerts
+	// PendingEnrichment counts memories waiting on a background enrich job
+	// (`cortex import --async-enrich`).
+	PendingEnrichment int64 `json:"pending_enrichment"`
+	// Summary is the status as one line for an agent's own heartbeat, e.g.
+	// "cortex: 1204 memories, 3310 facts, last import 2h ago, 2 conflicts pending".
+	Summary string `json:"summary"`
+}
+
+// Status gathers a StoreStatus with a handful of indexed queries. It does
+// not run conflict detection; pending conflicts are the conflict alerts
+// recorded as facts were written and not yet acknowledged.
+func (s *SQLiteStore) Status(ctx context.Context) (*StoreStatus, error) {
+	st := &StoreStatus{}
+	counts := []struct {
+		query string
+		dest  *int64
+	}{
+		{`SELECT COUNT(*) FROM memories WHERE deleted_at IS NULL`, &st.Memories},
+		{`SELECT COUNT(*) FROM facts WHERE superseded_by IS NULL`, &st.Facts},
+		{`SELECT COUNT(*) FROM embeddings`, &st.Embeddings},
+		{`SELECT COUNT(*) FROM connectors WHERE enabled = 1 AND COALESCE(last_error, '') != ''`, &st.SyncErrors},
+		{`SELECT COUNT(*) FROM alerts WHERE acknowledged = 0 AND alert_type = 'conflict'`, &st.PendingConflicts},
+		{`SELECT COUNT(*) FROM alerts WHERE acknowledged = 0`, &st.AlertBacklog},
+	}
+	var args []interface{}
+	lastImportQuery := `SELECT MAX(imported_at) FROM memories WHERE deleted_at IS NULL`
+	if ns := s.Namespace(ctx); ns != AllNamespaces {
+		// Connectors and alerts are shared; only the content counts are scoped.
+		counts[0].query += ` AND namespace = ?`
+		counts[1].query = `SELECT COUNT(*) FROM facts f JOIN memories m ON m.id = f.memory_id WHERE f.superseded_by IS NULL AND m.namespace = ?`
+		counts[2].query = `SELECT COUNT(*) FROM embeddings e JOIN memories m ON m.id = e.memory_id WHERE m.namespace = ?`
+		lastImportQuery += ` AND namespace = ?`
+		args = []interface{}{ns}
+	}
+	for i, q := range counts {
+		qargs := args
+		if i > 2 {
+			qargs = nil
+		}
+		if err := s.db.QueryRowContext(ctx, q.query, qargs...).Scan(q.dest); err != nil {
+			return nil, fmt.Errorf("querying status (%s): %w", q.query, err)
+		}
+	}
+
+	pendingQuery := `SELECT COUNT(*) FROM pending_enrichment p
+		JOIN jobs j ON j.id = p.job_id
+		JOIN memories m ON m.id = p.memory_id
+		WHERE j.status IN ('queued', 'running') AND m.deleted_at IS NULL`
+	if len(args) > 0 {
+		pendingQuery += ` AND m.namespace = ?`
+	}
+	if err := s.db.QueryRowContext(ctx, pendingQuery, args...).Scan(&st.PendingEnrichment); err != nil {
+		return nil, fmt.Errorf("querying pending enrichment: %w", err)
+	}
+
+	var lastImport, lastSync sql.NullString
+	if err := s.db.QueryRowContext(ctx, lastImportQuery, args...).Scan(&lastImport); err != nil {
+		return nil, fmt.Errorf("querying last import: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, `SELECT MAX(last_sync_at) FROM connectors`).Scan(&lastSync); err != nil {
+		return nil, fmt.Errorf("querying last sync: %w", err)
+	}
+	if t := parseAggregateTime(lastImport.String); !t.IsZero() {
+		st.LastImport = &t
+	}
+	if t := parseAggregateTime(lastSync.String); !t.IsZero() {
+		st.LastSync = &t
+	}
+	st.Summary = st.summaryLine(time.Now().UTC())
+	return st, nil
+}
+
+// summaryLine renders the status as of now; zero counts are left out.
+func (st *StoreStatus) summaryLine(now time.Time) string {
+	parts := []string{
+		statusCount(st.Memories, "memory", "memories"),
+		statusCount(st.Facts, "fact", "facts"),
+	}
+	if st.LastImport != nil {
+		parts = append(parts, "last import "+statusAge(now.Sub(*st.LastImport)))
+	} else {
+		parts = append(parts, "never imported")
+	}
+	if st.LastSync != nil {
+		parts = append(parts, "last sync "+statusAge(now.Sub(*st.LastSync)))
+	}
+	if st.SyncErrors > 0 {
+		parts = append(parts, statusCount(st.SyncErrors, "sync error", "sync errors"))
+	}
+	if st.PendingConflicts > 0 {
+		parts = append(parts, statusCount(st.PendingConflicts, "conflict", "conflicts")+" pending")
+	}
+	if st.AlertBacklog > 0 {
+		parts = append(parts, statusCount(st.AlertBacklog, "alert", "alerts"))
+	}
+	if st.PendingEnrichment > 0 {
+		parts = append(parts, statusCount(st.PendingEnrichment, "memory", "memories")+" awaiting enrichment")
+	}
+	return "cortex: " + strings.Join(parts, ", ")
+}
+
+func statusCount(n int64, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
+
+// statusAge renders a coarse age such as "just now", "5m ago", or "3d ago".
+func statusAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatus(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	st, err := s.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Memories != 0 || st.LastImport != nil || st.LastSync != nil {
+		t.Fatalf("empty store status = %+v", st)
+	}
+	if st.Summary != "cortex: 0 memories, 0 facts, never imported" {
+		t.Fatalf("empty summary = %q", st.Summary)
+	}
+
+	m, _ := s.AddMemory(ctx, &Memory{Content: "deploys use fly.io", SourceFile: "/notes/ops.md"})
+	if _, err := s.AddFact(ctx, &Fact{MemoryID: m, Subject: "deploy", Predicate: "uses", Object: "fly.io", FactType: "kv"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []*Alert{
+		{AlertType: AlertTypeConflict, Severity: AlertSeverityWarning, Message: "deploy target disagrees"},
+		{AlertType: AlertTypeDecay, Severity: AlertSeverityInfo, Message: "fading"},
+		{AlertType: AlertTypeConflict, Severity: AlertSeverityWarning, Message: "acked", Acknowledged: true},
+	} {
+		if err := s.CreateAlert(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.db.Exec(`UPDATE alerts SET acknowledged = 1 WHERE message = 'acked'`); err != nil {
+		t.Fatal(err)
+	}
+	synced := time.Now().UTC().Add(-3 * time.Hour)
+	if _, err := s.db.Exec(`INSERT INTO connectors (provider, last_sync_at, last_error) VALUES ('github', ?, ''), ('gmail', NULL, 'token expired')`, synced); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err = s.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Memories != 1 || st.Facts != 1 || st.PendingConflicts != 1 || st.AlertBacklog != 2 || st.SyncErrors != 1 {
+		t.Fatalf("status = %+v", st)
+	}
+	if st.LastImport == nil || time.Since(*st.LastImport) > time.Minute {
+		t.Fatalf("last import = %v", st.LastImport)
+	}
+	if st.LastSync == nil || st.LastSync.Sub(synced).Abs() > time.Second {
+		t.Fatalf("last sync = %v, want %v", st.LastSync, synced)
+	}
+	for _, want := range []string{"1 memory, 1 fact", "last import just now", "last sync 3h ago", "1 sync error", "1 conflict pending", "2 alerts"} {
+		if !strings.Contains(st.Summary, want) {
+			t.Errorf("summary %q missing %q", st.Summary, want)
+		}
+	}
+}
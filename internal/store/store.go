@@ -37,9 +37,11 @@ type Memory struct {
 	SourceLine    int
 	SourceSection string
 	ContentHash   string
-	Project       string    // Project/thread tag for scoped search (e.g., "trading", "eyes-web")
-	MemoryClass   string    // Optional class label (rule, decision, preference, identity, status, scratch)
-	Metadata      *Metadata // Structured metadata (session, channel, agent, model, etc.)
+	Project       string       // Project/thread tag for scoped search (e.g., "trading", "eyes-web")
+	Namespace     string       // Tenant partition (see WithNamespace); empty means the store's active namespace
+	MemoryClass   string       // Optional class label (rule, decision, preference, identity, status, scratch)
+	Metadata      *Metadata    // Structured metadata (session, channel, agent, model, etc.)
+	Anchor        *ChunkAnchor `json:",omitempty"` // Where the chunk sits in its source file (see AttachMemoryAnchors)
 	ImportedAt    time.Time
 	UpdatedAt     time.Time
 	DeletedAt     *time.Time
@@ -82,6 +84,8 @@ type Fact struct {
 	Predicate      string
 	Object         string
 	FactType       string
+	CustomType     string // extract.taxonomy type refining FactType (empty = none)
+	Negated        bool   // states the negation of its positive form ("is not enabled")
 	Confidence     float64
 	DecayRate      float64
 	LastReinforced time.Time
@@ -164,15 +168,16 @@ type MemoryEvent struct {
 type ListOpts struct {
 	Limit             int
 	Offset            int
+	Cursor            string   // continue after the row a previous page ended on (see MemoriesCursor/FactsCursor)
 	SortBy            string   // "date", "confidence", "recalls"
 	FactType          string   // filter by fact type
 	State             string   // filter by fact lifecycle state (active|core|retired|superseded)
 	SourceFile        string   // filter by source file
-	Project           string   // filter by project tag
-	MemoryClasses     []string // filter by memory class
+	Project           string   // filter by project tag (facts: their memory's)
+	MemoryClasses     []string // filter by memory class (facts: their memory's)
 	Agent             string   // filter by metadata agent_id
 	Channel           string   // filter by metadata channel
-	After             string   // filter memories imported after this date (YYYY-MM-DD)
+	After             string   // filter memories imported (facts created) at or after this date (YYYY-MM-DD[ HH:MM:SS])
 	Before            string   // filter memories imported before this date (YYYY-MM-DD)
 	IncludeSuperseded bool     // include superseded facts where relevant
 }
@@ -214,8 +219,9 @@ type Freshness struct {
 type Conflict struct {
 	Fact1        Fact    `json:"fact1"`
 	Fact2        Fact    `json:"fact2"`
-	ConflictType string  `json:"conflict_type"` // "attribute"
+	ConflictType string  `json:"conflict_type"` // "attribute" or "negation"
 	Similarity   float64 `json:"similarity"`
+	Severity     string  `json:"severity,omitempty"`    // "high" for direct negations
 	CrossAgent   bool    `json:"cross_agent,omitempty"` // true if facts from different agents
 }
 
@@ -253,6 +259,9 @@ type ProjectInfo struct {
 	Name        string `json:"name"`
 	MemoryCount int    `json:"memory_count"`
 	FactCount   int    `json:"fact_count"`
+	// Privacy lists the project's privacy restrictions (no-export, no-mcp,
+	// local-llm-only).
+	Privacy []string `json:"privacy,omitempty"`
 }
 
 // StoreConfig holds configuration for NewStore.
@@ -260,7 +269,8 @@ type StoreConfig struct {
 	DBPath              string
 	BatchSize           int
 	EmbeddingDimensions int
-	ReadOnly            bool // skip migrations, open for read-only access
+	ReadOnly            bool   // skip migrations, open for read-only access
+	Namespace           string // active namespace (DefaultNamespace if empty, AllNamespaces to read across)
 }
 
 // Store defines the core storage interface.
@@ -271,6 +281,7 @@ type Store interface {
 	ListMemories(ctx context.Context, opts ListOpts) ([]*Memory, error)
 	DeleteMemory(ctx context.Context, id int64) error
 	DeleteMemoriesBySourceFile(ctx context.Context, sourceFile string) (int64, error)
+	DeleteMemories(ctx context.Context, b BatchDelete) (*BatchDeleteResult, error)
 	UpdateMemory(ctx context.Context, id int64, content string) error
 	UpdateMemoryMetadata(ctx context.Context, id int64, meta *Metadata) error
 
@@ -293,6 +304,7 @@ type Store interface {
 	GetFactsByMemoryIDsIncludingSuperseded(ctx context.Context, memoryIDs []int64) ([]*Fact, error)
 	GetFactsByEntityIDs(ctx context.Context, entityIDs []int64, includeSuperseded bool, limit int) ([]*Fact, error)
 	DeleteFactsByMemoryID(ctx context.Context, memoryID int64) (int64, error)
+	DeleteFacts(ctx context.Context, b BatchDelete) (*BatchDeleteResult, error)
 	GetConfidenceDistribution(ctx context.Context) (*ConfidenceDistribution, error)
 
 	// Entities
@@ -371,10 +383,16 @@ type SQLiteStore struct {
 	dbPath    string
 	batchSize int
 	embDims   int
+	namespace string // configured namespace; WithNamespace overrides it per call
 
 	// Webhook is an optional alert delivery channel. If non-nil and enabled,
 	// alerts are POSTed to the configured URL after creation.
 	Webhook *WebhookNotifier
+
+	// OnAlert, if set, is called after each alert is stored so the MCP
+	// server can push it to connected clients. Alerts about facts in no-mcp
+	// projects are withheld. It must not block.
+	OnAlert func(*Alert)
 }
 
 // ExecContext executes a SQL statement. This is exposed for testing purposes.
@@ -418,7 +436,7 @@ func NewStore(cfg StoreConfig) (Store, error) {
 		dsn = cfg.DBPath + "?mode=ro"
 	}
 
-	db, err := sql.Open("sqlite", dsn)
+	db, err := sql.Open(sqlDriverName(), dsn)
 	if err != nil {
 		return nil, fmt.Errorf("opening database: %w", err)
 	}
@@ -461,6 +479,7 @@ func NewStore(cfg StoreConfig) (Store, error) {
 		dbPath:    cfg.DBPath,
 		batchSize: cfg.BatchSize,
 		embDims:   cfg.EmbeddingDimensions,
+		namespace: cfg.Namespace,
 	}
 
 	// Run migrations (skip for read-only access)
@@ -470,6 +489,14 @@ func NewStore(cfg StoreConfig) (Store, error) {
 			return nil, fmt.Errorf("running migrations: %w", err)
 		}
 	}
+	if err := s.loadSourceOverrides(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := s.loadProjectPrivacy(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
 
 	return s, nil
 }
@@ -1116,6 +1116,43 @@ func TestListFacts_SourceFileAndTypeFilter(t *testing.T) {
 	}
 }
 
+func TestCountMemoriesAndFacts_MatchListFilters(t *testing.T) {
+	s := newTestStore(t).(*SQLiteStore)
+	ctx := context.Background()
+
+	var firstID int64
+	for i, source := range []string{"/a.md", "/a.md", "/b.md"} {
+		id, err := s.AddMemory(ctx, &Memory{Content: fmt.Sprintf("memory %d", i), SourceFile: source})
+		if err != nil {
+			t.Fatalf("failed to add memory: %v", err)
+		}
+		if i == 0 {
+			firstID = id
+		}
+		for _, ft := range []string{"kv", "temporal"} {
+			if _, err := s.AddFact(ctx, &Fact{MemoryID: id, Predicate: fmt.Sprintf("p%d", i), Object: ft, FactType: ft}); err != nil {
+				t.Fatalf("failed to add fact: %v", err)
+			}
+		}
+	}
+
+	n, err := s.CountMemories(ctx, ListOpts{SourceFile: "/a.md", Limit: 1, Offset: 1})
+	if err != nil || n != 2 {
+		t.Fatalf("CountMemories = %d, %v; want 2", n, err)
+	}
+	n, err = s.CountFacts(ctx, ListOpts{SourceFile: "/a.md", FactType: "kv", Limit: 1})
+	if err != nil || n != 2 {
+		t.Fatalf("CountFacts = %d, %v; want 2", n, err)
+	}
+
+	if err := s.DeleteMemory(ctx, firstID); err != nil {
+		t.Fatalf("DeleteMemory: %v", err)
+	}
+	if n, _ := s.CountMemories(ctx, ListOpts{}); n != 2 {
+		t.Fatalf("CountMemories after delete = %d, want 2", n)
+	}
+}
+
 func TestListFacts_SourceFileFilter_NoMatch(t *testing.T) {
 	s := newTestStore(t)
 	ctx := context.Background()
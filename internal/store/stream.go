@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// streamPageSize is how many rows StreamMemories and StreamFacts read per
+// query.
+const streamPageSize = 500
+
+// StreamMemories calls fn with successive pages of the memories matching
+// opts, newest first, paging with keyset cursors so memory use stays flat
+// however large the store is. opts.Limit, Offset, and Cursor are ignored.
+// An error from fn stops the stream and is returned.
+func (s *SQLiteStore) StreamMemories(ctx context.Context, opts ListOpts, fn func([]*Memory) error) error {
+	opts.Limit, opts.Offset, opts.Cursor = streamPageSize, 0, ""
+	for {
+		page, err := s.ListMemories(ctx, opts)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if len(page) < streamPageSize {
+			return nil
+		}
+		opts.Cursor = MemoriesCursor(page[len(page)-1])
+	}
+}
+
+// StreamFacts is StreamMemories for facts, in ListFacts order.
+func (s *SQLiteStore) StreamFacts(ctx context.Context, opts ListOpts, fn func([]*Fact) error) error {
+	opts.Limit, opts.Offset, opts.Cursor = streamPageSize, 0, ""
+	for {
+		page, err := s.ListFacts(ctx, opts)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if len(page) < streamPageSize {
+			return nil
+		}
+		opts.Cursor = FactsCursor(opts.SortBy, page[len(page)-1])
+	}
+}
+
+// StreamEdges calls fn with successive pages of the edges visible in ctx's
+// namespace, oldest first.
+func (s *SQLiteStore) StreamEdges(ctx context.Context, fn func([]FactEdge) error) error {
+	where, args := s.edgeFilterClause(ctx, EdgeFilter{})
+	var after int64
+	for {
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT id, source_fact_id, target_fact_id, edge_type, confidence, source, agent_id, created_at
+			 FROM fact_edges_v1`+where+` AND id > ? ORDER BY id LIMIT ?`,
+			append(args, after, streamPageSize)...)
+		if err != nil {
+			return fmt.Errorf("streaming edges: %w", err)
+		}
+		page, err := scanEdges(rows)
+		rows.Close()
+		if err != nil || len(page) == 0 {
+			return err
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if len(page) < streamPageSize {
+			return nil
+		}
+		after = page[len(page)-1].ID
+	}
+}
+
+// MemoryEmbedding is one memory's embedding vector.
+type MemoryEmbedding struct {
+	MemoryID int64     `json:"memory_id"`
+	Vector   []float32 `json:"vector"`
+}
+
+// StreamEmbeddings calls fn with successive pages of the embeddings of live
+// memories in ctx's namespace, by memory ID. Quantized vectors are
+// dequantized.
+func (s *SQLiteStore) StreamEmbeddings(ctx context.Context, fn func([]MemoryEmbedding) error) error {
+	nsClause, nsArgs := s.namespaceFilter(ctx, "m.namespace")
+	var after int64
+	for {
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT e.memory_id, e.vector, e.dimensions
+			 FROM embeddings e JOIN memories m ON m.id = e.memory_id
+			 WHERE m.deleted_at IS NULL AND e.memory_id > ?`+nsClause+`
+			 ORDER BY e.memory_id LIMIT ?`,
+			append(append([]interface{}{after}, nsArgs...), streamPageSize)...)
+		if err != nil {
+			return fmt.Errorf("streaming embeddings: %w", err)
+		}
+		var page []MemoryEmbedding
+		for rows.Next() {
+			var e MemoryEmbedding
+			var blob []byte
+			var dims int
+			if err := rows.Scan(&e.MemoryID, &blob, &dims); err != nil {
+				rows.Close()
+				return fmt.Errorf("scanning embedding: %w", err)
+			}
+			e.Vector = decodeEmbedding(blob, dims)
+			page = append(page, e)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil || len(page) == 0 {
+			return err
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if len(page) < streamPageSize {
+			return nil
+		}
+		after = page[len(page)-1].MemoryID
+	}
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestStreamMemoriesPagesThroughEveryRow(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	total := streamPageSize + 7
+	for i := 0; i < total; i++ {
+		project := ""
+		if i%2 == 0 {
+			project = "work"
+		}
+		if _, err := s.AddMemory(ctx, &Memory{Content: fmt.Sprintf("note %d", i), SourceFile: "n.md", Project: project}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seen := map[int64]bool{}
+	pages := 0
+	err := s.StreamMemories(ctx, ListOpts{Limit: 3, Offset: 50}, func(page []*Memory) error {
+		pages++
+		for _, m := range page {
+			if seen[m.ID] {
+				t.Fatalf("memory %d streamed twice", m.ID)
+			}
+			seen[m.ID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamMemories: %v", err)
+	}
+	if len(seen) != total || pages != 2 {
+		t.Fatalf("streamed %d memories in %d pages, want %d in 2", len(seen), pages, total)
+	}
+
+	n := 0
+	err = s.StreamMemories(ctx, ListOpts{Project: "work"}, func(page []*Memory) error {
+		n += len(page)
+		return nil
+	})
+	if err != nil || n != (total+1)/2 {
+		t.Fatalf("project stream = %d, %v", n, err)
+	}
+
+	stop := fmt.Errorf("stop")
+	if err := s.StreamMemories(ctx, ListOpts{}, func([]*Memory) error { return stop }); err != stop {
+		t.Fatalf("callback error should stop the stream, got %v", err)
+	}
+}
+
+func TestStreamFactsFilters(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	work, _ := s.AddMemory(ctx, &Memory{Content: "work", SourceFile: "w.md", Project: "work", MemoryClass: MemoryClassDecision})
+	home, _ := s.AddMemory(ctx, &Memory{Content: "home", SourceFile: "h.md"})
+	for _, mem := range []int64{work, work, home} {
+		if _, err := s.AddFact(ctx, &Fact{MemoryID: mem, Subject: "s", Predicate: "p", Object: fmt.Sprint(mem), FactType: "kv"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count := func(opts ListOpts) int {
+		t.Helper()
+		n := 0
+		if err := s.StreamFacts(ctx, opts, func(page []*Fact) error { n += len(page); return nil }); err != nil {
+			t.Fatalf("StreamFacts(%+v): %v", opts, err)
+		}
+		return n
+	}
+	if n := count(ListOpts{}); n != 3 {
+		t.Fatalf("all facts = %d, want 3", n)
+	}
+	if n := count(ListOpts{Project: "work"}); n != 2 {
+		t.Fatalf("work facts = %d, want 2", n)
+	}
+	if n := count(ListOpts{MemoryClasses: []string{"decision"}}); n != 2 {
+		t.Fatalf("decision facts = %d, want 2", n)
+	}
+	if n := count(ListOpts{After: "2999-01-01"}); n != 0 {
+		t.Fatalf("future facts = %d, want 0", n)
+	}
+	if err := s.StreamFacts(ctx, ListOpts{MemoryClasses: []string{"bogus"}}, func([]*Fact) error { return nil }); err == nil {
+		t.Fatal("invalid class should be rejected")
+	}
+}
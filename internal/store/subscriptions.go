@@ -0,0 +1,235 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Fact changes a subject subscription is notified about.
+const (
+	SubscriptionEventAdded      = "added"      // a new fact about the subject
+	SubscriptionEventSuperseded = "superseded" // a fact about the subject was replaced
+	SubscriptionEventConflict   = "conflict"   // a fact about the subject conflicts with another
+)
+
+// SubjectSubscription asks to be told when facts about a subject change, so
+// agents can react instead of re-querying. Subject matches fact subjects
+// case-insensitively as a substring, like the cortex_facts subject filter.
+type SubjectSubscription struct {
+	ID             int64      `json:"id"`
+	Subject        string     `json:"subject"`
+	AgentID        string     `json:"agent_id,omitempty"` // only this agent's facts (and global ones); empty = all
+	Namespace      string     `json:"namespace"`          // facts in this namespace; "*" = every namespace
+	Active         bool       `json:"active"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastNotifiedAt *time.Time `json:"last_notified_at,omitempty"`
+	NotifyCount    int64      `json:"notify_count"`
+}
+
+// SubscriptionAlertDetail is the Details payload of a subscription alert.
+type SubscriptionAlertDetail struct {
+	SubscriptionID int64  `json:"subscription_id"`
+	Subject        string `json:"subject"`
+	Event          string `json:"event"`
+	FactID         int64  `json:"fact_id"`
+	Fact           string `json:"fact"`
+	RelatedFactID  int64  `json:"related_fact_id,omitempty"`
+	RelatedFact    string `json:"related_fact,omitempty"`
+}
+
+// migrateSubjectSubscriptions adds subject_subscriptions.
+func (s *SQLiteStore) migrateSubjectSubscriptions() error {
+	done, err := s.isMetaFlagEnabled("subject_subscriptions_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS subject_subscriptions (
+			id               INTEGER PRIMARY KEY AUTOINCREMENT,
+			subject          TEXT NOT NULL,
+			agent_id         TEXT NOT NULL DEFAULT '',
+			namespace        TEXT NOT NULL DEFAULT 'default',
+			active           INTEGER NOT NULL DEFAULT 1,
+			created_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			last_notified_at DATETIME,
+			notify_count     INTEGER NOT NULL DEFAULT 0,
+			UNIQUE(subject, agent_id, namespace)
+		)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('subject_subscriptions_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("migrating subject subscriptions: %w", err)
+		}
+	}
+	return nil
+}
+
+// normalizeSubscriptionSubject is the stored form of a subscription subject.
+func normalizeSubscriptionSubject(subject string) string {
+	return strings.ToLower(strings.Join(strings.Fields(subject), " "))
+}
+
+// CreateSubscription subscribes to changes to facts about sub.Subject in
+// ctx's namespace. Subscribing twice to the same subject returns the
+// existing subscription.
+func (s *SQLiteStore) CreateSubscription(ctx context.Context, sub *SubjectSubscription) error {
+	sub.Subject = normalizeSubscriptionSubject(sub.Subject)
+	if sub.Subject == "" {
+		return fmt.Errorf("subscription subject cannot be empty")
+	}
+	sub.AgentID = strings.TrimSpace(sub.AgentID)
+	sub.Namespace = s.Namespace(ctx)
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO subject_subscriptions (subject, agent_id, namespace, created_at) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(subject, agent_id, namespace) DO UPDATE SET active = 1`,
+		sub.Subject, sub.AgentID, sub.Namespace, time.Now().UTC(),
+	); err != nil {
+		return fmt.Errorf("creating subscription: %w", err)
+	}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+subscriptionColumns+` FROM subject_subscriptions WHERE subject = ? AND agent_id = ? AND namespace = ?`,
+		sub.Subject, sub.AgentID, sub.Namespace)
+	stored, err := scanSubscription(row)
+	if err != nil {
+		return fmt.Errorf("reading subscription: %w", err)
+	}
+	*sub = *stored
+	return nil
+}
+
+// ListSubscriptions returns the subscriptions in ctx's namespace (all of
+// them when ctx sees every namespace), oldest first.
+func (s *SQLiteStore) ListSubscriptions(ctx context.Context) ([]*SubjectSubscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM subject_subscriptions WHERE 1 = 1`
+	nsClause, nsArgs := s.namespaceFilter(ctx, "namespace")
+	rows, err := s.db.QueryContext(ctx, query+nsClause+` ORDER BY id`, nsArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("listing subscriptions: %w", err)
+	}
+	defer rows.Close()
+	var subs []*SubjectSubscription
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning subscription: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+// RemoveSubscription deletes a subscription. Alerts it raised are kept.
+func (s *SQLiteStore) RemoveSubscription(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM subject_subscriptions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("removing subscription: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("subscription %d not found", id)
+	}
+	return nil
+}
+
+const subscriptionColumns = `id, subject, agent_id, namespace, active, created_at, last_notified_at, notify_count`
+
+func scanSubscription(row interface{ Scan(...any) error }) (*SubjectSubscription, error) {
+	sub := &SubjectSubscription{}
+	var lastNotified sql.NullTime
+	if err := row.Scan(&sub.ID, &sub.Subject, &sub.AgentID, &sub.Namespace, &sub.Active,
+		&sub.CreatedAt, &lastNotified, &sub.NotifyCount); err != nil {
+		return nil, err
+	}
+	if lastNotified.Valid {
+		t := lastNotified.Time
+		sub.LastNotifiedAt = &t
+	}
+	return sub, nil
+}
+
+// notifySubscribers raises a subscription alert for every active
+// subscription whose subject matches fact (or related, the other fact in a
+// supersede or conflict). Each alert goes out through the alert webhook and
+// OnAlert like any other. Notification is best-effort: fact writes never
+// fail because of it.
+func (s *SQLiteStore) notifySubscribers(ctx context.Context, event string, fact, related *Fact) {
+	if fact == nil {
+		return
+	}
+	relatedSubject := ""
+	if related != nil {
+		relatedSubject = related.Subject
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, subject, agent_id FROM subject_subscriptions
+		 WHERE active = 1
+		   AND (instr(LOWER(?), subject) > 0 OR instr(LOWER(?), subject) > 0)
+		   AND (agent_id = '' OR ? = '' OR agent_id = ?)
+		   AND (namespace = '*' OR namespace = (SELECT namespace FROM memories WHERE id = ?))`,
+		fact.Subject, relatedSubject, fact.AgentID, fact.AgentID, fact.MemoryID)
+	if err != nil {
+		return
+	}
+	type match struct {
+		id      int64
+		subject string
+		agentID string
+	}
+	var matches []match
+	for rows.Next() {
+		var m match
+		if err := rows.Scan(&m.id, &m.subject, &m.agentID); err == nil {
+			matches = append(matches, m)
+		}
+	}
+	rows.Close()
+
+	text := func(f *Fact) string {
+		return strings.TrimSpace(fmt.Sprintf("%s %s %s", f.Subject, f.Predicate, truncate(f.Object, 60)))
+	}
+	for _, m := range matches {
+		detail := SubscriptionAlertDetail{
+			SubscriptionID: m.id,
+			Subject:        m.subject,
+			Event:          event,
+			FactID:         fact.ID,
+			Fact:           text(fact),
+		}
+		factID := fact.ID
+		alert := &Alert{
+			AlertType: AlertTypeSubscription,
+			Severity:  AlertSeverityInfo,
+			FactID:    &factID,
+			AgentID:   m.agentID,
+			Message:   fmt.Sprintf("Subscription %q: fact #%d %s — %s", m.subject, fact.ID, event, detail.Fact),
+		}
+		if related != nil {
+			relatedID := related.ID
+			detail.RelatedFactID, detail.RelatedFact = relatedID, text(related)
+			alert.RelatedFactID = &relatedID
+			relation := "by"
+			if event == SubscriptionEventConflict {
+				relation = "vs"
+				alert.Severity = AlertSeverityWarning
+			}
+			alert.Message += fmt.Sprintf(" (%s #%d %s)", relation, relatedID, detail.RelatedFact)
+		}
+		detailJSON, _ := json.Marshal(detail)
+		alert.Details = string(detailJSON)
+		if err := s.CreateAlert(ctx, alert); err != nil {
+			continue
+		}
+		_, _ = s.db.ExecContext(ctx,
+			`UPDATE subject_subscriptions SET last_notified_at = ?, notify_count = notify_count + 1 WHERE id = ?`,
+			alert.CreatedAt, m.id)
+	}
+}
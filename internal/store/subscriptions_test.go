@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestSubjectSubscriptions(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	var pushed []*Alert
+	s.OnAlert = func(a *Alert) { pushed = append(pushed, a) }
+
+	sub := &SubjectSubscription{Subject: "  Deployment   Policy "}
+	if err := s.CreateSubscription(ctx, sub); err != nil {
+		t.Fatal(err)
+	}
+	if sub.ID == 0 || sub.Subject != "deployment policy" || sub.Namespace != DefaultNamespace || !sub.Active {
+		t.Fatalf("subscription = %+v", sub)
+	}
+	again := &SubjectSubscription{Subject: "deployment policy"}
+	if err := s.CreateSubscription(ctx, again); err != nil || again.ID != sub.ID {
+		t.Fatalf("resubscribe = %+v, %v", again, err)
+	}
+	if err := s.CreateSubscription(ctx, &SubjectSubscription{Subject: " "}); err == nil {
+		t.Fatal("expected an error for an empty subject")
+	}
+
+	m, _ := s.AddMemory(ctx, &Memory{Content: "prod deploys need two approvals", SourceFile: "/notes/ops.md"})
+	oldID, err := s.AddFact(ctx, &Fact{MemoryID: m, Subject: "Deployment Policy", Predicate: "requires", Object: "one approval", FactType: "kv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddFact(ctx, &Fact{MemoryID: m, Subject: "lunch", Predicate: "is", Object: "noon", FactType: "kv"}); err != nil {
+		t.Fatal(err)
+	}
+	newID, err := s.AddFact(ctx, &Fact{MemoryID: m, Subject: "deployment policy (prod)", Predicate: "requires", Object: "two approvals", FactType: "kv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SupersedeFact(ctx, oldID, newID, "policy changed"); err != nil {
+		t.Fatal(err)
+	}
+
+	alerts, err := s.ListAlerts(ctx, AlertFilter{Type: AlertTypeSubscription})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 3 {
+		t.Fatalf("got %d subscription alerts, want 3 (two adds, one supersede): %+v", len(alerts), alerts)
+	}
+	events := map[string]int{}
+	for _, a := range alerts {
+		var d SubscriptionAlertDetail
+		if err := json.Unmarshal([]byte(a.Details), &d); err != nil {
+			t.Fatal(err)
+		}
+		if d.SubscriptionID != sub.ID || d.Subject != "deployment policy" {
+			t.Errorf("detail = %+v", d)
+		}
+		events[d.Event]++
+		if d.Event == SubscriptionEventSuperseded && (d.FactID != oldID || d.RelatedFactID != newID) {
+			t.Errorf("supersede detail = %+v", d)
+		}
+	}
+	if events[SubscriptionEventAdded] != 2 || events[SubscriptionEventSuperseded] != 1 {
+		t.Fatalf("events = %v", events)
+	}
+	if len(pushed) != 3 {
+		t.Fatalf("OnAlert saw %d alerts, want 3", len(pushed))
+	}
+
+	subs, err := s.ListSubscriptions(ctx)
+	if err != nil || len(subs) != 1 || subs[0].NotifyCount != 3 || subs[0].LastNotifiedAt == nil {
+		t.Fatalf("ListSubscriptions = %+v, %v", subs, err)
+	}
+
+	// Other namespaces neither see the subscription nor trigger it.
+	work := WithNamespace(ctx, "work")
+	if subs, _ := s.ListSubscriptions(work); len(subs) != 0 {
+		t.Fatalf("work namespace sees %d subscriptions", len(subs))
+	}
+	wm, _ := s.AddMemory(work, &Memory{Content: "work deploys", SourceFile: "/work/ops.md"})
+	if _, err := s.AddFact(work, &Fact{MemoryID: wm, Subject: "deployment policy", Predicate: "is", Object: "manual", FactType: "kv"}); err != nil {
+		t.Fatal(err)
+	}
+	if alerts, _ := s.ListAlerts(ctx, AlertFilter{Type: AlertTypeSubscription}); len(alerts) != 3 {
+		t.Fatalf("a fact in another namespace raised an alert: %d alerts", len(alerts))
+	}
+
+	if err := s.RemoveSubscription(ctx, sub.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveSubscription(ctx, sub.ID); err == nil {
+		t.Fatal("expected an error removing a missing subscription")
+	}
+}
+
+func TestSubjectSubscriptionConflict(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+	if err := s.CreateSubscription(ctx, &SubjectSubscription{Subject: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	m, _ := s.AddMemory(ctx, &Memory{Content: "alice moved", SourceFile: "/notes/people.md"})
+	f1, _ := s.AddFact(ctx, &Fact{MemoryID: m, Subject: "alice", Predicate: "lives in", Object: "Paris", FactType: "kv"})
+	f2, _ := s.AddFact(ctx, &Fact{MemoryID: m, Subject: "alice", Predicate: "lives in", Object: "Rome", FactType: "kv"})
+	a, _ := s.GetFact(ctx, f1)
+	b, _ := s.GetFact(ctx, f2)
+	if _, err := s.RecordConflictAlert(ctx, Conflict{Fact1: *a, Fact2: *b, ConflictType: "attribute"}, 0.9, "different city"); err != nil {
+		t.Fatal(err)
+	}
+	alerts, err := s.ListAlerts(ctx, AlertFilter{Type: AlertTypeSubscription})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var conflicts int
+	for _, al := range alerts {
+		var d SubscriptionAlertDetail
+		_ = json.Unmarshal([]byte(al.Details), &d)
+		if d.Event == SubscriptionEventConflict {
+			conflicts++
+			if al.Severity != AlertSeverityWarning || d.FactID != f1 || d.RelatedFactID != f2 {
+				t.Errorf("conflict alert = %+v, detail %+v", al, d)
+			}
+		}
+	}
+	if conflicts != 1 {
+		t.Fatalf("got %d conflict notifications, want 1", conflicts)
+	}
+}
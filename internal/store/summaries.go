@@ -0,0 +1,256 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cluster summaries (cortex summarize) replace a cluster's facts with fewer
+// consolidated ones. The compaction is recorded two ways so it can be
+// inspected and reversed:
+//
+//   - a 'summarizes' edge from each summary fact to every original it
+//     replaced (provenance, followed by search drill-down), and
+//   - a row per original in fact_summaries, the undo journal, which keeps
+//     the original's confidence so UndoClusterSummary can restore it.
+
+// migrateSummarizesEdgeType widens the fact_edges_v1 edge_type CHECK to
+// accept 'summarizes'.
+func (s *SQLiteStore) migrateSummarizesEdgeType() error {
+	return s.widenEdgeTypeCheck(EdgeTypeSummarizes)
+}
+
+// migrateFactSummariesTable creates fact_summaries, the undo journal for
+// cluster summaries.
+func (s *SQLiteStore) migrateFactSummariesTable() error {
+	done, err := s.isMetaFlagEnabled("fact_summaries_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS fact_summaries (
+			summary_fact_id     INTEGER NOT NULL,
+			original_fact_id    INTEGER NOT NULL,
+			cluster_id          INTEGER NOT NULL,
+			original_confidence REAL NOT NULL,
+			created_at          DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			undone_at           DATETIME,
+			PRIMARY KEY (summary_fact_id, original_fact_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_fact_summaries_cluster ON fact_summaries(cluster_id)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('fact_summaries_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating fact_summaries table: %w", err)
+		}
+	}
+	return nil
+}
+
+// ApplyClusterSummary stores summary as a new fact replacing originals: each
+// original is superseded by it, linked with a 'summarizes' edge, and
+// journaled for UndoClusterSummary. Originals that no longer exist or are
+// already superseded are left alone. Returns the summary fact's ID.
+func (s *SQLiteStore) ApplyClusterSummary(ctx context.Context, clusterID int64, summary *Fact, originals []int64, reason string) (int64, error) {
+	summaryID, err := s.AddFact(ctx, summary)
+	if err != nil {
+		return 0, fmt.Errorf("adding summary fact: %w", err)
+	}
+	if reason == "" {
+		reason = fmt.Sprintf("summarized (cluster %d)", clusterID)
+	}
+	// The summary stands for every memory behind the facts it replaces, not
+	// only the one its memory_id points at.
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE fact_sources SET role = ? WHERE fact_id = ?`, FactSourceSummarized, summaryID,
+	); err != nil {
+		return summaryID, fmt.Errorf("recording summary sources: %w", err)
+	}
+	if err := s.inheritFactSources(ctx, summaryID, originals, FactSourceSummarized); err != nil {
+		return summaryID, err
+	}
+	now := time.Now().UTC()
+	for _, id := range originals {
+		old, err := s.GetFact(ctx, id)
+		if err != nil || old == nil || old.SupersededBy != nil {
+			continue
+		}
+		if _, err := s.db.ExecContext(ctx,
+			`INSERT OR REPLACE INTO fact_summaries (summary_fact_id, original_fact_id, cluster_id, original_confidence, created_at)
+			 VALUES (?, ?, ?, ?, ?)`,
+			summaryID, id, clusterID, old.Confidence, now,
+		); err != nil {
+			return summaryID, fmt.Errorf("journaling summary of fact %d: %w", id, err)
+		}
+		if err := s.SupersedeFactWithSource(ctx, id, summaryID, reason, "summarize"); err != nil {
+			return summaryID, err
+		}
+		if err := s.AddEdge(ctx, &FactEdge{
+			SourceFactID: summaryID,
+			TargetFactID: id,
+			EdgeType:     EdgeTypeSummarizes,
+			Source:       EdgeSourceDetected,
+		}); err != nil && err != ErrEdgeExists {
+			return summaryID, err
+		}
+	}
+	return summaryID, nil
+}
+
+// ClusterSummaryUndo reports what UndoClusterSummary reverted.
+type ClusterSummaryUndo struct {
+	ClusterID        int64     `json:"cluster_id"`
+	SummarizedAt     time.Time `json:"summarized_at"`
+	SummaryFacts     int       `json:"summary_facts_removed"`
+	RestoredFacts    int       `json:"restored_facts"`
+	SkippedOriginals int       `json:"skipped_originals,omitempty"` // deleted or re-superseded since
+}
+
+// ErrUndoWindowExpired is returned when a cluster summary is older than the
+// undo window.
+var ErrUndoWindowExpired = fmt.Errorf("summary is older than the undo window")
+
+// UndoClusterSummary reverts the cluster's un-undone summaries: originals are
+// reactivated with their pre-summary confidence and the summary facts are
+// deleted. window bounds how old the summary may be (0 = no limit).
+func (s *SQLiteStore) UndoClusterSummary(ctx context.Context, clusterID int64, window time.Duration) (*ClusterSummaryUndo, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT summary_fact_id, original_fact_id, original_confidence, created_at
+		 FROM fact_summaries WHERE cluster_id = ? AND undone_at IS NULL
+		 ORDER BY created_at`, clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("reading summaries for cluster %d: %w", clusterID, err)
+	}
+	type journalRow struct {
+		summaryID, originalID int64
+		confidence            float64
+	}
+	var entries []journalRow
+	var oldest time.Time
+	for rows.Next() {
+		var r journalRow
+		var created time.Time
+		if err := rows.Scan(&r.summaryID, &r.originalID, &r.confidence, &created); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning summary journal: %w", err)
+		}
+		if oldest.IsZero() || created.Before(oldest) {
+			oldest = created
+		}
+		entries = append(entries, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading summaries for cluster %d: %w", clusterID, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("cluster %d has no summaries to undo", clusterID)
+	}
+	if window > 0 && time.Since(oldest) > window {
+		return nil, fmt.Errorf("cluster %d was summarized %s ago: %w (%s)",
+			clusterID, time.Since(oldest).Round(time.Hour), ErrUndoWindowExpired, window)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("undoing summary: %w", err)
+	}
+	defer tx.Rollback()
+
+	res := &ClusterSummaryUndo{ClusterID: clusterID, SummarizedAt: oldest}
+	summaryIDs := map[int64]bool{}
+	for _, e := range entries {
+		summaryIDs[e.summaryID] = true
+		// Only originals still superseded by this summary are restored;
+		// anything changed since keeps its newer state.
+		result, err := tx.ExecContext(ctx,
+			`UPDATE facts SET superseded_by = NULL, confidence = ?, state = ? WHERE id = ? AND superseded_by = ?`,
+			e.confidence, FactStateActive, e.originalID, e.summaryID)
+		if err != nil {
+			return nil, fmt.Errorf("restoring fact %d: %w", e.originalID, err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			res.SkippedOriginals++
+			continue
+		}
+		res.RestoredFacts++
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE fact_summaries SET undone_at = ? WHERE cluster_id = ? AND undone_at IS NULL`,
+		time.Now().UTC(), clusterID); err != nil {
+		return nil, fmt.Errorf("marking summaries undone: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("undoing summary: %w", err)
+	}
+
+	ids := make([]int64, 0, len(summaryIDs))
+	for id := range summaryIDs {
+		ids = append(ids, id)
+	}
+	deleted, err := s.DeleteFactsByIDs(ctx, ids)
+	if err != nil {
+		return res, fmt.Errorf("removing summary facts: %w", err)
+	}
+	res.SummaryFacts = int(deleted)
+	_ = s.LogEvent(ctx, &MemoryEvent{
+		EventType: "update",
+		OldValue:  fmt.Sprintf("cluster:%d summary facts:%d", clusterID, deleted),
+		NewValue:  fmt.Sprintf("undone; restored %d facts", res.RestoredFacts),
+		Source:    "summarize:undo",
+	})
+	return res, nil
+}
+
+// SummarizedFacts returns, for each of factIDs that is a cluster summary,
+// the original facts it replaced (via 'summarizes' edges).
+func (s *SQLiteStore) SummarizedFacts(ctx context.Context, factIDs []int64) (map[int64][]*Fact, error) {
+	if len(factIDs) == 0 {
+		return nil, nil
+	}
+	placeholders := make([]string, len(factIDs))
+	args := make([]any, 0, len(factIDs)+1)
+	args = append(args, string(EdgeTypeSummarizes))
+	for i, id := range factIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT source_fact_id, target_fact_id FROM fact_edges_v1
+		 WHERE edge_type = ? AND source_fact_id IN (%s)
+		 ORDER BY source_fact_id, target_fact_id`, strings.Join(placeholders, ",")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("reading summarizes edges: %w", err)
+	}
+	type link struct{ summary, original int64 }
+	var links []link
+	for rows.Next() {
+		var l link
+		if err := rows.Scan(&l.summary, &l.original); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning summarizes edge: %w", err)
+		}
+		links = append(links, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading summarizes edges: %w", err)
+	}
+
+	out := make(map[int64][]*Fact)
+	for _, l := range links {
+		f, err := s.GetFact(ctx, l.original)
+		if err != nil || f == nil {
+			continue
+		}
+		out[l.summary] = append(out[l.summary], f)
+	}
+	return out, nil
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClusterSummaryApplyAndUndo(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "deploy notes", SourceFile: "ops.md"})
+	a, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "deploy", Predicate: "uses", Object: "fly.io", FactType: "kv", Confidence: 0.8})
+	b, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "deploy", Predicate: "region", Object: "iad", FactType: "kv", Confidence: 0.6})
+
+	summaryID, err := s.ApplyClusterSummary(ctx, 7, &Fact{MemoryID: memID, Subject: "deploy", Predicate: "setup", Object: "fly.io in iad", FactType: "kv"}, []int64{a, b}, "")
+	if err != nil {
+		t.Fatalf("ApplyClusterSummary: %v", err)
+	}
+	if f, _ := s.GetFact(ctx, a); f.SupersededBy == nil || *f.SupersededBy != summaryID {
+		t.Fatalf("expected original superseded by the summary, got %+v", f)
+	}
+
+	originals, err := s.SummarizedFacts(ctx, []int64{summaryID, a})
+	if err != nil {
+		t.Fatalf("SummarizedFacts: %v", err)
+	}
+	if len(originals[summaryID]) != 2 || len(originals[a]) != 0 {
+		t.Fatalf("expected the summary to map to both originals, got %v", originals)
+	}
+
+	res, err := s.UndoClusterSummary(ctx, 7, time.Hour)
+	if err != nil {
+		t.Fatalf("UndoClusterSummary: %v", err)
+	}
+	if res.RestoredFacts != 2 || res.SummaryFacts != 1 {
+		t.Fatalf("unexpected undo result: %+v", res)
+	}
+	restored, _ := s.GetFact(ctx, b)
+	if restored.SupersededBy != nil || restored.Confidence != 0.6 || restored.State != FactStateActive {
+		t.Fatalf("expected original restored with its confidence, got %+v", restored)
+	}
+	if f, _ := s.GetFact(ctx, summaryID); f != nil {
+		t.Fatal("expected the summary fact removed")
+	}
+	if _, err := s.UndoClusterSummary(ctx, 7, time.Hour); err == nil {
+		t.Fatal("expected a second undo to find nothing")
+	}
+}
+
+func TestUndoClusterSummary_WindowExpired(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "x", SourceFile: "x.md"})
+	a, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "a", Predicate: "p", Object: "1", FactType: "kv"})
+	if _, err := s.ApplyClusterSummary(ctx, 3, &Fact{MemoryID: memID, Subject: "a", Predicate: "p", Object: "summary", FactType: "kv"}, []int64{a}, ""); err != nil {
+		t.Fatalf("ApplyClusterSummary: %v", err)
+	}
+	s.db.ExecContext(ctx, `UPDATE fact_summaries SET created_at = ?`, time.Now().UTC().Add(-48*time.Hour))
+
+	if _, err := s.UndoClusterSummary(ctx, 3, 24*time.Hour); !errors.Is(err, ErrUndoWindowExpired) {
+		t.Fatalf("expected ErrUndoWindowExpired, got %v", err)
+	}
+	if f, _ := s.GetFact(ctx, a); f.SupersededBy == nil {
+		t.Fatal("an expired undo must not touch the original")
+	}
+}
+
+func TestMigrateSummarizesEdgeType_RebuildsLegacyTable(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "x", SourceFile: "x.md"})
+	f1, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "a", Predicate: "p", Object: "1", FactType: "kv"})
+	f2, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "b", Predicate: "p", Object: "2", FactType: "kv"})
+
+	// Recreate the pre-'summarizes' table with one edge in it.
+	for _, stmt := range []string{
+		`DROP TABLE fact_edges_v1`,
+		`CREATE TABLE fact_edges_v1 (
+			id INTEGER PRIMARY KEY,
+			source_fact_id INTEGER NOT NULL,
+			target_fact_id INTEGER NOT NULL,
+			edge_type TEXT NOT NULL CHECK (edge_type IN ('supports','contradicts','relates_to','supersedes','derived_from')),
+			confidence REAL DEFAULT 1.0,
+			source TEXT NOT NULL DEFAULT 'explicit',
+			agent_id TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(source_fact_id, target_fact_id, edge_type)
+		)`,
+	} {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("legacy schema: %v", err)
+		}
+	}
+	if err := s.AddEdge(ctx, &FactEdge{SourceFactID: f1, TargetFactID: f2, EdgeType: EdgeTypeSupports}); err != nil {
+		t.Fatalf("AddEdge on legacy table: %v", err)
+	}
+	if err := s.AddEdge(ctx, &FactEdge{SourceFactID: f1, TargetFactID: f2, EdgeType: EdgeTypeSummarizes}); err == nil {
+		t.Fatal("expected the legacy CHECK to reject 'summarizes'")
+	}
+
+	if err := s.migrateSummarizesEdgeType(); err != nil {
+		t.Fatalf("migrateSummarizesEdgeType: %v", err)
+	}
+	if err := s.AddEdge(ctx, &FactEdge{SourceFactID: f1, TargetFactID: f2, EdgeType: EdgeTypeSummarizes}); err != nil {
+		t.Fatalf("AddEdge after rebuild: %v", err)
+	}
+	if n, _ := s.CountEdges(ctx); n != 2 {
+		t.Fatalf("expected the existing edge kept, got %d edges", n)
+	}
+}
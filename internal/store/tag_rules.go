@@ -0,0 +1,259 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TagRulesFileName is the per-user auto-tag rules file, kept next to
+// config.yaml in the cortex config directory.
+const TagRulesFileName = "tag-rules.yaml"
+
+// tagRulesFile is the on-disk shape of tag-rules.yaml:
+//
+//	path_rules:
+//	  - glob: "~/notes/garden/**"
+//	    project: garden
+//	  - contains: allotment
+//	    project: garden
+//	content_rules:
+//	  - keywords: [tomato, compost, seedling]
+//	    min_hits: 2
+//	    project: garden
+type tagRulesFile struct {
+	PathRules []struct {
+		Glob     string `yaml:"glob"`
+		Contains string `yaml:"contains"`
+		Project  string `yaml:"project"`
+	} `yaml:"path_rules"`
+	ContentRules []struct {
+		Keywords []string `yaml:"keywords"`
+		MinHits  int      `yaml:"min_hits"`
+		Project  string   `yaml:"project"`
+	} `yaml:"content_rules"`
+}
+
+// LoadTagRules reads user auto-tag rules from path. A missing file yields
+// no rules and no error.
+func LoadTagRules(path string) ([]ProjectRule, []ContentRule, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var file tagRulesFile
+	if err := yaml.Unmarshal(b, &file); err != nil {
+		return nil, nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	var pathRules []ProjectRule
+	for i, r := range file.PathRules {
+		rule := ProjectRule{
+			Pattern: strings.TrimSpace(r.Contains),
+			Glob:    strings.TrimSpace(r.Glob),
+			Project: strings.TrimSpace(r.Project),
+			Origin:  path,
+		}
+		if rule.Project == "" {
+			return nil, nil, fmt.Errorf("parsing %s path_rules[%d]: project is required", path, i)
+		}
+		if (rule.Glob == "") == (rule.Pattern == "") {
+			return nil, nil, fmt.Errorf("parsing %s path_rules[%d]: set exactly one of glob or contains", path, i)
+		}
+		if rule.Glob != "" {
+			re, err := compileTagGlob(rule.Glob)
+			if err != nil {
+				return nil, nil, fmt.Errorf("parsing %s path_rules[%d].glob: %w", path, i, err)
+			}
+			rule.glob = re
+		}
+		pathRules = append(pathRules, rule)
+	}
+
+	var contentRules []ContentRule
+	for i, r := range file.ContentRules {
+		rule := ContentRule{MinHits: r.MinHits, Project: strings.TrimSpace(r.Project), Origin: path}
+		for _, kw := range r.Keywords {
+			if kw = strings.ToLower(strings.TrimSpace(kw)); kw != "" {
+				rule.Keywords = append(rule.Keywords, kw)
+			}
+		}
+		if rule.Project == "" {
+			return nil, nil, fmt.Errorf("parsing %s content_rules[%d]: project is required", path, i)
+		}
+		if len(rule.Keywords) == 0 {
+			return nil, nil, fmt.Errorf("parsing %s content_rules[%d]: keywords are required", path, i)
+		}
+		contentRules = append(contentRules, rule)
+	}
+	return pathRules, contentRules, nil
+}
+
+var (
+	tagRulesMu       sync.RWMutex
+	userProjectRules []ProjectRule
+	userContentRules []ContentRule
+)
+
+// SetUserTagRules installs rules loaded from tag-rules.yaml. They are
+// consulted before the built-in defaults.
+func SetUserTagRules(pathRules []ProjectRule, contentRules []ContentRule) {
+	tagRulesMu.Lock()
+	userProjectRules = append([]ProjectRule(nil), pathRules...)
+	userContentRules = append([]ContentRule(nil), contentRules...)
+	tagRulesMu.Unlock()
+}
+
+// ActiveProjectRules returns user path rules followed by DefaultProjectRules.
+func ActiveProjectRules() []ProjectRule {
+	tagRulesMu.RLock()
+	defer tagRulesMu.RUnlock()
+	return append(append([]ProjectRule(nil), userProjectRules...), DefaultProjectRules...)
+}
+
+// ActiveContentRules returns user content rules followed by DefaultContentRules.
+func ActiveContentRules() []ContentRule {
+	tagRulesMu.RLock()
+	defer tagRulesMu.RUnlock()
+	return append(append([]ContentRule(nil), userContentRules...), DefaultContentRules...)
+}
+
+// TagMatch explains which auto-tag rule assigned a project.
+type TagMatch struct {
+	Project string `json:"project"`
+	Method  string `json:"method"` // "path" or "content"
+	Rule    string `json:"rule"`
+	Origin  string `json:"origin"` // "built-in" or the rules file path
+}
+
+// MatchTagRule applies path rules, then content rules, like InferProjectFull,
+// and reports the rule that fired. Returns nil when nothing matches.
+func MatchTagRule(sourceFile, content string, pathRules []ProjectRule, contentRules []ContentRule) *TagMatch {
+	if sourceFile != "" {
+		normalized := strings.ToLower(filepath.ToSlash(sourceFile))
+		for _, rule := range pathRules {
+			if rule.matchesPath(normalized) {
+				return &TagMatch{Project: rule.Project, Method: "path", Rule: rule.Describe(), Origin: ruleOrigin(rule.Origin)}
+			}
+		}
+	}
+	if content == "" {
+		return nil
+	}
+
+	lower := strings.ToLower(content)
+	var best *TagMatch
+	bestHits := 0
+	for _, rule := range contentRules {
+		minHits := rule.MinHits
+		if minHits <= 0 {
+			minHits = 2
+		}
+		var matched []string
+		for _, kw := range rule.Keywords {
+			if strings.Contains(lower, kw) {
+				matched = append(matched, strings.TrimSpace(kw))
+			}
+		}
+		if len(matched) >= minHits && len(matched) > bestHits {
+			bestHits = len(matched)
+			best = &TagMatch{
+				Project: rule.Project,
+				Method:  "content",
+				Rule:    fmt.Sprintf("keywords %s (%d of %d required)", strings.Join(matched, ", "), len(matched), minHits),
+				Origin:  ruleOrigin(rule.Origin),
+			}
+		}
+	}
+	return best
+}
+
+// Describe renders the rule's match condition for CLI output.
+func (r ProjectRule) Describe() string {
+	if r.Glob != "" {
+		return fmt.Sprintf("glob %q", r.Glob)
+	}
+	return fmt.Sprintf("path contains %q", r.Pattern)
+}
+
+// Describe renders the rule's match condition for CLI output.
+func (r ContentRule) Describe() string {
+	minHits := r.MinHits
+	if minHits <= 0 {
+		minHits = 2
+	}
+	keywords := make([]string, len(r.Keywords))
+	for i, kw := range r.Keywords {
+		keywords[i] = strings.TrimSpace(kw)
+	}
+	return fmt.Sprintf("%d+ of keywords %s", minHits, strings.Join(keywords, ", "))
+}
+
+func (r ProjectRule) matchesPath(normalized string) bool {
+	if r.Glob == "" {
+		return strings.Contains(normalized, strings.ToLower(r.Pattern))
+	}
+	re := r.glob
+	if re == nil {
+		var err error
+		if re, err = compileTagGlob(r.Glob); err != nil {
+			return false
+		}
+	}
+	return re.MatchString(normalized)
+}
+
+func ruleOrigin(origin string) string {
+	if origin == "" {
+		return "built-in"
+	}
+	return origin
+}
+
+// compileTagGlob turns a path glob into a case-insensitive regexp over
+// slash-separated paths. "*" and "?" stay within one segment, "**" spans
+// segments, a leading "~/" is the home directory, and relative globs may
+// match at any directory boundary.
+func compileTagGlob(glob string) (*regexp.Regexp, error) {
+	g := strings.ToLower(filepath.ToSlash(strings.TrimSpace(glob)))
+	if strings.HasPrefix(g, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("expanding ~: %w", err)
+		}
+		g = strings.TrimSuffix(strings.ToLower(filepath.ToSlash(home)), "/") + g[1:]
+	}
+
+	var b strings.Builder
+	if strings.HasPrefix(g, "/") {
+		b.WriteString("^")
+	} else {
+		b.WriteString("(^|/)")
+	}
+	for i := 0; i < len(g); i++ {
+		switch c := g[i]; {
+		case c == '*' && strings.HasPrefix(g[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(g[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
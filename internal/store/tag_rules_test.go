@@ -0,0 +1,98 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTagRules(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), TagRulesFileName)
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadTagRules(t *testing.T) {
+	path := writeTagRules(t, `path_rules:
+  - glob: "**/garden/*.md"
+    project: garden
+  - contains: Allotment
+    project: garden
+content_rules:
+  - keywords: [Tomato, compost, seedling]
+    project: garden
+`)
+	pathRules, contentRules, err := LoadTagRules(path)
+	if err != nil {
+		t.Fatalf("LoadTagRules: %v", err)
+	}
+	if len(pathRules) != 2 || len(contentRules) != 1 || contentRules[0].Keywords[0] != "tomato" {
+		t.Fatalf("unexpected rules: %+v %+v", pathRules, contentRules)
+	}
+
+	for _, tt := range []struct {
+		path string
+		want string
+	}{
+		{"/home/q/notes/Garden/plan.md", "garden"},
+		{"garden/plan.md", "garden"},
+		{"/home/q/notes/garden/2026/plan.md", ""}, // * stays within one directory
+		{"/home/q/notes/mygarden/plan.md", ""},    // globs match whole segments
+		{"/home/q/allotment-log.txt", "garden"},
+	} {
+		if got := InferProject(tt.path, pathRules); got != tt.want {
+			t.Errorf("InferProject(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	if rules, _, err := LoadTagRules(filepath.Join(t.TempDir(), "missing.yaml")); err != nil || rules != nil {
+		t.Fatalf("missing file should load nothing, got %v, %v", rules, err)
+	}
+	for _, bad := range []string{
+		"path_rules:\n  - glob: x\n",
+		"path_rules:\n  - glob: x\n    contains: y\n    project: p\n",
+		"content_rules:\n  - project: p\n",
+	} {
+		if _, _, err := LoadTagRules(writeTagRules(t, bad)); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestMatchTagRule_UserRulesFirst(t *testing.T) {
+	pathRules, contentRules, err := LoadTagRules(writeTagRules(t, `path_rules:
+  - glob: "**/trading/garden/**"
+    project: garden
+content_rules:
+  - keywords: [tomato, compost]
+    project: garden
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetUserTagRules(pathRules, contentRules)
+	t.Cleanup(func() { SetUserTagRules(nil, nil) })
+
+	match := MatchTagRule("/notes/trading/garden/beds.md", "", ActiveProjectRules(), ActiveContentRules())
+	if match == nil || match.Project != "garden" || match.Method != "path" || !strings.HasSuffix(match.Origin, TagRulesFileName) {
+		t.Fatalf("user glob should beat built-in 'trading' rule, got %+v", match)
+	}
+
+	match = MatchTagRule("/notes/trading/log.md", "", ActiveProjectRules(), ActiveContentRules())
+	if match == nil || match.Project != "trading" || match.Origin != "built-in" || match.Rule != `path contains "trading"` {
+		t.Fatalf("expected built-in trading rule, got %+v", match)
+	}
+
+	match = MatchTagRule("/notes/daily.md", "Moved the tomato seedlings next to the compost bin", ActiveProjectRules(), ActiveContentRules())
+	if match == nil || match.Project != "garden" || match.Method != "content" || !strings.Contains(match.Rule, "tomato, compost") {
+		t.Fatalf("expected content match naming keywords, got %+v", match)
+	}
+
+	if MatchTagRule("/notes/daily.md", "nothing relevant", ActiveProjectRules(), ActiveContentRules()) != nil {
+		t.Fatal("expected no match")
+	}
+}
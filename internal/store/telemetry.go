@@ -0,0 +1,190 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Telemetry event kinds.
+const (
+	TelemetryKindLLM   = "llm"
+	TelemetryKindEmbed = "embed"
+)
+
+// TelemetryEvent is the usage of one provider/model by one command run:
+// how many calls it made, the tokens it used, the time spent waiting on it,
+// and the estimated cost. Commands record one row per model they touched.
+type TelemetryEvent struct {
+	ID         int64
+	Command    string // top-level command, e.g. "reason", "import", "mcp"
+	Kind       string // TelemetryKindLLM or TelemetryKindEmbed
+	Provider   string
+	Model      string
+	Mode       string // command-specific mode, e.g. reason "one-shot" / "recursive"
+	Calls      int
+	TokensIn   int
+	TokensOut  int
+	DurationMS int64
+	Errors     int // calls that failed
+	Failovers  int // calls handed to a fallback provider instead
+	Repairs    int // structured replies that parsed only after local repair
+	Reprompts  int // structured replies so broken the model was asked again
+	CostUSD    float64
+	CostKnown  bool
+	Detail     string // optional JSON with command-specific fields
+	CreatedAt  time.Time
+}
+
+func (s *SQLiteStore) migrateTelemetryTable() error {
+	done, err := s.isMetaFlagEnabled("telemetry_events_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS telemetry_events (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			command     TEXT NOT NULL DEFAULT '',
+			kind        TEXT NOT NULL CHECK(kind IN ('llm','embed')),
+			provider    TEXT NOT NULL DEFAULT '',
+			model       TEXT NOT NULL DEFAULT '',
+			mode        TEXT NOT NULL DEFAULT '',
+			calls       INTEGER NOT NULL DEFAULT 0,
+			tokens_in   INTEGER NOT NULL DEFAULT 0,
+			tokens_out  INTEGER NOT NULL DEFAULT 0,
+			duration_ms INTEGER NOT NULL DEFAULT 0,
+			cost_usd    REAL NOT NULL DEFAULT 0,
+			cost_known  INTEGER NOT NULL DEFAULT 0,
+			detail      TEXT NOT NULL DEFAULT '',
+			created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_telemetry_events_created ON telemetry_events(created_at)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating telemetry_events table: %w", err)
+		}
+	}
+
+	if _, err := s.db.Exec(`INSERT OR REPLACE INTO meta (key, value) VALUES ('telemetry_events_v1', 'true')`); err != nil {
+		return fmt.Errorf("setting telemetry_events_v1 flag: %w", err)
+	}
+	return nil
+}
+
+// migrateTelemetryFailureColumns adds per-provider failure counts to
+// telemetry_events.
+func (s *SQLiteStore) migrateTelemetryFailureColumns() error {
+	return s.addTelemetryCountColumns("errors", "failovers")
+}
+
+// migrateTelemetryRepairColumns adds per-model counts of structured replies
+// that needed repair or a second request to telemetry_events.
+func (s *SQLiteStore) migrateTelemetryRepairColumns() error {
+	return s.addTelemetryCountColumns("repairs", "reprompts")
+}
+
+func (s *SQLiteStore) addTelemetryCountColumns(cols ...string) error {
+	for _, col := range cols {
+		var count int
+		if err := s.db.QueryRow(
+			"SELECT COUNT(*) FROM pragma_table_info('telemetry_events') WHERE name=?", col,
+		).Scan(&count); err != nil {
+			return fmt.Errorf("checking for telemetry_events.%s column: %w", col, err)
+		}
+		if count > 0 {
+			continue
+		}
+		if _, err := s.db.Exec(`ALTER TABLE telemetry_events ADD COLUMN ` + col + ` INTEGER NOT NULL DEFAULT 0`); err != nil && !isDuplicateColumnError(err) {
+			return fmt.Errorf("adding telemetry_events.%s column: %w", col, err)
+		}
+	}
+	return nil
+}
+
+// RecordTelemetryEvents appends events in one transaction. Events without a
+// CreatedAt are stamped with the current time.
+func (s *SQLiteStore) RecordTelemetryEvents(ctx context.Context, events []TelemetryEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning telemetry transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	for _, e := range events {
+		kind := strings.ToLower(strings.TrimSpace(e.Kind))
+		if kind != TelemetryKindLLM && kind != TelemetryKindEmbed {
+			return fmt.Errorf("invalid telemetry kind %q (valid: llm, embed)", e.Kind)
+		}
+		at := e.CreatedAt
+		if at.IsZero() {
+			at = now
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO telemetry_events (command, kind, provider, model, mode, calls, tokens_in, tokens_out, duration_ms, errors, failovers, repairs, reprompts, cost_usd, cost_known, detail, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			strings.TrimSpace(e.Command), kind, strings.TrimSpace(e.Provider), strings.TrimSpace(e.Model),
+			strings.TrimSpace(e.Mode), e.Calls, e.TokensIn, e.TokensOut, e.DurationMS, e.Errors, e.Failovers,
+			e.Repairs, e.Reprompts, e.CostUSD, e.CostKnown, e.Detail, at.UTC(),
+		); err != nil {
+			return fmt.Errorf("inserting telemetry event: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListTelemetryEvents returns telemetry rows created at or after since
+// (zero value = no lower bound), oldest first.
+func (s *SQLiteStore) ListTelemetryEvents(ctx context.Context, since time.Time) ([]TelemetryEvent, error) {
+	query := `SELECT id, command, kind, provider, model, mode, calls, tokens_in, tokens_out, duration_ms, errors, failovers, repairs, reprompts, cost_usd, cost_known, detail, created_at
+	          FROM telemetry_events`
+	var args []interface{}
+	if !since.IsZero() {
+		query += " WHERE created_at >= ?"
+		args = append(args, since.UTC())
+	}
+	query += " ORDER BY created_at ASC, id ASC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing telemetry events: %w", err)
+	}
+	defer rows.Close()
+	return scanTelemetryRows(rows)
+}
+
+// TelemetryCostSince sums the estimated LLM cost recorded since t; the
+// `--llm auto` router uses it to enforce its daily budget.
+func (s *SQLiteStore) TelemetryCostSince(ctx context.Context, t time.Time) (float64, error) {
+	var cost sql.NullFloat64
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT SUM(cost_usd) FROM telemetry_events WHERE created_at >= ?`, t.UTC(),
+	).Scan(&cost); err != nil {
+		return 0, fmt.Errorf("summing telemetry cost: %w", err)
+	}
+	return cost.Float64, nil
+}
+
+func scanTelemetryRows(rows *sql.Rows) ([]TelemetryEvent, error) {
+	var events []TelemetryEvent
+	for rows.Next() {
+		var e TelemetryEvent
+		if err := rows.Scan(&e.ID, &e.Command, &e.Kind, &e.Provider, &e.Model, &e.Mode, &e.Calls,
+			&e.TokensIn, &e.TokensOut, &e.DurationMS, &e.Errors, &e.Failovers, &e.Repairs, &e.Reprompts, &e.CostUSD, &e.CostKnown, &e.Detail, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning telemetry row: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
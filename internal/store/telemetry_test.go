@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTelemetryEvents_RecordAndList(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t).(*SQLiteStore)
+
+	if err := s.migrateTelemetryTable(); err != nil {
+		t.Fatalf("second migrateTelemetryTable call failed: %v", err)
+	}
+
+	old := time.Now().UTC().Add(-10 * 24 * time.Hour)
+	err := s.RecordTelemetryEvents(ctx, []TelemetryEvent{
+		{Command: "reason", Kind: "llm", Provider: "openrouter", Model: "m1", Mode: "recursive", Calls: 3, TokensIn: 900, TokensOut: 120, DurationMS: 4200, CostUSD: 0.002, CostKnown: true, Detail: `{"query":"q"}`},
+		{Command: "import", Kind: "embed", Provider: "ollama", Model: "nomic-embed-text", Calls: 2, DurationMS: 80},
+		{Command: "search", Kind: "embed", Provider: "ollama", Model: "nomic-embed-text", Calls: 1, DurationMS: 10, CreatedAt: old},
+	})
+	if err != nil {
+		t.Fatalf("RecordTelemetryEvents: %v", err)
+	}
+
+	all, err := s.ListTelemetryEvents(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("ListTelemetryEvents: %v", err)
+	}
+	if len(all) != 3 || all[0].Command != "search" {
+		t.Fatalf("expected 3 events oldest first, got %+v", all)
+	}
+
+	recent, err := s.ListTelemetryEvents(ctx, time.Now().Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatalf("ListTelemetryEvents since: %v", err)
+	}
+	if len(recent) != 2 {
+		t.Fatalf("expected 2 recent events, got %d", len(recent))
+	}
+	r := recent[0]
+	if r.Mode != "recursive" || r.Calls != 3 || r.TokensIn != 900 || !r.CostKnown || r.Detail != `{"query":"q"}` {
+		t.Fatalf("reason event round-trip mismatch: %+v", r)
+	}
+
+	if err := s.RecordTelemetryEvents(ctx, []TelemetryEvent{{Kind: "bogus"}}); err == nil {
+		t.Fatal("expected error for invalid kind")
+	}
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/timings"
+)
+
+// timedDriverName is the database/sql driver used when `--timings` is on. It
+// wraps the sqlite driver and charges every Exec, Query, and row fetch to
+// the timings.DB bucket. The plain "sqlite" driver is used otherwise, so the
+// wrapper costs nothing by default.
+const timedDriverName = "sqlite-timed"
+
+var registerTimedDriver sync.Once
+
+// sqlDriverName returns the driver NewStore should open.
+func sqlDriverName() string {
+	if !timings.DBEnabled() {
+		return "sqlite"
+	}
+	registerTimedDriver.Do(func() {
+		// sql.Open does not connect; it only resolves the registered driver.
+		db, err := sql.Open("sqlite", "")
+		if err != nil {
+			return
+		}
+		sql.Register(timedDriverName, timedDriver{inner: db.Driver()})
+		db.Close()
+	})
+	for _, name := range sql.Drivers() {
+		if name == timedDriverName {
+			return timedDriverName
+		}
+	}
+	return "sqlite"
+}
+
+type timedDriver struct{ inner driver.Driver }
+
+func (d timedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.inner.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &timedConn{Conn: c}, nil
+}
+
+// timedConn forwards every optional interface database/sql probes for, so
+// the wrapped connection behaves exactly like the sqlite one.
+type timedConn struct{ driver.Conn }
+
+func (c *timedConn) Prepare(query string) (driver.Stmt, error) {
+	st, err := c.Conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &timedStmt{Stmt: st}, nil
+}
+
+func (c *timedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	pc, ok := c.Conn.(driver.ConnPrepareContext)
+	if !ok {
+		return c.Prepare(query)
+	}
+	st, err := pc.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &timedStmt{Stmt: st}, nil
+}
+
+func (c *timedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if bt, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return bt.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() //nolint:staticcheck // fallback for drivers without BeginTx
+}
+
+func (c *timedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	defer timings.Start(timings.DB)()
+	return ec.ExecContext(ctx, query, args)
+}
+
+func (c *timedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := qc.QueryContext(ctx, query, args)
+	if err != nil {
+		timings.Add(timings.DB, time.Since(start))
+		return nil, err
+	}
+	return &timedRows{Rows: rows, elapsed: time.Since(start)}, nil
+}
+
+func (c *timedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *timedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *timedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+type timedStmt struct{ driver.Stmt }
+
+func (s *timedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	defer timings.Start(timings.DB)()
+	if ec, ok := s.Stmt.(driver.StmtExecContext); ok {
+		return ec.ExecContext(ctx, args)
+	}
+	return s.Stmt.Exec(namedToValues(args)) //nolint:staticcheck // fallback
+}
+
+func (s *timedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if qc, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = qc.QueryContext(ctx, args)
+	} else {
+		rows, err = s.Stmt.Query(namedToValues(args)) //nolint:staticcheck // fallback
+	}
+	if err != nil {
+		timings.Add(timings.DB, time.Since(start))
+		return nil, err
+	}
+	return &timedRows{Rows: rows, elapsed: time.Since(start)}, nil
+}
+
+func namedToValues(args []driver.NamedValue) []driver.Value {
+	out := make([]driver.Value, len(args))
+	for i, a := range args {
+		out[i] = a.Value
+	}
+	return out
+}
+
+// timedRows accumulates the query plus every row fetch and records one DB
+// call when the rows are closed, so a query counts once however many rows
+// it returns.
+type timedRows struct {
+	driver.Rows
+	elapsed time.Duration
+	closed  bool
+}
+
+func (r *timedRows) Next(dest []driver.Value) error {
+	start := time.Now()
+	err := r.Rows.Next(dest)
+	r.elapsed += time.Since(start)
+	return err
+}
+
+func (r *timedRows) Close() error {
+	if !r.closed {
+		r.closed = true
+		timings.Add(timings.DB, r.elapsed)
+	}
+	return r.Rows.Close()
+}
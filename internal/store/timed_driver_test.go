@@ -0,0 +1,36 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/hurttlocker/cortex/internal/timings"
+)
+
+func TestTimedDriverRecordsDBCalls(t *testing.T) {
+	timings.EnableDB()
+	t.Cleanup(timings.Disable)
+
+	s, err := NewStore(StoreConfig{DBPath: filepath.Join(t.TempDir(), "timed.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	if _, err := s.AddMemory(ctx, &Memory{Content: "timed driver check", SourceFile: "t.md"}); err != nil {
+		t.Fatalf("AddMemory: %v", err)
+	}
+	before := timings.Snapshot().DBCalls
+	results, err := s.SearchFTS(ctx, "timed", 5)
+	if err != nil {
+		t.Fatalf("SearchFTS: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("SearchFTS returned %d results, want 1", len(results))
+	}
+	if after := timings.Snapshot().DBCalls; after <= before {
+		t.Fatalf("DB calls did not increase: before=%d after=%d", before, after)
+	}
+}
@@ -0,0 +1,278 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Translations (cortex translate) are stored next to their originals
+// rather than replacing them:
+//
+//   - a translated memory is a new memory with the original's source,
+//     project, and class;
+//   - a translated fact is a new fact with a 'translation_of' edge to its
+//     original, and the pair is recorded as a conflict exception so the
+//     two wordings are never reported as contradicting each other;
+//   - a row in translations links each one to its original and language.
+//     A row without a translation marks an original that is already in
+//     that language, so later runs don't ask the LLM again.
+//
+// Search folds translation hits back onto their originals.
+
+// Translation kinds.
+const (
+	TranslationKindMemory = "memory"
+	TranslationKindFact   = "fact"
+)
+
+// Translation links an original memory or fact to its translation into
+// Lang.
+type Translation struct {
+	Kind          string    `json:"kind"`
+	OriginalID    int64     `json:"original_id"`
+	TranslationID int64     `json:"translation_id,omitempty"` // 0 when the original is already in Lang
+	Lang          string    `json:"lang"`
+	SourceLang    string    `json:"source_lang,omitempty"`
+	Model         string    `json:"model,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NormalizeLang lowercases a language code and trims region suffixes
+// ("EN-us" → "en").
+func NormalizeLang(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// migrateTranslationsTable creates the translations table.
+func (s *SQLiteStore) migrateTranslationsTable() error {
+	done, err := s.isMetaFlagEnabled("translations_v1")
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS translations (
+			kind           TEXT NOT NULL CHECK (kind IN ('memory','fact')),
+			original_id    INTEGER NOT NULL,
+			lang           TEXT NOT NULL,
+			translation_id INTEGER,
+			source_lang    TEXT NOT NULL DEFAULT '',
+			model          TEXT NOT NULL DEFAULT '',
+			created_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (kind, original_id, lang)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_translations_translation ON translations(kind, translation_id)`,
+		`INSERT OR REPLACE INTO meta (key, value) VALUES ('translations_v1', 'true')`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("creating translations table: %w", err)
+		}
+	}
+	return nil
+}
+
+// AddMemoryTranslation stores content as the lang translation of memory
+// originalID and returns the new memory's ID.
+func (s *SQLiteStore) AddMemoryTranslation(ctx context.Context, originalID int64, lang, sourceLang, model, content string) (int64, error) {
+	lang = NormalizeLang(lang)
+	if lang == "" {
+		return 0, fmt.Errorf("translation language is required")
+	}
+	original, err := s.GetMemory(ctx, originalID)
+	if err != nil {
+		return 0, err
+	}
+	if original == nil {
+		return 0, fmt.Errorf("memory %d not found", originalID)
+	}
+	id, err := s.AddMemory(ctx, &Memory{
+		Content:       strings.TrimSpace(content),
+		SourceFile:    original.SourceFile,
+		SourceLine:    original.SourceLine,
+		SourceSection: original.SourceSection,
+		Project:       original.Project,
+		MemoryClass:   original.MemoryClass,
+		Metadata:      original.Metadata,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("storing translation of memory %d: %w", originalID, err)
+	}
+	if err := s.recordTranslation(ctx, TranslationKindMemory, originalID, id, lang, sourceLang, model); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// AddFactTranslation stores translated as the lang translation of fact
+// originalID: type, confidence, and decay are copied from the original,
+// and the memory defaults to the original's. Returns the new fact's ID.
+func (s *SQLiteStore) AddFactTranslation(ctx context.Context, originalID int64, translated *Fact, lang, sourceLang, model string) (int64, error) {
+	lang = NormalizeLang(lang)
+	if lang == "" {
+		return 0, fmt.Errorf("translation language is required")
+	}
+	original, err := s.GetFact(ctx, originalID)
+	if err != nil {
+		return 0, err
+	}
+	if original == nil {
+		return 0, fmt.Errorf("fact %d not found", originalID)
+	}
+	f := &Fact{
+		MemoryID:    translated.MemoryID,
+		Subject:     strings.TrimSpace(translated.Subject),
+		Predicate:   strings.TrimSpace(translated.Predicate),
+		Object:      strings.TrimSpace(translated.Object),
+		FactType:    original.FactType,
+		Confidence:  original.Confidence,
+		DecayRate:   original.DecayRate,
+		SourceQuote: original.SourceQuote,
+		AgentID:     original.AgentID,
+		SessionID:   original.SessionID,
+		ProjectID:   original.ProjectID,
+	}
+	if f.MemoryID == 0 {
+		f.MemoryID = original.MemoryID
+	}
+	if f.Predicate == "" || f.Object == "" {
+		return 0, fmt.Errorf("translation of fact %d needs a predicate and object", originalID)
+	}
+	id, err := s.AddFact(ctx, f)
+	if err != nil {
+		return 0, fmt.Errorf("storing translation of fact %d: %w", originalID, err)
+	}
+	if err := s.AddEdge(ctx, &FactEdge{
+		SourceFactID: id,
+		TargetFactID: originalID,
+		EdgeType:     EdgeTypeTranslationOf,
+		Source:       EdgeSourceInferred,
+	}); err != nil && err != ErrEdgeExists {
+		return 0, err
+	}
+	if _, err := s.IgnoreConflict(ctx, id, originalID, "translation ("+lang+")"); err != nil {
+		return 0, err
+	}
+	if err := s.recordTranslation(ctx, TranslationKindFact, originalID, id, lang, sourceLang, model); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// MarkInLanguage records that an original memory or fact is already in
+// lang and needs no translation.
+func (s *SQLiteStore) MarkInLanguage(ctx context.Context, kind string, originalID int64, lang, model string) error {
+	lang = NormalizeLang(lang)
+	return s.recordTranslation(ctx, kind, originalID, 0, lang, lang, model)
+}
+
+func (s *SQLiteStore) recordTranslation(ctx context.Context, kind string, originalID, translationID int64, lang, sourceLang, model string) error {
+	var tid interface{}
+	if translationID > 0 {
+		tid = translationID
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO translations (kind, original_id, lang, translation_id, source_lang, model, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(kind, original_id, lang) DO UPDATE SET
+			translation_id = excluded.translation_id,
+			source_lang = excluded.source_lang,
+			model = excluded.model,
+			created_at = excluded.created_at`,
+		kind, originalID, lang, tid, NormalizeLang(sourceLang), model, time.Now().UTC(),
+	); err != nil {
+		return fmt.Errorf("recording %s translation: %w", kind, err)
+	}
+	return nil
+}
+
+// TranslatedOriginals returns the originals of kind already handled for
+// lang: translated, or recorded as being in lang already.
+func (s *SQLiteStore) TranslatedOriginals(ctx context.Context, kind, lang string) (map[int64]bool, error) {
+	return s.translationIDSet(ctx,
+		`SELECT original_id FROM translations WHERE kind = ? AND lang = ?`, kind, NormalizeLang(lang))
+}
+
+// TranslationIDs returns the IDs of every stored translation of kind, so
+// they are not themselves translated again.
+func (s *SQLiteStore) TranslationIDs(ctx context.Context, kind string) (map[int64]bool, error) {
+	return s.translationIDSet(ctx,
+		`SELECT translation_id FROM translations WHERE kind = ? AND translation_id IS NOT NULL`, kind)
+}
+
+func (s *SQLiteStore) translationIDSet(ctx context.Context, query string, args ...interface{}) (map[int64]bool, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing translations: %w", err)
+	}
+	defer rows.Close()
+	out := map[int64]bool{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning translation: %w", err)
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+// TranslationFor returns the lang translation of an original memory or
+// fact, or nil if there is none.
+func (s *SQLiteStore) TranslationFor(ctx context.Context, kind string, originalID int64, lang string) (*Translation, error) {
+	t := &Translation{}
+	var tid sql.NullInt64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT kind, original_id, lang, translation_id, source_lang, model, created_at
+		 FROM translations WHERE kind = ? AND original_id = ? AND lang = ?`,
+		kind, originalID, NormalizeLang(lang),
+	).Scan(&t.Kind, &t.OriginalID, &t.Lang, &tid, &t.SourceLang, &t.Model, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting translation: %w", err)
+	}
+	t.TranslationID = tid.Int64
+	return t, nil
+}
+
+// TranslationOriginals maps each of ids that is a stored translation of
+// kind to its translation record.
+func (s *SQLiteStore) TranslationOriginals(ctx context.Context, kind string, ids []int64) (map[int64]Translation, error) {
+	out := map[int64]Translation{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, 0, len(ids)+1)
+	args = append(args, kind)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT kind, original_id, lang, translation_id, source_lang, model, created_at
+		 FROM translations WHERE kind = ? AND translation_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("looking up translation originals: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t Translation
+		if err := rows.Scan(&t.Kind, &t.OriginalID, &t.Lang, &t.TranslationID, &t.SourceLang, &t.Model, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning translation: %w", err)
+		}
+		out[t.TranslationID] = t
+	}
+	return out, rows.Err()
+}
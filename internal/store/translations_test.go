@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTranslations_LinkOriginals(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "Alice wohnt in Berlin.", SourceFile: "notes/de.md", Project: "home"})
+	factID, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "Alice", Predicate: "wohnt_in", Object: "Berlin", FactType: "location", Confidence: 0.9})
+
+	trMemID, err := s.AddMemoryTranslation(ctx, memID, "EN-us", "de", "test/model", "Alice lives in Berlin.")
+	if err != nil {
+		t.Fatalf("AddMemoryTranslation: %v", err)
+	}
+	tr, _ := s.GetMemory(ctx, trMemID)
+	if tr.SourceFile != "notes/de.md" || tr.Project != "home" {
+		t.Fatalf("translation should keep the original's source and project, got %+v", tr)
+	}
+
+	trFactID, err := s.AddFactTranslation(ctx, factID, &Fact{MemoryID: trMemID, Subject: "Alice", Predicate: "lives_in", Object: "Berlin"}, "en", "de", "test/model")
+	if err != nil {
+		t.Fatalf("AddFactTranslation: %v", err)
+	}
+	f, _ := s.GetFact(ctx, trFactID)
+	if f.FactType != "location" || f.Confidence != 0.9 || f.MemoryID != trMemID {
+		t.Fatalf("translated fact should copy type and confidence, got %+v", f)
+	}
+
+	edges, err := s.GetEdgesForFact(ctx, trFactID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 1 || edges[0].EdgeType != EdgeTypeTranslationOf || edges[0].TargetFactID != factID {
+		t.Fatalf("expected a translation_of edge to the original, got %+v", edges)
+	}
+	exceptions, _ := s.ListConflictExceptions(ctx)
+	if len(exceptions) != 1 {
+		t.Fatalf("the pair should never be reported as a conflict, got %+v", exceptions)
+	}
+
+	done, _ := s.TranslatedOriginals(ctx, TranslationKindMemory, "en")
+	if !done[memID] || len(done) != 1 {
+		t.Fatalf("expected memory %d handled for en, got %v", memID, done)
+	}
+	ids, _ := s.TranslationIDs(ctx, TranslationKindFact)
+	if !ids[trFactID] || ids[factID] {
+		t.Fatalf("expected only the translated fact, got %v", ids)
+	}
+	originals, err := s.TranslationOriginals(ctx, TranslationKindMemory, []int64{memID, trMemID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(originals) != 1 || originals[trMemID].OriginalID != memID || originals[trMemID].SourceLang != "de" {
+		t.Fatalf("unexpected originals: %+v", originals)
+	}
+}
+
+func TestTranslations_MarkInLanguage(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "Already English.", SourceFile: "en.md"})
+	if err := s.MarkInLanguage(ctx, TranslationKindMemory, memID, "en", "test/model"); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := s.TranslationFor(ctx, TranslationKindMemory, memID, "en")
+	if err != nil || tr == nil {
+		t.Fatalf("TranslationFor: %v, %v", tr, err)
+	}
+	if tr.TranslationID != 0 || tr.SourceLang != "en" {
+		t.Fatalf("expected an in-language marker, got %+v", tr)
+	}
+	if ids, _ := s.TranslationIDs(ctx, TranslationKindMemory); len(ids) != 0 {
+		t.Fatalf("a marker is not a translation, got %v", ids)
+	}
+	if tr, _ := s.TranslationFor(ctx, TranslationKindMemory, memID, "fr"); tr != nil {
+		t.Fatalf("expected no fr translation, got %+v", tr)
+	}
+}
+
+func TestWidenEdgeTypeCheck_AcceptsTranslationOf(t *testing.T) {
+	s := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	memID, _ := s.AddMemory(ctx, &Memory{Content: "x", SourceFile: "x.md"})
+	f1, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "a", Predicate: "p", Object: "1", FactType: "kv"})
+	f2, _ := s.AddFact(ctx, &Fact{MemoryID: memID, Subject: "a", Predicate: "q", Object: "1", FactType: "kv"})
+
+	// The table as migrateSummarizesEdgeType used to leave it.
+	for _, stmt := range []string{
+		`DROP TABLE fact_edges_v1`,
+		`CREATE TABLE fact_edges_v1 (
+			id INTEGER PRIMARY KEY,
+			source_fact_id INTEGER NOT NULL,
+			target_fact_id INTEGER NOT NULL,
+			edge_type TEXT NOT NULL CHECK (edge_type IN ('supports','contradicts','relates_to','supersedes','derived_from','summarizes')),
+			confidence REAL DEFAULT 1.0,
+			source TEXT NOT NULL DEFAULT 'explicit',
+			agent_id TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(source_fact_id, target_fact_id, edge_type)
+		)`,
+	} {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("legacy schema: %v", err)
+		}
+	}
+	if err := s.AddEdge(ctx, &FactEdge{SourceFactID: f1, TargetFactID: f2, EdgeType: EdgeTypeTranslationOf}); err == nil {
+		t.Fatal("expected the legacy CHECK to reject 'translation_of'")
+	}
+	if err := s.widenEdgeTypeCheck(EdgeTypeTranslationOf); err != nil {
+		t.Fatalf("widenEdgeTypeCheck: %v", err)
+	}
+	if err := s.AddEdge(ctx, &FactEdge{SourceFactID: f1, TargetFactID: f2, EdgeType: EdgeTypeTranslationOf}); err != nil {
+		t.Fatalf("AddEdge after rebuild: %v", err)
+	}
+	if err := s.AddEdge(ctx, &FactEdge{SourceFactID: f2, TargetFactID: f1, EdgeType: EdgeTypeSummarizes}); err != nil {
+		t.Fatalf("existing types must still be accepted: %v", err)
+	}
+}
@@ -104,22 +104,30 @@ func (w *WebhookNotifier) Flush() {
 	w.flush()
 }
 
+// FlushWait sends all pending alerts and blocks until delivery finishes.
+// Short-lived commands call it before exiting so batched alerts are not lost.
+func (w *WebhookNotifier) FlushWait() {
+	if batch := w.takePending(); len(batch) > 0 {
+		w.sendBatch(batch)
+	}
+}
+
 func (w *WebhookNotifier) flush() {
-	w.mu.Lock()
-	if len(w.pending) == 0 {
-		w.mu.Unlock()
-		return
+	if batch := w.takePending(); len(batch) > 0 {
+		go w.sendBatch(batch)
 	}
+}
+
+func (w *WebhookNotifier) takePending() []WebhookPayload {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	batch := w.pending
 	w.pending = nil
 	if w.timer != nil {
 		w.timer.Stop()
 		w.timer = nil
 	}
-	w.mu.Unlock()
-
-	// Send batch
-	go w.sendBatch(batch)
+	return batch
 }
 
 func (w *WebhookNotifier) sendBatch(payloads []WebhookPayload) {
@@ -0,0 +1,325 @@
+// Package timings accumulates process-wide time and token counters for the
+// `--timings` global flag: how long a command spent in the database, in
+// embedding calls, and in LLM calls, and how many tokens each model used.
+//
+// Recording is a no-op until Enable is called, so instrumented hot paths
+// (every SQL statement, every embedding) cost one atomic load by default.
+package timings
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Category is a bucket of time spent outside the command's own CPU work.
+type Category string
+
+const (
+	DB    Category = "db"
+	Embed Category = "embed"
+	LLM   Category = "llm"
+)
+
+var (
+	enabled   atomic.Bool
+	dbEnabled atomic.Bool
+	started   time.Time
+
+	mu     sync.Mutex
+	totals = map[Category]*bucket{}
+	models = map[string]*ModelUsage{}
+)
+
+type bucket struct {
+	calls int
+	dur   time.Duration
+}
+
+// ModelUsage is the usage of one provider/model in one category.
+type ModelUsage struct {
+	Kind       Category `json:"kind"`
+	Provider   string   `json:"provider"`
+	Model      string   `json:"model"`
+	Calls      int      `json:"calls"`
+	TokensIn   int      `json:"tokens_in"`
+	TokensOut  int      `json:"tokens_out"`
+	DurationMS int64    `json:"duration_ms"`
+	Errors     int      `json:"errors,omitempty"`    // calls that returned an error
+	Failovers  int      `json:"failovers,omitempty"` // failed or skipped calls handed to a fallback provider
+	// Repairs counts structured (JSON) replies that parsed only after local
+	// repair; Reprompts counts replies so broken the model was asked again.
+	Repairs   int `json:"repairs,omitempty"`
+	Reprompts int `json:"reprompts,omitempty"`
+	// CacheHits counts calls served from a provider-side context cache
+	// created by an earlier call; CachedTokens is the part of TokensIn read
+	// from a cache (billed at a discount).
+	CacheHits    int     `json:"cache_hits,omitempty"`
+	CachedTokens int     `json:"cached_tokens,omitempty"`
+	Routes       []Route `json:"routes,omitempty"`
+}
+
+// Route counts how often a routing provider picked this model for one task
+// and reason (see llm.Router).
+type Route struct {
+	Task   string `json:"task"`
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// Enable turns recording on and starts the wall clock.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	started = time.Now()
+	totals = map[Category]*bucket{}
+	models = map[string]*ModelUsage{}
+	enabled.Store(true)
+}
+
+// EnableDB turns recording on including per-statement database timing,
+// which the store only wires in when asked (it wraps the SQL driver).
+func EnableDB() {
+	Enable()
+	dbEnabled.Store(true)
+}
+
+// Disable turns recording off (used by tests).
+func Disable() {
+	enabled.Store(false)
+	dbEnabled.Store(false)
+}
+
+// Enabled reports whether recording is on.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// DBEnabled reports whether database timing was requested.
+func DBEnabled() bool {
+	return dbEnabled.Load()
+}
+
+// Start begins timing one call in cat; call the returned func when it ends.
+//
+//	defer timings.Start(timings.Embed)()
+func Start(cat Category) func() {
+	if !enabled.Load() {
+		return func() {}
+	}
+	t0 := time.Now()
+	return func() { Add(cat, time.Since(t0)) }
+}
+
+// Add records one call of duration d in cat.
+func Add(cat Category, d time.Duration) {
+	if !enabled.Load() {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	b := totals[cat]
+	if b == nil {
+		b = &bucket{}
+		totals[cat] = b
+	}
+	b.calls++
+	b.dur += d
+}
+
+// Call is one in-flight embedding or LLM request, attributed to a
+// provider/model. A nil *Call (returned while recording is off) is valid and
+// does nothing.
+type Call struct {
+	cat             Category
+	provider, model string
+	start           time.Time
+	tokensIn        int
+	tokensOut       int
+	failed          bool
+	cacheHit        bool
+	cachedTokens    int
+}
+
+// Begin starts timing one request to provider/model in cat:
+//
+//	call := timings.Begin(timings.LLM, "openrouter", model)
+//	defer call.End()
+//	...
+//	call.Tokens(usage.PromptTokens, usage.CompletionTokens)
+func Begin(cat Category, provider, model string) *Call {
+	if !enabled.Load() {
+		return nil
+	}
+	return &Call{cat: cat, provider: provider, model: model, start: time.Now()}
+}
+
+// Tokens sets the call's token usage. Either count may be zero when the
+// provider does not report it.
+func (c *Call) Tokens(tokensIn, tokensOut int) {
+	if c == nil {
+		return
+	}
+	c.tokensIn, c.tokensOut = tokensIn, tokensOut
+}
+
+// Cache records provider-side context cache use: hit when the call reused a
+// cache created earlier, cachedTokens the input tokens read from it.
+func (c *Call) Cache(hit bool, cachedTokens int) {
+	if c == nil {
+		return
+	}
+	c.cacheHit = hit
+	c.cachedTokens = cachedTokens
+}
+
+// Fail marks the call as failed; End then counts it as an error against its
+// model. Passing a nil error is a no-op, so callers can write
+//
+//	defer func() { call.Fail(err); call.End() }()
+func (c *Call) Fail(err error) {
+	if c == nil || err == nil {
+		return
+	}
+	c.failed = true
+}
+
+// RecordFailover notes that a call meant for provider/model in cat went to
+// the next provider in a fallback chain instead, because it failed or its
+// circuit breaker was open.
+func RecordFailover(cat Category, provider, model string) {
+	if !enabled.Load() {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	usageLocked(cat, provider, model).Failovers++
+}
+
+// RecordRepair notes that a structured reply from provider/model in cat
+// needed local repair (fences, trailing commas) before it parsed.
+func RecordRepair(cat Category, provider, model string) {
+	if !enabled.Load() {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	usageLocked(cat, provider, model).Repairs++
+}
+
+// RecordReprompt notes that a structured reply from provider/model in cat
+// could not be used and the model was asked again.
+func RecordReprompt(cat Category, provider, model string) {
+	if !enabled.Load() {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	usageLocked(cat, provider, model).Reprompts++
+}
+
+// RecordRoute notes that a router sent a task to provider/model for reason.
+// The call itself is recorded separately by Begin/End.
+func RecordRoute(provider, model, task, reason string) {
+	if !enabled.Load() {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	u := usageLocked(LLM, provider, model)
+	for i := range u.Routes {
+		if u.Routes[i].Task == task && u.Routes[i].Reason == reason {
+			u.Routes[i].Count++
+			return
+		}
+	}
+	u.Routes = append(u.Routes, Route{Task: task, Reason: reason, Count: 1})
+}
+
+func usageLocked(cat Category, provider, model string) *ModelUsage {
+	key := string(cat) + "|" + provider + "/" + model
+	u := models[key]
+	if u == nil {
+		u = &ModelUsage{Kind: cat, Provider: provider, Model: model}
+		models[key] = u
+	}
+	return u
+}
+
+// End records the call's duration in its category and against its model.
+func (c *Call) End() {
+	if c == nil {
+		return
+	}
+	d := time.Since(c.start)
+	Add(c.cat, d)
+
+	mu.Lock()
+	defer mu.Unlock()
+	u := usageLocked(c.cat, c.provider, c.model)
+	u.Calls++
+	if c.failed {
+		u.Errors++
+	}
+	if c.cacheHit {
+		u.CacheHits++
+	}
+	u.CachedTokens += c.cachedTokens
+	u.TokensIn += c.tokensIn
+	u.TokensOut += c.tokensOut
+	u.DurationMS += d.Milliseconds()
+}
+
+// Summary is a snapshot of everything recorded since Enable.
+type Summary struct {
+	WallMS     int64        `json:"wall_ms"`
+	DBMS       int64        `json:"db_ms"`
+	DBCalls    int          `json:"db_calls"`
+	EmbedMS    int64        `json:"embed_ms"`
+	EmbedCalls int          `json:"embed_calls"`
+	LLMMS      int64        `json:"llm_ms"`
+	LLMCalls   int          `json:"llm_calls"`
+	TokensIn   int          `json:"tokens_in"`
+	TokensOut  int          `json:"tokens_out"`
+	Models     []ModelUsage `json:"models,omitempty"`
+	CostUSD    float64      `json:"cost_usd"`
+	CostKnown  bool         `json:"cost_known"` // false if any model lacks pricing
+}
+
+// Snapshot returns the current totals. Cost fields are left for the caller,
+// which owns the pricing table.
+func Snapshot() Summary {
+	mu.Lock()
+	defer mu.Unlock()
+	s := Summary{}
+	if !started.IsZero() {
+		s.WallMS = time.Since(started).Milliseconds()
+	}
+	if b := totals[DB]; b != nil {
+		s.DBMS, s.DBCalls = b.dur.Milliseconds(), b.calls
+	}
+	if b := totals[Embed]; b != nil {
+		s.EmbedMS, s.EmbedCalls = b.dur.Milliseconds(), b.calls
+	}
+	if b := totals[LLM]; b != nil {
+		s.LLMMS, s.LLMCalls = b.dur.Milliseconds(), b.calls
+	}
+	for _, u := range models {
+		m := *u
+		m.Routes = append([]Route(nil), u.Routes...)
+		s.Models = append(s.Models, m)
+		s.TokensIn += u.TokensIn
+		s.TokensOut += u.TokensOut
+	}
+	sort.Slice(s.Models, func(i, j int) bool {
+		if s.Models[i].Kind != s.Models[j].Kind {
+			return s.Models[i].Kind > s.Models[j].Kind // llm before embed
+		}
+		if s.Models[i].Provider != s.Models[j].Provider {
+			return s.Models[i].Provider < s.Models[j].Provider
+		}
+		return s.Models[i].Model < s.Models[j].Model
+	})
+	return s
+}
@@ -0,0 +1,95 @@
+package timings
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDisabledRecordsNothing(t *testing.T) {
+	Enable()
+	Disable()
+	Add(DB, time.Second)
+	call := Begin(LLM, "openrouter", "m")
+	call.Tokens(10, 5)
+	call.End()
+	if s := Snapshot(); s.DBCalls != 0 || len(s.Models) != 0 {
+		t.Fatalf("disabled recorder captured data: %+v", s)
+	}
+}
+
+func TestSnapshotAggregates(t *testing.T) {
+	Enable()
+	t.Cleanup(Disable)
+
+	Add(DB, 2*time.Millisecond)
+	Add(DB, 3*time.Millisecond)
+	Begin(Embed, "ollama", "nomic-embed-text").End()
+	for _, u := range []struct {
+		provider, model string
+		in, out         int
+	}{
+		{"openrouter", "b-model", 100, 20},
+		{"google", "a-model", 7, 3},
+		{"openrouter", "b-model", 50, 10},
+	} {
+		call := Begin(LLM, u.provider, u.model)
+		call.Tokens(u.in, u.out)
+		call.End()
+	}
+
+	s := Snapshot()
+	if s.DBCalls != 2 || s.DBMS != 5 {
+		t.Fatalf("db = %d calls %dms, want 2 calls 5ms", s.DBCalls, s.DBMS)
+	}
+	if s.EmbedCalls != 1 || s.LLMCalls != 3 {
+		t.Fatalf("embed/llm = %+v", s)
+	}
+	if s.TokensIn != 157 || s.TokensOut != 33 {
+		t.Fatalf("tokens = %d/%d, want 157/33", s.TokensIn, s.TokensOut)
+	}
+	if len(s.Models) != 3 || s.Models[0].Provider != "google" || s.Models[1].Calls != 2 || s.Models[2].Kind != Embed {
+		t.Fatalf("models = %+v", s.Models)
+	}
+
+	// Enable starts over.
+	Enable()
+	if s := Snapshot(); s.DBCalls != 0 || len(s.Models) != 0 {
+		t.Fatalf("Enable did not reset: %+v", s)
+	}
+}
+
+func TestFailuresAndFailovers(t *testing.T) {
+	Enable()
+	t.Cleanup(Disable)
+
+	ok := Begin(LLM, "openrouter", "m")
+	ok.Fail(nil)
+	ok.End()
+	bad := Begin(LLM, "openrouter", "m")
+	bad.Fail(errors.New("status 503"))
+	bad.End()
+	RecordFailover(LLM, "openrouter", "m")
+
+	s := Snapshot()
+	if len(s.Models) != 1 || s.Models[0].Calls != 2 || s.Models[0].Errors != 1 || s.Models[0].Failovers != 1 {
+		t.Fatalf("models = %+v", s.Models)
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	Enable()
+	t.Cleanup(Disable)
+
+	miss := Begin(LLM, "google", "gemini-2.5-flash")
+	miss.Cache(false, 1200)
+	miss.End()
+	hit := Begin(LLM, "google", "gemini-2.5-flash")
+	hit.Cache(true, 1200)
+	hit.End()
+
+	s := Snapshot()
+	if len(s.Models) != 1 || s.Models[0].CacheHits != 1 || s.Models[0].CachedTokens != 2400 {
+		t.Fatalf("models = %+v", s.Models)
+	}
+}
@@ -0,0 +1,208 @@
+// Package tokens approximates how many tokens a model's tokenizer produces
+// for a piece of text, without shipping the tokenizers themselves.
+//
+// A chars/4 estimate is close for English prose but badly undercounts code
+// punctuation and CJK text, where one character is often a whole token. The
+// approximation here mirrors how BPE tokenizers pre-split text: words,
+// digit groups, punctuation runs, and whitespace are counted separately, and
+// CJK, other non-Latin letters, and symbols are charged per rune. Parameters
+// are tuned per model family (cl100k, o200k, Claude, Gemini, Llama) and
+// round up, so budgets enforced with Count err on the safe side.
+package tokens
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Family is a group of models that share a tokenizer.
+type Family string
+
+const (
+	CL100K Family = "cl100k" // GPT-4, GPT-3.5, OpenAI embeddings
+	O200K  Family = "o200k"  // GPT-4o, GPT-4.1, GPT-5, o-series
+	Claude Family = "claude"
+	Gemini Family = "gemini"
+	Llama  Family = "llama"
+)
+
+// Default is the family used when the model is unknown.
+const Default = CL100K
+
+// Families lists every supported family.
+var Families = []Family{CL100K, O200K, Claude, Gemini, Llama}
+
+type params struct {
+	wordSpan    int     // ASCII letters per extra token in a word
+	digitSpan   int     // digits per token
+	cjk         float64 // tokens per CJK rune
+	otherLetter float64 // tokens per non-ASCII, non-CJK letter (accented Latin, Cyrillic, ...)
+	symbol      float64 // tokens per emoji or other symbol rune
+}
+
+var familyParams = map[Family]params{
+	CL100K: {wordSpan: 8, digitSpan: 3, cjk: 1.2, otherLetter: 0.45, symbol: 2},
+	O200K:  {wordSpan: 9, digitSpan: 3, cjk: 0.8, otherLetter: 0.3, symbol: 1.5},
+	Claude: {wordSpan: 7, digitSpan: 3, cjk: 1.3, otherLetter: 0.45, symbol: 2},
+	Gemini: {wordSpan: 8, digitSpan: 1, cjk: 0.7, otherLetter: 0.3, symbol: 1},
+	Llama:  {wordSpan: 8, digitSpan: 3, cjk: 1.0, otherLetter: 0.4, symbol: 2},
+}
+
+// ParseFamily returns the family named s, or false.
+func ParseFamily(s string) (Family, bool) {
+	f := Family(strings.ToLower(strings.TrimSpace(s)))
+	_, ok := familyParams[f]
+	return f, ok
+}
+
+// ForModel returns the tokenizer family for a model name such as
+// "openai/gpt-4o-mini", "openrouter/anthropic/claude-sonnet-4" or
+// "ollama/llama3". Unknown models get Default.
+func ForModel(model string) Family {
+	m := strings.ToLower(model)
+	if f, ok := ParseFamily(m); ok {
+		return f
+	}
+	if i := strings.LastIndex(m, "/"); i >= 0 {
+		m = m[i+1:]
+	}
+	switch {
+	case strings.Contains(m, "claude"):
+		return Claude
+	case strings.Contains(m, "gemini"), strings.Contains(m, "gemma"):
+		return Gemini
+	case strings.Contains(m, "llama"):
+		return Llama
+	case strings.HasPrefix(m, "gpt-4o"), strings.HasPrefix(m, "gpt-4.1"), strings.HasPrefix(m, "gpt-5"),
+		strings.HasPrefix(m, "o1"), strings.HasPrefix(m, "o3"), strings.HasPrefix(m, "o4"), strings.HasPrefix(m, "gpt-oss"):
+		return O200K
+	}
+	return Default
+}
+
+// Count approximates the tokens in text for the Default family.
+func Count(text string) int {
+	return Default.Count(text)
+}
+
+// CountModel approximates the tokens in text for model's family.
+func CountModel(model, text string) int {
+	return ForModel(model).Count(text)
+}
+
+// Count approximates the tokens in text for family f.
+func (f Family) Count(text string) int {
+	p, ok := familyParams[f]
+	if !ok {
+		p = familyParams[Default]
+	}
+	total := 0.0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case unicode.IsSpace(r):
+			// A single space rides with the next word; newlines and
+			// indentation runs are tokens of their own.
+			j, newline := i, false
+			for j < len(text) {
+				r, size := utf8.DecodeRuneInString(text[j:])
+				if !unicode.IsSpace(r) {
+					break
+				}
+				newline = newline || r == '\n'
+				j += size
+			}
+			if newline || j-i > 1 {
+				total++
+			}
+			i = j
+		case isCJK(r):
+			total += p.cjk
+			i += size
+		case unicode.IsLetter(r):
+			ascii, other := 0, 0
+			j := i
+			for j < len(text) {
+				r, size := utf8.DecodeRuneInString(text[j:])
+				if !(unicode.IsLetter(r) || unicode.IsMark(r)) || isCJK(r) {
+					break
+				}
+				if r < utf8.RuneSelf {
+					ascii++
+				} else {
+					other++
+				}
+				j += size
+			}
+			word := float64(other) * p.otherLetter
+			if ascii > 0 {
+				word += float64(1 + (ascii-1)/p.wordSpan)
+			}
+			total += math.Max(1, math.Ceil(word))
+			i = j
+		case unicode.IsDigit(r):
+			n, j := runOf(text, i, unicode.IsDigit)
+			total += float64((n + p.digitSpan - 1) / p.digitSpan)
+			i = j
+		case unicode.IsPunct(r) || (r < utf8.RuneSelf && unicode.IsSymbol(r)):
+			// Operator and bracket pairs like "){" or "->" usually merge.
+			n, j := runOf(text, i, func(r rune) bool {
+				return unicode.IsPunct(r) || (r < utf8.RuneSelf && unicode.IsSymbol(r))
+			})
+			total += float64((n + 1) / 2)
+			i = j
+		default:
+			total += p.symbol
+			i += size
+		}
+	}
+	return int(math.Ceil(total))
+}
+
+// Truncate returns the longest prefix of text, cut on a rune boundary, that
+// fits in maxTokens, and whether anything was cut.
+func (f Family) Truncate(text string, maxTokens int) (string, bool) {
+	if maxTokens <= 0 {
+		return "", text != ""
+	}
+	if f.Count(text) <= maxTokens {
+		return text, false
+	}
+	lo, hi := 0, len(text) // text[:lo] fits, text[:hi] does not
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		for mid > lo && !utf8.RuneStart(text[mid]) {
+			mid--
+		}
+		if mid == lo {
+			_, size := utf8.DecodeRuneInString(text[lo:])
+			if mid = lo + size; mid >= hi {
+				break
+			}
+		}
+		if f.Count(text[:mid]) <= maxTokens {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return text[:lo], true
+}
+
+func runOf(text string, i int, in func(rune) bool) (n, end int) {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !in(r) {
+			break
+		}
+		n++
+		i += size
+	}
+	return n, i
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,82 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCount_Prose(t *testing.T) {
+	// cl100k encodes this sentence as 9 tokens.
+	if got := Count("The quick brown fox jumps over the lazy dog"); got != 9 {
+		t.Errorf("prose count = %d, want 9", got)
+	}
+	if got := Count(""); got != 0 {
+		t.Errorf("empty count = %d", got)
+	}
+}
+
+func TestCount_CJKAndCodeExceedCharsOverFour(t *testing.T) {
+	cjk := strings.Repeat("数据库故障转移到副本", 20) // 200 runes, 600 bytes
+	for _, f := range Families {
+		got := f.Count(cjk)
+		if got < 100 || got > 300 {
+			t.Errorf("%s: CJK count %d outside per-rune range", f, got)
+		}
+	}
+	if chars := utf8.RuneCountInString(cjk) / 4; Count(cjk) <= chars {
+		t.Errorf("CJK count %d should exceed runes/4 (%d)", Count(cjk), chars)
+	}
+	if Gemini.Count(cjk) >= CL100K.Count(cjk) || O200K.Count(cjk) >= CL100K.Count(cjk) {
+		t.Error("larger vocabularies should encode CJK more compactly than cl100k")
+	}
+
+	code := "if (a[i] != b[j]) { return -1; }\n\tx := map[string]int{\"k\": 42}\n"
+	if got, chars := Count(code), len(code)/4; got <= chars {
+		t.Errorf("code count %d should exceed chars/4 (%d)", got, chars)
+	}
+}
+
+func TestCount_Digits(t *testing.T) {
+	if got := CL100K.Count("1234567"); got != 3 {
+		t.Errorf("cl100k digits = %d, want 3", got)
+	}
+	if got := Gemini.Count("1234567"); got != 7 {
+		t.Errorf("gemini digits = %d, want 7", got)
+	}
+}
+
+func TestForModel(t *testing.T) {
+	cases := map[string]Family{
+		"openai/gpt-4o-mini":                   O200K,
+		"openrouter/openai/gpt-5.1-codex-mini": O200K,
+		"gpt-4-turbo":                          CL100K,
+		"openrouter/anthropic/claude-sonnet-4": Claude,
+		"google/gemini-2.5-flash":              Gemini,
+		"ollama/llama3.1:8b":                   Llama,
+		"deepseek/deepseek-chat":               Default,
+		"o200k":                                O200K,
+	}
+	for model, want := range cases {
+		if got := ForModel(model); got != want {
+			t.Errorf("ForModel(%q) = %s, want %s", model, got, want)
+		}
+	}
+	if _, ok := ParseFamily("bogus"); ok {
+		t.Error("ParseFamily accepted an unknown family")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	text := strings.Repeat("alpha beta gamma 数据 ", 30)
+	got, cut := Claude.Truncate(text, 25)
+	if !cut || Claude.Count(got) > 25 || !utf8.ValidString(got) || !strings.HasPrefix(text, got) {
+		t.Fatalf("Truncate = %q (cut=%v, %d tokens)", got, cut, Claude.Count(got))
+	}
+	if Claude.Count(text[:len(got)+len(string([]rune(text[len(got):])[0]))]) <= 25 {
+		t.Error("Truncate did not keep the longest fitting prefix")
+	}
+	if got, cut := Claude.Truncate("short", 25); cut || got != "short" {
+		t.Errorf("short text should pass through, got %q", got)
+	}
+}
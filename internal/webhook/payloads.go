@@ -0,0 +1,287 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/hurttlocker/cortex/internal/connect"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+// Payload is a request body mapped to captures.
+type Payload struct {
+	Event   string
+	Records []connect.Record
+	// Extract is set when a generic payload asks for fact extraction.
+	Extract bool
+	// Challenge is Slack's url_verification token, echoed back unchanged.
+	Challenge string
+	// Note explains why an event produced no records.
+	Note string
+}
+
+// Parse maps a verified request body to captures: GitHub events by
+// X-GitHub-Event, Slack Events API callbacks by X-Slack-Signature, and
+// everything else as a generic capture (JSON or plain text).
+func Parse(h http.Header, body []byte) (*Payload, error) {
+	switch {
+	case h.Get("X-GitHub-Event") != "":
+		return parseGitHub(h.Get("X-GitHub-Event"), body)
+	case h.Get("X-Slack-Signature") != "":
+		return parseSlack(body)
+	default:
+		return parseGeneric(h.Get("Content-Type"), body)
+	}
+}
+
+// GenericCapture is one capture in a generic JSON payload.
+type GenericCapture struct {
+	Content     string `json:"content"`
+	Source      string `json:"source,omitempty"`
+	Section     string `json:"section,omitempty"`
+	Project     string `json:"project,omitempty"`
+	MemoryClass string `json:"memory_class,omitempty"`
+	AgentID     string `json:"agent_id,omitempty"`
+}
+
+// genericPayload is a single capture, or a batch under "memories".
+type genericPayload struct {
+	GenericCapture
+	Memories []GenericCapture `json:"memories,omitempty"`
+	Extract  bool             `json:"extract,omitempty"`
+}
+
+func parseGeneric(contentType string, body []byte) (*Payload, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "text/plain" {
+		text := strings.TrimSpace(strings.ReplaceAll(string(body), "\x00", ""))
+		if text == "" {
+			return nil, fmt.Errorf("empty capture")
+		}
+		return &Payload{Records: []connect.Record{{Content: text}}}, nil
+	}
+
+	var gp genericPayload
+	if err := json.Unmarshal(body, &gp); err != nil {
+		return nil, fmt.Errorf("invalid JSON payload: %v", err)
+	}
+	captures := gp.Memories
+	if gp.Content != "" {
+		captures = append([]GenericCapture{gp.GenericCapture}, captures...)
+	}
+	if len(captures) == 0 {
+		return nil, fmt.Errorf(`payload needs "content" or "memories"`)
+	}
+
+	p := &Payload{Extract: gp.Extract}
+	for i, c := range captures {
+		content := strings.TrimSpace(strings.ReplaceAll(c.Content, "\x00", ""))
+		if content == "" {
+			return nil, fmt.Errorf("capture %d has no content", i+1)
+		}
+		class := store.NormalizeMemoryClass(c.MemoryClass)
+		if class != "" && !store.IsValidMemoryClass(class) {
+			return nil, fmt.Errorf("capture %d: invalid memory_class %q", i+1, c.MemoryClass)
+		}
+		p.Records = append(p.Records, connect.Record{
+			Content:     content,
+			Source:      sanitizeSource(c.Source),
+			Section:     c.Section,
+			Project:     c.Project,
+			MemoryClass: class,
+			AgentID:     c.AgentID,
+		})
+	}
+	return p, nil
+}
+
+// sanitizeSource keeps a caller-chosen source from escaping its hook prefix.
+func sanitizeSource(s string) string {
+	s = strings.ReplaceAll(s, "..", "")
+	return strings.TrimSpace(strings.ReplaceAll(s, "\x00", ""))
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubIssue struct {
+	Number  int        `json:"number"`
+	Title   string     `json:"title"`
+	Body    string     `json:"body"`
+	HTMLURL string     `json:"html_url"`
+	User    githubUser `json:"user"`
+	Merged  bool       `json:"merged"`
+}
+
+type githubEvent struct {
+	Action     string `json:"action"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender      githubUser   `json:"sender"`
+	Issue       *githubIssue `json:"issue"`
+	PullRequest *githubIssue `json:"pull_request"`
+	Comment     *struct {
+		ID      int64      `json:"id"`
+		Body    string     `json:"body"`
+		HTMLURL string     `json:"html_url"`
+		User    githubUser `json:"user"`
+	} `json:"comment"`
+	Release *struct {
+		TagName string `json:"tag_name"`
+		Name    string `json:"name"`
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+	} `json:"release"`
+	Ref     string `json:"ref"`
+	After   string `json:"after"`
+	Commits []struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	} `json:"commits"`
+}
+
+func parseGitHub(event string, body []byte) (*Payload, error) {
+	var ev githubEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return nil, fmt.Errorf("invalid GitHub payload: %v", err)
+	}
+	p := &Payload{Event: "github/" + event}
+	repo := ev.Repository.FullName
+	var rec connect.Record
+
+	switch {
+	case event == "ping":
+		p.Note = "pong"
+		return p, nil
+	case event == "issues" && ev.Issue != nil:
+		rec.Source = fmt.Sprintf("github:%s#%d", repo, ev.Issue.Number)
+		rec.Section = ev.Issue.Title
+		rec.Content = githubText(fmt.Sprintf("[%s] Issue #%d %s by %s: %s", repo, ev.Issue.Number, ev.Action, ev.Sender.Login, ev.Issue.Title), ev.Issue.Body, ev.Issue.HTMLURL)
+	case event == "pull_request" && ev.PullRequest != nil:
+		action := ev.Action
+		if action == "closed" && ev.PullRequest.Merged {
+			action = "merged"
+		}
+		rec.Source = fmt.Sprintf("github:%s/pull/%d", repo, ev.PullRequest.Number)
+		rec.Section = ev.PullRequest.Title
+		rec.Content = githubText(fmt.Sprintf("[%s] Pull request #%d %s by %s: %s", repo, ev.PullRequest.Number, action, ev.Sender.Login, ev.PullRequest.Title), ev.PullRequest.Body, ev.PullRequest.HTMLURL)
+	case (event == "issue_comment" || event == "pull_request_review_comment") && ev.Comment != nil:
+		if ev.Action != "created" && ev.Action != "edited" {
+			p.Note = "comment action " + ev.Action + " ignored"
+			return p, nil
+		}
+		parent := ev.Issue
+		if parent == nil {
+			parent = ev.PullRequest
+		}
+		if parent == nil {
+			return nil, fmt.Errorf("comment event without issue or pull request")
+		}
+		rec.Source = fmt.Sprintf("github:%s#%d/comment/%d", repo, parent.Number, ev.Comment.ID)
+		rec.Section = parent.Title
+		rec.Content = githubText(fmt.Sprintf("[%s] %s commented on #%d (%s)", repo, ev.Comment.User.Login, parent.Number, parent.Title), ev.Comment.Body, ev.Comment.HTMLURL)
+	case event == "release" && ev.Release != nil:
+		if ev.Action != "published" {
+			p.Note = "release action " + ev.Action + " ignored"
+			return p, nil
+		}
+		title := valueOr(ev.Release.Name, ev.Release.TagName)
+		rec.Source = fmt.Sprintf("github:%s/releases/%s", repo, ev.Release.TagName)
+		rec.Section = title
+		rec.Content = githubText(fmt.Sprintf("[%s] Release %s published by %s", repo, title, ev.Sender.Login), ev.Release.Body, ev.Release.HTMLURL)
+	case event == "push":
+		if len(ev.Commits) == 0 {
+			p.Note = "push without commits ignored"
+			return p, nil
+		}
+		branch := strings.TrimPrefix(ev.Ref, "refs/heads/")
+		var b strings.Builder
+		fmt.Fprintf(&b, "[%s] %s pushed %d commit(s) to %s:", repo, ev.Sender.Login, len(ev.Commits), branch)
+		for _, c := range ev.Commits {
+			msg, _, _ := strings.Cut(c.Message, "\n")
+			fmt.Fprintf(&b, "\n- %s %s", shortSHA(c.ID), msg)
+		}
+		rec.Source = fmt.Sprintf("github:%s@%s", repo, shortSHA(ev.After))
+		rec.Section = branch
+		rec.Content = b.String()
+	default:
+		p.Note = "event " + event + " ignored"
+		return p, nil
+	}
+	p.Records = []connect.Record{rec}
+	return p, nil
+}
+
+func githubText(headline, body, url string) string {
+	parts := []string{headline}
+	if b := strings.TrimSpace(body); b != "" {
+		parts = append(parts, b)
+	}
+	if url != "" {
+		parts = append(parts, url)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+type slackEnvelope struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	TeamID    string `json:"team_id"`
+	Event     struct {
+		Type    string `json:"type"`
+		Subtype string `json:"subtype"`
+		BotID   string `json:"bot_id"`
+		User    string `json:"user"`
+		Text    string `json:"text"`
+		Channel string `json:"channel"`
+		TS      string `json:"ts"`
+	} `json:"event"`
+}
+
+func parseSlack(body []byte) (*Payload, error) {
+	var env slackEnvelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, fmt.Errorf("invalid Slack payload: %v", err)
+	}
+	switch env.Type {
+	case "url_verification":
+		if env.Challenge == "" {
+			return nil, fmt.Errorf("url_verification without challenge")
+		}
+		return &Payload{Event: "slack/url_verification", Challenge: env.Challenge}, nil
+	case "event_callback":
+	default:
+		return &Payload{Event: "slack/" + env.Type, Note: "payload type " + env.Type + " ignored"}, nil
+	}
+
+	ev := env.Event
+	p := &Payload{Event: "slack/" + ev.Type}
+	switch {
+	case ev.Type != "message" && ev.Type != "app_mention":
+		p.Note = "event " + ev.Type + " ignored"
+	case ev.Subtype != "" || ev.BotID != "":
+		// Edits, joins, and bot messages (including our own) are not captures.
+		p.Note = "message subtype ignored"
+	case strings.TrimSpace(ev.Text) == "":
+		p.Note = "empty message ignored"
+	default:
+		p.Records = []connect.Record{{
+			Content: strings.TrimSpace(ev.Text),
+			Source:  fmt.Sprintf("slack:%s/%s", ev.Channel, ev.TS),
+			Section: ev.User,
+		}}
+	}
+	return p, nil
+}
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseGeneric(t *testing.T) {
+	p, err := Parse(http.Header{}, []byte(`{"content":"first","memories":[{"content":"second","project":"ops"}],"extract":true}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(p.Records) != 2 || !p.Extract || p.Records[1].Project != "ops" {
+		t.Fatalf("unexpected payload: %+v", p)
+	}
+
+	h := http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}}
+	p, err = Parse(h, []byte("  remember the milk \n"))
+	if err != nil || len(p.Records) != 1 || p.Records[0].Content != "remember the milk" {
+		t.Fatalf("plain text: %+v, %v", p, err)
+	}
+
+	for _, body := range []string{`{}`, `not json`, `{"content":"x","memory_class":"bogus"}`} {
+		if _, err := Parse(http.Header{}, []byte(body)); err == nil {
+			t.Errorf("expected error for %s", body)
+		}
+	}
+}
+
+func TestParseGitHub(t *testing.T) {
+	h := http.Header{"X-Github-Event": []string{"issues"}}
+	body := `{"action":"opened","repository":{"full_name":"acme/api"},"sender":{"login":"ana"},
+		"issue":{"number":7,"title":"Login fails","body":"Steps to reproduce","html_url":"https://github.com/acme/api/issues/7"}}`
+	p, err := Parse(h, []byte(body))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(p.Records) != 1 {
+		t.Fatalf("expected 1 record, got %+v", p)
+	}
+	rec := p.Records[0]
+	if rec.Source != "github:acme/api#7" || !strings.HasPrefix(rec.Content, "[acme/api] Issue #7 opened by ana: Login fails") {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+
+	h.Set("X-GitHub-Event", "push")
+	p, err = Parse(h, []byte(`{"ref":"refs/heads/main","after":"abcdef123456","repository":{"full_name":"acme/api"},
+		"sender":{"login":"ana"},"commits":[{"id":"abcdef123456","message":"Fix login\n\nDetails"}]}`))
+	if err != nil || len(p.Records) != 1 || !strings.Contains(p.Records[0].Content, "- abcdef1 Fix login") {
+		t.Fatalf("push: %+v, %v", p, err)
+	}
+
+	h.Set("X-GitHub-Event", "star")
+	p, err = Parse(h, []byte(`{"action":"created"}`))
+	if err != nil || len(p.Records) != 0 || p.Note == "" {
+		t.Errorf("unhandled event should be ignored with a note: %+v, %v", p, err)
+	}
+}
+
+func TestParseSlack(t *testing.T) {
+	h := http.Header{"X-Slack-Signature": []string{"v0=00"}}
+	p, err := Parse(h, []byte(`{"type":"event_callback","event":{"type":"message","user":"U1","text":"deploy is at 5pm","channel":"C9","ts":"1.2"}}`))
+	if err != nil || len(p.Records) != 1 || p.Records[0].Source != "slack:C9/1.2" {
+		t.Fatalf("message: %+v, %v", p, err)
+	}
+
+	p, err = Parse(h, []byte(`{"type":"event_callback","event":{"type":"message","bot_id":"B1","text":"beep","channel":"C9"}}`))
+	if err != nil || len(p.Records) != 0 {
+		t.Errorf("bot messages should be ignored: %+v, %v", p, err)
+	}
+}
@@ -0,0 +1,301 @@
+// Package webhook receives signed push captures over HTTP, so external
+// systems (Zapier, GitHub webhooks, Slack events) can send content into
+// Cortex without a polling connector.
+//
+// Every request is POST /hooks/{name} with an HMAC-SHA256 signature over the
+// raw body. The payload is mapped to connector Records (GitHub and Slack
+// events by their headers, anything else as a generic capture) and stored as
+// memories under the source "webhook:{name}".
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/connect"
+	"github.com/hurttlocker/cortex/internal/jobs"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const (
+	// SourcePrefix prefixes the source of every webhook capture. It is also
+	// the quota key: import.quotas.connectors.webhook caps all hooks together.
+	SourcePrefix = "webhook"
+
+	// MaxBodyBytes caps a request body.
+	MaxBodyBytes = 1 << 20
+
+	// slackMaxSkew rejects Slack requests whose timestamp is further than
+	// this from now, which stops replays of captured requests.
+	slackMaxSkew = 5 * time.Minute
+)
+
+var hookNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,39}$`)
+
+// Config holds the inbound endpoint's settings.
+type Config struct {
+	Store *store.SQLiteStore
+	// Secret returns the signing secret for a hook name; "" rejects the hook.
+	Secret func(name string) string
+	// Project tags captures whose payload names none.
+	Project string
+	// Extract queues fact extraction for every capture. Generic payloads can
+	// also ask for it with "extract": true.
+	Extract bool
+	// Jobs runs extraction; when nil, extraction requests are skipped.
+	Jobs *jobs.Runner
+	// Quotas applies ingestion quotas under the "webhook" key.
+	Quotas *connect.QuotaPolicy
+	Logger *log.Logger
+	// Now is the clock; nil means time.Now.
+	Now func() time.Time
+}
+
+// Result is the JSON body returned for an accepted request.
+type Result struct {
+	Hook      string  `json:"hook"`
+	Event     string  `json:"event,omitempty"`
+	Stored    int     `json:"stored"`
+	Skipped   int     `json:"skipped"`
+	MemoryIDs []int64 `json:"memory_ids,omitempty"`
+	JobID     int64   `json:"extraction_job,omitempty"`
+	Note      string  `json:"note,omitempty"`
+}
+
+// NewHandler returns the POST /hooks/{name} endpoint.
+func NewHandler(cfg Config) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /hooks/{name}", func(w http.ResponseWriter, r *http.Request) {
+		handle(cfg, w, r)
+	})
+	return mux
+}
+
+func handle(cfg Config, w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if !hookNamePattern.MatchString(name) {
+		writeError(w, http.StatusNotFound, "invalid hook name")
+		return
+	}
+	secret := ""
+	if cfg.Secret != nil {
+		secret = cfg.Secret(name)
+	}
+	if secret == "" {
+		writeError(w, http.StatusServiceUnavailable, "no signing secret configured for hook "+name)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("body exceeds %d bytes", MaxBodyBytes))
+			return
+		}
+		writeError(w, http.StatusBadRequest, "reading body: "+err.Error())
+		return
+	}
+	now := time.Now
+	if cfg.Now != nil {
+		now = cfg.Now
+	}
+	if err := Verify(r.Header, body, secret, now()); err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	p, err := Parse(r.Header, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if p.Challenge != "" {
+		writeJSON(w, http.StatusOK, map[string]string{"challenge": p.Challenge})
+		return
+	}
+
+	res := Result{Hook: name, Event: p.Event, Note: p.Note}
+	if len(p.Records) == 0 {
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+
+	ctx := r.Context()
+	if retryAfter, err := checkQuota(ctx, cfg, len(p.Records), now()); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+		writeError(w, http.StatusTooManyRequests, "webhook ingestion quota exceeded")
+		return
+	}
+
+	for _, rec := range p.Records {
+		id, stored, err := storeRecord(ctx, cfg, name, rec)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !stored {
+			res.Skipped++
+			continue
+		}
+		res.Stored++
+		res.MemoryIDs = append(res.MemoryIDs, id)
+	}
+
+	if (cfg.Extract || p.Extract) && len(res.MemoryIDs) > 0 {
+		if cfg.Jobs == nil {
+			res.Note = "extraction skipped: background jobs are disabled"
+		} else if job, err := cfg.Jobs.Enqueue(ctx, jobs.KindExtract, jobs.ExtractParams{MemoryIDs: res.MemoryIDs}, "webhook:"+name); err != nil {
+			res.Note = "extraction not queued: " + err.Error()
+		} else {
+			res.JobID = job.ID
+		}
+	}
+
+	if cfg.Logger != nil {
+		cfg.Logger.Printf("hook %s: %s stored=%d skipped=%d", name, valueOr(p.Event, "capture"), res.Stored, res.Skipped)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// Verify checks the request's HMAC-SHA256 signature. It accepts, in order:
+//
+//	X-Hub-Signature-256: sha256=<hex>    GitHub, over the body
+//	X-Slack-Signature: v0=<hex>          Slack, over "v0:<timestamp>:<body>"
+//	X-Cortex-Signature: sha256=<hex>     everything else, over the body
+func Verify(h http.Header, body []byte, secret string, now time.Time) error {
+	if sig := h.Get("X-Hub-Signature-256"); sig != "" {
+		return checkMAC(secret, body, strings.TrimPrefix(sig, "sha256="))
+	}
+	if sig := h.Get("X-Slack-Signature"); sig != "" {
+		ts := h.Get("X-Slack-Request-Timestamp")
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return fmt.Errorf("missing or invalid X-Slack-Request-Timestamp")
+		}
+		if skew := now.Sub(time.Unix(sec, 0)); skew > slackMaxSkew || skew < -slackMaxSkew {
+			return fmt.Errorf("request timestamp is outside the allowed window")
+		}
+		signed := append([]byte("v0:"+ts+":"), body...)
+		return checkMAC(secret, signed, strings.TrimPrefix(sig, "v0="))
+	}
+	if sig := h.Get("X-Cortex-Signature"); sig != "" {
+		return checkMAC(secret, body, strings.TrimPrefix(sig, "sha256="))
+	}
+	return fmt.Errorf("missing signature header (X-Cortex-Signature, X-Hub-Signature-256, or X-Slack-Signature)")
+}
+
+// Sign returns the X-Cortex-Signature value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func checkMAC(secret string, msg []byte, hexSig string) error {
+	got, err := hex.DecodeString(strings.TrimSpace(hexSig))
+	if err != nil {
+		return fmt.Errorf("malformed signature")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(msg)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// checkQuota returns how long to wait when n more captures would exceed the
+// webhook quota, or 0 when they fit.
+func checkQuota(ctx context.Context, cfg Config, n int, now time.Time) (time.Duration, error) {
+	q := connect.EffectiveQuota(&connect.Connector{Provider: SourcePrefix}, cfg.Quotas)
+	windows := []struct {
+		limit  int
+		window time.Duration
+	}{{q.Daily, 24 * time.Hour}, {q.Hourly, time.Hour}}
+	for _, w := range windows {
+		if w.limit <= 0 {
+			continue
+		}
+		used, err := cfg.Store.CountSourceImportsSince(ctx, SourcePrefix, now.Add(-w.window))
+		if err != nil {
+			return 0, err
+		}
+		if used+n > w.limit {
+			if cfg.Logger != nil {
+				cfg.Logger.Printf("webhook quota exceeded: %d of %d per %s used", used, w.limit, w.window)
+			}
+			return w.window, nil
+		}
+	}
+	return 0, nil
+}
+
+// storeRecord saves one capture, reporting false for a duplicate (webhook
+// senders redeliver, so identical captures are skipped by content hash).
+func storeRecord(ctx context.Context, cfg Config, name string, rec connect.Record) (int64, bool, error) {
+	source := SourcePrefix + ":" + name
+	if rec.Source != "" {
+		source += ":" + rec.Source
+	}
+	hash := store.HashMemoryContent(rec.Content, source)
+	existing, err := cfg.Store.FindByHash(ctx, hash)
+	if err != nil {
+		return 0, false, fmt.Errorf("checking hash: %w", err)
+	}
+	if existing != nil {
+		return 0, false, nil
+	}
+
+	mem := &store.Memory{
+		Content:       rec.Content,
+		SourceFile:    source,
+		SourceSection: rec.Section,
+		ContentHash:   hash,
+		Project:       valueOr(rec.Project, cfg.Project),
+		MemoryClass:   valueOr(rec.MemoryClass, store.DefaultClassForSource(source)),
+	}
+	if rec.AgentID != "" {
+		mem.Metadata = &store.Metadata{AgentID: rec.AgentID}
+	}
+	id, err := cfg.Store.AddMemory(ctx, mem)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("storing memory: %w", err)
+	}
+	return id, true, nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
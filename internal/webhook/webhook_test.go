@@ -0,0 +1,156 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hurttlocker/cortex/internal/connect"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+const testSecret = "s3cret"
+
+func newTestHandler(t *testing.T, mutate func(*Config)) (http.Handler, *store.SQLiteStore) {
+	t.Helper()
+	st, err := store.NewStore(store.StoreConfig{DBPath: ":memory:"})
+	if err != nil {
+		t.Fatalf("creating store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	sqlStore := st.(*store.SQLiteStore)
+	cfg := Config{
+		Store:  sqlStore,
+		Secret: func(string) string { return testSecret },
+	}
+	if mutate != nil {
+		mutate(&cfg)
+	}
+	return NewHandler(cfg), sqlStore
+}
+
+func post(t *testing.T, h http.Handler, path string, body []byte, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerGenericCapture(t *testing.T) {
+	h, st := newTestHandler(t, func(c *Config) { c.Project = "inbox" })
+	body := []byte(`{"content":"Ship the beta on Friday","source":"zap-42","memory_class":"decision"}`)
+	sig := map[string]string{"X-Cortex-Signature": Sign(testSecret, body)}
+
+	rec := post(t, h, "/hooks/zapier", body, sig)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+	var res Result
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("decoding result: %v", err)
+	}
+	if res.Stored != 1 || len(res.MemoryIDs) != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	mem, err := st.GetMemory(context.Background(), res.MemoryIDs[0])
+	if err != nil {
+		t.Fatalf("GetMemory: %v", err)
+	}
+	if mem.SourceFile != "webhook:zapier:zap-42" || mem.Project != "inbox" || mem.MemoryClass != "decision" {
+		t.Errorf("unexpected memory: source=%q project=%q class=%q", mem.SourceFile, mem.Project, mem.MemoryClass)
+	}
+
+	// Redelivery is deduplicated.
+	rec = post(t, h, "/hooks/zapier", body, sig)
+	json.Unmarshal(rec.Body.Bytes(), &res)
+	if rec.Code != http.StatusOK || res.Stored != 0 || res.Skipped != 1 {
+		t.Errorf("redelivery: status %d, result %+v", rec.Code, res)
+	}
+}
+
+func TestHandlerRejectsBadSignature(t *testing.T) {
+	h, _ := newTestHandler(t, nil)
+	body := []byte(`{"content":"hello"}`)
+
+	if rec := post(t, h, "/hooks/zapier", body, nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned: status = %d, want 401", rec.Code)
+	}
+	bad := map[string]string{"X-Cortex-Signature": Sign("wrong", body)}
+	if rec := post(t, h, "/hooks/zapier", body, bad); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong secret: status = %d, want 401", rec.Code)
+	}
+}
+
+func TestHandlerNoSecret(t *testing.T) {
+	h, _ := newTestHandler(t, func(c *Config) {
+		c.Secret = func(name string) string {
+			if name == "slack" {
+				return testSecret
+			}
+			return ""
+		}
+	})
+	body := []byte(`{"content":"hello"}`)
+	rec := post(t, h, "/hooks/zapier", body, map[string]string{"X-Cortex-Signature": Sign(testSecret, body)})
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
+
+func TestHandlerQuota(t *testing.T) {
+	h, _ := newTestHandler(t, func(c *Config) {
+		c.Quotas = &connect.QuotaPolicy{Connectors: map[string]connect.Quota{"webhook": {Hourly: 2}}}
+	})
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		body := []byte(`{"content":"capture ` + strconv.Itoa(i) + `"}`)
+		rec := post(t, h, "/hooks/zapier", body, map[string]string{"X-Cortex-Signature": Sign(testSecret, body)})
+		if rec.Code != want {
+			t.Fatalf("capture %d: status = %d, want %d", i, rec.Code, want)
+		}
+		if want == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "3600" {
+			t.Errorf("Retry-After = %q, want 3600", rec.Header().Get("Retry-After"))
+		}
+	}
+}
+
+func TestHandlerSlackChallenge(t *testing.T) {
+	now := time.Unix(1_760_000_000, 0)
+	h, _ := newTestHandler(t, func(c *Config) { c.Now = func() time.Time { return now } })
+	body := []byte(`{"type":"url_verification","challenge":"abc123"}`)
+
+	rec := post(t, h, "/hooks/slack", body, slackHeaders(body, now))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"challenge":"abc123"`) {
+		t.Fatalf("status %d, body %s", rec.Code, rec.Body)
+	}
+
+	// A replayed request outside the window is refused.
+	rec = post(t, h, "/hooks/slack", body, slackHeaders(body, now.Add(-10*time.Minute)))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("stale timestamp: status = %d, want 401", rec.Code)
+	}
+}
+
+func slackHeaders(body []byte, ts time.Time) map[string]string {
+	stamp := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte("v0:" + stamp + ":"))
+	mac.Write(body)
+	return map[string]string{
+		"X-Slack-Request-Timestamp": stamp,
+		"X-Slack-Signature":         "v0=" + hex.EncodeToString(mac.Sum(nil)),
+	}
+}
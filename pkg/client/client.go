@@ -0,0 +1,227 @@
+// Package client is a Go client for a running Cortex server. It speaks MCP
+// over HTTP+SSE to `cortex mcp --port N` or `cortex daemon`, and wraps the
+// cortex_* tools in typed methods so Go programs can search, import, and
+// reason over a Cortex store without shelling out to the CLI.
+//
+//	c, err := client.Dial(ctx, client.Config{BaseURL: "http://localhost:8080"})
+//	if err != nil { ... }
+//	defer c.Close()
+//	results, err := c.Search(ctx, "deploy window", client.SearchOptions{Limit: 5})
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Config says where the server is and how to reach it.
+type Config struct {
+	// BaseURL is the server root, e.g. "http://localhost:8080". A URL that
+	// already ends in /sse is used as is.
+	BaseURL string
+	// Headers are sent with every request, e.g. an Authorization header
+	// for a proxy in front of the server.
+	Headers    map[string]string
+	HTTPClient *http.Client
+}
+
+// ToolError is returned when the server ran a tool and reported an error
+// (bad arguments, no LLM configured, ...), as opposed to a transport failure.
+type ToolError struct {
+	Tool    string
+	Message string
+}
+
+func (e *ToolError) Error() string { return e.Tool + ": " + e.Message }
+
+// Client is a connection to a Cortex server. It is safe for concurrent use.
+type Client struct {
+	mcp *mcpclient.Client
+}
+
+// Dial connects to the server and completes the MCP handshake.
+func Dial(ctx context.Context, cfg Config) (*Client, error) {
+	url := strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if url == "" {
+		return nil, errors.New("client: BaseURL is required")
+	}
+	if !strings.HasSuffix(url, "/sse") {
+		url += "/sse"
+	}
+	var opts []transport.ClientOption
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, transport.WithHeaders(cfg.Headers))
+	}
+	if cfg.HTTPClient != nil {
+		opts = append(opts, transport.WithHTTPClient(cfg.HTTPClient))
+	}
+	mc, err := mcpclient.NewSSEMCPClient(url, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("client: %w", err)
+	}
+	// The SSE stream lives as long as the Client, not the dial context.
+	if err := mc.Start(context.WithoutCancel(ctx)); err != nil {
+		return nil, fmt.Errorf("client: connecting to %s: %w", url, err)
+	}
+	init := mcp.InitializeRequest{}
+	init.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	init.Params.ClientInfo = mcp.Implementation{Name: "cortex-go-client", Version: "1"}
+	if _, err := mc.Initialize(ctx, init); err != nil {
+		mc.Close()
+		return nil, fmt.Errorf("client: initializing session: %w", err)
+	}
+	return &Client{mcp: mc}, nil
+}
+
+// Close ends the session.
+func (c *Client) Close() error { return c.mcp.Close() }
+
+// CallTool calls any cortex_* tool by name and returns its text result, for
+// tools without a typed method.
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]any) (string, error) {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	res, err := c.mcp.CallTool(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("client: calling %s: %w", name, err)
+	}
+	var b strings.Builder
+	for _, content := range res.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			b.WriteString(text.Text)
+		}
+	}
+	if res.IsError {
+		return "", &ToolError{Tool: name, Message: b.String()}
+	}
+	return b.String(), nil
+}
+
+// callJSON calls a tool and decodes its JSON result into out. Tools answer
+// "No ... found" in plain text when there is nothing to return; that leaves
+// out untouched.
+func (c *Client) callJSON(ctx context.Context, name string, args map[string]any, out any) error {
+	text, err := c.CallTool(ctx, name, args)
+	if err != nil {
+		return err
+	}
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "{") && !strings.HasPrefix(text, "[") && text != "null" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(text), out); err != nil {
+		return fmt.Errorf("client: decoding %s result: %w", name, err)
+	}
+	return nil
+}
+
+// Search returns the memory chunks that best match query.
+func (c *Client) Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	var out []SearchResult
+	err := c.callJSON(ctx, "cortex_search", searchArgs(query, opts, false), &out)
+	return out, err
+}
+
+// SearchFacts returns facts that match query directly, instead of memory
+// chunks.
+func (c *Client) SearchFacts(ctx context.Context, query string, opts SearchOptions) ([]FactHit, error) {
+	var out []FactHit
+	err := c.callJSON(ctx, "cortex_search", searchArgs(query, opts, true), &out)
+	return out, err
+}
+
+func searchArgs(query string, opts SearchOptions, facts bool) map[string]any {
+	args := map[string]any{"query": query}
+	if facts {
+		args["facts"] = true
+	}
+	setString(args, "mode", opts.Mode)
+	setString(args, "project", opts.Project)
+	setString(args, "agent_id", opts.Agent)
+	setString(args, "source", opts.Source)
+	setString(args, "profile", opts.Profile)
+	setInt(args, "limit", opts.Limit)
+	return args
+}
+
+// Import stores content as one or more memories.
+func (c *Client) Import(ctx context.Context, content string, opts ImportOptions) (*ImportResult, error) {
+	args := map[string]any{"content": content}
+	setString(args, "source", opts.Source)
+	setString(args, "project", opts.Project)
+	setString(args, "agent_id", opts.Agent)
+	setBool(args, "extract", opts.Extract)
+	setBool(args, "background", opts.Background)
+	setBool(args, "enrich", opts.Enrich)
+	out := &ImportResult{}
+	if err := c.callJSON(ctx, "cortex_import", args, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Facts lists stored facts.
+func (c *Client) Facts(ctx context.Context, opts FactsOptions) ([]Fact, error) {
+	args := map[string]any{}
+	setString(args, "subject", opts.Subject)
+	setString(args, "type", opts.Type)
+	setString(args, "agent_id", opts.Agent)
+	setInt(args, "limit", opts.Limit)
+	var out []Fact
+	err := c.callJSON(ctx, "cortex_facts", args, &out)
+	return out, err
+}
+
+// Graph walks the knowledge graph from a fact, up to depth hops (0 for the
+// server default of 2, max 5).
+func (c *Client) Graph(ctx context.Context, factID int64, depth int) ([]GraphNode, error) {
+	args := map[string]any{"fact_id": factID}
+	setInt(args, "depth", depth)
+	var out []GraphNode
+	err := c.callJSON(ctx, "cortex_graph", args, &out)
+	return out, err
+}
+
+// Reason answers query by having the server's LLM reason over the most
+// relevant memories and facts.
+func (c *Client) Reason(ctx context.Context, query string, opts ReasonOptions) (*ReasonResult, error) {
+	args := map[string]any{"query": query}
+	setString(args, "preset", opts.Preset)
+	setString(args, "model", opts.Model)
+	setString(args, "project", opts.Project)
+	setInt(args, "budget", opts.Budget)
+	setInt(args, "max_tokens", opts.MaxTokens)
+	setBool(args, "no_cache", opts.NoCache)
+	out := &ReasonResult{}
+	if err := c.callJSON(ctx, "cortex_reason", args, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func setString(args map[string]any, key, v string) {
+	if v != "" {
+		args[key] = v
+	}
+}
+
+func setInt(args map[string]any, key string, v int) {
+	if v > 0 {
+		args[key] = v
+	}
+}
+
+func setBool(args map[string]any, key string, v bool) {
+	if v {
+		args[key] = true
+	}
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	cortexmcp "github.com/hurttlocker/cortex/internal/mcp"
+	"github.com/hurttlocker/cortex/internal/store"
+)
+
+func dialTestServer(t *testing.T) (*Client, *store.SQLiteStore) {
+	t.Helper()
+	si, err := store.NewStore(store.StoreConfig{DBPath: filepath.Join(t.TempDir(), "cortex.db")})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { si.Close() })
+	st := si.(*store.SQLiteStore)
+
+	ts := httptest.NewServer(server.NewSSEServer(cortexmcp.NewServer(cortexmcp.ServerConfig{Store: st, DBPath: ":memory:"})))
+	t.Cleanup(ts.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c, err := Dial(ctx, Config{BaseURL: ts.URL + "/"})
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c, st
+}
+
+func TestClient_ImportSearchFactsGraph(t *testing.T) {
+	c, st := dialTestServer(t)
+	ctx := context.Background()
+
+	imp, err := c.Import(ctx, "The deploy window is Tuesday at 14:00 UTC. Owner: platform team.", ImportOptions{Source: "ops.md", Project: "ops"})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if imp.Stored != 1 || len(imp.IDs) != 1 || imp.Source != "ops.md" {
+		t.Fatalf("unexpected import result: %+v", imp)
+	}
+
+	results, err := c.Search(ctx, "deploy window", SearchOptions{Mode: "bm25", Limit: 5})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].MemoryID != imp.IDs[0] || results[0].Project != "ops" {
+		t.Fatalf("unexpected search results: %+v", results)
+	}
+
+	f1, _ := st.AddFact(ctx, &store.Fact{MemoryID: imp.IDs[0], Subject: "deploy window", Predicate: "day", Object: "Tuesday", FactType: "kv", Confidence: 0.9})
+	f2, _ := st.AddFact(ctx, &store.Fact{MemoryID: imp.IDs[0], Subject: "deploy window", Predicate: "owner", Object: "platform team", FactType: "kv", Confidence: 0.9})
+	if err := st.AddEdge(ctx, &store.FactEdge{SourceFactID: f1, TargetFactID: f2, EdgeType: store.EdgeTypeRelatesTo, Confidence: 0.8, Source: store.EdgeSourceExplicit}); err != nil {
+		t.Fatal(err)
+	}
+
+	facts, err := c.Facts(ctx, FactsOptions{Subject: "deploy"})
+	if err != nil {
+		t.Fatalf("Facts: %v", err)
+	}
+	if len(facts) != 2 || facts[0].Subject != "deploy window" || facts[0].MemoryID != imp.IDs[0] {
+		t.Fatalf("unexpected facts: %+v", facts)
+	}
+
+	nodes, err := c.Graph(ctx, f1, 1)
+	if err != nil {
+		t.Fatalf("Graph: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].Fact.ID != f1 || nodes[1].Fact.ID != f2 {
+		t.Fatalf("unexpected graph: %+v", nodes)
+	}
+	if nodes, err := c.Graph(ctx, 9999, 0); err != nil || len(nodes) != 0 {
+		t.Fatalf("missing fact should give an empty graph, got %+v, %v", nodes, err)
+	}
+}
+
+func TestClient_ToolErrors(t *testing.T) {
+	c, _ := dialTestServer(t)
+
+	_, err := c.Import(context.Background(), "   ", ImportOptions{})
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) || toolErr.Tool != "cortex_import" {
+		t.Fatalf("expected a ToolError from cortex_import, got %v", err)
+	}
+	if _, err := Dial(context.Background(), Config{}); err == nil {
+		t.Fatal("Dial without BaseURL should fail")
+	}
+}
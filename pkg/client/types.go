@@ -0,0 +1,160 @@
+package client
+
+import "time"
+
+// SearchOptions narrows a Search or SearchFacts call. Zero values use the
+// server defaults.
+type SearchOptions struct {
+	Mode    string // bm25, semantic, hybrid, rrf, or auto
+	Limit   int    // max 50
+	Project string
+	Agent   string
+	Source  string // source prefix, e.g. "github"
+	Profile string // saved search profile from the server's config
+}
+
+// SearchResult is one ranked memory chunk.
+type SearchResult struct {
+	MemoryID      int64     `json:"memory_id"`
+	Content       string    `json:"content"`
+	Snippet       string    `json:"snippet,omitempty"`
+	SourceFile    string    `json:"source_file"`
+	SourceLine    int       `json:"source_line"`
+	SourceSection string    `json:"source_section,omitempty"`
+	Project       string    `json:"project,omitempty"`
+	Class         string    `json:"class,omitempty"`
+	Score         float64   `json:"score"`
+	MatchType     string    `json:"match_type"`
+	FactIDs       []int64   `json:"fact_ids"`
+	ImportedAt    time.Time `json:"imported_at,omitempty"`
+}
+
+// FactHit is one fact returned by SearchFacts.
+type FactHit struct {
+	FactID     int64   `json:"fact_id"`
+	MemoryID   int64   `json:"memory_id,omitempty"`
+	Subject    string  `json:"subject"`
+	Predicate  string  `json:"predicate"`
+	Object     string  `json:"object"`
+	FactType   string  `json:"fact_type"`
+	Confidence float64 `json:"confidence"`
+	SourceFile string  `json:"source_file"`
+	Score      float64 `json:"score"`
+}
+
+// ImportOptions controls how Import stores content.
+type ImportOptions struct {
+	Source     string // filename or URL; default "mcp-import"
+	Project    string
+	Agent      string
+	Extract    bool // extract facts with the rule-based extractor
+	Background bool // with Extract, queue extraction as a job instead of waiting
+	Enrich     bool // with Background, also ask the server's LLM for facts
+}
+
+// ImportResult reports what Import stored.
+type ImportResult struct {
+	IDs            []int64 `json:"ids"`
+	Chunks         int     `json:"chunks"`
+	Stored         int     `json:"stored"` // chunks not already stored
+	Source         string  `json:"source"`
+	Message        string  `json:"message"`
+	FactsExtracted int     `json:"facts_extracted,omitempty"`
+	ExtractionJob  int64   `json:"extraction_job,omitempty"`
+}
+
+// FactsOptions filters Facts.
+type FactsOptions struct {
+	Subject string // case-insensitive partial match
+	Type    string // fact type, e.g. "kv" or "relationship"
+	Agent   string
+	Limit   int // default 20, max 100
+}
+
+// Fact is a subject-predicate-object triple. Field names match the server's
+// JSON, which has no tags for facts.
+type Fact struct {
+	ID             int64
+	MemoryID       int64
+	Subject        string
+	Predicate      string
+	Object         string
+	FactType       string
+	Confidence     float64
+	SourceQuote    string
+	State          string
+	SupersededBy   *int64
+	AgentID        string
+	CreatedAt      time.Time
+	LastReinforced time.Time
+}
+
+// Edge is a typed link between two facts.
+type Edge struct {
+	ID           int64     `json:"id"`
+	SourceFactID int64     `json:"source_fact_id"`
+	TargetFactID int64     `json:"target_fact_id"`
+	EdgeType     string    `json:"edge_type"`
+	Confidence   float64   `json:"confidence"`
+	Source       string    `json:"source"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// GraphNode is a fact reached by Graph, with the edges that led to it.
+type GraphNode struct {
+	Fact  *Fact  `json:"fact"`
+	Edges []Edge `json:"edges"`
+	Depth int    `json:"depth"`
+}
+
+// ReasonOptions controls a Reason call. Zero values use the preset's
+// defaults.
+type ReasonOptions struct {
+	Preset    string // e.g. "daily-digest"
+	Model     string // provider/model, e.g. "google/gemini-2.5-flash"
+	Project   string
+	Budget    int // context budget in tokens
+	MaxTokens int
+	NoCache   bool // always call the LLM, even if a cached answer is current
+}
+
+// ReasonResult is a synthesized answer with the memories it cites.
+type ReasonResult struct {
+	Content      string     `json:"content"`
+	Query        string     `json:"query"`
+	Project      string     `json:"project"`
+	Model        string     `json:"model"`
+	Provider     string     `json:"provider"`
+	Preset       string     `json:"preset"`
+	MemoriesUsed int        `json:"memories_used"`
+	FactsUsed    int        `json:"facts_used"`
+	DurationMS   int64      `json:"duration_ms"`
+	TokensIn     int        `json:"tokens_in"`
+	TokensOut    int        `json:"tokens_out"`
+	Cached       bool       `json:"cached,omitempty"` // served from the server's reason cache
+	Citations    []Citation `json:"citations"`
+
+	// Confidence grades the facts behind the cited memories; nil when the
+	// server could not grade them.
+	Confidence *AnswerConfidence `json:"confidence,omitempty"`
+}
+
+// AnswerConfidence says how far an answer's evidence can be trusted.
+type AnswerConfidence struct {
+	Band             string   `json:"band"`  // high, medium, low, or unknown (no facts)
+	Score            float64  `json:"score"` // mean effective confidence, discounted for conflicts
+	Facts            int      `json:"facts"`
+	StaleFacts       []int64  `json:"stale_facts,omitempty"`
+	ConflictingFacts []int64  `json:"conflicting_facts,omitempty"`
+	Flags            []string `json:"flags,omitempty"` // stale, conflicting, no_facts
+}
+
+// Citation points at a memory a Reason answer was built from.
+type Citation struct {
+	Index    int     `json:"index"`
+	Source   string  `json:"source"`
+	Title    string  `json:"title"`
+	Score    float64 `json:"score"`
+	MemoryID int64   `json:"memory_id"`
+	Project  string  `json:"project,omitempty"`
+}
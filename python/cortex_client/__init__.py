@@ -0,0 +1,19 @@
+"""Python client for a running Cortex server.
+
+Speaks MCP over HTTP+SSE to ``cortex mcp --port N`` or ``cortex daemon`` and
+wraps the cortex_* tools in plain methods, so notebooks and scripts can
+search memories, pull facts into a DataFrame, and push captures without
+shelling out to the CLI::
+
+    from cortex_client import Client
+
+    with Client("http://localhost:8080") as c:
+        c.capture("Deploy window moved to Wednesday.", source="notes.md")
+        hits = c.search("deploy window", limit=5)
+        df = c.facts_frame(subject="deploy")
+"""
+
+from .client import Client, CortexError, ToolError
+
+__all__ = ["Client", "CortexError", "ToolError"]
+__version__ = "0.1.0"
@@ -0,0 +1,294 @@
+"""MCP-over-SSE client for Cortex. Standard library only; pandas is optional."""
+
+import http.client
+import itertools
+import json
+import re
+import socket
+import threading
+import urllib.error
+import urllib.parse
+import urllib.request
+
+PROTOCOL_VERSION = "2025-06-18"
+
+
+class CortexError(Exception):
+    """A transport or protocol failure talking to the server."""
+
+
+class ToolError(CortexError):
+    """The server ran a tool and reported an error (bad arguments, no LLM
+    configured, ...), as opposed to a transport failure."""
+
+    def __init__(self, tool, message):
+        super().__init__(f"{tool}: {message}")
+        self.tool = tool
+        self.message = message
+
+
+class Client:
+    """A connection to a Cortex server. Safe to share between threads.
+
+    ``base_url`` is the server root, e.g. ``http://localhost:8080``; a URL
+    that already ends in ``/sse`` is used as is. ``headers`` are sent with
+    every request, e.g. an Authorization header for a proxy in front of the
+    server. ``timeout`` bounds each tool call, in seconds.
+    """
+
+    def __init__(self, base_url, headers=None, timeout=30.0):
+        url = (base_url or "").strip().rstrip("/")
+        if not url:
+            raise ValueError("base_url is required")
+        if not url.endswith("/sse"):
+            url += "/sse"
+        self._sse_url = url
+        self._headers = dict(headers or {})
+        self._timeout = timeout
+        self._ids = itertools.count(1)
+        self._lock = threading.Lock()
+        self._pending = {}
+        self._endpoint = None
+        self._endpoint_ready = threading.Event()
+        self._closed = False
+        self._stream_error = None
+
+        try:
+            self._sock, self._stream = _open_stream(url, self._headers, timeout)
+        except (http.client.HTTPException, OSError) as e:
+            raise CortexError(f"connecting to {url}: {e}") from e
+        self._reader = threading.Thread(target=self._read_stream, name="cortex-sse", daemon=True)
+        self._reader.start()
+
+        if not self._endpoint_ready.wait(timeout) or self._endpoint is None:
+            self.close()
+            raise CortexError(f"{url} did not announce a message endpoint")
+        try:
+            self._request("initialize", {
+                "protocolVersion": PROTOCOL_VERSION,
+                "capabilities": {},
+                "clientInfo": {"name": "cortex-python-client", "version": "1"},
+            })
+            self._post({"jsonrpc": "2.0", "method": "notifications/initialized"})
+        except CortexError as e:
+            self.close()
+            raise CortexError(f"initializing session: {e}") from e
+
+    def __enter__(self):
+        return self
+
+    def __exit__(self, *exc):
+        self.close()
+
+    def close(self):
+        """End the session."""
+        with self._lock:
+            if self._closed:
+                return
+            self._closed = True
+            pending = list(self._pending.values())
+        # Shut the socket down rather than closing the response: the reader
+        # thread is blocked reading it and holds the response's lock.
+        try:
+            self._sock.shutdown(socket.SHUT_RDWR)
+        except OSError:
+            pass
+        for slot in pending:
+            slot["done"].set()
+
+    # -- tools ---------------------------------------------------------------
+
+    def call_tool(self, name, arguments=None):
+        """Call any cortex_* tool by name and return its text result, for
+        tools without a dedicated method."""
+        result = self._request("tools/call", {"name": name, "arguments": arguments or {}})
+        text = "".join(c.get("text", "") for c in result.get("content") or [] if c.get("type") == "text")
+        if result.get("isError"):
+            raise ToolError(name, text)
+        return text
+
+    def search(self, query, mode=None, limit=None, project=None, agent=None, source=None, profile=None):
+        """Return the memory chunks that best match query, best first."""
+        args = _args(query=query, mode=mode, limit=limit, project=project,
+                     agent_id=agent, source=source, profile=profile)
+        return self._call_json("cortex_search", args) or []
+
+    def search_facts(self, query, mode=None, limit=None, project=None, agent=None, source=None, profile=None):
+        """Return facts that match query directly, instead of memory chunks."""
+        args = _args(query=query, facts=True, mode=mode, limit=limit, project=project,
+                     agent_id=agent, source=source, profile=profile)
+        return self._call_json("cortex_search", args) or []
+
+    def capture(self, content, source=None, project=None, agent=None, extract=False, background=False, enrich=False):
+        """Store content as one or more memories. With extract, facts are
+        pulled out with the rule-based extractor; with background as well,
+        extraction is queued as a job (and enrich adds LLM facts)."""
+        args = _args(content=content, source=source, project=project, agent_id=agent,
+                     extract=extract, background=background, enrich=enrich)
+        return self._call_json("cortex_import", args) or {}
+
+    def facts(self, subject=None, type=None, agent=None, limit=None):
+        """List stored facts. Keys are snake_case (``id``, ``memory_id``,
+        ``subject``, ...)."""
+        args = _args(subject=subject, type=type, agent_id=agent, limit=limit)
+        return [_snake_keys(f) for f in self._call_json("cortex_facts", args) or []]
+
+    def graph(self, fact_id, depth=None):
+        """Walk the knowledge graph from a fact, up to depth hops (server
+        default 2, max 5). Each node is ``{"fact", "edges", "depth"}``."""
+        nodes = self._call_json("cortex_graph", _args(fact_id=int(fact_id), depth=depth)) or []
+        for node in nodes:
+            if node.get("fact"):
+                node["fact"] = _snake_keys(node["fact"])
+        return nodes
+
+    def reason(self, query, preset=None, model=None, project=None, budget=None, max_tokens=None):
+        """Answer query with the server's LLM, reasoning over the most
+        relevant memories and facts. The result carries its citations."""
+        args = _args(query=query, preset=preset, model=model, project=project,
+                     budget=budget, max_tokens=max_tokens)
+        return self._call_json("cortex_reason", args) or {}
+
+    # -- DataFrames ----------------------------------------------------------
+
+    def facts_frame(self, **kwargs):
+        """facts() as a pandas DataFrame. Takes the same arguments."""
+        return _frame(self.facts(**kwargs))
+
+    def search_frame(self, query, **kwargs):
+        """search() as a pandas DataFrame. Takes the same arguments."""
+        return _frame(self.search(query, **kwargs))
+
+    # -- protocol ------------------------------------------------------------
+
+    def _call_json(self, name, arguments):
+        # Tools answer "No ... found" in plain text when there is nothing to
+        # return; that comes back as None.
+        text = self.call_tool(name, arguments).strip()
+        if not (text.startswith("{") or text.startswith("[") or text == "null"):
+            return None
+        try:
+            return json.loads(text)
+        except ValueError as e:
+            raise CortexError(f"decoding {name} result: {e}") from e
+
+    def _request(self, method, params):
+        msg_id = next(self._ids)
+        slot = {"done": threading.Event(), "response": None}
+        with self._lock:
+            if self._closed:
+                raise CortexError("client is closed")
+            self._pending[msg_id] = slot
+        try:
+            reply = self._post({"jsonrpc": "2.0", "id": msg_id, "method": method, "params": params})
+            if reply is not None:
+                # Errors the server rejects up front come back in the POST body.
+                slot["response"] = reply
+            elif not slot["done"].wait(self._timeout):
+                raise CortexError(f"{method}: no response after {self._timeout}s")
+        finally:
+            with self._lock:
+                self._pending.pop(msg_id, None)
+        response = slot["response"]
+        if response is None:
+            raise CortexError(f"{method}: connection closed" + (f" ({self._stream_error})" if self._stream_error else ""))
+        if response.get("error"):
+            raise CortexError(f"{method}: {response['error'].get('message', response['error'])}")
+        return response.get("result") or {}
+
+    def _post(self, message):
+        body = json.dumps(message).encode("utf-8")
+        req = urllib.request.Request(self._endpoint, data=body, method="POST",
+                                     headers={**self._headers, "Content-Type": "application/json"})
+        try:
+            with urllib.request.urlopen(req, timeout=self._timeout) as resp:
+                data = resp.read().strip()
+        except urllib.error.HTTPError as e:
+            data = e.read().strip()
+            if not data.startswith(b"{"):
+                raise CortexError(f"POST {self._endpoint}: HTTP {e.code}") from e
+        except (urllib.error.URLError, OSError) as e:
+            raise CortexError(f"POST {self._endpoint}: {e}") from e
+        if not data.startswith(b"{"):
+            return None
+        reply = json.loads(data)
+        return reply if reply.get("error") else None
+
+    def _read_stream(self):
+        event, data = "message", []
+        try:
+            for raw in self._stream:
+                line = raw.decode("utf-8").rstrip("\r\n")
+                if line:
+                    field, _, value = line.partition(":")
+                    value = value[1:] if value.startswith(" ") else value
+                    if field == "event":
+                        event = value
+                    elif field == "data":
+                        data.append(value)
+                    continue
+                if data:
+                    self._dispatch(event, "\n".join(data))
+                event, data = "message", []
+        except (OSError, ValueError) as e:
+            if not self._closed:
+                self._stream_error = str(e)
+        finally:
+            self._endpoint_ready.set()
+            self.close()
+            self._stream.close()
+
+    def _dispatch(self, event, data):
+        if event == "endpoint":
+            self._endpoint = urllib.parse.urljoin(self._sse_url, data)
+            self._endpoint_ready.set()
+            return
+        if event != "message":
+            return
+        try:
+            msg = json.loads(data)
+        except ValueError:
+            return
+        with self._lock:
+            slot = self._pending.get(msg.get("id"))
+        if slot is not None:
+            slot["response"] = msg
+            slot["done"].set()
+
+
+def _open_stream(url, headers, timeout):
+    """GET the SSE stream, returning its socket and the response."""
+    parts = urllib.parse.urlsplit(url)
+    conn_cls = http.client.HTTPSConnection if parts.scheme == "https" else http.client.HTTPConnection
+    conn = conn_cls(parts.netloc, timeout=timeout)
+    path = parts.path + ("?" + parts.query if parts.query else "")
+    conn.request("GET", path, headers={**headers, "Accept": "text/event-stream"})
+    sock = conn.sock
+    resp = conn.getresponse()
+    if resp.status != 200:
+        conn.close()
+        raise CortexError(f"HTTP {resp.status}")
+    # No read timeout from here on: the stream stays open and idle between calls.
+    sock.settimeout(None)
+    return sock, resp
+
+
+def _args(**kwargs):
+    """Drop unset arguments so the server applies its defaults."""
+    return {k: v for k, v in kwargs.items() if v is not None and v is not False and v != ""}
+
+
+_CAMEL = re.compile(r"(?<=[a-z0-9])(?=[A-Z])")
+
+
+def _snake_keys(fact):
+    # The server encodes facts with Go field names (MemoryID, FactType, ...).
+    return {_CAMEL.sub("_", k).lower(): v for k, v in fact.items()}
+
+
+def _frame(rows):
+    try:
+        import pandas as pd
+    except ImportError as e:
+        raise ImportError("DataFrame helpers need pandas: pip install 'cortex-client[pandas]'") from e
+    return pd.DataFrame(rows)
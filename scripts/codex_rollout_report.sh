@@ -1,14 +1,9 @@
 #!/usr/bin/env bash
 set -euo pipefail
 
+# Backward-compatible wrapper: rollout telemetry now lives in the cortex
+# database and is summarized by `cortex telemetry report`.
 ROOT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
-TELEMETRY_FILE="$HOME/.cortex/reason-telemetry.jsonl"
-
-# First non-flag positional arg (if provided) is treated as telemetry file path.
-if [[ $# -gt 0 && "$1" != --* ]]; then
-  TELEMETRY_FILE="$1"
-  shift
-fi
 
 cd "$ROOT_DIR"
-go run ./cmd/cortex codex-rollout-report --file "$TELEMETRY_FILE" "$@"
+go run ./cmd/cortex telemetry report --by mode "$@"